@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	internalApp "github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dao"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	"github.com/haierkeys/fast-note-sync-service/pkg/fileurl"
+	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func init() {
+	var configPath string
+	var username string
+	var historyID int64
+	var extract bool
+
+	var backupCmd = &cobra.Command{
+		Use:   "backup",
+		Short: "Backup maintenance commands",
+		// 备份维护命令
+	}
+
+	var verifyCmd = &cobra.Command{
+		Use:   "verify -u <username> --id <history_id> [--extract] [-c config_file]",
+		Short: "Verify that a stored backup is intact and restorable",
+		// 重新下载备份，与记录的大小和校验和比对，可选试解压，确认异地副本可用于恢复
+		Run: func(cmd *cobra.Command, args []string) {
+			if username == "" {
+				bootstrapLogger.Error("username is required, use -u flag")
+				os.Exit(1)
+			}
+			if historyID <= 0 {
+				bootstrapLogger.Error("backup history id is required, use --id flag")
+				os.Exit(1)
+			}
+
+			// Load configuration
+			// 加载配置
+			if configPath == "" {
+				if fileurl.IsExist("config/config-dev.yaml") {
+					configPath = "config/config-dev.yaml"
+				} else if fileurl.IsExist("config.yaml") {
+					configPath = "config.yaml"
+				} else {
+					configPath = "config/config.yaml"
+				}
+			}
+
+			appConfig, configRealpath, err := internalApp.LoadConfig(configPath)
+			if err != nil {
+				bootstrapLogger.Error("failed to load config", zap.Error(err))
+				os.Exit(1)
+			}
+			bootstrapLogger.Info("loading config", zap.String("path", configRealpath))
+
+			// Initialize logger
+			// 初始化日志
+			lg, err := logger.NewLogger(logger.Config{
+				Level:      appConfig.Log.Level,
+				File:       appConfig.Log.File,
+				Production: appConfig.Log.Production,
+			})
+			if err != nil {
+				bootstrapLogger.Error("failed to init logger", zap.Error(err))
+				os.Exit(1)
+			}
+
+			// Initialize database
+			// 初始化数据库
+			dbConfig := appConfig.Database
+			dbConfig.RunMode = appConfig.Server.RunMode
+
+			db, err := dao.NewEngine(dbConfig, lg)
+			if err != nil {
+				bootstrapLogger.Error("failed to init database", zap.Error(err))
+				os.Exit(1)
+			}
+
+			ctx := context.Background()
+			daoObj := dao.New(db, ctx, dao.WithConfig(&dbConfig), dao.WithLogger(lg))
+
+			user, err := dao.NewUserRepository(daoObj).GetByUsername(ctx, username)
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					fmt.Fprintf(os.Stderr, "Error: user '%s' not found\n", username)
+				} else {
+					fmt.Fprintf(os.Stderr, "Error: failed to query user: %v\n", err)
+				}
+				os.Exit(1)
+			}
+
+			// The backup service sweeps its staging directory on construction, a separate temp path
+			// keeps a running server's in-flight archives out of reach
+			// 备份服务构造时会清空暂存目录，使用独立的临时路径避免影响运行中服务正在上传的归档
+			tempPath := appConfig.App.TempPath
+			if tempPath == "" {
+				tempPath = "storage/temp"
+			}
+			storageService := service.NewStorageService(dao.NewStorageRepository(daoObj), &appConfig.Storage)
+			backupService := service.NewBackupService(
+				dao.NewBackupRepository(daoObj), dao.NewBackupBlobRepository(daoObj),
+				dao.NewNoteRepository(daoObj), dao.NewFolderRepository(daoObj), dao.NewFileRepository(daoObj), dao.NewVaultRepository(daoObj),
+				storageService, &appConfig.Storage, nil, filepath.Join(tempPath, "cli"), lg,
+			)
+
+			result, err := backupService.Verify(ctx, user.UID, historyID, extract)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Backup %d on storage %d (%s): %s\n", result.HistoryID, result.StorageID, result.StorageType, result.FilePath)
+			fmt.Printf("  size:     %d bytes (recorded %d)\n", result.Size, result.ExpectedSize)
+			fmt.Printf("  sha256:   %s (recorded %s)\n", result.Checksum, result.ExpectedChecksum)
+			if result.Extracted {
+				fmt.Printf("  extract:  %d files read back\n", result.FileCount)
+			}
+			fmt.Printf("  result:   %s\n", result.Message)
+			if !result.Verified {
+				os.Exit(1)
+			}
+		},
+	}
+
+	backupCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(backupCmd)
+	fs := verifyCmd.Flags()
+	fs.StringVarP(&configPath, "config", "c", "", "config file path (default: config/config.yaml)")
+	fs.StringVarP(&username, "username", "u", "", "owner of the backup (required)")
+	fs.Int64Var(&historyID, "id", 0, "backup history record id (required)")
+	fs.BoolVar(&extract, "extract", false, "also test-extract the archive")
+}
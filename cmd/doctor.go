@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	internalApp "github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dao"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
+	"github.com/haierkeys/fast-note-sync-service/pkg/fileurl"
+	"github.com/haierkeys/fast-note-sync-service/pkg/ipfilter"
+	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
+	"github.com/haierkeys/fast-note-sync-service/pkg/maintenance"
+	"github.com/haierkeys/fast-note-sync-service/pkg/redact"
+	"github.com/haierkeys/fast-note-sync-service/pkg/secretscan"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+
+	"github.com/spf13/cobra"
+)
+
+// doctorReport collects the results of the doctor checks
+// doctorReport 汇总 doctor 各项检查的结果
+type doctorReport struct {
+	failed int // Problems left unrepaired // 未修复的问题数
+}
+
+// line prints one check result
+// line 输出一条检查结果
+func (r *doctorReport) line(status, format string, args ...any) {
+	if status == "FAIL" {
+		r.failed++
+	}
+	fmt.Printf("[%-5s] %s\n", status, fmt.Sprintf(format, args...))
+}
+
+// issues prints the problems found by a data check, a missing issue list means the check passed
+// issues 输出数据检查发现的问题，问题列表为空表示检查通过
+func (r *doctorReport) issues(uid int64, check string, issues []*dao.DoctorIssue, err error) {
+	if err != nil {
+		r.line("FAIL", "uid=%d %s: %v", uid, check, err)
+		return
+	}
+	if len(issues) == 0 {
+		r.line("OK", "uid=%d %s", uid, check)
+		return
+	}
+	for _, issue := range issues {
+		status := "FAIL"
+		if issue.Fixed {
+			status = "FIXED"
+		}
+		r.line(status, "uid=%d %s: %s (%s)", uid, issue.Kind, issue.Target, issue.Detail)
+	}
+}
+
+func init() {
+	var configPath string
+	var fix bool
+
+	var doctorCmd = &cobra.Command{
+		Use:   "doctor [--fix] [-c config_file]",
+		Short: "Check the configuration, databases and content storage for problems",
+		// 检查配置、数据库与内容存储：SQLite 完整性、孤立的内容目录、重复的文件夹记录与全文索引偏差，--fix 时修复可修复的问题，修复前需停止服务
+		Run: func(cmd *cobra.Command, args []string) {
+			if configPath == "" {
+				if fileurl.IsExist("config/config-dev.yaml") {
+					configPath = "config/config-dev.yaml"
+				} else if fileurl.IsExist("config.yaml") {
+					configPath = "config.yaml"
+				} else {
+					configPath = "config/config.yaml"
+				}
+			}
+			report := &doctorReport{}
+
+			// Configuration
+			// 配置
+			appConfig, configRealpath, err := internalApp.LoadConfig(configPath)
+			if err != nil {
+				report.line("FAIL", "config %s: %v", configPath, err)
+				os.Exit(1)
+			}
+			report.line("OK", "config %s loaded", configRealpath)
+			doctorCheckConfig(report, appConfig)
+
+			lg, err := logger.NewLogger(logger.Config{
+				Level:      appConfig.Log.Level,
+				File:       appConfig.Log.File,
+				Production: appConfig.Log.Production,
+			})
+			if err != nil {
+				report.line("FAIL", "log: %v", err)
+				os.Exit(1)
+			}
+
+			dbConfig := appConfig.Database
+			dbConfig.RunMode = appConfig.Server.RunMode
+			db, err := dao.NewEngine(dbConfig, lg)
+			if err != nil {
+				report.line("FAIL", "database %s: %v", dbConfig.Type, err)
+				os.Exit(1)
+			}
+			report.line("OK", "database %s connected", dbConfig.Type)
+
+			userDbConfig := appConfig.UserDatabase
+			userDbConfig.RunMode = appConfig.Server.RunMode
+			// Index checks and rebuilds read note content, which may be encrypted at rest
+			// 索引检查与重建需要读取笔记正文，正文可能已落盘加密
+			if keyring, err := appConfig.GetEncryptionKeyring(); err == nil {
+				atrest.SetDefault(keyring)
+			}
+			bleveMgr := dao.NewBleveManager(appConfig.App.FtsBleveEnabled, appConfig.App.FtsBleveStoreRaw, lg)
+			defer bleveMgr.CloseAll()
+			daoObj := dao.New(db, context.Background(),
+				dao.WithConfig(&dbConfig),
+				dao.WithUserDatabaseConfig(&userDbConfig),
+				dao.WithLogger(lg),
+				dao.WithBleveManager(bleveMgr),
+			)
+			ctx := context.Background()
+
+			// SQLite integrity, corrupted files can only be restored from a backup
+			// SQLite 完整性，损坏的文件只能从备份恢复
+			for _, file := range daoObj.SQLiteFiles() {
+				problems, err := dao.SQLiteIntegrityCheck(file, lg)
+				switch {
+				case err != nil:
+					report.line("FAIL", "sqlite %s: %v", file, err)
+				case len(problems) > 0:
+					report.line("FAIL", "sqlite %s: %s", file, strings.Join(problems, "; "))
+				default:
+					report.line("OK", "sqlite %s integrity", file)
+				}
+			}
+
+			// Per-user data
+			// 各用户数据
+			uids, err := daoObj.DoctorUIDs()
+			if err != nil {
+				report.line("FAIL", "list users: %v", err)
+			}
+			for _, uid := range uids {
+				issues, err := daoObj.CheckOrphanContent(ctx, uid, fix)
+				report.issues(uid, "content folders", issues, err)
+				issues, err = daoObj.CheckDuplicateFolders(ctx, uid, fix)
+				report.issues(uid, "folder rows", issues, err)
+				if bleveMgr.IsEnabled() {
+					issues, err = daoObj.CheckFTSDrift(ctx, uid, fix)
+					report.issues(uid, "full-text index", issues, err)
+				}
+			}
+
+			if report.failed > 0 {
+				if !fix {
+					fmt.Printf("%d problem(s) found, stop the service and run with --fix to repair what can be repaired.\n", report.failed)
+				} else {
+					fmt.Printf("%d problem(s) could not be repaired.\n", report.failed)
+				}
+				bleveMgr.CloseAll()
+				os.Exit(1)
+			}
+			fmt.Println("No problems found.")
+		},
+	}
+
+	rootCmd.AddCommand(doctorCmd)
+	fs := doctorCmd.Flags()
+	fs.StringVarP(&configPath, "config", "c", "", "config file path (default: config/config.yaml)")
+	fs.BoolVar(&fix, "fix", false, "repair what can be repaired, the service must be stopped")
+}
+
+// doctorCheckConfig validates the parts of the configuration the service only parses at startup
+// doctorCheckConfig 校验服务仅在启动时才解析的配置项
+func doctorCheckConfig(report *doctorReport, cfg *internalApp.AppConfig) {
+	for _, key := range defaultSecretKeys {
+		if cfg.Security.AuthTokenKey == key {
+			report.line("WARN", "security.auth-token-key is the default value, change it before exposing the service")
+			break
+		}
+	}
+	if _, err := cfg.GetEncryptionKeyring(); err != nil {
+		report.line("FAIL", "encryption keys: %v", err)
+	}
+	if _, err := ipfilter.New(cfg.Security.IPDenyList); err != nil {
+		report.line("FAIL", "security.ip-deny-list: %v", err)
+	}
+	if cfg.Security.SecretScan.Enabled {
+		if _, err := secretscan.New(cfg.Security.SecretScan.Patterns); err != nil {
+			report.line("FAIL", "security.secret-scan patterns: %v", err)
+		}
+	}
+	_, err := redact.New(redact.Rules{
+		FrontmatterKeys: cfg.Export.Redaction.FrontmatterKeys,
+		ExcludeFolders:  cfg.Export.Redaction.ExcludeFolders,
+		MaskPatterns:    cfg.Export.Redaction.MaskPatterns,
+		Mask:            cfg.Export.Redaction.Mask,
+	})
+	if err != nil {
+		report.line("FAIL", "export.redaction: %v", err)
+	}
+	if cfg.Maintenance.IsEnabled {
+		duration, err := util.ParseDuration(cfg.Maintenance.Duration)
+		if err == nil {
+			_, err = maintenance.ParseWindow(cfg.Maintenance.Start, duration, cfg.Maintenance.Days, cfg.Maintenance.Timezone)
+		}
+		if err != nil {
+			report.line("FAIL", "maintenance window: %v", err)
+		}
+	}
+	if report.failed == 0 {
+		report.line("OK", "config values valid")
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	internalApp "github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func init() {
+	var configPath string
+	var uid int64
+	var fromURL string
+	var token string
+	var client string
+	var vaults []string
+
+	var migrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate data from another fast-note-sync-service instance",
+		// 从另一个 fast-note-sync-service 实例迁移数据，用于更换服务器
+	}
+
+	var remoteCmd = &cobra.Command{
+		Use:   "remote --from-url <url> --token <token> --uid <uid> [--vault <vault>]... [-c config_file]",
+		Short: "Pull the vaults, notes, history and attachments of a remote user into a local user",
+		// 通过远程实例的 API 拉取某个用户的保险库、笔记、历史版本与附件并导入本地用户，保留修改时间与版本号。
+		// 可重复运行，未变化的条目会被跳过；运行前需停止本地服务
+		Run: func(cmd *cobra.Command, args []string) {
+			if fromURL == "" {
+				bootstrapLogger.Error("source server is required, use --from-url flag")
+				os.Exit(1)
+			}
+			if token == "" {
+				bootstrapLogger.Error("token is required, use --token flag")
+				os.Exit(1)
+			}
+			if uid <= 0 {
+				bootstrapLogger.Error("uid is required, use --uid flag")
+				os.Exit(1)
+			}
+
+			appConfig, lg, db := openVaultCommandStore(configPath)
+			tempPath := vaultCommandTempPath(appConfig)
+			appConfig.App.TempPath = tempPath
+
+			// Migrated notes go through the same services as synced ones, so folders, links,
+			// search index and sync logs stay consistent
+			// 迁移的笔记与同步写入走相同的服务，保证文件夹、链接、搜索索引与同步日志一致
+			app, err := internalApp.NewApp(appConfig, lg, db, frontendFiles)
+			if err != nil {
+				bootstrapLogger.Error("failed to create app container", zap.Error(err))
+				os.Exit(1)
+			}
+			ctx, stopDrain := context.WithCancel(context.Background())
+			defer stopDrain()
+			requireVaultCommandUser(ctx, app.Dao, uid)
+			drainNoteHistoryQueue(ctx)
+
+			progress := func(msg *dto.MigrateProgressMessage) {
+				fmt.Fprintf(os.Stderr, "  %s: %d notes, %d history versions, %d files, %d skipped, %d failed\n",
+					msg.Vault, msg.Notes, msg.Histories, msg.Files, msg.Skipped, msg.Failed)
+			}
+			params := &dto.MigrateRemoteRequest{BaseURL: fromURL, Token: token, Client: client, Vaults: vaults}
+			result, err := app.MigrateService.WithClient("cli", internalApp.Name, internalApp.Version).MigrateRemote(ctx, uid, params, progress)
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+			defer cancel()
+			if shutdownErr := app.Shutdown(shutdownCtx); shutdownErr != nil {
+				lg.Warn("app shutdown error", zap.Error(shutdownErr))
+			}
+			_ = app.Close()
+
+			_ = os.RemoveAll(tempPath)
+			if result != nil {
+				fmt.Printf("Migrated %d vault(s) from %s to uid=%d: %d notes, %d history versions, %d files, %d skipped, %d failed.\n",
+					result.Vaults, fromURL, uid, result.Notes, result.Histories, result.Files, result.Skipped, result.Failed)
+				for _, e := range result.Errors {
+					fmt.Fprintf(os.Stderr, "  failed: %s\n", e)
+				}
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: migration from %s failed: %v\n", fromURL, err)
+				os.Exit(1)
+			}
+			if result.Failed > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+
+	migrateCmd.AddCommand(remoteCmd)
+	rootCmd.AddCommand(migrateCmd)
+	fs := remoteCmd.Flags()
+	fs.StringVarP(&configPath, "config", "c", "", "config file path (default: config/config.yaml)")
+	fs.Int64Var(&uid, "uid", 0, "local user receiving the data (required)")
+	fs.StringVar(&fromURL, "from-url", "", "address of the source server, e.g. https://notes.example.com (required)")
+	fs.StringVar(&token, "token", "", "auth token of the source user (required)")
+	fs.StringVar(&client, "client", "webgui", "client type sent to the source server, listing vaults requires a WebGUI login token")
+	fs.StringArrayVar(&vaults, "vault", nil, "only migrate this vault, repeatable, default all vaults")
+}
@@ -58,7 +58,9 @@ func AutoMigrate(db *gorm.DB, key string) error {
 			//goContentHeader += fmt.Sprintf("type %s = %s\n", field.Name, field.Type.Name())
 		}
 		//goContent += "\tcase \"\":\n\t\treturn db.AutoMigrate(" + strings.Join(fields, ", ") + ")"
-		goContent += "\t}\n\treturn nil\n}"
+		// Keys of hand-written models fall through to model_hand_written.go
+		// 手写模型的键交由 model_hand_written.go 处理
+		goContent += "\t}\n\treturn autoMigrateHandWritten(db, key)\n}"
 
 		_ = os.WriteFile(g.OutPath[0:len(g.OutPath)-6]+"/model/model.go", []byte(goContent), os.ModePerm)
 	}
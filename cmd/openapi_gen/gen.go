@@ -0,0 +1,52 @@
+// Command openapi_gen converts the Swagger 2.0 spec generated by swag into the OpenAPI 3 spec served at /api/openapi.json
+// Run by `make docs` right after swag so the embedded spec always matches the handler annotations.
+// openapi_gen 将 swag 生成的 Swagger 2.0 规范转换为 /api/openapi.json 提供的 OpenAPI 3 规范
+// 由 `make docs` 在 swag 之后运行，使嵌入的规范始终与处理器注解一致。
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+)
+
+const (
+	swaggerPath = "docs/swagger.json"
+	openapiPath = "docs/openapi.json"
+)
+
+func main() {
+	src, err := os.ReadFile(swaggerPath)
+	if err != nil {
+		log.Fatalf("read %s: %v", swaggerPath, err)
+	}
+	out, err := convert(src)
+	if err != nil {
+		log.Fatalf("convert %s: %v", swaggerPath, err)
+	}
+	if err := os.WriteFile(openapiPath, out, 0644); err != nil {
+		log.Fatalf("write %s: %v", openapiPath, err)
+	}
+	log.Printf("create openapi.json at %s", openapiPath)
+}
+
+// convert turns a Swagger 2.0 JSON document into an indented OpenAPI 3 JSON document
+// convert 将 Swagger 2.0 JSON 文档转换为带缩进的 OpenAPI 3 JSON 文档
+func convert(src []byte) ([]byte, error) {
+	var doc2 openapi2.T
+	if err := json.Unmarshal(src, &doc2); err != nil {
+		return nil, err
+	}
+	doc3, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return nil, err
+	}
+	out, err := json.MarshalIndent(doc3, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestOpenAPIInSync verifies docs/openapi.json matches the conversion of docs/swagger.json,
+// failing when `make docs` was only partly run after handler annotations changed.
+// TestOpenAPIInSync 验证 docs/openapi.json 与 docs/swagger.json 的转换结果一致，
+// 当处理器注解变更后未完整运行 `make docs` 时失败。
+func TestOpenAPIInSync(t *testing.T) {
+	src, err := os.ReadFile("../../" + swaggerPath)
+	require.NoError(t, err)
+	want, err := convert(src)
+	require.NoError(t, err)
+	got, err := os.ReadFile("../../" + openapiPath)
+	require.NoError(t, err)
+
+	require.True(t, bytes.Equal(want, got), "docs/openapi.json is stale, run `make docs`")
+	require.Contains(t, string(got), `"openapi": "3.0.3"`)
+}
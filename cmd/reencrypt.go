@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	internalApp "github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dao"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
+	"github.com/haierkeys/fast-note-sync-service/pkg/contentstore"
+	"github.com/haierkeys/fast-note-sync-service/pkg/fileurl"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func init() {
+	var configPath string
+	var workDir string
+	var generateKey bool
+	var oldKeys []string
+	var decrypt bool
+
+	var reencryptCmd = &cobra.Command{
+		Use:   "reencrypt [--old-key <key_or_file>]... [--decrypt] [-c config_file] [-d working_dir]",
+		Short: "Encrypt, rotate or decrypt stored note content and attachments in place",
+		// 原地加密、轮换或解密已存储的笔记内容与附件，运行前需停止服务
+		Long: `Encrypt, rotate or decrypt stored note content and attachments in place. Stop the service first.
+Covers storage/vault below the working directory, the thumbnail cache and the copies kept in the configured content store.
+The search index, the note full-text table in the database, the git sync workspace and local backups are not covered
+and stay plaintext.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if generateKey {
+				key, err := atrest.GenerateKey()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: failed to generate key: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Println(key)
+				return
+			}
+
+			// The content folders live below the working directory of the service, as with run -d
+			// 内容目录位于服务的工作目录下，与 run -d 一致
+			if workDir != "" {
+				if err := os.Chdir(workDir); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: failed to change the working directory: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
+			// Load configuration
+			// 加载配置
+			if configPath == "" {
+				if fileurl.IsExist("config/config-dev.yaml") {
+					configPath = "config/config-dev.yaml"
+				} else if fileurl.IsExist("config.yaml") {
+					configPath = "config.yaml"
+				} else {
+					configPath = "config/config.yaml"
+				}
+			}
+
+			appConfig, configRealpath, err := internalApp.LoadConfig(configPath)
+			if err != nil {
+				bootstrapLogger.Error("failed to load config", zap.Error(err))
+				os.Exit(1)
+			}
+			bootstrapLogger.Info("loading config", zap.String("path", configRealpath))
+
+			keyring, err := appConfig.GetEncryptionKeyring()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			// Old keys are only used to read files written before a rotation
+			// 旧密钥仅用于读取轮换前写入的文件
+			var extra []*atrest.Key
+			for _, s := range oldKeys {
+				k, err := atrest.ParseKey(s)
+				if err != nil {
+					if k, err = atrest.LoadKeyFile(s); err != nil {
+						fmt.Fprintf(os.Stderr, "Error: invalid --old-key: %v\n", err)
+						os.Exit(1)
+					}
+				}
+				extra = append(extra, k)
+			}
+			source := keyring.With(extra...)
+
+			var target *atrest.Keyring
+			if !decrypt {
+				if keyring.Current() == nil {
+					fmt.Fprintln(os.Stderr, "Error: security.encryption-at-rest must be enabled with a key, or use --decrypt")
+					os.Exit(1)
+				}
+				target = keyring
+			}
+
+			var rewritten, skipped, failed int
+			// rewrite re-encodes one file, name is what errors are reported under
+			// rewrite 重新编码单个文件，name 为报错时显示的名称
+			rewrite := func(path, name string) (changed bool) {
+				id, encrypted, err := atrest.FileKeyID(path)
+				if err != nil {
+					failed++
+					fmt.Fprintf(os.Stderr, "Error: %s: %v\n", name, err)
+					return false
+				}
+				if (target == nil && !encrypted) || (target != nil && encrypted && id == target.Current().ID()) {
+					skipped++
+					return false
+				}
+				if err := source.Rewrite(path, target); err != nil {
+					failed++
+					fmt.Fprintf(os.Stderr, "Error: %s: %v\n", name, err)
+					return false
+				}
+				rewritten++
+				return true
+			}
+
+			// Walk every content file and attachment plus the thumbnail cache
+			// 遍历所有内容文件、附件以及缩略图缓存
+			for _, root := range []string{dao.VaultRoot(), appConfig.Thumbnail.SavePath} {
+				if root == "" || !fileurl.IsExist(root) {
+					continue
+				}
+				err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+					if err != nil {
+						return err
+					}
+					if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+						return nil
+					}
+					rewrite(path, path)
+					return nil
+				})
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: failed to walk %s: %v\n", root, err)
+					os.Exit(1)
+				}
+			}
+
+			// The content store keeps its own copy of every content file, rewrite it through a temporary file
+			// 内容存储保存了每个内容文件的副本，经由临时文件重写
+			store, err := appConfig.GetContentStore()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if store != nil {
+				tempDir := appConfig.App.TempPath
+				if tempDir == "" {
+					tempDir = os.TempDir()
+				}
+				if err := os.MkdirAll(tempDir, 0755); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				err := store.List(dao.VaultStorePrefix(), func(key string) error {
+					if err := rewriteStored(store, key, tempDir, rewrite); err != nil {
+						failed++
+						fmt.Fprintf(os.Stderr, "Error: store %s: %v\n", key, err)
+					}
+					return nil
+				})
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: failed to list the content store: %v\n", err)
+					os.Exit(1)
+				}
+			}
+
+			fmt.Printf("Rewritten: %d, already up to date: %d, failed: %d\n", rewritten, skipped, failed)
+			if failed > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+
+	rootCmd.AddCommand(reencryptCmd)
+	flags := reencryptCmd.Flags()
+	flags.StringVarP(&configPath, "config", "c", "", "config file path (default: config/config.yaml)")
+	flags.StringVarP(&workDir, "dir", "d", "", "working directory of the service, the one passed to run -d")
+	flags.BoolVar(&generateKey, "generate-key", false, "print a new random key and exit")
+	flags.StringArrayVar(&oldKeys, "old-key", nil, "previous key or key file to read files encrypted before a rotation (repeatable)")
+	flags.BoolVar(&decrypt, "decrypt", false, "write every file back as plaintext")
+}
+
+// rewriteStored downloads a content store object, re-encodes it with rewrite and uploads it again when it changed
+// rewriteStored 下载内容存储中的对象，用 rewrite 重新编码，有变化时重新上传
+func rewriteStored(store contentstore.Store, key, tempDir string, rewrite func(path, name string) bool) error {
+	tmp, err := os.CreateTemp(tempDir, ".reencrypt-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	rc, err := store.Open(key)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	_, err = io.Copy(tmp, rc)
+	rc.Close()
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	if !rewrite(tmp.Name(), "store "+key) {
+		return nil
+	}
+	f, err := os.Open(tmp.Name())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return store.Put(key, f)
+}
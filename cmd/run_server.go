@@ -13,10 +13,12 @@ import (
 	internalApp "github.com/haierkeys/fast-note-sync-service/internal/app"
 	"github.com/haierkeys/fast-note-sync-service/internal/dao"
 	"github.com/haierkeys/fast-note-sync-service/internal/routers"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
 	"github.com/haierkeys/fast-note-sync-service/internal/task"
 	"github.com/haierkeys/fast-note-sync-service/internal/upgrade"
 	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
 	"github.com/haierkeys/fast-note-sync-service/pkg/safe_close"
+	"github.com/haierkeys/fast-note-sync-service/pkg/tracer"
 	"github.com/haierkeys/fast-note-sync-service/pkg/validator"
 
 	"github.com/gin-gonic/gin"
@@ -125,12 +127,24 @@ func NewServer(runEnv *runFlags) (*Server, error) {
 	// 检查安全配置（使用注入的配置）
 	checkSecurityConfigWithConfig(appConfig, s.logger)
 
+	// Initialize OpenTelemetry span export (using injected config)
+	// 初始化 OpenTelemetry 链路导出（使用注入的配置）
+	if err := initTracerWithConfig(s, appConfig); err != nil {
+		return nil, fmt.Errorf("initTracer: %w", err)
+	}
+
 	// Initialize storage directory (using injected config)
 	// 初始化存储目录（使用注入的配置）
 	if err := initStorageWithConfig(appConfig); err != nil {
 		return nil, fmt.Errorf("initStorage: %w", err)
 	}
 
+	// Apply a scheduled snapshot restore, must happen before any database is opened
+	// 执行已安排的快照恢复，必须在打开任何数据库之前进行
+	if err := service.ApplyPendingSnapshotRestore(&appConfig.Snapshot, s.logger); err != nil {
+		return nil, fmt.Errorf("applySnapshotRestore: %w", err)
+	}
+
 	// Initialize database (using injected config)
 	// 初始化数据库（使用注入的配置）
 	db, err := initDatabaseWithConfig(appConfig, s.logger)
@@ -146,6 +160,9 @@ func NewServer(runEnv *runFlags) (*Server, error) {
 		return nil, fmt.Errorf("failed to create app container: %w", err)
 	}
 	s.app = app
+	// Log through the container's logger from now on, so the admin log viewer sees the server and task logs too
+	// 此后使用容器的日志器，使管理员日志查看也能看到服务器与任务日志
+	s.logger = app.Logger()
 
 	// Auto-execute migration tasks (using injected config)
 	// 自动执行迁移任务（使用注入的配置）
@@ -362,6 +379,13 @@ func NewServer(runEnv *runFlags) (*Server, error) {
 }
 
 func initScheduler(s *Server) {
+	// In a multi-instance deployment only the instance running the tasks starts the scheduler
+	// 多实例部署时只有负责执行任务的实例启动调度器
+	if cluster := s.app.Config().Cluster; cluster.IsEnabled && cluster.RunTasks != nil && !*cluster.RunTasks {
+		s.logger.Info("scheduled tasks are run by another instance")
+		return
+	}
+
 	// Create task manager
 	// 创建任务管理器
 	manager := task.NewManager(s.logger, s.sc, s.app)
@@ -394,6 +418,40 @@ func initLoggerWithConfig(s *Server, cfg *internalApp.AppConfig) error {
 	return nil
 }
 
+// initTracerWithConfig exports spans over OTLP when tracer.otlp is enabled, the provider is shut down
+// on close so buffered spans are flushed
+// initTracerWithConfig 开启 tracer.otlp 时通过 OTLP 导出链路，关闭时关闭 provider 以刷新缓冲的链路
+func initTracerWithConfig(s *Server, cfg *internalApp.AppConfig) error {
+	if cfg.Tracer.Enabled == nil || !*cfg.Tracer.Enabled || !cfg.Tracer.OTLP.Enabled {
+		return nil
+	}
+
+	provider, err := tracer.NewOTLPProvider(context.Background(), tracer.OTLPOptions{
+		Endpoint:       cfg.Tracer.OTLP.Endpoint,
+		URLPath:        cfg.Tracer.OTLP.URLPath,
+		Insecure:       cfg.Tracer.OTLP.Insecure,
+		Headers:        cfg.Tracer.OTLP.Headers,
+		ServiceName:    cfg.Tracer.OTLP.ServiceName,
+		ServiceVersion: internalApp.Version,
+		SampleRatio:    cfg.Tracer.OTLP.SampleRatio,
+	})
+	if err != nil {
+		return err
+	}
+	s.logger.Info("OpenTelemetry span export enabled", zap.String("endpoint", cfg.Tracer.OTLP.Endpoint))
+
+	s.sc.Attach(func(done func(), closeSignal <-chan struct{}) {
+		defer done()
+		<-closeSignal
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			s.logger.Warn("failed to flush OpenTelemetry spans", zap.Error(err))
+		}
+	})
+	return nil
+}
+
 // initValidatorWithLogger initializes validator, returns UniversalTranslator
 // initValidatorWithLogger 初始化验证器，返回 UniversalTranslator
 func initValidatorWithLogger(lg *zap.Logger) (*ut.UniversalTranslator, error) {
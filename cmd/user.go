@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	internalApp "github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dao"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/fileurl"
+	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"golang.org/x/term"
+	"gorm.io/gorm"
+)
+
+// userListPageSize users fetched per query by "user list"
+// userListPageSize "user list" 每次查询获取的用户数
+const userListPageSize = 100
+
+func init() {
+	var configPath string
+	var username string
+	var email string
+	var password string
+	var admin bool
+
+	var userCmd = &cobra.Command{
+		Use:   "user",
+		Short: "Manage user accounts directly in the user store",
+		// 直接操作用户库管理账号，无需 WebGUI 或开放注册，可用于初始化管理员或解救被锁定的用户
+	}
+
+	var addCmd = &cobra.Command{
+		Use:   "add -u <username> -e <email> [-p <password>] [--admin] [-c config_file]",
+		Short: "Create a user, optionally making it the admin",
+		// 创建用户，--admin 时将其设为配置文件中的管理员
+		Run: func(cmd *cobra.Command, args []string) {
+			if username == "" {
+				bootstrapLogger.Error("username is required, use -u flag")
+				os.Exit(1)
+			}
+			if email == "" {
+				bootstrapLogger.Error("email is required, use -e flag")
+				os.Exit(1)
+			}
+			pwd := userCommandPassword(password)
+
+			appConfig, userRepo, lg := openUserStore(configPath)
+			ctx := context.Background()
+
+			// Reuse the service so the CLI applies the same validation as the WebGUI
+			// 复用服务层，使命令行与 WebGUI 使用相同的校验
+			userSvc := service.NewUserService(userRepo, nil, nil, nil, nil, lg, nil)
+			user, err := userSvc.Create(ctx, &dto.UserCreateRequest{
+				Email:           email,
+				Username:        username,
+				Password:        pwd,
+				ConfirmPassword: pwd,
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to create user: %s\n", userCommandError(err))
+				os.Exit(1)
+			}
+			fmt.Printf("User '%s' (uid=%d) has been created successfully.\n", user.Username, user.UID)
+
+			if admin {
+				appConfig.User.AdminUID = int(user.UID)
+				if err := appConfig.Save(); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: failed to save admin-uid to %s: %v\n", appConfig.File, err)
+					os.Exit(1)
+				}
+				fmt.Printf("User '%s' is now the admin (user.admin-uid=%d in %s), restart the service to apply.\n", user.Username, user.UID, appConfig.File)
+			}
+		},
+	}
+
+	var passwdCmd = &cobra.Command{
+		Use:   "passwd -u <username> [-p <password>] [-c config_file]",
+		Short: "Set a user's password without the old one",
+		// 无需旧密码直接设置用户密码
+		Run: func(cmd *cobra.Command, args []string) {
+			if username == "" {
+				bootstrapLogger.Error("username is required, use -u flag")
+				os.Exit(1)
+			}
+			pwd := userCommandPassword(password)
+
+			_, userRepo, _ := openUserStore(configPath)
+			ctx := context.Background()
+			user := lookupUser(ctx, userRepo, username)
+
+			hashedPassword, err := util.GeneratePasswordHash(pwd)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to generate password hash: %v\n", err)
+				os.Exit(1)
+			}
+			if err := userRepo.UpdatePassword(ctx, hashedPassword, user.UID); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to update password: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Password for user '%s' (uid=%d) has been reset successfully.\n", username, user.UID)
+		},
+	}
+
+	var listCmd = &cobra.Command{
+		Use:   "list [-c config_file]",
+		Short: "List all users, including disabled ones",
+		// 列出所有用户，包括已禁用的用户
+		Run: func(cmd *cobra.Command, args []string) {
+			appConfig, userRepo, _ := openUserStore(configPath)
+			ctx := context.Background()
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "UID\tUSERNAME\tEMAIL\tSTATUS\tCREATED")
+			for offset := 0; ; offset += userListPageSize {
+				users, total, err := userRepo.GetList(ctx, offset, userListPageSize)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: failed to list users: %v\n", err)
+					os.Exit(1)
+				}
+				for _, u := range users {
+					status := "active"
+					if u.IsDeleted {
+						status = "disabled"
+					}
+					if appConfig.User.AdminUID != 0 && u.UID == int64(appConfig.User.AdminUID) {
+						status += ",admin"
+					}
+					fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", u.UID, u.Username, u.Email, status, u.CreatedAt.Format("2006-01-02 15:04:05"))
+				}
+				if len(users) == 0 || int64(offset+len(users)) >= total {
+					break
+				}
+			}
+			w.Flush()
+		},
+	}
+
+	var disableCmd = &cobra.Command{
+		Use:   "disable -u <username> [-c config_file]",
+		Short: "Disable a user so it can no longer sign in",
+		// 禁用用户，使其无法再登录，数据保留
+		Run: func(cmd *cobra.Command, args []string) {
+			if username == "" {
+				bootstrapLogger.Error("username is required, use -u flag")
+				os.Exit(1)
+			}
+
+			_, userRepo, _ := openUserStore(configPath)
+			ctx := context.Background()
+			user := lookupUser(ctx, userRepo, username)
+
+			// Update keeps the stored password when it is empty
+			// Update 在密码为空时保留原密码
+			user.Password = ""
+			user.IsDeleted = true
+			if err := userRepo.Update(ctx, user); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to disable user: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("User '%s' (uid=%d) has been disabled.\n", username, user.UID)
+		},
+	}
+
+	rootCmd.AddCommand(userCmd)
+	userCmd.AddCommand(addCmd, passwdCmd, listCmd, disableCmd)
+	userCmd.PersistentFlags().StringVarP(&configPath, "config", "c", "", "config file path (default: config/config.yaml)")
+	for _, c := range []*cobra.Command{addCmd, passwdCmd, disableCmd} {
+		c.Flags().StringVarP(&username, "username", "u", "", "target username (required)")
+	}
+	for _, c := range []*cobra.Command{addCmd, passwdCmd} {
+		c.Flags().StringVarP(&password, "password", "p", "", "password (prompted or read from stdin when omitted)")
+	}
+	addCmd.Flags().StringVarP(&email, "email", "e", "", "user email (required)")
+	addCmd.Flags().BoolVar(&admin, "admin", false, "make the user the admin by writing user.admin-uid to the config file")
+}
+
+// openUserStore loads the configuration and opens the user repository, exiting on failure
+// openUserStore 加载配置并打开用户仓储，失败时退出
+func openUserStore(configPath string) (*internalApp.AppConfig, domain.UserRepository, *zap.Logger) {
+	if configPath == "" {
+		if fileurl.IsExist("config/config-dev.yaml") {
+			configPath = "config/config-dev.yaml"
+		} else if fileurl.IsExist("config.yaml") {
+			configPath = "config.yaml"
+		} else {
+			configPath = "config/config.yaml"
+		}
+	}
+
+	appConfig, configRealpath, err := internalApp.LoadConfig(configPath)
+	if err != nil {
+		bootstrapLogger.Error("failed to load config", zap.Error(err))
+		os.Exit(1)
+	}
+	bootstrapLogger.Info("loading config", zap.String("path", configRealpath))
+
+	lg, err := logger.NewLogger(logger.Config{
+		Level:      appConfig.Log.Level,
+		File:       appConfig.Log.File,
+		Production: appConfig.Log.Production,
+	})
+	if err != nil {
+		bootstrapLogger.Error("failed to init logger", zap.Error(err))
+		os.Exit(1)
+	}
+
+	dbConfig := appConfig.Database
+	dbConfig.RunMode = appConfig.Server.RunMode
+
+	db, err := dao.NewEngine(dbConfig, lg)
+	if err != nil {
+		bootstrapLogger.Error("failed to init database", zap.Error(err))
+		os.Exit(1)
+	}
+
+	daoObj := dao.New(db, context.Background(), dao.WithConfig(&dbConfig), dao.WithLogger(lg))
+	return appConfig, dao.NewUserRepository(daoObj), lg
+}
+
+// lookupUser finds an active user by username, exiting when it does not exist
+// lookupUser 根据用户名查找活跃用户，不存在时退出
+func lookupUser(ctx context.Context, userRepo domain.UserRepository, username string) *domain.User {
+	user, err := userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			fmt.Fprintf(os.Stderr, "Error: user '%s' not found\n", username)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: failed to query user: %v\n", err)
+		}
+		os.Exit(1)
+	}
+	return user
+}
+
+// userCommandPassword returns the flag value, or asks for the password so it stays out of the shell history
+// userCommandPassword 返回参数值，否则询问密码，避免密码留在 shell 历史中
+func userCommandPassword(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+
+	var pwd string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprint(os.Stderr, "Password: ")
+		b, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to read password: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprint(os.Stderr, "Confirm password: ")
+		confirm, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to read password: %v\n", err)
+			os.Exit(1)
+		}
+		if string(b) != string(confirm) {
+			fmt.Fprintln(os.Stderr, "Error: passwords do not match")
+			os.Exit(1)
+		}
+		pwd = string(b)
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Fprintf(os.Stderr, "Error: failed to read password from stdin: %v\n", err)
+			os.Exit(1)
+		}
+		pwd = strings.TrimRight(line, "\r\n")
+	}
+
+	if pwd == "" {
+		bootstrapLogger.Error("password is required, use -p flag or enter it when prompted")
+		os.Exit(1)
+	}
+	return pwd
+}
+
+// userCommandError describes a service error code for the terminal
+// userCommandError 为终端输出描述服务层错误码
+func userCommandError(err error) string {
+	var e *code.Code
+	if errors.As(err, &e) {
+		return fmt.Sprintf("%s (code %d)", e.Error(), e.Code())
+	}
+	return err.Error()
+}
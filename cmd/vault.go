@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	internalApp "github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dao"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
+	"github.com/haierkeys/fast-note-sync-service/pkg/fileurl"
+	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
+	"github.com/haierkeys/fast-note-sync-service/pkg/redact"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func init() {
+	var configPath string
+	var uid int64
+	var vaultName string
+	var folder string
+	var since int64
+	var outPath string
+	var inPath string
+	var dirPath string
+	var title string
+	var storageID int64
+
+	var vaultCmd = &cobra.Command{
+		Use:   "vault",
+		Short: "Vault export and import commands",
+		// 保险库导出与导入命令，复用备份导出/导入流程，无需 HTTP 调用
+	}
+
+	var exportCmd = &cobra.Command{
+		Use:   "export --uid <uid> --vault <vault> --out <file.zip> [--folder <folder>] [--since <ms>] [-c config_file]",
+		Short: "Export a vault's notes and attachments to a ZIP file",
+		// 将保险库的笔记和附件导出为 ZIP 文件，只读操作，可在服务运行时由 cron 调用
+		Run: func(cmd *cobra.Command, args []string) {
+			if uid <= 0 {
+				bootstrapLogger.Error("uid is required, use --uid flag")
+				os.Exit(1)
+			}
+			if vaultName == "" {
+				bootstrapLogger.Error("vault is required, use --vault flag")
+				os.Exit(1)
+			}
+			if outPath == "" {
+				bootstrapLogger.Error("output file is required, use --out flag")
+				os.Exit(1)
+			}
+
+			ctx := context.Background()
+			backupService, tempPath := newVaultCommandBackupService(ctx, configPath, uid)
+			defer os.RemoveAll(tempPath)
+
+			// Write next to the target and rename, so a cron job never leaves a truncated archive behind
+			// 先写入目标旁的临时文件再重命名，避免定时任务留下不完整的压缩包
+			partial := outPath + ".partial"
+			out, err := os.Create(partial)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to create %s: %v\n", partial, err)
+				os.Exit(1)
+			}
+			params := &dto.VaultExportRequest{Vault: vaultName, Folder: folder, Since: since}
+			err = backupService.ExportZip(ctx, uid, params, time.Now(), out)
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+			if err == nil {
+				err = os.Rename(partial, outPath)
+			}
+			if err != nil {
+				_ = os.Remove(partial)
+				_ = os.RemoveAll(tempPath)
+				fmt.Fprintf(os.Stderr, "Error: failed to export vault '%s': %v\n", vaultName, err)
+				os.Exit(1)
+			}
+
+			info, _ := os.Stat(outPath)
+			fmt.Printf("Vault '%s' (uid=%d) exported to %s (%d bytes).\n", vaultName, uid, outPath, info.Size())
+		},
+	}
+
+	var importCmd = &cobra.Command{
+		Use:   "import --uid <uid> --vault <vault> (--in <file.zip> | --dir <vault_dir>) [--folder <folder>] [-c config_file]",
+		Short: "Import the notes and attachments of a ZIP file or an Obsidian vault directory into a vault",
+		// 将 ZIP 文件或 Obsidian 本地保险库目录中的笔记和附件导入保险库，保险库不存在时自动创建，运行前需停止服务
+		Run: func(cmd *cobra.Command, args []string) {
+			if uid <= 0 {
+				bootstrapLogger.Error("uid is required, use --uid flag")
+				os.Exit(1)
+			}
+			if vaultName == "" {
+				bootstrapLogger.Error("vault is required, use --vault flag")
+				os.Exit(1)
+			}
+			if (inPath == "") == (dirPath == "") {
+				bootstrapLogger.Error("exactly one input is required, use --in or --dir flag")
+				os.Exit(1)
+			}
+			source := inPath
+			if dirPath != "" {
+				source = dirPath
+				if info, err := os.Stat(dirPath); err != nil || !info.IsDir() {
+					fmt.Fprintf(os.Stderr, "Error: %s is not a readable directory\n", dirPath)
+					os.Exit(1)
+				}
+			} else if info, err := os.Stat(inPath); err != nil || info.IsDir() {
+				fmt.Fprintf(os.Stderr, "Error: %s is not a readable file\n", inPath)
+				os.Exit(1)
+			}
+
+			appConfig, lg, db := openVaultCommandStore(configPath)
+			tempPath := vaultCommandTempPath(appConfig)
+			appConfig.App.TempPath = tempPath
+
+			// Imported notes go through the same services as a WebGUI import, so folders,
+			// history, search index and sync logs stay consistent
+			// 导入的笔记与 WebGUI 导入走相同的服务，保证文件夹、历史、搜索索引与同步日志一致
+			app, err := internalApp.NewApp(appConfig, lg, db, frontendFiles)
+			if err != nil {
+				bootstrapLogger.Error("failed to create app container", zap.Error(err))
+				os.Exit(1)
+			}
+			ctx, stopDrain := context.WithCancel(context.Background())
+			defer stopDrain()
+			requireVaultCommandUser(ctx, app.Dao, uid)
+			drainNoteHistoryQueue(ctx)
+
+			progress := func(msg *dto.VaultImportProgressMessage) {
+				if !msg.Done {
+					fmt.Fprintf(os.Stderr, "  %d/%d entries processed\n", msg.Processed, msg.Total)
+				}
+			}
+			params := &dto.VaultImportRequest{Vault: vaultName, Folder: folder}
+			importService := app.ImportService.WithClient("cli", internalApp.Name, internalApp.Version)
+			var result *dto.VaultImportResult
+			if dirPath != "" {
+				result, err = importService.ImportDir(ctx, uid, params, dirPath, progress)
+			} else {
+				result, err = importService.ImportZip(ctx, uid, params, inPath, progress)
+			}
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+			defer cancel()
+			if shutdownErr := app.Shutdown(shutdownCtx); shutdownErr != nil {
+				lg.Warn("app shutdown error", zap.Error(shutdownErr))
+			}
+			_ = app.Close()
+
+			_ = os.RemoveAll(tempPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: failed to import into vault '%s': %v\n", vaultName, err)
+				os.Exit(1)
+			}
+			fmt.Printf("Imported %s into vault '%s' (uid=%d): %d notes, %d files, %d folders, %d skipped, %d failed.\n",
+				source, vaultName, uid, result.Notes, result.Files, result.Folders, result.Skipped, result.Failed)
+			for _, e := range result.Errors {
+				fmt.Fprintf(os.Stderr, "  failed: %s\n", e)
+			}
+			if result.Failed > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+
+	var siteCmd = &cobra.Command{
+		Use:   "site --uid <uid> --vault <vault> (--out <dir> | --storage-id <id>) [--folder <folder>] [--title <title>] [-c config_file]",
+		Short: "Render a vault to a static HTML site with backlinks and search",
+		// 将保险库渲染为带反向链接与搜索的静态 HTML 站点，写入本地目录或通过已有的存储目标推送，只读操作
+		Run: func(cmd *cobra.Command, args []string) {
+			if uid <= 0 {
+				bootstrapLogger.Error("uid is required, use --uid flag")
+				os.Exit(1)
+			}
+			if vaultName == "" {
+				bootstrapLogger.Error("vault is required, use --vault flag")
+				os.Exit(1)
+			}
+			if (outPath == "") == (storageID == 0) {
+				bootstrapLogger.Error("exactly one target is required, use --out or --storage-id flag")
+				os.Exit(1)
+			}
+
+			ctx := context.Background()
+			backupService, tempPath := newVaultCommandBackupService(ctx, configPath, uid)
+			defer os.RemoveAll(tempPath)
+
+			params := &dto.VaultSiteExportRequest{Vault: vaultName, Folder: folder, Title: title, StorageID: storageID, OutDir: outPath}
+			result, err := backupService.ExportSite(ctx, uid, params)
+			if err != nil {
+				_ = os.RemoveAll(tempPath)
+				fmt.Fprintf(os.Stderr, "Error: failed to export vault '%s' as a site: %v\n", vaultName, err)
+				os.Exit(1)
+			}
+			fmt.Printf("Vault '%s' (uid=%d) rendered to %s: %d pages, %d files.\n", vaultName, uid, result.Target, result.Pages, result.Files)
+		},
+	}
+
+	vaultCmd.AddCommand(exportCmd, importCmd, siteCmd)
+	rootCmd.AddCommand(vaultCmd)
+	pfs := vaultCmd.PersistentFlags()
+	pfs.StringVarP(&configPath, "config", "c", "", "config file path (default: config/config.yaml)")
+	pfs.Int64Var(&uid, "uid", 0, "owner of the vault (required)")
+	pfs.StringVar(&vaultName, "vault", "", "vault name (required)")
+	pfs.StringVar(&folder, "folder", "", "only export this folder / import into this folder, empty for the vault root")
+	exportCmd.Flags().StringVar(&outPath, "out", "", "output ZIP file (required)")
+	exportCmd.Flags().Int64Var(&since, "since", 0, "only export entries changed after this timestamp (ms)")
+	importCmd.Flags().StringVar(&inPath, "in", "", "input ZIP file")
+	siteCmd.Flags().StringVar(&outPath, "out", "", "output directory of the site")
+	siteCmd.Flags().Int64Var(&storageID, "storage-id", 0, "push the site through this storage target of the user instead")
+	siteCmd.Flags().StringVar(&title, "title", "", "site title, default the vault or folder name")
+	importCmd.Flags().StringVar(&dirPath, "dir", "", "input Obsidian vault directory, hidden folders such as .obsidian are skipped")
+}
+
+// newVaultCommandBackupService builds the backup service used by the export commands, with content
+// decrypted and redacted like a WebGUI export. The caller removes the returned temp path when done.
+// newVaultCommandBackupService 构建导出命令使用的备份服务，内容的解密与脱敏与 WebGUI 导出一致。调用方结束后需删除返回的临时路径
+func newVaultCommandBackupService(ctx context.Context, configPath string, uid int64) (service.BackupService, string) {
+	appConfig, lg, db := openVaultCommandStore(configPath)
+	dbConfig := appConfig.Database
+	dbConfig.RunMode = appConfig.Server.RunMode
+	daoObj := dao.New(db, ctx, dao.WithConfig(&dbConfig), dao.WithLogger(lg))
+	requireVaultCommandUser(ctx, daoObj, uid)
+
+	// Exported content is decrypted and redacted exactly like a WebGUI export
+	// 导出内容的解密与脱敏与 WebGUI 导出完全一致
+	keyring, err := appConfig.GetEncryptionKeyring()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	atrest.SetDefault(keyring)
+	redactor, err := redact.New(redact.Rules{
+		FrontmatterKeys: appConfig.Export.Redaction.FrontmatterKeys,
+		ExcludeFolders:  appConfig.Export.Redaction.ExcludeFolders,
+		MaskPatterns:    appConfig.Export.Redaction.MaskPatterns,
+		Mask:            appConfig.Export.Redaction.Mask,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid export redaction rules: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The backup service sweeps its staging directory on construction, a per-run temp path
+	// keeps a running server and concurrent exports out of reach
+	// 备份服务构造时会清空暂存目录，每次运行使用独立的临时路径，避免影响运行中的服务和并发的导出
+	tempPath := vaultCommandTempPath(appConfig)
+	storageService := service.NewStorageService(dao.NewStorageRepository(daoObj), &appConfig.Storage)
+	backupService := service.NewBackupService(
+		dao.NewBackupRepository(daoObj), dao.NewBackupBlobRepository(daoObj),
+		dao.NewNoteRepository(daoObj), dao.NewFolderRepository(daoObj), dao.NewFileRepository(daoObj), dao.NewVaultRepository(daoObj),
+		storageService, &appConfig.Storage, redactor, tempPath, lg,
+	)
+	return backupService, tempPath
+}
+
+// openVaultCommandStore loads the configuration, logger and database of the vault commands, exiting on failure
+// openVaultCommandStore 加载保险库命令所需的配置、日志器与数据库，失败时退出
+func openVaultCommandStore(configPath string) (*internalApp.AppConfig, *zap.Logger, *gorm.DB) {
+	if configPath == "" {
+		if fileurl.IsExist("config/config-dev.yaml") {
+			configPath = "config/config-dev.yaml"
+		} else if fileurl.IsExist("config.yaml") {
+			configPath = "config.yaml"
+		} else {
+			configPath = "config/config.yaml"
+		}
+	}
+
+	appConfig, configRealpath, err := internalApp.LoadConfig(configPath)
+	if err != nil {
+		bootstrapLogger.Error("failed to load config", zap.Error(err))
+		os.Exit(1)
+	}
+	bootstrapLogger.Info("loading config", zap.String("path", configRealpath))
+
+	lg, err := logger.NewLogger(logger.Config{
+		Level:      appConfig.Log.Level,
+		File:       appConfig.Log.File,
+		Production: appConfig.Log.Production,
+	})
+	if err != nil {
+		bootstrapLogger.Error("failed to init logger", zap.Error(err))
+		os.Exit(1)
+	}
+
+	dbConfig := appConfig.Database
+	dbConfig.RunMode = appConfig.Server.RunMode
+	db, err := dao.NewEngine(dbConfig, lg)
+	if err != nil {
+		bootstrapLogger.Error("failed to init database", zap.Error(err))
+		os.Exit(1)
+	}
+	return appConfig, lg, db
+}
+
+// requireVaultCommandUser exits unless uid belongs to an active user
+// requireVaultCommandUser 当 uid 不属于活跃用户时退出
+func requireVaultCommandUser(ctx context.Context, daoObj *dao.Dao, uid int64) {
+	if _, err := dao.NewUserRepository(daoObj).GetByUID(ctx, uid, true); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			fmt.Fprintf(os.Stderr, "Error: user uid=%d not found\n", uid)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: failed to query user: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// drainNoteHistoryQueue discards the delayed history messages of a command run, which would otherwise fill the
+// queue and block writes. The history task of the service resumes notes whose snapshot differs from their content on start.
+// drainNoteHistoryQueue 丢弃命令运行期间的历史延时消息，否则队列写满后会阻塞写入。服务启动时历史任务会恢复快照与正文不一致的笔记。
+func drainNoteHistoryQueue(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-service.NoteHistoryChannel:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// vaultCommandTempPath returns a temp directory private to this run
+// vaultCommandTempPath 返回本次运行独占的临时目录
+func vaultCommandTempPath(appConfig *internalApp.AppConfig) string {
+	tempPath := appConfig.App.TempPath
+	if tempPath == "" {
+		tempPath = "storage/temp"
+	}
+	return filepath.Join(tempPath, "cli", uuid.New().String())
+}
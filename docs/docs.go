@@ -23,13 +23,142 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/admin/check": {
+        "/api/admin/audit": {
             "get": {
+                "description": "List who did what (logins, note deletes, config changes, backup runs, user deletes) with IP and client, newest first, requires admin privileges",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "List audit log",
+                "parameters": [
+                    {
+                        "enum": [
+                            "user.login",
+                            "user.login_failed",
+                            "user.login_locked",
+                            "user.delete",
+                            "user.approve",
+                            "note.delete",
+                            "note.restore",
+                            "file.restore",
+                            "vault.transfer",
+                            "admin.config_update",
+                            "admin.read_only",
+                            "admin.sync_trace",
+                            "admin.job_requeue",
+                            "admin.task_run",
+                            "backup.execute"
+                        ],
+                        "type": "string",
+                        "example": "note.delete",
+                        "description": "Action // 操作",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1800000000000,
+                        "description": "End time (ms, exclusive) // 结束时间（毫秒，不含）",
+                        "name": "endTime",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 64,
+                        "type": "string",
+                        "example": "127.0.0.1",
+                        "description": "Request IP // 请求 IP",
+                        "name": "ip",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1700000000000,
+                        "description": "Start time (ms, inclusive) // 开始时间（毫秒，含）",
+                        "name": "startTime",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Acting user ID // 操作用户 ID",
+                        "name": "uid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number // 页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size // 每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.AuditLogDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
+                ]
+            }
+        },
+        "/api/admin/check": {
+            "get": {
                 "description": "Check if the current logged-in user has system admin privileges",
                 "produces": [
                     "application/json"
@@ -57,16 +186,16 @@ const docTemplate = `{
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/admin/cloudflared_tunnel_download": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
+                ]
+            }
+        },
+        "/api/admin/cloudflared_tunnel_download": {
+            "get": {
                 "description": "Trigger the download of cloudflared binary for the current platform",
                 "produces": [
                     "application/json"
@@ -82,16 +211,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/admin/config": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
+                ]
+            }
+        },
+        "/api/admin/config": {
+            "get": {
                 "description": "Get full system configuration information, requires admin privileges",
                 "produces": [
                     "application/json"
@@ -125,14 +254,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
+                ]
+            },
+            "post": {
                 "description": "Modify full system configuration information, requires admin privileges",
                 "consumes": [
                     "application/json"
@@ -180,24 +309,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/admin/config/cloudflare": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get Cloudflare tunnel configuration, requires admin privileges",
+                ]
+            }
+        },
+        "/api/admin/config/branding": {
+            "get": {
+                "description": "Get the instance branding shown by the WebGUI, share and login pages, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Config"
                 ],
-                "summary": "Get Cloudflare config",
+                "summary": "Get branding config",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -210,7 +339,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.AdminCloudflareConfig"
+                                            "$ref": "#/definitions/dto.AdminBrandingConfig"
                                         }
                                     }
                                 }
@@ -223,15 +352,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Modify Cloudflare tunnel configuration, requires admin privileges",
+                ]
+            },
+            "post": {
+                "description": "Modify the instance name, logo, colors, login page message and custom CSS, requires admin privileges",
                 "consumes": [
                     "application/json"
                 ],
@@ -241,7 +370,7 @@ const docTemplate = `{
                 "tags": [
                     "Config"
                 ],
-                "summary": "Update Cloudflare config",
+                "summary": "Update branding config",
                 "parameters": [
                     {
                         "description": "Config Parameters",
@@ -249,7 +378,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.AdminCloudflareConfig"
+                            "$ref": "#/definitions/dto.AdminBrandingConfig"
                         }
                     }
                 ],
@@ -265,7 +394,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.AdminCloudflareConfig"
+                                            "$ref": "#/definitions/dto.AdminBrandingConfig"
                                         }
                                     }
                                 }
@@ -278,24 +407,36 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/admin/config/user_database": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/admin/config/branding/logo": {
+            "post": {
+                "description": "Upload a PNG, JPEG, GIF, WebP or ICO logo (max 1 MiB) used as logo and favicon, requires admin privileges",
+                "consumes": [
+                    "multipart/form-data"
                 ],
-                "description": "Get user database configuration information, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Config"
                 ],
-                "summary": "Get user database config",
+                "summary": "Upload branding logo",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Logo image",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -308,7 +449,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.AdminUserDatabaseConfig"
+                                            "$ref": "#/definitions/dto.AdminBrandingConfig"
                                         }
                                     }
                                 }
@@ -321,36 +462,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Modify user database configuration information, requires admin privileges",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/admin/config/cloudflare": {
+            "get": {
+                "description": "Get Cloudflare tunnel configuration, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Config"
                 ],
-                "summary": "Update user database config",
-                "parameters": [
-                    {
-                        "description": "Config Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.AdminUserDatabaseConfig"
-                        }
-                    }
-                ],
+                "summary": "Get Cloudflare config",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -363,7 +492,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.AdminUserDatabaseConfig"
+                                            "$ref": "#/definitions/dto.AdminCloudflareConfig"
                                         }
                                     }
                                 }
@@ -376,17 +505,15 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/admin/config/user_database/test": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Test if the provided database configuration can connect successfully, requires admin privileges",
+                ]
+            },
+            "post": {
+                "description": "Modify Cloudflare tunnel configuration, requires admin privileges",
                 "consumes": [
                     "application/json"
                 ],
@@ -396,7 +523,7 @@ const docTemplate = `{
                 "tags": [
                     "Config"
                 ],
-                "summary": "Test user database connection",
+                "summary": "Update Cloudflare config",
                 "parameters": [
                     {
                         "description": "Config Parameters",
@@ -404,7 +531,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.AdminUserDatabaseConfig"
+                            "$ref": "#/definitions/dto.AdminCloudflareConfig"
                         }
                     }
                 ],
@@ -412,89 +539,100 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AdminCloudflareConfig"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Connection failed",
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/admin/gc": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Manually run Go runtime GC and release memory to OS, requires admin privileges",
+                ]
+            }
+        },
+        "/api/admin/config/user_database": {
+            "get": {
+                "description": "Get user database configuration information, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Config"
                 ],
-                "summary": "Trigger manual GC",
+                "summary": "Get user database config",
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "403": {
-                        "description": "Insufficient privileges",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AdminUserDatabaseConfig"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/admin/restart": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            },
+            "post": {
+                "description": "Modify user database configuration information, requires admin privileges",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Gracefully restart the server",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Config"
                 ],
-                "summary": "Trigger server restart",
-                "responses": {
-                    "200": {
-                        "description": "Success",
+                "summary": "Update user database config",
+                "parameters": [
+                    {
+                        "description": "Config Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "$ref": "#/definitions/dto.AdminUserDatabaseConfig"
                         }
                     }
-                }
-            }
-        },
-        "/api/admin/system/info": {
-            "get": {
-                "security": [
-                    {
-                        "UserAuthToken": []
-                    }
                 ],
-                "description": "Get server runtime, CPU, memory, host and process info, requires admin privileges",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "System"
-                ],
-                "summary": "Get system stats",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -507,38 +645,49 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.AdminSystemInfo"
+                                            "$ref": "#/definitions/dto.AdminUserDatabaseConfig"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/admin/upgrade": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/admin/config/user_database/test": {
+            "post": {
+                "description": "Test if the provided database configuration can connect successfully, requires admin privileges",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Download latest version and restart server",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Config"
                 ],
-                "summary": "Trigger server upgrade",
+                "summary": "Test user database connection",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Version to upgrade (e.g. 2.0.10 or latest)",
-                        "name": "version",
-                        "in": "query",
-                        "required": true
+                        "description": "Config Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AdminUserDatabaseConfig"
+                        }
                     }
                 ],
                 "responses": {
@@ -547,39 +696,31 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
+                    },
+                    "400": {
+                        "description": "Connection failed",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/admin/users/create": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Create a new user, requires admin privileges",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/admin/db-export": {
+            "get": {
+                "description": "List the transaction-consistent database copies in the export directory that host backup tools should back up, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Config"
-                ],
-                "summary": "Create a new user",
-                "parameters": [
-                    {
-                        "description": "Config Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UserCreateRequest"
-                        }
-                    }
+                    "System"
                 ],
+                "summary": "Get the hot database export",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -592,7 +733,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.UserDTO"
+                                            "$ref": "#/definitions/dto.DatabaseExportDTO"
                                         }
                                     }
                                 }
@@ -605,38 +746,22 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/admin/users/list": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Handle request to get all users.",
+                ]
+            },
+            "post": {
+                "description": "Copy every SQLite database into the export directory with VACUUM INTO now, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Config"
-                ],
-                "summary": "Get all users",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Page number // 页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page size // 每页数量",
-                        "name": "pageSize",
-                        "in": "query"
-                    }
+                    "System"
                 ],
+                "summary": "Refresh the hot database export",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -649,64 +774,49 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "allOf": [
-                                                {
-                                                    "$ref": "#/definitions/app.ListRes"
-                                                },
-                                                {
-                                                    "type": "object",
-                                                    "properties": {
-                                                        "list": {
-                                                            "type": "array",
-                                                            "items": {
-                                                                "$ref": "#/definitions/dto.UserDTO"
-                                                            }
-                                                        }
-                                                    }
-                                                }
-                                            ]
+                                            "$ref": "#/definitions/dto.DatabaseExportDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Export failed or already running",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/admin/users/update": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Update a user, requires admin privileges",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/admin/file-gc": {
+            "get": {
+                "description": "List the attachment folders that no file record references (soft deleted records still count) and which of them the daily garbage collection would remove after the grace period, without removing anything, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Config"
+                    "System"
                 ],
-                "summary": "Update a user",
+                "summary": "Attachment garbage collection dry run",
                 "parameters": [
                     {
-                        "description": "Config Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UserUpdateRequest"
-                        }
+                        "type": "integer",
+                        "description": "User ID, 0 checks every user",
+                        "name": "uid",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -721,7 +831,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.UserDTO"
+                                            "$ref": "#/definitions/dto.FileGCReportDTO"
                                         }
                                     }
                                 }
@@ -734,33 +844,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/admin/ws_client/{traceId}": {
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Kick a WebSocket client by TraceID, requires admin privileges",
+                ]
+            }
+        },
+        "/api/admin/gc": {
+            "get": {
+                "description": "Manually run Go runtime GC and release memory to OS, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "System"
                 ],
-                "summary": "Kick a WebSocket client",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Trace ID of the client",
-                        "name": "traceId",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "Trigger manual GC",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -774,29 +875,69 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/admin/ws_clients": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get a list of all current WebSocket connections, requires admin privileges",
+                ]
+            }
+        },
+        "/api/admin/ip_ban/{ip}": {
+            "delete": {
+                "description": "Lift a temporary IP ban created by the honeypot, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "System"
                 ],
-                "summary": "Get connected WebSocket clients",
-                "responses": {
-                    "200": {
-                        "description": "Success",
-                        "schema": {
-                            "allOf": [
+                "summary": "Unban an IP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Banned IP",
+                        "name": "ip",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/ip_bans": {
+            "get": {
+                "description": "Get the IPs temporarily banned by the honeypot, requires admin privileges",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get banned IPs",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
                                 {
                                     "$ref": "#/definitions/app.Res"
                                 },
@@ -806,7 +947,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/app.WSClientInfo"
+                                                "$ref": "#/definitions/ipfilter.Ban"
                                             }
                                         }
                                     }
@@ -820,38 +961,81 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/auth/logout": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/admin/jobs": {
+            "get": {
+                "description": "Return the state of the background job queue (folder binding, vault counts, link/property/word count indexing, note history, ...) with per-kind counters since the server started and the jobs that are not done: queued, running, waiting for a retry, or failed after the last attempt; requires admin privileges. Finished jobs are not kept.\n返回后台任务队列（文件夹绑定、笔记库统计、链接/属性/字数索引、笔记历史等）的状态、服务启动以来按类型的计数，以及未完成的任务：排队中、运行中、等待重试或用完重试次数后失败的任务，需要管理员权限。已完成的任务不会保留。",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Handle user logout HTTP request, revoke current auth token.\n处理用户退出登录 HTTP 请求，注销当前认证 Token。",
                 "tags": [
-                    "User"
+                    "System"
+                ],
+                "summary": "List background jobs",
+                "parameters": [
+                    {
+                        "enum": [
+                            "queued",
+                            "running",
+                            "retrying",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "example": "failed",
+                        "description": "Only jobs in this state, empty for all // 仅返回该状态的任务，为空时返回全部",
+                        "name": "state",
+                        "in": "query"
+                    }
                 ],
-                "summary": "User logout",
                 "responses": {
                     "200": {
                         "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.JobQueueStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/backup/config": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
+                ]
+            }
+        },
+        "/api/admin/jobs/requeue": {
+            "post": {
+                "description": "Queue failed background jobs again with fresh attempts, every failed job when ids is empty; requires admin privileges. Returns the requeued jobs.\n以重置的重试次数重新排队失败的后台任务，ids 为空时重新排队全部失败任务，需要管理员权限。返回重新排队的任务。",
                 "consumes": [
                     "application/json"
                 ],
@@ -859,17 +1043,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Backup"
+                    "System"
                 ],
-                "summary": "Update backup configuration",
+                "summary": "Requeue failed background jobs",
                 "parameters": [
                     {
-                        "description": "Backup Parameters",
+                        "description": "Jobs to Requeue",
                         "name": "params",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.BackupConfigRequest"
+                            "$ref": "#/definitions/dto.JobRequeueRequest"
                         }
                     }
                 ],
@@ -885,7 +1068,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.BackupConfigDTO"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.JobDTO"
+                                            }
                                         }
                                     }
                                 }
@@ -898,39 +1084,109 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     },
-                    "401": {
-                        "description": "Token Required",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
+                ]
+            }
+        },
+        "/api/admin/logs": {
+            "get": {
+                "description": "Query the recent server log entries kept in memory (log.buffer-size) by level, time, uid, module and message text, oldest first, requires admin privileges.\nWith follow=true the response is a server-sent event stream: the matching entries first, then every new one as an event named log whose id is the sequence number; idle streams get a comment every 15 seconds.\nThe stream ends with the request timeout; an EventSource reconnects by itself and the Last-Event-ID header resumes after the last entry received.\n按级别、时间、uid、模块与消息文本查询内存中保留的最近服务器日志（log.buffer-size），按时间升序，需要管理员权限。\nfollow=true 时以服务器推送事件流响应：先推送匹配的条目，再将每条新条目作为名为 log、id 为序号的事件推送；空闲时每 15 秒发送一条注释。\n事件流随请求超时结束；EventSource 会自动重连，并通过 Last-Event-ID 请求头从最后收到的条目之后继续。",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "Backup"
+                    "System"
                 ],
-                "summary": "Delete backup configuration",
+                "summary": "Query server logs",
                 "parameters": [
                     {
+                        "type": "integer",
+                        "example": 0,
+                        "description": "Only entries after this sequence number, for polling // 仅返回该序号之后的条目，用于轮询",
+                        "name": "afterSeq",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1800000000000,
+                        "description": "End time (ms, exclusive) // 结束时间（毫秒，不含）",
+                        "name": "endTime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Keep streaming new entries as server-sent events // 以服务器推送事件持续推送新条目",
+                        "name": "follow",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 200,
+                        "type": "string",
+                        "example": "OnMessage",
+                        "description": "Text in the message, case-insensitive // 消息中包含的文本，不区分大小写",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "debug",
+                            "info",
+                            "warn",
+                            "error",
+                            "dpanic",
+                            "panic",
+                            "fatal"
+                        ],
+                        "type": "string",
+                        "example": "warn",
+                        "description": "Lowest level included // 包含的最低级别",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 200,
+                        "description": "Newest entries returned, default 200 // 返回的最新条目数，默认 200",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 100,
+                        "type": "string",
+                        "example": "WebsocketServer",
+                        "description": "Logger name or first word of the message // 日志器名称或消息的第一个词",
+                        "name": "module",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1700000000000,
+                        "description": "Start time (ms, inclusive) // 开始时间（毫秒，含）",
+                        "name": "startTime",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
                         "type": "integer",
                         "example": 1,
-                        "description": "ID // ID",
-                        "name": "id",
+                        "description": "Value of the uid field // uid 字段的值",
+                        "name": "uid",
                         "in": "query"
                     }
                 ],
@@ -938,7 +1194,22 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.LogEntryDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -947,35 +1218,30 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     },
-                    "401": {
-                        "description": "Token Required",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/backup/configs": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
+                ]
+            }
+        },
+        "/api/admin/maintenance": {
+            "get": {
+                "description": "Get the open or next maintenance window and the heavy jobs (full backups, cleanup, upgrades) queued for it, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Backup"
+                    "System"
                 ],
-                "summary": "Get backup configurations",
+                "summary": "Get maintenance window status",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -988,120 +1254,149 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.BackupConfigDTO"
-                                            }
+                                            "$ref": "#/definitions/dto.MaintenanceStatusDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "401": {
-                        "description": "Token Required",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/backup/execute": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/admin/maintenance/run": {
+            "post": {
+                "description": "Start the selected queued jobs (all of them when ids is empty) without waiting for the maintenance window, requires admin privileges",
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Backup"
+                    "System"
                 ],
-                "summary": "Trigger a backup manually",
+                "summary": "Run queued maintenance jobs now",
                 "parameters": [
                     {
-                        "description": "Backup Execute Parameters",
+                        "description": "Job IDs",
                         "name": "params",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.BackupExecuteRequest"
+                            "$ref": "#/definitions/dto.MaintenanceRunRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success",
+                        "description": "IDs of the started jobs",
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid Params",
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
-                    },
-                    "401": {
-                        "description": "Token Required",
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/read-only": {
+            "get": {
+                "description": "Get whether the server refuses changes, set by the admin or held by a running upgrade, restore or vault transfer, requires admin privileges\n获取服务器是否拒绝修改（由管理员开启，或由正在进行的升级、恢复、保险库转移持有），需要管理员权限",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get read-only mode status",
+                "responses": {
+                    "200": {
+                        "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ReadOnlyStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/backup/historys": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            },
+            "post": {
+                "description": "Switch the server into or out of read-only maintenance mode, requires admin privileges. Clients keep connecting and downloading, every change over the API, WebSocket and WebDAV is refused with a maintenance code and kept by the client until the mode ends. The admin API stays writable. The server remains read-only while an upgrade, restore or vault transfer holds it.\n切换服务器的只读维护模式，需要管理员权限。客户端仍可连接与下载，通过 API、WebSocket、WebDAV 的所有修改均以维护错误码拒绝，由客户端保留至维护结束。管理 API 仍可写。升级、恢复或保险库转移进行中时服务器保持只读。",
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Backup"
+                    "System"
                 ],
-                "summary": "Get backup history list",
+                "summary": "Switch read-only mode",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "Config ID // 配置 ID",
-                        "name": "configId",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "Page number // 页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "example": 10,
-                        "description": "Page size // 每页大小",
-                        "name": "pageSize",
-                        "in": "query"
+                        "description": "Read-only Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReadOnlyRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1116,22 +1411,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "allOf": [
-                                                {
-                                                    "$ref": "#/definitions/app.ListRes"
-                                                },
-                                                {
-                                                    "type": "object",
-                                                    "properties": {
-                                                        "list": {
-                                                            "type": "array",
-                                                            "items": {
-                                                                "$ref": "#/definitions/dto.BackupHistoryDTO"
-                                                            }
-                                                        }
-                                                    }
-                                                }
-                                            ]
+                                            "$ref": "#/definitions/dto.ReadOnlyStatusDTO"
                                         }
                                     }
                                 }
@@ -1139,146 +1419,103 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid Params",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "401": {
-                        "description": "Token Required",
+                        "description": "Invalid Parameters",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/file": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get raw binary data of an attachment by path, supports strong cache control",
+                ]
+            }
+        },
+        "/api/admin/reindex": {
+            "get": {
+                "description": "Get the latest background full-text index rebuild job of every user, or of one user when uid is given, requires admin privileges",
                 "produces": [
-                    "application/octet-stream"
+                    "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "System"
                 ],
-                "summary": "Get attachment content",
+                "summary": "Get full-text reindex jobs",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "ctx123",
-                        "description": "Context // 同步上下文",
-                        "name": "context",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "example": false,
-                        "description": "Is in recycle bin // 是否在回收站",
-                        "name": "isRecycle",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Image.png",
-                        "description": "File path // 文件路径",
-                        "name": "path",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "fhash123",
-                        "description": "Path hash // 路径哈希",
-                        "name": "pathHash",
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "uid",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "type": "file"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.ReindexJobDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Upload a file as an attachment",
+                ]
+            },
+            "post": {
+                "description": "Queue a background rebuild of the full-text index of all vaults of a user, or of every user when uid is 0. A user with an active job keeps it. Requires admin privileges",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "System"
                 ],
-                "summary": "Upload attachment",
+                "summary": "Start full-text reindex",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Vault name",
-                        "name": "vault",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "File path",
-                        "name": "path",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Creation timestamp",
-                        "name": "ctime",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Modification timestamp",
-                        "name": "mtime",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "File to upload",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "description": "User ID",
+                        "name": "params",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReindexRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success",
+                        "description": "Queued jobs",
                         "schema": {
                             "allOf": [
                                 {
@@ -1288,65 +1525,57 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.FileDTO"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.ReindexJobDTO"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/admin/reindex/cancel": {
+            "post": {
+                "description": "Cancel the queued or running full-text index rebuild job of a user, requires admin privileges",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Permanently delete a specific attachment record and its physical file",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "System"
                 ],
-                "summary": "Delete attachment",
+                "summary": "Cancel full-text reindex",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "ctx123",
-                        "description": "Context // 同步上下文",
-                        "name": "context",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Image.png",
-                        "description": "File path // 文件路径",
-                        "name": "path",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "fhash123",
-                        "description": "Path hash // 路径哈希",
-                        "name": "pathHash",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
-                        "in": "query",
-                        "required": true
+                        "description": "User ID",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReindexRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success",
+                        "description": "Canceled job",
                         "schema": {
                             "allOf": [
                                 {
@@ -1356,70 +1585,62 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.FileDTO"
+                                            "$ref": "#/definitions/dto.ReindexJobDTO"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/file/info": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get attachment metadata (FileDTO) by path",
+                ]
+            }
+        },
+        "/api/admin/restart": {
+            "get": {
+                "description": "Gracefully restart the server",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "System"
                 ],
-                "summary": "Get attachment info",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "example": "ctx123",
-                        "description": "Context // 同步上下文",
-                        "name": "context",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "example": false,
-                        "description": "Is in recycle bin // 是否在回收站",
-                        "name": "isRecycle",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Image.png",
-                        "description": "File path // 文件路径",
-                        "name": "path",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "fhash123",
-                        "description": "Path hash // 路径哈希",
-                        "name": "pathHash",
-                        "in": "query"
-                    },
+                "summary": "Trigger server restart",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
                     {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
-                        "in": "query",
-                        "required": true
+                        "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/admin/schedules": {
+            "get": {
+                "description": "List the scheduled tasks (DbCleanup, LocalSnapshot, BackupScheduled, ...) with their built-in and effective schedules, last run and next run, requires admin privileges. In a cluster only the instance running the tasks lists them.\n列出定时任务（DbCleanup、LocalSnapshot、BackupScheduled 等）的内置调度与生效调度、上次执行与下次执行情况，需要管理员权限。集群部署时只有执行任务的实例会列出任务。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
                 ],
+                "summary": "List scheduled tasks",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -1432,24 +1653,31 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.FileDTO"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.ScheduleDTO"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/file/recycle-clear": {
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Permanently clear selected files from recycle bin",
+                ]
+            },
+            "put": {
+                "description": "Replace the built-in interval of a task with a 5-field cron expression (server local time), a descriptor such as @daily or @every 90m, or off to stop its scheduled runs; an empty schedule restores the built-in interval. The schedule is saved to task.schedules in the configuration file and takes effect right away; requires admin privileges.\n用 5 段 cron 表达式（服务器本地时间）、@daily 或 @every 90m 等描述符替换任务的内置执行间隔，off 停止定时执行；为空时恢复内置间隔。调度保存到配置文件的 task.schedules 并立即生效，需要管理员权限。",
                 "consumes": [
                     "application/json"
                 ],
@@ -1457,38 +1685,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "System"
                 ],
-                "summary": "Clear recycle bin",
+                "summary": "Update a task schedule",
                 "parameters": [
                     {
-                        "description": "Clear Parameters",
+                        "description": "Task Schedule",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.FileRecycleClearRequest"
+                            "$ref": "#/definitions/dto.ScheduleUpdateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ScheduleDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/file/rename": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Rename an attachment to a new path",
+                ]
+            }
+        },
+        "/api/admin/schedules/run": {
+            "post": {
+                "description": "Run a task right away, outside its schedule; heavy tasks run even outside the maintenance window. The request returns once the run started, GET /api/admin/schedules shows its outcome; requires admin privileges.\n立即执行任务，不受调度限制；重型任务在维护窗口外也会执行。请求在执行开始后返回，可通过 GET /api/admin/schedules 查看结果，需要管理员权限。",
                 "consumes": [
                     "application/json"
                 ],
@@ -1496,17 +1748,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "System"
                 ],
-                "summary": "Rename attachment",
+                "summary": "Run a scheduled task now",
                 "parameters": [
                     {
-                        "description": "Rename Parameters",
+                        "description": "Task",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.FileRenameRequest"
+                            "$ref": "#/definitions/dto.ScheduleRunRequest"
                         }
                     }
                 ],
@@ -1522,42 +1774,43 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.FileDTO"
+                                            "$ref": "#/definitions/dto.ScheduleDTO"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/file/restore": {
-            "put": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Restore deleted attachment from trash",
+                ]
+            }
+        },
+        "/api/admin/snapshots": {
+            "get": {
+                "description": "List the local snapshots of the databases and content folders, newest first, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
-                ],
-                "summary": "Restore attachment",
-                "parameters": [
-                    {
-                        "description": "Restore Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.FileRestoreRequest"
-                        }
-                    }
+                    "System"
                 ],
+                "summary": "List local snapshots",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -1570,78 +1823,152 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.FileDTO"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.SnapshotDTO"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/files": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get attachment list for current user with pagination, search, filter, and sort support",
+                ]
+            },
+            "post": {
+                "description": "Snapshot every SQLite database and the content folders now and rotate old snapshots, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "File"
+                    "System"
                 ],
-                "summary": "Get file list",
-                "parameters": [
-                    {
-                        "type": "boolean",
-                        "example": false,
-                        "description": "Is in recycle bin // 是否在回收站",
-                        "name": "isRecycle",
-                        "in": "query"
+                "summary": "Take a local snapshot",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.SnapshotDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
                     },
-                    {
-                        "type": "string",
-                        "example": "vacation",
-                        "description": "Search keyword // 搜索关键词",
-                        "name": "keyword",
-                        "in": "query"
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
+                    "500": {
+                        "description": "Snapshot failed or already running",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
                     {
-                        "type": "string",
-                        "example": "mtime",
-                        "description": "Sort by field // 排序字段",
-                        "name": "sortBy",
-                        "in": "query"
-                    },
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/snapshots/restore": {
+            "post": {
+                "description": "Schedule the snapshot to be restored and restart the server; the replaced data is kept in a pre-restore directory next to the snapshots, requires admin privileges",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Restore a local snapshot",
+                "parameters": [
                     {
-                        "type": "string",
-                        "example": "desc",
-                        "description": "Sort order // 排序顺序",
-                        "name": "sortOrder",
-                        "in": "query"
+                        "description": "Snapshot name",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SnapshotRestoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
-                        "in": "query",
-                        "required": true
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "Page number // 页码",
-                        "name": "page",
-                        "in": "query"
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
+                    "500": {
+                        "description": "Snapshot not found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/stats": {
+            "get": {
+                "description": "Per user note/file counts and storage bytes, active WebSocket connections, sync events per hour and backup success rates over 24h, 7d and 30d, requires admin privileges",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get admin dashboard statistics",
+                "parameters": [
                     {
+                        "maximum": 168,
+                        "minimum": 1,
                         "type": "integer",
-                        "description": "Page size // 每页数量",
-                        "name": "pageSize",
+                        "example": 24,
+                        "description": "Window of the hourly sync event series, default 24 // 按小时同步事件序列的时间范围，默认 24",
+                        "name": "hours",
                         "in": "query"
                     }
                 ],
@@ -1657,70 +1984,37 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "allOf": [
-                                                {
-                                                    "$ref": "#/definitions/app.ListRes"
-                                                },
-                                                {
-                                                    "type": "object",
-                                                    "properties": {
-                                                        "list": {
-                                                            "type": "array",
-                                                            "items": {
-                                                                "$ref": "#/definitions/dto.FileDTO"
-                                                            }
-                                                        }
-                                                    }
-                                                }
-                                            ]
+                                            "$ref": "#/definitions/dto.AdminStatsDTO"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/folder": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get folder info for current user by path or pathHash",
+                ]
+            }
+        },
+        "/api/admin/storage-usage": {
+            "get": {
+                "description": "Per user bytes on disk for note content files, attachments, history snapshots, settings, in-progress backups and the SQLite databases, with the content size of each vault, largest user first. The numbers are kept up to date incrementally instead of walking the storage on every call. Requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Folder"
-                ],
-                "summary": "Get folder info",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "example": "Projects/Work",
-                        "description": "Folder path // 文件夹路径",
-                        "name": "path",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "fhash123",
-                        "description": "Path hash // 路径哈希",
-                        "name": "pathHash",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
-                        "in": "query",
-                        "required": true
-                    }
+                    "System"
                 ],
+                "summary": "Get storage usage",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -1733,43 +2027,37 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.FolderDTO"
+                                            "$ref": "#/definitions/dto.StorageUsageDTO"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Create a new folder or restore a deleted one by path",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/admin/sync-trace": {
+            "get": {
+                "description": "List the users whose sync is being traced to a separate file, requires admin privileges\n列出同步正被跟踪写入独立文件的用户，需要管理员权限",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Folder"
-                ],
-                "summary": "Create folder",
-                "parameters": [
-                    {
-                        "description": "Create Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.FolderCreateRequest"
-                        }
-                    }
+                    "System"
                 ],
+                "summary": "List sync traces",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -1782,22 +2070,31 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.FolderDTO"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.SyncTraceDTO"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Soft delete a folder by path or pathHash",
+                ]
+            },
+            "post": {
+                "description": "Write every WebSocket message, UpdateCheck decision and broadcast fan-out of one user to a separate file under log.sync-trace-dir until the trace expires, for debugging user-specific sync loops; requires admin privileges. Starting a traced user again restarts the trace with the new level and duration. Traces live in the memory of this instance and stop with the server.\n将某个用户的每条 WebSocket 消息、UpdateCheck 判定与广播扇出写入 log.sync-trace-dir 下的独立文件，直到跟踪过期，用于排查特定用户的同步循环，需要管理员权限。对已在跟踪的用户再次开启时以新的级别与时长重新开始。跟踪保存在本实例内存中，随服务停止而结束。",
                 "consumes": [
                     "application/json"
                 ],
@@ -1805,98 +2102,81 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Folder"
+                    "System"
                 ],
-                "summary": "Delete folder",
+                "summary": "Start sync tracing for a user",
                 "parameters": [
                     {
-                        "description": "Delete Parameters",
+                        "description": "Trace Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.FolderDeleteRequest"
+                            "$ref": "#/definitions/dto.SyncTraceRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.SyncTraceDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/folder/files": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "List non-deleted files in a specific folder with pagination and sorting",
+                ]
+            },
+            "delete": {
+                "description": "Stop the sync trace of a user before it expires, the trace file is kept; requires admin privileges\n在过期前停止用户的同步跟踪，跟踪文件会保留，需要管理员权限",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Folder"
+                    "System"
                 ],
-                "summary": "List files in folder",
+                "summary": "Stop sync tracing for a user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "Projects",
-                        "description": "Folder path // 文件夹路径",
-                        "name": "path",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "fhash123",
-                        "description": "Path hash // 路径哈希",
-                        "name": "pathHash",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "mtime",
-                        "description": "Sort by field // 排序字段",
-                        "name": "sortBy",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "desc",
-                        "description": "Sort order // 排序顺序",
-                        "name": "sortOrder",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 2,
+                        "description": "Traced user // 被跟踪的用户",
+                        "name": "uid",
                         "in": "query",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number // 页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page size // 每页数量",
-                        "name": "pageSize",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success",
+                        "description": "Whether a trace was running",
                         "schema": {
                             "allOf": [
                                 {
@@ -1906,96 +2186,43 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "allOf": [
-                                                {
-                                                    "$ref": "#/definitions/app.ListRes"
-                                                },
-                                                {
-                                                    "type": "object",
-                                                    "properties": {
-                                                        "list": {
-                                                            "type": "array",
-                                                            "items": {
-                                                                "$ref": "#/definitions/dto.FileDTO"
-                                                            }
-                                                        }
-                                                    }
-                                                }
-                                            ]
+                                            "type": "boolean"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/folder/notes": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "List non-deleted notes in a specific folder with pagination and sorting",
+                ]
+            }
+        },
+        "/api/admin/system/info": {
+            "get": {
+                "description": "Get server runtime, CPU, memory, host and process info, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Folder"
-                ],
-                "summary": "List notes in folder",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "example": "Projects",
-                        "description": "Folder path // 文件夹路径",
-                        "name": "path",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "fhash123",
-                        "description": "Path hash // 路径哈希",
-                        "name": "pathHash",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "mtime",
-                        "description": "Sort by field // 排序字段",
-                        "name": "sortBy",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "desc",
-                        "description": "Sort order // 排序顺序",
-                        "name": "sortOrder",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number // 页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page size // 每页数量",
-                        "name": "pageSize",
-                        "in": "query"
-                    }
+                    "System"
                 ],
+                "summary": "Get system stats",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -2008,122 +2235,83 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "allOf": [
-                                                {
-                                                    "$ref": "#/definitions/app.ListRes"
-                                                },
-                                                {
-                                                    "type": "object",
-                                                    "properties": {
-                                                        "list": {
-                                                            "type": "array",
-                                                            "items": {
-                                                                "$ref": "#/definitions/dto.NoteDTO"
-                                                            }
-                                                        }
-                                                    }
-                                                }
-                                            ]
+                                            "$ref": "#/definitions/dto.AdminSystemInfo"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/folder/tree": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get the complete folder tree structure for a vault",
+                ]
+            }
+        },
+        "/api/admin/upgrade": {
+            "get": {
+                "description": "Download latest version and restart server; outside the maintenance window the upgrade is queued unless forced",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Folder"
+                    "System"
                 ],
-                "summary": "Get folder tree",
+                "summary": "Trigger server upgrade",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "example": 3,
-                        "description": "Tree depth // 树深度",
-                        "name": "depth",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
+                        "description": "Version to upgrade (e.g. 2.0.10 or latest)",
+                        "name": "version",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Upgrade now even outside the maintenance window",
+                        "name": "force",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/app.Res"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/dto.FolderTreeResponse"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/folders": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/admin/users/create": {
+            "post": {
+                "description": "Create a new user, requires admin privileges",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get folder list for current user by parent path or pathHash",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Folder"
+                    "Config"
                 ],
-                "summary": "Get folder list",
+                "summary": "Create a new user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "Projects",
-                        "description": "Folder path // 文件夹路径",
-                        "name": "path",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "fhash123",
-                        "description": "Path hash // 路径哈希",
-                        "name": "pathHash",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
-                        "in": "query",
-                        "required": true
+                        "description": "Config Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -2138,47 +2326,37 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.FolderDTO"
-                                            }
+                                            "$ref": "#/definitions/dto.UserDTO"
                                         }
                                     }
                                 }
                             ]
                         }
-                    }
-                }
-            }
-        },
-        "/api/git-sync/config": {
-            "post": {
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/admin/users/invites": {
+            "get": {
+                "description": "List the registration invitation codes with their uses and expiry, newest first, requires admin privileges\n按时间倒序列出注册邀请码及其使用次数与过期时间，需要管理员权限",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "GitSync"
-                ],
-                "summary": "Update git sync configuration",
-                "parameters": [
-                    {
-                        "description": "Git Sync Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.GitSyncConfigRequest"
-                        }
-                    }
+                    "User"
                 ],
+                "summary": "List invitation codes",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -2191,54 +2369,49 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.GitSyncConfigDTO"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.UserInviteDTO"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Invalid Params",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "401": {
-                        "description": "Token Required",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            },
+            "post": {
+                "description": "Generate an invitation code admitting a limited number of registrations until it expires; it works while open registration is off and skips the approval queue, requires admin privileges\n生成可注册有限次数且到期失效的邀请码；开放注册关闭时同样可用，且无需审核，需要管理员权限",
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "GitSync"
+                    "User"
                 ],
-                "summary": "Delete git sync configuration",
+                "summary": "Create invitation code",
                 "parameters": [
                     {
-                        "description": "Git Sync ID",
+                        "description": "Invitation Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.GitSyncDeleteRequest"
+                            "$ref": "#/definitions/dto.UserInviteCreateRequest"
                         }
                     }
                 ],
@@ -2246,56 +2419,57 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserInviteDTO"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid Params",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "401": {
-                        "description": "Token Required",
+                        "description": "Invalid Parameters",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/git-sync/config/clean": {
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            },
+            "delete": {
+                "description": "Delete an invitation code so it admits no further registrations, requires admin privileges\n删除邀请码，使其不再可用于注册，需要管理员权限",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "GitSync"
+                    "User"
                 ],
-                "summary": "Clean local git workspace",
+                "summary": "Delete invitation code",
                 "parameters": [
                     {
-                        "description": "Clean Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.GitSyncCleanRequest"
-                        }
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Invitation ID // 邀请码 ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -2306,96 +2480,49 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid Params",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "401": {
-                        "description": "Token Required",
+                        "description": "Invitation Not Found",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/git-sync/config/execute": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/admin/users/list": {
+            "get": {
+                "description": "Handle request to get all users.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "GitSync"
+                    "Config"
                 ],
-                "summary": "Trigger a manual git sync",
+                "summary": "Get all users",
                 "parameters": [
                     {
-                        "description": "Execute Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.GitSyncExecuteRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid Params",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "401": {
-                        "description": "Token Required",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
+                        "type": "integer",
+                        "description": "Page number // 页码",
+                        "name": "page",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/git-sync/configs": {
-            "get": {
-                "security": [
                     {
-                        "UserAuthToken": []
+                        "type": "integer",
+                        "description": "Page size // 每页数量",
+                        "name": "pageSize",
+                        "in": "query"
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "GitSync"
-                ],
-                "summary": "Get git sync configurations",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -2408,10 +2535,22 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.GitSyncConfigDTO"
-                                            }
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.UserDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
                                         }
                                     }
                                 }
@@ -2419,47 +2558,39 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Token Required",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/git-sync/histories": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
+                ]
+            }
+        },
+        "/api/admin/users/pending": {
+            "get": {
+                "description": "List the registrations waiting for approval when user.register-approval is on, oldest first, requires admin privileges\n开启 user.register-approval 时按时间正序列出等待审核的注册，需要管理员权限",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "GitSync"
+                    "User"
                 ],
-                "summary": "Get git sync histories",
+                "summary": "List pending registrations",
                 "parameters": [
                     {
                         "type": "integer",
-                        "name": "configId",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
+                        "description": "Page number // 页码",
                         "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
+                        "description": "Page size // 每页数量",
                         "name": "pageSize",
                         "in": "query"
                     }
@@ -2486,7 +2617,7 @@ const docTemplate = `{
                                                         "list": {
                                                             "type": "array",
                                                             "items": {
-                                                                "$ref": "#/definitions/dto.GitSyncHistoryDTO"
+                                                                "$ref": "#/definitions/dto.PendingUserDTO"
                                                             }
                                                         }
                                                     }
@@ -2498,28 +2629,23 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "401": {
-                        "description": "Token Required",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/git-sync/validate": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
+                ]
+            }
+        },
+        "/api/admin/users/pending/approve": {
+            "post": {
+                "description": "Create the account of a registration waiting for approval, requires admin privileges\n为等待审核的注册创建账号，需要管理员权限",
                 "consumes": [
                     "application/json"
                 ],
@@ -2527,17 +2653,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "GitSync"
+                    "User"
                 ],
-                "summary": "Validate git sync parameters",
+                "summary": "Approve pending registration",
                 "parameters": [
                     {
-                        "description": "Validation Parameters",
+                        "description": "Pending Registration ID",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.GitSyncValidateRequest"
+                            "$ref": "#/definitions/dto.PendingUserRequest"
                         }
                     }
                 ],
@@ -2545,95 +2671,114 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserDTO"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid Params",
+                        "description": "Pending Registration Not Found / User Already Exists",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     },
-                    "401": {
-                        "description": "Token Required",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Insufficient privileges",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
             }
         },
-        "/api/health": {
-            "get": {
-                "description": "Check service health status, including database connection",
+        "/api/admin/users/pending/reject": {
+            "post": {
+                "description": "Drop a registration waiting for approval without creating the account, requires admin privileges\n丢弃等待审核的注册，不创建账号，需要管理员权限",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "User"
+                ],
+                "summary": "Reject pending registration",
+                "parameters": [
+                    {
+                        "description": "Pending Registration ID",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PendingUserRequest"
+                        }
+                    }
                 ],
-                "summary": "Health check",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/api_router.HealthResponse"
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "400": {
+                        "description": "Pending Registration Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/note": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/admin/users/update": {
+            "post": {
+                "description": "Update a user, requires admin privileges",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get specific note content and metadata by path or path hash",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Note"
+                    "Config"
                 ],
-                "summary": "Get note details",
+                "summary": "Update a user",
                 "parameters": [
                     {
-                        "type": "boolean",
-                        "example": false,
-                        "description": "Is in recycle bin // 是否在回收站",
-                        "name": "isRecycle",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "ReadMe.md",
-                        "description": "Note path // 笔记路径",
-                        "name": "path",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "hash123",
-                        "description": "Path hash // 路径哈希",
-                        "name": "pathHash",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
-                        "in": "query",
-                        "required": true
+                        "description": "Config Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserUpdateRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -2648,22 +2793,30 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.NoteWithFileLinksResponse"
+                                            "$ref": "#/definitions/dto.UserDTO"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Handle note creation, modification, or renaming (identified by path change)",
+                ]
+            }
+        },
+        "/api/admin/vault/transfer": {
+            "post": {
+                "description": "Move a vault with its notes, note history, attachments and client config from one user to another, e.g. when consolidating accounts, requires admin privileges. With dryRun nothing is changed and the report lists what would be moved and the paths an existing target vault has with other content. Conflicts abort the transfer unless overwrite is set. The source vault is deleted only after every entry arrived; a partly failed transfer keeps it and can be run again.\n将保险库及其笔记、笔记历史、附件与客户端配置从一个用户转移给另一个用户（例如合并账号时），需要管理员权限。试运行不做任何修改，报告将要转移的内容以及已有目标保险库中内容不同的路径。\n存在冲突时除非设置 overwrite，否则中止转移。仅在全部条目转移成功后删除源保险库；部分失败时保留源保险库，可重新运行。",
                 "consumes": [
                     "application/json"
                 ],
@@ -2671,17 +2824,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Note"
+                    "Vault"
                 ],
-                "summary": "Create or update note",
+                "summary": "Transfer vault to another user",
                 "parameters": [
                     {
-                        "description": "Note Content",
+                        "description": "Transfer Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.NoteModifyOrCreateRequest"
+                            "$ref": "#/definitions/dto.VaultTransferRequest"
                         }
                     }
                 ],
@@ -2697,61 +2850,95 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.NoteDTO"
+                                            "$ref": "#/definitions/dto.VaultTransferReportDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Conflicts with the target vault",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VaultTransferReportDTO"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Move note to trash",
+                ]
+            }
+        },
+        "/api/admin/ws_client/{traceId}": {
+            "delete": {
+                "description": "Kick a WebSocket client by TraceID, requires admin privileges",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Note"
+                    "System"
                 ],
-                "summary": "Delete note",
+                "summary": "Kick a WebSocket client",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "ctx123",
-                        "description": "Context // 同步上下文",
-                        "name": "context",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "ReadMe.md",
-                        "description": "Note path // 笔记路径",
-                        "name": "path",
-                        "in": "query",
+                        "description": "Trace ID of the client",
+                        "name": "traceId",
+                        "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
                     {
-                        "type": "string",
-                        "example": "hash123",
-                        "description": "Path hash // 路径哈希",
-                        "name": "pathHash",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
-                        "in": "query",
-                        "required": true
+                        "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/admin/ws_clients": {
+            "get": {
+                "description": "Get a list of all current WebSocket connections, requires admin privileges",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
                 ],
+                "summary": "Get connected WebSocket clients",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -2764,24 +2951,33 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.NoteDTO"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/app.WSClientInfo"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
-                    }
-                }
-            }
-        },
-        "/api/note/append": {
-            "post": {
+                    },
+                    "403": {
+                        "description": "Insufficient privileges",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Append content to the end of a note",
+                ]
+            }
+        },
+        "/api/alert/channel": {
+            "post": {
+                "description": "Enabled channels are alerted when a backup task of the user fails. Types: email (needs a server-side SMTP server), telegram, gotify, ntfy.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2789,17 +2985,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Note"
+                    "Alert"
                 ],
-                "summary": "Append content to note",
+                "summary": "Create or update an alert channel",
                 "parameters": [
                     {
-                        "description": "Append Parameters",
+                        "description": "Alert Channel Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.NoteAppendRequest"
+                            "$ref": "#/definitions/dto.AlertChannelRequest"
                         }
                     }
                 ],
@@ -2815,56 +3011,154 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.NoteDTO"
+                                            "$ref": "#/definitions/dto.AlertChannelDTO"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/note/backlinks": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get all other notes that link to the specified note",
+                ]
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Note"
+                    "Alert"
                 ],
-                "summary": "Get backlinks",
+                "summary": "Delete an alert channel",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "ReadMe.md",
-                        "description": "Note path // 笔记路径",
-                        "name": "path",
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Channel ID // 渠道 ID",
+                        "name": "id",
                         "in": "query",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
                     {
-                        "type": "string",
-                        "example": "hash123",
-                        "description": "Path hash // 路径哈希",
-                        "name": "pathHash",
-                        "in": "query"
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/alert/channel/test": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alert"
+                ],
+                "summary": "Send a test alert",
+                "parameters": [
+                    {
+                        "description": "Alert Channel ID",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.AlertChannelIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
                     {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
-                        "in": "query",
-                        "required": true
+                        "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/alert/channels": {
+            "get": {
+                "produces": [
+                    "application/json"
                 ],
+                "tags": [
+                    "Alert"
+                ],
+                "summary": "Get alert channels",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -2879,25 +3173,89 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/dto.NoteLinkItem"
+                                                "$ref": "#/definitions/dto.AlertChannelDTO"
                                             }
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
             }
         },
-        "/api/note/frontmatter": {
-            "patch": {
+        "/api/auth/logout": {
+            "post": {
+                "description": "Handle user logout HTTP request, revoke current auth token.\n处理用户退出登录 HTTP 请求，注销当前认证 Token。",
+                "tags": [
+                    "User"
+                ],
+                "summary": "User logout",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/auth/logout_all": {
+            "post": {
+                "description": "Revoke every auth token of the current user, including the one of this request, and close their connections.\n注销当前用户的所有认证 Token（包括本次请求使用的 Token），并关闭其连接。",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Update or delete note frontmatter fields",
+                "tags": [
+                    "User"
+                ],
+                "summary": "Log out of all devices",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/backup/config": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -2905,17 +3263,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Note"
+                    "Backup"
                 ],
-                "summary": "Modify note frontmatter",
+                "summary": "Update backup configuration",
                 "parameters": [
                     {
-                        "description": "Frontmatter Modification Parameters",
+                        "description": "Backup Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.NotePatchFrontmatterRequest"
+                            "$ref": "#/definitions/dto.BackupConfigRequest"
                         }
                     }
                 ],
@@ -2931,75 +3289,97 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.NoteDTO"
+                                            "$ref": "#/definitions/dto.BackupConfigDTO"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/note/histories": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get all history records for a specific note with pagination",
+                ]
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Note History"
+                    "Backup"
                 ],
-                "summary": "Get note history list",
+                "summary": "Delete backup configuration",
                 "parameters": [
                     {
-                        "type": "boolean",
-                        "example": false,
-                        "description": "Is in recycle bin // 是否在回收站",
-                        "name": "isRecycle",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "ReadMe.md",
-                        "description": "Note path // 笔记路径",
-                        "name": "path",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "hash123",
-                        "description": "Path hash // 路径哈希",
-                        "name": "pathHash",
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID // ID",
+                        "name": "id",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
-                        "in": "query",
-                        "required": true
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "Page number // 页码",
-                        "name": "page",
-                        "in": "query"
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Page size // 每页数量",
-                        "name": "pageSize",
-                        "in": "query"
+                        "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/backup/configs": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Backup"
                 ],
+                "summary": "Get backup configurations",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -3012,54 +3392,120 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "allOf": [
-                                                {
-                                                    "$ref": "#/definitions/app.ListRes"
-                                                },
-                                                {
-                                                    "type": "object",
-                                                    "properties": {
-                                                        "list": {
-                                                            "type": "array",
-                                                            "items": {
-                                                                "$ref": "#/definitions/dto.NoteHistoryDTO"
-                                                            }
-                                                        }
-                                                    }
-                                                }
-                                            ]
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.BackupConfigDTO"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
             }
         },
-        "/api/note/history": {
-            "get": {
+        "/api/backup/execute": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Backup"
+                ],
+                "summary": "Trigger a backup manually",
+                "parameters": [
+                    {
+                        "description": "Backup Execute Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BackupExecuteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get specific note history content by history record ID",
+                ]
+            }
+        },
+        "/api/backup/historys": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Note History"
+                    "Backup"
                 ],
-                "summary": "Get note history details",
+                "summary": "Get backup history list",
                 "parameters": [
                     {
                         "type": "integer",
-                        "format": "int64",
-                        "description": "History Record ID",
-                        "name": "id",
+                        "example": 1,
+                        "description": "Config ID // 配置 ID",
+                        "name": "configId",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Page number // 页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 10,
+                        "description": "Page size // 每页大小",
+                        "name": "pageSize",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -3074,24 +3520,57 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.NoteHistoryDTO"
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.BackupHistoryDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/note/history/restore": {
-            "put": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Restore note content to a specific history version",
+                ]
+            }
+        },
+        "/api/backup/verify": {
+            "post": {
+                "description": "Re-downloads the archive (or dedupe manifest) of a successful backup history record and compares its size and SHA-256 with the recorded values; with extract the archive is also test-extracted",
                 "consumes": [
                     "application/json"
                 ],
@@ -3099,17 +3578,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Note History"
+                    "Backup"
                 ],
-                "summary": "Restore note from history",
+                "summary": "Verify a backup's stored copy",
                 "parameters": [
                     {
-                        "description": "Restore Parameters",
+                        "description": "Backup Verify Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.NoteHistoryRestoreRequest"
+                            "$ref": "#/definitions/dto.BackupVerifyRequest"
                         }
                     }
                 ],
@@ -3125,43 +3604,107 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.NoteDTO"
+                                            "$ref": "#/definitions/dto.BackupVerifyDTO"
                                         }
                                     }
                                 }
                             ]
                         }
-                    }
-                }
-            }
-        },
-        "/api/note/outlinks": {
-            "get": {
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get other notes that the specified note links to",
+                ]
+            }
+        },
+        "/api/changelog": {
+            "get": {
+                "description": "Get release notes of the running server version and all newer releases, grouped by heading, so the changes can be reviewed before upgrading. Releases come from the cached release check",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Note"
+                    "System"
                 ],
-                "summary": "Get outgoing links",
+                "summary": "Get changelog",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ChangelogDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/file": {
+            "get": {
+                "description": "Get raw binary data of an attachment by path, supports strong cache control and Range requests for seeking in audio and video or resuming downloads",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Get attachment content",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "ReadMe.md",
-                        "description": "Note path // 笔记路径",
+                        "example": "ctx123",
+                        "description": "Context // 同步上下文",
+                        "name": "context",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Is in recycle bin // 是否在回收站",
+                        "name": "isRecycle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Image.png",
+                        "description": "File path // 文件路径",
                         "name": "path",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "example": "hash123",
+                        "example": "fhash123",
                         "description": "Path hash // 路径哈希",
                         "name": "pathHash",
                         "in": "query"
@@ -3173,60 +3716,84 @@ const docTemplate = `{
                         "name": "vault",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range, e.g. bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/app.Res"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.NoteLinkItem"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
                         }
+                    },
+                    "304": {
+                        "description": "Not Modified"
                     }
-                }
-            }
-        },
-        "/api/note/prepend": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Insert content at the beginning of a note (after frontmatter)",
+                ]
+            },
+            "post": {
+                "description": "Upload a file as an attachment",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Note"
+                    "File"
                 ],
-                "summary": "Prepend content to note",
+                "summary": "Upload attachment",
                 "parameters": [
                     {
-                        "description": "Prepend Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.NotePrependRequest"
-                        }
+                        "type": "string",
+                        "description": "Vault name",
+                        "name": "vault",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File path",
+                        "name": "path",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Creation timestamp",
+                        "name": "ctime",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Modification timestamp",
+                        "name": "mtime",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to upload",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -3241,82 +3808,60 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.NoteDTO"
+                                            "$ref": "#/definitions/dto.FileDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/note/recycle-clear": {
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Permanently clear selected notes from recycle bin",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            },
+            "delete": {
+                "description": "Permanently delete a specific attachment record and its physical file",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Note"
+                    "File"
                 ],
-                "summary": "Clear recycle bin",
+                "summary": "Delete attachment",
                 "parameters": [
                     {
-                        "description": "Clear Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.NoteRecycleClearRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
-                    }
-                }
-            }
-        },
-        "/api/note/rename": {
-            "post": {
-                "security": [
+                        "type": "string",
+                        "example": "ctx123",
+                        "description": "Context // 同步上下文",
+                        "name": "context",
+                        "in": "query"
+                    },
                     {
-                        "UserAuthToken": []
-                    }
-                ],
-                "description": "Rename a note to a new path",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Note"
-                ],
-                "summary": "Rename note",
-                "parameters": [
+                        "type": "string",
+                        "example": "Image.png",
+                        "description": "File path // 文件路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
                     {
-                        "description": "Rename Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.NoteRenameRequest"
-                        }
+                        "type": "string",
+                        "example": "fhash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -3331,24 +3876,216 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.NoteDTO"
+                                            "$ref": "#/definitions/dto.FileDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "head": {
+                "description": "Get raw binary data of an attachment by path, supports strong cache control and Range requests for seeking in audio and video or resuming downloads",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Get attachment content",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ctx123",
+                        "description": "Context // 同步上下文",
+                        "name": "context",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Is in recycle bin // 是否在回收站",
+                        "name": "isRecycle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Image.png",
+                        "description": "File path // 文件路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "fhash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range, e.g. bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
         },
-        "/api/note/replace": {
-            "post": {
+        "/api/file/info": {
+            "get": {
+                "description": "Get attachment metadata (FileDTO) by path",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Get attachment info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ctx123",
+                        "description": "Context // 同步上下文",
+                        "name": "context",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Is in recycle bin // 是否在回收站",
+                        "name": "isRecycle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Image.png",
+                        "description": "File path // 文件路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "fhash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.FileDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/file/recycle-clear": {
+            "delete": {
+                "description": "Permanently clear selected files from recycle bin",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Perform find and replace operation in a note, supporting regular expressions",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "File"
+                ],
+                "summary": "Clear recycle bin",
+                "parameters": [
+                    {
+                        "description": "Clear Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FileRecycleClearRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/file/rename": {
+            "post": {
+                "description": "Rename an attachment to a new path",
                 "consumes": [
                     "application/json"
                 ],
@@ -3356,17 +4093,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Note"
+                    "File"
                 ],
-                "summary": "Find and replace in note",
+                "summary": "Rename attachment",
                 "parameters": [
                     {
-                        "description": "Find and Replace Parameters",
+                        "description": "Rename Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.NoteReplaceRequest"
+                            "$ref": "#/definitions/dto.FileRenameRequest"
                         }
                     }
                 ],
@@ -3382,31 +4119,31 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.NoteReplaceResponse"
+                                            "$ref": "#/definitions/dto.FileDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/note/restore": {
-            "put": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Restore deleted note from trash",
+                ]
+            }
+        },
+        "/api/file/restore": {
+            "put": {
+                "description": "Restore deleted attachment from trash",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Note"
+                    "File"
                 ],
-                "summary": "Restore note",
+                "summary": "Restore attachment",
                 "parameters": [
                     {
                         "description": "Restore Parameters",
@@ -3414,7 +4151,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.NoteRestoreRequest"
+                            "$ref": "#/definitions/dto.FileRestoreRequest"
                         }
                     }
                 ],
@@ -3430,79 +4167,46 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.NoteDTO"
+                                            "$ref": "#/definitions/dto.FileDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/notes": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get note list for current user with pagination",
+                ]
+            }
+        },
+        "/api/file/thumbnail": {
+            "get": {
+                "description": "Get a downscaled preview of an image attachment (JPEG, PNG, GIF, BMP, WebP). The smallest configured width not below the requested width is returned, thumbnails are rendered on first request and cached. Supports ETag revalidation.",
                 "produces": [
-                    "application/json"
+                    "image/jpeg",
+                    "image/png"
                 ],
                 "tags": [
-                    "Note"
+                    "File"
                 ],
-                "summary": "Get note list",
+                "summary": "Get attachment thumbnail",
                 "parameters": [
-                    {
-                        "type": "boolean",
-                        "example": false,
-                        "description": "Is in recycle bin // 是否在回收站",
-                        "name": "isRecycle",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "example": "todo",
-                        "description": "Search keyword // 搜索关键词",
-                        "name": "keyword",
-                        "in": "query"
+                        "example": "Image.png",
+                        "description": "File path // 文件路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "example": "note1.md,note2.md",
-                        "description": "Comma-separated exact path list for share filter // 逗号分隔的精确路径列表，用于分享筛选",
-                        "name": "paths",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "example": true,
-                        "description": "Whether to search content // 是否搜索内容",
-                        "name": "searchContent",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "content",
-                        "description": "Search mode (path, content) // 搜索模式（路径、内容）",
-                        "name": "searchMode",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "mtime",
-                        "description": "Sort by field // 排序字段",
-                        "name": "sortBy",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "desc",
-                        "description": "Sort order // 排序顺序",
-                        "name": "sortOrder",
+                        "example": "fhash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
                         "in": "query"
                     },
                     {
@@ -3514,15 +4218,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
+                        "minimum": 0,
                         "type": "integer",
-                        "description": "Page number // 页码",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page size // 每页数量",
-                        "name": "pageSize",
+                        "example": 256,
+                        "description": "Requested width in pixels, 0 for the smallest // 请求的宽度（像素），0 表示最小宽度",
+                        "name": "width",
                         "in": "query"
                     }
                 ],
@@ -3530,55 +4230,36 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/app.Res"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "allOf": [
-                                                {
-                                                    "$ref": "#/definitions/app.ListRes"
-                                                },
-                                                {
-                                                    "type": "object",
-                                                    "properties": {
-                                                        "list": {
-                                                            "type": "array",
-                                                            "items": {
-                                                                "$ref": "#/definitions/dto.NoteNoContentDTO"
-                                                            }
-                                                        }
-                                                    }
-                                                }
-                                            ]
-                                        }
-                                    }
-                                }
-                            ]
+                            "type": "file"
                         }
+                    },
+                    "304": {
+                        "description": "Not Modified"
                     }
-                }
-            }
-        },
-        "/api/notes/share-paths": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/file/upload": {
+            "delete": {
+                "description": "Discard an unfinished chunked upload and its received chunks",
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "Share"
+                    "File"
                 ],
-                "summary": "Get active shared note paths",
+                "summary": "Abort chunked upload",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Vault name",
-                        "name": "vault",
+                        "example": "sess_123456",
+                        "description": "Upload session ID // 上传会话 ID",
+                        "name": "sessionId",
                         "in": "query",
                         "required": true
                     }
@@ -3587,64 +4268,55 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/app.Res"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "type": "string"
-                                            }
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/setting": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/file/upload/chunk": {
+            "put": {
+                "description": "Upload one chunk of a chunked upload as the raw request body. Every chunk is chunkSize bytes long except the last one. Chunks can be sent in any order, in parallel and more than once.",
+                "consumes": [
+                    "application/octet-stream"
                 ],
-                "description": "Get setting info for current user by path or pathHash",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Setting"
+                    "File"
                 ],
-                "summary": "Get setting info",
+                "summary": "Upload a chunk",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "User/Theme",
-                        "description": "Setting path // 配置路径",
-                        "name": "path",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "hash123",
-                        "description": "Path hash // 路径哈希",
-                        "name": "pathHash",
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 0,
+                        "description": "Zero-based chunk index // 从 0 开始的分块序号",
+                        "name": "index",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name // 保险库名称",
-                        "name": "vault",
+                        "example": "sess_123456",
+                        "description": "Upload session ID // 上传会话 ID",
+                        "name": "sessionId",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "description": "Chunk content",
+                        "name": "chunk",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 ],
                 "responses": {
@@ -3659,22 +4331,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.SettingDTO"
+                                            "$ref": "#/definitions/dto.FileUploadSessionDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Create a new setting or update an existing one",
+                ]
+            }
+        },
+        "/api/file/upload/complete": {
+            "post": {
+                "description": "Store the uploaded attachment once every chunk was received and notify the other devices. A failed request can be retried, the session is kept until the file is stored.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3682,17 +4356,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Setting"
+                    "File"
                 ],
-                "summary": "Create or update setting",
+                "summary": "Complete chunked upload",
                 "parameters": [
                     {
-                        "description": "Create/Update Parameters",
+                        "description": "Session Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.SettingModifyOrCreateRequest"
+                            "$ref": "#/definitions/dto.FileUploadSessionRequest"
                         }
                     }
                 ],
@@ -3708,22 +4382,24 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.SettingDTO"
+                                            "$ref": "#/definitions/dto.FileDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Soft delete a setting by path or pathHash",
+                ]
+            }
+        },
+        "/api/file/upload/init": {
+            "post": {
+                "description": "Start a resumable chunked upload for large attachments. When the server already stores the same content no upload is needed and the stored file is returned. Calling it again for the same path, content hash and size resumes the unfinished session and lists the chunks received so far. Upload every missing chunk with PUT /api/file/upload/chunk, then call POST /api/file/upload/complete.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3731,17 +4407,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Setting"
+                    "File"
                 ],
-                "summary": "Delete setting",
+                "summary": "Start chunked upload",
                 "parameters": [
                     {
-                        "description": "Delete Parameters",
+                        "description": "Upload Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.SettingDeleteRequest"
+                            "$ref": "#/definitions/dto.FileUploadInitRequest"
                         }
                     }
                 ],
@@ -3749,39 +4425,47 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.FileUploadSessionDTO"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
-                }
-            }
-        },
-        "/api/setting/rename": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Rename a setting and update its path and pathHash",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/file/upload/status": {
+            "get": {
+                "description": "Get the chunks received so far, used to resume an interrupted upload",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Setting"
+                    "File"
                 ],
-                "summary": "Rename setting",
+                "summary": "Get chunked upload status",
                 "parameters": [
                     {
-                        "description": "Rename Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.SettingRenameRequest"
-                        }
+                        "type": "string",
+                        "example": "sess_123456",
+                        "description": "Upload session ID // 上传会话 ID",
+                        "name": "sessionId",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -3796,39 +4480,60 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.SettingDTO"
+                                            "$ref": "#/definitions/dto.FileUploadSessionDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/settings": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get setting list for current user with pagination and keyword filtering",
+                ]
+            }
+        },
+        "/api/files": {
+            "get": {
+                "description": "Get attachment list for current user with pagination, search, filter, and sort support",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Setting"
+                    "File"
                 ],
-                "summary": "Get setting list",
+                "summary": "Get file list",
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Is in recycle bin // 是否在回收站",
+                        "name": "isRecycle",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "example": "User/",
-                        "description": "Keyword // 关键词",
+                        "example": "vacation",
+                        "description": "Search keyword // 搜索关键词",
                         "name": "keyword",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "example": "mtime",
+                        "description": "Sort by field // 排序字段",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "desc",
+                        "description": "Sort order // 排序顺序",
+                        "name": "sortOrder",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "example": "MyVault",
@@ -3872,7 +4577,7 @@ const docTemplate = `{
                                                         "list": {
                                                             "type": "array",
                                                             "items": {
-                                                                "$ref": "#/definitions/dto.SettingDTO"
+                                                                "$ref": "#/definitions/dto.FileDTO"
                                                             }
                                                         }
                                                     }
@@ -3884,41 +4589,42 @@ const docTemplate = `{
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/share": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/folder": {
+            "get": {
+                "description": "Get folder info for current user by path or pathHash",
+                "produces": [
+                    "application/json"
                 ],
-                "description": "Get share token and info by vault and path",
                 "tags": [
-                    "Share"
+                    "Folder"
                 ],
-                "summary": "Query share by path",
+                "summary": "Get folder info",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "ReadMe.md",
-                        "description": "Resource path // 资源路径",
+                        "example": "Projects/Work",
+                        "description": "Folder path // 文件夹路径",
                         "name": "path",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "hash123",
-                        "description": "Resource path Hash // 资源路径哈希",
+                        "example": "fhash123",
+                        "description": "Path hash // 路径哈希",
                         "name": "pathHash",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "example": "defaultVault",
+                        "example": "MyVault",
                         "description": "Vault name // 保险库名称",
                         "name": "vault",
                         "in": "query",
@@ -3937,22 +4643,22 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.ShareCreateResponse"
+                                            "$ref": "#/definitions/dto.FolderDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Create a share token for a specific note or attachment, automatically resolve attachment references and authorize",
+                ]
+            },
+            "post": {
+                "description": "Create a new folder or restore a deleted one by path",
                 "consumes": [
                     "application/json"
                 ],
@@ -3960,17 +4666,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Share"
+                    "Folder"
                 ],
-                "summary": "Create resource share",
+                "summary": "Create folder",
                 "parameters": [
                     {
-                        "description": "Share Parameters",
+                        "description": "Create Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ShareCreateRequest"
+                            "$ref": "#/definitions/dto.FolderCreateRequest"
                         }
                     }
                 ],
@@ -3986,22 +4692,22 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.ShareCreateResponse"
+                                            "$ref": "#/definitions/dto.FolderDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Cancel a share by ID or path parameters",
+                ]
+            },
+            "delete": {
+                "description": "Soft delete a folder by path or pathHash",
                 "consumes": [
                     "application/json"
                 ],
@@ -4009,17 +4715,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Share"
+                    "Folder"
                 ],
-                "summary": "Cancel share",
+                "summary": "Delete folder",
                 "parameters": [
                     {
-                        "description": "Cancel Parameters",
+                        "description": "Delete Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ShareCancelRequest"
+                            "$ref": "#/definitions/dto.FolderDeleteRequest"
                         }
                     }
                 ],
@@ -4030,94 +4736,122 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/share/file": {
-            "get": {
+                },
                 "security": [
                     {
-                        "ShareAuthToken": []
+                        "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/folder/archive": {
+            "post": {
+                "description": "Mark a folder as archived (read-only for all clients, excluded from keyword search by default) or clear the flag",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get raw binary data of a specific attachment via share token",
                 "produces": [
-                    "application/octet-stream"
+                    "application/json"
                 ],
                 "tags": [
-                    "Share"
+                    "Folder"
                 ],
-                "summary": "Get shared attachment content",
+                "summary": "Archive folder",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Auth Token",
-                        "name": "Share-Token",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "Resource ID // 资源 ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "123456",
-                        "description": "Share password // 分享密码",
-                        "name": "password",
-                        "in": "query"
+                        "description": "Archive Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.FolderArchiveRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "type": "file"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.FolderDTO"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
-                }
-            }
-        },
-        "/api/share/note": {
-            "get": {
+                },
                 "security": [
                     {
-                        "ShareAuthToken": []
+                        "UserAuthToken": []
                     }
-                ],
-                "description": "Get specific note content (restricted read-only access) via share token",
+                ]
+            }
+        },
+        "/api/folder/files": {
+            "get": {
+                "description": "List non-deleted files in a specific folder with pagination and sorting",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Share"
+                    "Folder"
                 ],
-                "summary": "Get shared note details",
+                "summary": "List files in folder",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Auth Token",
-                        "name": "Share-Token",
-                        "in": "header",
-                        "required": true
+                        "example": "Projects",
+                        "description": "Folder path // 文件夹路径",
+                        "name": "path",
+                        "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "Resource ID // 资源 ID",
-                        "name": "id",
+                        "type": "string",
+                        "example": "fhash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "mtime",
+                        "description": "Sort by field // 排序字段",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "desc",
+                        "description": "Sort order // 排序顺序",
+                        "name": "sortOrder",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
                         "in": "query",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "example": "123456",
-                        "description": "Share password // 分享密码",
-                        "name": "password",
+                        "type": "integer",
+                        "description": "Page number // 页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size // 每页数量",
+                        "name": "pageSize",
                         "in": "query"
                     }
                 ],
@@ -4133,24 +4867,39 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.NoteDTO"
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.FileDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/share/password": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Set or update password and expiration time for a share record",
+                ]
+            }
+        },
+        "/api/folder/move": {
+            "post": {
+                "description": "Move or rename a folder together with all its subfolders, notes and files, keeping note history; each moved resource is broadcast to the user's clients as a rename",
                 "consumes": [
                     "application/json"
                 ],
@@ -4158,17 +4907,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Share"
+                    "Folder"
                 ],
-                "summary": "Update share password",
+                "summary": "Move folder",
                 "parameters": [
                     {
-                        "description": "Update Parameters",
+                        "description": "Move Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.SharePasswordUpdateRequest"
+                            "$ref": "#/definitions/dto.FolderMoveRequest"
                         }
                     }
                 ],
@@ -4176,100 +4925,85 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.FolderMoveResult"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
-                }
-            }
-        },
-        "/api/share/short_link": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Call sink.cool API to generate a short link for a given share record",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/folder/notes": {
+            "get": {
+                "description": "List non-deleted notes in a specific folder with pagination and sorting",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Share"
+                    "Folder"
                 ],
-                "summary": "Create short link for share",
+                "summary": "List notes in folder",
                 "parameters": [
                     {
-                        "description": "Short Link Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ShareShortLinkCreateRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/app.Res"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    }
-                }
-            }
-        },
-        "/api/shares": {
-            "get": {
-                "security": [
+                        "type": "string",
+                        "example": "Projects",
+                        "description": "Folder path // 文件夹路径",
+                        "name": "path",
+                        "in": "query"
+                    },
                     {
-                        "UserAuthToken": []
-                    }
-                ],
-                "description": "Get all active and inactive shares of the user, supports sorting and pagination",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Share"
-                ],
-                "summary": "List shares",
-                "parameters": [
+                        "type": "string",
+                        "example": "fhash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Sort field: created_at, updated_at, expires_at (default: created_at)",
-                        "name": "sort_by",
+                        "example": "mtime",
+                        "description": "Sort by field // 排序字段",
+                        "name": "sortBy",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Sort direction: asc or desc (default: desc)",
-                        "name": "sort_order",
+                        "example": "desc",
+                        "description": "Sort order // 排序顺序",
+                        "name": "sortOrder",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "description": "Page number",
+                        "description": "Page number // 页码",
                         "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Page size",
+                        "description": "Page size // 每页数量",
                         "name": "pageSize",
                         "in": "query"
                     }
@@ -4296,7 +5030,7 @@ const docTemplate = `{
                                                         "list": {
                                                             "type": "array",
                                                             "items": {
-                                                                "$ref": "#/definitions/dto.ShareListItem"
+                                                                "$ref": "#/definitions/dto.NoteDTO"
                                                             }
                                                         }
                                                     }
@@ -4308,23 +5042,48 @@ const docTemplate = `{
                             ]
                         }
                     }
-                }
-            }
-        },
-        "/api/storage": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
+                ]
+            }
+        },
+        "/api/folder/tree": {
+            "get": {
+                "description": "Get the complete folder tree structure for a vault",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "Folder"
+                ],
+                "summary": "Get folder tree",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 3,
+                        "description": "Tree depth // 树深度",
+                        "name": "depth",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Attach README.md / index.md excerpts to nodes // 为节点附带 README.md / index.md 摘要",
+                        "name": "includeReadme",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get storage configuration list",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -4337,43 +5096,53 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.StorageDTO"
-                                            }
+                                            "$ref": "#/definitions/dto.FolderTreeResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/folders": {
+            "get": {
+                "description": "Get folder list for current user by parent path or pathHash",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "Folder"
                 ],
-                "summary": "Create or update storage configuration",
+                "summary": "Get folder list",
                 "parameters": [
                     {
-                        "description": "Storage Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.StoragePostRequest"
-                        }
+                        "type": "string",
+                        "example": "Projects",
+                        "description": "Folder path // 文件夹路径",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "fhash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -4388,61 +5157,50 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.StorageDTO"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.FolderDTO"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/git-sync/config": {
+            "post": {
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "GitSync"
                 ],
-                "summary": "Delete storage configuration",
+                "summary": "Update git sync configuration",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Storage ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Success",
+                        "description": "Git Sync Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "$ref": "#/definitions/dto.GitSyncConfigRequest"
                         }
                     }
-                }
-            }
-        },
-        "/api/storage/enabled_types": {
-            "get": {
-                "description": "Get list of enabled storage types. Possible values: localfs, oss, s3, r2, minio, webdav",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Storage"
                 ],
-                "summary": "Get enabled storage types",
                 "responses": {
                     "200": {
-                        "description": "Success. Data contains: localfs, oss, s3, r2, minio, webdav",
+                        "description": "Success",
                         "schema": {
                             "allOf": [
                                 {
@@ -4452,44 +5210,54 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "type": "string"
-                                            }
+                                            "$ref": "#/definitions/dto.GitSyncConfigDTO"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/storage/validate": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Storage"
+                    "GitSync"
                 ],
-                "summary": "Validate storage connection",
+                "summary": "Delete git sync configuration",
                 "parameters": [
                     {
-                        "description": "Storage Parameters",
+                        "description": "Git Sync ID",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.StoragePostRequest"
+                            "$ref": "#/definitions/dto.GitSyncDeleteRequest"
                         }
                     }
                 ],
@@ -4518,51 +5286,135 @@ const docTemplate = `{
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
             }
         },
-        "/api/support": {
-            "get": {
-                "description": "Get support records for the specified language with pagination and sorting",
+        "/api/git-sync/config/clean": {
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "GitSync"
                 ],
-                "summary": "Get support records",
+                "summary": "Clean local git workspace",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Language code (default: en)",
-                        "name": "lang",
-                        "in": "query"
+                        "description": "Clean Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.GitSyncCleanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Sort by field (amount, time, name, item)",
-                        "name": "sortBy",
-                        "in": "query"
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Sort order (asc, desc)",
-                        "name": "sortOrder",
-                        "in": "query"
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/git-sync/config/execute": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GitSync"
+                ],
+                "summary": "Trigger a manual git sync",
+                "parameters": [
+                    {
+                        "description": "Execute Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.GitSyncExecuteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Page size",
-                        "name": "pageSize",
-                        "in": "query"
+                        "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/git-sync/configs": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "GitSync"
                 ],
+                "summary": "Get git sync configurations",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -4575,62 +5427,58 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/app.ListRes"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.GitSyncConfigDTO"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/sync-logs": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Get sync log list for current user with optional type/action filters and pagination",
+                ]
+            }
+        },
+        "/api/git-sync/histories": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sync Log"
+                    "GitSync"
                 ],
-                "summary": "Get sync log list",
+                "summary": "Get git sync histories",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "modify",
-                        "description": "Action type // 操作类型",
-                        "name": "action",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "note",
-                        "description": "Resource type: note / file / setting / folder // 资源类型",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "MyVault",
-                        "description": "Vault name (optional filter) // 保险库名称（可选过滤）",
-                        "name": "vault",
+                        "type": "integer",
+                        "name": "configId",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Page number // 页码",
                         "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Page size // 每页数量",
                         "name": "pageSize",
                         "in": "query"
                     }
@@ -4657,7 +5505,7 @@ const docTemplate = `{
                                                         "list": {
                                                             "type": "array",
                                                             "items": {
-                                                                "$ref": "#/definitions/dto.SyncLogDTO"
+                                                                "$ref": "#/definitions/dto.GitSyncHistoryDTO"
                                                             }
                                                         }
                                                     }
@@ -4668,18 +5516,29 @@ const docTemplate = `{
                                 }
                             ]
                         }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/user/change_password": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Handle password change request for current user, validate old password and update new password.\n处理当前用户的修改密码请求，验证旧密码并更新新密码。",
+                ]
+            }
+        },
+        "/api/git-sync/validate": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -4687,17 +5546,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "GitSync"
                 ],
-                "summary": "Change user password",
+                "summary": "Validate git sync parameters",
                 "parameters": [
                     {
-                        "description": "Change Password Parameters",
+                        "description": "Validation Parameters",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UserChangePasswordRequest"
+                            "$ref": "#/definitions/dto.GitSyncValidateRequest"
                         }
                     }
                 ],
@@ -4709,28 +5568,34 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid Parameters / Old Password Incorrect",
+                        "description": "Invalid Params",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/user/info": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Handle request to get current user info.\n处理获取当前用户信息的请求。",
+                ]
+            }
+        },
+        "/api/graphql": {
+            "post": {
+                "description": "Query notes, folders, files, tags, links and history as a typed graph, selecting only the fields needed and following nested links such as backlinks of backlinks in one request. Only queries are supported. The response follows the GraphQL over HTTP format with data and errors. File fields require a file read scope. The schema is served by GET /api/graphql/schema.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4738,111 +5603,97 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "GraphQL"
                 ],
-                "summary": "Get user info",
-                "responses": {
-                    "200": {
-                        "description": "Success",
+                "summary": "GraphQL query",
+                "parameters": [
+                    {
+                        "description": "GraphQL request",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/app.Res"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/dto.UserDTO"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/graphql_router.Request"
                         }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "GraphQL response",
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
             }
         },
-        "/api/user/login": {
-            "post": {
-                "description": "Handle user login HTTP request, validate parameters and return auth token.\n处理用户登录 HTTP 请求，验证参数并返回认证 Token。",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/graphql/schema": {
+            "get": {
+                "description": "Get the GraphQL schema definition of /api/graphql",
                 "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "User"
+                    "GraphQL"
                 ],
-                "summary": "User login",
-                "parameters": [
-                    {
-                        "description": "Login Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
+                "summary": "GraphQL schema",
+                "responses": {
+                    "200": {
+                        "description": "Schema SDL",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserLoginRequest"
+                            "type": "string"
                         }
                     }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/health": {
+            "get": {
+                "description": "Check service health status, including database connection",
+                "produces": [
+                    "application/json"
                 ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Health check",
                 "responses": {
                     "200": {
-                        "description": "Success",
-                        "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/app.Res"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "data": {
-                                            "$ref": "#/definitions/dto.UserDTO"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid Parameters / Invalid Credentials",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "$ref": "#/definitions/api_router.HealthResponse"
                         }
                     }
                 }
             }
         },
-        "/api/user/register": {
-            "post": {
-                "description": "Handle user registration HTTP request, validate parameters and call UserService. Registration may be disabled in server settings.\n处理用户注册 HTTP 请求，验证参数并调用 UserService。注册功能可能在服务器设置中被禁用。",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/meta/errors": {
+            "get": {
+                "description": "Lists every response code with its stable name, category, HTTP status, message template and remediation hint, so clients can map the code of a response instead of parsing its message. Messages and hints follow the lang query parameter or header (en, zh_cn).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "System"
                 ],
-                "summary": "User registration",
+                "summary": "Get the error catalog",
                 "parameters": [
                     {
-                        "description": "Register Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UserCreateRequest"
-                        }
+                        "type": "string",
+                        "description": "Language code (default: en)",
+                        "name": "lang",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -4857,37 +5708,58 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.UserDTO"
+                                            "$ref": "#/definitions/dto.ErrorCatalogDTO"
                                         }
                                     }
                                 }
                             ]
                         }
-                    },
-                    "400": {
-                        "description": "Invalid Parameters / Registration Disabled / User Already Exists",
-                        "schema": {
-                            "$ref": "#/definitions/app.Res"
-                        }
                     }
                 }
             }
         },
-        "/api/vault": {
+        "/api/note": {
             "get": {
-                "security": [
-                    {
-                        "UserAuthToken": []
-                    }
-                ],
-                "description": "Get all note vaults for current user",
+                "description": "Get specific note content and metadata by path or path hash",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Vault"
+                    "Note"
+                ],
+                "summary": "Get note details",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Is in recycle bin // 是否在回收站",
+                        "name": "isRecycle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Note path // 笔记路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get vault list",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -4900,25 +5772,22 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/dto.VaultDTO"
-                                            }
+                                            "$ref": "#/definitions/dto.NoteWithFileLinksResponse"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            },
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Be used to create a new vault or update an existing vault configuration based on the ID in the request parameters",
+                ]
+            },
+            "post": {
+                "description": "Handle note creation, modification, or renaming (identified by path change). With template set, the content is the named note template with its {{date}}, {{time}} and {{title}} variables expanded; add createOnly to keep an existing note, e.g. for daily notes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4926,17 +5795,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Vault"
+                    "Note"
                 ],
-                "summary": "Create or update vault",
+                "summary": "Create or update note",
                 "parameters": [
                     {
-                        "description": "Vault Parameters",
+                        "description": "Note Content",
                         "name": "params",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.VaultPostRequest"
+                            "$ref": "#/definitions/dto.NoteModifyOrCreateRequest"
                         }
                     }
                 ],
@@ -4952,36 +5821,57 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VaultDTO"
+                                            "$ref": "#/definitions/dto.NoteDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     }
-                }
-            },
-            "delete": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Permanently delete a specific note vault and all associated notes and attachments",
+                ]
+            },
+            "delete": {
+                "description": "Move note to trash",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Vault"
+                    "Note"
                 ],
-                "summary": "Delete vault",
+                "summary": "Delete note",
                 "parameters": [
                     {
-                        "minimum": 1,
-                        "type": "integer",
-                        "example": 1,
-                        "description": "Vault ID // 保险库 ID",
-                        "name": "id",
+                        "type": "string",
+                        "example": "ctx123",
+                        "description": "Context // 同步上下文",
+                        "name": "context",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Note path // 笔记路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
                         "in": "query",
                         "required": true
                     }
@@ -4990,74 +5880,102 @@ const docTemplate = `{
                     "200": {
                         "description": "Success",
                         "schema": {
-                            "$ref": "#/definitions/app.Res"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteDTO"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
-                }
-            }
-        },
-        "/api/vault/force-delete-item": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Permanently delete a single note or file (attachment) in a vault",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/note-policies": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Vault"
-                ],
-                "summary": "Force delete a single item",
-                "parameters": [
-                    {
-                        "description": "Delete Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.VaultForceDeleteItemRequest"
-                        }
-                    }
+                    "NotePolicy"
                 ],
+                "summary": "Get note lifecycle policies",
                 "responses": {
                     "200": {
                         "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.NotePolicyDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
                     }
-                }
-            }
-        },
-        "/api/vault/get": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
+                ]
+            }
+        },
+        "/api/note-policy": {
+            "post": {
+                "description": "Archives (moves into archiveFolder, keeping the folder structure) or deletes (moves into the trash) the notes in folder that are older than olderThanDays and, if set, carry tag. Enabled policies run once a day; notes already in archiveFolder are never matched.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Get specific vault configuration details by vault ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Vault"
+                    "NotePolicy"
                 ],
-                "summary": "Get vault details",
+                "summary": "Create or update a note lifecycle policy",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Vault ID",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "Policy Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotePolicyRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -5072,43 +5990,54 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VaultDTO"
+                                            "$ref": "#/definitions/dto.NotePolicyDTO"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/vault/rebuild-index": {
-            "post": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Rebuild full-text search index from physical database and files for a specific vault, restricted to webgui client",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Vault"
+                    "NotePolicy"
                 ],
-                "summary": "Rebuild vault FTS index",
+                "summary": "Delete a note lifecycle policy",
                 "parameters": [
                     {
-                        "description": "Rebuild Index Parameters",
-                        "name": "params",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.VaultRebuildIndexRequest"
-                        }
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Policy ID // 策略 ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -5117,20 +6046,56 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/app.Res"
                         }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
             }
         },
-        "/api/version": {
-            "get": {
-                "description": "Get current server software version, Git tag, and build time",
+        "/api/note-policy/execute": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "NotePolicy"
+                ],
+                "summary": "Run a note lifecycle policy now",
+                "parameters": [
+                    {
+                        "description": "Policy ID",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotePolicyIDRequest"
+                        }
+                    }
                 ],
-                "summary": "Get server version info",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -5143,31 +6108,58 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.VersionDTO"
+                                            "$ref": "#/definitions/dto.NotePolicyRunDTO"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
-            }
-        },
-        "/api/version/probe": {
-            "get": {
+                },
                 "security": [
                     {
                         "UserAuthToken": []
                     }
-                ],
-                "description": "Parallel-probe GitHub and CNB release endpoints, return reachability, latency, recommended source and current selected mode",
+                ]
+            }
+        },
+        "/api/note-policy/preview": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "NotePolicy"
+                ],
+                "summary": "Dry-run a note lifecycle policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Policy ID // 策略 ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Probe version sources latency",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -5180,26 +6172,69 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.SourceProbeDTO"
+                                            "$ref": "#/definitions/dto.NotePolicyPreviewDTO"
                                         }
                                     }
                                 }
                             ]
                         }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
             }
         },
-        "/api/webgui/config": {
+        "/api/note-policy/runs": {
             "get": {
-                "description": "Get non-sensitive configuration required for frontend display, such as font settings, registration status, etc.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Config"
+                    "NotePolicy"
+                ],
+                "summary": "Get note lifecycle policy run history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Policy ID, 0 lists every policy // 策略 ID，为 0 时列出全部",
+                        "name": "policyId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get WebGUI basic config",
                 "responses": {
                     "200": {
                         "description": "Success",
@@ -5212,950 +6247,11118 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dto.AdminWebGUIConfig"
-                                        }
-                                    }
-                                }
-                            ]
-                        }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "api_router.HealthResponse": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.NotePolicyRunDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note-publish": {
+            "get": {
+                "description": "Notes whose frontmatter sets publish: true are shared automatically once publish_at (RFC 3339 or \"2006-01-02 15:04\", server time zone) has passed, or at once without publish_at. Removing publish or deleting the note revokes the share. The scheduler checks every minute.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "NotePublish"
+                ],
+                "summary": "Get scheduled note publishing status",
+                "parameters": [
+                    {
+                        "enum": [
+                            "scheduled",
+                            "published",
+                            "failed",
+                            "unpublished"
+                        ],
+                        "type": "string",
+                        "example": "scheduled",
+                        "description": "Only states with this status, empty for all // 仅返回该状态，为空表示全部",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.NotePublishDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/access-log": {
+            "get": {
+                "description": "Return whether reads of the note via share links, API and WebDAV are logged",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note Access Log"
+                ],
+                "summary": "Get note access log setting",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Note path // 笔记路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteAccessSettingDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Enable or disable logging of reads of the note via share links, API and WebDAV; existing entries are kept when disabled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note Access Log"
+                ],
+                "summary": "Update note access log setting",
+                "parameters": [
+                    {
+                        "description": "Setting Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteAccessSettingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteAccessSettingDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/access-logs": {
+            "get": {
+                "description": "Get reads of a note via share links, API and WebDAV, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note Access Log"
+                ],
+                "summary": "Get note access log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Note path // 笔记路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number // 页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size // 每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.NoteAccessLogDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/append": {
+            "post": {
+                "description": "Append content to the end of a note",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Append content to note",
+                "parameters": [
+                    {
+                        "description": "Append Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteAppendRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/append-to-heading": {
+            "post": {
+                "description": "Insert content at the end (or, with position=start, the top) of the section of a Markdown heading such as \"## Inbox\"; a missing heading is added at the end of the note",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Insert content under a heading",
+                "parameters": [
+                    {
+                        "description": "Append To Heading Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteAppendToHeadingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteAppendToHeadingResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/backlinks": {
+            "get": {
+                "description": "Get all other notes that link to the specified note",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Get backlinks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Note path // 笔记路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.NoteLinkItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/calendar.ics": {
+            "get": {
+                "description": "Export notes with a date, start, due or scheduled frontmatter property (with an optional end) and tasks with a due date (Tasks plugin 📅 or Dataview due::) as a read-only iCalendar feed for calendar apps. Dates without a time are all-day events, times without a zone are floating. Calendar apps cannot send headers, so subscribe with an API token in the token query parameter. Covers every vault the token may access unless vault is given. Completed tasks are left out unless done is true. Requires a note read scope.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Calendar feed of dated notes and tasks",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Include completed tasks // 是否包含已完成的任务",
+                        "name": "done",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name, every vault the token may access when empty // 保险库名称，为空时包含令牌可访问的所有保险库",
+                        "name": "vault",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "iCalendar feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/daily": {
+            "post": {
+                "description": "Resolves the daily note path of date (today when empty) from the user's daily note pattern, creates the note from the configured template when it is missing, then prepends and appends the given content. Meant for automations such as iOS Shortcuts and scripts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Open or write the daily note",
+                "parameters": [
+                    {
+                        "description": "Daily Note Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteDailyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteDailyResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/daily/setting": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Get daily note setting",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteDailySettingDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "pattern is a note path with Moment.js date formats in double braces, e.g. Journal/{{YYYY}}/{{YYYY-MM-DD}}.md; template names a note template the missing daily note is created from; timezone is the IANA time zone deciding what \"today\" is.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Update daily note setting",
+                "parameters": [
+                    {
+                        "description": "Daily Note Setting",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteDailySettingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteDailySettingDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/duplicate": {
+            "post": {
+                "description": "Create \"Copy of \u003cname\u003e\" next to a note (or at newPath); with copyAttachments the embedded attachments are duplicated too and the copy embeds the duplicates",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Duplicate note",
+                "parameters": [
+                    {
+                        "description": "Duplicate Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteDuplicateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteDuplicateResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/frontmatter": {
+            "patch": {
+                "description": "Update or delete note frontmatter fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Modify note frontmatter",
+                "parameters": [
+                    {
+                        "description": "Frontmatter Modification Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotePatchFrontmatterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/histories": {
+            "get": {
+                "description": "Get all history records for a specific note with pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note History"
+                ],
+                "summary": "Get note history list",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Is in recycle bin // 是否在回收站",
+                        "name": "isRecycle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Note path // 笔记路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number // 页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size // 每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.NoteHistoryDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/history": {
+            "get": {
+                "description": "Get specific note history content by history record ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note History"
+                ],
+                "summary": "Get note history details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "History Record ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteHistoryDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/history/restore": {
+            "put": {
+                "description": "Restore note content to a specific history version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note History"
+                ],
+                "summary": "Restore note from history",
+                "parameters": [
+                    {
+                        "description": "Restore Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteHistoryRestoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/lint": {
+            "post": {
+                "description": "Return spelling and grammar issues of the given text, or of the note addressed by vault and path when text is empty. Issues come from the configured LanguageTool server or the bundled word-list spellchecker; offsets count UTF-16 code units like JavaScript string indices. Requires a note read scope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Check note spelling and grammar",
+                "parameters": [
+                    {
+                        "description": "Lint Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteLintRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteLintDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/meta": {
+            "get": {
+                "description": "Return the custom attributes stored on the server for a note, apart from its frontmatter",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note Meta"
+                ],
+                "summary": "Get note metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Note path // 笔记路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteMetaDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create or replace one custom attribute of a note; strings, numbers, booleans, objects and arrays keep their type",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note Meta"
+                ],
+                "summary": "Set note metadata",
+                "parameters": [
+                    {
+                        "description": "Metadata Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteMetaSetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteMetaDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Remove one custom attribute of a note",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note Meta"
+                ],
+                "summary": "Delete note metadata",
+                "parameters": [
+                    {
+                        "maxLength": 64,
+                        "type": "string",
+                        "example": "color",
+                        "description": "Attribute name // 属性名",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Note path // 笔记路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteMetaDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/outlinks": {
+            "get": {
+                "description": "Get other notes that the specified note links to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Get outgoing links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Note path // 笔记路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.NoteLinkItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/prepend": {
+            "post": {
+                "description": "Insert content at the beginning of a note (after frontmatter)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Prepend content to note",
+                "parameters": [
+                    {
+                        "description": "Prepend Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotePrependRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/recycle-clear": {
+            "delete": {
+                "description": "Permanently clear selected notes from recycle bin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Clear recycle bin",
+                "parameters": [
+                    {
+                        "description": "Clear Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteRecycleClearRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/rename": {
+            "post": {
+                "description": "Rename a note to a new path",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Rename note",
+                "parameters": [
+                    {
+                        "description": "Rename Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteRenameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/render": {
+            "get": {
+                "description": "Render the note addressed by vault and path, or the unsaved content of the request, to sanitized HTML. [[Wiki links]] point at the escaped path of the linked note with the class internal-link, ![[embeds]] and local images point at /api/file; targets that do not exist get the class is-unresolved. Frontmatter is returned separately. POST accepts the same parameters as a JSON body for long content. Requires a note read scope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Render note to HTML",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "# Title",
+                        "description": "Unsaved content to preview, the stored note is rendered when omitted // 待预览的未保存内容，省略时渲染已存储的笔记",
+                        "name": "content",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Note path, relative links resolve from its folder // 笔记路径，相对链接从其所在目录解析",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteRenderDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Render the note addressed by vault and path, or the unsaved content of the request, to sanitized HTML. [[Wiki links]] point at the escaped path of the linked note with the class internal-link, ![[embeds]] and local images point at /api/file; targets that do not exist get the class is-unresolved. Frontmatter is returned separately. POST accepts the same parameters as a JSON body for long content. Requires a note read scope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Render note to HTML",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "# Title",
+                        "description": "Unsaved content to preview, the stored note is rendered when omitted // 待预览的未保存内容，省略时渲染已存储的笔记",
+                        "name": "content",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Note path, relative links resolve from its folder // 笔记路径，相对链接从其所在目录解析",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteRenderDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/replace": {
+            "post": {
+                "description": "Perform find and replace operation in a note, supporting regular expressions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Find and replace in note",
+                "parameters": [
+                    {
+                        "description": "Find and Replace Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteReplaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteReplaceResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/restore": {
+            "put": {
+                "description": "Restore deleted note from trash",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Restore note",
+                "parameters": [
+                    {
+                        "description": "Restore Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteRestoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/unresolved-links": {
+            "get": {
+                "description": "List the wiki link targets of a vault that match no existing note, with the notes linking to them",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "List unresolved links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.NoteUnresolvedLinkItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/note/unresolved-links/create": {
+            "post": {
+                "description": "Create an empty note for each unresolved link target, or the selected ones, so the links resolve. Targets whose path is invalid or already taken are skipped",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Create stub notes for unresolved links",
+                "parameters": [
+                    {
+                        "description": "Create Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteUnresolvedLinkCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteUnresolvedLinkCreateResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/notes": {
+            "get": {
+                "description": "Get note list for current user with pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Get note list",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Return a highlighted match snippet per result for keyword searches // 关键词搜索时为每条结果返回高亮匹配片段",
+                        "name": "highlight",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Include archived folders in keyword search // 关键词搜索时包含归档文件夹",
+                        "name": "includeArchived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "description": "Is in recycle bin // 是否在回收站",
+                        "name": "isRecycle",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "todo",
+                        "description": "Search keyword // 搜索关键词",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "note1.md,note2.md",
+                        "description": "Comma-separated exact path list for share filter // 逗号分隔的精确路径列表，用于分享筛选",
+                        "name": "paths",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Whether to search content // 是否搜索内容",
+                        "name": "searchContent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "content",
+                        "description": "Search mode (path, content, regex) // 搜索模式（路径、内容、正则）",
+                        "name": "searchMode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "mtime",
+                        "description": "Sort by field // 排序字段",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "desc",
+                        "description": "Sort order // 排序顺序",
+                        "name": "sortOrder",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number // 页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size // 每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.NoteNoContentDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/notes/properties": {
+            "get": {
+                "description": "List the frontmatter properties used in the notes of a vault with their most common type and the number of notes having them, for property panels and query builders",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "List frontmatter properties",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.NotePropertyKeyDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/notes/query-frontmatter": {
+            "post": {
+                "description": "List the notes of a vault whose frontmatter matches all conditions, newest first. Operators: exists, notExists, eq, ne, contains (list element or case-insensitive text), gt, gte, lt and lte (numbers or dates like 2026-01-31). Only notes with frontmatter are considered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Note"
+                ],
+                "summary": "Query notes by frontmatter",
+                "parameters": [
+                    {
+                        "description": "Query Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteFrontmatterQueryRequest"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number // 页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size // 每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.NoteFrontmatterItem"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/notes/share-paths": {
+            "get": {
+                "tags": [
+                    "Share"
+                ],
+                "summary": "Get active shared note paths",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Vault name",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/setting": {
+            "get": {
+                "description": "Get setting info for current user by path or pathHash",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Setting"
+                ],
+                "summary": "Get setting info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "User/Theme",
+                        "description": "Setting path // 配置路径",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash // 路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.SettingDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a new setting or update an existing one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Setting"
+                ],
+                "summary": "Create or update setting",
+                "parameters": [
+                    {
+                        "description": "Create/Update Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SettingModifyOrCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.SettingDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Soft delete a setting by path or pathHash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Setting"
+                ],
+                "summary": "Delete setting",
+                "parameters": [
+                    {
+                        "description": "Delete Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SettingDeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/setting/rename": {
+            "post": {
+                "description": "Rename a setting and update its path and pathHash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Setting"
+                ],
+                "summary": "Rename setting",
+                "parameters": [
+                    {
+                        "description": "Rename Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SettingRenameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.SettingDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/settings": {
+            "get": {
+                "description": "Get setting list for current user with pagination and keyword filtering",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Setting"
+                ],
+                "summary": "Get setting list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "User/",
+                        "description": "Keyword // 关键词",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number // 页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size // 每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.SettingDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/share": {
+            "get": {
+                "description": "Get share token and info by vault and path",
+                "tags": [
+                    "Share"
+                ],
+                "summary": "Query share by path",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Resource path // 资源路径",
+                        "name": "path",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Resource path Hash // 资源路径哈希",
+                        "name": "pathHash",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "defaultVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ShareCreateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create a share token for a specific note or attachment, automatically resolve attachment references and authorize",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Share"
+                ],
+                "summary": "Create resource share",
+                "parameters": [
+                    {
+                        "description": "Share Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ShareCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.ShareCreateResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Cancel a share by ID or path parameters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Share"
+                ],
+                "summary": "Cancel share",
+                "parameters": [
+                    {
+                        "description": "Cancel Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ShareCancelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/share/file": {
+            "get": {
+                "description": "Get raw binary data of a specific attachment via share token, supports Range requests for seeking in audio and video or resuming downloads",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Share"
+                ],
+                "summary": "Get shared attachment content",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Auth Token",
+                        "name": "Share-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Resource ID // 资源 ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "123456",
+                        "description": "Share password // 分享密码",
+                        "name": "password",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range, e.g. bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ShareAuthToken": []
+                    }
+                ]
+            },
+            "head": {
+                "description": "Get raw binary data of a specific attachment via share token, supports Range requests for seeking in audio and video or resuming downloads",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Share"
+                ],
+                "summary": "Get shared attachment content",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Auth Token",
+                        "name": "Share-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Resource ID // 资源 ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "123456",
+                        "description": "Share password // 分享密码",
+                        "name": "password",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range, e.g. bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ShareAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/share/note": {
+            "get": {
+                "description": "Get specific note content (restricted read-only access) via share token",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Share"
+                ],
+                "summary": "Get shared note details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Auth Token",
+                        "name": "Share-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Resource ID // 资源 ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "123456",
+                        "description": "Share password // 分享密码",
+                        "name": "password",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ShareAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/share/password": {
+            "post": {
+                "description": "Set or update password and expiration time for a share record",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Share"
+                ],
+                "summary": "Update share password",
+                "parameters": [
+                    {
+                        "description": "Update Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SharePasswordUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/share/short_link": {
+            "post": {
+                "description": "Call sink.cool API to generate a short link for a given share record",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Share"
+                ],
+                "summary": "Create short link for share",
+                "parameters": [
+                    {
+                        "description": "Short Link Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ShareShortLinkCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/shares": {
+            "get": {
+                "description": "Get all active and inactive shares of the user, supports sorting and pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Share"
+                ],
+                "summary": "List shares",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sort field: created_at, updated_at, expires_at (default: created_at)",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort direction: asc or desc (default: desc)",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.ShareListItem"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/storage": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Get storage configuration list",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.StorageDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Create or update storage configuration",
+                "parameters": [
+                    {
+                        "description": "Storage Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.StoragePostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.StorageDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Delete storage configuration",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Storage ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/storage/enabled_types": {
+            "get": {
+                "description": "Get list of enabled storage types. Possible values: localfs, oss, s3, r2, minio, webdav",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Get enabled storage types",
+                "responses": {
+                    "200": {
+                        "description": "Success. Data contains: localfs, oss, s3, r2, minio, webdav",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/storage/validate": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Validate storage connection",
+                "parameters": [
+                    {
+                        "description": "Storage Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.StoragePostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/support": {
+            "get": {
+                "description": "Get support records for the specified language with pagination and sorting",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get support records",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Language code (default: en)",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by field (amount, time, name, item)",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order (asc, desc)",
+                        "name": "sortOrder",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/app.ListRes"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sync-logs": {
+            "get": {
+                "description": "Get sync log list for current user with optional type/action filters and pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sync Log"
+                ],
+                "summary": "Get sync log list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "modify",
+                        "description": "Action type // 操作类型",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "note",
+                        "description": "Resource type: note / file / setting / folder // 资源类型",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name (optional filter) // 保险库名称（可选过滤）",
+                        "name": "vault",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number // 页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size // 每页数量",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.SyncLogDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/templates": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Template"
+                ],
+                "summary": "Get note templates",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.NoteTemplateDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Template content may use {{date}} (YYYY-MM-DD), {{time}} (HH:mm) and {{title}} (note file name without extension); {{date:FORMAT}} and {{time:FORMAT}} take a Moment.js format. Variables are expanded when a note is created with the template parameter of POST /api/note.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Template"
+                ],
+                "summary": "Create a note template",
+                "parameters": [
+                    {
+                        "description": "Template Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteTemplateDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/templates/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Template"
+                ],
+                "summary": "Get a note template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteTemplateDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Template"
+                ],
+                "summary": "Update a note template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.NoteTemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.NoteTemplateDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Template"
+                ],
+                "summary": "Delete a note template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/capabilities": {
+            "get": {
+                "description": "Return the server-evaluated experimental feature flags of the current user. Operators roll features out to canary accounts in the feature-flags config before enabling them globally.\n返回服务端为当前用户判断的实验性功能开关。运维人员可先在 feature-flags 配置中为灰度账号开启功能，再全局开启。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user capabilities",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserCapabilitiesDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/change_password": {
+            "post": {
+                "description": "Handle password change request for current user, validate old password and update new password.\n处理当前用户的修改密码请求，验证旧密码并更新新密码。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Change user password",
+                "parameters": [
+                    {
+                        "description": "Change Password Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Parameters / Old Password Incorrect",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/data-inventory": {
+            "get": {
+                "description": "Return a machine-readable summary of every category of personal data stored for the user (account, notes, attachments, history, logs, shares, tokens, backup targets, Git remotes) to answer data-subject (GDPR) requests. Only counts and locations are returned, never content or credentials. The admin may pass uid to inspect another user.\n返回为用户存储的各类个人数据的机器可读摘要（账号、笔记、附件、历史、日志、分享、Token、备份目标、Git 远端），用于答复数据主体（GDPR）请求。仅返回数量与位置，不含内容或凭据。管理员可传入 uid 查看其他用户。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get personal data inventory",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID (admin only)",
+                        "name": "uid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.DataInventoryDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "403": {
+                        "description": "Not Admin",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/device": {
+            "delete": {
+                "description": "Revokes the token the device authenticated with, closes its WebSocket connections and removes it from the list. Other devices sharing the same token are signed out as well.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Revoke a device",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Device ID // 设备 ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/devices": {
+            "get": {
+                "description": "Lists every device that has connected over WebSocket, most recently seen first, with its client name, version, platform, last IP and whether it is connected now.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get devices",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.DeviceDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/heatmap": {
+            "get": {
+                "description": "Note edits saved per day across all vaults of the current user, GitHub contributions style: one entry per day up to today (default 365 days) with an intensity level from 0 to 4, plus totals and the current and longest writing streaks. Days are in server local time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get writing activity heatmap",
+                "parameters": [
+                    {
+                        "maximum": 366,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 365,
+                        "description": "Days up to today, default 365 // 截至今天的天数，默认 365",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserHeatmapDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/info": {
+            "get": {
+                "description": "Handle request to get current user info.\n处理获取当前用户信息的请求。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user info",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/login": {
+            "post": {
+                "description": "Handle user login HTTP request, validate parameters and return auth token.\n处理用户登录 HTTP 请求，验证参数并返回认证 Token。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "Login Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Parameters / Invalid Credentials",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/password/forgot": {
+            "post": {
+                "description": "Email a reset link to the account of the address. Always succeeds for unknown addresses so registered emails cannot be discovered. Needs alert.smtp and server.ext-api-url to be configured.\n向该地址的账号发送密码重置链接。地址未注册时同样返回成功，避免探测已注册邮箱。需配置 alert.smtp 与 server.ext-api-url。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Send password reset email",
+                "parameters": [
+                    {
+                        "description": "Reset Email Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserPasswordResetSendRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Parameters / Reset Disabled",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/password/reset": {
+            "get": {
+                "description": "HTML page opened by the link of a reset email, it reads the token from the query and posts the new password to /api/user/password/reset.\n重置邮件链接打开的 HTML 页面，从查询参数读取令牌并将新密码提交到 /api/user/password/reset。",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Password reset page",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reset token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "HTML page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Set a new password with the token of a reset email. The token stops working once the password has changed, and every token of the user is revoked.\n使用重置邮件中的令牌设置新密码。密码修改后令牌即失效，并撤销该用户的全部 Token。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Reset Password Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Parameters / Invalid Or Expired Link",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/register": {
+            "post": {
+                "description": "Handle user registration HTTP request, validate parameters and call UserService. Registration may be disabled in server settings, an admin invitation code still admits the user then. With user.register-approval on, uninvited registrations wait for the admin and the response carries no user.\n处理用户注册 HTTP 请求，验证参数并调用 UserService。注册功能可能在服务器设置中被禁用，此时仍可凭管理员发放的邀请码注册。开启 user.register-approval 时，无邀请码的注册需等待管理员审核，响应中不含用户信息。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "User registration",
+                "parameters": [
+                    {
+                        "description": "Register Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Parameters / Registration Disabled / Invalid Invitation Code / User Already Exists",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/setting": {
+            "get": {
+                "description": "Get a per-user setting by key. Per-user settings are key-value pairs shared by all devices of the user, independent of vaults, e.g. theme, editor preferences or a plugin settings blob.\n根据键获取用户级设置。用户级设置是该用户所有设备共享的键值对，与笔记库无关，例如主题、编辑器偏好或插件设置数据。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user setting",
+                "parameters": [
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "example": "editor.theme",
+                        "description": "Setting key // 设置键",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserSettingDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Parameters / Setting Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Create or overwrite a per-user setting. version is the version the change is based on (0 for a new key); when another device has written since, the change is refused with a conflict carrying the current setting unless its mtime is newer than the stored one. Other devices of the user receive the change through the WebSocket action SettingsSync.\n创建或覆盖用户级设置。version 为修改所基于的版本（新键为 0）；若其间已有其他设备写入，除非 mtime 晚于已存储的版本，否则拒绝修改并返回携带当前设置的冲突错误。该用户的其他设备通过 WebSocket 动作 SettingsSync 接收变更。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Write user setting",
+                "parameters": [
+                    {
+                        "description": "Setting",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSettingPutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserSettingDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Parameters / Conflict, data holds the current setting",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserSettingDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Delete a per-user setting, with the same version handling as writing it. Other devices of the user receive the deletion through the WebSocket action SettingsSync.\n删除用户级设置，版本处理与写入相同。该用户的其他设备通过 WebSocket 动作 SettingsSync 接收删除。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete user setting",
+                "parameters": [
+                    {
+                        "description": "Setting",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSettingDeleteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserSettingDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Parameters / Setting Not Found / Conflict, data holds the current setting",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserSettingDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/setting/sync": {
+            "get": {
+                "description": "List the per-user settings updated after lastTime, deletions included, and the cursor for the next call. lastTime 0 lists every current setting. The WebSocket action SettingsSync takes the same parameters and replies with the same message.\n列出 lastTime 之后更新的用户级设置（包含删除）及下次调用的游标。lastTime 为 0 时列出全部现有设置。WebSocket 动作 SettingsSync 使用相同参数并回复相同消息。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Sync user settings",
+                "parameters": [
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "example": "req-1",
+                        "description": "Echoed back in the WebSocket reply // 在 WebSocket 回复中原样返回",
+                        "name": "context",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1700000000000,
+                        "description": "Only settings updated after this server timestamp, 0 lists all // 仅返回该服务端时间戳之后更新的设置，0 返回全部",
+                        "name": "lastTime",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserSettingsSyncMessage"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/settings": {
+            "get": {
+                "description": "Get the settings of the current user. softDeleteRetentionTime is the user-wide retention of soft-deleted notes, attachments and settings, empty when the server default applies; vaults may override it again through /api/vault/retention.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user settings",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserSettingsDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Update the settings of the current user, fields left out are not changed. softDeleteRetentionTime takes durations like 30d or 12h, 0 keeps soft-deleted items forever and an empty string falls back to the server default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update user settings",
+                "parameters": [
+                    {
+                        "description": "Settings",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserSettingsDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/totp": {
+            "get": {
+                "description": "Get whether TOTP two-factor authentication is enabled for the current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get 2FA status",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.TwoFactorStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/totp/disable": {
+            "post": {
+                "description": "Disable TOTP two-factor authentication after verifying a TOTP or recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Disable 2FA",
+                "parameters": [
+                    {
+                        "description": "TOTP or Recovery Code",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/totp/enable": {
+            "post": {
+                "description": "Confirm the pending TOTP secret with a code; returns one-time recovery codes that are shown only once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Enable 2FA",
+                "parameters": [
+                    {
+                        "description": "TOTP Code",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.TwoFactorEnableDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/totp/setup": {
+            "post": {
+                "description": "Generate a new TOTP secret and otpauth:// URI for QR display; 2FA stays off until confirmed via enable",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Set up 2FA",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.TwoFactorSetupDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/user/usage": {
+            "get": {
+                "description": "Get the bytes the current user uses on disk for note content files, attachments, history snapshots, settings, in-progress backups and the SQLite databases, with the content size of each vault. The numbers are kept up to date incrementally instead of walking the storage on every call.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get disk usage",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.UserUsageDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault": {
+            "get": {
+                "description": "Get all note vaults for current user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Get vault list",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.VaultDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Be used to create a new vault or update an existing vault configuration based on the ID in the request parameters",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Create or update vault",
+                "parameters": [
+                    {
+                        "description": "Vault Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VaultPostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VaultDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Permanently delete a specific note vault and all associated notes and attachments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Delete vault",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Vault ID // 保险库 ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/activity": {
+            "get": {
+                "description": "Recent note creations, edits, renames, deletions, attachment uploads and restores of a vault, newest first and grouped by day (server local time). Built from the current note and file records plus the audit log, so earlier edits of a path that changed again are not listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Get vault activity timeline",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Page number, starting from 1 // 页码，从 1 开始",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 50,
+                        "description": "Events per page, default 50 // 每页事件数，默认 50",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VaultActivityDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/as-of": {
+            "get": {
+                "description": "List the notes that existed in a vault at the given timestamp (milliseconds), sorted by path, for a read-only time machine view; notes purged from the trash are not listed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Browse vault as of a point in time",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1700000000000,
+                        "description": "Point in time in milliseconds // 时间点（毫秒）",
+                        "name": "timestamp",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.VaultAsOfItemDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/as-of/note": {
+            "get": {
+                "description": "Get the content a note had at the given timestamp (milliseconds), reconstructed from its history versions; precision is limited to the retained history",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Get note content as of a point in time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "Daily/a.md",
+                        "description": "Note path // 笔记路径",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Path hash, derived from path when empty // 路径哈希，为空时由路径计算",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1700000000000,
+                        "description": "Point in time in milliseconds // 时间点（毫秒）",
+                        "name": "timestamp",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VaultAsOfNoteDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/export": {
+            "get": {
+                "description": "Stream a ZIP of the vault's notes (.md) and attachments, optionally limited to a folder or to entries changed since a timestamp. Export redaction rules apply. An interrupted download can be resumed with Range (and If-Range set to the Last-Modified of the first response), which is served from the cached archive",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Export vault as ZIP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "Projects",
+                        "description": "Only export this folder, empty for the whole vault // 仅导出该目录，为空表示整个保险库",
+                        "name": "folder",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1700000000000,
+                        "description": "Only export entries changed after this timestamp (ms) // 仅导出该时间戳（毫秒）之后变更的条目",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ZIP archive",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Resumed part of the ZIP archive",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/force-delete-item": {
+            "post": {
+                "description": "Permanently delete a single note or file (attachment) in a vault",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Force delete a single item",
+                "parameters": [
+                    {
+                        "description": "Delete Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VaultForceDeleteItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/get": {
+            "get": {
+                "description": "Get specific vault configuration details by vault ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Get vault details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Vault ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VaultDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/graph": {
+            "get": {
+                "description": "Get the notes of a vault with their tags and the [[wiki links]] between them for graph views. Folder and tag filter the notes; path with depth (default 1, at most 5) returns only the notes within that many link hops of the note, in either direction. Links to missing notes are left out and at most 5000 notes are returned. Requires a note read scope.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Get vault link graph",
+                "parameters": [
+                    {
+                        "maximum": 5,
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Link hops from the center note, defaults to 1 // 距中心笔记的链接跳数，默认为 1",
+                        "name": "depth",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Projects",
+                        "description": "Only notes in this folder // 仅包含该目录下的笔记",
+                        "name": "folder",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "ReadMe.md",
+                        "description": "Center note of the neighborhood mode // 邻域模式的中心笔记",
+                        "name": "path",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "hash123",
+                        "description": "Path hash of the center note // 中心笔记的路径哈希",
+                        "name": "pathHash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "project",
+                        "description": "Only notes with this tag, nested tags included // 仅包含带有该标签的笔记，包括其嵌套标签",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VaultGraphDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/import": {
+            "post": {
+                "description": "Import notes (.md) and attachments from a ZIP archive into a vault. The archive is either uploaded as \"file\" or, for admins, read from serverPath. The import runs as a background job, the request returns once it is queued. Progress and the outcome are pushed to the user's WebSocket connections as VaultImportProgress",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Import ZIP into vault",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Vault name",
+                        "name": "vault",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target folder inside the vault",
+                        "name": "folder",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ZIP path on the server (admin only)",
+                        "name": "serverPath",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "ZIP archive",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VaultImportJob"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/import/local": {
+            "post": {
+                "description": "Import notes (.md) and attachments from a directory on the server, such as an existing Obsidian vault, keeping folder structure and file modification times. Hidden folders (.obsidian, .trash) and symlinks are skipped. Admin only. The import runs as a background job, the request returns once it is queued. Progress and the outcome are pushed to the user's WebSocket connections as VaultImportProgress",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Import local vault directory",
+                "parameters": [
+                    {
+                        "description": "Import Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VaultImportLocalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import queued",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VaultImportJob"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/rebuild-index": {
+            "post": {
+                "description": "Rebuild full-text search index from physical database and files for a specific vault, restricted to webgui client",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Rebuild vault FTS index",
+                "parameters": [
+                    {
+                        "description": "Rebuild Index Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VaultRebuildIndexRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/retention": {
+            "get": {
+                "description": "List every vault with its own soft delete retention, empty when it inherits the user setting, and the retention actually applied. 0 keeps soft-deleted items forever.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "List vault soft delete retention",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.VaultRetentionDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Override the soft delete retention of one vault, e.g. 7d to purge a work vault aggressively or 0 to keep the deleted items of a personal vault forever. An empty retention removes the override so the vault inherits the user setting again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Set vault soft delete retention",
+                "parameters": [
+                    {
+                        "description": "Retention",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VaultRetentionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VaultRetentionDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/site": {
+            "post": {
+                "description": "Render the vault, or one of its folders, to a static HTML site with an index page, backlinks and a search index. The site is pushed through one of the user's storage targets under site/\u003cvault\u003e/\u003cfolder\u003e, or, for admins, written to outDir on the server. Export redaction rules apply",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Export vault as static site",
+                "parameters": [
+                    {
+                        "description": "Export Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VaultSiteExportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VaultSiteExportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/stats": {
+            "get": {
+                "description": "Word and character counts of a vault: totals with estimated reading time, per folder, the longest notes and the writing activity per day (edits and words added or removed, server local time). Each CJK character counts as one word, frontmatter is not counted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Get vault word count statistics",
+                "parameters": [
+                    {
+                        "maximum": 365,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 30,
+                        "description": "Days of writing activity up to today, default 30 // 截至今天的写作活动天数，默认 30",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 10,
+                        "description": "Number of longest notes, default 10 // 最长笔记的数量，默认 10",
+                        "name": "top",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VaultStatsDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/sync-status": {
+            "get": {
+                "description": "Per vault and client: last successful sync, synced changes, bytes transferred and recent failures over the last days (default 7, at most 90). Per device: online state, last sync and the broadcast changes it has not received yet. upToDate is true when no device has pending changes and no client failed its last sync.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Get vault sync status",
+                "parameters": [
+                    {
+                        "maximum": 90,
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 7,
+                        "description": "Days of sync logs covered, default 7 // 统计的同步日志天数，默认 7",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name (optional filter) // 保险库名称（可选过滤）",
+                        "name": "vault",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.SyncStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/trash": {
+            "get": {
+                "description": "List deleted notes, files and folders of a vault with their deletion timestamps, most recently deleted first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "List vault trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "MyVault",
+                        "description": "Vault name // 保险库名称",
+                        "name": "vault",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.VaultTrashItemDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/vault/trash/empty": {
+            "post": {
+                "description": "Permanently delete the selected notes, files and folders in the trash, or everything when all is true, instead of waiting for the soft delete retention time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vault"
+                ],
+                "summary": "Empty vault trash",
+                "parameters": [
+                    {
+                        "description": "Purge Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.VaultTrashEmptyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VaultTrashEmptyResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/version": {
+            "get": {
+                "description": "Get current server software version, Git tag, and build time",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get server version info",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.VersionDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/version/probe": {
+            "get": {
+                "description": "Parallel-probe GitHub and CNB release endpoints, return reachability, latency, recommended source and current selected mode",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Probe version sources latency",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.SourceProbeDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/webgui/config": {
+            "get": {
+                "description": "Get non-sensitive configuration required for frontend display, such as font settings, registration status, etc.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Config"
+                ],
+                "summary": "Get WebGUI basic config",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.AdminWebGUIConfig"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhook/config": {
+            "post": {
+                "description": "Events: note.created, note.deleted, backup.succeeded, backup.failed, git_sync.failed, user.login, user.login_failed; empty subscribes to all. Requests are signed with X-FNS-Signature when a secret is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Create or update a webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook Parameters",
+                        "name": "params",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.WebhookDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Webhook ID // Webhook ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/webhook/configs": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get webhooks",
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.WebhookDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/api/webhook/deliveries": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Get webhook delivery log",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "Webhook ID, 0 lists every webhook // Webhook ID，为 0 时列出全部",
+                        "name": "webhookId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page Size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/app.Res"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "allOf": [
+                                                {
+                                                    "$ref": "#/definitions/app.ListRes"
+                                                },
+                                                {
+                                                    "type": "object",
+                                                    "properties": {
+                                                        "list": {
+                                                            "type": "array",
+                                                            "items": {
+                                                                "$ref": "#/definitions/dto.WebhookDeliveryDTO"
+                                                            }
+                                                        }
+                                                    }
+                                                }
+                                            ]
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Params",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "401": {
+                        "description": "Token Required",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "UserAuthToken": []
+                    }
+                ]
+            }
+        },
+        "/feed/{slug}": {
+            "get": {
+                "description": "Publish the most recently modified notes below a shared folder as an Atom feed, each entry carrying a plain-text summary and the note rendered to sanitized HTML. The slug is the share token of the folder share followed by \".xml\", as returned in the url of GET /api/shares. Password protected shares take the password query parameter.",
+                "produces": [
+                    "application/atom+xml"
+                ],
+                "tags": [
+                    "Share"
+                ],
+                "summary": "Atom feed of a shared folder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Share token followed by .xml",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share password",
+                        "name": "password",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Atom feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Share Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/app.Res"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api_router.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "description": "\"connected\" or \"error\" // \"connected\" 或 \"error\"",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"healthy\" or \"unhealthy\" // \"healthy\" 或 \"unhealthy\"",
+                    "type": "string"
+                },
+                "uptime": {
+                    "description": "Uptime (seconds) // 运行时间（秒）",
+                    "type": "number"
+                },
+                "version": {
+                    "description": "Service version number // 服务版本号",
+                    "type": "string"
+                }
+            }
+        },
+        "app.HistoricalVersion": {
+            "type": "object",
+            "properties": {
+                "changelogContent": {
+                    "description": "Changelog content // 更新日志内容",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version name // 版本号",
+                    "type": "string"
+                }
+            }
+        },
+        "app.ListRes": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "description": "Data list // 数据清单"
+                },
+                "pager": {
+                    "description": "Pagination info // 翻页信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/app.Pager"
+                        }
+                    ]
+                }
+            }
+        },
+        "app.Pager": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "description": "Page number // 页码",
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "description": "Page size // 每页数量",
+                    "type": "integer"
+                },
+                "totalRows": {
+                    "description": "Total rows // 总行数",
+                    "type": "integer"
+                }
+            }
+        },
+        "app.Res": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "context": {},
+                "data": {},
+                "details": {},
+                "message": {},
+                "pageIndex": {
+                    "description": "1-BASED wire value: 0/absent = non-paginated message, n\u003e0 = download page n-1 (internal 0-based). 1-based so page 0 stays distinguishable from non-paginated messages under proto3 zero-value elision; only set for paginated download messages on pv\u003e=2 connections. Client-to-server PageAck.pageIndex stays 0-based. // 线上值为 1-BASED：0/缺省 = 非分页消息，n\u003e0 = 下载页 n-1（内部 0-based）。采用 1-based 是为了让第 0 页在 proto3 零值不编码规则下仍可与非分页消息区分；仅 pv\u003e=2 连接的分页下载消息会设置。客户端→服务端的 PageAck.pageIndex 保持 0-based"
+                },
+                "path": {},
+                "status": {
+                    "type": "boolean"
+                },
+                "vault": {}
+            }
+        },
+        "app.WSClientInfo": {
+            "type": "object",
+            "properties": {
+                "clientName": {
+                    "type": "string"
+                },
+                "clientType": {
+                    "type": "string"
+                },
+                "clientVersion": {
+                    "type": "string"
+                },
+                "nickname": {
+                    "type": "string"
+                },
+                "platformInfo": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "remoteAddr": {
+                    "type": "string"
+                },
+                "startTime": {
+                    "type": "string"
+                },
+                "tokenId": {
+                    "type": "integer"
+                },
+                "traceId": {
+                    "type": "string"
+                },
+                "uid": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AdminBackupStatsDTO": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "description": "Failed runs // 失败次数",
+                    "type": "integer"
+                },
+                "stopped": {
+                    "description": "Stopped runs // 被停止次数",
+                    "type": "integer"
+                },
+                "success": {
+                    "description": "Successful runs, including runs without changes // 成功次数，包含无更新的运行",
+                    "type": "integer"
+                },
+                "successRate": {
+                    "description": "Success / (success + failed), 0 without finished runs // 成功率 = 成功 / (成功 + 失败)，无已完成运行时为 0",
+                    "type": "number"
+                },
+                "window": {
+                    "description": "Time window: 24h, 7d or 30d // 时间窗口：24h、7d 或 30d",
+                    "type": "string",
+                    "example": "24h"
+                }
+            }
+        },
+        "dto.AdminBrandingConfig": {
+            "type": "object",
+            "properties": {
+                "accentColor": {
+                    "description": "Accent hex color // 强调色（十六进制）",
+                    "type": "string",
+                    "maxLength": 9
+                },
+                "customCss": {
+                    "description": "Custom CSS // 自定义样式",
+                    "type": "string",
+                    "maxLength": 65536
+                },
+                "instanceName": {
+                    "description": "Instance name shown in page titles // 页面标题中的实例名称",
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "loginMessage": {
+                    "description": "Login page message // 登录页消息",
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "logoUrl": {
+                    "description": "Logo and favicon URL // 标志与站点图标地址",
+                    "type": "string",
+                    "maxLength": 1024
+                },
+                "primaryColor": {
+                    "description": "Primary hex color // 主色（十六进制）",
+                    "type": "string",
+                    "maxLength": 9
+                }
+            }
+        },
+        "dto.AdminCPUInfo": {
+            "type": "object",
+            "properties": {
+                "loadAvg": {
+                    "description": "Load average // 平均负载",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.AdminLoadInfo"
+                        }
+                    ]
+                },
+                "logicalCores": {
+                    "description": "Logical cores // 逻辑核心数",
+                    "type": "integer"
+                },
+                "modelName": {
+                    "description": "Model name // 型号",
+                    "type": "string"
+                },
+                "percent": {
+                    "description": "Usage percentage per core // 每个核心的使用率",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "physicalCores": {
+                    "description": "Physical cores // 物理核心数",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AdminCheckResponse": {
+            "type": "object",
+            "properties": {
+                "isAdmin": {
+                    "description": "Whether have admin privileges // 是否具有管理员权限",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.AdminCloudflareConfig": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Whether to enable cloudflare tunnel // 是否启用 cloudflare 隧道",
+                    "type": "boolean"
+                },
+                "logEnabled": {
+                    "description": "Whether to enable cloudflare tunnel logging // 是否开启 cloudflare 隧道日志",
+                    "type": "boolean"
+                },
+                "token": {
+                    "description": "cloudflare tunnel token // cloudflare 隧道令牌",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AdminConfig": {
+            "type": "object",
+            "properties": {
+                "adminUid": {
+                    "description": "Admin UID // 管理员 UID",
+                    "type": "integer"
+                },
+                "authTokenKey": {
+                    "description": "Auth token key // 认证 Token 密钥",
+                    "type": "string"
+                },
+                "customResponseHeaders": {
+                    "description": "Custom HTTP response headers // 自定义 HTTP 响应头",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "defaultApiFolder": {
+                    "description": "Default API folder // 默认 API 目录",
+                    "type": "string"
+                },
+                "defaultContextTimeout": {
+                    "description": "Default context timeout // 默认上下文超时",
+                    "type": "integer"
+                },
+                "defaultPageSize": {
+                    "description": "Default page size // 默认每页显示数",
+                    "type": "integer"
+                },
+                "downloadSessionTimeout": {
+                    "description": "Download session timeout // 下载分片超时",
+                    "type": "string"
+                },
+                "fileChunkSize": {
+                    "description": "File chunk size // 文件分块大小",
+                    "type": "string"
+                },
+                "fontSet": {
+                    "description": "Font set // 字体设置",
+                    "type": "string"
+                },
+                "ftsBleveEnabled": {
+                    "description": "Whether Bleve FTS is enabled // 是否启用 Bleve 全文搜索",
+                    "type": "boolean"
+                },
+                "ftsBleveStoreRaw": {
+                    "description": "Whether Bleve stores raw content // Bleve 全文搜索是否存储原始文本",
+                    "type": "boolean"
+                },
+                "gitEmail": {
+                    "description": "Git author email // Git 提交的作者邮箱",
+                    "type": "string"
+                },
+                "gitName": {
+                    "description": "Git author name // Git 提交的作者名称",
+                    "type": "string"
+                },
+                "historyKeepDuration": {
+                    "description": "Maximum age of history versions // 历史版本最长保留时间",
+                    "type": "string"
+                },
+                "historyKeepVersions": {
+                    "description": "History versions to keep // 历史版本保留数",
+                    "type": "integer"
+                },
+                "historySaveDelay": {
+                    "description": "History save delay // 历史保存延迟",
+                    "type": "string"
+                },
+                "isReturnSussess": {
+                    "description": "Whether to return success detail // 是否返回成功详情",
+                    "type": "boolean"
+                },
+                "logLevel": {
+                    "description": "Log level: debug | info | warn | error // 日志级别：debug | info | warn | error",
+                    "type": "string"
+                },
+                "maxAttachmentSize": {
+                    "description": "Largest attachment, e.g. 100MB; 0 = no limit // 单个附件最大大小，如 100MB；0 = 不限制",
+                    "type": "string"
+                },
+                "maxNoteSize": {
+                    "description": "Largest note content, e.g. 5MB; 0 = no limit // 单篇笔记正文最大大小，如 5MB；0 = 不限制",
+                    "type": "string"
+                },
+                "maxNotesPerVault": {
+                    "description": "Most notes per vault; 0 = no limit // 每个笔记库最多笔记数；0 = 不限制",
+                    "type": "integer"
+                },
+                "maxPageSize": {
+                    "description": "Max page size // 最大每页显示限制",
+                    "type": "integer"
+                },
+                "pipelineWindowDown": {
+                    "description": "Download pipeline window size for pv\u003e=2 connections; 0 = stop-and-wait // pv\u003e=2 连接的下行流水线窗口大小；0 = stop-and-wait",
+                    "type": "integer"
+                },
+                "pipelineWindowUp": {
+                    "description": "Upload pipeline window size for pv\u003e=2 connections; 0 = stop-and-wait // pv\u003e=2 连接的上行流水线窗口大小；0 = stop-and-wait",
+                    "type": "integer"
+                },
+                "pullReleaseChannel": {
+                    "description": "Update version channel: stable | beta // 更新版本通道：stable | beta",
+                    "type": "string"
+                },
+                "pullSource": {
+                    "description": "Data pull source: auto | github | cnb // 数据拉取源：auto | github | cnb",
+                    "type": "string"
+                },
+                "registerIsEnable": {
+                    "description": "Registration enablement // 是否开启注册",
+                    "type": "boolean"
+                },
+                "requireAdminTotp": {
+                    "description": "Require 2FA for the admin UID // 是否要求管理员启用两步验证",
+                    "type": "boolean"
+                },
+                "shareTokenExpiry": {
+                    "description": "Share token expiry // 分享 Token 有效期",
+                    "type": "string"
+                },
+                "shareTokenKey": {
+                    "description": "Share token key // 分享 Token 密钥",
+                    "type": "string"
+                },
+                "softDeleteRetentionTime": {
+                    "description": "Soft delete retention time // 软删除保留时间",
+                    "type": "string"
+                },
+                "swaggerUiEnabled": {
+                    "description": "Expose Swagger UI at /docs in release mode // release 模式下是否开放 /docs 的 Swagger UI",
+                    "type": "boolean"
+                },
+                "syncLogRetentionTime": {
+                    "description": "Sync log retention time // 同步日志保留时长",
+                    "type": "string"
+                },
+                "tempPath": {
+                    "description": "Temporary file path // 临时文件路径",
+                    "type": "string"
+                },
+                "tokenExpiry": {
+                    "description": "Token expiry // Token 有效期",
+                    "type": "string"
+                },
+                "uploadSessionTimeout": {
+                    "description": "Upload session timeout // 上传会话超时时间",
+                    "type": "string"
+                },
+                "webguiLoginTokenBindIp": {
+                    "description": "WebGUI login token bind IP // WebGUI 登录 Token 是否绑定 IP",
+                    "type": "boolean"
+                },
+                "webguiLoginTokenExpiry": {
+                    "description": "WebGUI login token expiry // WebGUI 登录 Token 有效期",
+                    "type": "string"
+                },
+                "workerPoolMaxWorkers": {
+                    "description": "Worker pool max workers // 协程池最大协程数",
+                    "type": "integer"
+                },
+                "workerPoolQueueSize": {
+                    "description": "Worker pool queue size // 协程池队列大小",
+                    "type": "integer"
+                },
+                "writeQueueCapacity": {
+                    "description": "Write queue capacity // 写入队列容量",
+                    "type": "integer"
+                },
+                "writeQueueIdleTime": {
+                    "description": "Write queue idle cleanup time // 写入队列空闲清理时长",
+                    "type": "string"
+                },
+                "writeQueueTimeout": {
+                    "description": "Write queue timeout // 写入队列超时",
+                    "type": "string"
+                },
+                "wsCheckUtf8Enabled": {
+                    "description": "Whether ws check UTF-8 is enabled // WebSocket 是否开启 UTF-8 校验",
+                    "type": "boolean"
+                },
+                "wsCompressionEnabled": {
+                    "description": "Whether ws compression is enabled // WebSocket 是否开启压缩",
+                    "type": "boolean"
+                },
+                "wsCompressionLevel": {
+                    "description": "Ws compression level // WebSocket 压缩级别",
+                    "type": "integer"
+                },
+                "wsCompressionThreshold": {
+                    "description": "Ws compression threshold // WebSocket 压缩阈值",
+                    "type": "integer"
+                },
+                "wsParallelEnabled": {
+                    "description": "Whether ws parallel is enabled // WebSocket 并行处理是否开启",
+                    "type": "boolean"
+                },
+                "wsParallelGolimit": {
+                    "description": "Ws parallel goroutine limit // WebSocket 并行协程限制",
+                    "type": "integer"
+                },
+                "wsReadMaxPayloadSize": {
+                    "description": "WebSocket max read payload // WebSocket 最大读取负载",
+                    "type": "string"
+                },
+                "wsWriteMaxPayloadSize": {
+                    "description": "WebSocket max write payload // WebSocket 最大写入负载",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AdminHostInfo": {
+            "type": "object",
+            "properties": {
+                "arch": {
+                    "description": "Architecture // 架构",
+                    "type": "string"
+                },
+                "currentTime": {
+                    "description": "Current system time // 当前系统时间",
+                    "type": "string"
+                },
+                "hostname": {
+                    "description": "Hostname // 主机名",
+                    "type": "string"
+                },
+                "kernelVersion": {
+                    "description": "Kernel version // 内核版本",
+                    "type": "string"
+                },
+                "os": {
+                    "description": "Operating system // 操作系统",
+                    "type": "string"
+                },
+                "osPretty": {
+                    "description": "Detailed OS name // 详细操作系统名称",
+                    "type": "string"
+                },
+                "platform": {
+                    "description": "Platform name // 平台",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Time zone name // 时区名称",
+                    "type": "string"
+                },
+                "timezoneOffset": {
+                    "description": "Time zone offset in seconds // 时区偏移（秒）",
+                    "type": "integer"
+                },
+                "uptime": {
+                    "description": "System uptime // 系统运行时间",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AdminLoadInfo": {
+            "type": "object",
+            "properties": {
+                "load1": {
+                    "description": "Load 1 min // 1分钟负载",
+                    "type": "number"
+                },
+                "load15": {
+                    "description": "Load 15 min // 15分钟负载",
+                    "type": "number"
+                },
+                "load5": {
+                    "description": "Load 5 min // 5分钟负载",
+                    "type": "number"
+                }
+            }
+        },
+        "dto.AdminMemoryInfo": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "Available memory // 可用内存",
+                    "type": "integer"
+                },
+                "swapTotal": {
+                    "description": "Total swap space // 交换区总量",
+                    "type": "integer"
+                },
+                "swapUsed": {
+                    "description": "Used swap space // 交换区已用",
+                    "type": "integer"
+                },
+                "swapUsedPercent": {
+                    "description": "Swap usage percentage // 交换区使用率",
+                    "type": "number"
+                },
+                "total": {
+                    "description": "Total physical memory // 系统总内存",
+                    "type": "integer"
+                },
+                "used": {
+                    "description": "Used memory // 已用内存",
+                    "type": "integer"
+                },
+                "usedPercent": {
+                    "description": "Memory usage percentage // 内存使用率",
+                    "type": "number"
+                }
+            }
+        },
+        "dto.AdminProcessInfo": {
+            "type": "object",
+            "properties": {
+                "cpuPercent": {
+                    "description": "CPU Usage percentage // CPU 使用率",
+                    "type": "number"
+                },
+                "memoryPercent": {
+                    "description": "Memory Usage percentage // 内存使用率",
+                    "type": "number"
+                },
+                "name": {
+                    "description": "Process Name // 进程名称",
+                    "type": "string"
+                },
+                "pid": {
+                    "description": "Process ID // 进程 ID",
+                    "type": "integer"
+                },
+                "ppid": {
+                    "description": "Parent Process ID // 父进程 ID",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AdminRuntimeInfo": {
+            "type": "object",
+            "properties": {
+                "buckHashSys": {
+                    "description": "Memory obtained from system for profiling bucket hash table (bytes) // 分析桶哈希表占用的系统内存",
+                    "type": "integer"
+                },
+                "gcSys": {
+                    "description": "Memory obtained from system for metadata for GC (bytes) // GC 元数据占用的系统内存",
+                    "type": "integer"
+                },
+                "heapIdle": {
+                    "description": "Memory in idle spans (bytes) // 空闲 Span 占用的内存",
+                    "type": "integer"
+                },
+                "heapInuse": {
+                    "description": "Memory in in-use spans (bytes) // 正在使用的 Span 占用的内存",
+                    "type": "integer"
+                },
+                "heapReleased": {
+                    "description": "Memory released to OS (bytes) // 释放回操作系统的内存（字节）",
+                    "type": "integer"
+                },
+                "heapSys": {
+                    "description": "Memory obtained from system for heap (bytes) // 堆占用的系统内存",
+                    "type": "integer"
+                },
+                "mCacheSys": {
+                    "description": "Memory obtained from system for mcache (bytes) // mcache 占用的系统内存",
+                    "type": "integer"
+                },
+                "mSpanSys": {
+                    "description": "Memory obtained from system for mspan (bytes) // mspan 占用的系统内存",
+                    "type": "integer"
+                },
+                "memAlloc": {
+                    "description": "Allocated memory (bytes) // 已分配内存（字节）",
+                    "type": "integer"
+                },
+                "memSys": {
+                    "description": "Memory obtained from system (bytes) // 从系统获取的内存（字节）",
+                    "type": "integer"
+                },
+                "memTotal": {
+                    "description": "Total memory allocated (bytes) // 累计分配内存（字节）",
+                    "type": "integer"
+                },
+                "nextGc": {
+                    "description": "Target heap size for the next GC cycle // 下次 GC 的目标堆大小",
+                    "type": "integer"
+                },
+                "numGc": {
+                    "description": "Number of completed GC cycles // GC 次数",
+                    "type": "integer"
+                },
+                "numGoroutine": {
+                    "description": "Number of goroutines // Goroutine 数量",
+                    "type": "integer"
+                },
+                "otherSys": {
+                    "description": "Other system memory (bytes) // 其他系统内存",
+                    "type": "integer"
+                },
+                "stackSys": {
+                    "description": "Memory obtained from system for stack (bytes) // 栈占用的系统内存",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AdminStatsDTO": {
+            "type": "object",
+            "properties": {
+                "backups": {
+                    "description": "Backup results per time window // 各时间窗口的备份结果",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AdminBackupStatsDTO"
+                    }
+                },
+                "generatedAt": {
+                    "description": "Generation time // 生成时间",
+                    "type": "string"
+                },
+                "syncEventsPerHour": {
+                    "description": "Sync events per hour, oldest first // 每小时同步事件数，按时间正序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AdminStatsHourDTO"
+                    }
+                },
+                "totals": {
+                    "description": "Instance totals // 实例合计",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.AdminStatsTotalsDTO"
+                        }
+                    ]
+                },
+                "users": {
+                    "description": "Per user usage, largest storage first // 每个用户的用量，按存储大小倒序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AdminUserStatsDTO"
+                    }
+                }
+            }
+        },
+        "dto.AdminStatsHourDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Events within the hour // 该小时内的事件数",
+                    "type": "integer"
+                },
+                "hour": {
+                    "description": "Start of the hour in milliseconds // 小时起始时间（毫秒）",
+                    "type": "integer",
+                    "example": 1700000000000
+                }
+            }
+        },
+        "dto.AdminStatsTotalsDTO": {
+            "type": "object",
+            "properties": {
+                "connections": {
+                    "description": "Active WebSocket connections // 活跃 WebSocket 连接数",
+                    "type": "integer"
+                },
+                "fileCount": {
+                    "description": "Attachments // 附件数",
+                    "type": "integer"
+                },
+                "fileSize": {
+                    "description": "Attachment bytes // 附件字节数",
+                    "type": "integer"
+                },
+                "noteCount": {
+                    "description": "Notes // 笔记数",
+                    "type": "integer"
+                },
+                "noteSize": {
+                    "description": "Note bytes // 笔记字节数",
+                    "type": "integer"
+                },
+                "syncEvents": {
+                    "description": "Sync events within the window // 时间范围内的同步事件数",
+                    "type": "integer"
+                },
+                "users": {
+                    "description": "Active users // 活跃用户数",
+                    "type": "integer"
+                },
+                "vaults": {
+                    "description": "Vaults // 仓库数",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AdminSystemInfo": {
+            "type": "object",
+            "properties": {
+                "cpu": {
+                    "description": "CPU information // CPU 信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.AdminCPUInfo"
+                        }
+                    ]
+                },
+                "host": {
+                    "description": "Host information // 主机信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.AdminHostInfo"
+                        }
+                    ]
+                },
+                "memory": {
+                    "description": "Memory information // 内存信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.AdminMemoryInfo"
+                        }
+                    ]
+                },
+                "process": {
+                    "description": "Process information // 进程信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.AdminProcessInfo"
+                        }
+                    ]
+                },
+                "runtimeStatus": {
+                    "description": "Go runtime status // Go 运行时状态",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.AdminRuntimeInfo"
+                        }
+                    ]
+                },
+                "startTime": {
+                    "description": "Start time // 启动时间",
+                    "type": "string"
+                },
+                "uptime": {
+                    "description": "Uptime (seconds) // 运行时间（秒）",
+                    "type": "number"
+                }
+            }
+        },
+        "dto.AdminUserDatabaseConfig": {
+            "type": "object",
+            "properties": {
+                "charset": {
+                    "description": "Charset // 字符集",
+                    "type": "string"
+                },
+                "connMaxIdleTime": {
+                    "description": "Connection max idle time // 空闲连接最大生命周期",
+                    "type": "string"
+                },
+                "connMaxLifetime": {
+                    "description": "Connection max lifetime // 连接最大生命周期",
+                    "type": "string"
+                },
+                "host": {
+                    "description": "Host // 主机",
+                    "type": "string"
+                },
+                "maxIdleConns": {
+                    "description": "Max idle connections // 最大闲置连接数",
+                    "type": "integer"
+                },
+                "maxOpenConns": {
+                    "description": "Max open connections // 最大打开连接数",
+                    "type": "integer"
+                },
+                "maxWriteConcurrency": {
+                    "description": "Max write concurrency // 最大并发写入数",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Database name // 数据库名",
+                    "type": "string"
+                },
+                "parseTime": {
+                    "description": "Parse time // 是否解析时间",
+                    "type": "boolean"
+                },
+                "password": {
+                    "description": "Password // 密码",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "SQLite database file path // SQLite 数据库文件路径",
+                    "type": "string"
+                },
+                "port": {
+                    "description": "Port // 端口",
+                    "type": "integer"
+                },
+                "schema": {
+                    "description": "Database schema (postgres only) // 数据库 Schema",
+                    "type": "string"
+                },
+                "sslMode": {
+                    "description": "SSL mode (postgres only) // SSL 模式",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Database type (mysql, postgres, sqlite) // 数据库类型",
+                    "type": "string",
+                    "enum": [
+                        "mysql",
+                        "postgres",
+                        "sqlite"
+                    ]
+                },
+                "userName": {
+                    "description": "Username // 用户名",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AdminUserStatsDTO": {
+            "type": "object",
+            "properties": {
+                "connections": {
+                    "description": "Active WebSocket connections // 活跃 WebSocket 连接数",
+                    "type": "integer"
+                },
+                "fileCount": {
+                    "description": "Attachments // 附件数",
+                    "type": "integer"
+                },
+                "fileSize": {
+                    "description": "Attachment bytes // 附件字节数",
+                    "type": "integer"
+                },
+                "noteCount": {
+                    "description": "Notes // 笔记数",
+                    "type": "integer"
+                },
+                "noteSize": {
+                    "description": "Note bytes // 笔记字节数",
+                    "type": "integer"
+                },
+                "syncEvents": {
+                    "description": "Sync events within the window // 时间范围内的同步事件数",
+                    "type": "integer"
+                },
+                "uid": {
+                    "description": "User ID // 用户 ID",
+                    "type": "integer"
+                },
+                "username": {
+                    "description": "Username // 用户名",
+                    "type": "string"
+                },
+                "vaults": {
+                    "description": "Vaults // 仓库数",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.AdminWebGUIConfig": {
+            "type": "object",
+            "properties": {
+                "branding": {
+                    "description": "Instance branding // 实例品牌设置",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.AdminBrandingConfig"
+                        }
+                    ]
+                },
+                "captchaProvider": {
+                    "description": "Captcha provider of the login, empty when disabled // 登录验证码服务，未启用时为空",
+                    "type": "string"
+                },
+                "captchaSiteKey": {
+                    "description": "Public site key of the captcha widget // 验证码组件的公开站点密钥",
+                    "type": "string"
+                },
+                "fontSet": {
+                    "description": "Font set // 字体设置",
+                    "type": "string"
+                },
+                "ftsBleveEnabled": {
+                    "description": "Whether Bleve FTS is enabled // 是否启用 Bleve 全文搜索",
+                    "type": "boolean"
+                },
+                "registerIsEnable": {
+                    "description": "Registration enablement // 是否开启注册",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.AlertChannelDTO": {
+            "type": "object",
+            "properties": {
+                "chatId": {
+                    "description": "telegram: chat ID // telegram：会话 ID",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "Created at // 创建时间",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Channel ID // 渠道 ID",
+                    "type": "integer"
+                },
+                "isEnabled": {
+                    "description": "Whether alerts are sent // 是否发送告警",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "Display name // 显示名称",
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "gotify / ntfy priority // gotify / ntfy 优先级",
+                    "type": "integer"
+                },
+                "to": {
+                    "description": "email: comma separated recipients // email：逗号分隔的收件人",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Bot, application or access token // 机器人、应用或访问令牌",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "email, telegram, gotify or ntfy // email、telegram、gotify 或 ntfy",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "Updated at // 更新时间",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Server, topic or Bot API URL // 服务器、主题或 Bot API 地址",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AlertChannelIDRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "Channel ID // 渠道 ID",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.AlertChannelRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "chatId": {
+                    "description": "telegram: chat ID // telegram：会话 ID",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": ""
+                },
+                "id": {
+                    "description": "ID, 0 creates a channel // ID，为 0 时新建",
+                    "type": "integer",
+                    "example": 1
+                },
+                "isEnabled": {
+                    "description": "Whether alerts are sent // 是否发送告警",
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "description": "Display name // 显示名称",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "Phone"
+                },
+                "priority": {
+                    "description": "gotify (0-10) / ntfy (1-5) priority, 0 uses the server default // gotify（0-10）/ ntfy（1-5）优先级，0 使用服务器默认值",
+                    "type": "integer",
+                    "maximum": 10,
+                    "minimum": 0,
+                    "example": 0
+                },
+                "to": {
+                    "description": "email: comma separated recipients // email：逗号分隔的收件人",
+                    "type": "string",
+                    "maxLength": 1024,
+                    "example": "me@example.com"
+                },
+                "token": {
+                    "description": "telegram: bot token; gotify: application token; ntfy: optional access token // telegram：机器人令牌；gotify：应用令牌；ntfy：可选访问令牌",
+                    "type": "string",
+                    "maxLength": 512,
+                    "example": ""
+                },
+                "type": {
+                    "description": "email, telegram, gotify or ntfy // email、telegram、gotify 或 ntfy",
+                    "type": "string",
+                    "enum": [
+                        "email",
+                        "telegram",
+                        "gotify",
+                        "ntfy"
+                    ],
+                    "example": "ntfy"
+                },
+                "url": {
+                    "description": "gotify: server URL; ntfy: topic URL; telegram: optional Bot API root // gotify：服务器地址；ntfy：主题地址；telegram：可选的 Bot API 根地址",
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://ntfy.sh/my-fns-alerts"
+                }
+            }
+        },
+        "dto.AuditLogDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action // 操作",
+                    "type": "string"
+                },
+                "clientName": {
+                    "description": "Client name // 客户端名称",
+                    "type": "string"
+                },
+                "clientType": {
+                    "description": "Client type // 客户端类型",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "Time of the action // 操作时间",
+                    "type": "string"
+                },
+                "detail": {
+                    "description": "Additional details // 附加信息",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Entry ID // 记录 ID",
+                    "type": "integer"
+                },
+                "ip": {
+                    "description": "Request IP // 请求 IP",
+                    "type": "string"
+                },
+                "target": {
+                    "description": "Object acted on // 操作对象",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "Acting user ID, 0 when unknown // 操作用户 ID，未知时为 0",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.BackupConfigDTO": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Created at // 创建时间",
+                    "type": "string"
+                },
+                "cronExpression": {
+                    "description": "Cron expression // Cron表达式",
+                    "type": "string"
+                },
+                "cronStrategy": {
+                    "description": "Cron strategy // 定时策略",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Config ID // 配置ID",
+                    "type": "integer"
+                },
+                "includeVaultName": {
+                    "description": "Whether sync path includes vault name // 同步路径是否包含仓库名",
+                    "type": "boolean"
+                },
+                "isEnabled": {
+                    "description": "Is enabled // 是否启用",
+                    "type": "boolean"
+                },
+                "lastMessage": {
+                    "description": "Last run result message // 上次运行结果消息",
+                    "type": "string"
+                },
+                "lastRunTime": {
+                    "description": "Last run time // 上次运行时间",
+                    "type": "string"
+                },
+                "lastStatus": {
+                    "description": "Last status (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped) // 上次状态 (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped)",
+                    "type": "integer"
+                },
+                "nextRunTime": {
+                    "description": "Next run time // 下次运行时间",
+                    "type": "string"
+                },
+                "passwordMode": {
+                    "description": "Password mode (0:None, 1:Fixed, 2:Random) // 密码模式 (0:无密码, 1:固定密码, 2:随机密码)",
+                    "type": "integer"
+                },
+                "passwordValue": {
+                    "description": "Password value for fixed mode // 固定密码值",
+                    "type": "string"
+                },
+                "pingUrl": {
+                    "description": "Dead-man switch ping URL // 健康检查 Ping 地址",
+                    "type": "string"
+                },
+                "retentionDays": {
+                    "description": "Retention days // 保留天数",
+                    "type": "integer"
+                },
+                "storageIds": {
+                    "description": "Storage ID list // 存储ID列表",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Backup type (full, incremental, sync, dedupe) // 备份类型 (full, incremental, sync, dedupe)",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "User UID // 用户ID",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "description": "Updated at // 更新时间",
+                    "type": "string"
+                },
+                "vault": {
+                    "description": "Associated vault name // 关联库名称",
+                    "type": "string"
+                },
+                "webhookSecret": {
+                    "description": "Backup report signing secret // 备份报告签名密钥",
+                    "type": "string"
+                },
+                "webhookUrl": {
+                    "description": "Backup report webhook URL // 备份报告 Webhook 地址",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BackupConfigRequest": {
+            "type": "object",
+            "required": [
+                "cronStrategy",
+                "storageIds",
+                "type"
+            ],
+            "properties": {
+                "cronExpression": {
+                    "description": "Cron expression // Cron 表达式",
+                    "type": "string",
+                    "example": "0 0 * * *"
+                },
+                "cronStrategy": {
+                    "description": "Cron strategy // 定时策略",
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "custom"
+                    ],
+                    "example": "daily"
+                },
+                "id": {
+                    "description": "ID // ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "includeVaultName": {
+                    "description": "Include vault name // 同步路径是否包含仓库名",
+                    "type": "boolean",
+                    "example": false
+                },
+                "isEnabled": {
+                    "description": "Is enabled // 是否启用",
+                    "type": "boolean",
+                    "example": true
+                },
+                "passwordMode": {
+                    "description": "Password mode (0:None, 1:Fixed, 2:Random) // 密码模式 (0:无密码, 1:固定密码, 2:随机密码)",
+                    "type": "integer",
+                    "example": 0
+                },
+                "passwordValue": {
+                    "description": "Password value for fixed mode // 固定密码值",
+                    "type": "string",
+                    "example": "123456"
+                },
+                "pingUrl": {
+                    "description": "Dead-man switch URL pinged on start/success/failure // 在开始/成功/失败时请求的健康检查地址",
+                    "type": "string",
+                    "example": "https://hc-ping.com/uuid"
+                },
+                "retentionDays": {
+                    "description": "Retention days // 保留天数",
+                    "type": "integer",
+                    "minimum": -1,
+                    "example": 7
+                },
+                "storageIds": {
+                    "description": "Storage IDs // 存储 ID 列表",
+                    "type": "string",
+                    "example": "[1, 2]"
+                },
+                "type": {
+                    "description": "Backup type; dedupe uploads only new content-addressed blobs plus a manifest and ignores the password mode // 备份类型；dedupe 仅上传新的内容寻址数据块与清单，不使用密码模式",
+                    "type": "string",
+                    "enum": [
+                        "full",
+                        "incremental",
+                        "sync",
+                        "dedupe"
+                    ],
+                    "example": "sync"
+                },
+                "vault": {
+                    "description": "Vault name // 仓库名称",
+                    "type": "string",
+                    "example": "test"
+                },
+                "webhookSecret": {
+                    "description": "HMAC-SHA256 key for the report signature // 报告签名使用的 HMAC-SHA256 密钥",
+                    "type": "string",
+                    "example": "s3cret"
+                },
+                "webhookUrl": {
+                    "description": "URL receiving a signed JSON report of each run // 接收每次运行签名 JSON 报告的地址",
+                    "type": "string",
+                    "example": "https://hc-ping.com/uuid"
+                }
+            }
+        },
+        "dto.BackupExecuteRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID // ID",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.BackupHistoryDTO": {
+            "type": "object",
+            "properties": {
+                "archiveSize": {
+                    "description": "Uploaded object size in bytes // 上传对象大小（字节）",
+                    "type": "integer"
+                },
+                "checksum": {
+                    "description": "Uploaded object SHA-256 // 上传对象 SHA-256",
+                    "type": "string"
+                },
+                "configId": {
+                    "description": "Config ID // 配置ID",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "description": "Created at // 创建时间",
+                    "type": "string"
+                },
+                "endTime": {
+                    "description": "End time // 结束时间",
+                    "type": "string"
+                },
+                "fileCount": {
+                    "description": "File count // 文件数量",
+                    "type": "integer"
+                },
+                "filePath": {
+                    "description": "File path // 文件路径",
+                    "type": "string"
+                },
+                "fileSize": {
+                    "description": "File size // 文件大小",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "History record ID // 历史记录ID",
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "Result message // 结果消息",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Password // 密码",
+                    "type": "string"
+                },
+                "startTime": {
+                    "description": "Start time // 开始时间",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped) // 状态 (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped)",
+                    "type": "integer"
+                },
+                "storageId": {
+                    "description": "Storage ID // 存储ID",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Backup type // 备份类型",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "User UID // 用户ID",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "description": "Updated at // 更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BackupVerifyDTO": {
+            "type": "object",
+            "properties": {
+                "checksum": {
+                    "description": "SHA-256 of the downloaded object // 下载对象的 SHA-256",
+                    "type": "string"
+                },
+                "expectedChecksum": {
+                    "description": "Recorded SHA-256 // 记录的 SHA-256",
+                    "type": "string"
+                },
+                "expectedSize": {
+                    "description": "Recorded size, 0 for records made before checksums // 记录的大小，校验和功能之前的记录为 0",
+                    "type": "integer"
+                },
+                "extracted": {
+                    "description": "Whether the archive was test-extracted // 是否试解压了归档",
+                    "type": "boolean"
+                },
+                "fileCount": {
+                    "description": "Files read back while extracting // 试解压时读回的文件数",
+                    "type": "integer"
+                },
+                "filePath": {
+                    "description": "Remote path // 远端路径",
+                    "type": "string"
+                },
+                "historyId": {
+                    "description": "History record ID // 历史记录 ID",
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "Result or failure reason // 结果或失败原因",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Downloaded size in bytes // 下载的字节数",
+                    "type": "integer"
+                },
+                "storageId": {
+                    "description": "Storage ID // 存储 ID",
+                    "type": "integer"
+                },
+                "storageType": {
+                    "description": "Storage type // 存储类型",
+                    "type": "string"
+                },
+                "verified": {
+                    "description": "Whether the copy is intact // 副本是否完好",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.BackupVerifyRequest": {
+            "type": "object",
+            "required": [
+                "historyId"
+            ],
+            "properties": {
+                "extract": {
+                    "description": "Also test-extract the archive // 同时试解压归档",
+                    "type": "boolean",
+                    "example": true
+                },
+                "historyId": {
+                    "description": "History record ID // 历史记录 ID",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.ChangelogDTO": {
+            "type": "object",
+            "properties": {
+                "currentVersion": {
+                    "description": "Running version // 当前运行版本",
+                    "type": "string",
+                    "example": "2.0.10"
+                },
+                "latestVersion": {
+                    "description": "Latest known release, empty before the first release check // 已知最新版本，首次检查版本前为空",
+                    "type": "string",
+                    "example": "2.1.0"
+                },
+                "releases": {
+                    "description": "Newest first // 最新版本在前",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ChangelogReleaseDTO"
+                    }
+                },
+                "upgradeAvailable": {
+                    "description": "Whether a newer release exists // 是否存在更新版本",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ChangelogReleaseDTO": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Raw Markdown release notes // 原始 Markdown 发布说明",
+                    "type": "string"
+                },
+                "isCurrent": {
+                    "description": "Whether this is the running version // 是否为当前运行版本",
+                    "type": "boolean"
+                },
+                "sections": {
+                    "description": "Release notes grouped by heading // 按标题分组的发布说明",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/util.ChangelogSection"
+                    }
+                },
+                "version": {
+                    "description": "Version name // 版本号",
+                    "type": "string",
+                    "example": "2.1.0"
+                }
+            }
+        },
+        "dto.DataInventoryAccountDTO": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Account created time // 账号创建时间",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "Email address // 邮件地址",
+                    "type": "string"
+                },
+                "externalIdentities": {
+                    "description": "Linked OIDC identities // 关联的 OIDC 身份",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DataInventoryIdentityDTO"
+                    }
+                },
+                "hasAvatar": {
+                    "description": "Avatar stored // 是否存储了头像",
+                    "type": "boolean"
+                },
+                "twoFactorEnabled": {
+                    "description": "TOTP secret stored // 是否存储了两步验证密钥",
+                    "type": "boolean"
+                },
+                "updatedAt": {
+                    "description": "Last updated time // 最后更新时间",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Username // 用户名",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DataInventoryBackupDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Backup config ID // 备份配置 ID",
+                    "type": "integer"
+                },
+                "isEnabled": {
+                    "description": "Is enabled // 是否启用",
+                    "type": "boolean"
+                },
+                "lastRunTime": {
+                    "description": "Last run time // 上次运行时间",
+                    "type": "string"
+                },
+                "runs": {
+                    "description": "Recorded backup runs // 已记录的备份次数",
+                    "type": "integer"
+                },
+                "storageIds": {
+                    "description": "Storage targets, see storages // 存储目标，见 storages",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "type": {
+                    "description": "full, incremental, sync, dedupe",
+                    "type": "string"
+                },
+                "vault": {
+                    "description": "Vault name, empty for all vaults // 仓库名称，为空表示所有仓库",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DataInventoryDTO": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "description": "Account data // 账号数据",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.DataInventoryAccountDTO"
+                        }
+                    ]
+                },
+                "authTokens": {
+                    "description": "API tokens and their access logs // API Token 及其访问日志",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.DataInventoryTokenDTO"
+                        }
+                    ]
+                },
+                "backups": {
+                    "description": "Backup configs and where their copies are held // 备份配置及副本存放位置",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DataInventoryBackupDTO"
+                    }
+                },
+                "generatedAt": {
+                    "description": "Report time // 生成时间",
+                    "type": "string"
+                },
+                "gitSyncs": {
+                    "description": "Git remotes receiving vault content // 接收仓库内容的 Git 远端",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DataInventoryGitSyncDTO"
+                    }
+                },
+                "shares": {
+                    "description": "Share links // 分享链接数",
+                    "type": "integer"
+                },
+                "storages": {
+                    "description": "Configured storage targets // 已配置的存储目标",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DataInventoryStorageDTO"
+                    }
+                },
+                "syncLogs": {
+                    "description": "Sync log (audit) entries // 同步日志（审计）条目数",
+                    "type": "integer"
+                },
+                "totals": {
+                    "description": "Sum over all vaults, ID and name empty // 所有仓库的合计，ID 与名称为空",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.DataInventoryVaultDTO"
+                        }
+                    ]
+                },
+                "uid": {
+                    "description": "User ID // 用户 ID",
+                    "type": "integer"
+                },
+                "vaults": {
+                    "description": "Content per vault // 各仓库内容",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DataInventoryVaultDTO"
+                    }
+                }
+            }
+        },
+        "dto.DataInventoryGitSyncDTO": {
+            "type": "object",
+            "properties": {
+                "branch": {
+                    "description": "Branch // 分支",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Git sync config ID // Git 同步配置 ID",
+                    "type": "integer"
+                },
+                "isEnabled": {
+                    "description": "Is enabled // 是否启用",
+                    "type": "boolean"
+                },
+                "repoUrl": {
+                    "description": "Remote URL without credentials // 不含凭据的远端地址",
+                    "type": "string"
+                },
+                "vault": {
+                    "description": "Vault name // 仓库名称",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DataInventoryIdentityDTO": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Linked time // 关联时间",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "Email reported by the provider // 身份提供方返回的邮件",
+                    "type": "string"
+                },
+                "issuer": {
+                    "description": "OIDC issuer // OIDC 签发方",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Username reported by the provider // 身份提供方返回的用户名",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DataInventoryStorageDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Storage ID // 存储 ID",
+                    "type": "integer"
+                },
+                "isEnabled": {
+                    "description": "Is enabled // 是否启用",
+                    "type": "boolean"
+                },
+                "location": {
+                    "description": "Endpoint, bucket and path, without credentials // 端点、存储桶与路径，不含凭据",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Storage type // 存储类型",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DataInventoryTokenDTO": {
+            "type": "object",
+            "properties": {
+                "accessLogs": {
+                    "description": "Access log entries (IP, user agent) // 访问日志条目数（含 IP、用户代理）",
+                    "type": "integer"
+                },
+                "active": {
+                    "description": "Active tokens // 有效 Token 数",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Tokens including revoked ones // Token 总数（含已注销）",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.DataInventoryVaultDTO": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "description": "Attachments // 附件数",
+                    "type": "integer"
+                },
+                "attachmentsSize": {
+                    "description": "Attachment size in bytes // 附件大小（字节）",
+                    "type": "integer"
+                },
+                "deletedAttachments": {
+                    "description": "Attachments in the recycle bin // 回收站中的附件数",
+                    "type": "integer"
+                },
+                "deletedNotes": {
+                    "description": "Notes in the recycle bin // 回收站中的笔记数",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Vault ID // 仓库 ID",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Vault name // 仓库名称",
+                    "type": "string"
+                },
+                "noteHistory": {
+                    "description": "Note history versions // 笔记历史版本数",
+                    "type": "integer"
+                },
+                "notes": {
+                    "description": "Notes // 笔记数",
+                    "type": "integer"
+                },
+                "notesSize": {
+                    "description": "Note content size in bytes // 笔记内容大小（字节）",
+                    "type": "integer"
+                },
+                "settings": {
+                    "description": "Synced client settings files // 同步的客户端配置文件数",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.DatabaseExportDTO": {
+            "type": "object",
+            "properties": {
+                "databases": {
+                    "description": "Exported database files // 导出的数据库文件",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DatabaseExportFileDTO"
+                    }
+                },
+                "exportedAt": {
+                    "description": "Time of the last export run // 最近一次导出时间",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Export directory // 导出目录",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Total size in bytes // 总大小（字节）",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.DatabaseExportFileDTO": {
+            "type": "object",
+            "properties": {
+                "file": {
+                    "description": "Path relative to the export directory // 相对导出目录的路径",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Size in bytes // 大小（字节）",
+                    "type": "integer"
+                },
+                "uid": {
+                    "description": "Owner user ID, 0 for the main database // 所属用户 ID，主库为 0",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.DeviceDTO": {
+            "type": "object",
+            "properties": {
+                "clientName": {
+                    "description": "Client name // 客户端名称",
+                    "type": "string"
+                },
+                "clientType": {
+                    "description": "Client type // 客户端类型",
+                    "type": "string"
+                },
+                "clientVersion": {
+                    "description": "Client version // 客户端版本",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "First seen at // 首次出现时间",
+                    "type": "string"
+                },
+                "deviceKey": {
+                    "description": "Stable device identifier // 稳定的设备标识",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Device ID // 设备 ID",
+                    "type": "integer"
+                },
+                "ip": {
+                    "description": "Last client IP // 最近一次的客户端 IP",
+                    "type": "string"
+                },
+                "isOnline": {
+                    "description": "Whether the device is connected now // 设备当前是否在线",
+                    "type": "boolean"
+                },
+                "lastSeenAt": {
+                    "description": "Last seen at // 最近出现时间",
+                    "type": "string"
+                },
+                "platform": {
+                    "description": "Platform flags reported as true // 上报为 true 的平台标记",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tokenId": {
+                    "description": "Token the device authenticated with // 设备鉴权使用的令牌 ID",
+                    "type": "integer"
+                },
+                "userAgent": {
+                    "description": "Last User-Agent // 最近一次的 User-Agent",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeviceSyncStatusDTO": {
+            "type": "object",
+            "properties": {
+                "clientName": {
+                    "description": "Client name // 客户端名称",
+                    "type": "string"
+                },
+                "clientType": {
+                    "description": "Client type // 客户端类型",
+                    "type": "string"
+                },
+                "clientVersion": {
+                    "description": "Client version // 客户端版本",
+                    "type": "string"
+                },
+                "deviceKey": {
+                    "description": "Stable device identifier // 稳定的设备标识",
+                    "type": "string"
+                },
+                "isOnline": {
+                    "description": "Whether the device is connected now // 设备当前是否在线",
+                    "type": "boolean"
+                },
+                "lastSeenAt": {
+                    "description": "Last seen at // 最近出现时间",
+                    "type": "string"
+                },
+                "lastSyncAt": {
+                    "description": "Last successful sync of the client in any vault // 该客户端在任意保险库中最近一次成功同步时间",
+                    "type": "string"
+                },
+                "pendingChanges": {
+                    "description": "Broadcast changes the device has not received yet // 设备尚未收到的广播变更数",
+                    "type": "integer"
+                },
+                "upToDate": {
+                    "description": "No pending changes and the last sync did not fail // 没有待同步变更且最近一次同步未失败",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.ErrorCatalogDTO": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "description": "Sorted by code // 按错误码排序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ErrorCodeDTO"
+                    }
+                },
+                "language": {
+                    "description": "Language of messages and hints // 消息与处理建议的语言",
+                    "type": "string",
+                    "example": "en"
+                }
+            }
+        },
+        "dto.ErrorCodeDTO": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "description": "Category of the code range // 错误码区间所属分类",
+                    "type": "string",
+                    "example": "note"
+                },
+                "code": {
+                    "description": "Numeric code of the response // 响应中的数字错误码",
+                    "type": "integer",
+                    "example": 430
+                },
+                "hint": {
+                    "description": "Remediation hint, empty for success codes // 处理建议，成功码为空",
+                    "type": "string",
+                    "example": "Sync again to refresh"
+                },
+                "httpStatus": {
+                    "description": "HTTP status of the response carrying the code // 携带该错误码的响应的 HTTP 状态码",
+                    "type": "integer",
+                    "example": 200
+                },
+                "message": {
+                    "description": "Message template, the response may append details // 消息模板，响应可能附加 details",
+                    "type": "string",
+                    "example": "Note does not exist"
+                },
+                "name": {
+                    "description": "Stable machine-readable name // 稳定的机器可读名称",
+                    "type": "string",
+                    "example": "ErrorNoteNotFound"
+                },
+                "status": {
+                    "description": "true for success codes // 成功码为 true",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.FileDTO": {
+            "type": "object",
+            "properties": {
+                "contentHash": {
+                    "description": "Content hash // 内容哈希",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "Created at time // 创建时间",
+                    "type": "string"
+                },
+                "ctime": {
+                    "description": "Creation timestamp // 创建时间戳",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "File ID // 文件 ID",
+                    "type": "integer"
+                },
+                "lastTime": {
+                    "description": "Updated timestamp // 更新时间戳",
+                    "type": "integer"
+                },
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "File path // 文件路径",
+                    "type": "string"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string"
+                },
+                "rename": {
+                    "description": "Rename flag // 重命名标记",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "File size // 文件大小",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "description": "Updated at time // 更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FileGCOrphanDTO": {
+            "type": "object",
+            "properties": {
+                "expired": {
+                    "description": "Older than the grace period // 已超过宽限期",
+                    "type": "boolean"
+                },
+                "fileId": {
+                    "description": "File ID taken from the folder name // 目录名中的文件 ID",
+                    "type": "integer"
+                },
+                "modTime": {
+                    "description": "Latest modification time // 最近修改时间",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Folder path // 目录路径",
+                    "type": "string"
+                },
+                "removed": {
+                    "description": "Removed by this run // 已由本次运行删除",
+                    "type": "boolean"
+                },
+                "size": {
+                    "description": "Total size in bytes // 总大小（字节）",
+                    "type": "integer"
+                },
+                "uid": {
+                    "description": "Owner // 所属用户",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.FileGCReportDTO": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "description": "Only reported, nothing removed // 仅报告，未删除",
+                    "type": "boolean"
+                },
+                "gracePeriod": {
+                    "description": "Configured grace period, 0 never removes // 配置的宽限期，0 表示从不删除",
+                    "type": "string"
+                },
+                "items": {
+                    "description": "Orphaned folders // 孤立目录列表",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FileGCOrphanDTO"
+                    }
+                },
+                "orphanSize": {
+                    "description": "Total size of the orphans in bytes // 孤立目录总大小（字节）",
+                    "type": "integer"
+                },
+                "orphans": {
+                    "description": "Orphaned folders found // 发现的孤立目录数",
+                    "type": "integer"
+                },
+                "removed": {
+                    "description": "Folders removed // 已删除的目录数",
+                    "type": "integer"
+                },
+                "removedSize": {
+                    "description": "Bytes freed // 释放的字节数",
+                    "type": "integer"
+                },
+                "users": {
+                    "description": "Users checked // 检查的用户数",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.FileRecycleClearRequest": {
+            "type": "object",
+            "required": [
+                "vault"
+            ],
+            "properties": {
+                "path": {
+                    "description": "File path, empty for all // 文件路径，为空则清理全部",
+                    "type": "string",
+                    "example": "path/to/file.png"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "fhash123"
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.FileRenameRequest": {
+            "type": "object",
+            "required": [
+                "oldPath",
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "context": {
+                    "description": "Context // 同步上下文",
+                    "type": "string",
+                    "example": "ctx123"
+                },
+                "oldPath": {
+                    "description": "Old path // 旧路径",
+                    "type": "string",
+                    "example": "OldImage.png"
+                },
+                "oldPathHash": {
+                    "description": "Old path hash // 旧路径哈希",
+                    "type": "string",
+                    "example": "ofhash456"
+                },
+                "path": {
+                    "description": "New path // 新路径",
+                    "type": "string",
+                    "example": "NewImage.png"
+                },
+                "pathHash": {
+                    "description": "New path hash // 新路径哈希",
+                    "type": "string",
+                    "example": "nfhash123"
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.FileRestoreRequest": {
+            "type": "object",
+            "required": [
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "path": {
+                    "description": "File path // 文件路径",
+                    "type": "string",
+                    "example": "Image.png"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "fhash123"
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.FileSyncRenameMessage": {
+            "type": "object",
+            "required": [
+                "path"
+            ],
+            "properties": {
+                "contentHash": {
+                    "description": "Content hash // 内容哈希",
+                    "type": "string",
+                    "example": "chash456"
+                },
+                "ctime": {
+                    "description": "Creation timestamp // 创建时间戳",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "lastTime": {
+                    "description": "Record update timestamp // 记录更新时间戳",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "oldPath": {
+                    "description": "Old path // 旧路径",
+                    "type": "string",
+                    "example": "OldImage.png"
+                },
+                "oldPathHash": {
+                    "description": "Old path hash // 旧路径哈希",
+                    "type": "string",
+                    "example": "ofhash456"
+                },
+                "path": {
+                    "description": "New path // 新路径",
+                    "type": "string",
+                    "example": "NewImage.png"
+                },
+                "pathHash": {
+                    "description": "New path hash // 新路径哈希",
+                    "type": "string",
+                    "example": "nfhash123"
+                },
+                "size": {
+                    "description": "File size // 文件大小",
+                    "type": "integer",
+                    "example": 1024
+                }
+            }
+        },
+        "dto.FileUploadInitRequest": {
+            "type": "object",
+            "required": [
+                "contentHash",
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "contentHash": {
+                    "description": "Content hash // 内容哈希",
+                    "type": "string",
+                    "example": "chash456"
+                },
+                "ctime": {
+                    "description": "Creation timestamp // 创建时间戳",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "path": {
+                    "description": "File path // 文件路径",
+                    "type": "string",
+                    "example": "Video.mp4"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "fhash123"
+                },
+                "size": {
+                    "description": "File size in bytes // 文件大小（字节）",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 104857600
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.FileUploadSessionDTO": {
+            "type": "object",
+            "properties": {
+                "chunkSize": {
+                    "description": "Size of every chunk but the last // 除最后一块外每块的大小",
+                    "type": "integer"
+                },
+                "expiresAt": {
+                    "description": "Unix milliseconds the idle session expires at // 空闲会话的过期时间（Unix 毫秒）",
+                    "type": "integer"
+                },
+                "file": {
+                    "description": "Stored file when no upload is needed // 无需上传时服务端已存储的文件",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.FileDTO"
+                        }
+                    ]
+                },
+                "needUpload": {
+                    "description": "Whether content has to be uploaded // 是否需要上传内容",
+                    "type": "boolean"
+                },
+                "path": {
+                    "description": "File path // 文件路径",
+                    "type": "string"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string"
+                },
+                "sessionId": {
+                    "description": "Upload session ID // 上传会话 ID",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "File size in bytes // 文件大小（字节）",
+                    "type": "integer"
+                },
+                "totalChunks": {
+                    "description": "Number of chunks // 分块总数",
+                    "type": "integer"
+                },
+                "uploadedBytes": {
+                    "description": "Bytes received // 已接收字节数",
+                    "type": "integer"
+                },
+                "uploadedChunks": {
+                    "description": "Indexes of received chunks, ascending // 已接收分块的序号，升序",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dto.FileUploadSessionRequest": {
+            "type": "object",
+            "required": [
+                "sessionId"
+            ],
+            "properties": {
+                "sessionId": {
+                    "description": "Upload session ID // 上传会话 ID",
+                    "type": "string",
+                    "example": "sess_123456"
+                }
+            }
+        },
+        "dto.FolderArchiveRequest": {
+            "type": "object",
+            "required": [
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "archived": {
+                    "description": "Archived (read-only) // 是否归档（只读）",
+                    "type": "boolean",
+                    "example": true
+                },
+                "path": {
+                    "description": "Folder path // 文件夹路径",
+                    "type": "string",
+                    "example": "Archive/2023"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "fhash789"
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.FolderCreateRequest": {
+            "type": "object",
+            "required": [
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "context": {
+                    "description": "Context // 同步上下文",
+                    "type": "string",
+                    "example": "ctx123"
+                },
+                "path": {
+                    "description": "Folder path // 文件夹路径",
+                    "type": "string",
+                    "example": "NewFolder"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "fhash456"
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.FolderDTO": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "description": "Archived (read-only) // 是否归档（只读）",
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "description": "Created at time // 创建时间",
+                    "type": "string"
+                },
+                "ctime": {
+                    "description": "Creation timestamp // 创建时间戳",
+                    "type": "integer"
+                },
+                "lastTime": {
+                    "description": "Record update timestamp // 记录更新时间戳",
+                    "type": "integer"
+                },
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "Folder path // 文件夹路径",
+                    "type": "string"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希值",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "Updated at time // 更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FolderDeleteRequest": {
+            "type": "object",
+            "required": [
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "context": {
+                    "description": "Context // 同步上下文",
+                    "type": "string",
+                    "example": "ctx123"
+                },
+                "path": {
+                    "description": "Folder path // 文件夹路径",
+                    "type": "string",
+                    "example": "OldFolder"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "fhash789"
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.FolderMoveRequest": {
+            "type": "object",
+            "required": [
+                "oldPath",
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "oldPath": {
+                    "description": "Folder to move // 需要移动的文件夹",
+                    "type": "string",
+                    "example": "Projects/Old"
+                },
+                "path": {
+                    "description": "New folder path // 新文件夹路径",
+                    "type": "string",
+                    "example": "Archive/Old"
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.FolderMoveResult": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "description": "Moved files // 已移动的文件",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FileSyncRenameMessage"
+                    }
+                },
+                "folders": {
+                    "description": "Moved folders, parents first // 已移动的文件夹，父文件夹在前",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FolderSyncRenameMessage"
+                    }
+                },
+                "notes": {
+                    "description": "Moved notes // 已移动的笔记",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NoteSyncRenameMessage"
+                    }
+                }
+            }
+        },
+        "dto.FolderReadme": {
+            "type": "object",
+            "properties": {
+                "excerpt": {
+                    "description": "Plain-text excerpt // 纯文本摘要",
+                    "type": "string"
+                },
+                "lastTime": {
+                    "description": "Record update timestamp // 记录更新时间戳",
+                    "type": "integer"
+                },
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "README note path // README 笔记路径",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Note size // 笔记大小",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Frontmatter title or first heading // frontmatter 标题或第一个标题",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FolderSyncRenameMessage": {
+            "type": "object",
+            "required": [
+                "path"
+            ],
+            "properties": {
+                "ctime": {
+                    "description": "Creation timestamp // 创建时间戳",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "lastTime": {
+                    "description": "Record update timestamp // 记录更新时间戳",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "oldPath": {
+                    "description": "Old path // 旧路径",
+                    "type": "string",
+                    "example": "OldFolder"
+                },
+                "oldPathHash": {
+                    "description": "Old path hash // 旧路径哈希",
+                    "type": "string",
+                    "example": "ofhash456"
+                },
+                "path": {
+                    "description": "New path // 新路径",
+                    "type": "string",
+                    "example": "NewFolder"
+                },
+                "pathHash": {
+                    "description": "New path hash // 新路径哈希",
+                    "type": "string",
+                    "example": "nfhash123"
+                }
+            }
+        },
+        "dto.FolderTreeNode": {
+            "type": "object",
+            "properties": {
+                "archived": {
+                    "description": "Archived (read-only) // 是否归档（只读）",
+                    "type": "boolean"
+                },
+                "children": {
+                    "description": "Child nodes // 子节点",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FolderTreeNode"
+                    }
+                },
+                "fileCount": {
+                    "description": "File count // 文件数量",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Node name // 节点名称",
+                    "type": "string"
+                },
+                "noteCount": {
+                    "description": "Note count // 笔记数量",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "Node path // 节点路径",
+                    "type": "string"
+                },
+                "readme": {
+                    "description": "Folder README summary // 文件夹 README 摘要",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.FolderReadme"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.FolderTreeResponse": {
+            "type": "object",
+            "properties": {
+                "folders": {
+                    "description": "Folder tree // 文件夹树",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FolderTreeNode"
+                    }
+                },
+                "rootFileCount": {
+                    "description": "File count in root // 根目录中的文件数量",
+                    "type": "integer"
+                },
+                "rootNoteCount": {
+                    "description": "Note count in root // 根目录中的笔记数量",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GitSyncCleanRequest": {
+            "type": "object",
+            "properties": {
+                "configId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GitSyncConfigDTO": {
+            "type": "object",
+            "properties": {
+                "branch": {
+                    "description": "Branch // 分支",
+                    "type": "string"
+                },
+                "configSyncRules": {
+                    "description": "Config sync rules // 配置同步规则",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "description": "Created at // 创建时间",
+                    "type": "string"
+                },
+                "delay": {
+                    "description": "Delay time (seconds) // 延迟时间（秒）",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Task ID // 任务ID",
+                    "type": "integer"
+                },
+                "includeConfig": {
+                    "description": "Include config sync // 是否开启配置同步",
+                    "type": "boolean"
+                },
+                "isEnabled": {
+                    "description": "Is enabled // 是否启用",
+                    "type": "boolean"
+                },
+                "lastMessage": {
+                    "description": "Last run result message // 上次运行结果消息",
+                    "type": "string"
+                },
+                "lastStatus": {
+                    "description": "Last status (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Shutdown) // 上次状态 (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Shutdown)",
+                    "type": "integer"
+                },
+                "lastSyncTime": {
+                    "description": "Last sync time // 上次同步时间",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Password // 密码",
+                    "type": "string"
+                },
+                "pingUrl": {
+                    "description": "Dead-man switch ping URL // 健康检查 Ping 地址",
+                    "type": "string"
+                },
+                "repoUrl": {
+                    "description": "Repository URL // 仓库地址",
+                    "type": "string"
+                },
+                "retentionDays": {
+                    "description": "History retention days // 历史记录保留天数",
+                    "type": "integer"
+                },
+                "uid": {
+                    "description": "User ID // 用户ID",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "description": "Updated at // 更新时间",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Username // 用户名",
+                    "type": "string"
+                },
+                "vault": {
+                    "description": "Associated vault name // 关联库名称",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GitSyncConfigRequest": {
+            "type": "object",
+            "required": [
+                "repoUrl"
+            ],
+            "properties": {
+                "branch": {
+                    "type": "string"
+                },
+                "configSyncRules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "delay": {
+                    "description": "Delay time (seconds) // 延迟时间（秒）",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "includeConfig": {
+                    "type": "boolean"
+                },
+                "isEnabled": {
+                    "type": "boolean"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "pingUrl": {
+                    "description": "Dead-man switch URL pinged on start/success/failure // 在开始/成功/失败时请求的健康检查地址",
+                    "type": "string"
+                },
+                "repoUrl": {
+                    "type": "string"
+                },
+                "retentionDays": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "vault": {
+                    "description": "Associated vault name // 关联笔记本名称",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GitSyncDeleteRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GitSyncExecuteRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GitSyncHistoryDTO": {
+            "type": "object",
+            "properties": {
+                "configId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "endTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "startTime": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "0:Idle, 1:Running, 2:Success, 3:Failed, 4:Shutdown",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.GitSyncValidateRequest": {
+            "type": "object",
+            "required": [
+                "repoUrl"
+            ],
+            "properties": {
+                "branch": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "repoUrl": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.JobDTO": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Runs so far // 已运行次数",
+                    "type": "integer"
+                },
+                "enqueuedAt": {
+                    "description": "Time the job was queued // 入队时间",
+                    "type": "string"
+                },
+                "finishedAt": {
+                    "description": "End of the last run // 最近一次运行的结束时间",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Job ID // 任务 ID",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Coalescing key within the kind // 类型内的合并键",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "Job kind, e.g. note.links // 任务类型，如 note.links",
+                    "type": "string"
+                },
+                "lastError": {
+                    "description": "Error of the last run // 最近一次运行的错误",
+                    "type": "string"
+                },
+                "nextRunAt": {
+                    "description": "Time of the next retry // 下一次重试时间",
+                    "type": "string"
+                },
+                "startedAt": {
+                    "description": "Start of the last run // 最近一次运行的开始时间",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "queued, running, retrying or failed // queued、running、retrying 或 failed",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "Owner, 0 for server jobs // 所属用户，服务器级任务为 0",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.JobKindMetricsDTO": {
+            "type": "object",
+            "properties": {
+                "coalesced": {
+                    "description": "Jobs merged into a queued job with the same key // 合并到同键排队任务中的任务数",
+                    "type": "integer"
+                },
+                "durationMs": {
+                    "description": "Time spent running (ms) // 运行总耗时（毫秒）",
+                    "type": "integer"
+                },
+                "enqueued": {
+                    "description": "Jobs queued // 入队的任务数",
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "Jobs out of attempts // 用完重试次数的任务数",
+                    "type": "integer"
+                },
+                "inline": {
+                    "description": "Jobs run by the caller because the queue was full // 因队列已满由调用方执行的任务数",
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "Job kind // 任务类型",
+                    "type": "string"
+                },
+                "retried": {
+                    "description": "Failed runs that were retried // 失败后重试的运行次数",
+                    "type": "integer"
+                },
+                "succeeded": {
+                    "description": "Successful runs // 成功的运行次数",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.JobQueueStatusDTO": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "description": "Failed jobs kept for requeueing // 保留供重新排队的失败任务数",
+                    "type": "integer"
+                },
+                "jobs": {
+                    "description": "Jobs that are not done, oldest first // 未完成的任务，按入队时间升序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JobDTO"
+                    }
+                },
+                "kinds": {
+                    "description": "Counters by kind // 按类型的计数",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.JobKindMetricsDTO"
+                    }
+                },
+                "queueSize": {
+                    "description": "Configured queue size // 配置的队列大小",
+                    "type": "integer"
+                },
+                "queued": {
+                    "description": "Jobs waiting for a worker // 等待 worker 的任务数",
+                    "type": "integer"
+                },
+                "retrying": {
+                    "description": "Jobs waiting to be retried // 等待重试的任务数",
+                    "type": "integer"
+                },
+                "running": {
+                    "description": "Jobs being run // 运行中的任务数",
+                    "type": "integer"
+                },
+                "workers": {
+                    "description": "Configured workers // 配置的 worker 数",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.JobRequeueRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "description": "Failed jobs to requeue, empty for every failed job // 需重新排队的失败任务，为空时重新排队全部失败任务",
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "12"
+                    ]
+                }
+            }
+        },
+        "dto.LogEntryDTO": {
+            "type": "object",
+            "properties": {
+                "caller": {
+                    "description": "Source location // 源码位置",
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "Structured fields // 结构化字段",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "level": {
+                    "description": "Log level // 日志级别",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Log message // 日志消息",
+                    "type": "string"
+                },
+                "module": {
+                    "description": "Logger name or first word of the message // 日志器名称或消息的第一个词",
+                    "type": "string"
+                },
+                "seq": {
+                    "description": "Sequence number, increasing // 递增序号",
+                    "type": "integer"
+                },
+                "time": {
+                    "description": "Log time // 日志时间",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "Value of the uid field // uid 字段的值",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MaintenanceJobDTO": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "Job description // 任务描述",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Job ID (kind:key) // 任务 ID (类型:标识)",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Backup config ID, task name or \"server\" // 备份配置 ID、任务名或 \"server\"",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "backup, task or upgrade // backup、task 或 upgrade",
+                    "type": "string"
+                },
+                "queuedAt": {
+                    "description": "First time the job was deferred // 首次被推迟的时间",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "Owner, 0 for server jobs // 所属用户，服务器级任务为 0",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.MaintenanceRunRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "description": "Job IDs, empty runs every queued job // 任务 ID，为空时执行全部排队任务",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.MaintenanceStatusDTO": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Whether a maintenance window is configured // 是否配置了维护窗口",
+                    "type": "boolean"
+                },
+                "inWindow": {
+                    "description": "Whether heavy jobs may run now // 当前是否可以执行重型任务",
+                    "type": "boolean"
+                },
+                "queued": {
+                    "description": "Jobs waiting for the window, oldest first // 等待窗口的任务，按排队时间升序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MaintenanceJobDTO"
+                    }
+                },
+                "windowEnd": {
+                    "description": "End of the open or next window // 当前或下一个窗口的结束时间",
+                    "type": "string"
+                },
+                "windowStart": {
+                    "description": "Start of the open or next window // 当前或下一个窗口的开始时间",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NoteAccessLogDTO": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Share ID, token ID or client name of the reader // 读取者的分享 ID、令牌 ID 或客户端名称",
+                    "type": "string"
+                },
+                "channel": {
+                    "description": "Access channel: share / api / webdav // 访问渠道",
+                    "type": "string"
+                },
+                "clientType": {
+                    "description": "Client type // 客户端类型",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "Access time // 访问时间",
+                    "type": "string"
+                },
+                "ip": {
+                    "description": "Request IP // 请求 IP",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Note path at the time of the read // 读取时的笔记路径",
+                    "type": "string"
+                },
+                "userAgent": {
+                    "description": "User agent // 用户代理",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NoteAccessSettingDTO": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Whether reads are logged // 是否记录读取",
+                    "type": "boolean"
+                },
+                "noteId": {
+                    "description": "Note ID // 笔记 ID",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NoteAccessSettingRequest": {
+            "type": "object",
+            "required": [
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "enabled": {
+                    "description": "Whether reads are logged // 是否记录读取",
+                    "type": "boolean",
+                    "example": true
+                },
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string",
+                    "example": "ReadMe.md"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "hash123"
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.NoteAppendRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "content": {
+                    "description": "Content to append // 追加内容",
+                    "type": "string",
+                    "example": "Appended content"
+                },
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string",
+                    "example": "ReadMe.md"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "hash123"
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.NoteAppendToHeadingRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "heading",
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "content": {
+                    "description": "Content to insert // 插入内容",
+                    "type": "string",
+                    "example": "- [ ] Call back"
+                },
+                "heading": {
+                    "description": "Heading text, optionally with its level marker // 标题文本，可带级别标记",
+                    "type": "string",
+                    "example": "## Inbox"
+                },
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string",
+                    "example": "ReadMe.md"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "hash123"
+                },
+                "position": {
+                    "description": "end (default) or start of the section // 插入到段落末尾（默认）或开头",
+                    "type": "string",
+                    "enum": [
+                        "end",
+                        "start"
+                    ],
+                    "example": "end"
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.NoteAppendToHeadingResponse": {
+            "type": "object",
+            "properties": {
+                "headingCreated": {
+                    "description": "Whether the heading was missing and added at the end // 标题是否缺失并被添加到末尾",
+                    "type": "boolean"
+                },
+                "note": {
+                    "description": "Updated note // 更新后的笔记",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.NoteDTO"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.NoteDTO": {
+            "type": "object",
+            "properties": {
+                "clientName": {
+                    "description": "Client name // 客户端名称",
+                    "type": "string"
+                },
+                "clientType": {
+                    "description": "Client type // 客户端类型",
+                    "type": "string"
+                },
+                "clientVersion": {
+                    "description": "Client version // 客户端版本",
+                    "type": "string"
+                },
+                "content": {
+                    "description": "Note content // 笔记内容",
+                    "type": "string"
+                },
+                "contentHash": {
+                    "description": "Content hash // 内容哈希",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "Created at time // 创建时间",
+                    "type": "string"
+                },
+                "ctime": {
+                    "description": "Creation timestamp // 创建时间戳",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Note ID // 笔记 ID",
+                    "type": "integer"
+                },
+                "lastTime": {
+                    "description": "Record update timestamp // 记录更新时间戳",
+                    "type": "integer"
+                },
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Note size // 笔记大小",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "description": "Updated at time // 更新时间",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version number // 版本号",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.NoteDailyRequest": {
+            "type": "object",
+            "required": [
+                "vault"
+            ],
+            "properties": {
+                "append": {
+                    "description": "Content appended to the note // 追加到笔记末尾的内容",
+                    "type": "string",
+                    "example": "- 09:30 Standup"
+                },
+                "date": {
+                    "description": "Day as YYYY-MM-DD, empty for today // 日期，格式 YYYY-MM-DD，为空表示今天",
+                    "type": "string",
+                    "example": "2026-03-07"
+                },
+                "prepend": {
+                    "description": "Content inserted at the top of the note, after frontmatter // 插入到笔记开头（Frontmatter 之后）的内容",
+                    "type": "string",
+                    "example": "\u003e Focus: release\n"
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.NoteDailyResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Whether the call created the note // 本次调用是否新建了笔记",
+                    "type": "boolean"
+                },
+                "note": {
+                    "description": "The daily note after the call // 调用后的每日笔记",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.NoteDTO"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.NoteDailySettingDTO": {
+            "type": "object",
+            "properties": {
+                "pattern": {
+                    "description": "Path pattern // 路径模式",
+                    "type": "string"
+                },
+                "template": {
+                    "description": "Template name // 模板名称",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "IANA time zone // IANA 时区",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "Updated at // 更新时间",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NoteDailySettingRequest": {
+            "type": "object",
+            "properties": {
+                "pattern": {
+                    "description": "Path pattern with Moment.js tokens in braces, empty resets to the default // 花括号内为 Moment.js 标记的路径模式，为空时恢复默认",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Journal/{{YYYY-MM-DD}}.md"
+                },
+                "template": {
+                    "description": "Template a missing daily note is created from, empty for an empty note // 创建缺失的每日笔记所用的模板，为空时创建空笔记",
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "Daily"
+                },
+                "timezone": {
+                    "description": "IANA time zone deciding what \"today\" is, empty for the server's local time // 决定“今天”的 IANA 时区，为空时使用服务器本地时间",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "Asia/Shanghai"
+                }
+            }
+        },
+        "dto.NoteDuplicateRequest": {
+            "type": "object",
+            "required": [
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "copyAttachments": {
+                    "description": "Duplicate embedded attachments and point the copy at them // 复制嵌入的附件并让副本引用新附件",
+                    "type": "boolean",
+                    "example": false
+                },
+                "newPath": {
+                    "description": "Target path, defaults to \"Copy of \u003cname\u003e\" in the same folder // 目标路径，默认为同目录下的 \"Copy of \u003c名称\u003e\"",
+                    "type": "string",
+                    "example": "Copy of ReadMe.md"
+                },
+                "path": {
+                    "description": "Source note path // 源笔记路径",
+                    "type": "string",
+                    "example": "ReadMe.md"
+                },
+                "pathHash": {
+                    "description": "Source path hash // 源路径哈希",
+                    "type": "string",
+                    "example": "hash123"
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.NoteDuplicateResult": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "description": "Duplicated attachments // 复制得到的附件",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FileDTO"
+                    }
+                },
+                "note": {
+                    "description": "Duplicated note // 复制得到的笔记",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.NoteDTO"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.NoteFrontmatterItem": {
             "type": "object",
             "properties": {
-                "database": {
-                    "description": "\"connected\" or \"error\" // \"connected\" 或 \"error\"",
+                "ctime": {
+                    "description": "Creation timestamp // 创建时间戳",
+                    "type": "integer"
+                },
+                "frontmatter": {
+                    "description": "Parsed frontmatter // 解析后的 frontmatter",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "id": {
+                    "description": "Note ID // 笔记 ID",
+                    "type": "integer"
+                },
+                "lastTime": {
+                    "description": "Record update timestamp // 记录更新时间戳",
+                    "type": "integer"
+                },
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "Note path // 笔记路径",
                     "type": "string"
                 },
-                "status": {
-                    "description": "\"healthy\" or \"unhealthy\" // \"healthy\" 或 \"unhealthy\"",
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
                     "type": "string"
                 },
-                "uptime": {
-                    "description": "Uptime (seconds) // 运行时间（秒）",
-                    "type": "number"
+                "size": {
+                    "description": "Note size // 笔记大小",
+                    "type": "integer"
                 },
                 "version": {
-                    "description": "Service version number // 服务版本号",
-                    "type": "string"
+                    "description": "Version number // 版本号",
+                    "type": "integer"
                 }
             }
         },
-        "app.HistoricalVersion": {
+        "dto.NoteFrontmatterQueryRequest": {
+            "type": "object"
+        },
+        "dto.NoteHistoryDTO": {
             "type": "object",
             "properties": {
-                "changelogContent": {
-                    "description": "Changelog content // 更新日志内容",
+                "clientName": {
+                    "description": "Client that made changes // 产生变更的客户端",
+                    "type": "string"
+                },
+                "clientType": {
+                    "description": "Client type // 客户端类型",
+                    "type": "string"
+                },
+                "clientVersion": {
+                    "description": "Client version // 客户端版本",
+                    "type": "string"
+                },
+                "content": {
+                    "description": "Full historical content // 完整历史内容",
+                    "type": "string"
+                },
+                "contentHash": {
+                    "description": "Content hash // 内容哈希",
                     "type": "string"
                 },
+                "createdAt": {
+                    "description": "Creation time of this version // 此版本的创建时间",
+                    "type": "string"
+                },
+                "diffs": {
+                    "description": "Text differences // 文本差异内容",
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "id": {
+                    "description": "History entry ID // 历史项 ID",
+                    "type": "integer"
+                },
+                "noteId": {
+                    "description": "Associated note ID // 笔记 ID",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "Note path at that time // 当时的笔记路径",
+                    "type": "string"
+                },
+                "vaultId": {
+                    "description": "Associated vault ID // 保险库 ID",
+                    "type": "integer"
+                },
                 "version": {
-                    "description": "Version name // 版本号",
+                    "description": "Historical version number // 历史版本号",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.NoteHistoryRestoreRequest": {
+            "type": "object",
+            "required": [
+                "historyId",
+                "vault"
+            ],
+            "properties": {
+                "historyId": {
+                    "description": "History version ID // 历史版本 ID",
+                    "type": "integer",
+                    "example": 1
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.NoteLinkItem": {
+            "type": "object",
+            "properties": {
+                "context": {
+                    "description": "Text context around link // 链接文本上下文",
+                    "type": "string"
+                },
+                "isEmbed": {
+                    "description": "Is it an embed (![[...]]) // 是否为嵌入",
+                    "type": "boolean"
+                },
+                "linkText": {
+                    "description": "Raw link text (optional) // 原始链接文本（可选）",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Target path // 目标路径",
                     "type": "string"
                 }
             }
         },
-        "app.ListRes": {
+        "dto.NoteLintDTO": {
             "type": "object",
             "properties": {
-                "list": {
-                    "description": "Data list // 数据清单"
+                "checker": {
+                    "description": "languagetool or wordlist // languagetool 或 wordlist",
+                    "type": "string"
                 },
-                "pager": {
-                    "description": "Pagination info // 翻页信息",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/app.Pager"
-                        }
-                    ]
+                "issues": {
+                    "description": "Issues ordered by offset // 按偏移排序的问题",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NoteLintIssueDTO"
+                    }
+                },
+                "path": {
+                    "description": "Note path, empty when raw text was checked // 笔记路径，检查原始文本时为空",
+                    "type": "string"
                 }
             }
         },
-        "app.Pager": {
+        "dto.NoteLintIssueDTO": {
             "type": "object",
             "properties": {
-                "page": {
-                    "description": "Page number // 页码",
+                "category": {
+                    "description": "Rule category // 规则分类",
+                    "type": "string"
+                },
+                "length": {
+                    "description": "Length of the issue // 问题长度",
                     "type": "integer"
                 },
-                "pageSize": {
-                    "description": "Page size // 每页数量",
+                "message": {
+                    "description": "Explanation // 说明",
+                    "type": "string"
+                },
+                "offset": {
+                    "description": "Start of the issue // 问题起始位置",
+                    "type": "integer"
+                },
+                "replacements": {
+                    "description": "Suggested replacements // 替换建议",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rule": {
+                    "description": "Rule ID // 规则 ID",
+                    "type": "string"
+                },
+                "shortMessage": {
+                    "description": "Short explanation // 简短说明",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NoteLintRequest": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "description": "Language code, empty uses the server default // 语言代码，为空时使用服务器默认值",
+                    "type": "string",
+                    "example": "en-US"
+                },
+                "path": {
+                    "description": "Note path, checked when text is empty // 笔记路径，text 为空时检查该笔记",
+                    "type": "string",
+                    "example": "ReadMe.md"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "hash123"
+                },
+                "text": {
+                    "description": "Raw text, takes precedence over the note // 原始文本，优先于笔记",
+                    "type": "string",
+                    "example": "This are a sentence."
+                },
+                "vault": {
+                    "description": "Vault name, required when checking a stored note // 保险库名称，检查已存储笔记时必填",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.NoteMetaDTO": {
+            "type": "object",
+            "properties": {
+                "meta": {
+                    "description": "Attributes by name with their typed values // 按属性名给出的带类型值",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "noteId": {
+                    "description": "Note ID // 笔记 ID",
                     "type": "integer"
                 },
-                "totalRows": {
-                    "description": "Total rows // 总行数",
-                    "type": "integer"
-                }
-            }
-        },
-        "app.Res": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer"
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NoteMetaSetRequest": {
+            "type": "object"
+        },
+        "dto.NoteModifyOrCreateRequest": {
+            "type": "object",
+            "required": [
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "baseHash": {
+                    "description": "Base hash for sync // 同步基准哈希",
+                    "type": "string",
+                    "example": "bhash789"
+                },
+                "baseHashMissing": {
+                    "description": "Marks if baseHash is unavailable // 标记基准哈希是否缺失",
+                    "type": "boolean",
+                    "example": false
+                },
+                "content": {
+                    "description": "Note content // 笔记内容",
+                    "type": "string",
+                    "example": "# Hello World"
+                },
+                "contentHash": {
+                    "description": "Content hash // 内容哈希",
+                    "type": "string",
+                    "example": "chash012"
+                },
+                "context": {
+                    "description": "Context // 同步上下文",
+                    "type": "string",
+                    "example": "ctx123"
+                },
+                "createOnly": {
+                    "description": "If true, fail if note already exists // 如果为 true，笔记已存在则失败",
+                    "type": "boolean",
+                    "example": false
+                },
+                "ctime": {
+                    "description": "Creation timestamp // 创建时间戳",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "isConflictResolved": {
+                    "description": "Marks if conflict is resolved manually // 标记是否为手动解决冲突",
+                    "type": "boolean",
+                    "example": false
+                },
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string",
+                    "example": "ReadMe.md"
                 },
-                "context": {},
-                "data": {},
-                "details": {},
-                "message": {},
-                "pageIndex": {
-                    "description": "1-BASED wire value: 0/absent = non-paginated message, n\u003e0 = download page n-1 (internal 0-based). 1-based so page 0 stays distinguishable from non-paginated messages under proto3 zero-value elision; only set for paginated download messages on pv\u003e=2 connections. Client-to-server PageAck.pageIndex stays 0-based. // 线上值为 1-BASED：0/缺省 = 非分页消息，n\u003e0 = 下载页 n-1（内部 0-based）。采用 1-based 是为了让第 0 页在 proto3 零值不编码规则下仍可与非分页消息区分；仅 pv\u003e=2 连接的分页下载消息会设置。客户端→服务端的 PageAck.pageIndex 保持 0-based"
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "hash123"
                 },
-                "path": {},
-                "status": {
-                    "type": "boolean"
+                "template": {
+                    "description": "Template name; the expanded template replaces content // 模板名称；展开后的模板替换 content",
+                    "type": "string",
+                    "example": "Daily"
                 },
-                "vault": {}
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
             }
         },
-        "app.WSClientInfo": {
+        "dto.NoteNoContentDTO": {
             "type": "object",
             "properties": {
                 "clientName": {
+                    "description": "Client name // 客户端名称",
                     "type": "string"
                 },
                 "clientType": {
+                    "description": "Client type // 客户端类型",
                     "type": "string"
                 },
                 "clientVersion": {
+                    "description": "Client version // 客户端版本",
                     "type": "string"
                 },
-                "nickname": {
+                "createdAt": {
+                    "description": "Created at time // 创建时间",
                     "type": "string"
                 },
-                "platformInfo": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "boolean"
-                    }
+                "ctime": {
+                    "description": "Creation timestamp // 创建时间戳",
+                    "type": "integer"
                 },
-                "remoteAddr": {
-                    "type": "string"
+                "id": {
+                    "description": "Note ID // 笔记 ID",
+                    "type": "integer"
                 },
-                "startTime": {
-                    "type": "string"
+                "lastTime": {
+                    "description": "Record update timestamp // 记录更新时间戳",
+                    "type": "integer"
                 },
-                "tokenId": {
+                "matchCount": {
+                    "description": "Number of regex matches in content, regex search only // 正文中的正则匹配次数，仅正则搜索返回",
                     "type": "integer"
                 },
-                "traceId": {
+                "meta": {
+                    "description": "Custom metadata by name // 按名称给出的自定义元数据",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "Note path // 笔记路径",
                     "type": "string"
                 },
-                "uid": {
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
                     "type": "string"
-                }
-            }
-        },
-        "dto.AdminCPUInfo": {
-            "type": "object",
-            "properties": {
-                "loadAvg": {
-                    "description": "Load average // 平均负载",
+                },
+                "size": {
+                    "description": "Note size // 笔记大小",
+                    "type": "integer"
+                },
+                "snippet": {
+                    "description": "Match snippet, only when highlight is requested // 匹配片段，仅在请求高亮时返回",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/dto.AdminLoadInfo"
+                            "$ref": "#/definitions/dto.NoteSearchSnippet"
                         }
                     ]
                 },
-                "logicalCores": {
-                    "description": "Logical cores // 逻辑核心数",
-                    "type": "integer"
-                },
-                "modelName": {
-                    "description": "Model name // 型号",
+                "updatedAt": {
+                    "description": "Updated at time // 更新时间",
                     "type": "string"
                 },
-                "percent": {
-                    "description": "Usage percentage per core // 每个核心的使用率",
-                    "type": "array",
-                    "items": {
-                        "type": "number"
-                    }
-                },
-                "physicalCores": {
-                    "description": "Physical cores // 物理核心数",
+                "version": {
+                    "description": "Version number // 版本号",
                     "type": "integer"
                 }
             }
         },
-        "dto.AdminCheckResponse": {
-            "type": "object",
-            "properties": {
-                "isAdmin": {
-                    "description": "Whether have admin privileges // 是否具有管理员权限",
-                    "type": "boolean"
-                }
-            }
-        },
-        "dto.AdminCloudflareConfig": {
+        "dto.NotePatchFrontmatterRequest": {
             "type": "object",
+            "required": [
+                "path",
+                "vault"
+            ],
             "properties": {
-                "enabled": {
-                    "description": "Whether to enable cloudflare tunnel // 是否启用 cloudflare 隧道",
-                    "type": "boolean"
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string",
+                    "example": "ReadMe.md"
                 },
-                "logEnabled": {
-                    "description": "Whether to enable cloudflare tunnel logging // 是否开启 cloudflare 隧道日志",
-                    "type": "boolean"
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "hash123"
                 },
-                "token": {
-                    "description": "cloudflare tunnel token // cloudflare 隧道令牌",
-                    "type": "string"
+                "remove": {
+                    "description": "Fields to remove // 待移除字段",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "old_tag"
+                    ]
+                },
+                "updates": {
+                    "description": "Fields to update // 待更新字段",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
                 }
             }
         },
-        "dto.AdminConfig": {
+        "dto.NotePolicyDTO": {
             "type": "object",
             "properties": {
-                "adminUid": {
-                    "description": "Admin UID // 管理员 UID",
-                    "type": "integer"
-                },
-                "authTokenKey": {
-                    "description": "Auth token key // 认证 Token 密钥",
+                "action": {
+                    "description": "archive or delete // archive 或 delete",
                     "type": "string"
                 },
-                "customResponseHeaders": {
-                    "description": "Custom HTTP response headers // 自定义 HTTP 响应头",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "defaultApiFolder": {
-                    "description": "Default API folder // 默认 API 目录",
+                "ageBasis": {
+                    "description": "modified or created // modified 或 created",
                     "type": "string"
                 },
-                "defaultContextTimeout": {
-                    "description": "Default context timeout // 默认上下文超时",
-                    "type": "integer"
-                },
-                "defaultPageSize": {
-                    "description": "Default page size // 默认每页显示数",
-                    "type": "integer"
-                },
-                "downloadSessionTimeout": {
-                    "description": "Download session timeout // 下载分片超时",
+                "archiveFolder": {
+                    "description": "Target folder of the archive action // 归档操作的目标目录",
                     "type": "string"
                 },
-                "fileChunkSize": {
-                    "description": "File chunk size // 文件分块大小",
+                "createdAt": {
+                    "description": "Created at // 创建时间",
                     "type": "string"
                 },
-                "fontSet": {
-                    "description": "Font set // 字体设置",
+                "folder": {
+                    "description": "Folder the policy applies to, empty for the whole vault // 策略作用的目录，为空表示整个仓库",
                     "type": "string"
                 },
-                "ftsBleveEnabled": {
-                    "description": "Whether Bleve FTS is enabled // 是否启用 Bleve 全文搜索",
-                    "type": "boolean"
+                "id": {
+                    "description": "Policy ID // 策略 ID",
+                    "type": "integer"
                 },
-                "ftsBleveStoreRaw": {
-                    "description": "Whether Bleve stores raw content // Bleve 全文搜索是否存储原始文本",
+                "isEnabled": {
+                    "description": "Whether the scheduler runs the policy // 是否由调度器运行",
                     "type": "boolean"
                 },
-                "gitEmail": {
-                    "description": "Git author email // Git 提交的作者邮箱",
+                "lastRunTime": {
+                    "description": "Last run time // 上次运行时间",
                     "type": "string"
                 },
-                "gitName": {
-                    "description": "Git author name // Git 提交的作者名称",
+                "name": {
+                    "description": "Display name // 显示名称",
                     "type": "string"
                 },
-                "historyKeepVersions": {
-                    "description": "History versions to keep // 历史版本保留数",
+                "olderThanDays": {
+                    "description": "Minimum age in days // 最小年龄（天）",
                     "type": "integer"
                 },
-                "historySaveDelay": {
-                    "description": "History save delay // 历史保存延迟",
+                "tag": {
+                    "description": "Only notes carrying this tag, empty for all notes // 仅匹配带有该标签的笔记，为空表示全部",
                     "type": "string"
                 },
-                "isReturnSussess": {
-                    "description": "Whether to return success detail // 是否返回成功详情",
-                    "type": "boolean"
+                "updatedAt": {
+                    "description": "Updated at // 更新时间",
+                    "type": "string"
                 },
-                "maxPageSize": {
-                    "description": "Max page size // 最大每页显示限制",
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NotePolicyIDRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "Policy ID // 策略 ID",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.NotePolicyPreviewDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "archive or delete // archive 或 delete",
+                    "type": "string"
+                },
+                "items": {
+                    "description": "Notes a run would process // 运行时将处理的笔记",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NotePolicyPreviewItemDTO"
+                    }
+                },
+                "matched": {
+                    "description": "Notes matching the policy // 匹配的笔记数",
                     "type": "integer"
                 },
-                "pipelineWindowDown": {
-                    "description": "Download pipeline window size for pv\u003e=2 connections; 0 = stop-and-wait // pv\u003e=2 连接的下行流水线窗口大小；0 = stop-and-wait",
+                "policyId": {
+                    "description": "Policy ID // 策略 ID",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.NotePolicyPreviewItemDTO": {
+            "type": "object",
+            "properties": {
+                "ctime": {
+                    "description": "Creation time in milliseconds // 创建时间（毫秒）",
                     "type": "integer"
                 },
-                "pipelineWindowUp": {
-                    "description": "Upload pipeline window size for pv\u003e=2 connections; 0 = stop-and-wait // pv\u003e=2 连接的上行流水线窗口大小；0 = stop-and-wait",
+                "mtime": {
+                    "description": "Modification time in milliseconds // 修改时间（毫秒）",
                     "type": "integer"
                 },
-                "pullReleaseChannel": {
-                    "description": "Update version channel: stable | beta // 更新版本通道：stable | beta",
+                "path": {
+                    "description": "Note path // 笔记路径",
                     "type": "string"
                 },
-                "pullSource": {
-                    "description": "Data pull source: auto | github | cnb // 数据拉取源：auto | github | cnb",
+                "target": {
+                    "description": "Path after archiving, empty for deletes // 归档后的路径，删除时为空",
                     "type": "string"
+                }
+            }
+        },
+        "dto.NotePolicyRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "olderThanDays",
+                "vault"
+            ],
+            "properties": {
+                "action": {
+                    "description": "archive or delete // archive 或 delete",
+                    "type": "string",
+                    "enum": [
+                        "archive",
+                        "delete"
+                    ],
+                    "example": "archive"
                 },
-                "registerIsEnable": {
-                    "description": "Registration enablement // 是否开启注册",
-                    "type": "boolean"
-                },
-                "shareTokenExpiry": {
-                    "description": "Share token expiry // 分享 Token 有效期",
-                    "type": "string"
+                "ageBasis": {
+                    "description": "Age measured from modified (default) or created // 年龄依据 modified（默认）或 created",
+                    "type": "string",
+                    "enum": [
+                        "modified",
+                        "created"
+                    ],
+                    "example": "modified"
                 },
-                "shareTokenKey": {
-                    "description": "Share token key // 分享 Token 密钥",
-                    "type": "string"
+                "archiveFolder": {
+                    "description": "Target folder of the archive action // 归档操作的目标目录",
+                    "type": "string",
+                    "example": "Archive"
                 },
-                "softDeleteRetentionTime": {
-                    "description": "Soft delete retention time // 软删除保留时间",
-                    "type": "string"
+                "folder": {
+                    "description": "Folder the policy applies to, empty for the whole vault // 策略作用的目录，为空表示整个仓库",
+                    "type": "string",
+                    "example": "Projects"
                 },
-                "syncLogRetentionTime": {
-                    "description": "Sync log retention time // 同步日志保留时长",
-                    "type": "string"
+                "id": {
+                    "description": "ID, 0 creates a policy // ID，为 0 时新建",
+                    "type": "integer",
+                    "example": 1
                 },
-                "tempPath": {
-                    "description": "Temporary file path // 临时文件路径",
-                    "type": "string"
+                "isEnabled": {
+                    "description": "Whether the scheduler runs the policy // 是否由调度器运行",
+                    "type": "boolean",
+                    "example": true
                 },
-                "tokenExpiry": {
-                    "description": "Token expiry // Token 有效期",
-                    "type": "string"
+                "name": {
+                    "description": "Display name // 显示名称",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "Archive stale notes"
                 },
-                "uploadSessionTimeout": {
-                    "description": "Upload session timeout // 上传会话超时时间",
-                    "type": "string"
+                "olderThanDays": {
+                    "description": "Minimum age in days // 最小年龄（天）",
+                    "type": "integer",
+                    "maximum": 36500,
+                    "minimum": 1,
+                    "example": 365
                 },
-                "webguiLoginTokenBindIp": {
-                    "description": "WebGUI login token bind IP // WebGUI 登录 Token 是否绑定 IP",
-                    "type": "boolean"
+                "tag": {
+                    "description": "Only notes carrying this tag, empty for all notes // 仅匹配带有该标签的笔记，为空表示全部",
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "temp"
                 },
-                "webguiLoginTokenExpiry": {
-                    "description": "WebGUI login token expiry // WebGUI 登录 Token 有效期",
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.NotePolicyRunDTO": {
+            "type": "object",
+            "properties": {
+                "endTime": {
+                    "description": "End time // 结束时间",
                     "type": "string"
                 },
-                "workerPoolMaxWorkers": {
-                    "description": "Worker pool max workers // 协程池最大协程数",
+                "failed": {
+                    "description": "Notes that could not be processed // 处理失败的笔记数",
                     "type": "integer"
                 },
-                "workerPoolQueueSize": {
-                    "description": "Worker pool queue size // 协程池队列大小",
+                "id": {
+                    "description": "Run ID // 运行记录 ID",
                     "type": "integer"
                 },
-                "writeQueueCapacity": {
-                    "description": "Write queue capacity // 写入队列容量",
+                "matched": {
+                    "description": "Notes matching the policy // 匹配的笔记数",
                     "type": "integer"
                 },
-                "writeQueueIdleTime": {
-                    "description": "Write queue idle cleanup time // 写入队列空闲清理时长",
+                "message": {
+                    "description": "Errors of the failed notes // 失败笔记的错误信息",
                     "type": "string"
                 },
-                "writeQueueTimeout": {
-                    "description": "Write queue timeout // 写入队列超时",
-                    "type": "string"
+                "paths": {
+                    "description": "Paths of the processed notes // 已处理笔记的路径",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "wsCheckUtf8Enabled": {
-                    "description": "Whether ws check UTF-8 is enabled // WebSocket 是否开启 UTF-8 校验",
-                    "type": "boolean"
+                "policyId": {
+                    "description": "Policy ID // 策略 ID",
+                    "type": "integer"
                 },
-                "wsCompressionEnabled": {
-                    "description": "Whether ws compression is enabled // WebSocket 是否开启压缩",
-                    "type": "boolean"
+                "startTime": {
+                    "description": "Start time // 开始时间",
+                    "type": "string"
                 },
-                "wsCompressionLevel": {
-                    "description": "Ws compression level // WebSocket 压缩级别",
+                "succeeded": {
+                    "description": "Notes archived or deleted // 成功归档或删除的笔记数",
                     "type": "integer"
                 },
-                "wsCompressionThreshold": {
-                    "description": "Ws compression threshold // WebSocket 压缩阈值",
-                    "type": "integer"
+                "trigger": {
+                    "description": "schedule or manual // schedule 或 manual",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NotePrependRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "content": {
+                    "description": "Content to prepend // 头部添加内容",
+                    "type": "string",
+                    "example": "Prepended content\n"
                 },
-                "wsParallelEnabled": {
-                    "description": "Whether ws parallel is enabled // WebSocket 并行处理是否开启",
-                    "type": "boolean"
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string",
+                    "example": "ReadMe.md"
                 },
-                "wsParallelGolimit": {
-                    "description": "Ws parallel goroutine limit // WebSocket 并行协程限制",
-                    "type": "integer"
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "hash123"
                 },
-                "wsReadMaxPayloadSize": {
-                    "description": "WebSocket max read payload // WebSocket 最大读取负载",
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.NotePropertyKeyDTO": {
+            "type": "object",
+            "properties": {
+                "isList": {
+                    "description": "Whether the property is mostly a list // 该属性是否多为列表",
+                    "type": "boolean"
+                },
+                "key": {
+                    "description": "Property name // 属性名",
                     "type": "string"
                 },
-                "wsWriteMaxPayloadSize": {
-                    "description": "WebSocket max write payload // WebSocket 最大写入负载",
+                "noteCount": {
+                    "description": "Number of notes having the property // 具有该属性的笔记数",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Most common type: string, number, date, bool, object or null // 最常见的类型：string、number、date、bool、object 或 null",
                     "type": "string"
                 }
             }
         },
-        "dto.AdminHostInfo": {
+        "dto.NotePublishDTO": {
             "type": "object",
             "properties": {
-                "arch": {
-                    "description": "Architecture // 架构",
-                    "type": "string"
+                "id": {
+                    "description": "State ID // 状态 ID",
+                    "type": "integer"
                 },
-                "currentTime": {
-                    "description": "Current system time // 当前系统时间",
+                "message": {
+                    "description": "Error of the last attempt // 最近一次尝试的错误",
                     "type": "string"
                 },
-                "hostname": {
-                    "description": "Hostname // 主机名",
+                "path": {
+                    "description": "Note path // 笔记路径",
                     "type": "string"
                 },
-                "kernelVersion": {
-                    "description": "Kernel version // 内核版本",
+                "pathHash": {
+                    "description": "Note path hash // 笔记路径哈希",
                     "type": "string"
                 },
-                "os": {
-                    "description": "Operating system // 操作系统",
+                "publishAt": {
+                    "description": "Scheduled time from publish_at, empty publishes at once // 来自 publish_at 的计划时间，为空表示立即发布",
                     "type": "string"
                 },
-                "osPretty": {
-                    "description": "Detailed OS name // 详细操作系统名称",
+                "publishedAt": {
+                    "description": "Time the share was created // 分享创建时间",
                     "type": "string"
                 },
-                "platform": {
-                    "description": "Platform name // 平台",
+                "shareId": {
+                    "description": "Share exposing the note // 公开该笔记的分享",
+                    "type": "integer"
+                },
+                "shareToken": {
+                    "description": "Token of the share // 分享 Token",
                     "type": "string"
                 },
-                "timezone": {
-                    "description": "Time zone name // 时区名称",
+                "status": {
+                    "description": "scheduled, published, failed or unpublished // scheduled、published、failed 或 unpublished",
                     "type": "string"
                 },
-                "timezoneOffset": {
-                    "description": "Time zone offset in seconds // 时区偏移（秒）",
-                    "type": "integer"
+                "updatedAt": {
+                    "description": "Updated at // 更新时间",
+                    "type": "string"
                 },
-                "uptime": {
-                    "description": "System uptime // 系统运行时间",
-                    "type": "integer"
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string"
                 }
             }
         },
-        "dto.AdminLoadInfo": {
+        "dto.NoteRecycleClearRequest": {
             "type": "object",
+            "required": [
+                "vault"
+            ],
             "properties": {
-                "load1": {
-                    "description": "Load 1 min // 1分钟负载",
-                    "type": "number"
+                "path": {
+                    "description": "Note path, empty for all // 笔记路径，为空则清理全部",
+                    "type": "string",
+                    "example": "path/to/note.md"
                 },
-                "load15": {
-                    "description": "Load 15 min // 15分钟负载",
-                    "type": "number"
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "hash123"
                 },
-                "load5": {
-                    "description": "Load 5 min // 5分钟负载",
-                    "type": "number"
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
                 }
             }
         },
-        "dto.AdminMemoryInfo": {
+        "dto.NoteRenameRequest": {
             "type": "object",
+            "required": [
+                "oldPath",
+                "path",
+                "vault"
+            ],
             "properties": {
-                "available": {
-                    "description": "Available memory // 可用内存",
-                    "type": "integer"
+                "context": {
+                    "description": "Context // 同步上下文",
+                    "type": "string",
+                    "example": "ctx123"
                 },
-                "swapTotal": {
-                    "description": "Total swap space // 交换区总量",
-                    "type": "integer"
+                "oldPath": {
+                    "description": "Old path // 旧路径",
+                    "type": "string",
+                    "example": "OldName.md"
                 },
-                "swapUsed": {
-                    "description": "Used swap space // 交换区已用",
-                    "type": "integer"
+                "oldPathHash": {
+                    "description": "Old path hash // 旧路径哈希",
+                    "type": "string",
+                    "example": "ohash456"
                 },
-                "swapUsedPercent": {
-                    "description": "Swap usage percentage // 交换区使用率",
-                    "type": "number"
+                "path": {
+                    "description": "New path // 新路径",
+                    "type": "string",
+                    "example": "NewName.md"
                 },
-                "total": {
-                    "description": "Total physical memory // 系统总内存",
-                    "type": "integer"
+                "pathHash": {
+                    "description": "New path hash // 新路径哈希",
+                    "type": "string",
+                    "example": "nhash123"
                 },
-                "used": {
-                    "description": "Used memory // 已用内存",
-                    "type": "integer"
+                "updateLinks": {
+                    "description": "Rewrite links pointing at the note, defaults to the server setting // 改写指向该笔记的链接，默认使用服务端配置",
+                    "type": "boolean",
+                    "example": true
                 },
-                "usedPercent": {
-                    "description": "Memory usage percentage // 内存使用率",
-                    "type": "number"
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
                 }
             }
         },
-        "dto.AdminProcessInfo": {
+        "dto.NoteRenderDTO": {
             "type": "object",
             "properties": {
-                "cpuPercent": {
-                    "description": "CPU Usage percentage // CPU 使用率",
-                    "type": "number"
+                "fileLinks": {
+                    "description": "Embed target to attachment path // 嵌入目标到附件路径的映射",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "memoryPercent": {
-                    "description": "Memory Usage percentage // 内存使用率",
-                    "type": "number"
+                "frontmatter": {
+                    "description": "Parsed frontmatter, not part of the HTML // 解析后的 frontmatter，不包含在 HTML 中",
+                    "type": "object",
+                    "additionalProperties": true
                 },
-                "name": {
-                    "description": "Process Name // 进程名称",
+                "html": {
+                    "description": "Sanitized HTML of the body // 正文经过清洗的 HTML",
                     "type": "string"
                 },
-                "pid": {
-                    "description": "Process ID // 进程 ID",
-                    "type": "integer"
+                "noteLinks": {
+                    "description": "Wiki link target to note path // 维基链接目标到笔记路径的映射",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "ppid": {
-                    "description": "Parent Process ID // 父进程 ID",
-                    "type": "integer"
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string"
                 }
             }
         },
-        "dto.AdminRuntimeInfo": {
+        "dto.NoteReplaceRequest": {
             "type": "object",
+            "required": [
+                "find",
+                "path",
+                "vault"
+            ],
             "properties": {
-                "buckHashSys": {
-                    "description": "Memory obtained from system for profiling bucket hash table (bytes) // 分析桶哈希表占用的系统内存",
-                    "type": "integer"
-                },
-                "gcSys": {
-                    "description": "Memory obtained from system for metadata for GC (bytes) // GC 元数据占用的系统内存",
-                    "type": "integer"
+                "all": {
+                    "description": "Replace all matches // 替换所有",
+                    "type": "boolean",
+                    "example": true
                 },
-                "heapIdle": {
-                    "description": "Memory in idle spans (bytes) // 空闲 Span 占用的内存",
-                    "type": "integer"
+                "failIfNoMatch": {
+                    "description": "Fail if no match found // 若无匹配则失败",
+                    "type": "boolean",
+                    "example": true
                 },
-                "heapInuse": {
-                    "description": "Memory in in-use spans (bytes) // 正在使用的 Span 占用的内存",
-                    "type": "integer"
+                "find": {
+                    "description": "String to find // 查找内容",
+                    "type": "string",
+                    "example": "old text"
                 },
-                "heapReleased": {
-                    "description": "Memory released to OS (bytes) // 释放回操作系统的内存（字节）",
-                    "type": "integer"
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string",
+                    "example": "ReadMe.md"
                 },
-                "heapSys": {
-                    "description": "Memory obtained from system for heap (bytes) // 堆占用的系统内存",
-                    "type": "integer"
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "hash123"
                 },
-                "mCacheSys": {
-                    "description": "Memory obtained from system for mcache (bytes) // mcache 占用的系统内存",
-                    "type": "integer"
+                "regex": {
+                    "description": "Use regex // 使用正则",
+                    "type": "boolean",
+                    "example": false
                 },
-                "mSpanSys": {
-                    "description": "Memory obtained from system for mspan (bytes) // mspan 占用的系统内存",
-                    "type": "integer"
+                "replace": {
+                    "description": "String to replace with // 替换内容",
+                    "type": "string",
+                    "example": "new text"
                 },
-                "memAlloc": {
-                    "description": "Allocated memory (bytes) // 已分配内存（字节）",
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.NoteReplaceResponse": {
+            "type": "object",
+            "properties": {
+                "matchCount": {
+                    "description": "Number of matches found // 匹配数量",
                     "type": "integer"
                 },
-                "memSys": {
-                    "description": "Memory obtained from system (bytes) // 从系统获取的内存（字节）",
-                    "type": "integer"
+                "note": {
+                    "description": "Updated note data // 更新后的笔记数据",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.NoteDTO"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.NoteRestoreRequest": {
+            "type": "object",
+            "required": [
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string",
+                    "example": "ReadMe.md"
                 },
-                "memTotal": {
-                    "description": "Total memory allocated (bytes) // 累计分配内存（字节）",
-                    "type": "integer"
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "hash123"
                 },
-                "nextGc": {
-                    "description": "Target heap size for the next GC cycle // 下次 GC 的目标堆大小",
-                    "type": "integer"
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.NoteSearchSnippet": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Matched field: content or path // 命中字段：content 或 path",
+                    "type": "string"
                 },
-                "numGc": {
-                    "description": "Number of completed GC cycles // GC 次数",
-                    "type": "integer"
+                "html": {
+                    "description": "HTML-escaped context with \u003cmark\u003e tags // 带 \u003cmark\u003e 标签且已转义的 HTML 上下文",
+                    "type": "string"
                 },
-                "numGoroutine": {
-                    "description": "Number of goroutines // Goroutine 数量",
-                    "type": "integer"
+                "matches": {
+                    "description": "Match positions within text // 匹配在 text 中的位置",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NoteSnippetRange"
+                    }
                 },
-                "otherSys": {
-                    "description": "Other system memory (bytes) // 其他系统内存",
+                "text": {
+                    "description": "Plain-text match context // 纯文本匹配上下文",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NoteSnippetRange": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "description": "End offset (exclusive) // 结束偏移（不含）",
                     "type": "integer"
                 },
-                "stackSys": {
-                    "description": "Memory obtained from system for stack (bytes) // 栈占用的系统内存",
+                "start": {
+                    "description": "Start offset // 起始偏移",
                     "type": "integer"
                 }
             }
         },
-        "dto.AdminSystemInfo": {
+        "dto.NoteSyncRenameMessage": {
             "type": "object",
+            "required": [
+                "path"
+            ],
             "properties": {
-                "cpu": {
-                    "description": "CPU information // CPU 信息",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/dto.AdminCPUInfo"
-                        }
-                    ]
+                "contentHash": {
+                    "description": "Content hash // 内容哈希",
+                    "type": "string",
+                    "example": "chash456"
                 },
-                "host": {
-                    "description": "Host information // 主机信息",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/dto.AdminHostInfo"
-                        }
-                    ]
+                "ctime": {
+                    "description": "Creation timestamp // 创建时间戳",
+                    "type": "integer",
+                    "example": 1700000000
                 },
-                "memory": {
-                    "description": "Memory information // 内存信息",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/dto.AdminMemoryInfo"
-                        }
-                    ]
+                "lastTime": {
+                    "description": "Record update timestamp // 记录更新时间戳",
+                    "type": "integer",
+                    "example": 1700000000
                 },
-                "process": {
-                    "description": "Process information // 进程信息",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/dto.AdminProcessInfo"
-                        }
-                    ]
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
+                    "type": "integer",
+                    "example": 1700000000
                 },
-                "runtimeStatus": {
-                    "description": "Go runtime status // Go 运行时状态",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/dto.AdminRuntimeInfo"
-                        }
-                    ]
+                "oldPath": {
+                    "description": "Old path // 旧路径",
+                    "type": "string",
+                    "example": "OldName.md"
+                },
+                "oldPathHash": {
+                    "description": "Old path hash // 旧路径哈希",
+                    "type": "string",
+                    "example": "ofhash456"
+                },
+                "path": {
+                    "description": "New path // 新路径",
+                    "type": "string",
+                    "example": "NewName.md"
                 },
-                "startTime": {
-                    "description": "Start time // 启动时间",
-                    "type": "string"
+                "pathHash": {
+                    "description": "New path hash // 新路径哈希",
+                    "type": "string",
+                    "example": "nfhash123"
                 },
-                "uptime": {
-                    "description": "Uptime (seconds) // 运行时间（秒）",
-                    "type": "number"
+                "size": {
+                    "description": "File size // 文件大小",
+                    "type": "integer",
+                    "example": 1024
                 }
             }
         },
-        "dto.AdminUserDatabaseConfig": {
+        "dto.NoteTemplateDTO": {
             "type": "object",
             "properties": {
-                "charset": {
-                    "description": "Charset // 字符集",
-                    "type": "string"
-                },
-                "connMaxIdleTime": {
-                    "description": "Connection max idle time // 空闲连接最大生命周期",
+                "content": {
+                    "description": "Template content // 模板内容",
                     "type": "string"
                 },
-                "connMaxLifetime": {
-                    "description": "Connection max lifetime // 连接最大生命周期",
+                "createdAt": {
+                    "description": "Created at // 创建时间",
                     "type": "string"
                 },
-                "host": {
-                    "description": "Host // 主机",
+                "description": {
+                    "description": "Description // 描述",
                     "type": "string"
                 },
-                "maxIdleConns": {
-                    "description": "Max idle connections // 最大闲置连接数",
-                    "type": "integer"
-                },
-                "maxOpenConns": {
-                    "description": "Max open connections // 最大打开连接数",
-                    "type": "integer"
-                },
-                "maxWriteConcurrency": {
-                    "description": "Max write concurrency // 最大并发写入数",
+                "id": {
+                    "description": "Template ID // 模板 ID",
                     "type": "integer"
                 },
                 "name": {
-                    "description": "Database name // 数据库名",
+                    "description": "Unique name // 唯一名称",
                     "type": "string"
                 },
-                "parseTime": {
-                    "description": "Parse time // 是否解析时间",
-                    "type": "boolean"
-                },
-                "password": {
-                    "description": "Password // 密码",
+                "updatedAt": {
+                    "description": "Updated at // 更新时间",
                     "type": "string"
+                }
+            }
+        },
+        "dto.NoteTemplateRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "content": {
+                    "description": "Content with {{date}}, {{time}} and {{title}} variables // 包含 {{date}}、{{time}}、{{title}} 变量的内容",
+                    "type": "string",
+                    "example": "# {{title}}\n\nCreated {{date}} {{time}}"
                 },
-                "path": {
-                    "description": "SQLite database file path // SQLite 数据库文件路径",
-                    "type": "string"
+                "description": {
+                    "description": "Description // 描述",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Daily journal"
                 },
-                "port": {
-                    "description": "Port // 端口",
-                    "type": "integer"
+                "name": {
+                    "description": "Unique name // 唯一名称",
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "Daily"
+                }
+            }
+        },
+        "dto.NoteUnresolvedLinkCreateRequest": {
+            "type": "object",
+            "required": [
+                "vault"
+            ],
+            "properties": {
+                "content": {
+                    "description": "Content of the stubs // 占位笔记的内容",
+                    "type": "string",
+                    "example": "#stub"
                 },
-                "schema": {
-                    "description": "Database schema (postgres only) // 数据库 Schema",
-                    "type": "string"
+                "folder": {
+                    "description": "Folder the stubs are created in // 创建占位笔记的目录",
+                    "type": "string",
+                    "example": "Inbox"
                 },
-                "sslMode": {
-                    "description": "SSL mode (postgres only) // SSL 模式",
-                    "type": "string"
+                "targets": {
+                    "description": "Targets to create, all when empty // 需要创建的目标，为空时创建全部",
+                    "type": "array",
+                    "maxItems": 1000,
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "type": {
-                    "description": "Database type (mysql, postgres, sqlite) // 数据库类型",
+                "vault": {
+                    "description": "Vault name // 保险库名称",
                     "type": "string",
-                    "enum": [
-                        "mysql",
-                        "postgres",
-                        "sqlite"
-                    ]
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.NoteUnresolvedLinkCreateResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Created stub notes // 已创建的占位笔记",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NoteDTO"
+                    }
                 },
-                "userName": {
-                    "description": "Username // 用户名",
-                    "type": "string"
+                "skipped": {
+                    "description": "Targets left alone because the path is invalid or taken // 因路径无效或已被占用而跳过的目标",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "dto.AdminWebGUIConfig": {
+        "dto.NoteUnresolvedLinkItem": {
             "type": "object",
             "properties": {
-                "fontSet": {
-                    "description": "Font set // 字体设置",
-                    "type": "string"
+                "count": {
+                    "description": "Number of links to the target // 指向该目标的链接数量",
+                    "type": "integer"
                 },
-                "ftsBleveEnabled": {
-                    "description": "Whether Bleve FTS is enabled // 是否启用 Bleve 全文搜索",
-                    "type": "boolean"
+                "sources": {
+                    "description": "Paths of the linking notes // 发起链接的笔记路径",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "registerIsEnable": {
-                    "description": "Registration enablement // 是否开启注册",
-                    "type": "boolean"
+                "stubPath": {
+                    "description": "Path of the note that would resolve the link // 可解析该链接的笔记路径",
+                    "type": "string"
+                },
+                "target": {
+                    "description": "Link target without heading // 不含标题的链接目标",
+                    "type": "string"
                 }
             }
         },
-        "dto.BackupConfigDTO": {
+        "dto.NoteWithFileLinksResponse": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "description": "Created at // 创建时间",
+                "content": {
+                    "description": "Note content // 笔记内容",
                     "type": "string"
                 },
-                "cronExpression": {
-                    "description": "Cron expression // Cron表达式",
+                "contentHash": {
+                    "description": "Content hash // 内容哈希",
                     "type": "string"
                 },
-                "cronStrategy": {
-                    "description": "Cron strategy // 定时策略",
-                    "type": "string"
+                "createdAt": {
+                    "description": "Created at time // 创建时间"
                 },
-                "id": {
-                    "description": "Config ID // 配置ID",
+                "ctime": {
+                    "description": "Creation timestamp // 创建时间戳",
                     "type": "integer"
                 },
-                "includeVaultName": {
-                    "description": "Whether sync path includes vault name // 同步路径是否包含仓库名",
-                    "type": "boolean"
-                },
-                "isEnabled": {
-                    "description": "Is enabled // 是否启用",
-                    "type": "boolean"
+                "fileLinks": {
+                    "description": "Map of file link to actual path // 文件链接到实际路径的映射",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "lastMessage": {
-                    "description": "Last run result message // 上次运行结果消息",
-                    "type": "string"
+                "lastTime": {
+                    "description": "Record update timestamp // 记录更新时间戳",
+                    "type": "integer"
                 },
-                "lastRunTime": {
-                    "description": "Last run time // 上次运行时间",
-                    "type": "string"
+                "meta": {
+                    "description": "Custom metadata by name // 按名称给出的自定义元数据",
+                    "type": "object",
+                    "additionalProperties": {}
                 },
-                "lastStatus": {
-                    "description": "Last status (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped) // 上次状态 (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped)",
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
                     "type": "integer"
                 },
-                "nextRunTime": {
-                    "description": "Next run time // 下次运行时间",
+                "path": {
+                    "description": "Note path // 笔记路径",
                     "type": "string"
                 },
-                "passwordMode": {
-                    "description": "Password mode (0:None, 1:Fixed, 2:Random) // 密码模式 (0:无密码, 1:固定密码, 2:随机密码)",
-                    "type": "integer"
-                },
-                "passwordValue": {
-                    "description": "Password value for fixed mode // 固定密码值",
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
                     "type": "string"
                 },
-                "retentionDays": {
-                    "description": "Retention days // 保留天数",
-                    "type": "integer"
+                "updatedAt": {
+                    "description": "Updated at time // 更新时间"
                 },
-                "storageIds": {
-                    "description": "Storage ID list // 存储ID列表",
+                "version": {
+                    "description": "Version number // 版本号",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.PendingUserDTO": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Registration time // 注册时间",
                     "type": "string"
                 },
-                "type": {
-                    "description": "Backup type (full, incremental, sync) // 备份类型 (full, incremental, sync)",
+                "email": {
+                    "description": "Email // 邮箱",
                     "type": "string"
                 },
-                "uid": {
-                    "description": "User UID // 用户ID",
+                "id": {
+                    "description": "Pending registration ID // 待审核注册 ID",
                     "type": "integer"
                 },
-                "updatedAt": {
-                    "description": "Updated at // 更新时间",
+                "ip": {
+                    "description": "Client IP of the registration // 注册时的客户端 IP",
                     "type": "string"
                 },
-                "vault": {
-                    "description": "Associated vault name // 关联库名称",
+                "username": {
+                    "description": "Username // 用户名",
                     "type": "string"
                 }
             }
         },
-        "dto.BackupConfigRequest": {
+        "dto.PendingUserRequest": {
             "type": "object",
             "required": [
-                "cronStrategy",
-                "storageIds",
-                "type"
+                "id"
             ],
             "properties": {
-                "cronExpression": {
-                    "description": "Cron expression // Cron 表达式",
-                    "type": "string",
-                    "example": "0 0 * * *"
+                "id": {
+                    "description": "Pending registration ID // 待审核注册 ID",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.ReadOnlyRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Refuse changes // 拒绝修改",
+                    "type": "boolean"
                 },
-                "cronStrategy": {
-                    "description": "Cron strategy // 定时策略",
+                "reason": {
+                    "description": "Reason shown to the admin panel // 在管理面板显示的原因",
                     "type": "string",
-                    "enum": [
-                        "daily",
-                        "weekly",
-                        "monthly",
-                        "custom"
-                    ],
-                    "example": "daily"
+                    "maxLength": 200
+                }
+            }
+        },
+        "dto.ReadOnlyStatusDTO": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Whether changes are refused // 是否拒绝修改",
+                    "type": "boolean"
+                },
+                "holds": {
+                    "description": "Operations keeping the server read-only, e.g. upgrade // 保持只读的操作，如 upgrade",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "id": {
-                    "description": "ID // ID",
-                    "type": "integer",
-                    "example": 1
+                "manual": {
+                    "description": "Switched on by the admin // 是否由管理员开启",
+                    "type": "boolean"
                 },
-                "includeVaultName": {
-                    "description": "Include vault name // 同步路径是否包含仓库名",
-                    "type": "boolean",
-                    "example": false
+                "reason": {
+                    "description": "Reason given by the admin // 管理员填写的原因",
+                    "type": "string"
                 },
-                "isEnabled": {
-                    "description": "Is enabled // 是否启用",
-                    "type": "boolean",
-                    "example": true
+                "since": {
+                    "description": "Start of the current read-only period // 本次只读开始时间",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReindexJobDTO": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Failure reason // 失败原因",
+                    "type": "string"
                 },
-                "passwordMode": {
-                    "description": "Password mode (0:None, 1:Fixed, 2:Random) // 密码模式 (0:无密码, 1:固定密码, 2:随机密码)",
-                    "type": "integer",
-                    "example": 0
+                "finishedAt": {
+                    "description": "Finished at // 结束时间",
+                    "type": "string"
                 },
-                "passwordValue": {
-                    "description": "Password value for fixed mode // 固定密码值",
-                    "type": "string",
-                    "example": "123456"
+                "notesDone": {
+                    "description": "Notes indexed // 已索引的笔记数",
+                    "type": "integer"
                 },
-                "retentionDays": {
-                    "description": "Retention days // 保留天数",
-                    "type": "integer",
-                    "minimum": -1,
-                    "example": 7
+                "notesTotal": {
+                    "description": "Notes of the vaults started so far // 已开始重建的仓库中的笔记总数",
+                    "type": "integer"
                 },
-                "storageIds": {
-                    "description": "Storage IDs // 存储 ID 列表",
-                    "type": "string",
-                    "example": "[1, 2]"
+                "queuedAt": {
+                    "description": "Queued at // 入队时间",
+                    "type": "string"
                 },
-                "type": {
-                    "description": "Backup type // 备份类型",
-                    "type": "string",
-                    "enum": [
-                        "full",
-                        "incremental",
-                        "sync"
-                    ],
-                    "example": "sync"
+                "startedAt": {
+                    "description": "Started at // 开始时间",
+                    "type": "string"
                 },
-                "vault": {
-                    "description": "Vault name // 仓库名称",
-                    "type": "string",
-                    "example": "test"
+                "status": {
+                    "description": "queued, running, done, failed or canceled // queued、running、done、failed 或 canceled",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "User ID // 用户 ID",
+                    "type": "integer"
+                },
+                "vaultsDone": {
+                    "description": "Vaults rebuilt // 已重建的仓库数",
+                    "type": "integer"
+                },
+                "vaultsTotal": {
+                    "description": "Vaults to rebuild // 待重建的仓库数",
+                    "type": "integer"
                 }
             }
         },
-        "dto.BackupExecuteRequest": {
+        "dto.ReindexRequest": {
             "type": "object",
             "properties": {
-                "id": {
-                    "description": "ID // ID",
+                "uid": {
+                    "description": "User ID, 0 means all users when starting // 用户 ID，启动时为 0 表示全部用户",
                     "type": "integer",
                     "example": 1
                 }
             }
         },
-        "dto.BackupHistoryDTO": {
+        "dto.ScheduleDTO": {
             "type": "object",
             "properties": {
-                "configId": {
-                    "description": "Config ID // 配置ID",
-                    "type": "integer"
-                },
-                "createdAt": {
-                    "description": "Created at // 创建时间",
-                    "type": "string"
+                "custom": {
+                    "description": "Schedule set in task.schedules // 调度来自 task.schedules",
+                    "type": "boolean"
                 },
-                "endTime": {
-                    "description": "End time // 结束时间",
+                "default": {
+                    "description": "Built-in schedule, empty when the task only runs on startup or by hand // 内置调度，仅在启动时或手动执行时为空",
                     "type": "string"
                 },
-                "fileCount": {
-                    "description": "File count // 文件数量",
+                "failures": {
+                    "description": "Failed runs since the server started // 服务启动以来失败的执行次数",
                     "type": "integer"
                 },
-                "filePath": {
-                    "description": "File path // 文件路径",
-                    "type": "string"
+                "heavy": {
+                    "description": "Scheduled runs wait for the maintenance window // 定时执行等待维护窗口",
+                    "type": "boolean"
                 },
-                "fileSize": {
-                    "description": "File size // 文件大小",
+                "lastDurationMs": {
+                    "description": "Duration of the last run (ms) // 上次执行的耗时（毫秒）",
                     "type": "integer"
                 },
-                "id": {
-                    "description": "History record ID // 历史记录ID",
-                    "type": "integer"
+                "lastError": {
+                    "description": "Error of the last failed run // 上次失败执行的错误",
+                    "type": "string"
                 },
-                "message": {
-                    "description": "Result message // 结果消息",
+                "lastFinishedAt": {
+                    "description": "End of the last run // 上次执行的结束时间",
                     "type": "string"
                 },
-                "password": {
-                    "description": "Password // 密码",
+                "lastResult": {
+                    "description": "success, failed, or deferred to the maintenance window // success、failed，或 deferred（推迟到维护窗口）",
                     "type": "string"
                 },
-                "startTime": {
-                    "description": "Start time // 开始时间",
+                "lastStartedAt": {
+                    "description": "Start of the last run // 上次执行的开始时间",
                     "type": "string"
                 },
-                "status": {
-                    "description": "Status (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped) // 状态 (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped)",
-                    "type": "integer"
+                "lastTrigger": {
+                    "description": "startup, schedule or manual // startup、schedule 或 manual",
+                    "type": "string"
                 },
-                "storageId": {
-                    "description": "Storage ID // 存储ID",
-                    "type": "integer"
+                "name": {
+                    "description": "Task name // 任务名称",
+                    "type": "string"
                 },
-                "type": {
-                    "description": "Backup type // 备份类型",
+                "nextRunAt": {
+                    "description": "Next scheduled run // 下次定时执行时间",
                     "type": "string"
                 },
-                "uid": {
-                    "description": "User UID // 用户ID",
+                "running": {
+                    "description": "A run is in progress // 正在执行",
+                    "type": "boolean"
+                },
+                "runs": {
+                    "description": "Finished runs since the server started // 服务启动以来完成的执行次数",
                     "type": "integer"
                 },
-                "updatedAt": {
-                    "description": "Updated at // 更新时间",
+                "schedule": {
+                    "description": "Schedule in effect // 生效的调度",
                     "type": "string"
+                },
+                "startupRun": {
+                    "description": "Runs once when the server starts // 服务启动时执行一次",
+                    "type": "boolean"
                 }
             }
         },
-        "dto.FileDTO": {
+        "dto.ScheduleRunRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Task name // 任务名称",
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "DbCleanup"
+                }
+            }
+        },
+        "dto.ScheduleUpdateRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Task name // 任务名称",
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "DbCleanup"
+                },
+                "schedule": {
+                    "description": "Cron expression, @daily, @every 90m or off; empty restores the built-in interval // cron 表达式、@daily、@every 90m 或 off；为空时恢复内置间隔",
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "30 4 * * *"
+                }
+            }
+        },
+        "dto.SettingDTO": {
             "type": "object",
             "properties": {
+                "content": {
+                    "description": "Setting content // 配置内容",
+                    "type": "string"
+                },
                 "contentHash": {
                     "description": "Content hash // 内容哈希",
                     "type": "string"
@@ -6169,11 +17372,11 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "id": {
-                    "description": "File ID // 文件 ID",
+                    "description": "Setting ID // 配置 ID",
                     "type": "integer"
                 },
                 "lastTime": {
-                    "description": "Updated timestamp // 更新时间戳",
+                    "description": "Record update timestamp // 记录更新时间戳",
                     "type": "integer"
                 },
                 "mtime": {
@@ -6181,42 +17384,40 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "path": {
-                    "description": "File path // 文件路径",
+                    "description": "Setting path // 配置路径",
                     "type": "string"
                 },
                 "pathHash": {
-                    "description": "Path hash // 路径哈希",
+                    "description": "Path hash // 路径哈希值",
                     "type": "string"
                 },
-                "rename": {
-                    "description": "Rename flag // 重命名标记",
-                    "type": "integer"
-                },
-                "size": {
-                    "description": "File size // 文件大小",
-                    "type": "integer"
-                },
                 "updatedAt": {
                     "description": "Updated at time // 更新时间",
                     "type": "string"
                 }
             }
         },
-        "dto.FileRecycleClearRequest": {
+        "dto.SettingDeleteRequest": {
             "type": "object",
             "required": [
+                "path",
                 "vault"
             ],
             "properties": {
+                "context": {
+                    "description": "Context // 同步上下文",
+                    "type": "string",
+                    "example": "ctx123"
+                },
                 "path": {
-                    "description": "File path, empty for all // 文件路径，为空则清理全部",
+                    "description": "Setting path // 配置路径",
                     "type": "string",
-                    "example": "path/to/file.png"
+                    "example": "User/Theme"
                 },
                 "pathHash": {
                     "description": "Path hash // 路径哈希",
                     "type": "string",
-                    "example": "fhash123"
+                    "example": "hash123"
                 },
                 "vault": {
                     "description": "Vault name // 保险库名称",
@@ -6225,38 +17426,82 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.FileRenameRequest": {
+        "dto.SettingModifyOrCreateRequest": {
             "type": "object",
             "required": [
-                "oldPath",
                 "path",
                 "vault"
             ],
             "properties": {
+                "content": {
+                    "description": "Setting content // 配置内容",
+                    "type": "string",
+                    "example": "dark"
+                },
+                "contentHash": {
+                    "description": "Content hash // 内容哈希",
+                    "type": "string",
+                    "example": "chash456"
+                },
                 "context": {
                     "description": "Context // 同步上下文",
                     "type": "string",
                     "example": "ctx123"
                 },
-                "oldPath": {
-                    "description": "Old path // 旧路径",
+                "ctime": {
+                    "description": "Creation timestamp // 创建时间戳",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "mtime": {
+                    "description": "Modification timestamp // 修改时间戳",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "path": {
+                    "description": "Setting path // 配置路径",
                     "type": "string",
-                    "example": "OldImage.png"
+                    "example": "User/Theme"
                 },
-                "oldPathHash": {
-                    "description": "Old path hash // 旧路径哈希",
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
                     "type": "string",
-                    "example": "ofhash456"
+                    "example": "hash123"
                 },
-                "path": {
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string",
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.SettingRenameRequest": {
+            "type": "object",
+            "required": [
+                "newPath",
+                "oldPath",
+                "vault"
+            ],
+            "properties": {
+                "newPath": {
                     "description": "New path // 新路径",
                     "type": "string",
-                    "example": "NewImage.png"
+                    "example": "New/Path"
                 },
-                "pathHash": {
+                "newPathHash": {
                     "description": "New path hash // 新路径哈希",
                     "type": "string",
-                    "example": "nfhash123"
+                    "example": "newhash456"
+                },
+                "oldPath": {
+                    "description": "Old path // 旧路径",
+                    "type": "string",
+                    "example": "Old/Path"
+                },
+                "oldPathHash": {
+                    "description": "Old path hash // 旧路径哈希",
+                    "type": "string",
+                    "example": "oldhash123"
                 },
                 "vault": {
                     "description": "Vault name // 保险库名称",
@@ -6265,401 +17510,546 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.FileRestoreRequest": {
+        "dto.ShareCancelRequest": {
             "type": "object",
             "required": [
-                "path",
                 "vault"
             ],
             "properties": {
+                "id": {
+                    "description": "Share ID (optional) // 分享 ID (可选)",
+                    "type": "integer",
+                    "example": 1
+                },
                 "path": {
-                    "description": "File path // 文件路径",
+                    "description": "Resource path (optional) // 资源路径 (可选)",
                     "type": "string",
-                    "example": "Image.png"
+                    "example": "ReadMe.md"
                 },
                 "pathHash": {
-                    "description": "Path hash // 路径哈希",
+                    "description": "Resource path Hash (optional) // 资源路径哈希 (可选)",
                     "type": "string",
-                    "example": "fhash123"
+                    "example": "hash123"
                 },
                 "vault": {
                     "description": "Vault name // 保险库名称",
                     "type": "string",
-                    "example": "MyVault"
+                    "example": "defaultVault"
                 }
             }
         },
-        "dto.FolderCreateRequest": {
+        "dto.ShareCreateRequest": {
             "type": "object",
             "required": [
                 "path",
+                "pathHash",
                 "vault"
             ],
             "properties": {
-                "context": {
-                    "description": "Context // 同步上下文",
+                "expireAt": {
+                    "description": "Expiration timestamp (unix seconds); 0 or omitted means the share never expires // 过期时间戳（unix 秒）；0 或不传表示永久有效",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "password": {
+                    "description": "Share password // 分享密码",
                     "type": "string",
-                    "example": "ctx123"
+                    "example": "123456"
                 },
                 "path": {
-                    "description": "Folder path // 文件夹路径",
+                    "description": "Resource path // 资源路径",
                     "type": "string",
-                    "example": "NewFolder"
+                    "example": "ReadMe.md"
                 },
                 "pathHash": {
-                    "description": "Path hash // 路径哈希",
+                    "description": "Resource path Hash // 资源路径哈希",
                     "type": "string",
-                    "example": "fhash456"
+                    "example": "hash123"
                 },
                 "vault": {
                     "description": "Vault name // 保险库名称",
                     "type": "string",
-                    "example": "MyVault"
+                    "example": "defaultVault"
                 }
             }
         },
-        "dto.FolderDTO": {
+        "dto.ShareCreateResponse": {
+            "type": "object",
+            "properties": {
+                "baseUrl": {
+                    "description": "Base URL for sharing // 分享基础 URL",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "Expiration time // 过期时间",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID of the note or file table (primary resource ID) // 笔记或文件表 ID（主资源 ID）",
+                    "type": "integer"
+                },
+                "isPassword": {
+                    "description": "Whether password is set // 是否设置了密码",
+                    "type": "boolean"
+                },
+                "shortLink": {
+                    "description": "Short link // 短链",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Share Token // 分享 Token",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Resource type: note or file // 资源类型：笔记（note）或文件（file）",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ShareListItem": {
             "type": "object",
             "properties": {
+                "baseUrl": {
+                    "description": "Base URL for sharing // 分享基础 URL",
+                    "type": "string"
+                },
                 "createdAt": {
-                    "description": "Created at time // 创建时间",
+                    "description": "Created at // 创建时间",
                     "type": "string"
                 },
-                "ctime": {
-                    "description": "Creation timestamp // 创建时间戳",
+                "expiresAt": {
+                    "description": "Expiration time // 过期时间",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Share ID // 分享记录 ID",
                     "type": "integer"
                 },
-                "lastTime": {
-                    "description": "Record update timestamp // 记录更新时间戳",
+                "isPassword": {
+                    "description": "Whether password is set // 是否设置了密码",
+                    "type": "boolean"
+                },
+                "lastViewedAt": {
+                    "description": "Last viewed time // 最后访问时间",
+                    "type": "string"
+                },
+                "notePath": {
+                    "description": "Note path, for frontend share filter matching // 笔记路径，用于前端分享筛选匹配",
+                    "type": "string"
+                },
+                "res": {
+                    "description": "Authorized resources // 资源授权列表",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "shortLink": {
+                    "description": "Short link // 短链",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status: 1-Active, 2-Cancelled // 状态: 1-有效, 2-已撤销",
                     "type": "integer"
                 },
-                "mtime": {
-                    "description": "Modification timestamp // 修改时间戳",
+                "title": {
+                    "description": "Resource title (note title or file name) // 资源标题（笔记标题或文件名）",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "User ID // 用户 ID",
                     "type": "integer"
                 },
-                "path": {
-                    "description": "Folder path // 文件夹路径",
+                "updatedAt": {
+                    "description": "Updated at // 更新时间",
                     "type": "string"
                 },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希值",
+                "url": {
+                    "description": "Share URL (path format: /id/token) // 分享 URL (路径格式: /id/token)",
                     "type": "string"
                 },
-                "updatedAt": {
-                    "description": "Updated at time // 更新时间",
+                "vaultName": {
+                    "description": "Vault name where the note belongs // 笔记所属仓库名",
                     "type": "string"
+                },
+                "viewCount": {
+                    "description": "View count // 访问次数",
+                    "type": "integer"
                 }
             }
         },
-        "dto.FolderDeleteRequest": {
+        "dto.SharePasswordUpdateRequest": {
             "type": "object",
             "required": [
                 "path",
+                "pathHash",
                 "vault"
             ],
             "properties": {
-                "context": {
-                    "description": "Context // 同步上下文",
+                "expireAt": {
+                    "description": "Expiration timestamp (unix seconds); 0 or omitted means the share never expires // 过期时间戳（unix 秒）；0 或不传表示永久有效",
+                    "type": "integer",
+                    "example": 1700000000
+                },
+                "password": {
+                    "description": "New password // 新密码",
                     "type": "string",
-                    "example": "ctx123"
+                    "example": "123456"
                 },
                 "path": {
-                    "description": "Folder path // 文件夹路径",
+                    "description": "Resource path // 资源路径",
                     "type": "string",
-                    "example": "OldFolder"
+                    "example": "未命名.md"
                 },
                 "pathHash": {
-                    "description": "Path hash // 路径哈希",
+                    "description": "Resource path Hash // 资源路径哈希",
                     "type": "string",
-                    "example": "fhash789"
+                    "example": "-677306325"
                 },
                 "vault": {
                     "description": "Vault name // 保险库名称",
                     "type": "string",
-                    "example": "MyVault"
+                    "example": "test"
                 }
             }
         },
-        "dto.FolderTreeNode": {
+        "dto.ShareShortLinkCreateRequest": {
             "type": "object",
+            "required": [
+                "path",
+                "pathHash",
+                "vault"
+            ],
             "properties": {
-                "children": {
-                    "description": "Child nodes // 子节点",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.FolderTreeNode"
-                    }
+                "is_force": {
+                    "description": "Whether to force regeneration // 是否强制重新生成",
+                    "type": "boolean",
+                    "example": false
                 },
-                "fileCount": {
-                    "description": "File count // 文件数量",
-                    "type": "integer"
+                "path": {
+                    "description": "Path // 路径",
+                    "type": "string",
+                    "example": "notes/todo.md"
                 },
-                "name": {
-                    "description": "Node name // 节点名称",
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string",
+                    "example": "..."
+                },
+                "url": {
+                    "description": "Full share URL from client; if provided, used directly without regenerating token // 客户端传入的完整分享链接，非空时直接使用，不重新生成 token",
+                    "type": "string",
+                    "example": "https://example.com/share/129/CNmkmQlq0s-4elT3NuZG2w"
+                },
+                "vault": {
+                    "description": "Vault name // 库名",
+                    "type": "string",
+                    "example": "work"
+                }
+            }
+        },
+        "dto.SnapshotDTO": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Creation time // 创建时间",
                     "type": "string"
                 },
-                "noteCount": {
-                    "description": "Note count // 笔记数量",
+                "databases": {
+                    "description": "Number of database files // 数据库文件数量",
                     "type": "integer"
                 },
-                "path": {
-                    "description": "Node path // 节点路径",
+                "files": {
+                    "description": "Number of content files // 内容文件数量",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Snapshot name // 快照名称",
                     "type": "string"
+                },
+                "size": {
+                    "description": "Total size in bytes // 总大小（字节）",
+                    "type": "integer"
                 }
             }
         },
-        "dto.FolderTreeResponse": {
+        "dto.SnapshotRestoreRequest": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
-                "folders": {
-                    "description": "Folder tree // 文件夹树",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.FolderTreeNode"
-                    }
+                "name": {
+                    "description": "Snapshot name // 快照名称",
+                    "type": "string",
+                    "example": "snapshot_20260101T000000"
+                }
+            }
+        },
+        "dto.SourceProbeDTO": {
+            "description": "Probe result containing GitHub/CNB reachability, latency, recommended source and current mode",
+            "type": "object",
+            "properties": {
+                "cnb": {
+                    "description": "CNB probe result // CNB 探测结果",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.SourceProbeItem"
+                        }
+                    ]
                 },
-                "rootFileCount": {
-                    "description": "File count in root // 根目录中的文件数量",
-                    "type": "integer"
+                "github": {
+                    "description": "GitHub probe result // GitHub 探测结果",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.SourceProbeItem"
+                        }
+                    ]
                 },
-                "rootNoteCount": {
-                    "description": "Note count in root // 根目录中的笔记数量",
-                    "type": "integer"
+                "recommended": {
+                    "description": "Recommended source: \"github\" or \"cnb\" // 推荐源",
+                    "type": "string",
+                    "example": "github"
+                },
+                "selectedMode": {
+                    "description": "Current configured pull-source mode // 当前配置的选源模式",
+                    "type": "string",
+                    "example": "auto"
                 }
             }
         },
-        "dto.GitSyncCleanRequest": {
+        "dto.SourceProbeItem": {
+            "description": "Single source probe result: reachability and latency",
             "type": "object",
             "properties": {
-                "configId": {
-                    "type": "integer"
+                "latencyMs": {
+                    "description": "Round-trip latency in ms // 往返延迟（毫秒）",
+                    "type": "integer",
+                    "example": 280
+                },
+                "ok": {
+                    "description": "Whether the source is reachable // 该源是否可达",
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "dto.GitSyncConfigDTO": {
+        "dto.StorageDTO": {
             "type": "object",
             "properties": {
-                "branch": {
-                    "description": "Branch // 分支",
+                "accessKeyId": {
+                    "description": "Access key ID // 访问密钥 ID",
                     "type": "string"
                 },
-                "configSyncRules": {
-                    "description": "Config sync rules // 配置同步规则",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "accessKeySecret": {
+                    "description": "Access key secret // 访问密钥秘密",
+                    "type": "string"
+                },
+                "accessUrlPrefix": {
+                    "description": "Access URL prefix // 访问地址前缀",
+                    "type": "string"
+                },
+                "accountId": {
+                    "description": "Account ID // 账户 ID",
+                    "type": "string"
+                },
+                "bucketName": {
+                    "description": "Bucket name // 存储桶名称",
+                    "type": "string"
+                },
+                "caCert": {
+                    "description": "Custom CA bundle in PEM (S3) // 自定义 PEM CA 证书 s3",
+                    "type": "string"
                 },
                 "createdAt": {
                     "description": "Created at // 创建时间",
                     "type": "string"
                 },
-                "delay": {
-                    "description": "Delay time (seconds) // 延迟时间（秒）",
-                    "type": "integer"
+                "customPath": {
+                    "description": "Custom path // 自定义路径",
+                    "type": "string"
+                },
+                "endpoint": {
+                    "description": "Endpoint // 访问端点",
+                    "type": "string"
                 },
                 "id": {
-                    "description": "Task ID // 任务ID",
+                    "description": "ID // ID",
                     "type": "integer"
                 },
-                "includeConfig": {
-                    "description": "Include config sync // 是否开启配置同步",
+                "insecureSkipVerify": {
+                    "description": "Skip TLS certificate verification (S3) // 跳过 TLS 证书校验 s3",
                     "type": "boolean"
                 },
                 "isEnabled": {
                     "description": "Is enabled // 是否启用",
                     "type": "boolean"
                 },
-                "lastMessage": {
-                    "description": "Last run result message // 上次运行结果消息",
-                    "type": "string"
-                },
-                "lastStatus": {
-                    "description": "Last status (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Shutdown) // 上次状态 (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Shutdown)",
-                    "type": "integer"
-                },
-                "lastSyncTime": {
-                    "description": "Last sync time // 上次同步时间",
-                    "type": "string"
-                },
                 "password": {
                     "description": "Password // 密码",
                     "type": "string"
                 },
-                "repoUrl": {
-                    "description": "Repository URL // 仓库地址",
-                    "type": "string"
+                "pathStyle": {
+                    "description": "Path-style bucket addressing (S3) // 路径方式访问存储桶 s3",
+                    "type": "boolean"
                 },
-                "retentionDays": {
-                    "description": "History retention days // 历史记录保留天数",
-                    "type": "integer"
+                "region": {
+                    "description": "Region // 区域",
+                    "type": "string"
                 },
-                "uid": {
-                    "description": "User ID // 用户ID",
-                    "type": "integer"
+                "type": {
+                    "description": "Storage type // 存储类型",
+                    "type": "string"
                 },
                 "updatedAt": {
                     "description": "Updated at // 更新时间",
                     "type": "string"
                 },
-                "username": {
+                "user": {
                     "description": "Username // 用户名",
                     "type": "string"
-                },
-                "vault": {
-                    "description": "Associated vault name // 关联库名称",
-                    "type": "string"
                 }
             }
         },
-        "dto.GitSyncConfigRequest": {
+        "dto.StoragePostRequest": {
             "type": "object",
             "required": [
-                "repoUrl"
+                "accessUrlPrefix",
+                "type"
             ],
             "properties": {
-                "branch": {
-                    "type": "string"
-                },
-                "configSyncRules": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "delay": {
-                    "description": "Delay time (seconds) // 延迟时间（秒）",
-                    "type": "integer"
+                "accessKeyId": {
+                    "description": "Access key ID // 访问密钥ID",
+                    "type": "string",
+                    "example": ""
                 },
-                "id": {
-                    "type": "integer"
+                "accessKeySecret": {
+                    "description": "Access key secret // 访问密钥秘密",
+                    "type": "string",
+                    "example": ""
                 },
-                "includeConfig": {
-                    "type": "boolean"
+                "accessUrlPrefix": {
+                    "description": "Access URL prefix // 访问地址前缀",
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2,
+                    "example": "https://cdn.com"
                 },
-                "isEnabled": {
-                    "type": "boolean"
+                "accountId": {
+                    "description": "Account ID (R2) // 账户ID r2",
+                    "type": "string",
+                    "example": "123456789"
                 },
-                "password": {
-                    "type": "string"
+                "bucketName": {
+                    "description": "Bucket name // 存储桶名称",
+                    "type": "string",
+                    "example": "my-bucket"
                 },
-                "repoUrl": {
-                    "type": "string"
+                "caCert": {
+                    "description": "Custom CA bundle in PEM (S3) // 自定义 PEM CA 证书 s3",
+                    "type": "string",
+                    "maxLength": 65536,
+                    "example": ""
                 },
-                "retentionDays": {
-                    "type": "integer"
+                "customPath": {
+                    "description": "Custom path // 自定义路径",
+                    "type": "string",
+                    "example": "/backups"
                 },
-                "username": {
-                    "type": "string"
+                "endpoint": {
+                    "description": "Endpoint (OSS) // 端点 oss",
+                    "type": "string",
+                    "example": "oss-cn-hangzhou.aliyuncs.com"
                 },
-                "vault": {
-                    "description": "Associated vault name // 关联笔记本名称",
-                    "type": "string"
-                }
-            }
-        },
-        "dto.GitSyncDeleteRequest": {
-            "type": "object",
-            "required": [
-                "id"
-            ],
-            "properties": {
-                "id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "dto.GitSyncExecuteRequest": {
-            "type": "object",
-            "required": [
-                "id"
-            ],
-            "properties": {
                 "id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "dto.GitSyncHistoryDTO": {
-            "type": "object",
-            "properties": {
-                "configId": {
-                    "type": "integer"
-                },
-                "createdAt": {
-                    "type": "string"
-                },
-                "endTime": {
-                    "type": "string"
+                    "description": "ID // ID",
+                    "type": "integer",
+                    "example": 1
                 },
-                "id": {
-                    "type": "integer"
+                "insecureSkipVerify": {
+                    "description": "Skip TLS certificate verification (S3) // 跳过 TLS 证书校验 s3",
+                    "type": "integer",
+                    "enum": [
+                        0,
+                        1
+                    ],
+                    "example": 0
                 },
-                "message": {
-                    "type": "string"
+                "isEnabled": {
+                    "description": "Is enabled // 是否启用",
+                    "type": "integer",
+                    "example": 1
                 },
-                "startTime": {
-                    "type": "string"
+                "password": {
+                    "description": "Password // 密码",
+                    "type": "string",
+                    "example": "secret_password"
                 },
-                "status": {
-                    "description": "0:Idle, 1:Running, 2:Success, 3:Failed, 4:Shutdown",
-                    "type": "integer"
-                }
-            }
-        },
-        "dto.GitSyncValidateRequest": {
-            "type": "object",
-            "required": [
-                "repoUrl"
-            ],
-            "properties": {
-                "branch": {
-                    "type": "string"
+                "pathStyle": {
+                    "description": "Path-style bucket addressing (S3) // 路径方式访问存储桶 s3",
+                    "type": "integer",
+                    "enum": [
+                        0,
+                        1
+                    ],
+                    "example": 1
                 },
-                "password": {
-                    "type": "string"
+                "region": {
+                    "description": "Region (S3) // 区域 s3",
+                    "type": "string",
+                    "example": "us-east-1"
                 },
-                "repoUrl": {
-                    "type": "string"
+                "type": {
+                    "description": "Storage type // 类型",
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "local-fs"
                 },
-                "username": {
-                    "type": "string"
+                "user": {
+                    "description": "Username // 访问用户名",
+                    "type": "string",
+                    "example": "admin"
                 }
             }
         },
-        "dto.NoteAppendRequest": {
+        "dto.StorageUsageDTO": {
             "type": "object",
-            "required": [
-                "content",
-                "path",
-                "vault"
-            ],
             "properties": {
-                "content": {
-                    "description": "Content to append // 追加内容",
-                    "type": "string",
-                    "example": "Appended content"
-                },
-                "path": {
-                    "description": "Note path // 笔记路径",
-                    "type": "string",
-                    "example": "ReadMe.md"
+                "generatedAt": {
+                    "description": "Generation time // 生成时间",
+                    "type": "string"
                 },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
-                    "type": "string",
-                    "example": "hash123"
+                "totals": {
+                    "description": "Instance totals // 实例合计",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.UsageBytesDTO"
+                        }
+                    ]
                 },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
-                    "type": "string",
-                    "example": "MyVault"
+                "users": {
+                    "description": "Per user usage, largest first // 每个用户的用量，按大小倒序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserUsageDTO"
+                    }
                 }
             }
         },
-        "dto.NoteDTO": {
+        "dto.SyncClientStatusDTO": {
             "type": "object",
             "properties": {
+                "bytes": {
+                    "description": "Bytes transferred // 传输字节数",
+                    "type": "integer"
+                },
+                "changes": {
+                    "description": "Synced changes // 已同步的变更数",
+                    "type": "integer"
+                },
                 "clientName": {
                     "description": "Client name // 客户端名称",
                     "type": "string"
@@ -6669,64 +18059,40 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "clientVersion": {
-                    "description": "Client version // 客户端版本",
-                    "type": "string"
-                },
-                "content": {
-                    "description": "Note content // 笔记内容",
-                    "type": "string"
-                },
-                "contentHash": {
-                    "description": "Content hash // 内容哈希",
-                    "type": "string"
-                },
-                "createdAt": {
-                    "description": "Created at time // 创建时间",
+                    "description": "Latest client version // 最新的客户端版本",
                     "type": "string"
                 },
-                "ctime": {
-                    "description": "Creation timestamp // 创建时间戳",
-                    "type": "integer"
-                },
-                "id": {
-                    "description": "Note ID // 笔记 ID",
-                    "type": "integer"
-                },
-                "lastTime": {
-                    "description": "Record update timestamp // 记录更新时间戳",
-                    "type": "integer"
-                },
-                "mtime": {
-                    "description": "Modification timestamp // 修改时间戳",
+                "failures": {
+                    "description": "Failed syncs // 同步失败次数",
                     "type": "integer"
                 },
-                "path": {
-                    "description": "Note path // 笔记路径",
+                "lastError": {
+                    "description": "Message of the last failure // 最近一次失败的消息",
                     "type": "string"
                 },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
+                "lastErrorAt": {
+                    "description": "Last failed sync // 最近一次同步失败时间",
                     "type": "string"
                 },
-                "size": {
-                    "description": "Note size // 笔记大小",
-                    "type": "integer"
-                },
-                "updatedAt": {
-                    "description": "Updated at time // 更新时间",
+                "lastSyncAt": {
+                    "description": "Last successful sync // 最近一次成功同步时间",
                     "type": "string"
-                },
-                "version": {
-                    "description": "Version number // 版本号",
-                    "type": "integer"
                 }
             }
         },
-        "dto.NoteHistoryDTO": {
+        "dto.SyncLogDTO": {
             "type": "object",
             "properties": {
+                "action": {
+                    "description": "Action type // 操作类型",
+                    "type": "string"
+                },
+                "changedFields": {
+                    "description": "Changed fields // 变更字段",
+                    "type": "string"
+                },
                 "clientName": {
-                    "description": "Client that made changes // 产生变更的客户端",
+                    "description": "Client name // 客户端名称",
                     "type": "string"
                 },
                 "clientType": {
@@ -6737,1363 +18103,1449 @@ const docTemplate = `{
                     "description": "Client version // 客户端版本",
                     "type": "string"
                 },
-                "content": {
-                    "description": "Full historical content // 完整历史内容",
+                "createdAt": {
+                    "description": "Log creation time // 创建时间",
                     "type": "string"
                 },
-                "contentHash": {
-                    "description": "Content hash // 内容哈希",
+                "message": {
+                    "description": "Additional message // 附加消息",
                     "type": "string"
                 },
-                "createdAt": {
-                    "description": "Creation time of this version // 此版本的创建时间",
+                "path": {
+                    "description": "Resource path // 资源路径",
                     "type": "string"
                 },
-                "diffs": {
-                    "description": "Text differences // 文本差异内容",
-                    "type": "array",
-                    "items": {
-                        "type": "object"
-                    }
+                "pathHash": {
+                    "description": "Resource path hash // 路径哈希",
+                    "type": "string"
                 },
-                "id": {
-                    "description": "History entry ID // 历史项 ID",
+                "size": {
+                    "description": "Size in bytes // 大小（字节）",
                     "type": "integer"
                 },
-                "noteId": {
-                    "description": "Associated note ID // 笔记 ID",
+                "status": {
+                    "description": "Status: 1 success, 2 failed // 状态",
                     "type": "integer"
                 },
-                "path": {
-                    "description": "Note path at that time // 当时的笔记路径",
+                "type": {
+                    "description": "Resource type // 资源类型",
                     "type": "string"
                 },
                 "vaultId": {
-                    "description": "Associated vault ID // 保险库 ID",
-                    "type": "integer"
-                },
-                "version": {
-                    "description": "Historical version number // 历史版本号",
+                    "description": "Vault ID // 笔记本 ID",
                     "type": "integer"
                 }
             }
         },
-        "dto.NoteHistoryRestoreRequest": {
+        "dto.SyncStatusDTO": {
             "type": "object",
-            "required": [
-                "historyId",
-                "vault"
-            ],
             "properties": {
-                "historyId": {
-                    "description": "History version ID // 历史版本 ID",
-                    "type": "integer",
-                    "example": 1
+                "devices": {
+                    "description": "Per device status // 各设备状态",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DeviceSyncStatusDTO"
+                    }
                 },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
-                    "type": "string",
-                    "example": "MyVault"
+                "since": {
+                    "description": "Start of the covered period // 统计起始时间",
+                    "type": "string"
+                },
+                "upToDate": {
+                    "description": "No device has pending changes and no client failed its last sync // 没有设备存在待同步变更且没有客户端最近一次同步失败",
+                    "type": "boolean"
+                },
+                "vaults": {
+                    "description": "Per vault status // 各保险库状态",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VaultSyncStatusDTO"
+                    }
                 }
             }
         },
-        "dto.NoteLinkItem": {
+        "dto.SyncTraceDTO": {
             "type": "object",
             "properties": {
-                "context": {
-                    "description": "Text context around link // 链接文本上下文",
+                "expiresAt": {
+                    "description": "The trace stops by itself at this time // 跟踪在此时间自动停止",
                     "type": "string"
                 },
-                "isEmbed": {
-                    "description": "Is it an embed (![[...]]) // 是否为嵌入",
-                    "type": "boolean"
+                "file": {
+                    "description": "Trace file on the server // 服务器上的跟踪文件",
+                    "type": "string"
                 },
-                "linkText": {
-                    "description": "Raw link text (optional) // 原始链接文本（可选）",
+                "level": {
+                    "description": "Lowest level written // 写入的最低级别",
                     "type": "string"
                 },
-                "path": {
-                    "description": "Target path // 目标路径",
+                "startedAt": {
+                    "description": "Start of the trace // 跟踪开始时间",
                     "type": "string"
+                },
+                "uid": {
+                    "description": "Traced user // 被跟踪的用户",
+                    "type": "integer"
                 }
             }
         },
-        "dto.NoteModifyOrCreateRequest": {
+        "dto.SyncTraceRequest": {
             "type": "object",
             "required": [
-                "path",
-                "vault"
+                "uid"
             ],
             "properties": {
-                "baseHash": {
-                    "description": "Base hash for sync // 同步基准哈希",
-                    "type": "string",
-                    "example": "bhash789"
-                },
-                "baseHashMissing": {
-                    "description": "Marks if baseHash is unavailable // 标记基准哈希是否缺失",
-                    "type": "boolean",
-                    "example": false
-                },
-                "content": {
-                    "description": "Note content // 笔记内容",
-                    "type": "string",
-                    "example": "# Hello World"
-                },
-                "contentHash": {
-                    "description": "Content hash // 内容哈希",
-                    "type": "string",
-                    "example": "chash012"
-                },
-                "context": {
-                    "description": "Context // 同步上下文",
+                "level": {
+                    "description": "info records messages, decisions and broadcasts, debug adds their payloads, default debug // info 记录消息、判定与广播，debug 额外记录其内容，默认 debug",
                     "type": "string",
-                    "example": "ctx123"
-                },
-                "createOnly": {
-                    "description": "If true, fail if note already exists // 如果为 true，笔记已存在则失败",
-                    "type": "boolean",
-                    "example": false
+                    "enum": [
+                        "debug",
+                        "info"
+                    ],
+                    "example": "debug"
                 },
-                "ctime": {
-                    "description": "Creation timestamp // 创建时间戳",
+                "minutes": {
+                    "description": "Minutes until the trace stops by itself, default 30 // 跟踪自动停止前的分钟数，默认 30",
                     "type": "integer",
-                    "example": 1700000000
+                    "maximum": 1440,
+                    "minimum": 1,
+                    "example": 30
                 },
-                "mtime": {
-                    "description": "Modification timestamp // 修改时间戳",
+                "uid": {
+                    "description": "Traced user // 被跟踪的用户",
                     "type": "integer",
-                    "example": 1700000000
-                },
-                "path": {
-                    "description": "Note path // 笔记路径",
-                    "type": "string",
-                    "example": "ReadMe.md"
-                },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
-                    "type": "string",
-                    "example": "hash123"
-                },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
+                    "minimum": 1,
+                    "example": 2
+                }
+            }
+        },
+        "dto.TwoFactorCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "TOTP or recovery code // 动态码或恢复码",
                     "type": "string",
-                    "example": "MyVault"
+                    "example": "123456"
                 }
             }
         },
-        "dto.NoteNoContentDTO": {
+        "dto.TwoFactorEnableDTO": {
             "type": "object",
             "properties": {
-                "clientName": {
-                    "description": "Client name // 客户端名称",
-                    "type": "string"
-                },
-                "clientType": {
-                    "description": "Client type // 客户端类型",
-                    "type": "string"
-                },
-                "clientVersion": {
-                    "description": "Client version // 客户端版本",
+                "recoveryCodes": {
+                    "description": "One-time recovery codes, shown only once // 一次性恢复码，仅显示一次",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.TwoFactorSetupDTO": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "description": "Base32 secret for manual entry // 供手动输入的 Base32 密钥",
                     "type": "string"
                 },
-                "createdAt": {
-                    "description": "Created at time // 创建时间",
+                "uri": {
+                    "description": "otpauth:// URI to render as QR code // 用于生成二维码的 otpauth:// URI",
                     "type": "string"
+                }
+            }
+        },
+        "dto.TwoFactorStatusDTO": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Whether 2FA is enabled // 是否已启用两步验证",
+                    "type": "boolean"
                 },
-                "ctime": {
-                    "description": "Creation timestamp // 创建时间戳",
+                "recoveryCodesRemaining": {
+                    "description": "Unused recovery codes // 剩余可用恢复码数量",
                     "type": "integer"
                 },
-                "id": {
-                    "description": "Note ID // 笔记 ID",
+                "required": {
+                    "description": "Whether 2FA is required for this account // 当前账号是否被要求启用两步验证",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.UsageBytesDTO": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "description": "Attachments // 附件",
                     "type": "integer"
                 },
-                "lastTime": {
-                    "description": "Record update timestamp // 记录更新时间戳",
+                "backupTemp": {
+                    "description": "In-progress backups and cached exports in the temp path // 临时目录中进行中的备份与缓存的导出",
                     "type": "integer"
                 },
-                "mtime": {
-                    "description": "Modification timestamp // 修改时间戳",
+                "database": {
+                    "description": "SQLite database files, 0 for other database types // SQLite 数据库文件，其他数据库类型为 0",
                     "type": "integer"
                 },
-                "path": {
-                    "description": "Note path // 笔记路径",
-                    "type": "string"
-                },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
-                    "type": "string"
+                "history": {
+                    "description": "History snapshots and diffs // 历史快照与差异",
+                    "type": "integer"
                 },
-                "size": {
-                    "description": "Note size // 笔记大小",
+                "notes": {
+                    "description": "Note content files // 笔记内容文件",
                     "type": "integer"
                 },
-                "updatedAt": {
-                    "description": "Updated at time // 更新时间",
-                    "type": "string"
+                "settings": {
+                    "description": "Setting content files // 配置内容文件",
+                    "type": "integer"
                 },
-                "version": {
-                    "description": "Version number // 版本号",
+                "total": {
+                    "description": "Sum of all the above // 以上各项合计",
                     "type": "integer"
                 }
             }
         },
-        "dto.NotePatchFrontmatterRequest": {
+        "dto.UserCapabilitiesDTO": {
             "type": "object",
-            "required": [
-                "path",
-                "vault"
-            ],
             "properties": {
-                "path": {
-                    "description": "Note path // 笔记路径",
-                    "type": "string",
-                    "example": "ReadMe.md"
-                },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
-                    "type": "string",
-                    "example": "hash123"
-                },
-                "remove": {
-                    "description": "Fields to remove // 待移除字段",
+                "enabled": {
+                    "description": "Names of the features that are on, sorted // 已开启功能的名称，已排序",
                     "type": "array",
                     "items": {
                         "type": "string"
-                    },
-                    "example": [
-                        "old_tag"
-                    ]
+                    }
                 },
-                "updates": {
-                    "description": "Fields to update // 待更新字段",
+                "features": {
+                    "description": "Every known feature and whether it is on // 所有已知功能及其是否开启",
                     "type": "object",
                     "additionalProperties": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
+                        "type": "boolean"
                     }
                 },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
-                    "type": "string",
-                    "example": "MyVault"
-                }
-            }
-        },
-        "dto.NotePrependRequest": {
-            "type": "object",
-            "required": [
-                "content",
-                "path",
-                "vault"
-            ],
-            "properties": {
-                "content": {
-                    "description": "Content to prepend // 头部添加内容",
-                    "type": "string",
-                    "example": "Prepended content\n"
-                },
-                "path": {
-                    "description": "Note path // 笔记路径",
-                    "type": "string",
-                    "example": "ReadMe.md"
-                },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
-                    "type": "string",
-                    "example": "hash123"
-                },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
-                    "type": "string",
-                    "example": "MyVault"
+                "uid": {
+                    "description": "User ID // 用户 ID",
+                    "type": "integer"
                 }
             }
         },
-        "dto.NoteRecycleClearRequest": {
+        "dto.UserChangePasswordRequest": {
             "type": "object",
             "required": [
-                "vault"
+                "confirmPassword",
+                "oldPassword",
+                "password"
             ],
             "properties": {
-                "path": {
-                    "description": "Note path, empty for all // 笔记路径，为空则清理全部",
+                "confirmPassword": {
+                    "description": "Confirm password // 校验密码",
                     "type": "string",
-                    "example": "path/to/note.md"
+                    "example": "new_password123"
                 },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
+                "oldPassword": {
+                    "description": "Old password // 旧密码",
                     "type": "string",
-                    "example": "hash123"
+                    "example": "old_password123"
                 },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
+                "password": {
+                    "description": "New password // 新密码",
                     "type": "string",
-                    "example": "MyVault"
+                    "example": "new_password123"
                 }
             }
         },
-        "dto.NoteRenameRequest": {
+        "dto.UserCreateRequest": {
             "type": "object",
             "required": [
-                "oldPath",
-                "path",
-                "vault"
+                "confirmPassword",
+                "email",
+                "password",
+                "username"
             ],
             "properties": {
-                "context": {
-                    "description": "Context // 同步上下文",
-                    "type": "string",
-                    "example": "ctx123"
-                },
-                "oldPath": {
-                    "description": "Old path // 旧路径",
+                "confirmPassword": {
+                    "description": "Confirm password // 校验密码",
                     "type": "string",
-                    "example": "OldName.md"
+                    "example": "password123"
                 },
-                "oldPathHash": {
-                    "description": "Old path hash // 旧路径哈希",
+                "email": {
+                    "description": "User email // 用户邮件",
                     "type": "string",
-                    "example": "ohash456"
+                    "example": "user@example.com"
                 },
-                "path": {
-                    "description": "New path // 新路径",
+                "inviteCode": {
+                    "description": "Invitation code, admits the registration while open registration is off // 邀请码，开放注册关闭时也可注册",
                     "type": "string",
-                    "example": "NewName.md"
+                    "maxLength": 64,
+                    "example": "x7Kp2mQa"
                 },
-                "pathHash": {
-                    "description": "New path hash // 新路径哈希",
+                "password": {
+                    "description": "User password // 用户密码",
                     "type": "string",
-                    "example": "nhash123"
+                    "example": "password123"
                 },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
+                "username": {
+                    "description": "User name // 用户名",
                     "type": "string",
-                    "example": "MyVault"
+                    "example": "username123"
                 }
             }
         },
-        "dto.NoteReplaceRequest": {
+        "dto.UserDTO": {
             "type": "object",
-            "required": [
-                "find",
-                "path",
-                "vault"
-            ],
             "properties": {
-                "all": {
-                    "description": "Replace all matches // 替换所有",
-                    "type": "boolean",
-                    "example": true
-                },
-                "failIfNoMatch": {
-                    "description": "Fail if no match found // 若无匹配则失败",
-                    "type": "boolean",
-                    "example": true
-                },
-                "find": {
-                    "description": "String to find // 查找内容",
-                    "type": "string",
-                    "example": "old text"
+                "avatar": {
+                    "description": "Avatar URL or handle // 头像路径或名称",
+                    "type": "string"
                 },
-                "path": {
-                    "description": "Note path // 笔记路径",
-                    "type": "string",
-                    "example": "ReadMe.md"
+                "createdAt": {
+                    "description": "Account created time // 账号创建时间",
+                    "type": "string"
                 },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
-                    "type": "string",
-                    "example": "hash123"
+                "email": {
+                    "description": "Email address // 邮件地址",
+                    "type": "string"
                 },
-                "regex": {
-                    "description": "Use regex // 使用正则",
-                    "type": "boolean",
-                    "example": false
+                "isDeleted": {
+                    "description": "User is blocked",
+                    "type": "boolean"
                 },
-                "replace": {
-                    "description": "String to replace with // 替换内容",
-                    "type": "string",
-                    "example": "new text"
+                "token": {
+                    "description": "Authentication Token // 认证 Token",
+                    "type": "string"
                 },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
-                    "type": "string",
-                    "example": "MyVault"
-                }
-            }
-        },
-        "dto.NoteReplaceResponse": {
-            "type": "object",
-            "properties": {
-                "matchCount": {
-                    "description": "Number of matches found // 匹配数量",
+                "tokenId": {
+                    "description": "Authentication Token ID // 认证 Token ID",
                     "type": "integer"
                 },
-                "note": {
-                    "description": "Updated note data // 更新后的笔记数据",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/dto.NoteDTO"
-                        }
-                    ]
-                }
-            }
-        },
-        "dto.NoteRestoreRequest": {
-            "type": "object",
-            "required": [
-                "path",
-                "vault"
-            ],
-            "properties": {
-                "path": {
-                    "description": "Note path // 笔记路径",
-                    "type": "string",
-                    "example": "ReadMe.md"
-                },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
-                    "type": "string",
-                    "example": "hash123"
+                "totpSetupRequired": {
+                    "description": "Admin must enroll 2FA, the token only reaches /api/user/totp until the next login // 管理员需要设置两步验证，重新登录前 Token 仅能访问 /api/user/totp",
+                    "type": "boolean"
                 },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
-                    "type": "string",
-                    "example": "MyVault"
+                "uid": {
+                    "description": "User ID (primary key) // 用户唯一标识（主键）",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "description": "Last updated time // 最后更新时间",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Username // 用户名",
+                    "type": "string"
                 }
             }
         },
-        "dto.NoteWithFileLinksResponse": {
+        "dto.UserHeatmapDTO": {
             "type": "object",
             "properties": {
-                "content": {
-                    "description": "Note content // 笔记内容",
-                    "type": "string"
-                },
-                "contentHash": {
-                    "description": "Content hash // 内容哈希",
-                    "type": "string"
-                },
-                "createdAt": {
-                    "description": "Created at time // 创建时间"
+                "activeDays": {
+                    "description": "Days with at least one edit // 至少有一次编辑的天数",
+                    "type": "integer"
                 },
-                "ctime": {
-                    "description": "Creation timestamp // 创建时间戳",
+                "currentStreak": {
+                    "description": "Consecutive active days up to today, or up to yesterday while today has no edit yet // 截至今天连续活跃的天数，今天尚无编辑时截至昨天",
                     "type": "integer"
                 },
-                "fileLinks": {
-                    "description": "Map of file link to actual path // 文件链接到实际路径的映射",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
+                "days": {
+                    "description": "One entry per day, oldest first // 每天一项，按日期升序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserHeatmapDayDTO"
                     }
                 },
-                "lastTime": {
-                    "description": "Record update timestamp // 记录更新时间戳",
+                "longestStreak": {
+                    "description": "Longest run of consecutive active days in the range // 范围内最长的连续活跃天数",
                     "type": "integer"
                 },
-                "mtime": {
-                    "description": "Modification timestamp // 修改时间戳",
+                "maxCount": {
+                    "description": "Edits on the busiest day // 最忙一天的编辑次数",
                     "type": "integer"
                 },
-                "path": {
-                    "description": "Note path // 笔记路径",
-                    "type": "string"
-                },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
-                    "type": "string"
+                "total": {
+                    "description": "Edits in the range // 范围内的编辑次数",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.UserHeatmapDayDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "Saved note edits // 保存的笔记编辑次数",
+                    "type": "integer"
                 },
-                "updatedAt": {
-                    "description": "Updated at time // 更新时间"
+                "date": {
+                    "description": "Day in server local time // 服务器本地时间的日期",
+                    "type": "string",
+                    "example": "2024-01-02"
                 },
-                "version": {
-                    "description": "Version number // 版本号",
+                "level": {
+                    "description": "Intensity from 0 (none) to 4 (busiest), relative to maxCount // 强度，0（无）到 4（最忙），相对于 maxCount",
                     "type": "integer"
                 }
             }
         },
-        "dto.SettingDTO": {
+        "dto.UserInviteCreateRequest": {
             "type": "object",
             "properties": {
-                "content": {
-                    "description": "Setting content // 配置内容",
-                    "type": "string"
+                "expiresIn": {
+                    "description": "Validity (e.g. 24h, 7d), empty never expires // 有效期（如 24h、7d），为空表示永不过期",
+                    "type": "string",
+                    "maxLength": 16,
+                    "example": "7d"
                 },
-                "contentHash": {
-                    "description": "Content hash // 内容哈希",
+                "maxUses": {
+                    "description": "Registrations allowed, 0 means unlimited // 允许注册的次数，0 表示不限",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 5
+                },
+                "note": {
+                    "description": "Admin note // 管理员备注",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "for Bob"
+                }
+            }
+        },
+        "dto.UserInviteDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Invitation code // 邀请码",
                     "type": "string"
                 },
                 "createdAt": {
-                    "description": "Created at time // 创建时间",
+                    "description": "Creation time // 创建时间",
                     "type": "string"
                 },
-                "ctime": {
-                    "description": "Creation timestamp // 创建时间戳",
+                "createdBy": {
+                    "description": "UID of the creating admin // 创建者 UID",
                     "type": "integer"
                 },
-                "id": {
-                    "description": "Setting ID // 配置 ID",
-                    "type": "integer"
+                "expiresAt": {
+                    "description": "Expiry, absent never expires // 过期时间，缺省表示永不过期",
+                    "type": "string"
                 },
-                "lastTime": {
-                    "description": "Record update timestamp // 记录更新时间戳",
+                "id": {
+                    "description": "Invitation ID // 邀请码 ID",
                     "type": "integer"
                 },
-                "mtime": {
-                    "description": "Modification timestamp // 修改时间戳",
+                "maxUses": {
+                    "description": "Registrations allowed, 0 means unlimited // 允许注册的次数，0 表示不限",
                     "type": "integer"
                 },
-                "path": {
-                    "description": "Setting path // 配置路径",
+                "note": {
+                    "description": "Admin note // 管理员备注",
                     "type": "string"
                 },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希值",
-                    "type": "string"
+                "usable": {
+                    "description": "Whether it still admits registrations // 是否仍可用于注册",
+                    "type": "boolean"
                 },
-                "updatedAt": {
-                    "description": "Updated at time // 更新时间",
-                    "type": "string"
+                "uses": {
+                    "description": "Registrations so far // 已注册次数",
+                    "type": "integer"
                 }
             }
         },
-        "dto.SettingDeleteRequest": {
+        "dto.UserLoginRequest": {
             "type": "object",
             "required": [
-                "path",
-                "vault"
+                "credentials",
+                "password"
             ],
             "properties": {
-                "context": {
-                    "description": "Context // 同步上下文",
+                "captchaToken": {
+                    "description": "Captcha response, required after repeated failed logins // 验证码响应，多次登录失败后必填",
                     "type": "string",
-                    "example": "ctx123"
+                    "example": "0.xxxx"
                 },
-                "path": {
-                    "description": "Setting path // 配置路径",
+                "credentials": {
+                    "description": "Username or Email // 登录凭证（用户名或邮件）",
                     "type": "string",
-                    "example": "User/Theme"
+                    "example": "user@example.com"
                 },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
+                "password": {
+                    "description": "Password // 密码",
                     "type": "string",
-                    "example": "hash123"
+                    "example": "password123"
                 },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
+                "tokenId": {
+                    "description": "Last token ID for rotation // 最后一个用于轮转的令牌ID",
+                    "type": "integer",
+                    "example": 123
+                },
+                "totpCode": {
+                    "description": "TOTP or recovery code when 2FA is enabled // 启用两步验证时的动态码或恢复码",
                     "type": "string",
-                    "example": "MyVault"
+                    "example": "123456"
                 }
             }
         },
-        "dto.SettingModifyOrCreateRequest": {
+        "dto.UserPasswordResetSendRequest": {
             "type": "object",
             "required": [
-                "path",
-                "vault"
+                "email"
             ],
             "properties": {
-                "content": {
-                    "description": "Setting content // 配置内容",
-                    "type": "string",
-                    "example": "dark"
-                },
-                "contentHash": {
-                    "description": "Content hash // 内容哈希",
-                    "type": "string",
-                    "example": "chash456"
-                },
-                "context": {
-                    "description": "Context // 同步上下文",
-                    "type": "string",
-                    "example": "ctx123"
-                },
-                "ctime": {
-                    "description": "Creation timestamp // 创建时间戳",
-                    "type": "integer",
-                    "example": 1700000000
-                },
-                "mtime": {
-                    "description": "Modification timestamp // 修改时间戳",
-                    "type": "integer",
-                    "example": 1700000000
-                },
-                "path": {
-                    "description": "Setting path // 配置路径",
-                    "type": "string",
-                    "example": "User/Theme"
-                },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
-                    "type": "string",
-                    "example": "hash123"
-                },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
+                "email": {
+                    "description": "Account email // 账号邮箱",
                     "type": "string",
-                    "example": "MyVault"
+                    "example": "user@example.com"
                 }
             }
         },
-        "dto.SettingRenameRequest": {
+        "dto.UserResetPasswordRequest": {
             "type": "object",
             "required": [
-                "newPath",
-                "oldPath",
-                "vault"
+                "confirmPassword",
+                "password",
+                "token"
             ],
             "properties": {
-                "newPath": {
-                    "description": "New path // 新路径",
+                "confirmPassword": {
+                    "description": "Confirm password // 校验密码",
                     "type": "string",
-                    "example": "New/Path"
+                    "example": "new_password123"
                 },
-                "newPathHash": {
-                    "description": "New path hash // 新路径哈希",
+                "password": {
+                    "description": "New password // 新密码",
                     "type": "string",
-                    "example": "newhash456"
+                    "example": "new_password123"
                 },
-                "oldPath": {
-                    "description": "Old path // 旧路径",
-                    "type": "string",
-                    "example": "Old/Path"
+                "token": {
+                    "description": "Token of the reset link // 重置链接中的令牌",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.UserSettingDTO": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "description": "Whether the setting was deleted // 是否已删除",
+                    "type": "boolean"
                 },
-                "oldPathHash": {
-                    "description": "Old path hash // 旧路径哈希",
-                    "type": "string",
-                    "example": "oldhash123"
+                "key": {
+                    "description": "Setting key // 设置键",
+                    "type": "string"
                 },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
-                    "type": "string",
-                    "example": "MyVault"
+                "lastTime": {
+                    "description": "Server update timestamp, the sync cursor // 服务端更新时间戳，即同步游标",
+                    "type": "integer"
+                },
+                "mtime": {
+                    "description": "Client modification time in milliseconds // 客户端修改时间（毫秒）",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "description": "Update time // 更新时间",
+                    "type": "string"
+                },
+                "value": {
+                    "description": "Setting value // 设置值",
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Current version // 当前版本",
+                    "type": "integer"
                 }
             }
         },
-        "dto.ShareCancelRequest": {
+        "dto.UserSettingDeleteRequest": {
             "type": "object",
             "required": [
-                "vault"
+                "key"
             ],
             "properties": {
-                "id": {
-                    "description": "Share ID (optional) // 分享 ID (可选)",
-                    "type": "integer",
-                    "example": 1
-                },
-                "path": {
-                    "description": "Resource path (optional) // 资源路径 (可选)",
+                "key": {
+                    "description": "Setting key // 设置键",
                     "type": "string",
-                    "example": "ReadMe.md"
+                    "maxLength": 255,
+                    "example": "editor.theme"
                 },
-                "pathHash": {
-                    "description": "Resource path Hash (optional) // 资源路径哈希 (可选)",
-                    "type": "string",
-                    "example": "hash123"
+                "mtime": {
+                    "description": "Client modification time in milliseconds, 0 uses the server time // 客户端修改时间（毫秒），0 使用服务端时间",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1700000000000
                 },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
-                    "type": "string",
-                    "example": "defaultVault"
+                "version": {
+                    "description": "Version the deletion is based on // 删除所基于的版本",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
                 }
             }
         },
-        "dto.ShareCreateRequest": {
+        "dto.UserSettingPutRequest": {
             "type": "object",
             "required": [
-                "path",
-                "pathHash",
-                "vault"
+                "key"
             ],
             "properties": {
-                "expireAt": {
-                    "description": "Expiration timestamp (unix seconds); 0 or omitted means the share never expires // 过期时间戳（unix 秒）；0 或不传表示永久有效",
-                    "type": "integer",
-                    "example": 1700000000
-                },
-                "password": {
-                    "description": "Share password // 分享密码",
+                "key": {
+                    "description": "Setting key // 设置键",
                     "type": "string",
-                    "example": "123456"
+                    "maxLength": 255,
+                    "example": "editor.theme"
                 },
-                "path": {
-                    "description": "Resource path // 资源路径",
-                    "type": "string",
-                    "example": "ReadMe.md"
+                "mtime": {
+                    "description": "Client modification time in milliseconds, 0 uses the server time // 客户端修改时间（毫秒），0 使用服务端时间",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1700000000000
                 },
-                "pathHash": {
-                    "description": "Resource path Hash // 资源路径哈希",
+                "value": {
+                    "description": "Setting value, opaque to the server // 设置值，服务端不解析",
                     "type": "string",
-                    "example": "hash123"
+                    "maxLength": 1048576,
+                    "example": "dark"
                 },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
-                    "type": "string",
-                    "example": "defaultVault"
+                "version": {
+                    "description": "Version the change is based on, 0 for a new key // 修改所基于的版本，新键为 0",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 3
                 }
             }
         },
-        "dto.ShareCreateResponse": {
+        "dto.UserSettingsDTO": {
             "type": "object",
             "properties": {
-                "baseUrl": {
-                    "description": "Base URL for sharing // 分享基础 URL",
-                    "type": "string"
-                },
-                "expiresAt": {
-                    "description": "Expiration time // 过期时间",
-                    "type": "string"
-                },
-                "id": {
-                    "description": "ID of the note or file table (primary resource ID) // 笔记或文件表 ID（主资源 ID）",
-                    "type": "integer"
-                },
-                "isPassword": {
-                    "description": "Whether password is set // 是否设置了密码",
-                    "type": "boolean"
-                },
-                "shortLink": {
-                    "description": "Short link // 短链",
+                "defaultSoftDeleteRetentionTime": {
+                    "description": "Server default soft delete retention, 0 keeps deleted items forever // 服务器默认软删除保留时间，0 表示永久保留",
                     "type": "string"
                 },
-                "token": {
-                    "description": "Share Token // 分享 Token",
+                "effectiveSoftDeleteRetentionTime": {
+                    "description": "Retention applied to vaults without their own override // 未单独设置的保险库实际使用的保留时间",
                     "type": "string"
                 },
-                "type": {
-                    "description": "Resource type: note or file // 资源类型：笔记（note）或文件（file）",
+                "softDeleteRetentionTime": {
+                    "description": "User-wide soft delete retention, empty uses the server default // 用户级软删除保留时间，为空使用服务器默认值",
                     "type": "string"
                 }
             }
         },
-        "dto.ShareListItem": {
+        "dto.UserSettingsRequest": {
             "type": "object",
             "properties": {
-                "baseUrl": {
-                    "description": "Base URL for sharing // 分享基础 URL",
-                    "type": "string"
-                },
-                "createdAt": {
-                    "description": "Created at // 创建时间",
-                    "type": "string"
-                },
-                "expiresAt": {
-                    "description": "Expiration time // 过期时间",
+                "softDeleteRetentionTime": {
+                    "description": "Soft delete retention like 30d or 12h, 0 keeps deleted items forever, empty uses the server default // 软删除保留时间，如 30d、12h，0 表示永久保留，为空使用服务器默认值",
+                    "type": "string",
+                    "example": "30d"
+                }
+            }
+        },
+        "dto.UserSettingsSyncMessage": {
+            "type": "object",
+            "properties": {
+                "context": {
+                    "description": "Context of the request, empty for pushes // 请求的上下文，推送时为空",
                     "type": "string"
                 },
-                "id": {
-                    "description": "Share ID // 分享记录 ID",
+                "lastTime": {
+                    "description": "Cursor to send with the next SettingsSync request, 0 in pushes // 下次 SettingsSync 请求应携带的游标，推送时为 0",
                     "type": "integer"
                 },
-                "isPassword": {
-                    "description": "Whether password is set // 是否设置了密码",
-                    "type": "boolean"
-                },
-                "lastViewedAt": {
-                    "description": "Last viewed time // 最后访问时间",
-                    "type": "string"
-                },
-                "notePath": {
-                    "description": "Note path, for frontend share filter matching // 笔记路径，用于前端分享筛选匹配",
-                    "type": "string"
-                },
-                "res": {
-                    "description": "Authorized resources // 资源授权列表",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        }
+                "settings": {
+                    "description": "Changed settings, deletions included // 变更的设置，包含删除",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserSettingDTO"
                     }
+                }
+            }
+        },
+        "dto.UserUpdateRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "uid",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "description": "User email // 用户邮件",
+                    "type": "string",
+                    "example": "user@example.com"
                 },
-                "shortLink": {
-                    "description": "Short link // 短链",
-                    "type": "string"
+                "isDeleted": {
+                    "description": "User deleted flag",
+                    "type": "boolean",
+                    "example": true
                 },
-                "status": {
-                    "description": "Status: 1-Active, 2-Cancelled // 状态: 1-有效, 2-已撤销",
-                    "type": "integer"
+                "password": {
+                    "description": "User password // 用户密码",
+                    "type": "string",
+                    "example": "password123"
                 },
-                "title": {
-                    "description": "Resource title (note title or file name) // 资源标题（笔记标题或文件名）",
-                    "type": "string"
+                "uid": {
+                    "description": "User ID (primary key) // 用户唯一标识（主键）",
+                    "type": "integer",
+                    "example": 123
                 },
+                "username": {
+                    "description": "User name // 用户名",
+                    "type": "string",
+                    "example": "username123"
+                }
+            }
+        },
+        "dto.UserUsageDTO": {
+            "type": "object",
+            "properties": {
                 "uid": {
                     "description": "User ID // 用户 ID",
                     "type": "integer"
                 },
-                "updatedAt": {
-                    "description": "Updated at // 更新时间",
-                    "type": "string"
-                },
-                "url": {
-                    "description": "Share URL (path format: /id/token) // 分享 URL (路径格式: /id/token)",
-                    "type": "string"
+                "usage": {
+                    "description": "On-disk bytes by kind // 按类型统计的磁盘字节数",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.UsageBytesDTO"
+                        }
+                    ]
                 },
-                "vaultName": {
-                    "description": "Vault name where the note belongs // 笔记所属仓库名",
+                "username": {
+                    "description": "Username // 用户名",
                     "type": "string"
                 },
-                "viewCount": {
-                    "description": "View count // 访问次数",
-                    "type": "integer"
+                "vaults": {
+                    "description": "Per vault content size // 各仓库的内容大小",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VaultUsageDTO"
+                    }
                 }
             }
         },
-        "dto.SharePasswordUpdateRequest": {
+        "dto.VaultActivityDTO": {
             "type": "object",
-            "required": [
-                "path",
-                "pathHash",
-                "vault"
-            ],
             "properties": {
-                "expireAt": {
-                    "description": "Expiration timestamp (unix seconds); 0 or omitted means the share never expires // 过期时间戳（unix 秒）；0 或不传表示永久有效",
-                    "type": "integer",
-                    "example": 1700000000
+                "days": {
+                    "description": "Days of the page, newest first // 本页包含的日期，最新的排在前面",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VaultActivityDayDTO"
+                    }
                 },
-                "password": {
-                    "description": "New password // 新密码",
-                    "type": "string",
-                    "example": "123456"
+                "hasMore": {
+                    "description": "Whether older events follow // 是否还有更早的事件",
+                    "type": "boolean"
                 },
-                "path": {
-                    "description": "Resource path // 资源路径",
-                    "type": "string",
-                    "example": "未命名.md"
+                "page": {
+                    "description": "Page number // 页码",
+                    "type": "integer"
                 },
-                "pathHash": {
-                    "description": "Resource path Hash // 资源路径哈希",
+                "pageSize": {
+                    "description": "Events per page // 每页事件数",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.VaultActivityDayDTO": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "Day in server local time // 服务器本地时间的日期",
                     "type": "string",
-                    "example": "-677306325"
+                    "example": "2024-01-02"
                 },
-                "vault": {
-                    "description": "Vault name // 保险库名称",
-                    "type": "string",
-                    "example": "test"
+                "items": {
+                    "description": "Changes of the day // 当天的变更",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VaultActivityItemDTO"
+                    }
                 }
             }
         },
-        "dto.ShareShortLinkCreateRequest": {
+        "dto.VaultActivityItemDTO": {
             "type": "object",
-            "required": [
-                "path",
-                "pathHash",
-                "vault"
-            ],
             "properties": {
-                "is_force": {
-                    "description": "Whether to force regeneration // 是否强制重新生成",
-                    "type": "boolean",
-                    "example": false
+                "client": {
+                    "description": "Client that made the change, when known // 做出变更的客户端（已知时）",
+                    "type": "string"
                 },
-                "path": {
-                    "description": "Path // 路径",
+                "kind": {
+                    "description": "Change: create, modify, upload, rename, delete or restore // 变更类型：create、modify、upload、rename、delete 或 restore",
                     "type": "string",
-                    "example": "notes/todo.md"
+                    "example": "modify"
                 },
-                "pathHash": {
-                    "description": "Path hash // 路径哈希",
-                    "type": "string",
-                    "example": "..."
+                "oldPath": {
+                    "description": "Path before a rename // 重命名前的路径",
+                    "type": "string"
                 },
-                "url": {
-                    "description": "Full share URL from client; if provided, used directly without regenerating token // 客户端传入的完整分享链接，非空时直接使用，不重新生成 token",
+                "path": {
+                    "description": "Item path, empty for a rename whose new path changed since // 条目路径，重命名后新路径又发生变更时为空",
                     "type": "string",
-                    "example": "https://example.com/share/129/CNmkmQlq0s-4elT3NuZG2w"
+                    "example": "Daily/a.md"
                 },
-                "vault": {
-                    "description": "Vault name // 库名",
-                    "type": "string",
-                    "example": "work"
-                }
-            }
-        },
-        "dto.SourceProbeDTO": {
-            "description": "Probe result containing GitHub/CNB reachability, latency, recommended source and current mode",
-            "type": "object",
-            "properties": {
-                "cnb": {
-                    "description": "CNB probe result // CNB 探测结果",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/dto.SourceProbeItem"
-                        }
-                    ]
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string"
                 },
-                "github": {
-                    "description": "GitHub probe result // GitHub 探测结果",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/dto.SourceProbeItem"
-                        }
-                    ]
+                "size": {
+                    "description": "Size in bytes, 0 when unknown // 大小（字节），未知时为 0",
+                    "type": "integer"
                 },
-                "recommended": {
-                    "description": "Recommended source: \"github\" or \"cnb\" // 推荐源",
-                    "type": "string",
-                    "example": "github"
+                "timestamp": {
+                    "description": "Change time in milliseconds // 变更时间（毫秒）",
+                    "type": "integer",
+                    "example": 1700000000000
                 },
-                "selectedMode": {
-                    "description": "Current configured pull-source mode // 当前配置的选源模式",
+                "type": {
+                    "description": "Item type: note or file // 条目类型：note 或 file",
                     "type": "string",
-                    "example": "auto"
+                    "example": "note"
                 }
             }
         },
-        "dto.SourceProbeItem": {
-            "description": "Single source probe result: reachability and latency",
+        "dto.VaultAsOfItemDTO": {
             "type": "object",
             "properties": {
-                "latencyMs": {
-                    "description": "Round-trip latency in ms // 往返延迟（毫秒）",
-                    "type": "integer",
-                    "example": 280
+                "changed": {
+                    "description": "Modified or deleted since then // 此后是否被修改或删除",
+                    "type": "boolean"
                 },
-                "ok": {
-                    "description": "Whether the source is reachable // 该源是否可达",
-                    "type": "boolean",
-                    "example": true
+                "deleted": {
+                    "description": "Deleted since then // 此后是否已被删除",
+                    "type": "boolean"
+                },
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string",
+                    "example": "Daily/a.md"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string"
                 }
             }
         },
-        "dto.StorageDTO": {
+        "dto.VaultAsOfNoteDTO": {
             "type": "object",
             "properties": {
-                "accessKeyId": {
-                    "description": "Access key ID // 访问密钥 ID",
+                "content": {
+                    "description": "Content at that time // 该时间点的内容",
                     "type": "string"
                 },
-                "accessKeySecret": {
-                    "description": "Access key secret // 访问密钥秘密",
+                "contentHash": {
+                    "description": "Content hash // 内容哈希",
                     "type": "string"
                 },
-                "accessUrlPrefix": {
-                    "description": "Access URL prefix // 访问地址前缀",
-                    "type": "string"
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string",
+                    "example": "Daily/a.md"
                 },
-                "accountId": {
-                    "description": "Account ID // 账户 ID",
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
                     "type": "string"
                 },
-                "bucketName": {
-                    "description": "Bucket name // 存储桶名称",
-                    "type": "string"
+                "source": {
+                    "description": "Where the content comes from: history or note // 内容来源：history（历史版本）或 note（笔记本身）",
+                    "type": "string",
+                    "example": "history"
                 },
+                "timestamp": {
+                    "description": "Requested point in time in milliseconds // 请求的时间点（毫秒）",
+                    "type": "integer",
+                    "example": 1700000000000
+                },
+                "version": {
+                    "description": "History version the content was taken from, 0 for note // 内容所取自的历史版本号，来源为 note 时为 0",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.VaultDTO": {
+            "type": "object",
+            "properties": {
                 "createdAt": {
-                    "description": "Created at // 创建时间",
+                    "description": "Creation time // 创建时间",
                     "type": "string"
                 },
-                "customPath": {
-                    "description": "Custom path // 自定义路径",
-                    "type": "string"
+                "fileCount": {
+                    "description": "Number of files // 文件数量",
+                    "type": "integer"
                 },
-                "endpoint": {
-                    "description": "Endpoint // 访问端点",
-                    "type": "string"
+                "fileSize": {
+                    "description": "Size of files // 文件大小",
+                    "type": "integer"
                 },
                 "id": {
-                    "description": "ID // ID",
+                    "description": "Vault ID // 保险库 ID",
                     "type": "integer"
                 },
-                "isEnabled": {
-                    "description": "Is enabled // 是否启用",
-                    "type": "boolean"
-                },
-                "password": {
-                    "description": "Password // 密码",
-                    "type": "string"
+                "noteCount": {
+                    "description": "Number of notes // 笔记数量",
+                    "type": "integer"
                 },
-                "region": {
-                    "description": "Region // 区域",
-                    "type": "string"
+                "noteSize": {
+                    "description": "Size of notes // 笔记大小",
+                    "type": "integer"
                 },
-                "type": {
-                    "description": "Storage type // 存储类型",
-                    "type": "string"
+                "size": {
+                    "description": "Total size // 总大小",
+                    "type": "integer"
                 },
                 "updatedAt": {
-                    "description": "Updated at // 更新时间",
+                    "description": "Updated time // 更新时间",
                     "type": "string"
                 },
-                "user": {
-                    "description": "Username // 用户名",
+                "vault": {
+                    "description": "Vault name // 保险库名称",
                     "type": "string"
                 }
             }
         },
-        "dto.StoragePostRequest": {
+        "dto.VaultForceDeleteItemRequest": {
             "type": "object",
             "required": [
-                "accessUrlPrefix",
-                "type"
+                "id",
+                "type",
+                "vaultId"
             ],
             "properties": {
-                "accessKeyId": {
-                    "description": "Access key ID // 访问密钥ID",
-                    "type": "string",
-                    "example": ""
-                },
-                "accessKeySecret": {
-                    "description": "Access key secret // 访问密钥秘密",
-                    "type": "string",
-                    "example": ""
+                "id": {
+                    "description": "Resource ID // 资源 ID",
+                    "type": "integer",
+                    "example": 100
                 },
-                "accessUrlPrefix": {
-                    "description": "Access URL prefix // 访问地址前缀",
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 2,
-                    "example": "https://cdn.com"
+                "type": {
+                    "description": "Resource type: note or file // 资源类型",
+                    "type": "string"
                 },
-                "accountId": {
-                    "description": "Account ID (R2) // 账户ID r2",
-                    "type": "string",
-                    "example": "123456789"
+                "vaultId": {
+                    "description": "Vault ID // 笔记库 ID",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.VaultGraphDTO": {
+            "type": "object",
+            "properties": {
+                "edges": {
+                    "description": "Links between the returned notes // 返回的笔记之间的链接",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VaultGraphEdge"
+                    }
                 },
-                "bucketName": {
-                    "description": "Bucket name // 存储桶名称",
-                    "type": "string",
-                    "example": "my-bucket"
+                "nodes": {
+                    "description": "Notes ordered by path // 按路径排序的笔记",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VaultGraphNode"
+                    }
                 },
-                "customPath": {
-                    "description": "Custom path // 自定义路径",
-                    "type": "string",
-                    "example": "/backups"
+                "truncated": {
+                    "description": "Nodes were cut at the size limit // 节点数量达到上限被截断",
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.VaultGraphEdge": {
+            "type": "object",
+            "properties": {
+                "isEmbed": {
+                    "description": "Is it an embed (![[...]]) // 是否为嵌入",
+                    "type": "boolean"
                 },
-                "endpoint": {
-                    "description": "Endpoint (OSS) // 端点 oss",
-                    "type": "string",
-                    "example": "oss-cn-hangzhou.aliyuncs.com"
+                "source": {
+                    "description": "Linking note ID // 发起链接的笔记 ID",
+                    "type": "integer"
                 },
+                "target": {
+                    "description": "Linked note ID // 被链接的笔记 ID",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.VaultGraphNode": {
+            "type": "object",
+            "properties": {
                 "id": {
-                    "description": "ID // ID",
-                    "type": "integer",
-                    "example": 1
+                    "description": "Note ID // 笔记 ID",
+                    "type": "integer"
                 },
-                "isEnabled": {
-                    "description": "Is enabled // 是否启用",
-                    "type": "integer",
-                    "example": 1
+                "name": {
+                    "description": "File name without .md // 不含 .md 的文件名",
+                    "type": "string"
                 },
-                "password": {
-                    "description": "Password // 密码",
+                "path": {
+                    "description": "Note path // 笔记路径",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Tags without \"#\" // 不含 \"#\" 的标签",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.VaultImportJob": {
+            "type": "object",
+            "properties": {
+                "jobId": {
+                    "description": "Job ID in the background job queue // 后台任务队列中的任务 ID",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.VaultImportLocalRequest": {
+            "type": "object",
+            "required": [
+                "path",
+                "vault"
+            ],
+            "properties": {
+                "folder": {
+                    "description": "Target folder inside the vault, empty for root // 导入到保险库内的目标目录，为空表示根目录",
                     "type": "string",
-                    "example": "secret_password"
+                    "example": "Imported"
                 },
-                "region": {
-                    "description": "Region (S3) // 区域 s3",
+                "path": {
+                    "description": "Vault directory on the server // 服务器上的保险库目录",
                     "type": "string",
-                    "example": "us-east-1"
+                    "example": "/data/obsidian/MyVault"
                 },
-                "type": {
-                    "description": "Storage type // 类型",
+                "vault": {
+                    "description": "Vault name, created if missing // 保险库名称，不存在时自动创建",
                     "type": "string",
-                    "minLength": 1,
-                    "example": "local-fs"
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.VaultPostRequest": {
+            "type": "object",
+            "required": [
+                "vault"
+            ],
+            "properties": {
+                "id": {
+                    "description": "Vault ID (optional for update) // 保险库 ID（可选，用于更新）",
+                    "type": "integer",
+                    "example": 1
                 },
-                "user": {
-                    "description": "Username // 访问用户名",
+                "vault": {
+                    "description": "Vault name // 保险库名称",
                     "type": "string",
-                    "example": "admin"
+                    "example": "MyVault"
+                }
+            }
+        },
+        "dto.VaultRebuildIndexRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "description": "Vault ID // 保险库 ID",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1
                 }
             }
         },
-        "dto.SyncLogDTO": {
+        "dto.VaultRetentionDTO": {
             "type": "object",
             "properties": {
-                "action": {
-                    "description": "Action type // 操作类型",
-                    "type": "string"
-                },
-                "changedFields": {
-                    "description": "Changed fields // 变更字段",
-                    "type": "string"
-                },
-                "clientName": {
-                    "description": "Client name // 客户端名称",
-                    "type": "string"
-                },
-                "clientType": {
-                    "description": "Client type // 客户端类型",
-                    "type": "string"
-                },
-                "clientVersion": {
-                    "description": "Client version // 客户端版本",
-                    "type": "string"
-                },
-                "createdAt": {
-                    "description": "Log creation time // 创建时间",
-                    "type": "string"
-                },
-                "message": {
-                    "description": "Additional message // 附加消息",
-                    "type": "string"
-                },
-                "path": {
-                    "description": "Resource path // 资源路径",
+                "effectiveSoftDeleteRetentionTime": {
+                    "description": "Retention applied to the vault, 0 keeps deleted items forever // 保险库实际使用的保留时间，0 表示永久保留",
                     "type": "string"
                 },
-                "pathHash": {
-                    "description": "Resource path hash // 路径哈希",
+                "softDeleteRetentionTime": {
+                    "description": "Vault override, empty uses the user setting // 保险库覆盖值，为空使用用户设置",
                     "type": "string"
                 },
-                "size": {
-                    "description": "Size in bytes // 大小（字节）",
-                    "type": "integer"
-                },
-                "status": {
-                    "description": "Status: 1 success, 2 failed // 状态",
-                    "type": "integer"
-                },
-                "type": {
-                    "description": "Resource type // 资源类型",
+                "vault": {
+                    "description": "Vault name // 保险库名称",
                     "type": "string"
                 },
                 "vaultId": {
-                    "description": "Vault ID // 笔记本 ID",
+                    "description": "Vault ID // 保险库 ID",
                     "type": "integer"
                 }
             }
         },
-        "dto.UserChangePasswordRequest": {
+        "dto.VaultRetentionRequest": {
             "type": "object",
             "required": [
-                "confirmPassword",
-                "oldPassword",
-                "password"
+                "vault"
             ],
             "properties": {
-                "confirmPassword": {
-                    "description": "Confirm password // 校验密码",
-                    "type": "string",
-                    "example": "new_password123"
-                },
-                "oldPassword": {
-                    "description": "Old password // 旧密码",
+                "softDeleteRetentionTime": {
+                    "description": "Soft delete retention like 7d or 12h, 0 keeps deleted items forever, empty uses the user setting // 软删除保留时间，如 7d、12h，0 表示永久保留，为空使用用户设置",
                     "type": "string",
-                    "example": "old_password123"
+                    "example": "7d"
                 },
-                "password": {
-                    "description": "New password // 新密码",
+                "vault": {
+                    "description": "Vault name // 保险库名称",
                     "type": "string",
-                    "example": "new_password123"
+                    "example": "MyVault"
                 }
             }
         },
-        "dto.UserCreateRequest": {
+        "dto.VaultSiteExportRequest": {
             "type": "object",
             "required": [
-                "confirmPassword",
-                "email",
-                "password",
-                "username"
+                "vault"
             ],
             "properties": {
-                "confirmPassword": {
-                    "description": "Confirm password // 校验密码",
+                "folder": {
+                    "description": "Only publish this folder, e.g. a shared folder // 仅发布该目录，例如分享的文件夹",
                     "type": "string",
-                    "example": "password123"
+                    "example": "Public"
                 },
-                "email": {
-                    "description": "User email // 用户邮件",
+                "outDir": {
+                    "description": "Write the site to this server directory instead (admin only) // 改为写入服务器上的该目录（仅管理员）",
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "/var/www/notes"
                 },
-                "password": {
-                    "description": "User password // 用户密码",
+                "storageId": {
+                    "description": "Push the site through this storage target // 通过该存储目标推送站点",
+                    "type": "integer",
+                    "example": 1
+                },
+                "title": {
+                    "description": "Site title, default the vault or folder name // 站点标题，默认为保险库或目录名称",
                     "type": "string",
-                    "example": "password123"
+                    "example": "My Notes"
                 },
-                "username": {
-                    "description": "User name // 用户名",
+                "vault": {
+                    "description": "Vault name // 保险库名称",
                     "type": "string",
-                    "example": "username123"
+                    "example": "MyVault"
                 }
             }
         },
-        "dto.UserDTO": {
+        "dto.VaultSiteExportResult": {
             "type": "object",
             "properties": {
-                "avatar": {
-                    "description": "Avatar URL or handle // 头像路径或名称",
-                    "type": "string"
+                "files": {
+                    "description": "Attachments copied // 复制的附件数",
+                    "type": "integer"
                 },
-                "createdAt": {
-                    "description": "Account created time // 账号创建时间",
-                    "type": "string"
+                "pages": {
+                    "description": "Note pages rendered // 渲染的笔记页面数",
+                    "type": "integer"
                 },
-                "email": {
-                    "description": "Email address // 邮件地址",
+                "target": {
+                    "description": "Directory or storage path the site was written to // 站点写入的目录或存储路径",
                     "type": "string"
+                }
+            }
+        },
+        "dto.VaultStatsDTO": {
+            "type": "object",
+            "properties": {
+                "activity": {
+                    "description": "Writing activity per day, oldest first // 每日写作活动，按日期升序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VaultStatsDayDTO"
+                    }
                 },
-                "isDeleted": {
-                    "description": "User is blocked",
-                    "type": "boolean"
+                "chars": {
+                    "description": "Characters, whitespace excluded // 字符数，不含空白",
+                    "type": "integer"
                 },
-                "token": {
-                    "description": "Authentication Token // 认证 Token",
-                    "type": "string"
+                "folders": {
+                    "description": "Per folder, most words first // 按文件夹统计，字数多的排在前面",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VaultStatsFolderDTO"
+                    }
                 },
-                "tokenId": {
-                    "description": "Authentication Token ID // 认证 Token ID",
-                    "type": "integer"
+                "longest": {
+                    "description": "Longest notes by words // 字数最多的笔记",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VaultStatsNoteDTO"
+                    }
                 },
-                "uid": {
-                    "description": "User ID (primary key) // 用户唯一标识（主键）",
+                "notes": {
+                    "description": "Notes // 笔记数",
                     "type": "integer"
                 },
-                "updatedAt": {
-                    "description": "Last updated time // 最后更新时间",
-                    "type": "string"
+                "readingMinutes": {
+                    "description": "Estimated reading time of all notes // 全部笔记的预计阅读时间（分钟）",
+                    "type": "integer"
                 },
-                "username": {
-                    "description": "Username // 用户名",
-                    "type": "string"
+                "words": {
+                    "description": "Words, each CJK character counts as one // 字数，每个中日韩字符计为一个",
+                    "type": "integer"
                 }
             }
         },
-        "dto.UserLoginRequest": {
+        "dto.VaultStatsDayDTO": {
             "type": "object",
-            "required": [
-                "credentials",
-                "password"
-            ],
             "properties": {
-                "credentials": {
-                    "description": "Username or Email // 登录凭证（用户名或邮件）",
+                "date": {
+                    "description": "Day in server local time // 服务器本地时间的日期",
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "2024-01-02"
                 },
-                "password": {
-                    "description": "Password // 密码",
-                    "type": "string",
-                    "example": "password123"
+                "edits": {
+                    "description": "Saved note edits // 保存的笔记编辑次数",
+                    "type": "integer"
                 },
-                "tokenId": {
-                    "description": "Last token ID for rotation // 最后一个用于轮转的令牌ID",
-                    "type": "integer",
-                    "example": 123
+                "wordsAdded": {
+                    "description": "Words added // 新增字数",
+                    "type": "integer"
+                },
+                "wordsRemoved": {
+                    "description": "Words removed // 删除字数",
+                    "type": "integer"
                 }
             }
         },
-        "dto.UserUpdateRequest": {
+        "dto.VaultStatsFolderDTO": {
             "type": "object",
-            "required": [
-                "email",
-                "uid",
-                "username"
-            ],
             "properties": {
-                "email": {
-                    "description": "User email // 用户邮件",
+                "chars": {
+                    "description": "Characters // 字符数",
+                    "type": "integer"
+                },
+                "folder": {
+                    "description": "Folder path, empty for the vault root // 文件夹路径，保险库根目录为空",
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "Daily"
                 },
-                "isDeleted": {
-                    "description": "User deleted flag",
-                    "type": "boolean",
-                    "example": true
+                "notes": {
+                    "description": "Notes // 笔记数",
+                    "type": "integer"
                 },
-                "password": {
-                    "description": "User password // 用户密码",
+                "words": {
+                    "description": "Words // 字数",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.VaultStatsNoteDTO": {
+            "type": "object",
+            "properties": {
+                "chars": {
+                    "description": "Characters // 字符数",
+                    "type": "integer"
+                },
+                "path": {
+                    "description": "Note path // 笔记路径",
                     "type": "string",
-                    "example": "password123"
+                    "example": "Daily/a.md"
                 },
-                "uid": {
-                    "description": "User ID (primary key) // 用户唯一标识（主键）",
-                    "type": "integer",
-                    "example": 123
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string"
                 },
-                "username": {
-                    "description": "User name // 用户名",
-                    "type": "string",
-                    "example": "username123"
+                "readingMinutes": {
+                    "description": "Estimated reading time // 预计阅读时间（分钟）",
+                    "type": "integer"
+                },
+                "words": {
+                    "description": "Words // 字数",
+                    "type": "integer"
                 }
             }
         },
-        "dto.VaultDTO": {
+        "dto.VaultSyncStatusDTO": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "description": "Creation time // 创建时间",
+                "bytes": {
+                    "description": "Bytes transferred // 传输字节数",
+                    "type": "integer"
+                },
+                "changes": {
+                    "description": "Synced changes // 已同步的变更数",
+                    "type": "integer"
+                },
+                "clients": {
+                    "description": "Per client activity, most recent first // 各客户端活动，最近的在前",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SyncClientStatusDTO"
+                    }
+                },
+                "failures": {
+                    "description": "Failed syncs // 同步失败次数",
+                    "type": "integer"
+                },
+                "lastSyncAt": {
+                    "description": "Last successful sync // 最近一次成功同步时间",
+                    "type": "string"
+                },
+                "recentErrors": {
+                    "description": "Most recent failures, newest first // 最近的失败记录，最新的在前",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SyncLogDTO"
+                    }
+                },
+                "vault": {
+                    "description": "Vault name // 保险库名称",
+                    "type": "string"
+                },
+                "vaultId": {
+                    "description": "Vault ID // 保险库 ID",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.VaultTransferConflictDTO": {
+            "type": "object",
+            "properties": {
+                "path": {
+                    "description": "Path in the vault // 保险库中的路径",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "note or file // note 或 file",
                     "type": "string"
+                }
+            }
+        },
+        "dto.VaultTransferReportDTO": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "description": "Entries of the target vault with other content // 目标保险库中内容不同的条目",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VaultTransferConflictDTO"
+                    }
+                },
+                "dryRun": {
+                    "description": "Nothing was changed // 未做任何修改",
+                    "type": "boolean"
+                },
+                "errors": {
+                    "description": "First few failure reasons // 前若干条失败原因",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "fileCount": {
-                    "description": "Number of files // 文件数量",
+                "failed": {
+                    "description": "Entries that failed to move // 转移失败的条目数",
                     "type": "integer"
                 },
-                "fileSize": {
-                    "description": "Size of files // 文件大小",
+                "files": {
+                    "description": "Attachments to move // 需转移的附件数",
                     "type": "integer"
                 },
-                "id": {
-                    "description": "Vault ID // 保险库 ID",
+                "fromUid": {
+                    "description": "Source user ID // 源用户 ID",
                     "type": "integer"
                 },
-                "noteCount": {
-                    "description": "Number of notes // 笔记数量",
+                "histories": {
+                    "description": "Note history versions to move // 需转移的笔记历史版本数",
                     "type": "integer"
                 },
-                "noteSize": {
-                    "description": "Size of notes // 笔记大小",
+                "identical": {
+                    "description": "Entries the target vault has with the same content // 目标保险库中内容相同的条目数",
+                    "type": "integer"
+                },
+                "notes": {
+                    "description": "Notes to move // 需转移的笔记数",
+                    "type": "integer"
+                },
+                "settings": {
+                    "description": "Client config files the target vault does not have yet // 目标保险库尚无的客户端配置文件数",
                     "type": "integer"
                 },
                 "size": {
-                    "description": "Total size // 总大小",
+                    "description": "Bytes of the notes and attachments to move // 需转移的笔记与附件字节数",
                     "type": "integer"
                 },
-                "updatedAt": {
-                    "description": "Updated time // 更新时间",
+                "targetExists": {
+                    "description": "The target user has the target vault already // 目标用户已有目标保险库",
+                    "type": "boolean"
+                },
+                "targetVault": {
+                    "description": "Target vault // 目标保险库",
                     "type": "string"
                 },
+                "toUid": {
+                    "description": "Target user ID // 目标用户 ID",
+                    "type": "integer"
+                },
+                "transferred": {
+                    "description": "The source vault was moved and deleted // 源保险库已转移并删除",
+                    "type": "boolean"
+                },
+                "trashed": {
+                    "description": "Entries in the trash, dropped with the source vault // 回收站中的条目数，随源保险库一并删除",
+                    "type": "integer"
+                },
                 "vault": {
-                    "description": "Vault name // 保险库名称",
+                    "description": "Source vault // 源保险库",
                     "type": "string"
                 }
             }
         },
-        "dto.VaultForceDeleteItemRequest": {
+        "dto.VaultTransferRequest": {
             "type": "object",
             "required": [
-                "id",
-                "type",
-                "vaultId"
+                "fromUid",
+                "toUid",
+                "vault"
             ],
             "properties": {
-                "id": {
-                    "description": "Resource ID // 资源 ID",
+                "dryRun": {
+                    "description": "Only report what would be moved // 仅报告将要转移的内容",
+                    "type": "boolean"
+                },
+                "fromUid": {
+                    "description": "Source user ID // 源用户 ID",
                     "type": "integer",
-                    "example": 100
+                    "example": 2
                 },
-                "type": {
-                    "description": "Resource type: note or file // 资源类型",
+                "overwrite": {
+                    "description": "Replace conflicting entries of the target vault // 覆盖目标保险库中冲突的条目",
+                    "type": "boolean"
+                },
+                "targetVault": {
+                    "description": "Vault name for the target user, empty keeps the name // 目标用户下的保险库名称，为空时保持原名",
                     "type": "string"
                 },
-                "vaultId": {
-                    "description": "Vault ID // 笔记库 ID",
+                "toUid": {
+                    "description": "Target user ID // 目标用户 ID",
                     "type": "integer",
                     "example": 1
+                },
+                "vault": {
+                    "description": "Vault of the source user // 源用户的保险库",
+                    "type": "string",
+                    "example": "defaultVault"
                 }
             }
         },
-        "dto.VaultPostRequest": {
+        "dto.VaultTrashEmptyRequest": {
             "type": "object",
             "required": [
                 "vault"
             ],
             "properties": {
-                "id": {
-                    "description": "Vault ID (optional for update) // 保险库 ID（可选，用于更新）",
-                    "type": "integer",
-                    "example": 1
+                "all": {
+                    "description": "Purge the whole trash, Items is ignored // 清空整个回收站，忽略 Items",
+                    "type": "boolean"
+                },
+                "items": {
+                    "description": "Items to purge // 待彻底删除的条目",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VaultTrashItem"
+                    }
                 },
                 "vault": {
                     "description": "Vault name // 保险库名称",
@@ -8102,17 +19554,113 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.VaultRebuildIndexRequest": {
+        "dto.VaultTrashEmptyResult": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "description": "Purged files // 已删除文件数",
+                    "type": "integer"
+                },
+                "folders": {
+                    "description": "Purged folders // 已删除文件夹数",
+                    "type": "integer"
+                },
+                "notes": {
+                    "description": "Purged notes // 已删除笔记数",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.VaultTrashItem": {
             "type": "object",
             "required": [
-                "id"
+                "type"
             ],
             "properties": {
+                "path": {
+                    "description": "Item path // 条目路径",
+                    "type": "string",
+                    "example": "Daily/a.md"
+                },
+                "pathHash": {
+                    "description": "Path hash, derived from path when empty // 路径哈希，为空时由路径计算",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Item type: note, file or folder // 条目类型：note、file 或 folder",
+                    "type": "string",
+                    "enum": [
+                        "note",
+                        "file",
+                        "folder"
+                    ],
+                    "example": "note"
+                }
+            }
+        },
+        "dto.VaultTrashItemDTO": {
+            "type": "object",
+            "properties": {
+                "deletedAt": {
+                    "description": "Deletion timestamp in milliseconds // 删除时间戳（毫秒）",
+                    "type": "integer",
+                    "example": 1700000000000
+                },
                 "id": {
-                    "description": "Vault ID // 保险库 ID",
+                    "description": "Item ID // 条目 ID",
                     "type": "integer",
-                    "minimum": 1,
-                    "example": 1
+                    "example": 100
+                },
+                "path": {
+                    "description": "Item path // 条目路径",
+                    "type": "string",
+                    "example": "Daily/a.md"
+                },
+                "pathHash": {
+                    "description": "Path hash // 路径哈希",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "Size in bytes, 0 for folders // 大小（字节），文件夹为 0",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "Item type: note, file or folder // 条目类型：note、file 或 folder",
+                    "type": "string",
+                    "example": "note"
+                }
+            }
+        },
+        "dto.VaultUsageDTO": {
+            "type": "object",
+            "properties": {
+                "fileCount": {
+                    "description": "Attachments // 附件数",
+                    "type": "integer"
+                },
+                "fileSize": {
+                    "description": "Attachment bytes // 附件字节数",
+                    "type": "integer"
+                },
+                "noteCount": {
+                    "description": "Notes // 笔记数",
+                    "type": "integer"
+                },
+                "noteSize": {
+                    "description": "Note bytes // 笔记字节数",
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "Note and attachment bytes // 笔记与附件字节数",
+                    "type": "integer"
+                },
+                "vault": {
+                    "description": "Vault name // 仓库名称",
+                    "type": "string"
+                },
+                "vaultId": {
+                    "description": "Vault ID // 仓库 ID",
+                    "type": "integer"
                 }
             }
         },
@@ -8182,6 +19730,189 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "dto.WebhookDTO": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Created at // 创建时间",
+                    "type": "string"
+                },
+                "events": {
+                    "description": "Subscribed events, empty subscribes to all // 订阅的事件，为空表示全部",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "description": "Webhook ID // Webhook ID",
+                    "type": "integer"
+                },
+                "isEnabled": {
+                    "description": "Whether deliveries are sent // 是否投递",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "Display name // 显示名称",
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "HMAC-SHA256 signing secret // HMAC-SHA256 签名密钥",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "Updated at // 更新时间",
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Endpoint receiving the POSTs // 接收 POST 请求的地址",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.WebhookDeliveryDTO": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Attempts made so far // 已尝试次数",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "description": "Event time // 事件时间",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "Error of the last failed attempt // 最近一次失败的错误信息",
+                    "type": "string"
+                },
+                "event": {
+                    "description": "Event name // 事件名",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Delivery ID, also sent as deliveryId in the payload // 投递 ID，同时作为负载中的 deliveryId 发送",
+                    "type": "integer"
+                },
+                "payload": {
+                    "description": "JSON body sent // 发送的 JSON 内容",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending / success / failed // 投递状态",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "description": "Last attempt time // 最近一次尝试时间",
+                    "type": "string"
+                },
+                "webhookId": {
+                    "description": "Webhook ID // Webhook ID",
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.WebhookRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "description": "Subscribed events, empty subscribes to all // 订阅的事件，为空表示全部",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "note.created",
+                        "backup.failed"
+                    ]
+                },
+                "id": {
+                    "description": "ID, 0 creates a webhook // ID，为 0 时新建",
+                    "type": "integer",
+                    "example": 1
+                },
+                "isEnabled": {
+                    "description": "Whether deliveries are sent // 是否投递",
+                    "type": "boolean",
+                    "example": true
+                },
+                "name": {
+                    "description": "Display name // 显示名称",
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "Home automation"
+                },
+                "secret": {
+                    "description": "HMAC-SHA256 signing secret, empty sends unsigned requests // HMAC-SHA256 签名密钥，为空时不签名",
+                    "type": "string",
+                    "maxLength": 256,
+                    "example": "s3cret"
+                },
+                "url": {
+                    "description": "Endpoint receiving the POSTs // 接收 POST 请求的地址",
+                    "type": "string",
+                    "maxLength": 2048,
+                    "example": "https://example.com/fns-hook"
+                }
+            }
+        },
+        "graphql_router.Request": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "description": "Operation to run when the document has several // 文档包含多个操作时要执行的操作",
+                    "type": "string"
+                },
+                "query": {
+                    "description": "GraphQL query document // GraphQL 查询文档",
+                    "type": "string"
+                },
+                "variables": {
+                    "description": "Query variables // 查询变量",
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "ipfilter.Ban": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "Ban time // 封禁时间",
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "description": "Expiry time // 到期时间",
+                    "type": "string"
+                },
+                "ip": {
+                    "description": "Banned IP // 被封禁的 IP",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Ban reason // 封禁原因",
+                    "type": "string"
+                }
+            }
+        },
+        "util.ChangelogSection": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "description": "Entries of the section // 分组内的条目",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "Section heading, empty for entries before the first heading // 分组标题，首个标题之前的条目为空",
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -14,15 +14,27 @@ import (
 	"sync"
 	"time"
 
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/internal/service"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/cluster"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"github.com/haierkeys/fast-note-sync-service/pkg/fileurl"
+	"github.com/haierkeys/fast-note-sync-service/pkg/ipfilter"
+	"github.com/haierkeys/fast-note-sync-service/pkg/jobqueue"
+	"github.com/haierkeys/fast-note-sync-service/pkg/logbuf"
+	"github.com/haierkeys/fast-note-sync-service/pkg/loginguard"
+	"github.com/haierkeys/fast-note-sync-service/pkg/maintenance"
+	"github.com/haierkeys/fast-note-sync-service/pkg/readonly"
+	"github.com/haierkeys/fast-note-sync-service/pkg/schedule"
+	"github.com/haierkeys/fast-note-sync-service/pkg/synctrace"
 	"github.com/haierkeys/fast-note-sync-service/pkg/workerpool"
 	"github.com/haierkeys/fast-note-sync-service/pkg/writequeue"
 	"golang.org/x/mod/semver"
 
 	"github.com/gin-gonic/gin/binding"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"gorm.io/gorm"
 )
 
@@ -65,11 +77,21 @@ func NewApp(cfg *AppConfig, logger *zap.Logger, db *gorm.DB, efs embed.FS) (*App
 		return nil, fmt.Errorf("config, logger and db are required")
 	}
 
+	// Keep the recent log entries for the admin log viewer
+	// 保留最近的日志条目供管理员查看
+	logBuffer := logbuf.New(cfg.Log.BufferSize)
+	if logBuffer != nil {
+		logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(core, logBuffer.Core(core))
+		}))
+	}
+
 	// 1. Initialize Infrastructure
 	infra, err := initInfra(cfg, logger, db)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize infra: %w", err)
 	}
+	infra.logBuffer = logBuffer
 
 	// 2. Initialize Repositories
 	repos := initRepositories(infra.Dao)
@@ -85,6 +107,7 @@ func NewApp(cfg *AppConfig, logger *zap.Logger, db *gorm.DB, efs embed.FS) (*App
 
 	// 4. Initialize Services (needs app context for some reason? No, it's just wiring)
 	a.Services = initServices(cfg, infra, repos, logger)
+	a.subscribeConfigChanges(a.serviceConfig)
 
 	// Load support records
 	a.loadSupportRecords(efs)
@@ -240,6 +263,14 @@ func (a *App) SetCheckVersionInfo(info pkgapp.CheckVersionInfo) {
 	a.checkVersion = info
 }
 
+// ServiceReleases returns a copy of the cached service releases, newest first
+// ServiceReleases 返回缓存的服务端发布版本列表副本，最新版本在前
+func (a *App) ServiceReleases() []pkgapp.HistoricalVersion {
+	a.checkVersionMu.RLock()
+	defer a.checkVersionMu.RUnlock()
+	return append([]pkgapp.HistoricalVersion(nil), a.serviceReleases...)
+}
+
 // SetCheckVersionReleases sets all filtered service and plugin releases
 // SetCheckVersionReleases 设置所有过滤后的服务和插件发布版本列表
 func (a *App) SetCheckVersionReleases(serviceReleases, pluginReleases []pkgapp.HistoricalVersion) {
@@ -262,6 +293,14 @@ func (a *App) SetWSS(wss *pkgapp.WebsocketServer) {
 			}
 		})
 	}
+	if a.wss != nil && a.Services != nil && a.Services.DeviceService != nil {
+		a.Services.DeviceService.SetKickHandler(a.wss.KickDevice)
+	}
+	if a.wss != nil && a.Services != nil && a.Services.BackupService != nil {
+		a.Services.BackupService.SetProgressHandler(func(uid int64, msg *dto.BackupProgressMessage) {
+			a.wss.BroadcastToUser(uid, code.Success.WithData(msg), "BackupProgress")
+		})
+	}
 }
 
 // GetWSS gets WebSocket server reference
@@ -370,6 +409,61 @@ func (a *App) WorkerPool() *workerpool.Pool {
 	return a.workerPool
 }
 
+// IPFilter gets the IP filter shared by the deny list, honeypot bans and admin API
+// IPFilter 获取 IP 过滤器（黑名单、诱饵封禁与管理接口共用）
+func (a *App) IPFilter() *ipfilter.Filter {
+	return a.ipFilter
+}
+
+// LoginGuard gets the failed login counters, nil when the protection is turned off
+// LoginGuard 获取登录失败计数器，关闭防护时为 nil
+func (a *App) LoginGuard() *loginguard.Guard {
+	return a.loginGuard
+}
+
+// Maintenance gets the maintenance window coordinator heavy jobs go through
+// Maintenance 获取重型任务使用的维护窗口协调器
+func (a *App) Maintenance() *maintenance.Coordinator {
+	return a.maintenance
+}
+
+// LogBuffer gets the recent log entries, nil when log.buffer-size is 0
+// LogBuffer 获取最近的日志条目，log.buffer-size 为 0 时为 nil
+func (a *App) LogBuffer() *logbuf.Buffer {
+	return a.logBuffer
+}
+
+// ReadOnly gets the read-only maintenance mode switch
+// ReadOnly 获取只读维护模式开关
+func (a *App) ReadOnly() *readonly.Mode {
+	return a.readOnly
+}
+
+// Jobs gets the background job queue
+// Jobs 获取后台任务队列
+func (a *App) Jobs() *jobqueue.Queue {
+	return a.jobQueue
+}
+
+// SyncTracer gets the per-user sync tracing switched on by the admin
+// SyncTracer 获取由管理员开启的按用户同步跟踪
+func (a *App) SyncTracer() *synctrace.Tracer {
+	return a.syncTracer
+}
+
+// Schedules gets the scheduler running the scheduled tasks; it has no tasks on instances that leave the
+// tasks to another instance
+// Schedules 获取运行定时任务的调度器；由其他实例执行任务的实例上没有任务
+func (a *App) Schedules() *schedule.Scheduler {
+	return a.schedules
+}
+
+// ClusterBus gets the pub/sub bridge between instances, nil when running alone
+// ClusterBus 获取实例间的发布/订阅桥，单实例运行时为 nil
+func (a *App) ClusterBus() cluster.Bus {
+	return a.clusterBus
+}
+
 // WriteQueueManager gets Write Queue Manager (for advanced operations)
 // WriteQueueManager 获取 Write Queue Manager（用于高级操作）
 func (a *App) WriteQueueManager() *writequeue.Manager {
@@ -584,6 +678,11 @@ func (a *App) UpdateSupportRecords(lang string, records []pkgapp.SupportRecord)
 // TriggerUpgrade 触发升级流程
 func (a *App) TriggerUpgrade(newBinaryPath string) {
 	a.logger.Info("Triggering upgrade", zap.String("path", newBinaryPath))
+	// Refuse changes until the process is replaced, the hold ends with it
+	// 进程被替换前拒绝修改，该持有随进程结束
+	if a.readOnly != nil {
+		a.readOnly.Hold("upgrade")
+	}
 	select {
 	case a.UpgradeSignal <- newBinaryPath:
 	default:
@@ -680,6 +779,32 @@ func (a *App) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	// 0.45 Shutdown NotificationService (stop pending webhook retries)
+	// 0.45 关闭 NotificationService（停止等待中的 Webhook 重试）
+	if a.NotificationService != nil {
+		a.logger.Info("Shutting down notification service...")
+		if err := a.NotificationService.Shutdown(ctx); err != nil {
+			a.logger.Warn("Notification service shutdown error", zap.Error(err))
+		} else {
+			a.logger.Info("Notification service shutdown completed")
+		}
+	}
+
+	// 0.46 Shutdown AlertService (wait for alerts being sent)
+	// 0.46 关闭 AlertService（等待发送中的告警）
+	if a.AlertService != nil {
+		a.logger.Info("Shutting down alert service...")
+		if err := a.AlertService.Shutdown(ctx); err != nil {
+			a.logger.Warn("Alert service shutdown error", zap.Error(err))
+		} else {
+			a.logger.Info("Alert service shutdown completed")
+		}
+	}
+
+	// 0.47 Stop the sync traces, flushing their files
+	// 0.47 停止同步跟踪并刷新其文件
+	a.syncTracer.Close()
+
 	// 0.5 Shutdown SyncLogService (flush buffered sync log batch before write queue closes)
 	// 0.5 关闭 SyncLogService（在写队列关闭前 flush 缓冲的同步日志批次）
 	if a.SyncLogService != nil {
@@ -691,6 +816,17 @@ func (a *App) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	// 0.6 Shutdown Job Queue (finish the queued background jobs while the write queue is still open)
+	// 0.6 关闭 Job Queue（在写队列关闭前完成排队中的后台任务）
+	if a.jobQueue != nil {
+		a.logger.Info("Shutting down job queue...")
+		if err := a.jobQueue.Shutdown(ctx); err != nil {
+			a.logger.Warn("Job queue shutdown error", zap.Error(err))
+		} else {
+			a.logger.Info("Job queue shutdown completed")
+		}
+	}
+
 	// 1. Shutdown Worker Pool (stop accepting new tasks, wait for existing tasks to complete)
 	// 1. 关闭 Worker Pool（停止接受新任务，等待现有任务完成）
 	if a.workerPool != nil {
@@ -703,6 +839,12 @@ func (a *App) Shutdown(ctx context.Context) error {
 		}
 	}
 
+	// 1.5 Flush buffered note updates while the write queue still accepts writes
+	// 1.5 在写队列仍可写入时刷出缓冲的笔记更新
+	if a.Dao != nil {
+		a.Dao.FlushNoteWrites(ctx)
+	}
+
 	// 2. Shutdown Write Queue Manager (drain all queues)
 	// 2. 关闭 Write Queue Manager（排空所有队列）
 	if a.writeQueueMgr != nil {
@@ -731,6 +873,22 @@ func (a *App) Shutdown(ctx context.Context) error {
 		errs = append(errs, fmt.Errorf("background operations timeout: %w", ctx.Err()))
 	}
 
+	// 3.5 Stop relaying events to the other instances
+	// 3.5 停止向其他实例转发事件
+	if a.clusterBus != nil {
+		if err := a.clusterBus.Close(); err != nil {
+			a.logger.Warn("cluster bus close error", zap.Error(err))
+		}
+	}
+
+	// 3.6 Release the token denylist
+	// 3.6 释放令牌拒绝列表
+	if a.tokenDenylist != nil {
+		if err := a.tokenDenylist.Close(); err != nil {
+			a.logger.Warn("token denylist close error", zap.Error(err))
+		}
+	}
+
 	// 4. Close database connection
 	// 4. 关闭数据库连接
 	if err := a.Close(); err != nil {
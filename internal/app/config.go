@@ -5,8 +5,16 @@ package app
 import (
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
+	"github.com/haierkeys/fast-note-sync-service/pkg/cluster"
+	"github.com/haierkeys/fast-note-sync-service/pkg/contentstore"
+	"github.com/haierkeys/fast-note-sync-service/pkg/jobqueue"
+	"github.com/haierkeys/fast-note-sync-service/pkg/loginguard"
+	"github.com/haierkeys/fast-note-sync-service/pkg/revocation"
+	"github.com/haierkeys/fast-note-sync-service/pkg/storage/aws_s3"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"github.com/haierkeys/fast-note-sync-service/pkg/workerpool"
 	"github.com/haierkeys/fast-note-sync-service/pkg/writequeue"
@@ -14,6 +22,7 @@ import (
 	"github.com/creasty/defaults"
 	"github.com/haierkeys/fast-note-sync-service/internal/config"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 )
 
@@ -33,12 +42,25 @@ type AppConfig struct {
 	Tracer           config.TracerConfig           `yaml:"tracer"`
 	ShortLink        config.ShortLinkConfig        `yaml:"short-link"`
 	Storage          config.StorageConfig          `yaml:"storage"`
+	Export           config.ExportConfig           `yaml:"export"`     // Export and backup redaction configuration // 导出与备份脱敏配置
+	RateLimit        config.RateLimitConfig        `yaml:"rate-limit"` // API and WebSocket rate limiting configuration // API 与 WebSocket 限流配置
 	Git              config.GitConfig              `yaml:"git"`
 	WebGUI           config.WebGUIConfig           `yaml:"webgui"`
 	Cloudflare       config.CloudflareConfig       `yaml:"cloudflare"`
 	OAuth            config.OAuthConfig            `yaml:"oauth"`
 	OIDC             config.OIDCConfig             `yaml:"oidc"`
 	AttachmentStatic config.AttachmentStaticConfig `yaml:"attachment-static"` // Attachment static access configuration // 附件模拟静态访问配置
+	Snapshot         config.SnapshotConfig         `yaml:"snapshot"`          // Scheduled local snapshot configuration // 定时本地快照配置
+	Task             config.TaskConfig             `yaml:"task"`              // Scheduled task configuration // 定时任务配置
+	WebDAV           config.WebDAVConfig           `yaml:"webdav"`            // WebDAV endpoint configuration // WebDAV 端点配置
+	FeatureFlags     config.FeatureFlagsConfig     `yaml:"feature-flags"`     // Per-user rollout of experimental features // 实验性功能的按用户灰度配置
+	Lint             config.LintConfig             `yaml:"lint"`              // Spelling and grammar check configuration // 拼写与语法检查配置
+	Maintenance      config.MaintenanceConfig      `yaml:"maintenance"`       // Maintenance window for heavy jobs // 重型任务的维护窗口
+	Alert            config.AlertConfig            `yaml:"alert"`             // Backup failure alerting // 备份失败告警
+	Thumbnail        config.ThumbnailConfig        `yaml:"thumbnail"`         // Image attachment thumbnails // 图片附件缩略图
+	ContentStore     config.ContentStoreConfig     `yaml:"content-store"`     // Pluggable store of the user content files // 用户内容文件的可插拔存储
+	Cluster          config.ClusterConfig          `yaml:"cluster"`           // Multi-instance deployment // 多实例部署
+	TokenDenylist    config.TokenDenylistConfig    `yaml:"token-denylist"`    // Auth tokens revoked before their expiry // 过期前被撤销的认证令牌
 }
 
 // LoadConfig loads configuration from file
@@ -121,6 +143,29 @@ func (c *AppConfig) GetWorkerPoolConfig() workerpool.Config {
 	return cfg
 }
 
+// GetJobQueueConfig gets Job Queue configuration
+// GetJobQueueConfig 获取 Job Queue 配置
+func (c *AppConfig) GetJobQueueConfig() jobqueue.Config {
+	cfg := jobqueue.DefaultConfig()
+
+	if c.App.JobQueueWorkers > 0 {
+		cfg.Workers = c.App.JobQueueWorkers
+	}
+	if c.App.JobQueueSize > 0 {
+		cfg.QueueSize = c.App.JobQueueSize
+	}
+	if c.App.JobQueueMaxAttempts > 0 {
+		cfg.MaxAttempts = c.App.JobQueueMaxAttempts
+	}
+	if c.App.JobQueueRetryDelay != "" {
+		if delay, err := util.ParseDuration(c.App.JobQueueRetryDelay); err == nil && delay > 0 {
+			cfg.RetryDelay = delay
+		}
+	}
+
+	return cfg
+}
+
 // GetWriteQueueConfig gets Write Queue configuration
 // GetWriteQueueConfig 获取 Write Queue 配置
 func (c *AppConfig) GetWriteQueueConfig() writequeue.Config {
@@ -143,6 +188,195 @@ func (c *AppConfig) GetWriteQueueConfig() writequeue.Config {
 	return cfg
 }
 
+// GetNoteWriteCoalesceWindow gets the note write coalescing window, 0 when disabled or invalid
+// GetNoteWriteCoalesceWindow 获取笔记写入合并窗口期，禁用或配置无效时返回 0
+func (c *AppConfig) GetNoteWriteCoalesceWindow() time.Duration {
+	// Buffered updates would be invisible to the other instances
+	// 缓冲中的更新对其他实例不可见
+	if c.App.NoteWriteCoalesceWindow == "" || c.Cluster.IsEnabled {
+		return 0
+	}
+	window, err := util.ParseDuration(c.App.NoteWriteCoalesceWindow)
+	if err != nil || window < 0 {
+		return 0
+	}
+	return window
+}
+
+// GetEncryptionKeyring builds the encryption at rest keyring, nil when no key is configured
+// With encryption disabled a configured key is still used to read files encrypted earlier.
+// GetEncryptionKeyring 构建落盘加密密钥环，未配置密钥时返回 nil
+// 未启用加密时，已配置的密钥仍用于读取此前加密的文件。
+func (c *AppConfig) GetEncryptionKeyring() (*atrest.Keyring, error) {
+	enc := c.Security.EncryptionAtRest
+
+	var key *atrest.Key
+	var err error
+	switch {
+	case enc.KeyFile != "":
+		key, err = atrest.LoadKeyFile(enc.KeyFile)
+	case enc.Key != "":
+		key, err = atrest.ParseKey(enc.Key)
+	case enc.Enabled:
+		err = errors.New("security.encryption-at-rest is enabled but neither key nor key-file is set")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "encryption-at-rest")
+	}
+
+	var previous []*atrest.Key
+	for _, p := range enc.PreviousKeys {
+		k, err := atrest.ParseKey(p)
+		if err != nil {
+			if k, err = atrest.LoadKeyFile(p); err != nil {
+				return nil, errors.Wrap(err, "encryption-at-rest: previous key")
+			}
+		}
+		previous = append(previous, k)
+	}
+
+	if key == nil && len(previous) == 0 {
+		return nil, nil
+	}
+	if !enc.Enabled {
+		return atrest.NewKeyring(nil, append(previous, key)...), nil
+	}
+	return atrest.NewKeyring(key, previous...), nil
+}
+
+// GetContentStore builds the store behind the user content folders, nil when the content only lives in storage/vault
+// GetContentStore 构建用户内容目录背后的存储，内容仅保存在 storage/vault 时返回 nil
+func (c *AppConfig) GetContentStore() (contentstore.Store, error) {
+	cs := c.ContentStore
+	store, err := contentstore.New(contentstore.Config{
+		Type:      cs.Type,
+		LocalPath: cs.LocalPath,
+		S3: &aws_s3.Config{
+			Region:             cs.S3.Region,
+			BucketName:         cs.S3.BucketName,
+			AccessKeyID:        cs.S3.AccessKeyID,
+			AccessKeySecret:    cs.S3.AccessKeySecret,
+			CustomPath:         cs.S3.Prefix,
+			Endpoint:           cs.S3.Endpoint,
+			PathStyle:          cs.S3.PathStyle,
+			CACert:             cs.S3.CACert,
+			InsecureSkipVerify: cs.S3.InsecureSkipVerify,
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "content-store")
+	}
+	return store, nil
+}
+
+// GetClusterBus validates the multi-instance mode and connects the pub/sub bridge, nil when running alone
+// GetClusterBus 校验多实例模式并连接发布/订阅桥，单实例运行时返回 nil
+func (c *AppConfig) GetClusterBus(logger *zap.Logger) (cluster.Bus, error) {
+	if !c.Cluster.IsEnabled {
+		return nil, nil
+	}
+
+	// SQLite files are owned by a single process
+	// SQLite 文件只能由单个进程持有
+	userDBType := c.UserDatabase.Type
+	if userDBType == "" {
+		userDBType = c.Database.Type
+	}
+	for name, dbType := range map[string]string{"database": c.Database.Type, "user-database": userDBType} {
+		if dbType != "mysql" && dbType != "postgres" {
+			return nil, errors.Errorf("cluster: %s.type must be mysql or postgres, got %q", name, dbType)
+		}
+	}
+
+	instanceID := c.Cluster.InstanceID
+	if instanceID == "" {
+		instanceID = util.GetMachineID() + "-" + strconv.Itoa(os.Getpid())
+	}
+	rc := c.Cluster.Redis
+	bus, err := cluster.NewRedis(cluster.RedisConfig{
+		Addr:     rc.Addr,
+		Username: rc.Username,
+		Password: rc.Password,
+		DB:       rc.DB,
+		Channel:  rc.Channel,
+		TLS:      rc.TLS,
+	}, instanceID, logger)
+	if err != nil {
+		return nil, err
+	}
+	return bus, nil
+}
+
+// GetTokenDenylist builds the list of the auth tokens revoked before their expiry
+// GetTokenDenylist 构建过期前被撤销的认证令牌列表
+func (c *AppConfig) GetTokenDenylist() (revocation.List, error) {
+	dl := c.TokenDenylist
+	switch dl.Type {
+	case "", revocation.TypeMemory:
+		list, err := revocation.NewMemory(dl.SavePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "token-denylist")
+		}
+		return list, nil
+	case revocation.TypeRedis:
+		rc := revocation.RedisConfig{
+			Addr:      dl.Redis.Addr,
+			Username:  dl.Redis.Username,
+			Password:  dl.Redis.Password,
+			DB:        dl.Redis.DB,
+			KeyPrefix: dl.Redis.KeyPrefix,
+			TLS:       dl.Redis.TLS,
+		}
+		// Share the server of the cluster bridge unless one is given
+		// 未单独指定时复用多实例转发所用的 Redis 服务
+		if rc.Addr == "" {
+			cr := c.Cluster.Redis
+			rc.Addr, rc.Username, rc.Password, rc.DB, rc.TLS = cr.Addr, cr.Username, cr.Password, cr.DB, cr.TLS
+		}
+		list, err := revocation.NewRedis(rc)
+		if err != nil {
+			return nil, errors.Wrap(err, "token-denylist")
+		}
+		return list, nil
+	default:
+		return nil, errors.Errorf("token-denylist: unknown type %q", dl.Type)
+	}
+}
+
+// GetLoginGuard builds the failed login counters, nil when the protection is turned off
+// GetLoginGuard 构建登录失败计数器，关闭防护时返回 nil
+func (c *AppConfig) GetLoginGuard() (*loginguard.Guard, error) {
+	lg := c.Security.LoginGuard
+	if lg.Enabled != nil && !*lg.Enabled {
+		return nil, nil
+	}
+	durations := map[string]string{"base-lockout": lg.BaseLockout, "max-lockout": lg.MaxLockout, "reset-after": lg.ResetAfter}
+	parsed := make(map[string]time.Duration, len(durations))
+	for name, value := range durations {
+		d, err := util.ParseDuration(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "security.login-guard.%s", name)
+		}
+		parsed[name] = d
+	}
+	captcha, err := loginguard.NewCaptcha(lg.Captcha.Provider, lg.Captcha.SiteKey, lg.Captcha.SecretKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "security.login-guard.captcha")
+	}
+	captchaAfter := 3
+	if lg.Captcha.After != nil {
+		captchaAfter = *lg.Captcha.After
+	}
+	return loginguard.New(loginguard.Config{
+		IPThreshold:      lg.IPThreshold,
+		AccountThreshold: lg.AccountThreshold,
+		BaseLockout:      parsed["base-lockout"],
+		MaxLockout:       parsed["max-lockout"],
+		ResetAfter:       parsed["reset-after"],
+		CaptchaAfter:     captchaAfter,
+	}, captcha), nil
+}
+
 // GetTokenExpiry gets Token expiry duration
 // GetTokenExpiry 获取 Token 过期时间
 func (c *AppConfig) GetTokenExpiry() time.Duration {
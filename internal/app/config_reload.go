@@ -0,0 +1,65 @@
+package app
+
+import (
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// OnConfigChange registers reload to run after the admin saved a changed configuration; subsystems that
+// copy settings at startup use it to pick the new values up without a restart
+// OnConfigChange 注册管理员保存配置变更后执行的 reload；启动时复制设置的子系统借此无需重启即可使用新值
+func (a *App) OnConfigChange(name string, reload func(cfg *AppConfig)) {
+	a.configBus.Subscribe(name, reload)
+}
+
+// SaveConfig writes the configuration file and notifies the subscribers of the change
+// SaveConfig 写入配置文件并通知订阅者配置已变更
+func (a *App) SaveConfig() error {
+	if err := a.config.Save(); err != nil {
+		return err
+	}
+	a.configBus.Publish(a.config)
+	return nil
+}
+
+// subscribeConfigChanges reloads the settings the app container and the services copied at startup
+// subscribeConfigChanges 重新加载应用容器与服务在启动时复制的设置
+func (a *App) subscribeConfigChanges(svcConfig *service.ServiceConfig) {
+	a.OnConfigChange("log", func(cfg *AppConfig) {
+		level, err := zapcore.ParseLevel(cfg.Log.Level)
+		if err != nil {
+			a.logger.Warn("config reload: invalid log level", zap.String("level", cfg.Log.Level), zap.Error(err))
+			return
+		}
+		logger.SetLevel(level)
+	})
+
+	a.OnConfigChange("pull-source", func(cfg *AppConfig) {
+		a.SetPullSourceMode(cfg.App.PullSource)
+	})
+
+	a.OnConfigChange("service", func(cfg *AppConfig) {
+		svcConfig.User.RegisterIsEnable = cfg.User.RegisterIsEnable
+		svcConfig.User.AdminUID = cfg.User.AdminUID
+		svcConfig.User.RequireAdminTOTP = cfg.Security.RequireAdminTOTP
+		svcConfig.User.RegisterApproval = cfg.User.RegisterApproval
+
+		svcConfig.App.SoftDeleteRetentionTime = cfg.App.SoftDeleteRetentionTime
+		svcConfig.App.HistoryKeepVersions = cfg.App.HistoryKeepVersions
+		svcConfig.App.HistorySaveDelay = cfg.App.HistorySaveDelay
+		svcConfig.App.ShareTokenExpiry = cfg.Security.ShareTokenExpiry
+		svcConfig.App.TempPath = cfg.App.TempPath
+		svcConfig.App.Limits.Set(util.ParseSize(cfg.App.MaxNoteSize, 0), util.ParseSize(cfg.App.MaxAttachmentSize, 0), int64(cfg.App.MaxNotesPerVault))
+
+		svcConfig.Token.WebGUILoginTokenExpiry = cfg.Security.WebGUILoginTokenExpiry
+		if cfg.Security.WebGUILoginTokenBindIP != nil {
+			svcConfig.Token.WebGUILoginTokenBindIP = *cfg.Security.WebGUILoginTokenBindIP
+		}
+		if a.TokenService != nil {
+			a.TokenService.SetConfig(svcConfig.Token)
+		}
+	})
+}
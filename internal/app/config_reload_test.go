@@ -0,0 +1,45 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	"github.com/haierkeys/fast-note-sync-service/pkg/fileurl"
+	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSaveConfigReloadsServices(t *testing.T) {
+	defer logger.SetLevel(zapcore.InfoLevel)
+
+	svcConfig := &service.ServiceConfig{App: service.AppServiceConfig{
+		SoftDeleteRetentionTime: "90d",
+		Limits:                  service.NewContentLimits(0, 0, 0),
+	}}
+	a := NewTestApp(&Services{serviceConfig: svcConfig})
+	a.config.File = filepath.Join(t.TempDir(), "config.yaml")
+	a.sourceSelector = fileurl.NewSourceSelector("auto")
+	a.subscribeConfigChanges(svcConfig)
+
+	var reloaded *AppConfig
+	a.OnConfigChange("test", func(cfg *AppConfig) { reloaded = cfg })
+
+	a.config.Log.Level = "debug"
+	a.config.App.SoftDeleteRetentionTime = "7d"
+	a.config.App.MaxNoteSize = "1KB"
+	a.config.User.RegisterIsEnable = true
+	require.NoError(t, a.SaveConfig())
+
+	assert.Same(t, a.config, reloaded)
+	assert.Equal(t, "7d", svcConfig.App.SoftDeleteRetentionTime)
+	assert.True(t, svcConfig.User.RegisterIsEnable)
+	assert.Error(t, svcConfig.App.Limits.CheckNoteSize(2048))
+	assert.True(t, logger.L().Core().Enabled(zapcore.DebugLevel))
+
+	_, err := os.Stat(a.config.File)
+	assert.NoError(t, err)
+}
@@ -6,7 +6,21 @@ import (
 
 	"github.com/haierkeys/fast-note-sync-service/internal/dao"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
+	"github.com/haierkeys/fast-note-sync-service/pkg/cluster"
+	"github.com/haierkeys/fast-note-sync-service/pkg/configbus"
 	"github.com/haierkeys/fast-note-sync-service/pkg/fileurl"
+	"github.com/haierkeys/fast-note-sync-service/pkg/ipfilter"
+	"github.com/haierkeys/fast-note-sync-service/pkg/jobqueue"
+	"github.com/haierkeys/fast-note-sync-service/pkg/logbuf"
+	"github.com/haierkeys/fast-note-sync-service/pkg/loginguard"
+	"github.com/haierkeys/fast-note-sync-service/pkg/maintenance"
+	"github.com/haierkeys/fast-note-sync-service/pkg/readonly"
+	"github.com/haierkeys/fast-note-sync-service/pkg/redact"
+	"github.com/haierkeys/fast-note-sync-service/pkg/revocation"
+	"github.com/haierkeys/fast-note-sync-service/pkg/schedule"
+	"github.com/haierkeys/fast-note-sync-service/pkg/secretscan"
+	"github.com/haierkeys/fast-note-sync-service/pkg/synctrace"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"github.com/haierkeys/fast-note-sync-service/pkg/workerpool"
 	"github.com/haierkeys/fast-note-sync-service/pkg/writequeue"
@@ -22,8 +36,21 @@ type Infra struct {
 	Dao            *dao.Dao
 	workerPool     *workerpool.Pool
 	writeQueueMgr  *writequeue.Manager
+	jobQueue       *jobqueue.Queue
 	TokenManager   pkgapp.TokenManager
 	sourceSelector *fileurl.SourceSelector
+	ipFilter       *ipfilter.Filter
+	redactor       *redact.Redactor
+	secretScanner  *secretscan.Scanner
+	maintenance    *maintenance.Coordinator
+	readOnly       *readonly.Mode
+	configBus      *configbus.Bus[*AppConfig]
+	logBuffer      *logbuf.Buffer
+	syncTracer     *synctrace.Tracer
+	schedules      *schedule.Scheduler
+	clusterBus     cluster.Bus
+	tokenDenylist  revocation.List
+	loginGuard     *loginguard.Guard
 }
 
 // initInfra initializes infrastructure components
@@ -36,8 +63,67 @@ func initInfra(cfg *AppConfig, logger *zap.Logger, db *gorm.DB) (*Infra, error)
 		logger:         logger,
 		DB:             db,
 		sourceSelector: fileurl.NewSourceSelector(cfg.App.PullSource),
+		configBus:      configbus.New[*AppConfig](logger),
 	}
 
+	// IP Filter
+	ipFilter, err := ipfilter.New(cfg.Security.IPDenyList)
+	if err != nil {
+		return nil, err
+	}
+	infra.ipFilter = ipFilter
+
+	// Login Guard
+	infra.loginGuard, err = cfg.GetLoginGuard()
+	if err != nil {
+		return nil, err
+	}
+
+	// Export Redactor
+	redactor, err := redact.New(redact.Rules{
+		FrontmatterKeys: cfg.Export.Redaction.FrontmatterKeys,
+		ExcludeFolders:  cfg.Export.Redaction.ExcludeFolders,
+		MaskPatterns:    cfg.Export.Redaction.MaskPatterns,
+		Mask:            cfg.Export.Redaction.Mask,
+	})
+	if err != nil {
+		return nil, err
+	}
+	infra.redactor = redactor
+
+	// Secret Scanner
+	if cfg.Security.SecretScan.Enabled {
+		scanner, err := secretscan.New(cfg.Security.SecretScan.Patterns)
+		if err != nil {
+			return nil, err
+		}
+		infra.secretScanner = scanner
+	}
+
+	// Encryption at rest
+	keyring, err := cfg.GetEncryptionKeyring()
+	if err != nil {
+		return nil, err
+	}
+	atrest.SetDefault(keyring)
+
+	// Maintenance Window
+	var window *maintenance.Window
+	if cfg.Maintenance.IsEnabled {
+		duration, err := util.ParseDuration(cfg.Maintenance.Duration)
+		if err != nil {
+			return nil, err
+		}
+		window, err = maintenance.ParseWindow(cfg.Maintenance.Start, duration, cfg.Maintenance.Days, cfg.Maintenance.Timezone)
+		if err != nil {
+			return nil, err
+		}
+	}
+	infra.maintenance = maintenance.New(window, logger)
+	infra.readOnly = readonly.New()
+	infra.syncTracer = synctrace.New(cfg.Log.SyncTraceDir)
+	infra.schedules = schedule.New(logger)
+
 	// Worker Pool
 	wpConfig := cfg.GetWorkerPoolConfig()
 	infra.workerPool = workerpool.New(&wpConfig, logger)
@@ -46,6 +132,10 @@ func initInfra(cfg *AppConfig, logger *zap.Logger, db *gorm.DB) (*Infra, error)
 	wqConfig := cfg.GetWriteQueueConfig()
 	infra.writeQueueMgr = writequeue.New(&wqConfig, logger)
 
+	// Job Queue
+	jqConfig := cfg.GetJobQueueConfig()
+	infra.jobQueue = jobqueue.New(&jqConfig, logger)
+
 	// DAO
 	dbCfg := cfg.Database
 	dbCfg.RunMode = cfg.Server.RunMode
@@ -56,12 +146,28 @@ func initInfra(cfg *AppConfig, logger *zap.Logger, db *gorm.DB) (*Infra, error)
 	// Bleve Manager
 	bleveMgr := dao.NewBleveManager(cfg.App.FtsBleveEnabled, cfg.App.FtsBleveStoreRaw, logger)
 
+	// Content Store
+	contentStore, err := cfg.GetContentStore()
+	if err != nil {
+		return nil, err
+	}
+
+	// Cluster Bus
+	infra.clusterBus, err = cfg.GetClusterBus(logger)
+	if err != nil {
+		return nil, err
+	}
+	infra.readOnly.UseClusterBus(infra.clusterBus, logger)
+
 	infra.Dao = dao.New(db, context.Background(),
 		dao.WithConfig(&dbCfg),
 		dao.WithUserDatabaseConfig(&userDbCfg),
 		dao.WithLogger(logger),
 		dao.WithWriteQueueManager(infra.writeQueueMgr),
 		dao.WithBleveManager(bleveMgr),
+		dao.WithNoteWriteCoalesceWindow(cfg.GetNoteWriteCoalesceWindow()),
+		dao.WithContentStore(contentStore),
+		dao.WithClusterBus(infra.clusterBus),
 	)
 
 	// TokenManager
@@ -74,5 +180,11 @@ func initInfra(cfg *AppConfig, logger *zap.Logger, db *gorm.DB) (*Infra, error)
 	}
 	infra.TokenManager = pkgapp.NewTokenManager(tokenConfig)
 
+	// Token Denylist
+	infra.tokenDenylist, err = cfg.GetTokenDenylist()
+	if err != nil {
+		return nil, err
+	}
+
 	return infra, nil
 }
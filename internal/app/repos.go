@@ -18,12 +18,31 @@ type Repositories struct {
 	FolderRepo       domain.FolderRepository
 	StorageRepo      domain.StorageRepository
 	BackupRepo       domain.BackupRepository
+	BackupBlobRepo   domain.BackupBlobRepository
 	GitSyncRepo      domain.GitSyncRepository
 	SyncLogRepo      domain.SyncLogRepository
 	NoteFTSRepo      domain.NoteFTSRepository
 	AuthTokenRepo    domain.AuthTokenRepository
 	AuthTokenLogRepo domain.AuthTokenLogRepository
 	OIDCIdentityRepo domain.OIDCIdentityRepository
+	UserTOTPRepo     domain.UserTOTPRepository
+	UserInviteRepo   domain.UserInviteRepository
+	PendingUserRepo  domain.PendingUserRepository
+	UserSettingRepo  domain.UserSettingRepository
+	SnapshotRepo     domain.SnapshotRepository
+	NoteAccessRepo   domain.NoteAccessRepository
+	WebhookRepo      domain.WebhookRepository
+	AlertChannelRepo domain.AlertChannelRepository
+	AuditLogRepo     domain.AuditLogRepository
+	NotePolicyRepo   domain.NotePolicyRepository
+	NotePublishRepo  domain.NotePublishRepository
+	DeviceRepo       domain.DeviceRepository
+	NoteTemplateRepo domain.NoteTemplateRepository
+	RetentionRepo    domain.RetentionPolicyRepository
+	NoteMetaRepo     domain.NoteMetaRepository
+	PropertyRepo     domain.NotePropertyRepository
+	NoteStatRepo     domain.NoteStatRepository
+	UsageRepo        domain.UsageRepository
 }
 
 // initRepositories initializes all repositories
@@ -40,11 +59,30 @@ func initRepositories(d *dao.Dao) *Repositories {
 		FolderRepo:       dao.NewFolderRepository(d),
 		StorageRepo:      dao.NewStorageRepository(d),
 		BackupRepo:       dao.NewBackupRepository(d),
+		BackupBlobRepo:   dao.NewBackupBlobRepository(d),
 		GitSyncRepo:      dao.NewGitSyncRepository(d),
 		SyncLogRepo:      dao.NewSyncLogRepository(d),
 		NoteFTSRepo:      dao.NewNoteFTSRepository(d),
 		AuthTokenRepo:    dao.NewAuthTokenRepository(d),
 		AuthTokenLogRepo: dao.NewAuthTokenLogRepository(d),
 		OIDCIdentityRepo: dao.NewOIDCIdentityRepository(d),
+		UserTOTPRepo:     dao.NewUserTOTPRepository(d),
+		UserInviteRepo:   dao.NewUserInviteRepository(d),
+		PendingUserRepo:  dao.NewPendingUserRepository(d),
+		UserSettingRepo:  dao.NewUserSettingRepository(d),
+		SnapshotRepo:     dao.NewSnapshotRepository(d),
+		NoteAccessRepo:   dao.NewNoteAccessRepository(d),
+		WebhookRepo:      dao.NewWebhookRepository(d),
+		AlertChannelRepo: dao.NewAlertChannelRepository(d),
+		AuditLogRepo:     dao.NewAuditLogRepository(d),
+		NotePolicyRepo:   dao.NewNotePolicyRepository(d),
+		NotePublishRepo:  dao.NewNotePublishRepository(d),
+		DeviceRepo:       dao.NewDeviceRepository(d),
+		NoteTemplateRepo: dao.NewNoteTemplateRepository(d),
+		RetentionRepo:    dao.NewRetentionPolicyRepository(d),
+		NoteMetaRepo:     dao.NewNoteMetaRepository(d),
+		PropertyRepo:     dao.NewNotePropertyRepository(d),
+		NoteStatRepo:     dao.NewNoteStatRepository(d),
+		UsageRepo:        dao.NewUsageRepository(d),
 	}
 }
@@ -2,28 +2,63 @@ package app
 
 import (
 	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"go.uber.org/zap"
 )
 
 // Services encapsulates all business service instances
 type Services struct {
-	VaultService       service.VaultService
-	NoteService        service.NoteService
-	UserService        service.UserService
-	TokenService       service.TokenService
-	FileService        service.FileService
-	SettingService     service.SettingService
-	NoteHistoryService service.NoteHistoryService
-	ConflictService    service.ConflictService
-	ShareService       service.ShareService
-	NoteLinkService    service.NoteLinkService
-	FolderService      service.FolderService
-	StorageService     service.StorageService
-	BackupService      service.BackupService
-	GitSyncService     service.GitSyncService
-	CloudflareService  service.CloudflareService
-	SyncLogService     service.SyncLogService
-	OIDCService        service.OIDCService
+	VaultService         service.VaultService
+	NoteService          service.NoteService
+	UserService          service.UserService
+	RegistrationService  service.RegistrationService
+	TokenService         service.TokenService
+	FileService          service.FileService
+	SettingService       service.SettingService
+	UserSettingService   service.UserSettingService
+	NoteHistoryService   service.NoteHistoryService
+	ConflictService      service.ConflictService
+	ShareService         service.ShareService
+	NoteLinkService      service.NoteLinkService
+	FolderService        service.FolderService
+	StorageService       service.StorageService
+	BackupService        service.BackupService
+	GitSyncService       service.GitSyncService
+	CloudflareService    service.CloudflareService
+	SyncLogService       service.SyncLogService
+	OIDCService          service.OIDCService
+	TwoFactorService     service.TwoFactorService
+	SecretScanService    service.SecretScanService
+	ImportService        service.ImportService
+	MigrateService       service.MigrateService
+	SnapshotService      service.SnapshotService
+	FeatureFlagService   service.FeatureFlagService
+	DataInventoryService service.DataInventoryService
+	NoteAccessService    service.NoteAccessService
+	NoteLintService      service.NoteLintService
+	NoteRenderService    service.NoteRenderService
+	NoteCalendarService  service.NoteCalendarService
+	NotificationService  service.NotificationService
+	AlertService         service.AlertService
+	AuditService         service.AuditService
+	LogService           service.LogService
+	AdminStatsService    service.AdminStatsService
+	NotePolicyService    service.NotePolicyService
+	NotePublishService   service.NotePublishService
+	DeviceService        service.DeviceService
+	SyncStatusService    service.SyncStatusService
+	RetentionService     service.RetentionService
+	ThumbnailService     service.ThumbnailService
+	TemplateService      service.TemplateService
+	ReindexService       service.ReindexService
+	NoteMetaService      service.NoteMetaService
+	FrontmatterService   service.NoteFrontmatterService
+	NoteStatsService     service.NoteStatsService
+	FileGCService        service.FileGCService
+	UsageService         service.UsageService
+	ContentLimits        *service.ContentLimits
+
+	serviceConfig *service.ServiceConfig // Settings the services read, reloaded on config changes // 服务读取的设置，配置变更时重新加载
 }
 
 // initServices initializes all services
@@ -32,6 +67,12 @@ func initServices(cfg *AppConfig, infra *Infra, repos *Repositories, logger *zap
 		User: service.UserServiceConfig{
 			RegisterIsEnable: cfg.User.RegisterIsEnable,
 			AdminUID:         cfg.User.AdminUID,
+			RequireAdminTOTP: cfg.Security.RequireAdminTOTP,
+			TOTPIssuer:       cfg.Security.TOTPIssuer,
+			RegisterApproval: cfg.User.RegisterApproval,
+
+			PasswordResetExpiry: cfg.User.PasswordResetExpiry,
+			PasswordResetURL:    cfg.Server.ExtApiUrl,
 		},
 		Token: service.TokenServiceConfig{
 			WebGUILoginTokenExpiry: cfg.Security.WebGUILoginTokenExpiry,
@@ -42,6 +83,10 @@ func initServices(cfg *AppConfig, infra *Infra, repos *Repositories, logger *zap
 			HistoryKeepVersions:     cfg.App.HistoryKeepVersions,
 			HistorySaveDelay:        cfg.App.HistorySaveDelay,
 			ShareTokenExpiry:        cfg.Security.ShareTokenExpiry,
+			TempPath:                cfg.App.TempPath,
+			Limits:                  service.NewContentLimits(util.ParseSize(cfg.App.MaxNoteSize, 0), util.ParseSize(cfg.App.MaxAttachmentSize, 0), int64(cfg.App.MaxNotesPerVault)),
+			SyncTrace:               infra.syncTracer,
+			Jobs:                    infra.jobQueue,
 			ShortLink: service.ShortLinkServiceConfig{
 				BaseURL:  cfg.ShortLink.BaseURL,
 				APIKey:   cfg.ShortLink.APIKey,
@@ -51,7 +96,14 @@ func initServices(cfg *AppConfig, infra *Infra, repos *Repositories, logger *zap
 		},
 	}
 
-	s := &Services{}
+	s := &Services{ContentLimits: svcConfig.App.Limits, serviceConfig: svcConfig}
+	// NotificationService only depends on its repository, created first so services can report events to it
+	// NotificationService 仅依赖自身仓储，最先创建以便其他服务向其上报事件
+	s.NotificationService = service.NewNotificationService(repos.WebhookRepo, logger)
+	s.AlertService = service.NewAlertService(repos.AlertChannelRepo, &cfg.Alert, logger)
+	s.AuditService = service.NewAuditService(repos.AuditLogRepo, logger)
+	s.LogService = service.NewLogService(infra.logBuffer)
+	s.AdminStatsService = service.NewAdminStatsService(repos.UserRepo, repos.VaultRepo, repos.SyncLogRepo, repos.BackupRepo, logger)
 	s.VaultService = service.NewVaultService(
 		repos.VaultRepo,
 		repos.NoteRepo,
@@ -67,26 +119,81 @@ func initServices(cfg *AppConfig, infra *Infra, repos *Repositories, logger *zap
 		repos.BackupRepo,
 		logger,
 	)
+	s.VaultService.SetAuditLogRepository(repos.AuditLogRepo)
 	s.StorageService = service.NewStorageService(repos.StorageRepo, &cfg.Storage)
-	s.BackupService = service.NewBackupService(repos.BackupRepo, repos.NoteRepo, repos.FolderRepo, repos.FileRepo, repos.VaultRepo, s.StorageService, &cfg.Storage, cfg.App.TempPath, logger)
+	s.BackupService = service.NewBackupService(repos.BackupRepo, repos.BackupBlobRepo, repos.NoteRepo, repos.FolderRepo, repos.FileRepo, repos.VaultRepo, s.StorageService, &cfg.Storage, infra.redactor, cfg.App.TempPath, logger)
+	s.BackupService.SetMaintenance(infra.maintenance)
+	s.BackupService.SetNotifier(s.NotificationService)
+	s.BackupService.SetAlerter(s.AlertService)
 	s.GitSyncService = service.NewGitSyncService(repos.GitSyncRepo, repos.NoteRepo, repos.FolderRepo, repos.FileRepo, repos.VaultRepo, repos.SettingRepo, &cfg.Git, logger)
+	s.GitSyncService.SetNotifier(s.NotificationService)
 
 	// Initialize SyncLogService first, as NoteService/FileService/SettingService depend on it
 	// SyncLogService 必须最先初始化，因为其他服务依赖它
 	s.SyncLogService = service.NewSyncLogService(repos.SyncLogRepo, logger)
+	s.RetentionService = service.NewRetentionService(repos.RetentionRepo, repos.VaultRepo, repos.UserRepo, logger, svcConfig)
 
 	s.FolderService = service.NewFolderService(repos.FolderRepo, repos.NoteRepo, repos.FileRepo, s.VaultService, s.BackupService, s.GitSyncService, s.SyncLogService, infra.workerPool)
-	s.NoteService = service.NewNoteService(repos.UserRepo, repos.NoteRepo, repos.NoteLinkRepo, repos.FileRepo, repos.ShareRepo, s.VaultService, s.FolderService, s.BackupService, s.GitSyncService, s.SyncLogService, svcConfig)
+	s.NoteService = service.NewNoteService(repos.UserRepo, repos.NoteRepo, repos.NoteLinkRepo, repos.PropertyRepo, repos.NoteStatRepo, repos.FileRepo, repos.ShareRepo, s.VaultService, s.FolderService, s.BackupService, s.GitSyncService, s.SyncLogService, s.RetentionService, s.NotificationService, svcConfig)
 	s.TokenService = service.NewTokenService(repos.AuthTokenRepo, repos.AuthTokenLogRepo, infra.TokenManager, logger, svcConfig.Token)
-	s.UserService = service.NewUserService(repos.UserRepo, infra.TokenManager, s.TokenService, logger, svcConfig)
-	s.OIDCService = service.NewOIDCService(repos.UserRepo, repos.OIDCIdentityRepo, s.TokenService)
-	s.FileService = service.NewFileService(repos.UserRepo, repos.FileRepo, repos.NoteRepo, s.VaultService, s.FolderService, s.BackupService, s.GitSyncService, s.SyncLogService, svcConfig)
-	s.SettingService = service.NewSettingService(repos.SettingRepo, s.VaultService, s.SyncLogService, svcConfig)
+	s.TokenService.SetDenylist(infra.tokenDenylist)
+	s.SecretScanService = service.NewSecretScanService(infra.secretScanner, cfg.Security.SecretScan.Strict, logger)
+	s.NoteService.SetSecretScan(s.SecretScanService)
+	s.DeviceService = service.NewDeviceService(repos.DeviceRepo, s.TokenService, logger)
+	s.SyncStatusService = service.NewSyncStatusService(repos.VaultRepo, repos.SyncLogRepo, repos.DeviceRepo, logger)
+	s.TwoFactorService = service.NewTwoFactorService(repos.UserTOTPRepo, repos.UserRepo, logger, svcConfig)
+	s.UserService = service.NewUserService(repos.UserRepo, infra.TokenManager, s.TokenService, s.TwoFactorService, s.NotificationService, logger, svcConfig)
+	s.RegistrationService = service.NewRegistrationService(repos.UserInviteRepo, repos.PendingUserRepo, repos.UserRepo, logger)
+	s.UserService.SetRegistration(s.RegistrationService)
+	s.UserSettingService = service.NewUserSettingService(repos.UserSettingRepo, logger)
+	// Password reset emails go through the SMTP server of the alert channels
+	// 密码重置邮件通过告警渠道的 SMTP 服务器发送
+	s.UserService.SetMailer(s.AlertService.Mailer())
+	s.OIDCService = service.NewOIDCService(repos.UserRepo, repos.OIDCIdentityRepo, s.TokenService, s.TwoFactorService)
+	s.FileService = service.NewFileService(repos.UserRepo, repos.FileRepo, repos.NoteRepo, s.VaultService, s.FolderService, s.BackupService, s.GitSyncService, s.SyncLogService, s.RetentionService, svcConfig)
+	s.ThumbnailService = service.NewThumbnailService(s.FileService, &cfg.Thumbnail, logger)
+	s.SettingService = service.NewSettingService(repos.SettingRepo, s.VaultService, s.SyncLogService, s.RetentionService, svcConfig)
 	s.NoteHistoryService = service.NewNoteHistoryService(repos.NoteHistoryRepo, repos.NoteRepo, repos.UserRepo, s.VaultService, s.FolderService, s.NoteService, s.BackupService, s.GitSyncService, logger, &svcConfig.App)
+	s.NoteHistoryService.SetSecretScan(s.SecretScanService)
 	s.ConflictService = service.NewConflictService(repos.NoteRepo, s.VaultService, logger)
-	s.ShareService = service.NewShareService(repos.ShareRepo, infra.TokenManager, repos.NoteRepo, repos.FileRepo, repos.VaultRepo, logger, svcConfig)
+	s.ShareService = service.NewShareService(repos.ShareRepo, infra.TokenManager, repos.NoteRepo, repos.FileRepo, repos.FolderRepo, repos.VaultRepo, logger, svcConfig)
 	s.NoteLinkService = service.NewNoteLinkService(repos.NoteLinkRepo, repos.NoteRepo, s.VaultService)
 	s.CloudflareService = service.NewCloudflareService(logger)
+	s.ImportService = service.NewImportService(s.VaultService, s.NoteService, s.FileService, s.FolderService, svcConfig.App.Limits, cfg.App.TempPath, logger)
+	s.MigrateService = service.NewMigrateService(s.VaultService, s.NoteService, s.FileService, repos.NoteRepo, repos.NoteHistoryRepo, repos.FileRepo, repos.SettingRepo, repos.UserRepo, cfg.App.TempPath, logger)
+	s.SnapshotService = service.NewSnapshotService(repos.SnapshotRepo, &cfg.Snapshot, logger)
+	s.FeatureFlagService = service.NewFeatureFlagService(&cfg.FeatureFlags)
+	s.NoteAccessService = service.NewNoteAccessService(repos.NoteAccessRepo, repos.NoteRepo, s.VaultService, logger)
+	s.NoteMetaService = service.NewNoteMetaService(repos.NoteMetaRepo, repos.NoteRepo, s.VaultService, logger)
+	s.FrontmatterService = service.NewNoteFrontmatterService(repos.PropertyRepo, repos.NoteRepo, s.VaultService, logger)
+	s.NoteStatsService = service.NewNoteStatsService(repos.NoteStatRepo, repos.NoteRepo, s.VaultService, logger)
+	s.NoteLintService = service.NewNoteLintService(&cfg.Lint, repos.NoteRepo, s.VaultService, logger)
+	s.NoteRenderService = service.NewNoteRenderService(repos.NoteRepo, s.VaultService, s.FileService, s.NoteLinkService, logger)
+	s.NoteCalendarService = service.NewNoteCalendarService(repos.NoteRepo, s.VaultService, logger)
+	s.NotePolicyService = service.NewNotePolicyService(repos.NotePolicyRepo, repos.NoteRepo, s.VaultService, s.NoteService, logger)
+	s.NotePublishService = service.NewNotePublishService(repos.NotePublishRepo, repos.NoteRepo, s.VaultService, s.ShareService, logger)
+	s.TemplateService = service.NewTemplateService(repos.NoteTemplateRepo, logger)
+	s.ReindexService = service.NewReindexService(repos.NoteFTSRepo, repos.UserRepo, cfg.App.FtsBleveEnabled == nil || *cfg.App.FtsBleveEnabled, logger)
+	s.FileGCService = service.NewFileGCService(repos.FileRepo, repos.UserRepo, cfg.App.FileGCGracePeriod, logger)
+	s.UsageService = service.NewUsageService(repos.UsageRepo, repos.UserRepo, repos.VaultRepo, cfg.App.TempPath, logger)
+	s.DataInventoryService = service.NewDataInventoryService(
+		repos.UserRepo,
+		repos.OIDCIdentityRepo,
+		repos.UserTOTPRepo,
+		repos.VaultRepo,
+		repos.NoteRepo,
+		repos.FileRepo,
+		repos.SettingRepo,
+		repos.NoteHistoryRepo,
+		repos.ShareRepo,
+		repos.AuthTokenRepo,
+		repos.AuthTokenLogRepo,
+		repos.SyncLogRepo,
+		repos.StorageRepo,
+		repos.BackupRepo,
+		repos.GitSyncRepo,
+		logger,
+	)
 
 	return s
 }
@@ -3,14 +3,17 @@
 package app
 
 import (
+	"github.com/haierkeys/fast-note-sync-service/pkg/configbus"
+	"github.com/haierkeys/fast-note-sync-service/pkg/loginguard"
+	"github.com/haierkeys/fast-note-sync-service/pkg/readonly"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 // NewTestApp creates a minimal App instance for unit testing.
 // NewTestApp 创建用于单元测试的最小 App 实例。
-// Only the Services field and a nop logger are initialized;
-// 仅初始化 Services 字段和 nop logger；
+// Only the Services field, a nop logger, the config bus and the read-only switch are initialized;
+// 仅初始化 Services 字段、nop logger、配置总线和只读开关；
 // all other infrastructure fields remain zero/nil.
 // 所有其他基础设施字段保持零值/nil。
 func NewTestApp(svcs *Services, dbs ...*gorm.DB) *App {
@@ -21,10 +24,18 @@ func NewTestApp(svcs *Services, dbs ...*gorm.DB) *App {
 
 	return &App{
 		Infra: &Infra{
-			logger: zap.NewNop(), // safe nop logger, prevents Logger() panic // 安全的 nop logger，防止 Logger() panic
-			config: &AppConfig{},
-			DB:     db,
+			logger:    zap.NewNop(), // safe nop logger, prevents Logger() panic // 安全的 nop logger，防止 Logger() panic
+			config:    &AppConfig{},
+			DB:        db,
+			configBus: configbus.New[*AppConfig](nil),
+			readOnly:  readonly.New(),
 		},
 		Services: svcs,
 	}
 }
+
+// SetLoginGuard replaces the failed login counters, for tests that exercise the lockout.
+// SetLoginGuard 替换登录失败计数器，供测试锁定逻辑使用。
+func (a *App) SetLoginGuard(guard *loginguard.Guard) {
+	a.loginGuard = guard
+}
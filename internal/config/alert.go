@@ -0,0 +1,58 @@
+package config
+
+// AlertConfig alerting of failed backups through email, Telegram, Gotify and ntfy
+// AlertConfig 通过邮件、Telegram、Gotify 与 ntfy 发送备份失败告警
+type AlertConfig struct {
+	// Cooldown minimum interval between two alerts about the same backup task, e.g. 1h; 0 alerts on every failure
+	// Cooldown 同一备份任务两次告警的最小间隔，例如 1h；为 0 时每次失败都告警
+	Cooldown string `yaml:"cooldown" default:"1h"`
+	// SMTP mail server used by email channels of the admin and of users
+	// SMTP 管理员与用户的 email 渠道使用的邮件服务器
+	SMTP AlertSMTPConfig `yaml:"smtp"`
+	// Channels admin channels receiving backup failures of every user
+	// Channels 接收所有用户备份失败告警的管理员渠道
+	Channels []AlertChannelConfig `yaml:"channels"`
+}
+
+// AlertSMTPConfig mail server used by email channels
+// AlertSMTPConfig email 渠道使用的邮件服务器
+type AlertSMTPConfig struct {
+	// Host SMTP host, empty disables email channels
+	// Host SMTP 主机，为空时禁用 email 渠道
+	Host string `yaml:"host" default:""`
+	// Port SMTP port, 465 uses implicit TLS, other ports upgrade with STARTTLS
+	// Port SMTP 端口，465 使用隐式 TLS，其他端口使用 STARTTLS
+	Port     int    `yaml:"port" default:"587"`
+	Username string `yaml:"username" default:""`
+	Password string `yaml:"password" default:""`
+	// From sender address
+	// From 发件人地址
+	From string `yaml:"from" default:""`
+	// InsecureSkipVerify skip TLS certificate verification of the SMTP server
+	// InsecureSkipVerify 跳过 SMTP 服务器的 TLS 证书校验
+	InsecureSkipVerify bool `yaml:"insecure-skip-verify" default:"false"`
+}
+
+// AlertChannelConfig one alert destination; which fields are used depends on Type
+// AlertChannelConfig 一个告警目标，使用哪些字段取决于 Type
+type AlertChannelConfig struct {
+	// Type email, telegram, gotify or ntfy
+	// Type email、telegram、gotify 或 ntfy
+	Type string `yaml:"type"`
+	Name string `yaml:"name"`
+	// To email: comma separated recipients
+	// To email：逗号分隔的收件人
+	To string `yaml:"to"`
+	// URL gotify: server URL; ntfy: topic URL; telegram: optional Bot API root
+	// URL gotify：服务器地址；ntfy：主题地址；telegram：可选的 Bot API 根地址
+	URL string `yaml:"url"`
+	// Token telegram: bot token; gotify: application token; ntfy: optional access token
+	// Token telegram：机器人令牌；gotify：应用令牌；ntfy：可选访问令牌
+	Token string `yaml:"token"`
+	// ChatID telegram: chat, group or channel ID
+	// ChatID telegram：会话、群组或频道 ID
+	ChatID string `yaml:"chat-id"`
+	// Priority gotify (0-10) and ntfy (1-5) priority, 0 uses the server default
+	// Priority gotify（0-10）与 ntfy（1-5）优先级，0 使用服务器默认值
+	Priority int `yaml:"priority"`
+}
@@ -25,9 +25,23 @@ type AppSettings struct {
 	// SyncLogRetentionTime retention time for sync logs
 	// SyncLogRetentionTime 同步日志保留时间
 	SyncLogRetentionTime string `yaml:"sync-log-retention-time" default:"30d"`
+	// NoteAccessLogRetentionTime retention time for note access logs
+	// NoteAccessLogRetentionTime 笔记访问日志保留时间
+	NoteAccessLogRetentionTime string `yaml:"note-access-log-retention-time" default:"90d"`
+	// WebhookDeliveryRetentionTime retention time for the webhook delivery log
+	// WebhookDeliveryRetentionTime Webhook 投递日志保留时间
+	WebhookDeliveryRetentionTime string `yaml:"webhook-delivery-retention-time" default:"30d"`
+	// AuditLogRetentionTime retention time for the audit log, 0 keeps it forever
+	// AuditLogRetentionTime 审计日志保留时间，0 表示永久保留
+	AuditLogRetentionTime string `yaml:"audit-log-retention-time" default:"180d"`
 	// HistoryKeepVersions number of historical versions to keep, default 100; yaml 显式 0 = 无限保留不清理，nil 才用默认 100
 	// HistoryKeepVersions 历史记录保留版本数，默认 100；yaml 显式 0 = 无限保留不清理，nil 才用默认 100
 	HistoryKeepVersions *int `yaml:"history-keep-versions" default:"100"`
+	// HistoryKeepDuration maximum age of history versions (e.g. 180d, 0 for no limit); when set, old versions are thinned
+	// to one per day after 30 days and one per week after 6 months instead of following SoftDeleteRetentionTime
+	// HistoryKeepDuration 历史版本最长保留时间（如 180d，0 表示不限）；配置后旧版本在 30 天后每天保留一个、
+	// 6 个月后每周保留一个，不再跟随 SoftDeleteRetentionTime 清理
+	HistoryKeepDuration string `yaml:"history-keep-duration"`
 	// HistorySaveDelay historical record save delay time, supports format: 10s (seconds), 1m (minutes), default 10s
 	// HistorySaveDelay历史记录保存延迟时间，支持格式：10s（秒）、1m（分钟），默认 10s
 	HistorySaveDelay string `yaml:"history-save-delay" default:"10s"`
@@ -40,17 +54,42 @@ type AppSettings struct {
 	// DownloadSessionTimeout file chunk download timeout duration
 	// DownloadSessionTimeout 文件分片下载超时时间
 	DownloadSessionTimeout string `yaml:"download-session-timeout" default:"1h"`
+	// FileGCGracePeriod age an orphaned attachment folder must reach before the daily garbage collection removes it (0 only reports)
+	// FileGCGracePeriod 孤立附件目录达到该时长后才会被每日垃圾回收删除（0 表示仅报告不删除）
+	FileGCGracePeriod string `yaml:"file-gc-grace-period" default:"7d"`
+	// RenameUpdateLinks whether renaming a note rewrites the wiki links pointing at it, when the request does not say
+	// RenameUpdateLinks 请求未指定时，重命名笔记是否改写指向它的 Wiki 链接
+	RenameUpdateLinks bool `yaml:"rename-update-links" default:"false"`
+	// MaxNoteSize largest note content accepted (e.g. 5MB), "0" for no limit
+	// MaxNoteSize 单篇笔记正文的最大大小（如 5MB），"0" 表示不限制
+	MaxNoteSize string `yaml:"max-note-size" default:"0"`
+	// MaxAttachmentSize largest attachment accepted (e.g. 100MB), "0" for no limit
+	// MaxAttachmentSize 单个附件的最大大小（如 100MB），"0" 表示不限制
+	MaxAttachmentSize string `yaml:"max-attachment-size" default:"0"`
+	// MaxNotesPerVault most notes a vault may hold, 0 for no limit
+	// MaxNotesPerVault 每个笔记库最多可保存的笔记数量，0 表示不限制
+	MaxNotesPerVault int `yaml:"max-notes-per-vault" default:"0"`
 
 	// Worker Pool configurations
 	// Worker Pool 配置
 	WorkerPoolMaxWorkers int `yaml:"worker-pool-max-workers" default:"100"`
 	WorkerPoolQueueSize  int `yaml:"worker-pool-queue-size" default:"1000"`
 
+	// Job Queue configurations, for background jobs such as link indexing, vault counts and note history
+	// Job Queue 配置，用于链接索引、笔记库统计、笔记历史等后台任务
+	JobQueueWorkers     int    `yaml:"job-queue-workers" default:"4"`
+	JobQueueSize        int    `yaml:"job-queue-size" default:"1000"`
+	JobQueueMaxAttempts int    `yaml:"job-queue-max-attempts" default:"3"`
+	JobQueueRetryDelay  string `yaml:"job-queue-retry-delay" default:"2s"`
+
 	// Write Queue configurations
 	// Write Queue 配置
 	WriteQueueCapacity int    `yaml:"write-queue-capacity" default:"1000"`
 	WriteQueueTimeout  string `yaml:"write-queue-timeout" default:"30s"`
 	WriteQueueIdleTime string `yaml:"write-queue-idle-time" default:"10m"`
+	// NoteWriteCoalesceWindow buffers rapid successive updates of the same note and writes them once per window; 0 disables
+	// NoteWriteCoalesceWindow 缓冲同一笔记的连续更新并在每个窗口期内只写入一次；0 表示禁用
+	NoteWriteCoalesceWindow string `yaml:"note-write-coalesce-window" default:"0"`
 
 	// WebSocket configurations
 	// WebSocket 配置
@@ -62,12 +101,48 @@ type AppSettings struct {
 	WebSocketCompressionEnabled   *bool  `yaml:"ws-compression-enabled" default:"true"`
 	WebSocketCompressionLevel     int    `yaml:"ws-compression-level" default:"1"`
 	WebSocketCompressionThreshold int    `yaml:"ws-compression-threshold" default:"512"`
+	// WebSocketMsgpackEnabled lets clients request MessagePack sync payloads with the "protocol=msgpack" handshake query
+	// WebSocketMsgpackEnabled 允许客户端通过握手 query "protocol=msgpack" 请求 MessagePack 同步负载
+	WebSocketMsgpackEnabled *bool `yaml:"ws-msgpack-enabled" default:"true"`
 	// WebSocketWriteTimeout application-layer write deadline (seconds) for outbound messages
 	// (ToResponse/BroadcastResponse/SendBinary etc.), guarding against zombie connections blocking
 	// WriteMessage indefinitely; yaml 显式 0 = 不设写超时（旧行为），nil 才用默认 10
 	// WebSocketWriteTimeout WebSocket 应用层出站消息（ToResponse/BroadcastResponse/SendBinary 等）
 	// 的写超时（秒），防止僵尸连接让 WriteMessage 无限阻塞；yaml 显式 0 = 不设写超时（旧行为），nil 才用默认 10
 	WebSocketWriteTimeout *int `yaml:"ws-write-timeout" default:"10"`
+	// WebSocketEchoWindow window in which an identical NoteModify (same note and content hash) is
+	// acknowledged without being processed again, breaking client re-broadcast loops; "0" disables
+	// WebSocketEchoWindow 相同 NoteModify（同一笔记、同一内容哈希）在该时间窗口内直接确认而不重复处理，
+	// 用于打断客户端之间的互相回传循环；"0" 表示关闭
+	WebSocketEchoWindow string `yaml:"ws-echo-window" default:"1s"`
+	// WebSocketSyncJournalSize sync events queued per user so a reconnecting device can replay what it
+	// missed with SyncResume; older events make the device fall back to a full sync
+	// WebSocketSyncJournalSize 每个用户排队的同步事件数，重连设备可通过 SyncResume 重放错过的事件；
+	// 更早的事件会使设备回退到完整同步
+	WebSocketSyncJournalSize int `yaml:"ws-sync-journal-size" default:"500"`
+	// WebSocketSyncJournalTTL how long queued sync events and idle device cursors are kept
+	// WebSocketSyncJournalTTL 排队同步事件与空闲设备游标的保留时长
+	WebSocketSyncJournalTTL string `yaml:"ws-sync-journal-ttl" default:"24h"`
+	// WebSocketMaxConnections WebSocket connections the server accepts in total, 0 means unlimited
+	// WebSocketMaxConnections 服务器总共接受的 WebSocket 连接数，0 表示不限制
+	WebSocketMaxConnections int `yaml:"ws-max-connections" default:"10000"`
+	// WebSocketMaxConnectionsPerUser authenticated WebSocket connections per user, 0 means unlimited
+	// WebSocketMaxConnectionsPerUser 每个用户已认证的 WebSocket 连接数，0 表示不限制
+	WebSocketMaxConnectionsPerUser int `yaml:"ws-max-connections-per-user" default:"32"`
+	// WebSocketPingInterval seconds between server pings
+	// WebSocketPingInterval 服务端发送 ping 的间隔（秒）
+	WebSocketPingInterval int `yaml:"ws-ping-interval" default:"25"`
+	// WebSocketPingWait seconds without any frame or pong before the connection is force closed
+	// WebSocketPingWait 未收到任何帧或 pong 的秒数，超过后强制关闭连接
+	WebSocketPingWait int `yaml:"ws-ping-wait" default:"60"`
+	// WebSocketAuthTimeout how long a connection may stay open without authorizing, "0" disables
+	// WebSocketAuthTimeout 连接未完成鉴权时允许保持的时长，"0" 表示不限制
+	WebSocketAuthTimeout string `yaml:"ws-auth-timeout" default:"30s"`
+	// WebSocketSendQueueSize outbound frames queued per connection; a connection whose queue is full
+	// is closed as a slow consumer and catches up with SyncResume or a full sync after reconnecting
+	// WebSocketSendQueueSize 每个连接排队的出站帧数；队列已满的连接作为慢消费者被关闭，
+	// 重连后通过 SyncResume 或完整同步补齐
+	WebSocketSendQueueSize int `yaml:"ws-send-queue-size" default:"256"`
 	// PullSource data pull source: auto | github | cnb
 	// PullSource 数据拉取源：auto | github | cnb
 	PullSource string `yaml:"pull-source" default:"auto"`
@@ -83,6 +158,11 @@ type AppSettings struct {
 	FtsBleveStoreRaw *bool `yaml:"fts-bleve-store-raw" default:"false"` // Bleve FTS store raw content flag // Bleve 全文搜索是否存储原始文本（默认启用为方案 B，若设为 false 则为仅索引不存储的方案 A）
 	SyncDownChunkNum int `yaml:"sync-down-chunk-num" default:"200"` // Serial download sync page chunk size // 串行下载同步的分块数量
 	SyncUpChunkNum   int `yaml:"sync-up-chunk-num" default:"100"`  // Serial upload sync batch size // 串行上传同步的分包大小
+	// SyncDownPageBytes note content per download page; a page closes early once its notes reach it,
+	// a larger note is sent alone in its own page. "0" only limits pages by sync-down-chunk-num
+	// SyncDownPageBytes 每个下载分页的笔记正文总量；页内笔记达到该值时提前结束本页，
+	// 超过该值的笔记单独成页。"0" 表示仅按 sync-down-chunk-num 分页
+	SyncDownPageBytes string `yaml:"sync-down-page-bytes" default:"8MB"`
 
 	// PipelineWindowUp negotiated upload sliding-window size for pv>=2 connections; 0 disables
 	// the window (stop-and-wait, same as pre-3.6.0 behavior — this is the runtime rollback
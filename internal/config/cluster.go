@@ -0,0 +1,45 @@
+package config
+
+// ClusterConfig multi-instance deployment. The instances share an external database (mysql or postgres
+// for both database and user-database) and either a content store or a shared storage/vault volume;
+// broadcasts, kicks and cache invalidations are relayed between them through Redis pub/sub.
+// ClusterConfig 多实例部署配置。各实例共享外部数据库（database 与 user-database 均为 mysql 或 postgres），
+// 以及内容存储或共享的 storage/vault 卷；广播、踢下线与缓存失效通知通过 Redis 发布/订阅在实例间转发。
+type ClusterConfig struct {
+	// IsEnabled whether this instance runs as part of a multi-instance deployment
+	// IsEnabled 是否作为多实例部署的一部分运行
+	IsEnabled bool `yaml:"is-enable" default:"false"`
+	// InstanceID unique name of this instance, empty uses the machine ID plus the process ID
+	// InstanceID 当前实例的唯一名称，为空时使用机器标识加进程号
+	InstanceID string `yaml:"instance-id"`
+	// RunTasks whether this instance runs the scheduled tasks, keep it on for exactly one instance;
+	// an explicit false in yaml turns it off, nil defaults to true
+	// RunTasks 当前实例是否执行定时任务，应只在一个实例上开启；yaml 显式 false 关闭，nil 才用默认 true
+	RunTasks *bool `yaml:"run-tasks" default:"true"`
+	// Redis server relaying the events between the instances
+	// Redis 在实例间转发事件的 Redis 服务
+	Redis ClusterRedisConfig `yaml:"redis"`
+}
+
+// ClusterRedisConfig Redis server used as the pub/sub bridge
+// ClusterRedisConfig 用作发布/订阅桥的 Redis 服务
+type ClusterRedisConfig struct {
+	// Addr host:port of the server
+	// Addr 服务地址 host:port
+	Addr string `yaml:"addr" default:"127.0.0.1:6379"`
+	// Username ACL user name, empty for the default user
+	// Username ACL 用户名，默认用户留空
+	Username string `yaml:"username"`
+	// Password password
+	// Password 密码
+	Password string `yaml:"password"`
+	// DB database number
+	// DB 数据库编号
+	DB int `yaml:"db"`
+	// Channel channel prefix, the instances of one deployment must share it
+	// Channel 频道前缀，同一部署的实例必须一致
+	Channel string `yaml:"channel" default:"fns"`
+	// TLS connect with TLS
+	// TLS 使用 TLS 连接
+	TLS bool `yaml:"tls"`
+}
@@ -0,0 +1,50 @@
+package config
+
+// ContentStoreConfig where the content files of the users (note content, history, settings and attachments) are kept.
+// storage/vault always holds a local copy; with a store configured it is only a cache that is refilled on demand,
+// so the server can run on an ephemeral disk.
+// ContentStoreConfig 用户内容文件（笔记内容、历史、配置与附件）的存储配置。
+// storage/vault 始终保存一份本地副本；配置存储后它仅作为按需回填的缓存，服务可运行在临时磁盘上。
+type ContentStoreConfig struct {
+	// Type store backend: local or s3
+	// Type 存储后端：local 或 s3
+	Type string `yaml:"type" default:"local"`
+	// LocalPath directory the local backend writes through to, e.g. a mounted persistent volume; empty keeps the content in storage/vault only
+	// LocalPath 本地后端同步写入的目录，例如挂载的持久卷；为空时内容仅保存在 storage/vault
+	LocalPath string `yaml:"local-path"`
+	// S3 bucket of the s3 backend
+	// S3 s3 后端的存储桶
+	S3 ContentStoreS3Config `yaml:"s3"`
+}
+
+// ContentStoreS3Config S3-compatible bucket holding the user content
+// ContentStoreS3Config 保存用户内容的 S3 兼容存储桶
+type ContentStoreS3Config struct {
+	// Endpoint custom endpoint for S3-compatible servers (MinIO, Ceph RGW, ...), empty for AWS
+	// Endpoint S3 兼容服务（MinIO、Ceph RGW 等）的自定义端点，AWS 留空
+	Endpoint string `yaml:"endpoint"`
+	// Region bucket region
+	// Region 存储桶区域
+	Region string `yaml:"region"`
+	// BucketName bucket name
+	// BucketName 存储桶名称
+	BucketName string `yaml:"bucket-name"`
+	// AccessKeyID access key ID
+	// AccessKeyID 访问密钥 ID
+	AccessKeyID string `yaml:"access-key-id"`
+	// AccessKeySecret access key secret
+	// AccessKeySecret 访问密钥
+	AccessKeySecret string `yaml:"access-key-secret"`
+	// Prefix key prefix inside the bucket
+	// Prefix 存储桶内的键前缀
+	Prefix string `yaml:"prefix"`
+	// PathStyle address the bucket as endpoint/bucket instead of bucket.endpoint
+	// PathStyle 以 endpoint/bucket 而非 bucket.endpoint 方式访问存储桶
+	PathStyle bool `yaml:"path-style"`
+	// CACert PEM CA bundle trusted in addition to the system roots
+	// CACert 在系统根证书之外额外信任的 PEM CA 证书
+	CACert string `yaml:"ca-cert"`
+	// InsecureSkipVerify skip TLS certificate verification
+	// InsecureSkipVerify 跳过 TLS 证书校验
+	InsecureSkipVerify bool `yaml:"insecure-skip-verify"`
+}
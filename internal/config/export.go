@@ -0,0 +1,26 @@
+package config
+
+// ExportConfig export and backup configuration
+// ExportConfig 导出与备份配置
+type ExportConfig struct {
+	// Redaction rules applied to notes leaving the server via backup or export
+	// Redaction 笔记通过备份或导出离开服务器时应用的脱敏规则
+	Redaction ExportRedactionConfig `yaml:"redaction"`
+}
+
+// ExportRedactionConfig export redaction configuration
+// ExportRedactionConfig 导出脱敏配置
+type ExportRedactionConfig struct {
+	// FrontmatterKeys frontmatter key glob patterns to strip, case-insensitive (e.g. "api_*")
+	// FrontmatterKeys 需移除的 frontmatter 键通配模式，不区分大小写（如 "api_*"）
+	FrontmatterKeys []string `yaml:"frontmatter-keys"`
+	// ExcludeFolders vault-relative folders excluded from backups and exports
+	// ExcludeFolders 从备份与导出中排除的 Vault 相对目录
+	ExcludeFolders []string `yaml:"exclude-folders"`
+	// MaskPatterns regular expressions whose matches in note content are masked
+	// MaskPatterns 笔记内容中需遮盖的正则表达式
+	MaskPatterns []string `yaml:"mask-patterns"`
+	// Mask replacement text for masked matches
+	// Mask 遮盖替换文本
+	Mask string `yaml:"mask" default:"[REDACTED]"`
+}
@@ -0,0 +1,16 @@
+package config
+
+// FeatureFlagConfig rollout rule of one experimental feature
+// FeatureFlagConfig 单个实验性功能的灰度规则
+type FeatureFlagConfig struct {
+	// Enabled turns the feature on for every user
+	// Enabled 为所有用户开启该功能
+	Enabled bool `yaml:"enabled" default:"false"`
+	// UIDs canary users the feature is turned on for while it is not enabled globally
+	// UIDs 未全局开启时，为其开启该功能的灰度用户
+	UIDs []int64 `yaml:"uids"`
+}
+
+// FeatureFlagsConfig rollout rules keyed by feature name
+// FeatureFlagsConfig 以功能名为键的灰度规则
+type FeatureFlagsConfig map[string]FeatureFlagConfig
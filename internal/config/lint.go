@@ -0,0 +1,28 @@
+package config
+
+// LintConfig spelling and grammar check configuration of /api/note/lint
+// LintConfig /api/note/lint 拼写与语法检查配置
+type LintConfig struct {
+	// IsEnabled whether the lint endpoint is available
+	// IsEnabled 是否开启检查接口
+	IsEnabled bool `yaml:"is-enable" default:"false"`
+	// LanguageToolURL root of a LanguageTool server, e.g. http://languagetool:8010; empty uses the word list below
+	// LanguageToolURL LanguageTool 服务器根地址，例如 http://languagetool:8010；为空时使用下方词表
+	LanguageToolURL string `yaml:"languagetool-url" default:""`
+	// Username and APIKey of a LanguageTool premium account, optional
+	// Username 与 APIKey 为 LanguageTool 高级版账号，可选
+	Username string `yaml:"username" default:""`
+	APIKey   string `yaml:"api-key" default:""`
+	// Dictionary word list (one word per line, Hunspell .dic accepted) of the bundled spellchecker
+	// Dictionary 内置拼写检查器的词表（每行一个单词，可使用 Hunspell .dic）
+	Dictionary string `yaml:"dictionary" default:""`
+	// DefaultLanguage language used when the request does not set one, "auto" lets LanguageTool detect it
+	// DefaultLanguage 请求未指定语言时使用的语言，"auto" 表示由 LanguageTool 自动识别
+	DefaultLanguage string `yaml:"default-language" default:"auto"`
+	// Timeout of one LanguageTool request
+	// Timeout 单次 LanguageTool 请求的超时时间
+	Timeout string `yaml:"timeout" default:"10s"`
+	// MaxTextLength longest text in bytes accepted per request
+	// MaxTextLength 单次请求接受的最大文本字节数
+	MaxTextLength int `yaml:"max-text-length" default:"100000"`
+}
@@ -12,4 +12,10 @@ type LogConfig struct {
 	// Production whether to enable JSON output
 	// Production 是否启用 JSON 输出
 	Production bool `yaml:"production"`
+	// BufferSize recent entries kept in memory for the admin log viewer, 0 disables it
+	// BufferSize 在内存中保留供管理员日志查看的最近条目数，0 表示关闭
+	BufferSize int `yaml:"buffer-size" default:"5000"`
+	// SyncTraceDir directory of the per-user sync trace files switched on by the admin
+	// SyncTraceDir 管理员开启的按用户同步跟踪文件所在目录
+	SyncTraceDir string `yaml:"sync-trace-dir" default:"storage/logs/sync-trace"`
 }
@@ -0,0 +1,21 @@
+package config
+
+// MaintenanceConfig maintenance window heavy jobs (full backups, snapshots, database cleanup, upgrades) are confined to
+// MaintenanceConfig 维护窗口配置，重型任务（全量备份、快照、数据库清理、升级）仅在窗口内执行
+type MaintenanceConfig struct {
+	// IsEnabled whether heavy jobs wait for the window; when disabled they run as soon as they are due
+	// IsEnabled 重型任务是否等待维护窗口；关闭时任务到期即执行
+	IsEnabled bool `yaml:"is-enable" default:"false"`
+	// Start time of day the window opens, HH:MM
+	// Start 窗口每天的开启时刻，HH:MM
+	Start string `yaml:"start" default:"02:00"`
+	// Duration window length, at most 24h, e.g. 3h
+	// Duration 窗口时长，最长 24h，例如 3h
+	Duration string `yaml:"duration" default:"3h"`
+	// Days weekdays the window opens on (sun, mon, ... sat), empty for every day
+	// Days 窗口开启的星期（sun、mon …… sat），为空表示每天
+	Days []string `yaml:"days"`
+	// Timezone IANA time zone of Start, e.g. Asia/Shanghai; empty uses the server's local time
+	// Timezone Start 所在的 IANA 时区，例如 Asia/Shanghai；为空时使用服务器本地时间
+	Timezone string `yaml:"timezone" default:""`
+}
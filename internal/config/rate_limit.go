@@ -0,0 +1,27 @@
+package config
+
+// RateLimitConfig per-user and per-connection rate limiting configuration
+// RateLimitConfig 按用户及按连接的限流配置
+type RateLimitConfig struct {
+	// Enabled whether to enable rate limiting
+	// Enabled 是否启用限流
+	Enabled bool `yaml:"enabled" default:"false"`
+	// APIRate HTTP API requests per second per uid (or client IP before auth), 0 disables
+	// APIRate 每个用户（认证前按客户端 IP）每秒 HTTP API 请求数，0 表示不限制
+	APIRate float64 `yaml:"api-rate" default:"20"`
+	// APIBurst HTTP API burst size
+	// APIBurst HTTP API 突发容量
+	APIBurst int64 `yaml:"api-burst" default:"100"`
+	// WSUserRate WebSocket messages per second per uid across all connections, 0 disables
+	// WSUserRate 每个用户所有连接合计每秒 WebSocket 消息数，0 表示不限制
+	WSUserRate float64 `yaml:"ws-user-rate" default:"200"`
+	// WSUserBurst WebSocket per-uid burst size
+	// WSUserBurst WebSocket 按用户突发容量
+	WSUserBurst int64 `yaml:"ws-user-burst" default:"2000"`
+	// WSConnRate WebSocket messages per second per connection, 0 disables
+	// WSConnRate 每个连接每秒 WebSocket 消息数，0 表示不限制
+	WSConnRate float64 `yaml:"ws-conn-rate" default:"100"`
+	// WSConnBurst WebSocket per-connection burst size
+	// WSConnBurst WebSocket 按连接突发容量
+	WSConnBurst int64 `yaml:"ws-conn-burst" default:"1000"`
+}
@@ -16,4 +16,139 @@ type SecurityConfig struct {
 	// WebGUILoginTokenBindIP whether to bind the client IP when issuing WebGUI login tokens
 	// WebGUILoginTokenBindIP 签发 WebGUI 登录 Token 时是否绑定客户端 IP
 	WebGUILoginTokenBindIP *bool `yaml:"webgui-login-token-bind-ip" default:"true"`
+	// RequireAdminTOTP whether the admin account must enroll two-factor authentication
+	// RequireAdminTOTP 是否要求管理员账号启用两步验证
+	RequireAdminTOTP bool `yaml:"require-admin-totp" default:"false"`
+	// TOTPIssuer issuer name shown in authenticator apps
+	// TOTPIssuer 身份验证器应用中显示的签发方名称
+	TOTPIssuer string `yaml:"totp-issuer" default:"Fast Note Sync"`
+	// IPDenyList IPs or CIDRs that are always denied access
+	// IPDenyList 始终拒绝访问的 IP 或 CIDR 列表
+	IPDenyList []string `yaml:"ip-deny-list"`
+	// Headers security response headers
+	// Headers 安全响应头
+	Headers SecurityHeadersConfig `yaml:"headers"`
+	// Honeypot decoy admin endpoints that ban probing IPs
+	// Honeypot 诱饵管理端点，封禁探测 IP
+	Honeypot HoneypotConfig `yaml:"honeypot"`
+	// SecretScan credential scanning of incoming note content
+	// SecretScan 对上传笔记内容进行凭据扫描
+	SecretScan SecretScanConfig `yaml:"secret-scan"`
+	// EncryptionAtRest encryption of note content files and attachments on disk
+	// EncryptionAtRest 笔记内容文件与附件的落盘加密
+	EncryptionAtRest EncryptionAtRestConfig `yaml:"encryption-at-rest"`
+	// LoginGuard brute-force protection of the login
+	// LoginGuard 登录暴力破解防护
+	LoginGuard LoginGuardConfig `yaml:"login-guard"`
+}
+
+// SecurityHeadersConfig security response headers configuration
+// SecurityHeadersConfig 安全响应头配置
+type SecurityHeadersConfig struct {
+	// Enabled whether to send security headers
+	// Enabled 是否发送安全响应头
+	Enabled *bool `yaml:"enabled" default:"true"`
+	// ContentSecurityPolicy CSP applied to WebGUI and share pages, empty disables it
+	// ContentSecurityPolicy 作用于 WebGUI 与分享页面的 CSP，为空则不发送
+	ContentSecurityPolicy string `yaml:"content-security-policy" default:"default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline' https:; img-src 'self' data: blob: https:; font-src 'self' data: https:; connect-src 'self' ws: wss: https:; object-src 'none'; base-uri 'self'; frame-ancestors 'none'"`
+	// FrameOptions X-Frame-Options value, empty disables it
+	// FrameOptions X-Frame-Options 取值，为空则不发送
+	FrameOptions string `yaml:"frame-options" default:"DENY"`
+	// ReferrerPolicy Referrer-Policy value, empty disables it
+	// ReferrerPolicy Referrer-Policy 取值，为空则不发送
+	ReferrerPolicy string `yaml:"referrer-policy" default:"strict-origin-when-cross-origin"`
+	// HSTSMaxAge HSTS max-age in seconds, only sent over HTTPS; nil = default 1 year, explicit 0 = disabled
+	// HSTSMaxAge HSTS max-age（秒），仅在 HTTPS 下发送；nil=默认一年，显式 0=关闭
+	HSTSMaxAge *int `yaml:"hsts-max-age" default:"31536000"`
+}
+
+// HoneypotConfig decoy endpoint configuration
+// HoneypotConfig 诱饵端点配置
+type HoneypotConfig struct {
+	// Enabled whether to register decoy endpoints
+	// Enabled 是否注册诱饵端点
+	Enabled bool `yaml:"enabled" default:"false"`
+	// Paths decoy paths; empty uses the built-in list of commonly probed admin paths
+	// Paths 诱饵路径；为空时使用内置的常见扫描路径列表
+	Paths []string `yaml:"paths"`
+	// BanDuration how long a probing IP is banned (e.g. 24h, 7d), 0 only logs
+	// BanDuration 探测 IP 的封禁时长（如 24h、7d），0 表示仅记录日志
+	BanDuration string `yaml:"ban-duration" default:"24h"`
+}
+
+// SecretScanConfig note secret scanning configuration
+// SecretScanConfig 笔记敏感凭据扫描配置
+type SecretScanConfig struct {
+	// Enabled whether to scan incoming note content for credentials
+	// Enabled 是否扫描上传的笔记内容中的凭据
+	Enabled bool `yaml:"enabled" default:"false"`
+	// Strict reject notes containing credentials instead of only warning
+	// Strict 拒绝包含凭据的笔记，而非仅发出警告
+	Strict bool `yaml:"strict" default:"false"`
+	// Patterns extra regular expressions treated as credentials
+	// Patterns 额外视为凭据的正则表达式
+	Patterns []string `yaml:"patterns"`
+}
+
+// EncryptionAtRestConfig encryption at rest configuration
+// Only the content folders, their content store copies and the thumbnail cache are encrypted; the bleve index,
+// the note FTS table, the git sync workspace and local backups stay plaintext
+// EncryptionAtRestConfig 落盘加密配置
+// 仅加密内容目录、其在内容存储中的副本与缩略图缓存；bleve 索引、笔记 FTS 表、Git 同步工作区与本地备份仍为明文
+type EncryptionAtRestConfig struct {
+	// Enabled whether new content files and attachments are written encrypted
+	// Enabled 新写入的内容文件与附件是否加密
+	Enabled bool `yaml:"enabled" default:"false"`
+	// Key 32-byte key encoded as base64 or hex
+	// Key 以 base64 或十六进制编码的 32 字节密钥
+	Key string `yaml:"key"`
+	// KeyFile file holding the key, e.g. mounted by a KMS or secret manager; takes precedence over Key
+	// KeyFile 保存密钥的文件，例如由 KMS 或密钥管理服务挂载；优先于 Key
+	KeyFile string `yaml:"key-file"`
+	// PreviousKeys keys or key files still accepted for reading while files are re-encrypted after a rotation
+	// PreviousKeys 轮换密钥后重新加密期间，仍可用于读取的旧密钥或密钥文件
+	PreviousKeys []string `yaml:"previous-keys"`
+}
+
+// LoginGuardConfig failed login counters per client IP and per account with exponential lockout
+// LoginGuardConfig 按客户端 IP 与账号统计登录失败次数，并按指数增长锁定时长
+type LoginGuardConfig struct {
+	// Enabled whether failed logins are counted and locked out; an explicit false in yaml turns it off, nil defaults to true
+	// Enabled 是否统计登录失败并锁定；yaml 显式 false 关闭，nil 才用默认 true
+	Enabled *bool `yaml:"enabled" default:"true"`
+	// IPThreshold failed logins of one client IP before it is locked out, 0 disables the IP lockout
+	// IPThreshold 单个客户端 IP 被锁定前允许的登录失败次数，0 表示不按 IP 锁定
+	IPThreshold int `yaml:"ip-threshold" default:"20"`
+	// AccountThreshold failed logins of one account before it is locked out, 0 disables the account lockout
+	// AccountThreshold 单个账号被锁定前允许的登录失败次数，0 表示不按账号锁定
+	AccountThreshold int `yaml:"account-threshold" default:"5"`
+	// BaseLockout first lockout (e.g. 1m), doubled on every further lockout
+	// BaseLockout 首次锁定时长（如 1m），此后每次锁定翻倍
+	BaseLockout string `yaml:"base-lockout" default:"1m"`
+	// MaxLockout upper bound of a lockout (e.g. 1h)
+	// MaxLockout 锁定时长上限（如 1h）
+	MaxLockout string `yaml:"max-lockout" default:"1h"`
+	// ResetAfter time without failures after which the counters start over (e.g. 24h)
+	// ResetAfter 无失败多久后计数清零（如 24h）
+	ResetAfter string `yaml:"reset-after" default:"24h"`
+	// Captcha captcha the WebGUI login has to pass after repeated failures
+	// Captcha 多次失败后 WebGUI 登录需通过的验证码
+	Captcha LoginCaptchaConfig `yaml:"captcha"`
+}
+
+// LoginCaptchaConfig captcha of the WebGUI login
+// LoginCaptchaConfig WebGUI 登录验证码配置
+type LoginCaptchaConfig struct {
+	// Provider turnstile, hcaptcha or recaptcha, empty disables the captcha
+	// Provider turnstile、hcaptcha 或 recaptcha，为空表示不启用验证码
+	Provider string `yaml:"provider"`
+	// SiteKey public key of the widget, sent to the WebGUI
+	// SiteKey 验证码组件的公开密钥，下发给 WebGUI
+	SiteKey string `yaml:"site-key"`
+	// SecretKey secret key used to verify the responses
+	// SecretKey 用于校验响应的私密密钥
+	SecretKey string `yaml:"secret-key"`
+	// After failed logins of the IP or account before the captcha is required; nil = default 3, explicit 0 = every login
+	// After 需要验证码前 IP 或账号允许的登录失败次数；nil=默认 3，显式 0=每次登录都需要
+	After *int `yaml:"after" default:"3"`
 }
@@ -41,4 +41,7 @@ type ServerConfig struct {
 	// CustomResponseHeaders custom response headers for all requests
 	// CustomResponseHeaders 所有请求的自定义响应头
 	CustomResponseHeaders map[string]string `yaml:"custom-response-headers"` // Custom response headers mapped to string
+	// SwaggerUI expose the Swagger UI at /docs in release mode, it is always served in debug mode
+	// SwaggerUI 在 release 模式下开放 /docs 的 Swagger UI，debug 模式下始终开放
+	SwaggerUI bool `yaml:"swagger-ui"`
 }
@@ -0,0 +1,38 @@
+package config
+
+// SnapshotConfig scheduled local snapshot configuration
+// SnapshotConfig 定时本地快照配置
+type SnapshotConfig struct {
+	// IsEnabled whether scheduled snapshots run; manual snapshots from the admin API work either way
+	// IsEnabled 是否执行定时快照；管理接口的手动快照不受此开关影响
+	IsEnabled bool `yaml:"is-enable" default:"false"`
+	// Interval time between two scheduled snapshots, e.g. 24h, 7d
+	// Interval 两次定时快照的间隔，例如 24h、7d
+	Interval string `yaml:"interval" default:"24h"`
+	// Keep number of snapshots kept, older ones are removed
+	// Keep 保留的快照数量，更早的快照会被删除
+	Keep int `yaml:"keep" default:"3"`
+	// SavePath directory holding the snapshots
+	// SavePath 快照存放目录
+	SavePath string `yaml:"save-path" default:"storage/snapshots"`
+	// DatabaseExport frequent hot export of every SQLite database for file-level backup tools
+	// DatabaseExport 供文件级备份工具使用的 SQLite 数据库高频热导出
+	DatabaseExport DatabaseExportConfig `yaml:"db-export"`
+}
+
+// DatabaseExportConfig hot database export configuration.
+// Every database is copied with VACUUM INTO and moved into place atomically, so the export directory
+// always holds transaction-consistent copies that can be backed up while the server is running.
+// DatabaseExportConfig 数据库热导出配置。
+// 每个数据库通过 VACUUM INTO 复制后原子替换，导出目录中始终是事务一致的副本，可在服务运行时直接备份。
+type DatabaseExportConfig struct {
+	// IsEnabled whether scheduled exports run; manual exports from the admin API work either way
+	// IsEnabled 是否执行定时导出；管理接口的手动导出不受此开关影响
+	IsEnabled bool `yaml:"is-enable" default:"false"`
+	// Interval time between two scheduled exports, e.g. 30m, 1h
+	// Interval 两次定时导出的间隔，例如 30m、1h
+	Interval string `yaml:"interval" default:"1h"`
+	// SavePath directory holding the exported databases, main database at the top, user databases under user_<uid>
+	// SavePath 导出数据库的存放目录，主库位于顶层，用户库位于 user_<uid> 子目录
+	SavePath string `yaml:"save-path" default:"storage/db-export"`
+}
@@ -9,6 +9,25 @@ type StorageConfig struct {
 	CloudflareR2 StorageBaseConfig    `yaml:"cloudflare-r2"`
 	MinIO        StorageBaseConfig    `yaml:"minio"`
 	WebDAV       StorageBaseConfig    `yaml:"webdav"`
+	Retry        StorageRetryConfig   `yaml:"retry"`
+
+	// UploadConcurrency storage targets a backup uploads to at the same time
+	// UploadConcurrency 单次备份同时上传的存储目标数
+	UploadConcurrency int `yaml:"upload-concurrency" default:"3"`
+}
+
+// StorageRetryConfig retry policy of backup uploads to remote storages
+// StorageRetryConfig 备份上传到远程存储时的重试策略
+type StorageRetryConfig struct {
+	// MaxAttempts attempts per file including the first one, 1 disables retries
+	// MaxAttempts 每个文件含首次在内的尝试次数，1 表示不重试
+	MaxAttempts int `yaml:"max-attempts" default:"4"`
+	// BaseDelay wait before the first retry, doubled on every further retry
+	// BaseDelay 首次重试前的等待时间，之后每次翻倍
+	BaseDelay string `yaml:"base-delay" default:"1s"`
+	// MaxDelay upper bound of a single wait
+	// MaxDelay 单次等待时间上限
+	MaxDelay string `yaml:"max-delay" default:"30s"`
 }
 
 // StorageLocalFSConfig Local file system storage configuration
@@ -0,0 +1,15 @@
+package config
+
+// TaskConfig scheduled task configuration
+// TaskConfig 定时任务配置
+type TaskConfig struct {
+	// PingURLs dead-man switch URLs (healthchecks.io compatible) keyed by task name, e.g. DbCleanup,
+	// pinged with /start when the task starts, then on success or with /fail on failure
+	// PingURLs 以任务名为键的失联告警地址（兼容 healthchecks.io），例如 DbCleanup，
+	// 任务开始时请求 /start，成功时请求原地址，失败时请求 /fail
+	PingURLs map[string]string `yaml:"ping-urls"`
+	// Schedules cron expressions (or @every 90m, @daily, off) keyed by task name that replace the
+	// built-in interval of the task
+	// Schedules 以任务名为键的 cron 表达式（或 @every 90m、@daily、off），替换任务内置的执行间隔
+	Schedules map[string]string `yaml:"schedules"`
+}
@@ -0,0 +1,22 @@
+package config
+
+// ThumbnailConfig image attachment thumbnail configuration
+// ThumbnailConfig 图片附件缩略图配置
+type ThumbnailConfig struct {
+	// Widths thumbnail widths in pixels; a request is served with the smallest width not below the requested one
+	// Widths 缩略图宽度（像素）；请求按不小于所请求宽度的最小配置宽度返回
+	Widths []int `yaml:"widths" default:"[256, 1024]"`
+	// SavePath directory caching the rendered thumbnails, it can be deleted at any time
+	// SavePath 缓存已生成缩略图的目录，可随时删除
+	SavePath string `yaml:"save-path" default:"storage/thumbs"`
+	// GenerateOnUpload render all widths in the background right after an image is uploaded,
+	// otherwise thumbnails are rendered on first request; an explicit false in yaml turns it off, nil defaults to true
+	// GenerateOnUpload 图片上传后立即在后台生成所有宽度的缩略图，否则在首次请求时生成；yaml 显式 false 关闭，nil 才用默认 true
+	GenerateOnUpload *bool `yaml:"generate-on-upload" default:"true"`
+	// MaxSourceSize largest image attachment thumbnails are rendered for
+	// MaxSourceSize 生成缩略图的最大图片附件大小
+	MaxSourceSize string `yaml:"max-source-size" default:"50MB"`
+	// CacheMaxAge max-age sent in the Cache-Control header, clients revalidate with the ETag afterwards
+	// CacheMaxAge Cache-Control 头中的 max-age，过期后客户端通过 ETag 重新验证
+	CacheMaxAge string `yaml:"cache-max-age" default:"1h"`
+}
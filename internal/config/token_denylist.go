@@ -0,0 +1,41 @@
+package config
+
+// TokenDenylistConfig list of the auth tokens revoked before their expiry, checked on every authentication
+// next to the token status in the database. The memory list is saved to a file; in multi-instance mode
+// use redis so every instance sees the revocations at once.
+// TokenDenylistConfig 在过期前被撤销的认证令牌列表，每次认证时与数据库中的令牌状态一同检查。memory 列表保存到文件；
+// 多实例模式下请使用 redis，使所有实例立即看到撤销记录。
+type TokenDenylistConfig struct {
+	// Type list backend: memory or redis
+	// Type 列表后端：memory 或 redis
+	Type string `yaml:"type" default:"memory"`
+	// SavePath file the memory list is saved to, empty keeps it in memory only
+	// SavePath memory 列表保存的文件，为空时仅保存在内存中
+	SavePath string `yaml:"save-path" default:"storage/token_denylist.json"`
+	// Redis server holding the redis list, an empty addr uses the cluster redis server
+	// Redis 保存 redis 列表的服务，addr 为空时使用 cluster 的 redis 服务
+	Redis TokenDenylistRedisConfig `yaml:"redis"`
+}
+
+// TokenDenylistRedisConfig Redis server holding the token denylist
+// TokenDenylistRedisConfig 保存令牌拒绝列表的 Redis 服务
+type TokenDenylistRedisConfig struct {
+	// Addr host:port of the server
+	// Addr 服务地址 host:port
+	Addr string `yaml:"addr"`
+	// Username ACL user name, empty for the default user
+	// Username ACL 用户名，默认用户留空
+	Username string `yaml:"username"`
+	// Password password
+	// Password 密码
+	Password string `yaml:"password"`
+	// DB database number
+	// DB 数据库编号
+	DB int `yaml:"db"`
+	// KeyPrefix key prefix, the instances of one deployment must share it
+	// KeyPrefix 键前缀，同一部署的实例必须一致
+	KeyPrefix string `yaml:"key-prefix" default:"fns"`
+	// TLS connect with TLS
+	// TLS 使用 TLS 连接
+	TLS bool `yaml:"tls"`
+}
@@ -9,4 +9,33 @@ type TracerConfig struct {
 	// Header tracing ID request header name, default X-Trace-ID
 	// Header 追踪 ID 请求头名称，默认 X-Trace-ID
 	Header string `yaml:"header" default:"X-Trace-ID"`
+	// OTLP OpenTelemetry span export configuration
+	// OTLP OpenTelemetry 链路导出配置
+	OTLP TracerOTLPConfig `yaml:"otlp"`
+}
+
+// TracerOTLPConfig OpenTelemetry OTLP/HTTP exporter configuration
+// TracerOTLPConfig OpenTelemetry OTLP/HTTP 导出器配置
+type TracerOTLPConfig struct {
+	// Enabled whether spans are exported, requires tracer.enabled
+	// Enabled 是否导出链路，需要同时开启 tracer.enabled
+	Enabled bool `yaml:"enabled"`
+	// Endpoint collector host:port of the OTLP/HTTP receiver, e.g. localhost:4318 for Jaeger or Tempo
+	// Endpoint 采集器 OTLP/HTTP 接收端的 host:port，如 Jaeger 或 Tempo 的 localhost:4318
+	Endpoint string `yaml:"endpoint" default:"localhost:4318"`
+	// URLPath path of the trace receiver
+	// URLPath 链路接收路径
+	URLPath string `yaml:"url-path" default:"/v1/traces"`
+	// Insecure send over plain HTTP instead of HTTPS
+	// Insecure 使用 HTTP 而非 HTTPS 发送
+	Insecure bool `yaml:"insecure"`
+	// Headers extra request headers, e.g. authentication of a hosted collector
+	// Headers 额外的请求头，如托管采集器的认证信息
+	Headers map[string]string `yaml:"headers"`
+	// ServiceName service.name resource attribute of the spans
+	// ServiceName 链路的 service.name 资源属性
+	ServiceName string `yaml:"service-name" default:"fast-note-sync-service"`
+	// SampleRatio share of new traces that are recorded, 0-1, traces continued from a sampled parent are always recorded
+	// SampleRatio 记录的新链路比例（0-1），父链路已采样的链路始终记录
+	SampleRatio float64 `yaml:"sample-ratio" default:"1"`
 }
@@ -6,7 +6,13 @@ type UserConfig struct {
 	// RegisterIsEnable whether registration is enabled
 	// RegisterIsEnable 注册是否启用
 	RegisterIsEnable bool `yaml:"register-is-enable"`
+	// RegisterApproval open registrations wait in a queue until the admin approves them; invited registrations skip it
+	// RegisterApproval 开放注册需在队列中等待管理员审核；使用邀请码的注册无需审核
+	RegisterApproval bool `yaml:"register-approval"`
 	// AdminUID admin UID, 0 means no restriction on admin access
 	// AdminUID 管理员 UID，0 表示不限制管理员访问
 	AdminUID int `yaml:"admin-uid" default:"0"`
+	// PasswordResetExpiry validity of the links sent by the forgotten password email (e.g. 30m, 2h)
+	// PasswordResetExpiry 忘记密码邮件中重置链接的有效期（如 30m、2h）
+	PasswordResetExpiry string `yaml:"password-reset-expiry" default:"30m"`
 }
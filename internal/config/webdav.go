@@ -0,0 +1,12 @@
+package config
+
+// WebDAVConfig built-in WebDAV endpoint configuration
+// WebDAVConfig 内置 WebDAV 端点配置
+type WebDAVConfig struct {
+	// Enabled whether to serve vaults over WebDAV at /dav
+	// Enabled 是否在 /dav 下通过 WebDAV 提供仓库访问
+	Enabled *bool `yaml:"enabled" default:"true"`
+	// Realm realm shown by WebDAV clients when prompting for credentials
+	// Realm WebDAV 客户端提示输入凭据时显示的领域名称
+	Realm string `yaml:"realm" default:"Fast Note Sync"`
+}
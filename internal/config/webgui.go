@@ -3,5 +3,29 @@ package config
 // WebGUIConfig Web GUI configuration
 // WebGUIConfig Web GUI 配置
 type WebGUIConfig struct {
-	FontSet string `yaml:"font-set" json:"fontSet" default:""`
+	FontSet  string         `yaml:"font-set" json:"fontSet" default:""`
+	Branding BrandingConfig `yaml:"branding" json:"branding"`
+}
+
+// BrandingConfig instance branding shown by the WebGUI, share and login pages, empty fields keep the defaults
+// BrandingConfig WebGUI、分享页与登录页展示的实例品牌设置，留空字段保持默认
+type BrandingConfig struct {
+	// InstanceName replaces "Fast Note Sync" in page titles
+	// InstanceName 替换页面标题中的 "Fast Note Sync"
+	InstanceName string `yaml:"instance-name" json:"instanceName" default:""`
+	// LogoURL logo and favicon, an absolute URL or a path on this server
+	// LogoURL 标志与站点图标，绝对 URL 或本服务上的路径
+	LogoURL string `yaml:"logo-url" json:"logoUrl" default:""`
+	// PrimaryColor main color as a hex color, e.g. #3b82f6
+	// PrimaryColor 主色，十六进制颜色，如 #3b82f6
+	PrimaryColor string `yaml:"primary-color" json:"primaryColor" default:""`
+	// AccentColor accent color as a hex color
+	// AccentColor 强调色，十六进制颜色
+	AccentColor string `yaml:"accent-color" json:"accentColor" default:""`
+	// LoginMessage plain text shown on the login page
+	// LoginMessage 登录页展示的纯文本消息
+	LoginMessage string `yaml:"login-message" json:"loginMessage" default:""`
+	// CustomCSS appended to /branding.css
+	// CustomCSS 追加到 /branding.css 的自定义样式
+	CustomCSS string `yaml:"custom-css" json:"customCss" default:""`
 }
@@ -0,0 +1,153 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+)
+
+// alertChannelRepository implements domain.AlertChannelRepository
+// alertChannelRepository 实现 domain.AlertChannelRepository 接口
+type alertChannelRepository struct {
+	dao             *Dao
+	customPrefixKey string
+	migrateOnce     sync.Map // tracks per-key migration completion // 记录每个 key 是否已完成 AutoMigrate
+}
+
+// NewAlertChannelRepository creates an AlertChannelRepository instance
+// NewAlertChannelRepository 创建 AlertChannelRepository 实例
+func NewAlertChannelRepository(dao *Dao) domain.AlertChannelRepository {
+	return &alertChannelRepository{dao: dao, customPrefixKey: "user_alert_"}
+}
+
+// GetKey returns the database routing key for the given user
+// GetKey 返回指定用户的数据库路由键
+func (r *alertChannelRepository) GetKey(uid int64) string {
+	return r.customPrefixKey + strconv.FormatInt(uid, 10)
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name: "AlertChannel",
+		RepoFactory: func(d *Dao) daoDBCustomKey {
+			return NewAlertChannelRepository(d).(daoDBCustomKey)
+		},
+		IsMainDB: false,
+	})
+}
+
+// db returns the *gorm.DB of the user's alert database, with one-time AutoMigrate
+// db 返回用户告警库的 *gorm.DB，确保每个用户库只迁移一次
+func (r *alertChannelRepository) db(uid int64) *gorm.DB {
+	key := r.GetKey(uid)
+	if _, loaded := r.migrateOnce.LoadOrStore(key+"#alert", true); !loaded {
+		if db := r.dao.ResolveDB(key); db != nil {
+			model.AutoMigrate(db, "AlertChannel")
+		}
+	}
+	return r.dao.ResolveDB(key)
+}
+
+// alertChannelToDomain converts the database model to the domain model
+// alertChannelToDomain 将数据库模型转换为领域模型
+func alertChannelToDomain(m *model.AlertChannel) *domain.AlertChannel {
+	return &domain.AlertChannel{
+		ID:        m.ID,
+		UID:       m.UID,
+		Name:      m.Name,
+		Type:      m.Type,
+		To:        m.To,
+		URL:       m.URL,
+		Token:     m.Token,
+		ChatID:    m.ChatID,
+		Priority:  int(m.Priority),
+		IsEnabled: m.IsEnabled == 1,
+		CreatedAt: time.Time(m.CreatedAt),
+		UpdatedAt: time.Time(m.UpdatedAt),
+	}
+}
+
+// List lists all alert channels of a user
+// List 列出用户的全部告警渠道
+func (r *alertChannelRepository) List(ctx context.Context, uid int64) ([]*domain.AlertChannel, error) {
+	var rows []*model.AlertChannel
+	if err := r.db(uid).WithContext(ctx).Where("uid = ?", uid).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	results := make([]*domain.AlertChannel, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, alertChannelToDomain(m))
+	}
+	return results, nil
+}
+
+// Get returns an alert channel, nil when it does not exist
+// Get 获取告警渠道，不存在时返回 nil
+func (r *alertChannelRepository) Get(ctx context.Context, id, uid int64) (*domain.AlertChannel, error) {
+	var m model.AlertChannel
+	err := r.db(uid).WithContext(ctx).Where("id = ? AND uid = ?", id, uid).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return alertChannelToDomain(&m), nil
+}
+
+// Save creates the channel when ID is 0, otherwise updates it
+// Save ID 为 0 时新建渠道，否则更新
+func (r *alertChannelRepository) Save(ctx context.Context, channel *domain.AlertChannel, uid int64) (*domain.AlertChannel, error) {
+	var result *domain.AlertChannel
+	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		m := &model.AlertChannel{
+			ID:        channel.ID,
+			UID:       uid,
+			Name:      channel.Name,
+			Type:      channel.Type,
+			To:        channel.To,
+			URL:       channel.URL,
+			Token:     channel.Token,
+			ChatID:    channel.ChatID,
+			Priority:  int64(channel.Priority),
+			CreatedAt: timex.Time(channel.CreatedAt),
+			UpdatedAt: timex.Now(),
+		}
+		if channel.IsEnabled {
+			m.IsEnabled = 1
+		}
+		if m.ID == 0 {
+			m.CreatedAt = m.UpdatedAt
+			if err := r.db(uid).WithContext(ctx).Create(m).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := r.db(uid).WithContext(ctx).Where("id = ? AND uid = ?", m.ID, uid).
+				Select("name", "type", "recipients", "url", "token", "chat_id", "priority", "is_enabled", "updated_at").Updates(m).Error; err != nil {
+				return err
+			}
+		}
+		result = alertChannelToDomain(m)
+		return nil
+	})
+	return result, err
+}
+
+// Delete removes an alert channel
+// Delete 删除告警渠道
+func (r *alertChannelRepository) Delete(ctx context.Context, id, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Where("id = ? AND uid = ?", id, uid).Delete(&model.AlertChannel{}).Error
+	})
+}
+
+// Ensure alertChannelRepository implements domain.AlertChannelRepository
+// 确保 alertChannelRepository 实现了 domain.AlertChannelRepository 接口
+var _ domain.AlertChannelRepository = (*alertChannelRepository)(nil)
@@ -0,0 +1,144 @@
+package dao
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+)
+
+// auditLogRepository implements domain.AuditLogRepository, the audit log lives in the main database
+// auditLogRepository 实现 domain.AuditLogRepository 接口，审计日志存放在主库中
+type auditLogRepository struct {
+	dao *Dao
+}
+
+// NewAuditLogRepository creates an AuditLogRepository instance
+// NewAuditLogRepository 创建 AuditLogRepository 实例
+func NewAuditLogRepository(dao *Dao) domain.AuditLogRepository {
+	return &auditLogRepository{dao: dao}
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name:     "AuditLog",
+		IsMainDB: true,
+	})
+}
+
+func (r *auditLogRepository) db() *gorm.DB {
+	db := r.dao.ResolveDB()
+	r.dao.QueryWithOnceInit(func(g *gorm.DB) {
+		model.AutoMigrate(g, "AuditLog")
+	}, "audit_log#audit_log")
+	return db
+}
+
+func (r *auditLogRepository) toDomain(m *model.AuditLog) *domain.AuditLog {
+	return &domain.AuditLog{
+		ID:         m.ID,
+		UID:        m.UID,
+		Action:     domain.AuditAction(m.Action),
+		Target:     m.Target,
+		Detail:     m.Detail,
+		IP:         m.IP,
+		ClientType: m.ClientType,
+		ClientName: m.ClientName,
+		CreatedAt:  time.Time(m.CreatedAt),
+	}
+}
+
+// Create stores an audit log entry
+// Create 存储一条审计日志
+func (r *auditLogRepository) Create(ctx context.Context, log *domain.AuditLog) error {
+	m := &model.AuditLog{
+		UID:        log.UID,
+		Action:     string(log.Action),
+		Target:     log.Target,
+		Detail:     log.Detail,
+		IP:         log.IP,
+		ClientType: log.ClientType,
+		ClientName: log.ClientName,
+		CreatedAt:  timex.Time(log.CreatedAt),
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = timex.Now()
+	}
+	if err := r.db().WithContext(ctx).Create(m).Error; err != nil {
+		return err
+	}
+	log.ID = m.ID
+	return nil
+}
+
+// List lists audit log entries matching filter, newest first
+// List 按时间倒序分页列出符合条件的审计日志
+func (r *auditLogRepository) List(ctx context.Context, filter *domain.AuditLogFilter, page, pageSize int) ([]*domain.AuditLog, int64, error) {
+	query := r.db().WithContext(ctx).Model(&model.AuditLog{})
+	if filter != nil {
+		if filter.UID > 0 {
+			query = query.Where("uid = ?", filter.UID)
+		}
+		if filter.Action != "" {
+			query = query.Where("action = ?", string(filter.Action))
+		}
+		if len(filter.Actions) > 0 {
+			actions := make([]string, 0, len(filter.Actions))
+			for _, a := range filter.Actions {
+				actions = append(actions, string(a))
+			}
+			query = query.Where("action IN ?", actions)
+		}
+		if filter.TargetPrefix != "" {
+			// Compared by substring so that % and _ in vault names need no escaping
+			// 按子串比较，笔记库名称中的 % 与 _ 无需转义
+			query = query.Where("SUBSTR(target, 1, ?) = ?", utf8.RuneCountInString(filter.TargetPrefix), filter.TargetPrefix)
+		}
+		if filter.IP != "" {
+			query = query.Where("ip = ?", filter.IP)
+		}
+		if !filter.StartTime.IsZero() {
+			query = query.Where("created_at >= ?", filter.StartTime)
+		}
+		if !filter.EndTime.IsZero() {
+			query = query.Where("created_at < ?", filter.EndTime)
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	var rows []*model.AuditLog
+	if err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]*domain.AuditLog, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, r.toDomain(m))
+	}
+	return results, total, nil
+}
+
+// DeleteBefore removes audit log entries older than cutoff
+// DeleteBefore 删除早于 cutoff 的审计日志
+func (r *auditLogRepository) DeleteBefore(ctx context.Context, cutoff time.Time) error {
+	return r.db().WithContext(ctx).Where("created_at < ?", cutoff).Delete(&model.AuditLog{}).Error
+}
+
+// Ensure auditLogRepository implements domain.AuditLogRepository
+// 确保 auditLogRepository 实现了 domain.AuditLogRepository 接口
+var _ domain.AuditLogRepository = (*auditLogRepository)(nil)
@@ -0,0 +1,142 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// backupBlobBatchSize rows per statement when touching blobs, keeps SQLite under its variable limit
+// backupBlobBatchSize 登记数据块时每条语句的行数，避免超出 SQLite 变量数限制
+const backupBlobBatchSize = 500
+
+// backupBlobRepository implements domain.BackupBlobRepository
+// backupBlobRepository 实现 domain.BackupBlobRepository 接口
+type backupBlobRepository struct {
+	dao         *Dao
+	migrateOnce sync.Map // tracks per-key migration completion // 记录每个 key 是否已完成 AutoMigrate
+}
+
+// NewBackupBlobRepository creates a BackupBlobRepository instance
+// NewBackupBlobRepository 创建 BackupBlobRepository 实例
+func NewBackupBlobRepository(dao *Dao) domain.BackupBlobRepository {
+	return &backupBlobRepository{dao: dao}
+}
+
+// GetKey returns the database routing key for the given user, shared with the backup history
+// GetKey 返回指定用户的数据库路由键，与备份历史共用
+func (r *backupBlobRepository) GetKey(uid int64) string {
+	return "user_backup_" + fmt.Sprintf("%d", uid)
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name: "BackupBlob",
+		RepoFactory: func(d *Dao) daoDBCustomKey {
+			return NewBackupBlobRepository(d).(daoDBCustomKey)
+		},
+	})
+}
+
+// db returns the *gorm.DB of the user's backup database, with one-time AutoMigrate
+// db 返回用户备份库的 *gorm.DB，确保每个用户库只迁移一次
+func (r *backupBlobRepository) db(uid int64) *gorm.DB {
+	key := r.GetKey(uid)
+	if _, loaded := r.migrateOnce.LoadOrStore(key+"#backupBlob", true); !loaded {
+		if db := r.dao.ResolveDB(key); db != nil {
+			model.AutoMigrate(db, "BackupBlob")
+		}
+	}
+	return r.dao.ResolveDB(key)
+}
+
+// ListHashes lists the hashes of blobs already uploaded to a storage target by a config
+// ListHashes 列出某配置在某存储目标上已上传的数据块摘要
+func (r *backupBlobRepository) ListHashes(ctx context.Context, configID, storageID, uid int64) ([]string, error) {
+	var hashes []string
+	err := r.db(uid).WithContext(ctx).Model(&model.BackupBlob{}).
+		Where("config_id = ? AND storage_id = ?", configID, storageID).
+		Pluck("hash", &hashes).Error
+	return hashes, err
+}
+
+// Touch registers the blobs referenced by a run: new blobs are inserted, known blobs get LastRefAt updated
+// Touch 登记本次运行引用的数据块，新数据块插入，已有数据块更新 LastRefAt
+func (r *backupBlobRepository) Touch(ctx context.Context, blobs []*domain.BackupBlob, refAt time.Time, uid int64) error {
+	if len(blobs) == 0 {
+		return nil
+	}
+	now := timex.Now()
+	rows := make([]*model.BackupBlob, 0, len(blobs))
+	for _, b := range blobs {
+		rows = append(rows, &model.BackupBlob{
+			UID:       uid,
+			ConfigID:  b.ConfigID,
+			StorageID: b.StorageID,
+			Hash:      b.Hash,
+			Size:      b.Size,
+			LastRefAt: timex.Time(refAt),
+			CreatedAt: now,
+		})
+	}
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "config_id"}, {Name: "storage_id"}, {Name: "hash"}},
+			DoUpdates: clause.AssignmentColumns([]string{"last_ref_at"}),
+		}).CreateInBatches(rows, backupBlobBatchSize).Error
+	})
+}
+
+// ListUnreferenced lists blobs last referenced before the given time
+// ListUnreferenced 列出 LastRefAt 早于指定时间的数据块
+func (r *backupBlobRepository) ListUnreferenced(ctx context.Context, configID, storageID int64, before time.Time, uid int64) ([]*domain.BackupBlob, error) {
+	var rows []*model.BackupBlob
+	err := r.db(uid).WithContext(ctx).
+		Where("config_id = ? AND storage_id = ? AND last_ref_at < ?", configID, storageID, timex.Time(before)).
+		Order("id ASC").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*domain.BackupBlob, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, &domain.BackupBlob{
+			ID:        m.ID,
+			UID:       m.UID,
+			ConfigID:  m.ConfigID,
+			StorageID: m.StorageID,
+			Hash:      m.Hash,
+			Size:      m.Size,
+			LastRefAt: time.Time(m.LastRefAt),
+			CreatedAt: time.Time(m.CreatedAt),
+		})
+	}
+	return results, nil
+}
+
+// Delete removes blob records
+// Delete 删除数据块记录
+func (r *backupBlobRepository) Delete(ctx context.Context, ids []int64, uid int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		for start := 0; start < len(ids); start += backupBlobBatchSize {
+			end := min(start+backupBlobBatchSize, len(ids))
+			if err := r.db(uid).WithContext(ctx).Where("id IN ?", ids[start:end]).Delete(&model.BackupBlob{}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Ensure backupBlobRepository implements domain.BackupBlobRepository
+// 确保 backupBlobRepository 实现了 domain.BackupBlobRepository 接口
+var _ domain.BackupBlobRepository = (*backupBlobRepository)(nil)
@@ -68,6 +68,9 @@ func (r *backupRepository) configToDomain(m *model.BackupConfig) *domain.BackupC
 		LastMessage:      m.LastMessage,
 		PasswordMode:     int(m.PasswordMode),
 		PasswordValue:    m.PasswordValue,
+		WebhookURL:       m.WebhookURL,
+		WebhookSecret:    m.WebhookSecret,
+		PingURL:          m.PingURL,
 		CreatedAt:        time.Time(m.CreatedAt),
 		UpdatedAt:        time.Time(m.UpdatedAt),
 	}
@@ -102,6 +105,9 @@ func (r *backupRepository) configToModel(d *domain.BackupConfig) *model.BackupCo
 		LastMessage:      d.LastMessage,
 		PasswordMode:     int64(d.PasswordMode),
 		PasswordValue:    d.PasswordValue,
+		WebhookURL:       d.WebhookURL,
+		WebhookSecret:    d.WebhookSecret,
+		PingURL:          d.PingURL,
 		CreatedAt:        timex.Time(d.CreatedAt),
 		UpdatedAt:        timex.Time(d.UpdatedAt),
 	}
@@ -112,21 +118,23 @@ func (r *backupRepository) historyToDomain(m *model.BackupHistory) *domain.Backu
 		return nil
 	}
 	return &domain.BackupHistory{
-		ID:        m.ID,
-		UID:       m.UID,
-		ConfigID:  m.ConfigID,
-		StorageID: m.StorageID,
-		Type:      m.Type,
-		StartTime: m.StartTime,
-		EndTime:   m.EndTime,
-		Status:    int(m.Status),
-		FileSize:  m.FileSize,
-		FileCount: m.FileCount,
-		Message:   m.Message,
-		FilePath:  m.FilePath,
-		Password:  m.Password,
-		CreatedAt: time.Time(m.CreatedAt),
-		UpdatedAt: time.Time(m.UpdatedAt),
+		ID:          m.ID,
+		UID:         m.UID,
+		ConfigID:    m.ConfigID,
+		StorageID:   m.StorageID,
+		Type:        m.Type,
+		StartTime:   m.StartTime,
+		EndTime:     m.EndTime,
+		Status:      int(m.Status),
+		FileSize:    m.FileSize,
+		FileCount:   m.FileCount,
+		Message:     m.Message,
+		FilePath:    m.FilePath,
+		Password:    m.Password,
+		ArchiveSize: m.ArchiveSize,
+		Checksum:    m.Checksum,
+		CreatedAt:   time.Time(m.CreatedAt),
+		UpdatedAt:   time.Time(m.UpdatedAt),
 	}
 }
 
@@ -135,21 +143,23 @@ func (r *backupRepository) historyToModel(d *domain.BackupHistory) *model.Backup
 		return nil
 	}
 	return &model.BackupHistory{
-		ID:        d.ID,
-		UID:       d.UID,
-		ConfigID:  d.ConfigID,
-		StorageID: d.StorageID,
-		Type:      d.Type,
-		StartTime: d.StartTime,
-		EndTime:   d.EndTime,
-		Status:    int64(d.Status),
-		FileSize:  d.FileSize,
-		FileCount: d.FileCount,
-		Message:   d.Message,
-		FilePath:  d.FilePath,
-		Password:  d.Password,
-		CreatedAt: timex.Time(d.CreatedAt),
-		UpdatedAt: timex.Time(d.UpdatedAt),
+		ID:          d.ID,
+		UID:         d.UID,
+		ConfigID:    d.ConfigID,
+		StorageID:   d.StorageID,
+		Type:        d.Type,
+		StartTime:   d.StartTime,
+		EndTime:     d.EndTime,
+		Status:      int64(d.Status),
+		FileSize:    d.FileSize,
+		FileCount:   d.FileCount,
+		Message:     d.Message,
+		FilePath:    d.FilePath,
+		Password:    d.Password,
+		ArchiveSize: d.ArchiveSize,
+		Checksum:    d.Checksum,
+		CreatedAt:   timex.Time(d.CreatedAt),
+		UpdatedAt:   timex.Time(d.UpdatedAt),
 	}
 }
 
@@ -294,6 +304,18 @@ func (r *backupRepository) ListHistory(ctx context.Context, uid int64, configID
 	return list, count, nil
 }
 
+func (r *backupRepository) GetHistory(ctx context.Context, id, uid int64) (*domain.BackupHistory, error) {
+	q := r.backup(uid).BackupHistory
+	m, err := q.WithContext(ctx).Where(q.UID.Eq(uid), q.ID.Eq(id)).First()
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.historyToDomain(m), nil
+}
+
 func (r *backupRepository) ListOldHistory(ctx context.Context, uid int64, configID int64, cutoffTime time.Time) ([]*domain.BackupHistory, error) {
 	q := r.backup(uid).BackupHistory
 	modelList, err := q.WithContext(ctx).Where(q.ConfigID.Eq(configID), q.UID.Eq(uid), q.CreatedAt.Lt(timex.Time(cutoffTime))).Find()
@@ -317,6 +339,29 @@ func (r *backupRepository) DeleteOldHistory(ctx context.Context, uid int64, conf
 	})
 }
 
+// CountHistoryByStatus counts history records created since the given time per status
+// CountHistoryByStatus 按状态统计自指定时间以来创建的历史记录数量
+func (r *backupRepository) CountHistoryByStatus(ctx context.Context, uid int64, since time.Time) (map[int]int64, error) {
+	q := r.backup(uid).BackupHistory
+	var rows []struct {
+		Status int64
+		Count  int64
+	}
+	err := q.WithContext(ctx).
+		Select(q.Status, q.ID.Count().As("count")).
+		Where(q.UID.Eq(uid), q.CreatedAt.Gte(timex.Time(since))).
+		Group(q.Status).
+		Scan(&rows)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		counts[int(row.Status)] = row.Count
+	}
+	return counts, nil
+}
+
 // DisableByVaultID 禁用仓库下的备份任务
 func (r *backupRepository) DisableByVaultID(ctx context.Context, vaultID, uid int64) error {
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/analysis/lang/cjk"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+// bleveIndexVersion is bumped whenever the index mapping or analyzers change; indexes written
+// with an older version are rebuilt in the background
+// bleveIndexVersion 在索引映射或分析器变化时递增；旧版本写入的索引会在后台重建
+const bleveIndexVersion = 2
+
+// bleveCJKIndexAnalyzer indexes CJK runs as overlapping bigrams plus unigrams, so both
+// single-character and multi-character queries can hit
+// bleveCJKIndexAnalyzer 将中日韩文本索引为重叠二元词并保留单字，单字与多字查询均可命中
+const bleveCJKIndexAnalyzer = "note_cjk_index"
+
+// bleveCJKQueryAnalyzer splits CJK queries into bigrams only; a lone character falls back to
+// a unigram. This avoids ANDing every single character of the query
+// bleveCJKQueryAnalyzer 查询时仅切分为二元词，孤立单字才回退为单字，避免逐字 AND 匹配
+const bleveCJKQueryAnalyzer = "note_cjk_query"
+
+func init() {
+	// Registered globally rather than on the index mapping so queries against indexes built
+	// by older versions can still resolve the query analyzer
+	// 全局注册而非挂在索引映射上，以便查询旧版本索引时仍能解析查询分析器
+	if err := registry.RegisterAnalyzer(bleveCJKIndexAnalyzer, newCJKBigramAnalyzerConstructor(true)); err != nil {
+		panic(err)
+	}
+	if err := registry.RegisterAnalyzer(bleveCJKQueryAnalyzer, newCJKBigramAnalyzerConstructor(false)); err != nil {
+		panic(err)
+	}
+}
+
+// newCJKBigramAnalyzerConstructor builds the unicode + width + lowercase + bigram chain used by
+// the stock "cjk" analyzer, with configurable unigram output
+// newCJKBigramAnalyzerConstructor 构造与内置 "cjk" 分析器相同的 unicode + 全半角 + 小写 + 二元词链路，
+// 可配置是否输出单字
+func newCJKBigramAnalyzerConstructor(outputUnigram bool) registry.AnalyzerConstructor {
+	return func(config map[string]interface{}, cache *registry.Cache) (analysis.Analyzer, error) {
+		tokenizer, err := cache.TokenizerNamed(unicode.Name)
+		if err != nil {
+			return nil, err
+		}
+		widthFilter, err := cache.TokenFilterNamed(cjk.WidthName)
+		if err != nil {
+			return nil, err
+		}
+		toLowerFilter, err := cache.TokenFilterNamed(lowercase.Name)
+		if err != nil {
+			return nil, err
+		}
+		return &analysis.DefaultAnalyzer{
+			Tokenizer: tokenizer,
+			TokenFilters: []analysis.TokenFilter{
+				widthFilter,
+				toLowerFilter,
+				cjk.NewCJKBigramFilter(outputUnigram),
+			},
+		}, nil
+	}
+}
@@ -0,0 +1,32 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// analyzeTerms runs a registered analyzer and returns the emitted terms
+// analyzeTerms 执行已注册的分析器并返回输出的词项
+func analyzeTerms(t *testing.T, name, text string) []string {
+	analyzer, err := registry.NewCache().AnalyzerNamed(name)
+	require.NoError(t, err)
+	var terms []string
+	for _, token := range analyzer.Analyze([]byte(text)) {
+		terms = append(terms, string(token.Term))
+	}
+	return terms
+}
+
+// TestCJKAnalyzers verifies the index analyzer keeps unigrams alongside bigrams while the query
+// analyzer only emits bigrams, falling back to a unigram for a lone character.
+// TestCJKAnalyzers 验证索引分析器同时保留单字与二元词，查询分析器仅输出二元词，孤立单字回退为单字。
+func TestCJKAnalyzers(t *testing.T) {
+	indexTerms := analyzeTerms(t, bleveCJKIndexAnalyzer, "全文搜索 Fast")
+	assert.Subset(t, indexTerms, []string{"全", "全文", "文搜", "搜索", "索", "fast"})
+
+	assert.Equal(t, []string{"全文", "文搜", "搜索", "fast"}, analyzeTerms(t, bleveCJKQueryAnalyzer, "全文搜索 Fast"))
+	assert.Equal(t, []string{"搜"}, analyzeTerms(t, bleveCJKQueryAnalyzer, "搜"))
+}
@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"github.com/blevesearch/bleve/v2"
-	_ "github.com/blevesearch/bleve/v2/analysis/lang/cjk"
 	"github.com/blevesearch/bleve/v2/mapping"
 	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
 	"go.uber.org/zap"
@@ -59,7 +58,7 @@ type ftsPendingBatch struct {
 // BleveMeta 存储在索引旁的元数据，用于检测配置变化
 type BleveMeta struct {
 	FtsBleveStoreRaw bool `json:"fts-bleve-store-raw"` // Config value for store raw content // 是否存储原始文本配置值
-	Version          int  `json:"version"`             // Metadata schema version // 元数据版本号
+	Version          int  `json:"version"`             // Index mapping/analyzer version, see bleveIndexVersion // 索引映射/分析器版本，见 bleveIndexVersion
 }
 
 // BleveNoteDoc defines the document structure indexed in Bleve
@@ -83,6 +82,12 @@ type BleveManager struct {
 	logger   *zap.Logger // Logger instance // 日志记录器实例
 	indexes  sync.Map    // Cached open bleve.Index instances, keyed by "uid_vaultID" // 已打开的 bleve.Index 实例缓存，键为 "uid_vaultID"
 	mu       sync.Mutex  // Mutex protecting open/create operations on index files // 保护索引文件打开/创建操作的互斥锁
+	rebuilds sync.Map    // Vault indexes currently being rebuilt, keyed by "uid_vaultID" // 正在重建的仓库索引，键为 "uid_vaultID"
+
+	reindexJobs  sync.Map            // Latest background reindex job per user, keyed by uid // 每个用户最近一次的后台索引重建任务，键为 uid
+	reindexQueue chan *ftsReindexJob // Background reindex job queue, created on first use // 后台索引重建任务队列，首次使用时创建
+	reindexOnce  sync.Once           // Starts the reindex worker once // 保证索引重建 worker 只启动一次
+	reindexMu    sync.Mutex          // Serializes job enqueueing per manager // 串行化任务入队
 
 	ftsQueue    chan ftsOp     // Async FTS mutation queue consumed by ftsWorker // 由 ftsWorker 消费的异步 FTS 变更队列
 	ftsWorkerWG sync.WaitGroup // Tracks the background ftsWorker goroutine // 跟踪后台 ftsWorker goroutine
@@ -190,6 +195,7 @@ func (m *BleveManager) Shutdown() {
 		m.ftsMu.Unlock()
 	})
 	m.ftsWorkerWG.Wait()
+	m.cancelAllReindex()
 }
 
 // ftsWorker consumes queued FTS ops, accumulating them into per-vault Bleve
@@ -345,7 +351,7 @@ func (m *BleveManager) GetIndex(uid, vaultID int64) (bleve.Index, error) {
 		// 写入 meta.json
 		meta := BleveMeta{
 			FtsBleveStoreRaw: m.storeRaw,
-			Version:          1,
+			Version:          bleveIndexVersion,
 		}
 		metaData, marshalErr := json.Marshal(meta)
 		if marshalErr == nil {
@@ -414,6 +420,20 @@ func (m *BleveManager) CloseAll() error {
 	return lastErr
 }
 
+// IsOutdated reports whether an existing index on disk was built with an older mapping version
+// IsOutdated 判断磁盘上已存在的索引是否由旧版本映射构建
+func (m *BleveManager) IsOutdated(uid, vaultID int64) bool {
+	metaData, err := os.ReadFile(filepath.Join(m.GetIndexPath(uid, vaultID), "meta.json"))
+	if err != nil {
+		return false // Missing indexes are handled by the regular create/rebuild path // 索引缺失由常规创建/重建流程处理
+	}
+	var meta BleveMeta
+	if err := json.Unmarshal(metaData, &meta); err != nil {
+		return false
+	}
+	return meta.Version < bleveIndexVersion
+}
+
 // DeleteIndex closes and physically removes index files for a specific vault
 // DeleteIndex 关闭并物理删除特定仓库的索引文件
 func (m *BleveManager) DeleteIndex(uid, vaultID int64) error {
@@ -444,10 +464,10 @@ func (m *BleveManager) closeAndClean(uid, vaultID int64) error {
 func (m *BleveManager) createIndexMapping() mapping.IndexMapping {
 	indexMapping := bleve.NewIndexMapping()
 
-	// Text field mapping using "cjk" analyzer
+	// Text field mapping using the CJK bigram + unigram index analyzer
 	// 文本字段映射，使用内置的 "cjk" 中日韩分词器
 	textFieldMapping := bleve.NewTextFieldMapping()
-	textFieldMapping.Analyzer = "cjk"
+	textFieldMapping.Analyzer = bleveCJKIndexAnalyzer
 	textFieldMapping.Store = m.storeRaw
 	textFieldMapping.Index = true
 
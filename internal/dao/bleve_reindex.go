@@ -0,0 +1,207 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
+	"go.uber.org/zap"
+)
+
+// ftsReindexQueueSize is the buffer size of the background reindex job queue
+// ftsReindexQueueSize 是后台索引重建任务队列的缓冲区大小
+const ftsReindexQueueSize = 256
+
+// errFTSRebuildInProgress is returned when a vault index is already being rebuilt
+// errFTSRebuildInProgress 在仓库索引已在重建中时返回
+var errFTSRebuildInProgress = errors.New("fts index rebuild already in progress")
+
+// ftsReindexJob is a queued or running background reindex of one user's vaults.
+// Jobs run one at a time on a single worker so a large rebuild never competes with another.
+// ftsReindexJob 表示单个用户仓库的一次排队或运行中的后台索引重建。
+// 任务由单个 worker 依次执行，避免多个大规模重建相互争抢资源。
+type ftsReindexJob struct {
+	mu     sync.Mutex
+	state  domain.FTSReindexJob
+	ctx    context.Context
+	cancel context.CancelFunc
+	run    func(ctx context.Context, job *ftsReindexJob) error
+}
+
+// snapshot returns a copy of the job state
+// snapshot 返回任务状态的副本
+func (j *ftsReindexJob) snapshot() *domain.FTSReindexJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	state := j.state
+	return &state
+}
+
+// update mutates the job state under its lock
+// update 在锁内修改任务状态
+func (j *ftsReindexJob) update(fn func(state *domain.FTSReindexJob)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	fn(&j.state)
+}
+
+// EnqueueReindex queues a background reindex job for a user; if the user already has an active
+// job, that job is returned instead. run reports progress through the job it receives.
+// EnqueueReindex 为用户加入一个后台索引重建任务；若用户已有进行中的任务则直接返回该任务。
+// run 通过传入的任务上报进度。
+func (m *BleveManager) EnqueueReindex(uid int64, run func(ctx context.Context, job *ftsReindexJob) error) *domain.FTSReindexJob {
+	m.reindexMu.Lock()
+	defer m.reindexMu.Unlock()
+
+	if val, ok := m.reindexJobs.Load(uid); ok {
+		if existing := val.(*ftsReindexJob).snapshot(); existing.IsActive() {
+			return existing
+		}
+	}
+
+	m.reindexOnce.Do(func() {
+		m.reindexQueue = make(chan *ftsReindexJob, ftsReindexQueueSize)
+		safego.Go(m.logger, m.reindexWorker)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &ftsReindexJob{
+		state:  domain.FTSReindexJob{UID: uid, Status: domain.FTSReindexQueued, QueuedAt: time.Now()},
+		ctx:    ctx,
+		cancel: cancel,
+		run:    run,
+	}
+	m.reindexJobs.Store(uid, job)
+
+	select {
+	case m.reindexQueue <- job:
+	default:
+		cancel()
+		job.update(func(state *domain.FTSReindexJob) {
+			state.Status = domain.FTSReindexFailed
+			state.Error = "reindex queue is full"
+			state.FinishedAt = time.Now()
+		})
+	}
+	return job.snapshot()
+}
+
+// ReindexJob returns the latest reindex job of a user
+// ReindexJob 返回用户最近一次的索引重建任务
+func (m *BleveManager) ReindexJob(uid int64) (*domain.FTSReindexJob, bool) {
+	val, ok := m.reindexJobs.Load(uid)
+	if !ok {
+		return nil, false
+	}
+	return val.(*ftsReindexJob).snapshot(), true
+}
+
+// ReindexJobs returns the latest reindex job of every user, most recently queued first
+// ReindexJobs 返回所有用户最近一次的索引重建任务，按入队时间倒序
+func (m *BleveManager) ReindexJobs() []*domain.FTSReindexJob {
+	jobs := []*domain.FTSReindexJob{}
+	m.reindexJobs.Range(func(_, val any) bool {
+		jobs = append(jobs, val.(*ftsReindexJob).snapshot())
+		return true
+	})
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].QueuedAt.After(jobs[j].QueuedAt) })
+	return jobs
+}
+
+// ReindexPending reports whether a user has a queued or running reindex job
+// ReindexPending 判断用户是否有排队或运行中的索引重建任务
+func (m *BleveManager) ReindexPending(uid int64) bool {
+	job, ok := m.ReindexJob(uid)
+	return ok && job.IsActive()
+}
+
+// CancelReindex cancels a user's queued or running reindex job; a running job stops after the
+// note being indexed, leaving the interrupted vault to be rebuilt on the next search
+// CancelReindex 取消用户排队或运行中的索引重建任务；运行中的任务在当前笔记处理完后停止，
+// 被中断的仓库会在下次搜索时重建
+func (m *BleveManager) CancelReindex(uid int64) bool {
+	val, ok := m.reindexJobs.Load(uid)
+	if !ok {
+		return false
+	}
+	job := val.(*ftsReindexJob)
+	if !job.snapshot().IsActive() {
+		return false
+	}
+	job.cancel()
+	job.update(func(state *domain.FTSReindexJob) {
+		if state.Status == domain.FTSReindexQueued {
+			state.Status = domain.FTSReindexCanceled
+			state.FinishedAt = time.Now()
+		}
+	})
+	return true
+}
+
+// cancelAllReindex cancels every active reindex job, used on shutdown
+// cancelAllReindex 取消所有进行中的索引重建任务，用于关闭时
+func (m *BleveManager) cancelAllReindex() {
+	m.reindexJobs.Range(func(key, _ any) bool {
+		m.CancelReindex(key.(int64))
+		return true
+	})
+}
+
+// reindexWorker runs queued reindex jobs one at a time
+// reindexWorker 依次执行排队的索引重建任务
+func (m *BleveManager) reindexWorker() {
+	for job := range m.reindexQueue {
+		if job.ctx.Err() != nil {
+			continue // Canceled while queued // 排队期间已被取消
+		}
+		job.update(func(state *domain.FTSReindexJob) {
+			state.Status = domain.FTSReindexRunning
+			state.StartedAt = time.Now()
+		})
+
+		err := job.run(job.ctx, job)
+
+		job.update(func(state *domain.FTSReindexJob) {
+			state.FinishedAt = time.Now()
+			switch {
+			case job.ctx.Err() != nil:
+				state.Status = domain.FTSReindexCanceled
+			case err != nil:
+				state.Status = domain.FTSReindexFailed
+				state.Error = err.Error()
+			default:
+				state.Status = domain.FTSReindexDone
+			}
+		})
+		job.cancel()
+
+		if err != nil && job.ctx.Err() == nil {
+			m.logger.Warn("background FTS reindex failed", zap.Int64("uid", job.snapshot().UID), zap.Error(err))
+		}
+	}
+}
+
+// tryBeginRebuild marks a vault index as being rebuilt, returns false if it already is
+// tryBeginRebuild 标记仓库索引正在重建，若已在重建中则返回 false
+func (m *BleveManager) tryBeginRebuild(uid, vaultID int64) bool {
+	_, running := m.rebuilds.LoadOrStore(fmt.Sprintf("%d_%d", uid, vaultID), struct{}{})
+	return !running
+}
+
+// endRebuild clears the rebuild mark of a vault index
+// endRebuild 清除仓库索引的重建标记
+func (m *BleveManager) endRebuild(uid, vaultID int64) {
+	m.rebuilds.Delete(fmt.Sprintf("%d_%d", uid, vaultID))
+}
+
+// IsRebuilding reports whether a vault index is being rebuilt
+// IsRebuilding 判断仓库索引是否正在重建
+func (m *BleveManager) IsRebuilding(uid, vaultID int64) bool {
+	_, ok := m.rebuilds.Load(fmt.Sprintf("%d_%d", uid, vaultID))
+	return ok
+}
@@ -0,0 +1,74 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// waitReindexStatus polls a user's reindex job until it reaches the wanted status
+// waitReindexStatus 轮询用户的索引重建任务直到其达到期望状态
+func waitReindexStatus(t *testing.T, m *BleveManager, uid int64, want domain.FTSReindexStatus) *domain.FTSReindexJob {
+	var job *domain.FTSReindexJob
+	require.Eventually(t, func() bool {
+		job, _ = m.ReindexJob(uid)
+		return job != nil && job.Status == want
+	}, 2*time.Second, 5*time.Millisecond)
+	return job
+}
+
+// TestBleveManager_ReindexQueue verifies jobs run one at a time with progress, an active job is
+// reused instead of queued twice, and queued or running jobs can be canceled.
+// TestBleveManager_ReindexQueue 验证任务依次执行并上报进度、进行中的任务不会重复入队，
+// 以及排队或运行中的任务可以被取消。
+func TestBleveManager_ReindexQueue(t *testing.T) {
+	m := NewBleveManager(util.Ptr(true), util.Ptr(false), zap.NewNop())
+	defer m.Shutdown()
+
+	release := make(chan struct{})
+	blocking := func(ctx context.Context, job *ftsReindexJob) error {
+		job.update(func(state *domain.FTSReindexJob) { state.VaultsTotal, state.NotesTotal, state.NotesDone = 1, 10, 4 })
+		select {
+		case <-release:
+			job.update(func(state *domain.FTSReindexJob) { state.VaultsDone, state.NotesDone = 1, 10 })
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	first := m.EnqueueReindex(1, blocking)
+	assert.Equal(t, domain.FTSReindexQueued, first.Status)
+	running := waitReindexStatus(t, m, 1, domain.FTSReindexRunning)
+	assert.Equal(t, 4, running.NotesDone)
+
+	again := m.EnqueueReindex(1, blocking)
+	assert.Equal(t, domain.FTSReindexRunning, again.Status, "an active job is returned instead of queueing another")
+
+	queued := m.EnqueueReindex(2, blocking)
+	assert.Equal(t, domain.FTSReindexQueued, queued.Status)
+	assert.True(t, m.ReindexPending(2))
+	assert.True(t, m.CancelReindex(2))
+	canceled, _ := m.ReindexJob(2)
+	assert.Equal(t, domain.FTSReindexCanceled, canceled.Status)
+	assert.False(t, m.CancelReindex(2), "a finished job cannot be canceled")
+
+	close(release)
+	done := waitReindexStatus(t, m, 1, domain.FTSReindexDone)
+	assert.Equal(t, 10, done.NotesDone)
+	assert.False(t, done.FinishedAt.IsZero())
+
+	release = make(chan struct{})
+	m.EnqueueReindex(3, blocking)
+	waitReindexStatus(t, m, 3, domain.FTSReindexRunning)
+	assert.True(t, m.CancelReindex(3))
+	waitReindexStatus(t, m, 3, domain.FTSReindexCanceled)
+
+	assert.Len(t, m.ReindexJobs(), 3)
+}
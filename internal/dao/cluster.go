@@ -0,0 +1,78 @@
+package dao
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/cluster"
+	"go.uber.org/zap"
+)
+
+// Bus topics of the cache invalidations relayed between instances
+// 实例间转发缓存失效通知所用的总线主题
+const (
+	clusterTopicLookup  = "dao.lookup"
+	clusterTopicContent = "dao.content"
+)
+
+// WithClusterBus relays the invalidations of the per-instance caches to the other instances sharing
+// the database: the hot vault and note rows, and the local copies of the content store
+// WithClusterBus 向共享数据库的其他实例转发各实例缓存的失效通知：热点仓库与笔记行，以及内容存储的本地副本
+func WithClusterBus(bus cluster.Bus) DaoOption {
+	return func(d *Dao) {
+		d.cluster = bus
+	}
+}
+
+// joinCluster subscribes to the invalidations of the other instances and publishes the local ones
+// joinCluster 订阅其他实例的失效通知并发布本实例的失效通知
+func (d *Dao) joinCluster() {
+	if d.cluster == nil {
+		return
+	}
+
+	d.lookups.onWrite = func(uid int64) {
+		if err := d.cluster.Publish(clusterTopicLookup, []byte(strconv.FormatInt(uid, 10))); err != nil {
+			d.logger.Warn("cluster: publish lookup invalidation failed", zap.Int64("uid", uid), zap.Error(err))
+		}
+	}
+	d.cluster.Subscribe(clusterTopicLookup, func(payload []byte) {
+		if uid, err := strconv.ParseInt(string(payload), 10, 64); err == nil {
+			d.lookups.dropUser(uid)
+		}
+	})
+
+	// Without a content store storage/vault is shared by the instances and is not a cache
+	// 未配置内容存储时 storage/vault 由各实例共享，并非缓存
+	if d.contents != nil {
+		d.cluster.Subscribe(clusterTopicContent, func(payload []byte) {
+			d.evictContent(filepath.FromSlash(string(payload)))
+		})
+	}
+}
+
+// publishContentChange tells the other instances their local copy of a content path is stale
+// publishContentChange 通知其他实例某内容路径的本地副本已过期
+func (d *Dao) publishContentChange(path string) {
+	if d.cluster == nil || d.contents == nil {
+		return
+	}
+	if err := d.cluster.Publish(clusterTopicContent, []byte(filepath.ToSlash(path))); err != nil {
+		d.logger.Warn("cluster: publish content invalidation failed", zap.String("path", path), zap.Error(err))
+	}
+}
+
+// evictContent drops the local copy of a content file or folder, it is fetched again on the next read
+// evictContent 删除内容文件或目录的本地副本，下次读取时重新拉取
+func (d *Dao) evictContent(path string) {
+	if _, _, ok := usagePathOwner(path); !ok {
+		return
+	}
+	size := usageDirSize(path)
+	if err := os.RemoveAll(path); err != nil {
+		d.logger.Warn("cluster: evict content failed", zap.String("path", path), zap.Error(err))
+		return
+	}
+	d.usage.add(path, -size)
+}
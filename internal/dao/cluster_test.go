@@ -0,0 +1,72 @@
+package dao
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/contentstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingBus cluster.Bus keeping the published messages and the subscribed handlers
+type recordingBus struct {
+	published map[string][]string
+	handlers  map[string]func([]byte)
+}
+
+func (b *recordingBus) Publish(topic string, payload []byte) error {
+	b.published[topic] = append(b.published[topic], string(payload))
+	return nil
+}
+
+func (b *recordingBus) Subscribe(topic string, handler func([]byte)) { b.handlers[topic] = handler }
+func (b *recordingBus) InstanceID() string                           { return "test" }
+func (b *recordingBus) Close() error                                 { return nil }
+
+// TestDao_ClusterInvalidation verifies local writes are published and the invalidations of the
+// other instances drop the cached rows and the local copies of the content store.
+// TestDao_ClusterInvalidation 验证本地写入会被发布，且其他实例的失效通知会清除缓存的行与内容存储的本地副本。
+func TestDao_ClusterInvalidation(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	bus := &recordingBus{published: map[string][]string{}, handlers: map[string]func([]byte){}}
+	daoInst.contents = contentstore.NewLocal(t.TempDir())
+	daoInst.cluster = bus
+	daoInst.joinCluster()
+
+	vault := &model.Vault{ID: 7, Vault: "Work"}
+	daoInst.lookups.addVault(1, daoInst.lookups.generation(1), vault)
+	bus.handlers[clusterTopicLookup]([]byte("1"))
+	_, ok := daoInst.lookups.getVault(vaultLookupKey{uid: 1, id: 7})
+	assert.False(t, ok)
+	assert.Empty(t, bus.published[clusterTopicLookup], "remote invalidations are not published again")
+
+	daoInst.lookups.invalidateVaults(1)
+	assert.Equal(t, []string{"1"}, bus.published[clusterTopicLookup])
+
+	folder := daoInst.GetNoteFolderPath(1, 1)
+	require.NoError(t, daoInst.SaveContentToFile(folder, "content.txt", "hello"))
+	path := filepath.Join(folder, "content.txt")
+	assert.Equal(t, []string{filepath.ToSlash(path)}, bus.published[clusterTopicContent])
+
+	bus.handlers[clusterTopicContent]([]byte(filepath.ToSlash(path)))
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// The next read refills the evicted copy from the store
+	// 下次读取时从存储回填被清除的副本
+	content, exists, err := daoInst.LoadContentFromFile(folder, "content.txt")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "hello", content)
+
+	// Paths outside the content folders are never touched
+	// 内容目录之外的路径不受影响
+	require.NoError(t, os.WriteFile("keep.txt", []byte("x"), 0644))
+	bus.handlers[clusterTopicContent]([]byte("keep.txt"))
+	assert.FileExists(t, "keep.txt")
+}
@@ -0,0 +1,163 @@
+package dao
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/contentstore"
+)
+
+// contentStoreRoot local directory the content store keys are relative to
+// contentStoreRoot 内容存储键所相对的本地目录
+const contentStoreRoot = "storage"
+
+// WithContentStore keeps the content folders in a pluggable store, storage/vault becomes a local
+// cache that is written through and refilled on demand (nil keeps the content on the local disk only)
+// WithContentStore 将内容目录保存到可插拔存储中，storage/vault 成为同步写入、按需回填的本地缓存
+// （nil 表示内容仅保存在本地磁盘）
+func WithContentStore(store contentstore.Store) DaoOption {
+	return func(d *Dao) {
+		d.contents = store
+	}
+}
+
+// VaultRoot returns the local root of the per-user content folders, relative to the working directory
+// VaultRoot 返回各用户内容目录的本地根目录，相对于工作目录
+func VaultRoot() string {
+	return usageVaultRoot
+}
+
+// VaultStorePrefix returns the content store folder the per-user content folders are kept under, e.g. vault/
+// VaultStorePrefix 返回各用户内容目录在内容存储中所在的目录，如 vault/
+func VaultStorePrefix() string {
+	rel, _ := filepath.Rel(contentStoreRoot, usageVaultRoot)
+	return filepath.ToSlash(rel) + "/"
+}
+
+// contentKey returns the store key of a path below storage/vault, e.g. vault/u_1/file/f_2/file.dat
+// contentKey 返回 storage/vault 下路径的存储键，如 vault/u_1/file/f_2/file.dat
+func contentKey(path string) (string, bool) {
+	if _, _, ok := usagePathOwner(path); !ok {
+		return "", false
+	}
+	rel, err := filepath.Rel(contentStoreRoot, path)
+	if err != nil {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// pushContent uploads a local content file to the store
+// pushContent 将本地内容文件上传到存储
+func (d *Dao) pushContent(path string) error {
+	if d.contents == nil {
+		return nil
+	}
+	key, ok := contentKey(path)
+	if !ok {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := d.contents.Put(key, f); err != nil {
+		return err
+	}
+	d.publishContentChange(path)
+	return nil
+}
+
+// FetchContentFile downloads a content file missing from the local cache, fs.ErrNotExist is returned
+// when the store does not have it either; without a store it does nothing
+// FetchContentFile 下载本地缓存中缺失的内容文件，存储中也不存在时返回 fs.ErrNotExist；未配置存储时不做任何操作
+func (d *Dao) FetchContentFile(path string) error {
+	if d.contents == nil {
+		return nil
+	}
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	key, ok := contentKey(path)
+	if !ok {
+		return nil
+	}
+
+	rc, err := d.contents.Open(key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// Concurrent fetches of the same file each write their own temporary file
+	// 同一文件的并发回填各自写入独立的临时文件
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".fetch-*")
+	if err != nil {
+		return err
+	}
+	size, err := io.Copy(tmp, rc)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	d.usage.add(path, size)
+	return nil
+}
+
+// RenameContentFile moves a content file to another content path, fetching it first when it is not cached
+// RenameContentFile 将内容文件移动到另一内容路径，未缓存时先回填
+func (d *Dao) RenameContentFile(src, dst string) error {
+	if err := d.FetchContentFile(src); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	before := usageFileSize(dst)
+	size := usageFileSize(src)
+	if err := os.Rename(src, dst); err != nil {
+		return err
+	}
+	d.usage.add(src, -size)
+	d.usage.add(dst, size-before)
+
+	if err := d.pushContent(dst); err != nil {
+		return err
+	}
+	if key, ok := contentKey(src); ok && d.contents != nil {
+		if err := d.contents.Delete(key); err != nil {
+			return err
+		}
+		d.publishContentChange(src)
+	}
+	return nil
+}
+
+// removeStoredFolder removes a content folder from the store
+// removeStoredFolder 从存储中删除内容目录
+func (d *Dao) removeStoredFolder(folderPath string) error {
+	if d.contents == nil {
+		return nil
+	}
+	key, ok := contentKey(folderPath)
+	if !ok {
+		return nil
+	}
+	if err := d.contents.DeletePrefix(strings.TrimSuffix(key, "/") + "/"); err != nil {
+		return err
+	}
+	d.publishContentChange(folderPath)
+	return nil
+}
@@ -0,0 +1,60 @@
+package dao
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/contentstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContentStore_WriteThroughAndRefill verifies content written through the helpers reaches the store,
+// a wiped local cache is refilled on read and renames and removals are applied to the store as well.
+// TestContentStore_WriteThroughAndRefill 验证通过辅助方法写入的内容会同步到存储，本地缓存被清空后读取时回填，
+// 重命名与删除也会同步到存储。
+func TestContentStore_WriteThroughAndRefill(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	storeDir := t.TempDir()
+	daoInst.contents = contentstore.NewLocal(storeDir)
+	const uid = int64(1)
+
+	noteFolder := daoInst.GetNoteFolderPath(uid, 1)
+	require.NoError(t, daoInst.SaveContentToFile(noteFolder, "content.txt", "hello"))
+	assert.FileExists(t, filepath.Join(storeDir, "vault", "u_1", "note", "n_1", "content.txt"))
+
+	src := filepath.Join(t.TempDir(), "upload.tmp")
+	require.NoError(t, os.WriteFile(src, []byte("blob"), 0644))
+	blob := filepath.Join(daoInst.GetFileFolderPath(uid, 1), "file.dat")
+	require.NoError(t, os.MkdirAll(filepath.Dir(blob), 0755))
+	require.NoError(t, daoInst.MoveContentFile(src, blob))
+
+	// Simulate a fresh ephemeral disk
+	// 模拟全新的临时磁盘
+	require.NoError(t, os.RemoveAll(usageVaultRoot))
+
+	content, exists, err := daoInst.LoadContentFromFile(noteFolder, "content.txt")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "hello", content)
+
+	_, exists, err = daoInst.LoadContentFromFile(daoInst.GetNoteFolderPath(uid, 2), "content.txt")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	moved := filepath.Join(daoInst.GetFileFolderPath(uid, 2), "file.dat")
+	require.NoError(t, daoInst.RenameContentFile(blob, moved))
+	data, err := os.ReadFile(moved)
+	require.NoError(t, err)
+	assert.Equal(t, "blob", string(data))
+	assert.NoFileExists(t, filepath.Join(storeDir, "vault", "u_1", "file", "f_1", "file.dat"))
+	assert.FileExists(t, filepath.Join(storeDir, "vault", "u_1", "file", "f_2", "file.dat"))
+
+	require.NoError(t, os.RemoveAll(usageVaultRoot))
+	require.NoError(t, daoInst.RemoveContentFolder(daoInst.GetFileFolderPath(uid, 2)))
+	assert.NoDirExists(t, filepath.Join(storeDir, "vault", "u_1", "file", "f_2"))
+	assert.ErrorIs(t, daoInst.FetchContentFile(moved), os.ErrNotExist)
+}
@@ -13,12 +13,14 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/internal/config"
 	"github.com/haierkeys/fast-note-sync-service/internal/model"
 	"github.com/haierkeys/fast-note-sync-service/internal/query"
+	"github.com/haierkeys/fast-note-sync-service/pkg/cluster"
+	"github.com/haierkeys/fast-note-sync-service/pkg/contentstore"
 	"github.com/haierkeys/fast-note-sync-service/pkg/fileurl"
+	"github.com/haierkeys/fast-note-sync-service/pkg/tracer"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"github.com/haierkeys/fast-note-sync-service/pkg/writequeue"
 
 	"github.com/glebarez/sqlite"
-	"github.com/haierkeys/gormTracing"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -76,6 +78,11 @@ type Dao struct {
 	logger        *zap.Logger
 	writeQueueMgr *writequeue.Manager
 	BleveMgr      *BleveManager       // Bleve index manager instance // Bleve 索引管理器实例
+	noteWrites    *noteWriteCoalescer // note update coalescer, nil when disabled // 笔记更新合并器，未启用时为 nil
+	lookups       *lookupCache        // hot vault and note row cache // 热点仓库与笔记行缓存
+	usage         *usageTracker       // on-disk bytes of the content folders // 内容目录占用的磁盘字节数
+	contents      contentstore.Store  // store behind the content folders, nil keeps them local only // 内容目录背后的存储，nil 表示仅保存在本地
+	cluster       cluster.Bus         // relays cache invalidations to the other instances, nil when running alone // 向其他实例转发缓存失效通知，单实例运行时为 nil
 }
 
 // DaoOption option function for configuring Dao
@@ -122,6 +129,16 @@ func WithBleveManager(mgr *BleveManager) DaoOption {
 	}
 }
 
+// WithNoteWriteCoalesceWindow buffers rapid successive updates of the same note for the window (0 disables)
+// WithNoteWriteCoalesceWindow 在窗口期内缓冲同一笔记的连续更新（0 表示禁用）
+func WithNoteWriteCoalesceWindow(window time.Duration) DaoOption {
+	return func(d *Dao) {
+		if window > 0 {
+			d.noteWrites = newNoteWriteCoalescer(window, nil)
+		}
+	}
+}
+
 // WithMaxCachedDBConns overrides the cap on cached tenant DB instances (default 200)
 // WithMaxCachedDBConns 覆盖缓存的租户 DB 实例数量上限（默认 200）
 func WithMaxCachedDBConns(n int) DaoOption {
@@ -184,9 +201,11 @@ func RegisterModel(cfg ModelConfig) {
 // opts: Optional configuration items // opts: 可选配置项
 func New(db *gorm.DB, ctx context.Context, opts ...DaoOption) *Dao {
 	d := &Dao{
-		Db:    db,
-		ctx:   ctx,
-		KeyDb: make(map[string]*dbEntry),
+		Db:      db,
+		ctx:     ctx,
+		KeyDb:   make(map[string]*dbEntry),
+		lookups: newLookupCache(),
+		usage:   newUsageTracker(),
 	}
 
 	// 应用选项
@@ -198,6 +217,10 @@ func New(db *gorm.DB, ctx context.Context, opts ...DaoOption) *Dao {
 	if d.logger == nil {
 		d.logger = zap.NewNop()
 	}
+	if d.noteWrites != nil {
+		d.noteWrites.logger = d.logger
+	}
+	d.joinCluster()
 
 	return d
 }
@@ -217,6 +240,14 @@ func (d *Dao) Config() *config.DatabaseConfig {
 	return d.config
 }
 
+// FlushNoteWrites writes all buffered note updates, called before the write queue shuts down
+// FlushNoteWrites 写入所有缓冲的笔记更新，在写队列关闭前调用
+func (d *Dao) FlushNoteWrites(ctx context.Context) {
+	if d.noteWrites != nil {
+		d.noteWrites.flushUser(ctx, 0)
+	}
+}
+
 // WriteQueueManager gets the write queue manager
 // WriteQueueManager 获取写队列管理器
 func (d *Dao) WriteQueueManager() *writequeue.Manager {
@@ -308,6 +339,13 @@ func (d *Dao) resolveConfig(key string) config.DatabaseConfig {
 	return cfg
 }
 
+// sqliteKeyPath returns the file of a keyed SQLite database, the key is inserted before the extension
+// sqliteKeyPath 返回带标识的 SQLite 数据库文件路径，标识插入在扩展名之前
+func sqliteKeyPath(path, key string) string {
+	ext := filepath.Ext(path)
+	return path[:len(path)-len(ext)] + "_" + key + ext
+}
+
 func (d *Dao) GetOrCreateDB(key string) *gorm.DB {
 	// Use read lock to check if already exists
 	// 使用读锁检查是否已存在
@@ -354,8 +392,7 @@ func (d *Dao) GetOrCreateDB(key string) *gorm.DB {
 	} else if c.Type == "sqlite" && key != "" {
 		// SQLite: Maintain multi-file isolation mode (using full key as filename suffix)
 		// SQLite: 维持多文件隔离模式 (使用完整的 key 作为文件名后缀)
-		ext := filepath.Ext(c.Path)
-		c.Path = c.Path[:len(c.Path)-len(ext)] + "_" + key + ext
+		c.Path = sqliteKeyPath(c.Path, key)
 	}
 
 	dbNew, err := NewEngine(c, d.Logger())
@@ -511,7 +548,7 @@ func NewEngine(c config.DatabaseConfig, zapLogger *zap.Logger) (*gorm.DB, error)
 	}
 	sqlDB.SetConnMaxIdleTime(connMaxIdleTime)
 
-	_ = db.Use(&gormTracing.OpentracingPlugin{})
+	_ = db.Use(&tracer.GormPlugin{})
 
 	return db, nil
 
@@ -744,7 +781,15 @@ func (d *Dao) AutoMigrate(uid int64, modelKey string) error {
 	if b == nil {
 		return fmt.Errorf("database connection is nil for model %s (uid=%d, dbKey=%s)", modelKey, uid, dbKey)
 	}
-	return model.AutoMigrate(b, modelKey)
+	if err := model.AutoMigrate(b, modelKey); err != nil {
+		return err
+	}
+	if modelKey == "Folder" {
+		// Merge duplicate folders of existing databases before the unique index can be added
+		// 在添加唯一索引前合并已有数据库中的重复文件夹
+		return ensureFolderPathUnique(b)
+	}
+	return nil
 }
 
 // user gets the user query object (internal method)
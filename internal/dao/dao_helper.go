@@ -1,10 +1,13 @@
 package dao
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
 	"github.com/haierkeys/fast-note-sync-service/pkg/fileurl"
 )
 
@@ -39,7 +42,23 @@ func (d *Dao) SaveContentToFile(folderPath string, fileName string, content stri
 		return err
 	}
 	filePath := filepath.Join(folderPath, fileName)
-	return os.WriteFile(filePath, []byte(content), 0644)
+	before := usageFileSize(filePath)
+	if err := atrest.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return err
+	}
+	d.usage.add(filePath, usageFileSize(filePath)-before)
+	return d.pushContent(filePath)
+}
+
+// MoveContentFile moves a file into a content folder, replacing the existing one
+// MoveContentFile 将文件移动到内容目录中，替换已存在的文件
+func (d *Dao) MoveContentFile(src, dst string) error {
+	before := usageFileSize(dst)
+	if err := atrest.MoveFile(src, dst); err != nil {
+		return err
+	}
+	d.usage.add(dst, usageFileSize(dst)-before)
+	return d.pushContent(dst)
 }
 
 // loadContentFromFile loads content from a file
@@ -48,9 +67,16 @@ func (d *Dao) SaveContentToFile(folderPath string, fileName string, content stri
 // 返回值: 内容, 是否存在, 错误
 func (d *Dao) LoadContentFromFile(folderPath string, fileName string) (string, bool, error) {
 	filePath := filepath.Join(folderPath, fileName)
-	content, err := os.ReadFile(filePath)
+	content, err := atrest.ReadFile(filePath)
+	if os.IsNotExist(err) && d.contents != nil {
+		// Not cached locally, refill from the content store
+		// 本地未缓存，从内容存储回填
+		if err = d.FetchContentFile(filePath); err == nil {
+			content, err = atrest.ReadFile(filePath)
+		}
+	}
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			return "", false, nil
 		}
 		return "", false, err
@@ -62,7 +88,11 @@ func (d *Dao) LoadContentFromFile(folderPath string, fileName string) (string, b
 // removeContentFolder 删除内容文件夹
 func (d *Dao) RemoveContentFolder(folderPath string) error {
 	if fileurl.IsExist(folderPath) {
-		return os.RemoveAll(folderPath)
+		size := usageDirSize(folderPath)
+		if err := os.RemoveAll(folderPath); err != nil {
+			return err
+		}
+		d.usage.add(folderPath, -size)
 	}
-	return nil
+	return d.removeStoredFolder(folderPath)
 }
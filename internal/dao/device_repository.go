@@ -0,0 +1,137 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// deviceRepository implements domain.DeviceRepository
+// deviceRepository 实现 domain.DeviceRepository 接口
+type deviceRepository struct {
+	dao *Dao
+}
+
+// NewDeviceRepository creates a DeviceRepository instance
+// NewDeviceRepository 创建 DeviceRepository 实例
+func NewDeviceRepository(dao *Dao) domain.DeviceRepository {
+	return &deviceRepository{dao: dao}
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name:     "Device",
+		IsMainDB: true,
+	})
+}
+
+func (r *deviceRepository) db() *gorm.DB {
+	db := r.dao.ResolveDB()
+	r.dao.QueryWithOnceInit(func(g *gorm.DB) {
+		model.AutoMigrate(g, "Device")
+	}, "user#device")
+	return db
+}
+
+// deviceToDomain converts the database model to the domain model
+// deviceToDomain 将数据库模型转换为领域模型
+func deviceToDomain(m *model.Device) *domain.Device {
+	d := &domain.Device{
+		ID:            m.ID,
+		UID:           m.UID,
+		DeviceKey:     m.DeviceKey,
+		TokenID:       m.TokenID,
+		ClientName:    m.ClientName,
+		ClientType:    m.ClientType,
+		ClientVersion: m.ClientVersion,
+		IP:            m.IP,
+		UserAgent:     m.UserAgent,
+		LastSeenAt:    time.Time(m.LastSeenAt),
+		CreatedAt:     time.Time(m.CreatedAt),
+		UpdatedAt:     time.Time(m.UpdatedAt),
+	}
+	for _, flag := range strings.Split(m.Platform, ",") {
+		if flag != "" {
+			d.Platform = append(d.Platform, flag)
+		}
+	}
+	return d
+}
+
+// ListByUID lists the devices of a user, most recently seen first
+// ListByUID 按最近出现时间倒序列出用户的设备
+func (r *deviceRepository) ListByUID(ctx context.Context, uid int64) ([]*domain.Device, error) {
+	var rows []*model.Device
+	if err := r.db().WithContext(ctx).Where("uid = ?", uid).Order("last_seen_at DESC, id DESC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	results := make([]*domain.Device, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, deviceToDomain(m))
+	}
+	return results, nil
+}
+
+// Get returns a device, nil when it does not exist
+// Get 获取设备，不存在时返回 nil
+func (r *deviceRepository) Get(ctx context.Context, id, uid int64) (*domain.Device, error) {
+	var m model.Device
+	err := r.db().WithContext(ctx).Where("id = ? AND uid = ?", id, uid).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return deviceToDomain(&m), nil
+}
+
+// Upsert creates the device or refreshes the device with the same UID and DeviceKey
+// Upsert 新建设备，或刷新 UID 与 DeviceKey 相同的设备
+func (r *deviceRepository) Upsert(ctx context.Context, device *domain.Device) (*domain.Device, error) {
+	now := timex.Now()
+	m := &model.Device{
+		UID:           device.UID,
+		DeviceKey:     device.DeviceKey,
+		TokenID:       device.TokenID,
+		ClientName:    device.ClientName,
+		ClientType:    device.ClientType,
+		ClientVersion: device.ClientVersion,
+		Platform:      strings.Join(device.Platform, ","),
+		IP:            device.IP,
+		UserAgent:     device.UserAgent,
+		LastSeenAt:    timex.Time(device.LastSeenAt),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	err := r.db().WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "uid"}, {Name: "device_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"token_id", "client_name", "client_type", "client_version", "platform", "ip", "user_agent", "last_seen_at", "updated_at",
+		}),
+	}).Create(m).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var saved model.Device
+	if err := r.db().WithContext(ctx).Where("uid = ? AND device_key = ?", device.UID, device.DeviceKey).First(&saved).Error; err != nil {
+		return nil, err
+	}
+	return deviceToDomain(&saved), nil
+}
+
+// Delete removes a device
+// Delete 删除设备
+func (r *deviceRepository) Delete(ctx context.Context, id, uid int64) error {
+	return r.db().WithContext(ctx).Where("id = ? AND uid = ?", id, uid).Delete(&model.Device{}).Error
+}
+
+var _ domain.DeviceRepository = (*deviceRepository)(nil)
@@ -0,0 +1,346 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"go.uber.org/zap"
+)
+
+// DoctorIssue a problem found by an integrity check
+// DoctorIssue 完整性检查发现的问题
+type DoctorIssue struct {
+	UID    int64  // Owner of the data, 0 for the main database // 数据所属用户，主数据库为 0
+	Kind   string // Problem kind, e.g. "orphan-note" or "fts-drift" // 问题类型，如 "orphan-note" 或 "fts-drift"
+	Target string // Affected file, folder or record // 受影响的文件、目录或记录
+	Detail string // Human readable description // 可读的描述
+	Fixed  bool   // Repaired by fix mode // 已由修复模式修复
+}
+
+// doctorContentKind a kind of content folder below storage/vault/u_<uid> and the table holding its rows
+// doctorContentKind storage/vault/u_<uid> 下的一类内容目录，以及保存其记录的表
+type doctorContentKind struct {
+	name   string // Sub directory, also the issue suffix // 子目录名，也是问题类型后缀
+	prefix string // Folder name prefix before the row ID // 目录名中记录 ID 前的前缀
+	dbKey  string // Database key prefix of the owning repository // 所属仓储的数据库标识前缀
+	model  any    // Model of the table // 表对应的模型
+}
+
+// doctorContentKinds content folders checked for orphans, see GetNoteFolderPath and friends
+// doctorContentKinds 检查孤立目录的内容目录类型，参见 GetNoteFolderPath 等
+var doctorContentKinds = []doctorContentKind{
+	{name: "note", prefix: "n_", dbKey: "user_", model: &model.Note{}},
+	{name: "file", prefix: "f_", dbKey: "user_file_", model: &model.File{}},
+	{name: "history", prefix: "h_", dbKey: "user_note_history_", model: &model.NoteHistory{}},
+	{name: "setting", prefix: "s_", dbKey: "user_setting_", model: &model.Setting{}},
+}
+
+// userDBExists reports whether the database of a key exists, without creating an empty SQLite file for it
+// userDBExists 判断某标识的数据库是否存在，不会为其创建空的 SQLite 文件
+func (d *Dao) userDBExists(key string) bool {
+	c := d.resolveConfig(key)
+	if c.Type != "sqlite" {
+		return true
+	}
+	_, err := os.Stat(sqliteKeyPath(c.Path, key))
+	return err == nil
+}
+
+// DoctorUIDs returns the UIDs of all users, disabled ones included
+// DoctorUIDs 返回所有用户的 UID，包括已禁用的用户
+func (d *Dao) DoctorUIDs() ([]int64, error) {
+	var uids []int64
+	u := d.user().User
+	if err := u.WithContext(d.ctx).Select(u.UID).Order(u.UID).Scan(&uids); err != nil {
+		return nil, err
+	}
+	return uids, nil
+}
+
+// SQLiteFiles lists the SQLite files of the main and per-user databases that exist on disk
+// SQLiteFiles 列出磁盘上已存在的主数据库与各用户数据库的 SQLite 文件
+func (d *Dao) SQLiteFiles() []string {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(path string) {
+		if abs, err := filepath.Abs(path); err == nil && !seen[abs] {
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				seen[abs] = true
+				files = append(files, path)
+			}
+		}
+	}
+	for _, key := range []string{"", "user_0"} {
+		c := d.resolveConfig(key)
+		if c.Type != "sqlite" || c.Path == "" {
+			continue
+		}
+		add(c.Path)
+		ext := filepath.Ext(c.Path)
+		matches, _ := filepath.Glob(c.Path[:len(c.Path)-len(ext)] + "_*" + ext)
+		sort.Strings(matches)
+		for _, m := range matches {
+			add(m)
+		}
+	}
+	return files
+}
+
+// SQLiteIntegrityCheck runs PRAGMA integrity_check on a SQLite file, returning the reported problems
+// SQLiteIntegrityCheck 对 SQLite 文件执行 PRAGMA integrity_check，返回报告的问题
+func SQLiteIntegrityCheck(path string, lg *zap.Logger) ([]string, error) {
+	db, err := NewEngine(config.DatabaseConfig{Type: "sqlite", Path: path}, lg)
+	if err != nil {
+		return nil, err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	var rows []string
+	if err := db.Raw("PRAGMA integrity_check").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 1 && rows[0] == "ok" {
+		return nil, nil
+	}
+	return rows, nil
+}
+
+// CheckOrphanContent finds content folders of notes, attachments, history and settings that have no
+// database row, removing them in fix mode. Kinds whose table does not exist are skipped, so that a
+// misconfigured database never makes all content look orphaned.
+// CheckOrphanContent 查找没有数据库记录的笔记、附件、历史与配置内容目录，修复模式下将其删除。
+// 表不存在的类型会被跳过，避免数据库配置错误时所有内容都被误判为孤立。
+func (d *Dao) CheckOrphanContent(ctx context.Context, uid int64, fix bool) ([]*DoctorIssue, error) {
+	var issues []*DoctorIssue
+	base := filepath.Join("storage", "vault", fmt.Sprintf("u_%d", uid))
+
+	for _, kind := range doctorContentKinds {
+		entries, err := os.ReadDir(filepath.Join(base, kind.name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return issues, err
+		}
+
+		key := kind.dbKey + strconv.FormatInt(uid, 10)
+		if !d.userDBExists(key) {
+			continue
+		}
+		db := d.ResolveDB(key)
+		if db == nil || !db.Migrator().HasTable(kind.model) {
+			continue
+		}
+		var ids []int64
+		if err := db.WithContext(ctx).Model(kind.model).Pluck("id", &ids).Error; err != nil {
+			return issues, err
+		}
+		known := make(map[int64]bool, len(ids))
+		for _, id := range ids {
+			known[id] = true
+		}
+
+		for _, e := range entries {
+			id, err := strconv.ParseInt(strings.TrimPrefix(e.Name(), kind.prefix), 10, 64)
+			if !e.IsDir() || !strings.HasPrefix(e.Name(), kind.prefix) || err != nil || known[id] {
+				continue
+			}
+			dir := filepath.Join(base, kind.name, e.Name())
+			issue := &DoctorIssue{UID: uid, Kind: "orphan-" + kind.name, Target: dir, Detail: fmt.Sprintf("no %s row with id %d", kind.name, id)}
+			if fix {
+				if err := os.RemoveAll(dir); err != nil {
+					issue.Detail += ", remove failed: " + err.Error()
+				} else {
+					issue.Fixed = true
+				}
+			}
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// CheckDuplicateFolders finds folder rows sharing a vault and path, merging them and adding the
+// unique index in fix mode
+// CheckDuplicateFolders 查找同一保险库中路径相同的文件夹记录，修复模式下合并并添加唯一索引
+func (d *Dao) CheckDuplicateFolders(ctx context.Context, uid int64, fix bool) ([]*DoctorIssue, error) {
+	key := "user_folder_" + strconv.FormatInt(uid, 10)
+	if !d.userDBExists(key) {
+		return nil, nil
+	}
+	db := d.ResolveDB(key)
+	if db == nil || !db.Migrator().HasTable(&model.Folder{}) {
+		return nil, nil
+	}
+
+	var groups []struct {
+		VaultID int64
+		Path    string
+		Cnt     int64
+	}
+	err := db.WithContext(ctx).Model(&model.Folder{}).
+		Select("vault_id, MIN(path) AS path, COUNT(*) AS cnt").
+		Group("vault_id, path_hash").Having("COUNT(*) > 1").
+		Scan(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+
+	var fixErr error
+	if fix {
+		fixErr = ensureFolderPathUnique(db.WithContext(ctx))
+	}
+	issues := make([]*DoctorIssue, 0, len(groups))
+	for _, g := range groups {
+		issue := &DoctorIssue{
+			UID:    uid,
+			Kind:   "folder-duplicate",
+			Target: fmt.Sprintf("vault %d: %s", g.VaultID, g.Path),
+			Detail: fmt.Sprintf("%d folder rows share this path", g.Cnt),
+			Fixed:  fix && fixErr == nil,
+		}
+		if fixErr != nil {
+			issue.Detail += ", merge failed: " + fixErr.Error()
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// bleveIndexProblem describes why the index of a vault cannot be compared with the database,
+// empty when it can be opened as is
+// bleveIndexProblem 描述仓库索引无法与数据库比对的原因，可直接打开时返回空
+func (d *Dao) bleveIndexProblem(uid, vaultID int64) string {
+	path := d.BleveMgr.GetIndexPath(uid, vaultID)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "index missing"
+	}
+	metaData, err := os.ReadFile(filepath.Join(path, "meta.json"))
+	if err != nil {
+		return "index metadata unreadable"
+	}
+	var meta BleveMeta
+	if err := json.Unmarshal(metaData, &meta); err != nil {
+		return "index metadata unreadable"
+	}
+	if meta.Version < bleveIndexVersion {
+		return "index built with an older mapping version"
+	}
+	if meta.FtsBleveStoreRaw != d.BleveMgr.storeRaw {
+		return "index built with a different fts-bleve-store-raw setting"
+	}
+	return ""
+}
+
+// CheckFTSDrift compares the full-text index of every vault with its notes, rebuilding drifted
+// indexes in fix mode
+// CheckFTSDrift 比对每个仓库的全文索引与其笔记，修复模式下重建存在偏差的索引
+func (d *Dao) CheckFTSDrift(ctx context.Context, uid int64, fix bool) ([]*DoctorIssue, error) {
+	if d.BleveMgr == nil || !d.BleveMgr.IsEnabled() {
+		return nil, nil
+	}
+	vaultKey := "user_vault_" + strconv.FormatInt(uid, 10)
+	if !d.userDBExists(vaultKey) {
+		return nil, nil
+	}
+	vaultDB := d.ResolveDB(vaultKey)
+	if vaultDB == nil || !vaultDB.Migrator().HasTable(&model.Vault{}) {
+		return nil, nil
+	}
+	var vaults []model.Vault
+	if err := vaultDB.WithContext(ctx).Where("is_deleted = 0").Order("id").Find(&vaults).Error; err != nil {
+		return nil, err
+	}
+
+	noteKey := "user_" + strconv.FormatInt(uid, 10)
+	hasNotes := d.userDBExists(noteKey)
+	var issues []*DoctorIssue
+	for _, v := range vaults {
+		var ids []int64
+		if hasNotes {
+			noteDB := d.ResolveDB(noteKey)
+			if noteDB != nil && noteDB.Migrator().HasTable(&model.Note{}) {
+				if err := noteDB.WithContext(ctx).Model(&model.Note{}).Where("vault_id = ?", v.ID).Pluck("id", &ids).Error; err != nil {
+					return issues, err
+				}
+			}
+		}
+
+		detail := d.bleveIndexProblem(uid, v.ID)
+		if detail == "index missing" && len(ids) == 0 {
+			continue
+		}
+		if detail == "" {
+			missing, stale, err := d.bleveIndexDiff(uid, v.ID, ids)
+			if err != nil {
+				detail = "index unreadable: " + err.Error()
+			} else if missing > 0 || stale > 0 {
+				detail = fmt.Sprintf("%d notes not indexed, %d stale index entries", missing, stale)
+			}
+		}
+		if detail == "" {
+			continue
+		}
+
+		issue := &DoctorIssue{UID: uid, Kind: "fts-drift", Target: fmt.Sprintf("vault %d (%s)", v.ID, v.Vault), Detail: detail}
+		if fix {
+			if err := rebuildVaultIndex(ctx, d, uid, v.ID, nil); err != nil {
+				issue.Detail += ", rebuild failed: " + err.Error()
+			} else {
+				issue.Fixed = true
+			}
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// bleveIndexDiff counts notes missing from a vault index and index entries without a note
+// bleveIndexDiff 统计仓库索引中缺失的笔记数与没有对应笔记的索引条目数
+func (d *Dao) bleveIndexDiff(uid, vaultID int64, noteIDs []int64) (missing, stale int, err error) {
+	index, err := d.BleveMgr.GetIndex(uid, vaultID)
+	if err != nil {
+		return 0, 0, err
+	}
+	count, err := index.DocCount()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	indexed := make(map[string]bool, count)
+	if count > 0 {
+		req := bleve.NewSearchRequest(bleve.NewMatchAllQuery())
+		req.Size = int(count)
+		res, err := index.Search(req)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, hit := range res.Hits {
+			indexed[hit.ID] = true
+		}
+	}
+
+	for _, id := range noteIDs {
+		docID := strconv.FormatInt(id, 10)
+		if indexed[docID] {
+			delete(indexed, docID)
+		} else {
+			missing++
+		}
+	}
+	return missing, len(indexed), nil
+}
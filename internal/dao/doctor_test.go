@@ -0,0 +1,69 @@
+package dao
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestDoctor_CheckOrphanContent verifies note folders without a row are reported and only removed in fix mode,
+// while kinds whose database does not exist are skipped.
+// TestDoctor_CheckOrphanContent 验证没有记录的笔记目录会被报告且仅在修复模式下删除，数据库不存在的类型被跳过。
+func TestDoctor_CheckOrphanContent(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(1)
+
+	noteRepo := NewNoteRepository(daoInst).(*noteRepository)
+	_, err := noteRepo.CountByFIDs(ctx, []int64{1}, vaultID, uid)
+	require.NoError(t, err)
+	note := &model.Note{VaultID: vaultID, Path: "a.md", PathHash: "a", Action: "create"}
+	require.NoError(t, daoInst.ResolveDB(noteRepo.GetKey(uid)).Create(note).Error)
+
+	kept := daoInst.GetNoteFolderPath(uid, note.ID)
+	orphan := daoInst.GetNoteFolderPath(uid, note.ID+100)
+	require.NoError(t, os.MkdirAll(kept, 0755))
+	require.NoError(t, os.MkdirAll(orphan, 0755))
+	// No file table exists, so the attachment folder must not be reported
+	// 附件表不存在，附件目录不应被报告
+	require.NoError(t, os.MkdirAll(daoInst.GetFileFolderPath(uid, 7), 0755))
+
+	issues, err := daoInst.CheckOrphanContent(ctx, uid, false)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, "orphan-note", issues[0].Kind)
+	assert.Equal(t, orphan, issues[0].Target)
+	assert.False(t, issues[0].Fixed)
+	assert.DirExists(t, orphan)
+
+	issues, err = daoInst.CheckOrphanContent(ctx, uid, true)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.True(t, issues[0].Fixed)
+	assert.NoDirExists(t, orphan)
+	assert.DirExists(t, kept)
+}
+
+// TestDoctor_SQLiteIntegrityCheck verifies every existing database file is listed and passes the integrity check.
+// TestDoctor_SQLiteIntegrityCheck 验证所有已存在的数据库文件均被列出并通过完整性检查。
+func TestDoctor_SQLiteIntegrityCheck(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	require.NotNil(t, daoInst.ResolveDB("user_folder_1"))
+
+	files := daoInst.SQLiteFiles()
+	require.Len(t, files, 2, "main database and the folder database of uid 1")
+	for _, f := range files {
+		problems, err := SQLiteIntegrityCheck(f, zap.NewNop())
+		require.NoError(t, err, f)
+		assert.Empty(t, problems, f)
+	}
+}
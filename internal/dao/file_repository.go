@@ -4,6 +4,9 @@ package dao
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -16,6 +19,7 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/internal/query"
 	"github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"go.uber.org/zap"
 	"gorm.io/gen/field"
 	"gorm.io/gorm"
@@ -151,7 +155,16 @@ func (r *fileRepository) fillFilePath(uid int64, f *domain.File) {
 		if _, errOld := os.Stat(oldSavePath); errOld == nil {
 			// 只有在确定要移动文件时才创建目录
 			_ = os.MkdirAll(folderPath, 0755)
-			_ = os.Rename(oldSavePath, standardPath)
+			if os.Rename(oldSavePath, standardPath) == nil {
+				r.dao.usage.add(standardPath, usageFileSize(standardPath))
+				if err := r.dao.pushContent(standardPath); err != nil {
+					r.dao.Logger().Warn("failed to store migrated file blob",
+						zap.Int64("uid", uid),
+						zap.String("savePath", standardPath),
+						zap.Error(err),
+					)
+				}
+			}
 		} else {
 			// 新旧路径均不存在（孤立记录），状态尚未确定，不写入缓存，
 			// 保留后续重试自愈的可能（例如旧文件被人工恢复）
@@ -267,7 +280,7 @@ func (r *fileRepository) Create(ctx context.Context, file *domain.File, uid int6
 			_ = os.MkdirAll(folderPath, 0755)
 			finalPath := filepath.Join(folderPath, "file.dat")
 
-			if err := os.Rename(tempSavePath, finalPath); err != nil {
+			if err := r.dao.MoveContentFile(tempSavePath, finalPath); err != nil {
 				r.dao.Logger().Error("failed to move uploaded file into place after Create, deleting orphaned row",
 					zap.Int64("uid", uid),
 					zap.Int64("fileId", m.ID),
@@ -319,7 +332,7 @@ func (r *fileRepository) Update(ctx context.Context, file *domain.File, uid int6
 			folderPath := r.dao.GetFileFolderPath(uid, m.ID)
 			_ = os.MkdirAll(folderPath, 0755)
 			finalPath := filepath.Join(folderPath, "file.dat")
-			if err := os.Rename(tempSavePath, finalPath); err != nil {
+			if err := r.dao.MoveContentFile(tempSavePath, finalPath); err != nil {
 				r.dao.Logger().Error("failed to move uploaded file into place during Update, aborting before DB write",
 					zap.Int64("uid", uid),
 					zap.Int64("fileId", m.ID),
@@ -407,15 +420,32 @@ func (r *fileRepository) Delete(ctx context.Context, id, uid int64) error {
 // DeletePhysicalByTime physically deletes files marked as deleted by time
 // DeletePhysicalByTime 根据时间物理删除已标记删除的文件
 func (r *fileRepository) DeletePhysicalByTime(ctx context.Context, timestamp, uid int64) error {
+	return r.DeletePhysicalByTimeInVaults(ctx, timestamp, nil, true, uid)
+}
+
+// DeletePhysicalByTimeInVaults physically deletes files marked as deleted by time, only in vaultIDs or, with exclude, outside them
+// DeletePhysicalByTimeInVaults 根据时间物理删除已标记删除的文件，仅限 vaultIDs 中的保险库，exclude 时则排除这些保险库
+func (r *fileRepository) DeletePhysicalByTimeInVaults(ctx context.Context, timestamp int64, vaultIDs []int64, exclude bool, uid int64) error {
+	if !exclude && len(vaultIDs) == 0 {
+		return nil
+	}
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := r.file(uid).File
+		q := u.WithContext(ctx).Where(
+			u.Action.Eq("delete"),
+			u.UpdatedTimestamp.Lt(timestamp),
+		)
+		if len(vaultIDs) > 0 {
+			if exclude {
+				q = q.Where(u.VaultID.NotIn(vaultIDs...))
+			} else {
+				q = q.Where(u.VaultID.In(vaultIDs...))
+			}
+		}
 
 		// Find records to be deleted to remove folders in the file system
 		// 查找待删除的记录，以便删除文件系统中的文件夹
-		mList, err := u.WithContext(ctx).Where(
-			u.Action.Eq("delete"),
-			u.UpdatedTimestamp.Lt(timestamp),
-		).Find()
+		mList, err := q.Find()
 
 		if err == nil {
 			for _, m := range mList {
@@ -424,10 +454,7 @@ func (r *fileRepository) DeletePhysicalByTime(ctx context.Context, timestamp, ui
 			}
 		}
 
-		_, err = u.WithContext(ctx).Where(
-			u.Action.Eq("delete"),
-			u.UpdatedTimestamp.Lt(timestamp),
-		).Delete()
+		_, err = q.Delete()
 		return err
 	})
 }
@@ -811,6 +838,120 @@ func (r *fileRepository) ListByPathPrefix(ctx context.Context, pathPrefix string
 	return res, nil
 }
 
+// MoveByPathPrefix moves every live file under oldPrefix to the same relative path under newPrefix.
+// Each file is tombstoned with the rename flag and recreated (or a deleted record at the new path reused)
+// inside one transaction, and its file.dat is moved to the new record; the moves are undone on rollback.
+// MoveByPathPrefix 将 oldPrefix 下的所有有效文件移动到 newPrefix 下的相同相对路径。
+// 每个文件在同一事务内标记为重命名删除并重新创建（或复用新路径上已删除的记录），file.dat 随之移动到新记录下；事务回滚时撤销移动。
+func (r *fileRepository) MoveByPathPrefix(ctx context.Context, oldPrefix, newPrefix string, fids map[string]int64, vaultID, uid int64) ([]*domain.FileMove, error) {
+	oldPrefix = strings.Trim(oldPrefix, "/")
+	newPrefix = strings.Trim(newPrefix, "/")
+
+	var moves []*domain.FileMove
+	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		moves = nil
+		var renamed [][2]string
+		err := r.file(uid).Transaction(func(tx *query.Query) error {
+			u := tx.File
+			ms, err := u.WithContext(ctx).Where(
+				u.VaultID.Eq(vaultID),
+				u.Path.Like(oldPrefix+"/%"),
+				u.Action.Neq(string(domain.FileActionDelete)),
+			).Find()
+			if err != nil {
+				return err
+			}
+
+			now := timex.Now()
+			for _, m := range ms {
+				if !isPathWithinPrefix(m.Path, oldPrefix) {
+					continue
+				}
+				old := r.toDomain(m, uid)
+				newPath := newPrefix + strings.TrimPrefix(m.Path, oldPrefix)
+				newPathHash := util.EncodeHash32(newPath)
+				target, err := u.WithContext(ctx).Where(u.VaultID.Eq(vaultID), u.PathHash.Eq(newPathHash)).First()
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+				if target != nil && target.Action != string(domain.FileActionDelete) {
+					return fmt.Errorf("file already exists at %s", newPath)
+				}
+
+				// Tombstone the old record with the rename flag // 以重命名标志标记旧记录删除
+				m.Action = string(domain.FileActionDelete)
+				m.Rename = 1
+				m.UpdatedTimestamp = now.UnixMilli()
+				m.UpdatedAt = now
+				if _, err := u.WithContext(ctx).Where(u.ID.Eq(m.ID)).Select(u.Action, u.Rename, u.UpdatedTimestamp, u.UpdatedAt).Updates(m); err != nil {
+					return err
+				}
+
+				nm := &model.File{
+					VaultID:          vaultID,
+					Action:           string(domain.FileActionCreate),
+					FID:              fids[parentDir(newPath)],
+					Path:             newPath,
+					PathHash:         newPathHash,
+					ContentHash:      m.ContentHash,
+					Size:             m.Size,
+					Ctime:            m.Ctime,
+					Mtime:            m.Mtime, // Preserve original mtime // 保留原始修改时间
+					UpdatedTimestamp: now.UnixMilli(),
+					UpdatedAt:        now,
+				}
+				if target != nil {
+					// Reuse deleted record // 复用已删除的记录
+					nm.ID = target.ID
+					nm.CreatedAt = target.CreatedAt
+					err = u.WithContext(ctx).Where(u.ID.Eq(nm.ID)).Save(nm)
+				} else {
+					nm.CreatedAt = now
+					err = u.WithContext(ctx).Create(nm)
+				}
+				if err != nil {
+					return err
+				}
+
+				finalPath := filepath.Join(r.dao.GetFileFolderPath(uid, nm.ID), "file.dat")
+				if err := r.dao.RenameContentFile(old.SavePath, finalPath); err == nil {
+					renamed = append(renamed, [2]string{old.SavePath, finalPath})
+				} else if !errors.Is(err, fs.ErrNotExist) {
+					return err
+				} else {
+					r.dao.Logger().Warn("file blob missing while moving, record moved without content",
+						zap.Int64("uid", uid),
+						zap.Int64("fileId", m.ID),
+						zap.String("savePath", old.SavePath),
+					)
+				}
+
+				old.Action, old.Rename, old.UpdatedTimestamp = domain.FileAction(m.Action), m.Rename, m.UpdatedTimestamp
+				moves = append(moves, &domain.FileMove{Old: old, New: r.toDomain(nm, uid)})
+			}
+			return nil
+		})
+		if err != nil {
+			// Put the moved blobs back, the records pointing at them were rolled back // 记录已回滚，将已移动的文件放回原处
+			for i := len(renamed) - 1; i >= 0; i-- {
+				if rbErr := r.dao.RenameContentFile(renamed[i][1], renamed[i][0]); rbErr != nil {
+					r.dao.Logger().Error("failed to restore file blob after move rollback",
+						zap.Int64("uid", uid),
+						zap.String("from", renamed[i][1]),
+						zap.String("to", renamed[i][0]),
+						zap.Error(rbErr),
+					)
+				}
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moves, nil
+}
+
 // buildFileOrderClause builds file order clause
 // buildFileOrderClause 构建文件排序语句
 func buildFileOrderClause(sortBy, sortOrder string) string {
@@ -880,3 +1021,103 @@ func (r *fileRepository) DeleteByVaultID(ctx context.Context, vaultID, uid int64
 		return nil
 	})
 }
+
+// ListOrphanContent lists attachment folders without a file row, soft deleted rows still count as
+// references since they can be restored until the retention purge removes them. Users without a
+// file database are skipped, so that a misconfigured database never makes every attachment look orphaned.
+// ListOrphanContent 列出没有文件记录的附件目录，已软删除的记录在保留期清理前仍可恢复，因此仍视为引用。
+// 没有文件数据库的用户会被跳过，避免数据库配置错误时所有附件都被误判为孤立。
+func (r *fileRepository) ListOrphanContent(ctx context.Context, uid int64) ([]*domain.FileOrphan, error) {
+	root := filepath.Dir(r.dao.GetFileFolderPath(uid, 0))
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !r.dao.userDBExists(r.GetKey(uid)) {
+		return nil, nil
+	}
+
+	var ids []int64
+	for _, e := range entries {
+		id, err := strconv.ParseInt(strings.TrimPrefix(e.Name(), "f_"), 10, 64)
+		if e.IsDir() && strings.HasPrefix(e.Name(), "f_") && err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	// Look the IDs up in chunks to stay below the SQL variable limit
+	// 分批查询，避免超出 SQL 变量数量限制
+	known := make(map[int64]bool, len(ids))
+	u := r.file(uid).File
+	for start := 0; start < len(ids); start += 500 {
+		end := min(start+500, len(ids))
+		var found []int64
+		if err := u.WithContext(ctx).Where(u.ID.In(ids[start:end]...)).Pluck(u.ID, &found); err != nil {
+			return nil, err
+		}
+		for _, id := range found {
+			known[id] = true
+		}
+	}
+
+	var orphans []*domain.FileOrphan
+	for _, id := range ids {
+		if known[id] {
+			continue
+		}
+		orphan := &domain.FileOrphan{FileID: id, Path: r.dao.GetFileFolderPath(uid, id)}
+		_ = filepath.WalkDir(orphan.Path, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			if !d.IsDir() {
+				orphan.Size += info.Size()
+			}
+			if info.ModTime().After(orphan.ModTime) {
+				orphan.ModTime = info.ModTime()
+			}
+			return nil
+		})
+		orphans = append(orphans, orphan)
+	}
+	return orphans, nil
+}
+
+// RemoveOrphanContent removes an attachment folder after checking again, inside the write queue, that no
+// file row uses its ID, so that a file created meanwhile keeps its content
+// RemoveOrphanContent 在写队列内再次确认没有文件记录使用该 ID 后删除附件目录，避免误删期间新建文件的内容
+func (r *fileRepository) RemoveOrphanContent(ctx context.Context, fileID, uid int64) (bool, error) {
+	removed := false
+	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		u := r.file(uid).File
+		count, err := u.WithContext(ctx).Where(u.ID.Eq(fileID)).Count()
+		if err != nil || count > 0 {
+			return err
+		}
+		if err := r.dao.RemoveContentFolder(r.dao.GetFileFolderPath(uid, fileID)); err != nil {
+			return err
+		}
+		removed = true
+		return nil
+	})
+	return removed, err
+}
+
+// FetchContent downloads the attachment content into SavePath when it is not cached locally
+// FetchContent 附件内容未在本地缓存时将其下载到 SavePath
+func (r *fileRepository) FetchContent(ctx context.Context, file *domain.File, uid int64) error {
+	if file == nil || file.SavePath == "" {
+		return nil
+	}
+	if err := r.dao.FetchContentFile(file.SavePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
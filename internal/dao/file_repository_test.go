@@ -142,3 +142,54 @@ func TestFileRepository_Update_RenameFailure_AbortsBeforeDBWrite(t *testing.T) {
 	require.NotNil(t, got)
 	assert.Equal(t, "content-hash-b-v1", got.ContentHash, "DB row must not have been overwritten when rename failed")
 }
+
+// TestFileRepository_OrphanContent verifies only attachment folders without any file row, soft deleted
+// ones included, are listed, that removal rechecks the row, and that users without a file database are skipped.
+// TestFileRepository_OrphanContent 验证只列出没有任何文件记录（含已软删除记录）的附件目录，删除前会再次
+// 确认记录，且没有文件数据库的用户会被跳过。
+func TestFileRepository_OrphanContent(t *testing.T) {
+	fileRepo, cleanup := setupFileRepoTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid = int64(1)
+	repo := fileRepo.(*fileRepository)
+
+	var ids []int64
+	for _, path := range []string{"kept.png", "deleted.png"} {
+		f, err := fileRepo.Create(ctx, &domain.File{VaultID: 1, Path: path, PathHash: path, Action: domain.FileActionCreate}, uid)
+		require.NoError(t, err)
+		ids = append(ids, f.ID)
+	}
+	require.NoError(t, fileRepo.UpdateActionMtime(ctx, domain.FileActionDelete, 2000, ids[1], uid))
+
+	orphanID := ids[1] + 100
+	for _, id := range append(ids, orphanID) {
+		require.NoError(t, repo.dao.SaveContentToFile(repo.dao.GetFileFolderPath(uid, id), "file.dat", "12345"))
+	}
+	// No file database exists for uid 2, so its folder must not be reported
+	// uid 2 没有文件数据库，其目录不应被报告
+	require.NoError(t, os.MkdirAll(repo.dao.GetFileFolderPath(2, 1), 0755))
+
+	orphans, err := fileRepo.ListOrphanContent(ctx, uid)
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, orphanID, orphans[0].FileID)
+	assert.Equal(t, repo.dao.GetFileFolderPath(uid, orphanID), orphans[0].Path)
+	assert.Positive(t, orphans[0].Size)
+	assert.False(t, orphans[0].ModTime.IsZero())
+
+	orphans, err = fileRepo.ListOrphanContent(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, orphans)
+
+	removed, err := fileRepo.RemoveOrphanContent(ctx, ids[0], uid)
+	require.NoError(t, err)
+	assert.False(t, removed, "a folder with a file row must never be removed")
+	assert.DirExists(t, repo.dao.GetFileFolderPath(uid, ids[0]))
+
+	removed, err = fileRepo.RemoveOrphanContent(ctx, orphanID, uid)
+	require.NoError(t, err)
+	assert.True(t, removed)
+	assert.NoDirExists(t, repo.dao.GetFileFolderPath(uid, orphanID))
+}
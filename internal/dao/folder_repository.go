@@ -2,14 +2,22 @@ package dao
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/model"
 	"github.com/haierkeys/fast-note-sync-service/internal/query"
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type folderRepository struct {
@@ -38,9 +46,71 @@ func init() {
 func (r *folderRepository) folder(uid int64) *query.Query {
 	return r.Dao.QueryWithOnceInit(func(g *gorm.DB) {
 		model.AutoMigrate(g, "Folder")
+		if err := ensureFolderPathUnique(g); err != nil {
+			// Inserts fall back to a lookup and a plain insert until the index exists
+			// 索引建立前，插入回退为先查询再普通插入
+			folderPathIndexMissing.Store(r.GetKey(uid), true)
+			r.Dao.Logger().Warn("failed to add unique folder path index, folder inserts are not deduplicated by the database", zap.Int64("uid", uid), zap.Error(err))
+		} else {
+			folderPathIndexMissing.Delete(r.GetKey(uid))
+		}
 	}, r.GetKey(uid)+"#folder", r.GetKey(uid))
 }
 
+// folderPathUniqueIndex name of the UNIQUE(vault_id, path_hash) index on the folder table
+// folderPathUniqueIndex folder 表上 UNIQUE(vault_id, path_hash) 索引的名称
+const folderPathUniqueIndex = "uk_folder_vault_id_path_hash"
+
+// folderPathIndexMissing database keys whose folder table could not get the unique path index
+// folderPathIndexMissing 文件夹表未能添加唯一路径索引的数据库 key
+var folderPathIndexMissing sync.Map
+
+// ensureFolderPathUnique merges duplicate folder rows of a path and adds the UNIQUE(vault_id, path_hash) index.
+// Of each duplicate group a live row survives over deleted ones, the most recently updated first, and the subfolders of the dropped rows are
+// re-parented onto it. Notes and files live in other databases; their FIDs are repaired by the SyncFID task.
+// ensureFolderPathUnique 合并同一路径的重复文件夹记录，并添加 UNIQUE(vault_id, path_hash) 索引。
+// 每组重复记录优先保留未删除的记录，其次保留最近更新的一条，被移除记录的子文件夹改为挂到保留的记录下。笔记与文件位于其他数据库，其 FID 由 SyncFID 任务修复。
+func ensureFolderPathUnique(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&model.Folder{}) || migrator.HasIndex(&model.Folder{}, folderPathUniqueIndex) {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		var groups []struct {
+			VaultID  int64
+			PathHash string
+		}
+		if err := tx.Model(&model.Folder{}).Select("vault_id, path_hash").Group("vault_id, path_hash").Having("COUNT(*) > 1").Scan(&groups).Error; err != nil {
+			return err
+		}
+		for _, g := range groups {
+			var rows []*model.Folder
+			if err := tx.Where("vault_id = ? AND path_hash = ?", g.VaultID, g.PathHash).Order("CASE WHEN action = 'delete' THEN 1 ELSE 0 END, updated_timestamp DESC, id DESC").Find(&rows).Error; err != nil {
+				return err
+			}
+			dropped := make([]int64, 0, len(rows)-1)
+			for _, row := range rows[1:] {
+				dropped = append(dropped, row.ID)
+			}
+			if err := tx.Model(&model.Folder{}).Where("fid IN ?", dropped).Update("fid", rows[0].ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", dropped).Delete(&model.Folder{}).Error; err != nil {
+				return err
+			}
+		}
+
+		columns := "vault_id, path_hash"
+		if tx.Dialector.Name() == "mysql" {
+			// Path hashes are short, a key prefix keeps the index within MySQL's key length limit
+			// 路径哈希很短，使用前缀索引以满足 MySQL 的索引长度限制
+			columns = "vault_id, path_hash(191)"
+		}
+		return tx.Exec("CREATE UNIQUE INDEX " + folderPathUniqueIndex + " ON " + model.TableNameFolder + " (" + columns + ")").Error
+	})
+}
+
 func (r *folderRepository) GetByID(ctx context.Context, id, uid int64) (*domain.Folder, error) {
 	f := r.folder(uid).Folder
 	m, err := f.WithContext(ctx).Where(f.ID.Eq(id)).First()
@@ -86,15 +156,12 @@ func (r *folderRepository) GetByFID(ctx context.Context, fid int64, vaultID, uid
 	return res, nil
 }
 
+// Create creates a folder; when a row for the same path already exists, that row is returned instead
+// Create 创建文件夹；若同一路径的记录已存在，则返回该记录
 func (r *folderRepository) Create(ctx context.Context, folder *domain.Folder, uid int64) (*domain.Folder, error) {
 	var result *domain.Folder
 	err := r.Dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
-		m := r.domainToModel(folder)
-		m.CreatedAt = timex.Now()
-		m.UpdatedAt = timex.Now()
-		m.UpdatedTimestamp = timex.Now().UnixMilli()
-		f := r.folder(uid).Folder
-		err := f.WithContext(ctx).Create(m)
+		m, err := r.createOrGet(ctx, r.folder(uid), r.domainToModel(folder), uid)
 		if err != nil {
 			return err
 		}
@@ -107,6 +174,131 @@ func (r *folderRepository) Create(ctx context.Context, folder *domain.Folder, ui
 	return result, nil
 }
 
+// createOrGet inserts a folder row, ON CONFLICT(vault_id, path_hash) DO NOTHING, and returns the row stored for the path.
+// Without the unique index the conflict clause cannot work, the existing row is looked up before a plain insert instead.
+// createOrGet 插入文件夹记录（ON CONFLICT(vault_id, path_hash) DO NOTHING），并返回该路径实际存储的记录。
+// 缺少唯一索引时冲突子句无法生效，改为先查询已有记录再普通插入。
+func (r *folderRepository) createOrGet(ctx context.Context, q *query.Query, m *model.Folder, uid int64) (*model.Folder, error) {
+	f := q.Folder
+	m.CreatedAt = timex.Now()
+	m.UpdatedAt = timex.Now()
+	m.UpdatedTimestamp = timex.Now().UnixMilli()
+	if _, missing := folderPathIndexMissing.Load(r.GetKey(uid)); missing {
+		existing, err := f.WithContext(ctx).Where(f.VaultID.Eq(m.VaultID), f.PathHash.Eq(m.PathHash)).Order(f.ID).First()
+		if err == nil {
+			return existing, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if err := f.WithContext(ctx).Create(m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	err := f.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "vault_id"}, {Name: "path_hash"}},
+		DoNothing: true,
+	}).Create(m)
+	if err != nil {
+		return nil, err
+	}
+	if m.ID != 0 {
+		return m, nil
+	}
+	// Another writer created the path first // 其他写入方已先创建该路径
+	return f.WithContext(ctx).Where(f.VaultID.Eq(m.VaultID), f.PathHash.Eq(m.PathHash)).First()
+}
+
+// EnsurePath makes sure every level of path has a live folder, creating missing levels and restoring deleted
+// ones parent first in one transaction. The common case where every level exists is answered without a write.
+// EnsurePath 确保路径上每一级都存在有效文件夹，在单个事务内按先父后子的顺序创建缺失的层级并恢复已删除的层级。所有层级均已存在的常见情况无需写入即可返回。
+func (r *folderRepository) EnsurePath(ctx context.Context, path string, vaultID, uid int64) ([]*domain.FolderEnsureResult, error) {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil, nil
+	}
+	parts := strings.Split(path, "/")
+
+	lookup := func(q *query.Query, levelPath string) (*model.Folder, error) {
+		f := q.Folder
+		m, err := f.WithContext(ctx).Where(f.VaultID.Eq(vaultID), f.PathHash.Eq(util.EncodeHash32(levelPath))).Order(f.ID).First()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return m, err
+	}
+
+	results := make([]*domain.FolderEnsureResult, 0, len(parts))
+	q := r.folder(uid)
+	for i := range parts {
+		m, err := lookup(q, strings.Join(parts[:i+1], "/"))
+		if err != nil {
+			return nil, err
+		}
+		if m == nil || m.Action == string(domain.FolderActionDelete) {
+			results = nil
+			break
+		}
+		results = append(results, &domain.FolderEnsureResult{Folder: r.modelToDomain(m)})
+	}
+	if results != nil {
+		return results, nil
+	}
+
+	err := r.Dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		results = results[:0]
+		return q.Transaction(func(tx *query.Query) error {
+			var fid int64
+			for i := range parts {
+				levelPath := strings.Join(parts[:i+1], "/")
+				m, err := lookup(tx, levelPath)
+				if err != nil {
+					return err
+				}
+				res := &domain.FolderEnsureResult{}
+				now := timex.Now()
+				switch {
+				case m == nil:
+					m, err = r.createOrGet(ctx, tx, &model.Folder{
+						VaultID:  vaultID,
+						Action:   string(domain.FolderActionCreate),
+						Path:     levelPath,
+						PathHash: util.EncodeHash32(levelPath),
+						Level:    int64(i + 1),
+						FID:      fid,
+						Ctime:    now.UnixMilli(),
+						Mtime:    now.UnixMilli(),
+					}, uid)
+					if err != nil {
+						return err
+					}
+					res.Created = true
+				case m.Action == string(domain.FolderActionDelete):
+					m.Action = string(domain.FolderActionCreate)
+					m.Ctime = now.UnixMilli()
+					m.Mtime = now.UnixMilli()
+					m.UpdatedTimestamp = now.UnixMilli()
+					m.UpdatedAt = now
+					f := tx.Folder
+					if _, err := f.WithContext(ctx).Where(f.ID.Eq(m.ID)).Select(f.Action, f.Ctime, f.Mtime, f.UpdatedTimestamp, f.UpdatedAt).Updates(m); err != nil {
+						return err
+					}
+					res.Restored = true
+				}
+				res.Folder = r.modelToDomain(m)
+				results = append(results, res)
+				fid = m.ID
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 func (r *folderRepository) Update(ctx context.Context, folder *domain.Folder, uid int64) (*domain.Folder, error) {
 	var result *domain.Folder
 	err := r.Dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
@@ -169,6 +361,105 @@ func (r *folderRepository) ListByPathPrefix(ctx context.Context, pathPrefix stri
 	return res, nil
 }
 
+// MoveByPathPrefix moves a folder and all its live subfolders to newPath in one transaction.
+// Old records are tombstoned, new ones are created (or deleted records at the new paths reused) parents first,
+// so every moved folder points at its moved parent; fid is the ID of the parent of newPath.
+// MoveByPathPrefix 在单个事务内将文件夹及其所有有效子文件夹移动到 newPath。
+// 旧记录标记删除，新记录按先父后子的顺序创建（或复用新路径上已删除的记录），使每个文件夹指向移动后的父文件夹；fid 为 newPath 父文件夹的 ID。
+func (r *folderRepository) MoveByPathPrefix(ctx context.Context, oldPath, newPath string, fid int64, vaultID, uid int64) ([]*domain.FolderMove, error) {
+	oldPath = strings.Trim(oldPath, "/")
+	newPath = strings.Trim(newPath, "/")
+
+	var moves []*domain.FolderMove
+	err := r.Dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		moves = nil
+		return r.folder(uid).Transaction(func(tx *query.Query) error {
+			f := tx.Folder
+			ms, err := f.WithContext(ctx).Where(
+				f.VaultID.Eq(vaultID),
+				f.Path.Like(oldPath+"%"),
+				f.Action.Neq(string(domain.FolderActionDelete)),
+			).Find()
+			if err != nil {
+				return err
+			}
+
+			// Parents first, duplicate records of a path are moved together // 先父后子，同一路径的重复记录一起移动
+			byPath := make(map[string][]*model.Folder)
+			var paths []string
+			for _, m := range ms {
+				if m.Path != oldPath && !isPathWithinPrefix(m.Path, oldPath) {
+					continue
+				}
+				if _, ok := byPath[m.Path]; !ok {
+					paths = append(paths, m.Path)
+				}
+				byPath[m.Path] = append(byPath[m.Path], m)
+			}
+			sort.SliceStable(paths, func(i, j int) bool {
+				return strings.Count(paths[i], "/") < strings.Count(paths[j], "/")
+			})
+
+			now := timex.Now()
+			ids := map[string]int64{parentDir(newPath): fid}
+			for _, p := range paths {
+				olds := byPath[p]
+				for _, m := range olds {
+					m.Action = string(domain.FolderActionDelete)
+					m.UpdatedTimestamp = now.UnixMilli()
+					m.UpdatedAt = now
+					if _, err := f.WithContext(ctx).Where(f.ID.Eq(m.ID)).Select(f.Action, f.UpdatedTimestamp, f.UpdatedAt).Updates(m); err != nil {
+						return err
+					}
+				}
+
+				target := newPath + strings.TrimPrefix(p, oldPath)
+				targetHash := util.EncodeHash32(target)
+				existing, err := f.WithContext(ctx).Where(f.VaultID.Eq(vaultID), f.PathHash.Eq(targetHash)).First()
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+				if existing != nil && existing.Action != string(domain.FolderActionDelete) {
+					return fmt.Errorf("folder already exists at %s", target)
+				}
+
+				nm := &model.Folder{
+					VaultID:          vaultID,
+					Action:           string(domain.FolderActionCreate),
+					Path:             target,
+					PathHash:         targetHash,
+					Level:            int64(strings.Count(target, "/") + 1),
+					FID:              ids[parentDir(target)],
+					Archived:         olds[0].Archived,
+					Ctime:            olds[0].Ctime,
+					Mtime:            olds[0].Mtime,
+					UpdatedTimestamp: now.UnixMilli(),
+					UpdatedAt:        now,
+				}
+				if existing != nil {
+					// Reuse deleted record // 复用已删除的记录
+					nm.ID = existing.ID
+					nm.CreatedAt = existing.CreatedAt
+					err = f.WithContext(ctx).Where(f.ID.Eq(nm.ID)).Save(nm)
+				} else {
+					nm.CreatedAt = now
+					err = f.WithContext(ctx).Create(nm)
+				}
+				if err != nil {
+					return err
+				}
+				ids[target] = nm.ID
+				moves = append(moves, &domain.FolderMove{Old: r.modelToDomain(olds[0]), New: r.modelToDomain(nm)})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return moves, nil
+}
+
 func (r *folderRepository) modelToDomain(m *model.Folder) *domain.Folder {
 	if m == nil {
 		return nil
@@ -181,6 +472,7 @@ func (r *folderRepository) modelToDomain(m *model.Folder) *domain.Folder {
 		PathHash:         m.PathHash,
 		Level:            m.Level,
 		FID:              m.FID,
+		Archived:         m.Archived == 1,
 		Ctime:            m.Ctime,
 		Mtime:            m.Mtime,
 		UpdatedTimestamp: m.UpdatedTimestamp,
@@ -193,6 +485,10 @@ func (r *folderRepository) domainToModel(d *domain.Folder) *model.Folder {
 	if d == nil {
 		return nil
 	}
+	var archived int64
+	if d.Archived {
+		archived = 1
+	}
 	return &model.Folder{
 		ID:               d.ID,
 		VaultID:          d.VaultID,
@@ -201,6 +497,7 @@ func (r *folderRepository) domainToModel(d *domain.Folder) *model.Folder {
 		PathHash:         d.PathHash,
 		Level:            d.Level,
 		FID:              d.FID,
+		Archived:         archived,
 		Ctime:            d.Ctime,
 		Mtime:            d.Mtime,
 		UpdatedTimestamp: d.UpdatedTimestamp,
@@ -234,6 +531,39 @@ func (r *folderRepository) ListAll(ctx context.Context, uid int64) ([]*domain.Fo
 	return res, nil
 }
 
+// SetArchived sets or clears the archived flag on every record of a folder path
+// SetArchived 设置或清除指定路径下所有文件夹记录的归档标记
+func (r *folderRepository) SetArchived(ctx context.Context, pathHash string, vaultID int64, archived bool, uid int64) error {
+	var value int64
+	if archived {
+		value = 1
+	}
+	return r.Dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		f := r.folder(uid).Folder
+		_, err := f.WithContext(ctx).Where(f.VaultID.Eq(vaultID), f.PathHash.Eq(pathHash)).UpdateSimple(
+			f.Archived.Value(value),
+			f.UpdatedTimestamp.Value(timex.Now().UnixMilli()),
+			f.UpdatedAt.Value(timex.Now()),
+		)
+		return err
+	})
+}
+
+// ListArchived retrieves all non-deleted archived folders in a vault
+// ListArchived 获取仓库下所有未删除的归档文件夹
+func (r *folderRepository) ListArchived(ctx context.Context, vaultID, uid int64) ([]*domain.Folder, error) {
+	f := r.folder(uid).Folder
+	ms, err := f.WithContext(ctx).Where(f.VaultID.Eq(vaultID), f.Archived.Eq(1), f.Action.Neq("delete")).Find()
+	if err != nil {
+		return nil, err
+	}
+	var res []*domain.Folder
+	for _, m := range ms {
+		res = append(res, r.modelToDomain(m))
+	}
+	return res, nil
+}
+
 // DeleteByVaultID removes all folder records for a specific vault
 // DeleteByVaultID 删除指定仓库的所有文件夹记录
 func (r *folderRepository) DeleteByVaultID(ctx context.Context, vaultID, uid int64) error {
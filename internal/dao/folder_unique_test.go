@@ -0,0 +1,135 @@
+package dao
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestEnsureFolderPathUnique_MergesDuplicates verifies duplicate folder rows are merged into the most
+// recently updated one, their subfolders re-parented, and the unique index added afterwards.
+// TestEnsureFolderPathUnique_MergesDuplicates 验证重复的文件夹记录被合并到最近更新的一条，其子文件夹被重新挂载，随后添加唯一索引。
+func TestEnsureFolderPathUnique_MergesDuplicates(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "folder.sqlite3")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, model.AutoMigrate(db, "Folder"))
+
+	hash := util.EncodeHash32("Projects")
+	rows := []*model.Folder{
+		{VaultID: 1, Path: "Projects", PathHash: hash, Action: "create", UpdatedTimestamp: 100},
+		{VaultID: 1, Path: "Projects", PathHash: hash, Action: "create", UpdatedTimestamp: 300},
+		{VaultID: 1, Path: "Projects", PathHash: hash, Action: "create", UpdatedTimestamp: 200},
+		{VaultID: 2, Path: "Projects", PathHash: hash, Action: "create", UpdatedTimestamp: 100},
+	}
+	require.NoError(t, db.Create(rows).Error)
+	child := &model.Folder{VaultID: 1, Path: "Projects/Docs", PathHash: util.EncodeHash32("Projects/Docs"), Action: "create", FID: rows[0].ID}
+	require.NoError(t, db.Create(child).Error)
+	archiveHash := util.EncodeHash32("Archive")
+	archive := []*model.Folder{
+		{VaultID: 1, Path: "Archive", PathHash: archiveHash, Action: "create", UpdatedTimestamp: 100},
+		{VaultID: 1, Path: "Archive", PathHash: archiveHash, Action: "delete", UpdatedTimestamp: 300},
+	}
+	require.NoError(t, db.Create(archive).Error)
+
+	require.NoError(t, ensureFolderPathUnique(db))
+
+	var left []*model.Folder
+	require.NoError(t, db.Where("path_hash = ?", hash).Order("vault_id").Find(&left).Error)
+	require.Len(t, left, 2, "one row per vault survives")
+	assert.Equal(t, rows[1].ID, left[0].ID, "the most recently updated row is kept")
+	assert.Equal(t, rows[3].ID, left[1].ID)
+
+	require.NoError(t, db.First(child, child.ID).Error)
+	assert.Equal(t, rows[1].ID, child.FID)
+
+	var kept []*model.Folder
+	require.NoError(t, db.Where("path_hash = ?", archiveHash).Find(&kept).Error)
+	require.Len(t, kept, 1)
+	assert.Equal(t, archive[0].ID, kept[0].ID, "a live row is kept over a more recently deleted one")
+
+	assert.True(t, db.Migrator().HasIndex(&model.Folder{}, folderPathUniqueIndex))
+	assert.Error(t, db.Create(&model.Folder{VaultID: 1, Path: "Projects", PathHash: hash}).Error)
+	require.NoError(t, ensureFolderPathUnique(db), "running again is a no-op")
+}
+
+// TestFolderRepository_CreateAndEnsurePath verifies Create returns the existing row on a path conflict and
+// EnsurePath creates missing levels and restores deleted ones in order.
+// TestFolderRepository_CreateAndEnsurePath 验证 Create 在路径冲突时返回已有记录，EnsurePath 按顺序创建缺失层级并恢复已删除层级。
+func TestFolderRepository_CreateAndEnsurePath(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(1)
+	repo := NewFolderRepository(daoInst)
+
+	newFolder := func(path string, action domain.FolderAction) *domain.Folder {
+		return &domain.Folder{VaultID: vaultID, Action: action, Path: path, PathHash: util.EncodeHash32(path)}
+	}
+	first, err := repo.Create(ctx, newFolder("A", domain.FolderActionCreate), uid)
+	require.NoError(t, err)
+	again, err := repo.Create(ctx, newFolder("A", domain.FolderActionCreate), uid)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID)
+
+	deleted, err := repo.Create(ctx, newFolder("A/B", domain.FolderActionDelete), uid)
+	require.NoError(t, err)
+
+	results, err := repo.EnsurePath(ctx, "A/B/C", vaultID, uid)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, first.ID, results[0].Folder.ID)
+	assert.False(t, results[0].Created || results[0].Restored)
+	assert.Equal(t, deleted.ID, results[1].Folder.ID)
+	assert.True(t, results[1].Restored)
+	assert.True(t, results[2].Created)
+	assert.Equal(t, deleted.ID, results[2].Folder.FID)
+	assert.Equal(t, int64(3), results[2].Folder.Level)
+
+	restored, err := repo.GetByPathHash(ctx, util.EncodeHash32("A/B"), vaultID, uid)
+	require.NoError(t, err)
+	assert.False(t, restored.IsDeleted())
+
+	results, err = repo.EnsurePath(ctx, "A/B/C", vaultID, uid)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.False(t, results[2].Created, "an existing path is returned without changes")
+}
+
+// TestFolderRepository_CreateWithoutUniqueIndex verifies Create still returns the existing row of a path when
+// the unique index could not be added.
+// TestFolderRepository_CreateWithoutUniqueIndex 验证未能添加唯一索引时 Create 仍返回路径的已有记录。
+func TestFolderRepository_CreateWithoutUniqueIndex(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(1)
+	repo := NewFolderRepository(daoInst).(*folderRepository)
+
+	db := repo.folder(uid).Folder.WithContext(ctx).UnderlyingDB()
+	require.NoError(t, db.Migrator().DropIndex(&model.Folder{}, folderPathUniqueIndex))
+	folderPathIndexMissing.Store(repo.GetKey(uid), true)
+	defer folderPathIndexMissing.Delete(repo.GetKey(uid))
+
+	folder := func() *domain.Folder {
+		return &domain.Folder{VaultID: vaultID, Action: domain.FolderActionCreate, Path: "A", PathHash: util.EncodeHash32("A")}
+	}
+	first, err := repo.Create(ctx, folder(), uid)
+	require.NoError(t, err)
+	again, err := repo.Create(ctx, folder(), uid)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID)
+
+	var count int64
+	require.NoError(t, db.Model(&model.Folder{}).Where("path_hash = ?", util.EncodeHash32("A")).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
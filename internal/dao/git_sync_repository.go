@@ -155,6 +155,7 @@ func (r *gitSyncRepository) toDomain(m *model.GitSyncConfig) *domain.GitSyncConf
 			_ = json.Unmarshal([]byte(m.ConfigSyncRules), &rules)
 			return rules
 		}(),
+		PingURL:   m.PingURL,
 		CreatedAt: time.Time(m.CreatedAt),
 		UpdatedAt: time.Time(m.UpdatedAt),
 	}
@@ -196,6 +197,7 @@ func (r *gitSyncRepository) toModel(d *domain.GitSyncConfig) *model.GitSyncConfi
 			b, _ := json.Marshal(d.ConfigSyncRules)
 			return string(b)
 		}(),
+		PingURL:   d.PingURL,
 		CreatedAt: timex.Time(d.CreatedAt),
 		UpdatedAt: timex.Time(d.UpdatedAt),
 	}
@@ -0,0 +1,150 @@
+package dao
+
+import (
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/lrucache"
+)
+
+const (
+	// defaultVaultLookupCacheSize caps cached vault rows across all users
+	// defaultVaultLookupCacheSize 所有用户缓存的仓库行数量上限
+	defaultVaultLookupCacheSize = 1024
+	// defaultNoteLookupCacheSize caps cached note rows across all users
+	// defaultNoteLookupCacheSize 所有用户缓存的笔记行数量上限
+	defaultNoteLookupCacheSize = 8192
+	// lookupCacheTTL bounds how long a row is served without hitting the database
+	// lookupCacheTTL 限制缓存行在不访问数据库的情况下可被使用的时长
+	lookupCacheTTL = 5 * time.Minute
+)
+
+// vaultLookupKey identifies a cached vault row, looked up either by ID or by name
+// vaultLookupKey 标识一条缓存的仓库行，按 ID 或名称查询
+type vaultLookupKey struct {
+	uid  int64
+	id   int64
+	name string
+}
+
+// noteLookupKey identifies a cached note row by path hash; live excludes deleted notes
+// noteLookupKey 按路径哈希标识一条缓存的笔记行；live 表示排除已删除笔记
+type noteLookupKey struct {
+	uid      int64
+	vaultID  int64
+	pathHash string
+	live     bool
+}
+
+// lookupCache keeps hot vault and note rows read on every sync message out of SQLite
+// Repositories invalidate the affected rows after every write. A per-user generation
+// stops a read that raced with a write from caching the row it read before the write.
+// lookupCache 缓存每条同步消息都会读取的热点仓库与笔记行，避免反复查询 SQLite
+// 仓库层在每次写入后使受影响的行失效；按用户的代数计数防止与写入并发的读取把写入前读到的行放入缓存。
+type lookupCache struct {
+	vaults *lrucache.Cache[vaultLookupKey, *model.Vault]
+	notes  *lrucache.Cache[noteLookupKey, *model.Note]
+
+	mu   sync.Mutex
+	gens map[int64]uint64
+
+	onWrite func(uid int64) // called after every local invalidation, relays it to the other instances // 每次本地失效后调用，用于转发给其他实例
+}
+
+// newLookupCache creates the lookup cache with default sizes
+// newLookupCache 使用默认容量创建查询缓存
+func newLookupCache() *lookupCache {
+	return &lookupCache{
+		vaults: lrucache.New[vaultLookupKey, *model.Vault](defaultVaultLookupCacheSize, lookupCacheTTL),
+		notes:  lrucache.New[noteLookupKey, *model.Note](defaultNoteLookupCacheSize, lookupCacheTTL),
+		gens:   make(map[int64]uint64),
+	}
+}
+
+// generation returns the user's write generation, captured before a database read
+// generation 返回用户的写入代数，在读取数据库前获取
+func (c *lookupCache) generation(uid int64) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gens[uid]
+}
+
+func (c *lookupCache) bump(uid int64) {
+	c.mu.Lock()
+	c.gens[uid]++
+	c.mu.Unlock()
+	if c.onWrite != nil {
+		c.onWrite(uid)
+	}
+}
+
+// getVault returns a cached vault row
+// getVault 返回缓存的仓库行
+func (c *lookupCache) getVault(key vaultLookupKey) (*model.Vault, bool) {
+	return c.vaults.Get(key)
+}
+
+// addVault caches a vault row under both its ID and name, unless a write happened since gen
+// addVault 按 ID 与名称缓存仓库行，若自 gen 以来发生过写入则不缓存
+func (c *lookupCache) addVault(uid int64, gen uint64, m *model.Vault) {
+	if c.generation(uid) != gen {
+		return
+	}
+	c.vaults.Add(vaultLookupKey{uid: uid, id: m.ID}, m)
+	c.vaults.Add(vaultLookupKey{uid: uid, name: m.Vault}, m)
+}
+
+// invalidateVaults drops every cached vault row of the user
+// invalidateVaults 使该用户所有缓存的仓库行失效
+func (c *lookupCache) invalidateVaults(uid int64) {
+	c.bump(uid)
+	c.vaults.RemoveFunc(func(k vaultLookupKey, _ *model.Vault) bool { return k.uid == uid })
+}
+
+// getNote returns a cached note row
+// getNote 返回缓存的笔记行
+func (c *lookupCache) getNote(key noteLookupKey) (*model.Note, bool) {
+	return c.notes.Get(key)
+}
+
+// addNote caches a note row, unless a write happened since gen
+// addNote 缓存笔记行，若自 gen 以来发生过写入则不缓存
+func (c *lookupCache) addNote(key noteLookupKey, gen uint64, m *model.Note) {
+	if c.generation(key.uid) != gen {
+		return
+	}
+	c.notes.Add(key, m)
+}
+
+// invalidateNotePath drops the cached rows at a path, used when a note is created there
+// invalidateNotePath 使某路径上缓存的笔记行失效，用于在该路径创建笔记时
+func (c *lookupCache) invalidateNotePath(uid, vaultID int64, pathHash string) {
+	c.bump(uid)
+	c.notes.Remove(noteLookupKey{uid: uid, vaultID: vaultID, pathHash: pathHash, live: true})
+	c.notes.Remove(noteLookupKey{uid: uid, vaultID: vaultID, pathHash: pathHash, live: false})
+}
+
+// invalidateNoteID drops the cached rows of a note wherever it is cached
+// invalidateNoteID 使某笔记在所有位置缓存的行失效
+func (c *lookupCache) invalidateNoteID(uid, id int64) {
+	c.bump(uid)
+	c.notes.RemoveFunc(func(k noteLookupKey, m *model.Note) bool { return k.uid == uid && m.ID == id })
+}
+
+// invalidateNotes drops every cached note row of the user, used by bulk writes
+// invalidateNotes 使该用户所有缓存的笔记行失效，用于批量写入
+func (c *lookupCache) invalidateNotes(uid int64) {
+	c.bump(uid)
+	c.notes.RemoveFunc(func(k noteLookupKey, _ *model.Note) bool { return k.uid == uid })
+}
+
+// dropUser drops every cached row of the user after a write made by another instance
+// dropUser 在其他实例写入后使该用户所有缓存的行失效
+func (c *lookupCache) dropUser(uid int64) {
+	c.mu.Lock()
+	c.gens[uid]++
+	c.mu.Unlock()
+	c.vaults.RemoveFunc(func(k vaultLookupKey, _ *model.Vault) bool { return k.uid == uid })
+	c.notes.RemoveFunc(func(k noteLookupKey, _ *model.Note) bool { return k.uid == uid })
+}
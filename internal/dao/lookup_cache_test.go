@@ -0,0 +1,92 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestVaultRepository_LookupCache verifies vault lookups are served from the cache and that
+// renames and stat updates through the repository invalidate it.
+// TestVaultRepository_LookupCache 验证仓库查询由缓存提供，且经由仓库层的重命名与统计更新会使其失效。
+func TestVaultRepository_LookupCache(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid = int64(1)
+	repo := NewVaultRepository(daoInst)
+
+	// Trigger schema auto-migration via the repository's own query accessor.
+	// 通过仓库自身的查询访问器触发建表。
+	_, err := repo.List(ctx, uid)
+	require.NoError(t, err)
+	v, err := repo.Create(ctx, &domain.Vault{Name: "work"}, uid)
+	require.NoError(t, err)
+	_, err = repo.GetByName(ctx, "work", uid)
+	require.NoError(t, err)
+
+	// A write that bypasses the repository is not seen while the row is cached
+	// 绕过仓库层的写入在缓存期间不可见
+	db := daoInst.ResolveDB(repo.(*vaultRepository).GetKey(uid))
+	require.NoError(t, db.Model(&model.Vault{}).Where("id = ?", v.ID).Update("note_count", 7).Error)
+	cached, err := repo.GetByName(ctx, "work", uid)
+	require.NoError(t, err)
+	assert.Zero(t, cached.NoteCount)
+
+	require.NoError(t, repo.UpdateNoteCountSize(ctx, 10, 3, v.ID, uid))
+	fresh, err := repo.GetByID(ctx, v.ID, uid)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), fresh.NoteCount)
+
+	fresh.Name = "personal"
+	require.NoError(t, repo.Update(ctx, fresh, uid))
+	_, err = repo.GetByName(ctx, "work", uid)
+	assert.Error(t, err, "the old name is no longer served from the cache")
+	renamed, err := repo.GetByName(ctx, "personal", uid)
+	require.NoError(t, err)
+	assert.Equal(t, v.ID, renamed.ID)
+}
+
+// TestNoteRepository_LookupCache verifies path hash lookups are cached per live/all variant and
+// that note writes through the repository invalidate them.
+// TestNoteRepository_LookupCache 验证路径哈希查询按 live/all 分别缓存，且经由仓库层的笔记写入会使其失效。
+func TestNoteRepository_LookupCache(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+	daoInst.BleveMgr = NewBleveManager(util.Ptr(false), util.Ptr(false), zap.NewNop())
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(1)
+	repo := NewNoteRepository(daoInst)
+	pathHash := util.EncodeHash32("a.md")
+
+	note, err := repo.Create(ctx, &domain.Note{VaultID: vaultID, Action: domain.NoteActionCreate, Path: "a.md", PathHash: pathHash, Content: "v0"}, uid)
+	require.NoError(t, err)
+	_, err = repo.GetAllByPathHash(ctx, pathHash, vaultID, uid)
+	require.NoError(t, err)
+	_, err = repo.GetByPathHash(ctx, pathHash, vaultID, uid)
+	require.NoError(t, err)
+	assert.Equal(t, 2, daoInst.lookups.notes.Len())
+
+	note.Mtime = 42
+	require.NoError(t, repo.UpdateMtime(ctx, note.Mtime, note.ID, uid))
+	assert.Zero(t, daoInst.lookups.notes.Len())
+	got, err := repo.GetAllByPathHash(ctx, pathHash, vaultID, uid)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), got.Mtime)
+
+	note.Action = domain.NoteActionDelete
+	require.NoError(t, repo.UpdateDelete(ctx, note, uid))
+	_, err = repo.GetByPathHash(ctx, pathHash, vaultID, uid)
+	assert.Error(t, err, "a deleted note is not served from the live cache")
+	got, err = repo.GetAllByPathHash(ctx, pathHash, vaultID, uid)
+	require.NoError(t, err)
+	assert.True(t, got.IsDeleted())
+}
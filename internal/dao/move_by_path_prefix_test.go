@@ -0,0 +1,146 @@
+package dao
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestFolderRepository_MoveByPathPrefix verifies subfolders are recreated parents first under the new
+// path, each pointing at its moved parent, while unrelated folders sharing the prefix are left alone.
+// TestFolderRepository_MoveByPathPrefix 验证子文件夹按先父后子的顺序在新路径下重建，并指向移动后的父文件夹，
+// 共享前缀的无关文件夹不受影响。
+func TestFolderRepository_MoveByPathPrefix(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(1)
+	repo := NewFolderRepository(daoInst)
+
+	create := func(path string, fid int64) *domain.Folder {
+		f, err := repo.Create(ctx, &domain.Folder{VaultID: vaultID, Action: domain.FolderActionCreate, Path: path, PathHash: util.EncodeHash32(path), FID: fid}, uid)
+		require.NoError(t, err)
+		return f
+	}
+	a := create("A", 0)
+	ab := create("A/B", a.ID)
+	create("A/B/C", ab.ID)
+	create("AB", 0)
+	dest := create("Dest", 0)
+
+	moves, err := repo.MoveByPathPrefix(ctx, "A", "Dest/A2", dest.ID, vaultID, uid)
+	require.NoError(t, err)
+	require.Len(t, moves, 3)
+	assert.Equal(t, "A", moves[0].Old.Path)
+	assert.Equal(t, "Dest/A2", moves[0].New.Path)
+	assert.Equal(t, dest.ID, moves[0].New.FID)
+	assert.Equal(t, int64(2), moves[0].New.Level)
+	assert.Equal(t, "Dest/A2/B/C", moves[2].New.Path)
+	assert.Equal(t, moves[1].New.ID, moves[2].New.FID)
+
+	old, err := repo.GetByPathHash(ctx, util.EncodeHash32("A/B"), vaultID, uid)
+	require.NoError(t, err)
+	assert.True(t, old.IsDeleted())
+	untouched, err := repo.GetByPathHash(ctx, util.EncodeHash32("AB"), vaultID, uid)
+	require.NoError(t, err)
+	assert.False(t, untouched.IsDeleted())
+
+	_, err = repo.MoveByPathPrefix(ctx, "Dest/A2/B", "Dest", dest.ID, vaultID, uid)
+	assert.Error(t, err, "moving onto a live folder fails")
+	live, err := repo.GetByPathHash(ctx, util.EncodeHash32("Dest/A2/B"), vaultID, uid)
+	require.NoError(t, err)
+	assert.False(t, live.IsDeleted(), "the failed move is rolled back")
+}
+
+// TestFileRepository_MoveByPathPrefix verifies moved files keep their content and blob, and a
+// deleted record at the new path is reused.
+// TestFileRepository_MoveByPathPrefix 验证移动后的文件保留内容与数据文件，并复用新路径上已删除的记录。
+func TestFileRepository_MoveByPathPrefix(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(1)
+	repo := NewFileRepository(daoInst)
+
+	create := func(path string, action domain.FileAction, content string) *domain.File {
+		tmp := filepath.Join(t.TempDir(), "upload")
+		require.NoError(t, os.WriteFile(tmp, []byte(content), 0644))
+		f, err := repo.Create(ctx, &domain.File{VaultID: vaultID, Action: action, Path: path, PathHash: util.EncodeHash32(path), ContentHash: content, SavePath: tmp, Size: int64(len(content))}, uid)
+		require.NoError(t, err)
+		return f
+	}
+	img := create("A/img.png", domain.FileActionCreate, "png")
+	create("A/sub/doc.pdf", domain.FileActionCreate, "pdf")
+	stale := create("B/img.png", domain.FileActionDelete, "stale")
+
+	moves, err := repo.MoveByPathPrefix(ctx, "A", "B", map[string]int64{"B": 9}, vaultID, uid)
+	require.NoError(t, err)
+	require.Len(t, moves, 2)
+
+	byPath := make(map[string]*domain.FileMove)
+	for _, mv := range moves {
+		byPath[mv.New.Path] = mv
+	}
+	moved := byPath["B/img.png"]
+	require.NotNil(t, moved)
+	assert.Equal(t, img.ID, moved.Old.ID)
+	assert.True(t, moved.Old.IsDeleted())
+	assert.Equal(t, int64(1), moved.Old.Rename)
+	assert.Equal(t, stale.ID, moved.New.ID, "the deleted record at the new path is reused")
+	assert.Equal(t, int64(9), moved.New.FID)
+	assert.Equal(t, "png", moved.New.ContentHash)
+	data, err := os.ReadFile(moved.New.SavePath)
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+
+	require.NotNil(t, byPath["B/sub/doc.pdf"])
+	assert.Equal(t, int64(0), byPath["B/sub/doc.pdf"].New.FID, "folders missing from fids are left for SyncResourceFID")
+}
+
+// TestNoteRepository_MoveByPathPrefix verifies moved notes keep their content, and a move rolled back by a
+// conflict leaves the content of a reused deleted record untouched.
+// TestNoteRepository_MoveByPathPrefix 验证移动后的笔记保留内容，且因冲突回滚的移动不会改动被复用的已删除记录的内容。
+func TestNoteRepository_MoveByPathPrefix(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(1)
+	daoInst.BleveMgr = NewBleveManager(util.Ptr(false), util.Ptr(false), zap.NewNop())
+	repo := NewNoteRepository(daoInst)
+
+	create := func(path string, action domain.NoteAction, content string) *domain.Note {
+		n, err := repo.Create(ctx, &domain.Note{VaultID: vaultID, Action: action, Path: path, PathHash: util.EncodeHash32(path), Content: content, ContentHash: content}, uid)
+		require.NoError(t, err)
+		return n
+	}
+	create("A/x.md", domain.NoteActionCreate, "moved")
+	create("A/y.md", domain.NoteActionCreate, "other")
+	stale := create("B/x.md", domain.NoteActionDelete, "stale")
+	blocker := create("B/y.md", domain.NoteActionCreate, "blocker")
+
+	_, err := repo.MoveByPathPrefix(ctx, "A", "B", nil, vaultID, uid)
+	require.Error(t, err)
+	kept, err := repo.GetByID(ctx, stale.ID, uid)
+	require.NoError(t, err)
+	assert.Equal(t, "stale", kept.Content, "a rolled back move does not overwrite the reused record's content")
+
+	require.NoError(t, repo.Delete(ctx, blocker.ID, vaultID, uid))
+	moves, err := repo.MoveByPathPrefix(ctx, "A", "B", nil, vaultID, uid)
+	require.NoError(t, err)
+	require.Len(t, moves, 2)
+	for _, mv := range moves {
+		moved, err := repo.GetByID(ctx, mv.New.ID, uid)
+		require.NoError(t, err)
+		assert.Equal(t, mv.Old.Content, moved.Content, mv.New.Path)
+	}
+}
@@ -0,0 +1,191 @@
+package dao
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// noteAccessRepository implements domain.NoteAccessRepository
+// noteAccessRepository 实现 domain.NoteAccessRepository 接口
+type noteAccessRepository struct {
+	dao             *Dao
+	customPrefixKey string
+	migrateOnce     sync.Map // tracks per-key migration completion // 记录每个 key 是否已完成 AutoMigrate
+}
+
+// NewNoteAccessRepository creates a NoteAccessRepository instance
+// NewNoteAccessRepository 创建 NoteAccessRepository 实例
+func NewNoteAccessRepository(dao *Dao) domain.NoteAccessRepository {
+	return &noteAccessRepository{dao: dao, customPrefixKey: "user_note_access_"}
+}
+
+// GetKey returns the database routing key for the given user
+// GetKey 返回指定用户的数据库路由键
+func (r *noteAccessRepository) GetKey(uid int64) string {
+	return r.customPrefixKey + strconv.FormatInt(uid, 10)
+}
+
+func init() {
+	for _, name := range []string{"NoteAccessWatch", "NoteAccessLog"} {
+		RegisterModel(ModelConfig{
+			Name: name,
+			RepoFactory: func(d *Dao) daoDBCustomKey {
+				return NewNoteAccessRepository(d).(daoDBCustomKey)
+			},
+			IsMainDB: false,
+		})
+	}
+}
+
+// db returns the *gorm.DB of the user's access log database, with one-time AutoMigrate
+// db 返回用户访问日志库的 *gorm.DB，确保每个用户库只迁移一次
+func (r *noteAccessRepository) db(uid int64) *gorm.DB {
+	key := r.GetKey(uid)
+	if _, loaded := r.migrateOnce.LoadOrStore(key+"#noteAccess", true); !loaded {
+		if db := r.dao.ResolveDB(key); db != nil {
+			model.AutoMigrate(db, "NoteAccessWatch")
+			model.AutoMigrate(db, "NoteAccessLog")
+		}
+	}
+	return r.dao.ResolveDB(key)
+}
+
+// Watch enables access logging of a note, enabling twice is a no-op
+// Watch 开启笔记的访问日志，重复开启无副作用
+func (r *noteAccessRepository) Watch(ctx context.Context, watch *domain.NoteAccessWatch, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		m := &model.NoteAccessWatch{
+			UID:       watch.UID,
+			VaultID:   watch.VaultID,
+			NoteID:    watch.NoteID,
+			CreatedAt: timex.Time(watch.CreatedAt),
+		}
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = timex.Now()
+		}
+		return r.db(uid).WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(m).Error
+	})
+}
+
+// Unwatch disables access logging of a note, existing entries are kept
+// Unwatch 关闭笔记的访问日志，已有记录保留
+func (r *noteAccessRepository) Unwatch(ctx context.Context, noteID, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Where("note_id = ?", noteID).Delete(&model.NoteAccessWatch{}).Error
+	})
+}
+
+// ListWatches lists all watched notes of a user
+// ListWatches 列出用户所有开启访问日志的笔记
+func (r *noteAccessRepository) ListWatches(ctx context.Context, uid int64) ([]*domain.NoteAccessWatch, error) {
+	var rows []*model.NoteAccessWatch
+	if err := r.db(uid).WithContext(ctx).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	results := make([]*domain.NoteAccessWatch, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, &domain.NoteAccessWatch{
+			ID:        m.ID,
+			UID:       m.UID,
+			VaultID:   m.VaultID,
+			NoteID:    m.NoteID,
+			CreatedAt: time.Time(m.CreatedAt),
+		})
+	}
+	return results, nil
+}
+
+// CreateLog stores an access log entry
+// CreateLog 存储一条访问日志
+func (r *noteAccessRepository) CreateLog(ctx context.Context, log *domain.NoteAccessLog, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		m := &model.NoteAccessLog{
+			UID:        log.UID,
+			VaultID:    log.VaultID,
+			NoteID:     log.NoteID,
+			Path:       log.Path,
+			Channel:    string(log.Channel),
+			Actor:      log.Actor,
+			ClientType: log.ClientType,
+			IP:         log.IP,
+			Ua:         log.UA,
+			CreatedAt:  timex.Time(log.CreatedAt),
+		}
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = timex.Now()
+		}
+		return r.db(uid).WithContext(ctx).Create(m).Error
+	})
+}
+
+// ListLogs lists access log entries of a note, newest first
+// ListLogs 按时间倒序分页列出笔记的访问日志
+func (r *noteAccessRepository) ListLogs(ctx context.Context, noteID, uid int64, page, pageSize int) ([]*domain.NoteAccessLog, int64, error) {
+	query := r.db(uid).WithContext(ctx).Model(&model.NoteAccessLog{}).Where("note_id = ?", noteID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	var rows []*model.NoteAccessLog
+	if err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]*domain.NoteAccessLog, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, &domain.NoteAccessLog{
+			ID:         m.ID,
+			UID:        m.UID,
+			VaultID:    m.VaultID,
+			NoteID:     m.NoteID,
+			Path:       m.Path,
+			Channel:    domain.NoteAccessChannel(m.Channel),
+			Actor:      m.Actor,
+			ClientType: m.ClientType,
+			IP:         m.IP,
+			UA:         m.Ua,
+			CreatedAt:  time.Time(m.CreatedAt),
+		})
+	}
+	return results, total, nil
+}
+
+// CleanupByTimeAll removes access log entries older than the given timestamp for all users
+// CleanupByTimeAll 清理所有用户在指定时间戳之前的访问日志
+func (r *noteAccessRepository) CleanupByTimeAll(ctx context.Context, timestamp int64) error {
+	uids, err := r.dao.GetAllUserUIDs()
+	if err != nil {
+		return err
+	}
+
+	for i, uid := range uids {
+		if i > 0 {
+			time.Sleep(100 * time.Millisecond) // Slight delay to reduce bursts
+		}
+		_ = r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+			return r.db(uid).WithContext(ctx).Where("created_at < ?", time.UnixMilli(timestamp)).Delete(&model.NoteAccessLog{}).Error
+		})
+	}
+	return nil
+}
+
+// Ensure noteAccessRepository implements domain.NoteAccessRepository
+// 确保 noteAccessRepository 实现了 domain.NoteAccessRepository 接口
+var _ domain.NoteAccessRepository = (*noteAccessRepository)(nil)
@@ -4,6 +4,7 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"strconv"
 
 	"github.com/blevesearch/bleve/v2"
@@ -107,10 +108,12 @@ func (r *noteFTSRepository) Search(ctx context.Context, keyword string, vaultID,
 	pathQuery := bleve.NewMatchQuery(keyword)
 	pathQuery.SetField("path")
 	pathQuery.Operator = bleveQuery.MatchQueryOperatorAnd
+	pathQuery.Analyzer = bleveCJKQueryAnalyzer
 
 	contentQuery := bleve.NewMatchQuery(keyword)
 	contentQuery.SetField("content")
 	contentQuery.Operator = bleveQuery.MatchQueryOperatorAnd
+	contentQuery.Analyzer = bleveCJKQueryAnalyzer
 
 	actionQuery := bleve.NewBooleanQuery()
 	actionTermQuery := bleve.NewTermQuery("delete")
@@ -165,10 +168,12 @@ func (r *noteFTSRepository) SearchCount(ctx context.Context, keyword string, vau
 	pathQuery := bleve.NewMatchQuery(keyword)
 	pathQuery.SetField("path")
 	pathQuery.Operator = bleveQuery.MatchQueryOperatorAnd
+	pathQuery.Analyzer = bleveCJKQueryAnalyzer
 
 	contentQuery := bleve.NewMatchQuery(keyword)
 	contentQuery.SetField("content")
 	contentQuery.Operator = bleveQuery.MatchQueryOperatorAnd
+	contentQuery.Analyzer = bleveCJKQueryAnalyzer
 
 	actionQuery := bleve.NewBooleanQuery()
 	actionTermQuery := bleve.NewTermQuery("delete")
@@ -212,25 +217,42 @@ func (r *noteFTSRepository) RebuildIndex(ctx context.Context, uid int64) error {
 // rebuildVault rebuilds index for a specific vault
 // rebuildVault 重建特定仓库的索引
 func (r *noteFTSRepository) rebuildVault(ctx context.Context, uid, vaultID int64) error {
-	if !r.dao.BleveMgr.IsEnabled() {
+	return rebuildVaultIndex(ctx, r.dao, uid, vaultID, nil)
+}
+
+// rebuildVaultIndex rebuilds a vault index from the database and note content files, reporting
+// (done, total) notes to progress when set. It stops when ctx is canceled and refuses to run
+// while another rebuild of the same vault is in progress.
+// rebuildVaultIndex 从数据库与笔记正文文件重建仓库索引，progress 非空时上报已处理/总笔记数。
+// ctx 取消时停止；同一仓库已有重建进行中时拒绝执行。
+func rebuildVaultIndex(ctx context.Context, d *Dao, uid, vaultID int64, progress func(done, total int)) error {
+	if !d.BleveMgr.IsEnabled() {
 		return nil // If FTS is disabled, do nothing // 若 FTS 未启用，则不进行任何操作
 	}
-	_ = r.dao.BleveMgr.DeleteIndex(uid, vaultID)
+	if !d.BleveMgr.tryBeginRebuild(uid, vaultID) {
+		return errFTSRebuildInProgress
+	}
+	defer d.BleveMgr.endRebuild(uid, vaultID)
 
-	index, err := r.dao.BleveMgr.GetIndex(uid, vaultID)
+	_ = d.BleveMgr.DeleteIndex(uid, vaultID)
+
+	index, err := d.BleveMgr.GetIndex(uid, vaultID)
 	if err != nil {
 		return err
 	}
 
-	db := r.dao.ResolveDB("user_" + strconv.FormatInt(uid, 10))
+	db := d.ResolveDB("user_" + strconv.FormatInt(uid, 10))
 	var notes []model.Note
 	if err := db.Where("vault_id = ?", vaultID).Find(&notes).Error; err != nil {
 		return err
 	}
 
-	for _, note := range notes {
-		folder := r.dao.GetNoteFolderPath(uid, note.ID)
-		content, exists, err := r.dao.LoadContentFromFile(folder, "content.txt")
+	for i, note := range notes {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		folder := d.GetNoteFolderPath(uid, note.ID)
+		content, exists, err := d.LoadContentFromFile(folder, "content.txt")
 		if err != nil || !exists {
 			content = ""
 		}
@@ -247,11 +269,66 @@ func (r *noteFTSRepository) rebuildVault(ctx context.Context, uid, vaultID int64
 		}
 
 		_ = index.Index(doc.ID, doc)
+		if progress != nil {
+			progress(i+1, len(notes))
+		}
 	}
 
 	return nil
 }
 
+// EnqueueReindex queues a background rebuild of the given vaults (all vaults when empty)
+// EnqueueReindex 将指定仓库（为空时为全部仓库）的索引重建加入后台队列
+func (r *noteFTSRepository) EnqueueReindex(uid int64, vaultIDs []int64) *domain.FTSReindexJob {
+	return r.dao.BleveMgr.EnqueueReindex(uid, func(ctx context.Context, job *ftsReindexJob) error {
+		ids := vaultIDs
+		if len(ids) == 0 {
+			var vaults []model.Vault
+			vaultDb := r.dao.ResolveDB("user_vault_" + strconv.FormatInt(uid, 10))
+			if err := vaultDb.WithContext(ctx).Table("vault").Where("is_deleted = 0").Find(&vaults).Error; err != nil {
+				return err
+			}
+			for _, v := range vaults {
+				ids = append(ids, v.ID)
+			}
+		}
+		job.update(func(state *domain.FTSReindexJob) { state.VaultsTotal = len(ids) })
+
+		for _, vaultID := range ids {
+			var baseDone, baseTotal int
+			job.update(func(state *domain.FTSReindexJob) { baseDone, baseTotal = state.NotesDone, state.NotesTotal })
+			err := rebuildVaultIndex(ctx, r.dao, uid, vaultID, func(done, total int) {
+				job.update(func(state *domain.FTSReindexJob) {
+					state.NotesDone, state.NotesTotal = baseDone+done, baseTotal+total
+				})
+			})
+			if err != nil && !errors.Is(err, errFTSRebuildInProgress) {
+				return err
+			}
+			job.update(func(state *domain.FTSReindexJob) { state.VaultsDone++ })
+		}
+		return nil
+	})
+}
+
+// ReindexJob returns the latest reindex job of a user
+// ReindexJob 返回用户最近一次的索引重建任务
+func (r *noteFTSRepository) ReindexJob(uid int64) (*domain.FTSReindexJob, bool) {
+	return r.dao.BleveMgr.ReindexJob(uid)
+}
+
+// ReindexJobs returns the latest reindex job of every user
+// ReindexJobs 返回所有用户最近一次的索引重建任务
+func (r *noteFTSRepository) ReindexJobs() []*domain.FTSReindexJob {
+	return r.dao.BleveMgr.ReindexJobs()
+}
+
+// CancelReindex cancels a user's queued or running reindex job
+// CancelReindex 取消用户排队或运行中的索引重建任务
+func (r *noteFTSRepository) CancelReindex(uid int64) bool {
+	return r.dao.BleveMgr.CancelReindex(uid)
+}
+
 // DeleteByVaultID deletes all FTS records for a vault
 // DeleteByVaultID 删除指定仓库的所有 FTS 记录
 func (r *noteFTSRepository) DeleteByVaultID(ctx context.Context, vaultID, uid int64) error {
@@ -246,6 +246,23 @@ func (r *noteHistoryRepository) ListByNoteID(ctx context.Context, noteID int64,
 	return results, count, nil
 }
 
+// GetFirstAfter retrieves the first history record of the note created after the given time, nil when there is none
+// GetFirstAfter 获取笔记在指定时间之后创建的第一条历史记录，不存在时返回 nil
+func (r *noteHistoryRepository) GetFirstAfter(ctx context.Context, noteID int64, after time.Time, uid int64) (*domain.NoteHistory, error) {
+	u := r.noteHistory(uid).NoteHistory
+	m, err := u.WithContext(ctx).Where(
+		u.NoteID.Eq(noteID),
+		u.CreatedAt.Gt(timex.Time(after)),
+	).Order(u.CreatedAt, u.Version).First()
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.toDomain(m, uid)
+}
+
 // GetLatestVersion retrieves the latest version number of the note
 // GetLatestVersion 获取笔记的最新版本号
 func (r *noteHistoryRepository) GetLatestVersion(ctx context.Context, noteID, uid int64) (int64, error) {
@@ -336,31 +353,73 @@ func (r *noteHistoryRepository) DeleteOldVersions(ctx context.Context, noteID in
 			return nil
 		}
 
-		// Delete database records
-		// 删除数据库记录
-		_, err = u.WithContext(ctx).Where(u.ID.In(toDeleteIDs...)).Delete()
-		if err != nil {
-			return err
-		}
+		return r.deleteByIDs(ctx, toDeleteIDs, uid)
+	})
+}
 
-		// Delete associated files
-		// 删除关联的文件
-		for _, id := range toDeleteIDs {
-			folder := r.dao.GetNoteHistoryFolderPath(uid, id)
-			if err := r.dao.RemoveContentFolder(folder); err != nil {
-				r.dao.Logger().Warn("failed to delete history folder",
-					zap.Int64(logger.FieldUID, uid),
-					zap.Int64("historyId", id),
-					zap.String("folder", folder),
-					zap.Error(err),
-				)
-			}
-		}
+// ListVersions retrieves ID, version and creation time of every history record of the note, newest version first
+// ListVersions 获取笔记全部历史记录的 ID、版本号与创建时间，按版本号倒序
+func (r *noteHistoryRepository) ListVersions(ctx context.Context, noteID int64, uid int64) ([]*domain.NoteHistory, error) {
+	u := r.noteHistory(uid).NoteHistory
+	modelList, err := u.WithContext(ctx).
+		Select(u.ID, u.NoteID, u.VaultID, u.Version, u.CreatedAt).
+		Where(u.NoteID.Eq(noteID)).
+		Order(u.Version.Desc()).
+		Find()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*domain.NoteHistory, 0, len(modelList))
+	for _, m := range modelList {
+		results = append(results, &domain.NoteHistory{
+			ID:        m.ID,
+			NoteID:    m.NoteID,
+			VaultID:   m.VaultID,
+			Version:   m.Version,
+			CreatedAt: time.Time(m.CreatedAt),
+		})
+	}
+	return results, nil
+}
 
+// DeleteByIDs deletes the history records with the given IDs together with their files
+// DeleteByIDs 删除指定ID的历史记录及其文件
+func (r *noteHistoryRepository) DeleteByIDs(ctx context.Context, ids []int64, uid int64) error {
+	if len(ids) == 0 {
 		return nil
+	}
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.deleteByIDs(ctx, ids, uid)
 	})
 }
 
+// deleteByIDs deletes history records and their files, must run inside ExecuteWrite
+// deleteByIDs 删除历史记录及其文件，需在 ExecuteWrite 内调用
+func (r *noteHistoryRepository) deleteByIDs(ctx context.Context, ids []int64, uid int64) error {
+	u := r.noteHistory(uid).NoteHistory
+
+	// Delete database records
+	// 删除数据库记录
+	if _, err := u.WithContext(ctx).Where(u.ID.In(ids...)).Delete(); err != nil {
+		return err
+	}
+
+	// Delete associated files
+	// 删除关联的文件
+	for _, id := range ids {
+		folder := r.dao.GetNoteHistoryFolderPath(uid, id)
+		if err := r.dao.RemoveContentFolder(folder); err != nil {
+			r.dao.Logger().Warn("failed to delete history folder",
+				zap.Int64(logger.FieldUID, uid),
+				zap.Int64("historyId", id),
+				zap.String("folder", folder),
+				zap.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
 // Delete deletes the history record with the specified ID
 // Delete 删除指定ID的历史记录
 func (r *noteHistoryRepository) Delete(ctx context.Context, id, uid int64) error {
@@ -389,6 +448,13 @@ func (r *noteHistoryRepository) Delete(ctx context.Context, id, uid int64) error
 	})
 }
 
+// CountByVaultID counts the history records of a vault
+// CountByVaultID 统计仓库下的历史记录数量
+func (r *noteHistoryRepository) CountByVaultID(ctx context.Context, vaultID, uid int64) (int64, error) {
+	u := r.noteHistory(uid).NoteHistory
+	return u.WithContext(ctx).Where(u.VaultID.Eq(vaultID)).Count()
+}
+
 // DeleteByVaultID physically deletes all history in a vault
 // DeleteByVaultID 物理删除仓库下的所有历史记录
 func (r *noteHistoryRepository) DeleteByVaultID(ctx context.Context, vaultID, uid int64) error {
@@ -162,6 +162,23 @@ func (r *noteLinkRepository) GetOutlinks(ctx context.Context, sourceNoteID, uid
 	return results, nil
 }
 
+// ListByVaultID gets all links of a vault
+func (r *noteLinkRepository) ListByVaultID(ctx context.Context, vaultID, uid int64) ([]*domain.NoteLink, error) {
+	nl := r.noteLink(uid).NoteLink
+	modelList, err := nl.WithContext(ctx).
+		Where(nl.VaultID.Eq(vaultID)).
+		Find()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*domain.NoteLink
+	for _, m := range modelList {
+		results = append(results, r.toDomain(m))
+	}
+	return results, nil
+}
+
 // DeleteByVaultID deletes all links for a vault
 func (r *noteLinkRepository) DeleteByVaultID(ctx context.Context, vaultID, uid int64) error {
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
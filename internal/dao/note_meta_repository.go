@@ -0,0 +1,123 @@
+package dao
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// noteMetaRepository implements domain.NoteMetaRepository
+// noteMetaRepository 实现 domain.NoteMetaRepository 接口
+type noteMetaRepository struct {
+	dao             *Dao
+	customPrefixKey string
+	migrateOnce     sync.Map // tracks per-key migration completion // 记录每个 key 是否已完成 AutoMigrate
+}
+
+// NewNoteMetaRepository creates a NoteMetaRepository instance
+// NewNoteMetaRepository 创建 NoteMetaRepository 实例
+func NewNoteMetaRepository(dao *Dao) domain.NoteMetaRepository {
+	return &noteMetaRepository{dao: dao, customPrefixKey: "user_note_meta_"}
+}
+
+// GetKey returns the database routing key for the given user
+// GetKey 返回指定用户的数据库路由键
+func (r *noteMetaRepository) GetKey(uid int64) string {
+	return r.customPrefixKey + strconv.FormatInt(uid, 10)
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name: "NoteMeta",
+		RepoFactory: func(d *Dao) daoDBCustomKey {
+			return NewNoteMetaRepository(d).(daoDBCustomKey)
+		},
+		IsMainDB: false,
+	})
+}
+
+// db returns the *gorm.DB of the user's note metadata database, with one-time AutoMigrate
+// db 返回用户笔记元数据库的 *gorm.DB，确保每个用户库只迁移一次
+func (r *noteMetaRepository) db(uid int64) *gorm.DB {
+	key := r.GetKey(uid)
+	if _, loaded := r.migrateOnce.LoadOrStore(key+"#note_meta", true); !loaded {
+		if db := r.dao.ResolveDB(key); db != nil {
+			model.AutoMigrate(db, "NoteMeta")
+		}
+	}
+	return r.dao.ResolveDB(key)
+}
+
+// ListByNoteIDs lists the metadata of the given notes ordered by note and key
+// ListByNoteIDs 按笔记与键排序列出指定笔记的元数据
+func (r *noteMetaRepository) ListByNoteIDs(ctx context.Context, noteIDs []int64, uid int64) ([]*domain.NoteMeta, error) {
+	if len(noteIDs) == 0 {
+		return nil, nil
+	}
+	var rows []*model.NoteMeta
+	if err := r.db(uid).WithContext(ctx).Where("note_id IN ?", noteIDs).Order("note_id ASC, meta_key ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	results := make([]*domain.NoteMeta, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, &domain.NoteMeta{
+			ID:        m.ID,
+			NoteID:    m.NoteID,
+			VaultID:   m.VaultID,
+			Key:       m.Key,
+			Type:      domain.NoteMetaType(m.Type),
+			Value:     m.Value,
+			CreatedAt: time.Time(m.CreatedAt),
+			UpdatedAt: time.Time(m.UpdatedAt),
+		})
+	}
+	return results, nil
+}
+
+// Set creates or replaces the entry of a note with the same key
+// Set 新建或替换笔记中同名键的元数据
+func (r *noteMetaRepository) Set(ctx context.Context, meta *domain.NoteMeta, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		now := timex.Now()
+		m := &model.NoteMeta{
+			NoteID:    meta.NoteID,
+			VaultID:   meta.VaultID,
+			Key:       meta.Key,
+			Type:      string(meta.Type),
+			Value:     meta.Value,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		return r.db(uid).WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "note_id"}, {Name: "meta_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value_type", "value", "updated_at"}),
+		}).Create(m).Error
+	})
+}
+
+// Delete removes one entry of a note
+// Delete 删除笔记的一条元数据
+func (r *noteMetaRepository) Delete(ctx context.Context, noteID int64, key string, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Where("note_id = ? AND meta_key = ?", noteID, key).Delete(&model.NoteMeta{}).Error
+	})
+}
+
+// Migrate moves the metadata of a renamed note to its new note ID
+// Migrate 将重命名笔记的元数据迁移到新的笔记 ID
+func (r *noteMetaRepository) Migrate(ctx context.Context, oldNoteID, newNoteID, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Model(&model.NoteMeta{}).Where("note_id = ?", oldNoteID).Update("note_id", newNoteID).Error
+	})
+}
+
+// Ensure noteMetaRepository implements domain.NoteMetaRepository
+// 确保 noteMetaRepository 实现了 domain.NoteMetaRepository 接口
+var _ domain.NoteMetaRepository = (*noteMetaRepository)(nil)
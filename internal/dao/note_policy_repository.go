@@ -0,0 +1,304 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+)
+
+// notePolicyRunKeep runs kept per policy, older runs are removed when a new one is stored
+// notePolicyRunKeep 每个策略保留的运行记录数，存储新记录时删除更早的记录
+const notePolicyRunKeep = 100
+
+// notePolicyRepository implements domain.NotePolicyRepository
+// notePolicyRepository 实现 domain.NotePolicyRepository 接口
+type notePolicyRepository struct {
+	dao             *Dao
+	customPrefixKey string
+	migrateOnce     sync.Map // tracks per-key migration completion // 记录每个 key 是否已完成 AutoMigrate
+}
+
+// NewNotePolicyRepository creates a NotePolicyRepository instance
+// NewNotePolicyRepository 创建 NotePolicyRepository 实例
+func NewNotePolicyRepository(dao *Dao) domain.NotePolicyRepository {
+	return &notePolicyRepository{dao: dao, customPrefixKey: "user_note_policy_"}
+}
+
+// GetKey returns the database routing key for the given user
+// GetKey 返回指定用户的数据库路由键
+func (r *notePolicyRepository) GetKey(uid int64) string {
+	return r.customPrefixKey + strconv.FormatInt(uid, 10)
+}
+
+func init() {
+	for _, name := range []string{"NotePolicy", "NotePolicyRun"} {
+		RegisterModel(ModelConfig{
+			Name: name,
+			RepoFactory: func(d *Dao) daoDBCustomKey {
+				return NewNotePolicyRepository(d).(daoDBCustomKey)
+			},
+			IsMainDB: false,
+		})
+	}
+}
+
+// db returns the *gorm.DB of the user's policy database, with one-time AutoMigrate
+// db 返回用户策略库的 *gorm.DB，确保每个用户库只迁移一次
+func (r *notePolicyRepository) db(uid int64) *gorm.DB {
+	key := r.GetKey(uid)
+	if _, loaded := r.migrateOnce.LoadOrStore(key+"#note_policy", true); !loaded {
+		if db := r.dao.ResolveDB(key); db != nil {
+			model.AutoMigrate(db, "NotePolicy")
+			model.AutoMigrate(db, "NotePolicyRun")
+		}
+	}
+	return r.dao.ResolveDB(key)
+}
+
+// notePolicyToDomain converts the database model to the domain model
+// notePolicyToDomain 将数据库模型转换为领域模型
+func notePolicyToDomain(m *model.NotePolicy) *domain.NotePolicy {
+	return &domain.NotePolicy{
+		ID:            m.ID,
+		UID:           m.UID,
+		VaultID:       m.VaultID,
+		Name:          m.Name,
+		Folder:        m.Folder,
+		Tag:           m.Tag,
+		AgeBasis:      domain.NotePolicyAgeBasis(m.AgeBasis),
+		OlderThanDays: int(m.OlderThanDays),
+		Action:        domain.NotePolicyAction(m.Action),
+		ArchiveFolder: m.ArchiveFolder,
+		IsEnabled:     m.IsEnabled == 1,
+		LastRunTime:   time.Time(m.LastRunTime),
+		CreatedAt:     time.Time(m.CreatedAt),
+		UpdatedAt:     time.Time(m.UpdatedAt),
+	}
+}
+
+// notePolicyToModel converts the domain model to the database model
+// notePolicyToModel 将领域模型转换为数据库模型
+func notePolicyToModel(p *domain.NotePolicy) *model.NotePolicy {
+	m := &model.NotePolicy{
+		ID:            p.ID,
+		UID:           p.UID,
+		VaultID:       p.VaultID,
+		Name:          p.Name,
+		Folder:        p.Folder,
+		Tag:           p.Tag,
+		AgeBasis:      string(p.AgeBasis),
+		OlderThanDays: int64(p.OlderThanDays),
+		Action:        string(p.Action),
+		ArchiveFolder: p.ArchiveFolder,
+		LastRunTime:   timex.Time(p.LastRunTime),
+		CreatedAt:     timex.Time(p.CreatedAt),
+		UpdatedAt:     timex.Time(p.UpdatedAt),
+	}
+	if p.IsEnabled {
+		m.IsEnabled = 1
+	}
+	return m
+}
+
+// notePolicyRunToDomain converts the database model to the domain model
+// notePolicyRunToDomain 将数据库模型转换为领域模型
+func notePolicyRunToDomain(m *model.NotePolicyRun) *domain.NotePolicyRun {
+	run := &domain.NotePolicyRun{
+		ID:        m.ID,
+		UID:       m.UID,
+		PolicyID:  m.PolicyID,
+		Trigger:   domain.NotePolicyTrigger(m.Trigger),
+		Matched:   int(m.Matched),
+		Succeeded: int(m.Succeeded),
+		Failed:    int(m.Failed),
+		Message:   m.Message,
+		StartTime: time.Time(m.StartTime),
+		EndTime:   time.Time(m.EndTime),
+	}
+	if m.Paths != "" {
+		run.Paths = strings.Split(m.Paths, "\n")
+	}
+	return run
+}
+
+// List lists all policies of a user
+// List 列出用户的全部策略
+func (r *notePolicyRepository) List(ctx context.Context, uid int64) ([]*domain.NotePolicy, error) {
+	var rows []*model.NotePolicy
+	if err := r.db(uid).WithContext(ctx).Where("uid = ?", uid).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	results := make([]*domain.NotePolicy, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, notePolicyToDomain(m))
+	}
+	return results, nil
+}
+
+// ListEnabled lists the enabled policies of all users
+// ListEnabled 列出所有用户已启用的策略
+func (r *notePolicyRepository) ListEnabled(ctx context.Context) ([]*domain.NotePolicy, error) {
+	uids, err := r.dao.GetAllUserUIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*domain.NotePolicy
+	for _, uid := range uids {
+		var rows []*model.NotePolicy
+		if err := r.db(uid).WithContext(ctx).Where("uid = ? AND is_enabled = ?", uid, 1).Order("id ASC").Find(&rows).Error; err != nil {
+			continue
+		}
+		for _, m := range rows {
+			results = append(results, notePolicyToDomain(m))
+		}
+	}
+	return results, nil
+}
+
+// Get returns a policy, nil when it does not exist
+// Get 获取策略，不存在时返回 nil
+func (r *notePolicyRepository) Get(ctx context.Context, id, uid int64) (*domain.NotePolicy, error) {
+	var m model.NotePolicy
+	err := r.db(uid).WithContext(ctx).Where("id = ? AND uid = ?", id, uid).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return notePolicyToDomain(&m), nil
+}
+
+// Save creates the policy when ID is 0, otherwise updates it
+// Save ID 为 0 时新建策略，否则更新
+func (r *notePolicyRepository) Save(ctx context.Context, policy *domain.NotePolicy, uid int64) (*domain.NotePolicy, error) {
+	var result *domain.NotePolicy
+	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		m := notePolicyToModel(policy)
+		m.UID = uid
+		m.UpdatedAt = timex.Now()
+		if m.ID == 0 {
+			m.CreatedAt = m.UpdatedAt
+			if err := r.db(uid).WithContext(ctx).Create(m).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := r.db(uid).WithContext(ctx).Where("id = ? AND uid = ?", m.ID, uid).
+				Select("vault_id", "name", "folder", "tag", "age_basis", "older_than_days", "action", "archive_folder", "is_enabled", "updated_at").
+				Updates(m).Error; err != nil {
+				return err
+			}
+		}
+		result = notePolicyToDomain(m)
+		return nil
+	})
+	return result, err
+}
+
+// Delete removes a policy together with its run history
+// Delete 删除策略及其运行历史
+func (r *notePolicyRepository) Delete(ctx context.Context, id, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("policy_id = ? AND uid = ?", id, uid).Delete(&model.NotePolicyRun{}).Error; err != nil {
+				return err
+			}
+			return tx.Where("id = ? AND uid = ?", id, uid).Delete(&model.NotePolicy{}).Error
+		})
+	})
+}
+
+// UpdateLastRunTime stores the time of the latest run
+// UpdateLastRunTime 存储最近一次运行的时间
+func (r *notePolicyRepository) UpdateLastRunTime(ctx context.Context, id, uid int64, lastRun time.Time) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Model(&model.NotePolicy{}).Where("id = ? AND uid = ?", id, uid).
+			Update("last_run_time", timex.Time(lastRun)).Error
+	})
+}
+
+// CreateRun stores a run, keeping the newest runs of the policy only
+// CreateRun 存储运行记录，仅保留该策略最近的运行记录
+func (r *notePolicyRepository) CreateRun(ctx context.Context, run *domain.NotePolicyRun, uid int64) (*domain.NotePolicyRun, error) {
+	var result *domain.NotePolicyRun
+	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		m := &model.NotePolicyRun{
+			UID:       uid,
+			PolicyID:  run.PolicyID,
+			Trigger:   string(run.Trigger),
+			Matched:   int64(run.Matched),
+			Succeeded: int64(run.Succeeded),
+			Failed:    int64(run.Failed),
+			Paths:     strings.Join(run.Paths, "\n"),
+			Message:   run.Message,
+			StartTime: timex.Time(run.StartTime),
+			EndTime:   timex.Time(run.EndTime),
+		}
+		if err := r.db(uid).WithContext(ctx).Create(m).Error; err != nil {
+			return err
+		}
+
+		// Trim the history: the oldest run kept decides what goes
+		// 裁剪历史：以保留的最早一条记录为界删除更早的记录
+		var oldestKept []int64
+		if err := r.db(uid).WithContext(ctx).Model(&model.NotePolicyRun{}).Where("policy_id = ? AND uid = ?", m.PolicyID, uid).
+			Order("id DESC").Offset(notePolicyRunKeep-1).Limit(1).Pluck("id", &oldestKept).Error; err != nil {
+			return err
+		}
+		if len(oldestKept) > 0 {
+			if err := r.db(uid).WithContext(ctx).Where("policy_id = ? AND uid = ? AND id < ?", m.PolicyID, uid, oldestKept[0]).
+				Delete(&model.NotePolicyRun{}).Error; err != nil {
+				return err
+			}
+		}
+
+		result = notePolicyRunToDomain(m)
+		return nil
+	})
+	return result, err
+}
+
+// ListRuns lists runs of a user, newest first; policyID 0 lists every policy
+// ListRuns 按时间倒序分页列出用户的运行记录；policyID 为 0 时列出全部策略
+func (r *notePolicyRepository) ListRuns(ctx context.Context, policyID, uid int64, page, pageSize int) ([]*domain.NotePolicyRun, int64, error) {
+	query := r.db(uid).WithContext(ctx).Model(&model.NotePolicyRun{}).Where("uid = ?", uid)
+	if policyID > 0 {
+		query = query.Where("policy_id = ?", policyID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	var rows []*model.NotePolicyRun
+	if err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]*domain.NotePolicyRun, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, notePolicyRunToDomain(m))
+	}
+	return results, total, nil
+}
+
+// Ensure notePolicyRepository implements domain.NotePolicyRepository
+// 确保 notePolicyRepository 实现了 domain.NotePolicyRepository 接口
+var _ domain.NotePolicyRepository = (*notePolicyRepository)(nil)
@@ -0,0 +1,256 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// notePropertyRepository implements domain.NotePropertyRepository
+// notePropertyRepository 实现 domain.NotePropertyRepository 接口
+type notePropertyRepository struct {
+	dao             *Dao
+	customPrefixKey string
+	migrateOnce     sync.Map // tracks per-key migration completion // 记录每个 key 是否已完成 AutoMigrate
+}
+
+// NewNotePropertyRepository creates a NotePropertyRepository instance
+// NewNotePropertyRepository 创建 NotePropertyRepository 实例
+func NewNotePropertyRepository(dao *Dao) domain.NotePropertyRepository {
+	return &notePropertyRepository{dao: dao, customPrefixKey: "user_note_property_"}
+}
+
+// GetKey returns the database routing key for the given user
+// GetKey 返回指定用户的数据库路由键
+func (r *notePropertyRepository) GetKey(uid int64) string {
+	return r.customPrefixKey + strconv.FormatInt(uid, 10)
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name: "NoteProperty",
+		RepoFactory: func(d *Dao) daoDBCustomKey {
+			return NewNotePropertyRepository(d).(daoDBCustomKey)
+		},
+		IsMainDB: false,
+	})
+}
+
+// db returns the *gorm.DB of the user's property index database, with one-time AutoMigrate
+// db 返回用户属性索引库的 *gorm.DB，确保每个用户库只迁移一次
+func (r *notePropertyRepository) db(uid int64) *gorm.DB {
+	key := r.GetKey(uid)
+	if _, loaded := r.migrateOnce.LoadOrStore(key+"#note_property", true); !loaded {
+		if db := r.dao.ResolveDB(key); db != nil {
+			model.AutoMigrate(db, "NoteProperty")
+		}
+	}
+	return r.dao.ResolveDB(key)
+}
+
+// IndexedUntil implements domain.NotePropertyRepository
+// IndexedUntil 实现 domain.NotePropertyRepository
+func (r *notePropertyRepository) IndexedUntil(ctx context.Context, vaultID, uid int64) (int64, error) {
+	var state model.NotePropertyState
+	err := r.db(uid).WithContext(ctx).Where("vault_id = ?", vaultID).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return state.IndexedUntil, nil
+}
+
+// toModels converts the properties of notes of a vault to rows
+// toModels 将保险库笔记的属性转换为数据行
+func (r *notePropertyRepository) toModels(vaultID int64, props []*domain.NoteProperty) []*model.NoteProperty {
+	rows := make([]*model.NoteProperty, 0, len(props))
+	for _, p := range props {
+		rows = append(rows, &model.NoteProperty{
+			NoteID:    p.NoteID,
+			VaultID:   vaultID,
+			Key:       p.Key,
+			ValueType: string(p.Type),
+			Text:      p.Text,
+			Number:    p.Number,
+			Time:      p.Time,
+			InList:    p.InList,
+		})
+	}
+	return rows
+}
+
+// replace deletes the rows of the given notes and inserts the new ones
+// replace 删除指定笔记的数据行并插入新行
+func (r *notePropertyRepository) replace(tx *gorm.DB, noteIDs []int64, rows []*model.NoteProperty) error {
+	for start := 0; start < len(noteIDs); start += 500 {
+		end := min(start+500, len(noteIDs))
+		if err := tx.Where("note_id IN ?", noteIDs[start:end]).Delete(&model.NoteProperty{}).Error; err != nil {
+			return err
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(rows, 200).Error
+}
+
+// ReplaceByNoteID implements domain.NotePropertyRepository
+// ReplaceByNoteID 实现 domain.NotePropertyRepository
+func (r *notePropertyRepository) ReplaceByNoteID(ctx context.Context, vaultID, noteID int64, props []*domain.NoteProperty, uid int64) error {
+	rows := r.toModels(vaultID, props)
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return r.replace(tx, []int64{noteID}, rows)
+		})
+	})
+}
+
+// Reindex implements domain.NotePropertyRepository
+// Reindex 实现 domain.NotePropertyRepository
+func (r *notePropertyRepository) Reindex(ctx context.Context, vaultID int64, noteIDs []int64, props []*domain.NoteProperty, indexedUntil, uid int64) error {
+	rows := r.toModels(vaultID, props)
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := r.replace(tx, noteIDs, rows); err != nil {
+				return err
+			}
+			return tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "vault_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"indexed_until"}),
+			}).Create(&model.NotePropertyState{VaultID: vaultID, IndexedUntil: indexedUntil}).Error
+		})
+	})
+}
+
+// Query implements domain.NotePropertyRepository
+// Query 实现 domain.NotePropertyRepository
+func (r *notePropertyRepository) Query(ctx context.Context, vaultID int64, predicates []*domain.NotePropertyPredicate, offset, limit int, uid int64) ([]int64, int64, error) {
+	db := r.db(uid).WithContext(ctx)
+
+	// fieldsOf selects the notes having a field of the key matching the condition
+	// fieldsOf 选出具有满足条件的该键字段的笔记
+	fieldsOf := func(key string, cond string, args ...interface{}) *gorm.DB {
+		q := db.Model(&model.NoteProperty{}).Select("note_id").Where("vault_id = ? AND prop_key = ?", vaultID, key)
+		if cond != "" {
+			q = q.Where(cond, args...)
+		}
+		return q
+	}
+
+	q := db.Model(&model.NoteProperty{}).Distinct("note_id").Where("vault_id = ?", vaultID)
+	for _, p := range predicates {
+		switch p.Op {
+		case domain.NotePropertyOpExists:
+			q = q.Where("note_id IN (?)", fieldsOf(p.Key, ""))
+		case domain.NotePropertyOpNotExists:
+			q = q.Where("note_id NOT IN (?)", fieldsOf(p.Key, ""))
+		case domain.NotePropertyOpEq, domain.NotePropertyOpNe:
+			var sub *gorm.DB
+			switch {
+			case p.Time != nil:
+				sub = fieldsOf(p.Key, "value_time = ?", *p.Time)
+			case p.Number != nil:
+				sub = fieldsOf(p.Key, "value_number = ? OR value_text = ?", *p.Number, p.Text)
+			default:
+				sub = fieldsOf(p.Key, "value_text = ?", p.Text)
+			}
+			if p.Op == domain.NotePropertyOpEq {
+				q = q.Where("note_id IN (?)", sub)
+			} else {
+				q = q.Where("note_id NOT IN (?)", sub)
+			}
+		case domain.NotePropertyOpContains:
+			text := strings.ToLower(p.Text)
+			q = q.Where("note_id IN (?)", fieldsOf(p.Key, "(in_list = ? AND LOWER(value_text) = ?) OR (in_list = ? AND LOWER(value_text) LIKE ?)", true, text, false, "%"+text+"%"))
+		case domain.NotePropertyOpGt, domain.NotePropertyOpGte, domain.NotePropertyOpLt, domain.NotePropertyOpLte:
+			op := map[domain.NotePropertyOp]string{
+				domain.NotePropertyOpGt:  ">",
+				domain.NotePropertyOpGte: ">=",
+				domain.NotePropertyOpLt:  "<",
+				domain.NotePropertyOpLte: "<=",
+			}[p.Op]
+			switch {
+			case p.Time != nil:
+				q = q.Where("note_id IN (?)", fieldsOf(p.Key, "value_time "+op+" ?", *p.Time))
+			case p.Number != nil:
+				q = q.Where("note_id IN (?)", fieldsOf(p.Key, "value_number "+op+" ?", *p.Number))
+			default:
+				return nil, 0, errors.New("comparison needs a number or date operand")
+			}
+		}
+	}
+
+	var total int64
+	if err := db.Table("(?) AS matched", q).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var ids []int64
+	q = q.Order("note_id DESC")
+	if limit > 0 {
+		q = q.Offset(offset).Limit(limit)
+	}
+	if err := q.Pluck("note_id", &ids).Error; err != nil {
+		return nil, 0, err
+	}
+	return ids, total, nil
+}
+
+// ListKeys implements domain.NotePropertyRepository
+// ListKeys 实现 domain.NotePropertyRepository
+func (r *notePropertyRepository) ListKeys(ctx context.Context, vaultID, uid int64) ([]*domain.NotePropertyKey, error) {
+	db := r.db(uid).WithContext(ctx)
+
+	var counts []struct {
+		PropKey string
+		Notes   int64
+	}
+	if err := db.Model(&model.NoteProperty{}).Select("prop_key, COUNT(DISTINCT note_id) AS notes").
+		Where("vault_id = ?", vaultID).Group("prop_key").Order("prop_key ASC").Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	var shapes []struct {
+		PropKey   string
+		ValueType string
+		InList    bool
+		Notes     int64
+	}
+	if err := db.Model(&model.NoteProperty{}).Select("prop_key, value_type, in_list, COUNT(DISTINCT note_id) AS notes").
+		Where("vault_id = ?", vaultID).Group("prop_key, value_type, in_list").Scan(&shapes).Error; err != nil {
+		return nil, err
+	}
+
+	// The most common shape of a key wins, ties go to the first seen
+	// 取键最常见的形态，数量相同时取先出现者
+	best := make(map[string]int, len(counts))
+	for i, sh := range shapes {
+		if j, ok := best[sh.PropKey]; !ok || sh.Notes > shapes[j].Notes {
+			best[sh.PropKey] = i
+		}
+	}
+
+	keys := make([]*domain.NotePropertyKey, 0, len(counts))
+	for _, c := range counts {
+		k := &domain.NotePropertyKey{Key: c.PropKey, NoteCount: c.Notes}
+		if i, ok := best[c.PropKey]; ok {
+			k.Type = domain.NotePropertyType(shapes[i].ValueType)
+			k.IsList = shapes[i].InList
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// Ensure notePropertyRepository implements domain.NotePropertyRepository
+// 确保 notePropertyRepository 实现了 domain.NotePropertyRepository 接口
+var _ domain.NotePropertyRepository = (*notePropertyRepository)(nil)
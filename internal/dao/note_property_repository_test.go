@@ -0,0 +1,88 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNotePropertyRepository_Query verifies predicates are evaluated against the index in SQL:
+// list membership, case-insensitive text containment, numeric and date comparisons and negations,
+// and that reindexing a note replaces its fields.
+// TestNotePropertyRepository_Query 验证条件在 SQL 中针对索引求值：列表成员、不区分大小写的文本包含、
+// 数值与日期比较及否定条件，并验证重新索引笔记会替换其字段。
+func TestNotePropertyRepository_Query(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(7)
+	repo := NewNotePropertyRepository(daoInst)
+
+	num := func(v float64) *float64 { return &v }
+	ms := func(v int64) *int64 { return &v }
+	fields := []*domain.NoteProperty{
+		{NoteID: 1, Key: "tags", Type: domain.NotePropertyTypeString, Text: "project", InList: true},
+		{NoteID: 1, Key: "tags", Type: domain.NotePropertyTypeString, Text: "work", InList: true},
+		{NoteID: 1, Key: "rating", Type: domain.NotePropertyTypeNumber, Text: "4", Number: num(4)},
+		{NoteID: 1, Key: "due", Type: domain.NotePropertyTypeDate, Text: "2026-03-01", Time: ms(1772323200000)},
+		{NoteID: 2, Key: "tags", Type: domain.NotePropertyTypeString, Text: "home", InList: true},
+		{NoteID: 2, Key: "title", Type: domain.NotePropertyTypeString, Text: "Weekly Project Review"},
+		{NoteID: 2, Key: "rating", Type: domain.NotePropertyTypeNumber, Text: "2", Number: num(2)},
+		{NoteID: 3, Key: "status", Type: domain.NotePropertyTypeString, Text: "draft"},
+	}
+	require.NoError(t, repo.Reindex(ctx, vaultID, []int64{1, 2, 3}, fields, 100, uid))
+
+	until, err := repo.IndexedUntil(ctx, vaultID, uid)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), until)
+
+	query := func(predicates ...*domain.NotePropertyPredicate) []int64 {
+		ids, total, err := repo.Query(ctx, vaultID, predicates, 0, 10, uid)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(ids)), total)
+		return ids
+	}
+
+	assert.Equal(t, []int64{3, 2, 1}, query())
+	assert.Equal(t, []int64{2, 1}, query(&domain.NotePropertyPredicate{Key: "rating", Op: domain.NotePropertyOpExists}))
+	assert.Equal(t, []int64{3}, query(&domain.NotePropertyPredicate{Key: "rating", Op: domain.NotePropertyOpNotExists}))
+	assert.Equal(t, []int64{1}, query(&domain.NotePropertyPredicate{Key: "tags", Op: domain.NotePropertyOpContains, Text: "Work"}))
+	assert.Equal(t, []int64{2}, query(&domain.NotePropertyPredicate{Key: "title", Op: domain.NotePropertyOpContains, Text: "project"}))
+	assert.Equal(t, []int64{3, 2}, query(&domain.NotePropertyPredicate{Key: "tags", Op: domain.NotePropertyOpNe, Text: "work"}))
+	assert.Equal(t, []int64{1}, query(
+		&domain.NotePropertyPredicate{Key: "rating", Op: domain.NotePropertyOpGte, Text: "3", Number: num(3)},
+		&domain.NotePropertyPredicate{Key: "due", Op: domain.NotePropertyOpLt, Text: "2026-04-01", Time: ms(1775001600000)},
+	))
+	assert.Equal(t, []int64{2}, query(&domain.NotePropertyPredicate{Key: "rating", Op: domain.NotePropertyOpEq, Text: "2", Number: num(2)}))
+
+	_, _, err = repo.Query(ctx, vaultID, []*domain.NotePropertyPredicate{{Key: "title", Op: domain.NotePropertyOpGt, Text: "a"}}, 0, 10, uid)
+	assert.Error(t, err)
+
+	// Reindexing a note drops its old fields, a note without frontmatter leaves the index
+	// 重新索引笔记会删除旧字段，没有 frontmatter 的笔记会离开索引
+	require.NoError(t, repo.Reindex(ctx, vaultID, []int64{1, 3}, []*domain.NoteProperty{{NoteID: 1, Key: "status", Type: domain.NotePropertyTypeString, Text: "done"}}, 200, uid))
+	assert.Equal(t, []int64{2, 1}, query())
+	assert.Equal(t, []int64{1}, query(&domain.NotePropertyPredicate{Key: "status", Op: domain.NotePropertyOpEq, Text: "done"}))
+
+	// Writes replace one note without moving the indexed timestamp
+	// 写入时替换单条笔记，不改变已索引时间戳
+	require.NoError(t, repo.ReplaceByNoteID(ctx, vaultID, 3, []*domain.NoteProperty{{NoteID: 3, Key: "rating", Type: domain.NotePropertyTypeNumber, Text: "5", Number: num(5)}}, uid))
+	until, err = repo.IndexedUntil(ctx, vaultID, uid)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), until)
+
+	keys, err := repo.ListKeys(ctx, vaultID, uid)
+	require.NoError(t, err)
+	require.Len(t, keys, 4)
+	assert.Equal(t, &domain.NotePropertyKey{Key: "rating", Type: domain.NotePropertyTypeNumber, NoteCount: 2}, keys[0])
+	assert.Equal(t, &domain.NotePropertyKey{Key: "tags", Type: domain.NotePropertyTypeString, IsList: true, NoteCount: 1}, keys[2])
+
+	ids, total, err := repo.Query(ctx, vaultID, nil, 1, 1, uid)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, ids)
+	assert.Equal(t, int64(3), total)
+}
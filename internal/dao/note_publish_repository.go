@@ -0,0 +1,197 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+)
+
+// notePublishRepository implements domain.NotePublishRepository
+// notePublishRepository 实现 domain.NotePublishRepository 接口
+type notePublishRepository struct {
+	dao             *Dao
+	customPrefixKey string
+	migrateOnce     sync.Map // tracks per-key migration completion // 记录每个 key 是否已完成 AutoMigrate
+}
+
+// NewNotePublishRepository creates a NotePublishRepository instance
+// NewNotePublishRepository 创建 NotePublishRepository 实例
+func NewNotePublishRepository(dao *Dao) domain.NotePublishRepository {
+	return &notePublishRepository{dao: dao, customPrefixKey: "user_note_publish_"}
+}
+
+// GetKey returns the database routing key for the given user
+// GetKey 返回指定用户的数据库路由键
+func (r *notePublishRepository) GetKey(uid int64) string {
+	return r.customPrefixKey + strconv.FormatInt(uid, 10)
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name: "NotePublish",
+		RepoFactory: func(d *Dao) daoDBCustomKey {
+			return NewNotePublishRepository(d).(daoDBCustomKey)
+		},
+		IsMainDB: false,
+	})
+}
+
+// db returns the *gorm.DB of the user's publishing database, with one-time AutoMigrate
+// db 返回用户发布状态库的 *gorm.DB，确保每个用户库只迁移一次
+func (r *notePublishRepository) db(uid int64) *gorm.DB {
+	key := r.GetKey(uid)
+	if _, loaded := r.migrateOnce.LoadOrStore(key+"#note_publish", true); !loaded {
+		if db := r.dao.ResolveDB(key); db != nil {
+			model.AutoMigrate(db, "NotePublish")
+		}
+	}
+	return r.dao.ResolveDB(key)
+}
+
+// notePublishToDomain converts the database model to the domain model
+// notePublishToDomain 将数据库模型转换为领域模型
+func notePublishToDomain(m *model.NotePublish) *domain.NotePublish {
+	return &domain.NotePublish{
+		ID:          m.ID,
+		UID:         m.UID,
+		VaultID:     m.VaultID,
+		PathHash:    m.PathHash,
+		Path:        m.Path,
+		NoteID:      m.NoteID,
+		Status:      domain.NotePublishStatus(m.Status),
+		PublishAt:   time.Time(m.PublishAt),
+		PublishedAt: time.Time(m.PublishedAt),
+		ShareID:     m.ShareID,
+		ShareToken:  m.ShareToken,
+		Message:     m.Message,
+		CreatedAt:   time.Time(m.CreatedAt),
+		UpdatedAt:   time.Time(m.UpdatedAt),
+	}
+}
+
+// notePublishToModel converts the domain model to the database model
+// notePublishToModel 将领域模型转换为数据库模型
+func notePublishToModel(p *domain.NotePublish) *model.NotePublish {
+	return &model.NotePublish{
+		ID:          p.ID,
+		UID:         p.UID,
+		VaultID:     p.VaultID,
+		PathHash:    p.PathHash,
+		Path:        p.Path,
+		NoteID:      p.NoteID,
+		Status:      string(p.Status),
+		PublishAt:   timex.Time(p.PublishAt),
+		PublishedAt: timex.Time(p.PublishedAt),
+		ShareID:     p.ShareID,
+		ShareToken:  p.ShareToken,
+		Message:     p.Message,
+		CreatedAt:   timex.Time(p.CreatedAt),
+		UpdatedAt:   timex.Time(p.UpdatedAt),
+	}
+}
+
+// ListUIDs lists the users whose notes are scanned for publishing
+// ListUIDs 列出需要扫描待发布笔记的用户
+func (r *notePublishRepository) ListUIDs(ctx context.Context) ([]int64, error) {
+	return r.dao.GetAllUserUIDs()
+}
+
+// GetByPathHash returns the state of a note, nil when it was never scheduled
+// GetByPathHash 获取笔记的发布状态，从未计划发布时返回 nil
+func (r *notePublishRepository) GetByPathHash(ctx context.Context, vaultID int64, pathHash string, uid int64) (*domain.NotePublish, error) {
+	var m model.NotePublish
+	err := r.db(uid).WithContext(ctx).Where("vault_id = ? AND path_hash = ? AND uid = ?", vaultID, pathHash, uid).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return notePublishToDomain(&m), nil
+}
+
+// Save creates the state when ID is 0, otherwise updates it
+// Save ID 为 0 时新建状态，否则更新
+func (r *notePublishRepository) Save(ctx context.Context, p *domain.NotePublish, uid int64) (*domain.NotePublish, error) {
+	var result *domain.NotePublish
+	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		m := notePublishToModel(p)
+		m.UID = uid
+		m.UpdatedAt = timex.Now()
+		if m.ID == 0 {
+			m.CreatedAt = m.UpdatedAt
+			if err := r.db(uid).WithContext(ctx).Create(m).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := r.db(uid).WithContext(ctx).Where("id = ? AND uid = ?", m.ID, uid).
+				Select("path", "note_id", "status", "publish_at", "published_at", "share_id", "share_token", "message", "updated_at").
+				Updates(m).Error; err != nil {
+				return err
+			}
+		}
+		result = notePublishToDomain(m)
+		return nil
+	})
+	return result, err
+}
+
+// ListDue lists the scheduled notes whose publish time is not after now
+// ListDue 列出计划发布时间不晚于 now 的待发布笔记
+func (r *notePublishRepository) ListDue(ctx context.Context, now time.Time, uid int64) ([]*domain.NotePublish, error) {
+	var rows []*model.NotePublish
+	err := r.db(uid).WithContext(ctx).
+		Where("uid = ? AND status = ? AND (publish_at IS NULL OR publish_at <= ?)", uid, string(domain.NotePublishStatusScheduled), timex.Time(now)).
+		Order("id ASC").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*domain.NotePublish, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, notePublishToDomain(m))
+	}
+	return results, nil
+}
+
+// List lists the states of a vault by publish time, newest first; an empty status lists all
+// List 按发布时间倒序列出仓库的发布状态；status 为空时列出全部
+func (r *notePublishRepository) List(ctx context.Context, vaultID int64, status domain.NotePublishStatus, uid int64, page, pageSize int) ([]*domain.NotePublish, int64, error) {
+	query := r.db(uid).WithContext(ctx).Model(&model.NotePublish{}).Where("vault_id = ? AND uid = ?", vaultID, uid)
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	var rows []*model.NotePublish
+	if err := query.Order("publish_at DESC").Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]*domain.NotePublish, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, notePublishToDomain(m))
+	}
+	return results, total, nil
+}
+
+// Ensure notePublishRepository implements domain.NotePublishRepository
+// 确保 notePublishRepository 实现了 domain.NotePublishRepository 接口
+var _ domain.NotePublishRepository = (*notePublishRepository)(nil)
@@ -0,0 +1,66 @@
+package dao
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNoteRepository_ListByContentRegex verifies content files are scanned in list order, match
+// counts are reported, and deleted, archived and oversized notes are skipped.
+// TestNoteRepository_ListByContentRegex 验证按列表顺序扫描正文文件并返回匹配次数，
+// 且跳过已删除、归档及超出大小限制的笔记。
+func TestNoteRepository_ListByContentRegex(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(1)
+	noteRepo := NewNoteRepository(daoInst).(*noteRepository)
+
+	// Trigger schema auto-migration via the repository's own query accessor.
+	// 通过仓库自身的查询访问器触发建表。
+	_, err := noteRepo.CountByFIDs(ctx, []int64{1}, vaultID, uid)
+	require.NoError(t, err)
+
+	db := daoInst.ResolveDB(noteRepo.GetKey(uid))
+	create := func(path, action, content string, mtime int64) {
+		n := &model.Note{VaultID: vaultID, Action: action, Path: path, PathHash: path, Size: int64(len(content)), Mtime: mtime}
+		require.NoError(t, db.Create(n).Error)
+		require.NoError(t, daoInst.SaveContentToFile(daoInst.GetNoteFolderPath(uid, n.ID), "content.txt", content))
+	}
+	create("todo.md", "modify", "- [ ] milk\n- [x] eggs\n- [ ] bread", 300)
+	create("done.md", "modify", "- [x] all done", 200)
+	create("old.md", "modify", "- [ ] later", 100)
+	create("gone.md", "delete", "- [ ] removed", 400)
+	create("Archive/a.md", "modify", "- [ ] archived", 500)
+	create("big.md", "modify", "- [ ] "+string(make([]byte, 64)), 600)
+
+	matches, truncated, err := noteRepo.ListByContentRegex(ctx, vaultID, uid, &domain.NoteRegexQuery{
+		Pattern:         regexp.MustCompile(`(?m)^- \[ \] `),
+		SortBy:          "mtime",
+		SortOrder:       "desc",
+		ExcludePrefixes: []string{"Archive"},
+		MaxFileSize:     40,
+	})
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "todo.md", matches[0].Note.Path)
+	assert.Equal(t, 2, matches[0].MatchCount)
+	assert.Contains(t, matches[0].Note.Content, "bread")
+	assert.Equal(t, "old.md", matches[1].Note.Path)
+
+	matches, truncated, err = noteRepo.ListByContentRegex(ctx, vaultID, uid, &domain.NoteRegexQuery{
+		Pattern:    regexp.MustCompile(`- \[`),
+		MaxResults: 1,
+	})
+	require.NoError(t, err)
+	assert.True(t, truncated, "the result cap stops the scan early")
+	assert.Len(t, matches, 1)
+}
@@ -4,9 +4,11 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/blevesearch/bleve/v2"
@@ -17,7 +19,9 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/tracer"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
@@ -61,6 +65,7 @@ func (r *noteRepository) note(uid int64) *query.Query {
 // ListByIDs retrieves note list by ID list
 // ListByIDs 根据ID列表获取笔记列表
 func (r *noteRepository) ListByIDs(ctx context.Context, ids []int64, uid int64) ([]*domain.Note, error) {
+	r.flushPendingWrites(ctx, uid)
 	if len(ids) == 0 {
 		return []*domain.Note{}, nil
 	}
@@ -92,12 +97,19 @@ func (r *noteRepository) EnsureFTSIndex(ctx context.Context, uid int64) error {
 		return err
 	}
 
+	// Missing or outdated (older analyzer version) indexes are rebuilt by the background reindex
+	// queue instead of blocking the request; outdated indexes keep serving until then
+	// 缺失或过期（旧版本分析器构建）的索引交由后台重建队列处理，不阻塞当前请求；过期索引在重建前继续使用
+	var stale []int64
 	for _, v := range vaults {
 		path := r.dao.BleveMgr.GetIndexPath(uid, v.ID)
-		if _, err := os.Stat(path); os.IsNotExist(err) {
-			_ = r.RebuildVaultIndex(ctx, uid, v.ID)
+		if _, err := os.Stat(path); os.IsNotExist(err) || r.dao.BleveMgr.IsOutdated(uid, v.ID) {
+			stale = append(stale, v.ID)
 		}
 	}
+	if len(stale) > 0 && !r.dao.BleveMgr.ReindexPending(uid) {
+		NewNoteFTSRepository(r.dao).EnqueueReindex(uid, stale)
+	}
 	return nil
 }
 
@@ -261,22 +273,47 @@ func (r *noteRepository) GetByID(ctx context.Context, id, uid int64) (*domain.No
 	if err != nil {
 		return nil, err
 	}
-	return r.toDomain(m, uid)
+	note, err := r.toDomain(m, uid)
+	if err != nil {
+		return nil, err
+	}
+	return r.overlayPendingWrite(uid, note), nil
 }
 
 // GetByPathHash retrieves note by path hash (excluding deleted)
 // GetByPathHash 根据路径哈希获取笔记（排除已删除）
 func (r *noteRepository) GetByPathHash(ctx context.Context, pathHash string, vaultID, uid int64) (*domain.Note, error) {
-	u := r.note(uid).Note
-	m, err := u.WithContext(ctx).Where(
-		u.VaultID.Eq(vaultID),
-		u.PathHash.Eq(pathHash),
-		u.Action.Neq("delete"),
-	).First()
+	m, err := r.cachedByPathHash(noteLookupKey{uid: uid, vaultID: vaultID, pathHash: pathHash, live: true}, func() (*model.Note, error) {
+		u := r.note(uid).Note
+		return u.WithContext(ctx).Where(
+			u.VaultID.Eq(vaultID),
+			u.PathHash.Eq(pathHash),
+			u.Action.Neq("delete"),
+		).First()
+	})
+	if err != nil {
+		return nil, err
+	}
+	note, err := r.toDomain(m, uid)
 	if err != nil {
 		return nil, err
 	}
-	return r.toDomain(m, uid)
+	return r.overlayPendingWrite(uid, note), nil
+}
+
+// cachedByPathHash serves a path hash lookup from the lookup cache, loading and caching the row on a miss
+// cachedByPathHash 从查询缓存读取路径哈希查询结果，未命中时加载并缓存该行
+func (r *noteRepository) cachedByPathHash(key noteLookupKey, load func() (*model.Note, error)) (*model.Note, error) {
+	if m, ok := r.dao.lookups.getNote(key); ok {
+		return m, nil
+	}
+	gen := r.dao.lookups.generation(key.uid)
+	m, err := load()
+	if err != nil {
+		return nil, err
+	}
+	r.dao.lookups.addNote(key, gen, m)
+	return m, nil
 }
 
 // GetByPathHashIncludeRecycle retrieves note by path hash (optionally including recycle bin)
@@ -298,21 +335,31 @@ func (r *noteRepository) GetByPathHashIncludeRecycle(ctx context.Context, pathHa
 	if err != nil {
 		return nil, err
 	}
-	return r.toDomain(m, uid)
+	note, err := r.toDomain(m, uid)
+	if err != nil {
+		return nil, err
+	}
+	return r.overlayPendingWrite(uid, note), nil
 }
 
 // GetAllByPathHash retrieves note by path hash (including all statuses)
 // GetAllByPathHash 根据路径哈希获取笔记（包含所有状态）
 func (r *noteRepository) GetAllByPathHash(ctx context.Context, pathHash string, vaultID, uid int64) (*domain.Note, error) {
-	u := r.note(uid).Note
-	m, err := u.WithContext(ctx).Where(
-		u.VaultID.Eq(vaultID),
-		u.PathHash.Eq(pathHash),
-	).First()
+	m, err := r.cachedByPathHash(noteLookupKey{uid: uid, vaultID: vaultID, pathHash: pathHash}, func() (*model.Note, error) {
+		u := r.note(uid).Note
+		return u.WithContext(ctx).Where(
+			u.VaultID.Eq(vaultID),
+			u.PathHash.Eq(pathHash),
+		).First()
+	})
 	if err != nil {
 		return nil, err
 	}
-	return r.toDomain(m, uid)
+	note, err := r.toDomain(m, uid)
+	if err != nil {
+		return nil, err
+	}
+	return r.overlayPendingWrite(uid, note), nil
 }
 
 // ListByPathHash retrieves note list by path hash (handling duplicate records)
@@ -332,7 +379,7 @@ func (r *noteRepository) ListByPathHash(ctx context.Context, pathHash string, va
 		if err != nil {
 			return nil, err
 		}
-		res = append(res, note)
+		res = append(res, r.overlayPendingWrite(uid, note))
 	}
 	return res, nil
 }
@@ -348,12 +395,36 @@ func (r *noteRepository) GetByPath(ctx context.Context, path string, vaultID, ui
 	if err != nil {
 		return nil, err
 	}
-	return r.toDomain(m, uid)
+	note, err := r.toDomain(m, uid)
+	if err != nil {
+		return nil, err
+	}
+	return r.overlayPendingWrite(uid, note), nil
+}
+
+// GetByPathLike retrieves note by path suffix, excluding deleted notes
+// GetByPathLike 根据路径后缀获取笔记（排除已删除）
+func (r *noteRepository) GetByPathLike(ctx context.Context, path string, vaultID, uid int64) (*domain.Note, error) {
+	u := r.note(uid).Note
+	m, err := u.WithContext(ctx).Where(
+		u.VaultID.Eq(vaultID),
+		u.Path.Like("%"+path),
+		u.Action.Neq("delete"),
+	).First()
+	if err != nil {
+		return nil, err
+	}
+	note, err := r.toDomain(m, uid)
+	if err != nil {
+		return nil, err
+	}
+	return r.overlayPendingWrite(uid, note), nil
 }
 
 // Create creates a note
 // Create 创建笔记
 func (r *noteRepository) Create(ctx context.Context, note *domain.Note, uid int64) (*domain.Note, error) {
+	defer r.dao.lookups.invalidateNotePath(uid, note.VaultID, note.PathHash)
 	var result *domain.Note
 	var createErr error
 
@@ -400,22 +471,34 @@ func (r *noteRepository) Create(ctx context.Context, note *domain.Note, uid int6
 	return result, createErr
 }
 
-// Update updates a note
-// Update 更新笔记
+// Update updates a note; with write coalescing enabled, rapid successive updates are buffered
+// Update 更新笔记；启用写入合并时，短时间内的连续更新会被缓冲
 func (r *noteRepository) Update(ctx context.Context, note *domain.Note, uid int64) (*domain.Note, error) {
+	m := r.toModel(note)
+	content := m.Content
+	m.Content = "" // Do not update content in database // 不在数据库更新内容
+
+	if r.dao.noteWrites != nil {
+		m.UpdatedTimestamp = timex.Now().UnixMilli()
+		m.UpdatedAt = timex.Now()
+		return r.dao.noteWrites.update(ctx, r, uid, m, content)
+	}
+	return r.writeUpdate(ctx, m, content, uid)
+}
+
+// writeUpdate writes the note row, content file and FTS document
+// writeUpdate 写入笔记行、正文文件和 FTS 文档
+func (r *noteRepository) writeUpdate(ctx context.Context, m *model.Note, content string, uid int64) (*domain.Note, error) {
+	defer r.dao.lookups.invalidateNoteID(uid, m.ID)
 	var result *domain.Note
 	var updateErr error
 
 	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := r.note(uid).Note
-		m := r.toModel(note)
 
 		m.UpdatedTimestamp = timex.Now().UnixMilli()
 		m.UpdatedAt = timex.Now()
 
-		content := m.Content
-		m.Content = "" // Do not update content in database // 不在数据库更新内容
-
 		updateErr = u.WithContext(ctx).Where(
 			u.ID.Eq(m.ID),
 		).Select(
@@ -469,9 +552,37 @@ func (r *noteRepository) Update(ctx context.Context, note *domain.Note, uid int6
 	return result, updateErr
 }
 
+// pendingToDomain builds the result of a buffered update without touching the database
+// pendingToDomain 构造缓冲更新的返回结果，不访问数据库
+func (r *noteRepository) pendingToDomain(m *model.Note, content string) *domain.Note {
+	note := r.toDomainMeta(m)
+	note.Content = content
+	note.ContentLastSnapshot = m.ContentLastSnapshot
+	return note
+}
+
+// flushPendingWrites writes the user's buffered updates before queries that cannot overlay them
+// flushPendingWrites 在无法叠加缓冲状态的查询与写入之前，先写入该用户缓冲的更新
+func (r *noteRepository) flushPendingWrites(ctx context.Context, uid int64) {
+	if r.dao.noteWrites != nil {
+		r.dao.noteWrites.flushUser(ctx, uid)
+	}
+}
+
+// overlayPendingWrite applies a buffered update onto a note loaded from the database
+// overlayPendingWrite 将缓冲的更新叠加到从数据库加载的笔记上
+func (r *noteRepository) overlayPendingWrite(uid int64, n *domain.Note) *domain.Note {
+	if r.dao.noteWrites != nil {
+		r.dao.noteWrites.overlay(uid, n)
+	}
+	return n
+}
+
 // UpdateDelete updates note to deleted status
 // UpdateDelete 更新笔记为删除状态
 func (r *noteRepository) UpdateDelete(ctx context.Context, note *domain.Note, uid int64) error {
+	defer r.dao.lookups.invalidateNoteID(uid, note.ID)
+	r.flushPendingWrites(ctx, uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := r.note(uid).Note
 		m := &model.Note{
@@ -511,6 +622,8 @@ func (r *noteRepository) UpdateDelete(ctx context.Context, note *domain.Note, ui
 // UpdateMtime updates note modification time
 // UpdateMtime 更新笔记修改时间
 func (r *noteRepository) UpdateMtime(ctx context.Context, mtime int64, id, uid int64) error {
+	defer r.dao.lookups.invalidateNoteID(uid, id)
+	r.flushPendingWrites(ctx, uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := r.note(uid).Note
 
@@ -528,6 +641,8 @@ func (r *noteRepository) UpdateMtime(ctx context.Context, mtime int64, id, uid i
 // UpdateActionMtime updates note modification time
 // UpdateActionMtime 更新笔记修改时间
 func (r *noteRepository) UpdateActionMtime(ctx context.Context, action domain.NoteAction, mtime int64, id, uid int64) error {
+	defer r.dao.lookups.invalidateNoteID(uid, id)
+	r.flushPendingWrites(ctx, uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := r.note(uid).Note
 
@@ -546,6 +661,8 @@ func (r *noteRepository) UpdateActionMtime(ctx context.Context, action domain.No
 // UpdateSnapshot updates note snapshot
 // UpdateSnapshot 更新笔记快照
 func (r *noteRepository) UpdateSnapshot(ctx context.Context, snapshot, snapshotHash string, version, id, uid int64) error {
+	defer r.dao.lookups.invalidateNoteID(uid, id)
+	r.flushPendingWrites(ctx, uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := r.note(uid).Note
 
@@ -567,6 +684,8 @@ func (r *noteRepository) UpdateSnapshot(ctx context.Context, snapshot, snapshotH
 // Delete physically deletes a note
 // Delete 物理删除笔记
 func (r *noteRepository) Delete(ctx context.Context, id, vaultID, uid int64) error {
+	defer r.dao.lookups.invalidateNoteID(uid, id)
+	r.flushPendingWrites(ctx, uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := r.note(uid).Note
 
@@ -590,23 +709,39 @@ func (r *noteRepository) Delete(ctx context.Context, id, vaultID, uid int64) err
 // DeletePhysicalByTime physically deletes notes marked as deleted by time
 // DeletePhysicalByTime 根据时间物理删除已标记删除的笔记
 func (r *noteRepository) DeletePhysicalByTime(ctx context.Context, timestamp, uid int64) error {
+	return r.DeletePhysicalByTimeInVaults(ctx, timestamp, nil, true, uid)
+}
+
+// DeletePhysicalByTimeInVaults physically deletes notes marked as deleted by time, only in vaultIDs or, with exclude, outside them
+// DeletePhysicalByTimeInVaults 根据时间物理删除已标记删除的笔记，仅限 vaultIDs 中的保险库，exclude 时则排除这些保险库
+func (r *noteRepository) DeletePhysicalByTimeInVaults(ctx context.Context, timestamp int64, vaultIDs []int64, exclude bool, uid int64) error {
+	if !exclude && len(vaultIDs) == 0 {
+		return nil
+	}
+	defer r.dao.lookups.invalidateNotes(uid)
+	r.flushPendingWrites(ctx, uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := r.note(uid).Note
-
-		// 先找到要删除的 ID
-		list, _ := u.WithContext(ctx).Where(
+		q := u.WithContext(ctx).Where(
 			u.Action.Eq("delete"),
 			u.UpdatedTimestamp.Lt(timestamp),
-		).Select(u.ID, u.VaultID).Find()
+		)
+		if len(vaultIDs) > 0 {
+			if exclude {
+				q = q.Where(u.VaultID.NotIn(vaultIDs...))
+			} else {
+				q = q.Where(u.VaultID.In(vaultIDs...))
+			}
+		}
+
+		// 先找到要删除的 ID
+		list, _ := q.Select(u.ID, u.VaultID).Find()
 
 		for _, m := range list {
 			r.deleteFTS(m.ID, m.VaultID, uid)
 		}
 
-		_, err := u.WithContext(ctx).Where(
-			u.Action.Eq("delete"),
-			u.UpdatedTimestamp.Lt(timestamp),
-		).Delete()
+		_, err := q.Delete()
 
 		if err == nil {
 			for _, m := range list {
@@ -643,9 +778,13 @@ func (r *noteRepository) DeletePhysicalByTimeAll(ctx context.Context, timestamp
 	return nil
 }
 
-// List retrieves note list by page
-// List 分页获取笔记列表
-func (r *noteRepository) List(ctx context.Context, vaultID int64, page, pageSize int, uid int64, keyword string, isRecycle bool, searchMode string, searchContent bool, sortBy string, sortOrder string, paths []string) ([]*domain.Note, error) {
+// List retrieves note list by page; keyword searches skip notes under excludePrefixes
+// List 分页获取笔记列表；关键词搜索时跳过 excludePrefixes 目录下的笔记
+func (r *noteRepository) List(ctx context.Context, vaultID int64, page, pageSize int, uid int64, keyword string, isRecycle bool, searchMode string, searchContent bool, sortBy string, sortOrder string, paths []string, excludePrefixes []string) ([]*domain.Note, error) {
+	r.flushPendingWrites(ctx, uid)
+	ctx, span := tracer.Start(ctx, "NoteRepository.List", tracer.UID(uid), tracer.VaultID(vaultID))
+	defer span.End()
+
 	u := r.note(uid).Note
 	q := u.WithContext(ctx).Where(
 		u.VaultID.Eq(vaultID),
@@ -676,7 +815,10 @@ func (r *noteRepository) List(ctx context.Context, vaultID int64, page, pageSize
 			// 确保 FTS 索引存在
 			_ = r.EnsureFTSIndex(ctx, uid)
 
-			noteIDs, ftsErr := r.searchFTS(uid, vaultID, keyword, isRecycle, sortBy, sortOrder, pageSize, app.GetPageOffset(page, pageSize))
+			_, ftsSpan := tracer.Start(ctx, "NoteRepository.searchFTS", tracer.UID(uid), tracer.VaultID(vaultID))
+			noteIDs, ftsErr := r.searchFTS(uid, vaultID, keyword, isRecycle, sortBy, sortOrder, pageSize, app.GetPageOffset(page, pageSize), excludePrefixes)
+			ftsSpan.SetAttributes(attribute.Int("fts.hits", len(noteIDs)))
+			tracer.End(ftsSpan, ftsErr)
 			if ftsErr != nil {
 				return nil, ftsErr
 			}
@@ -690,7 +832,7 @@ func (r *noteRepository) List(ctx context.Context, vaultID int64, page, pageSize
 		} else {
 			// 路径搜索：使用 LIKE
 			key := "%" + keyword + "%"
-			err = q.UnderlyingDB().Where("path LIKE ?", key).
+			err = excludePathPrefixes(q.UnderlyingDB().Where("path LIKE ?", key), excludePrefixes).
 				Order(orderClause).
 				Limit(pageSize).
 				Offset(app.GetPageOffset(page, pageSize)).
@@ -721,6 +863,7 @@ func (r *noteRepository) List(ctx context.Context, vaultID int64, page, pageSize
 }
 
 func (r *noteRepository) ListByPathPrefix(ctx context.Context, pathPrefix string, vaultID, uid int64) ([]*domain.Note, error) {
+	r.flushPendingWrites(ctx, uid)
 	u := r.note(uid).Note
 	// Use LIKE 'prefix/%'
 	// 使用 LIKE 'prefix/%'
@@ -747,6 +890,119 @@ func (r *noteRepository) ListByPathPrefix(ctx context.Context, pathPrefix string
 	return res, nil
 }
 
+// MoveByPathPrefix moves every live note under oldPrefix to the same relative path under newPrefix.
+// Each note is tombstoned with the rename flag and recreated (or a deleted record at the new path reused)
+// inside one transaction; content files and the FTS index are written after commit.
+// MoveByPathPrefix 将 oldPrefix 下的所有有效笔记移动到 newPrefix 下的相同相对路径。
+// 每条笔记在同一事务内标记为重命名删除并重新创建（或复用新路径上已删除的记录）；内容文件与 FTS 索引在提交后写入。
+func (r *noteRepository) MoveByPathPrefix(ctx context.Context, oldPrefix, newPrefix string, fids map[string]int64, vaultID, uid int64) ([]*domain.NoteMove, error) {
+	defer r.dao.lookups.invalidateNotes(uid)
+	r.flushPendingWrites(ctx, uid)
+	oldPrefix = strings.Trim(oldPrefix, "/")
+	newPrefix = strings.Trim(newPrefix, "/")
+
+	var moves []*domain.NoteMove
+	var oldModels, newModels []*model.Note
+	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		moves, oldModels, newModels = nil, nil, nil
+		return r.note(uid).Transaction(func(tx *query.Query) error {
+			u := tx.Note
+			ms, err := u.WithContext(ctx).Where(
+				u.VaultID.Eq(vaultID),
+				u.Path.Like(oldPrefix+"/%"),
+				u.Action.Neq(string(domain.NoteActionDelete)),
+			).Find()
+			if err != nil {
+				return err
+			}
+
+			now := timex.Now()
+			for _, m := range ms {
+				if !isPathWithinPrefix(m.Path, oldPrefix) {
+					continue
+				}
+				old, err := r.toDomain(m, uid)
+				if err != nil {
+					return err
+				}
+				newPath := newPrefix + strings.TrimPrefix(m.Path, oldPrefix)
+				newPathHash := util.EncodeHash32(newPath)
+				target, err := u.WithContext(ctx).Where(u.VaultID.Eq(vaultID), u.PathHash.Eq(newPathHash)).First()
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+				if target != nil && target.Action != string(domain.NoteActionDelete) {
+					return fmt.Errorf("note already exists at %s", newPath)
+				}
+
+				// Tombstone the old record with the rename flag // 以重命名标志标记旧记录删除
+				m.Action = string(domain.NoteActionDelete)
+				m.Rename = 1
+				m.UpdatedTimestamp = now.UnixMilli()
+				m.UpdatedAt = now
+				if _, err := u.WithContext(ctx).Where(u.ID.Eq(m.ID)).Select(u.Action, u.Rename, u.UpdatedTimestamp, u.UpdatedAt).Updates(m); err != nil {
+					return err
+				}
+
+				nm := &model.Note{
+					VaultID:          vaultID,
+					Action:           string(domain.NoteActionCreate),
+					FID:              fids[parentDir(newPath)],
+					Path:             newPath,
+					PathHash:         newPathHash,
+					ContentHash:      m.ContentHash,
+					Version:          m.Version,
+					ClientName:       m.ClientName,
+					ClientType:       m.ClientType,
+					ClientVersion:    m.ClientVersion,
+					Size:             m.Size,
+					Ctime:            m.Ctime,
+					Mtime:            m.Mtime, // Preserve original mtime // 保留原始修改时间
+					UpdatedTimestamp: now.UnixMilli(),
+					UpdatedAt:        now,
+				}
+				if target != nil {
+					// Reuse deleted record // 复用已删除的记录
+					nm.ID = target.ID
+					nm.CreatedAt = target.CreatedAt
+					err = u.WithContext(ctx).Where(u.ID.Eq(nm.ID)).Select(
+						u.ID, u.VaultID, u.Action, u.Rename, u.FID, u.Path, u.PathHash, u.Content, u.ContentHash, u.Version,
+						u.ClientName, u.ClientType, u.ClientVersion, u.Size, u.Ctime, u.Mtime, u.UpdatedAt, u.UpdatedTimestamp,
+					).Save(nm)
+				} else {
+					nm.CreatedAt = now
+					err = u.WithContext(ctx).Create(nm)
+				}
+				if err != nil {
+					return err
+				}
+
+				// The content file is written after commit, a rolled back move must not overwrite the reused record's file
+				// 正文文件在提交后写入，避免回滚的移动覆盖被复用记录的文件
+				moved := r.toDomainMeta(nm)
+				moved.Content = old.Content
+				old.Action, old.Rename, old.UpdatedTimestamp = domain.NoteAction(m.Action), m.Rename, m.UpdatedTimestamp
+				moves = append(moves, &domain.NoteMove{Old: old, New: moved})
+				oldModels = append(oldModels, m)
+				newModels = append(newModels, nm)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, mv := range moves {
+		if err := r.dao.SaveContentToFile(r.dao.GetNoteFolderPath(uid, mv.New.ID), "content.txt", mv.New.Content); err != nil {
+			return nil, err
+		}
+		r.upsertFTS(oldModels[i], mv.Old.Content, uid)
+		r.upsertFTS(newModels[i], mv.New.Content, uid)
+	}
+	return moves, nil
+}
+
 // getSortField maps sort fields
 // getSortField 映射排序字段
 func getSortField(sortBy string) string {
@@ -776,9 +1032,13 @@ func buildOrderClause(sortBy, sortOrder string) string {
 	return getSortField(sortBy) + " " + sortOrder
 }
 
-// ListCount retrieves note count
-// ListCount 获取笔记数量
-func (r *noteRepository) ListCount(ctx context.Context, vaultID, uid int64, keyword string, isRecycle bool, searchMode string, searchContent bool, paths []string) (int64, error) {
+// ListCount retrieves note count, honoring excludePrefixes like List
+// ListCount 获取笔记数量，excludePrefixes 的处理与 List 一致
+func (r *noteRepository) ListCount(ctx context.Context, vaultID, uid int64, keyword string, isRecycle bool, searchMode string, searchContent bool, paths []string, excludePrefixes []string) (int64, error) {
+	r.flushPendingWrites(ctx, uid)
+	ctx, span := tracer.Start(ctx, "NoteRepository.ListCount", tracer.UID(uid), tracer.VaultID(vaultID))
+	defer span.End()
+
 	u := r.note(uid).Note
 	q := u.WithContext(ctx).Where(
 		u.VaultID.Eq(vaultID),
@@ -799,11 +1059,13 @@ func (r *noteRepository) ListCount(ctx context.Context, vaultID, uid int64, keyw
 	} else if keyword != "" {
 		// 内容搜索模式：使用 Bleve 全文搜索
 		if searchMode == "content" && r.dao.BleveMgr.IsEnabled() {
-			count, err = r.searchFTSCount(uid, vaultID, keyword, isRecycle)
+			_, ftsSpan := tracer.Start(ctx, "NoteRepository.searchFTSCount", tracer.UID(uid), tracer.VaultID(vaultID))
+			count, err = r.searchFTSCount(uid, vaultID, keyword, isRecycle, excludePrefixes)
+			tracer.End(ftsSpan, err)
 		} else {
 			// 路径搜索：使用 LIKE
 			key := "%" + keyword + "%"
-			err = q.UnderlyingDB().Where("path LIKE ?", key).Count(&count).Error
+			err = excludePathPrefixes(q.UnderlyingDB().Where("path LIKE ?", key), excludePrefixes).Count(&count).Error
 		}
 	} else {
 		count, err = q.Order(u.CreatedAt).Count()
@@ -819,12 +1081,14 @@ func (r *noteRepository) ListCount(ctx context.Context, vaultID, uid int64, keyw
 // ListByUpdatedTimestamp retrieves note list by updated timestamp
 // ListByUpdatedTimestamp 根据更新时间戳获取笔记列表
 func (r *noteRepository) ListByUpdatedTimestamp(ctx context.Context, timestamp, vaultID, uid int64) ([]*domain.Note, error) {
+	r.flushPendingWrites(ctx, uid)
 	return r.ListByUpdatedTimestampPage(ctx, timestamp, vaultID, uid, 0, 0)
 }
 
 // ListByUpdatedTimestampPage retrieves note list by updated timestamp by page
 // ListByUpdatedTimestampPage 根据更新时间戳分页获取笔记列表
 func (r *noteRepository) ListByUpdatedTimestampPage(ctx context.Context, timestamp, vaultID, uid int64, offset, limit int) ([]*domain.Note, error) {
+	r.flushPendingWrites(ctx, uid)
 	u := r.note(uid).Note
 	query := u.WithContext(ctx).Where(
 		u.VaultID.Eq(vaultID),
@@ -855,6 +1119,79 @@ func (r *noteRepository) ListByUpdatedTimestampPage(ctx context.Context, timesta
 	return list, nil
 }
 
+// regexSearchBatchSize is the number of note rows loaded per page while scanning content for a regex search
+// regexSearchBatchSize 是正则搜索扫描正文时每页加载的笔记行数
+const regexSearchBatchSize = 200
+
+// ListByContentRegex streams note content files in list order and returns the notes whose content
+// matches query.Pattern. Notes larger than MaxFileSize are skipped; the scan stops early (truncated)
+// once MaxResults matches are found or Timeout elapses.
+// ListByContentRegex 按列表排序逐条读取正文文件并返回正文匹配 query.Pattern 的笔记。
+// 超过 MaxFileSize 的笔记跳过；命中数达到 MaxResults 或超过 Timeout 时提前结束（truncated）。
+func (r *noteRepository) ListByContentRegex(ctx context.Context, vaultID, uid int64, query *domain.NoteRegexQuery) ([]*domain.NoteRegexMatch, bool, error) {
+	r.flushPendingWrites(ctx, uid)
+	ctx, span := tracer.Start(ctx, "NoteRepository.ListByContentRegex", tracer.UID(uid), tracer.VaultID(vaultID))
+	defer span.End()
+
+	var deadline time.Time
+	if query.Timeout > 0 {
+		deadline = time.Now().Add(query.Timeout)
+	}
+
+	var matches []*domain.NoteRegexMatch
+	for offset := 0; ; offset += regexSearchBatchSize {
+		u := r.note(uid).Note
+		q := u.WithContext(ctx).Where(u.VaultID.Eq(vaultID))
+		if query.IsRecycle {
+			q = q.Where(u.Action.Eq("delete"), u.Rename.Eq(0))
+		} else {
+			q = q.Where(u.Action.Neq("delete"))
+		}
+
+		var batch []*model.Note
+		err := excludePathPrefixes(q.UnderlyingDB(), query.ExcludePrefixes).
+			Order(buildOrderClause(query.SortBy, query.SortOrder)).
+			Limit(regexSearchBatchSize).
+			Offset(offset).
+			Find(&batch).Error
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, m := range batch {
+			if err := ctx.Err(); err != nil {
+				return nil, false, err
+			}
+			if !deadline.IsZero() && time.Now().After(deadline) {
+				return matches, true, nil
+			}
+			if query.MaxFileSize > 0 && m.Size > query.MaxFileSize {
+				continue
+			}
+
+			content, exists, err := r.dao.LoadContentFromFile(r.dao.GetNoteFolderPath(uid, m.ID), "content.txt")
+			if err != nil || !exists {
+				continue
+			}
+			count := len(query.Pattern.FindAllStringIndex(content, -1))
+			if count == 0 {
+				continue
+			}
+
+			note := r.toDomainMeta(m)
+			note.Content = content
+			matches = append(matches, &domain.NoteRegexMatch{Note: note, MatchCount: count})
+			if query.MaxResults > 0 && len(matches) >= query.MaxResults {
+				return matches, true, nil
+			}
+		}
+
+		if len(batch) < regexSearchBatchSize {
+			return matches, false, nil
+		}
+	}
+}
+
 // ListByPathHashesMeta retrieves note metadata (no content) for a batch of path hashes in a
 // single query, including all statuses (e.g. soft-deleted). Used for batch existence
 // pre-checks (e.g. before deleting a batch of client-reported notes) to avoid N+1
@@ -862,6 +1199,7 @@ func (r *noteRepository) ListByUpdatedTimestampPage(ctx context.Context, timesta
 // ListByPathHashesMeta 单次查询批量获取一组路径哈希对应的笔记元数据（不读正文），包含所有状态
 // （含已软删除）。用于批量存在性预检查（例如批量处理客户端删除上报前），避免逐条查询的 N+1。
 func (r *noteRepository) ListByPathHashesMeta(ctx context.Context, pathHashes []string, vaultID, uid int64) (map[string]*domain.Note, error) {
+	r.flushPendingWrites(ctx, uid)
 	result := make(map[string]*domain.Note, len(pathHashes))
 	if len(pathHashes) == 0 {
 		return result, nil
@@ -895,12 +1233,14 @@ func (r *noteRepository) ListByPathHashesMeta(ctx context.Context, pathHashes []
 // （content.txt/snapshot.txt）。用于同步下发的差量比对路径——该路径只需要 ContentHash/Mtime
 // 做比对，真正需要下发的少数笔记再按需读取正文。
 func (r *noteRepository) ListByUpdatedTimestampMeta(ctx context.Context, timestamp, vaultID, uid int64) ([]*domain.Note, error) {
+	r.flushPendingWrites(ctx, uid)
 	return r.ListByUpdatedTimestampPageMeta(ctx, timestamp, vaultID, uid, 0, 0)
 }
 
 // ListByUpdatedTimestampPageMeta is the paged variant of ListByUpdatedTimestampMeta.
 // ListByUpdatedTimestampPageMeta 是 ListByUpdatedTimestampMeta 的分页变体。
 func (r *noteRepository) ListByUpdatedTimestampPageMeta(ctx context.Context, timestamp, vaultID, uid int64, offset, limit int) ([]*domain.Note, error) {
+	r.flushPendingWrites(ctx, uid)
 	u := r.note(uid).Note
 	query := u.WithContext(ctx).Where(
 		u.VaultID.Eq(vaultID),
@@ -929,6 +1269,7 @@ func (r *noteRepository) ListByUpdatedTimestampPageMeta(ctx context.Context, tim
 // ListContentUnchanged retrieves note list with unchanged content
 // ListContentUnchanged 获取内容未变更的笔记列表
 func (r *noteRepository) ListContentUnchanged(ctx context.Context, uid int64) ([]*domain.Note, error) {
+	r.flushPendingWrites(ctx, uid)
 	u := r.note(uid).Note
 	var mList []*model.Note
 
@@ -955,6 +1296,7 @@ func (r *noteRepository) ListContentUnchanged(ctx context.Context, uid int64) ([
 
 // CountSizeSum 获取笔记数量和大小总和
 func (r *noteRepository) CountSizeSum(ctx context.Context, vaultID, uid int64) (*domain.CountSizeResult, error) {
+	r.flushPendingWrites(ctx, uid)
 	u := r.note(uid).Note
 
 	result := &struct {
@@ -980,6 +1322,7 @@ func (r *noteRepository) CountSizeSum(ctx context.Context, vaultID, uid int64) (
 
 // ListByFID 根据文件夹ID获取笔记列表
 func (r *noteRepository) ListByFID(ctx context.Context, fid, vaultID, uid int64, page, pageSize int, sortBy, sortOrder string) ([]*domain.Note, error) {
+	r.flushPendingWrites(ctx, uid)
 	u := r.note(uid).Note
 	q := u.WithContext(ctx).Where(
 		u.VaultID.Eq(vaultID),
@@ -1015,6 +1358,7 @@ func (r *noteRepository) ListByFID(ctx context.Context, fid, vaultID, uid int64,
 
 // ListByFIDCount 根据文件夹ID获取笔记数量
 func (r *noteRepository) ListByFIDCount(ctx context.Context, fid, vaultID, uid int64) (int64, error) {
+	r.flushPendingWrites(ctx, uid)
 	u := r.note(uid).Note
 	q := u.WithContext(ctx).Where(
 		u.VaultID.Eq(vaultID),
@@ -1026,6 +1370,7 @@ func (r *noteRepository) ListByFIDCount(ctx context.Context, fid, vaultID, uid i
 }
 
 func (r *noteRepository) ListByFIDs(ctx context.Context, fids []int64, vaultID, uid int64, page, pageSize int, sortBy, sortOrder string) ([]*domain.Note, error) {
+	r.flushPendingWrites(ctx, uid)
 	u := r.note(uid).Note
 	q := u.WithContext(ctx).Where(
 		u.VaultID.Eq(vaultID),
@@ -1059,6 +1404,7 @@ func (r *noteRepository) ListByFIDs(ctx context.Context, fids []int64, vaultID,
 }
 
 func (r *noteRepository) ListByFIDsCount(ctx context.Context, fids []int64, vaultID, uid int64) (int64, error) {
+	r.flushPendingWrites(ctx, uid)
 	u := r.note(uid).Note
 	q := u.WithContext(ctx).Where(
 		u.VaultID.Eq(vaultID),
@@ -1074,6 +1420,7 @@ func (r *noteRepository) ListByFIDsCount(ctx context.Context, fids []int64, vaul
 // CountByFIDs groups by folder ID and returns note counts for all given fids in a single
 // query (replaces calling ListByFIDCount once per folder, which is N+1).
 func (r *noteRepository) CountByFIDs(ctx context.Context, fids []int64, vaultID, uid int64) (map[int64]int64, error) {
+	r.flushPendingWrites(ctx, uid)
 	result := make(map[int64]int64, len(fids))
 	if len(fids) == 0 {
 		return result, nil
@@ -1106,6 +1453,8 @@ func (r *noteRepository) CountByFIDs(ctx context.Context, fids []int64, vaultID,
 
 // RecycleClear 清理回收站
 func (r *noteRepository) RecycleClear(ctx context.Context, path, pathHash string, vaultID, uid int64) error {
+	defer r.dao.lookups.invalidateNotes(uid)
+	r.flushPendingWrites(ctx, uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := r.note(uid).Note
 		q := u.WithContext(ctx).Where(u.VaultID.Eq(vaultID), u.Action.Eq(string(domain.NoteActionDelete)), u.Rename.Eq(0))
@@ -1126,6 +1475,8 @@ func (r *noteRepository) RecycleClear(ctx context.Context, path, pathHash string
 // Only updates the folder ID (FID) without touching updated_timestamp
 // Used by SyncResourceFID to avoid polluting incremental sync timestamps
 func (r *noteRepository) UpdateFID(ctx context.Context, id, fid, uid int64) error {
+	defer r.dao.lookups.invalidateNoteID(uid, id)
+	r.flushPendingWrites(ctx, uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := r.note(uid).Note
 		_, err := u.WithContext(ctx).Where(u.ID.Eq(id)).UpdateSimple(u.FID.Value(fid))
@@ -1170,7 +1521,7 @@ func (r *noteRepository) deleteFTS(noteID, vaultID, uid int64) {
 
 // searchFTS uses Bleve to search note IDs, returning matching ID slice
 // searchFTS 使用 Bleve 搜索内容，返回匹配的 note_id 列表
-func (r *noteRepository) searchFTS(uid, vaultID int64, keyword string, isRecycle bool, sortBy, sortOrder string, limit, offset int) ([]int64, error) {
+func (r *noteRepository) searchFTS(uid, vaultID int64, keyword string, isRecycle bool, sortBy, sortOrder string, limit, offset int, excludePrefixes []string) ([]int64, error) {
 	index, err := r.dao.BleveMgr.GetIndex(uid, vaultID)
 	if err != nil {
 		return nil, err
@@ -1182,7 +1533,7 @@ func (r *noteRepository) searchFTS(uid, vaultID int64, keyword string, isRecycle
 	if res, err := index.DocCount(); err == nil {
 		docCount = res
 	}
-	if docCount == 0 {
+	if docCount == 0 && !r.dao.BleveMgr.IsRebuilding(uid, vaultID) && !r.dao.BleveMgr.ReindexPending(uid) {
 		_ = r.RebuildVaultIndex(context.Background(), uid, vaultID)
 		if idxNew, err := r.dao.BleveMgr.GetIndex(uid, vaultID); err == nil {
 			index = idxNew
@@ -1215,10 +1566,12 @@ func (r *noteRepository) searchFTS(uid, vaultID int64, keyword string, isRecycle
 	pathQuery := bleve.NewMatchQuery(keyword)
 	pathQuery.SetField("path")
 	pathQuery.Operator = bleveQuery.MatchQueryOperatorAnd
+	pathQuery.Analyzer = bleveCJKQueryAnalyzer
 
 	contentQuery := bleve.NewMatchQuery(keyword)
 	contentQuery.SetField("content")
 	contentQuery.Operator = bleveQuery.MatchQueryOperatorAnd
+	contentQuery.Analyzer = bleveCJKQueryAnalyzer
 
 	query := bleve.NewConjunctionQuery(
 		bleve.NewDisjunctionQuery(
@@ -1227,6 +1580,9 @@ func (r *noteRepository) searchFTS(uid, vaultID int64, keyword string, isRecycle
 		),
 		actionQuery,
 	)
+	if len(excludePrefixes) > 0 {
+		query.AddQuery(excludePathPrefixQuery(excludePrefixes))
+	}
 
 	req := bleve.NewSearchRequest(query)
 	req.Size = limit
@@ -1275,9 +1631,21 @@ func (r *noteRepository) searchFTS(uid, vaultID int64, keyword string, isRecycle
 	return noteIDs, nil
 }
 
+// excludePathPrefixQuery matches notes outside all given folders (paths without surrounding slashes)
+// excludePathPrefixQuery 匹配不在任何给定目录（不含首尾斜杠）下的笔记
+func excludePathPrefixQuery(prefixes []string) bleveQuery.Query {
+	boolQuery := bleve.NewBooleanQuery()
+	for _, prefix := range prefixes {
+		prefixQuery := bleve.NewPrefixQuery(prefix + "/")
+		prefixQuery.SetField("path_raw")
+		boolQuery.AddMustNot(prefixQuery)
+	}
+	return boolQuery
+}
+
 // searchFTSCount returns search matches count
 // searchFTSCount 返回全文搜索匹配计数
-func (r *noteRepository) searchFTSCount(uid, vaultID int64, keyword string, isRecycle bool) (int64, error) {
+func (r *noteRepository) searchFTSCount(uid, vaultID int64, keyword string, isRecycle bool, excludePrefixes []string) (int64, error) {
 	index, err := r.dao.BleveMgr.GetIndex(uid, vaultID)
 	if err != nil {
 		return 0, err
@@ -1308,10 +1676,12 @@ func (r *noteRepository) searchFTSCount(uid, vaultID int64, keyword string, isRe
 	pathQuery := bleve.NewMatchQuery(keyword)
 	pathQuery.SetField("path")
 	pathQuery.Operator = bleveQuery.MatchQueryOperatorAnd
+	pathQuery.Analyzer = bleveCJKQueryAnalyzer
 
 	contentQuery := bleve.NewMatchQuery(keyword)
 	contentQuery.SetField("content")
 	contentQuery.Operator = bleveQuery.MatchQueryOperatorAnd
+	contentQuery.Analyzer = bleveCJKQueryAnalyzer
 
 	query := bleve.NewConjunctionQuery(
 		bleve.NewDisjunctionQuery(
@@ -1320,6 +1690,9 @@ func (r *noteRepository) searchFTSCount(uid, vaultID int64, keyword string, isRe
 		),
 		actionQuery,
 	)
+	if len(excludePrefixes) > 0 {
+		query.AddQuery(excludePathPrefixQuery(excludePrefixes))
+	}
 
 	req := bleve.NewSearchRequest(query)
 	req.Size = 0 // Count only // 仅计数
@@ -1334,49 +1707,15 @@ func (r *noteRepository) searchFTSCount(uid, vaultID int64, keyword string, isRe
 // RebuildVaultIndex rebuilds index from database and file contents for a specific vault
 // RebuildVaultIndex 从数据库和物理文件内容重建指定仓库的索引
 func (r *noteRepository) RebuildVaultIndex(ctx context.Context, uid, vaultID int64) error {
-	if !r.dao.BleveMgr.IsEnabled() {
-		return nil // If FTS is disabled, do nothing // 若 FTS 未启用，则不进行任何操作
-	}
-	_ = r.dao.BleveMgr.DeleteIndex(uid, vaultID)
-
-	index, err := r.dao.BleveMgr.GetIndex(uid, vaultID)
-	if err != nil {
-		return err
-	}
-
-	db := r.dao.ResolveDB(r.GetKey(uid))
-	var notes []model.Note
-	if err := db.Where("vault_id = ?", vaultID).Find(&notes).Error; err != nil {
-		return err
-	}
-
-	for _, note := range notes {
-		folder := r.dao.GetNoteFolderPath(uid, note.ID)
-		content, exists, err := r.dao.LoadContentFromFile(folder, "content.txt")
-		if err != nil || !exists {
-			content = ""
-		}
-
-		doc := BleveNoteDoc{
-			ID:      strconv.FormatInt(note.ID, 10),
-			Path:    note.Path,
-			PathRaw: note.Path,
-			Content: content,
-			Action:  note.Action,
-			Rename:  float64(note.Rename),
-			Ctime:   float64(note.Ctime),
-			Mtime:   float64(note.Mtime),
-		}
-
-		_ = index.Index(doc.ID, doc)
-	}
-
-	return nil
+	r.flushPendingWrites(ctx, uid)
+	return rebuildVaultIndex(ctx, r.dao, uid, vaultID, nil)
 }
 
 // DeleteByVaultID physically deletes all notes in a vault
 // DeleteByVaultID 物理删除仓库下的所有笔记
 func (r *noteRepository) DeleteByVaultID(ctx context.Context, vaultID, uid int64) error {
+	defer r.dao.lookups.invalidateNotes(uid)
+	r.flushPendingWrites(ctx, uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := r.note(uid).Note
 
@@ -189,7 +189,7 @@ func TestBleveFTSAllInOne(t *testing.T) {
 	// 1. Test basic search (keyword "sync")
 	// 1. 测试基础搜索（关键词 "sync"）
 	t.Run("Basic search keyword sync", func(t *testing.T) {
-		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "sync", false, "mtime", "desc", 10, 0)
+		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "sync", false, "mtime", "desc", 10, 0, nil)
 		require.NoError(t, err)
 		// ID 4 is deleted, so only ID 1 and 2 should match
 		// ID 4 已删除，所以只有 ID 1 和 2 应该匹配
@@ -201,7 +201,7 @@ func TestBleveFTSAllInOne(t *testing.T) {
 	// 2. Test sorting options
 	// 2. 测试排序选项
 	t.Run("Sorting by ctime asc", func(t *testing.T) {
-		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "guide", false, "ctime", "asc", 10, 0)
+		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "guide", false, "ctime", "asc", 10, 0, nil)
 		require.NoError(t, err)
 		// "guide" matches ID 1 (ctime 1000, "intro guide") and ID 3 (ctime 3000, "options guide")
 		// "guide" 匹配 ID 1 (ctime 1000) 和 ID 3 (ctime 3000)
@@ -211,7 +211,7 @@ func TestBleveFTSAllInOne(t *testing.T) {
 	})
 
 	t.Run("Sorting by ctime desc", func(t *testing.T) {
-		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "guide", false, "ctime", "desc", 10, 0)
+		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "guide", false, "ctime", "desc", 10, 0, nil)
 		require.NoError(t, err)
 		assert.Len(t, ids, 2)
 		assert.Equal(t, int64(3), ids[0])
@@ -219,7 +219,7 @@ func TestBleveFTSAllInOne(t *testing.T) {
 	})
 
 	t.Run("Sorting by mtime asc", func(t *testing.T) {
-		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "guide", false, "mtime", "asc", 10, 0)
+		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "guide", false, "mtime", "asc", 10, 0, nil)
 		require.NoError(t, err)
 		// ID 3 (mtime 3000) < ID 1 (mtime 5000)
 		assert.Len(t, ids, 2)
@@ -228,7 +228,7 @@ func TestBleveFTSAllInOne(t *testing.T) {
 	})
 
 	t.Run("Sorting by path asc", func(t *testing.T) {
-		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "guide", false, "path", "asc", 10, 0)
+		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "guide", false, "path", "asc", 10, 0, nil)
 		require.NoError(t, err)
 		// ID 1 ("A_intro.md") < ID 3 ("C_advanced.md")
 		assert.Len(t, ids, 2)
@@ -237,7 +237,7 @@ func TestBleveFTSAllInOne(t *testing.T) {
 	})
 
 	t.Run("Sorting by path desc", func(t *testing.T) {
-		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "guide", false, "path", "desc", 10, 0)
+		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "guide", false, "path", "desc", 10, 0, nil)
 		require.NoError(t, err)
 		assert.Len(t, ids, 2)
 		assert.Equal(t, int64(3), ids[0])
@@ -247,7 +247,7 @@ func TestBleveFTSAllInOne(t *testing.T) {
 	// 3. Test recycle bin filter
 	// 3. 测试回收站过滤
 	t.Run("Recycle bin filter isRecycle=true", func(t *testing.T) {
-		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "sync", true, "mtime", "desc", 10, 0)
+		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "sync", true, "mtime", "desc", 10, 0, nil)
 		require.NoError(t, err)
 		// Only ID 4 (action="delete", content contains "sync") should match
 		// 只有 ID 4（已删除，且内容包含 "sync"）应该匹配
@@ -264,7 +264,7 @@ func TestBleveFTSAllInOne(t *testing.T) {
 		// deleteFTS 现在是异步的，断言前强制同步刷新
 		daoInst.BleveMgr.FlushSync()
 
-		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "intro", false, "mtime", "desc", 10, 0)
+		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "intro", false, "mtime", "desc", 10, 0, nil)
 		require.NoError(t, err)
 		assert.Empty(t, ids)
 	})
@@ -294,7 +294,7 @@ func TestBleveFTSAllInOne(t *testing.T) {
 
 		// Search FTS should automatically trigger RebuildVaultIndex when DocCount is 0
 		// 当 DocCount 为 0 时，searchFTS 应该会自动触发 RebuildVaultIndex 重建
-		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "tutorial", false, "mtime", "desc", 10, 0)
+		ids, err := noteRepo.(*noteRepository).searchFTS(uid, vaultID, "tutorial", false, "mtime", "desc", 10, 0, nil)
 		require.NoError(t, err)
 		assert.Len(t, ids, 1)
 		assert.Equal(t, int64(2), ids[0])
@@ -408,7 +408,7 @@ func TestBleveFTSDisabled(t *testing.T) {
 	// 3. 验证搜索 fallback 行为或错误处理
 	// When FTS is disabled, list fallbacks to standard DB path search (searchMode="content" is ignored or falls back)
 	// We search with searchMode="content", noteRepository should not trigger Bleve search
-	results, err := noteRepo.List(ctx, vaultID, 1, 10, uid, "disable", false, "content", true, "mtime", "desc", nil)
+	results, err := noteRepo.List(ctx, vaultID, 1, 10, uid, "disable", false, "content", true, "mtime", "desc", nil, nil)
 	require.NoError(t, err)
 	assert.Empty(t, results) // Should fall back or return empty without panic
 }
@@ -0,0 +1,225 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// noteStatRepository implements domain.NoteStatRepository
+// noteStatRepository 实现 domain.NoteStatRepository 接口
+type noteStatRepository struct {
+	dao             *Dao
+	customPrefixKey string
+	migrateOnce     sync.Map // tracks per-key migration completion // 记录每个 key 是否已完成 AutoMigrate
+}
+
+// NewNoteStatRepository creates a NoteStatRepository instance
+// NewNoteStatRepository 创建 NoteStatRepository 实例
+func NewNoteStatRepository(dao *Dao) domain.NoteStatRepository {
+	return &noteStatRepository{dao: dao, customPrefixKey: "user_note_stat_"}
+}
+
+// GetKey returns the database routing key for the given user
+// GetKey 返回指定用户的数据库路由键
+func (r *noteStatRepository) GetKey(uid int64) string {
+	return r.customPrefixKey + strconv.FormatInt(uid, 10)
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name: "NoteStat",
+		RepoFactory: func(d *Dao) daoDBCustomKey {
+			return NewNoteStatRepository(d).(daoDBCustomKey)
+		},
+		IsMainDB: false,
+	})
+}
+
+// db returns the *gorm.DB of the user's note statistics database, with one-time AutoMigrate
+// db 返回用户笔记统计库的 *gorm.DB，确保每个用户库只迁移一次
+func (r *noteStatRepository) db(uid int64) *gorm.DB {
+	key := r.GetKey(uid)
+	if _, loaded := r.migrateOnce.LoadOrStore(key+"#note_stat", true); !loaded {
+		if db := r.dao.ResolveDB(key); db != nil {
+			model.AutoMigrate(db, "NoteStat")
+		}
+	}
+	return r.dao.ResolveDB(key)
+}
+
+// toModel converts the counts of a note to a row
+// toModel 将笔记统计转换为数据行
+func (r *noteStatRepository) toModel(vaultID int64, s *domain.NoteStat) *model.NoteStat {
+	return &model.NoteStat{
+		NoteID:  s.NoteID,
+		VaultID: vaultID,
+		Path:    s.Path,
+		Words:   s.Words,
+		Chars:   s.Chars,
+	}
+}
+
+// toDomain converts a row to the counts of a note
+// toDomain 将数据行转换为笔记统计
+func (r *noteStatRepository) toDomain(m *model.NoteStat) *domain.NoteStat {
+	return &domain.NoteStat{
+		NoteID:  m.NoteID,
+		VaultID: m.VaultID,
+		Path:    m.Path,
+		Words:   m.Words,
+		Chars:   m.Chars,
+	}
+}
+
+// Save implements domain.NoteStatRepository
+// Save 实现 domain.NoteStatRepository
+func (r *noteStatRepository) Save(ctx context.Context, stat *domain.NoteStat, uid int64) (*domain.NoteStat, error) {
+	var previous *domain.NoteStat
+	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var old model.NoteStat
+			err := tx.Where("note_id = ?", stat.NoteID).First(&old).Error
+			switch {
+			case err == nil:
+				previous = r.toDomain(&old)
+			case !errors.Is(err, gorm.ErrRecordNotFound):
+				return err
+			}
+			return tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "note_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"vault_id", "path", "words", "chars"}),
+			}).Create(r.toModel(stat.VaultID, stat)).Error
+		})
+	})
+	return previous, err
+}
+
+// AddDay implements domain.NoteStatRepository
+// AddDay 实现 domain.NoteStatRepository
+func (r *noteStatRepository) AddDay(ctx context.Context, day *domain.NoteStatDay, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "vault_id"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"edits":         gorm.Expr("edits + ?", day.Edits),
+				"words_added":   gorm.Expr("words_added + ?", day.WordsAdded),
+				"words_removed": gorm.Expr("words_removed + ?", day.WordsRemoved),
+			}),
+		}).Create(&model.NoteStatDay{
+			VaultID:      day.VaultID,
+			Day:          day.Day,
+			Edits:        day.Edits,
+			WordsAdded:   day.WordsAdded,
+			WordsRemoved: day.WordsRemoved,
+		}).Error
+	})
+}
+
+// IndexedUntil implements domain.NoteStatRepository
+// IndexedUntil 实现 domain.NoteStatRepository
+func (r *noteStatRepository) IndexedUntil(ctx context.Context, vaultID, uid int64) (int64, error) {
+	var state model.NoteStatState
+	err := r.db(uid).WithContext(ctx).Where("vault_id = ?", vaultID).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return state.IndexedUntil, nil
+}
+
+// Reindex implements domain.NoteStatRepository
+// Reindex 实现 domain.NoteStatRepository
+func (r *noteStatRepository) Reindex(ctx context.Context, vaultID int64, noteIDs []int64, stats []*domain.NoteStat, indexedUntil, uid int64) error {
+	rows := make([]*model.NoteStat, 0, len(stats))
+	for _, s := range stats {
+		rows = append(rows, r.toModel(vaultID, s))
+	}
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			for start := 0; start < len(noteIDs); start += 500 {
+				end := min(start+500, len(noteIDs))
+				if err := tx.Where("note_id IN ?", noteIDs[start:end]).Delete(&model.NoteStat{}).Error; err != nil {
+					return err
+				}
+			}
+			if len(rows) > 0 {
+				if err := tx.CreateInBatches(rows, 200).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "vault_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"indexed_until"}),
+			}).Create(&model.NoteStatState{VaultID: vaultID, IndexedUntil: indexedUntil}).Error
+		})
+	})
+}
+
+// ListByVault implements domain.NoteStatRepository
+// ListByVault 实现 domain.NoteStatRepository
+func (r *noteStatRepository) ListByVault(ctx context.Context, vaultID, uid int64) ([]*domain.NoteStat, error) {
+	var rows []*model.NoteStat
+	if err := r.db(uid).WithContext(ctx).Where("vault_id = ?", vaultID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	stats := make([]*domain.NoteStat, 0, len(rows))
+	for _, m := range rows {
+		stats = append(stats, r.toDomain(m))
+	}
+	return stats, nil
+}
+
+// ListDays implements domain.NoteStatRepository
+// ListDays 实现 domain.NoteStatRepository
+func (r *noteStatRepository) ListDays(ctx context.Context, vaultID int64, from string, uid int64) ([]*domain.NoteStatDay, error) {
+	var rows []*model.NoteStatDay
+	if err := r.db(uid).WithContext(ctx).Where("vault_id = ? AND day >= ?", vaultID, from).Order("day ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	days := make([]*domain.NoteStatDay, 0, len(rows))
+	for _, m := range rows {
+		days = append(days, &domain.NoteStatDay{
+			VaultID:      m.VaultID,
+			Day:          m.Day,
+			Edits:        m.Edits,
+			WordsAdded:   m.WordsAdded,
+			WordsRemoved: m.WordsRemoved,
+		})
+	}
+	return days, nil
+}
+
+// ListUserDays implements domain.NoteStatRepository
+// ListUserDays 实现 domain.NoteStatRepository
+func (r *noteStatRepository) ListUserDays(ctx context.Context, from string, uid int64) ([]*domain.NoteStatDay, error) {
+	var rows []*model.NoteStatDay
+	err := r.db(uid).WithContext(ctx).Model(&model.NoteStatDay{}).
+		Select("day, SUM(edits) AS edits, SUM(words_added) AS words_added, SUM(words_removed) AS words_removed").
+		Where("day >= ?", from).Group("day").Order("day ASC").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	days := make([]*domain.NoteStatDay, 0, len(rows))
+	for _, m := range rows {
+		days = append(days, &domain.NoteStatDay{
+			Day:          m.Day,
+			Edits:        m.Edits,
+			WordsAdded:   m.WordsAdded,
+			WordsRemoved: m.WordsRemoved,
+		})
+	}
+	return days, nil
+}
+
+// Ensure noteStatRepository implements domain.NoteStatRepository
+// 确保 noteStatRepository 实现了 domain.NoteStatRepository 接口
+var _ domain.NoteStatRepository = (*noteStatRepository)(nil)
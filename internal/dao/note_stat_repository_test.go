@@ -0,0 +1,61 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNoteStatRepository verifies saving returns the previous counts, reindexing replaces notes and
+// advances the indexed timestamp, and day totals accumulate.
+// TestNoteStatRepository 验证保存时返回之前的统计，重新统计会替换笔记并推进已统计时间戳，每日合计会累加。
+func TestNoteStatRepository(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(7)
+	repo := NewNoteStatRepository(daoInst)
+
+	previous, err := repo.Save(ctx, &domain.NoteStat{NoteID: 1, VaultID: vaultID, Path: "a.md", Words: 10, Chars: 40}, uid)
+	require.NoError(t, err)
+	assert.Nil(t, previous)
+	previous, err = repo.Save(ctx, &domain.NoteStat{NoteID: 1, VaultID: vaultID, Path: "a.md", Words: 12, Chars: 50}, uid)
+	require.NoError(t, err)
+	require.NotNil(t, previous)
+	assert.Equal(t, int64(10), previous.Words)
+
+	require.NoError(t, repo.Reindex(ctx, vaultID, []int64{1, 2}, []*domain.NoteStat{{NoteID: 2, Path: "b.md", Words: 3, Chars: 9}}, 100, uid))
+	until, err := repo.IndexedUntil(ctx, vaultID, uid)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), until)
+
+	stats, err := repo.ListByVault(ctx, vaultID, uid)
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, "b.md", stats[0].Path)
+	assert.Equal(t, int64(3), stats[0].Words)
+
+	require.NoError(t, repo.AddDay(ctx, &domain.NoteStatDay{VaultID: vaultID, Day: "2024-01-01", Edits: 1, WordsAdded: 5}, uid))
+	require.NoError(t, repo.AddDay(ctx, &domain.NoteStatDay{VaultID: vaultID, Day: "2024-01-02", Edits: 1, WordsAdded: 2, WordsRemoved: 1}, uid))
+	require.NoError(t, repo.AddDay(ctx, &domain.NoteStatDay{VaultID: vaultID, Day: "2024-01-02", Edits: 1, WordsRemoved: 4}, uid))
+
+	days, err := repo.ListDays(ctx, vaultID, "2024-01-02", uid)
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	assert.Equal(t, int64(2), days[0].Edits)
+	assert.Equal(t, int64(2), days[0].WordsAdded)
+	assert.Equal(t, int64(5), days[0].WordsRemoved)
+
+	// Days of all vaults are summed for the user
+	// 用户全部保险库的每日统计会被合计
+	require.NoError(t, repo.AddDay(ctx, &domain.NoteStatDay{VaultID: 8, Day: "2024-01-02", Edits: 3}, uid))
+	days, err = repo.ListUserDays(ctx, "2024-01-01", uid)
+	require.NoError(t, err)
+	require.Len(t, days, 2)
+	assert.Equal(t, int64(1), days[0].Edits)
+	assert.Equal(t, int64(5), days[1].Edits)
+}
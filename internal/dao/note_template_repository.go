@@ -0,0 +1,208 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// noteTemplateRepository implements domain.NoteTemplateRepository
+// noteTemplateRepository 实现 domain.NoteTemplateRepository 接口
+type noteTemplateRepository struct {
+	dao             *Dao
+	customPrefixKey string
+	migrateOnce     sync.Map // tracks per-key migration completion // 记录每个 key 是否已完成 AutoMigrate
+}
+
+// NewNoteTemplateRepository creates a NoteTemplateRepository instance
+// NewNoteTemplateRepository 创建 NoteTemplateRepository 实例
+func NewNoteTemplateRepository(dao *Dao) domain.NoteTemplateRepository {
+	return &noteTemplateRepository{dao: dao, customPrefixKey: "user_note_template_"}
+}
+
+// GetKey returns the database routing key for the given user
+// GetKey 返回指定用户的数据库路由键
+func (r *noteTemplateRepository) GetKey(uid int64) string {
+	return r.customPrefixKey + strconv.FormatInt(uid, 10)
+}
+
+func init() {
+	for _, name := range []string{"NoteTemplate", "NoteDailySetting"} {
+		RegisterModel(ModelConfig{
+			Name: name,
+			RepoFactory: func(d *Dao) daoDBCustomKey {
+				return NewNoteTemplateRepository(d).(daoDBCustomKey)
+			},
+			IsMainDB: false,
+		})
+	}
+}
+
+// db returns the *gorm.DB of the user's template database, with one-time AutoMigrate
+// db 返回用户模板库的 *gorm.DB，确保每个用户库只迁移一次
+func (r *noteTemplateRepository) db(uid int64) *gorm.DB {
+	key := r.GetKey(uid)
+	if _, loaded := r.migrateOnce.LoadOrStore(key+"#note_template", true); !loaded {
+		if db := r.dao.ResolveDB(key); db != nil {
+			model.AutoMigrate(db, "NoteTemplate")
+			model.AutoMigrate(db, "NoteDailySetting")
+		}
+	}
+	return r.dao.ResolveDB(key)
+}
+
+// noteTemplateToDomain converts the database model to the domain model
+// noteTemplateToDomain 将数据库模型转换为领域模型
+func noteTemplateToDomain(m *model.NoteTemplate) *domain.NoteTemplate {
+	return &domain.NoteTemplate{
+		ID:          m.ID,
+		UID:         m.UID,
+		Name:        m.Name,
+		Description: m.Description,
+		Content:     m.Content,
+		CreatedAt:   time.Time(m.CreatedAt),
+		UpdatedAt:   time.Time(m.UpdatedAt),
+	}
+}
+
+// first returns the first template matching the conditions, nil when there is none
+// first 返回满足条件的第一个模板，不存在时返回 nil
+func (r *noteTemplateRepository) first(ctx context.Context, uid int64, query string, args ...any) (*domain.NoteTemplate, error) {
+	var m model.NoteTemplate
+	err := r.db(uid).WithContext(ctx).Where(query, args...).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return noteTemplateToDomain(&m), nil
+}
+
+// List lists all templates of a user
+// List 列出用户的全部模板
+func (r *noteTemplateRepository) List(ctx context.Context, uid int64) ([]*domain.NoteTemplate, error) {
+	var rows []*model.NoteTemplate
+	if err := r.db(uid).WithContext(ctx).Where("uid = ?", uid).Order("name ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	results := make([]*domain.NoteTemplate, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, noteTemplateToDomain(m))
+	}
+	return results, nil
+}
+
+// Get returns a template, nil when it does not exist
+// Get 获取模板，不存在时返回 nil
+func (r *noteTemplateRepository) Get(ctx context.Context, id, uid int64) (*domain.NoteTemplate, error) {
+	return r.first(ctx, uid, "id = ? AND uid = ?", id, uid)
+}
+
+// GetByName returns a template by name, nil when it does not exist
+// GetByName 按名称获取模板，不存在时返回 nil
+func (r *noteTemplateRepository) GetByName(ctx context.Context, name string, uid int64) (*domain.NoteTemplate, error) {
+	return r.first(ctx, uid, "name = ? AND uid = ?", name, uid)
+}
+
+// Save creates the template when ID is 0, otherwise updates it
+// Save ID 为 0 时新建模板，否则更新
+func (r *noteTemplateRepository) Save(ctx context.Context, template *domain.NoteTemplate, uid int64) (*domain.NoteTemplate, error) {
+	var result *domain.NoteTemplate
+	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		m := &model.NoteTemplate{
+			ID:          template.ID,
+			UID:         uid,
+			Name:        template.Name,
+			Description: template.Description,
+			Content:     template.Content,
+			CreatedAt:   timex.Time(template.CreatedAt),
+			UpdatedAt:   timex.Now(),
+		}
+		if m.ID == 0 {
+			m.CreatedAt = m.UpdatedAt
+			if err := r.db(uid).WithContext(ctx).Create(m).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := r.db(uid).WithContext(ctx).Where("id = ? AND uid = ?", m.ID, uid).
+				Select("name", "description", "content", "updated_at").
+				Updates(m).Error; err != nil {
+				return err
+			}
+		}
+		result = noteTemplateToDomain(m)
+		return nil
+	})
+	return result, err
+}
+
+// Delete removes a template
+// Delete 删除模板
+func (r *noteTemplateRepository) Delete(ctx context.Context, id, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Where("id = ? AND uid = ?", id, uid).Delete(&model.NoteTemplate{}).Error
+	})
+}
+
+// GetDailySetting returns the daily note setting of a user, nil when it was never saved
+// GetDailySetting 获取用户的每日笔记设置，从未保存时返回 nil
+func (r *noteTemplateRepository) GetDailySetting(ctx context.Context, uid int64) (*domain.NoteDailySetting, error) {
+	var m model.NoteDailySetting
+	err := r.db(uid).WithContext(ctx).Where("uid = ?", uid).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain.NoteDailySetting{
+		UID:       m.UID,
+		Pattern:   m.Pattern,
+		Template:  m.Template,
+		Timezone:  m.Timezone,
+		UpdatedAt: time.Time(m.UpdatedAt),
+	}, nil
+}
+
+// SaveDailySetting creates or replaces the daily note setting of a user
+// SaveDailySetting 新建或替换用户的每日笔记设置
+func (r *noteTemplateRepository) SaveDailySetting(ctx context.Context, setting *domain.NoteDailySetting, uid int64) (*domain.NoteDailySetting, error) {
+	var result *domain.NoteDailySetting
+	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		m := &model.NoteDailySetting{
+			UID:       uid,
+			Pattern:   setting.Pattern,
+			Template:  setting.Template,
+			Timezone:  setting.Timezone,
+			UpdatedAt: timex.Now(),
+		}
+		if err := r.db(uid).WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "uid"}},
+			DoUpdates: clause.AssignmentColumns([]string{"pattern", "template", "timezone", "updated_at"}),
+		}).Create(m).Error; err != nil {
+			return err
+		}
+		result = &domain.NoteDailySetting{
+			UID:       uid,
+			Pattern:   m.Pattern,
+			Template:  m.Template,
+			Timezone:  m.Timezone,
+			UpdatedAt: time.Time(m.UpdatedAt),
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Ensure noteTemplateRepository implements domain.NoteTemplateRepository
+// 确保 noteTemplateRepository 实现了 domain.NoteTemplateRepository 接口
+var _ domain.NoteTemplateRepository = (*noteTemplateRepository)(nil)
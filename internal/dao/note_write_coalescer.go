@@ -0,0 +1,270 @@
+package dao
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// noteWriteMaxFlushAttempts is how many times a buffered update is written before it is given up
+// noteWriteMaxFlushAttempts 缓冲的更新写入失败多少次后放弃
+const noteWriteMaxFlushAttempts = 5
+
+// noteWriteKey identifies a note across tenants
+// noteWriteKey 跨租户标识一条笔记
+type noteWriteKey struct {
+	uid    int64
+	noteID int64
+}
+
+// noteWriteEntry tracks a note that was written recently
+// base is the row state of the last landed write; nil while a write that may change the path is in flight.
+// pending is the newest buffered update; every Update writes the full row and content, so it supersedes older ones.
+// A pending update whose write failed stays buffered and is retried on the next window, flush or shutdown.
+// noteWriteEntry 记录最近写过的笔记
+// base 为最近一次落盘后的行状态；可能改变路径的写入进行中时为 nil。
+// pending 为最新缓冲的更新；每次 Update 都写入整行与正文，因此新的更新会覆盖旧的。
+// 写入失败的缓冲更新继续保留，在下一个窗口期、刷出或关闭时重试。
+type noteWriteEntry struct {
+	writeMu sync.Mutex // serializes writes of this note // 串行化该笔记的写入
+
+	repo     *noteRepository
+	base     *model.Note
+	pending  *model.Note
+	content  string
+	failures int // failed writes of the pending update // 缓冲更新的写入失败次数
+	timer    *time.Timer
+}
+
+// noteWriteCoalescer merges rapid successive Update calls on the same note (e.g. keystroke sync)
+// The first update of a note is written through; updates arriving within the window afterwards only
+// replace the buffered state, which is written once when the window elapses: one row update, one
+// content file write and one FTS upsert instead of one per keystroke.
+// Only updates that keep the path, vault, action, rename flag and folder of the landed row are buffered,
+// so point lookups stay correct by overlaying the buffered state; list, sync and other write paths
+// flush the user's buffered updates first.
+// noteWriteCoalescer 合并同一笔记在短时间内的连续 Update（如逐键同步）
+// 笔记的第一次更新直接落盘；窗口期内随后的更新只替换缓冲状态，窗口结束时统一写入一次：
+// 一次行更新、一次正文文件写入和一次 FTS 更新，而不是每次按键各一次。
+// 仅缓冲与已落盘行的路径、仓库、动作、重命名标记和文件夹一致的更新，
+// 因此单条查询通过叠加缓冲状态保持正确；列表、同步及其他写入路径会先刷出该用户的缓冲更新。
+type noteWriteCoalescer struct {
+	window time.Duration
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	entries map[noteWriteKey]*noteWriteEntry
+}
+
+// newNoteWriteCoalescer creates a coalescer with the given window
+// newNoteWriteCoalescer 创建指定窗口期的写入合并器
+func newNoteWriteCoalescer(window time.Duration, log *zap.Logger) *noteWriteCoalescer {
+	return &noteWriteCoalescer{
+		window:  window,
+		logger:  log,
+		entries: make(map[noteWriteKey]*noteWriteEntry),
+	}
+}
+
+// sameRow reports whether m keeps every column the lookups and sync filters depend on
+// sameRow 判断 m 是否保留了查询与同步过滤所依赖的全部列
+func sameRow(base, m *model.Note) bool {
+	return base.VaultID == m.VaultID &&
+		base.Path == m.Path &&
+		base.PathHash == m.PathHash &&
+		base.Action == m.Action &&
+		base.Rename == m.Rename &&
+		base.FID == m.FID
+}
+
+// update buffers m when its note was written within the window, otherwise writes it through
+// update 笔记在窗口期内写过时缓冲 m，否则直接写入
+func (c *noteWriteCoalescer) update(ctx context.Context, r *noteRepository, uid int64, m *model.Note, content string) (*domain.Note, error) {
+	key := noteWriteKey{uid: uid, noteID: m.ID}
+
+	c.mu.Lock()
+	e := c.entries[key]
+	if e != nil && e.base != nil && sameRow(e.base, m) {
+		result := r.pendingToDomain(m, content)
+		e.repo = r
+		e.pending = m
+		e.content = content
+		e.failures = 0
+		c.mu.Unlock()
+		return result, nil
+	}
+	if e == nil {
+		e = &noteWriteEntry{}
+		e.timer = time.AfterFunc(c.window, func() { c.fire(key, e) })
+		c.entries[key] = e
+	}
+	e.repo = r
+	e.base = nil
+	c.mu.Unlock()
+
+	e.writeMu.Lock()
+	defer e.writeMu.Unlock()
+
+	// The write-through update supersedes anything still buffered
+	// 直接写入的更新会覆盖仍在缓冲中的状态
+	c.mu.Lock()
+	e.pending = nil
+	e.failures = 0
+	c.mu.Unlock()
+
+	result, err := r.writeUpdate(ctx, m, content, uid)
+
+	c.mu.Lock()
+	if err == nil {
+		e.base = m
+	}
+	e.timer.Reset(c.window)
+	c.mu.Unlock()
+	return result, err
+}
+
+// fire writes the buffered update when the window elapses, and forgets the note once it goes quiet
+// fire 在窗口期结束时写入缓冲的更新，笔记安静下来后将其移除
+func (c *noteWriteCoalescer) fire(key noteWriteKey, e *noteWriteEntry) {
+	e.writeMu.Lock()
+	defer e.writeMu.Unlock()
+
+	if c.flushEntry(context.Background(), key, e) {
+		c.mu.Lock()
+		e.timer.Reset(c.window)
+		c.mu.Unlock()
+		return
+	}
+
+	c.mu.Lock()
+	if e.pending == nil && c.entries[key] == e {
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+}
+
+// flushEntry writes the buffered update of e, if any; the caller must hold e.writeMu
+// A failed write keeps the update buffered unless a newer one replaced it meanwhile, it is dropped
+// only after noteWriteMaxFlushAttempts failures.
+// flushEntry 写入 e 的缓冲更新（如有）；调用方须持有 e.writeMu
+// 写入失败时保留该更新（期间已被更新的缓冲替换时除外），连续失败 noteWriteMaxFlushAttempts 次后才丢弃。
+func (c *noteWriteCoalescer) flushEntry(ctx context.Context, key noteWriteKey, e *noteWriteEntry) bool {
+	c.mu.Lock()
+	m, content, r := e.pending, e.content, e.repo
+	e.pending = nil
+	c.mu.Unlock()
+	if m == nil {
+		return false
+	}
+
+	if _, err := r.writeUpdate(ctx, m, content, key.uid); err != nil {
+		c.mu.Lock()
+		if e.pending == nil {
+			e.failures++
+			if e.failures < noteWriteMaxFlushAttempts {
+				e.pending, e.content = m, content
+			}
+		}
+		failures, kept := e.failures, e.pending != nil
+		if !kept {
+			e.failures = 0
+		}
+		c.mu.Unlock()
+
+		fields := []zap.Field{
+			zap.Int64(logger.FieldUID, key.uid),
+			zap.Int64("noteId", key.noteID),
+			zap.Int("failures", failures),
+			zap.String(logger.FieldMethod, "noteWriteCoalescer.flushEntry"),
+			zap.Error(err),
+		}
+		if kept {
+			c.logger.Warn("note write coalescer: flush failed, will retry", fields...)
+		} else {
+			c.logger.Error("note write coalescer: flush failed, update dropped", fields...)
+		}
+		return true
+	}
+
+	c.mu.Lock()
+	e.base = m
+	if e.pending == nil {
+		e.failures = 0
+	}
+	c.mu.Unlock()
+	return true
+}
+
+// flushUser writes every buffered update of the user (uid 0 flushes all users)
+// flushUser 写入该用户全部缓冲的更新（uid 为 0 时刷出所有用户）
+func (c *noteWriteCoalescer) flushUser(ctx context.Context, uid int64) {
+	type target struct {
+		key   noteWriteKey
+		entry *noteWriteEntry
+	}
+	var targets []target
+
+	c.mu.Lock()
+	for key, e := range c.entries {
+		if (uid == 0 || key.uid == uid) && e.pending != nil {
+			targets = append(targets, target{key: key, entry: e})
+		}
+	}
+	c.mu.Unlock()
+
+	for _, t := range targets {
+		t.entry.writeMu.Lock()
+		c.flushEntry(ctx, t.key, t.entry)
+		t.entry.writeMu.Unlock()
+	}
+}
+
+// overlay applies the buffered update of the note onto n, if any
+// overlay 将笔记缓冲中的更新叠加到 n 上（如有）
+func (c *noteWriteCoalescer) overlay(uid int64, n *domain.Note) {
+	if n == nil {
+		return
+	}
+	c.mu.Lock()
+	e := c.entries[noteWriteKey{uid: uid, noteID: n.ID}]
+	var m *model.Note
+	var content string
+	if e != nil && e.pending != nil {
+		m, content = e.pending, e.content
+	}
+	c.mu.Unlock()
+	if m == nil {
+		return
+	}
+
+	n.Content = content
+	n.ContentHash = m.ContentHash
+	n.ClientName = m.ClientName
+	n.ClientType = m.ClientType
+	n.ClientVersion = m.ClientVersion
+	n.Size = m.Size
+	n.Ctime = m.Ctime
+	n.Mtime = m.Mtime
+	n.Version = m.Version
+	n.UpdatedTimestamp = m.UpdatedTimestamp
+	n.UpdatedAt = time.Time(m.UpdatedAt)
+}
+
+// pendingCount reports how many notes have a buffered update
+// pendingCount 返回存在缓冲更新的笔记数量
+func (c *noteWriteCoalescer) pendingCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, e := range c.entries {
+		if e.pending != nil {
+			n++
+		}
+	}
+	return n
+}
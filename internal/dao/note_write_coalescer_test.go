@@ -0,0 +1,164 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// setupNoteWriteCoalescerTest creates a repository whose updates are coalesced within window
+// setupNoteWriteCoalescerTest 创建在窗口期内合并更新的笔记仓库
+func setupNoteWriteCoalescerTest(t *testing.T, window time.Duration) (*noteRepository, func()) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	daoInst.BleveMgr = NewBleveManager(util.Ptr(false), util.Ptr(false), zap.NewNop())
+	daoInst.noteWrites = newNoteWriteCoalescer(window, zap.NewNop())
+	return NewNoteRepository(daoInst).(*noteRepository), cleanup
+}
+
+// storedContent reads the content file that has actually landed on disk
+// storedContent 读取实际落盘的正文文件
+func storedContent(t *testing.T, r *noteRepository, uid, id int64) string {
+	content, _, err := r.dao.LoadContentFromFile(r.dao.GetNoteFolderPath(uid, id), "content.txt")
+	require.NoError(t, err)
+	return content
+}
+
+// TestNoteWriteCoalescer_BuffersRapidUpdates verifies the first update is written through, later ones
+// are buffered yet visible to point lookups, and list queries flush them.
+// TestNoteWriteCoalescer_BuffersRapidUpdates 验证第一次更新直接落盘，之后的更新被缓冲但对单条查询可见，
+// 列表查询会将其刷出。
+func TestNoteWriteCoalescer_BuffersRapidUpdates(t *testing.T) {
+	r, cleanup := setupNoteWriteCoalescerTest(t, time.Hour)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(1)
+	note, err := r.Create(ctx, &domain.Note{VaultID: vaultID, Action: domain.NoteActionCreate, Path: "a.md", PathHash: util.EncodeHash32("a.md"), Content: "v0"}, uid)
+	require.NoError(t, err)
+
+	update := func(content string) *domain.Note {
+		note.Action = domain.NoteActionModify
+		note.Content = content
+		note.ContentHash = util.EncodeHash32(content)
+		res, err := r.Update(ctx, note, uid)
+		require.NoError(t, err)
+		return res
+	}
+
+	update("v1")
+	assert.Equal(t, "v1", storedContent(t, r, uid, note.ID), "the first update is written through")
+
+	res := update("v2")
+	assert.Equal(t, "v2", res.Content)
+	res = update("v3")
+	assert.Equal(t, "v3", res.Content)
+	assert.Equal(t, "v1", storedContent(t, r, uid, note.ID), "later updates are buffered")
+	assert.Equal(t, 1, r.dao.noteWrites.pendingCount())
+
+	got, err := r.GetByPathHash(ctx, util.EncodeHash32("a.md"), vaultID, uid)
+	require.NoError(t, err)
+	assert.Equal(t, "v3", got.Content)
+	assert.Equal(t, util.EncodeHash32("v3"), got.ContentHash)
+
+	list, err := r.ListByUpdatedTimestamp(ctx, 0, vaultID, uid)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "v3", list[0].Content)
+	assert.Equal(t, "v3", storedContent(t, r, uid, note.ID))
+	assert.Zero(t, r.dao.noteWrites.pendingCount())
+}
+
+// TestNoteWriteCoalescer_PathChangeWritesThrough verifies an update that moves the note is written
+// immediately and supersedes the buffered content.
+// TestNoteWriteCoalescer_PathChangeWritesThrough 验证移动笔记的更新会立即落盘并覆盖缓冲的正文。
+func TestNoteWriteCoalescer_PathChangeWritesThrough(t *testing.T) {
+	r, cleanup := setupNoteWriteCoalescerTest(t, time.Hour)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(1)
+	note, err := r.Create(ctx, &domain.Note{VaultID: vaultID, Action: domain.NoteActionCreate, Path: "a.md", PathHash: util.EncodeHash32("a.md"), Content: "v0"}, uid)
+	require.NoError(t, err)
+
+	note.Content = "v1"
+	_, err = r.Update(ctx, note, uid)
+	require.NoError(t, err)
+	note.Content = "v2"
+	_, err = r.Update(ctx, note, uid)
+	require.NoError(t, err)
+	require.Equal(t, 1, r.dao.noteWrites.pendingCount())
+
+	note.Path, note.PathHash, note.Content = "b.md", util.EncodeHash32("b.md"), "v3"
+	_, err = r.Update(ctx, note, uid)
+	require.NoError(t, err)
+	assert.Zero(t, r.dao.noteWrites.pendingCount())
+	assert.Equal(t, "v3", storedContent(t, r, uid, note.ID))
+
+	got, err := r.GetByPathHash(ctx, util.EncodeHash32("b.md"), vaultID, uid)
+	require.NoError(t, err)
+	assert.Equal(t, "v3", got.Content)
+}
+
+// TestNoteWriteCoalescer_FlushesAfterWindow verifies buffered updates land once the window elapses.
+// TestNoteWriteCoalescer_FlushesAfterWindow 验证窗口期结束后缓冲的更新会落盘。
+func TestNoteWriteCoalescer_FlushesAfterWindow(t *testing.T) {
+	r, cleanup := setupNoteWriteCoalescerTest(t, 20*time.Millisecond)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(1)
+	note, err := r.Create(ctx, &domain.Note{VaultID: vaultID, Action: domain.NoteActionCreate, Path: "a.md", PathHash: util.EncodeHash32("a.md"), Content: "v0"}, uid)
+	require.NoError(t, err)
+
+	for _, content := range []string{"v1", "v2", "v3"} {
+		note.Content = content
+		_, err = r.Update(ctx, note, uid)
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return r.dao.noteWrites.pendingCount() == 0 && storedContent(t, r, uid, note.ID) == "v3"
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+// TestNoteWriteCoalescer_FailedFlushStaysPending verifies a buffered update whose write fails is kept
+// and lands on the next flush instead of being lost.
+// TestNoteWriteCoalescer_FailedFlushStaysPending 验证写入失败的缓冲更新会被保留，
+// 并在下一次刷出时落盘而不会丢失。
+func TestNoteWriteCoalescer_FailedFlushStaysPending(t *testing.T) {
+	r, cleanup := setupNoteWriteCoalescerTest(t, time.Hour)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid, vaultID = int64(1), int64(1)
+	note, err := r.Create(ctx, &domain.Note{VaultID: vaultID, Action: domain.NoteActionCreate, Path: "a.md", PathHash: util.EncodeHash32("a.md"), Content: "v0"}, uid)
+	require.NoError(t, err)
+
+	for _, content := range []string{"v1", "v2"} {
+		note.Action = domain.NoteActionModify
+		note.Content = content
+		_, err = r.Update(ctx, note, uid)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, r.dao.noteWrites.pendingCount())
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	r.dao.FlushNoteWrites(canceled)
+	assert.Equal(t, 1, r.dao.noteWrites.pendingCount(), "a failed flush keeps the update buffered")
+	assert.Equal(t, "v1", storedContent(t, r, uid, note.ID))
+
+	got, err := r.GetByPathHash(ctx, util.EncodeHash32("a.md"), vaultID, uid)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", got.Content, "lookups still see the buffered update")
+
+	r.dao.FlushNoteWrites(ctx)
+	assert.Zero(t, r.dao.noteWrites.pendingCount())
+	assert.Equal(t, "v2", storedContent(t, r, uid, note.ID))
+}
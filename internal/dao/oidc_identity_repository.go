@@ -83,4 +83,16 @@ func (r *oidcIdentityRepository) Create(ctx context.Context, identity *domain.OI
 	return r.toDomain(m), nil
 }
 
+func (r *oidcIdentityRepository) ListByUID(ctx context.Context, uid int64) ([]*domain.OIDCIdentity, error) {
+	var list []*model.UserOIDCIdentity
+	if err := r.db().WithContext(ctx).Where("uid = ?", uid).Order("id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	identities := make([]*domain.OIDCIdentity, 0, len(list))
+	for _, m := range list {
+		identities = append(identities, r.toDomain(m))
+	}
+	return identities, nil
+}
+
 var _ domain.OIDCIdentityRepository = (*oidcIdentityRepository)(nil)
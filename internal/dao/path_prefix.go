@@ -1,6 +1,10 @@
 package dao
 
-import "strings"
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
 
 func isPathWithinPrefix(path, prefix string) bool {
 	path = strings.Trim(path, "/")
@@ -10,3 +14,21 @@ func isPathWithinPrefix(path, prefix string) bool {
 	}
 	return strings.HasPrefix(path, prefix+"/")
 }
+
+// parentDir returns the folder part of a vault path, empty for top-level paths
+// parentDir 返回仓库路径的目录部分，顶层路径返回空字符串
+func parentDir(path string) string {
+	if idx := strings.LastIndex(path, "/"); idx >= 0 {
+		return path[:idx]
+	}
+	return ""
+}
+
+// excludePathPrefixes filters out rows whose path lies under any of the given folders
+// excludePathPrefixes 过滤掉路径位于任一给定目录下的记录
+func excludePathPrefixes(db *gorm.DB, prefixes []string) *gorm.DB {
+	for _, prefix := range prefixes {
+		db = db.Where("path NOT LIKE ?", strings.Trim(prefix, "/")+"/%")
+	}
+	return db
+}
@@ -0,0 +1,102 @@
+package dao
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// retentionPolicyRepository implements domain.RetentionPolicyRepository
+// retentionPolicyRepository 实现 domain.RetentionPolicyRepository 接口
+type retentionPolicyRepository struct {
+	dao             *Dao
+	customPrefixKey string
+	migrateOnce     sync.Map // tracks per-key migration completion // 记录每个 key 是否已完成 AutoMigrate
+}
+
+// NewRetentionPolicyRepository creates a RetentionPolicyRepository instance
+// NewRetentionPolicyRepository 创建 RetentionPolicyRepository 实例
+func NewRetentionPolicyRepository(dao *Dao) domain.RetentionPolicyRepository {
+	return &retentionPolicyRepository{dao: dao, customPrefixKey: "user_retention_policy_"}
+}
+
+// GetKey returns the database routing key for the given user
+// GetKey 返回指定用户的数据库路由键
+func (r *retentionPolicyRepository) GetKey(uid int64) string {
+	return r.customPrefixKey + strconv.FormatInt(uid, 10)
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name: "RetentionPolicy",
+		RepoFactory: func(d *Dao) daoDBCustomKey {
+			return NewRetentionPolicyRepository(d).(daoDBCustomKey)
+		},
+		IsMainDB: false,
+	})
+}
+
+// db returns the *gorm.DB of the user's retention policy database, with one-time AutoMigrate
+// db 返回用户保留策略库的 *gorm.DB，确保每个用户库只迁移一次
+func (r *retentionPolicyRepository) db(uid int64) *gorm.DB {
+	key := r.GetKey(uid)
+	if _, loaded := r.migrateOnce.LoadOrStore(key+"#retention_policy", true); !loaded {
+		if db := r.dao.ResolveDB(key); db != nil {
+			model.AutoMigrate(db, "RetentionPolicy")
+		}
+	}
+	return r.dao.ResolveDB(key)
+}
+
+// List lists the retention overrides of a user
+// List 列出用户的保留时间覆盖
+func (r *retentionPolicyRepository) List(ctx context.Context, uid int64) ([]*domain.RetentionPolicy, error) {
+	var rows []*model.RetentionPolicy
+	if err := r.db(uid).WithContext(ctx).Order("vault_id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	list := make([]*domain.RetentionPolicy, 0, len(rows))
+	for _, m := range rows {
+		list = append(list, &domain.RetentionPolicy{
+			ID:                  m.ID,
+			UID:                 m.UID,
+			VaultID:             m.VaultID,
+			SoftDeleteRetention: m.SoftDeleteRetention,
+			CreatedAt:           time.Time(m.CreatedAt),
+			UpdatedAt:           time.Time(m.UpdatedAt),
+		})
+	}
+	return list, nil
+}
+
+// Save creates or replaces the override of a vault, vaultID 0 being the user-wide one
+// Save 新建或替换保险库的覆盖，vaultID 为 0 表示用户级覆盖
+func (r *retentionPolicyRepository) Save(ctx context.Context, vaultID int64, retention string, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		now := timex.Now()
+		m := &model.RetentionPolicy{UID: uid, VaultID: vaultID, SoftDeleteRetention: retention, CreatedAt: now, UpdatedAt: now}
+		return r.db(uid).WithContext(ctx).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "vault_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"soft_delete_retention", "updated_at"}),
+		}).Create(m).Error
+	})
+}
+
+// Delete removes the override of a vault, vaultID 0 being the user-wide one
+// Delete 删除保险库的覆盖，vaultID 为 0 表示用户级覆盖
+func (r *retentionPolicyRepository) Delete(ctx context.Context, vaultID int64, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Where("vault_id = ?", vaultID).Delete(&model.RetentionPolicy{}).Error
+	})
+}
+
+// Ensure retentionPolicyRepository implements domain.RetentionPolicyRepository
+// 确保 retentionPolicyRepository 实现了 domain.RetentionPolicyRepository 接口
+var _ domain.RetentionPolicyRepository = (*retentionPolicyRepository)(nil)
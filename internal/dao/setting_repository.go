@@ -307,15 +307,32 @@ func (r *settingRepository) Delete(ctx context.Context, id, uid int64) error {
 // DeletePhysicalByTime physically deletes settings marked for deletion based on time
 // DeletePhysicalByTime 根据时间物理删除已标记删除的配置
 func (r *settingRepository) DeletePhysicalByTime(ctx context.Context, timestamp, uid int64) error {
+	return r.DeletePhysicalByTimeInVaults(ctx, timestamp, nil, true, uid)
+}
+
+// DeletePhysicalByTimeInVaults physically deletes settings marked for deletion by time, only in vaultIDs or, with exclude, outside them
+// DeletePhysicalByTimeInVaults 根据时间物理删除已标记删除的配置，仅限 vaultIDs 中的保险库，exclude 时则排除这些保险库
+func (r *settingRepository) DeletePhysicalByTimeInVaults(ctx context.Context, timestamp int64, vaultIDs []int64, exclude bool, uid int64) error {
+	if !exclude && len(vaultIDs) == 0 {
+		return nil
+	}
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := r.setting(uid).Setting
+		q := u.WithContext(ctx).Where(
+			u.Action.Eq("delete"),
+			u.UpdatedTimestamp.Lt(timestamp),
+		)
+		if len(vaultIDs) > 0 {
+			if exclude {
+				q = q.Where(u.VaultID.NotIn(vaultIDs...))
+			} else {
+				q = q.Where(u.VaultID.In(vaultIDs...))
+			}
+		}
 
 		// Find records to be physically deleted, clean up files
 		// 查找待物理删除的记录，清理文件
-		mList, err := u.WithContext(ctx).Where(
-			u.Action.Eq("delete"),
-			u.UpdatedTimestamp.Lt(timestamp),
-		).Find()
+		mList, err := q.Find()
 
 		if err == nil {
 			for _, m := range mList {
@@ -324,11 +341,7 @@ func (r *settingRepository) DeletePhysicalByTime(ctx context.Context, timestamp,
 			}
 		}
 
-		_, err = u.WithContext(ctx).Where(
-			u.Action.Eq("delete"),
-			u.UpdatedTimestamp.Lt(timestamp),
-		).Delete()
-
+		_, err = q.Delete()
 		return err
 	})
 }
@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+)
+
+// snapshotRepository implements domain.SnapshotRepository interface
+// snapshotRepository 实现 domain.SnapshotRepository 接口
+type snapshotRepository struct {
+	dao *Dao
+}
+
+// NewSnapshotRepository creates SnapshotRepository instance
+// NewSnapshotRepository 创建 SnapshotRepository 实例
+func NewSnapshotRepository(dao *Dao) domain.SnapshotRepository {
+	return &snapshotRepository{dao: dao}
+}
+
+// Databases implements domain.SnapshotRepository
+// User databases are found on disk rather than in KeyDb, so that databases of users
+// who have not connected since the last start are included too.
+// 用户库从磁盘上查找而不是从 KeyDb 中获取，以便包含自上次启动以来尚未连接过的用户的数据库。
+func (r *snapshotRepository) Databases(ctx context.Context) ([]*domain.SnapshotDatabase, error) {
+	var dbs []*domain.SnapshotDatabase
+
+	mainCfg := r.dao.resolveConfig("")
+	if mainCfg.Type == "sqlite" {
+		dbs = append(dbs, &domain.SnapshotDatabase{Key: "", Path: mainCfg.Path})
+	}
+
+	// Any non-empty key resolves to the user database configuration
+	// 任意非空 key 都会解析为用户库配置
+	userCfg := r.dao.resolveConfig("user")
+	if userCfg.Type != "sqlite" {
+		return dbs, nil
+	}
+	ext := filepath.Ext(userCfg.Path)
+	prefix := userCfg.Path[:len(userCfg.Path)-len(ext)] + "_"
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	for _, m := range matches {
+		key := strings.TrimSuffix(strings.TrimPrefix(m, prefix), ext)
+		if key == "" {
+			continue
+		}
+		db := &domain.SnapshotDatabase{Key: key, Path: m}
+		if schema, ok := r.dao.extractUserSchema(key); ok {
+			db.UID, _ = strconv.ParseInt(strings.TrimPrefix(schema, "user_"), 10, 64)
+		}
+		dbs = append(dbs, db)
+	}
+	return dbs, nil
+}
+
+// VacuumInto implements domain.SnapshotRepository
+func (r *snapshotRepository) VacuumInto(ctx context.Context, key string, dst string) error {
+	db := r.dao.ResolveDB(key)
+	if db == nil {
+		return fmt.Errorf("database %q is not available", key)
+	}
+	absDst, err := filepath.Abs(dst)
+	if err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Exec("VACUUM INTO ?", absDst).Error
+}
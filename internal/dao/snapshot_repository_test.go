@@ -0,0 +1,73 @@
+package dao
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestSnapshotRepository_DatabasesAndVacuumInto verifies user databases are found on disk
+// and that VACUUM INTO produces a readable copy of a tenant database.
+// TestSnapshotRepository_DatabasesAndVacuumInto 验证能从磁盘找到用户库，且 VACUUM INTO 能生成可读的租户库副本。
+func TestSnapshotRepository_DatabasesAndVacuumInto(t *testing.T) {
+	tempDir := t.TempDir()
+	origWd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tempDir))
+	defer func() { _ = os.Chdir(origWd) }()
+	require.NoError(t, os.MkdirAll(filepath.Join("storage", "database"), 0755))
+
+	dbPath := filepath.Join("storage", "database", "db.sqlite3")
+	mainDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	require.NoError(t, err)
+	defer func() {
+		if sqlDB, err := mainDB.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}()
+
+	dbCfg := &config.DatabaseConfig{
+		Type:             "sqlite",
+		Path:             dbPath,
+		EnableWriteQueue: util.Ptr(false),
+	}
+	d := New(mainDB, context.Background(), WithConfig(dbCfg), WithLogger(zap.NewNop()))
+	defer d.CleanupConnections(0)
+
+	tenant := d.GetOrCreateDB("user_note_7")
+	require.NotNil(t, tenant)
+	require.NoError(t, tenant.Exec("CREATE TABLE t (v TEXT)").Error)
+	require.NoError(t, tenant.Exec("INSERT INTO t (v) VALUES ('kept')").Error)
+
+	repo := NewSnapshotRepository(d)
+	dbs, err := repo.Databases(context.Background())
+	require.NoError(t, err)
+	require.Len(t, dbs, 2)
+	assert.Equal(t, "", dbs[0].Key)
+	assert.Equal(t, "user_note_7", dbs[1].Key)
+	assert.Equal(t, int64(0), dbs[0].UID)
+	assert.Equal(t, int64(7), dbs[1].UID)
+
+	dst := filepath.Join(tempDir, "copy.sqlite3")
+	require.NoError(t, repo.VacuumInto(context.Background(), "user_note_7", dst))
+
+	copyDB, err := gorm.Open(sqlite.Open(dst), &gorm.Config{})
+	require.NoError(t, err)
+	defer func() {
+		if sqlDB, err := copyDB.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}()
+	var v string
+	require.NoError(t, copyDB.Raw("SELECT v FROM t").Scan(&v).Error)
+	assert.Equal(t, "kept", v)
+}
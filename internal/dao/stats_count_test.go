@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// TestStatsCounts verifies the hourly sync log counts and the backup history counts per status.
+// TestStatsCounts 验证按小时统计的同步日志数量与按状态统计的备份历史数量。
+func TestStatsCounts(t *testing.T) {
+	tempDir := t.TempDir()
+	origWd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tempDir))
+	defer func() { _ = os.Chdir(origWd) }()
+	require.NoError(t, os.MkdirAll(filepath.Join("storage", "database"), 0755))
+
+	dbPath := filepath.Join("storage", "database", "db.sqlite3")
+	mainDB, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	require.NoError(t, err)
+	defer func() {
+		if sqlDB, err := mainDB.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	}()
+	d := New(mainDB, context.Background(), WithConfig(&config.DatabaseConfig{
+		Type:             "sqlite",
+		Path:             dbPath,
+		EnableWriteQueue: util.Ptr(false),
+	}), WithLogger(zap.NewNop()))
+	defer d.CleanupConnections(0)
+
+	ctx := context.Background()
+	hour := time.Now().Truncate(time.Hour)
+
+	syncLogs := NewSyncLogRepository(d)
+	for _, at := range []time.Time{hour.Add(time.Minute), hour.Add(2 * time.Minute), hour.Add(-30 * time.Minute), hour.Add(-48 * time.Hour)} {
+		require.NoError(t, syncLogs.Create(ctx, &domain.SyncLog{UID: 1, Type: domain.SyncLogTypeNote, Action: domain.SyncLogActionModify, CreatedAt: timex.Time(at)}, 1))
+	}
+	counts, err := syncLogs.CountByHour(ctx, hour.Add(-time.Hour), 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int64{hour.UnixMilli(): 2, hour.Add(-time.Hour).UnixMilli(): 1}, counts)
+
+	backups := NewBackupRepository(d)
+	for _, status := range []int{domain.BackupStatusSuccess, domain.BackupStatusSuccess, domain.BackupStatusFailed} {
+		_, err := backups.CreateHistory(ctx, &domain.BackupHistory{UID: 1, ConfigID: 1, Status: status}, 1)
+		require.NoError(t, err)
+	}
+	byStatus, err := backups.CountHistoryByStatus(ctx, 1, hour.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int64{domain.BackupStatusSuccess: 2, domain.BackupStatusFailed: 1}, byStatus)
+}
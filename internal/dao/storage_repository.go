@@ -52,23 +52,26 @@ func (r *storageRepository) toDomain(m *model.Storage) *domain.Storage {
 		return nil
 	}
 	return &domain.Storage{
-		ID:              m.ID,
-		UID:             m.UID,
-		Type:            m.Type,
-		Endpoint:        m.Endpoint,
-		Region:          m.Region,
-		AccountID:       m.AccountID,
-		BucketName:      m.BucketName,
-		AccessKeyID:     m.AccessKeyID,
-		AccessKeySecret: m.AccessKeySecret,
-		CustomPath:      m.CustomPath,
-		AccessURLPrefix: m.AccessURLPrefix,
-		User:            m.User,
-		Password:        m.Password,
-		IsEnabled:       m.IsEnabled == 1,
-		IsDeleted:       m.IsDeleted == 1,
-		CreatedAt:       time.Time(m.CreatedAt),
-		UpdatedAt:       time.Time(m.UpdatedAt),
+		ID:                 m.ID,
+		UID:                m.UID,
+		Type:               m.Type,
+		Endpoint:           m.Endpoint,
+		Region:             m.Region,
+		AccountID:          m.AccountID,
+		BucketName:         m.BucketName,
+		AccessKeyID:        m.AccessKeyID,
+		AccessKeySecret:    m.AccessKeySecret,
+		CustomPath:         m.CustomPath,
+		AccessURLPrefix:    m.AccessURLPrefix,
+		User:               m.User,
+		Password:           m.Password,
+		PathStyle:          m.PathStyle == 1,
+		CACert:             m.CACert,
+		InsecureSkipVerify: m.InsecureSkipVerify == 1,
+		IsEnabled:          m.IsEnabled == 1,
+		IsDeleted:          m.IsDeleted == 1,
+		CreatedAt:          time.Time(m.CreatedAt),
+		UpdatedAt:          time.Time(m.UpdatedAt),
 	}
 }
 
@@ -96,6 +99,7 @@ func (r *storageRepository) toModel(s *domain.Storage) *model.Storage {
 		AccessURLPrefix: s.AccessURLPrefix,
 		User:            s.User,
 		Password:        s.Password,
+		CACert:          s.CACert,
 		IsEnabled:       int64(0),
 		IsDeleted:       isDeleted,
 		CreatedAt:       timex.Time(s.CreatedAt),
@@ -105,6 +109,12 @@ func (r *storageRepository) toModel(s *domain.Storage) *model.Storage {
 	if s.IsEnabled {
 		modelStorage.IsEnabled = 1
 	}
+	if s.PathStyle {
+		modelStorage.PathStyle = 1
+	}
+	if s.InsecureSkipVerify {
+		modelStorage.InsecureSkipVerify = 1
+	}
 	return modelStorage
 }
 
@@ -173,6 +173,58 @@ func (r *syncLogRepository) List(ctx context.Context, uid int64, logType, action
 	return results, total, nil
 }
 
+// CountByHour counts the sync logs of a user created since the given time per hour
+// Bucketed in Go rather than SQL, date functions differ between SQLite, MySQL and PostgreSQL
+// CountByHour 按小时统计用户自指定时间以来的同步日志数量
+// 在 Go 中分桶而非 SQL，SQLite、MySQL 与 PostgreSQL 的日期函数各不相同
+func (r *syncLogRepository) CountByHour(ctx context.Context, since time.Time, uid int64) (map[int64]int64, error) {
+	var times []timex.Time
+	err := r.db(uid).WithContext(ctx).Model(&model.SyncLog{}).
+		Where("created_at >= ?", since).
+		Pluck("created_at", &times).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int64]int64)
+	for _, t := range times {
+		counts[time.Time(t).Truncate(time.Hour).UnixMilli()]++
+	}
+	return counts, nil
+}
+
+// ListSince lists the sync logs of a user created since the given time, oldest first
+// ListSince 按时间正序列出用户自指定时间以来的同步日志
+func (r *syncLogRepository) ListSince(ctx context.Context, since time.Time, uid int64) ([]*domain.SyncLog, error) {
+	var rows []*model.SyncLog
+	err := r.db(uid).WithContext(ctx).
+		Select("id", "uid", "vault_id", "type", "action", "path", "size", "client_name", "client_type", "client_version", "status", "message", "created_at").
+		Where("created_at >= ?", since).
+		Order("id ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*domain.SyncLog, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, &domain.SyncLog{
+			ID:            m.ID,
+			UID:           m.UID,
+			VaultID:       m.VaultID,
+			Type:          domain.SyncLogType(m.Type),
+			Action:        domain.SyncLogAction(m.Action),
+			Path:          m.Path,
+			Size:          m.Size,
+			ClientName:    m.ClientName,
+			ClientType:    m.ClientType,
+			ClientVersion: m.ClientVersion,
+			Status:        int(m.Status),
+			Message:       m.Message,
+			CreatedAt:     m.CreatedAt,
+		})
+	}
+	return results, nil
+}
+
 // CleanupByTime removes sync logs older than the given timestamp for a specific user
 // CleanupByTime 清理指定用户在指定时间戳之前的同步日志
 func (r *syncLogRepository) CleanupByTime(ctx context.Context, timestamp int64, uid int64) error {
@@ -0,0 +1,64 @@
+package dao
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+)
+
+// usageRepository implements domain.UsageRepository interface
+// usageRepository 实现 domain.UsageRepository 接口
+type usageRepository struct {
+	dao *Dao
+}
+
+// NewUsageRepository creates UsageRepository instance
+// NewUsageRepository 创建 UsageRepository 实例
+func NewUsageRepository(dao *Dao) domain.UsageRepository {
+	return &usageRepository{dao: dao}
+}
+
+// ContentUsage implements domain.UsageRepository
+// ContentUsage 实现 domain.UsageRepository 接口
+func (r *usageRepository) ContentUsage(ctx context.Context, uid int64) (*domain.ContentUsage, error) {
+	bytes, err := r.dao.usage.get(uid)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ContentUsage{
+		Notes:    bytes["note"],
+		Files:    bytes["file"],
+		History:  bytes["history"],
+		Settings: bytes["setting"],
+	}, nil
+}
+
+// DatabaseSize implements domain.UsageRepository, the WAL and shared memory files are included
+// DatabaseSize 实现 domain.UsageRepository 接口，包含 WAL 与共享内存文件
+func (r *usageRepository) DatabaseSize(ctx context.Context, uid int64) (int64, bool, error) {
+	c := r.dao.resolveConfig("user_" + strconv.FormatInt(uid, 10))
+	if c.Type != "sqlite" || c.Path == "" {
+		return 0, false, nil
+	}
+
+	// Every repository key of the user ends with the UID, e.g. db_user_1.sqlite3 or db_user_file_1.sqlite3
+	// 用户的每个仓储标识都以 UID 结尾，如 db_user_1.sqlite3 或 db_user_file_1.sqlite3
+	ext := filepath.Ext(c.Path)
+	base := c.Path[:len(c.Path)-len(ext)]
+	matches, err := filepath.Glob(base + "_user_*" + ext + "*")
+	if err != nil {
+		return 0, true, err
+	}
+	suffix := "_" + strconv.FormatInt(uid, 10) + ext
+	var size int64
+	for _, m := range matches {
+		name := strings.TrimSuffix(strings.TrimSuffix(m, "-wal"), "-shm")
+		if strings.HasSuffix(name, suffix) {
+			size += usageFileSize(m)
+		}
+	}
+	return size, true, nil
+}
@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUsageRepository_ContentUsage verifies the first read walks the user's folders and later
+// writes, moves and removals through the content helpers are applied without walking again.
+// TestUsageRepository_ContentUsage 验证首次读取遍历用户目录，之后通过内容辅助方法进行的写入、移动与删除
+// 会被增量应用而无需再次遍历。
+func TestUsageRepository_ContentUsage(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const uid = int64(1)
+	repo := NewUsageRepository(daoInst)
+
+	require.NoError(t, daoInst.SaveContentToFile(daoInst.GetNoteFolderPath(uid, 1), "content.txt", "hello"))
+	require.NoError(t, daoInst.SaveContentToFile(daoInst.GetNoteHistoryFolderPath(uid, 1), "diff.patch", "abc"))
+
+	usage, err := repo.ContentUsage(ctx, uid)
+	require.NoError(t, err)
+	noteSize := usageFileSize(filepath.Join(daoInst.GetNoteFolderPath(uid, 1), "content.txt"))
+	assert.Equal(t, noteSize, usage.Notes)
+	assert.Positive(t, usage.History)
+	assert.Zero(t, usage.Files)
+
+	// A change made behind the tracker's back is not seen, proving reads do not walk again
+	// 绕过追踪器的修改不会被看到，证明读取不会再次遍历
+	require.NoError(t, os.WriteFile(filepath.Join(daoInst.GetNoteFolderPath(uid, 1), "extra.txt"), []byte("untracked"), 0644))
+
+	require.NoError(t, daoInst.SaveContentToFile(daoInst.GetNoteFolderPath(uid, 2), "content.txt", "hello"))
+	src := filepath.Join(t.TempDir(), "upload.tmp")
+	require.NoError(t, os.WriteFile(src, []byte("attachment"), 0644))
+	require.NoError(t, os.MkdirAll(daoInst.GetFileFolderPath(uid, 1), 0755))
+	require.NoError(t, daoInst.MoveContentFile(src, filepath.Join(daoInst.GetFileFolderPath(uid, 1), "file.dat")))
+	require.NoError(t, daoInst.RemoveContentFolder(daoInst.GetNoteHistoryFolderPath(uid, 1)))
+
+	usage, err = repo.ContentUsage(ctx, uid)
+	require.NoError(t, err)
+	assert.Equal(t, 2*noteSize, usage.Notes)
+	assert.Positive(t, usage.Files)
+	assert.Zero(t, usage.History)
+
+	// Other users are not affected
+	// 其他用户不受影响
+	usage, err = repo.ContentUsage(ctx, 2)
+	require.NoError(t, err)
+	assert.Zero(t, usage.Notes+usage.Files+usage.History+usage.Settings)
+}
+
+// TestUsageRepository_DatabaseSize verifies only the SQLite files of the user are counted.
+// TestUsageRepository_DatabaseSize 验证只统计该用户的 SQLite 文件。
+func TestUsageRepository_DatabaseSize(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	_, err := NewFileRepository(daoInst).CountByFIDs(ctx, []int64{1}, 1, 1)
+	require.NoError(t, err)
+
+	repo := NewUsageRepository(daoInst)
+	size, ok, err := repo.DatabaseSize(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Positive(t, size)
+
+	size, ok, err = repo.DatabaseSize(ctx, 11)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Zero(t, size)
+}
@@ -0,0 +1,141 @@
+package dao
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// usageVaultRoot root of the per-user content folders, see GetNoteFolderPath and friends
+// usageVaultRoot 各用户内容目录的根目录，参见 GetNoteFolderPath 等
+var usageVaultRoot = filepath.Join("storage", "vault")
+
+// userContentUsage on-disk bytes of one user's content folders by kind
+// userContentUsage 单个用户按类型统计的内容目录磁盘字节数
+type userContentUsage struct {
+	mu     sync.Mutex
+	loaded bool
+	bytes  map[string]int64
+}
+
+// usageTracker keeps the on-disk bytes of every user's content folders. A user's folders are walked
+// once on the first read, afterwards SaveContentToFile, MoveContentFile and RemoveContentFolder apply
+// the size changes they make, so reads never walk the tree again.
+// usageTracker 记录每个用户内容目录占用的磁盘字节数。首次读取时遍历一次用户目录，之后由
+// SaveContentToFile、MoveContentFile 与 RemoveContentFolder 应用各自造成的大小变化，读取不再重复遍历。
+type usageTracker struct {
+	mu    sync.Mutex
+	users map[int64]*userContentUsage
+}
+
+// newUsageTracker creates an empty usage tracker
+// newUsageTracker 创建空的用量追踪器
+func newUsageTracker() *usageTracker {
+	return &usageTracker{users: make(map[int64]*userContentUsage)}
+}
+
+func (t *usageTracker) user(uid int64) *userContentUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u, ok := t.users[uid]
+	if !ok {
+		u = &userContentUsage{bytes: make(map[string]int64)}
+		t.users[uid] = u
+	}
+	return u
+}
+
+// usagePathOwner returns the user and content kind of a path below storage/vault/u_<uid>/<kind>
+// usagePathOwner 返回 storage/vault/u_<uid>/<kind> 下路径所属的用户与内容类型
+func usagePathOwner(path string) (int64, string, bool) {
+	rel, err := filepath.Rel(usageVaultRoot, path)
+	if err != nil {
+		return 0, "", false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 2 || !strings.HasPrefix(parts[0], "u_") {
+		return 0, "", false
+	}
+	uid, err := strconv.ParseInt(strings.TrimPrefix(parts[0], "u_"), 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+	return uid, parts[1], true
+}
+
+// add applies a size change of a content path, users not read yet are skipped since their walk counts it
+// add 应用内容路径的大小变化，尚未读取过的用户跳过，其首次遍历会统计到该变化
+func (t *usageTracker) add(path string, delta int64) {
+	if t == nil || delta == 0 {
+		return
+	}
+	uid, kind, ok := usagePathOwner(path)
+	if !ok {
+		return
+	}
+	u := t.user(uid)
+	u.mu.Lock()
+	if u.loaded {
+		u.bytes[kind] += delta
+		if u.bytes[kind] < 0 {
+			u.bytes[kind] = 0
+		}
+	}
+	u.mu.Unlock()
+}
+
+// get returns the bytes of a user by kind, walking the user's folders on the first call
+// get 返回用户按类型统计的字节数，首次调用时遍历用户目录
+func (t *usageTracker) get(uid int64) (map[string]int64, error) {
+	u := t.user(uid)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if !u.loaded {
+		base := filepath.Join(usageVaultRoot, fmt.Sprintf("u_%d", uid))
+		entries, err := os.ReadDir(base)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				u.bytes[e.Name()] = usageDirSize(filepath.Join(base, e.Name()))
+			}
+		}
+		u.loaded = true
+	}
+	res := make(map[string]int64, len(u.bytes))
+	for kind, n := range u.bytes {
+		res[kind] = n
+	}
+	return res, nil
+}
+
+// usageFileSize returns the size of a file, 0 when it does not exist
+// usageFileSize 返回文件大小，不存在时为 0
+func usageFileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return 0
+	}
+	return info.Size()
+}
+
+// usageDirSize returns the total size of the files below a directory
+// usageDirSize 返回目录下所有文件的总大小
+func usageDirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,132 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+)
+
+// userInviteRepository implements domain.UserInviteRepository, invitations live in the main database
+// userInviteRepository 实现 domain.UserInviteRepository 接口，邀请码存放在主库中
+type userInviteRepository struct {
+	dao *Dao
+}
+
+// NewUserInviteRepository creates a UserInviteRepository instance
+// NewUserInviteRepository 创建 UserInviteRepository 实例
+func NewUserInviteRepository(dao *Dao) domain.UserInviteRepository {
+	return &userInviteRepository{dao: dao}
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name:     "UserInvite",
+		IsMainDB: true,
+	})
+}
+
+func (r *userInviteRepository) db() *gorm.DB {
+	db := r.dao.ResolveDB()
+	r.dao.QueryWithOnceInit(func(g *gorm.DB) {
+		model.AutoMigrate(g, "UserInvite")
+	}, "user_invite#user_invite")
+	return db
+}
+
+func (r *userInviteRepository) toDomain(m *model.UserInvite) *domain.UserInvite {
+	invite := &domain.UserInvite{
+		ID:        m.ID,
+		Code:      m.Code,
+		MaxUses:   m.MaxUses,
+		Uses:      m.Uses,
+		Note:      m.Note,
+		CreatedBy: m.CreatedBy,
+		CreatedAt: time.Time(m.CreatedAt),
+	}
+	if m.ExpiresAt > 0 {
+		invite.ExpiresAt = time.Unix(m.ExpiresAt, 0)
+	}
+	return invite
+}
+
+// Create stores a new invitation code
+// Create 存储新的邀请码
+func (r *userInviteRepository) Create(ctx context.Context, invite *domain.UserInvite) (*domain.UserInvite, error) {
+	m := &model.UserInvite{
+		Code:      invite.Code,
+		MaxUses:   invite.MaxUses,
+		Note:      invite.Note,
+		CreatedBy: invite.CreatedBy,
+		CreatedAt: timex.Now(),
+	}
+	if !invite.ExpiresAt.IsZero() {
+		m.ExpiresAt = invite.ExpiresAt.Unix()
+	}
+	if err := r.db().WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomain(m), nil
+}
+
+// GetByCode retrieves an invitation by its code
+// GetByCode 根据邀请码获取邀请
+func (r *userInviteRepository) GetByCode(ctx context.Context, code string) (*domain.UserInvite, error) {
+	var m model.UserInvite
+	if err := r.db().WithContext(ctx).Where("code = ?", code).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomain(&m), nil
+}
+
+// List lists all invitations, newest first
+// List 按时间倒序列出全部邀请码
+func (r *userInviteRepository) List(ctx context.Context) ([]*domain.UserInvite, error) {
+	var ms []*model.UserInvite
+	if err := r.db().WithContext(ctx).Order("id DESC").Find(&ms).Error; err != nil {
+		return nil, err
+	}
+	invites := make([]*domain.UserInvite, 0, len(ms))
+	for _, m := range ms {
+		invites = append(invites, r.toDomain(m))
+	}
+	return invites, nil
+}
+
+// Use counts one registration against the code; the condition keeps concurrent registrations within max_uses
+// Use 为邀请码计入一次注册；条件更新保证并发注册不超过 max_uses
+func (r *userInviteRepository) Use(ctx context.Context, id int64) (bool, error) {
+	result := r.db().WithContext(ctx).Model(&model.UserInvite{}).
+		Where("id = ? AND (max_uses = 0 OR uses < max_uses)", id).
+		Update("uses", gorm.Expr("uses + 1"))
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Release gives back one use, the condition keeps the counter from going below zero
+// Release 退还一次使用，条件更新保证计数不会小于零
+func (r *userInviteRepository) Release(ctx context.Context, id int64) error {
+	return r.db().WithContext(ctx).Model(&model.UserInvite{}).
+		Where("id = ? AND uses > 0", id).
+		Update("uses", gorm.Expr("uses - 1")).Error
+}
+
+// Delete removes an invitation
+// Delete 删除邀请码
+func (r *userInviteRepository) Delete(ctx context.Context, id int64) error {
+	result := r.db().WithContext(ctx).Where("id = ?", id).Delete(&model.UserInvite{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+var _ domain.UserInviteRepository = (*userInviteRepository)(nil)
@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+)
+
+// userPendingRepository implements domain.PendingUserRepository, the queue lives in the main database
+// userPendingRepository 实现 domain.PendingUserRepository 接口，审核队列存放在主库中
+type userPendingRepository struct {
+	dao *Dao
+}
+
+// NewPendingUserRepository creates a PendingUserRepository instance
+// NewPendingUserRepository 创建 PendingUserRepository 实例
+func NewPendingUserRepository(dao *Dao) domain.PendingUserRepository {
+	return &userPendingRepository{dao: dao}
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name:     "UserPending",
+		IsMainDB: true,
+	})
+}
+
+func (r *userPendingRepository) db() *gorm.DB {
+	db := r.dao.ResolveDB()
+	r.dao.QueryWithOnceInit(func(g *gorm.DB) {
+		model.AutoMigrate(g, "UserPending")
+	}, "user_pending#user_pending")
+	return db
+}
+
+func (r *userPendingRepository) toDomain(m *model.UserPending) *domain.PendingUser {
+	return &domain.PendingUser{
+		ID:        m.ID,
+		Email:     m.Email,
+		Username:  m.Username,
+		Password:  m.Password,
+		IP:        m.IP,
+		CreatedAt: time.Time(m.CreatedAt),
+	}
+}
+
+// Create queues a registration
+// Create 将注册加入队列
+func (r *userPendingRepository) Create(ctx context.Context, user *domain.PendingUser) (*domain.PendingUser, error) {
+	m := &model.UserPending{
+		Email:     user.Email,
+		Username:  user.Username,
+		Password:  user.Password,
+		IP:        user.IP,
+		CreatedAt: timex.Now(),
+	}
+	if err := r.db().WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomain(m), nil
+}
+
+// GetByID retrieves a pending registration
+// GetByID 获取待审核的注册
+func (r *userPendingRepository) GetByID(ctx context.Context, id int64) (*domain.PendingUser, error) {
+	var m model.UserPending
+	if err := r.db().WithContext(ctx).Where("id = ?", id).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomain(&m), nil
+}
+
+// Exists reports whether a pending registration uses the email or the username
+// Exists 判断是否有待审核的注册使用了该邮箱或用户名
+func (r *userPendingRepository) Exists(ctx context.Context, email, username string) (bool, error) {
+	var count int64
+	err := r.db().WithContext(ctx).Model(&model.UserPending{}).
+		Where("email = ? OR username = ?", email, username).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// List lists pending registrations with pagination, oldest first
+// List 按时间正序分页列出待审核的注册
+func (r *userPendingRepository) List(ctx context.Context, offset, limit int) ([]*domain.PendingUser, int64, error) {
+	var total int64
+	query := r.db().WithContext(ctx).Model(&model.UserPending{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var ms []*model.UserPending
+	if err := query.Order("id ASC").Offset(offset).Limit(limit).Find(&ms).Error; err != nil {
+		return nil, 0, err
+	}
+	users := make([]*domain.PendingUser, 0, len(ms))
+	for _, m := range ms {
+		users = append(users, r.toDomain(m))
+	}
+	return users, total, nil
+}
+
+// Delete removes a pending registration
+// Delete 删除待审核的注册
+func (r *userPendingRepository) Delete(ctx context.Context, id int64) error {
+	result := r.db().WithContext(ctx).Where("id = ?", id).Delete(&model.UserPending{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+var _ domain.PendingUserRepository = (*userPendingRepository)(nil)
@@ -0,0 +1,141 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+)
+
+// userSettingRepository implements domain.UserSettingRepository, settings live in the main database
+// userSettingRepository 实现 domain.UserSettingRepository 接口，设置存放在主库中
+type userSettingRepository struct {
+	dao *Dao
+}
+
+// NewUserSettingRepository creates a UserSettingRepository instance
+// NewUserSettingRepository 创建 UserSettingRepository 实例
+func NewUserSettingRepository(dao *Dao) domain.UserSettingRepository {
+	return &userSettingRepository{dao: dao}
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name:     "UserSetting",
+		IsMainDB: true,
+	})
+}
+
+func (r *userSettingRepository) db() *gorm.DB {
+	db := r.dao.ResolveDB()
+	r.dao.QueryWithOnceInit(func(g *gorm.DB) {
+		model.AutoMigrate(g, "UserSetting")
+	}, "user_setting#user_setting")
+	return db
+}
+
+func (r *userSettingRepository) toDomain(m *model.UserSetting) *domain.UserSetting {
+	return &domain.UserSetting{
+		ID:               m.ID,
+		UID:              m.UID,
+		Key:              m.Key,
+		Value:            m.Value,
+		Version:          m.Version,
+		Mtime:            m.Mtime,
+		Deleted:          m.IsDeleted == 1,
+		UpdatedTimestamp: m.UpdatedTimestamp,
+		CreatedAt:        time.Time(m.CreatedAt),
+		UpdatedAt:        time.Time(m.UpdatedAt),
+	}
+}
+
+func (r *userSettingRepository) toModel(s *domain.UserSetting) *model.UserSetting {
+	m := &model.UserSetting{
+		ID:               s.ID,
+		UID:              s.UID,
+		Key:              s.Key,
+		Value:            s.Value,
+		Version:          s.Version,
+		Mtime:            s.Mtime,
+		UpdatedTimestamp: s.UpdatedTimestamp,
+		CreatedAt:        timex.Time(s.CreatedAt),
+		UpdatedAt:        timex.Time(s.UpdatedAt),
+	}
+	if s.Deleted {
+		m.IsDeleted = 1
+	}
+	return m
+}
+
+// Get retrieves a setting of the user by key, tombstones included
+// Get 根据键获取用户的设置，包含已删除的记录
+func (r *userSettingRepository) Get(ctx context.Context, uid int64, key string) (*domain.UserSetting, error) {
+	var m model.UserSetting
+	if err := r.db().WithContext(ctx).Where("uid = ? AND setting_key = ?", uid, key).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomain(&m), nil
+}
+
+// ListSince lists the settings of the user updated after timestamp, tombstones included, oldest first
+// ListSince 按更新时间正序列出用户在 timestamp 之后更新的设置，包含已删除的记录
+func (r *userSettingRepository) ListSince(ctx context.Context, uid int64, timestamp int64) ([]*domain.UserSetting, error) {
+	var ms []*model.UserSetting
+	err := r.db().WithContext(ctx).
+		Where("uid = ? AND updated_timestamp > ?", uid, timestamp).
+		Order("updated_timestamp ASC, id ASC").
+		Find(&ms).Error
+	if err != nil {
+		return nil, err
+	}
+	settings := make([]*domain.UserSetting, 0, len(ms))
+	for _, m := range ms {
+		settings = append(settings, r.toDomain(m))
+	}
+	return settings, nil
+}
+
+// Create stores a new setting
+// Create 存储新的设置
+func (r *userSettingRepository) Create(ctx context.Context, setting *domain.UserSetting) (*domain.UserSetting, error) {
+	m := r.toModel(setting)
+	m.ID = 0
+	if err := r.db().WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomain(m), nil
+}
+
+// UpdateIfVersion overwrites the setting only while its stored version is still version; the condition
+// makes concurrent writers from several devices fail instead of silently overwriting each other
+// UpdateIfVersion 仅当存储的版本仍为 version 时覆盖该设置；条件更新使多台设备的并发写入失败而不是互相静默覆盖
+func (r *userSettingRepository) UpdateIfVersion(ctx context.Context, setting *domain.UserSetting, version int64) (bool, error) {
+	m := r.toModel(setting)
+	result := r.db().WithContext(ctx).Model(&model.UserSetting{}).
+		Where("id = ? AND uid = ? AND version = ?", m.ID, m.UID, version).
+		Updates(map[string]any{
+			"value":             m.Value,
+			"version":           m.Version,
+			"mtime":             m.Mtime,
+			"is_deleted":        m.IsDeleted,
+			"updated_timestamp": m.UpdatedTimestamp,
+			"updated_at":        m.UpdatedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeletePhysicalByTime physically removes the tombstones updated before timestamp
+// DeletePhysicalByTime 物理删除 timestamp 之前更新的删除标记
+func (r *userSettingRepository) DeletePhysicalByTime(ctx context.Context, timestamp int64) error {
+	return r.db().WithContext(ctx).
+		Where("is_deleted = 1 AND updated_timestamp < ?", timestamp).
+		Delete(&model.UserSetting{}).Error
+}
+
+var _ domain.UserSettingRepository = (*userSettingRepository)(nil)
@@ -0,0 +1,68 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUserSettingRepository verifies keys are unique per user and that a write based on a stale
+// version does not overwrite the stored setting.
+// TestUserSettingRepository 验证键在每个用户下唯一，且基于过期版本的写入不会覆盖已存储的设置。
+func TestUserSettingRepository(t *testing.T) {
+	daoInst, cleanup := setupCountByFIDsTestEnv(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewUserSettingRepository(daoInst)
+	now := time.Now()
+
+	created, err := repo.Create(ctx, &domain.UserSetting{UID: 1, Key: "theme", Value: "dark", Version: 1, UpdatedTimestamp: 100, CreatedAt: now, UpdatedAt: now})
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &domain.UserSetting{UID: 1, Key: "theme", Value: "light", Version: 1, UpdatedTimestamp: 101})
+	assert.Error(t, err)
+	_, err = repo.Create(ctx, &domain.UserSetting{UID: 2, Key: "theme", Value: "light", Version: 1, UpdatedTimestamp: 101})
+	require.NoError(t, err)
+
+	change := *created
+	change.Value = "light"
+	change.Version = 2
+	change.UpdatedTimestamp = 200
+	ok, err := repo.UpdateIfVersion(ctx, &change, 1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	change.Value = "stale"
+	change.Version = 2
+	ok, err = repo.UpdateIfVersion(ctx, &change, 1)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	got, err := repo.Get(ctx, 1, "theme")
+	require.NoError(t, err)
+	assert.Equal(t, "light", got.Value)
+	assert.Equal(t, int64(2), got.Version)
+
+	change = *got
+	change.Deleted = true
+	change.Version = 3
+	change.UpdatedTimestamp = 300
+	ok, err = repo.UpdateIfVersion(ctx, &change, 2)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	list, err := repo.ListSince(ctx, 1, 200)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.True(t, list[0].Deleted)
+
+	require.NoError(t, repo.DeletePhysicalByTime(ctx, 301))
+	_, err = repo.Get(ctx, 1, "theme")
+	assert.Error(t, err)
+	_, err = repo.Get(ctx, 2, "theme")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,110 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+)
+
+type userTOTPRepository struct {
+	dao *Dao
+}
+
+func NewUserTOTPRepository(dao *Dao) domain.UserTOTPRepository {
+	return &userTOTPRepository{dao: dao}
+}
+
+func init() {
+	RegisterModel(ModelConfig{
+		Name:     "UserTOTP",
+		IsMainDB: true,
+	})
+}
+
+func (r *userTOTPRepository) db() *gorm.DB {
+	db := r.dao.ResolveDB()
+	r.dao.QueryWithOnceInit(func(g *gorm.DB) {
+		model.AutoMigrate(g, "UserTOTP")
+	}, "user_totp#user_totp")
+	return db
+}
+
+func (r *userTOTPRepository) toDomain(m *model.UserTOTP) *domain.UserTOTP {
+	if m == nil {
+		return nil
+	}
+	var codes []string
+	if m.RecoveryCodes != "" {
+		_ = json.Unmarshal([]byte(m.RecoveryCodes), &codes)
+	}
+	return &domain.UserTOTP{
+		ID:            m.ID,
+		UID:           m.UID,
+		Secret:        m.Secret,
+		Enabled:       m.Enabled == 1,
+		RecoveryCodes: codes,
+		LastCounter:   m.LastCounter,
+		CreatedAt:     time.Time(m.CreatedAt),
+		UpdatedAt:     time.Time(m.UpdatedAt),
+	}
+}
+
+func (r *userTOTPRepository) toModel(t *domain.UserTOTP) *model.UserTOTP {
+	if t == nil {
+		return nil
+	}
+	codes := ""
+	if len(t.RecoveryCodes) > 0 {
+		b, _ := json.Marshal(t.RecoveryCodes)
+		codes = string(b)
+	}
+	var enabled int64
+	if t.Enabled {
+		enabled = 1
+	}
+	return &model.UserTOTP{
+		ID:            t.ID,
+		UID:           t.UID,
+		Secret:        t.Secret,
+		Enabled:       enabled,
+		RecoveryCodes: codes,
+		LastCounter:   t.LastCounter,
+		CreatedAt:     timex.Time(t.CreatedAt),
+		UpdatedAt:     timex.Time(t.UpdatedAt),
+	}
+}
+
+func (r *userTOTPRepository) GetByUID(ctx context.Context, uid int64) (*domain.UserTOTP, error) {
+	var m model.UserTOTP
+	if err := r.db().WithContext(ctx).Where("uid = ?", uid).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomain(&m), nil
+}
+
+func (r *userTOTPRepository) Save(ctx context.Context, t *domain.UserTOTP) (*domain.UserTOTP, error) {
+	m := r.toModel(t)
+	m.UpdatedAt = timex.Now()
+	if m.ID == 0 {
+		m.CreatedAt = timex.Now()
+		if err := r.db().WithContext(ctx).Create(m).Error; err != nil {
+			return nil, err
+		}
+		return r.toDomain(m), nil
+	}
+	if err := r.db().WithContext(ctx).Save(m).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomain(m), nil
+}
+
+func (r *userTOTPRepository) DeleteByUID(ctx context.Context, uid int64) error {
+	return r.db().WithContext(ctx).Where("uid = ?", uid).Delete(&model.UserTOTP{}).Error
+}
+
+var _ domain.UserTOTPRepository = (*userTOTPRepository)(nil)
@@ -94,28 +94,39 @@ func (r *vaultRepository) toModel(vault *domain.Vault) *model.Vault {
 // GetByID retrieves vault by ID
 // GetByID 根据ID获取仓库
 func (r *vaultRepository) GetByID(ctx context.Context, id, uid int64) (*domain.Vault, error) {
+	if m, ok := r.dao.lookups.getVault(vaultLookupKey{uid: uid, id: id}); ok {
+		return r.toDomain(m), nil
+	}
+	gen := r.dao.lookups.generation(uid)
 	u := r.vault(uid).Vault
 	m, err := u.WithContext(ctx).Where(u.ID.Eq(id), u.IsDeleted.Eq(0)).First()
 	if err != nil {
 		return nil, err
 	}
+	r.dao.lookups.addVault(uid, gen, m)
 	return r.toDomain(m), nil
 }
 
 // GetByName retrieves vault by name
 // GetByName 根据名称获取仓库
 func (r *vaultRepository) GetByName(ctx context.Context, name string, uid int64) (*domain.Vault, error) {
+	if m, ok := r.dao.lookups.getVault(vaultLookupKey{uid: uid, name: name}); ok {
+		return r.toDomain(m), nil
+	}
+	gen := r.dao.lookups.generation(uid)
 	u := r.vault(uid).Vault
 	m, err := u.WithContext(ctx).Where(u.Vault.Eq(name), u.IsDeleted.Eq(0)).First()
 	if err != nil {
 		return nil, err
 	}
+	r.dao.lookups.addVault(uid, gen, m)
 	return r.toDomain(m), nil
 }
 
 // Create creates a vault
 // Create 创建仓库
 func (r *vaultRepository) Create(ctx context.Context, vault *domain.Vault, uid int64) (*domain.Vault, error) {
+	defer r.dao.lookups.invalidateVaults(uid)
 	var result *domain.Vault
 	var createErr error
 
@@ -149,6 +160,7 @@ func (r *vaultRepository) Create(ctx context.Context, vault *domain.Vault, uid i
 // Update updates a vault
 // Update 更新仓库
 func (r *vaultRepository) Update(ctx context.Context, vault *domain.Vault, uid int64) error {
+	defer r.dao.lookups.invalidateVaults(uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := query.Use(db).Vault
 		m := r.toModel(vault)
@@ -161,6 +173,7 @@ func (r *vaultRepository) Update(ctx context.Context, vault *domain.Vault, uid i
 // UpdateNoteCountSize updates the note count and size of the vault
 // UpdateNoteCountSize 更新仓库的笔记数量和大小
 func (r *vaultRepository) UpdateNoteCountSize(ctx context.Context, noteSize, noteCount, vaultID, uid int64) error {
+	defer r.dao.lookups.invalidateVaults(uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := query.Use(db).Vault
 
@@ -178,6 +191,7 @@ func (r *vaultRepository) UpdateNoteCountSize(ctx context.Context, noteSize, not
 // UpdateFileCountSize updates the file count and size of the vault
 // UpdateFileCountSize 更新仓库的文件数量和大小
 func (r *vaultRepository) UpdateFileCountSize(ctx context.Context, fileSize, fileCount, vaultID, uid int64) error {
+	defer r.dao.lookups.invalidateVaults(uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := query.Use(db).Vault
 
@@ -218,6 +232,7 @@ func (r *vaultRepository) List(ctx context.Context, uid int64) ([]*domain.Vault,
 // Delete deletes the vault (soft delete)
 // Delete 删除仓库（软删除）
 func (r *vaultRepository) Delete(ctx context.Context, id, uid int64) error {
+	defer r.dao.lookups.invalidateVaults(uid)
 	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
 		u := query.Use(db).Vault
 
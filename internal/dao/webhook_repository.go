@@ -0,0 +1,287 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/model"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"gorm.io/gorm"
+)
+
+// webhookRepository implements domain.WebhookRepository
+// webhookRepository 实现 domain.WebhookRepository 接口
+type webhookRepository struct {
+	dao             *Dao
+	customPrefixKey string
+	migrateOnce     sync.Map // tracks per-key migration completion // 记录每个 key 是否已完成 AutoMigrate
+}
+
+// NewWebhookRepository creates a WebhookRepository instance
+// NewWebhookRepository 创建 WebhookRepository 实例
+func NewWebhookRepository(dao *Dao) domain.WebhookRepository {
+	return &webhookRepository{dao: dao, customPrefixKey: "user_webhook_"}
+}
+
+// GetKey returns the database routing key for the given user
+// GetKey 返回指定用户的数据库路由键
+func (r *webhookRepository) GetKey(uid int64) string {
+	return r.customPrefixKey + strconv.FormatInt(uid, 10)
+}
+
+func init() {
+	for _, name := range []string{"Webhook", "WebhookDelivery"} {
+		RegisterModel(ModelConfig{
+			Name: name,
+			RepoFactory: func(d *Dao) daoDBCustomKey {
+				return NewWebhookRepository(d).(daoDBCustomKey)
+			},
+			IsMainDB: false,
+		})
+	}
+}
+
+// db returns the *gorm.DB of the user's webhook database, with one-time AutoMigrate
+// db 返回用户 Webhook 库的 *gorm.DB，确保每个用户库只迁移一次
+func (r *webhookRepository) db(uid int64) *gorm.DB {
+	key := r.GetKey(uid)
+	if _, loaded := r.migrateOnce.LoadOrStore(key+"#webhook", true); !loaded {
+		if db := r.dao.ResolveDB(key); db != nil {
+			model.AutoMigrate(db, "Webhook")
+			model.AutoMigrate(db, "WebhookDelivery")
+		}
+	}
+	return r.dao.ResolveDB(key)
+}
+
+// webhookToDomain converts the database model to the domain model
+// webhookToDomain 将数据库模型转换为领域模型
+func webhookToDomain(m *model.Webhook) *domain.Webhook {
+	w := &domain.Webhook{
+		ID:        m.ID,
+		UID:       m.UID,
+		Name:      m.Name,
+		URL:       m.URL,
+		Secret:    m.Secret,
+		IsEnabled: m.IsEnabled == 1,
+		CreatedAt: time.Time(m.CreatedAt),
+		UpdatedAt: time.Time(m.UpdatedAt),
+	}
+	for _, event := range strings.Split(m.Events, ",") {
+		if event != "" {
+			w.Events = append(w.Events, domain.WebhookEvent(event))
+		}
+	}
+	return w
+}
+
+// webhookToModel converts the domain model to the database model
+// webhookToModel 将领域模型转换为数据库模型
+func webhookToModel(w *domain.Webhook) *model.Webhook {
+	events := make([]string, 0, len(w.Events))
+	for _, event := range w.Events {
+		events = append(events, string(event))
+	}
+	m := &model.Webhook{
+		ID:        w.ID,
+		UID:       w.UID,
+		Name:      w.Name,
+		URL:       w.URL,
+		Secret:    w.Secret,
+		Events:    strings.Join(events, ","),
+		CreatedAt: timex.Time(w.CreatedAt),
+		UpdatedAt: timex.Time(w.UpdatedAt),
+	}
+	if w.IsEnabled {
+		m.IsEnabled = 1
+	}
+	return m
+}
+
+// deliveryToDomain converts the database model to the domain model
+// deliveryToDomain 将数据库模型转换为领域模型
+func deliveryToDomain(m *model.WebhookDelivery) *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		ID:        m.ID,
+		UID:       m.UID,
+		WebhookID: m.WebhookID,
+		Event:     domain.WebhookEvent(m.Event),
+		Payload:   m.Payload,
+		Status:    domain.WebhookDeliveryStatus(m.Status),
+		Attempts:  int(m.Attempts),
+		Error:     m.Error,
+		CreatedAt: time.Time(m.CreatedAt),
+		UpdatedAt: time.Time(m.UpdatedAt),
+	}
+}
+
+// List lists all webhooks of a user
+// List 列出用户的全部 Webhook
+func (r *webhookRepository) List(ctx context.Context, uid int64) ([]*domain.Webhook, error) {
+	var rows []*model.Webhook
+	if err := r.db(uid).WithContext(ctx).Where("uid = ?", uid).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	results := make([]*domain.Webhook, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, webhookToDomain(m))
+	}
+	return results, nil
+}
+
+// Get returns a webhook, nil when it does not exist
+// Get 获取 Webhook，不存在时返回 nil
+func (r *webhookRepository) Get(ctx context.Context, id, uid int64) (*domain.Webhook, error) {
+	var m model.Webhook
+	err := r.db(uid).WithContext(ctx).Where("id = ? AND uid = ?", id, uid).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return webhookToDomain(&m), nil
+}
+
+// Save creates the webhook when ID is 0, otherwise updates it
+// Save ID 为 0 时新建 Webhook，否则更新
+func (r *webhookRepository) Save(ctx context.Context, webhook *domain.Webhook, uid int64) (*domain.Webhook, error) {
+	var result *domain.Webhook
+	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		m := webhookToModel(webhook)
+		m.UID = uid
+		m.UpdatedAt = timex.Now()
+		if m.ID == 0 {
+			m.CreatedAt = m.UpdatedAt
+			if err := r.db(uid).WithContext(ctx).Create(m).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := r.db(uid).WithContext(ctx).Where("id = ? AND uid = ?", m.ID, uid).
+				Select("name", "url", "secret", "events", "is_enabled", "updated_at").Updates(m).Error; err != nil {
+				return err
+			}
+		}
+		result = webhookToDomain(m)
+		return nil
+	})
+	return result, err
+}
+
+// Delete removes a webhook together with its delivery log
+// Delete 删除 Webhook 及其投递日志
+func (r *webhookRepository) Delete(ctx context.Context, id, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("webhook_id = ? AND uid = ?", id, uid).Delete(&model.WebhookDelivery{}).Error; err != nil {
+				return err
+			}
+			return tx.Where("id = ? AND uid = ?", id, uid).Delete(&model.Webhook{}).Error
+		})
+	})
+}
+
+// CreateDelivery stores a new delivery
+// CreateDelivery 存储新的投递记录
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery, uid int64) (*domain.WebhookDelivery, error) {
+	var result *domain.WebhookDelivery
+	err := r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		m := &model.WebhookDelivery{
+			UID:       uid,
+			WebhookID: delivery.WebhookID,
+			Event:     string(delivery.Event),
+			Payload:   delivery.Payload,
+			Status:    string(delivery.Status),
+			Attempts:  int64(delivery.Attempts),
+			Error:     delivery.Error,
+			CreatedAt: timex.Time(delivery.CreatedAt),
+			UpdatedAt: timex.Time(delivery.UpdatedAt),
+		}
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = timex.Now()
+		}
+		if m.UpdatedAt.IsZero() {
+			m.UpdatedAt = m.CreatedAt
+		}
+		if err := r.db(uid).WithContext(ctx).Create(m).Error; err != nil {
+			return err
+		}
+		result = deliveryToDomain(m)
+		return nil
+	})
+	return result, err
+}
+
+// UpdateDelivery stores the outcome of an attempt
+// UpdateDelivery 存储一次尝试的结果
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery, uid int64) error {
+	return r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+		return r.db(uid).WithContext(ctx).Model(&model.WebhookDelivery{}).Where("id = ? AND uid = ?", delivery.ID, uid).
+			Updates(map[string]any{
+				"status":     string(delivery.Status),
+				"attempts":   delivery.Attempts,
+				"error":      delivery.Error,
+				"updated_at": timex.Now(),
+			}).Error
+	})
+}
+
+// ListDeliveries lists deliveries of a user, newest first; webhookID 0 lists every webhook
+// ListDeliveries 按时间倒序分页列出用户的投递记录；webhookID 为 0 时列出全部 Webhook
+func (r *webhookRepository) ListDeliveries(ctx context.Context, webhookID, uid int64, page, pageSize int) ([]*domain.WebhookDelivery, int64, error) {
+	query := r.db(uid).WithContext(ctx).Model(&model.WebhookDelivery{}).Where("uid = ?", uid)
+	if webhookID > 0 {
+		query = query.Where("webhook_id = ?", webhookID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+
+	var rows []*model.WebhookDelivery
+	if err := query.Order("id DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]*domain.WebhookDelivery, 0, len(rows))
+	for _, m := range rows {
+		results = append(results, deliveryToDomain(m))
+	}
+	return results, total, nil
+}
+
+// CleanupDeliveriesByTimeAll removes deliveries older than the given timestamp for all users
+// CleanupDeliveriesByTimeAll 清理所有用户在指定时间戳之前的投递记录
+func (r *webhookRepository) CleanupDeliveriesByTimeAll(ctx context.Context, timestamp int64) error {
+	uids, err := r.dao.GetAllUserUIDs()
+	if err != nil {
+		return err
+	}
+
+	for i, uid := range uids {
+		if i > 0 {
+			time.Sleep(100 * time.Millisecond) // Slight delay to reduce bursts
+		}
+		_ = r.dao.ExecuteWrite(ctx, uid, r, func(db *gorm.DB) error {
+			return r.db(uid).WithContext(ctx).Where("created_at < ?", time.UnixMilli(timestamp)).Delete(&model.WebhookDelivery{}).Error
+		})
+	}
+	return nil
+}
+
+// Ensure webhookRepository implements domain.WebhookRepository
+// 确保 webhookRepository 实现了 domain.WebhookRepository 接口
+var _ domain.WebhookRepository = (*webhookRepository)(nil)
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AlertChannel a user's alert destination for failed backups
+// AlertChannel 用户接收备份失败告警的目标
+type AlertChannel struct {
+	ID        int64     // Primary Key // 主键
+	UID       int64     // Owner User ID // 所有者用户 ID
+	Name      string    // Display name // 显示名称
+	Type      string    // email, telegram, gotify or ntfy // email、telegram、gotify 或 ntfy
+	To        string    // email: comma separated recipients // email：逗号分隔的收件人
+	URL       string    // gotify: server URL; ntfy: topic URL; telegram: optional Bot API root // gotify：服务器地址；ntfy：主题地址；telegram：可选的 Bot API 根地址
+	Token     string    // Bot, application or access token // 机器人、应用或访问令牌
+	ChatID    string    // telegram: chat ID // telegram：会话 ID
+	Priority  int       // gotify / ntfy priority, 0 uses the server default // gotify / ntfy 优先级，0 使用服务器默认值
+	IsEnabled bool      // Whether alerts are sent // 是否发送告警
+	CreatedAt time.Time // Creation Time // 创建时间
+	UpdatedAt time.Time // Update Time // 更新时间
+}
+
+// AlertChannelRepository defines the alert channel repository interface
+// AlertChannelRepository 定义告警渠道仓储接口
+type AlertChannelRepository interface {
+	// List lists all alert channels of a user
+	// List 列出用户的全部告警渠道
+	List(ctx context.Context, uid int64) ([]*AlertChannel, error)
+
+	// Get returns an alert channel, nil when it does not exist
+	// Get 获取告警渠道，不存在时返回 nil
+	Get(ctx context.Context, id, uid int64) (*AlertChannel, error)
+
+	// Save creates the channel when ID is 0, otherwise updates it
+	// Save ID 为 0 时新建渠道，否则更新
+	Save(ctx context.Context, channel *AlertChannel, uid int64) (*AlertChannel, error)
+
+	// Delete removes an alert channel
+	// Delete 删除告警渠道
+	Delete(ctx context.Context, id, uid int64) error
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AuditAction action recorded in the audit log
+// AuditAction 审计日志记录的操作
+type AuditAction string
+
+const (
+	AuditActionLogin         AuditAction = "user.login"          // Successful login // 登录成功
+	AuditActionLoginFailed   AuditAction = "user.login_failed"   // Failed login // 登录失败
+	AuditActionLoginLocked   AuditAction = "user.login_locked"   // Repeated failed logins locked an IP or account out // 多次登录失败导致 IP 或账号被锁定
+	AuditActionUserDelete    AuditAction = "user.delete"         // Admin deleted (blocked) a user // 管理员删除（禁用）用户
+	AuditActionUserApprove   AuditAction = "user.approve"        // Admin approved a pending registration // 管理员通过待审核的注册
+	AuditActionNoteDelete    AuditAction = "note.delete"         // Note deleted // 删除笔记
+	AuditActionNoteRestore   AuditAction = "note.restore"        // Note restored from the trash // 从回收站恢复笔记
+	AuditActionFileRestore   AuditAction = "file.restore"        // Attachment restored from the trash // 从回收站恢复附件
+	AuditActionVaultTransfer AuditAction = "vault.transfer"      // Admin moved a vault to another user // 管理员将保险库转移给其他用户
+	AuditActionConfigUpdate  AuditAction = "admin.config_update" // Admin changed the server configuration // 管理员修改服务器配置
+	AuditActionReadOnly      AuditAction = "admin.read_only"     // Admin switched the read-only maintenance mode // 管理员切换只读维护模式
+	AuditActionSyncTrace     AuditAction = "admin.sync_trace"    // Admin switched the sync tracing of a user // 管理员切换用户的同步跟踪
+	AuditActionJobRequeue    AuditAction = "admin.job_requeue"   // Admin requeued failed background jobs // 管理员重新排队失败的后台任务
+	AuditActionTaskRun       AuditAction = "admin.task_run"      // Admin ran a scheduled task by hand // 管理员手动执行定时任务
+	AuditActionBackupExecute AuditAction = "backup.execute"      // Backup executed manually // 手动执行备份
+)
+
+// AuditActions all recorded actions
+// AuditActions 全部记录的操作
+var AuditActions = []AuditAction{
+	AuditActionLogin,
+	AuditActionLoginFailed,
+	AuditActionLoginLocked,
+	AuditActionUserDelete,
+	AuditActionUserApprove,
+	AuditActionNoteDelete,
+	AuditActionNoteRestore,
+	AuditActionFileRestore,
+	AuditActionVaultTransfer,
+	AuditActionConfigUpdate,
+	AuditActionReadOnly,
+	AuditActionSyncTrace,
+	AuditActionJobRequeue,
+	AuditActionTaskRun,
+	AuditActionBackupExecute,
+}
+
+// AuditLog who did what, from where and when
+// AuditLog 谁在何时从何处做了什么
+type AuditLog struct {
+	ID         int64       // Primary Key // 主键
+	UID        int64       // Acting user, 0 when unknown (e.g. failed login) // 操作用户，未知时为 0（例如登录失败）
+	Action     AuditAction // Action // 操作
+	Target     string      // Object acted on, e.g. vault/path, config section, user ID // 操作对象，例如 笔记库/路径、配置项、用户 ID
+	Detail     string      // Additional details // 附加信息
+	IP         string      // Request IP // 请求 IP
+	ClientType string      // Client type // 客户端类型
+	ClientName string      // Client name // 客户端名称
+	CreatedAt  time.Time   // Time of the action // 操作时间
+}
+
+// AuditLogFilter filter of an audit log query, zero values match everything
+// AuditLogFilter 审计日志查询条件，零值表示不限制
+type AuditLogFilter struct {
+	UID          int64
+	Action       AuditAction
+	Actions      []AuditAction // Any of these actions // 任一操作
+	TargetPrefix string        // Target starts with, e.g. "vault/" // 操作对象前缀，例如 "笔记库/"
+	IP           string
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+// AuditLogRepository defines the audit log repository interface
+// AuditLogRepository 定义审计日志仓储接口
+type AuditLogRepository interface {
+	// Create stores an audit log entry
+	// Create 存储一条审计日志
+	Create(ctx context.Context, log *AuditLog) error
+
+	// List lists audit log entries matching filter, newest first
+	// List 按时间倒序分页列出符合条件的审计日志
+	List(ctx context.Context, filter *AuditLogFilter, page, pageSize int) ([]*AuditLog, int64, error)
+
+	// DeleteBefore removes audit log entries older than cutoff
+	// DeleteBefore 删除早于 cutoff 的审计日志
+	DeleteBefore(ctx context.Context, cutoff time.Time) error
+}
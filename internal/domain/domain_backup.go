@@ -19,7 +19,7 @@ type BackupConfig struct {
 	ID               int64
 	UID              int64
 	VaultID          int64     // 关联库 ID (0 表示所有库)
-	Type             string    // full, incremental, sync
+	Type             string    // full, incremental, sync, dedupe
 	StorageIds       string    // JSON 数组，如 "[1, 2]"
 	IsEnabled        bool      // 是否启用
 	CronStrategy     string    // daily, weekly, monthly, custom
@@ -30,6 +30,9 @@ type BackupConfig struct {
 	NextRunTime      time.Time // 下次运行时间
 	PasswordMode     int       // 密码模式 (0: 无密码, 1: 固定密码, 2: 随机密码)
 	PasswordValue    string    // 固定密码值
+	WebhookURL       string    // 备份报告 Webhook 地址（为空表示不推送）
+	WebhookSecret    string    // 备份报告签名密钥
+	PingURL          string    // 健康检查 Ping 地址（兼容 healthchecks.io，为空表示不 Ping）
 	LastStatus       int       // 上次状态 (0: Idle, 1: Running, 2: Success, 3: Failed, 4: Stopped, 5: SuccessNoUpdate)
 	LastMessage      string    // 上次运行结果消息
 	CreatedAt        time.Time
@@ -38,21 +41,23 @@ type BackupConfig struct {
 
 // BackupHistory 备份历史领域模型
 type BackupHistory struct {
-	ID        int64
-	UID       int64
-	ConfigID  int64
-	StorageID int64
-	Type      string // full, incremental, sync
-	StartTime time.Time
-	EndTime   time.Time
-	Status    int // 0: Idle, 1: Running, 2: Success, 3: Failed, 4: Stopped, 5: SuccessNoUpdate
-	FileSize  int64
-	FileCount int64
-	Message   string
-	FilePath  string
-	Password  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          int64
+	UID         int64
+	ConfigID    int64
+	StorageID   int64
+	Type        string // full, incremental, sync, dedupe
+	StartTime   time.Time
+	EndTime     time.Time
+	Status      int // 0: Idle, 1: Running, 2: Success, 3: Failed, 4: Stopped, 5: SuccessNoUpdate
+	FileSize    int64
+	FileCount   int64
+	Message     string
+	FilePath    string
+	Password    string
+	ArchiveSize int64  // 上传对象的字节数（归档 zip 或去重清单）
+	Checksum    string // 上传对象的 SHA-256（十六进制）
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 // BackupRepository 备份仓储接口
@@ -74,6 +79,9 @@ type BackupRepository interface {
 	CreateHistory(ctx context.Context, history *BackupHistory, uid int64) (*BackupHistory, error)
 	// ListHistory 分页获取备份历史记录
 	ListHistory(ctx context.Context, uid int64, configID int64, page, pageSize int) ([]*BackupHistory, int64, error)
+	// GetHistory Get a history record by ID
+	// 根据ID获取备份历史记录
+	GetHistory(ctx context.Context, id, uid int64) (*BackupHistory, error)
 	// ListOldHistory List old history records created before cutoffTime
 	// 获取早于 cutoffTime 的历史记录
 	ListOldHistory(ctx context.Context, uid int64, configID int64, cutoffTime time.Time) ([]*BackupHistory, error)
@@ -81,6 +89,10 @@ type BackupRepository interface {
 	// 删除早于 cutoffTime 的历史记录
 	DeleteOldHistory(ctx context.Context, uid int64, configID int64, cutoffTime time.Time) error
 
+	// CountHistoryByStatus Count history records created since the given time per status
+	// 按状态统计自指定时间以来创建的历史记录数量
+	CountHistoryByStatus(ctx context.Context, uid int64, since time.Time) (map[int]int64, error)
+
 	// DisableByVaultID 禁用仓库下的备份任务
 	DisableByVaultID(ctx context.Context, vaultID, uid int64) error
 }
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// BackupBlob 去重备份上传到存储目标的内容寻址数据块
+type BackupBlob struct {
+	ID        int64
+	UID       int64
+	ConfigID  int64
+	StorageID int64
+	Hash      string    // SHA-256 十六进制摘要，同时作为远端对象名
+	Size      int64     // 字节数
+	LastRefAt time.Time // 最近一次引用该数据块的运行开始时间
+	CreatedAt time.Time
+}
+
+// BackupBlobRepository 去重备份数据块仓储接口
+type BackupBlobRepository interface {
+	// ListHashes 列出某配置在某存储目标上已上传的数据块摘要
+	ListHashes(ctx context.Context, configID, storageID, uid int64) ([]string, error)
+	// Touch 登记本次运行引用的数据块，新数据块插入，已有数据块更新 LastRefAt
+	Touch(ctx context.Context, blobs []*BackupBlob, refAt time.Time, uid int64) error
+	// ListUnreferenced 列出 LastRefAt 早于 before 的数据块，即不再被任何保留清单引用的数据块
+	ListUnreferenced(ctx context.Context, configID, storageID int64, before time.Time, uid int64) ([]*BackupBlob, error)
+	// Delete 删除数据块记录
+	Delete(ctx context.Context, ids []int64, uid int64) error
+}
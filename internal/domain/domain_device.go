@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Device a client device that has connected over WebSocket
+// Device 通过 WebSocket 连接过的客户端设备
+type Device struct {
+	ID            int64     // Primary Key // 主键
+	UID           int64     // Owner User ID // 所有者用户 ID
+	DeviceKey     string    // Stable device identifier, unique per user // 稳定的设备标识，按用户唯一
+	TokenID       int64     // Token the device authenticated with // 设备鉴权使用的令牌
+	ClientName    string    // Client name, e.g. "Mac", "iPhone" // 客户端名称，如 "Mac"、"iPhone"
+	ClientType    string    // Client type "web" | "desktop" | "mobile" | "obsidianPlugin" // 客户端类型
+	ClientVersion string    // Client version // 客户端版本
+	Platform      []string  // Platform flags reported as true, e.g. isDesktop, isMacOS // 上报为 true 的平台标记，如 isDesktop、isMacOS
+	IP            string    // Last client IP // 最近一次的客户端 IP
+	UserAgent     string    // Last User-Agent // 最近一次的 User-Agent
+	LastSeenAt    time.Time // Last authentication or client info report // 最近一次鉴权或上报客户端信息的时间
+	CreatedAt     time.Time // First Seen Time // 首次出现时间
+	UpdatedAt     time.Time // Update Time // 更新时间
+}
+
+// DeviceRepository defines the device repository interface
+// DeviceRepository 定义设备仓储接口
+type DeviceRepository interface {
+	// ListByUID lists the devices of a user, most recently seen first
+	// ListByUID 按最近出现时间倒序列出用户的设备
+	ListByUID(ctx context.Context, uid int64) ([]*Device, error)
+
+	// Get returns a device, nil when it does not exist
+	// Get 获取设备，不存在时返回 nil
+	Get(ctx context.Context, id, uid int64) (*Device, error)
+
+	// Upsert creates the device or refreshes the device with the same UID and DeviceKey
+	// Upsert 新建设备，或刷新 UID 与 DeviceKey 相同的设备
+	Upsert(ctx context.Context, device *Device) (*Device, error)
+
+	// Delete removes a device
+	// Delete 删除设备
+	Delete(ctx context.Context, id, uid int64) error
+}
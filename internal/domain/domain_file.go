@@ -33,6 +33,20 @@ type File struct {
 	UpdatedAt        time.Time
 }
 
+// FileMove 文件移动结果：标记为重命名删除的旧记录与新路径下的记录
+type FileMove struct {
+	Old *File
+	New *File
+}
+
+// FileOrphan 孤立附件内容：磁盘上存在但已没有对应文件记录的附件目录
+type FileOrphan struct {
+	FileID  int64     // 目录名中的文件 ID
+	Path    string    // 附件目录路径
+	Size    int64     // 目录内文件总大小（字节）
+	ModTime time.Time // 目录及其文件最近的修改时间
+}
+
 // IsDeleted 判断文件是否已删除
 func (f *File) IsDeleted() bool {
 	return f.Action == FileActionDelete
@@ -89,6 +103,10 @@ type FileRepository interface {
 	// DeletePhysicalByTime 根据时间物理删除已标记删除的文件
 	DeletePhysicalByTime(ctx context.Context, timestamp, uid int64) error
 
+	// DeletePhysicalByTimeInVaults physically deletes files marked as deleted by time, only in vaultIDs or, with exclude, outside them
+	// DeletePhysicalByTimeInVaults 根据时间物理删除已标记删除的文件，仅限 vaultIDs 中的保险库，exclude 时则排除这些保险库
+	DeletePhysicalByTimeInVaults(ctx context.Context, timestamp int64, vaultIDs []int64, exclude bool, uid int64) error
+
 	// DeletePhysicalByTimeAll 根据时间物理删除所有用户的已标记删除的文件
 	DeletePhysicalByTimeAll(ctx context.Context, timestamp int64) error
 
@@ -131,9 +149,21 @@ type FileRepository interface {
 	// ListByPathPrefix 根据路径前缀获取文件列表
 	ListByPathPrefix(ctx context.Context, pathPrefix string, vaultID, uid int64) ([]*File, error)
 
+	// MoveByPathPrefix 在单个事务内将前缀下的文件移动到新前缀下，fids 为新目录路径到文件夹 ID 的映射
+	MoveByPathPrefix(ctx context.Context, oldPrefix, newPrefix string, fids map[string]int64, vaultID, uid int64) ([]*FileMove, error)
+
 	// RecycleClear 清理回收站
 	RecycleClear(ctx context.Context, path, pathHash string, vaultID, uid int64) error
 
 	// DeleteByVaultID 物理删除仓库下的所有文件
 	DeleteByVaultID(ctx context.Context, vaultID, uid int64) error
+
+	// ListOrphanContent 列出没有对应文件记录的附件目录，已软删除的记录仍视为引用（可恢复）
+	ListOrphanContent(ctx context.Context, uid int64) ([]*FileOrphan, error)
+
+	// RemoveOrphanContent 再次确认文件记录不存在后删除附件目录，返回是否已删除
+	RemoveOrphanContent(ctx context.Context, fileID, uid int64) (bool, error)
+
+	// FetchContent 确保附件内容位于本地 SavePath，未缓存时从内容存储下载；内容存储中也不存在时不报错，交由调用方的缺失处理
+	FetchContent(ctx context.Context, file *File, uid int64) error
 }
@@ -23,6 +23,7 @@ type Folder struct {
 	PathHash         string
 	Level            int64
 	FID              int64
+	Archived         bool // 归档（只读）
 	Ctime            int64
 	Mtime            int64
 	UpdatedTimestamp int64
@@ -30,6 +31,19 @@ type Folder struct {
 	UpdatedAt        time.Time
 }
 
+// FolderMove 文件夹移动结果：标记删除的旧记录与新路径下的记录
+type FolderMove struct {
+	Old *Folder
+	New *Folder
+}
+
+// FolderEnsureResult EnsurePath 对路径上每一级文件夹的处理结果
+type FolderEnsureResult struct {
+	Folder   *Folder
+	Created  bool // 本次新建
+	Restored bool // 本次由删除状态恢复
+}
+
 // IsDeleted 判断文件夹是否已删除
 func (f *Folder) IsDeleted() bool {
 	return f.Action == FolderActionDelete
@@ -64,11 +78,23 @@ type FolderRepository interface {
 	// ListByPathPrefix 获取指定路径下的子文件夹
 	ListByPathPrefix(ctx context.Context, pathPrefix string, vaultID, uid int64) ([]*Folder, error)
 
+	// EnsurePath 在单个事务内确保路径上每一级文件夹都存在且有效，按层级顺序返回
+	EnsurePath(ctx context.Context, path string, vaultID, uid int64) ([]*FolderEnsureResult, error)
+
+	// MoveByPathPrefix 在单个事务内将文件夹及其子文件夹移动到新路径，fid 为新路径父文件夹的 ID
+	MoveByPathPrefix(ctx context.Context, oldPath, newPath string, fid int64, vaultID, uid int64) ([]*FolderMove, error)
+
 	// List 获取指定仓库下的所有文件夹
 	List(ctx context.Context, vaultID int64, uid int64) ([]*Folder, error)
 	// ListAll 获取该用户所有的文件夹
 	ListAll(ctx context.Context, uid int64) ([]*Folder, error)
 
+	// SetArchived 设置或清除指定路径下所有文件夹记录的归档标记
+	SetArchived(ctx context.Context, pathHash string, vaultID int64, archived bool, uid int64) error
+
+	// ListArchived 获取仓库下所有未删除的归档文件夹
+	ListArchived(ctx context.Context, vaultID, uid int64) ([]*Folder, error)
+
 	// DeleteByVaultID 删除仓库下的所有文件夹记录
 	DeleteByVaultID(ctx context.Context, vaultID, uid int64) error
 }
@@ -30,6 +30,7 @@ type GitSyncConfig struct {
 	LastMessage     string     `json:"lastMessage"`
 	IncludeConfig   bool       `json:"includeConfig" gorm:"column:include_config"`
 	ConfigSyncRules []string   `json:"configSyncRules" gorm:"column:config_sync_rules;type:text;serializer:json"`
+	PingURL         string     `json:"pingUrl"` // 健康检查 Ping 地址（兼容 healthchecks.io，为空表示不 Ping）
 	CreatedAt       time.Time  `json:"createdAt"`
 	UpdatedAt       time.Time  `json:"updatedAt"`
 }
@@ -3,6 +3,7 @@ package domain
 
 import (
 	"context"
+	"regexp"
 	"time"
 )
 
@@ -46,6 +47,31 @@ type CountSizeResult struct {
 	Size  int64
 }
 
+// NoteMove 笔记移动结果：标记为重命名删除的旧记录与新路径下的记录
+type NoteMove struct {
+	Old *Note
+	New *Note
+}
+
+// NoteRegexQuery 笔记正文正则搜索条件
+// MaxFileSize 超出的笔记跳过不扫描；扫描达到 MaxResults 或 Timeout 时提前结束并标记结果被截断
+type NoteRegexQuery struct {
+	Pattern         *regexp.Regexp
+	IsRecycle       bool
+	SortBy          string
+	SortOrder       string
+	ExcludePrefixes []string
+	MaxFileSize     int64
+	MaxResults      int
+	Timeout         time.Duration
+}
+
+// NoteRegexMatch 正则搜索命中的笔记（Note.Content 已加载）及其匹配次数
+type NoteRegexMatch struct {
+	Note       *Note
+	MatchCount int
+}
+
 // IsDeleted 判断笔记是否已删除
 func (n *Note) IsDeleted() bool {
 	return n.Action == NoteActionDelete
@@ -85,6 +111,9 @@ type NoteRepository interface {
 	// GetByPath 根据路径获取笔记
 	GetByPath(ctx context.Context, path string, vaultID, uid int64) (*Note, error)
 
+	// GetByPathLike 根据路径后缀获取笔记（排除已删除）
+	GetByPathLike(ctx context.Context, path string, vaultID, uid int64) (*Note, error)
+
 	// Create 创建笔记
 	Create(ctx context.Context, note *Note, uid int64) (*Note, error)
 
@@ -115,6 +144,10 @@ type NoteRepository interface {
 	// DeletePhysicalByTime 根据时间物理删除已标记删除的笔记
 	DeletePhysicalByTime(ctx context.Context, timestamp, uid int64) error
 
+	// DeletePhysicalByTimeInVaults physically deletes notes marked as deleted by time, only in vaultIDs or, with exclude, outside them
+	// DeletePhysicalByTimeInVaults 根据时间物理删除已标记删除的笔记，仅限 vaultIDs 中的保险库，exclude 时则排除这些保险库
+	DeletePhysicalByTimeInVaults(ctx context.Context, timestamp int64, vaultIDs []int64, exclude bool, uid int64) error
+
 	// DeletePhysicalByTimeAll 根据时间物理删除所有用户的已标记删除的笔记
 	DeletePhysicalByTimeAll(ctx context.Context, timestamp int64) error
 
@@ -123,11 +156,15 @@ type NoteRepository interface {
 	// sortBy: mtime(默认), ctime, path
 	// sortOrder: desc(默认), asc
 	// paths: 逗号分隔的精确路径列表，非空时忽略 keyword 做 IN 查询
-	List(ctx context.Context, vaultID int64, page, pageSize int, uid int64, keyword string, isRecycle bool, searchMode string, searchContent bool, sortBy string, sortOrder string, paths []string) ([]*Note, error)
+	// excludePrefixes: 关键词搜索时排除的目录（如归档文件夹）
+	List(ctx context.Context, vaultID int64, page, pageSize int, uid int64, keyword string, isRecycle bool, searchMode string, searchContent bool, sortBy string, sortOrder string, paths []string, excludePrefixes []string) ([]*Note, error)
 
 	// ListCount 获取笔记数量
 	// searchMode: path(默认), content, regex
-	ListCount(ctx context.Context, vaultID, uid int64, keyword string, isRecycle bool, searchMode string, searchContent bool, paths []string) (int64, error)
+	ListCount(ctx context.Context, vaultID, uid int64, keyword string, isRecycle bool, searchMode string, searchContent bool, paths []string, excludePrefixes []string) (int64, error)
+
+	// ListByContentRegex 按列表排序逐条读取正文文件做正则匹配，返回命中的笔记；truncated 表示因数量或时间限制提前结束
+	ListByContentRegex(ctx context.Context, vaultID, uid int64, query *NoteRegexQuery) (matches []*NoteRegexMatch, truncated bool, err error)
 
 	// ListByUpdatedTimestamp 根据更新时间戳获取笔记列表
 	ListByUpdatedTimestamp(ctx context.Context, timestamp, vaultID, uid int64) ([]*Note, error)
@@ -169,6 +206,9 @@ type NoteRepository interface {
 	// ListByPathPrefix 根据路径前缀获取笔记列表
 	ListByPathPrefix(ctx context.Context, pathPrefix string, vaultID, uid int64) ([]*Note, error)
 
+	// MoveByPathPrefix 在单个事务内将前缀下的笔记移动到新前缀下，fids 为新目录路径到文件夹 ID 的映射
+	MoveByPathPrefix(ctx context.Context, oldPrefix, newPrefix string, fids map[string]int64, vaultID, uid int64) ([]*NoteMove, error)
+
 	// RecycleClear 清理回收站
 	RecycleClear(ctx context.Context, path, pathHash string, vaultID, uid int64) error
 
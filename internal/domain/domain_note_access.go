@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// NoteAccessChannel channel a note was read through
+// NoteAccessChannel 笔记被读取的渠道
+type NoteAccessChannel string
+
+const (
+	NoteAccessChannelShare  NoteAccessChannel = "share"  // Public share link // 公开分享链接
+	NoteAccessChannelAPI    NoteAccessChannel = "api"    // REST API with a user or API token // 使用用户或 API 令牌的 REST API
+	NoteAccessChannelWebDAV NoteAccessChannel = "webdav" // WebDAV mount // WebDAV 挂载
+)
+
+// NoteAccessWatch a note whose reads are logged, access logging is opt-in per note
+// NoteAccessWatch 开启了访问日志的笔记，访问日志按笔记单独开启
+type NoteAccessWatch struct {
+	ID        int64     // Primary Key // 主键
+	UID       int64     // Owner User ID // 所有者用户 ID
+	VaultID   int64     // Vault ID // 笔记库 ID
+	NoteID    int64     // Note ID // 笔记 ID
+	CreatedAt time.Time // Enabled Time // 开启时间
+}
+
+// NoteAccessLog one read of a watched note
+// NoteAccessLog 一次对受监控笔记的读取记录
+type NoteAccessLog struct {
+	ID         int64             // Primary Key // 主键
+	UID        int64             // Owner User ID // 所有者用户 ID
+	VaultID    int64             // Vault ID // 笔记库 ID
+	NoteID     int64             // Note ID // 笔记 ID
+	Path       string            // Note path at the time of the read // 读取时的笔记路径
+	Channel    NoteAccessChannel // Access channel // 访问渠道
+	Actor      string            // Share ID, token ID or client name of the reader // 读取者的分享 ID、令牌 ID 或客户端名称
+	ClientType string            // Client Type // 客户端类型
+	IP         string            // Request IP // 请求 IP
+	UA         string            // User Agent // 用户代理
+	CreatedAt  time.Time         // Access Time // 访问时间
+}
+
+// NoteAccessRepository defines the note access log repository interface
+// NoteAccessRepository 定义笔记访问日志仓储接口
+type NoteAccessRepository interface {
+	// Watch enables access logging of a note, enabling twice is a no-op
+	// Watch 开启笔记的访问日志，重复开启无副作用
+	Watch(ctx context.Context, watch *NoteAccessWatch, uid int64) error
+
+	// Unwatch disables access logging of a note, existing entries are kept
+	// Unwatch 关闭笔记的访问日志，已有记录保留
+	Unwatch(ctx context.Context, noteID, uid int64) error
+
+	// ListWatches lists all watched notes of a user
+	// ListWatches 列出用户所有开启访问日志的笔记
+	ListWatches(ctx context.Context, uid int64) ([]*NoteAccessWatch, error)
+
+	// CreateLog stores an access log entry
+	// CreateLog 存储一条访问日志
+	CreateLog(ctx context.Context, log *NoteAccessLog, uid int64) error
+
+	// ListLogs lists access log entries of a note, newest first
+	// ListLogs 按时间倒序分页列出笔记的访问日志
+	ListLogs(ctx context.Context, noteID, uid int64, page, pageSize int) ([]*NoteAccessLog, int64, error)
+
+	// CleanupByTimeAll removes access log entries older than the given timestamp for all users
+	// CleanupByTimeAll 清理所有用户在指定时间戳之前的访问日志
+	CleanupByTimeAll(ctx context.Context, timestamp int64) error
+}
@@ -2,8 +2,42 @@ package domain
 
 import (
 	"context"
+	"time"
 )
 
+// FTSReindexStatus status of a background FTS reindex job
+// FTSReindexStatus 后台全文索引重建任务状态
+type FTSReindexStatus string
+
+const (
+	FTSReindexQueued   FTSReindexStatus = "queued"
+	FTSReindexRunning  FTSReindexStatus = "running"
+	FTSReindexDone     FTSReindexStatus = "done"
+	FTSReindexFailed   FTSReindexStatus = "failed"
+	FTSReindexCanceled FTSReindexStatus = "canceled"
+)
+
+// FTSReindexJob progress snapshot of a user's background FTS reindex job
+// FTSReindexJob 单个用户后台全文索引重建任务的进度快照
+type FTSReindexJob struct {
+	UID         int64
+	Status      FTSReindexStatus
+	VaultsTotal int
+	VaultsDone  int
+	NotesTotal  int
+	NotesDone   int
+	Error       string
+	QueuedAt    time.Time
+	StartedAt   time.Time
+	FinishedAt  time.Time
+}
+
+// IsActive reports whether the job is still queued or running
+// IsActive 判断任务是否仍在排队或运行中
+func (j *FTSReindexJob) IsActive() bool {
+	return j.Status == FTSReindexQueued || j.Status == FTSReindexRunning
+}
+
 // NoteFTSRepository FTS full-text search repository interface
 // NoteFTSRepository FTS 全文搜索仓库接口
 type NoteFTSRepository interface {
@@ -25,4 +59,16 @@ type NoteFTSRepository interface {
 	// DeleteByVaultID deletes all FTS records for a vault
 	// DeleteByVaultID 删除指定仓库的所有 FTS 记录
 	DeleteByVaultID(ctx context.Context, vaultID, uid int64) error
+	// EnqueueReindex queues a background rebuild of the given vaults (all vaults when empty); an active job of the user is returned as is
+	// EnqueueReindex 将指定仓库（为空时为全部仓库）的索引重建加入后台队列；用户已有进行中的任务时直接返回该任务
+	EnqueueReindex(uid int64, vaultIDs []int64) *FTSReindexJob
+	// ReindexJob returns the latest reindex job of a user
+	// ReindexJob 返回用户最近一次的索引重建任务
+	ReindexJob(uid int64) (*FTSReindexJob, bool)
+	// ReindexJobs returns the latest reindex job of every user
+	// ReindexJobs 返回所有用户最近一次的索引重建任务
+	ReindexJobs() []*FTSReindexJob
+	// CancelReindex cancels a user's queued or running reindex job, returns false when there is none
+	// CancelReindex 取消用户排队或运行中的索引重建任务，无此任务时返回 false
+	CancelReindex(uid int64) bool
 }
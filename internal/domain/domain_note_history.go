@@ -37,6 +37,9 @@ type NoteHistoryRepository interface {
 	// ListByNoteID 根据笔记ID获取历史记录列表
 	ListByNoteID(ctx context.Context, noteID int64, page, pageSize int, uid int64) ([]*NoteHistory, int64, error)
 
+	// GetFirstAfter 获取笔记在指定时间之后创建的第一条历史记录，不存在时返回 nil
+	GetFirstAfter(ctx context.Context, noteID int64, after time.Time, uid int64) (*NoteHistory, error)
+
 	// GetLatestVersion 获取笔记的最新版本号
 	GetLatestVersion(ctx context.Context, noteID, uid int64) (int64, error)
 
@@ -53,9 +56,18 @@ type NoteHistoryRepository interface {
 	// keepVersions: 保留的最近版本数量
 	DeleteOldVersions(ctx context.Context, noteID int64, cutoffTime int64, keepVersions int, uid int64) error
 
+	// ListVersions 获取笔记全部历史版本的 ID、版本号与创建时间（不加载内容），按版本号倒序
+	ListVersions(ctx context.Context, noteID int64, uid int64) ([]*NoteHistory, error)
+
+	// DeleteByIDs 批量删除指定ID的历史记录（包含物理目录）
+	DeleteByIDs(ctx context.Context, ids []int64, uid int64) error
+
 	// Delete 删除指定ID的历史记录
 	Delete(ctx context.Context, id, uid int64) error
 
 	// DeleteByVaultID 删除仓库下的所有历史记录（包含物理目录）
 	DeleteByVaultID(ctx context.Context, vaultID, uid int64) error
+
+	// CountByVaultID 统计仓库下的历史记录数量
+	CountByVaultID(ctx context.Context, vaultID, uid int64) (int64, error)
 }
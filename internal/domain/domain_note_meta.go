@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// NoteMetaType value type of a note metadata entry
+// NoteMetaType 笔记元数据的值类型
+type NoteMetaType string
+
+const (
+	NoteMetaTypeString NoteMetaType = "string" // Plain string // 字符串
+	NoteMetaTypeNumber NoteMetaType = "number" // Number stored in decimal form // 以十进制存储的数字
+	NoteMetaTypeBool   NoteMetaType = "bool"   // true or false // 布尔值
+	NoteMetaTypeJSON   NoteMetaType = "json"   // JSON object or array // JSON 对象或数组
+)
+
+// NoteMeta a typed server-side attribute of a note, kept apart from its frontmatter
+// NoteMeta 笔记的带类型服务端属性，独立于 frontmatter 存储
+type NoteMeta struct {
+	ID        int64        // Primary Key // 主键
+	NoteID    int64        // Note ID // 笔记 ID
+	VaultID   int64        // Vault ID // 仓库 ID
+	Key       string       // Attribute name // 属性名
+	Type      NoteMetaType // Value type // 值类型
+	Value     string       // Encoded value // 编码后的值
+	CreatedAt time.Time    // Creation Time // 创建时间
+	UpdatedAt time.Time    // Update Time // 更新时间
+}
+
+// NoteMetaRepository defines the note metadata repository interface
+// NoteMetaRepository 定义笔记元数据仓储接口
+type NoteMetaRepository interface {
+	// ListByNoteIDs lists the metadata of the given notes ordered by note and key
+	// ListByNoteIDs 按笔记与键排序列出指定笔记的元数据
+	ListByNoteIDs(ctx context.Context, noteIDs []int64, uid int64) ([]*NoteMeta, error)
+
+	// Set creates or replaces the entry of a note with the same key
+	// Set 新建或替换笔记中同名键的元数据
+	Set(ctx context.Context, meta *NoteMeta, uid int64) error
+
+	// Delete removes one entry of a note
+	// Delete 删除笔记的一条元数据
+	Delete(ctx context.Context, noteID int64, key string, uid int64) error
+
+	// Migrate moves the metadata of a renamed note to its new note ID
+	// Migrate 将重命名笔记的元数据迁移到新的笔记 ID
+	Migrate(ctx context.Context, oldNoteID, newNoteID, uid int64) error
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// NotePolicyAction what a lifecycle policy does with matching notes
+// NotePolicyAction 生命周期策略对匹配笔记执行的操作
+type NotePolicyAction string
+
+const (
+	NotePolicyActionArchive NotePolicyAction = "archive" // Move into the archive folder // 移动到归档目录
+	NotePolicyActionDelete  NotePolicyAction = "delete"  // Move into the trash // 移入回收站
+)
+
+// IsValid reports whether a is a known action
+// IsValid 判断 a 是否为已知操作
+func (a NotePolicyAction) IsValid() bool {
+	return a == NotePolicyActionArchive || a == NotePolicyActionDelete
+}
+
+// NotePolicyAgeBasis timestamp the age of a note is measured from
+// NotePolicyAgeBasis 计算笔记年龄所依据的时间
+type NotePolicyAgeBasis string
+
+const (
+	NotePolicyAgeModified NotePolicyAgeBasis = "modified" // Last modification, "untouched for" // 最后修改时间，即“多久未修改”
+	NotePolicyAgeCreated  NotePolicyAgeBasis = "created"  // Creation // 创建时间
+)
+
+// IsValid reports whether b is a known age basis
+// IsValid 判断 b 是否为已知的年龄依据
+func (b NotePolicyAgeBasis) IsValid() bool {
+	return b == NotePolicyAgeModified || b == NotePolicyAgeCreated
+}
+
+// NotePolicyTrigger what started a policy run
+// NotePolicyTrigger 策略运行的触发方式
+type NotePolicyTrigger string
+
+const (
+	NotePolicyTriggerSchedule NotePolicyTrigger = "schedule" // Run by the job scheduler // 由任务调度器运行
+	NotePolicyTriggerManual   NotePolicyTrigger = "manual"   // Run on request of the user // 由用户手动运行
+)
+
+// NotePolicy a per-folder lifecycle policy archiving or deleting notes by age and tag
+// NotePolicy 按目录配置的生命周期策略，根据年龄与标签归档或删除笔记
+type NotePolicy struct {
+	ID            int64              // Primary Key // 主键
+	UID           int64              // Owner User ID // 所有者用户 ID
+	VaultID       int64              // Vault ID // 仓库 ID
+	Name          string             // Display name // 显示名称
+	Folder        string             // Folder the policy applies to, empty for the whole vault // 策略作用的目录，为空表示整个仓库
+	Tag           string             // Only notes carrying this tag, empty for all notes // 仅匹配带有该标签的笔记，为空表示全部
+	AgeBasis      NotePolicyAgeBasis // Timestamp the age is measured from // 计算年龄所依据的时间
+	OlderThanDays int                // Minimum age in days // 最小年龄（天）
+	Action        NotePolicyAction   // Action applied to matching notes // 对匹配笔记执行的操作
+	ArchiveFolder string             // Target folder of the archive action // 归档操作的目标目录
+	IsEnabled     bool               // Whether the scheduler runs the policy // 是否由调度器运行
+	LastRunTime   time.Time          // Last Run Time // 上次运行时间
+	CreatedAt     time.Time          // Creation Time // 创建时间
+	UpdatedAt     time.Time          // Update Time // 更新时间
+}
+
+// NotePolicyRun outcome of one run of a policy
+// NotePolicyRun 策略的一次运行结果
+type NotePolicyRun struct {
+	ID        int64             // Primary Key // 主键
+	UID       int64             // Owner User ID // 所有者用户 ID
+	PolicyID  int64             // Policy ID // 策略 ID
+	Trigger   NotePolicyTrigger // What started the run // 触发方式
+	Matched   int               // Notes matching the policy // 匹配的笔记数
+	Succeeded int               // Notes archived or deleted // 成功归档或删除的笔记数
+	Failed    int               // Notes that could not be processed // 处理失败的笔记数
+	Paths     []string          // Paths of the processed notes // 已处理笔记的路径
+	Message   string            // Errors of the failed notes // 失败笔记的错误信息
+	StartTime time.Time         // Start Time // 开始时间
+	EndTime   time.Time         // End Time // 结束时间
+}
+
+// NotePolicyRepository defines the note lifecycle policy repository interface
+// NotePolicyRepository 定义笔记生命周期策略仓储接口
+type NotePolicyRepository interface {
+	// List lists all policies of a user
+	// List 列出用户的全部策略
+	List(ctx context.Context, uid int64) ([]*NotePolicy, error)
+
+	// ListEnabled lists the enabled policies of all users
+	// ListEnabled 列出所有用户已启用的策略
+	ListEnabled(ctx context.Context) ([]*NotePolicy, error)
+
+	// Get returns a policy, nil when it does not exist
+	// Get 获取策略，不存在时返回 nil
+	Get(ctx context.Context, id, uid int64) (*NotePolicy, error)
+
+	// Save creates the policy when ID is 0, otherwise updates it
+	// Save ID 为 0 时新建策略，否则更新
+	Save(ctx context.Context, policy *NotePolicy, uid int64) (*NotePolicy, error)
+
+	// Delete removes a policy together with its run history
+	// Delete 删除策略及其运行历史
+	Delete(ctx context.Context, id, uid int64) error
+
+	// UpdateLastRunTime stores the time of the latest run
+	// UpdateLastRunTime 存储最近一次运行的时间
+	UpdateLastRunTime(ctx context.Context, id, uid int64, lastRun time.Time) error
+
+	// CreateRun stores a run, keeping the newest runs of the policy only
+	// CreateRun 存储运行记录，仅保留该策略最近的运行记录
+	CreateRun(ctx context.Context, run *NotePolicyRun, uid int64) (*NotePolicyRun, error)
+
+	// ListRuns lists runs of a user, newest first; policyID 0 lists every policy
+	// ListRuns 按时间倒序分页列出用户的运行记录；policyID 为 0 时列出全部策略
+	ListRuns(ctx context.Context, policyID, uid int64, page, pageSize int) ([]*NotePolicyRun, int64, error)
+}
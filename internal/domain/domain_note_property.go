@@ -0,0 +1,87 @@
+package domain
+
+import "context"
+
+// NotePropertyType value type of an indexed frontmatter property
+// NotePropertyType 已索引 frontmatter 属性的值类型
+type NotePropertyType string
+
+const (
+	NotePropertyTypeString NotePropertyType = "string" // Text // 文本
+	NotePropertyTypeNumber NotePropertyType = "number" // Integer or decimal // 整数或小数
+	NotePropertyTypeDate   NotePropertyType = "date"   // Date or date and time // 日期或日期时间
+	NotePropertyTypeBool   NotePropertyType = "bool"   // true or false // 布尔值
+	NotePropertyTypeObject NotePropertyType = "object" // Nested mapping kept as JSON text // 以 JSON 文本保存的嵌套映射
+	NotePropertyTypeNull   NotePropertyType = "null"   // Key without a value or an empty list // 无值的键或空列表
+)
+
+// NotePropertyOp comparison operator of a property predicate
+// NotePropertyOp 属性条件的比较运算符
+type NotePropertyOp string
+
+const (
+	NotePropertyOpExists    NotePropertyOp = "exists"    // Key present // 键存在
+	NotePropertyOpNotExists NotePropertyOp = "notExists" // Key absent // 键不存在
+	NotePropertyOpEq        NotePropertyOp = "eq"        // Value or list element equal // 值或列表元素相等
+	NotePropertyOpNe        NotePropertyOp = "ne"        // No value or list element equal // 值与列表元素均不相等
+	NotePropertyOpContains  NotePropertyOp = "contains"  // List element equal or text containing, case-insensitive // 列表元素相等或文本包含，不区分大小写
+	NotePropertyOpGt        NotePropertyOp = "gt"        // Greater than // 大于
+	NotePropertyOpGte       NotePropertyOp = "gte"       // Greater than or equal // 大于等于
+	NotePropertyOpLt        NotePropertyOp = "lt"        // Less than // 小于
+	NotePropertyOpLte       NotePropertyOp = "lte"       // Less than or equal // 小于等于
+)
+
+// NoteProperty one indexed frontmatter value of a note; a list gives one property per element
+// NoteProperty 笔记的一个已索引 frontmatter 值；列表的每个元素各为一条属性
+type NoteProperty struct {
+	NoteID int64            // Note ID // 笔记 ID
+	Key    string           // Property name // 属性名
+	Type   NotePropertyType // Value type // 值类型
+	Text   string           // Value as text // 文本形式的值
+	Number *float64         // Numeric value, nil when not a number // 数值，非数字时为 nil
+	Time   *int64           // Date value in Unix milliseconds, nil when not a date // 日期值（Unix 毫秒），非日期时为 nil
+	InList bool             // Whether the value is a list element // 是否为列表元素
+}
+
+// NotePropertyKey a property name used in a vault with its most common type
+// NotePropertyKey 保险库中使用的属性名及其最常见的类型
+type NotePropertyKey struct {
+	Key       string           // Property name // 属性名
+	Type      NotePropertyType // Most common value type // 最常见的值类型
+	IsList    bool             // Whether the property is mostly a list // 该属性是否多为列表
+	NoteCount int64            // Number of notes having the property // 具有该属性的笔记数
+}
+
+// NotePropertyPredicate one condition on a property; comparisons use Time when set, then Number
+// NotePropertyPredicate 针对一个属性的条件；比较时优先使用 Time，其次 Number
+type NotePropertyPredicate struct {
+	Key    string         // Property name // 属性名
+	Op     NotePropertyOp // Operator // 运算符
+	Text   string         // Operand as text // 文本形式的操作数
+	Number *float64       // Numeric operand // 数值操作数
+	Time   *int64         // Date operand in Unix milliseconds // 日期操作数（Unix 毫秒）
+}
+
+// NotePropertyRepository frontmatter property index repository interface
+// NotePropertyRepository frontmatter 属性索引仓储接口
+type NotePropertyRepository interface {
+	// ReplaceByNoteID replaces the properties of one note
+	// ReplaceByNoteID 替换单条笔记的属性
+	ReplaceByNoteID(ctx context.Context, vaultID, noteID int64, props []*NoteProperty, uid int64) error
+
+	// IndexedUntil returns the update timestamp of the last note indexed in a vault, 0 when never indexed
+	// IndexedUntil 返回保险库中最后一条已索引笔记的更新时间戳，从未索引时为 0
+	IndexedUntil(ctx context.Context, vaultID, uid int64) (int64, error)
+
+	// Reindex replaces the properties of the given notes and advances the vault's indexed timestamp in one transaction
+	// Reindex 在一个事务中替换指定笔记的属性并推进保险库的已索引时间戳
+	Reindex(ctx context.Context, vaultID int64, noteIDs []int64, props []*NoteProperty, indexedUntil, uid int64) error
+
+	// Query returns the IDs of the vault's notes with properties matching all predicates, newest first, and their total
+	// Query 返回保险库中属性满足全部条件的笔记 ID（按新到旧）及总数
+	Query(ctx context.Context, vaultID int64, predicates []*NotePropertyPredicate, offset, limit int, uid int64) ([]int64, int64, error)
+
+	// ListKeys lists the property names used in a vault by name
+	// ListKeys 按名称列出保险库中使用的属性名
+	ListKeys(ctx context.Context, vaultID, uid int64) ([]*NotePropertyKey, error)
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// NotePublishStatus publishing state of a note
+// NotePublishStatus 笔记的发布状态
+type NotePublishStatus string
+
+const (
+	NotePublishStatusScheduled   NotePublishStatus = "scheduled"   // Waiting for publish_at // 等待 publish_at 到达
+	NotePublishStatusPublished   NotePublishStatus = "published"   // Exposed through a share // 已通过分享公开
+	NotePublishStatusFailed      NotePublishStatus = "failed"      // Publishing failed, retried when the note changes // 发布失败，笔记变更后重试
+	NotePublishStatusUnpublished NotePublishStatus = "unpublished" // publish removed or note deleted, share revoked // publish 已移除或笔记已删除，分享已撤销
+)
+
+// IsValid reports whether s is a known status
+// IsValid 判断 s 是否为已知状态
+func (s NotePublishStatus) IsValid() bool {
+	switch s {
+	case NotePublishStatusScheduled, NotePublishStatusPublished, NotePublishStatusFailed, NotePublishStatusUnpublished:
+		return true
+	}
+	return false
+}
+
+// NotePublish publishing state of a note scheduled through its frontmatter (publish / publish_at)
+// NotePublish 通过 frontmatter（publish / publish_at）计划发布的笔记的发布状态
+type NotePublish struct {
+	ID          int64             // Primary Key // 主键
+	UID         int64             // Owner User ID // 所有者用户 ID
+	VaultID     int64             // Vault ID // 仓库 ID
+	PathHash    string            // Note path hash // 笔记路径哈希
+	Path        string            // Note path // 笔记路径
+	NoteID      int64             // Note ID // 笔记 ID
+	Status      NotePublishStatus // Publishing state // 发布状态
+	PublishAt   time.Time         // Scheduled time, zero publishes at once // 计划发布时间，零值表示立即发布
+	PublishedAt time.Time         // Time the share was created // 分享创建时间
+	ShareID     int64             // Share created for the note // 为笔记创建的分享
+	ShareToken  string            // Token of the share // 分享 Token
+	Message     string            // Error of the last attempt // 最近一次尝试的错误
+	CreatedAt   time.Time         // Creation Time // 创建时间
+	UpdatedAt   time.Time         // Update Time // 更新时间
+}
+
+// NotePublishRepository defines the note publishing state repository interface
+// NotePublishRepository 定义笔记发布状态仓储接口
+type NotePublishRepository interface {
+	// ListUIDs lists the users whose notes are scanned for publishing
+	// ListUIDs 列出需要扫描待发布笔记的用户
+	ListUIDs(ctx context.Context) ([]int64, error)
+
+	// GetByPathHash returns the state of a note, nil when it was never scheduled
+	// GetByPathHash 获取笔记的发布状态，从未计划发布时返回 nil
+	GetByPathHash(ctx context.Context, vaultID int64, pathHash string, uid int64) (*NotePublish, error)
+
+	// Save creates the state when ID is 0, otherwise updates it
+	// Save ID 为 0 时新建状态，否则更新
+	Save(ctx context.Context, p *NotePublish, uid int64) (*NotePublish, error)
+
+	// ListDue lists the scheduled notes whose publish time is not after now
+	// ListDue 列出计划发布时间不晚于 now 的待发布笔记
+	ListDue(ctx context.Context, now time.Time, uid int64) ([]*NotePublish, error)
+
+	// List lists the states of a vault by publish time, newest first; an empty status lists all
+	// List 按发布时间倒序列出仓库的发布状态；status 为空时列出全部
+	List(ctx context.Context, vaultID int64, status NotePublishStatus, uid int64, page, pageSize int) ([]*NotePublish, int64, error)
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "context"
+
+// NoteStat word and character counts of a note
+// NoteStat 笔记的字数与字符数
+type NoteStat struct {
+	NoteID  int64  // Note ID // 笔记 ID
+	VaultID int64  // Vault ID // 仓库 ID
+	Path    string // Note path // 笔记路径
+	Words   int64  // Words, each CJK character counts as one // 字数，每个中日韩字符计为一个
+	Chars   int64  // Characters, whitespace excluded // 字符数，不含空白
+}
+
+// NoteStatDay note edits of a vault on one day
+// NoteStatDay 保险库某一天的笔记编辑情况
+type NoteStatDay struct {
+	VaultID      int64  // Vault ID // 仓库 ID
+	Day          string // Day in server local time, 2006-01-02 // 服务器本地时间的日期，格式 2006-01-02
+	Edits        int64  // Saved note edits // 保存的笔记编辑次数
+	WordsAdded   int64  // Words added // 新增字数
+	WordsRemoved int64  // Words removed // 删除字数
+}
+
+// NoteStatRepository note statistics repository interface
+// NoteStatRepository 笔记统计仓储接口
+type NoteStatRepository interface {
+	// Save creates or replaces the counts of a note and returns the previous counts, nil when there were none
+	// Save 新建或替换笔记的统计，返回之前的统计，不存在时为 nil
+	Save(ctx context.Context, stat *NoteStat, uid int64) (*NoteStat, error)
+
+	// AddDay adds the edits and word changes to the day's totals of a vault
+	// AddDay 将编辑次数与字数变化累加到保险库当天的合计中
+	AddDay(ctx context.Context, day *NoteStatDay, uid int64) error
+
+	// IndexedUntil returns the update timestamp of the last note counted in a vault, 0 when never counted
+	// IndexedUntil 返回保险库中最后一条已统计笔记的更新时间戳，从未统计时为 0
+	IndexedUntil(ctx context.Context, vaultID, uid int64) (int64, error)
+
+	// Reindex replaces the counts of the given notes and advances the vault's indexed timestamp in one transaction
+	// Reindex 在一个事务中替换指定笔记的统计并推进保险库的已统计时间戳
+	Reindex(ctx context.Context, vaultID int64, noteIDs []int64, stats []*NoteStat, indexedUntil, uid int64) error
+
+	// ListByVault lists the counts of all notes of a vault
+	// ListByVault 列出保险库全部笔记的统计
+	ListByVault(ctx context.Context, vaultID, uid int64) ([]*NoteStat, error)
+
+	// ListDays lists the days of a vault from the given day on, oldest first
+	// ListDays 列出保险库自指定日期起的每日统计，按日期升序
+	ListDays(ctx context.Context, vaultID int64, from string, uid int64) ([]*NoteStatDay, error)
+
+	// ListUserDays lists the days of all vaults of the user from the given day on, summed per day, oldest first
+	// ListUserDays 列出用户全部保险库自指定日期起按天合计的统计，按日期升序
+	ListUserDays(ctx context.Context, from string, uid int64) ([]*NoteStatDay, error)
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// NoteTemplate a reusable note template of a user
+// NoteTemplate 用户的可复用笔记模板
+type NoteTemplate struct {
+	ID          int64     // Primary Key // 主键
+	UID         int64     // Owner User ID // 所有者用户 ID
+	Name        string    // Unique name per user // 用户内唯一的名称
+	Description string    // Description // 描述
+	Content     string    // Template content with {{date}}, {{time}} and {{title}} variables // 包含 {{date}}、{{time}}、{{title}} 变量的模板内容
+	CreatedAt   time.Time // Creation Time // 创建时间
+	UpdatedAt   time.Time // Update Time // 更新时间
+}
+
+// NoteDailySetting how the daily note of a user is resolved
+// NoteDailySetting 用户每日笔记的解析方式
+type NoteDailySetting struct {
+	UID       int64     // Owner User ID // 所有者用户 ID
+	Pattern   string    // Path pattern with Moment.js tokens in braces, e.g. Journal/{{YYYY-MM-DD}}.md // 花括号内为 Moment.js 标记的路径模式，如 Journal/{{YYYY-MM-DD}}.md
+	Template  string    // Name of the template a missing daily note is created from, empty for an empty note // 创建缺失的每日笔记所用模板名称，为空时创建空笔记
+	Timezone  string    // IANA time zone deciding what "today" is, empty for the server's local time // 决定“今天”的 IANA 时区，为空时使用服务器本地时间
+	UpdatedAt time.Time // Update Time // 更新时间
+}
+
+// NoteTemplateRepository defines the note template repository interface
+// NoteTemplateRepository 定义笔记模板仓储接口
+type NoteTemplateRepository interface {
+	// List lists all templates of a user
+	// List 列出用户的全部模板
+	List(ctx context.Context, uid int64) ([]*NoteTemplate, error)
+
+	// Get returns a template, nil when it does not exist
+	// Get 获取模板，不存在时返回 nil
+	Get(ctx context.Context, id, uid int64) (*NoteTemplate, error)
+
+	// GetByName returns a template by name, nil when it does not exist
+	// GetByName 按名称获取模板，不存在时返回 nil
+	GetByName(ctx context.Context, name string, uid int64) (*NoteTemplate, error)
+
+	// Save creates the template when ID is 0, otherwise updates it
+	// Save ID 为 0 时新建模板，否则更新
+	Save(ctx context.Context, template *NoteTemplate, uid int64) (*NoteTemplate, error)
+
+	// Delete removes a template
+	// Delete 删除模板
+	Delete(ctx context.Context, id, uid int64) error
+
+	// GetDailySetting returns the daily note setting of a user, nil when it was never saved
+	// GetDailySetting 获取用户的每日笔记设置，从未保存时返回 nil
+	GetDailySetting(ctx context.Context, uid int64) (*NoteDailySetting, error)
+
+	// SaveDailySetting creates or replaces the daily note setting of a user
+	// SaveDailySetting 新建或替换用户的每日笔记设置
+	SaveDailySetting(ctx context.Context, setting *NoteDailySetting, uid int64) (*NoteDailySetting, error)
+}
@@ -21,4 +21,5 @@ type OIDCIdentity struct {
 type OIDCIdentityRepository interface {
 	GetByIssuerSubject(ctx context.Context, issuer, subject string) (*OIDCIdentity, error)
 	Create(ctx context.Context, identity *OIDCIdentity) (*OIDCIdentity, error)
+	ListByUID(ctx context.Context, uid int64) ([]*OIDCIdentity, error)
 }
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RetentionPolicy soft delete retention override of a user, or of one of its vaults
+// RetentionPolicy 用户或其某个保险库的软删除保留时间覆盖
+type RetentionPolicy struct {
+	ID                  int64     // Primary Key // 主键
+	UID                 int64     // Owner User ID // 所有者用户 ID
+	VaultID             int64     // Vault ID, 0 for the user-wide override // 仓库 ID，0 表示用户级覆盖
+	SoftDeleteRetention string    // Retention like "30d", "0" keeps soft-deleted records forever // 保留时间，如 "30d"，"0" 表示永久保留
+	CreatedAt           time.Time // Creation Time // 创建时间
+	UpdatedAt           time.Time // Update Time // 更新时间
+}
+
+// RetentionPolicyRepository defines the retention policy repository interface
+// RetentionPolicyRepository 定义保留策略仓储接口
+type RetentionPolicyRepository interface {
+	// List lists the retention overrides of a user
+	// List 列出用户的保留时间覆盖
+	List(ctx context.Context, uid int64) ([]*RetentionPolicy, error)
+
+	// Save creates or replaces the override of a vault, vaultID 0 being the user-wide one
+	// Save 新建或替换保险库的覆盖，vaultID 为 0 表示用户级覆盖
+	Save(ctx context.Context, vaultID int64, retention string, uid int64) error
+
+	// Delete removes the override of a vault, vaultID 0 being the user-wide one
+	// Delete 删除保险库的覆盖，vaultID 为 0 表示用户级覆盖
+	Delete(ctx context.Context, vaultID int64, uid int64) error
+}
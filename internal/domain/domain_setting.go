@@ -74,6 +74,10 @@ type SettingRepository interface {
 	// DeletePhysicalByTime 根据时间物理删除已标记删除的配置
 	DeletePhysicalByTime(ctx context.Context, timestamp, uid int64) error
 
+	// DeletePhysicalByTimeInVaults physically deletes settings marked for deletion by time, only in vaultIDs or, with exclude, outside them
+	// DeletePhysicalByTimeInVaults 根据时间物理删除已标记删除的配置，仅限 vaultIDs 中的保险库，exclude 时则排除这些保险库
+	DeletePhysicalByTimeInVaults(ctx context.Context, timestamp int64, vaultIDs []int64, exclude bool, uid int64) error
+
 	// DeletePhysicalByTimeAll 根据时间物理删除所有用户的已标记删除的配置
 	DeletePhysicalByTimeAll(ctx context.Context, timestamp int64) error
 
@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+// SnapshotDatabase 可做快照的数据库文件
+type SnapshotDatabase struct {
+	// Key 数据库标识，主库为空
+	Key string
+	// Path 数据库文件路径
+	Path string
+	// UID 用户库所属用户，主库为 0
+	UID int64
+}
+
+// SnapshotRepository 本地快照仓储接口
+type SnapshotRepository interface {
+	// Databases 返回所有可做快照的 SQLite 数据库文件（主库与各用户库），非 SQLite 数据库返回空列表
+	Databases(ctx context.Context) ([]*SnapshotDatabase, error)
+
+	// VacuumInto 使用 VACUUM INTO 将 key 对应数据库的一致性副本写入 dst
+	VacuumInto(ctx context.Context, key string, dst string) error
+}
@@ -7,23 +7,26 @@ import (
 
 // Storage 存储配置领域模型
 type Storage struct {
-	ID              int64
-	UID             int64
-	Type            string
-	Endpoint        string
-	Region          string
-	AccountID       string
-	BucketName      string
-	AccessKeyID     string
-	AccessKeySecret string
-	CustomPath      string
-	AccessURLPrefix string
-	User            string
-	Password        string
-	IsEnabled       bool
-	IsDeleted       bool
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	ID                 int64
+	UID                int64
+	Type               string
+	Endpoint           string
+	Region             string
+	AccountID          string
+	BucketName         string
+	AccessKeyID        string
+	AccessKeySecret    string
+	CustomPath         string
+	AccessURLPrefix    string
+	User               string
+	Password           string
+	PathStyle          bool
+	CACert             string
+	InsecureSkipVerify bool
+	IsEnabled          bool
+	IsDeleted          bool
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
 }
 
 // StorageRepository 存储仓储接口
@@ -4,6 +4,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
 )
@@ -56,6 +57,10 @@ const (
 	// SyncLogActionRestore represents restoring a resource from the recycle bin
 	// SyncLogActionRestore 表示从回收站恢复
 	SyncLogActionRestore SyncLogAction = "restore"
+
+	// SyncLogActionSync represents a client pulling the changes of a vault, only recorded when it fails
+	// SyncLogActionSync 表示客户端拉取保险库变更，仅在失败时记录
+	SyncLogActionSync SyncLogAction = "sync"
 )
 
 // SyncLog represents a synchronization log entry
@@ -93,6 +98,15 @@ type SyncLogRepository interface {
 	// List 按条件分页查询用户的同步日志
 	List(ctx context.Context, uid int64, logType, action string, page, pageSize int) ([]*SyncLog, int64, error)
 
+	// CountByHour counts the sync logs of a user created since the given time per hour,
+	// keyed by the start of the hour in milliseconds
+	// CountByHour 按小时统计用户自指定时间以来的同步日志数量，键为该小时起始时间（毫秒）
+	CountByHour(ctx context.Context, since time.Time, uid int64) (map[int64]int64, error)
+
+	// ListSince lists the sync logs of a user created since the given time, oldest first
+	// ListSince 按时间正序列出用户自指定时间以来的同步日志
+	ListSince(ctx context.Context, since time.Time, uid int64) ([]*SyncLog, error)
+
 	// CleanupByTime removes sync logs older than the given timestamp for a specific user
 	// CleanupByTime 清理指定用户在指定时间戳之前的同步日志
 	CleanupByTime(ctx context.Context, timestamp int64, uid int64) error
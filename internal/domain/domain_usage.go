@@ -0,0 +1,25 @@
+package domain
+
+import "context"
+
+// ContentUsage on-disk bytes of the content folders of a user
+// ContentUsage 用户内容目录占用的磁盘字节数
+type ContentUsage struct {
+	Notes    int64 // Note content files // 笔记内容文件
+	Files    int64 // Attachments // 附件
+	History  int64 // History snapshots and diffs // 历史快照与差异
+	Settings int64 // Setting content files // 配置内容文件
+}
+
+// UsageRepository disk usage repository interface
+// UsageRepository 磁盘用量仓储接口
+type UsageRepository interface {
+	// ContentUsage returns the on-disk bytes of a user's content folders; the folders are walked on the
+	// first read only, later writes and removals keep the numbers up to date
+	// ContentUsage 返回用户内容目录占用的磁盘字节数；仅首次读取时遍历目录，之后由写入与删除操作增量更新
+	ContentUsage(ctx context.Context, uid int64) (*ContentUsage, error)
+
+	// DatabaseSize returns the size of a user's SQLite database files, ok is false for other database types
+	// DatabaseSize 返回用户 SQLite 数据库文件的大小，其他数据库类型时 ok 为 false
+	DatabaseSize(ctx context.Context, uid int64) (size int64, ok bool, err error)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UserInvite invitation code letting its holders register, also while open registration is disabled
+// UserInvite 邀请码，持有者可注册账号，开放注册关闭时同样有效
+type UserInvite struct {
+	ID        int64     // Primary Key // 主键
+	Code      string    // Invitation code // 邀请码
+	MaxUses   int64     // Registrations allowed, 0 means unlimited // 允许注册的次数，0 表示不限
+	Uses      int64     // Registrations so far // 已注册次数
+	ExpiresAt time.Time // Expiry, zero means never // 过期时间，零值表示永不过期
+	Note      string    // Admin note, e.g. who it was given to // 管理员备注，例如发放对象
+	CreatedBy int64     // UID of the admin who created it // 创建该邀请码的管理员 UID
+	CreatedAt time.Time // Creation time // 创建时间
+}
+
+// Usable reports whether the code still admits a registration at now
+// Usable 判断该邀请码在 now 时是否仍可用于注册
+func (i *UserInvite) Usable(now time.Time) bool {
+	if i.MaxUses > 0 && i.Uses >= i.MaxUses {
+		return false
+	}
+	return i.ExpiresAt.IsZero() || now.Before(i.ExpiresAt)
+}
+
+// UserInviteRepository defines the invitation code repository interface
+// UserInviteRepository 定义邀请码仓储接口
+type UserInviteRepository interface {
+	// Create stores a new invitation code
+	// Create 存储新的邀请码
+	Create(ctx context.Context, invite *UserInvite) (*UserInvite, error)
+
+	// GetByCode retrieves an invitation by its code
+	// GetByCode 根据邀请码获取邀请
+	GetByCode(ctx context.Context, code string) (*UserInvite, error)
+
+	// List lists all invitations, newest first
+	// List 按时间倒序列出全部邀请码
+	List(ctx context.Context) ([]*UserInvite, error)
+
+	// Use counts one registration against the code, false when it is used up; expiry is checked by the caller
+	// Use 为邀请码计入一次注册，已用完时返回 false；过期由调用方检查
+	Use(ctx context.Context, id int64) (bool, error)
+
+	// Release gives back one use counted by Use, e.g. when the registration failed afterwards
+	// Release 退还一次由 Use 计入的使用，例如随后注册失败时
+	Release(ctx context.Context, id int64) error
+
+	// Delete removes an invitation
+	// Delete 删除邀请码
+	Delete(ctx context.Context, id int64) error
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PendingUser registration waiting for the approval of the admin
+// PendingUser 等待管理员审核的注册
+type PendingUser struct {
+	ID        int64     // Primary Key // 主键
+	Email     string    // Email // 邮箱
+	Username  string    // Username // 用户名
+	Password  string    // Password hash // 密码哈希
+	IP        string    // Client IP of the registration // 注册时的客户端 IP
+	CreatedAt time.Time // Registration time // 注册时间
+}
+
+// PendingUserRepository defines the pending registration repository interface
+// PendingUserRepository 定义待审核注册仓储接口
+type PendingUserRepository interface {
+	// Create queues a registration
+	// Create 将注册加入队列
+	Create(ctx context.Context, user *PendingUser) (*PendingUser, error)
+
+	// GetByID retrieves a pending registration
+	// GetByID 获取待审核的注册
+	GetByID(ctx context.Context, id int64) (*PendingUser, error)
+
+	// Exists reports whether a pending registration uses the email or the username
+	// Exists 判断是否有待审核的注册使用了该邮箱或用户名
+	Exists(ctx context.Context, email, username string) (bool, error)
+
+	// List lists pending registrations with pagination, oldest first
+	// List 按时间正序分页列出待审核的注册
+	List(ctx context.Context, offset, limit int) ([]*PendingUser, int64, error)
+
+	// Delete removes a pending registration
+	// Delete 删除待审核的注册
+	Delete(ctx context.Context, id int64) error
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UserSetting one key of the per-user settings shared by all devices of the user, e.g. theme, editor preferences or a plugin settings blob
+// UserSetting 用户级设置中的一个键，由该用户的所有设备共享，例如主题、编辑器偏好或插件设置数据
+type UserSetting struct {
+	ID               int64     // Primary Key // 主键
+	UID              int64     // Owner // 所属用户
+	Key              string    // Setting key // 设置键
+	Value            string    // Setting value, opaque to the server // 设置值，服务端不解析
+	Version          int64     // Incremented on every write, used for optimistic concurrency // 每次写入递增，用于乐观并发控制
+	Mtime            int64     // Client modification time in milliseconds // 客户端修改时间（毫秒）
+	Deleted          bool      // Tombstone kept so other devices learn about the deletion // 删除标记，保留以便其他设备同步删除
+	UpdatedTimestamp int64     // Server update time in milliseconds, the sync cursor // 服务端更新时间（毫秒），即同步游标
+	CreatedAt        time.Time // Creation time // 创建时间
+	UpdatedAt        time.Time // Update time // 更新时间
+}
+
+// UserSettingRepository defines the per-user settings repository interface
+// UserSettingRepository 定义用户级设置仓储接口
+type UserSettingRepository interface {
+	// Get retrieves a setting of the user by key, tombstones included
+	// Get 根据键获取用户的设置，包含已删除的记录
+	Get(ctx context.Context, uid int64, key string) (*UserSetting, error)
+
+	// ListSince lists the settings of the user updated after timestamp, tombstones included, oldest first
+	// ListSince 按更新时间正序列出用户在 timestamp 之后更新的设置，包含已删除的记录
+	ListSince(ctx context.Context, uid int64, timestamp int64) ([]*UserSetting, error)
+
+	// Create stores a new setting
+	// Create 存储新的设置
+	Create(ctx context.Context, setting *UserSetting) (*UserSetting, error)
+
+	// UpdateIfVersion overwrites the setting only while its stored version is still version, false when another write came first
+	// UpdateIfVersion 仅当存储的版本仍为 version 时覆盖该设置，已被其他写入抢先时返回 false
+	UpdateIfVersion(ctx context.Context, setting *UserSetting, version int64) (bool, error)
+
+	// DeletePhysicalByTime physically removes the tombstones updated before timestamp
+	// DeletePhysicalByTime 物理删除 timestamp 之前更新的删除标记
+	DeletePhysicalByTime(ctx context.Context, timestamp int64) error
+}
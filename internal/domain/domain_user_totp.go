@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UserTOTP holds a user's two-factor enrollment.
+// RecoveryCodes contains only SHA-256 hashes, never the plaintext codes.
+type UserTOTP struct {
+	ID            int64
+	UID           int64
+	Secret        string
+	Enabled       bool
+	RecoveryCodes []string
+	LastCounter   int64
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// UserTOTPRepository stores per-user TOTP enrollments.
+type UserTOTPRepository interface {
+	GetByUID(ctx context.Context, uid int64) (*UserTOTP, error)
+	Save(ctx context.Context, totp *UserTOTP) (*UserTOTP, error)
+	DeleteByUID(ctx context.Context, uid int64) error
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// WebhookEvent event a webhook can subscribe to
+// WebhookEvent Webhook 可订阅的事件
+type WebhookEvent string
+
+const (
+	WebhookEventNoteCreated     WebhookEvent = "note.created"      // A note was created // 新建笔记
+	WebhookEventNoteDeleted     WebhookEvent = "note.deleted"      // A note was deleted // 删除笔记
+	WebhookEventBackupSucceeded WebhookEvent = "backup.succeeded"  // A backup or mirror sync run succeeded // 备份或镜像同步成功
+	WebhookEventBackupFailed    WebhookEvent = "backup.failed"     // A backup or mirror sync run failed // 备份或镜像同步失败
+	WebhookEventGitSyncFailed   WebhookEvent = "git_sync.failed"   // A Git sync run failed // Git 同步失败
+	WebhookEventUserLogin       WebhookEvent = "user.login"        // Successful login // 登录成功
+	WebhookEventUserLoginFailed WebhookEvent = "user.login_failed" // Login with a wrong password // 密码错误导致登录失败
+)
+
+// WebhookEvents all events a webhook can subscribe to
+// WebhookEvents Webhook 可订阅的全部事件
+var WebhookEvents = []WebhookEvent{
+	WebhookEventNoteCreated,
+	WebhookEventNoteDeleted,
+	WebhookEventBackupSucceeded,
+	WebhookEventBackupFailed,
+	WebhookEventGitSyncFailed,
+	WebhookEventUserLogin,
+	WebhookEventUserLoginFailed,
+}
+
+// IsValid reports whether e is a known event
+// IsValid 判断 e 是否为已知事件
+func (e WebhookEvent) IsValid() bool {
+	for _, known := range WebhookEvents {
+		if e == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook a user's webhook endpoint
+// Webhook 用户配置的 Webhook 端点
+type Webhook struct {
+	ID        int64          // Primary Key // 主键
+	UID       int64          // Owner User ID // 所有者用户 ID
+	Name      string         // Display name // 显示名称
+	URL       string         // Endpoint receiving the POSTs // 接收 POST 请求的地址
+	Secret    string         // HMAC signing secret, empty sends unsigned requests // HMAC 签名密钥，为空时不签名
+	Events    []WebhookEvent // Subscribed events, empty subscribes to all // 订阅的事件，为空表示全部
+	IsEnabled bool           // Whether deliveries are sent // 是否投递
+	CreatedAt time.Time      // Creation Time // 创建时间
+	UpdatedAt time.Time      // Update Time // 更新时间
+}
+
+// Subscribes reports whether the webhook is enabled and receives e
+// Subscribes 判断 Webhook 是否启用并接收事件 e
+func (w *Webhook) Subscribes(e WebhookEvent) bool {
+	if !w.IsEnabled {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, event := range w.Events {
+		if event == e {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus delivery state
+// WebhookDeliveryStatus 投递状态
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending" // Waiting for (another) attempt // 等待（再次）投递
+	WebhookDeliverySuccess WebhookDeliveryStatus = "success" // Delivered with a 2xx response // 已投递并收到 2xx 响应
+	WebhookDeliveryFailed  WebhookDeliveryStatus = "failed"  // Every attempt failed // 所有尝试均失败
+)
+
+// WebhookDelivery one event delivered to one webhook, including its retries
+// WebhookDelivery 一个事件向一个 Webhook 的投递，包含其重试
+type WebhookDelivery struct {
+	ID        int64                 // Primary Key // 主键
+	UID       int64                 // Owner User ID // 所有者用户 ID
+	WebhookID int64                 // Webhook ID // Webhook ID
+	Event     WebhookEvent          // Event name // 事件名
+	Payload   string                // JSON body sent // 发送的 JSON 内容
+	Status    WebhookDeliveryStatus // Delivery state // 投递状态
+	Attempts  int                   // Attempts made so far // 已尝试次数
+	Error     string                // Error of the last failed attempt // 最近一次失败的错误信息
+	CreatedAt time.Time             // Event Time // 事件时间
+	UpdatedAt time.Time             // Last Attempt Time // 最近一次尝试时间
+}
+
+// WebhookRepository defines the webhook repository interface
+// WebhookRepository 定义 Webhook 仓储接口
+type WebhookRepository interface {
+	// List lists all webhooks of a user
+	// List 列出用户的全部 Webhook
+	List(ctx context.Context, uid int64) ([]*Webhook, error)
+
+	// Get returns a webhook, nil when it does not exist
+	// Get 获取 Webhook，不存在时返回 nil
+	Get(ctx context.Context, id, uid int64) (*Webhook, error)
+
+	// Save creates the webhook when ID is 0, otherwise updates it
+	// Save ID 为 0 时新建 Webhook，否则更新
+	Save(ctx context.Context, webhook *Webhook, uid int64) (*Webhook, error)
+
+	// Delete removes a webhook together with its delivery log
+	// Delete 删除 Webhook 及其投递日志
+	Delete(ctx context.Context, id, uid int64) error
+
+	// CreateDelivery stores a new delivery
+	// CreateDelivery 存储新的投递记录
+	CreateDelivery(ctx context.Context, delivery *WebhookDelivery, uid int64) (*WebhookDelivery, error)
+
+	// UpdateDelivery stores the outcome of an attempt
+	// UpdateDelivery 存储一次尝试的结果
+	UpdateDelivery(ctx context.Context, delivery *WebhookDelivery, uid int64) error
+
+	// ListDeliveries lists deliveries of a user, newest first; webhookID 0 lists every webhook
+	// ListDeliveries 按时间倒序分页列出用户的投递记录；webhookID 为 0 时列出全部 Webhook
+	ListDeliveries(ctx context.Context, webhookID, uid int64, page, pageSize int) ([]*WebhookDelivery, int64, error)
+
+	// CleanupDeliveriesByTimeAll removes deliveries older than the given timestamp for all users
+	// CleanupDeliveriesByTimeAll 清理所有用户在指定时间戳之前的投递记录
+	CleanupDeliveriesByTimeAll(ctx context.Context, timestamp int64) error
+}
@@ -74,6 +74,14 @@ func (m *MockBackupRepository) ListHistory(ctx context.Context, uid int64, confi
 	return args.Get(0).([]*domain.BackupHistory), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockBackupRepository) GetHistory(ctx context.Context, id, uid int64) (*domain.BackupHistory, error) {
+	args := m.Called(ctx, id, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BackupHistory), args.Error(1)
+}
+
 func (m *MockBackupRepository) ListOldHistory(ctx context.Context, uid int64, configID int64, cutoffTime time.Time) ([]*domain.BackupHistory, error) {
 	args := m.Called(ctx, uid, configID, cutoffTime)
 	if args.Get(0) == nil {
@@ -87,6 +95,14 @@ func (m *MockBackupRepository) DeleteOldHistory(ctx context.Context, uid int64,
 	return args.Error(0)
 }
 
+func (m *MockBackupRepository) CountHistoryByStatus(ctx context.Context, uid int64, since time.Time) (map[int]int64, error) {
+	args := m.Called(ctx, uid, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]int64), args.Error(1)
+}
+
 func (m *MockBackupRepository) DisableByVaultID(ctx context.Context, vaultID, uid int64) error {
 	args := m.Called(ctx, vaultID, uid)
 	return args.Error(0)
@@ -96,6 +96,11 @@ func (m *MockFileRepository) DeletePhysicalByTime(ctx context.Context, timestamp
 	return args.Error(0)
 }
 
+func (m *MockFileRepository) DeletePhysicalByTimeInVaults(ctx context.Context, timestamp int64, vaultIDs []int64, exclude bool, uid int64) error {
+	args := m.Called(ctx, timestamp, vaultIDs, exclude, uid)
+	return args.Error(0)
+}
+
 func (m *MockFileRepository) DeletePhysicalByTimeAll(ctx context.Context, timestamp int64) error {
 	args := m.Called(ctx, timestamp)
 	return args.Error(0)
@@ -196,6 +201,14 @@ func (m *MockFileRepository) ListByPathPrefix(ctx context.Context, pathPrefix st
 	return args.Get(0).([]*domain.File), args.Error(1)
 }
 
+func (m *MockFileRepository) MoveByPathPrefix(ctx context.Context, oldPrefix, newPrefix string, fids map[string]int64, vaultID, uid int64) ([]*domain.FileMove, error) {
+	args := m.Called(ctx, oldPrefix, newPrefix, fids, vaultID, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.FileMove), args.Error(1)
+}
+
 func (m *MockFileRepository) DeleteByVaultID(ctx context.Context, vaultID, uid int64) error {
 	args := m.Called(ctx, vaultID, uid)
 	return args.Error(0)
@@ -206,6 +219,24 @@ func (m *MockFileRepository) RecycleClear(ctx context.Context, path, pathHash st
 	return args.Error(0)
 }
 
+func (m *MockFileRepository) ListOrphanContent(ctx context.Context, uid int64) ([]*domain.FileOrphan, error) {
+	args := m.Called(ctx, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.FileOrphan), args.Error(1)
+}
+
+func (m *MockFileRepository) RemoveOrphanContent(ctx context.Context, fileID, uid int64) (bool, error) {
+	args := m.Called(ctx, fileID, uid)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockFileRepository) FetchContent(ctx context.Context, file *domain.File, uid int64) error {
+	args := m.Called(ctx, file, uid)
+	return args.Error(0)
+}
+
 // Compile-time check: MockFileRepository must implement domain.FileRepository.
 // 编译时检查：MockFileRepository 必须实现 domain.FileRepository 接口。
 var _ domain.FileRepository = (*MockFileRepository)(nil)
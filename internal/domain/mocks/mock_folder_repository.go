@@ -84,6 +84,22 @@ func (m *MockFolderRepository) ListByPathPrefix(ctx context.Context, pathPrefix
 	return args.Get(0).([]*domain.Folder), args.Error(1)
 }
 
+func (m *MockFolderRepository) EnsurePath(ctx context.Context, path string, vaultID, uid int64) ([]*domain.FolderEnsureResult, error) {
+	args := m.Called(ctx, path, vaultID, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.FolderEnsureResult), args.Error(1)
+}
+
+func (m *MockFolderRepository) MoveByPathPrefix(ctx context.Context, oldPath, newPath string, fid int64, vaultID, uid int64) ([]*domain.FolderMove, error) {
+	args := m.Called(ctx, oldPath, newPath, fid, vaultID, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.FolderMove), args.Error(1)
+}
+
 func (m *MockFolderRepository) List(ctx context.Context, vaultID int64, uid int64) ([]*domain.Folder, error) {
 	args := m.Called(ctx, vaultID, uid)
 	if args.Get(0) == nil {
@@ -100,6 +116,19 @@ func (m *MockFolderRepository) ListAll(ctx context.Context, uid int64) ([]*domai
 	return args.Get(0).([]*domain.Folder), args.Error(1)
 }
 
+func (m *MockFolderRepository) SetArchived(ctx context.Context, pathHash string, vaultID int64, archived bool, uid int64) error {
+	args := m.Called(ctx, pathHash, vaultID, archived, uid)
+	return args.Error(0)
+}
+
+func (m *MockFolderRepository) ListArchived(ctx context.Context, vaultID, uid int64) ([]*domain.Folder, error) {
+	args := m.Called(ctx, vaultID, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Folder), args.Error(1)
+}
+
 func (m *MockFolderRepository) DeleteByVaultID(ctx context.Context, vaultID, uid int64) error {
 	args := m.Called(ctx, vaultID, uid)
 	return args.Error(0)
@@ -45,4 +45,33 @@ func (m *MockNoteFTSRepository) DeleteByVaultID(ctx context.Context, vaultID, ui
 	return args.Error(0)
 }
 
+func (m *MockNoteFTSRepository) EnqueueReindex(uid int64, vaultIDs []int64) *domain.FTSReindexJob {
+	args := m.Called(uid, vaultIDs)
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*domain.FTSReindexJob)
+}
+
+func (m *MockNoteFTSRepository) ReindexJob(uid int64) (*domain.FTSReindexJob, bool) {
+	args := m.Called(uid)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1)
+	}
+	return args.Get(0).(*domain.FTSReindexJob), args.Bool(1)
+}
+
+func (m *MockNoteFTSRepository) ReindexJobs() []*domain.FTSReindexJob {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).([]*domain.FTSReindexJob)
+}
+
+func (m *MockNoteFTSRepository) CancelReindex(uid int64) bool {
+	args := m.Called(uid)
+	return args.Bool(0)
+}
+
 var _ domain.NoteFTSRepository = (*MockNoteFTSRepository)(nil)
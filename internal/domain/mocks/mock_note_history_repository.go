@@ -4,6 +4,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/stretchr/testify/mock"
@@ -47,6 +48,14 @@ func (m *MockNoteHistoryRepository) ListByNoteID(ctx context.Context, noteID int
 	return args.Get(0).([]*domain.NoteHistory), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockNoteHistoryRepository) GetFirstAfter(ctx context.Context, noteID int64, after time.Time, uid int64) (*domain.NoteHistory, error) {
+	args := m.Called(ctx, noteID, after, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.NoteHistory), args.Error(1)
+}
+
 func (m *MockNoteHistoryRepository) GetLatestVersion(ctx context.Context, noteID, uid int64) (int64, error) {
 	args := m.Called(ctx, noteID, uid)
 	return args.Get(0).(int64), args.Error(1)
@@ -70,6 +79,19 @@ func (m *MockNoteHistoryRepository) DeleteOldVersions(ctx context.Context, noteI
 	return args.Error(0)
 }
 
+func (m *MockNoteHistoryRepository) ListVersions(ctx context.Context, noteID int64, uid int64) ([]*domain.NoteHistory, error) {
+	args := m.Called(ctx, noteID, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NoteHistory), args.Error(1)
+}
+
+func (m *MockNoteHistoryRepository) DeleteByIDs(ctx context.Context, ids []int64, uid int64) error {
+	args := m.Called(ctx, ids, uid)
+	return args.Error(0)
+}
+
 func (m *MockNoteHistoryRepository) Delete(ctx context.Context, id, uid int64) error {
 	args := m.Called(ctx, id, uid)
 	return args.Error(0)
@@ -80,6 +102,11 @@ func (m *MockNoteHistoryRepository) DeleteByVaultID(ctx context.Context, vaultID
 	return args.Error(0)
 }
 
+func (m *MockNoteHistoryRepository) CountByVaultID(ctx context.Context, vaultID, uid int64) (int64, error) {
+	args := m.Called(ctx, vaultID, uid)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // Compile-time check: MockNoteHistoryRepository must implement domain.NoteHistoryRepository.
 // 编译时检查：MockNoteHistoryRepository 必须实现 domain.NoteHistoryRepository 接口。
 var _ domain.NoteHistoryRepository = (*MockNoteHistoryRepository)(nil)
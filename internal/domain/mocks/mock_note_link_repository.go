@@ -49,6 +49,14 @@ func (m *MockNoteLinkRepository) GetOutlinks(ctx context.Context, sourceNoteID,
 	return args.Get(0).([]*domain.NoteLink), args.Error(1)
 }
 
+func (m *MockNoteLinkRepository) ListByVaultID(ctx context.Context, vaultID, uid int64) ([]*domain.NoteLink, error) {
+	args := m.Called(ctx, vaultID, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NoteLink), args.Error(1)
+}
+
 func (m *MockNoteLinkRepository) DeleteByVaultID(ctx context.Context, vaultID, uid int64) error {
 	args := m.Called(ctx, vaultID, uid)
 	return args.Error(0)
@@ -72,6 +72,14 @@ func (m *MockNoteRepository) GetByPath(ctx context.Context, path string, vaultID
 	return args.Get(0).(*domain.Note), args.Error(1)
 }
 
+func (m *MockNoteRepository) GetByPathLike(ctx context.Context, path string, vaultID, uid int64) (*domain.Note, error) {
+	args := m.Called(ctx, path, vaultID, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Note), args.Error(1)
+}
+
 func (m *MockNoteRepository) Create(ctx context.Context, note *domain.Note, uid int64) (*domain.Note, error) {
 	args := m.Called(ctx, note, uid)
 	if args.Get(0) == nil {
@@ -123,24 +131,37 @@ func (m *MockNoteRepository) DeletePhysicalByTime(ctx context.Context, timestamp
 	return args.Error(0)
 }
 
+func (m *MockNoteRepository) DeletePhysicalByTimeInVaults(ctx context.Context, timestamp int64, vaultIDs []int64, exclude bool, uid int64) error {
+	args := m.Called(ctx, timestamp, vaultIDs, exclude, uid)
+	return args.Error(0)
+}
+
 func (m *MockNoteRepository) DeletePhysicalByTimeAll(ctx context.Context, timestamp int64) error {
 	args := m.Called(ctx, timestamp)
 	return args.Error(0)
 }
 
-func (m *MockNoteRepository) List(ctx context.Context, vaultID int64, page, pageSize int, uid int64, keyword string, isRecycle bool, searchMode string, searchContent bool, sortBy string, sortOrder string, paths []string) ([]*domain.Note, error) {
-	args := m.Called(ctx, vaultID, page, pageSize, uid, keyword, isRecycle, searchMode, searchContent, sortBy, sortOrder, paths)
+func (m *MockNoteRepository) List(ctx context.Context, vaultID int64, page, pageSize int, uid int64, keyword string, isRecycle bool, searchMode string, searchContent bool, sortBy string, sortOrder string, paths []string, excludePrefixes []string) ([]*domain.Note, error) {
+	args := m.Called(ctx, vaultID, page, pageSize, uid, keyword, isRecycle, searchMode, searchContent, sortBy, sortOrder, paths, excludePrefixes)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*domain.Note), args.Error(1)
 }
 
-func (m *MockNoteRepository) ListCount(ctx context.Context, vaultID, uid int64, keyword string, isRecycle bool, searchMode string, searchContent bool, paths []string) (int64, error) {
-	args := m.Called(ctx, vaultID, uid, keyword, isRecycle, searchMode, searchContent, paths)
+func (m *MockNoteRepository) ListCount(ctx context.Context, vaultID, uid int64, keyword string, isRecycle bool, searchMode string, searchContent bool, paths []string, excludePrefixes []string) (int64, error) {
+	args := m.Called(ctx, vaultID, uid, keyword, isRecycle, searchMode, searchContent, paths, excludePrefixes)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockNoteRepository) ListByContentRegex(ctx context.Context, vaultID, uid int64, query *domain.NoteRegexQuery) ([]*domain.NoteRegexMatch, bool, error) {
+	args := m.Called(ctx, vaultID, uid, query)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]*domain.NoteRegexMatch), args.Bool(1), args.Error(2)
+}
+
 func (m *MockNoteRepository) ListByUpdatedTimestamp(ctx context.Context, timestamp, vaultID, uid int64) ([]*domain.Note, error) {
 	args := m.Called(ctx, timestamp, vaultID, uid)
 	if args.Get(0) == nil {
@@ -239,6 +260,14 @@ func (m *MockNoteRepository) ListByPathPrefix(ctx context.Context, pathPrefix st
 	return args.Get(0).([]*domain.Note), args.Error(1)
 }
 
+func (m *MockNoteRepository) MoveByPathPrefix(ctx context.Context, oldPrefix, newPrefix string, fids map[string]int64, vaultID, uid int64) ([]*domain.NoteMove, error) {
+	args := m.Called(ctx, oldPrefix, newPrefix, fids, vaultID, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.NoteMove), args.Error(1)
+}
+
 func (m *MockNoteRepository) RecycleClear(ctx context.Context, path, pathHash string, vaultID, uid int64) error {
 	args := m.Called(path, pathHash, vaultID, uid)
 	return args.Error(0)
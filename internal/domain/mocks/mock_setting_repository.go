@@ -67,6 +67,11 @@ func (m *MockSettingRepository) DeletePhysicalByTime(ctx context.Context, timest
 	return args.Error(0)
 }
 
+func (m *MockSettingRepository) DeletePhysicalByTimeInVaults(ctx context.Context, timestamp int64, vaultIDs []int64, exclude bool, uid int64) error {
+	args := m.Called(ctx, timestamp, vaultIDs, exclude, uid)
+	return args.Error(0)
+}
+
 func (m *MockSettingRepository) DeletePhysicalByTimeAll(ctx context.Context, timestamp int64) error {
 	args := m.Called(ctx, timestamp)
 	return args.Error(0)
@@ -0,0 +1,33 @@
+// Package mocks provides testify/mock implementations for domain Repository interfaces.
+// Package mocks 提供 domain Repository 接口的 testify/mock 实现。
+package mocks
+
+import (
+	"context"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSnapshotRepository is a testify mock for domain.SnapshotRepository.
+// MockSnapshotRepository 是 domain.SnapshotRepository 的 testify mock 实现。
+type MockSnapshotRepository struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotRepository) Databases(ctx context.Context) ([]*domain.SnapshotDatabase, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.SnapshotDatabase), args.Error(1)
+}
+
+func (m *MockSnapshotRepository) VacuumInto(ctx context.Context, key string, dst string) error {
+	args := m.Called(ctx, key, dst)
+	return args.Error(0)
+}
+
+// Compile-time check: MockSnapshotRepository must implement domain.SnapshotRepository.
+// 编译时检查：MockSnapshotRepository 必须实现 domain.SnapshotRepository 接口。
+var _ domain.SnapshotRepository = (*MockSnapshotRepository)(nil)
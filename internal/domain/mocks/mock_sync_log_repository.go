@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/stretchr/testify/mock"
@@ -30,6 +31,22 @@ func (m *MockSyncLogRepository) List(ctx context.Context, uid int64, logType, ac
 	return args.Get(0).([]*domain.SyncLog), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockSyncLogRepository) CountByHour(ctx context.Context, since time.Time, uid int64) (map[int64]int64, error) {
+	args := m.Called(ctx, since, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]int64), args.Error(1)
+}
+
+func (m *MockSyncLogRepository) ListSince(ctx context.Context, since time.Time, uid int64) ([]*domain.SyncLog, error) {
+	args := m.Called(ctx, since, uid)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.SyncLog), args.Error(1)
+}
+
 func (m *MockSyncLogRepository) CleanupByTime(ctx context.Context, timestamp int64, uid int64) error {
 	args := m.Called(ctx, timestamp, uid)
 	return args.Error(0)
@@ -5,9 +5,12 @@ import "time"
 // AdminWebGUIConfig WebGUI configuration response structure (public interface)
 // AdminWebGUIConfig WebGUI 配置响应结构（公开接口）
 type AdminWebGUIConfig struct {
-	FontSet          string `json:"fontSet"`          // Font set // 字体设置
-	RegisterIsEnable bool   `json:"registerIsEnable"` // Registration enablement // 是否开启注册
-	FtsBleveEnabled  bool   `json:"ftsBleveEnabled"`  // Whether Bleve FTS is enabled // 是否启用 Bleve 全文搜索
+	FontSet          string              `json:"fontSet"`          // Font set // 字体设置
+	RegisterIsEnable bool                `json:"registerIsEnable"` // Registration enablement // 是否开启注册
+	FtsBleveEnabled  bool                `json:"ftsBleveEnabled"`  // Whether Bleve FTS is enabled // 是否启用 Bleve 全文搜索
+	Branding         AdminBrandingConfig `json:"branding"`         // Instance branding // 实例品牌设置
+	CaptchaProvider  string              `json:"captchaProvider"`  // Captcha provider of the login, empty when disabled // 登录验证码服务，未启用时为空
+	CaptchaSiteKey   string              `json:"captchaSiteKey"`   // Public site key of the captcha widget // 验证码组件的公开站点密钥
 }
 
 // AdminCheckResponse Admin check response structure
@@ -25,6 +28,7 @@ type AdminConfig struct {
 	SoftDeleteRetentionTime *string `json:"softDeleteRetentionTime,omitempty" form:"softDeleteRetentionTime"` // Soft delete retention time // 软删除保留时间
 	UploadSessionTimeout    *string `json:"uploadSessionTimeout,omitempty" form:"uploadSessionTimeout"`       // Upload session timeout // 上传会话超时时间
 	HistoryKeepVersions     *int    `json:"historyKeepVersions,omitempty" form:"historyKeepVersions"`         // History versions to keep // 历史版本保留数
+	HistoryKeepDuration     *string `json:"historyKeepDuration,omitempty" form:"historyKeepDuration"`         // Maximum age of history versions // 历史版本最长保留时间
 	HistorySaveDelay        *string `json:"historySaveDelay,omitempty" form:"historySaveDelay"`               // History save delay // 历史保存延迟
 	DefaultAPIFolder        *string `json:"defaultApiFolder,omitempty" form:"defaultApiFolder"`               // Default API folder // 默认 API 目录
 	AdminUID                *int    `json:"adminUid,omitempty" form:"adminUid"`                               // Admin UID // 管理员 UID
@@ -36,7 +40,9 @@ type AdminConfig struct {
 	PullReleaseChannel      *string `json:"pullReleaseChannel,omitempty" form:"pullReleaseChannel"`           // Update version channel: stable | beta // 更新版本通道：stable | beta
 	WebGUILoginTokenExpiry  *string `json:"webguiLoginTokenExpiry,omitempty" form:"webguiLoginTokenExpiry"`   // WebGUI login token expiry // WebGUI 登录 Token 有效期
 	WebGUILoginTokenBindIP  *bool   `json:"webguiLoginTokenBindIp,omitempty" form:"webguiLoginTokenBindIp"`   // WebGUI login token bind IP // WebGUI 登录 Token 是否绑定 IP
+	RequireAdminTOTP        *bool   `json:"requireAdminTotp,omitempty" form:"requireAdminTotp"`               // Require 2FA for the admin UID // 是否要求管理员启用两步验证
 	CustomResponseHeaders   *map[string]string `json:"customResponseHeaders,omitempty" form:"customResponseHeaders"` // Custom HTTP response headers // 自定义 HTTP 响应头
+	SwaggerUIEnabled        *bool              `json:"swaggerUiEnabled,omitempty" form:"swaggerUiEnabled"`           // Expose Swagger UI at /docs in release mode // release 模式下是否开放 /docs 的 Swagger UI
 	DefaultPageSize               *int               `json:"defaultPageSize,omitempty" form:"defaultPageSize"`                             // Default page size // 默认每页显示数
 	MaxPageSize                   *int               `json:"maxPageSize,omitempty" form:"maxPageSize"`                                     // Max page size // 最大每页显示限制
 	DefaultContextTimeout         *int               `json:"defaultContextTimeout,omitempty" form:"defaultContextTimeout"`                 // Default context timeout // 默认上下文超时
@@ -63,6 +69,10 @@ type AdminConfig struct {
 	PipelineWindowDown            *int               `json:"pipelineWindowDown,omitempty" form:"pipelineWindowDown"`                       // Download pipeline window size for pv>=2 connections; 0 = stop-and-wait // pv>=2 连接的下行流水线窗口大小；0 = stop-and-wait
 	GitName                       *string            `json:"gitName,omitempty" form:"gitName"`                                             // Git author name // Git 提交的作者名称
 	GitEmail                      *string            `json:"gitEmail,omitempty" form:"gitEmail"`                                           // Git author email // Git 提交的作者邮箱
+	MaxNoteSize                   *string            `json:"maxNoteSize,omitempty" form:"maxNoteSize"`                                     // Largest note content, e.g. 5MB; 0 = no limit // 单篇笔记正文最大大小，如 5MB；0 = 不限制
+	MaxAttachmentSize             *string            `json:"maxAttachmentSize,omitempty" form:"maxAttachmentSize"`                         // Largest attachment, e.g. 100MB; 0 = no limit // 单个附件最大大小，如 100MB；0 = 不限制
+	MaxNotesPerVault              *int               `json:"maxNotesPerVault,omitempty" form:"maxNotesPerVault"`                           // Most notes per vault; 0 = no limit // 每个笔记库最多笔记数；0 = 不限制
+	LogLevel                      *string            `json:"logLevel,omitempty" form:"logLevel"`                                           // Log level: debug | info | warn | error // 日志级别：debug | info | warn | error
 }
 
 // AdminUserDatabaseConfig User database configuration structure
@@ -96,6 +106,17 @@ type AdminCloudflareConfig struct {
 	LogEnabled bool   `json:"logEnabled" form:"logEnabled"` // Whether to enable cloudflare tunnel logging // 是否开启 cloudflare 隧道日志
 }
 
+// AdminBrandingConfig instance branding configuration, empty fields keep the defaults
+// AdminBrandingConfig 实例品牌配置，留空字段保持默认
+type AdminBrandingConfig struct {
+	InstanceName string `json:"instanceName" form:"instanceName" binding:"max=64"`   // Instance name shown in page titles // 页面标题中的实例名称
+	LogoURL      string `json:"logoUrl" form:"logoUrl" binding:"max=1024"`           // Logo and favicon URL // 标志与站点图标地址
+	PrimaryColor string `json:"primaryColor" form:"primaryColor" binding:"max=9"`    // Primary hex color // 主色（十六进制）
+	AccentColor  string `json:"accentColor" form:"accentColor" binding:"max=9"`      // Accent hex color // 强调色（十六进制）
+	LoginMessage string `json:"loginMessage" form:"loginMessage" binding:"max=2000"` // Login page message // 登录页消息
+	CustomCSS    string `json:"customCss" form:"customCss" binding:"max=65536"`      // Custom CSS // 自定义样式
+}
+
 // AdminSystemInfo system information response structure
 // AdminSystemInfo 系统信息响应结构
 type AdminSystemInfo struct {
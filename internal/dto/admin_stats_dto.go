@@ -0,0 +1,63 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// AdminStatsRequest admin dashboard statistics query parameters
+// AdminStatsRequest 管理后台统计查询参数
+type AdminStatsRequest struct {
+	Hours int `json:"hours" form:"hours" binding:"omitempty,min=1,max=168" example:"24"` // Window of the hourly sync event series, default 24 // 按小时同步事件序列的时间范围，默认 24
+}
+
+// AdminStatsDTO admin dashboard statistics
+// AdminStatsDTO 管理后台统计数据
+type AdminStatsDTO struct {
+	Totals            AdminStatsTotalsDTO    `json:"totals"`            // Instance totals // 实例合计
+	Users             []*AdminUserStatsDTO   `json:"users"`             // Per user usage, largest storage first // 每个用户的用量，按存储大小倒序
+	SyncEventsPerHour []*AdminStatsHourDTO   `json:"syncEventsPerHour"` // Sync events per hour, oldest first // 每小时同步事件数，按时间正序
+	Backups           []*AdminBackupStatsDTO `json:"backups"`           // Backup results per time window // 各时间窗口的备份结果
+	GeneratedAt       timex.Time             `json:"generatedAt"`       // Generation time // 生成时间
+}
+
+// AdminStatsTotalsDTO instance totals
+// AdminStatsTotalsDTO 实例合计
+type AdminStatsTotalsDTO struct {
+	Users       int   `json:"users"`       // Active users // 活跃用户数
+	Vaults      int   `json:"vaults"`      // Vaults // 仓库数
+	NoteCount   int64 `json:"noteCount"`   // Notes // 笔记数
+	NoteSize    int64 `json:"noteSize"`    // Note bytes // 笔记字节数
+	FileCount   int64 `json:"fileCount"`   // Attachments // 附件数
+	FileSize    int64 `json:"fileSize"`    // Attachment bytes // 附件字节数
+	Connections int   `json:"connections"` // Active WebSocket connections // 活跃 WebSocket 连接数
+	SyncEvents  int64 `json:"syncEvents"`  // Sync events within the window // 时间范围内的同步事件数
+}
+
+// AdminUserStatsDTO usage of a user
+// AdminUserStatsDTO 单个用户的用量
+type AdminUserStatsDTO struct {
+	UID         int64  `json:"uid"`         // User ID // 用户 ID
+	Username    string `json:"username"`    // Username // 用户名
+	Vaults      int    `json:"vaults"`      // Vaults // 仓库数
+	NoteCount   int64  `json:"noteCount"`   // Notes // 笔记数
+	NoteSize    int64  `json:"noteSize"`    // Note bytes // 笔记字节数
+	FileCount   int64  `json:"fileCount"`   // Attachments // 附件数
+	FileSize    int64  `json:"fileSize"`    // Attachment bytes // 附件字节数
+	Connections int    `json:"connections"` // Active WebSocket connections // 活跃 WebSocket 连接数
+	SyncEvents  int64  `json:"syncEvents"`  // Sync events within the window // 时间范围内的同步事件数
+}
+
+// AdminStatsHourDTO a point of an hourly series
+// AdminStatsHourDTO 按小时序列中的一个点
+type AdminStatsHourDTO struct {
+	Hour  int64 `json:"hour" example:"1700000000000"` // Start of the hour in milliseconds // 小时起始时间（毫秒）
+	Count int64 `json:"count"`                        // Events within the hour // 该小时内的事件数
+}
+
+// AdminBackupStatsDTO backup results within a time window
+// AdminBackupStatsDTO 时间窗口内的备份结果
+type AdminBackupStatsDTO struct {
+	Window      string  `json:"window" example:"24h"` // Time window: 24h, 7d or 30d // 时间窗口：24h、7d 或 30d
+	Success     int64   `json:"success"`              // Successful runs, including runs without changes // 成功次数，包含无更新的运行
+	Failed      int64   `json:"failed"`               // Failed runs // 失败次数
+	Stopped     int64   `json:"stopped"`              // Stopped runs // 被停止次数
+	SuccessRate float64 `json:"successRate"`          // Success / (success + failed), 0 without finished runs // 成功率 = 成功 / (成功 + 失败)，无已完成运行时为 0
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// AlertChannelRequest alert channel create or update request
+// AlertChannelRequest 告警渠道新建或更新请求
+type AlertChannelRequest struct {
+	ID        int64  `json:"id" form:"id" example:"1"`                                                            // ID, 0 creates a channel // ID，为 0 时新建
+	Name      string `json:"name" form:"name" binding:"max=64" example:"Phone"`                                   // Display name // 显示名称
+	Type      string `json:"type" form:"type" binding:"required,oneof=email telegram gotify ntfy" example:"ntfy"` // email, telegram, gotify or ntfy // email、telegram、gotify 或 ntfy
+	To        string `json:"to" form:"to" binding:"max=1024" example:"me@example.com"`                            // email: comma separated recipients // email：逗号分隔的收件人
+	URL       string `json:"url" form:"url" binding:"max=2048" example:"https://ntfy.sh/my-fns-alerts"`           // gotify: server URL; ntfy: topic URL; telegram: optional Bot API root // gotify：服务器地址；ntfy：主题地址；telegram：可选的 Bot API 根地址
+	Token     string `json:"token" form:"token" binding:"max=512" example:""`                                     // telegram: bot token; gotify: application token; ntfy: optional access token // telegram：机器人令牌；gotify：应用令牌；ntfy：可选访问令牌
+	ChatID    string `json:"chatId" form:"chatId" binding:"max=64" example:""`                                    // telegram: chat ID // telegram：会话 ID
+	Priority  int    `json:"priority" form:"priority" binding:"min=0,max=10" example:"0"`                         // gotify (0-10) / ntfy (1-5) priority, 0 uses the server default // gotify（0-10）/ ntfy（1-5）优先级，0 使用服务器默认值
+	IsEnabled bool   `json:"isEnabled" form:"isEnabled" example:"true"`                                           // Whether alerts are sent // 是否发送告警
+}
+
+// AlertChannelIDRequest alert channel delete or test request
+// AlertChannelIDRequest 告警渠道删除或测试请求
+type AlertChannelIDRequest struct {
+	ID int64 `json:"id" form:"id" binding:"required,gt=0" example:"1"` // Channel ID // 渠道 ID
+}
+
+// AlertChannelDTO alert channel DTO
+// AlertChannelDTO 告警渠道 DTO
+type AlertChannelDTO struct {
+	ID        int64      `json:"id"`        // Channel ID // 渠道 ID
+	Name      string     `json:"name"`      // Display name // 显示名称
+	Type      string     `json:"type"`      // email, telegram, gotify or ntfy // email、telegram、gotify 或 ntfy
+	To        string     `json:"to"`        // email: comma separated recipients // email：逗号分隔的收件人
+	URL       string     `json:"url"`       // Server, topic or Bot API URL // 服务器、主题或 Bot API 地址
+	Token     string     `json:"token"`     // Bot, application or access token // 机器人、应用或访问令牌
+	ChatID    string     `json:"chatId"`    // telegram: chat ID // telegram：会话 ID
+	Priority  int        `json:"priority"`  // gotify / ntfy priority // gotify / ntfy 优先级
+	IsEnabled bool       `json:"isEnabled"` // Whether alerts are sent // 是否发送告警
+	CreatedAt timex.Time `json:"createdAt"` // Created at // 创建时间
+	UpdatedAt timex.Time `json:"updatedAt"` // Updated at // 更新时间
+}
@@ -2,7 +2,10 @@
 // Package dto 定义数据传输对象（请求参数和响应结构体）
 package dto
 
-import pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+import (
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+)
 
 // VersionDTO version information for API response
 // VersionDTO 版本信息 API 响应对象
@@ -27,6 +30,7 @@ type VersionDTO struct {
 // UpgradeRequest 升级请求参数
 type UpgradeRequest struct {
 	Version string `form:"version" binding:"required"` // Version to upgrade (e.g. 2.0.10 or latest) // 升级版本
+	Force   bool   `form:"force"`                      // Upgrade now even outside the maintenance window // 即使在维护窗口外也立即升级
 }
 
 // SourceProbeItem is one source's reachability + latency result.
@@ -49,3 +53,40 @@ type SourceProbeDTO struct {
 	Recommended  string          `json:"recommended" example:"github"` // Recommended source: "github" or "cnb" // 推荐源
 	SelectedMode string          `json:"selectedMode" example:"auto"`  // Current configured pull-source mode // 当前配置的选源模式
 }
+
+// ChangelogReleaseDTO release notes of one server version
+// ChangelogReleaseDTO 单个服务端版本的发布说明
+type ChangelogReleaseDTO struct {
+	Version   string                  `json:"version" example:"2.1.0"` // Version name // 版本号
+	IsCurrent bool                    `json:"isCurrent"`               // Whether this is the running version // 是否为当前运行版本
+	Sections  []util.ChangelogSection `json:"sections"`                // Release notes grouped by heading // 按标题分组的发布说明
+	Content   string                  `json:"content"`                 // Raw Markdown release notes // 原始 Markdown 发布说明
+}
+
+// ChangelogDTO release notes of the running version and every newer release
+// ChangelogDTO 当前运行版本及所有更新版本的发布说明
+type ChangelogDTO struct {
+	CurrentVersion   string                `json:"currentVersion" example:"2.0.10"` // Running version // 当前运行版本
+	LatestVersion    string                `json:"latestVersion" example:"2.1.0"`   // Latest known release, empty before the first release check // 已知最新版本，首次检查版本前为空
+	UpgradeAvailable bool                  `json:"upgradeAvailable"`                // Whether a newer release exists // 是否存在更新版本
+	Releases         []ChangelogReleaseDTO `json:"releases"`                        // Newest first // 最新版本在前
+}
+
+// ErrorCodeDTO one code of the error catalog
+// ErrorCodeDTO 错误目录中的一个错误码
+type ErrorCodeDTO struct {
+	Code       int    `json:"code" example:"430"`                    // Numeric code of the response // 响应中的数字错误码
+	Name       string `json:"name" example:"ErrorNoteNotFound"`      // Stable machine-readable name // 稳定的机器可读名称
+	Category   string `json:"category" example:"note"`               // Category of the code range // 错误码区间所属分类
+	Status     bool   `json:"status"`                                // true for success codes // 成功码为 true
+	HTTPStatus int    `json:"httpStatus" example:"200"`              // HTTP status of the response carrying the code // 携带该错误码的响应的 HTTP 状态码
+	Message    string `json:"message" example:"Note does not exist"` // Message template, the response may append details // 消息模板，响应可能附加 details
+	Hint       string `json:"hint" example:"Sync again to refresh"`  // Remediation hint, empty for success codes // 处理建议，成功码为空
+}
+
+// ErrorCatalogDTO every response code of the server
+// ErrorCatalogDTO 服务端的所有响应码
+type ErrorCatalogDTO struct {
+	Language string          `json:"language" example:"en"` // Language of messages and hints // 消息与处理建议的语言
+	Codes    []*ErrorCodeDTO `json:"codes"`                 // Sorted by code // 按错误码排序
+}
@@ -0,0 +1,27 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// AuditLogListRequest audit log query parameters, empty fields match everything
+// AuditLogListRequest 审计日志查询参数，为空的字段不限制
+type AuditLogListRequest struct {
+	UID       int64  `json:"uid" form:"uid" binding:"min=0" example:"1"`                                                                                                                                                                                                                                                             // Acting user ID // 操作用户 ID
+	Action    string `json:"action" form:"action" binding:"omitempty,oneof=user.login user.login_failed user.login_locked user.delete user.approve note.delete note.restore file.restore vault.transfer admin.config_update admin.read_only admin.sync_trace admin.job_requeue admin.task_run backup.execute" example:"note.delete"` // Action // 操作
+	IP        string `json:"ip" form:"ip" binding:"max=64" example:"127.0.0.1"`                                                                                                                                                                                                                                                      // Request IP // 请求 IP
+	StartTime int64  `json:"startTime" form:"startTime" binding:"min=0" example:"1700000000000"`                                                                                                                                                                                                                                     // Start time (ms, inclusive) // 开始时间（毫秒，含）
+	EndTime   int64  `json:"endTime" form:"endTime" binding:"min=0" example:"1800000000000"`                                                                                                                                                                                                                                         // End time (ms, exclusive) // 结束时间（毫秒，不含）
+}
+
+// AuditLogDTO an audited action
+// AuditLogDTO 一条审计记录
+type AuditLogDTO struct {
+	ID         int64      `json:"id"`         // Entry ID // 记录 ID
+	UID        int64      `json:"uid"`        // Acting user ID, 0 when unknown // 操作用户 ID，未知时为 0
+	Action     string     `json:"action"`     // Action // 操作
+	Target     string     `json:"target"`     // Object acted on // 操作对象
+	Detail     string     `json:"detail"`     // Additional details // 附加信息
+	IP         string     `json:"ip"`         // Request IP // 请求 IP
+	ClientType string     `json:"clientType"` // Client type // 客户端类型
+	ClientName string     `json:"clientName"` // Client name // 客户端名称
+	CreatedAt  timex.Time `json:"createdAt"`  // Time of the action // 操作时间
+}
@@ -7,7 +7,7 @@ import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
 type BackupConfigRequest struct {
 	ID               int64  `json:"id" form:"id" example:"1"`                                                                              // ID // ID
 	Vault            string `json:"vault" form:"vault" example:"test"`                                                                     // Vault name // 仓库名称
-	Type             string `json:"type" form:"type" binding:"required,oneof=full incremental sync" example:"sync"`                        // Backup type // 备份类型
+	Type             string `json:"type" form:"type" binding:"required,oneof=full incremental sync dedupe" example:"sync"`                 // Backup type; dedupe uploads only new content-addressed blobs plus a manifest and ignores the password mode // 备份类型；dedupe 仅上传新的内容寻址数据块与清单，不使用密码模式
 	StorageIds       string `json:"storageIds" form:"storageIds" binding:"required" example:"[1, 2]"`                                      // Storage IDs // 存储 ID 列表
 	IsEnabled        bool   `json:"isEnabled" form:"isEnabled" example:"true"`                                                             // Is enabled // 是否启用
 	CronStrategy     string `json:"cronStrategy" form:"cronStrategy" binding:"required,oneof=daily weekly monthly custom" example:"daily"` // Cron strategy // 定时策略
@@ -16,6 +16,9 @@ type BackupConfigRequest struct {
 	IncludeVaultName bool   `json:"includeVaultName" form:"includeVaultName" example:"false"`                                              // Include vault name // 同步路径是否包含仓库名
 	PasswordMode     int    `json:"passwordMode" form:"passwordMode" example:"0"`                                                          // Password mode (0:None, 1:Fixed, 2:Random) // 密码模式 (0:无密码, 1:固定密码, 2:随机密码)
 	PasswordValue    string `json:"passwordValue" form:"passwordValue" example:"123456"`                                                   // Password value for fixed mode // 固定密码值
+	WebhookURL       string `json:"webhookUrl" form:"webhookUrl" binding:"omitempty,url" example:"https://hc-ping.com/uuid"`               // URL receiving a signed JSON report of each run // 接收每次运行签名 JSON 报告的地址
+	WebhookSecret    string `json:"webhookSecret" form:"webhookSecret" example:"s3cret"`                                                   // HMAC-SHA256 key for the report signature // 报告签名使用的 HMAC-SHA256 密钥
+	PingURL          string `json:"pingUrl" form:"pingUrl" binding:"omitempty,url" example:"https://hc-ping.com/uuid"`                     // Dead-man switch URL pinged on start/success/failure // 在开始/成功/失败时请求的健康检查地址
 }
 
 // BackupExecuteRequest backup execution request
@@ -24,6 +27,13 @@ type BackupExecuteRequest struct {
 	ID int64 `json:"id" form:"id" example:"1"` // ID // ID
 }
 
+// BackupVerifyRequest backup verification request
+// BackupVerifyRequest 备份校验请求
+type BackupVerifyRequest struct {
+	HistoryID int64 `json:"historyId" form:"historyId" binding:"required,gt=0" example:"1"` // History record ID // 历史记录 ID
+	Extract   bool  `json:"extract" form:"extract" example:"true"`                          // Also test-extract the archive // 同时试解压归档
+}
+
 // BackupHistoryListRequest backup history list request
 // BackupHistoryListRequest 备份历史列表请求
 type BackupHistoryListRequest struct {
@@ -38,7 +48,7 @@ type BackupConfigDTO struct {
 	ID               int64      `json:"id"`               // Config ID // 配置ID
 	UID              int64      `json:"uid"`              // User UID // 用户ID
 	Vault            string     `json:"vault"`            // Associated vault name // 关联库名称
-	Type             string     `json:"type"`             // Backup type (full, incremental, sync) // 备份类型 (full, incremental, sync)
+	Type             string     `json:"type"`             // Backup type (full, incremental, sync, dedupe) // 备份类型 (full, incremental, sync, dedupe)
 	StorageIds       string     `json:"storageIds"`       // Storage ID list // 存储ID列表
 	IsEnabled        bool       `json:"isEnabled"`        // Is enabled // 是否启用
 	CronStrategy     string     `json:"cronStrategy"`     // Cron strategy // 定时策略
@@ -47,6 +57,9 @@ type BackupConfigDTO struct {
 	IncludeVaultName bool       `json:"includeVaultName"` // Whether sync path includes vault name // 同步路径是否包含仓库名
 	PasswordMode     int        `json:"passwordMode"`     // Password mode (0:None, 1:Fixed, 2:Random) // 密码模式 (0:无密码, 1:固定密码, 2:随机密码)
 	PasswordValue    string     `json:"passwordValue"`    // Password value for fixed mode // 固定密码值
+	WebhookURL       string     `json:"webhookUrl"`       // Backup report webhook URL // 备份报告 Webhook 地址
+	WebhookSecret    string     `json:"webhookSecret"`    // Backup report signing secret // 备份报告签名密钥
+	PingURL          string     `json:"pingUrl"`          // Dead-man switch ping URL // 健康检查 Ping 地址
 	LastRunTime      timex.Time `json:"lastRunTime"`      // Last run time // 上次运行时间
 	NextRunTime      timex.Time `json:"nextRunTime"`      // Next run time // 下次运行时间
 	LastStatus       int        `json:"lastStatus"`       // Last status (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped) // 上次状态 (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped)
@@ -58,19 +71,76 @@ type BackupConfigDTO struct {
 // BackupHistoryDTO backup history DTO
 // BackupHistoryDTO 备份历史 DTO
 type BackupHistoryDTO struct {
-	ID        int64      `json:"id"`        // History record ID // 历史记录ID
-	UID       int64      `json:"uid"`       // User UID // 用户ID
-	ConfigID  int64      `json:"configId"`  // Config ID // 配置ID
-	StorageID int64      `json:"storageId"` // Storage ID // 存储ID
-	Type      string     `json:"type"`      // Backup type // 备份类型
-	StartTime timex.Time `json:"startTime"` // Start time // 开始时间
-	EndTime   timex.Time `json:"endTime"`   // End time // 结束时间
-	Status    int        `json:"status"`    // Status (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped) // 状态 (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped)
-	FileSize  int64      `json:"fileSize"`  // File size // 文件大小
-	FileCount int64      `json:"fileCount"` // File count // 文件数量
-	Message   string     `json:"message"`   // Result message // 结果消息
-	FilePath  string     `json:"filePath"`  // File path // 文件路径
-	Password  string     `json:"password"`  // Password // 密码
-	CreatedAt timex.Time `json:"createdAt"` // Created at // 创建时间
-	UpdatedAt timex.Time `json:"updatedAt"` // Updated at // 更新时间
+	ID          int64      `json:"id"`          // History record ID // 历史记录ID
+	UID         int64      `json:"uid"`         // User UID // 用户ID
+	ConfigID    int64      `json:"configId"`    // Config ID // 配置ID
+	StorageID   int64      `json:"storageId"`   // Storage ID // 存储ID
+	Type        string     `json:"type"`        // Backup type // 备份类型
+	StartTime   timex.Time `json:"startTime"`   // Start time // 开始时间
+	EndTime     timex.Time `json:"endTime"`     // End time // 结束时间
+	Status      int        `json:"status"`      // Status (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped) // 状态 (0:Idle, 1:Running, 2:Success, 3:Failed, 4:Stopped)
+	FileSize    int64      `json:"fileSize"`    // File size // 文件大小
+	FileCount   int64      `json:"fileCount"`   // File count // 文件数量
+	Message     string     `json:"message"`     // Result message // 结果消息
+	FilePath    string     `json:"filePath"`    // File path // 文件路径
+	Password    string     `json:"password"`    // Password // 密码
+	ArchiveSize int64      `json:"archiveSize"` // Uploaded object size in bytes // 上传对象大小（字节）
+	Checksum    string     `json:"checksum"`    // Uploaded object SHA-256 // 上传对象 SHA-256
+	CreatedAt   timex.Time `json:"createdAt"`   // Created at // 创建时间
+	UpdatedAt   timex.Time `json:"updatedAt"`   // Updated at // 更新时间
+}
+
+// BackupVerifyDTO result of verifying the stored copy of a backup
+// BackupVerifyDTO 备份存储副本的校验结果
+type BackupVerifyDTO struct {
+	HistoryID        int64  `json:"historyId"`        // History record ID // 历史记录 ID
+	StorageID        int64  `json:"storageId"`        // Storage ID // 存储 ID
+	StorageType      string `json:"storageType"`      // Storage type // 存储类型
+	FilePath         string `json:"filePath"`         // Remote path // 远端路径
+	Verified         bool   `json:"verified"`         // Whether the copy is intact // 副本是否完好
+	Size             int64  `json:"size"`             // Downloaded size in bytes // 下载的字节数
+	ExpectedSize     int64  `json:"expectedSize"`     // Recorded size, 0 for records made before checksums // 记录的大小，校验和功能之前的记录为 0
+	Checksum         string `json:"checksum"`         // SHA-256 of the downloaded object // 下载对象的 SHA-256
+	ExpectedChecksum string `json:"expectedChecksum"` // Recorded SHA-256 // 记录的 SHA-256
+	Extracted        bool   `json:"extracted"`        // Whether the archive was test-extracted // 是否试解压了归档
+	FileCount        int    `json:"fileCount"`        // Files read back while extracting // 试解压时读回的文件数
+	Message          string `json:"message"`          // Result or failure reason // 结果或失败原因
+}
+
+// BackupReport JSON report posted to a backup config's webhook after each run
+// BackupReport 每次备份运行后推送到备份配置 Webhook 的 JSON 报告
+type BackupReport struct {
+	Event      string               `json:"event"`      // Event name, always "backup.report" // 事件名，固定为 "backup.report"
+	ConfigID   int64                `json:"configId"`   // Config ID // 配置 ID
+	Vault      string               `json:"vault"`      // Vault name, "all" for every vault // 仓库名称，"all" 表示所有仓库
+	Type       string               `json:"type"`       // Backup type (full, incremental, sync, dedupe) // 备份类型 (full, incremental, sync, dedupe)
+	Status     int                  `json:"status"`     // Status (2:Success, 3:Failed, 4:Stopped, 5:SuccessNoUpdate) // 状态 (2:成功, 3:失败, 4:停止, 5:无更新)
+	Success    bool                 `json:"success"`    // Whether the run is healthy (success or no update) // 本次运行是否健康（成功或无更新）
+	Message    string               `json:"message"`    // Result message // 结果消息
+	FileCount  int64                `json:"fileCount"`  // File count // 文件数量
+	FileSize   int64                `json:"fileSize"`   // Total size in bytes // 总大小（字节）
+	StartTime  timex.Time           `json:"startTime"`  // Start time // 开始时间
+	EndTime    timex.Time           `json:"endTime"`    // End time // 结束时间
+	DurationMs int64                `json:"durationMs"` // Duration in milliseconds // 耗时（毫秒）
+	Targets    []BackupReportTarget `json:"targets"`    // Storage targets // 存储目标
+}
+
+// BackupReportTarget storage target of a backup report
+// BackupReportTarget 备份报告中的存储目标
+type BackupReportTarget struct {
+	StorageID int64  `json:"storageId"` // Storage ID // 存储 ID
+	Type      string `json:"type"`      // Storage type // 存储类型
+}
+
+// BackupProgressMessage WebSocket progress message of one storage target of a running backup
+// BackupProgressMessage 运行中备份的单个存储目标的 WebSocket 进度消息
+type BackupProgressMessage struct {
+	ConfigID    int64  `json:"configId"`    // Config ID // 配置ID
+	StorageID   int64  `json:"storageId"`   // Storage ID // 存储ID
+	StorageType string `json:"storageType"` // Storage type // 存储类型
+	Type        string `json:"type"`        // Backup type (full, incremental, sync, dedupe) // 备份类型 (full, incremental, sync, dedupe)
+	Status      int    `json:"status"`      // Status (1:Running, 2:Success, 3:Failed, 5:No update) // 状态 (1:Running, 2:Success, 3:Failed, 5:No update)
+	Processed   int64  `json:"processed"`   // Bytes uploaded for archives, files synced for sync // 归档为已上传字节数，同步为已同步文件数
+	Total       int64  `json:"total"`       // Archive size in bytes, 0 for sync // 归档大小（字节），同步为 0
+	Message     string `json:"message"`     // Result message once finished // 结束时的结果消息
 }
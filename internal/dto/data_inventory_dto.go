@@ -0,0 +1,95 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// DataInventoryDTO machine-readable summary of the personal data stored for one user,
+// used to answer data-subject (GDPR) requests
+// DataInventoryDTO 单个用户所存储个人数据的机器可读摘要，用于答复数据主体（GDPR）请求
+type DataInventoryDTO struct {
+	UID         int64                      `json:"uid"`         // User ID // 用户 ID
+	GeneratedAt timex.Time                 `json:"generatedAt"` // Report time // 生成时间
+	Account     DataInventoryAccountDTO    `json:"account"`     // Account data // 账号数据
+	Totals      DataInventoryVaultDTO      `json:"totals"`      // Sum over all vaults, ID and name empty // 所有仓库的合计，ID 与名称为空
+	Vaults      []*DataInventoryVaultDTO   `json:"vaults"`      // Content per vault // 各仓库内容
+	Shares      int64                      `json:"shares"`      // Share links // 分享链接数
+	AuthTokens  DataInventoryTokenDTO      `json:"authTokens"`  // API tokens and their access logs // API Token 及其访问日志
+	SyncLogs    int64                      `json:"syncLogs"`    // Sync log (audit) entries // 同步日志（审计）条目数
+	Storages    []*DataInventoryStorageDTO `json:"storages"`    // Configured storage targets // 已配置的存储目标
+	Backups     []*DataInventoryBackupDTO  `json:"backups"`     // Backup configs and where their copies are held // 备份配置及副本存放位置
+	GitSyncs    []*DataInventoryGitSyncDTO `json:"gitSyncs"`    // Git remotes receiving vault content // 接收仓库内容的 Git 远端
+}
+
+// DataInventoryAccountDTO account data of the inventory
+// DataInventoryAccountDTO 数据清单中的账号数据
+type DataInventoryAccountDTO struct {
+	Username           string                      `json:"username"`           // Username // 用户名
+	Email              string                      `json:"email"`              // Email address // 邮件地址
+	HasAvatar          bool                        `json:"hasAvatar"`          // Avatar stored // 是否存储了头像
+	TwoFactorEnabled   bool                        `json:"twoFactorEnabled"`   // TOTP secret stored // 是否存储了两步验证密钥
+	ExternalIdentities []*DataInventoryIdentityDTO `json:"externalIdentities"` // Linked OIDC identities // 关联的 OIDC 身份
+	CreatedAt          timex.Time                  `json:"createdAt"`          // Account created time // 账号创建时间
+	UpdatedAt          timex.Time                  `json:"updatedAt"`          // Last updated time // 最后更新时间
+}
+
+// DataInventoryIdentityDTO linked external identity
+// DataInventoryIdentityDTO 关联的外部身份
+type DataInventoryIdentityDTO struct {
+	Issuer    string     `json:"issuer"`    // OIDC issuer // OIDC 签发方
+	Email     string     `json:"email"`     // Email reported by the provider // 身份提供方返回的邮件
+	Username  string     `json:"username"`  // Username reported by the provider // 身份提供方返回的用户名
+	CreatedAt timex.Time `json:"createdAt"` // Linked time // 关联时间
+}
+
+// DataInventoryVaultDTO content counts of one vault
+// DataInventoryVaultDTO 单个仓库的内容统计
+type DataInventoryVaultDTO struct {
+	ID                 int64  `json:"id,omitempty"`       // Vault ID // 仓库 ID
+	Name               string `json:"name,omitempty"`     // Vault name // 仓库名称
+	Notes              int64  `json:"notes"`              // Notes // 笔记数
+	NotesSize          int64  `json:"notesSize"`          // Note content size in bytes // 笔记内容大小（字节）
+	DeletedNotes       int64  `json:"deletedNotes"`       // Notes in the recycle bin // 回收站中的笔记数
+	NoteHistory        int64  `json:"noteHistory"`        // Note history versions // 笔记历史版本数
+	Attachments        int64  `json:"attachments"`        // Attachments // 附件数
+	AttachmentsSize    int64  `json:"attachmentsSize"`    // Attachment size in bytes // 附件大小（字节）
+	DeletedAttachments int64  `json:"deletedAttachments"` // Attachments in the recycle bin // 回收站中的附件数
+	Settings           int64  `json:"settings"`           // Synced client settings files // 同步的客户端配置文件数
+}
+
+// DataInventoryTokenDTO API token counts
+// DataInventoryTokenDTO API Token 统计
+type DataInventoryTokenDTO struct {
+	Total      int64 `json:"total"`      // Tokens including revoked ones // Token 总数（含已注销）
+	Active     int64 `json:"active"`     // Active tokens // 有效 Token 数
+	AccessLogs int64 `json:"accessLogs"` // Access log entries (IP, user agent) // 访问日志条目数（含 IP、用户代理）
+}
+
+// DataInventoryStorageDTO storage target holding user data
+// DataInventoryStorageDTO 存放用户数据的存储目标
+type DataInventoryStorageDTO struct {
+	ID        int64  `json:"id"`        // Storage ID // 存储 ID
+	Type      string `json:"type"`      // Storage type // 存储类型
+	Location  string `json:"location"`  // Endpoint, bucket and path, without credentials // 端点、存储桶与路径，不含凭据
+	IsEnabled bool   `json:"isEnabled"` // Is enabled // 是否启用
+}
+
+// DataInventoryBackupDTO backup config and the targets its copies are held on
+// DataInventoryBackupDTO 备份配置及其副本所在的存储目标
+type DataInventoryBackupDTO struct {
+	ID          int64      `json:"id"`          // Backup config ID // 备份配置 ID
+	Vault       string     `json:"vault"`       // Vault name, empty for all vaults // 仓库名称，为空表示所有仓库
+	Type        string     `json:"type"`        // full, incremental, sync, dedupe
+	IsEnabled   bool       `json:"isEnabled"`   // Is enabled // 是否启用
+	StorageIDs  []int64    `json:"storageIds"`  // Storage targets, see storages // 存储目标，见 storages
+	Runs        int64      `json:"runs"`        // Recorded backup runs // 已记录的备份次数
+	LastRunTime timex.Time `json:"lastRunTime"` // Last run time // 上次运行时间
+}
+
+// DataInventoryGitSyncDTO Git remote receiving vault content
+// DataInventoryGitSyncDTO 接收仓库内容的 Git 远端
+type DataInventoryGitSyncDTO struct {
+	ID        int64  `json:"id"`        // Git sync config ID // Git 同步配置 ID
+	Vault     string `json:"vault"`     // Vault name // 仓库名称
+	RepoURL   string `json:"repoUrl"`   // Remote URL without credentials // 不含凭据的远端地址
+	Branch    string `json:"branch"`    // Branch // 分支
+	IsEnabled bool   `json:"isEnabled"` // Is enabled // 是否启用
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// DeviceRevokeRequest device revoke request
+// DeviceRevokeRequest 设备撤销请求
+type DeviceRevokeRequest struct {
+	ID int64 `json:"id" form:"id" binding:"required,gt=0" example:"1"` // Device ID // 设备 ID
+}
+
+// DeviceDTO device DTO
+// DeviceDTO 设备 DTO
+type DeviceDTO struct {
+	ID            int64      `json:"id"`            // Device ID // 设备 ID
+	DeviceKey     string     `json:"deviceKey"`     // Stable device identifier // 稳定的设备标识
+	TokenID       int64      `json:"tokenId"`       // Token the device authenticated with // 设备鉴权使用的令牌 ID
+	ClientName    string     `json:"clientName"`    // Client name // 客户端名称
+	ClientType    string     `json:"clientType"`    // Client type // 客户端类型
+	ClientVersion string     `json:"clientVersion"` // Client version // 客户端版本
+	Platform      []string   `json:"platform"`      // Platform flags reported as true // 上报为 true 的平台标记
+	IP            string     `json:"ip"`            // Last client IP // 最近一次的客户端 IP
+	UserAgent     string     `json:"userAgent"`     // Last User-Agent // 最近一次的 User-Agent
+	IsOnline      bool       `json:"isOnline"`      // Whether the device is connected now // 设备当前是否在线
+	LastSeenAt    timex.Time `json:"lastSeenAt"`    // Last seen at // 最近出现时间
+	CreatedAt     timex.Time `json:"createdAt"`     // First seen at // 首次出现时间
+}
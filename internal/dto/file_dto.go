@@ -27,6 +27,47 @@ type FileUploadRequest struct {
 	Mtime    int64  `form:"mtime" example:"1700000000"`                  // Modification timestamp // 修改时间戳
 }
 
+// FileUploadInitRequest Request parameters for starting a chunked HTTP upload
+// FileUploadInitRequest 开始 HTTP 分块上传的请求参数
+type FileUploadInitRequest struct {
+	Vault       string `json:"vault" form:"vault" binding:"required" example:"MyVault"`              // Vault name // 保险库名称
+	Path        string `json:"path" form:"path" binding:"required" example:"Video.mp4"`              // File path // 文件路径
+	PathHash    string `json:"pathHash" form:"pathHash" example:"fhash123"`                          // Path hash // 路径哈希
+	ContentHash string `json:"contentHash" form:"contentHash" binding:"required" example:"chash456"` // Content hash // 内容哈希
+	Size        int64  `json:"size" form:"size" binding:"gte=0" example:"104857600"`                 // File size in bytes // 文件大小（字节）
+	Ctime       int64  `json:"ctime" form:"ctime" example:"1700000000"`                              // Creation timestamp // 创建时间戳
+	Mtime       int64  `json:"mtime" form:"mtime" example:"1700000000"`                              // Modification timestamp // 修改时间戳
+}
+
+// FileUploadChunkRequest Query parameters of a chunk of a chunked HTTP upload, the chunk is the request body
+// FileUploadChunkRequest HTTP 分块上传中单个分块的查询参数，分块内容为请求体
+type FileUploadChunkRequest struct {
+	SessionID string `json:"sessionId" form:"sessionId" binding:"required" example:"sess_123456"` // Upload session ID // 上传会话 ID
+	Index     int64  `json:"index" form:"index" binding:"gte=0" example:"0"`                      // Zero-based chunk index // 从 0 开始的分块序号
+}
+
+// FileUploadSessionRequest Request parameters addressing a chunked HTTP upload session
+// FileUploadSessionRequest 指定 HTTP 分块上传会话的请求参数
+type FileUploadSessionRequest struct {
+	SessionID string `json:"sessionId" form:"sessionId" binding:"required" example:"sess_123456"` // Upload session ID // 上传会话 ID
+}
+
+// FileUploadSessionDTO State of a chunked HTTP upload
+// FileUploadSessionDTO HTTP 分块上传的状态
+type FileUploadSessionDTO struct {
+	NeedUpload     bool     `json:"needUpload"`          // Whether content has to be uploaded // 是否需要上传内容
+	SessionID      string   `json:"sessionId,omitempty"` // Upload session ID // 上传会话 ID
+	Path           string   `json:"path"`                // File path // 文件路径
+	PathHash       string   `json:"pathHash"`            // Path hash // 路径哈希
+	Size           int64    `json:"size"`                // File size in bytes // 文件大小（字节）
+	ChunkSize      int64    `json:"chunkSize,omitempty"` // Size of every chunk but the last // 除最后一块外每块的大小
+	TotalChunks    int64    `json:"totalChunks"`         // Number of chunks // 分块总数
+	UploadedChunks []int64  `json:"uploadedChunks"`      // Indexes of received chunks, ascending // 已接收分块的序号，升序
+	UploadedBytes  int64    `json:"uploadedBytes"`       // Bytes received // 已接收字节数
+	ExpiresAt      int64    `json:"expiresAt,omitempty"` // Unix milliseconds the idle session expires at // 空闲会话的过期时间（Unix 毫秒）
+	File           *FileDTO `json:"file,omitempty"`      // Stored file when no upload is needed // 无需上传时服务端已存储的文件
+}
+
 // FileUpdateRequest Request parameters for creating or modifying a file
 // 用于创建或修改文件的请求参数
 type FileUpdateRequest struct {
@@ -94,6 +135,15 @@ type FileSyncRequest struct {
 	Files        []FileSyncCheckRequest `json:"files" form:"files"`                                          // Files to check // 待检查文件列表
 	DelFiles     []FileSyncDelFile      `json:"delFiles" form:"delFiles"`                                    // Files to delete // 待删除文件列表
 	MissingFiles []FileSyncDelFile      `json:"missingFiles" form:"missingFiles"`                            // Missing files // 缺失文件列表
+	// ConfigFiles opts the device into syncing the vault config folder (ConfigDir); without it files inside that folder are left out
+	// ConfigFiles 表示该设备参与笔记库配置目录（ConfigDir）的同步；未开启时该目录内的文件不参与同步
+	ConfigFiles bool `json:"configFiles" form:"configFiles" example:"true"`
+	// ConfigDir is the vault config folder, ".obsidian" when empty
+	// ConfigDir 为笔记库配置目录，为空时为 ".obsidian"
+	ConfigDir string `json:"configDir" form:"configDir" example:".obsidian"`
+	// ExcludeWorkspace keeps the per-device window layout (workspace.json, workspace-mobile.json) of the config folder out of sync
+	// ExcludeWorkspace 表示不同步配置目录中各设备自身的窗口布局（workspace.json、workspace-mobile.json）
+	ExcludeWorkspace bool `json:"excludeWorkspace" form:"excludeWorkspace" example:"true"`
 }
 
 // FileUploadCompleteRequest Parameters for file upload completion
@@ -112,6 +162,15 @@ type FileGetRequest struct {
 	Context   string `json:"context" form:"context" example:"ctx123"`                 // Context // 同步上下文
 }
 
+// FileThumbnailRequest Request parameters for retrieving the thumbnail of an image attachment
+// FileThumbnailRequest 获取图片附件缩略图的请求参数
+type FileThumbnailRequest struct {
+	Vault    string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Path     string `json:"path" form:"path" binding:"required" example:"Image.png"` // File path // 文件路径
+	PathHash string `json:"pathHash" form:"pathHash" example:"fhash123"`             // Path hash // 路径哈希
+	Width    int    `json:"width" form:"width" binding:"gte=0" example:"256"`        // Requested width in pixels, 0 for the smallest // 请求的宽度（像素），0 表示最小宽度
+}
+
 // FileListRequest Pagination parameters for retrieving the file list
 // FileListRequest 获取文件列表的分页参数
 type FileListRequest struct {
@@ -133,6 +192,15 @@ type FileRenameRequest struct {
 	Context     string `json:"context" form:"context" example:"ctx123"`                          // Context // 同步上下文
 }
 
+// FileCopyRequest Parameters required for copying a file
+// FileCopyRequest 复制文件所需参数
+type FileCopyRequest struct {
+	Vault    string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Path     string `json:"path" form:"path" binding:"required" example:"Image.png"` // Source path // 源路径
+	PathHash string `json:"pathHash" form:"pathHash" example:"fhash123"`             // Source path hash // 源路径哈希
+	NewPath  string `json:"newPath" form:"newPath" example:"Copy of Image.png"`      // Target path, defaults to "Copy of <name>" in the same folder // 目标路径，默认为同目录下的 "Copy of <名称>"
+}
+
 // ---------------- DTO / Response ----------------
 
 // FileDTO File Data Transfer Object
@@ -0,0 +1,34 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// FileGCRequest attachment garbage collection dry run request
+// FileGCRequest 附件垃圾回收试运行请求
+type FileGCRequest struct {
+	UID int64 `json:"uid" form:"uid" example:"1"` // Only check this user, 0 checks every user // 仅检查该用户，0 表示检查所有用户
+}
+
+// FileGCOrphanDTO an attachment folder without a file record
+// FileGCOrphanDTO 没有文件记录的附件目录
+type FileGCOrphanDTO struct {
+	UID     int64      `json:"uid"`     // Owner // 所属用户
+	FileID  int64      `json:"fileId"`  // File ID taken from the folder name // 目录名中的文件 ID
+	Path    string     `json:"path"`    // Folder path // 目录路径
+	Size    int64      `json:"size"`    // Total size in bytes // 总大小（字节）
+	ModTime timex.Time `json:"modTime"` // Latest modification time // 最近修改时间
+	Expired bool       `json:"expired"` // Older than the grace period // 已超过宽限期
+	Removed bool       `json:"removed"` // Removed by this run // 已由本次运行删除
+}
+
+// FileGCReportDTO result of an attachment garbage collection run
+// FileGCReportDTO 附件垃圾回收的运行结果
+type FileGCReportDTO struct {
+	DryRun      bool               `json:"dryRun"`      // Only reported, nothing removed // 仅报告，未删除
+	GracePeriod string             `json:"gracePeriod"` // Configured grace period, 0 never removes // 配置的宽限期，0 表示从不删除
+	Users       int                `json:"users"`       // Users checked // 检查的用户数
+	Orphans     int                `json:"orphans"`     // Orphaned folders found // 发现的孤立目录数
+	OrphanSize  int64              `json:"orphanSize"`  // Total size of the orphans in bytes // 孤立目录总大小（字节）
+	Removed     int                `json:"removed"`     // Folders removed // 已删除的目录数
+	RemovedSize int64              `json:"removedSize"` // Bytes freed // 释放的字节数
+	Items       []*FileGCOrphanDTO `json:"items"`       // Orphaned folders // 孤立目录列表
+}
@@ -36,6 +36,15 @@ type FolderDeleteRequest struct {
 	Context  string `json:"context" form:"context" example:"ctx123"`                 // Context // 同步上下文
 }
 
+// FolderArchiveRequest Request parameters for archiving or unarchiving a folder
+// 归档/取消归档文件夹请求参数
+type FolderArchiveRequest struct {
+	Vault    string `json:"vault" form:"vault" binding:"required" example:"MyVault"`    // Vault name // 保险库名称
+	Path     string `json:"path" form:"path" binding:"required" example:"Archive/2023"` // Folder path // 文件夹路径
+	PathHash string `json:"pathHash" form:"pathHash" example:"fhash789"`                // Path hash // 路径哈希
+	Archived bool   `json:"archived" form:"archived" example:"true"`                    // Archived (read-only) // 是否归档（只读）
+}
+
 // FolderSyncCheckRequest Parameters for single record check during synchronization
 // 同步检查单条记录的参数
 type FolderSyncCheckRequest struct {
@@ -75,6 +84,22 @@ type FolderRenameRequest struct {
 	Context     string `json:"context" form:"context" example:"ctx123"`                               // Context // 同步上下文
 }
 
+// FolderMoveRequest Request parameters for moving a folder together with its contents
+// 移动文件夹及其内容的请求参数
+type FolderMoveRequest struct {
+	Vault   string `json:"vault" form:"vault" binding:"required" example:"MyVault"`          // Vault name // 保险库名称
+	OldPath string `json:"oldPath" form:"oldPath" binding:"required" example:"Projects/Old"` // Folder to move // 需要移动的文件夹
+	Path    string `json:"path" form:"path" binding:"required" example:"Archive/Old"`        // New folder path // 新文件夹路径
+}
+
+// FolderMoveResult every resource moved by a folder move, as the rename messages broadcast to clients
+// FolderMoveResult 文件夹移动涉及的所有资源，以广播给客户端的重命名消息表示
+type FolderMoveResult struct {
+	Folders []*FolderSyncRenameMessage `json:"folders"` // Moved folders, parents first // 已移动的文件夹，父文件夹在前
+	Notes   []*NoteSyncRenameMessage   `json:"notes"`   // Moved notes // 已移动的笔记
+	Files   []*FileSyncRenameMessage   `json:"files"`   // Moved files // 已移动的文件
+}
+
 // FolderContentRequest Request parameters for retrieving folder contents
 // 获取文件夹内容的请求参数
 type FolderContentRequest struct {
@@ -88,8 +113,9 @@ type FolderContentRequest struct {
 // FolderTreeRequest Request parameters for retrieving the folder tree
 // 获取文件夹树的请求参数
 type FolderTreeRequest struct {
-	Vault string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
-	Depth int    `json:"depth" form:"depth" example:"3"`                          // Tree depth // 树深度
+	Vault         string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Depth         int    `json:"depth" form:"depth" example:"3"`                          // Tree depth // 树深度
+	IncludeReadme bool   `json:"includeReadme" form:"includeReadme" example:"true"`       // Attach README.md / index.md excerpts to nodes // 为节点附带 README.md / index.md 摘要
 }
 
 // ---------------- DTO / Response ----------------
@@ -103,6 +129,7 @@ type FolderDTO struct {
 	PathHash         string     `json:"pathHash" form:"pathHash"`         // Path hash // 路径哈希值
 	Level            int64      `json:"-" form:"level"`                   // Level // 层级
 	FID              int64      `json:"-" form:"fid"`                     // Parent ID // 父 ID
+	Archived         bool       `json:"archived" form:"archived"`         // Archived (read-only) // 是否归档（只读）
 	Ctime            int64      `json:"ctime" form:"ctime"`               // Creation timestamp // 创建时间戳
 	Mtime            int64      `json:"mtime" form:"mtime"`               // Modification timestamp // 修改时间戳
 	UpdatedTimestamp int64      `json:"lastTime" form:"updatedTimestamp"` // Record update timestamp // 记录更新时间戳
@@ -117,9 +144,22 @@ type FolderTreeNode struct {
 	Name      string            `json:"name"`               // Node name // 节点名称
 	NoteCount int               `json:"noteCount"`          // Note count // 笔记数量
 	FileCount int               `json:"fileCount"`          // File count // 文件数量
+	Archived  bool              `json:"archived,omitempty"` // Archived (read-only) // 是否归档（只读）
+	Readme    *FolderReadme     `json:"readme,omitempty"`   // Folder README summary // 文件夹 README 摘要
 	Children  []*FolderTreeNode `json:"children,omitempty"` // Child nodes // 子节点
 }
 
+// FolderReadme summary of a folder's README.md / index.md
+// FolderReadme 文件夹 README.md / index.md 的摘要
+type FolderReadme struct {
+	Path             string `json:"path"`              // README note path // README 笔记路径
+	Title            string `json:"title,omitempty"`   // Frontmatter title or first heading // frontmatter 标题或第一个标题
+	Excerpt          string `json:"excerpt,omitempty"` // Plain-text excerpt // 纯文本摘要
+	Size             int64  `json:"size"`              // Note size // 笔记大小
+	Mtime            int64  `json:"mtime"`             // Modification timestamp // 修改时间戳
+	UpdatedTimestamp int64  `json:"lastTime"`          // Record update timestamp // 记录更新时间戳
+}
+
 // FolderTreeResponse Folder tree response structure
 // FolderTreeResponse 文件夹树响应结构体
 type FolderTreeResponse struct {
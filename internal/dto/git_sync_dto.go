@@ -16,6 +16,7 @@ type GitSyncConfigRequest struct {
 	RetentionDays   int64    `json:"retentionDays" form:"retentionDays"`
 	IncludeConfig   bool     `json:"includeConfig" form:"includeConfig"`
 	ConfigSyncRules []string `json:"configSyncRules" form:"configSyncRules"`
+	PingURL         string   `json:"pingUrl" form:"pingUrl" binding:"omitempty,url"` // Dead-man switch URL pinged on start/success/failure // 在开始/成功/失败时请求的健康检查地址
 }
 
 // GitSyncValidateRequest git repository sync task parameter validation request
@@ -63,6 +64,7 @@ type GitSyncConfigDTO struct {
 	LastMessage     string     `json:"lastMessage"`     // Last run result message // 上次运行结果消息
 	IncludeConfig   bool       `json:"includeConfig"`   // Include config sync // 是否开启配置同步
 	ConfigSyncRules []string   `json:"configSyncRules"` // Config sync rules // 配置同步规则
+	PingURL         string     `json:"pingUrl"`         // Dead-man switch ping URL // 健康检查 Ping 地址
 	CreatedAt       timex.Time `json:"createdAt"`       // Created at // 创建时间
 	UpdatedAt       timex.Time `json:"updatedAt"`       // Updated at // 更新时间
 }
@@ -0,0 +1,57 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// JobListRequest background job list parameters
+// JobListRequest 后台任务列表参数
+type JobListRequest struct {
+	State string `json:"state" form:"state" binding:"omitempty,oneof=queued running retrying failed" example:"failed"` // Only jobs in this state, empty for all // 仅返回该状态的任务，为空时返回全部
+}
+
+// JobRequeueRequest requeue failed background jobs
+// JobRequeueRequest 重新排队失败的后台任务
+type JobRequeueRequest struct {
+	IDs []string `json:"ids" form:"ids" binding:"max=1000,dive,required" example:"12"` // Failed jobs to requeue, empty for every failed job // 需重新排队的失败任务，为空时重新排队全部失败任务
+}
+
+// JobDTO a background job that is not done
+// JobDTO 未完成的后台任务
+type JobDTO struct {
+	ID         string      `json:"id"`                   // Job ID // 任务 ID
+	Kind       string      `json:"kind"`                 // Job kind, e.g. note.links // 任务类型，如 note.links
+	Key        string      `json:"key,omitempty"`        // Coalescing key within the kind // 类型内的合并键
+	UID        int64       `json:"uid"`                  // Owner, 0 for server jobs // 所属用户，服务器级任务为 0
+	State      string      `json:"state"`                // queued, running, retrying or failed // queued、running、retrying 或 failed
+	Attempts   int         `json:"attempts"`             // Runs so far // 已运行次数
+	LastError  string      `json:"lastError,omitempty"`  // Error of the last run // 最近一次运行的错误
+	EnqueuedAt timex.Time  `json:"enqueuedAt"`           // Time the job was queued // 入队时间
+	StartedAt  *timex.Time `json:"startedAt,omitempty"`  // Start of the last run // 最近一次运行的开始时间
+	FinishedAt *timex.Time `json:"finishedAt,omitempty"` // End of the last run // 最近一次运行的结束时间
+	NextRunAt  *timex.Time `json:"nextRunAt,omitempty"`  // Time of the next retry // 下一次重试时间
+}
+
+// JobKindMetricsDTO counters of one job kind since the server started
+// JobKindMetricsDTO 服务启动以来某类任务的计数
+type JobKindMetricsDTO struct {
+	Kind       string `json:"kind"`       // Job kind // 任务类型
+	Enqueued   uint64 `json:"enqueued"`   // Jobs queued // 入队的任务数
+	Coalesced  uint64 `json:"coalesced"`  // Jobs merged into a queued job with the same key // 合并到同键排队任务中的任务数
+	Inline     uint64 `json:"inline"`     // Jobs run by the caller because the queue was full // 因队列已满由调用方执行的任务数
+	Succeeded  uint64 `json:"succeeded"`  // Successful runs // 成功的运行次数
+	Retried    uint64 `json:"retried"`    // Failed runs that were retried // 失败后重试的运行次数
+	Failed     uint64 `json:"failed"`     // Jobs out of attempts // 用完重试次数的任务数
+	DurationMs int64  `json:"durationMs"` // Time spent running (ms) // 运行总耗时（毫秒）
+}
+
+// JobQueueStatusDTO background job queue state
+// JobQueueStatusDTO 后台任务队列状态
+type JobQueueStatusDTO struct {
+	Workers   int                  `json:"workers"`   // Configured workers // 配置的 worker 数
+	QueueSize int                  `json:"queueSize"` // Configured queue size // 配置的队列大小
+	Queued    int                  `json:"queued"`    // Jobs waiting for a worker // 等待 worker 的任务数
+	Running   int                  `json:"running"`   // Jobs being run // 运行中的任务数
+	Retrying  int                  `json:"retrying"`  // Jobs waiting to be retried // 等待重试的任务数
+	Failed    int                  `json:"failed"`    // Failed jobs kept for requeueing // 保留供重新排队的失败任务数
+	Kinds     []*JobKindMetricsDTO `json:"kinds"`     // Counters by kind // 按类型的计数
+	Jobs      []*JobDTO            `json:"jobs"`      // Jobs that are not done, oldest first // 未完成的任务，按入队时间升序
+}
@@ -0,0 +1,54 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// LogQueryRequest log viewer query parameters, empty fields match everything
+// LogQueryRequest 日志查看查询参数，为空的字段不限制
+type LogQueryRequest struct {
+	Level     string `json:"level" form:"level" binding:"omitempty,oneof=debug info warn error dpanic panic fatal" example:"warn"` // Lowest level included // 包含的最低级别
+	StartTime int64  `json:"startTime" form:"startTime" binding:"min=0" example:"1700000000000"`                                   // Start time (ms, inclusive) // 开始时间（毫秒，含）
+	EndTime   int64  `json:"endTime" form:"endTime" binding:"min=0" example:"1800000000000"`                                       // End time (ms, exclusive) // 结束时间（毫秒，不含）
+	UID       int64  `json:"uid" form:"uid" binding:"min=0" example:"1"`                                                           // Value of the uid field // uid 字段的值
+	Module    string `json:"module" form:"module" binding:"max=100" example:"WebsocketServer"`                                     // Logger name or first word of the message // 日志器名称或消息的第一个词
+	Keyword   string `json:"keyword" form:"keyword" binding:"max=200" example:"OnMessage"`                                         // Text in the message, case-insensitive // 消息中包含的文本，不区分大小写
+	AfterSeq  uint64 `json:"afterSeq" form:"afterSeq" example:"0"`                                                                 // Only entries after this sequence number, for polling // 仅返回该序号之后的条目，用于轮询
+	Limit     int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=1000" example:"200"`                                  // Newest entries returned, default 200 // 返回的最新条目数，默认 200
+	Follow    bool   `json:"follow" form:"follow" example:"false"`                                                                 // Keep streaming new entries as server-sent events // 以服务器推送事件持续推送新条目
+}
+
+// LogEntryDTO a log entry
+// LogEntryDTO 一条日志
+type LogEntryDTO struct {
+	Seq     uint64         `json:"seq"`              // Sequence number, increasing // 递增序号
+	Time    timex.Time     `json:"time"`             // Log time // 日志时间
+	Level   string         `json:"level"`            // Log level // 日志级别
+	Module  string         `json:"module"`           // Logger name or first word of the message // 日志器名称或消息的第一个词
+	Message string         `json:"message"`          // Log message // 日志消息
+	UID     string         `json:"uid,omitempty"`    // Value of the uid field // uid 字段的值
+	Caller  string         `json:"caller,omitempty"` // Source location // 源码位置
+	Fields  map[string]any `json:"fields,omitempty"` // Structured fields // 结构化字段
+}
+
+// SyncTraceRequest switch on the sync tracing of a user
+// SyncTraceRequest 开启用户的同步跟踪
+type SyncTraceRequest struct {
+	UID     int64  `json:"uid" form:"uid" binding:"required,min=1" example:"2"`                     // Traced user // 被跟踪的用户
+	Level   string `json:"level" form:"level" binding:"omitempty,oneof=debug info" example:"debug"` // info records messages, decisions and broadcasts, debug adds their payloads, default debug // info 记录消息、判定与广播，debug 额外记录其内容，默认 debug
+	Minutes int    `json:"minutes" form:"minutes" binding:"omitempty,min=1,max=1440" example:"30"`  // Minutes until the trace stops by itself, default 30 // 跟踪自动停止前的分钟数，默认 30
+}
+
+// SyncTraceStopRequest switch off the sync tracing of a user
+// SyncTraceStopRequest 关闭用户的同步跟踪
+type SyncTraceStopRequest struct {
+	UID int64 `json:"uid" form:"uid" binding:"required,min=1" example:"2"` // Traced user // 被跟踪的用户
+}
+
+// SyncTraceDTO an active sync trace
+// SyncTraceDTO 进行中的同步跟踪
+type SyncTraceDTO struct {
+	UID       int64      `json:"uid"`       // Traced user // 被跟踪的用户
+	Level     string     `json:"level"`     // Lowest level written // 写入的最低级别
+	File      string     `json:"file"`      // Trace file on the server // 服务器上的跟踪文件
+	StartedAt timex.Time `json:"startedAt"` // Start of the trace // 跟踪开始时间
+	ExpiresAt timex.Time `json:"expiresAt"` // The trace stops by itself at this time // 跟踪在此时间自动停止
+}
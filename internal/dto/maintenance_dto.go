@@ -0,0 +1,47 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// MaintenanceStatusDTO maintenance window state and the jobs queued for the next window
+// MaintenanceStatusDTO 维护窗口状态及排队等待下一个窗口的任务
+type MaintenanceStatusDTO struct {
+	Enabled     bool                 `json:"enabled"`     // Whether a maintenance window is configured // 是否配置了维护窗口
+	InWindow    bool                 `json:"inWindow"`    // Whether heavy jobs may run now // 当前是否可以执行重型任务
+	WindowStart *timex.Time          `json:"windowStart"` // Start of the open or next window // 当前或下一个窗口的开始时间
+	WindowEnd   *timex.Time          `json:"windowEnd"`   // End of the open or next window // 当前或下一个窗口的结束时间
+	Queued      []*MaintenanceJobDTO `json:"queued"`      // Jobs waiting for the window, oldest first // 等待窗口的任务，按排队时间升序
+}
+
+// MaintenanceJobDTO a heavy job waiting for the maintenance window
+// MaintenanceJobDTO 等待维护窗口的重型任务
+type MaintenanceJobDTO struct {
+	ID          string     `json:"id"`          // Job ID (kind:key) // 任务 ID (类型:标识)
+	Kind        string     `json:"kind"`        // backup, task or upgrade // backup、task 或 upgrade
+	Key         string     `json:"key"`         // Backup config ID, task name or "server" // 备份配置 ID、任务名或 "server"
+	Description string     `json:"description"` // Job description // 任务描述
+	UID         int64      `json:"uid"`         // Owner, 0 for server jobs // 所属用户，服务器级任务为 0
+	QueuedAt    timex.Time `json:"queuedAt"`    // First time the job was deferred // 首次被推迟的时间
+}
+
+// MaintenanceRunRequest force queued jobs to run now
+// MaintenanceRunRequest 强制立即执行排队任务
+type MaintenanceRunRequest struct {
+	IDs []string `json:"ids" form:"ids"` // Job IDs, empty runs every queued job // 任务 ID，为空时执行全部排队任务
+}
+
+// ReadOnlyStatusDTO read-only maintenance mode state
+// ReadOnlyStatusDTO 只读维护模式状态
+type ReadOnlyStatusDTO struct {
+	Enabled bool        `json:"enabled"` // Whether changes are refused // 是否拒绝修改
+	Manual  bool        `json:"manual"`  // Switched on by the admin // 是否由管理员开启
+	Reason  string      `json:"reason"`  // Reason given by the admin // 管理员填写的原因
+	Since   *timex.Time `json:"since"`   // Start of the current read-only period // 本次只读开始时间
+	Holds   []string    `json:"holds"`   // Operations keeping the server read-only, e.g. upgrade // 保持只读的操作，如 upgrade
+}
+
+// ReadOnlyRequest switch the read-only maintenance mode
+// ReadOnlyRequest 切换只读维护模式
+type ReadOnlyRequest struct {
+	Enabled bool   `json:"enabled" form:"enabled"`                           // Refuse changes // 拒绝修改
+	Reason  string `json:"reason" form:"reason" binding:"omitempty,max=200"` // Reason shown to the admin panel // 在管理面板显示的原因
+}
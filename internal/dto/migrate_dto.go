@@ -0,0 +1,75 @@
+// Package dto Defines data transfer objects (request parameters and response structs)
+// Package dto 定义数据传输对象（请求参数和响应结构体）
+package dto
+
+// MigrateRemoteRequest Parameters of a migration from another fast-note-sync-service instance
+// MigrateRemoteRequest 从另一个 fast-note-sync-service 实例迁移数据的参数
+type MigrateRemoteRequest struct {
+	BaseURL string   `json:"baseUrl"` // Address of the source server // 源服务器地址
+	Token   string   `json:"-"`       // Auth token of the source user // 源用户的认证令牌
+	Client  string   `json:"client"`  // Client type sent to the source server, must match the token // 发送给源服务器的客户端类型，需与令牌匹配
+	Vaults  []string `json:"vaults"`  // Vaults to migrate, empty for all // 需要迁移的保险库，为空表示全部
+}
+
+// MigrateRemoteResult summary of a finished migration
+// MigrateRemoteResult 迁移完成后的汇总
+type MigrateRemoteResult struct {
+	Vaults    int      `json:"vaults"`           // Vaults migrated // 迁移的保险库数
+	Notes     int      `json:"notes"`            // Notes created or updated // 新建或更新的笔记数
+	Histories int      `json:"histories"`        // Note history versions copied // 复制的笔记历史版本数
+	Files     int      `json:"files"`            // Attachments created or updated // 新建或更新的附件数
+	Skipped   int      `json:"skipped"`          // Entries unchanged since a previous run // 自上次运行以来未变化的条目数
+	Failed    int      `json:"failed"`           // Entries that failed to migrate // 迁移失败的条目数
+	Errors    []string `json:"errors,omitempty"` // First few failure reasons // 前若干条失败原因
+}
+
+// MigrateProgressMessage progress of a running migration
+// MigrateProgressMessage 迁移进行中的进度
+type MigrateProgressMessage struct {
+	Vault     string `json:"vault"`     // Vault being migrated // 正在迁移的保险库
+	Notes     int    `json:"notes"`     // Notes migrated so far // 已迁移笔记数
+	Histories int    `json:"histories"` // History versions copied so far // 已复制历史版本数
+	Files     int    `json:"files"`     // Attachments migrated so far // 已迁移附件数
+	Skipped   int    `json:"skipped"`   // Entries skipped so far // 已跳过条目数
+	Failed    int    `json:"failed"`    // Entries failed so far // 已失败条目数
+}
+
+// VaultTransferRequest Parameters of moving a vault from one user to another
+// VaultTransferRequest 将保险库从一个用户转移给另一个用户的参数
+type VaultTransferRequest struct {
+	Vault       string `json:"vault" form:"vault" binding:"required" example:"defaultVault"` // Vault of the source user // 源用户的保险库
+	FromUID     int64  `json:"fromUid" form:"fromUid" binding:"required,gt=0" example:"2"`   // Source user ID // 源用户 ID
+	ToUID       int64  `json:"toUid" form:"toUid" binding:"required,gt=0" example:"1"`       // Target user ID // 目标用户 ID
+	TargetVault string `json:"targetVault" form:"targetVault"`                               // Vault name for the target user, empty keeps the name // 目标用户下的保险库名称，为空时保持原名
+	Overwrite   bool   `json:"overwrite" form:"overwrite"`                                   // Replace conflicting entries of the target vault // 覆盖目标保险库中冲突的条目
+	DryRun      bool   `json:"dryRun" form:"dryRun"`                                         // Only report what would be moved // 仅报告将要转移的内容
+}
+
+// VaultTransferConflictDTO an entry existing in both vaults with different content
+// VaultTransferConflictDTO 两个保险库中都存在且内容不同的条目
+type VaultTransferConflictDTO struct {
+	Type string `json:"type"` // note or file // note 或 file
+	Path string `json:"path"` // Path in the vault // 保险库中的路径
+}
+
+// VaultTransferReportDTO what a vault transfer moves, or moved
+// VaultTransferReportDTO 保险库转移将要（或已经）转移的内容
+type VaultTransferReportDTO struct {
+	Vault        string                      `json:"vault"`            // Source vault // 源保险库
+	TargetVault  string                      `json:"targetVault"`      // Target vault // 目标保险库
+	FromUID      int64                       `json:"fromUid"`          // Source user ID // 源用户 ID
+	ToUID        int64                       `json:"toUid"`            // Target user ID // 目标用户 ID
+	DryRun       bool                        `json:"dryRun"`           // Nothing was changed // 未做任何修改
+	TargetExists bool                        `json:"targetExists"`     // The target user has the target vault already // 目标用户已有目标保险库
+	Notes        int                         `json:"notes"`            // Notes to move // 需转移的笔记数
+	Histories    int                         `json:"histories"`        // Note history versions to move // 需转移的笔记历史版本数
+	Files        int                         `json:"files"`            // Attachments to move // 需转移的附件数
+	Settings     int                         `json:"settings"`         // Client config files the target vault does not have yet // 目标保险库尚无的客户端配置文件数
+	Size         int64                       `json:"size"`             // Bytes of the notes and attachments to move // 需转移的笔记与附件字节数
+	Identical    int                         `json:"identical"`        // Entries the target vault has with the same content // 目标保险库中内容相同的条目数
+	Trashed      int                         `json:"trashed"`          // Entries in the trash, dropped with the source vault // 回收站中的条目数，随源保险库一并删除
+	Conflicts    []*VaultTransferConflictDTO `json:"conflicts"`        // Entries of the target vault with other content // 目标保险库中内容不同的条目
+	Failed       int                         `json:"failed"`           // Entries that failed to move // 转移失败的条目数
+	Errors       []string                    `json:"errors,omitempty"` // First few failure reasons // 前若干条失败原因
+	Transferred  bool                        `json:"transferred"`      // The source vault was moved and deleted // 源保险库已转移并删除
+}
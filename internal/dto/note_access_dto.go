@@ -0,0 +1,40 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// NoteAccessRequest Request parameters identifying a note for access logging
+// NoteAccessRequest 指定开启访问日志的笔记的请求参数
+type NoteAccessRequest struct {
+	Vault    string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Path     string `json:"path" form:"path" binding:"required" example:"ReadMe.md"` // Note path // 笔记路径
+	PathHash string `json:"pathHash" form:"pathHash" example:"hash123"`              // Path hash // 路径哈希
+}
+
+// NoteAccessSettingRequest Request parameters for enabling or disabling access logging of a note
+// NoteAccessSettingRequest 开启或关闭笔记访问日志的请求参数
+type NoteAccessSettingRequest struct {
+	Vault    string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Path     string `json:"path" form:"path" binding:"required" example:"ReadMe.md"` // Note path // 笔记路径
+	PathHash string `json:"pathHash" form:"pathHash" example:"hash123"`              // Path hash // 路径哈希
+	Enabled  bool   `json:"enabled" form:"enabled" example:"true"`                   // Whether reads are logged // 是否记录读取
+}
+
+// NoteAccessSettingDTO Access logging state of a note
+// NoteAccessSettingDTO 笔记的访问日志状态
+type NoteAccessSettingDTO struct {
+	NoteID  int64  `json:"noteId"`  // Note ID // 笔记 ID
+	Path    string `json:"path"`    // Note path // 笔记路径
+	Enabled bool   `json:"enabled"` // Whether reads are logged // 是否记录读取
+}
+
+// NoteAccessLogDTO One read of a watched note
+// NoteAccessLogDTO 一次对受监控笔记的读取记录
+type NoteAccessLogDTO struct {
+	Path       string     `json:"path"`       // Note path at the time of the read // 读取时的笔记路径
+	Channel    string     `json:"channel"`    // Access channel: share / api / webdav // 访问渠道
+	Actor      string     `json:"actor"`      // Share ID, token ID or client name of the reader // 读取者的分享 ID、令牌 ID 或客户端名称
+	ClientType string     `json:"clientType"` // Client type // 客户端类型
+	IP         string     `json:"ip"`         // Request IP // 请求 IP
+	UserAgent  string     `json:"userAgent"`  // User agent // 用户代理
+	CreatedAt  timex.Time `json:"createdAt"`  // Access time // 访问时间
+}
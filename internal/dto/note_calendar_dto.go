@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+// NoteCalendarRequest Request parameters for the calendar feed of dated notes and tasks
+// NoteCalendarRequest 带日期笔记与任务的日历订阅源请求参数
+type NoteCalendarRequest struct {
+	Vault string `json:"vault" form:"vault" example:"MyVault"` // Vault name, every vault the token may access when empty // 保险库名称，为空时包含令牌可访问的所有保险库
+	Done  bool   `json:"done" form:"done" example:"false"`     // Include completed tasks // 是否包含已完成的任务
+}
+
+// NoteCalendarEvent a calendar event taken from note frontmatter or a task with a due date
+// NoteCalendarEvent 取自笔记 frontmatter 或带截止日期任务的日历事件
+type NoteCalendarEvent struct {
+	ID       string    `json:"id"`       // Stable identifier across feed refreshes // 订阅源刷新间保持不变的标识
+	Vault    string    `json:"vault"`    // Vault name // 保险库名称
+	Path     string    `json:"path"`     // Note path // 笔记路径
+	Title    string    `json:"title"`    // Note title or task text // 笔记标题或任务文本
+	Tags     []string  `json:"tags"`     // Note tags without "#" // 不含 "#" 的笔记标签
+	IsTask   bool      `json:"isTask"`   // Taken from a task rather than frontmatter // 取自任务而非 frontmatter
+	Done     bool      `json:"done"`     // Task is completed // 任务已完成
+	Start    time.Time `json:"start"`    // Start, or the due date of a task // 开始时间，任务为截止日期
+	End      time.Time `json:"end"`      // End, zero when the note has none // 结束时间，笔记未设置时为零值
+	AllDay   bool      `json:"allDay"`   // Date without a time of day // 不含时刻的日期
+	Floating bool      `json:"floating"` // Time without a time zone // 不含时区的时间
+	Mtime    int64     `json:"mtime"`    // Note modification timestamp // 笔记修改时间戳
+}
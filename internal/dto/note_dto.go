@@ -33,6 +33,7 @@ type NoteModifyOrCreateRequest struct {
 	CreateOnly      bool   `json:"createOnly" form:"createOnly" example:"false"`                 // If true, fail if note already exists // 如果为 true，笔记已存在则失败
 	Context            string `json:"context" form:"context" example:"ctx123"`                      // Context // 同步上下文
 	IsConflictResolved bool   `json:"isConflictResolved" form:"isConflictResolved" example:"false"` // Marks if conflict is resolved manually // 标记是否为手动解决冲突
+	Template           string `json:"template" form:"template" example:"Daily"`                     // Template name; the expanded template replaces content // 模板名称；展开后的模板替换 content
 }
 
 // ContentModifyRequest Request parameters for modifying content only
@@ -100,6 +101,23 @@ type NotePrependRequest struct {
 	Content  string `json:"content" form:"content" binding:"required" example:"Prepended content\n"` // Content to prepend // 头部添加内容
 }
 
+// NoteAppendToHeadingRequest parameters for inserting content under a heading of a note
+// NoteAppendToHeadingRequest 在笔记指定标题下插入内容请求参数
+type NoteAppendToHeadingRequest struct {
+	Vault    string `json:"vault" form:"vault" binding:"required" example:"MyVault"`                    // Vault name // 保险库名称
+	Path     string `json:"path" form:"path" binding:"required" example:"ReadMe.md"`                    // Note path // 笔记路径
+	PathHash string `json:"pathHash" form:"pathHash" example:"hash123"`                                 // Path hash // 路径哈希
+	Heading  string `json:"heading" form:"heading" binding:"required" example:"## Inbox"`               // Heading text, optionally with its level marker // 标题文本，可带级别标记
+	Content  string `json:"content" form:"content" binding:"required" example:"- [ ] Call back"`        // Content to insert // 插入内容
+	Position string `json:"position" form:"position" binding:"omitempty,oneof=end start" example:"end"` // end (default) or start of the section // 插入到段落末尾（默认）或开头
+}
+
+// NoteAppendToHeadingResponse response of inserting content under a heading
+// NoteAppendToHeadingResponse 在标题下插入内容的响应
+type NoteAppendToHeadingResponse struct {
+	Note           *NoteDTO `json:"note"`           // Updated note // 更新后的笔记
+	HeadingCreated bool     `json:"headingCreated"` // Whether the heading was missing and added at the end // 标题是否缺失并被添加到末尾
+}
 // NoteReplaceRequest parameters for find/replace in a note
 // NoteReplaceRequest 笔记查找替换请求参数
 type NoteReplaceRequest struct {
@@ -123,6 +141,16 @@ type NoteMoveRequest struct {
 	Overwrite   bool   `json:"overwrite" form:"overwrite" example:"false"`                                   // Overwrite existing // 覆盖现有
 }
 
+// NoteDuplicateRequest parameters for duplicating a note
+// NoteDuplicateRequest 复制笔记请求参数
+type NoteDuplicateRequest struct {
+	Vault           string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Path            string `json:"path" form:"path" binding:"required" example:"ReadMe.md"` // Source note path // 源笔记路径
+	PathHash        string `json:"pathHash" form:"pathHash" example:"hash123"`              // Source path hash // 源路径哈希
+	NewPath         string `json:"newPath" form:"newPath" example:"Copy of ReadMe.md"`      // Target path, defaults to "Copy of <name>" in the same folder // 目标路径，默认为同目录下的 "Copy of <名称>"
+	CopyAttachments bool   `json:"copyAttachments" form:"copyAttachments" example:"false"`  // Duplicate embedded attachments and point the copy at them // 复制嵌入的附件并让副本引用新附件
+}
+
 // NoteLinkQueryRequest parameters for backlinks/outlinks query
 // NoteLinkQueryRequest 反向链接/出链查询请求参数
 type NoteLinkQueryRequest struct {
@@ -186,19 +214,22 @@ type NoteRenameRequest struct {
 	OldPath     string `json:"oldPath" form:"oldPath" binding:"required" example:"OldName.md"` // Old path // 旧路径
 	OldPathHash string `json:"oldPathHash" form:"oldPathHash" example:"ohash456"`              // Old path hash // 旧路径哈希
 	Context     string `json:"context" form:"context" example:"ctx123"`                        // Context // 同步上下文
+	UpdateLinks *bool  `json:"updateLinks" form:"updateLinks" example:"true"`                  // Rewrite links pointing at the note, defaults to the server setting // 改写指向该笔记的链接，默认使用服务端配置
 }
 
 // NoteListRequest Pagination parameters for retrieving the note list
 // NoteListRequest 获取笔记列表的分页参数
 type NoteListRequest struct {
-	Vault         string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
-	Keyword       string `json:"keyword" form:"keyword" example:"todo"`                   // Search keyword // 搜索关键词
-	IsRecycle     bool   `json:"isRecycle" form:"isRecycle" example:"false"`              // Is in recycle bin // 是否在回收站
-	SearchMode    string `json:"searchMode" form:"searchMode" example:"content"`          // Search mode (path, content) // 搜索模式（路径、内容）
-	SearchContent bool   `json:"searchContent" form:"searchContent" example:"true"`       // Whether to search content // 是否搜索内容
-	SortBy        string `json:"sortBy" form:"sortBy" example:"mtime"`                    // Sort by field // 排序字段
-	SortOrder     string `json:"sortOrder" form:"sortOrder" example:"desc"`               // Sort order // 排序顺序
-	Paths         string `json:"paths" form:"paths" example:"note1.md,note2.md"`          // Comma-separated exact path list for share filter // 逗号分隔的精确路径列表，用于分享筛选
+	Vault           string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Keyword         string `json:"keyword" form:"keyword" example:"todo"`                   // Search keyword // 搜索关键词
+	IsRecycle       bool   `json:"isRecycle" form:"isRecycle" example:"false"`              // Is in recycle bin // 是否在回收站
+	SearchMode      string `json:"searchMode" form:"searchMode" example:"content"`          // Search mode (path, content, regex) // 搜索模式（路径、内容、正则）
+	SearchContent   bool   `json:"searchContent" form:"searchContent" example:"true"`       // Whether to search content // 是否搜索内容
+	SortBy          string `json:"sortBy" form:"sortBy" example:"mtime"`                    // Sort by field // 排序字段
+	SortOrder       string `json:"sortOrder" form:"sortOrder" example:"desc"`               // Sort order // 排序顺序
+	Paths           string `json:"paths" form:"paths" example:"note1.md,note2.md"`          // Comma-separated exact path list for share filter // 逗号分隔的精确路径列表，用于分享筛选
+	IncludeArchived bool   `json:"includeArchived" form:"includeArchived" example:"false"`  // Include archived folders in keyword search // 关键词搜索时包含归档文件夹
+	Highlight       bool   `json:"highlight" form:"highlight" example:"false"`              // Return a highlighted match snippet per result for keyword searches // 关键词搜索时为每条结果返回高亮匹配片段
 }
 
 // NoteHistoryListRequest Note history list request parameters
@@ -240,23 +271,49 @@ type NoteDTO struct {
 	CreatedAt        timex.Time `json:"createdAt"`                      // Created at time // 创建时间
 }
 
+// NoteDuplicateResult note duplication result
+// NoteDuplicateResult 复制笔记结果
+type NoteDuplicateResult struct {
+	Note  *NoteDTO   `json:"note"`            // Duplicated note // 复制得到的笔记
+	Files []*FileDTO `json:"files,omitempty"` // Duplicated attachments // 复制得到的附件
+}
+
 // NoteNoContentDTO Note DTO without content
 // NoteNoContentDTO 不包含内容的笔记 DTO
 type NoteNoContentDTO struct {
-	ID               int64      `json:"id" form:"id"`                      // Note ID // 笔记 ID
-	Action           string     `json:"-" form:"action"`                  // Action // 动作
-	Path             string     `json:"path" form:"path"`                 // Note path // 笔记路径
-	PathHash         string     `json:"pathHash" form:"pathHash"`         // Path hash // 路径哈希
-	Version          int64      `json:"version" form:"version"`           // Version number // 版本号
-	Ctime            int64      `json:"ctime" form:"ctime"`               // Creation timestamp // 创建时间戳
-	Mtime            int64      `json:"mtime" form:"mtime"`               // Modification timestamp // 修改时间戳
-	Size             int64      `json:"size" form:"size"`                 // Note size // 笔记大小
-	ClientName       string     `json:"clientName"`                       // Client name // 客户端名称
-	ClientType       string     `json:"clientType"`                       // Client type // 客户端类型
-	ClientVersion    string     `json:"clientVersion"`                    // Client version // 客户端版本
-	UpdatedTimestamp int64      `json:"lastTime" form:"updatedTimestamp"` // Record update timestamp // 记录更新时间戳
-	UpdatedAt        timex.Time `json:"updatedAt"`                        // Updated at time // 更新时间
-	CreatedAt        timex.Time `json:"createdAt"`                        // Created at time // 创建时间
+	ID               int64              `json:"id" form:"id"`                     // Note ID // 笔记 ID
+	Action           string             `json:"-" form:"action"`                  // Action // 动作
+	Path             string             `json:"path" form:"path"`                 // Note path // 笔记路径
+	PathHash         string             `json:"pathHash" form:"pathHash"`         // Path hash // 路径哈希
+	Version          int64              `json:"version" form:"version"`           // Version number // 版本号
+	Ctime            int64              `json:"ctime" form:"ctime"`               // Creation timestamp // 创建时间戳
+	Mtime            int64              `json:"mtime" form:"mtime"`               // Modification timestamp // 修改时间戳
+	Size             int64              `json:"size" form:"size"`                 // Note size // 笔记大小
+	ClientName       string             `json:"clientName"`                       // Client name // 客户端名称
+	ClientType       string             `json:"clientType"`                       // Client type // 客户端类型
+	ClientVersion    string             `json:"clientVersion"`                    // Client version // 客户端版本
+	UpdatedTimestamp int64              `json:"lastTime" form:"updatedTimestamp"` // Record update timestamp // 记录更新时间戳
+	UpdatedAt        timex.Time         `json:"updatedAt"`                        // Updated at time // 更新时间
+	CreatedAt        timex.Time         `json:"createdAt"`                        // Created at time // 创建时间
+	Snippet          *NoteSearchSnippet `json:"snippet,omitempty"`                // Match snippet, only when highlight is requested // 匹配片段，仅在请求高亮时返回
+	MatchCount       int                `json:"matchCount,omitempty"`             // Number of regex matches in content, regex search only // 正文中的正则匹配次数，仅正则搜索返回
+	Meta             map[string]any     `json:"meta,omitempty"`                   // Custom metadata by name // 按名称给出的自定义元数据
+}
+
+// NoteSearchSnippet shows why a note matched a keyword search
+// NoteSearchSnippet 说明笔记为何命中关键词搜索
+type NoteSearchSnippet struct {
+	Field   string             `json:"field"`   // Matched field: content or path // 命中字段：content 或 path
+	Text    string             `json:"text"`    // Plain-text match context // 纯文本匹配上下文
+	HTML    string             `json:"html"`    // HTML-escaped context with <mark> tags // 带 <mark> 标签且已转义的 HTML 上下文
+	Matches []NoteSnippetRange `json:"matches"` // Match positions within text // 匹配在 text 中的位置
+}
+
+// NoteSnippetRange is a match position in a snippet, as character (rune) offsets [start, end)
+// NoteSnippetRange 片段中的匹配位置，为字符（rune）偏移区间 [start, end)
+type NoteSnippetRange struct {
+	Start int `json:"start"` // Start offset // 起始偏移
+	End   int `json:"end"`   // End offset (exclusive) // 结束偏移（不含）
 }
 
 // NoteReplaceResponse response for replace operation
@@ -278,18 +335,19 @@ type NoteLinkItem struct {
 // NoteWithFileLinksResponse Note response structure with file links
 // NoteWithFileLinksResponse 带有文件链接的笔记响应结构体
 type NoteWithFileLinksResponse struct {
-	ID               int64             `json:"-"`           // Note ID // 笔记 ID
-	Path             string            `json:"path"`        // Note path // 笔记路径
-	PathHash         string            `json:"pathHash"`    // Path hash // 路径哈希
-	Content          string            `json:"content"`     // Note content // 笔记内容
-	ContentHash      string            `json:"contentHash"` // Content hash // 内容哈希
-	FileLinks        map[string]string `json:"fileLinks"`   // Map of file link to actual path // 文件链接到实际路径的映射
-	Version          int64             `json:"version"`     // Version number // 版本号
-	Ctime            int64             `json:"ctime"`       // Creation timestamp // 创建时间戳
-	Mtime            int64             `json:"mtime"`       // Modification timestamp // 修改时间戳
-	UpdatedTimestamp int64             `json:"lastTime"`    // Record update timestamp // 记录更新时间戳
-	UpdatedAt        interface{}       `json:"updatedAt"`   // Updated at time // 更新时间
-	CreatedAt        interface{}       `json:"createdAt"`   // Created at time // 创建时间
+	ID               int64             `json:"-"`              // Note ID // 笔记 ID
+	Path             string            `json:"path"`           // Note path // 笔记路径
+	PathHash         string            `json:"pathHash"`       // Path hash // 路径哈希
+	Content          string            `json:"content"`        // Note content // 笔记内容
+	ContentHash      string            `json:"contentHash"`    // Content hash // 内容哈希
+	FileLinks        map[string]string `json:"fileLinks"`      // Map of file link to actual path // 文件链接到实际路径的映射
+	Version          int64             `json:"version"`        // Version number // 版本号
+	Ctime            int64             `json:"ctime"`          // Creation timestamp // 创建时间戳
+	Mtime            int64             `json:"mtime"`          // Modification timestamp // 修改时间戳
+	UpdatedTimestamp int64             `json:"lastTime"`       // Record update timestamp // 记录更新时间戳
+	UpdatedAt        interface{}       `json:"updatedAt"`      // Updated at time // 更新时间
+	CreatedAt        interface{}       `json:"createdAt"`      // Created at time // 创建时间
+	Meta             map[string]any    `json:"meta,omitempty"` // Custom metadata by name // 按名称给出的自定义元数据
 }
 
 // NoteHistoryDTO Note history data transfer object
@@ -24,6 +24,7 @@ type NoteSyncModifyMessage struct {
 	Ctime            int64  `json:"ctime" form:"ctime" example:"1700000000"`               // Creation timestamp // 创建时间戳
 	Mtime            int64  `json:"mtime" form:"mtime" example:"1700000000"`               // Modification timestamp // 修改时间戳
 	UpdatedTimestamp int64  `json:"lastTime" form:"updatedTimestamp" example:"1700000000"` // Record update timestamp // 记录更新时间戳
+	OriginClient     string `json:"originClient,omitempty" example:"a1b2c3"`               // Client ID that produced this change // 产生该修改的客户端 ID
 }
 
 // NoteSyncEndMessage message structure returned when sync ends
@@ -71,6 +72,24 @@ type NoteModifyAckMessage struct {
 	PathHash string `json:"pathHash"` // Path hash // 路径哈希值
 }
 
+// SecretFindingDTO a credential detected in note content
+// SecretFindingDTO 笔记内容中检测到的凭据
+type SecretFindingDTO struct {
+	Rule   string `json:"rule" example:"aws-access-key-id"` // Matched rule name // 命中的规则名称
+	Line   int    `json:"line" example:"3"`                 // 1-based line number // 行号（从 1 开始）
+	Column int    `json:"column" example:"7"`               // 1-based column // 列号（从 1 开始）
+	Match  string `json:"match" example:"AKIA********"`     // Masked match preview // 遮盖后的匹配预览
+}
+
+// NoteSecretWarningMessage warns the user that a note contains credentials
+// NoteSecretWarningMessage 提醒用户笔记中包含凭据
+type NoteSecretWarningMessage struct {
+	Path     string             `json:"path"`     // Note path // 笔记路径
+	PathHash string             `json:"pathHash"` // Path hash // 路径哈希值
+	Blocked  bool               `json:"blocked"`  // Whether the write was rejected (strict mode) // 是否已拒绝写入（严格模式）
+	Findings []SecretFindingDTO `json:"findings"` // Detected credentials // 检测到的凭据
+}
+
 // NoteRenameAckMessage note rename operation ACK, sent back to sender after server processes NoteRename
 // NoteRenameAckMessage 笔记重命名操作 ACK，服务端处理完 NoteRename 后回发给发送方
 type NoteRenameAckMessage struct {
@@ -0,0 +1,45 @@
+package dto
+
+// NoteFrontmatterPredicate one condition on a frontmatter property
+// NoteFrontmatterPredicate 针对一个 frontmatter 属性的条件
+type NoteFrontmatterPredicate struct {
+	Key   string `json:"key" binding:"required" example:"status"`                                                // Property name // 属性名
+	Op    string `json:"op" binding:"required,oneof=exists notExists eq ne contains gt gte lt lte" example:"eq"` // exists, notExists, eq, ne, contains, gt, gte, lt or lte // 运算符
+	Value any    `json:"value" example:"done"`                                                                   // Operand; numbers and dates like 2026-01-31 compare by value // 操作数；数字与 2026-01-31 之类的日期按值比较
+}
+
+// NoteFrontmatterQueryRequest request filtering the notes of a vault by frontmatter
+// NoteFrontmatterQueryRequest 按 frontmatter 筛选保险库笔记的请求
+type NoteFrontmatterQueryRequest struct {
+	Vault string                      `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Where []*NoteFrontmatterPredicate `json:"where" binding:"max=20,dive"`                             // Conditions, all must hold; empty matches every note with frontmatter // 条件，需全部满足；为空时匹配所有带 frontmatter 的笔记
+}
+
+// NoteFrontmatterItem a note matching a frontmatter query
+// NoteFrontmatterItem 满足 frontmatter 查询的笔记
+type NoteFrontmatterItem struct {
+	ID               int64          `json:"id"`          // Note ID // 笔记 ID
+	Path             string         `json:"path"`        // Note path // 笔记路径
+	PathHash         string         `json:"pathHash"`    // Path hash // 路径哈希
+	Version          int64          `json:"version"`     // Version number // 版本号
+	Size             int64          `json:"size"`        // Note size // 笔记大小
+	Ctime            int64          `json:"ctime"`       // Creation timestamp // 创建时间戳
+	Mtime            int64          `json:"mtime"`       // Modification timestamp // 修改时间戳
+	UpdatedTimestamp int64          `json:"lastTime"`    // Record update timestamp // 记录更新时间戳
+	Frontmatter      map[string]any `json:"frontmatter"` // Parsed frontmatter // 解析后的 frontmatter
+}
+
+// NotePropertiesRequest request listing the frontmatter properties used in a vault
+// NotePropertiesRequest 列出保险库中使用的 frontmatter 属性的请求
+type NotePropertiesRequest struct {
+	Vault string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+}
+
+// NotePropertyKeyDTO a frontmatter property used in a vault
+// NotePropertyKeyDTO 保险库中使用的 frontmatter 属性
+type NotePropertyKeyDTO struct {
+	Key       string `json:"key"`       // Property name // 属性名
+	Type      string `json:"type"`      // Most common type: string, number, date, bool, object or null // 最常见的类型：string、number、date、bool、object 或 null
+	IsList    bool   `json:"isList"`    // Whether the property is mostly a list // 该属性是否多为列表
+	NoteCount int64  `json:"noteCount"` // Number of notes having the property // 具有该属性的笔记数
+}
@@ -0,0 +1,31 @@
+package dto
+
+// NoteLintRequest Request parameters for checking spelling and grammar of a note or of raw text
+// NoteLintRequest 检查笔记或原始文本拼写与语法的请求参数
+type NoteLintRequest struct {
+	Vault    string `json:"vault" form:"vault" example:"MyVault"`            // Vault name, required when checking a stored note // 保险库名称，检查已存储笔记时必填
+	Path     string `json:"path" form:"path" example:"ReadMe.md"`            // Note path, checked when text is empty // 笔记路径，text 为空时检查该笔记
+	PathHash string `json:"pathHash" form:"pathHash" example:"hash123"`      // Path hash // 路径哈希
+	Text     string `json:"text" form:"text" example:"This are a sentence."` // Raw text, takes precedence over the note // 原始文本，优先于笔记
+	Language string `json:"language" form:"language" example:"en-US"`        // Language code, empty uses the server default // 语言代码，为空时使用服务器默认值
+}
+
+// NoteLintIssueDTO a spelling or grammar issue, offsets count UTF-16 code units like JavaScript string indices
+// NoteLintIssueDTO 拼写或语法问题，偏移以 UTF-16 码元计数，与 JavaScript 字符串下标一致
+type NoteLintIssueDTO struct {
+	Offset       int      `json:"offset"`       // Start of the issue // 问题起始位置
+	Length       int      `json:"length"`       // Length of the issue // 问题长度
+	Message      string   `json:"message"`      // Explanation // 说明
+	ShortMessage string   `json:"shortMessage"` // Short explanation // 简短说明
+	Rule         string   `json:"rule"`         // Rule ID // 规则 ID
+	Category     string   `json:"category"`     // Rule category // 规则分类
+	Replacements []string `json:"replacements"` // Suggested replacements // 替换建议
+}
+
+// NoteLintDTO result of a spelling and grammar check
+// NoteLintDTO 拼写与语法检查结果
+type NoteLintDTO struct {
+	Path    string              `json:"path"`    // Note path, empty when raw text was checked // 笔记路径，检查原始文本时为空
+	Checker string              `json:"checker"` // languagetool or wordlist // languagetool 或 wordlist
+	Issues  []*NoteLintIssueDTO `json:"issues"`  // Issues ordered by offset // 按偏移排序的问题
+}
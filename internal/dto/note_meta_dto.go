@@ -0,0 +1,36 @@
+package dto
+
+// NoteMetaRequest request addressing the metadata of a note
+// NoteMetaRequest 指定笔记元数据的请求
+type NoteMetaRequest struct {
+	Vault    string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Path     string `json:"path" form:"path" binding:"required" example:"ReadMe.md"` // Note path // 笔记路径
+	PathHash string `json:"pathHash" form:"pathHash" example:"hash123"`              // Path hash // 路径哈希
+}
+
+// NoteMetaSetRequest request setting one metadata entry of a note
+// NoteMetaSetRequest 设置笔记一条元数据的请求
+type NoteMetaSetRequest struct {
+	Vault    string `json:"vault" form:"vault" binding:"required" example:"MyVault"`  // Vault name // 保险库名称
+	Path     string `json:"path" form:"path" binding:"required" example:"ReadMe.md"`  // Note path // 笔记路径
+	PathHash string `json:"pathHash" form:"pathHash" example:"hash123"`               // Path hash // 路径哈希
+	Key      string `json:"key" form:"key" binding:"required,max=64" example:"color"` // Attribute name // 属性名
+	Value    any    `json:"value" example:"#ff8800"`                                  // String, number, boolean, object or array // 字符串、数字、布尔值、对象或数组
+}
+
+// NoteMetaDeleteRequest request removing one metadata entry of a note
+// NoteMetaDeleteRequest 删除笔记一条元数据的请求
+type NoteMetaDeleteRequest struct {
+	Vault    string `json:"vault" form:"vault" binding:"required" example:"MyVault"`  // Vault name // 保险库名称
+	Path     string `json:"path" form:"path" binding:"required" example:"ReadMe.md"`  // Note path // 笔记路径
+	PathHash string `json:"pathHash" form:"pathHash" example:"hash123"`               // Path hash // 路径哈希
+	Key      string `json:"key" form:"key" binding:"required,max=64" example:"color"` // Attribute name // 属性名
+}
+
+// NoteMetaDTO metadata of a note
+// NoteMetaDTO 笔记的元数据
+type NoteMetaDTO struct {
+	NoteID int64          `json:"noteId"` // Note ID // 笔记 ID
+	Path   string         `json:"path"`   // Note path // 笔记路径
+	Meta   map[string]any `json:"meta"`   // Attributes by name with their typed values // 按属性名给出的带类型值
+}
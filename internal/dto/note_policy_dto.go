@@ -0,0 +1,81 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// NotePolicyRequest note lifecycle policy create or update request
+// NotePolicyRequest 笔记生命周期策略新建或更新请求
+type NotePolicyRequest struct {
+	ID            int64  `json:"id" form:"id" example:"1"`                                                               // ID, 0 creates a policy // ID，为 0 时新建
+	Vault         string `json:"vault" form:"vault" binding:"required" example:"MyVault"`                                // Vault name // 保险库名称
+	Name          string `json:"name" form:"name" binding:"max=64" example:"Archive stale notes"`                        // Display name // 显示名称
+	Folder        string `json:"folder" form:"folder" example:"Projects"`                                                // Folder the policy applies to, empty for the whole vault // 策略作用的目录，为空表示整个仓库
+	Tag           string `json:"tag" form:"tag" binding:"max=128" example:"temp"`                                        // Only notes carrying this tag, empty for all notes // 仅匹配带有该标签的笔记，为空表示全部
+	AgeBasis      string `json:"ageBasis" form:"ageBasis" binding:"omitempty,oneof=modified created" example:"modified"` // Age measured from modified (default) or created // 年龄依据 modified（默认）或 created
+	OlderThanDays int    `json:"olderThanDays" form:"olderThanDays" binding:"required,min=1,max=36500" example:"365"`    // Minimum age in days // 最小年龄（天）
+	Action        string `json:"action" form:"action" binding:"required,oneof=archive delete" example:"archive"`         // archive or delete // archive 或 delete
+	ArchiveFolder string `json:"archiveFolder" form:"archiveFolder" example:"Archive"`                                   // Target folder of the archive action // 归档操作的目标目录
+	IsEnabled     bool   `json:"isEnabled" form:"isEnabled" example:"true"`                                              // Whether the scheduler runs the policy // 是否由调度器运行
+}
+
+// NotePolicyIDRequest request addressing a single policy
+// NotePolicyIDRequest 指定单个策略的请求
+type NotePolicyIDRequest struct {
+	ID int64 `json:"id" form:"id" binding:"required,gt=0" example:"1"` // Policy ID // 策略 ID
+}
+
+// NotePolicyRunListRequest policy run history request
+// NotePolicyRunListRequest 策略运行历史请求
+type NotePolicyRunListRequest struct {
+	PolicyID int64 `json:"policyId" form:"policyId" example:"1"` // Policy ID, 0 lists every policy // 策略 ID，为 0 时列出全部
+}
+
+// NotePolicyDTO note lifecycle policy
+// NotePolicyDTO 笔记生命周期策略
+type NotePolicyDTO struct {
+	ID            int64      `json:"id"`            // Policy ID // 策略 ID
+	Vault         string     `json:"vault"`         // Vault name // 保险库名称
+	Name          string     `json:"name"`          // Display name // 显示名称
+	Folder        string     `json:"folder"`        // Folder the policy applies to, empty for the whole vault // 策略作用的目录，为空表示整个仓库
+	Tag           string     `json:"tag"`           // Only notes carrying this tag, empty for all notes // 仅匹配带有该标签的笔记，为空表示全部
+	AgeBasis      string     `json:"ageBasis"`      // modified or created // modified 或 created
+	OlderThanDays int        `json:"olderThanDays"` // Minimum age in days // 最小年龄（天）
+	Action        string     `json:"action"`        // archive or delete // archive 或 delete
+	ArchiveFolder string     `json:"archiveFolder"` // Target folder of the archive action // 归档操作的目标目录
+	IsEnabled     bool       `json:"isEnabled"`     // Whether the scheduler runs the policy // 是否由调度器运行
+	LastRunTime   timex.Time `json:"lastRunTime"`   // Last run time // 上次运行时间
+	CreatedAt     timex.Time `json:"createdAt"`     // Created at // 创建时间
+	UpdatedAt     timex.Time `json:"updatedAt"`     // Updated at // 更新时间
+}
+
+// NotePolicyPreviewItemDTO a note a policy would process
+// NotePolicyPreviewItemDTO 策略将要处理的一篇笔记
+type NotePolicyPreviewItemDTO struct {
+	Path   string `json:"path"`   // Note path // 笔记路径
+	Target string `json:"target"` // Path after archiving, empty for deletes // 归档后的路径，删除时为空
+	Mtime  int64  `json:"mtime"`  // Modification time in milliseconds // 修改时间（毫秒）
+	Ctime  int64  `json:"ctime"`  // Creation time in milliseconds // 创建时间（毫秒）
+}
+
+// NotePolicyPreviewDTO dry run of a policy
+// NotePolicyPreviewDTO 策略的试运行结果
+type NotePolicyPreviewDTO struct {
+	PolicyID int64                       `json:"policyId"` // Policy ID // 策略 ID
+	Action   string                      `json:"action"`   // archive or delete // archive 或 delete
+	Matched  int                         `json:"matched"`  // Notes matching the policy // 匹配的笔记数
+	Items    []*NotePolicyPreviewItemDTO `json:"items"`    // Notes a run would process // 运行时将处理的笔记
+}
+
+// NotePolicyRunDTO outcome of one run of a policy
+// NotePolicyRunDTO 策略的一次运行结果
+type NotePolicyRunDTO struct {
+	ID        int64      `json:"id"`        // Run ID // 运行记录 ID
+	PolicyID  int64      `json:"policyId"`  // Policy ID // 策略 ID
+	Trigger   string     `json:"trigger"`   // schedule or manual // schedule 或 manual
+	Matched   int        `json:"matched"`   // Notes matching the policy // 匹配的笔记数
+	Succeeded int        `json:"succeeded"` // Notes archived or deleted // 成功归档或删除的笔记数
+	Failed    int        `json:"failed"`    // Notes that could not be processed // 处理失败的笔记数
+	Paths     []string   `json:"paths"`     // Paths of the processed notes // 已处理笔记的路径
+	Message   string     `json:"message"`   // Errors of the failed notes // 失败笔记的错误信息
+	StartTime timex.Time `json:"startTime"` // Start time // 开始时间
+	EndTime   timex.Time `json:"endTime"`   // End time // 结束时间
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// NotePublishListRequest scheduled publishing status request
+// NotePublishListRequest 定时发布状态查询请求
+type NotePublishListRequest struct {
+	Vault  string `json:"vault" form:"vault" binding:"required" example:"MyVault"`                                                   // Vault name // 保险库名称
+	Status string `json:"status" form:"status" binding:"omitempty,oneof=scheduled published failed unpublished" example:"scheduled"` // Only states with this status, empty for all // 仅返回该状态，为空表示全部
+}
+
+// NotePublishDTO publishing state of a note scheduled through its frontmatter
+// NotePublishDTO 通过 frontmatter 计划发布的笔记的发布状态
+type NotePublishDTO struct {
+	ID          int64      `json:"id"`          // State ID // 状态 ID
+	Vault       string     `json:"vault"`       // Vault name // 保险库名称
+	Path        string     `json:"path"`        // Note path // 笔记路径
+	PathHash    string     `json:"pathHash"`    // Note path hash // 笔记路径哈希
+	Status      string     `json:"status"`      // scheduled, published, failed or unpublished // scheduled、published、failed 或 unpublished
+	PublishAt   timex.Time `json:"publishAt"`   // Scheduled time from publish_at, empty publishes at once // 来自 publish_at 的计划时间，为空表示立即发布
+	PublishedAt timex.Time `json:"publishedAt"` // Time the share was created // 分享创建时间
+	ShareID     int64      `json:"shareId"`     // Share exposing the note // 公开该笔记的分享
+	ShareToken  string     `json:"shareToken"`  // Token of the share // 分享 Token
+	Message     string     `json:"message"`     // Error of the last attempt // 最近一次尝试的错误
+	UpdatedAt   timex.Time `json:"updatedAt"`   // Updated at // 更新时间
+}
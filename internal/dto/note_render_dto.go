@@ -0,0 +1,20 @@
+package dto
+
+// NoteRenderRequest Request parameters for rendering a note to HTML
+// NoteRenderRequest 将笔记渲染为 HTML 的请求参数
+type NoteRenderRequest struct {
+	Vault    string  `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Path     string  `json:"path" form:"path" binding:"required" example:"ReadMe.md"` // Note path, relative links resolve from its folder // 笔记路径，相对链接从其所在目录解析
+	PathHash string  `json:"pathHash" form:"pathHash" example:"hash123"`              // Path hash // 路径哈希
+	Content  *string `json:"content" form:"content" example:"# Title"`                // Unsaved content to preview, the stored note is rendered when omitted // 待预览的未保存内容，省略时渲染已存储的笔记
+}
+
+// NoteRenderDTO a note rendered to sanitized HTML
+// NoteRenderDTO 渲染为经过清洗的 HTML 的笔记
+type NoteRenderDTO struct {
+	Path        string                 `json:"path"`                  // Note path // 笔记路径
+	HTML        string                 `json:"html"`                  // Sanitized HTML of the body // 正文经过清洗的 HTML
+	Frontmatter map[string]interface{} `json:"frontmatter,omitempty"` // Parsed frontmatter, not part of the HTML // 解析后的 frontmatter，不包含在 HTML 中
+	NoteLinks   map[string]string      `json:"noteLinks"`             // Wiki link target to note path // 维基链接目标到笔记路径的映射
+	FileLinks   map[string]string      `json:"fileLinks"`             // Embed target to attachment path // 嵌入目标到附件路径的映射
+}
@@ -0,0 +1,55 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// NoteTemplateRequest note template create or update request
+// NoteTemplateRequest 笔记模板新建或更新请求
+type NoteTemplateRequest struct {
+	Name        string `json:"name" form:"name" binding:"required,max=128" example:"Daily"`               // Unique name // 唯一名称
+	Description string `json:"description" form:"description" binding:"max=255" example:"Daily journal"`  // Description // 描述
+	Content     string `json:"content" form:"content" example:"# {{title}}\n\nCreated {{date}} {{time}}"` // Content with {{date}}, {{time}} and {{title}} variables // 包含 {{date}}、{{time}}、{{title}} 变量的内容
+}
+
+// NoteTemplateDTO note template
+// NoteTemplateDTO 笔记模板
+type NoteTemplateDTO struct {
+	ID          int64      `json:"id"`          // Template ID // 模板 ID
+	Name        string     `json:"name"`        // Unique name // 唯一名称
+	Description string     `json:"description"` // Description // 描述
+	Content     string     `json:"content"`     // Template content // 模板内容
+	CreatedAt   timex.Time `json:"createdAt"`   // Created at // 创建时间
+	UpdatedAt   timex.Time `json:"updatedAt"`   // Updated at // 更新时间
+}
+
+// NoteDailySettingRequest daily note setting update request
+// NoteDailySettingRequest 每日笔记设置更新请求
+type NoteDailySettingRequest struct {
+	Pattern  string `json:"pattern" form:"pattern" binding:"max=255" example:"Journal/{{YYYY-MM-DD}}.md"` // Path pattern with Moment.js tokens in braces, empty resets to the default // 花括号内为 Moment.js 标记的路径模式，为空时恢复默认
+	Template string `json:"template" form:"template" binding:"max=128" example:"Daily"`                   // Template a missing daily note is created from, empty for an empty note // 创建缺失的每日笔记所用的模板，为空时创建空笔记
+	Timezone string `json:"timezone" form:"timezone" binding:"max=64" example:"Asia/Shanghai"`            // IANA time zone deciding what "today" is, empty for the server's local time // 决定“今天”的 IANA 时区，为空时使用服务器本地时间
+}
+
+// NoteDailySettingDTO daily note setting
+// NoteDailySettingDTO 每日笔记设置
+type NoteDailySettingDTO struct {
+	Pattern   string     `json:"pattern"`   // Path pattern // 路径模式
+	Template  string     `json:"template"`  // Template name // 模板名称
+	Timezone  string     `json:"timezone"`  // IANA time zone // IANA 时区
+	UpdatedAt timex.Time `json:"updatedAt"` // Updated at // 更新时间
+}
+
+// NoteDailyRequest daily note request
+// NoteDailyRequest 每日笔记请求
+type NoteDailyRequest struct {
+	Vault   string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Date    string `json:"date" form:"date" example:"2026-03-07"`                   // Day as YYYY-MM-DD, empty for today // 日期，格式 YYYY-MM-DD，为空表示今天
+	Append  string `json:"append" form:"append" example:"- 09:30 Standup"`          // Content appended to the note // 追加到笔记末尾的内容
+	Prepend string `json:"prepend" form:"prepend" example:"> Focus: release\n"`     // Content inserted at the top of the note, after frontmatter // 插入到笔记开头（Frontmatter 之后）的内容
+}
+
+// NoteDailyResult daily note response
+// NoteDailyResult 每日笔记响应
+type NoteDailyResult struct {
+	Note    *NoteDTO `json:"note"`    // The daily note after the call // 调用后的每日笔记
+	Created bool     `json:"created"` // Whether the call created the note // 本次调用是否新建了笔记
+}
@@ -0,0 +1,32 @@
+package dto
+
+// NoteUnresolvedLinkRequest Request parameters for listing the unresolved links of a vault
+// NoteUnresolvedLinkRequest 列出仓库中未解析链接的请求参数
+type NoteUnresolvedLinkRequest struct {
+	Vault string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+}
+
+// NoteUnresolvedLinkItem a link target that matches no existing note
+// NoteUnresolvedLinkItem 未匹配任何已存在笔记的链接目标
+type NoteUnresolvedLinkItem struct {
+	Target   string   `json:"target"`   // Link target without heading // 不含标题的链接目标
+	StubPath string   `json:"stubPath"` // Path of the note that would resolve the link // 可解析该链接的笔记路径
+	Count    int      `json:"count"`    // Number of links to the target // 指向该目标的链接数量
+	Sources  []string `json:"sources"`  // Paths of the linking notes // 发起链接的笔记路径
+}
+
+// NoteUnresolvedLinkCreateRequest Request parameters for creating stub notes for unresolved links
+// NoteUnresolvedLinkCreateRequest 为未解析链接创建占位笔记的请求参数
+type NoteUnresolvedLinkCreateRequest struct {
+	Vault   string   `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Targets []string `json:"targets" form:"targets" binding:"omitempty,max=1000"`     // Targets to create, all when empty // 需要创建的目标，为空时创建全部
+	Folder  string   `json:"folder" form:"folder" example:"Inbox"`                    // Folder the stubs are created in // 创建占位笔记的目录
+	Content string   `json:"content" form:"content" example:"#stub"`                  // Content of the stubs // 占位笔记的内容
+}
+
+// NoteUnresolvedLinkCreateResult result of creating stub notes
+// NoteUnresolvedLinkCreateResult 创建占位笔记的结果
+type NoteUnresolvedLinkCreateResult struct {
+	Created []*NoteDTO `json:"created"` // Created stub notes // 已创建的占位笔记
+	Skipped []string   `json:"skipped"` // Targets left alone because the path is invalid or taken // 因路径无效或已被占用而跳过的目标
+}
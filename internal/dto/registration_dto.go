@@ -0,0 +1,47 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// UserInviteCreateRequest invitation code creation parameters
+// UserInviteCreateRequest 创建邀请码请求参数
+type UserInviteCreateRequest struct {
+	MaxUses   int64  `json:"maxUses" form:"maxUses" binding:"min=0" example:"5"`       // Registrations allowed, 0 means unlimited // 允许注册的次数，0 表示不限
+	ExpiresIn string `json:"expiresIn" form:"expiresIn" binding:"max=16" example:"7d"` // Validity (e.g. 24h, 7d), empty never expires // 有效期（如 24h、7d），为空表示永不过期
+	Note      string `json:"note" form:"note" binding:"max=255" example:"for Bob"`     // Admin note // 管理员备注
+}
+
+// UserInviteDeleteRequest invitation code deletion parameters
+// UserInviteDeleteRequest 删除邀请码请求参数
+type UserInviteDeleteRequest struct {
+	ID int64 `json:"id" form:"id" binding:"required,gt=0" example:"1"` // Invitation ID // 邀请码 ID
+}
+
+// UserInviteDTO an invitation code
+// UserInviteDTO 一个邀请码
+type UserInviteDTO struct {
+	ID        int64       `json:"id"`                  // Invitation ID // 邀请码 ID
+	Code      string      `json:"code"`                // Invitation code // 邀请码
+	MaxUses   int64       `json:"maxUses"`             // Registrations allowed, 0 means unlimited // 允许注册的次数，0 表示不限
+	Uses      int64       `json:"uses"`                // Registrations so far // 已注册次数
+	ExpiresAt *timex.Time `json:"expiresAt,omitempty"` // Expiry, absent never expires // 过期时间，缺省表示永不过期
+	Usable    bool        `json:"usable"`              // Whether it still admits registrations // 是否仍可用于注册
+	Note      string      `json:"note"`                // Admin note // 管理员备注
+	CreatedBy int64       `json:"createdBy"`           // UID of the creating admin // 创建者 UID
+	CreatedAt timex.Time  `json:"createdAt"`           // Creation time // 创建时间
+}
+
+// PendingUserRequest pending registration approval or rejection parameters
+// PendingUserRequest 批准或拒绝待审核注册的请求参数
+type PendingUserRequest struct {
+	ID int64 `json:"id" form:"id" binding:"required,gt=0" example:"1"` // Pending registration ID // 待审核注册 ID
+}
+
+// PendingUserDTO a registration waiting for approval
+// PendingUserDTO 一条待审核的注册
+type PendingUserDTO struct {
+	ID        int64      `json:"id"`        // Pending registration ID // 待审核注册 ID
+	Email     string     `json:"email"`     // Email // 邮箱
+	Username  string     `json:"username"`  // Username // 用户名
+	IP        string     `json:"ip"`        // Client IP of the registration // 注册时的客户端 IP
+	CreatedAt timex.Time `json:"createdAt"` // Registration time // 注册时间
+}
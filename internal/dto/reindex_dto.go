@@ -0,0 +1,24 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// ReindexJobDTO progress of a user's background full-text index rebuild
+// ReindexJobDTO 用户后台全文索引重建任务的进度
+type ReindexJobDTO struct {
+	UID         int64       `json:"uid"`                  // User ID // 用户 ID
+	Status      string      `json:"status"`               // queued, running, done, failed or canceled // queued、running、done、failed 或 canceled
+	VaultsTotal int         `json:"vaultsTotal"`          // Vaults to rebuild // 待重建的仓库数
+	VaultsDone  int         `json:"vaultsDone"`           // Vaults rebuilt // 已重建的仓库数
+	NotesTotal  int         `json:"notesTotal"`           // Notes of the vaults started so far // 已开始重建的仓库中的笔记总数
+	NotesDone   int         `json:"notesDone"`            // Notes indexed // 已索引的笔记数
+	Error       string      `json:"error,omitempty"`      // Failure reason // 失败原因
+	QueuedAt    timex.Time  `json:"queuedAt"`             // Queued at // 入队时间
+	StartedAt   *timex.Time `json:"startedAt,omitempty"`  // Started at // 开始时间
+	FinishedAt  *timex.Time `json:"finishedAt,omitempty"` // Finished at // 结束时间
+}
+
+// ReindexRequest selects the user of a reindex job
+// ReindexRequest 指定索引重建任务所属的用户
+type ReindexRequest struct {
+	UID int64 `json:"uid" form:"uid" example:"1"` // User ID, 0 means all users when starting // 用户 ID，启动时为 0 表示全部用户
+}
@@ -0,0 +1,31 @@
+package dto
+
+// UserSettingsRequest user settings update request, fields left out are not changed
+// UserSettingsRequest 用户设置更新请求，未传的字段保持不变
+type UserSettingsRequest struct {
+	SoftDeleteRetentionTime *string `json:"softDeleteRetentionTime" form:"softDeleteRetentionTime" example:"30d"` // Soft delete retention like 30d or 12h, 0 keeps deleted items forever, empty uses the server default // 软删除保留时间，如 30d、12h，0 表示永久保留，为空使用服务器默认值
+}
+
+// UserSettingsDTO settings of the current user
+// UserSettingsDTO 当前用户的设置
+type UserSettingsDTO struct {
+	SoftDeleteRetentionTime          string `json:"softDeleteRetentionTime"`          // User-wide soft delete retention, empty uses the server default // 用户级软删除保留时间，为空使用服务器默认值
+	DefaultSoftDeleteRetentionTime   string `json:"defaultSoftDeleteRetentionTime"`   // Server default soft delete retention, 0 keeps deleted items forever // 服务器默认软删除保留时间，0 表示永久保留
+	EffectiveSoftDeleteRetentionTime string `json:"effectiveSoftDeleteRetentionTime"` // Retention applied to vaults without their own override // 未单独设置的保险库实际使用的保留时间
+}
+
+// VaultRetentionRequest vault soft delete retention update request
+// VaultRetentionRequest 保险库软删除保留时间更新请求
+type VaultRetentionRequest struct {
+	Vault                   string `json:"vault" form:"vault" binding:"required" example:"MyVault"`             // Vault name // 保险库名称
+	SoftDeleteRetentionTime string `json:"softDeleteRetentionTime" form:"softDeleteRetentionTime" example:"7d"` // Soft delete retention like 7d or 12h, 0 keeps deleted items forever, empty uses the user setting // 软删除保留时间，如 7d、12h，0 表示永久保留，为空使用用户设置
+}
+
+// VaultRetentionDTO soft delete retention of a vault
+// VaultRetentionDTO 保险库的软删除保留时间
+type VaultRetentionDTO struct {
+	VaultID                          int64  `json:"vaultId"`                          // Vault ID // 保险库 ID
+	Vault                            string `json:"vault"`                            // Vault name // 保险库名称
+	SoftDeleteRetentionTime          string `json:"softDeleteRetentionTime"`          // Vault override, empty uses the user setting // 保险库覆盖值，为空使用用户设置
+	EffectiveSoftDeleteRetentionTime string `json:"effectiveSoftDeleteRetentionTime"` // Retention applied to the vault, 0 keeps deleted items forever // 保险库实际使用的保留时间，0 表示永久保留
+}
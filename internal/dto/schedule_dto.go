@@ -0,0 +1,37 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// ScheduleUpdateRequest change the schedule of a task
+// ScheduleUpdateRequest 修改任务的调度
+type ScheduleUpdateRequest struct {
+	Name     string `json:"name" form:"name" binding:"required,max=100" example:"DbCleanup"` // Task name // 任务名称
+	Schedule string `json:"schedule" form:"schedule" binding:"max=200" example:"30 4 * * *"` // Cron expression, @daily, @every 90m or off; empty restores the built-in interval // cron 表达式、@daily、@every 90m 或 off；为空时恢复内置间隔
+}
+
+// ScheduleRunRequest run a task by hand
+// ScheduleRunRequest 手动执行任务
+type ScheduleRunRequest struct {
+	Name string `json:"name" form:"name" binding:"required,max=100" example:"DbCleanup"` // Task name // 任务名称
+}
+
+// ScheduleDTO schedule and last run of a task
+// ScheduleDTO 任务的调度与上次执行情况
+type ScheduleDTO struct {
+	Name           string      `json:"name"`                     // Task name // 任务名称
+	Default        string      `json:"default"`                  // Built-in schedule, empty when the task only runs on startup or by hand // 内置调度，仅在启动时或手动执行时为空
+	Schedule       string      `json:"schedule"`                 // Schedule in effect // 生效的调度
+	Custom         bool        `json:"custom"`                   // Schedule set in task.schedules // 调度来自 task.schedules
+	StartupRun     bool        `json:"startupRun"`               // Runs once when the server starts // 服务启动时执行一次
+	Heavy          bool        `json:"heavy"`                    // Scheduled runs wait for the maintenance window // 定时执行等待维护窗口
+	Running        bool        `json:"running"`                  // A run is in progress // 正在执行
+	Runs           uint64      `json:"runs"`                     // Finished runs since the server started // 服务启动以来完成的执行次数
+	Failures       uint64      `json:"failures"`                 // Failed runs since the server started // 服务启动以来失败的执行次数
+	LastTrigger    string      `json:"lastTrigger,omitempty"`    // startup, schedule or manual // startup、schedule 或 manual
+	LastStartedAt  *timex.Time `json:"lastStartedAt,omitempty"`  // Start of the last run // 上次执行的开始时间
+	LastFinishedAt *timex.Time `json:"lastFinishedAt,omitempty"` // End of the last run // 上次执行的结束时间
+	LastDurationMs int64       `json:"lastDurationMs"`           // Duration of the last run (ms) // 上次执行的耗时（毫秒）
+	LastResult     string      `json:"lastResult,omitempty"`     // success, failed, or deferred to the maintenance window // success、failed，或 deferred（推迟到维护窗口）
+	LastError      string      `json:"lastError,omitempty"`      // Error of the last failed run // 上次失败执行的错误
+	NextRunAt      *timex.Time `json:"nextRunAt,omitempty"`      // Next scheduled run // 下次定时执行时间
+}
@@ -82,6 +82,30 @@ type ShareCreateResponse struct {
 	BaseUrl    string    `json:"baseUrl"`    // Base URL for sharing // 分享基础 URL
 }
 
+// ShareFolderFeed the recently modified notes of a shared folder
+// ShareFolderFeed 分享文件夹中最近修改的笔记
+type ShareFolderFeed struct {
+	ShareID int64             `json:"shareId"` // Share ID // 分享记录 ID
+	Vault   string            `json:"vault"`   // Vault name // 保险库名称
+	Folder  string            `json:"folder"`  // Shared folder path // 分享的文件夹路径
+	Title   string            `json:"title"`   // Folder name // 文件夹名称
+	Updated   time.Time         `json:"updated"`   // Latest note modification, or the share creation time // 最近的笔记修改时间，无笔记时为分享创建时间
+	CreatedAt time.Time         `json:"createdAt"` // Share creation time // 分享创建时间
+	Entries []*ShareFeedEntry `json:"entries"` // Notes, most recently modified first // 笔记，最近修改的在前
+}
+
+// ShareFeedEntry a note of a shared folder feed
+// ShareFeedEntry 分享文件夹订阅源中的笔记
+type ShareFeedEntry struct {
+	NoteID  int64  `json:"noteId"`  // Note ID // 笔记 ID
+	Path    string `json:"path"`    // Path relative to the shared folder // 相对于分享文件夹的路径
+	Title   string `json:"title"`   // Note title // 笔记标题
+	Summary string `json:"summary"` // Plain-text excerpt // 纯文本摘要
+	HTML    string `json:"html"`    // Sanitized HTML of the body // 正文经过清洗的 HTML
+	Ctime   int64  `json:"ctime"`   // Creation timestamp // 创建时间戳
+	Mtime   int64  `json:"mtime"`   // Modification timestamp // 修改时间戳
+}
+
 // ShareListItem Represents a share item in list
 // ShareListItem 分享列表项
 type ShareListItem struct {
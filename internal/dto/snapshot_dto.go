@@ -0,0 +1,36 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// SnapshotDTO local snapshot information
+// SnapshotDTO 本地快照信息
+type SnapshotDTO struct {
+	Name      string     `json:"name"`      // Snapshot name // 快照名称
+	CreatedAt timex.Time `json:"createdAt"` // Creation time // 创建时间
+	Databases int        `json:"databases"` // Number of database files // 数据库文件数量
+	Files     int        `json:"files"`     // Number of content files // 内容文件数量
+	Size      int64      `json:"size"`      // Total size in bytes // 总大小（字节）
+}
+
+// SnapshotRestoreRequest snapshot restore request
+// SnapshotRestoreRequest 快照恢复请求
+type SnapshotRestoreRequest struct {
+	Name string `json:"name" form:"name" binding:"required" example:"snapshot_20260101T000000"` // Snapshot name // 快照名称
+}
+
+// DatabaseExportDTO hot database export information
+// DatabaseExportDTO 数据库热导出信息
+type DatabaseExportDTO struct {
+	Path       string                   `json:"path"`       // Export directory // 导出目录
+	ExportedAt timex.Time               `json:"exportedAt"` // Time of the last export run // 最近一次导出时间
+	Size       int64                    `json:"size"`       // Total size in bytes // 总大小（字节）
+	Databases  []*DatabaseExportFileDTO `json:"databases"`  // Exported database files // 导出的数据库文件
+}
+
+// DatabaseExportFileDTO an exported database file
+// DatabaseExportFileDTO 一个导出的数据库文件
+type DatabaseExportFileDTO struct {
+	UID  int64  `json:"uid"`  // Owner user ID, 0 for the main database // 所属用户 ID，主库为 0
+	File string `json:"file"` // Path relative to the export directory // 相对导出目录的路径
+	Size int64  `json:"size"` // Size in bytes // 大小（字节）
+}
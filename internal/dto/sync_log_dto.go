@@ -30,3 +30,60 @@ type SyncLogDTO struct {
 	Message       string     `json:"message"`       // Additional message // 附加消息
 	CreatedAt     timex.Time `json:"createdAt"`     // Log creation time // 创建时间
 }
+
+// VaultSyncStatusRequest Request parameters for the sync status of the vaults
+// VaultSyncStatusRequest 查询保险库同步状态的请求参数
+type VaultSyncStatusRequest struct {
+	Vault string `json:"vault" form:"vault" example:"MyVault"`                          // Vault name (optional filter) // 保险库名称（可选过滤）
+	Days  int    `json:"days" form:"days" binding:"omitempty,min=1,max=90" example:"7"` // Days of sync logs covered, default 7 // 统计的同步日志天数，默认 7
+}
+
+// SyncStatusDTO Sync health of the vaults and devices of a user
+// SyncStatusDTO 用户保险库与设备的同步健康状态
+type SyncStatusDTO struct {
+	UpToDate bool                   `json:"upToDate"` // No device has pending changes and no client failed its last sync // 没有设备存在待同步变更且没有客户端最近一次同步失败
+	Since    timex.Time             `json:"since"`    // Start of the covered period // 统计起始时间
+	Vaults   []*VaultSyncStatusDTO  `json:"vaults"`   // Per vault status // 各保险库状态
+	Devices  []*DeviceSyncStatusDTO `json:"devices"`  // Per device status // 各设备状态
+}
+
+// VaultSyncStatusDTO Sync activity of one vault
+// VaultSyncStatusDTO 单个保险库的同步活动
+type VaultSyncStatusDTO struct {
+	VaultID      int64                  `json:"vaultId"`      // Vault ID // 保险库 ID
+	Vault        string                 `json:"vault"`        // Vault name // 保险库名称
+	LastSyncAt   timex.Time             `json:"lastSyncAt"`   // Last successful sync // 最近一次成功同步时间
+	Changes      int64                  `json:"changes"`      // Synced changes // 已同步的变更数
+	Bytes        int64                  `json:"bytes"`        // Bytes transferred // 传输字节数
+	Failures     int64                  `json:"failures"`     // Failed syncs // 同步失败次数
+	Clients      []*SyncClientStatusDTO `json:"clients"`      // Per client activity, most recent first // 各客户端活动，最近的在前
+	RecentErrors []*SyncLogDTO          `json:"recentErrors"` // Most recent failures, newest first // 最近的失败记录，最新的在前
+}
+
+// SyncClientStatusDTO Sync activity of one client in a vault
+// SyncClientStatusDTO 单个客户端在保险库中的同步活动
+type SyncClientStatusDTO struct {
+	ClientName    string     `json:"clientName"`    // Client name // 客户端名称
+	ClientType    string     `json:"clientType"`    // Client type // 客户端类型
+	ClientVersion string     `json:"clientVersion"` // Latest client version // 最新的客户端版本
+	LastSyncAt    timex.Time `json:"lastSyncAt"`    // Last successful sync // 最近一次成功同步时间
+	LastErrorAt   timex.Time `json:"lastErrorAt"`   // Last failed sync // 最近一次同步失败时间
+	LastError     string     `json:"lastError"`     // Message of the last failure // 最近一次失败的消息
+	Changes       int64      `json:"changes"`       // Synced changes // 已同步的变更数
+	Bytes         int64      `json:"bytes"`         // Bytes transferred // 传输字节数
+	Failures      int64      `json:"failures"`      // Failed syncs // 同步失败次数
+}
+
+// DeviceSyncStatusDTO Sync state of one device
+// DeviceSyncStatusDTO 单个设备的同步状态
+type DeviceSyncStatusDTO struct {
+	DeviceKey      string     `json:"deviceKey"`      // Stable device identifier // 稳定的设备标识
+	ClientName     string     `json:"clientName"`     // Client name // 客户端名称
+	ClientType     string     `json:"clientType"`     // Client type // 客户端类型
+	ClientVersion  string     `json:"clientVersion"`  // Client version // 客户端版本
+	IsOnline       bool       `json:"isOnline"`       // Whether the device is connected now // 设备当前是否在线
+	LastSeenAt     timex.Time `json:"lastSeenAt"`     // Last seen at // 最近出现时间
+	LastSyncAt     timex.Time `json:"lastSyncAt"`     // Last successful sync of the client in any vault // 该客户端在任意保险库中最近一次成功同步时间
+	PendingChanges int64      `json:"pendingChanges"` // Broadcast changes the device has not received yet // 设备尚未收到的广播变更数
+	UpToDate       bool       `json:"upToDate"`       // No pending changes and the last sync did not fail // 没有待同步变更且最近一次同步未失败
+}
@@ -15,6 +15,10 @@ type WSQueuedMessage struct {
 	// NoteID 非零时，表示该消息的笔记正文需要在同步分页发送前由发送方按需回填
 	// （此时 Data 须为 Content 留空的 NoteSyncModifyMessage）。不参与序列化。
 	NoteID int64 `json:"-"`
+
+	// Size content size in bytes of a lazily filled note, used to cut download pages by bytes. Not serialized.
+	// Size 按需回填笔记的正文字节数，用于按字节切分下载分页。不参与序列化。
+	Size int64 `json:"-"`
 }
 
 // SyncPageMessage 服务端分页下发控制消息
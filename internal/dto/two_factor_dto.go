@@ -0,0 +1,28 @@
+package dto
+
+// TwoFactorStatusDTO Two-factor authentication status
+// TwoFactorStatusDTO 两步验证状态
+type TwoFactorStatusDTO struct {
+	Enabled                bool `json:"enabled"`                // Whether 2FA is enabled // 是否已启用两步验证
+	RecoveryCodesRemaining int  `json:"recoveryCodesRemaining"` // Unused recovery codes // 剩余可用恢复码数量
+	Required               bool `json:"required"`               // Whether 2FA is required for this account // 当前账号是否被要求启用两步验证
+}
+
+// TwoFactorSetupDTO Two-factor provisioning data
+// TwoFactorSetupDTO 两步验证配置数据
+type TwoFactorSetupDTO struct {
+	Secret string `json:"secret"` // Base32 secret for manual entry // 供手动输入的 Base32 密钥
+	URI    string `json:"uri"`    // otpauth:// URI to render as QR code // 用于生成二维码的 otpauth:// URI
+}
+
+// TwoFactorCodeRequest Request carrying a TOTP or recovery code
+// TwoFactorCodeRequest 携带动态码或恢复码的请求参数
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" form:"code" binding:"required" example:"123456"` // TOTP or recovery code // 动态码或恢复码
+}
+
+// TwoFactorEnableDTO Result of enabling 2FA
+// TwoFactorEnableDTO 启用两步验证的结果
+type TwoFactorEnableDTO struct {
+	RecoveryCodes []string `json:"recoveryCodes"` // One-time recovery codes, shown only once // 一次性恢复码，仅显示一次
+}
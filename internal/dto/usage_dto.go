@@ -0,0 +1,44 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// UsageBytesDTO disk usage breakdown in bytes
+// UsageBytesDTO 磁盘用量明细（字节）
+type UsageBytesDTO struct {
+	Notes       int64 `json:"notes"`       // Note content files // 笔记内容文件
+	Attachments int64 `json:"attachments"` // Attachments // 附件
+	History     int64 `json:"history"`     // History snapshots and diffs // 历史快照与差异
+	Settings    int64 `json:"settings"`    // Setting content files // 配置内容文件
+	BackupTemp  int64 `json:"backupTemp"`  // In-progress backups and cached exports in the temp path // 临时目录中进行中的备份与缓存的导出
+	Database    int64 `json:"database"`    // SQLite database files, 0 for other database types // SQLite 数据库文件，其他数据库类型为 0
+	Total       int64 `json:"total"`       // Sum of all the above // 以上各项合计
+}
+
+// VaultUsageDTO content size of a vault, as recorded on its notes and attachments
+// VaultUsageDTO 仓库的内容大小，取自笔记与附件记录
+type VaultUsageDTO struct {
+	VaultID   int64  `json:"vaultId"`   // Vault ID // 仓库 ID
+	Vault     string `json:"vault"`     // Vault name // 仓库名称
+	NoteCount int64  `json:"noteCount"` // Notes // 笔记数
+	NoteSize  int64  `json:"noteSize"`  // Note bytes // 笔记字节数
+	FileCount int64  `json:"fileCount"` // Attachments // 附件数
+	FileSize  int64  `json:"fileSize"`  // Attachment bytes // 附件字节数
+	Size      int64  `json:"size"`      // Note and attachment bytes // 笔记与附件字节数
+}
+
+// UserUsageDTO disk usage of a user
+// UserUsageDTO 用户的磁盘用量
+type UserUsageDTO struct {
+	UID      int64            `json:"uid"`      // User ID // 用户 ID
+	Username string           `json:"username"` // Username // 用户名
+	Usage    UsageBytesDTO    `json:"usage"`    // On-disk bytes by kind // 按类型统计的磁盘字节数
+	Vaults   []*VaultUsageDTO `json:"vaults"`   // Per vault content size // 各仓库的内容大小
+}
+
+// StorageUsageDTO disk usage of the whole instance
+// StorageUsageDTO 整个实例的磁盘用量
+type StorageUsageDTO struct {
+	Totals      UsageBytesDTO   `json:"totals"`      // Instance totals // 实例合计
+	Users       []*UserUsageDTO `json:"users"`       // Per user usage, largest first // 每个用户的用量，按大小倒序
+	GeneratedAt timex.Time      `json:"generatedAt"` // Generation time // 生成时间
+}
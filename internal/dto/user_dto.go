@@ -11,6 +11,7 @@ type UserCreateRequest struct {
 	Username        string `json:"username" form:"username" binding:"required" example:"username123"`               // User name // 用户名
 	Password        string `json:"password" form:"password" binding:"required" example:"password123"`               // User password // 用户密码
 	ConfirmPassword string `json:"confirmPassword" form:"confirmPassword" binding:"required" example:"password123"` // Confirm password // 校验密码
+	InviteCode      string `json:"inviteCode" form:"inviteCode" binding:"max=64" example:"x7Kp2mQa"`                // Invitation code, admits the registration while open registration is off // 邀请码，开放注册关闭时也可注册
 }
 
 // UserUpdateRequest User update request parameters
@@ -25,9 +26,11 @@ type UserUpdateRequest struct {
 // UserLoginRequest User login request parameters
 // 用户登录请求参数
 type UserLoginRequest struct {
-	Credentials string `json:"credentials" form:"credentials" binding:"required" example:"user@example.com"` // Username or Email // 登录凭证（用户名或邮件）
-	Password    string `json:"password" form:"password" binding:"required" example:"password123"`            // Password // 密码
-	TokenID     int64  `json:"tokenId" form:"tokenId" example:"123"`                                         // Last token ID for rotation // 最后一个用于轮转的令牌ID
+	Credentials  string `json:"credentials" form:"credentials" binding:"required" example:"user@example.com"` // Username or Email // 登录凭证（用户名或邮件）
+	Password     string `json:"password" form:"password" binding:"required" example:"password123"`            // Password // 密码
+	TokenID      int64  `json:"tokenId" form:"tokenId" example:"123"`                                         // Last token ID for rotation // 最后一个用于轮转的令牌ID
+	TOTPCode     string `json:"totpCode" form:"totpCode" example:"123456"`                                    // TOTP or recovery code when 2FA is enabled // 启用两步验证时的动态码或恢复码
+	CaptchaToken string `json:"captchaToken" form:"captchaToken" example:"0.xxxx"`                            // Captcha response, required after repeated failed logins // 验证码响应，多次登录失败后必填
 }
 
 // UserRegisterSendEmailRequest Request parameters for sending registration email
@@ -44,18 +47,66 @@ type UserChangePasswordRequest struct {
 	ConfirmPassword string `json:"confirmPassword" form:"confirmPassword" binding:"required" example:"new_password123"` // Confirm password // 校验密码
 }
 
+// UserPasswordResetSendRequest Request parameters for sending a password reset email
+// 发送密码重置邮件请求参数
+type UserPasswordResetSendRequest struct {
+	Email string `json:"email" form:"email" binding:"required,email" example:"user@example.com"` // Account email // 账号邮箱
+}
+
+// UserResetPasswordRequest Request parameters for setting a new password with a reset token
+// 使用重置令牌设置新密码请求参数
+type UserResetPasswordRequest struct {
+	Token           string `json:"token" form:"token" binding:"required"`                                               // Token of the reset link // 重置链接中的令牌
+	Password        string `json:"password" form:"password" binding:"required" example:"new_password123"`               // New password // 新密码
+	ConfirmPassword string `json:"confirmPassword" form:"confirmPassword" binding:"required" example:"new_password123"` // Confirm password // 校验密码
+}
+
 // ---------------- DTO / Response ----------------
 
 // UserDTO User data transfer object
 // UserDTO 用户数据传输对象
 type UserDTO struct {
-	UID       int64      `json:"uid"`       // User ID (primary key) // 用户唯一标识（主键）
-	Email     string     `json:"email"`     // Email address // 邮件地址
-	Username  string     `json:"username"`  // Username // 用户名
-	Token     string     `json:"token"`     // Authentication Token // 认证 Token
-	TokenID   int64      `json:"tokenId"`   // Authentication Token ID // 认证 Token ID
-	Avatar    string     `json:"avatar"`    // Avatar URL or handle // 头像路径或名称
-	IsDeleted bool       `json:"isDeleted"` // User is blocked
-	UpdatedAt timex.Time `json:"updatedAt"` // Last updated time // 最后更新时间
-	CreatedAt timex.Time `json:"createdAt"` // Account created time // 账号创建时间
+	UID               int64      `json:"uid"`                         // User ID (primary key) // 用户唯一标识（主键）
+	Email             string     `json:"email"`                       // Email address // 邮件地址
+	Username          string     `json:"username"`                    // Username // 用户名
+	Token             string     `json:"token"`                       // Authentication Token // 认证 Token
+	TokenID           int64      `json:"tokenId"`                     // Authentication Token ID // 认证 Token ID
+	Avatar            string     `json:"avatar"`                      // Avatar URL or handle // 头像路径或名称
+	IsDeleted         bool       `json:"isDeleted"`                   // User is blocked
+	UpdatedAt         timex.Time `json:"updatedAt"`                   // Last updated time // 最后更新时间
+	CreatedAt         timex.Time `json:"createdAt"`                   // Account created time // 账号创建时间
+	TOTPSetupRequired bool       `json:"totpSetupRequired,omitempty"` // Admin must enroll 2FA, the token only reaches /api/user/totp until the next login // 管理员需要设置两步验证，重新登录前 Token 仅能访问 /api/user/totp
+}
+
+// UserCapabilitiesDTO experimental features evaluated for the current user
+// UserCapabilitiesDTO 当前用户的实验性功能开关结果
+type UserCapabilitiesDTO struct {
+	UID      int64           `json:"uid"`      // User ID // 用户 ID
+	Features map[string]bool `json:"features"` // Every known feature and whether it is on // 所有已知功能及其是否开启
+	Enabled  []string        `json:"enabled"`  // Names of the features that are on, sorted // 已开启功能的名称，已排序
+}
+
+// UserHeatmapRequest Request parameters for the writing activity heatmap
+// UserHeatmapRequest 写作活动热力图的请求参数
+type UserHeatmapRequest struct {
+	Days int `json:"days" form:"days" binding:"omitempty,min=1,max=366" example:"365"` // Days up to today, default 365 // 截至今天的天数，默认 365
+}
+
+// UserHeatmapDTO Note edits per day across all vaults of the user, GitHub contributions style
+// UserHeatmapDTO 用户全部保险库每天的笔记编辑次数，形式与 GitHub 贡献图相同
+type UserHeatmapDTO struct {
+	Days          []*UserHeatmapDayDTO `json:"days"`          // One entry per day, oldest first // 每天一项，按日期升序
+	Total         int64                `json:"total"`         // Edits in the range // 范围内的编辑次数
+	ActiveDays    int                  `json:"activeDays"`    // Days with at least one edit // 至少有一次编辑的天数
+	MaxCount      int64                `json:"maxCount"`      // Edits on the busiest day // 最忙一天的编辑次数
+	CurrentStreak int                  `json:"currentStreak"` // Consecutive active days up to today, or up to yesterday while today has no edit yet // 截至今天连续活跃的天数，今天尚无编辑时截至昨天
+	LongestStreak int                  `json:"longestStreak"` // Longest run of consecutive active days in the range // 范围内最长的连续活跃天数
+}
+
+// UserHeatmapDayDTO Note edits of one day
+// UserHeatmapDayDTO 某一天的笔记编辑次数
+type UserHeatmapDayDTO struct {
+	Date  string `json:"date" example:"2024-01-02"` // Day in server local time // 服务器本地时间的日期
+	Count int64  `json:"count"`                     // Saved note edits // 保存的笔记编辑次数
+	Level int    `json:"level"`                     // Intensity from 0 (none) to 4 (busiest), relative to maxCount // 强度，0（无）到 4（最忙），相对于 maxCount
 }
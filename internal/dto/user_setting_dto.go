@@ -0,0 +1,54 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// UserSettingGetRequest per-user setting query parameters
+// UserSettingGetRequest 获取用户设置请求参数
+type UserSettingGetRequest struct {
+	Key string `json:"key" form:"key" binding:"required,max=255" example:"editor.theme"` // Setting key // 设置键
+}
+
+// UserSettingListRequest per-user settings list parameters
+// UserSettingListRequest 获取用户设置列表请求参数
+type UserSettingListRequest struct {
+	LastTime int64  `json:"lastTime" form:"lastTime" binding:"min=0" example:"1700000000000"` // Only settings updated after this server timestamp, 0 lists all // 仅返回该服务端时间戳之后更新的设置，0 返回全部
+	Context  string `json:"context" form:"context" binding:"max=255" example:"req-1"`         // Echoed back in the WebSocket reply // 在 WebSocket 回复中原样返回
+}
+
+// UserSettingPutRequest per-user setting write parameters
+// UserSettingPutRequest 写入用户设置请求参数
+type UserSettingPutRequest struct {
+	Key     string `json:"key" form:"key" binding:"required,max=255" example:"editor.theme"` // Setting key // 设置键
+	Value   string `json:"value" form:"value" binding:"max=1048576" example:"dark"`          // Setting value, opaque to the server // 设置值，服务端不解析
+	Version int64  `json:"version" form:"version" binding:"min=0" example:"3"`               // Version the change is based on, 0 for a new key // 修改所基于的版本，新键为 0
+	Mtime   int64  `json:"mtime" form:"mtime" binding:"min=0" example:"1700000000000"`       // Client modification time in milliseconds, 0 uses the server time // 客户端修改时间（毫秒），0 使用服务端时间
+}
+
+// UserSettingDeleteRequest per-user setting deletion parameters
+// UserSettingDeleteRequest 删除用户设置请求参数
+type UserSettingDeleteRequest struct {
+	Key     string `json:"key" form:"key" binding:"required,max=255" example:"editor.theme"` // Setting key // 设置键
+	Version int64  `json:"version" form:"version" binding:"min=0" example:"3"`               // Version the deletion is based on // 删除所基于的版本
+	Mtime   int64  `json:"mtime" form:"mtime" binding:"min=0" example:"1700000000000"`       // Client modification time in milliseconds, 0 uses the server time // 客户端修改时间（毫秒），0 使用服务端时间
+}
+
+// UserSettingDTO a per-user setting
+// UserSettingDTO 一项用户设置
+type UserSettingDTO struct {
+	Key              string     `json:"key"`       // Setting key // 设置键
+	Value            string     `json:"value"`     // Setting value // 设置值
+	Version          int64      `json:"version"`   // Current version // 当前版本
+	Mtime            int64      `json:"mtime"`     // Client modification time in milliseconds // 客户端修改时间（毫秒）
+	Deleted          bool       `json:"deleted"`   // Whether the setting was deleted // 是否已删除
+	UpdatedTimestamp int64      `json:"lastTime"`  // Server update timestamp, the sync cursor // 服务端更新时间戳，即同步游标
+	UpdatedAt        timex.Time `json:"updatedAt"` // Update time // 更新时间
+}
+
+// UserSettingsSyncMessage per-user settings pushed to the devices of the user, both as the reply to a
+// SettingsSync request and whenever another device changes a setting
+// UserSettingsSyncMessage 推送给用户设备的用户设置，既作为 SettingsSync 请求的回复，也在其他设备修改设置时推送
+type UserSettingsSyncMessage struct {
+	Settings []*UserSettingDTO `json:"settings"` // Changed settings, deletions included // 变更的设置，包含删除
+	LastTime int64             `json:"lastTime"` // Cursor to send with the next SettingsSync request, 0 in pushes // 下次 SettingsSync 请求应携带的游标，推送时为 0
+	Context  string            `json:"context"`  // Context of the request, empty for pushes // 请求的上下文，推送时为空
+}
@@ -29,6 +29,40 @@ type VaultForceDeleteItemRequest struct {
 	ID       int64  `json:"id" form:"id" binding:"required" example:"100"`                      // Resource ID // 资源 ID
 }
 
+// VaultImportRequest Request parameters for importing a ZIP of Markdown files into a vault
+// 将 Markdown 文件 ZIP 包导入保险库的请求参数
+type VaultImportRequest struct {
+	Vault      string `json:"vault" form:"vault" binding:"required" example:"MyVault"`       // Vault name, created if missing // 保险库名称，不存在时自动创建
+	Folder     string `json:"folder" form:"folder" example:"Imported"`                       // Target folder inside the vault, empty for root // 导入到保险库内的目标目录，为空表示根目录
+	ServerPath string `json:"serverPath" form:"serverPath" example:"/data/import/notes.zip"` // ZIP path on the server instead of an upload (admin only) // 服务器上的 ZIP 路径，替代上传（仅管理员）
+}
+
+// VaultImportLocalRequest Request parameters for importing an Obsidian vault directory on the server into a vault
+// 将服务器上的 Obsidian 保险库目录导入保险库的请求参数
+type VaultImportLocalRequest struct {
+	Vault  string `json:"vault" form:"vault" binding:"required" example:"MyVault"`              // Vault name, created if missing // 保险库名称，不存在时自动创建
+	Folder string `json:"folder" form:"folder" example:"Imported"`                              // Target folder inside the vault, empty for root // 导入到保险库内的目标目录，为空表示根目录
+	Path   string `json:"path" form:"path" binding:"required" example:"/data/obsidian/MyVault"` // Vault directory on the server // 服务器上的保险库目录
+}
+
+// VaultExportRequest Request parameters for downloading a vault as a ZIP archive
+// 将保险库下载为 ZIP 压缩包的请求参数
+type VaultExportRequest struct {
+	Vault  string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Folder string `json:"folder" form:"folder" example:"Projects"`                 // Only export this folder, empty for the whole vault // 仅导出该目录，为空表示整个保险库
+	Since  int64  `json:"since" form:"since" example:"1700000000000"`              // Only export entries changed after this timestamp (ms) // 仅导出该时间戳（毫秒）之后变更的条目
+}
+
+// VaultSiteExportRequest Request parameters for publishing a vault as a static HTML site
+// 将保险库发布为静态 HTML 站点的请求参数
+type VaultSiteExportRequest struct {
+	Vault     string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Folder    string `json:"folder" form:"folder" example:"Public"`                   // Only publish this folder, e.g. a shared folder // 仅发布该目录，例如分享的文件夹
+	Title     string `json:"title" form:"title" example:"My Notes"`                   // Site title, default the vault or folder name // 站点标题，默认为保险库或目录名称
+	StorageID int64  `json:"storageId" form:"storageId" example:"1"`                  // Push the site through this storage target // 通过该存储目标推送站点
+	OutDir    string `json:"outDir" form:"outDir" example:"/var/www/notes"`           // Write the site to this server directory instead (admin only) // 改为写入服务器上的该目录（仅管理员）
+}
+
 // ---------------- DTO / Response ----------------
 // ---------------- DTO / 响应参数 ----------------
 
@@ -45,3 +79,205 @@ type VaultDTO struct {
 	CreatedAt string `json:"createdAt"` // Creation time // 创建时间
 	UpdatedAt string `json:"updatedAt"` // Updated time // 更新时间
 }
+
+// VaultSiteExportResult summary of a generated static site
+// VaultSiteExportResult 生成静态站点后的汇总
+type VaultSiteExportResult struct {
+	Pages  int    `json:"pages"`  // Note pages rendered // 渲染的笔记页面数
+	Files  int    `json:"files"`  // Attachments copied // 复制的附件数
+	Target string `json:"target"` // Directory or storage path the site was written to // 站点写入的目录或存储路径
+}
+
+// VaultImportResult summary of a finished vault import
+// VaultImportResult 保险库导入完成后的汇总
+type VaultImportResult struct {
+	Notes   int      `json:"notes"`            // Notes created or updated // 新建或更新的笔记数
+	Files   int      `json:"files"`            // Attachments created or updated // 新建或更新的附件数
+	Folders int      `json:"folders"`          // Folders ensured // 确保存在的文件夹数
+	Skipped int      `json:"skipped"`          // Entries skipped (unchanged, hidden or unsafe paths) // 跳过的条目数（未变化、隐藏或不安全路径）
+	Failed  int      `json:"failed"`           // Entries that failed to import // 导入失败的条目数
+	Errors  []string `json:"errors,omitempty"` // First few failure reasons // 前若干条失败原因
+}
+
+// VaultImportProgressMessage WebSocket progress message for a running vault import
+// VaultImportProgressMessage 保险库导入进行中的 WebSocket 进度消息
+type VaultImportProgressMessage struct {
+	Total     int      `json:"total"`            // Entries in the archive // 压缩包内条目总数
+	Processed int      `json:"processed"`        // Entries processed so far // 已处理条目数
+	Notes     int      `json:"notes"`            // Notes imported so far // 已导入笔记数
+	Files     int      `json:"files"`            // Attachments imported so far // 已导入附件数
+	Skipped   int      `json:"skipped"`          // Entries skipped so far // 已跳过条目数
+	Failed    int      `json:"failed"`           // Entries failed so far // 已失败条目数
+	Done      bool     `json:"done"`             // Import finished; clients should run an incremental sync // 导入已完成，客户端应执行一次增量同步
+	Errors    []string `json:"errors,omitempty"` // First few failure reasons, sent with Done // 前若干条失败原因，随 Done 发送
+	Error     string   `json:"error,omitempty"`  // Reason the import stopped, sent with Done // 导入中止的原因，随 Done 发送
+}
+
+// VaultImportJob background job running a vault import
+// VaultImportJob 执行保险库导入的后台任务
+type VaultImportJob struct {
+	JobID string `json:"jobId"` // Job ID in the background job queue // 后台任务队列中的任务 ID
+}
+
+// VaultTrashRequest Request parameters for listing the trash of a vault
+// 获取保险库回收站列表的请求参数
+type VaultTrashRequest struct {
+	Vault string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+}
+
+// VaultTrashItemDTO A deleted note, file or folder in the trash
+// 回收站中的已删除笔记、文件或文件夹
+type VaultTrashItemDTO struct {
+	Type      string `json:"type" example:"note"`               // Item type: note, file or folder // 条目类型：note、file 或 folder
+	ID        int64  `json:"id" example:"100"`                  // Item ID // 条目 ID
+	Path      string `json:"path" example:"Daily/a.md"`         // Item path // 条目路径
+	PathHash  string `json:"pathHash"`                          // Path hash // 路径哈希
+	Size      int64  `json:"size"`                              // Size in bytes, 0 for folders // 大小（字节），文件夹为 0
+	DeletedAt int64  `json:"deletedAt" example:"1700000000000"` // Deletion timestamp in milliseconds // 删除时间戳（毫秒）
+}
+
+// VaultTrashItem Identifies one trash item to purge
+// 指定一个待彻底删除的回收站条目
+type VaultTrashItem struct {
+	Type     string `json:"type" form:"type" binding:"required,oneof=note file folder" example:"note"` // Item type: note, file or folder // 条目类型：note、file 或 folder
+	Path     string `json:"path" form:"path" example:"Daily/a.md"`                                     // Item path // 条目路径
+	PathHash string `json:"pathHash" form:"pathHash"`                                                  // Path hash, derived from path when empty // 路径哈希，为空时由路径计算
+}
+
+// VaultTrashEmptyRequest Request parameters for purging trash items of a vault
+// 彻底删除保险库回收站条目的请求参数
+type VaultTrashEmptyRequest struct {
+	Vault string           `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Items []VaultTrashItem `json:"items" form:"items" binding:"omitempty,dive"`             // Items to purge // 待彻底删除的条目
+	All   bool             `json:"all" form:"all"`                                          // Purge the whole trash, Items is ignored // 清空整个回收站，忽略 Items
+}
+
+// VaultAsOfRequest Request parameters for listing the notes of a vault as they existed at a point in time
+// 获取保险库在某一时间点的笔记列表的请求参数
+type VaultAsOfRequest struct {
+	Vault     string `json:"vault" form:"vault" binding:"required" example:"MyVault"`                    // Vault name // 保险库名称
+	Timestamp int64  `json:"timestamp" form:"timestamp" binding:"required,gt=0" example:"1700000000000"` // Point in time in milliseconds // 时间点（毫秒）
+}
+
+// VaultAsOfItemDTO A note that existed at the requested point in time
+// 在请求的时间点存在的笔记
+type VaultAsOfItemDTO struct {
+	Path     string `json:"path" example:"Daily/a.md"` // Note path // 笔记路径
+	PathHash string `json:"pathHash"`                  // Path hash // 路径哈希
+	Changed  bool   `json:"changed"`                   // Modified or deleted since then // 此后是否被修改或删除
+	Deleted  bool   `json:"deleted"`                   // Deleted since then // 此后是否已被删除
+}
+
+// VaultAsOfNoteRequest Request parameters for getting note content as it existed at a point in time
+// 获取笔记在某一时间点内容的请求参数
+type VaultAsOfNoteRequest struct {
+	Vault     string `json:"vault" form:"vault" binding:"required" example:"MyVault"`                    // Vault name // 保险库名称
+	Path      string `json:"path" form:"path" example:"Daily/a.md"`                                      // Note path // 笔记路径
+	PathHash  string `json:"pathHash" form:"pathHash"`                                                   // Path hash, derived from path when empty // 路径哈希，为空时由路径计算
+	Timestamp int64  `json:"timestamp" form:"timestamp" binding:"required,gt=0" example:"1700000000000"` // Point in time in milliseconds // 时间点（毫秒）
+}
+
+// VaultAsOfNoteDTO Note content as it existed at a point in time
+// 笔记在某一时间点的内容
+type VaultAsOfNoteDTO struct {
+	Path        string `json:"path" example:"Daily/a.md"`         // Note path // 笔记路径
+	PathHash    string `json:"pathHash"`                          // Path hash // 路径哈希
+	Content     string `json:"content"`                           // Content at that time // 该时间点的内容
+	ContentHash string `json:"contentHash"`                       // Content hash // 内容哈希
+	Source      string `json:"source" example:"history"`          // Where the content comes from: history or note // 内容来源：history（历史版本）或 note（笔记本身）
+	Version     int64  `json:"version"`                           // History version the content was taken from, 0 for note // 内容所取自的历史版本号，来源为 note 时为 0
+	Timestamp   int64  `json:"timestamp" example:"1700000000000"` // Requested point in time in milliseconds // 请求的时间点（毫秒）
+}
+
+// VaultTrashEmptyResult Number of purged items per type
+// 各类型被彻底删除的条目数量
+type VaultTrashEmptyResult struct {
+	Notes   int `json:"notes"`   // Purged notes // 已删除笔记数
+	Files   int `json:"files"`   // Purged files // 已删除文件数
+	Folders int `json:"folders"` // Purged folders // 已删除文件夹数
+}
+
+// VaultActivityRequest Request parameters for the recent activity timeline of a vault
+// 获取保险库最近活动时间线的请求参数
+type VaultActivityRequest struct {
+	Vault    string `json:"vault" form:"vault" binding:"required" example:"MyVault"`                 // Vault name // 保险库名称
+	Page     int    `json:"page" form:"page" binding:"omitempty,min=1" example:"1"`                  // Page number, starting from 1 // 页码，从 1 开始
+	PageSize int    `json:"pageSize" form:"pageSize" binding:"omitempty,min=1,max=100" example:"50"` // Events per page, default 50 // 每页事件数，默认 50
+}
+
+// VaultActivityItemDTO One change in the activity timeline
+// 活动时间线中的一次变更
+type VaultActivityItemDTO struct {
+	Type      string `json:"type" example:"note"`               // Item type: note or file // 条目类型：note 或 file
+	Kind      string `json:"kind" example:"modify"`             // Change: create, modify, upload, rename, delete or restore // 变更类型：create、modify、upload、rename、delete 或 restore
+	Path      string `json:"path" example:"Daily/a.md"`         // Item path, empty for a rename whose new path changed since // 条目路径，重命名后新路径又发生变更时为空
+	OldPath   string `json:"oldPath,omitempty"`                 // Path before a rename // 重命名前的路径
+	PathHash  string `json:"pathHash"`                          // Path hash // 路径哈希
+	Size      int64  `json:"size"`                              // Size in bytes, 0 when unknown // 大小（字节），未知时为 0
+	Client    string `json:"client,omitempty"`                  // Client that made the change, when known // 做出变更的客户端（已知时）
+	Timestamp int64  `json:"timestamp" example:"1700000000000"` // Change time in milliseconds // 变更时间（毫秒）
+}
+
+// VaultActivityDayDTO Changes of one day, newest first
+// 某一天的变更，最新的排在前面
+type VaultActivityDayDTO struct {
+	Date  string                  `json:"date" example:"2024-01-02"` // Day in server local time // 服务器本地时间的日期
+	Items []*VaultActivityItemDTO `json:"items"`                     // Changes of the day // 当天的变更
+}
+
+// VaultActivityDTO One page of the activity timeline, grouped by day
+// 按天分组的一页活动时间线
+type VaultActivityDTO struct {
+	Days     []*VaultActivityDayDTO `json:"days"`     // Days of the page, newest first // 本页包含的日期，最新的排在前面
+	Page     int                    `json:"page"`     // Page number // 页码
+	PageSize int                    `json:"pageSize"` // Events per page // 每页事件数
+	HasMore  bool                   `json:"hasMore"`  // Whether older events follow // 是否还有更早的事件
+}
+
+// VaultStatsRequest Request parameters for the word count statistics of a vault
+// 获取保险库字数统计的请求参数
+type VaultStatsRequest struct {
+	Vault string `json:"vault" form:"vault" binding:"required" example:"MyVault"`         // Vault name // 保险库名称
+	Top   int    `json:"top" form:"top" binding:"omitempty,min=1,max=100" example:"10"`   // Number of longest notes, default 10 // 最长笔记的数量，默认 10
+	Days  int    `json:"days" form:"days" binding:"omitempty,min=1,max=365" example:"30"` // Days of writing activity up to today, default 30 // 截至今天的写作活动天数，默认 30
+}
+
+// VaultStatsDTO Word count statistics of a vault
+// 保险库字数统计
+type VaultStatsDTO struct {
+	Notes          int64                  `json:"notes"`          // Notes // 笔记数
+	Words          int64                  `json:"words"`          // Words, each CJK character counts as one // 字数，每个中日韩字符计为一个
+	Chars          int64                  `json:"chars"`          // Characters, whitespace excluded // 字符数，不含空白
+	ReadingMinutes int64                  `json:"readingMinutes"` // Estimated reading time of all notes // 全部笔记的预计阅读时间（分钟）
+	Folders        []*VaultStatsFolderDTO `json:"folders"`        // Per folder, most words first // 按文件夹统计，字数多的排在前面
+	Longest        []*VaultStatsNoteDTO   `json:"longest"`        // Longest notes by words // 字数最多的笔记
+	Activity       []*VaultStatsDayDTO    `json:"activity"`       // Writing activity per day, oldest first // 每日写作活动，按日期升序
+}
+
+// VaultStatsFolderDTO Word counts of the notes directly in one folder
+// 直接位于某个文件夹中的笔记的字数统计
+type VaultStatsFolderDTO struct {
+	Folder string `json:"folder" example:"Daily"` // Folder path, empty for the vault root // 文件夹路径，保险库根目录为空
+	Notes  int64  `json:"notes"`                  // Notes // 笔记数
+	Words  int64  `json:"words"`                  // Words // 字数
+	Chars  int64  `json:"chars"`                  // Characters // 字符数
+}
+
+// VaultStatsNoteDTO Word counts of one note
+// 单篇笔记的字数统计
+type VaultStatsNoteDTO struct {
+	Path           string `json:"path" example:"Daily/a.md"` // Note path // 笔记路径
+	PathHash       string `json:"pathHash"`                  // Path hash // 路径哈希
+	Words          int64  `json:"words"`                     // Words // 字数
+	Chars          int64  `json:"chars"`                     // Characters // 字符数
+	ReadingMinutes int64  `json:"readingMinutes"`            // Estimated reading time // 预计阅读时间（分钟）
+}
+
+// VaultStatsDayDTO Note edits saved on one day
+// 某一天保存的笔记编辑
+type VaultStatsDayDTO struct {
+	Date         string `json:"date" example:"2024-01-02"` // Day in server local time // 服务器本地时间的日期
+	Edits        int64  `json:"edits"`                     // Saved note edits // 保存的笔记编辑次数
+	WordsAdded   int64  `json:"wordsAdded"`                // Words added // 新增字数
+	WordsRemoved int64  `json:"wordsRemoved"`              // Words removed // 删除字数
+}
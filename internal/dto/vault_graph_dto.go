@@ -0,0 +1,37 @@
+package dto
+
+// VaultGraphRequest Request parameters for the note link graph of a vault
+// VaultGraphRequest 获取仓库笔记链接图的请求参数
+type VaultGraphRequest struct {
+	Vault    string `json:"vault" form:"vault" binding:"required" example:"MyVault"` // Vault name // 保险库名称
+	Folder   string `json:"folder" form:"folder" example:"Projects"`                 // Only notes in this folder // 仅包含该目录下的笔记
+	Tag      string `json:"tag" form:"tag" example:"project"`                        // Only notes with this tag, nested tags included // 仅包含带有该标签的笔记，包括其嵌套标签
+	Path     string `json:"path" form:"path" example:"ReadMe.md"`                    // Center note of the neighborhood mode // 邻域模式的中心笔记
+	PathHash string `json:"pathHash" form:"pathHash" example:"hash123"`              // Path hash of the center note // 中心笔记的路径哈希
+	Depth    int    `json:"depth" form:"depth" binding:"gte=0,lte=5" example:"1"`    // Link hops from the center note, defaults to 1 // 距中心笔记的链接跳数，默认为 1
+}
+
+// VaultGraphNode a note of the link graph
+// VaultGraphNode 链接图中的笔记
+type VaultGraphNode struct {
+	ID   int64    `json:"id"`   // Note ID // 笔记 ID
+	Path string   `json:"path"` // Note path // 笔记路径
+	Name string   `json:"name"` // File name without .md // 不含 .md 的文件名
+	Tags []string `json:"tags"` // Tags without "#" // 不含 "#" 的标签
+}
+
+// VaultGraphEdge a link between two notes of the graph
+// VaultGraphEdge 图中两篇笔记之间的链接
+type VaultGraphEdge struct {
+	Source  int64 `json:"source"`  // Linking note ID // 发起链接的笔记 ID
+	Target  int64 `json:"target"`  // Linked note ID // 被链接的笔记 ID
+	IsEmbed bool  `json:"isEmbed"` // Is it an embed (![[...]]) // 是否为嵌入
+}
+
+// VaultGraphDTO note link graph of a vault
+// VaultGraphDTO 仓库的笔记链接图
+type VaultGraphDTO struct {
+	Nodes     []*VaultGraphNode `json:"nodes"`     // Notes ordered by path // 按路径排序的笔记
+	Edges     []*VaultGraphEdge `json:"edges"`     // Links between the returned notes // 返回的笔记之间的链接
+	Truncated bool              `json:"truncated"` // Nodes were cut at the size limit // 节点数量达到上限被截断
+}
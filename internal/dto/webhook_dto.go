@@ -0,0 +1,63 @@
+package dto
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+// WebhookRequest webhook create or update request
+// WebhookRequest Webhook 新建或更新请求
+type WebhookRequest struct {
+	ID        int64    `json:"id" form:"id" example:"1"`                                                          // ID, 0 creates a webhook // ID，为 0 时新建
+	Name      string   `json:"name" form:"name" binding:"max=64" example:"Home automation"`                       // Display name // 显示名称
+	URL       string   `json:"url" form:"url" binding:"required,max=2048" example:"https://example.com/fns-hook"` // Endpoint receiving the POSTs // 接收 POST 请求的地址
+	Secret    string   `json:"secret" form:"secret" binding:"max=256" example:"s3cret"`                           // HMAC-SHA256 signing secret, empty sends unsigned requests // HMAC-SHA256 签名密钥，为空时不签名
+	Events    []string `json:"events" form:"events" example:"note.created,backup.failed"`                         // Subscribed events, empty subscribes to all // 订阅的事件，为空表示全部
+	IsEnabled bool     `json:"isEnabled" form:"isEnabled" example:"true"`                                         // Whether deliveries are sent // 是否投递
+}
+
+// WebhookDeleteRequest webhook delete request
+// WebhookDeleteRequest Webhook 删除请求
+type WebhookDeleteRequest struct {
+	ID int64 `json:"id" form:"id" binding:"required,gt=0" example:"1"` // Webhook ID // Webhook ID
+}
+
+// WebhookDeliveryListRequest webhook delivery log request
+// WebhookDeliveryListRequest Webhook 投递日志请求
+type WebhookDeliveryListRequest struct {
+	WebhookID int64 `json:"webhookId" form:"webhookId" example:"1"` // Webhook ID, 0 lists every webhook // Webhook ID，为 0 时列出全部
+}
+
+// WebhookDTO webhook DTO
+// WebhookDTO Webhook DTO
+type WebhookDTO struct {
+	ID        int64      `json:"id"`        // Webhook ID // Webhook ID
+	Name      string     `json:"name"`      // Display name // 显示名称
+	URL       string     `json:"url"`       // Endpoint receiving the POSTs // 接收 POST 请求的地址
+	Secret    string     `json:"secret"`    // HMAC-SHA256 signing secret // HMAC-SHA256 签名密钥
+	Events    []string   `json:"events"`    // Subscribed events, empty subscribes to all // 订阅的事件，为空表示全部
+	IsEnabled bool       `json:"isEnabled"` // Whether deliveries are sent // 是否投递
+	CreatedAt timex.Time `json:"createdAt"` // Created at // 创建时间
+	UpdatedAt timex.Time `json:"updatedAt"` // Updated at // 更新时间
+}
+
+// WebhookDeliveryDTO one event delivered to one webhook
+// WebhookDeliveryDTO 一个事件向一个 Webhook 的投递
+type WebhookDeliveryDTO struct {
+	ID        int64      `json:"id"`        // Delivery ID, also sent as deliveryId in the payload // 投递 ID，同时作为负载中的 deliveryId 发送
+	WebhookID int64      `json:"webhookId"` // Webhook ID // Webhook ID
+	Event     string     `json:"event"`     // Event name // 事件名
+	Payload   string     `json:"payload"`   // JSON body sent // 发送的 JSON 内容
+	Status    string     `json:"status"`    // pending / success / failed // 投递状态
+	Attempts  int        `json:"attempts"`  // Attempts made so far // 已尝试次数
+	Error     string     `json:"error"`     // Error of the last failed attempt // 最近一次失败的错误信息
+	CreatedAt timex.Time `json:"createdAt"` // Event time // 事件时间
+	UpdatedAt timex.Time `json:"updatedAt"` // Last attempt time // 最近一次尝试时间
+}
+
+// WebhookPayload JSON body POSTed to webhooks
+// WebhookPayload 向 Webhook POST 的 JSON 内容
+type WebhookPayload struct {
+	DeliveryID int64  `json:"deliveryId"` // Delivery ID, identical across retries // 投递 ID，重试时保持不变
+	Event      string `json:"event"`      // Event name // 事件名
+	UID        int64  `json:"uid"`        // User the event belongs to // 事件所属用户
+	Timestamp  int64  `json:"timestamp"`  // Event time in milliseconds // 事件时间（毫秒）
+	Data       any    `json:"data"`       // Event details // 事件详情
+}
@@ -43,7 +43,10 @@ func Cors(allowedOrigins []string, extApiUrl string) gin.HandlerFunc {
 		}
 
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, X-Requested-With, X-CSRF-Token, X-Client, X-Client-Name, X-Client-Version, X-Default-Vault-Name, AccessToken, Authorization, Debug, Domain, Token, Share-Token, Lang, Content-Type, Content-Length, Accept")
+		c.Header("Access-Control-Allow-Headers", "Origin, X-Requested-With, X-CSRF-Token, X-Client, X-Client-Name, X-Client-Version, X-Default-Vault-Name, AccessToken, Authorization, Debug, Domain, Token, Share-Token, Lang, Content-Type, Content-Length, Accept, Range, If-Range, If-None-Match")
+		// Range responses of attachment downloads must be readable by players and download managers
+		// 附件下载的 Range 响应头需对播放器与下载工具可见
+		c.Header("Access-Control-Expose-Headers", "Content-Range, Accept-Ranges, Content-Length, ETag")
 
 		if allowedOrigin != "" {
 			c.Header("Access-Control-Allow-Origin", allowedOrigin)
@@ -56,7 +59,9 @@ func Cors(allowedOrigins []string, extApiUrl string) gin.HandlerFunc {
 
 		// Allow OPTIONS requests to pass
 		// 允许放行OPTIONS请求
-		if c.Request.Method == "OPTIONS" {
+		// WebDAV clients send OPTIONS to discover capabilities, only browser preflights are answered here
+		// WebDAV 客户端通过 OPTIONS 探测能力，此处仅应答浏览器预检请求
+		if c.Request.Method == "OPTIONS" && (origin != "" || !IsDAVPath(c.Request.URL.Path)) {
 			c.AbortWithStatus(http.StatusNoContent)
 		}
 		c.Next()
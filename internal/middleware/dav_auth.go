@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DAVPrefix URL prefix of the built-in WebDAV endpoint
+// DAVPrefix 内置 WebDAV 端点的 URL 前缀
+const DAVPrefix = "/dav"
+
+// DAVClientType client type recorded for requests that do not send X-Client
+// DAVClientType 未携带 X-Client 的请求所记录的客户端类型
+const DAVClientType = "WebDAV"
+
+// IsDAVPath reports whether the request path belongs to the WebDAV endpoint
+// IsDAVPath 判断请求路径是否属于 WebDAV 端点
+func IsDAVPath(p string) bool {
+	return p == DAVPrefix || strings.HasPrefix(p, DAVPrefix+"/")
+}
+
+// davResource maps a WebDAV path to the token function resource: notes and folders are "note", attachments are "file"
+// davResource 将 WebDAV 路径映射为 Token 功能资源：笔记和文件夹为 "note"，附件为 "file"
+func davResource(p string) string {
+	ext := strings.ToLower(path.Ext(p))
+	if ext == "" || ext == ".md" {
+		return "note"
+	}
+	return "file"
+}
+
+// DAVAuthWithConfig user Token authentication middleware of the WebDAV endpoint.
+// The token is accepted as the HTTP Basic password; failures answer with a Basic challenge instead of a JSON body
+// so that clients prompt for credentials.
+// DAVAuthWithConfig WebDAV 端点的用户 Token 认证中间件。
+// Token 作为 HTTP Basic 密码传入；认证失败时返回 Basic 质询而非 JSON，以便客户端提示输入凭据。
+func DAVAuthWithConfig(secretKey string, realm string, tokenService service.TokenService) gin.HandlerFunc {
+	challenge := fmt.Sprintf("Basic realm=%q, charset=\"UTF-8\"", realm)
+	return func(c *gin.Context) {
+		if c.GetHeader("x-client") == "" && c.Query("client") == "" {
+			c.Request.Header.Set("X-Client", DAVClientType)
+		}
+
+		user, scope, vaults, dbToken, appErr := AuthenticateUserToken(c, secretKey, tokenService)
+		if appErr != nil {
+			status := http.StatusUnauthorized
+			if errors.Is(appErr, code.ErrorAuthTokenScopeRestricted) {
+				status = http.StatusForbidden
+			} else {
+				c.Header("WWW-Authenticate", challenge)
+			}
+			c.String(status, appErr.Msg())
+			c.Abort()
+			return
+		}
+		c.Set("user_token", user)
+		c.Set("scope", scope)
+		c.Set("vaults", vaults)
+		c.Set("token_issue_type", dbToken.IssueType)
+		c.Set("token_client_type", dbToken.ClientType)
+		c.Next()
+	}
+}
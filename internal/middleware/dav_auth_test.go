@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func runDAVAuthMiddleware(t *testing.T, tokenService *fakeMiddlewareTokenService, method, target string, configure func(*http.Request)) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(DAVAuthWithConfig("test-secret", "FNS", tokenService))
+	router.Handle(method, "/dav/*path", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("vaults"))
+	})
+
+	req := httptest.NewRequest(method, target, nil)
+	if configure != nil {
+		configure(req)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func davToken(scope string) *fakeMiddlewareTokenService {
+	return &fakeMiddlewareTokenService{activeToken: &domain.AuthToken{
+		ID:          2,
+		UID:         1,
+		TokenString: "nonce-ok",
+		Status:      1,
+		Scope:       scope,
+		Vaults:      "Work",
+		IssueType:   2,
+		ExpiredAt:   time.Now().Add(time.Hour),
+	}}
+}
+
+// TestDAVAuthWithConfig_AcceptsBasicPassword verifies the token is accepted as the HTTP Basic password.
+// TestDAVAuthWithConfig_AcceptsBasicPassword 验证 Token 可作为 HTTP Basic 密码传入。
+func TestDAVAuthWithConfig_AcceptsBasicPassword(t *testing.T) {
+	token := newMiddlewareJWT(t, "test-secret", "nonce-ok")
+	w := runDAVAuthMiddleware(t, davToken("p:dav c:WebDAV f:note_rw"), "PROPFIND", "/dav/Work/a.md", func(req *http.Request) {
+		req.SetBasicAuth("anyone", token)
+	})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Work", w.Body.String())
+}
+
+// TestDAVAuthWithConfig_ChallengesMissingToken verifies a request without credentials gets a Basic challenge.
+// TestDAVAuthWithConfig_ChallengesMissingToken 验证未携带凭据的请求收到 Basic 质询。
+func TestDAVAuthWithConfig_ChallengesMissingToken(t *testing.T) {
+	w := runDAVAuthMiddleware(t, davToken(""), "PROPFIND", "/dav/", nil)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), `Basic realm="FNS"`)
+}
+
+// TestDAVAuthWithConfig_EnforcesScope verifies the dav protocol and note/file functions are checked.
+// TestDAVAuthWithConfig_EnforcesScope 验证 dav 协议及笔记/附件功能权限被校验。
+func TestDAVAuthWithConfig_EnforcesScope(t *testing.T) {
+	token := newMiddlewareJWT(t, "test-secret", "nonce-ok")
+	basic := func(req *http.Request) { req.SetBasicAuth("", token) }
+
+	w := runDAVAuthMiddleware(t, davToken("p:rest f:note_rw"), "PROPFIND", "/dav/Work/a.md", basic)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = runDAVAuthMiddleware(t, davToken("p:dav f:note_r"), http.MethodPut, "/dav/Work/a.md", basic)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = runDAVAuthMiddleware(t, davToken("p:dav f:note_rw"), http.MethodPut, "/dav/Work/logo.png", basic)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = runDAVAuthMiddleware(t, davToken("p:dav f:note_rw,file_rw"), http.MethodPut, "/dav/Work/logo.png", basic)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
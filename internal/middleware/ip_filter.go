@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/ipfilter"
+	"go.uber.org/zap"
+)
+
+// DefaultHoneypotPaths commonly probed admin paths used when none are configured
+// DefaultHoneypotPaths 未配置时使用的常见扫描路径
+var DefaultHoneypotPaths = []string{
+	"/wp-admin",
+	"/wp-login.php",
+	"/phpmyadmin",
+	"/pma",
+	"/administrator",
+	"/admin.php",
+	"/.env",
+	"/.git/config",
+	"/server-status",
+	"/actuator/env",
+}
+
+// IPFilter creates middleware that rejects denied or banned client IPs
+// IPFilter 创建拒绝黑名单或被封禁客户端 IP 的中间件
+func IPFilter(f *ipfilter.Filter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if f != nil && f.IsBlocked(c.ClientIP()) {
+			response := app.NewResponse(c)
+			response.ToResponse(code.ErrorIPBlocked)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// Honeypot creates middleware serving decoy paths: a request to one of them (or below it)
+// is logged and the client IP banned. It answers like an unknown route so scanners learn nothing.
+// Honeypot 创建诱饵路径中间件：访问诱饵路径（或其子路径）时记录日志并封禁客户端 IP。
+// 其响应与未知路由一致，避免向扫描器泄露信息。
+func Honeypot(f *ipfilter.Filter, paths []string, banDuration time.Duration, logger *zap.Logger) gin.HandlerFunc {
+	if len(paths) == 0 {
+		paths = DefaultHoneypotPaths
+	}
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if !matchHoneypotPath(paths, path) {
+			c.Next()
+			return
+		}
+
+		ip := c.ClientIP()
+		banned := false
+		if f != nil && banDuration > 0 {
+			banned = f.Ban(ip, banDuration, "honeypot "+c.Request.Method+" "+path)
+		}
+		logger.Warn("honeypot triggered",
+			zap.String("ip", ip),
+			zap.String("method", c.Request.Method),
+			zap.String("path", path),
+			zap.String("userAgent", c.Request.UserAgent()),
+			zap.Bool("banned", banned),
+		)
+		response := app.NewResponse(c)
+		response.ToResponse(code.ErrorNotFoundAPI)
+		c.Abort()
+	}
+}
+
+// matchHoneypotPath reports whether path equals or lies below one of the decoy paths
+// matchHoneypotPath 判断路径是否等于某个诱饵路径或位于其下
+func matchHoneypotPath(paths []string, path string) bool {
+	for _, p := range paths {
+		p = strings.TrimRight(p, "/")
+		if p == "" {
+			continue
+		}
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/ipfilter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// doSecurityRequest sends a request from remoteAddr and returns the recorder and parsed body.
+// doSecurityRequest 以 remoteAddr 发送请求，返回记录器与解析后的响应体
+func doSecurityRequest(t *testing.T, router *gin.Engine, path, remoteAddr string) (*httptest.ResponseRecorder, app.Res) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var res app.Res
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return w, res
+}
+
+// TestHoneypot_BansProbingIP verifies a decoy hit bans the IP for all later requests.
+// TestHoneypot_BansProbingIP 验证访问诱饵路径后该 IP 后续请求均被拒绝
+func TestHoneypot_BansProbingIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	f, err := ipfilter.New(nil)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(IPFilter(f))
+	router.Use(Honeypot(f, nil, time.Hour, zap.NewNop()))
+	router.GET("/api/version", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"code": code.Success.Code(), "status": true})
+	})
+
+	_, res := doSecurityRequest(t, router, "/api/version", "203.0.113.5:1234")
+	assert.Equal(t, code.Success.Code(), res.Code)
+
+	_, res = doSecurityRequest(t, router, "/wp-admin/install.php", "203.0.113.5:1234")
+	assert.Equal(t, code.ErrorNotFoundAPI.Code(), res.Code)
+
+	_, res = doSecurityRequest(t, router, "/api/version", "203.0.113.5:1234")
+	assert.Equal(t, code.ErrorIPBlocked.Code(), res.Code)
+
+	// Other clients are unaffected
+	// 其他客户端不受影响
+	_, res = doSecurityRequest(t, router, "/api/version", "198.51.100.7:1234")
+	assert.Equal(t, code.Success.Code(), res.Code)
+}
+
+// TestSecurityHeaders verifies headers are set and HSTS is only sent over HTTPS.
+// TestSecurityHeaders 验证安全响应头已设置且 HSTS 仅在 HTTPS 下发送
+func TestSecurityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	maxAge := 600
+	router := gin.New()
+	router.Use(SecurityHeaders(config.SecurityHeadersConfig{
+		ContentSecurityPolicy: "default-src 'self'",
+		FrameOptions:          "DENY",
+		HSTSMaxAge:            &maxAge,
+	}))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"code": code.Success.Code(), "status": true})
+	})
+
+	w, _ := doSecurityRequest(t, router, "/test", "192.0.2.1:1234")
+	assert.Equal(t, "default-src 'self'", w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"))
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "max-age=600; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+}
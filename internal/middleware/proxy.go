@@ -7,14 +7,23 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// TrustedProxyList returns the configured trusted proxies, loopback addresses only when none are configured.
+// The engine trusts the same list, so c.ClientIP only follows forwarding headers sent by these proxies
+// TrustedProxyList 返回配置的可信代理，未配置时只信任本地回环地址。
+// 引擎信任同一列表，因此 c.ClientIP 只采用这些代理发送的转发头
+func TrustedProxyList(trustedProxies []string) []string {
+	if len(trustedProxies) == 0 {
+		return []string{"127.0.0.1", "::1"}
+	}
+	return trustedProxies
+}
+
 // Proxy handles proxy headers and restores original request information based on trusted proxies
 // Proxy 根据可信代理处理代理头部并恢复原始请求信息
 func Proxy(trustedProxies []string) gin.HandlerFunc {
 	// If trustedProxies is empty, default to loopback addresses only
 	// 如果 trustedProxies 为空，默认只信任本地回环地址
-	if len(trustedProxies) == 0 {
-		trustedProxies = []string{"127.0.0.1", "::1"}
-	}
+	trustedProxies = TrustedProxyList(trustedProxies)
 
 	var trustedIPs []net.IP
 	var trustedSubnets []*net.IPNet
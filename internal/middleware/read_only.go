@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/readonly"
+)
+
+// readOnlyMethods request methods that never change data
+// readOnlyMethods 不会修改数据的请求方法
+var readOnlyMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	"PROPFIND":         true,
+}
+
+// ReadOnly creates middleware refusing changing requests while the server is in read-only maintenance mode.
+// Paths in exempt stay usable: an entry ending with "/" matches every path below it, others match exactly.
+// ReadOnly 创建在只读维护模式下拒绝修改类请求的中间件。
+// exempt 中的路径仍可使用：以 "/" 结尾的条目匹配其下所有路径，其余条目精确匹配。
+func ReadOnly(mode *readonly.Mode, exempt ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !mode.Enabled() || readOnlyMethods[c.Request.Method] || matchReadOnlyExempt(exempt, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+		response := app.NewResponse(c)
+		response.ToResponse(code.ErrorServerReadOnly)
+		c.Abort()
+	}
+}
+
+// DAVReadOnly creates middleware refusing changing WebDAV requests while the server is in read-only
+// maintenance mode, with 503 and Retry-After since WebDAV clients only understand HTTP status codes
+// DAVReadOnly 创建在只读维护模式下拒绝修改类 WebDAV 请求的中间件；WebDAV 客户端只识别 HTTP 状态码，因此返回 503 与 Retry-After
+func DAVReadOnly(mode *readonly.Mode) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !mode.Enabled() || readOnlyMethods[c.Request.Method] {
+			c.Next()
+			return
+		}
+		c.Header("Retry-After", "60")
+		c.AbortWithStatus(http.StatusServiceUnavailable)
+	}
+}
+
+func matchReadOnlyExempt(exempt []string, path string) bool {
+	for _, p := range exempt {
+		if path == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(path, p)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/readonly"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadOnly verifies changing requests get the maintenance code while reads and exempt paths go through.
+// TestReadOnly 验证只读模式下修改类请求返回维护错误码，读取请求与豁免路径正常通过
+func TestReadOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mode := readonly.New()
+
+	router := gin.New()
+	router.Use(ReadOnly(mode, "/api/admin/", "/api/user/login"))
+	ok := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"code": code.Success.Code(), "status": true})
+	}
+	router.GET("/api/note", ok)
+	router.POST("/api/note", ok)
+	router.POST("/api/user/login", ok)
+	router.POST("/api/admin/read-only", ok)
+
+	do := func(method, path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		var res app.Res
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		return res.Code
+	}
+
+	assert.Equal(t, code.Success.Code(), do(http.MethodPost, "/api/note"))
+
+	mode.Set(true, "")
+	assert.Equal(t, code.ErrorServerReadOnly.Code(), do(http.MethodPost, "/api/note"))
+	assert.Equal(t, code.Success.Code(), do(http.MethodGet, "/api/note"))
+	assert.Equal(t, code.Success.Code(), do(http.MethodPost, "/api/user/login"))
+	assert.Equal(t, code.Success.Code(), do(http.MethodPost, "/api/admin/read-only"))
+
+	davRouter := gin.New()
+	davRouter.Use(DAVReadOnly(mode))
+	davRouter.Handle(http.MethodPut, "/dav/*path", ok)
+	w := httptest.NewRecorder()
+	davRouter.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/dav/a.md", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+}
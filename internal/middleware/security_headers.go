@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+)
+
+// SecurityHeaders creates middleware that sets strict security response headers
+// SecurityHeaders 创建设置严格安全响应头的中间件
+func SecurityHeaders(cfg config.SecurityHeadersConfig) gin.HandlerFunc {
+	hsts := ""
+	if cfg.HSTSMaxAge != nil && *cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(*cfg.HSTSMaxAge) + "; includeSubDomains"
+	}
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		if cfg.FrameOptions != "" {
+			h.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		if cfg.ReferrerPolicy != "" {
+			h.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		if cfg.ContentSecurityPolicy != "" {
+			h.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+		// Browsers ignore HSTS over plain HTTP, only send it on HTTPS requests
+		// 浏览器会忽略 HTTP 下的 HSTS，仅在 HTTPS 请求时发送
+		if hsts != "" && isHTTPS(c) {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+		c.Next()
+	}
+}
+
+// isHTTPS reports whether the request arrived over TLS, directly or via a proxy
+// isHTTPS 判断请求是否通过 TLS 到达（直连或经代理）
+func isHTTPS(c *gin.Context) bool {
+	if c.Request.TLS != nil {
+		return true
+	}
+	return strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")
+}
@@ -5,9 +5,17 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/tracer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -28,6 +36,10 @@ const (
 // 2. 将 Trace ID 注入到 gin.Context 和 request.Context
 // 3. Return Trace ID in the response header
 // 3. 在响应头中返回 Trace ID
+// 4. Start an OpenTelemetry server span continuing an incoming traceparent, without a Trace ID
+// header its trace ID becomes the Trace ID so logs and exported spans match
+// 4. 开始一个延续传入 traceparent 的 OpenTelemetry 服务端链路，未传入 Trace ID 请求头时
+// 使用其 trace ID 作为 Trace ID，使日志与导出的链路一致
 func TraceMiddlewareWithConfig(enabled bool, headerName string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Check if tracing is enabled
@@ -43,9 +55,33 @@ func TraceMiddlewareWithConfig(enabled bool, headerName string) gin.HandlerFunc
 			headerName = DefaultTraceIDHeader
 		}
 
+		// Start the server span, WebSocket connections live too long to be one span
+		// 开始服务端链路，WebSocket 连接存活时间过长，不作为单个链路
+		ctx := c.Request.Context()
+		var span trace.Span
+		if !c.IsWebsocket() {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(c.Request.Header))
+			route := c.FullPath()
+			if route == "" {
+				route = "unmatched"
+			}
+			ctx, span = tracer.Tracer().Start(ctx, c.Request.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.request.method", c.Request.Method),
+					attribute.String("http.route", route),
+					attribute.String("client.address", c.ClientIP()),
+				),
+			)
+			defer span.End()
+		}
+
 		// Try to get Trace ID from request header
 		// 尝试从请求头获取 Trace ID
 		traceID := c.GetHeader(headerName)
+		if traceID == "" {
+			traceID = tracer.TraceID(ctx)
+		}
 		if traceID == "" {
 			// Generate new Trace ID
 			// 生成新的 Trace ID
@@ -58,7 +94,7 @@ func TraceMiddlewareWithConfig(enabled bool, headerName string) gin.HandlerFunc
 
 		// Inject into request.Context
 		// 注入到 request.Context
-		ctx := context.WithValue(c.Request.Context(), TraceIDKey, traceID)
+		ctx = context.WithValue(ctx, TraceIDKey, traceID)
 		c.Request = c.Request.WithContext(ctx)
 
 		// Add to response header
@@ -66,6 +102,19 @@ func TraceMiddlewareWithConfig(enabled bool, headerName string) gin.HandlerFunc
 		c.Header(headerName, traceID)
 
 		c.Next()
+
+		if span != nil {
+			status := c.Writer.Status()
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			if uid := app.GetUID(c); uid > 0 {
+				span.SetAttributes(tracer.UID(uid))
+			}
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			} else if len(c.Errors) > 0 {
+				span.SetStatus(codes.Error, c.Errors.String())
+			}
+		}
 	}
 }
 
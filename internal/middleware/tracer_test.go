@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/tracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestTraceMiddleware_ServerSpan verifies requests get a server span whose trace ID is the Trace ID,
+// and that spans started below the handler become its children.
+// TestTraceMiddleware_ServerSpan 验证请求获得服务端链路且其 trace ID 即 Trace ID，处理器内开始的链路成为其子链路
+func TestTraceMiddleware_ServerSpan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	router := gin.New()
+	router.Use(TraceMiddlewareWithConfig(true, DefaultTraceIDHeader))
+	router.GET("/api/notes", func(c *gin.Context) {
+		c.Set("user_token", &app.UserEntity{UID: 7})
+		_, span := tracer.Start(c.Request.Context(), "NoteService.List")
+		span.End()
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/notes?vault=a", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+	assert.Equal(t, "GET /api/notes", server.Name)
+	assert.Equal(t, server.SpanContext.TraceID().String(), w.Header().Get(DefaultTraceIDHeader))
+	assert.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID())
+	assert.Contains(t, server.Attributes, attribute.Int("http.response.status_code", http.StatusOK))
+	assert.Contains(t, server.Attributes, tracer.UID(7))
+
+	// An incoming traceparent is continued, an explicit Trace ID header is kept
+	// 延续传入的 traceparent，显式传入的 Trace ID 请求头保持不变
+	exporter.Reset()
+	req := httptest.NewRequest(http.MethodGet, "/api/notes", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set(DefaultTraceIDHeader, "client-trace")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	spans = exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[1].SpanContext.TraceID().String())
+	assert.Equal(t, "client-trace", w.Header().Get(DefaultTraceIDHeader))
+}
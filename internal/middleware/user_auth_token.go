@@ -15,6 +15,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// readOnlyPosts POST endpoints that only read data and are checked against the read scope
+// readOnlyPosts 仅读取数据的 POST 接口，按读权限校验
+var readOnlyPosts = map[string]bool{
+	"/api/note/lint":   true, // POST only because the checked text may be long // 仅因待检查文本可能很长而使用 POST
+	"/api/note/render": true, // POST carries unsaved content to preview // POST 用于携带待预览的未保存内容
+	"/api/graphql":     true, // Only queries are served, POST carries the query document // 仅支持查询，POST 用于携带查询文档
+}
+
 func UserAuthTokenWithConfig(secretKey string, tokenService service.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		response := app.NewResponse(c)
@@ -40,6 +48,11 @@ func ExtractUserAuthToken(c *gin.Context) string {
 	if authHeader != "" && strings.HasPrefix(authHeader, "Bearer ") {
 		return strings.TrimPrefix(authHeader, "Bearer ")
 	}
+	// WebDAV clients only speak HTTP Basic, the token is sent as the password
+	// WebDAV 客户端仅支持 HTTP Basic 认证，Token 作为密码传入
+	if _, password, ok := c.Request.BasicAuth(); ok && password != "" {
+		return password
+	}
 
 	if authHeader := c.GetHeader("Token"); authHeader != "" {
 		return authHeader
@@ -106,17 +119,25 @@ func AuthenticateUserToken(c *gin.Context, secretKey string, tokenService servic
 	method := c.Request.Method
 	var function string
 
+	// A TOTP setup token only reaches the enrollment routes, the admin signs in again once enrolled
+	// TOTP 设置令牌只能访问设置接口，管理员完成设置后需重新登录
+	if app.IsTOTPSetupScope(dbToken.Scope) && !isTOTPSetupPath(path) {
+		return nil, "", "", nil, code.ErrorUserTOTPNotSetup.WithDetails("Set up two-factor authentication and sign in again to continue")
+	}
+
 	var resource string
-	if strings.HasPrefix(path, "/api/note") || strings.HasPrefix(path, "/api/folder") {
+	if strings.HasPrefix(path, "/api/note") || strings.HasPrefix(path, "/api/folder") || path == "/api/vault/graph" || strings.HasPrefix(path, "/api/graphql") {
 		resource = "note"
 	} else if strings.HasPrefix(path, "/api/file") || strings.HasPrefix(path, "/api/storage") {
 		resource = "file"
 	} else if strings.HasPrefix(path, "/api/setting") || strings.HasPrefix(path, "/api/admin/config") {
 		resource = "config"
+	} else if IsDAVPath(path) {
+		resource = davResource(path)
 	}
 
 	if resource != "" {
-		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+		if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions || method == "PROPFIND" || readOnlyPosts[path] {
 			function = resource + "_r"
 		} else {
 			function = resource + "_w"
@@ -126,6 +147,8 @@ func AuthenticateUserToken(c *gin.Context, secretKey string, tokenService servic
 	protocol := "rest"
 	if strings.HasPrefix(path, "/api/mcp") {
 		protocol = "mcp"
+	} else if IsDAVPath(path) {
+		protocol = "dav"
 	}
 
 	if path != "/api/health" && !app.VerifyPermissions(dbToken.Scope, protocol, reqClientType, function) {
@@ -174,6 +197,12 @@ func AuthenticateUserToken(c *gin.Context, secretKey string, tokenService servic
 	return user, dbToken.Scope, dbToken.Vaults, dbToken, nil
 }
 
+// isTOTPSetupPath checks if the path may be reached with a TOTP setup token
+// isTOTPSetupPath 检查该路径是否允许使用 TOTP 设置令牌访问
+func isTOTPSetupPath(path string) bool {
+	return path == "/api/health" || path == "/api/user/info" || path == "/api/user/totp" || strings.HasPrefix(path, "/api/user/totp/")
+}
+
 func isHeaderlessLoginTokenResourceRead(c *gin.Context) bool {
 	return c.Request.URL.Path == "/api/file" &&
 		(c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead)
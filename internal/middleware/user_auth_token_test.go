@@ -12,8 +12,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
 	"github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/revocation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -31,6 +33,10 @@ func (s *fakeMiddlewareTokenService) CreateForLogin(ctx context.Context, uid int
 	return nil, "", errors.New("not implemented")
 }
 
+func (s *fakeMiddlewareTokenService) CreateForTOTPSetup(ctx context.Context, uid int64, clientType, ip, userAgent string) (*domain.AuthToken, string, error) {
+	return nil, "", errors.New("not implemented")
+}
+
 func (s *fakeMiddlewareTokenService) ListByUser(ctx context.Context, uid int64) ([]*dto.TokenResponse, error) {
 	return nil, errors.New("not implemented")
 }
@@ -67,6 +73,14 @@ func (s *fakeMiddlewareTokenService) UpdateLastUsedAt(ctx context.Context, token
 	return errors.New("not implemented")
 }
 
+func (s *fakeMiddlewareTokenService) RevokeAll(ctx context.Context, uid int64) error {
+	return nil
+}
+
+func (s *fakeMiddlewareTokenService) SetDenylist(list revocation.List) {}
+
+func (s *fakeMiddlewareTokenService) SetConfig(config service.TokenServiceConfig) {}
+
 func (s *fakeMiddlewareTokenService) SetSyncHandler(handler func(uid int64, tokenID int64, scope string, kick bool)) {
 }
 
@@ -74,6 +88,10 @@ func (s *fakeMiddlewareTokenService) GetRecentClients(ctx context.Context, uid i
 	return nil, nil
 }
 
+func (s *fakeMiddlewareTokenService) CleanExpired(ctx context.Context, uid int64, issueType int) error {
+	return nil
+}
+
 func newMiddlewareJWT(t *testing.T, secretKey, nonce string) string {
 	t.Helper()
 	tokenManager := app.NewTokenManager(app.TokenConfig{
@@ -107,6 +125,9 @@ func runUserAuthMiddlewareWithRequest(t *testing.T, tokenService *fakeMiddleware
 	router.POST("/api/file", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"code": code.Success.Code(), "status": true})
 	})
+	router.GET("/api/user/totp", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"code": code.Success.Code(), "status": true})
+	})
 
 	req := httptest.NewRequest(method, target, nil)
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -225,6 +246,31 @@ func TestUserAuthTokenWithConfig_RejectsHeaderlessLoginTokenWrite(t *testing.T)
 	assert.Equal(t, code.ErrorAuthTokenClientRestricted.Code(), res.Code)
 }
 
+func TestUserAuthTokenWithConfig_LimitsTOTPSetupToken(t *testing.T) {
+	token := newMiddlewareJWT(t, "test-secret", "nonce-ok")
+	tokenService := &fakeMiddlewareTokenService{activeToken: &domain.AuthToken{
+		ID:          2,
+		UID:         1,
+		TokenString: "nonce-ok",
+		Status:      1,
+		Scope:       "p:rest c:WebGui f:" + app.ScopeFunctionTOTPSetup,
+		ClientType:  "WebGui",
+		IssueType:   1,
+		ExpiredAt:   time.Now().Add(time.Hour),
+	}}
+	webGUI := func(req *http.Request) {
+		req.Header.Set("x-client", "WebGui")
+	}
+
+	res := runUserAuthMiddlewareWithRequest(t, tokenService, token, http.MethodGet, "/api/user/totp", webGUI)
+	assert.Equal(t, code.Success.Code(), res.Code)
+
+	for _, target := range []string{"/api/note/list?path=test.md", "/api/file?vault=main&path=image.png"} {
+		res = runUserAuthMiddlewareWithRequest(t, tokenService, token, http.MethodGet, target, webGUI)
+		assert.Equal(t, code.ErrorUserTOTPNotSetup.Code(), res.Code, target)
+	}
+}
+
 func TestUserAuthTokenWithConfig_RejectsManualTokenWithoutClientHeader(t *testing.T) {
 	token := newMiddlewareJWT(t, "test-secret", "nonce-ok")
 	res := runUserAuthMiddlewareWithRequest(t, &fakeMiddlewareTokenService{activeToken: &domain.AuthToken{
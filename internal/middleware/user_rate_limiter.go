@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/limiter"
+	"go.uber.org/zap"
+)
+
+// UserRateLimiter limits requests per authenticated uid, falling back to the client IP
+// when no user is bound to the request yet
+// UserRateLimiter 按已认证用户 uid 限流，请求尚未绑定用户时按客户端 IP 限流
+func UserRateLimiter(l *limiter.KeyedLimiter, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l == nil {
+			c.Next()
+			return
+		}
+
+		key := "ip:" + c.ClientIP()
+		if uid := app.GetUID(c); uid != 0 {
+			key = "uid:" + strconv.FormatInt(uid, 10)
+		}
+
+		ok, first := l.Take(key)
+		if !ok {
+			if first && logger != nil {
+				logger.Warn("api rate limit exceeded",
+					zap.String("key", key),
+					zap.String("method", c.Request.Method),
+					zap.String("path", c.Request.URL.Path),
+					zap.String("traceId", GetTraceID(c.Request.Context())))
+			}
+			app.NewResponse(c).ToResponse(code.ErrorTooManyRequests)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/limiter"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// TestUserRateLimiter_PerUIDAndIP verifies buckets are keyed by uid when authenticated and by IP otherwise.
+// TestUserRateLimiter_PerUIDAndIP 验证已认证请求按 uid 限流，否则按 IP 限流
+func TestUserRateLimiter_PerUIDAndIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	l := limiter.NewKeyedLimiter(0.001, 2)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if c.Query("uid") == "1" {
+			c.Set("user_token", &app.UserEntity{UID: 1})
+		}
+	})
+	router.Use(UserRateLimiter(l, zap.NewNop()))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"code": code.Success.Code(), "status": true})
+	})
+
+	for i := 0; i < 2; i++ {
+		_, res := doSecurityRequest(t, router, "/test?uid=1", "203.0.113.5:1234")
+		assert.Equal(t, code.Success.Code(), res.Code)
+	}
+	_, res := doSecurityRequest(t, router, "/test?uid=1", "203.0.113.5:1234")
+	assert.Equal(t, code.ErrorTooManyRequests.Code(), res.Code)
+
+	// Same IP without a user has its own bucket
+	// 同一 IP 的未认证请求使用独立的令牌桶
+	_, res = doSecurityRequest(t, router, "/test", "203.0.113.5:1234")
+	assert.Equal(t, code.Success.Code(), res.Code)
+}
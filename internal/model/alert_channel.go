@@ -0,0 +1,25 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const TableNameAlertChannel = "alert_channel"
+
+// AlertChannel stores a user's alert destination.
+type AlertChannel struct {
+	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID       int64      `gorm:"column:uid;not null;default:0" json:"uid" form:"uid"`
+	Name      string     `gorm:"column:name;default:''" json:"name" form:"name"`
+	Type      string     `gorm:"column:type;not null;default:''" json:"type" form:"type"`
+	To        string     `gorm:"column:recipients;type:TEXT;default:''" json:"to" form:"to"`
+	URL       string     `gorm:"column:url;type:TEXT;default:''" json:"url" form:"url"`
+	Token     string     `gorm:"column:token;default:''" json:"token" form:"token"`
+	ChatID    string     `gorm:"column:chat_id;default:''" json:"chatId" form:"chatId"`
+	Priority  int64      `gorm:"column:priority;not null;default:0" json:"priority" form:"priority"`
+	IsEnabled int64      `gorm:"column:is_enabled;not null;default:0" json:"isEnabled" form:"isEnabled"`
+	CreatedAt timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+	UpdatedAt timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
+}
+
+func (*AlertChannel) TableName() string {
+	return TableNameAlertChannel
+}
@@ -0,0 +1,22 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const TableNameAuditLog = "audit_log"
+
+// AuditLog stores one audited action.
+type AuditLog struct {
+	ID         int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID        int64      `gorm:"column:uid;not null;index:idx_audit_log_uid;default:0" json:"uid" form:"uid"`
+	Action     string     `gorm:"column:action;not null;index:idx_audit_log_action;default:''" json:"action" form:"action"`
+	Target     string     `gorm:"column:target;type:TEXT;default:''" json:"target" form:"target"`
+	Detail     string     `gorm:"column:detail;type:TEXT;default:''" json:"detail" form:"detail"`
+	IP         string     `gorm:"column:ip;default:''" json:"ip" form:"ip"`
+	ClientType string     `gorm:"column:client_type;default:''" json:"clientType" form:"clientType"`
+	ClientName string     `gorm:"column:client_name;default:''" json:"clientName" form:"clientName"`
+	CreatedAt  timex.Time `gorm:"column:created_at;index:idx_audit_log_created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+}
+
+func (*AuditLog) TableName() string {
+	return TableNameAuditLog
+}
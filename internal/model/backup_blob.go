@@ -0,0 +1,21 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const TableNameBackupBlob = "backup_blob"
+
+// BackupBlob records a content-addressed chunk uploaded by a dedupe backup.
+type BackupBlob struct {
+	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID       int64      `gorm:"column:uid;not null;default:0" json:"uid" form:"uid"`
+	ConfigID  int64      `gorm:"column:config_id;not null;uniqueIndex:idx_backup_blob_target_hash,priority:1;index:idx_backup_blob_last_ref,priority:1;default:0" json:"configId" form:"configId"`
+	StorageID int64      `gorm:"column:storage_id;not null;uniqueIndex:idx_backup_blob_target_hash,priority:2;index:idx_backup_blob_last_ref,priority:2;default:0" json:"storageId" form:"storageId"`
+	Hash      string     `gorm:"column:hash;not null;uniqueIndex:idx_backup_blob_target_hash,priority:3;default:''" json:"hash" form:"hash"`
+	Size      int64      `gorm:"column:size;not null;default:0" json:"size" form:"size"`
+	LastRefAt timex.Time `gorm:"column:last_ref_at;index:idx_backup_blob_last_ref,priority:3;default:NULL" json:"lastRefAt" form:"lastRefAt"`
+	CreatedAt timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+}
+
+func (*BackupBlob) TableName() string {
+	return TableNameBackupBlob
+}
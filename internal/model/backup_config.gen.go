@@ -32,6 +32,9 @@ type BackupConfig struct {
 	LastMessage   string     `gorm:"column:last_message;type:TEXT;default:''" json:"lastMessage" form:"lastMessage"`
 	PasswordMode  int64      `gorm:"column:password_mode;default:0" json:"passwordMode" form:"passwordMode"`
 	PasswordValue string     `gorm:"column:password_value;type:TEXT;default:''" json:"passwordValue" form:"passwordValue"`
+	WebhookURL    string     `gorm:"column:webhook_url;type:TEXT;default:''" json:"webhookUrl" form:"webhookUrl"`
+	WebhookSecret string     `gorm:"column:webhook_secret;type:TEXT;default:''" json:"webhookSecret" form:"webhookSecret"`
+	PingURL       string     `gorm:"column:ping_url;type:TEXT;default:''" json:"pingUrl" form:"pingUrl"`
 	CreatedAt     timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
 	UpdatedAt     timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
 }
@@ -0,0 +1,26 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const TableNameDevice = "device"
+
+// Device stores a client device that has connected over WebSocket.
+type Device struct {
+	ID            int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID           int64      `gorm:"column:uid;not null;uniqueIndex:idx_device_uid_key,priority:1;default:0" json:"uid" form:"uid"`
+	DeviceKey     string     `gorm:"column:device_key;not null;uniqueIndex:idx_device_uid_key,priority:2;default:''" json:"deviceKey" form:"deviceKey"`
+	TokenID       int64      `gorm:"column:token_id;not null;default:0" json:"tokenId" form:"tokenId"`
+	ClientName    string     `gorm:"column:client_name;default:''" json:"clientName" form:"clientName"`
+	ClientType    string     `gorm:"column:client_type;default:''" json:"clientType" form:"clientType"`
+	ClientVersion string     `gorm:"column:client_version;default:''" json:"clientVersion" form:"clientVersion"`
+	Platform      string     `gorm:"column:platform;default:''" json:"platform" form:"platform"`
+	IP            string     `gorm:"column:ip;default:''" json:"ip" form:"ip"`
+	UserAgent     string     `gorm:"column:user_agent;type:TEXT;default:''" json:"userAgent" form:"userAgent"`
+	LastSeenAt    timex.Time `gorm:"column:last_seen_at;default:NULL" json:"lastSeenAt" form:"lastSeenAt"`
+	CreatedAt     timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+	UpdatedAt     timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
+}
+
+func (*Device) TableName() string {
+	return TableNameDevice
+}
@@ -17,6 +17,7 @@ type Folder struct {
 	PathHash         string     `gorm:"column:path_hash;type:varchar(1024);index:idx_folder_vault_id_path_hash,priority:2;default:''" json:"pathHash" form:"pathHash"`
 	Level            int64      `gorm:"column:level;index:idx_folder_vault_id_level_path,priority:2;default:0" json:"level" form:"level"`
 	FID              int64      `gorm:"column:fid;index:idx_folder_vault_id_fid_path,priority:2;default:0" json:"fid" form:"fid"`
+	Archived         int64      `gorm:"column:archived;default:0" json:"archived" form:"archived"`
 	Ctime            int64      `gorm:"column:ctime;default:0" json:"ctime" form:"ctime"`
 	Mtime            int64      `gorm:"column:mtime;not null;default:0" json:"mtime" form:"mtime"`
 	UpdatedTimestamp int64      `gorm:"column:updated_timestamp;not null;index:idx_folder_vault_id_updated_timestamp,priority:2;default:0" json:"updatedTimestamp" form:"updatedTimestamp"`
@@ -29,6 +29,7 @@ type GitSyncConfig struct {
 	LastMessage     string     `gorm:"column:last_message;type:TEXT;default:''" json:"lastMessage" form:"lastMessage"`
 	IncludeConfig   int64      `gorm:"column:include_config;default:0" json:"includeConfig" form:"includeConfig"`
 	ConfigSyncRules string     `gorm:"column:config_sync_rules;type:TEXT;default:''" json:"configSyncRules" form:"configSyncRules"`
+	PingURL         string     `gorm:"column:ping_url;type:TEXT;default:''" json:"pingUrl" form:"pingUrl"`
 	CreatedAt       timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
 	UpdatedAt       timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
 }
@@ -64,5 +64,5 @@ func AutoMigrate(db *gorm.DB, key string) error {
 	case "Vault":
 		return db.AutoMigrate(Vault{})
 	}
-	return nil
+	return autoMigrateHandWritten(db, key)
 }
\ No newline at end of file
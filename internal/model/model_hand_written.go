@@ -0,0 +1,44 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+// handWrittenModels models written by hand rather than generated from the query package, by AutoMigrate key.
+// A key covers every table its repository uses
+// handWrittenModels 手写（非由 query 包生成）的模型，按 AutoMigrate 键索引，一个键包含其仓库使用的全部表
+var handWrittenModels = map[string][]any{
+	"AlertChannel":     {&AlertChannel{}},
+	"AuditLog":         {&AuditLog{}},
+	"BackupBlob":       {&BackupBlob{}},
+	"Device":           {&Device{}},
+	"NoteAccessLog":    {&NoteAccessLog{}},
+	"NoteAccessWatch":  {&NoteAccessWatch{}},
+	"NoteDailySetting": {&NoteDailySetting{}},
+	"NoteMeta":         {&NoteMeta{}},
+	"NotePolicy":       {&NotePolicy{}},
+	"NotePolicyRun":    {&NotePolicyRun{}},
+	"NoteProperty":     {&NoteProperty{}, &NotePropertyState{}},
+	"NotePublish":      {&NotePublish{}},
+	"NoteStat":         {&NoteStat{}, &NoteStatDay{}, &NoteStatState{}},
+	"NoteTemplate":     {&NoteTemplate{}},
+	"RetentionPolicy":  {&RetentionPolicy{}},
+	"UserInvite":       {&UserInvite{}},
+	"UserOIDCIdentity": {&UserOIDCIdentity{}},
+	"UserPending":      {&UserPending{}},
+	"UserSetting":      {&UserSetting{}},
+	"UserTOTP":         {&UserTOTP{}},
+	"Webhook":          {&Webhook{}},
+	"WebhookDelivery":  {&WebhookDelivery{}},
+}
+
+// autoMigrateHandWritten migrates the hand-written models of key, AutoMigrate falls back to it for the keys
+// it was not generated with
+// autoMigrateHandWritten 迁移 key 对应的手写模型，AutoMigrate 对生成时未包含的键回退到此函数
+func autoMigrateHandWritten(db *gorm.DB, key string) error {
+	models, ok := handWrittenModels[key]
+	if !ok {
+		return nil
+	}
+	return db.AutoMigrate(models...)
+}
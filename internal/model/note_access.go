@@ -0,0 +1,40 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const (
+	TableNameNoteAccessWatch = "note_access_watch"
+	TableNameNoteAccessLog   = "note_access_log"
+)
+
+// NoteAccessWatch marks a note whose reads are logged.
+type NoteAccessWatch struct {
+	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID       int64      `gorm:"column:uid;not null;default:0" json:"uid" form:"uid"`
+	VaultID   int64      `gorm:"column:vault_id;not null;default:0" json:"vaultId" form:"vaultId"`
+	NoteID    int64      `gorm:"column:note_id;uniqueIndex:idx_note_access_watch_note_id;not null;default:0" json:"noteId" form:"noteId"`
+	CreatedAt timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+}
+
+func (*NoteAccessWatch) TableName() string {
+	return TableNameNoteAccessWatch
+}
+
+// NoteAccessLog stores one read of a watched note.
+type NoteAccessLog struct {
+	ID         int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID        int64      `gorm:"column:uid;not null;default:0" json:"uid" form:"uid"`
+	VaultID    int64      `gorm:"column:vault_id;not null;default:0" json:"vaultId" form:"vaultId"`
+	NoteID     int64      `gorm:"column:note_id;not null;index:idx_note_access_log_note_id,priority:1;default:0" json:"noteId" form:"noteId"`
+	Path       string     `gorm:"column:path;type:TEXT;default:''" json:"path" form:"path"`
+	Channel    string     `gorm:"column:channel;not null;default:''" json:"channel" form:"channel"`
+	Actor      string     `gorm:"column:actor;default:''" json:"actor" form:"actor"`
+	ClientType string     `gorm:"column:client_type;default:''" json:"clientType" form:"clientType"`
+	IP         string     `gorm:"column:ip;default:''" json:"ip" form:"ip"`
+	Ua         string     `gorm:"column:ua;default:''" json:"ua" form:"ua"`
+	CreatedAt  timex.Time `gorm:"column:created_at;index:idx_note_access_log_note_id,priority:2;index:idx_note_access_log_created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+}
+
+func (*NoteAccessLog) TableName() string {
+	return TableNameNoteAccessLog
+}
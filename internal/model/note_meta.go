@@ -0,0 +1,21 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const TableNameNoteMeta = "note_meta"
+
+// NoteMeta stores one typed server-side attribute of a note, kept apart from its frontmatter.
+type NoteMeta struct {
+	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	NoteID    int64      `gorm:"column:note_id;not null;uniqueIndex:idx_note_meta_note_key,priority:1;default:0" json:"noteId" form:"noteId"`
+	VaultID   int64      `gorm:"column:vault_id;not null;index:idx_note_meta_vault_id;default:0" json:"vaultId" form:"vaultId"`
+	Key       string     `gorm:"column:meta_key;not null;uniqueIndex:idx_note_meta_note_key,priority:2;default:''" json:"key" form:"key"`
+	Type      string     `gorm:"column:value_type;not null;default:''" json:"type" form:"type"`
+	Value     string     `gorm:"column:value;type:text" json:"value" form:"value"`
+	CreatedAt timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+	UpdatedAt timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
+}
+
+func (*NoteMeta) TableName() string {
+	return TableNameNoteMeta
+}
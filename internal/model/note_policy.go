@@ -0,0 +1,49 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const (
+	TableNameNotePolicy    = "note_policy"
+	TableNameNotePolicyRun = "note_policy_run"
+)
+
+// NotePolicy stores a note lifecycle policy.
+type NotePolicy struct {
+	ID            int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID           int64      `gorm:"column:uid;not null;default:0" json:"uid" form:"uid"`
+	VaultID       int64      `gorm:"column:vault_id;not null;default:0" json:"vaultId" form:"vaultId"`
+	Name          string     `gorm:"column:name;default:''" json:"name" form:"name"`
+	Folder        string     `gorm:"column:folder;type:TEXT;default:''" json:"folder" form:"folder"`
+	Tag           string     `gorm:"column:tag;default:''" json:"tag" form:"tag"`
+	AgeBasis      string     `gorm:"column:age_basis;not null;default:''" json:"ageBasis" form:"ageBasis"`
+	OlderThanDays int64      `gorm:"column:older_than_days;not null;default:0" json:"olderThanDays" form:"olderThanDays"`
+	Action        string     `gorm:"column:action;not null;default:''" json:"action" form:"action"`
+	ArchiveFolder string     `gorm:"column:archive_folder;type:TEXT;default:''" json:"archiveFolder" form:"archiveFolder"`
+	IsEnabled     int64      `gorm:"column:is_enabled;not null;default:0" json:"isEnabled" form:"isEnabled"`
+	LastRunTime   timex.Time `gorm:"column:last_run_time;default:NULL" json:"lastRunTime" form:"lastRunTime"`
+	CreatedAt     timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+	UpdatedAt     timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
+}
+
+func (*NotePolicy) TableName() string {
+	return TableNameNotePolicy
+}
+
+// NotePolicyRun stores one run of a note lifecycle policy.
+type NotePolicyRun struct {
+	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID       int64      `gorm:"column:uid;not null;default:0" json:"uid" form:"uid"`
+	PolicyID  int64      `gorm:"column:policy_id;not null;index:idx_note_policy_run_policy_id;default:0" json:"policyId" form:"policyId"`
+	Trigger   string     `gorm:"column:trigger_type;not null;default:''" json:"trigger" form:"trigger"`
+	Matched   int64      `gorm:"column:matched;not null;default:0" json:"matched" form:"matched"`
+	Succeeded int64      `gorm:"column:succeeded;not null;default:0" json:"succeeded" form:"succeeded"`
+	Failed    int64      `gorm:"column:failed;not null;default:0" json:"failed" form:"failed"`
+	Paths     string     `gorm:"column:paths;type:TEXT;default:''" json:"paths" form:"paths"`
+	Message   string     `gorm:"column:message;type:TEXT;default:''" json:"message" form:"message"`
+	StartTime timex.Time `gorm:"column:start_time;default:NULL" json:"startTime" form:"startTime"`
+	EndTime   timex.Time `gorm:"column:end_time;default:NULL" json:"endTime" form:"endTime"`
+}
+
+func (*NotePolicyRun) TableName() string {
+	return TableNameNotePolicyRun
+}
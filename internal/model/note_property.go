@@ -0,0 +1,33 @@
+package model
+
+const (
+	TableNameNoteProperty      = "note_property"
+	TableNameNotePropertyState = "note_property_state"
+)
+
+// NoteProperty stores one indexed frontmatter property value of a note, one row per list element.
+type NoteProperty struct {
+	ID        int64    `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	NoteID    int64    `gorm:"column:note_id;not null;index:idx_note_property_note_id;default:0" json:"noteId" form:"noteId"`
+	VaultID   int64    `gorm:"column:vault_id;not null;index:idx_note_property_vault_key,priority:1;default:0" json:"vaultId" form:"vaultId"`
+	Key       string   `gorm:"column:prop_key;not null;index:idx_note_property_vault_key,priority:2;default:''" json:"key" form:"key"`
+	ValueType string   `gorm:"column:value_type;not null;default:''" json:"valueType" form:"valueType"`
+	Text      string   `gorm:"column:value_text;type:text" json:"text" form:"text"`
+	Number    *float64 `gorm:"column:value_number" json:"number" form:"number"`
+	Time      *int64   `gorm:"column:value_time" json:"time" form:"time"`
+	InList    bool     `gorm:"column:in_list;not null;default:false" json:"inList" form:"inList"`
+}
+
+func (*NoteProperty) TableName() string {
+	return TableNameNoteProperty
+}
+
+// NotePropertyState records up to which note update a vault's property index is current.
+type NotePropertyState struct {
+	VaultID      int64 `gorm:"column:vault_id;primaryKey;autoIncrement:false" json:"vaultId" form:"vaultId"`
+	IndexedUntil int64 `gorm:"column:indexed_until;not null;default:0" json:"indexedUntil" form:"indexedUntil"`
+}
+
+func (*NotePropertyState) TableName() string {
+	return TableNameNotePropertyState
+}
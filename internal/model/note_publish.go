@@ -0,0 +1,27 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const TableNameNotePublish = "note_publish"
+
+// NotePublish stores the publishing state of a note scheduled through its frontmatter.
+type NotePublish struct {
+	ID          int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID         int64      `gorm:"column:uid;not null;default:0" json:"uid" form:"uid"`
+	VaultID     int64      `gorm:"column:vault_id;not null;uniqueIndex:idx_note_publish_vault_path,priority:1;default:0" json:"vaultId" form:"vaultId"`
+	PathHash    string     `gorm:"column:path_hash;not null;uniqueIndex:idx_note_publish_vault_path,priority:2;default:''" json:"pathHash" form:"pathHash"`
+	Path        string     `gorm:"column:path;type:TEXT;default:''" json:"path" form:"path"`
+	NoteID      int64      `gorm:"column:note_id;not null;default:0" json:"noteId" form:"noteId"`
+	Status      string     `gorm:"column:status;not null;index:idx_note_publish_status;default:''" json:"status" form:"status"`
+	PublishAt   timex.Time `gorm:"column:publish_at;default:NULL" json:"publishAt" form:"publishAt"`
+	PublishedAt timex.Time `gorm:"column:published_at;default:NULL" json:"publishedAt" form:"publishedAt"`
+	ShareID     int64      `gorm:"column:share_id;not null;default:0" json:"shareId" form:"shareId"`
+	ShareToken  string     `gorm:"column:share_token;type:TEXT;default:''" json:"shareToken" form:"shareToken"`
+	Message     string     `gorm:"column:message;type:TEXT;default:''" json:"message" form:"message"`
+	CreatedAt   timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+	UpdatedAt   timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
+}
+
+func (*NotePublish) TableName() string {
+	return TableNameNotePublish
+}
@@ -0,0 +1,43 @@
+package model
+
+const (
+	TableNameNoteStat      = "note_stat"
+	TableNameNoteStatDay   = "note_stat_day"
+	TableNameNoteStatState = "note_stat_state"
+)
+
+// NoteStat stores the word and character counts of a note.
+type NoteStat struct {
+	NoteID  int64  `gorm:"column:note_id;primaryKey;autoIncrement:false" json:"noteId" form:"noteId"`
+	VaultID int64  `gorm:"column:vault_id;not null;index:idx_note_stat_vault_id;default:0" json:"vaultId" form:"vaultId"`
+	Path    string `gorm:"column:path;type:varchar(1024);not null;default:''" json:"path" form:"path"`
+	Words   int64  `gorm:"column:words;not null;default:0" json:"words" form:"words"`
+	Chars   int64  `gorm:"column:chars;not null;default:0" json:"chars" form:"chars"`
+}
+
+func (*NoteStat) TableName() string {
+	return TableNameNoteStat
+}
+
+// NoteStatDay accumulates the note edits of a vault on one day.
+type NoteStatDay struct {
+	VaultID      int64  `gorm:"column:vault_id;primaryKey;autoIncrement:false" json:"vaultId" form:"vaultId"`
+	Day          string `gorm:"column:day;type:varchar(10);primaryKey" json:"day" form:"day"`
+	Edits        int64  `gorm:"column:edits;not null;default:0" json:"edits" form:"edits"`
+	WordsAdded   int64  `gorm:"column:words_added;not null;default:0" json:"wordsAdded" form:"wordsAdded"`
+	WordsRemoved int64  `gorm:"column:words_removed;not null;default:0" json:"wordsRemoved" form:"wordsRemoved"`
+}
+
+func (*NoteStatDay) TableName() string {
+	return TableNameNoteStatDay
+}
+
+// NoteStatState records up to which note update a vault's note statistics are current.
+type NoteStatState struct {
+	VaultID      int64 `gorm:"column:vault_id;primaryKey;autoIncrement:false" json:"vaultId" form:"vaultId"`
+	IndexedUntil int64 `gorm:"column:indexed_until;not null;default:0" json:"indexedUntil" form:"indexedUntil"`
+}
+
+func (*NoteStatState) TableName() string {
+	return TableNameNoteStatState
+}
@@ -0,0 +1,37 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const (
+	TableNameNoteTemplate     = "note_template"
+	TableNameNoteDailySetting = "note_daily_setting"
+)
+
+// NoteTemplate stores a reusable note template of a user.
+type NoteTemplate struct {
+	ID          int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID         int64      `gorm:"column:uid;not null;uniqueIndex:idx_note_template_uid_name,priority:1;default:0" json:"uid" form:"uid"`
+	Name        string     `gorm:"column:name;not null;size:128;uniqueIndex:idx_note_template_uid_name,priority:2;default:''" json:"name" form:"name"`
+	Description string     `gorm:"column:description;default:''" json:"description" form:"description"`
+	Content     string     `gorm:"column:content;type:TEXT;default:''" json:"content" form:"content"`
+	CreatedAt   timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+	UpdatedAt   timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
+}
+
+func (*NoteTemplate) TableName() string {
+	return TableNameNoteTemplate
+}
+
+// NoteDailySetting stores how the daily note of a user is resolved.
+type NoteDailySetting struct {
+	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID       int64      `gorm:"column:uid;not null;uniqueIndex:idx_note_daily_setting_uid;default:0" json:"uid" form:"uid"`
+	Pattern   string     `gorm:"column:pattern;default:''" json:"pattern" form:"pattern"`
+	Template  string     `gorm:"column:template;default:''" json:"template" form:"template"`
+	Timezone  string     `gorm:"column:timezone;default:''" json:"timezone" form:"timezone"`
+	UpdatedAt timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
+}
+
+func (*NoteDailySetting) TableName() string {
+	return TableNameNoteDailySetting
+}
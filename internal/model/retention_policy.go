@@ -0,0 +1,19 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const TableNameRetentionPolicy = "retention_policy"
+
+// RetentionPolicy stores a soft delete retention override of a user (vault_id 0) or of one vault.
+type RetentionPolicy struct {
+	ID                  int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID                 int64      `gorm:"column:uid;not null;default:0" json:"uid" form:"uid"`
+	VaultID             int64      `gorm:"column:vault_id;not null;uniqueIndex:idx_retention_policy_vault_id;default:0" json:"vaultId" form:"vaultId"`
+	SoftDeleteRetention string     `gorm:"column:soft_delete_retention;not null;default:''" json:"softDeleteRetention" form:"softDeleteRetention"`
+	CreatedAt           timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+	UpdatedAt           timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
+}
+
+func (*RetentionPolicy) TableName() string {
+	return TableNameRetentionPolicy
+}
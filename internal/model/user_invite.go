@@ -0,0 +1,21 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const TableNameUserInvite = "user_invite"
+
+// UserInvite stores a registration invitation code.
+type UserInvite struct {
+	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	Code      string     `gorm:"column:code;type:varchar(64);uniqueIndex:idx_user_invite_code;not null" json:"code" form:"code"`
+	MaxUses   int64      `gorm:"column:max_uses;not null;default:0" json:"maxUses" form:"maxUses"`
+	Uses      int64      `gorm:"column:uses;not null;default:0" json:"uses" form:"uses"`
+	ExpiresAt int64      `gorm:"column:expires_at;not null;default:0" json:"expiresAt" form:"expiresAt"` // Unix seconds, 0 never expires
+	Note      string     `gorm:"column:note;type:text;default:''" json:"note" form:"note"`
+	CreatedBy int64      `gorm:"column:created_by;not null;default:0" json:"createdBy" form:"createdBy"`
+	CreatedAt timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+}
+
+func (*UserInvite) TableName() string {
+	return TableNameUserInvite
+}
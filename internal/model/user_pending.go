@@ -0,0 +1,19 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const TableNameUserPending = "user_pending"
+
+// UserPending stores a registration waiting for approval.
+type UserPending struct {
+	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	Email     string     `gorm:"column:email;index:idx_user_pending_email;default:''" json:"email" form:"email"`
+	Username  string     `gorm:"column:username;index:idx_user_pending_username;default:''" json:"username" form:"username"`
+	Password  string     `gorm:"column:password;default:''" json:"password" form:"password"`
+	IP        string     `gorm:"column:ip;default:''" json:"ip" form:"ip"`
+	CreatedAt timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+}
+
+func (*UserPending) TableName() string {
+	return TableNameUserPending
+}
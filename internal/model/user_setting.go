@@ -0,0 +1,23 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const TableNameUserSetting = "user_setting"
+
+// UserSetting stores one key of the per-user settings synced across devices.
+type UserSetting struct {
+	ID               int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID              int64      `gorm:"column:uid;uniqueIndex:idx_user_setting_uid_key,priority:1;not null" json:"uid" form:"uid"`
+	Key              string     `gorm:"column:setting_key;type:varchar(255);uniqueIndex:idx_user_setting_uid_key,priority:2;not null" json:"settingKey" form:"settingKey"`
+	Value            string     `gorm:"column:value;type:text;default:''" json:"value" form:"value"`
+	Version          int64      `gorm:"column:version;not null;default:0" json:"version" form:"version"`
+	Mtime            int64      `gorm:"column:mtime;not null;default:0" json:"mtime" form:"mtime"`
+	IsDeleted        int64      `gorm:"column:is_deleted;not null;default:0" json:"isDeleted" form:"isDeleted"`
+	UpdatedTimestamp int64      `gorm:"column:updated_timestamp;not null;default:0" json:"updatedTimestamp" form:"updatedTimestamp"`
+	CreatedAt        timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+	UpdatedAt        timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
+}
+
+func (*UserSetting) TableName() string {
+	return TableNameUserSetting
+}
@@ -0,0 +1,21 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const TableNameUserTOTP = "user_totp"
+
+// UserTOTP stores a user's TOTP secret and hashed recovery codes.
+type UserTOTP struct {
+	ID            int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID           int64      `gorm:"column:uid;uniqueIndex:idx_user_totp_uid;not null" json:"uid" form:"uid"`
+	Secret        string     `gorm:"column:secret;type:varchar(128);not null" json:"secret" form:"secret"`
+	Enabled       int64      `gorm:"column:enabled;not null;default:0" json:"enabled" form:"enabled"`
+	RecoveryCodes string     `gorm:"column:recovery_codes;type:text;default:''" json:"recoveryCodes" form:"recoveryCodes"`
+	LastCounter   int64      `gorm:"column:last_counter;not null;default:0" json:"lastCounter" form:"lastCounter"`
+	CreatedAt     timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+	UpdatedAt     timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
+}
+
+func (*UserTOTP) TableName() string {
+	return TableNameUserTOTP
+}
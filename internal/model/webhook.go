@@ -0,0 +1,43 @@
+package model
+
+import "github.com/haierkeys/fast-note-sync-service/pkg/timex"
+
+const (
+	TableNameWebhook         = "webhook"
+	TableNameWebhookDelivery = "webhook_delivery"
+)
+
+// Webhook stores a user's webhook endpoint.
+type Webhook struct {
+	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID       int64      `gorm:"column:uid;not null;default:0" json:"uid" form:"uid"`
+	Name      string     `gorm:"column:name;default:''" json:"name" form:"name"`
+	URL       string     `gorm:"column:url;type:TEXT;default:''" json:"url" form:"url"`
+	Secret    string     `gorm:"column:secret;default:''" json:"secret" form:"secret"`
+	Events    string     `gorm:"column:events;type:TEXT;default:''" json:"events" form:"events"`
+	IsEnabled int64      `gorm:"column:is_enabled;not null;default:0" json:"isEnabled" form:"isEnabled"`
+	CreatedAt timex.Time `gorm:"column:created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+	UpdatedAt timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
+}
+
+func (*Webhook) TableName() string {
+	return TableNameWebhook
+}
+
+// WebhookDelivery stores one event delivered to one webhook.
+type WebhookDelivery struct {
+	ID        int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id" form:"id"`
+	UID       int64      `gorm:"column:uid;not null;default:0" json:"uid" form:"uid"`
+	WebhookID int64      `gorm:"column:webhook_id;not null;index:idx_webhook_delivery_webhook_id;default:0" json:"webhookId" form:"webhookId"`
+	Event     string     `gorm:"column:event;not null;default:''" json:"event" form:"event"`
+	Payload   string     `gorm:"column:payload;type:TEXT;default:''" json:"payload" form:"payload"`
+	Status    string     `gorm:"column:status;not null;default:''" json:"status" form:"status"`
+	Attempts  int64      `gorm:"column:attempts;not null;default:0" json:"attempts" form:"attempts"`
+	Error     string     `gorm:"column:error;type:TEXT;default:''" json:"error" form:"error"`
+	CreatedAt timex.Time `gorm:"column:created_at;index:idx_webhook_delivery_created_at;default:NULL;autoCreateTime:false" json:"createdAt" form:"createdAt"`
+	UpdatedAt timex.Time `gorm:"column:updated_at;default:NULL;autoUpdateTime:false" json:"updatedAt" form:"updatedAt"`
+}
+
+func (*WebhookDelivery) TableName() string {
+	return TableNameWebhookDelivery
+}
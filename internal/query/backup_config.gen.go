@@ -44,6 +44,9 @@ func newBackupConfig(db *gorm.DB, opts ...gen.DOOption) backupConfig {
 	_backupConfig.LastMessage = field.NewString(tableName, "last_message")
 	_backupConfig.PasswordMode = field.NewInt64(tableName, "password_mode")
 	_backupConfig.PasswordValue = field.NewString(tableName, "password_value")
+	_backupConfig.WebhookURL = field.NewString(tableName, "webhook_url")
+	_backupConfig.WebhookSecret = field.NewString(tableName, "webhook_secret")
+	_backupConfig.PingURL = field.NewString(tableName, "ping_url")
 	_backupConfig.CreatedAt = field.NewField(tableName, "created_at")
 	_backupConfig.UpdatedAt = field.NewField(tableName, "updated_at")
 
@@ -72,6 +75,9 @@ type backupConfig struct {
 	LastMessage      field.String
 	PasswordMode     field.Int64
 	PasswordValue    field.String
+	WebhookURL       field.String
+	WebhookSecret    field.String
+	PingURL          field.String
 	CreatedAt        field.Field
 	UpdatedAt        field.Field
 
@@ -106,6 +112,9 @@ func (b *backupConfig) updateTableName(table string) *backupConfig {
 	b.LastMessage = field.NewString(table, "last_message")
 	b.PasswordMode = field.NewInt64(table, "password_mode")
 	b.PasswordValue = field.NewString(table, "password_value")
+	b.WebhookURL = field.NewString(table, "webhook_url")
+	b.WebhookSecret = field.NewString(table, "webhook_secret")
+	b.PingURL = field.NewString(table, "ping_url")
 	b.CreatedAt = field.NewField(table, "created_at")
 	b.UpdatedAt = field.NewField(table, "updated_at")
 
@@ -136,7 +145,7 @@ func (b *backupConfig) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (b *backupConfig) fillFieldMap() {
-	b.fieldMap = make(map[string]field.Expr, 18)
+	b.fieldMap = make(map[string]field.Expr, 21)
 	b.fieldMap["id"] = b.ID
 	b.fieldMap["uid"] = b.UID
 	b.fieldMap["vault_id"] = b.VaultID
@@ -153,6 +162,9 @@ func (b *backupConfig) fillFieldMap() {
 	b.fieldMap["last_message"] = b.LastMessage
 	b.fieldMap["password_mode"] = b.PasswordMode
 	b.fieldMap["password_value"] = b.PasswordValue
+	b.fieldMap["webhook_url"] = b.WebhookURL
+	b.fieldMap["webhook_secret"] = b.WebhookSecret
+	b.fieldMap["ping_url"] = b.PingURL
 	b.fieldMap["created_at"] = b.CreatedAt
 	b.fieldMap["updated_at"] = b.UpdatedAt
 }
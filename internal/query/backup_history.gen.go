@@ -41,6 +41,8 @@ func newBackupHistory(db *gorm.DB, opts ...gen.DOOption) backupHistory {
 	_backupHistory.Message = field.NewString(tableName, "message")
 	_backupHistory.FilePath = field.NewString(tableName, "file_path")
 	_backupHistory.Password = field.NewString(tableName, "password")
+	_backupHistory.ArchiveSize = field.NewInt64(tableName, "archive_size")
+	_backupHistory.Checksum = field.NewString(tableName, "checksum")
 	_backupHistory.CreatedAt = field.NewField(tableName, "created_at")
 	_backupHistory.UpdatedAt = field.NewField(tableName, "updated_at")
 
@@ -52,22 +54,24 @@ func newBackupHistory(db *gorm.DB, opts ...gen.DOOption) backupHistory {
 type backupHistory struct {
 	backupHistoryDo backupHistoryDo
 
-	ALL       field.Asterisk
-	ID        field.Int64
-	UID       field.Int64
-	ConfigID  field.Int64
-	StorageID field.Int64
-	Type      field.String
-	StartTime field.Time
-	EndTime   field.Time
-	Status    field.Int64
-	FileSize  field.Int64
-	FileCount field.Int64
-	Message   field.String
-	FilePath  field.String
-	Password  field.String
-	CreatedAt field.Field
-	UpdatedAt field.Field
+	ALL         field.Asterisk
+	ID          field.Int64
+	UID         field.Int64
+	ConfigID    field.Int64
+	StorageID   field.Int64
+	Type        field.String
+	StartTime   field.Time
+	EndTime     field.Time
+	Status      field.Int64
+	FileSize    field.Int64
+	FileCount   field.Int64
+	Message     field.String
+	FilePath    field.String
+	Password    field.String
+	ArchiveSize field.Int64
+	Checksum    field.String
+	CreatedAt   field.Field
+	UpdatedAt   field.Field
 
 	fieldMap map[string]field.Expr
 }
@@ -97,6 +101,8 @@ func (b *backupHistory) updateTableName(table string) *backupHistory {
 	b.Message = field.NewString(table, "message")
 	b.FilePath = field.NewString(table, "file_path")
 	b.Password = field.NewString(table, "password")
+	b.ArchiveSize = field.NewInt64(table, "archive_size")
+	b.Checksum = field.NewString(table, "checksum")
 	b.CreatedAt = field.NewField(table, "created_at")
 	b.UpdatedAt = field.NewField(table, "updated_at")
 
@@ -127,7 +133,7 @@ func (b *backupHistory) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (b *backupHistory) fillFieldMap() {
-	b.fieldMap = make(map[string]field.Expr, 15)
+	b.fieldMap = make(map[string]field.Expr, 17)
 	b.fieldMap["id"] = b.ID
 	b.fieldMap["uid"] = b.UID
 	b.fieldMap["config_id"] = b.ConfigID
@@ -141,6 +147,8 @@ func (b *backupHistory) fillFieldMap() {
 	b.fieldMap["message"] = b.Message
 	b.fieldMap["file_path"] = b.FilePath
 	b.fieldMap["password"] = b.Password
+	b.fieldMap["archive_size"] = b.ArchiveSize
+	b.fieldMap["checksum"] = b.Checksum
 	b.fieldMap["created_at"] = b.CreatedAt
 	b.fieldMap["updated_at"] = b.UpdatedAt
 }
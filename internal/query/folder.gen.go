@@ -35,6 +35,7 @@ func newFolder(db *gorm.DB, opts ...gen.DOOption) folder {
 	_folder.PathHash = field.NewString(tableName, "path_hash")
 	_folder.Level = field.NewInt64(tableName, "level")
 	_folder.FID = field.NewInt64(tableName, "fid")
+	_folder.Archived = field.NewInt64(tableName, "archived")
 	_folder.Ctime = field.NewInt64(tableName, "ctime")
 	_folder.Mtime = field.NewInt64(tableName, "mtime")
 	_folder.UpdatedTimestamp = field.NewInt64(tableName, "updated_timestamp")
@@ -57,6 +58,7 @@ type folder struct {
 	PathHash         field.String
 	Level            field.Int64
 	FID              field.Int64
+	Archived         field.Int64
 	Ctime            field.Int64
 	Mtime            field.Int64
 	UpdatedTimestamp field.Int64
@@ -85,6 +87,7 @@ func (f *folder) updateTableName(table string) *folder {
 	f.PathHash = field.NewString(table, "path_hash")
 	f.Level = field.NewInt64(table, "level")
 	f.FID = field.NewInt64(table, "fid")
+	f.Archived = field.NewInt64(table, "archived")
 	f.Ctime = field.NewInt64(table, "ctime")
 	f.Mtime = field.NewInt64(table, "mtime")
 	f.UpdatedTimestamp = field.NewInt64(table, "updated_timestamp")
@@ -114,7 +117,7 @@ func (f *folder) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (f *folder) fillFieldMap() {
-	f.fieldMap = make(map[string]field.Expr, 12)
+	f.fieldMap = make(map[string]field.Expr, 13)
 	f.fieldMap["id"] = f.ID
 	f.fieldMap["vault_id"] = f.VaultID
 	f.fieldMap["action"] = f.Action
@@ -122,6 +125,7 @@ func (f *folder) fillFieldMap() {
 	f.fieldMap["path_hash"] = f.PathHash
 	f.fieldMap["level"] = f.Level
 	f.fieldMap["fid"] = f.FID
+	f.fieldMap["archived"] = f.Archived
 	f.fieldMap["ctime"] = f.Ctime
 	f.fieldMap["mtime"] = f.Mtime
 	f.fieldMap["updated_timestamp"] = f.UpdatedTimestamp
@@ -43,6 +43,7 @@ func newGitSyncConfig(db *gorm.DB, opts ...gen.DOOption) gitSyncConfig {
 	_gitSyncConfig.LastMessage = field.NewString(tableName, "last_message")
 	_gitSyncConfig.IncludeConfig = field.NewInt64(tableName, "include_config")
 	_gitSyncConfig.ConfigSyncRules = field.NewString(tableName, "config_sync_rules")
+	_gitSyncConfig.PingURL = field.NewString(tableName, "ping_url")
 	_gitSyncConfig.CreatedAt = field.NewField(tableName, "created_at")
 	_gitSyncConfig.UpdatedAt = field.NewField(tableName, "updated_at")
 
@@ -70,6 +71,7 @@ type gitSyncConfig struct {
 	LastMessage     field.String
 	IncludeConfig   field.Int64
 	ConfigSyncRules field.String
+	PingURL         field.String
 	CreatedAt       field.Field
 	UpdatedAt       field.Field
 
@@ -103,6 +105,7 @@ func (g *gitSyncConfig) updateTableName(table string) *gitSyncConfig {
 	g.LastMessage = field.NewString(table, "last_message")
 	g.IncludeConfig = field.NewInt64(table, "include_config")
 	g.ConfigSyncRules = field.NewString(table, "config_sync_rules")
+	g.PingURL = field.NewString(table, "ping_url")
 	g.CreatedAt = field.NewField(table, "created_at")
 	g.UpdatedAt = field.NewField(table, "updated_at")
 
@@ -133,7 +136,7 @@ func (g *gitSyncConfig) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (g *gitSyncConfig) fillFieldMap() {
-	g.fieldMap = make(map[string]field.Expr, 17)
+	g.fieldMap = make(map[string]field.Expr, 18)
 	g.fieldMap["id"] = g.ID
 	g.fieldMap["uid"] = g.UID
 	g.fieldMap["vault_id"] = g.VaultID
@@ -149,6 +152,7 @@ func (g *gitSyncConfig) fillFieldMap() {
 	g.fieldMap["last_message"] = g.LastMessage
 	g.fieldMap["include_config"] = g.IncludeConfig
 	g.fieldMap["config_sync_rules"] = g.ConfigSyncRules
+	g.fieldMap["ping_url"] = g.PingURL
 	g.fieldMap["created_at"] = g.CreatedAt
 	g.fieldMap["updated_at"] = g.UpdatedAt
 }
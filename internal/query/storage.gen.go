@@ -41,6 +41,9 @@ func newStorage(db *gorm.DB, opts ...gen.DOOption) storage {
 	_storage.AccessURLPrefix = field.NewString(tableName, "access_url_prefix")
 	_storage.User = field.NewString(tableName, "user")
 	_storage.Password = field.NewString(tableName, "password")
+	_storage.PathStyle = field.NewInt64(tableName, "path_style")
+	_storage.CACert = field.NewString(tableName, "ca_cert")
+	_storage.InsecureSkipVerify = field.NewInt64(tableName, "insecure_skip_verify")
 	_storage.IsEnabled = field.NewInt64(tableName, "is_enabled")
 	_storage.IsDeleted = field.NewInt64(tableName, "is_deleted")
 	_storage.CreatedAt = field.NewField(tableName, "created_at")
@@ -55,25 +58,28 @@ func newStorage(db *gorm.DB, opts ...gen.DOOption) storage {
 type storage struct {
 	storageDo storageDo
 
-	ALL             field.Asterisk
-	ID              field.Int64
-	UID             field.Int64
-	Type            field.String
-	Endpoint        field.String
-	Region          field.String
-	AccountID       field.String
-	BucketName      field.String
-	AccessKeyID     field.String
-	AccessKeySecret field.String
-	CustomPath      field.String
-	AccessURLPrefix field.String
-	User            field.String
-	Password        field.String
-	IsEnabled       field.Int64
-	IsDeleted       field.Int64
-	CreatedAt       field.Field
-	UpdatedAt       field.Field
-	DeletedAt       field.Field
+	ALL                field.Asterisk
+	ID                 field.Int64
+	UID                field.Int64
+	Type               field.String
+	Endpoint           field.String
+	Region             field.String
+	AccountID          field.String
+	BucketName         field.String
+	AccessKeyID        field.String
+	AccessKeySecret    field.String
+	CustomPath         field.String
+	AccessURLPrefix    field.String
+	User               field.String
+	Password           field.String
+	PathStyle          field.Int64
+	CACert             field.String
+	InsecureSkipVerify field.Int64
+	IsEnabled          field.Int64
+	IsDeleted          field.Int64
+	CreatedAt          field.Field
+	UpdatedAt          field.Field
+	DeletedAt          field.Field
 
 	fieldMap map[string]field.Expr
 }
@@ -103,6 +109,9 @@ func (s *storage) updateTableName(table string) *storage {
 	s.AccessURLPrefix = field.NewString(table, "access_url_prefix")
 	s.User = field.NewString(table, "user")
 	s.Password = field.NewString(table, "password")
+	s.PathStyle = field.NewInt64(table, "path_style")
+	s.CACert = field.NewString(table, "ca_cert")
+	s.InsecureSkipVerify = field.NewInt64(table, "insecure_skip_verify")
 	s.IsEnabled = field.NewInt64(table, "is_enabled")
 	s.IsDeleted = field.NewInt64(table, "is_deleted")
 	s.CreatedAt = field.NewField(table, "created_at")
@@ -132,7 +141,7 @@ func (s *storage) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (s *storage) fillFieldMap() {
-	s.fieldMap = make(map[string]field.Expr, 18)
+	s.fieldMap = make(map[string]field.Expr, 21)
 	s.fieldMap["id"] = s.ID
 	s.fieldMap["uid"] = s.UID
 	s.fieldMap["type"] = s.Type
@@ -146,6 +155,9 @@ func (s *storage) fillFieldMap() {
 	s.fieldMap["access_url_prefix"] = s.AccessURLPrefix
 	s.fieldMap["user"] = s.User
 	s.fieldMap["password"] = s.Password
+	s.fieldMap["path_style"] = s.PathStyle
+	s.fieldMap["ca_cert"] = s.CACert
+	s.fieldMap["insecure_skip_verify"] = s.InsecureSkipVerify
 	s.fieldMap["is_enabled"] = s.IsEnabled
 	s.fieldMap["is_deleted"] = s.IsDeleted
 	s.fieldMap["created_at"] = s.CreatedAt
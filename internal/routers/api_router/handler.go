@@ -7,7 +7,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 )
 
 // Handler basic Handler struct, encapsulates App Container
@@ -48,3 +50,36 @@ func (h *Handler) getClientInfo(c *gin.Context) (string, string, string) {
 
 	return clientType, clientName, clientVersion
 }
+
+// checkAdmin responds with an error and returns the caller UID, 0 when the caller is not the admin
+// checkAdmin 调用者不是管理员时返回错误响应并返回 0，否则返回调用者 UID
+func (h *Handler) checkAdmin(c *gin.Context, response *pkgapp.Response) int64 {
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return 0
+	}
+	if adminUID := h.App.Config().User.AdminUID; adminUID != 0 && uid != int64(adminUID) {
+		response.ToResponse(code.ErrorUserIsNotAdmin)
+		return 0
+	}
+	return uid
+}
+
+// audit records an audit log entry for the request, with the client IP and client information
+// audit 为请求记录一条审计日志，包含客户端 IP 和客户端信息
+func (h *Handler) audit(c *gin.Context, uid int64, action domain.AuditAction, target, detail string) {
+	if h.App.AuditService == nil {
+		return
+	}
+	clientType, clientName, _ := h.getClientInfo(c)
+	h.App.AuditService.Record(c.Request.Context(), &domain.AuditLog{
+		UID:        uid,
+		Action:     action,
+		Target:     target,
+		Detail:     detail,
+		IP:         c.ClientIP(),
+		ClientType: clientType,
+		ClientName: clientName,
+	})
+}
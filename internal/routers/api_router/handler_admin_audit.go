@@ -0,0 +1,72 @@
+package api_router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// AdminAuditHandler audit log API router handler (admin only)
+// AdminAuditHandler 审计日志 API 路由处理器（仅管理员）
+type AdminAuditHandler struct {
+	*Handler
+}
+
+// NewAdminAuditHandler creates AdminAuditHandler instance
+// NewAdminAuditHandler 创建 AdminAuditHandler 实例
+func NewAdminAuditHandler(a *app.App) *AdminAuditHandler {
+	return &AdminAuditHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// List lists audit log entries
+// @Summary List audit log
+// @Description List who did what (logins, note deletes, config changes, backup runs, user deletes) with IP and client, newest first, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.AuditLogListRequest false "Filter Parameters"
+// @Param pagination query pkgapp.PaginationRequest false "Pagination Parameters"
+// @Success 200 {object} pkgapp.Res{data=pkgapp.ListRes{list=[]dto.AuditLogDTO}} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/audit [get]
+func (h *AdminAuditHandler) List(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.AuditLogListRequest{}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+	cfg := h.App.Config()
+	if cfg.User.AdminUID != 0 && uid != int64(cfg.User.AdminUID) {
+		response.ToResponse(code.ErrorUserIsNotAdmin)
+		return
+	}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	pager := pkgapp.NewPager(c)
+	list, total, err := h.App.AuditService.List(c.Request.Context(), params, pager.Page, pager.PageSize)
+	if err != nil {
+		h.App.Logger().Error("AdminAuditHandler.List",
+			zap.Error(err),
+			zap.String("traceId", middleware.GetTraceID(c.Request.Context())),
+		)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponseList(code.Success, list, int(total))
+}
@@ -0,0 +1,218 @@
+package api_router
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/branding"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"go.uber.org/zap"
+)
+
+const (
+	// brandingLogoDir directory uploaded logos are stored in, served under brandingLogoURL
+	// brandingLogoDir 上传的标志的存放目录，通过 brandingLogoURL 访问
+	brandingLogoDir = "storage/user_static/branding"
+	brandingLogoURL = "/user_static/branding/"
+	// brandingLogoMaxSize upper bound of an uploaded logo
+	// brandingLogoMaxSize 上传标志的大小上限
+	brandingLogoMaxSize = 1 << 20
+)
+
+// brandingLogoTypes image types accepted for logo uploads. SVG is not accepted because an uploaded SVG
+// could carry scripts served from this origin; an SVG logo can still be linked through logoUrl.
+// brandingLogoTypes 允许上传的标志图片类型。不接受 SVG，因为上传的 SVG 可能携带脚本并以本站来源提供；
+// 仍可通过 logoUrl 链接 SVG 标志。
+var brandingLogoTypes = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true, ".ico": true}
+
+// GetBrandingConfig retrieves the instance branding (requires admin privileges)
+// @Summary Get branding config
+// @Description Get the instance branding shown by the WebGUI, share and login pages, requires admin privileges
+// @Tags Config
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.AdminBrandingConfig} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/config/branding [get]
+func (h *AdminControlHandler) GetBrandingConfig(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	cfg := h.App.Config()
+	logger := h.App.Logger()
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		logger.Error("apiRouter.AdminControl.GetBrandingConfig err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	if cfg.User.AdminUID != 0 && uid != int64(cfg.User.AdminUID) {
+		response.ToResponse(code.ErrorUserIsNotAdmin)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(dto.AdminBrandingConfig(cfg.WebGUI.Branding)))
+}
+
+// UpdateBrandingConfig updates the instance branding (requires admin privileges)
+// @Summary Update branding config
+// @Description Modify the instance name, logo, colors, login page message and custom CSS, requires admin privileges
+// @Tags Config
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.AdminBrandingConfig true "Config Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.AdminBrandingConfig} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/config/branding [post]
+func (h *AdminControlHandler) UpdateBrandingConfig(c *gin.Context) {
+	params := &dto.AdminBrandingConfig{}
+	response := pkgapp.NewResponse(c)
+	cfg := h.App.Config()
+	logger := h.App.Logger()
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		logger.Error("apiRouter.AdminControl.UpdateBrandingConfig.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		logger.Error("apiRouter.AdminControl.UpdateBrandingConfig err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	if cfg.User.AdminUID != 0 && uid != int64(cfg.User.AdminUID) {
+		response.ToResponse(code.ErrorUserIsNotAdmin)
+		return
+	}
+
+	params.InstanceName = strings.TrimSpace(params.InstanceName)
+	params.LogoURL = strings.TrimSpace(params.LogoURL)
+	params.PrimaryColor = strings.TrimSpace(params.PrimaryColor)
+	params.AccentColor = strings.TrimSpace(params.AccentColor)
+	if !branding.ValidColor(params.PrimaryColor) || !branding.ValidColor(params.AccentColor) {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails("colors must be hex colors such as #3b82f6"))
+		return
+	}
+	if !branding.ValidLogoURL(params.LogoURL) {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails("logoUrl must be a path on this server or an http(s) URL"))
+		return
+	}
+
+	previousLogo := cfg.WebGUI.Branding.LogoURL
+	cfg.WebGUI.Branding.InstanceName = params.InstanceName
+	cfg.WebGUI.Branding.LogoURL = params.LogoURL
+	cfg.WebGUI.Branding.PrimaryColor = params.PrimaryColor
+	cfg.WebGUI.Branding.AccentColor = params.AccentColor
+	cfg.WebGUI.Branding.LoginMessage = params.LoginMessage
+	cfg.WebGUI.Branding.CustomCSS = params.CustomCSS
+
+	if err := h.App.SaveConfig(); err != nil {
+		logger.Error("apiRouter.AdminControl.UpdateBrandingConfig.Save err", zap.Error(err))
+		response.ToResponse(code.ErrorConfigSaveFailed)
+		return
+	}
+	h.audit(c, uid, domain.AuditActionConfigUpdate, "config/branding", "")
+	if previousLogo != params.LogoURL {
+		removeUploadedLogo(previousLogo)
+	}
+
+	response.ToResponse(code.Success.WithData(params))
+}
+
+// UploadBrandingLogo uploads a logo and makes it the instance logo (requires admin privileges)
+// @Summary Upload branding logo
+// @Description Upload a PNG, JPEG, GIF, WebP or ICO logo (max 1 MiB) used as logo and favicon, requires admin privileges
+// @Tags Config
+// @Security UserAuthToken
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Logo image"
+// @Success 200 {object} pkgapp.Res{data=dto.AdminBrandingConfig} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/config/branding/logo [post]
+func (h *AdminControlHandler) UploadBrandingLogo(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	cfg := h.App.Config()
+	logger := h.App.Logger()
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		logger.Error("apiRouter.AdminControl.UploadBrandingLogo err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	if cfg.User.AdminUID != 0 && uid != int64(cfg.User.AdminUID) {
+		response.ToResponse(code.ErrorUserIsNotAdmin)
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails("file is required"))
+		return
+	}
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if !brandingLogoTypes[ext] {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails("logo must be a png, jpg, gif, webp or ico image"))
+		return
+	}
+	if file.Size > brandingLogoMaxSize {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails("logo must not exceed 1 MiB"))
+		return
+	}
+
+	// /user_static is cached for a year, a new name per upload makes browsers fetch the new logo
+	// /user_static 缓存一年，每次上传使用新文件名，浏览器才会获取新标志
+	name := fmt.Sprintf("logo-%d%s", time.Now().UnixMilli(), ext)
+	if err := os.MkdirAll(brandingLogoDir, 0o755); err != nil {
+		logger.Error("apiRouter.AdminControl.UploadBrandingLogo.MkdirAll err", zap.Error(err))
+		response.ToResponse(code.ErrorFileUploadFailed)
+		return
+	}
+	if err := c.SaveUploadedFile(file, filepath.Join(brandingLogoDir, name)); err != nil {
+		logger.Error("apiRouter.AdminControl.UploadBrandingLogo.SaveUploadedFile err", zap.Error(err))
+		response.ToResponse(code.ErrorFileUploadFailed)
+		return
+	}
+
+	previousLogo := cfg.WebGUI.Branding.LogoURL
+	cfg.WebGUI.Branding.LogoURL = brandingLogoURL + name
+	if err := h.App.SaveConfig(); err != nil {
+		logger.Error("apiRouter.AdminControl.UploadBrandingLogo.Save err", zap.Error(err))
+		cfg.WebGUI.Branding.LogoURL = previousLogo
+		_ = os.Remove(filepath.Join(brandingLogoDir, name))
+		response.ToResponse(code.ErrorConfigSaveFailed)
+		return
+	}
+	h.audit(c, uid, domain.AuditActionConfigUpdate, "config/branding/logo", "")
+	removeUploadedLogo(previousLogo)
+
+	response.ToResponse(code.Success.WithData(dto.AdminBrandingConfig(cfg.WebGUI.Branding)))
+}
+
+// removeUploadedLogo deletes a logo previously uploaded through UploadBrandingLogo, other URLs are left alone
+// removeUploadedLogo 删除此前通过 UploadBrandingLogo 上传的标志，其他地址不做处理
+func removeUploadedLogo(logoURL string) {
+	if !strings.HasPrefix(logoURL, brandingLogoURL) {
+		return
+	}
+	name := path.Base(logoURL)
+	if name != strings.TrimPrefix(logoURL, brandingLogoURL) || !strings.HasPrefix(name, "logo-") {
+		return
+	}
+	_ = os.Remove(filepath.Join(brandingLogoDir, name))
+}
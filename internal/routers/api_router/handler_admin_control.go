@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,9 +20,11 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
 	"github.com/haierkeys/fast-note-sync-service/internal/config"
 	"github.com/haierkeys/fast-note-sync-service/internal/dao"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/ipfilter"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/host"
@@ -29,6 +32,7 @@ import (
 	"github.com/shirou/gopsutil/v4/mem"
 	"github.com/shirou/gopsutil/v4/process"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"golang.org/x/mod/semver"
 )
 
@@ -68,6 +72,10 @@ func (h *AdminControlHandler) Config(c *gin.Context) {
 		FontSet:          cfg.WebGUI.FontSet,
 		RegisterIsEnable: h.App.UserService.IsRegisterEnabled(c),
 		FtsBleveEnabled:  ftsBleveEnabled,
+		Branding:         dto.AdminBrandingConfig(cfg.WebGUI.Branding),
+	}
+	if guard := h.App.LoginGuard(); guard != nil {
+		data.CaptchaProvider, data.CaptchaSiteKey = guard.CaptchaSiteKey()
 	}
 	response.ToResponse(code.Success.WithData(data))
 }
@@ -134,6 +142,7 @@ func (h *AdminControlHandler) GetConfig(c *gin.Context) {
 		SoftDeleteRetentionTime: &cfg.App.SoftDeleteRetentionTime,
 		UploadSessionTimeout:    &cfg.App.UploadSessionTimeout,
 		HistoryKeepVersions:     cfg.App.HistoryKeepVersions,
+		HistoryKeepDuration:     &cfg.App.HistoryKeepDuration,
 		HistorySaveDelay:        &cfg.App.HistorySaveDelay,
 		// DefaultAPIFolder:        &cfg.App.DefaultAPIFolder,
 		AdminUID:                      &cfg.User.AdminUID,
@@ -145,7 +154,9 @@ func (h *AdminControlHandler) GetConfig(c *gin.Context) {
 		PullReleaseChannel:            &cfg.App.PullReleaseChannel,
 		WebGUILoginTokenExpiry:        &cfg.Security.WebGUILoginTokenExpiry,
 		WebGUILoginTokenBindIP:        cfg.Security.WebGUILoginTokenBindIP,
+		RequireAdminTOTP:              &cfg.Security.RequireAdminTOTP,
 		CustomResponseHeaders:         &cfg.Server.CustomResponseHeaders,
+		SwaggerUIEnabled:              &cfg.Server.SwaggerUI,
 		DefaultPageSize:               &cfg.App.DefaultPageSize,
 		MaxPageSize:                   &cfg.App.MaxPageSize,
 		DefaultContextTimeout:         &cfg.App.DefaultContextTimeout,
@@ -172,6 +183,10 @@ func (h *AdminControlHandler) GetConfig(c *gin.Context) {
 		GitEmail:                      &cfg.Git.Email,
 		PipelineWindowUp:              cfg.App.PipelineWindowUp,
 		PipelineWindowDown:            cfg.App.PipelineWindowDown,
+		MaxNoteSize:                   &cfg.App.MaxNoteSize,
+		MaxAttachmentSize:             &cfg.App.MaxAttachmentSize,
+		MaxNotesPerVault:              &cfg.App.MaxNotesPerVault,
+		LogLevel:                      &cfg.Log.Level,
 	}
 
 	response.ToResponse(code.Success.WithData(data))
@@ -224,6 +239,17 @@ func (h *AdminControlHandler) UpdateConfig(c *gin.Context) {
 		return
 	}
 
+	// Validate historyKeepDuration format
+	// 验证 historyKeepDuration 格式
+	if params.HistoryKeepDuration != nil && *params.HistoryKeepDuration != "" {
+		if d, err := util.ParseDuration(*params.HistoryKeepDuration); err != nil || d < 0 {
+			logger.Warn("apiRouter.WebGUI.UpdateConfig invalid historyKeepDuration format",
+				zap.String("value", *params.HistoryKeepDuration))
+			response.ToResponse(code.ErrorInvalidParams.WithDetails("historyKeepDuration format invalid, e.g. 180d, 0"))
+			return
+		}
+	}
+
 	// Validate historySaveDelay cannot be less than 1 second
 	// 验证 historySaveDelay 不能小于 1 秒
 	if params.HistorySaveDelay != nil && *params.HistorySaveDelay != "" {
@@ -294,6 +320,32 @@ func (h *AdminControlHandler) UpdateConfig(c *gin.Context) {
 		}
 	}
 
+	// Validate content limits, "0" disables a size limit
+	// 验证内容限制，"0" 表示不限制大小
+	for name, size := range map[string]*string{"maxNoteSize": params.MaxNoteSize, "maxAttachmentSize": params.MaxAttachmentSize} {
+		if size != nil && strings.TrimSpace(*size) != "0" && util.ParseSize(*size, -1) < 0 {
+			logger.Warn("apiRouter.WebGUI.UpdateConfig invalid "+name+" format",
+				zap.String("value", *size))
+			response.ToResponse(code.ErrorInvalidParams.WithDetails(name + " format invalid, e.g. 5MB, 512KB, 0"))
+			return
+		}
+	}
+	if params.MaxNotesPerVault != nil && *params.MaxNotesPerVault < 0 {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails("maxNotesPerVault must not be negative"))
+		return
+	}
+
+	// Validate log level
+	// 验证日志级别
+	if params.LogLevel != nil {
+		if _, err := zapcore.ParseLevel(*params.LogLevel); err != nil {
+			logger.Warn("apiRouter.WebGUI.UpdateConfig invalid logLevel",
+				zap.String("value", *params.LogLevel))
+			response.ToResponse(code.ErrorInvalidParams.WithDetails("logLevel invalid, e.g. debug, info, warn, error"))
+			return
+		}
+	}
+
 	// Update configuration
 	// 更新配置
 	if params.FontSet != nil {
@@ -314,6 +366,9 @@ func (h *AdminControlHandler) UpdateConfig(c *gin.Context) {
 	if params.HistoryKeepVersions != nil {
 		cfg.App.HistoryKeepVersions = params.HistoryKeepVersions
 	}
+	if params.HistoryKeepDuration != nil {
+		cfg.App.HistoryKeepDuration = *params.HistoryKeepDuration
+	}
 	if params.HistorySaveDelay != nil {
 		cfg.App.HistorySaveDelay = *params.HistorySaveDelay
 	}
@@ -334,10 +389,6 @@ func (h *AdminControlHandler) UpdateConfig(c *gin.Context) {
 	}
 	if params.PullSource != nil {
 		cfg.App.PullSource = *params.PullSource
-		// Sync the running SourceSelector so the change takes effect immediately
-		// instead of waiting for a server restart.
-		// 同步到运行中的 SourceSelector，使改动立即生效，无需重启服务端。
-		h.App.SetPullSourceMode(*params.PullSource)
 	}
 	if params.PullReleaseChannel != nil {
 		cfg.App.PullReleaseChannel = *params.PullReleaseChannel
@@ -348,9 +399,15 @@ func (h *AdminControlHandler) UpdateConfig(c *gin.Context) {
 	if params.WebGUILoginTokenBindIP != nil {
 		cfg.Security.WebGUILoginTokenBindIP = params.WebGUILoginTokenBindIP
 	}
+	if params.RequireAdminTOTP != nil {
+		cfg.Security.RequireAdminTOTP = *params.RequireAdminTOTP
+	}
 	if params.CustomResponseHeaders != nil {
 		cfg.Server.CustomResponseHeaders = *params.CustomResponseHeaders
 	}
+	if params.SwaggerUIEnabled != nil {
+		cfg.Server.SwaggerUI = *params.SwaggerUIEnabled
+	}
 	if params.DefaultPageSize != nil {
 		cfg.App.DefaultPageSize = *params.DefaultPageSize
 	}
@@ -433,14 +490,27 @@ func (h *AdminControlHandler) UpdateConfig(c *gin.Context) {
 	if params.PipelineWindowDown != nil {
 		cfg.App.PipelineWindowDown = params.PipelineWindowDown
 	}
+	if params.MaxNoteSize != nil {
+		cfg.App.MaxNoteSize = *params.MaxNoteSize
+	}
+	if params.MaxAttachmentSize != nil {
+		cfg.App.MaxAttachmentSize = *params.MaxAttachmentSize
+	}
+	if params.MaxNotesPerVault != nil {
+		cfg.App.MaxNotesPerVault = *params.MaxNotesPerVault
+	}
+	if params.LogLevel != nil {
+		cfg.Log.Level = *params.LogLevel
+	}
 
-	// Save configuration to file
-	// 保存配置到文件
-	if err := cfg.Save(); err != nil {
+	// Save configuration to file, the running services pick the changes up without a restart
+	// 保存配置到文件，运行中的服务无需重启即可使用新配置
+	if err := h.App.SaveConfig(); err != nil {
 		logger.Error("apiRouter.WebGUI.UpdateConfig.Save err", zap.Error(err))
 		response.ToResponse(code.ErrorConfigSaveFailed)
 		return
 	}
+	h.audit(c, uid, domain.AuditActionConfigUpdate, "config", "")
 
 	response.ToResponse(code.Success.WithData(params))
 }
@@ -577,11 +647,12 @@ func (h *AdminControlHandler) UpdateUserDatabaseConfig(c *gin.Context) {
 
 	// Save configuration to file
 	// 保存配置到文件
-	if err := cfg.Save(); err != nil {
+	if err := h.App.SaveConfig(); err != nil {
 		logger.Error("apiRouter.AdminControl.UpdateUserDatabaseConfig.Save err", zap.Error(err))
 		response.ToResponse(code.ErrorConfigSaveFailed)
 		return
 	}
+	h.audit(c, uid, domain.AuditActionConfigUpdate, "config/user_database", "")
 
 	response.ToResponse(code.Success.WithData(params))
 }
@@ -780,11 +851,12 @@ func (h *AdminControlHandler) UpdateCloudflareConfig(c *gin.Context) {
 	cfg.Cloudflare.Token = params.Token
 	cfg.Cloudflare.LogEnabled = params.LogEnabled
 
-	if err := cfg.Save(); err != nil {
+	if err := h.App.SaveConfig(); err != nil {
 		logger.Error("apiRouter.AdminControl.UpdateCloudflareConfig.Save err", zap.Error(err))
 		response.ToResponse(code.ErrorConfigSaveFailed)
 		return
 	}
+	h.audit(c, uid, domain.AuditActionConfigUpdate, "config/cloudflare", "")
 
 	response.ToResponse(code.Success.WithData(params))
 }
@@ -904,6 +976,9 @@ func (h *AdminControlHandler) UpdateUser(c *gin.Context) {
 		}
 		return
 	}
+	if params.IsDeleted {
+		h.audit(c, uid, domain.AuditActionUserDelete, strconv.FormatInt(params.UID, 10), params.Username)
+	}
 
 	response.ToResponse(code.SuccessUpdate)
 }
@@ -1115,11 +1190,12 @@ func (h *AdminControlHandler) GetSystemInfo(c *gin.Context) {
 
 // Upgrade triggers server automatic upgrade
 // @Summary Trigger server upgrade
-// @Description Download latest version and restart server
+// @Description Download latest version and restart server; outside the maintenance window the upgrade is queued unless forced
 // @Tags System
 // @Produce json
 // @Security UserAuthToken
 // @Param version query string true "Version to upgrade (e.g. 2.0.10 or latest)"
+// @Param force query bool false "Upgrade now even outside the maintenance window"
 // @Success 200 {object} pkgapp.Res "Success"
 // @Router /api/admin/upgrade [get]
 func (h *AdminControlHandler) Upgrade(c *gin.Context) {
@@ -1192,6 +1268,29 @@ func (h *AdminControlHandler) Upgrade(c *gin.Context) {
 		downloadURL = fmt.Sprintf("https://cnb.cool/haierkeys/fast-note-sync-service/-/releases/download/%s/%s", versionRaw, fileName)
 	}
 
+	// Outside the maintenance window the upgrade is queued and runs once the window opens
+	// 维护窗口外升级进入队列，窗口开启后执行
+	if coordinator := h.App.Maintenance(); !coordinator.Allow(upgradeReq.Force) {
+		coordinator.Defer("upgrade", "server", "Upgrade to "+versionRaw, uid, func(ctx context.Context) error {
+			return h.performUpgrade(ctx, downloadURL, fileName, versionRaw)
+		})
+		start, _ := coordinator.NextWindow()
+		response.ToResponse(code.Success.WithDetails("Upgrade queued for the maintenance window starting at " + start.Format(time.RFC3339)))
+		return
+	}
+
+	if err := h.performUpgrade(c.Request.Context(), downloadURL, fileName, versionRaw); err != nil {
+		response.ToResponse(code.Failed.WithDetails(err.Error()))
+		return
+	}
+
+	response.ToResponse(code.Success.WithDetails("Upgrade triggered, server is restarting..."))
+}
+
+// performUpgrade downloads and extracts the release archive, then restarts into the new binary
+// performUpgrade 下载并解压发布包，然后切换到新的可执行文件重启
+func (h *AdminControlHandler) performUpgrade(ctx context.Context, downloadURL, fileName, versionRaw string) error {
+	cfg := h.App.Config()
 	h.App.Logger().Info("Starting upgrade download", zap.String("url", downloadURL), zap.String("version", versionRaw))
 
 	// Prepare temp directory
@@ -1199,38 +1298,34 @@ func (h *AdminControlHandler) Upgrade(c *gin.Context) {
 	tempDir := filepath.Join(cfg.App.TempPath, "upgrade")
 	_ = os.RemoveAll(tempDir)
 	if err := os.MkdirAll(tempDir, 0755); err != nil {
-		response.ToResponse(code.Failed.WithDetails("Failed to create temp directory: " + err.Error()))
-		return
+		return fmt.Errorf("failed to create temp directory: %w", err)
 	}
 
 	// Download
 	tarPath := filepath.Join(tempDir, fileName)
-	if err := h.downloadFile(c.Request.Context(), downloadURL, tarPath); err != nil {
+	if err := h.downloadFile(ctx, downloadURL, tarPath); err != nil {
 		h.App.Logger().Error("Upgrade download failed",
 			zap.String("url", downloadURL),
 			zap.Error(err),
 		)
-		response.ToResponse(code.Failed.WithDetails("Download failed: " + err.Error()))
-		return
+		return fmt.Errorf("download failed: %w", err)
 	}
 
 	// Extract
 	binaryName := "fast-note-sync-service"
-	if goos == "windows" {
+	if runtime.GOOS == "windows" {
 		binaryName += ".exe"
 	}
 	extractedBinaryPath := filepath.Join(tempDir, binaryName)
 
 	if err := h.extractBinary(tarPath, tempDir, binaryName); err != nil {
 		h.App.Logger().Error("Upgrade extract failed", zap.Error(err))
-		response.ToResponse(code.Failed.WithDetails("Extract failed: " + err.Error()))
-		return
+		return fmt.Errorf("extract failed: %w", err)
 	}
 
 	// Trigger upgrade in App
 	h.App.TriggerUpgrade(extractedBinaryPath)
-
-	response.ToResponse(code.Success.WithDetails("Upgrade triggered, server is restarting..."))
+	return nil
 }
 
 // Restart triggers server automatic restart
@@ -1385,6 +1480,78 @@ func (h *AdminControlHandler) KickWSClient(c *gin.Context) {
 	response.ToResponse(code.Success.WithDetails("Client kicked successfully"))
 }
 
+// GetIPBans retrieves currently banned client IPs (requires admin privileges)
+// @Summary Get banned IPs
+// @Description Get the IPs temporarily banned by the honeypot, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=[]ipfilter.Ban} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/ip_bans [get]
+func (h *AdminControlHandler) GetIPBans(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	cfg := h.App.Config()
+	uid := pkgapp.GetUID(c)
+
+	if uid == 0 {
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	if cfg.User.AdminUID != 0 && uid != int64(cfg.User.AdminUID) {
+		response.ToResponse(code.ErrorUserIsNotAdmin)
+		return
+	}
+
+	bans := []ipfilter.Ban{}
+	if f := h.App.IPFilter(); f != nil {
+		bans = f.Bans()
+	}
+	response.ToResponse(code.Success.WithData(bans))
+}
+
+// UnbanIP lifts a temporary IP ban (requires admin privileges)
+// @Summary Unban an IP
+// @Description Lift a temporary IP ban created by the honeypot, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Param ip path string true "Banned IP"
+// @Produce json
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/ip_ban/{ip} [delete]
+func (h *AdminControlHandler) UnbanIP(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	cfg := h.App.Config()
+	uid := pkgapp.GetUID(c)
+
+	if uid == 0 {
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	if cfg.User.AdminUID != 0 && uid != int64(cfg.User.AdminUID) {
+		response.ToResponse(code.ErrorUserIsNotAdmin)
+		return
+	}
+
+	ip := c.Param("ip")
+	if ip == "" {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails("ip is required"))
+		return
+	}
+
+	f := h.App.IPFilter()
+	if f == nil || !f.Unban(ip) {
+		response.ToResponse(code.Failed.WithDetails("IP is not banned"))
+		return
+	}
+
+	h.App.Logger().Info("admin unbanned IP", zap.String("ip", ip), zap.Int64("uid", uid))
+	response.ToResponse(code.Success.WithDetails("IP unbanned successfully"))
+}
+
 func (h *AdminControlHandler) downloadFile(ctx context.Context, url string, dest string) error {
 	client := &http.Client{
 		Timeout: 3 * time.Minute,
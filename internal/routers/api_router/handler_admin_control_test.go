@@ -206,3 +206,56 @@ func TestAdminControlHandler_UpdateConfig_InvalidExpiry(t *testing.T) {
 	assertResponseCode(t, w, code.ErrorInvalidParams.Code())
 	assert.Contains(t, w.Body.String(), "webguiLoginTokenExpiry format invalid")
 }
+
+func TestAdminControlHandler_UpdateBrandingConfig_Success(t *testing.T) {
+	handler, testApp, mockUserSvc := newTestAdminHandler()
+	cfg := testApp.Config()
+
+	tempFile, err := os.CreateTemp("", "config_test_*.yaml")
+	assert.NoError(t, err)
+	defer os.Remove(tempFile.Name())
+	cfg.File = tempFile.Name()
+
+	reqBody := `{"instanceName":" ACME Notes ","primaryColor":"#123456","loginMessage":"Authorized users only"}`
+	c, w := newAdminTestContext("POST", "/api/admin/config/branding", reqBody, 1)
+
+	handler.UpdateBrandingConfig(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertResponseCode(t, w, code.Success.Code())
+	assert.Equal(t, "ACME Notes", cfg.WebGUI.Branding.InstanceName)
+	assert.Equal(t, "#123456", cfg.WebGUI.Branding.PrimaryColor)
+
+	// The public WebGUI config exposes the branding to the login page
+	// 公开的 WebGUI 配置向登录页提供品牌设置
+	mockUserSvc.On("IsRegisterEnabled", mock.Anything).Return(false)
+	c, w = newAdminTestContext("GET", "/api/webgui/config", "", 0)
+	handler.Config(c)
+	var resp struct {
+		Data dto.AdminWebGUIConfig `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Authorized users only", resp.Data.Branding.LoginMessage)
+}
+
+func TestAdminControlHandler_UpdateBrandingConfig_InvalidColor(t *testing.T) {
+	handler, testApp, _ := newTestAdminHandler()
+
+	reqBody := `{"primaryColor":"red;}*{"}`
+	c, w := newAdminTestContext("POST", "/api/admin/config/branding", reqBody, 1)
+
+	handler.UpdateBrandingConfig(c)
+
+	assertResponseCode(t, w, code.ErrorInvalidParams.Code())
+	assert.Empty(t, testApp.Config().WebGUI.Branding.PrimaryColor)
+}
+
+func TestAdminControlHandler_UpdateBrandingConfig_Forbidden(t *testing.T) {
+	handler, _, _ := newTestAdminHandler()
+
+	c, w := newAdminTestContext("POST", "/api/admin/config/branding", `{"instanceName":"x"}`, 2)
+
+	handler.UpdateBrandingConfig(c)
+
+	assertResponseCode(t, w, code.ErrorUserIsNotAdmin.Code())
+}
@@ -0,0 +1,71 @@
+package api_router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// AdminFileGCHandler attachment garbage collection API router handler (admin only)
+// AdminFileGCHandler 附件垃圾回收 API 路由处理器（仅管理员）
+type AdminFileGCHandler struct {
+	*Handler
+}
+
+// NewAdminFileGCHandler creates AdminFileGCHandler instance
+// NewAdminFileGCHandler 创建 AdminFileGCHandler 实例
+func NewAdminFileGCHandler(a *app.App) *AdminFileGCHandler {
+	return &AdminFileGCHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// DryRun reports the orphaned attachments without removing anything
+// @Summary Attachment garbage collection dry run
+// @Description List the attachment folders that no file record references (soft deleted records still count) and which of them the daily garbage collection would remove after the grace period, without removing anything, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Param uid query int false "User ID, 0 checks every user"
+// @Success 200 {object} pkgapp.Res{data=dto.FileGCReportDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/file-gc [get]
+func (h *AdminFileGCHandler) DryRun(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.FileGCRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	ctx := c.Request.Context()
+	report, err := h.App.FileGCService.Run(ctx, params.UID, true)
+	if err != nil {
+		h.logError(ctx, "AdminFileGCHandler.DryRun", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(report))
+}
+
+// logError records error log with Trace ID
+// logError 记录带有 Trace ID 的错误日志
+func (h *AdminFileGCHandler) logError(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
@@ -0,0 +1,177 @@
+package api_router
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/jobqueue"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap"
+)
+
+// AdminJobHandler background job queue API router handler (admin only)
+// AdminJobHandler 后台任务队列 API 路由处理器（仅管理员）
+type AdminJobHandler struct {
+	*Handler
+}
+
+// NewAdminJobHandler creates AdminJobHandler instance
+// NewAdminJobHandler 创建 AdminJobHandler 实例
+func NewAdminJobHandler(a *app.App) *AdminJobHandler {
+	return &AdminJobHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// List returns the background job queue state and its jobs
+// @Summary List background jobs
+// @Description Return the state of the background job queue (folder binding, vault counts, link/property/word count indexing, note history, ...) with per-kind counters since the server started and the jobs that are not done: queued, running, waiting for a retry, or failed after the last attempt; requires admin privileges. Finished jobs are not kept.
+// @Description 返回后台任务队列（文件夹绑定、笔记库统计、链接/属性/字数索引、笔记历史等）的状态、服务启动以来按类型的计数，以及未完成的任务：排队中、运行中、等待重试或用完重试次数后失败的任务，需要管理员权限。已完成的任务不会保留。
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.JobListRequest false "Filter Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.JobQueueStatusDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/jobs [get]
+func (h *AdminJobHandler) List(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.JobListRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	queue := h.App.Jobs()
+	if queue == nil {
+		response.ToResponse(code.ErrorJobQueueDisabled)
+		return
+	}
+
+	metrics := queue.Metrics()
+	status := &dto.JobQueueStatusDTO{
+		Workers:   metrics.Workers,
+		QueueSize: metrics.QueueSize,
+		Queued:    metrics.Queued,
+		Running:   metrics.Running,
+		Retrying:  metrics.Retrying,
+		Failed:    metrics.Failed,
+		Kinds:     make([]*dto.JobKindMetricsDTO, 0, len(metrics.Kinds)),
+		Jobs:      jobDTOs(queue.Jobs(jobqueue.State(params.State))),
+	}
+	for _, k := range metrics.Kinds {
+		status.Kinds = append(status.Kinds, &dto.JobKindMetricsDTO{
+			Kind:       k.Kind,
+			Enqueued:   k.Enqueued,
+			Coalesced:  k.Coalesced,
+			Inline:     k.Inline,
+			Succeeded:  k.Succeeded,
+			Retried:    k.Retried,
+			Failed:     k.Failed,
+			DurationMs: k.TotalDuration.Milliseconds(),
+		})
+	}
+	response.ToResponse(code.Success.WithData(status))
+}
+
+// Requeue queues failed background jobs again
+// @Summary Requeue failed background jobs
+// @Description Queue failed background jobs again with fresh attempts, every failed job when ids is empty; requires admin privileges. Returns the requeued jobs.
+// @Description 以重置的重试次数重新排队失败的后台任务，ids 为空时重新排队全部失败任务，需要管理员权限。返回重新排队的任务。
+// @Tags System
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.JobRequeueRequest false "Jobs to Requeue"
+// @Success 200 {object} pkgapp.Res{data=[]dto.JobDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/jobs/requeue [post]
+func (h *AdminJobHandler) Requeue(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.JobRequeueRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	queue := h.App.Jobs()
+	if queue == nil {
+		response.ToResponse(code.ErrorJobQueueDisabled)
+		return
+	}
+
+	jobs, err := queue.Requeue(params.IDs...)
+	if err != nil {
+		switch {
+		case errors.Is(err, jobqueue.ErrJobNotFound):
+			response.ToResponse(code.ErrorJobNotFound.WithDetails(err.Error()))
+		case errors.Is(err, jobqueue.ErrJobNotFailed):
+			response.ToResponse(code.ErrorJobNotFailed.WithDetails(err.Error()))
+		default:
+			response.ToResponse(code.ErrorJobQueueDisabled.WithDetails(err.Error()))
+		}
+		return
+	}
+
+	if len(jobs) > 0 {
+		uid := pkgapp.GetUID(c)
+		ids := make([]string, 0, len(jobs))
+		for _, j := range jobs {
+			ids = append(ids, j.ID)
+		}
+		h.App.Logger().Info("admin requeued background jobs", zap.Strings("jobs", ids), zap.Int64("uid", uid))
+		h.audit(c, uid, domain.AuditActionJobRequeue, strings.Join(ids, ","), strconv.Itoa(len(ids))+" jobs")
+	}
+
+	response.ToResponse(code.Success.WithData(jobDTOs(jobs)))
+}
+
+// jobDTOs converts job snapshots into DTOs
+// jobDTOs 将任务快照转换为 DTO
+func jobDTOs(jobs []jobqueue.Job) []*dto.JobDTO {
+	list := make([]*dto.JobDTO, 0, len(jobs))
+	for _, j := range jobs {
+		list = append(list, &dto.JobDTO{
+			ID:         j.ID,
+			Kind:       j.Kind,
+			Key:        j.Key,
+			UID:        j.UID,
+			State:      string(j.State),
+			Attempts:   j.Attempts,
+			LastError:  j.LastError,
+			EnqueuedAt: timex.Time(j.EnqueuedAt),
+			StartedAt:  optionalTime(j.StartedAt),
+			FinishedAt: optionalTime(j.FinishedAt),
+			NextRunAt:  optionalTime(j.NextRunAt),
+		})
+	}
+	return list
+}
+
+// optionalTime returns nil for the zero time
+// optionalTime 零值时间返回 nil
+func optionalTime(t time.Time) *timex.Time {
+	if t.IsZero() {
+		return nil
+	}
+	tt := timex.Time(t)
+	return &tt
+}
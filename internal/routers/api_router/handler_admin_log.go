@@ -0,0 +1,259 @@
+package api_router
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"github.com/haierkeys/fast-note-sync-service/pkg/json"
+	"github.com/haierkeys/fast-note-sync-service/pkg/synctrace"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// logFollowKeepAlive interval of the comments keeping an idle log stream open through proxies
+// logFollowKeepAlive 空闲日志流发送注释以穿过代理保持连接的间隔
+const logFollowKeepAlive = 15 * time.Second
+
+// AdminLogHandler log viewer API router handler (admin only)
+// AdminLogHandler 日志查看 API 路由处理器（仅管理员）
+type AdminLogHandler struct {
+	*Handler
+}
+
+// NewAdminLogHandler creates AdminLogHandler instance
+// NewAdminLogHandler 创建 AdminLogHandler 实例
+func NewAdminLogHandler(a *app.App) *AdminLogHandler {
+	return &AdminLogHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// Query lists or follows the recent server log entries
+// @Summary Query server logs
+// @Description Query the recent server log entries kept in memory (log.buffer-size) by level, time, uid, module and message text, oldest first, requires admin privileges.
+// @Description With follow=true the response is a server-sent event stream: the matching entries first, then every new one as an event named log whose id is the sequence number; idle streams get a comment every 15 seconds.
+// @Description The stream ends with the request timeout; an EventSource reconnects by itself and the Last-Event-ID header resumes after the last entry received.
+// @Description 按级别、时间、uid、模块与消息文本查询内存中保留的最近服务器日志（log.buffer-size），按时间升序，需要管理员权限。
+// @Description follow=true 时以服务器推送事件流响应：先推送匹配的条目，再将每条新条目作为名为 log、id 为序号的事件推送；空闲时每 15 秒发送一条注释。
+// @Description 事件流随请求超时结束；EventSource 会自动重连，并通过 Last-Event-ID 请求头从最后收到的条目之后继续。
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Produce text/event-stream
+// @Param params query dto.LogQueryRequest false "Filter Parameters"
+// @Success 200 {object} pkgapp.Res{data=[]dto.LogEntryDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/logs [get]
+func (h *AdminLogHandler) Query(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.LogQueryRequest{}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+	cfg := h.App.Config()
+	if cfg.User.AdminUID != 0 && uid != int64(cfg.User.AdminUID) {
+		response.ToResponse(code.ErrorUserIsNotAdmin)
+		return
+	}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	if params.Follow {
+		h.follow(c, params)
+		return
+	}
+
+	entries, err := h.App.LogService.Query(params)
+	if err != nil {
+		h.logError(c, "AdminLogHandler.Query", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(entries))
+}
+
+// follow streams the matching entries as server-sent events until the client goes away
+// follow 以服务器推送事件流推送匹配的条目，直到客户端断开
+func (h *AdminLogHandler) follow(c *gin.Context, params *dto.LogQueryRequest) {
+	// A reconnecting EventSource resumes after the last entry it received
+	// 重连的 EventSource 从最后收到的条目之后继续
+	if lastID, err := strconv.ParseUint(c.GetHeader("Last-Event-ID"), 10, 64); err == nil && lastID > params.AfterSeq {
+		params.AfterSeq = lastID
+	}
+
+	started := false
+	err := h.App.LogService.Follow(c.Request.Context(), params, logFollowKeepAlive, func(entry *dto.LogEntryDTO) error {
+		if !started {
+			started = true
+			c.Header("Content-Type", "text/event-stream")
+			c.Header("Cache-Control", "no-cache")
+			c.Header("Connection", "keep-alive")
+			c.Header("X-Accel-Buffering", "no") // Disable proxy buffering / 禁用代理缓冲
+			c.Status(200)
+		}
+
+		var err error
+		if entry == nil {
+			_, err = fmt.Fprint(c.Writer, ": keep-alive\n\n")
+		} else {
+			data, marshalErr := json.Marshal(entry)
+			if marshalErr != nil {
+				return marshalErr
+			}
+			_, err = fmt.Fprintf(c.Writer, "id: %d\nevent: log\ndata: %s\n\n", entry.Seq, data)
+		}
+		if err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil && !started {
+		h.logError(c, "AdminLogHandler.follow", err)
+		apperrors.ErrorResponse(c, err)
+	}
+}
+
+// SyncTraces lists the active per-user sync traces
+// @Summary List sync traces
+// @Description List the users whose sync is being traced to a separate file, requires admin privileges
+// @Description 列出同步正被跟踪写入独立文件的用户，需要管理员权限
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=[]dto.SyncTraceDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/sync-trace [get]
+func (h *AdminLogHandler) SyncTraces(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	traces := []*dto.SyncTraceDTO{}
+	for _, session := range h.App.SyncTracer().List() {
+		traces = append(traces, syncTraceDTO(session))
+	}
+	response.ToResponse(code.Success.WithData(traces))
+}
+
+// StartSyncTrace switches on the sync tracing of a user
+// @Summary Start sync tracing for a user
+// @Description Write every WebSocket message, UpdateCheck decision and broadcast fan-out of one user to a separate file under log.sync-trace-dir until the trace expires, for debugging user-specific sync loops; requires admin privileges. Starting a traced user again restarts the trace with the new level and duration. Traces live in the memory of this instance and stop with the server.
+// @Description 将某个用户的每条 WebSocket 消息、UpdateCheck 判定与广播扇出写入 log.sync-trace-dir 下的独立文件，直到跟踪过期，用于排查特定用户的同步循环，需要管理员权限。对已在跟踪的用户再次开启时以新的级别与时长重新开始。跟踪保存在本实例内存中，随服务停止而结束。
+// @Tags System
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.SyncTraceRequest true "Trace Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.SyncTraceDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/sync-trace [post]
+func (h *AdminLogHandler) StartSyncTrace(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.SyncTraceRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	level := zapcore.DebugLevel
+	if params.Level == "info" {
+		level = zapcore.InfoLevel
+	}
+	minutes := params.Minutes
+	if minutes == 0 {
+		minutes = 30
+	}
+
+	session, err := h.App.SyncTracer().Enable(params.UID, level, time.Duration(minutes)*time.Minute)
+	if err != nil {
+		h.logError(c, "AdminLogHandler.StartSyncTrace", err)
+		response.ToResponse(code.ErrorSyncTraceFailed.WithDetails(err.Error()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	h.App.Logger().Info("admin started sync trace", zap.Int64("target", params.UID), zap.Stringer("level", level), zap.Int("minutes", minutes), zap.Int64("uid", uid))
+	h.audit(c, uid, domain.AuditActionSyncTrace, strconv.FormatInt(params.UID, 10), fmt.Sprintf("on %s %dm", level, minutes))
+
+	response.ToResponse(code.Success.WithData(syncTraceDTO(session)))
+}
+
+// StopSyncTrace switches off the sync tracing of a user
+// @Summary Stop sync tracing for a user
+// @Description Stop the sync trace of a user before it expires, the trace file is kept; requires admin privileges
+// @Description 在过期前停止用户的同步跟踪，跟踪文件会保留，需要管理员权限
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.SyncTraceStopRequest true "Traced User"
+// @Success 200 {object} pkgapp.Res{data=bool} "Whether a trace was running"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/sync-trace [delete]
+func (h *AdminLogHandler) StopSyncTrace(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.SyncTraceStopRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	stopped := h.App.SyncTracer().Disable(params.UID)
+	if stopped {
+		uid := pkgapp.GetUID(c)
+		h.App.Logger().Info("admin stopped sync trace", zap.Int64("target", params.UID), zap.Int64("uid", uid))
+		h.audit(c, uid, domain.AuditActionSyncTrace, strconv.FormatInt(params.UID, 10), "off")
+	}
+
+	response.ToResponse(code.Success.WithData(stopped))
+}
+
+// syncTraceDTO converts an active trace into its DTO
+// syncTraceDTO 将进行中的跟踪转换为 DTO
+func syncTraceDTO(session synctrace.Session) *dto.SyncTraceDTO {
+	return &dto.SyncTraceDTO{
+		UID:       session.UID,
+		Level:     session.Level.String(),
+		File:      session.File,
+		StartedAt: timex.Time(session.StartedAt),
+		ExpiresAt: timex.Time(session.ExpiresAt),
+	}
+}
+
+// logError records error log with Trace ID
+// logError 记录带有 Trace ID 的错误日志
+func (h *AdminLogHandler) logError(c *gin.Context, method string, err error) {
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", middleware.GetTraceID(c.Request.Context())),
+	)
+}
@@ -0,0 +1,190 @@
+package api_router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap"
+)
+
+// AdminMaintenanceHandler maintenance window API router handler (admin only)
+// AdminMaintenanceHandler 维护窗口 API 路由处理器（仅管理员）
+type AdminMaintenanceHandler struct {
+	*Handler
+}
+
+// NewAdminMaintenanceHandler creates AdminMaintenanceHandler instance
+// NewAdminMaintenanceHandler 创建 AdminMaintenanceHandler 实例
+func NewAdminMaintenanceHandler(a *app.App) *AdminMaintenanceHandler {
+	return &AdminMaintenanceHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// Status returns the maintenance window and the jobs queued for it
+// @Summary Get maintenance window status
+// @Description Get the open or next maintenance window and the heavy jobs (full backups, cleanup, upgrades) queued for it, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.MaintenanceStatusDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/maintenance [get]
+func (h *AdminMaintenanceHandler) Status(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	coordinator := h.App.Maintenance()
+	status := &dto.MaintenanceStatusDTO{
+		Enabled:  coordinator.Enabled(),
+		InWindow: coordinator.InWindow(),
+		Queued:   []*dto.MaintenanceJobDTO{},
+	}
+	if start, end := coordinator.NextWindow(); !start.IsZero() {
+		windowStart, windowEnd := timex.Time(start), timex.Time(end)
+		status.WindowStart, status.WindowEnd = &windowStart, &windowEnd
+	}
+	for _, job := range coordinator.Queued() {
+		status.Queued = append(status.Queued, &dto.MaintenanceJobDTO{
+			ID:          job.ID,
+			Kind:        job.Kind,
+			Key:         job.Key,
+			Description: job.Description,
+			UID:         job.UID,
+			QueuedAt:    timex.Time(job.QueuedAt),
+		})
+	}
+
+	response.ToResponse(code.Success.WithData(status))
+}
+
+// Run forces queued jobs to run now
+// @Summary Run queued maintenance jobs now
+// @Description Start the selected queued jobs (all of them when ids is empty) without waiting for the maintenance window, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.MaintenanceRunRequest false "Job IDs"
+// @Success 200 {object} pkgapp.Res{data=[]string} "IDs of the started jobs"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/maintenance/run [post]
+func (h *AdminMaintenanceHandler) Run(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.MaintenanceRunRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	coordinator := h.App.Maintenance()
+	wanted := make(map[string]bool, len(params.IDs))
+	for _, id := range params.IDs {
+		wanted[id] = true
+	}
+	ids := []string{}
+	for _, job := range coordinator.Queued() {
+		if len(wanted) == 0 || wanted[job.ID] {
+			ids = append(ids, job.ID)
+		}
+	}
+	if len(ids) == 0 {
+		response.ToResponse(code.Success.WithData(ids).WithDetails("No queued jobs to run"))
+		return
+	}
+
+	// Heavy jobs outlive the request, they run in the background one after another
+	// 重型任务耗时超过请求生命周期，在后台依次执行
+	h.App.Logger().Info("admin forced maintenance jobs", zap.Strings("ids", ids), zap.Int64("uid", pkgapp.GetUID(c)))
+	safego.Go(h.App.Logger(), func() {
+		coordinator.RunNow(context.Background(), ids)
+	})
+
+	response.ToResponse(code.Success.WithData(ids))
+}
+
+// ReadOnlyStatus returns the read-only maintenance mode state
+// @Summary Get read-only mode status
+// @Description Get whether the server refuses changes, set by the admin or held by a running upgrade, restore or vault transfer, requires admin privileges
+// @Description 获取服务器是否拒绝修改（由管理员开启，或由正在进行的升级、恢复、保险库转移持有），需要管理员权限
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.ReadOnlyStatusDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/read-only [get]
+func (h *AdminMaintenanceHandler) ReadOnlyStatus(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(h.readOnlyStatus()))
+}
+
+// SetReadOnly switches the read-only maintenance mode
+// @Summary Switch read-only mode
+// @Description Switch the server into or out of read-only maintenance mode, requires admin privileges. Clients keep connecting and downloading, every change over the API, WebSocket and WebDAV is refused with a maintenance code and kept by the client until the mode ends. The admin API stays writable. The server remains read-only while an upgrade, restore or vault transfer holds it.
+// @Description 切换服务器的只读维护模式，需要管理员权限。客户端仍可连接与下载，通过 API、WebSocket、WebDAV 的所有修改均以维护错误码拒绝，由客户端保留至维护结束。管理 API 仍可写。升级、恢复或保险库转移进行中时服务器保持只读。
+// @Tags System
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.ReadOnlyRequest true "Read-only Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.ReadOnlyStatusDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Parameters"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/read-only [post]
+func (h *AdminMaintenanceHandler) SetReadOnly(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.ReadOnlyRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	h.App.ReadOnly().Set(params.Enabled, params.Reason)
+	h.App.Logger().Info("admin switched read-only mode", zap.Bool("enabled", params.Enabled), zap.Int64("uid", uid))
+	detail := "off"
+	if params.Enabled {
+		detail = "on"
+	}
+	h.audit(c, uid, domain.AuditActionReadOnly, "server", detail)
+
+	response.ToResponse(code.Success.WithData(h.readOnlyStatus()))
+}
+
+// readOnlyStatus converts the read-only mode state into its DTO
+// readOnlyStatus 将只读模式状态转换为 DTO
+func (h *AdminMaintenanceHandler) readOnlyStatus() *dto.ReadOnlyStatusDTO {
+	state := h.App.ReadOnly().Status()
+	status := &dto.ReadOnlyStatusDTO{
+		Enabled: state.Enabled,
+		Manual:  state.Manual,
+		Reason:  state.Reason,
+		Holds:   state.Holds,
+	}
+	if !state.Since.IsZero() {
+		since := timex.Time(state.Since)
+		status.Since = &since
+	}
+	return status
+}
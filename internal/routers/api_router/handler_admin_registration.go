@@ -0,0 +1,240 @@
+package api_router
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// AdminRegistrationHandler invitation code and registration approval API router handler (admin only)
+// AdminRegistrationHandler 邀请码与注册审核 API 路由处理器（仅管理员）
+type AdminRegistrationHandler struct {
+	*Handler
+}
+
+// NewAdminRegistrationHandler creates AdminRegistrationHandler instance
+// NewAdminRegistrationHandler 创建 AdminRegistrationHandler 实例
+func NewAdminRegistrationHandler(a *app.App) *AdminRegistrationHandler {
+	return &AdminRegistrationHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// ListInvites lists the invitation codes
+// @Summary List invitation codes
+// @Description List the registration invitation codes with their uses and expiry, newest first, requires admin privileges
+// @Description 按时间倒序列出注册邀请码及其使用次数与过期时间，需要管理员权限
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=[]dto.UserInviteDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/users/invites [get]
+func (h *AdminRegistrationHandler) ListInvites(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	ctx := c.Request.Context()
+	invites, err := h.App.RegistrationService.ListInvites(ctx)
+	if err != nil {
+		h.logError(ctx, "AdminRegistrationHandler.ListInvites", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(invites))
+}
+
+// CreateInvite generates an invitation code
+// @Summary Create invitation code
+// @Description Generate an invitation code admitting a limited number of registrations until it expires; it works while open registration is off and skips the approval queue, requires admin privileges
+// @Description 生成可注册有限次数且到期失效的邀请码；开放注册关闭时同样可用，且无需审核，需要管理员权限
+// @Tags User
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.UserInviteCreateRequest true "Invitation Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.UserInviteDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Parameters"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/users/invites [post]
+func (h *AdminRegistrationHandler) CreateInvite(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.UserInviteCreateRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	uid := h.checkAdmin(c, response)
+	if uid == 0 {
+		return
+	}
+
+	ctx := c.Request.Context()
+	invite, err := h.App.RegistrationService.CreateInvite(ctx, uid, params)
+	if err != nil {
+		h.logError(ctx, "AdminRegistrationHandler.CreateInvite", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessCreate.WithData(invite))
+}
+
+// DeleteInvite deletes an invitation code
+// @Summary Delete invitation code
+// @Description Delete an invitation code so it admits no further registrations, requires admin privileges
+// @Description 删除邀请码，使其不再可用于注册，需要管理员权限
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.UserInviteDeleteRequest true "Invitation ID"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 400 {object} pkgapp.Res "Invitation Not Found"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/users/invites [delete]
+func (h *AdminRegistrationHandler) DeleteInvite(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.UserInviteDeleteRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.App.RegistrationService.DeleteInvite(ctx, params.ID); err != nil {
+		h.logError(ctx, "AdminRegistrationHandler.DeleteInvite", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessDelete)
+}
+
+// ListPending lists the registrations waiting for approval
+// @Summary List pending registrations
+// @Description List the registrations waiting for approval when user.register-approval is on, oldest first, requires admin privileges
+// @Description 开启 user.register-approval 时按时间正序列出等待审核的注册，需要管理员权限
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Param pagination query pkgapp.PaginationRequest false "Pagination Parameters"
+// @Success 200 {object} pkgapp.Res{data=pkgapp.ListRes{list=[]dto.PendingUserDTO}} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/users/pending [get]
+func (h *AdminRegistrationHandler) ListPending(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	ctx := c.Request.Context()
+	pager := pkgapp.NewPager(c)
+	list, total, err := h.App.RegistrationService.ListPending(ctx, pager)
+	if err != nil {
+		h.logError(ctx, "AdminRegistrationHandler.ListPending", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponseList(code.Success, list, int(total))
+}
+
+// ApprovePending creates the account of a pending registration
+// @Summary Approve pending registration
+// @Description Create the account of a registration waiting for approval, requires admin privileges
+// @Description 为等待审核的注册创建账号，需要管理员权限
+// @Tags User
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.PendingUserRequest true "Pending Registration ID"
+// @Success 200 {object} pkgapp.Res{data=dto.UserDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Pending Registration Not Found / User Already Exists"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/users/pending/approve [post]
+func (h *AdminRegistrationHandler) ApprovePending(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.PendingUserRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	uid := h.checkAdmin(c, response)
+	if uid == 0 {
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := h.App.RegistrationService.Approve(ctx, params.ID)
+	if err != nil {
+		h.logError(ctx, "AdminRegistrationHandler.ApprovePending", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+	h.audit(c, uid, domain.AuditActionUserApprove, strconv.FormatInt(user.UID, 10), user.Username)
+
+	response.ToResponse(code.Success.WithData(user))
+}
+
+// RejectPending drops a pending registration
+// @Summary Reject pending registration
+// @Description Drop a registration waiting for approval without creating the account, requires admin privileges
+// @Description 丢弃等待审核的注册，不创建账号，需要管理员权限
+// @Tags User
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.PendingUserRequest true "Pending Registration ID"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 400 {object} pkgapp.Res "Pending Registration Not Found"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/users/pending/reject [post]
+func (h *AdminRegistrationHandler) RejectPending(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.PendingUserRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.App.RegistrationService.Reject(ctx, params.ID); err != nil {
+		h.logError(ctx, "AdminRegistrationHandler.RejectPending", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success)
+}
+
+// logError records error log with Trace ID
+// logError 记录带有 Trace ID 的错误日志
+func (h *AdminRegistrationHandler) logError(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
@@ -0,0 +1,144 @@
+package api_router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// AdminReindexHandler background full-text index rebuild API router handler (admin only)
+// AdminReindexHandler 后台全文索引重建 API 路由处理器（仅管理员）
+type AdminReindexHandler struct {
+	*Handler
+}
+
+// NewAdminReindexHandler creates AdminReindexHandler instance
+// NewAdminReindexHandler 创建 AdminReindexHandler 实例
+func NewAdminReindexHandler(a *app.App) *AdminReindexHandler {
+	return &AdminReindexHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// List returns reindex job progress
+// @Summary Get full-text reindex jobs
+// @Description Get the latest background full-text index rebuild job of every user, or of one user when uid is given, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Param uid query int false "User ID"
+// @Success 200 {object} pkgapp.Res{data=[]dto.ReindexJobDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/reindex [get]
+func (h *AdminReindexHandler) List(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.ReindexRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if params.UID != 0 {
+		job, err := h.App.ReindexService.Get(ctx, params.UID)
+		if err != nil {
+			apperrors.ErrorResponse(c, err)
+			return
+		}
+		response.ToResponse(code.Success.WithData([]*dto.ReindexJobDTO{job}))
+		return
+	}
+
+	jobs, err := h.App.ReindexService.List(ctx)
+	if err != nil {
+		h.logError(ctx, "AdminReindexHandler.List", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+	response.ToResponse(code.Success.WithData(jobs))
+}
+
+// Start queues full-text index rebuilds
+// @Summary Start full-text reindex
+// @Description Queue a background rebuild of the full-text index of all vaults of a user, or of every user when uid is 0. A user with an active job keeps it. Requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.ReindexRequest false "User ID"
+// @Success 200 {object} pkgapp.Res{data=[]dto.ReindexJobDTO} "Queued jobs"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/reindex [post]
+func (h *AdminReindexHandler) Start(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.ReindexRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	ctx := c.Request.Context()
+	jobs, err := h.App.ReindexService.Start(ctx, params.UID)
+	if err != nil {
+		h.logError(ctx, "AdminReindexHandler.Start", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	h.App.Logger().Info("admin started fts reindex", zap.Int64("target", params.UID), zap.Int64("uid", pkgapp.GetUID(c)))
+	response.ToResponse(code.Success.WithData(jobs))
+}
+
+// Cancel cancels a user's reindex job
+// @Summary Cancel full-text reindex
+// @Description Cancel the queued or running full-text index rebuild job of a user, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.ReindexRequest true "User ID"
+// @Success 200 {object} pkgapp.Res{data=dto.ReindexJobDTO} "Canceled job"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/reindex/cancel [post]
+func (h *AdminReindexHandler) Cancel(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.ReindexRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	job, err := h.App.ReindexService.Cancel(c.Request.Context(), params.UID)
+	if err != nil {
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+	response.ToResponse(code.Success.WithData(job))
+}
+
+func (h *AdminReindexHandler) logError(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
@@ -0,0 +1,183 @@
+package api_router
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/schedule"
+	"go.uber.org/zap"
+)
+
+// AdminScheduleHandler scheduled task API router handler (admin only)
+// AdminScheduleHandler 定时任务 API 路由处理器（仅管理员）
+type AdminScheduleHandler struct {
+	*Handler
+}
+
+// NewAdminScheduleHandler creates AdminScheduleHandler instance
+// NewAdminScheduleHandler 创建 AdminScheduleHandler 实例
+func NewAdminScheduleHandler(a *app.App) *AdminScheduleHandler {
+	return &AdminScheduleHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// List returns the schedule and last run of every scheduled task
+// @Summary List scheduled tasks
+// @Description List the scheduled tasks (DbCleanup, LocalSnapshot, BackupScheduled, ...) with their built-in and effective schedules, last run and next run, requires admin privileges. In a cluster only the instance running the tasks lists them.
+// @Description 列出定时任务（DbCleanup、LocalSnapshot、BackupScheduled 等）的内置调度与生效调度、上次执行与下次执行情况，需要管理员权限。集群部署时只有执行任务的实例会列出任务。
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=[]dto.ScheduleDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/schedules [get]
+func (h *AdminScheduleHandler) List(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	list := []*dto.ScheduleDTO{}
+	for _, status := range h.App.Schedules().List() {
+		list = append(list, scheduleDTO(status))
+	}
+	response.ToResponse(code.Success.WithData(list))
+}
+
+// Update changes the schedule of a task
+// @Summary Update a task schedule
+// @Description Replace the built-in interval of a task with a 5-field cron expression (server local time), a descriptor such as @daily or @every 90m, or off to stop its scheduled runs; an empty schedule restores the built-in interval. The schedule is saved to task.schedules in the configuration file and takes effect right away; requires admin privileges.
+// @Description 用 5 段 cron 表达式（服务器本地时间）、@daily 或 @every 90m 等描述符替换任务的内置执行间隔，off 停止定时执行；为空时恢复内置间隔。调度保存到配置文件的 task.schedules 并立即生效，需要管理员权限。
+// @Tags System
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.ScheduleUpdateRequest true "Task Schedule"
+// @Success 200 {object} pkgapp.Res{data=dto.ScheduleDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/schedules [put]
+func (h *AdminScheduleHandler) Update(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.ScheduleUpdateRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	if _, err := h.App.Schedules().Status(params.Name); err != nil {
+		response.ToResponse(code.ErrorScheduleTaskNotFound.WithDetails(params.Name))
+		return
+	}
+	spec := strings.TrimSpace(params.Schedule)
+	if spec != "" {
+		if _, err := schedule.Parse(spec); err != nil {
+			response.ToResponse(code.ErrorScheduleInvalid.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	// Replace the map instead of editing it, readers of the previous configuration keep a consistent copy
+	// 替换而非修改 map，读取旧配置的调用方保留一致的副本
+	cfg := h.App.Config()
+	previous := cfg.Task.Schedules
+	schedules := make(map[string]string, len(previous)+1)
+	for name, s := range previous {
+		schedules[name] = s
+	}
+	if spec == "" {
+		delete(schedules, params.Name)
+	} else {
+		schedules[params.Name] = spec
+	}
+	cfg.Task.Schedules = schedules
+
+	if err := h.App.SaveConfig(); err != nil {
+		h.App.Logger().Error("apiRouter.AdminSchedule.Update.Save err", zap.Error(err))
+		cfg.Task.Schedules = previous
+		response.ToResponse(code.ErrorConfigSaveFailed)
+		return
+	}
+	uid := pkgapp.GetUID(c)
+	h.audit(c, uid, domain.AuditActionConfigUpdate, "config/task/schedules", params.Name+"="+spec)
+
+	status, _ := h.App.Schedules().Status(params.Name)
+	response.ToResponse(code.Success.WithData(scheduleDTO(status)))
+}
+
+// Run runs a task by hand
+// @Summary Run a scheduled task now
+// @Description Run a task right away, outside its schedule; heavy tasks run even outside the maintenance window. The request returns once the run started, GET /api/admin/schedules shows its outcome; requires admin privileges.
+// @Description 立即执行任务，不受调度限制；重型任务在维护窗口外也会执行。请求在执行开始后返回，可通过 GET /api/admin/schedules 查看结果，需要管理员权限。
+// @Tags System
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.ScheduleRunRequest true "Task"
+// @Success 200 {object} pkgapp.Res{data=dto.ScheduleDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/schedules/run [post]
+func (h *AdminScheduleHandler) Run(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.ScheduleRunRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	if err := h.App.Schedules().Trigger(params.Name); err != nil {
+		switch {
+		case errors.Is(err, schedule.ErrTaskRunning):
+			response.ToResponse(code.ErrorScheduleTaskRunning.WithDetails(params.Name))
+		default:
+			response.ToResponse(code.ErrorScheduleTaskNotFound.WithDetails(params.Name))
+		}
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	h.App.Logger().Info("admin ran scheduled task", zap.String("name", params.Name), zap.Int64("uid", uid))
+	h.audit(c, uid, domain.AuditActionTaskRun, params.Name, "")
+
+	status, _ := h.App.Schedules().Status(params.Name)
+	response.ToResponse(code.Success.WithData(scheduleDTO(status)))
+}
+
+// scheduleDTO converts a task status into its DTO
+// scheduleDTO 将任务状态转换为 DTO
+func scheduleDTO(status schedule.Status) *dto.ScheduleDTO {
+	return &dto.ScheduleDTO{
+		Name:           status.Name,
+		Default:        status.Default,
+		Schedule:       status.Schedule,
+		Custom:         status.Custom,
+		StartupRun:     status.StartupRun,
+		Heavy:          status.Heavy,
+		Running:        status.Running,
+		Runs:           status.Runs,
+		Failures:       status.Failures,
+		LastTrigger:    string(status.LastTrigger),
+		LastStartedAt:  optionalTime(status.LastStartedAt),
+		LastFinishedAt: optionalTime(status.LastFinishedAt),
+		LastDurationMs: status.LastDuration.Milliseconds(),
+		LastResult:     string(status.LastResult),
+		LastError:      status.LastError,
+		NextRunAt:      optionalTime(status.NextRunAt),
+	}
+}
@@ -0,0 +1,182 @@
+package api_router
+
+import (
+	"context"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// AdminSnapshotHandler local snapshot API router handler (admin only)
+// AdminSnapshotHandler 本地快照 API 路由处理器（仅管理员）
+type AdminSnapshotHandler struct {
+	*Handler
+}
+
+// NewAdminSnapshotHandler creates AdminSnapshotHandler instance
+// NewAdminSnapshotHandler 创建 AdminSnapshotHandler 实例
+func NewAdminSnapshotHandler(a *app.App) *AdminSnapshotHandler {
+	return &AdminSnapshotHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// List lists local snapshots
+// @Summary List local snapshots
+// @Description List the local snapshots of the databases and content folders, newest first, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=[]dto.SnapshotDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/snapshots [get]
+func (h *AdminSnapshotHandler) List(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	snapshots, err := h.App.SnapshotService.List(c.Request.Context())
+	if err != nil {
+		h.logError(c.Request.Context(), "AdminSnapshotHandler.List", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(snapshots))
+}
+
+// Create takes a local snapshot now
+// @Summary Take a local snapshot
+// @Description Snapshot every SQLite database and the content folders now and rotate old snapshots, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.SnapshotDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Failure 500 {object} pkgapp.Res "Snapshot failed or already running"
+// @Router /api/admin/snapshots [post]
+func (h *AdminSnapshotHandler) Create(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	snapshot, err := h.App.SnapshotService.Create(c.Request.Context())
+	if err != nil {
+		h.logError(c.Request.Context(), "AdminSnapshotHandler.Create", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(snapshot))
+}
+
+// Restore restores a local snapshot
+// @Summary Restore a local snapshot
+// @Description Schedule the snapshot to be restored and restart the server; the replaced data is kept in a pre-restore directory next to the snapshots, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.SnapshotRestoreRequest true "Snapshot name"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Failure 500 {object} pkgapp.Res "Snapshot not found"
+// @Router /api/admin/snapshots/restore [post]
+func (h *AdminSnapshotHandler) Restore(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.SnapshotRestoreRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	if err := h.App.SnapshotService.Restore(c.Request.Context(), params.Name); err != nil {
+		h.logError(c.Request.Context(), "AdminSnapshotHandler.Restore", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	// The databases can only be replaced while closed, the restore runs during the restart
+	// 数据库只能在关闭状态下替换，恢复在重启过程中执行
+	currentBinary, err := os.Executable()
+	if err != nil {
+		response.ToResponse(code.Failed.WithDetails("Failed to get current executable path: " + err.Error()))
+		return
+	}
+	h.App.TriggerUpgrade(currentBinary)
+
+	response.ToResponse(code.Success.WithDetails("Snapshot restore scheduled, server is restarting..."))
+}
+
+// DatabaseExport shows the hot database export
+// @Summary Get the hot database export
+// @Description List the transaction-consistent database copies in the export directory that host backup tools should back up, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.DatabaseExportDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/db-export [get]
+func (h *AdminSnapshotHandler) DatabaseExport(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	export, err := h.App.SnapshotService.DatabaseExport(c.Request.Context())
+	if err != nil {
+		h.logError(c.Request.Context(), "AdminSnapshotHandler.DatabaseExport", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(export))
+}
+
+// ExportDatabases refreshes the hot database export now
+// @Summary Refresh the hot database export
+// @Description Copy every SQLite database into the export directory with VACUUM INTO now, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.DatabaseExportDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Failure 500 {object} pkgapp.Res "Export failed or already running"
+// @Router /api/admin/db-export [post]
+func (h *AdminSnapshotHandler) ExportDatabases(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	if h.checkAdmin(c, response) == 0 {
+		return
+	}
+
+	export, err := h.App.SnapshotService.ExportDatabases(c.Request.Context())
+	if err != nil {
+		h.logError(c.Request.Context(), "AdminSnapshotHandler.ExportDatabases", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(export))
+}
+
+func (h *AdminSnapshotHandler) logError(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
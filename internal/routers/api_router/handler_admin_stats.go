@@ -0,0 +1,60 @@
+package api_router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// GetStats retrieves usage statistics for the admin dashboard (requires admin privileges)
+// @Summary Get admin dashboard statistics
+// @Description Per user note/file counts and storage bytes, active WebSocket connections, sync events per hour and backup success rates over 24h, 7d and 30d, requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.AdminStatsRequest false "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.AdminStatsDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/stats [get]
+func (h *AdminControlHandler) GetStats(c *gin.Context) {
+	params := &dto.AdminStatsRequest{}
+	response := pkgapp.NewResponse(c)
+	cfg := h.App.Config()
+	logger := h.App.Logger()
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		logger.Error("apiRouter.AdminControl.GetStats err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	if cfg.User.AdminUID != 0 && uid != int64(cfg.User.AdminUID) {
+		response.ToResponse(code.ErrorUserIsNotAdmin)
+		return
+	}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		logger.Error("apiRouter.AdminControl.GetStats.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	var connections map[int64]int
+	if h.wss != nil {
+		connections = h.wss.ConnectionsByUser()
+	}
+
+	stats, err := h.App.AdminStatsService.Stats(c.Request.Context(), params, connections)
+	if err != nil {
+		logger.Error("apiRouter.AdminControl.GetStats.Stats err", zap.Error(err))
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(stats))
+}
@@ -0,0 +1,45 @@
+package api_router
+
+import (
+	"github.com/gin-gonic/gin"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// GetStorageUsage retrieves the disk usage of every user (requires admin privileges)
+// @Summary Get storage usage
+// @Description Per user bytes on disk for note content files, attachments, history snapshots, settings, in-progress backups and the SQLite databases, with the content size of each vault, largest user first. The numbers are kept up to date incrementally instead of walking the storage on every call. Requires admin privileges
+// @Tags System
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.StorageUsageDTO} "Success"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/storage-usage [get]
+func (h *AdminControlHandler) GetStorageUsage(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	cfg := h.App.Config()
+	logger := h.App.Logger()
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		logger.Error("apiRouter.AdminControl.GetStorageUsage err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	if cfg.User.AdminUID != 0 && uid != int64(cfg.User.AdminUID) {
+		response.ToResponse(code.ErrorUserIsNotAdmin)
+		return
+	}
+
+	usage, err := h.App.UsageService.All(c.Request.Context())
+	if err != nil {
+		logger.Error("apiRouter.AdminControl.GetStorageUsage err", zap.Error(err))
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(usage))
+}
@@ -0,0 +1,89 @@
+package api_router
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// AdminVaultTransferHandler vault re-ownership API router handler (admin only)
+// AdminVaultTransferHandler 保险库所有权转移 API 路由处理器（仅管理员）
+type AdminVaultTransferHandler struct {
+	*Handler
+}
+
+// NewAdminVaultTransferHandler creates AdminVaultTransferHandler instance
+// NewAdminVaultTransferHandler 创建 AdminVaultTransferHandler 实例
+func NewAdminVaultTransferHandler(a *app.App) *AdminVaultTransferHandler {
+	return &AdminVaultTransferHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// Transfer moves a vault from one user to another
+// @Summary Transfer vault to another user
+// @Description Move a vault with its notes, note history, attachments and client config from one user to another, e.g. when consolidating accounts, requires admin privileges. With dryRun nothing is changed and the report lists what would be moved and the paths an existing target vault has with other content. Conflicts abort the transfer unless overwrite is set. The source vault is deleted only after every entry arrived; a partly failed transfer keeps it and can be run again.
+// @Description 将保险库及其笔记、笔记历史、附件与客户端配置从一个用户转移给另一个用户（例如合并账号时），需要管理员权限。试运行不做任何修改，报告将要转移的内容以及已有目标保险库中内容不同的路径。
+// @Description 存在冲突时除非设置 overwrite，否则中止转移。仅在全部条目转移成功后删除源保险库；部分失败时保留源保险库，可重新运行。
+// @Tags Vault
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.VaultTransferRequest true "Transfer Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.VaultTransferReportDTO} "Success"
+// @Failure 400 {object} pkgapp.Res{data=dto.VaultTransferReportDTO} "Conflicts with the target vault"
+// @Failure 403 {object} pkgapp.Res "Insufficient privileges"
+// @Router /api/admin/vault/transfer [post]
+func (h *AdminVaultTransferHandler) Transfer(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultTransferRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+	uid := h.checkAdmin(c, response)
+	if uid == 0 {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if !params.DryRun {
+		// Keep clients from writing into either vault while it moves
+		// 转移期间阻止客户端写入源或目标保险库
+		release := h.App.ReadOnly().Hold("vault transfer")
+		defer release()
+	}
+	clientType, clientName, clientVersion := h.getClientInfo(c)
+	report, err := h.App.MigrateService.WithClient(clientType, clientName, clientVersion).TransferVault(ctx, params)
+	if err != nil {
+		h.logError(ctx, "AdminVaultTransferHandler.Transfer", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+	if report.Transferred {
+		h.audit(c, uid, domain.AuditActionVaultTransfer, fmt.Sprintf("%d/%s", params.FromUID, params.Vault),
+			fmt.Sprintf("to %d/%s", params.ToUID, report.TargetVault))
+	}
+
+	response.ToResponse(code.Success.WithData(report))
+}
+
+// logError records error log with Trace ID
+// logError 记录带有 Trace ID 的错误日志
+func (h *AdminVaultTransferHandler) logError(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
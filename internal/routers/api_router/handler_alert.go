@@ -0,0 +1,175 @@
+package api_router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// AlertHandler backup failure alert channel API router handler
+// AlertHandler 备份失败告警渠道 API 路由处理器
+type AlertHandler struct {
+	*Handler
+}
+
+// NewAlertHandler creates AlertHandler instance
+// NewAlertHandler 创建 AlertHandler 实例
+func NewAlertHandler(a *app.App) *AlertHandler {
+	return &AlertHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// GetChannels gets the alert channels of the current user
+// @Summary Get alert channels
+// @Tags Alert
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=[]dto.AlertChannelDTO} "Success"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/alert/channels [get]
+func (h *AlertHandler) GetChannels(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	list, err := h.App.AlertService.List(c.Request.Context(), uid)
+	if err != nil {
+		h.logError(c.Request.Context(), "AlertHandler.GetChannels", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(list))
+}
+
+// UpdateChannel creates or updates an alert channel
+// @Summary Create or update an alert channel
+// @Description Enabled channels are alerted when a backup task of the user fails. Types: email (needs a server-side SMTP server), telegram, gotify, ntfy.
+// @Tags Alert
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.AlertChannelRequest true "Alert Channel Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.AlertChannelDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/alert/channel [post]
+func (h *AlertHandler) UpdateChannel(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.AlertChannelRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	channel, err := h.App.AlertService.Save(c.Request.Context(), uid, params)
+	if err != nil {
+		h.logError(c.Request.Context(), "AlertHandler.UpdateChannel", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessUpdate.WithData(channel))
+}
+
+// DeleteChannel deletes an alert channel
+// @Summary Delete an alert channel
+// @Tags Alert
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.AlertChannelIDRequest true "Alert Channel ID"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/alert/channel [delete]
+func (h *AlertHandler) DeleteChannel(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.AlertChannelIDRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	if err := h.App.AlertService.Delete(c.Request.Context(), uid, params.ID); err != nil {
+		h.logError(c.Request.Context(), "AlertHandler.DeleteChannel", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessDelete)
+}
+
+// TestChannel sends a test alert through a channel
+// @Summary Send a test alert
+// @Tags Alert
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.AlertChannelIDRequest true "Alert Channel ID"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/alert/channel/test [post]
+func (h *AlertHandler) TestChannel(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.AlertChannelIDRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	if err := h.App.AlertService.Test(c.Request.Context(), uid, params.ID); err != nil {
+		h.logError(c.Request.Context(), "AlertHandler.TestChannel", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success)
+}
+
+// logError logs an error with the trace ID of the request
+// logError 记录带请求追踪 ID 的错误日志
+func (h *AlertHandler) logError(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
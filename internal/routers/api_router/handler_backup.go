@@ -2,9 +2,11 @@ package api_router
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
@@ -198,15 +200,55 @@ func (h *BackupHandler) Execute(c *gin.Context) {
 	}
 
 	err := h.App.BackupService.ExecuteUserBackup(c.Request.Context(), uid, params.ID)
+	target := strconv.FormatInt(params.ID, 10)
 	if err != nil {
 		h.logError(c.Request.Context(), "BackupHandler.Execute", err)
+		h.audit(c, uid, domain.AuditActionBackupExecute, target, err.Error())
 		apperrors.ErrorResponse(c, err)
 		return
 	}
+	h.audit(c, uid, domain.AuditActionBackupExecute, target, "")
 
 	response.ToResponse(code.Success.WithDetails("Backup task completed, check history for details"))
 }
 
+// Verify re-downloads a backup and checks it against the recorded size and checksum
+// @Summary Verify a backup's stored copy
+// @Description Re-downloads the archive (or dedupe manifest) of a successful backup history record and compares its size and SHA-256 with the recorded values; with extract the archive is also test-extracted
+// @Tags Backup
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.BackupVerifyRequest true "Backup Verify Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.BackupVerifyDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/backup/verify [post]
+func (h *BackupHandler) Verify(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.BackupVerifyRequest{}
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	result, err := h.App.BackupService.Verify(c.Request.Context(), uid, params.HistoryID, params.Extract)
+	if err != nil {
+		h.logError(c.Request.Context(), "BackupHandler.Verify", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(result))
+}
+
 func (h *BackupHandler) logError(ctx context.Context, method string, err error) {
 	traceID := middleware.GetTraceID(ctx)
 	h.App.Logger().Error(method,
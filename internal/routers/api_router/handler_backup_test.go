@@ -139,3 +139,20 @@ func TestBackupHandler_Execute_Success(t *testing.T) {
 	assertResponseCode(t, w, code.Success.Code())
 	mockSvc.AssertExpectations(t)
 }
+
+// TestBackupHandler_Verify_Success verifies a backup verification request is passed to the service
+func TestBackupHandler_Verify_Success(t *testing.T) {
+	mockSvc := new(svcmocks.MockBackupService)
+
+	mockSvc.On("Verify", mock.Anything, int64(1), int64(7), true).Return(&dto.BackupVerifyDTO{HistoryID: 7, Verified: true}, nil)
+
+	handler := newTestBackupHandler(mockSvc)
+	body := `{"historyId":7,"extract":true}`
+	c, w := newBackupTestContext("POST", "/api/backup/verify", body, 1)
+
+	handler.Verify(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertResponseCode(t, w, code.Success.Code())
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,110 @@
+package api_router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// DeviceHandler device management API router handler
+// DeviceHandler 设备管理 API 路由处理器
+type DeviceHandler struct {
+	*Handler
+}
+
+// NewDeviceHandler creates DeviceHandler instance
+// NewDeviceHandler 创建 DeviceHandler 实例
+func NewDeviceHandler(a *app.App) *DeviceHandler {
+	return &DeviceHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// List gets the devices of the current user
+// @Summary Get devices
+// @Description Lists every device that has connected over WebSocket, most recently seen first, with its client name, version, platform, last IP and whether it is connected now.
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=[]dto.DeviceDTO} "Success"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/user/devices [get]
+func (h *DeviceHandler) List(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	list, err := h.App.DeviceService.List(c.Request.Context(), uid)
+	if err != nil {
+		h.logError(c.Request.Context(), "DeviceHandler.List", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	if wss := h.App.GetWSS(); wss != nil {
+		online := wss.GetActiveDeviceKeys(uid)
+		for _, d := range list {
+			d.IsOnline = online[d.DeviceKey]
+		}
+	}
+
+	response.ToResponse(code.Success.WithData(list))
+}
+
+// Revoke revokes a device
+// @Summary Revoke a device
+// @Description Revokes the token the device authenticated with, closes its WebSocket connections and removes it from the list. Other devices sharing the same token are signed out as well.
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.DeviceRevokeRequest true "Device ID"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/user/device [delete]
+func (h *DeviceHandler) Revoke(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.DeviceRevokeRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	if err := h.App.DeviceService.Revoke(c.Request.Context(), uid, params.ID); err != nil {
+		h.logError(c.Request.Context(), "DeviceHandler.Revoke", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessDelete)
+}
+
+// logError logs an error with the trace ID of the request
+// logError 记录带请求追踪 ID 的错误日志
+func (h *DeviceHandler) logError(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
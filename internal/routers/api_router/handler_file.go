@@ -2,6 +2,7 @@ package api_router
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -10,11 +11,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"go.uber.org/zap"
 )
@@ -83,13 +86,17 @@ func (h *FileHandler) List(c *gin.Context) {
 
 // GetInfo retrieves raw content of file or note
 // @Summary Get attachment content
-// @Description Get raw binary data of an attachment by path, supports strong cache control
+// @Description Get raw binary data of an attachment by path, supports strong cache control and Range requests for seeking in audio and video or resuming downloads
 // @Tags File
 // @Security UserAuthToken
 // @Produce octet-stream
 // @Param params query dto.FileGetRequest true "Get Parameters"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
 // @Success 200 {file} binary "Success"
+// @Success 206 {file} binary "Partial Content"
+// @Success 304 "Not Modified"
 // @Router /api/file [get]
+// @Router /api/file [head]
 func (h *FileHandler) GetInfo(c *gin.Context) {
 	response := pkgapp.NewResponse(c)
 	params := &dto.FileGetRequest{}
@@ -126,25 +133,64 @@ func (h *FileHandler) GetInfo(c *gin.Context) {
 		return
 	}
 
-	// Open file for zero-copy serving
-	file, err := os.Open(savePath)
-	if err != nil {
+	// Stream the file with Range support so media can be scrubbed and downloads resumed
+	// 以支持 Range 的方式流式输出文件，使媒体可拖动进度、下载可续传
+	if err := pkgapp.ServeFile(c, savePath, fileName, contentType, etag, time.UnixMilli(mtime), pkgapp.AttachmentCacheControl); err != nil {
 		h.logError(ctx, "FileHandler.GetContent.Open", err)
 		c.AbortWithStatus(http.StatusNotFound)
+	}
+}
+
+// Thumbnail serves the thumbnail of an image attachment
+// @Summary Get attachment thumbnail
+// @Description Get a downscaled preview of an image attachment (JPEG, PNG, GIF, BMP, WebP). The smallest configured width not below the requested width is returned, thumbnails are rendered on first request and cached. Supports ETag revalidation.
+// @Tags File
+// @Security UserAuthToken
+// @Produce image/jpeg
+// @Produce image/png
+// @Param params query dto.FileThumbnailRequest true "Thumbnail Parameters"
+// @Success 200 {file} binary "Success"
+// @Success 304 "Not Modified"
+// @Router /api/file/thumbnail [get]
+func (h *FileHandler) Thumbnail(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.FileThumbnailRequest{}
+
+	// Parameter binding and validation
+	// 参数绑定和验证
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("FileHandler.Thumbnail.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("FileHandler.Thumbnail err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
 		return
 	}
-	defer file.Close()
 
-	// Set response headers
-	if contentType != "" {
-		c.Header("Content-Type", contentType)
+	if params.PathHash == "" {
+		params.PathHash = util.EncodeHash32(params.Path)
 	}
-	c.Header("Cache-Control", "public, s-maxage=31536000, max-age=31536000, must-revalidate")
-	if etag != "" {
-		c.Header("ETag", etag)
+
+	ctx := c.Request.Context()
+	thumb, err := h.App.ThumbnailService.Get(ctx, uid, params)
+	if err != nil {
+		h.logError(ctx, "FileHandler.Thumbnail", err)
+		apperrors.ErrorResponse(c, err)
+		return
 	}
 
-	http.ServeContent(c.Writer, c.Request, fileName, time.UnixMilli(mtime), file)
+	// Thumbnails are per user, shared caches must not keep them; the ETag lets clients revalidate cheaply
+	// 缩略图按用户区分，共享缓存不得保存；客户端可通过 ETag 低成本重新验证
+	cacheControl := fmt.Sprintf("private, max-age=%d, must-revalidate", int(thumb.CacheMaxAge.Seconds()))
+	if err := pkgapp.ServeFile(c, thumb.Path, thumb.Name, thumb.ContentType, thumb.ETag, thumb.ModTime, cacheControl); err != nil {
+		h.logError(ctx, "FileHandler.Thumbnail.Open", err)
+		c.AbortWithStatus(http.StatusNotFound)
+	}
 }
 
 // GetSharedContent retrieves shared file content
@@ -328,11 +374,22 @@ func (h *FileHandler) Restore(c *gin.Context) {
 		apperrors.ErrorResponse(c, err)
 		return
 	}
+	h.audit(c, uid, domain.AuditActionFileRestore, params.Vault+"/"+file.Path, "")
 
 	response.ToResponse(code.Success.WithData(file))
 	h.WSS.BroadcastToUser(uid, code.Success.WithData(file).WithVault(params.Vault), "FileSyncUpdate")
 }
 
+// prepareThumbnails renders the thumbnails of an uploaded attachment in the background
+// prepareThumbnails 在后台为上传的附件生成缩略图
+func (h *FileHandler) prepareThumbnails(uid int64, file *dto.FileDTO) {
+	safego.Go(h.App.Logger(), func() {
+		if err := h.App.ThumbnailService.Prepare(context.Background(), uid, file); err != nil {
+			h.App.Logger().Warn("FileHandler.prepareThumbnails err", zap.Int64("uid", uid), zap.String("path", file.Path), zap.Error(err))
+		}
+	})
+}
+
 // logError records error log, including Trace ID
 // logError 记录错误日志，包含 Trace ID
 func (h *FileHandler) logError(ctx context.Context, method string, err error) {
@@ -562,6 +619,10 @@ func (h *FileHandler) Upload(c *gin.Context) {
 
 	response.ToResponse(code.Success.WithData(fileDTO))
 
+	// Render thumbnails of uploaded images in the background
+	// 在后台为上传的图片生成缩略图
+	h.prepareThumbnails(uid, fileDTO)
+
 	// Broadcast WebSocket event: FileSyncUpdate
 	// 广播 WebSocket 事件: 文件同步更新
 	h.WSS.BroadcastToUser(uid, code.Success.WithData(
@@ -0,0 +1,629 @@
+package api_router
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+// defaultUploadSessionTimeout idle time after which an unfinished HTTP upload is discarded
+// defaultUploadSessionTimeout 未完成的 HTTP 上传在空闲多久后被丢弃
+const defaultUploadSessionTimeout = 20 * time.Minute
+
+// FileUploadHTTPSession state of a chunked HTTP upload.
+// It lives in the same per-user session store as WebSocket binary chunk uploads, so starting an upload
+// of the same path over either protocol replaces the other one. Chunks may arrive in any order and more
+// than once; every chunk is written at its own offset of the temp file.
+// FileUploadHTTPSession HTTP 分块上传的状态。
+// 与 WebSocket 二进制分块上传共用按用户划分的会话存储，任一协议开始上传同一路径时会替换另一方的会话。
+// 分块可以乱序、重复到达，每个分块写入临时文件中各自的偏移位置。
+type FileUploadHTTPSession struct {
+	ID          string // Session ID // 会话 ID
+	UID         int64  // Owner // 所属用户
+	Vault       string // Vault Name // 仓库名称
+	Path        string // File Path // 文件路径
+	PathHash    string // File Path Hash // 文件路径哈希值
+	ContentHash string // File Content Hash // 文件内容哈希值
+	Ctime       int64  // Creation time // 创建时间
+	Mtime       int64  // Modification time // 修改时间
+	Size        int64  // File size // 文件大小
+	ChunkSize   int64  // Chunk size // 分块大小
+	TotalChunks int64  // Total chunks // 总分块数
+	SavePath    string // Temp save path // 临时保存路径
+	CreatedAt   time.Time
+
+	mu            sync.Mutex
+	file          *os.File           // Temp file, opened on the first chunk // 临时文件，收到第一个分块时打开
+	chunks        map[int64]struct{} // Received chunk indexes // 已接收的分块序号
+	uploadedBytes int64              // Bytes received // 已接收字节数
+	timeout       time.Duration      // Idle timeout // 空闲超时
+	expiresAt     time.Time          // Time the idle timer fires // 空闲定时器触发时间
+	timer         *time.Timer        // Idle timer // 空闲定时器
+	completing    bool               // A complete request is storing the file // 完成请求正在保存文件
+	closed        bool               // Completed or cleaned up // 已完成或已清理
+}
+
+// Cleanup stops the idle timer and removes the temp file
+// Cleanup 停止空闲定时器并删除临时文件
+func (s *FileUploadHTTPSession) Cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	if s.file != nil {
+		_ = s.file.Close()
+		s.file = nil
+	}
+	if s.SavePath != "" {
+		if err := os.Remove(s.SavePath); err != nil && !os.IsNotExist(err) {
+			zap.L().Warn("cleanup: failed to remove temp file",
+				zap.String("sessionID", s.ID),
+				zap.String("path", s.SavePath),
+				zap.String("method", "FileUploadHTTPSession.Cleanup"),
+				zap.Error(err),
+			)
+		}
+		s.SavePath = ""
+	}
+}
+
+// GetPathHash returns the path hash of the session
+// GetPathHash 返回会话的路径哈希值
+func (s *FileUploadHTTPSession) GetPathHash() string {
+	return s.PathHash
+}
+
+// GetCreatedAt returns the creation time of the session
+// GetCreatedAt 返回会话的创建时间
+func (s *FileUploadHTTPSession) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
+// touch pushes the idle timeout back, the caller holds s.mu
+// touch 推迟空闲超时，调用方需持有 s.mu
+func (s *FileUploadHTTPSession) touch() {
+	s.expiresAt = time.Now().Add(s.timeout)
+	if s.timer != nil {
+		s.timer.Reset(s.timeout)
+	}
+}
+
+// toDTO returns the upload state, the caller holds s.mu
+// toDTO 返回上传状态，调用方需持有 s.mu
+func (s *FileUploadHTTPSession) toDTO() *dto.FileUploadSessionDTO {
+	chunks := make([]int64, 0, len(s.chunks))
+	for index := range s.chunks {
+		chunks = append(chunks, index)
+	}
+	sort.Slice(chunks, func(i, j int) bool { return chunks[i] < chunks[j] })
+
+	return &dto.FileUploadSessionDTO{
+		NeedUpload:     true,
+		SessionID:      s.ID,
+		Path:           s.Path,
+		PathHash:       s.PathHash,
+		Size:           s.Size,
+		ChunkSize:      s.ChunkSize,
+		TotalChunks:    s.TotalChunks,
+		UploadedChunks: chunks,
+		UploadedBytes:  s.uploadedBytes,
+		ExpiresAt:      s.expiresAt.UnixMilli(),
+	}
+}
+
+// chunkLength returns the expected length of a chunk, every chunk is ChunkSize long except the last
+// chunkLength 返回分块的预期长度，除最后一块外每块长度均为 ChunkSize
+func (s *FileUploadHTTPSession) chunkLength(index int64) int64 {
+	if remaining := s.Size - index*s.ChunkSize; remaining < s.ChunkSize {
+		return remaining
+	}
+	return s.ChunkSize
+}
+
+// uploadSession returns the HTTP upload session of the user, nil when it does not exist or expired
+// uploadSession 返回用户的 HTTP 上传会话，不存在或已过期时返回 nil
+func (h *FileHandler) uploadSession(uid int64, sessionID string) *FileUploadHTTPSession {
+	session, _ := h.WSS.GetSession(strconv.FormatInt(uid, 10), sessionID).(*FileUploadHTTPSession)
+	return session
+}
+
+// removeUploadSession unregisters a session and releases its resources
+// removeUploadSession 注销会话并释放其资源
+func (h *FileHandler) removeUploadSession(session *FileUploadHTTPSession) {
+	h.WSS.RemoveSession(strconv.FormatInt(session.UID, 10), session.ID)
+	session.Cleanup()
+}
+
+// uploadSessionTimeout returns the configured upload session idle timeout
+// uploadSessionTimeout 返回配置的上传会话空闲超时
+func (h *FileHandler) uploadSessionTimeout() time.Duration {
+	cfg := h.App.Config().App.UploadSessionTimeout
+	if cfg == "" || cfg == "0" {
+		return defaultUploadSessionTimeout
+	}
+	timeout, err := util.ParseDuration(cfg)
+	if err != nil || timeout <= 0 {
+		h.App.Logger().Warn("FileHandler: invalid upload-session-timeout config, using default 20m", zap.String("config", cfg), zap.Error(err))
+		return defaultUploadSessionTimeout
+	}
+	return timeout
+}
+
+// UploadInit starts or resumes a chunked upload
+// @Summary Start chunked upload
+// @Description Start a resumable chunked upload for large attachments. When the server already stores the same content no upload is needed and the stored file is returned. Calling it again for the same path, content hash and size resumes the unfinished session and lists the chunks received so far. Upload every missing chunk with PUT /api/file/upload/chunk, then call POST /api/file/upload/complete.
+// @Tags File
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.FileUploadInitRequest true "Upload Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.FileUploadSessionDTO} "Success"
+// @Router /api/file/upload/init [post]
+func (h *FileHandler) UploadInit(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.FileUploadInitRequest{}
+
+	// Parameter binding and validation
+	// 参数绑定和验证
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("FileHandler.UploadInit.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("FileHandler.UploadInit err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	if params.PathHash == "" {
+		params.PathHash = util.EncodeHash32(params.Path)
+	}
+	now := time.Now().UnixMilli()
+	if params.Ctime == 0 {
+		params.Ctime = now
+	}
+	if params.Mtime == 0 {
+		params.Mtime = now
+	}
+
+	ctx := c.Request.Context()
+	uidStr := strconv.FormatInt(uid, 10)
+
+	// Resume an unfinished upload of the same content
+	// 续传相同内容的未完成上传
+	if existing, ok := h.WSS.GetSessionByPathHash(uidStr, params.PathHash).(*FileUploadHTTPSession); ok {
+		existing.mu.Lock()
+		if !existing.closed && !existing.completing && existing.Vault == params.Vault &&
+			existing.ContentHash == params.ContentHash && existing.Size == params.Size {
+			existing.Ctime, existing.Mtime = params.Ctime, params.Mtime
+			existing.touch()
+			data := existing.toDTO()
+			existing.mu.Unlock()
+			response.ToResponse(code.Success.WithData(data))
+			return
+		}
+		existing.mu.Unlock()
+	}
+
+	// Check and create vault, internally uses SF to merge concurrent requests
+	// 检查并创建仓库，内部使用 SF 合并并发请求
+	h.App.VaultService.GetOrCreate(ctx, uid, params.Vault)
+
+	fileSvc := h.App.GetFileService(h.getClientInfo(c))
+	updateMode, fileDTO, err := fileSvc.UploadCheck(ctx, uid, &dto.FileUpdateCheckRequest{
+		Vault:       params.Vault,
+		Path:        params.Path,
+		PathHash:    params.PathHash,
+		ContentHash: params.ContentHash,
+		Size:        params.Size,
+		Ctime:       params.Ctime,
+		Mtime:       params.Mtime,
+	})
+	if err != nil {
+		h.logError(ctx, "FileHandler.UploadInit.UploadCheck", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+	if updateMode != "UpdateContent" && updateMode != "Create" {
+		// The server stores this content already
+		// 服务端已存储相同内容
+		response.ToResponse(code.Success.WithData(&dto.FileUploadSessionDTO{
+			Path:           params.Path,
+			PathHash:       params.PathHash,
+			Size:           params.Size,
+			UploadedChunks: []int64{},
+			File:           fileDTO,
+		}))
+		return
+	}
+
+	// Replace other uploads of the same path, WebSocket ones included
+	// 替换同一路径的其他上传，包括 WebSocket 上传
+	h.WSS.CleanSessionsByPathHash(uidStr, params.PathHash)
+
+	cfg := h.App.Config()
+	tempDir := cfg.App.TempPath
+	if tempDir == "" {
+		tempDir = "storage/temp"
+	}
+	if err := os.MkdirAll(tempDir, 0754); err != nil {
+		h.logError(ctx, "FileHandler.UploadInit.MkdirAll", err)
+		response.ToResponse(code.ErrorFileUploadCheckFailed.WithDetails(err.Error()))
+		return
+	}
+
+	session := &FileUploadHTTPSession{
+		ID:          uuid.New().String(),
+		UID:         uid,
+		Vault:       params.Vault,
+		Path:        params.Path,
+		PathHash:    params.PathHash,
+		ContentHash: params.ContentHash,
+		Ctime:       params.Ctime,
+		Mtime:       params.Mtime,
+		Size:        params.Size,
+		ChunkSize:   util.ParseSize(cfg.App.FileChunkSize, 1024*512),
+		CreatedAt:   time.Now(),
+		chunks:      make(map[int64]struct{}),
+		timeout:     h.uploadSessionTimeout(),
+	}
+	session.SavePath = filepath.Join(tempDir, session.ID)
+	session.TotalChunks = util.Ceil(session.Size, session.ChunkSize)
+	session.expiresAt = time.Now().Add(session.timeout)
+	session.timer = time.AfterFunc(session.timeout, func() {
+		h.App.Logger().Warn("FileHandler.UploadInit: upload session timeout, cleaning up",
+			zap.Int64("uid", uid),
+			zap.String("sessionID", session.ID),
+			zap.String("path", session.Path))
+		h.removeUploadSession(session)
+	})
+
+	h.WSS.SetSession(uidStr, session.ID, session)
+
+	h.App.Logger().Info("FileHandler.UploadInit: upload session created",
+		zap.Int64("uid", uid),
+		zap.String("sessionID", session.ID),
+		zap.String("path", session.Path),
+		zap.Int64("size", session.Size),
+		zap.Int64("totalChunks", session.TotalChunks))
+
+	session.mu.Lock()
+	data := session.toDTO()
+	session.mu.Unlock()
+	response.ToResponse(code.Success.WithData(data))
+}
+
+// UploadChunk receives one chunk of a chunked upload
+// @Summary Upload a chunk
+// @Description Upload one chunk of a chunked upload as the raw request body. Every chunk is chunkSize bytes long except the last one. Chunks can be sent in any order, in parallel and more than once.
+// @Tags File
+// @Security UserAuthToken
+// @Accept octet-stream
+// @Produce json
+// @Param params query dto.FileUploadChunkRequest true "Chunk Parameters"
+// @Param chunk body string true "Chunk content"
+// @Success 200 {object} pkgapp.Res{data=dto.FileUploadSessionDTO} "Success"
+// @Router /api/file/upload/chunk [put]
+func (h *FileHandler) UploadChunk(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.FileUploadChunkRequest{}
+
+	// The chunk is the body, bind the query only
+	// 分块内容为请求体，仅绑定查询参数
+	if err := c.ShouldBindQuery(params); err != nil {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(err.Error()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("FileHandler.UploadChunk err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	session := h.uploadSession(uid, params.SessionID)
+	if session == nil {
+		response.ToResponse(code.ErrorFileUploadSessionNotFound.WithData(map[string]string{"sessionID": params.SessionID}))
+		return
+	}
+	if params.Index >= session.TotalChunks {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(fmt.Sprintf("chunk index must be below %d", session.TotalChunks)))
+		return
+	}
+
+	expected := session.chunkLength(params.Index)
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, expected+1))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			response.ToResponse(code.ErrorInvalidParams.WithDetails(fmt.Sprintf("chunk %d must be %d bytes long", params.Index, expected)))
+			return
+		}
+		h.logError(ctx, "FileHandler.UploadChunk.Read", err)
+		response.ToResponse(code.ErrorFileUploadFailed.WithDetails(err.Error()))
+		return
+	}
+	if int64(len(data)) != expected {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(fmt.Sprintf("chunk %d must be %d bytes long", params.Index, expected)))
+		return
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if session.closed || session.completing {
+		response.ToResponse(code.ErrorFileUploadSessionNotFound.WithData(map[string]string{"sessionID": params.SessionID}))
+		return
+	}
+	session.touch()
+
+	// Duplicate chunks are acknowledged without writing them again
+	// 重复的分块直接确认，不再重复写入
+	if _, ok := session.chunks[params.Index]; ok {
+		response.ToResponse(code.Success.WithData(session.toDTO()))
+		return
+	}
+
+	if session.file == nil {
+		f, err := os.OpenFile(session.SavePath, os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			h.logError(ctx, "FileHandler.UploadChunk.Open", err)
+			response.ToResponse(code.ErrorFileUploadFailed.WithDetails(err.Error()))
+			return
+		}
+		session.file = f
+	}
+	if _, err := session.file.WriteAt(data, params.Index*session.ChunkSize); err != nil {
+		h.logError(ctx, "FileHandler.UploadChunk.WriteAt", err)
+		response.ToResponse(code.ErrorFileUploadFailed.WithDetails(err.Error()))
+		return
+	}
+
+	session.chunks[params.Index] = struct{}{}
+	session.uploadedBytes += int64(len(data))
+	response.ToResponse(code.Success.WithData(session.toDTO()))
+}
+
+// UploadStatus returns the state of a chunked upload
+// @Summary Get chunked upload status
+// @Description Get the chunks received so far, used to resume an interrupted upload
+// @Tags File
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.FileUploadSessionRequest true "Session Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.FileUploadSessionDTO} "Success"
+// @Router /api/file/upload/status [get]
+func (h *FileHandler) UploadStatus(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.FileUploadSessionRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("FileHandler.UploadStatus.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("FileHandler.UploadStatus err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	session := h.uploadSession(uid, params.SessionID)
+	if session == nil {
+		response.ToResponse(code.ErrorFileUploadSessionNotFound.WithData(map[string]string{"sessionID": params.SessionID}))
+		return
+	}
+
+	session.mu.Lock()
+	data := session.toDTO()
+	session.mu.Unlock()
+	response.ToResponse(code.Success.WithData(data))
+}
+
+// UploadComplete stores the uploaded file once every chunk was received
+// @Summary Complete chunked upload
+// @Description Store the uploaded attachment once every chunk was received and notify the other devices. A failed request can be retried, the session is kept until the file is stored.
+// @Tags File
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.FileUploadSessionRequest true "Session Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.FileDTO} "Success"
+// @Router /api/file/upload/complete [post]
+func (h *FileHandler) UploadComplete(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.FileUploadSessionRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("FileHandler.UploadComplete.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("FileHandler.UploadComplete err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	session := h.uploadSession(uid, params.SessionID)
+	if session == nil {
+		response.ToResponse(code.ErrorFileUploadSessionNotFound.WithData(map[string]string{"sessionID": params.SessionID}))
+		return
+	}
+
+	session.mu.Lock()
+	if session.closed || session.completing {
+		session.mu.Unlock()
+		response.ToResponse(code.ErrorFileUploadSessionNotFound.WithData(map[string]string{"sessionID": params.SessionID}))
+		return
+	}
+	if received := int64(len(session.chunks)); received < session.TotalChunks {
+		data := session.toDTO()
+		session.mu.Unlock()
+		response.ToResponse(code.ErrorFileUploadFailed.WithDetails(fmt.Sprintf("%d of %d chunks received", received, session.TotalChunks)).WithData(data))
+		return
+	}
+
+	// Close the temp file, an empty file never received a chunk and is created here
+	// 关闭临时文件，空文件没有任何分块，在此创建
+	if session.file == nil {
+		f, err := os.Create(session.SavePath)
+		if err != nil {
+			session.mu.Unlock()
+			h.logError(ctx, "FileHandler.UploadComplete.Create", err)
+			response.ToResponse(code.ErrorFileUploadFailed.WithDetails(err.Error()))
+			return
+		}
+		session.file = f
+	}
+	if err := session.file.Close(); err != nil {
+		session.mu.Unlock()
+		h.logError(ctx, "FileHandler.UploadComplete.Close", err)
+		response.ToResponse(code.ErrorFileUploadFailed.WithDetails(err.Error()))
+		return
+	}
+	session.file = nil
+	session.completing = true
+	session.timer.Stop()
+	updateParams := &dto.FileUpdateRequest{
+		Vault:       session.Vault,
+		Path:        session.Path,
+		PathHash:    session.PathHash,
+		ContentHash: session.ContentHash,
+		SavePath:    session.SavePath,
+		Size:        session.Size,
+		Ctime:       session.Ctime,
+		Mtime:       session.Mtime,
+	}
+	session.mu.Unlock()
+
+	h.App.VaultService.GetOrCreate(ctx, uid, session.Vault)
+
+	// The repository moves the temp file into place
+	// 仓储层会将临时文件移动到最终位置
+	fileSvc := h.App.GetFileService(h.getClientInfo(c))
+	_, fileDTO, err := fileSvc.UploadComplete(ctx, uid, updateParams)
+	if err != nil {
+		// Keep the session so the request can be retried
+		// 保留会话以便重试
+		session.mu.Lock()
+		session.completing = false
+		session.touch()
+		session.mu.Unlock()
+		h.logError(ctx, "FileHandler.UploadComplete.UploadComplete", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	h.WSS.RemoveSession(strconv.FormatInt(uid, 10), session.ID)
+	session.mu.Lock()
+	session.closed = true
+	session.SavePath = "" // Moved by the repository // 已由仓储层移动
+	session.mu.Unlock()
+
+	h.App.Logger().Info("FileHandler.UploadComplete: upload finished",
+		zap.Int64("uid", uid),
+		zap.String("sessionID", session.ID),
+		zap.String("path", session.Path),
+		zap.Int64("size", session.Size))
+
+	response.ToResponse(code.Success.WithData(fileDTO))
+	if fileDTO == nil {
+		return
+	}
+
+	// Render thumbnails of uploaded images in the background
+	// 在后台为上传的图片生成缩略图
+	h.prepareThumbnails(uid, fileDTO)
+
+	// Broadcast WebSocket event: FileSyncUpdate
+	// 广播 WebSocket 事件: 文件同步更新
+	h.WSS.BroadcastToUser(uid, code.Success.WithData(
+		dto.FileSyncModifyMessage{
+			Path:             fileDTO.Path,
+			PathHash:         fileDTO.PathHash,
+			ContentHash:      fileDTO.ContentHash,
+			Size:             fileDTO.Size,
+			Ctime:            fileDTO.Ctime,
+			Mtime:            fileDTO.Mtime,
+			UpdatedTimestamp: fileDTO.UpdatedTimestamp,
+		},
+	).WithVault(session.Vault), "FileSyncUpdate")
+}
+
+// UploadAbort discards an unfinished chunked upload
+// @Summary Abort chunked upload
+// @Description Discard an unfinished chunked upload and its received chunks
+// @Tags File
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.FileUploadSessionRequest true "Session Parameters"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Router /api/file/upload [delete]
+func (h *FileHandler) UploadAbort(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.FileUploadSessionRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("FileHandler.UploadAbort.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("FileHandler.UploadAbort err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	session := h.uploadSession(uid, params.SessionID)
+	if session == nil {
+		response.ToResponse(code.ErrorFileUploadSessionNotFound.WithData(map[string]string{"sessionID": params.SessionID}))
+		return
+	}
+
+	session.mu.Lock()
+	completing := session.completing
+	session.mu.Unlock()
+	if completing {
+		response.ToResponse(code.ErrorFileUploadFailed.WithDetails("upload is being completed"))
+		return
+	}
+
+	h.removeUploadSession(session)
+	response.ToResponse(code.SuccessDelete)
+}
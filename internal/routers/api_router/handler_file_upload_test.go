@@ -0,0 +1,160 @@
+package api_router
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	svcmocks "github.com/haierkeys/fast-note-sync-service/internal/service/mocks"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// noopThumbnailService skips thumbnail rendering after uploads
+// noopThumbnailService 上传后跳过缩略图生成
+type noopThumbnailService struct {
+	service.ThumbnailService
+}
+
+func (noopThumbnailService) Prepare(ctx context.Context, uid int64, file *dto.FileDTO) error {
+	return nil
+}
+
+func newTestUploadHandler(t *testing.T, fileSvc *svcmocks.MockFileService) *FileHandler {
+	t.Helper()
+	vaultSvc := new(svcmocks.MockVaultService)
+	vaultSvc.On("GetOrCreate", mock.Anything, mock.Anything, mock.Anything).Return(&domain.Vault{ID: 1}, nil)
+	fileSvc.On("WithClient", mock.Anything, mock.Anything, mock.Anything).Return(fileSvc)
+
+	testApp := app.NewTestApp(&app.Services{
+		FileService:      fileSvc,
+		VaultService:     vaultSvc,
+		ThumbnailService: noopThumbnailService{},
+	})
+	testApp.Config().App.TempPath = t.TempDir()
+	testApp.Config().App.FileChunkSize = "4B"
+	wss := pkgapp.NewWebsocketServer(pkgapp.WSConfig{}, testApp)
+	return NewFileHandler(testApp, wss)
+}
+
+func uploadSessionData(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	data, ok := decodeRes(t, w)["data"].(map[string]interface{})
+	require.True(t, ok, "response should carry the upload session")
+	return data
+}
+
+func putChunk(h *FileHandler, sessionID, index, body string) *httptest.ResponseRecorder {
+	c, w := newFileTestContext(http.MethodPut, "/api/file/upload/chunk?sessionId="+sessionID+"&index="+index, "", 1)
+	c.Request.Body = io.NopCloser(strings.NewReader(body))
+	h.UploadChunk(c)
+	return w
+}
+
+// TestFileHandler_ChunkedUpload verifies out-of-order, duplicate and resumed chunks assemble the file
+// TestFileHandler_ChunkedUpload 验证乱序、重复与续传的分块能正确拼装文件
+func TestFileHandler_ChunkedUpload(t *testing.T) {
+	mockSvc := new(svcmocks.MockFileService)
+	mockSvc.On("UploadCheck", mock.Anything, int64(1), mock.AnythingOfType("*dto.FileUpdateCheckRequest")).
+		Return("Create", (*dto.FileDTO)(nil), nil)
+
+	var stored string
+	mockSvc.On("UploadComplete", mock.Anything, int64(1), mock.AnythingOfType("*dto.FileUpdateRequest")).
+		Run(func(args mock.Arguments) {
+			data, err := os.ReadFile(args.Get(2).(*dto.FileUpdateRequest).SavePath)
+			require.NoError(t, err)
+			stored = string(data)
+		}).
+		Return(true, &dto.FileDTO{Path: "clip.mp4", Size: 10}, nil)
+
+	h := newTestUploadHandler(t, mockSvc)
+	initBody := `{"vault":"main","path":"clip.mp4","contentHash":"c1","size":10}`
+
+	c, w := newFileTestContext(http.MethodPost, "/api/file/upload/init", initBody, 1)
+	h.UploadInit(c)
+	assertResponseCode(t, w, code.Success.Code())
+	session := uploadSessionData(t, w)
+	sessionID := session["sessionId"].(string)
+	assert.Equal(t, true, session["needUpload"])
+	assert.Equal(t, float64(3), session["totalChunks"])
+
+	assertResponseCode(t, putChunk(h, sessionID, "2", "89"), code.Success.Code())
+	assertResponseCode(t, putChunk(h, sessionID, "0", "0123"), code.Success.Code())
+	assertResponseCode(t, putChunk(h, sessionID, "0", "0123"), code.Success.Code())
+	assertResponseCode(t, putChunk(h, sessionID, "1", "45"), code.ErrorInvalidParams.Code())
+	assertResponseCode(t, putChunk(h, sessionID, "3", "0123"), code.ErrorInvalidParams.Code())
+
+	// Completing early reports the missing chunks
+	c, w = newFileTestContext(http.MethodPost, "/api/file/upload/complete", `{"sessionId":"`+sessionID+`"}`, 1)
+	h.UploadComplete(c)
+	assertResponseCode(t, w, code.ErrorFileUploadFailed.Code())
+
+	// Starting the same upload again resumes the session
+	c, w = newFileTestContext(http.MethodPost, "/api/file/upload/init", initBody, 1)
+	h.UploadInit(c)
+	session = uploadSessionData(t, w)
+	assert.Equal(t, sessionID, session["sessionId"])
+	assert.Equal(t, []interface{}{float64(0), float64(2)}, session["uploadedChunks"])
+	assert.Equal(t, float64(6), session["uploadedBytes"])
+
+	assertResponseCode(t, putChunk(h, sessionID, "1", "4567"), code.Success.Code())
+
+	c, w = newFileTestContext(http.MethodPost, "/api/file/upload/complete", `{"sessionId":"`+sessionID+`"}`, 1)
+	h.UploadComplete(c)
+	assertResponseCode(t, w, code.Success.Code())
+	assert.Equal(t, "0123456789", stored)
+
+	// The session is gone once the file is stored
+	c, w = newFileTestContext(http.MethodGet, "/api/file/upload/status?sessionId="+sessionID, "", 1)
+	h.UploadStatus(c)
+	assertResponseCode(t, w, code.ErrorFileUploadSessionNotFound.Code())
+}
+
+// TestFileHandler_UploadInit_NoUpload verifies unchanged content needs no upload
+// TestFileHandler_UploadInit_NoUpload 验证内容未变化时无需上传
+func TestFileHandler_UploadInit_NoUpload(t *testing.T) {
+	mockSvc := new(svcmocks.MockFileService)
+	mockSvc.On("UploadCheck", mock.Anything, int64(1), mock.AnythingOfType("*dto.FileUpdateCheckRequest")).
+		Return("", &dto.FileDTO{Path: "clip.mp4", ContentHash: "c1"}, nil)
+
+	h := newTestUploadHandler(t, mockSvc)
+	c, w := newFileTestContext(http.MethodPost, "/api/file/upload/init", `{"vault":"main","path":"clip.mp4","contentHash":"c1","size":10}`, 1)
+	h.UploadInit(c)
+
+	assertResponseCode(t, w, code.Success.Code())
+	session := uploadSessionData(t, w)
+	assert.Equal(t, false, session["needUpload"])
+	assert.Nil(t, session["sessionId"])
+	assert.NotNil(t, session["file"])
+}
+
+// TestFileHandler_UploadAbort verifies aborting removes the session
+// TestFileHandler_UploadAbort 验证中止上传会移除会话
+func TestFileHandler_UploadAbort(t *testing.T) {
+	mockSvc := new(svcmocks.MockFileService)
+	mockSvc.On("UploadCheck", mock.Anything, int64(1), mock.AnythingOfType("*dto.FileUpdateCheckRequest")).
+		Return("Create", (*dto.FileDTO)(nil), nil)
+
+	h := newTestUploadHandler(t, mockSvc)
+	c, w := newFileTestContext(http.MethodPost, "/api/file/upload/init", `{"vault":"main","path":"clip.mp4","contentHash":"c1","size":10}`, 1)
+	h.UploadInit(c)
+	sessionID := uploadSessionData(t, w)["sessionId"].(string)
+	assertResponseCode(t, putChunk(h, sessionID, "0", "0123"), code.Success.Code())
+
+	c, w = newFileTestContext(http.MethodDelete, "/api/file/upload?sessionId="+sessionID, "", 1)
+	h.UploadAbort(c)
+	assertResponseCode(t, w, code.SuccessDelete.Code())
+
+	assertResponseCode(t, putChunk(h, sessionID, "1", "4567"), code.ErrorFileUploadSessionNotFound.Code())
+}
@@ -14,8 +14,8 @@ type FolderHandler struct {
 	*Handler
 }
 
-func NewFolderHandler(appContainer *app.App) *FolderHandler {
-	return &FolderHandler{Handler: NewHandler(appContainer)}
+func NewFolderHandler(appContainer *app.App, wss *pkgapp.WebsocketServer) *FolderHandler {
+	return &FolderHandler{Handler: NewHandlerWithWSS(appContainer, wss)}
 }
 
 // Get retrieves a folder
@@ -132,6 +132,76 @@ func (h *FolderHandler) Delete(c *gin.Context) {
 	response.ToResponse(code.Success)
 }
 
+// Move moves a folder with its contents
+// @Summary Move folder
+// @Description Move or rename a folder together with all its subfolders, notes and files, keeping note history; each moved resource is broadcast to the user's clients as a rename
+// @Tags Folder
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.FolderMoveRequest true "Move Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.FolderMoveResult} "Success"
+// @Router /api/folder/move [post]
+func (h *FolderHandler) Move(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.FolderMoveRequest{}
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		h.App.Logger().Error("FolderHandler.Move.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	res, err := h.App.GetFolderService(h.getClientInfo(c)).Move(c.Request.Context(), uid, params)
+	if err != nil {
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	// Folders first so clients create the targets before resources arrive
+	// 先广播文件夹，使客户端在资源到达前创建目标目录
+	for _, f := range res.Folders {
+		h.WSS.BroadcastToUser(uid, code.Success.WithData(f).WithVault(params.Vault), "FolderSyncRename")
+	}
+	for _, n := range res.Notes {
+		h.WSS.BroadcastToUser(uid, code.Success.WithData(n).WithVault(params.Vault), "NoteSyncRename")
+	}
+	for _, f := range res.Files {
+		h.WSS.BroadcastToUser(uid, code.Success.WithData(f).WithVault(params.Vault), "FileSyncRename")
+	}
+
+	response.ToResponse(code.Success.WithData(res))
+}
+
+// Archive archives or unarchives a folder
+// @Summary Archive folder
+// @Description Mark a folder as archived (read-only for all clients, excluded from keyword search by default) or clear the flag
+// @Tags Folder
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.FolderArchiveRequest true "Archive Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.FolderDTO} "Success"
+// @Router /api/folder/archive [post]
+func (h *FolderHandler) Archive(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.FolderArchiveRequest{}
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		h.App.Logger().Error("FolderHandler.Archive.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	res, err := h.App.GetFolderService(h.getClientInfo(c)).SetArchived(c.Request.Context(), uid, params)
+	if err != nil {
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(res))
+}
+
 // ListNotes retrieves notes in a folder
 // @Summary List notes in folder
 // @Description List non-deleted notes in a specific folder with pagination and sorting
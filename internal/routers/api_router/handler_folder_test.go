@@ -41,7 +41,8 @@ func newTestFolderHandler(folderSvc *svcmocks.MockFolderService) *FolderHandler
 		FolderService: folderSvc,
 	})
 	folderSvc.On("WithClient", mock.Anything, mock.Anything, mock.Anything).Return(folderSvc)
-	return NewFolderHandler(testApp)
+	wss := pkgapp.NewWebsocketServer(pkgapp.WSConfig{}, testApp)
+	return NewFolderHandler(testApp, wss)
 }
 
 // TestFolderHandler_Get_Success verifies successful folder fetch
@@ -127,3 +128,29 @@ func TestFolderHandler_Delete_Success(t *testing.T) {
 	assertResponseCode(t, w, code.Success.Code())
 	mockSvc.AssertExpectations(t)
 }
+
+// TestFolderHandler_Move_Success verifies a folder move returns the moved resources
+func TestFolderHandler_Move_Success(t *testing.T) {
+	mockSvc := new(svcmocks.MockFolderService)
+
+	result := &dto.FolderMoveResult{
+		Folders: []*dto.FolderSyncRenameMessage{{Path: "Archive/f1", OldPath: "f1"}},
+		Notes:   []*dto.NoteSyncRenameMessage{{Path: "Archive/f1/a.md", OldPath: "f1/a.md"}},
+		Files:   []*dto.FileSyncRenameMessage{},
+	}
+	mockSvc.On("Move", mock.Anything, int64(1), mock.MatchedBy(func(p *dto.FolderMoveRequest) bool {
+		return p.OldPath == "f1" && p.Path == "Archive/f1"
+	})).Return(result, nil)
+
+	handler := newTestFolderHandler(mockSvc)
+	body := `{"vault":"main", "oldPath":"f1", "path":"Archive/f1"}`
+	c, w := newFolderTestContext("POST", "/api/folder/move", body, 1)
+
+	handler.Move(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertResponseCode(t, w, code.Success.Code())
+	data := decodeRes(t, w)["data"].(map[string]interface{})
+	assert.Len(t, data["notes"], 1)
+	mockSvc.AssertExpectations(t)
+}
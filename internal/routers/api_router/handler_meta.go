@@ -0,0 +1,61 @@
+package api_router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+)
+
+// MetaHandler API metadata router handler
+// MetaHandler API 元数据路由处理器
+type MetaHandler struct {
+	*Handler
+}
+
+// NewMetaHandler creates MetaHandler instance
+// NewMetaHandler 创建 MetaHandler 实例
+func NewMetaHandler(a *app.App) *MetaHandler {
+	return &MetaHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// Errors lists every response code of the server
+// @Summary Get the error catalog
+// @Description Lists every response code with its stable name, category, HTTP status, message template and remediation hint, so clients can map the code of a response instead of parsing its message. Messages and hints follow the lang query parameter or header (en, zh_cn).
+// @Tags System
+// @Produce json
+// @Param lang query string false "Language code (default: en)"
+// @Success 200 {object} pkgapp.Res{data=dto.ErrorCatalogDTO} "Success"
+// @Router /api/meta/errors [get]
+func (h *MetaHandler) Errors(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	language := code.FALLBACK_LNG
+	for _, l := range code.GetSupportedLanguages() {
+		if l == c.GetString("lang") {
+			language = l
+		}
+	}
+
+	entries := code.Catalog(language)
+	catalog := &dto.ErrorCatalogDTO{
+		Language: language,
+		Codes:    make([]*dto.ErrorCodeDTO, 0, len(entries)),
+	}
+	for _, e := range entries {
+		catalog.Codes = append(catalog.Codes, &dto.ErrorCodeDTO{
+			Code:       e.Code,
+			Name:       e.Name,
+			Category:   e.Category,
+			Status:     e.Status,
+			HTTPStatus: e.HTTPStatus,
+			Message:    e.Message,
+			Hint:       e.Hint,
+		})
+	}
+
+	response.ToResponse(code.Success.WithData(catalog))
+}
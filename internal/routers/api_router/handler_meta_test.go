@@ -0,0 +1,57 @@
+package api_router
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetaHandler_Errors(t *testing.T) {
+	handler := NewMetaHandler(app.NewTestApp(nil))
+	c, w := newVersionTestContext("/api/meta/errors")
+	c.Set("lang", "zh_cn")
+
+	handler.Errors(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertResponseCode(t, w, code.Success.Code())
+
+	var resp struct {
+		Data dto.ErrorCatalogDTO `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "zh_cn", resp.Data.Language)
+
+	var found *dto.ErrorCodeDTO
+	for _, e := range resp.Data.Codes {
+		if e.Code == code.ErrorNoteNotFound.Code() {
+			found = e
+		}
+	}
+	require.NotNil(t, found)
+	assert.Equal(t, "ErrorNoteNotFound", found.Name)
+	assert.Equal(t, "note", found.Category)
+	assert.Equal(t, code.ErrorNoteNotFound.MsgIn("zh_cn"), found.Message)
+	assert.Equal(t, code.ErrorNoteNotFound.Hint("zh_cn"), found.Hint)
+}
+
+func TestMetaHandler_Errors_UnknownLanguage(t *testing.T) {
+	handler := NewMetaHandler(app.NewTestApp(nil))
+	c, w := newVersionTestContext("/api/meta/errors")
+	c.Set("lang", "fr")
+
+	handler.Errors(c)
+
+	var resp struct {
+		Data dto.ErrorCatalogDTO `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "en", resp.Data.Language)
+	assert.NotEmpty(t, resp.Data.Codes)
+}
@@ -2,12 +2,16 @@ package api_router
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
@@ -79,6 +83,7 @@ func (h *NoteHandler) Get(c *gin.Context) {
 		apperrors.ErrorResponse(c, err)
 		return
 	}
+	h.recordNoteAccess(c, uid, note, domain.NoteAccessChannelAPI, service.TokenActor(pkgapp.GetTokenID(c)))
 
 	// Parse ![[ ]] tags in content
 	// 解析内容中的 ![[ ]] 标签
@@ -102,6 +107,10 @@ func (h *NoteHandler) Get(c *gin.Context) {
 		CreatedAt:        note.CreatedAt,
 	}
 
+	// Attach custom metadata
+	// 附加自定义元数据
+	noteWithLinks.Meta = h.noteMetas(ctx, uid, []int64{note.ID})[note.ID]
+
 	response.ToResponse(code.Success.WithData(noteWithLinks))
 }
 
@@ -160,12 +169,23 @@ func (h *NoteHandler) List(c *gin.Context) {
 		return
 	}
 
+	// Attach custom metadata
+	// 附加自定义元数据
+	noteIDs := make([]int64, 0, len(notes))
+	for _, note := range notes {
+		noteIDs = append(noteIDs, note.ID)
+	}
+	metas := h.noteMetas(ctx, uid, noteIDs)
+	for _, note := range notes {
+		note.Meta = metas[note.ID]
+	}
+
 	response.ToResponseList(code.Success, notes, count)
 }
 
 // CreateOrUpdate creates or updates a note
 // @Summary Create or update note
-// @Description Handle note creation, modification, or renaming (identified by path change)
+// @Description Handle note creation, modification, or renaming (identified by path change). With template set, the content is the named note template with its {{date}}, {{time}} and {{title}} variables expanded; add createOnly to keep an existing note, e.g. for daily notes.
 // @Tags Note
 // @Security UserAuthToken
 // @Accept json
@@ -201,6 +221,19 @@ func (h *NoteHandler) CreateOrUpdate(c *gin.Context) {
 		return
 	}
 
+	// Create from a template: the expanded template replaces the request content
+	// 从模板创建：展开后的模板替换请求内容
+	if params.Template != "" {
+		content, err := h.App.TemplateService.Expand(c.Request.Context(), uid, params.Template, params.Path, time.Now())
+		if err != nil {
+			h.logError(c.Request.Context(), "NoteHandler.CreateOrUpdate.TemplateExpand", err)
+			apperrors.ErrorResponse(c, err)
+			return
+		}
+		params.Content = content
+		params.ContentHash = ""
+	}
+
 	// Apply default folder if configured
 	// if defaultFolder := h.App.Config().App.DefaultAPIFolder; defaultFolder != "" {
 	// 	params.Path = util.ApplyDefaultFolder(params.Path, defaultFolder)
@@ -250,6 +283,15 @@ func (h *NoteHandler) CreateOrUpdate(c *gin.Context) {
 		}
 	}
 
+	// Scan for embedded credentials, strict mode rejects the write
+	// 扫描嵌入的凭据，严格模式下拒绝写入
+	secretFindings, err := h.App.SecretScanService.Check(ctx, uid, params.Path, params.Content)
+	if err != nil {
+		h.logError(ctx, "NoteHandler.CreateOrUpdate.SecretScan", err)
+		response.ToResponse(code.ErrorNoteSecretDetected.WithData(secretFindings))
+		return
+	}
+
 	var noteNew *dto.NoteDTO
 
 	_, noteNew, err = noteSvc.ModifyOrCreate(ctx, uid, params, false, checkedNote)
@@ -261,6 +303,16 @@ func (h *NoteHandler) CreateOrUpdate(c *gin.Context) {
 
 	response.ToResponse(code.Success.WithData(noteNew))
 	h.WSS.BroadcastToUser(uid, code.Success.WithData(noteNew).WithVault(params.Vault), "NoteSyncModify")
+
+	// Warn the user's connected clients about the detected credentials
+	// 向用户已连接的客户端提示检测到的凭据
+	if len(secretFindings) > 0 {
+		h.WSS.BroadcastToUser(uid, code.Success.WithData(dto.NoteSecretWarningMessage{
+			Path:     noteNew.Path,
+			PathHash: noteNew.PathHash,
+			Findings: secretFindings,
+		}).WithVault(params.Vault), "NoteSecretWarning")
+	}
 }
 
 // Delete deletes a note
@@ -331,6 +383,7 @@ func (h *NoteHandler) Delete(c *gin.Context) {
 		apperrors.ErrorResponse(c, err)
 		return
 	}
+	h.audit(c, uid, domain.AuditActionNoteDelete, params.Vault+"/"+params.Path, "")
 
 	response.ToResponse(code.Success.WithData(note))
 	h.WSS.BroadcastToUser(uid, code.Success.WithData(note).WithVault(params.Vault), "NoteSyncDelete")
@@ -405,6 +458,7 @@ func (h *NoteHandler) Restore(c *gin.Context) {
 		apperrors.ErrorResponse(c, err)
 		return
 	}
+	h.audit(c, uid, domain.AuditActionNoteRestore, params.Vault+"/"+note.Path, "")
 
 	response.ToResponse(code.Success.WithData(note))
 	h.WSS.BroadcastToUser(uid, code.Success.WithData(note).WithVault(params.Vault), "NoteSyncModify")
@@ -528,6 +582,162 @@ func (h *NoteHandler) Append(c *gin.Context) {
 	h.WSS.BroadcastToUser(uid, code.Success.WithData(note).WithVault(params.Vault), "NoteSyncModify")
 }
 
+// Duplicate creates a copy of a note
+// @Summary Duplicate note
+// @Description Create "Copy of <name>" next to a note (or at newPath); with copyAttachments the embedded attachments are duplicated too and the copy embeds the duplicates
+// @Tags Note
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.NoteDuplicateRequest true "Duplicate Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteDuplicateResult} "Success"
+// @Router /api/note/duplicate [post]
+func (h *NoteHandler) Duplicate(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteDuplicateRequest{}
+
+	// Parameter binding and validation
+	// 参数绑定和验证
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteHandler.Duplicate.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	// Get UID
+	// 获取用户 ID
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteHandler.Duplicate err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	if params.PathHash == "" {
+		params.PathHash = util.EncodeHash32(params.Path)
+	}
+
+	ctx := c.Request.Context()
+	noteSvc := h.App.GetNoteService(h.getClientInfo(c))
+	fileSvc := h.App.GetFileService(h.getClientInfo(c))
+
+	// Copy embedded attachments first so the duplicated note can point at the copies
+	// 先复制嵌入的附件，使复制出的笔记可以引用这些副本
+	var fileLinks map[string]string
+	var files []*dto.FileDTO
+	if params.CopyAttachments {
+		source, err := noteSvc.Get(ctx, uid, &dto.NoteGetRequest{Vault: params.Vault, Path: params.Path, PathHash: params.PathHash})
+		if err != nil {
+			h.logError(ctx, "NoteHandler.Duplicate.Get", err)
+			apperrors.ErrorResponse(c, err)
+			return
+		}
+		refs, err := fileSvc.ResolveEmbedLinks(ctx, uid, params.Vault, source.Path, source.Content)
+		if err != nil {
+			h.logError(ctx, "NoteHandler.Duplicate.ResolveEmbedLinks", err)
+			apperrors.ErrorResponse(c, err)
+			return
+		}
+
+		fileLinks = make(map[string]string, len(refs))
+		copied := make(map[string]string, len(refs))
+		for rawRef, filePath := range refs {
+			if copyPath, ok := copied[filePath]; ok {
+				fileLinks[rawRef] = copyPath
+				continue
+			}
+			file, err := fileSvc.Copy(ctx, uid, &dto.FileCopyRequest{Vault: params.Vault, Path: filePath})
+			if err != nil {
+				h.logError(ctx, "NoteHandler.Duplicate.FileCopy", err)
+				apperrors.ErrorResponse(c, err)
+				return
+			}
+			copied[filePath] = file.Path
+			fileLinks[rawRef] = file.Path
+			files = append(files, file)
+		}
+	}
+
+	note, err := noteSvc.Duplicate(ctx, uid, params, fileLinks)
+	if err != nil {
+		h.logError(ctx, "NoteHandler.Duplicate", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(&dto.NoteDuplicateResult{Note: note, Files: files}))
+
+	// Broadcast WebSocket events: FileSyncUpdate for each copied attachment, then NoteSyncModify
+	// 广播 WebSocket 事件：每个复制的附件发送 FileSyncUpdate，随后发送 NoteSyncModify
+	for _, file := range files {
+		h.WSS.BroadcastToUser(uid, code.Success.WithData(
+			dto.FileSyncModifyMessage{
+				Path:             file.Path,
+				PathHash:         file.PathHash,
+				ContentHash:      file.ContentHash,
+				Size:             file.Size,
+				Ctime:            file.Ctime,
+				Mtime:            file.Mtime,
+				UpdatedTimestamp: file.UpdatedTimestamp,
+			},
+		).WithVault(params.Vault), "FileSyncUpdate")
+	}
+	h.WSS.BroadcastToUser(uid, code.Success.WithData(note).WithVault(params.Vault), "NoteSyncModify")
+}
+
+// AppendToHeading inserts content under a heading of a note
+// @Summary Insert content under a heading
+// @Description Insert content at the end (or, with position=start, the top) of the section of a Markdown heading such as "## Inbox"; a missing heading is added at the end of the note
+// @Tags Note
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.NoteAppendToHeadingRequest true "Append To Heading Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteAppendToHeadingResponse} "Success"
+// @Router /api/note/append-to-heading [post]
+func (h *NoteHandler) AppendToHeading(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteAppendToHeadingRequest{}
+
+	// Parameter binding and validation
+	// 参数绑定和验证
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteHandler.AppendToHeading.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	// Get UID
+	// 获取用户 ID
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteHandler.AppendToHeading err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	// Calculate PathHash
+	// 计算 PathHash
+	if params.PathHash == "" {
+		params.PathHash = util.EncodeHash32(params.Path)
+	}
+
+	ctx := c.Request.Context()
+
+	noteSvc := h.App.GetNoteService(h.getClientInfo(c))
+	result, err := noteSvc.AppendToHeading(ctx, uid, params)
+	if err != nil {
+		h.logError(ctx, "NoteHandler.AppendToHeading", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(result))
+	h.WSS.BroadcastToUser(uid, code.Success.WithData(result.Note).WithVault(params.Vault), "NoteSyncModify")
+}
+
 // Prepend inserts content at the beginning of a note
 // @Summary Prepend content to note
 // @Description Insert content at the beginning of a note (after frontmatter)
@@ -707,6 +917,20 @@ func (h *NoteHandler) Rename(c *gin.Context) {
 		return
 	}
 
+	// Rewrite the links pointing at the old path, the rename stands even if this fails
+	// 改写指向旧路径的链接，即使失败重命名依然有效
+	updateLinks := h.App.Config().App.RenameUpdateLinks
+	if params.UpdateLinks != nil {
+		updateLinks = *params.UpdateLinks
+	}
+	var linkedNotes []*dto.NoteDTO
+	if updateLinks {
+		linkedNotes, err = noteSvc.UpdateRenamedLinks(ctx, uid, params.Vault, oldNote.Path, newNote.Path)
+		if err != nil {
+			h.logError(ctx, "NoteHandler.Rename.UpdateRenamedLinks", err)
+		}
+	}
+
 	response.ToResponse(code.Success.WithData(newNote))
 
 	// Broadcast WebSocket event: NoteSyncRename
@@ -722,6 +946,10 @@ func (h *NoteHandler) Rename(c *gin.Context) {
 		OldPathHash:      oldNote.PathHash,
 		UpdatedTimestamp: newNote.UpdatedTimestamp,
 	}).WithVault(params.Vault), "NoteSyncRename")
+
+	for _, note := range linkedNotes {
+		h.WSS.BroadcastToUser(uid, code.Success.WithData(note).WithVault(params.Vault), "NoteSyncModify")
+	}
 }
 
 // GetBacklinks retrieves backlinks to a specific note
@@ -836,6 +1064,150 @@ func (h *NoteHandler) GetOutlinks(c *gin.Context) {
 	response.ToResponse(code.Success.WithData(links))
 }
 
+// ListUnresolvedLinks lists the link targets of a vault that match no existing note
+// @Summary List unresolved links
+// @Description List the wiki link targets of a vault that match no existing note, with the notes linking to them
+// @Tags Note
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.NoteUnresolvedLinkRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=[]dto.NoteUnresolvedLinkItem} "Success"
+// @Router /api/note/unresolved-links [get]
+func (h *NoteHandler) ListUnresolvedLinks(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteUnresolvedLinkRequest{}
+
+	// Parameter binding and validation
+	// 参数绑定和验证
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteHandler.ListUnresolvedLinks.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	// Get UID
+	// 获取用户 ID
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteHandler.ListUnresolvedLinks err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	items, err := h.App.NoteLinkService.ListUnresolved(ctx, uid, params.Vault)
+	if err != nil {
+		h.logError(ctx, "NoteHandler.ListUnresolvedLinks", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(items))
+}
+
+// CreateUnresolvedStubs creates stub notes for unresolved links
+// @Summary Create stub notes for unresolved links
+// @Description Create an empty note for each unresolved link target, or the selected ones, so the links resolve. Targets whose path is invalid or already taken are skipped
+// @Tags Note
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.NoteUnresolvedLinkCreateRequest true "Create Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteUnresolvedLinkCreateResult} "Success"
+// @Router /api/note/unresolved-links/create [post]
+func (h *NoteHandler) CreateUnresolvedStubs(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteUnresolvedLinkCreateRequest{}
+
+	// Parameter binding and validation
+	// 参数绑定和验证
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteHandler.CreateUnresolvedStubs.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	// Get UID
+	// 获取用户 ID
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteHandler.CreateUnresolvedStubs err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	folder := strings.Trim(params.Folder, "/")
+	if folder != "" && !util.ValidatePath(folder) {
+		response.ToResponse(code.ErrorInvalidPath)
+		return
+	}
+
+	ctx := c.Request.Context()
+	noteSvc := h.App.GetNoteService(h.getClientInfo(c))
+
+	items, err := h.App.NoteLinkService.ListUnresolved(ctx, uid, params.Vault)
+	if err != nil {
+		h.logError(ctx, "NoteHandler.CreateUnresolvedStubs.ListUnresolved", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	var selected map[string]bool
+	if len(params.Targets) > 0 {
+		selected = make(map[string]bool, len(params.Targets))
+		for _, target := range params.Targets {
+			selected[target] = true
+		}
+	}
+
+	// Stubs go through ModifyOrCreate so history, sync and the link index see a normal note
+	// 占位笔记通过 ModifyOrCreate 创建，使历史、同步与链接索引将其视为普通笔记
+	result := &dto.NoteUnresolvedLinkCreateResult{Created: []*dto.NoteDTO{}, Skipped: []string{}}
+	contentHash := util.EncodeHash32(params.Content)
+	for _, item := range items {
+		if selected != nil && !selected[item.Target] {
+			continue
+		}
+		if !util.ValidatePath(item.StubPath) {
+			result.Skipped = append(result.Skipped, item.Target)
+			continue
+		}
+		stubPath := item.StubPath
+		if folder != "" {
+			stubPath = folder + "/" + stubPath
+		}
+
+		mtime := time.Now().UnixMilli()
+		_, note, err := noteSvc.ModifyOrCreate(ctx, uid, &dto.NoteModifyOrCreateRequest{
+			Vault:       params.Vault,
+			Path:        stubPath,
+			PathHash:    util.EncodeHash32(stubPath),
+			Content:     params.Content,
+			ContentHash: contentHash,
+			Ctime:       mtime,
+			Mtime:       mtime,
+			CreateOnly:  true,
+		}, false)
+		if errors.Is(err, code.ErrorNoteExist) {
+			result.Skipped = append(result.Skipped, item.Target)
+			continue
+		}
+		if err != nil {
+			h.logError(ctx, "NoteHandler.CreateUnresolvedStubs.NoteModifyOrCreate", err)
+			apperrors.ErrorResponse(c, err)
+			return
+		}
+
+		result.Created = append(result.Created, note)
+		h.WSS.BroadcastToUser(uid, code.Success.WithData(note).WithVault(params.Vault), "NoteSyncModify")
+	}
+
+	response.ToResponse(code.Success.WithData(result))
+}
+
 // logError records error log, including Trace ID
 // logError 记录错误日志，包含 Trace ID
 func (h *NoteHandler) logError(ctx context.Context, method string, err error) {
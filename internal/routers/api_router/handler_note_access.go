@@ -0,0 +1,173 @@
+package api_router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// NoteAccessHandler note access log API router handler
+// NoteAccessHandler 笔记访问日志 API 路由处理器
+type NoteAccessHandler struct {
+	*Handler
+}
+
+// NewNoteAccessHandler creates NoteAccessHandler instance
+// NewNoteAccessHandler 创建 NoteAccessHandler 实例
+func NewNoteAccessHandler(a *app.App) *NoteAccessHandler {
+	return &NoteAccessHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// GetSetting returns whether access logging is enabled for a note
+// @Summary Get note access log setting
+// @Description Return whether reads of the note via share links, API and WebDAV are logged
+// @Tags Note Access Log
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.NoteAccessRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteAccessSettingDTO} "Success"
+// @Router /api/note/access-log [get]
+func (h *NoteAccessHandler) GetSetting(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteAccessRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteAccessHandler.GetSetting.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteAccessHandler.GetSetting err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	setting, err := h.App.NoteAccessService.Get(ctx, uid, params)
+	if err != nil {
+		h.noteAccessErr(ctx, "NoteAccessHandler.GetSetting", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(setting))
+}
+
+// UpdateSetting enables or disables access logging of a note
+// @Summary Update note access log setting
+// @Description Enable or disable logging of reads of the note via share links, API and WebDAV; existing entries are kept when disabled
+// @Tags Note Access Log
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.NoteAccessSettingRequest true "Setting Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteAccessSettingDTO} "Success"
+// @Router /api/note/access-log [post]
+func (h *NoteAccessHandler) UpdateSetting(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteAccessSettingRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteAccessHandler.UpdateSetting.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteAccessHandler.UpdateSetting err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	setting, err := h.App.NoteAccessService.Set(ctx, uid, params)
+	if err != nil {
+		h.noteAccessErr(ctx, "NoteAccessHandler.UpdateSetting", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(setting))
+}
+
+// List retrieves the access log of a note with pagination
+// @Summary Get note access log
+// @Description Get reads of a note via share links, API and WebDAV, newest first
+// @Tags Note Access Log
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.NoteAccessRequest true "Query Parameters"
+// @Param pagination query pkgapp.PaginationRequest true "Pagination Parameters"
+// @Success 200 {object} pkgapp.Res{data=pkgapp.ListRes{list=[]dto.NoteAccessLogDTO}} "Success"
+// @Router /api/note/access-logs [get]
+func (h *NoteAccessHandler) List(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteAccessRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteAccessHandler.List.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteAccessHandler.List err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	pager := pkgapp.NewPager(c)
+
+	list, total, err := h.App.NoteAccessService.List(ctx, uid, params, pager.Page, pager.PageSize)
+	if err != nil {
+		h.noteAccessErr(ctx, "NoteAccessHandler.List", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponseList(code.Success, list, int(total))
+}
+
+// noteAccessErr records error log
+// noteAccessErr 记录错误日志
+func (h *NoteAccessHandler) noteAccessErr(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
+
+// recordNoteAccess logs a read of a note if its owner enabled access logging
+// recordNoteAccess 若笔记所有者开启了访问日志，则记录本次读取
+func (h *Handler) recordNoteAccess(c *gin.Context, uid int64, note *dto.NoteDTO, channel domain.NoteAccessChannel, actor string) {
+	if h.App.NoteAccessService == nil || note == nil {
+		return
+	}
+	h.App.NoteAccessService.Record(uid, note.ID, note.Path, service.NoteAccess{
+		Channel:    channel,
+		Actor:      actor,
+		ClientType: c.GetHeader("X-Client"),
+		IP:         pkgapp.GetRequestIP(c),
+		UserAgent:  c.Request.UserAgent(),
+	})
+}
@@ -0,0 +1,183 @@
+package api_router
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+// Daily opens the daily note, creating it when missing, and optionally appends or prepends content
+// @Summary Open or write the daily note
+// @Description Resolves the daily note path of date (today when empty) from the user's daily note pattern, creates the note from the configured template when it is missing, then prepends and appends the given content. Meant for automations such as iOS Shortcuts and scripts.
+// @Tags Note
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.NoteDailyRequest true "Daily Note Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteDailyResult} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/note/daily [post]
+func (h *NoteHandler) Daily(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteDailyRequest{}
+
+	// Parameter binding and validation
+	// 参数绑定和验证
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteHandler.Daily.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	// Get UID
+	// 获取用户 ID
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteHandler.Daily err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	notePath, content, err := h.App.TemplateService.ResolveDaily(ctx, uid, params.Date)
+	if err != nil {
+		h.logError(ctx, "NoteHandler.Daily.ResolveDaily", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+	pathHash := util.EncodeHash32(notePath)
+	noteSvc := h.App.GetNoteService(h.getClientInfo(c))
+
+	// Create-only keeps an existing daily note untouched
+	// 仅创建模式，已存在的每日笔记保持不变
+	mtime := time.Now().UnixMilli()
+	_, note, err := noteSvc.ModifyOrCreate(ctx, uid, &dto.NoteModifyOrCreateRequest{
+		Vault:       params.Vault,
+		Path:        notePath,
+		PathHash:    pathHash,
+		Content:     content,
+		ContentHash: util.EncodeHash32(content),
+		Ctime:       mtime,
+		Mtime:       mtime,
+		CreateOnly:  true,
+	}, false)
+	created := err == nil
+	if errors.Is(err, code.ErrorNoteExist) {
+		err = nil
+	}
+	if err != nil {
+		h.logError(ctx, "NoteHandler.Daily.NoteModifyOrCreate", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+	modified := created
+
+	if params.Prepend != "" {
+		note, err = noteSvc.PrependContent(ctx, uid, &dto.NotePrependRequest{Vault: params.Vault, Path: notePath, PathHash: pathHash, Content: params.Prepend})
+		if err != nil {
+			h.logError(ctx, "NoteHandler.Daily.PrependContent", err)
+			apperrors.ErrorResponse(c, err)
+			return
+		}
+		modified = true
+	}
+	if params.Append != "" {
+		note, err = noteSvc.AppendContent(ctx, uid, &dto.NoteAppendRequest{Vault: params.Vault, Path: notePath, PathHash: pathHash, Content: params.Append})
+		if err != nil {
+			h.logError(ctx, "NoteHandler.Daily.AppendContent", err)
+			apperrors.ErrorResponse(c, err)
+			return
+		}
+		modified = true
+	}
+
+	if !modified {
+		note, err = noteSvc.Get(ctx, uid, &dto.NoteGetRequest{Vault: params.Vault, Path: notePath, PathHash: pathHash})
+		if err != nil {
+			h.logError(ctx, "NoteHandler.Daily.Get", err)
+			apperrors.ErrorResponse(c, err)
+			return
+		}
+	}
+
+	response.ToResponse(code.Success.WithData(&dto.NoteDailyResult{Note: note, Created: created}))
+	if modified {
+		h.WSS.BroadcastToUser(uid, code.Success.WithData(note).WithVault(params.Vault), "NoteSyncModify")
+	}
+}
+
+// GetDailySetting gets the daily note setting of the current user
+// @Summary Get daily note setting
+// @Tags Note
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.NoteDailySettingDTO} "Success"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/note/daily/setting [get]
+func (h *NoteHandler) GetDailySetting(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	setting, err := h.App.TemplateService.GetDailySetting(c.Request.Context(), uid)
+	if err != nil {
+		h.logError(c.Request.Context(), "NoteHandler.GetDailySetting", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(setting))
+}
+
+// UpdateDailySetting updates the daily note setting of the current user
+// @Summary Update daily note setting
+// @Description pattern is a note path with Moment.js date formats in double braces, e.g. Journal/{{YYYY}}/{{YYYY-MM-DD}}.md; template names a note template the missing daily note is created from; timezone is the IANA time zone deciding what "today" is.
+// @Tags Note
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.NoteDailySettingRequest true "Daily Note Setting"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteDailySettingDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/note/daily/setting [post]
+func (h *NoteHandler) UpdateDailySetting(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteDailySettingRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	setting, err := h.App.TemplateService.SaveDailySetting(c.Request.Context(), uid, params)
+	if err != nil {
+		h.logError(c.Request.Context(), "NoteHandler.UpdateDailySetting", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessUpdate.WithData(setting))
+}
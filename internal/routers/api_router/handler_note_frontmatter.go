@@ -0,0 +1,118 @@
+package api_router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// NoteFrontmatterHandler frontmatter query API router handler
+// NoteFrontmatterHandler frontmatter 查询 API 路由处理器
+type NoteFrontmatterHandler struct {
+	*Handler
+}
+
+// NewNoteFrontmatterHandler creates NoteFrontmatterHandler instance
+// NewNoteFrontmatterHandler 创建 NoteFrontmatterHandler 实例
+func NewNoteFrontmatterHandler(a *app.App) *NoteFrontmatterHandler {
+	return &NoteFrontmatterHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// Query lists the notes whose frontmatter matches all conditions
+// @Summary Query notes by frontmatter
+// @Description List the notes of a vault whose frontmatter matches all conditions, newest first. Operators: exists, notExists, eq, ne, contains (list element or case-insensitive text), gt, gte, lt and lte (numbers or dates like 2026-01-31). Only notes with frontmatter are considered.
+// @Tags Note
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.NoteFrontmatterQueryRequest true "Query Parameters"
+// @Param pagination query pkgapp.PaginationRequest true "Pagination Parameters"
+// @Success 200 {object} pkgapp.Res{data=pkgapp.ListRes{list=[]dto.NoteFrontmatterItem}} "Success"
+// @Router /api/notes/query-frontmatter [post]
+func (h *NoteFrontmatterHandler) Query(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteFrontmatterQueryRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteFrontmatterHandler.Query.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteFrontmatterHandler.Query err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	pager := pkgapp.NewPager(c)
+
+	list, total, err := h.App.FrontmatterService.Query(ctx, uid, params, pager)
+	if err != nil {
+		h.frontmatterErr(ctx, "NoteFrontmatterHandler.Query", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponseList(code.Success, list, total)
+}
+
+// Properties lists the frontmatter properties used in a vault
+// @Summary List frontmatter properties
+// @Description List the frontmatter properties used in the notes of a vault with their most common type and the number of notes having them, for property panels and query builders
+// @Tags Note
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.NotePropertiesRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=[]dto.NotePropertyKeyDTO} "Success"
+// @Router /api/notes/properties [get]
+func (h *NoteFrontmatterHandler) Properties(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NotePropertiesRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteFrontmatterHandler.Properties.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteFrontmatterHandler.Properties err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	properties, err := h.App.FrontmatterService.Properties(ctx, uid, params)
+	if err != nil {
+		h.frontmatterErr(ctx, "NoteFrontmatterHandler.Properties", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(properties))
+}
+
+// frontmatterErr records error log
+// frontmatterErr 记录错误日志
+func (h *NoteFrontmatterHandler) frontmatterErr(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
@@ -0,0 +1,68 @@
+package api_router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// NoteLintHandler spelling and grammar check API router handler
+// NoteLintHandler 拼写与语法检查 API 路由处理器
+type NoteLintHandler struct {
+	*Handler
+}
+
+// NewNoteLintHandler creates NoteLintHandler instance
+// NewNoteLintHandler 创建 NoteLintHandler 实例
+func NewNoteLintHandler(a *app.App) *NoteLintHandler {
+	return &NoteLintHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// Lint checks spelling and grammar of raw text or of a stored note
+// @Summary Check note spelling and grammar
+// @Description Return spelling and grammar issues of the given text, or of the note addressed by vault and path when text is empty. Issues come from the configured LanguageTool server or the bundled word-list spellchecker; offsets count UTF-16 code units like JavaScript string indices. Requires a note read scope.
+// @Tags Note
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.NoteLintRequest true "Lint Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteLintDTO} "Success"
+// @Router /api/note/lint [post]
+func (h *NoteLintHandler) Lint(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteLintRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteLintHandler.Lint.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteLintHandler.Lint err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.App.NoteLintService.Lint(ctx, uid, params)
+	if err != nil {
+		h.App.Logger().Error("NoteLintHandler.Lint",
+			zap.Error(err),
+			zap.String("traceId", middleware.GetTraceID(ctx)),
+		)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(result))
+}
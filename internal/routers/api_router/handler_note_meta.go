@@ -0,0 +1,167 @@
+package api_router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// NoteMetaHandler note metadata API router handler
+// NoteMetaHandler 笔记元数据 API 路由处理器
+type NoteMetaHandler struct {
+	*Handler
+}
+
+// NewNoteMetaHandler creates NoteMetaHandler instance
+// NewNoteMetaHandler 创建 NoteMetaHandler 实例
+func NewNoteMetaHandler(a *app.App) *NoteMetaHandler {
+	return &NoteMetaHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// Get returns the metadata of a note
+// @Summary Get note metadata
+// @Description Return the custom attributes stored on the server for a note, apart from its frontmatter
+// @Tags Note Meta
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.NoteMetaRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteMetaDTO} "Success"
+// @Router /api/note/meta [get]
+func (h *NoteMetaHandler) Get(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteMetaRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteMetaHandler.Get.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteMetaHandler.Get err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	meta, err := h.App.NoteMetaService.Get(ctx, uid, params)
+	if err != nil {
+		h.noteMetaErr(ctx, "NoteMetaHandler.Get", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(meta))
+}
+
+// Set creates or replaces one metadata entry of a note
+// @Summary Set note metadata
+// @Description Create or replace one custom attribute of a note; strings, numbers, booleans, objects and arrays keep their type
+// @Tags Note Meta
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.NoteMetaSetRequest true "Metadata Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteMetaDTO} "Success"
+// @Router /api/note/meta [post]
+func (h *NoteMetaHandler) Set(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteMetaSetRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteMetaHandler.Set.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteMetaHandler.Set err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	meta, err := h.App.NoteMetaService.Set(ctx, uid, params)
+	if err != nil {
+		h.noteMetaErr(ctx, "NoteMetaHandler.Set", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(meta))
+}
+
+// Delete removes one metadata entry of a note
+// @Summary Delete note metadata
+// @Description Remove one custom attribute of a note
+// @Tags Note Meta
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.NoteMetaDeleteRequest true "Delete Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteMetaDTO} "Success"
+// @Router /api/note/meta [delete]
+func (h *NoteMetaHandler) Delete(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteMetaDeleteRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteMetaHandler.Delete.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteMetaHandler.Delete err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	meta, err := h.App.NoteMetaService.Delete(ctx, uid, params)
+	if err != nil {
+		h.noteMetaErr(ctx, "NoteMetaHandler.Delete", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(meta))
+}
+
+// noteMetaErr records error log
+// noteMetaErr 记录错误日志
+func (h *NoteMetaHandler) noteMetaErr(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
+
+// noteMetas returns the custom metadata of the given notes by note ID, logging instead of failing the response
+// noteMetas 按笔记 ID 返回指定笔记的自定义元数据，出错时仅记录日志而不影响响应
+func (h *Handler) noteMetas(ctx context.Context, uid int64, noteIDs []int64) map[int64]map[string]any {
+	if h.App.NoteMetaService == nil || len(noteIDs) == 0 {
+		return nil
+	}
+	metas, err := h.App.NoteMetaService.MapByNoteIDs(ctx, uid, noteIDs)
+	if err != nil {
+		h.App.Logger().Error("NoteMetaService.MapByNoteIDs err", zap.Error(err), zap.String("traceId", middleware.GetTraceID(ctx)))
+		return nil
+	}
+	return metas
+}
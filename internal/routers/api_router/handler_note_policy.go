@@ -0,0 +1,251 @@
+package api_router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// NotePolicyHandler note lifecycle policy API router handler
+// NotePolicyHandler 笔记生命周期策略 API 路由处理器
+type NotePolicyHandler struct {
+	*Handler
+}
+
+// NewNotePolicyHandler creates NotePolicyHandler instance
+// NewNotePolicyHandler 创建 NotePolicyHandler 实例
+func NewNotePolicyHandler(a *app.App) *NotePolicyHandler {
+	return &NotePolicyHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// GetPolicies gets the note lifecycle policies of the current user
+// @Summary Get note lifecycle policies
+// @Tags NotePolicy
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=[]dto.NotePolicyDTO} "Success"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/note-policies [get]
+func (h *NotePolicyHandler) GetPolicies(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	list, err := h.App.NotePolicyService.List(c.Request.Context(), uid)
+	if err != nil {
+		h.logError(c.Request.Context(), "NotePolicyHandler.GetPolicies", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(list))
+}
+
+// UpdatePolicy creates or updates a note lifecycle policy
+// @Summary Create or update a note lifecycle policy
+// @Description Archives (moves into archiveFolder, keeping the folder structure) or deletes (moves into the trash) the notes in folder that are older than olderThanDays and, if set, carry tag. Enabled policies run once a day; notes already in archiveFolder are never matched.
+// @Tags NotePolicy
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.NotePolicyRequest true "Policy Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NotePolicyDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/note-policy [post]
+func (h *NotePolicyHandler) UpdatePolicy(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NotePolicyRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	policy, err := h.App.NotePolicyService.Save(c.Request.Context(), uid, params)
+	if err != nil {
+		h.logError(c.Request.Context(), "NotePolicyHandler.UpdatePolicy", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessUpdate.WithData(policy))
+}
+
+// DeletePolicy deletes a note lifecycle policy and its run history
+// @Summary Delete a note lifecycle policy
+// @Tags NotePolicy
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.NotePolicyIDRequest true "Policy ID"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/note-policy [delete]
+func (h *NotePolicyHandler) DeletePolicy(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NotePolicyIDRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	if err := h.App.NotePolicyService.Delete(c.Request.Context(), uid, params.ID); err != nil {
+		h.logError(c.Request.Context(), "NotePolicyHandler.DeletePolicy", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessDelete)
+}
+
+// Preview lists the notes a run of the policy would process, without changing anything
+// @Summary Dry-run a note lifecycle policy
+// @Tags NotePolicy
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.NotePolicyIDRequest true "Policy ID"
+// @Success 200 {object} pkgapp.Res{data=dto.NotePolicyPreviewDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/note-policy/preview [get]
+func (h *NotePolicyHandler) Preview(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NotePolicyIDRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	preview, err := h.App.NotePolicyService.Preview(c.Request.Context(), uid, params.ID)
+	if err != nil {
+		h.logError(c.Request.Context(), "NotePolicyHandler.Preview", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(preview))
+}
+
+// Execute runs a note lifecycle policy immediately
+// @Summary Run a note lifecycle policy now
+// @Tags NotePolicy
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.NotePolicyIDRequest true "Policy ID"
+// @Success 200 {object} pkgapp.Res{data=dto.NotePolicyRunDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/note-policy/execute [post]
+func (h *NotePolicyHandler) Execute(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NotePolicyIDRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	run, err := h.App.NotePolicyService.Execute(c.Request.Context(), uid, params.ID)
+	if err != nil {
+		h.logError(c.Request.Context(), "NotePolicyHandler.Execute", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(run))
+}
+
+// ListRuns gets the run history of the note lifecycle policies
+// @Summary Get note lifecycle policy run history
+// @Tags NotePolicy
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.NotePolicyRunListRequest true "Run History Parameters"
+// @Param page query int false "Page"
+// @Param pageSize query int false "Page Size"
+// @Success 200 {object} pkgapp.Res{data=pkgapp.ListRes{list=[]dto.NotePolicyRunDTO}} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/note-policy/runs [get]
+func (h *NotePolicyHandler) ListRuns(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NotePolicyRunListRequest{}
+	pager := pkgapp.NewPager(c)
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	list, total, err := h.App.NotePolicyService.ListRuns(c.Request.Context(), uid, params.PolicyID, pager.Page, pager.PageSize)
+	if err != nil {
+		h.logError(c.Request.Context(), "NotePolicyHandler.ListRuns", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponseList(code.Success, list, int(total))
+}
+
+// logError logs an error with the trace ID of the request
+// logError 记录带请求追踪 ID 的错误日志
+func (h *NotePolicyHandler) logError(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
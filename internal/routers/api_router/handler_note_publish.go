@@ -0,0 +1,78 @@
+package api_router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// NotePublishHandler scheduled note publishing API router handler
+// NotePublishHandler 笔记定时发布 API 路由处理器
+type NotePublishHandler struct {
+	*Handler
+}
+
+// NewNotePublishHandler creates NotePublishHandler instance
+// NewNotePublishHandler 创建 NotePublishHandler 实例
+func NewNotePublishHandler(a *app.App) *NotePublishHandler {
+	return &NotePublishHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// List gets the publishing states of the notes scheduled through their frontmatter
+// @Summary Get scheduled note publishing status
+// @Description Notes whose frontmatter sets publish: true are shared automatically once publish_at (RFC 3339 or "2006-01-02 15:04", server time zone) has passed, or at once without publish_at. Removing publish or deleting the note revokes the share. The scheduler checks every minute.
+// @Tags NotePublish
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.NotePublishListRequest true "Status Parameters"
+// @Param page query int false "Page"
+// @Param pageSize query int false "Page Size"
+// @Success 200 {object} pkgapp.Res{data=pkgapp.ListRes{list=[]dto.NotePublishDTO}} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/note-publish [get]
+func (h *NotePublishHandler) List(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NotePublishListRequest{}
+	pager := pkgapp.NewPager(c)
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	list, total, err := h.App.NotePublishService.List(c.Request.Context(), uid, params, pager.Page, pager.PageSize)
+	if err != nil {
+		h.logError(c.Request.Context(), "NotePublishHandler.List", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponseList(code.Success, list, int(total))
+}
+
+// logError logs an error with the trace ID of the request
+// logError 记录带请求追踪 ID 的错误日志
+func (h *NotePublishHandler) logError(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
@@ -0,0 +1,69 @@
+package api_router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// NoteRenderHandler note Markdown rendering API router handler
+// NoteRenderHandler 笔记 Markdown 渲染 API 路由处理器
+type NoteRenderHandler struct {
+	*Handler
+}
+
+// NewNoteRenderHandler creates NoteRenderHandler instance
+// NewNoteRenderHandler 创建 NoteRenderHandler 实例
+func NewNoteRenderHandler(a *app.App) *NoteRenderHandler {
+	return &NoteRenderHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// Render renders a note to sanitized HTML
+// @Summary Render note to HTML
+// @Description Render the note addressed by vault and path, or the unsaved content of the request, to sanitized HTML. [[Wiki links]] point at the escaped path of the linked note with the class internal-link, ![[embeds]] and local images point at /api/file; targets that do not exist get the class is-unresolved. Frontmatter is returned separately. POST accepts the same parameters as a JSON body for long content. Requires a note read scope.
+// @Tags Note
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params query dto.NoteRenderRequest true "Render Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteRenderDTO} "Success"
+// @Router /api/note/render [get]
+// @Router /api/note/render [post]
+func (h *NoteRenderHandler) Render(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteRenderRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("NoteRenderHandler.Render.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("NoteRenderHandler.Render err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.App.NoteRenderService.Render(ctx, uid, params)
+	if err != nil {
+		h.App.Logger().Error("NoteRenderHandler.Render",
+			zap.Error(err),
+			zap.String("traceId", middleware.GetTraceID(ctx)),
+		)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(result))
+}
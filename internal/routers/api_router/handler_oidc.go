@@ -12,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
 	appconfig "github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	internaloidc "github.com/haierkeys/fast-note-sync-service/internal/oidc"
 	"github.com/haierkeys/fast-note-sync-service/internal/service"
@@ -159,9 +160,11 @@ func (h *OIDCHandler) Callback(c *gin.Context) {
 	user, err := h.App.OIDCService.Authenticate(c.Request.Context(), oidcServiceConfig(providerConfig), *claims, c.ClientIP(), "WebGUI", c.GetHeader("User-Agent"))
 	if err != nil {
 		h.App.Logger().Error("OIDCHandler.Callback.Authenticate", zap.Error(err))
+		h.audit(c, 0, domain.AuditActionLoginFailed, claims.Subject, err.Error())
 		apperrors.ErrorResponse(c, err)
 		return
 	}
+	h.audit(c, user.UID, domain.AuditActionLogin, user.Username, "oidc:"+providerConfig.ID)
 
 	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(renderOIDCCallbackSuccessHTML(user, state.RedirectTo)))
 }
@@ -225,6 +228,9 @@ func renderOIDCCallbackSuccessHTML(user *dto.UserDTO, redirectTo string) string
 		"tokenId":  fmt.Sprintf("%d", user.TokenID),
 		"user":     "true",
 	}
+	if user.TOTPSetupRequired {
+		payload["totpSetupRequired"] = "true"
+	}
 	raw, _ := json.Marshal(payload)
 	return fmt.Sprintf(`<!doctype html>
 <html>
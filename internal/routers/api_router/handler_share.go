@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"os"
 	"strconv"
 	"time"
 
@@ -12,8 +11,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"go.uber.org/zap"
@@ -116,20 +117,26 @@ func (h *ShareHandler) NoteGet(c *gin.Context) {
 		}
 		return
 	}
+	if entity := pkgapp.GetShareEntity(c); entity != nil {
+		h.recordNoteAccess(c, entity.UID, noteDTO, domain.NoteAccessChannelShare, service.ShareActor(entity.SID))
+	}
 
 	response.ToResponse(code.Success.WithData(noteDTO))
 }
 
 // GetSharedContent retrieves shared file content
 // @Summary Get shared attachment content
-// @Description Get raw binary data of a specific attachment via share token
+// @Description Get raw binary data of a specific attachment via share token, supports Range requests for seeking in audio and video or resuming downloads
 // @Tags Share
 // @Security ShareAuthToken
 // @Param Share-Token header string true "Auth Token"
 // @Produce octet-stream
 // @Param params query dto.ShareResourceRequest true "Get Parameters"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
 // @Success 200 {file} binary "Success"
+// @Success 206 {file} binary "Partial Content"
 // @Router /api/share/file [get]
+// @Router /api/share/file [head]
 func (h *ShareHandler) FileGet(c *gin.Context) {
 	response := pkgapp.NewResponse(c)
 	params := &dto.ShareResourceRequest{}
@@ -162,25 +169,12 @@ func (h *ShareHandler) FileGet(c *gin.Context) {
 		return
 	}
 
-	// Open file for zero-copy serving
-	file, err := os.Open(savePath)
-	if err != nil {
+	// Stream the file with Range support so media can be scrubbed and downloads resumed
+	// 以支持 Range 的方式流式输出文件，使媒体可拖动进度、下载可续传
+	if err := pkgapp.ServeFile(c, savePath, fileName, contentType, etag, time.UnixMilli(mtime), pkgapp.AttachmentCacheControl); err != nil {
 		h.logError(ctx, "ShareHandler.FileGet.Open", err)
 		c.AbortWithStatus(http.StatusNotFound)
-		return
 	}
-	defer file.Close()
-
-	// Set response headers and output content
-	if contentType != "" {
-		c.Header("Content-Type", contentType)
-	}
-	c.Header("Cache-Control", "public, s-maxage=31536000, max-age=31536000, must-revalidate")
-	if etag != "" {
-		c.Header("ETag", etag)
-	}
-
-	http.ServeContent(c.Writer, c.Request, fileName, time.UnixMilli(mtime), file)
 }
 
 // Query queries a share by path
@@ -0,0 +1,89 @@
+package api_router
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atom"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+)
+
+// Feed returns the recently modified notes of a shared folder as an Atom feed
+// @Summary Atom feed of a shared folder
+// @Description Publish the most recently modified notes below a shared folder as an Atom feed, each entry carrying a plain-text summary and the note rendered to sanitized HTML. The slug is the share token of the folder share followed by ".xml", as returned in the url of GET /api/shares. Password protected shares take the password query parameter.
+// @Tags Share
+// @Produce application/atom+xml
+// @Param slug path string true "Share token followed by .xml"
+// @Param password query string false "Share password"
+// @Success 200 {string} string "Atom feed"
+// @Failure 404 {object} pkgapp.Res "Share Not Found"
+// @Router /feed/{slug} [get]
+func (h *ShareHandler) Feed(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	token := strings.TrimSuffix(c.Param("slug"), ".xml")
+	ctx := c.Request.Context()
+
+	feed, err := h.App.ShareService.GetSharedFolderFeed(ctx, token, c.Query("password"))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrShareCancelled):
+			response.ToResponse(code.ErrorShareRevoked)
+		case errors.Is(err, domain.ErrShareExpired):
+			response.ToResponse(code.ErrorShareExpired)
+		case errors.Is(err, domain.ErrSharePasswordRequired):
+			response.ToResponse(code.ErrorSharePasswordRequired)
+		case errors.Is(err, domain.ErrSharePasswordInvalid):
+			response.ToResponse(code.ErrorSharePasswordInvalid)
+		default:
+			h.logError(ctx, "ShareHandler.Feed", err)
+			response.ToResponse(code.ErrorShareNotFound)
+		}
+		return
+	}
+
+	baseURL := strings.TrimSuffix(h.getShareBaseUrl(c), "/")
+	selfURL := baseURL + c.Request.URL.Path
+	// Tag URIs keep entry IDs stable while the share token in the feed URL is rotated
+	// 标签 URI 使条目 ID 在订阅源 URL 中的分享 Token 轮换时保持不变
+	host := c.Request.Host
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	idPrefix := fmt.Sprintf("tag:%s,%s:share/%d", host, feed.CreatedAt.UTC().Format("2006-01-02"), feed.ShareID)
+
+	out := &atom.Feed{
+		ID:        idPrefix,
+		Title:     feed.Title,
+		Subtitle:  feed.Vault + "/" + feed.Folder,
+		Updated:   atom.Time(feed.Updated),
+		Generator: app.Name,
+		Links:     []atom.Link{{Href: selfURL, Rel: "self", Type: "application/atom+xml"}},
+	}
+	for _, e := range feed.Entries {
+		out.Entries = append(out.Entries, &atom.Entry{
+			ID:        idPrefix + "/note/" + strconv.FormatInt(e.NoteID, 10),
+			Title:     e.Title,
+			Published: atom.Time(time.UnixMilli(e.Ctime)),
+			Updated:   atom.Time(time.UnixMilli(e.Mtime)),
+			Summary:   &atom.Text{Type: "text", Body: e.Summary},
+			Content:   &atom.Text{Type: "html", Body: e.HTML},
+		})
+	}
+
+	body, err := out.Marshal()
+	if err != nil {
+		h.logError(ctx, "ShareHandler.Feed.Marshal", err)
+		response.ToResponse(code.ErrorServerInternal)
+		return
+	}
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", body)
+}
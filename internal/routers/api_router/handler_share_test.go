@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
@@ -196,3 +197,48 @@ func TestShareHandler_List_Success(t *testing.T) {
 	assertResponseCode(t, w, code.Success.Code())
 	mockSvc.AssertExpectations(t)
 }
+
+func TestShareHandler_Feed_Success(t *testing.T) {
+	mockSvc := new(svcmocks.MockShareService)
+
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockSvc.On("GetSharedFolderFeed", mock.Anything, "token_123", "").
+		Return(&dto.ShareFolderFeed{
+			ShareID:   9,
+			Vault:     "main",
+			Folder:    "Blog",
+			Title:     "Blog",
+			Updated:   created,
+			CreatedAt: created,
+			Entries: []*dto.ShareFeedEntry{
+				{NoteID: 3, Path: "hello.md", Title: "Hello", Summary: "Hi", HTML: "<p>Hi</p>", Ctime: created.UnixMilli(), Mtime: created.UnixMilli()},
+			},
+		}, nil)
+
+	handler := newTestShareHandler(mockSvc, nil)
+	c, w := newShareTestContext("GET", "/feed/token_123.xml", "", 0)
+	c.Params = gin.Params{{Key: "slug", Value: "token_123.xml"}}
+
+	handler.Feed(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "<id>tag:example.com,2024-03-01:share/9/note/3</id>")
+	assert.Contains(t, w.Body.String(), `<link href="http://example.com/feed/token_123.xml" rel="self"`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestShareHandler_Feed_Revoked(t *testing.T) {
+	mockSvc := new(svcmocks.MockShareService)
+	mockSvc.On("GetSharedFolderFeed", mock.Anything, "token_123", "").
+		Return(nil, domain.ErrShareCancelled)
+
+	handler := newTestShareHandler(mockSvc, nil)
+	c, w := newShareTestContext("GET", "/feed/token_123.xml", "", 0)
+	c.Params = gin.Params{{Key: "slug", Value: "token_123.xml"}}
+
+	handler.Feed(c)
+
+	assertResponseCode(t, w, code.ErrorShareRevoked.Code())
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,227 @@
+package api_router
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// TemplateHandler note template API router handler
+// TemplateHandler 笔记模板 API 路由处理器
+type TemplateHandler struct {
+	*Handler
+}
+
+// NewTemplateHandler creates TemplateHandler instance
+// NewTemplateHandler 创建 TemplateHandler 实例
+func NewTemplateHandler(a *app.App) *TemplateHandler {
+	return &TemplateHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// List gets the note templates of the current user
+// @Summary Get note templates
+// @Tags Template
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=[]dto.NoteTemplateDTO} "Success"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/templates [get]
+func (h *TemplateHandler) List(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	list, err := h.App.TemplateService.List(c.Request.Context(), uid)
+	if err != nil {
+		h.logError(c.Request.Context(), "TemplateHandler.List", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(list))
+}
+
+// Get gets a note template
+// @Summary Get a note template
+// @Tags Template
+// @Security UserAuthToken
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteTemplateDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/templates/{id} [get]
+func (h *TemplateHandler) Get(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	id, ok := templateID(c)
+	if !ok {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails("invalid id"))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	template, err := h.App.TemplateService.Get(c.Request.Context(), uid, id)
+	if err != nil {
+		h.logError(c.Request.Context(), "TemplateHandler.Get", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(template))
+}
+
+// Create creates a note template
+// @Summary Create a note template
+// @Description Template content may use {{date}} (YYYY-MM-DD), {{time}} (HH:mm) and {{title}} (note file name without extension); {{date:FORMAT}} and {{time:FORMAT}} take a Moment.js format. Variables are expanded when a note is created with the template parameter of POST /api/note.
+// @Tags Template
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.NoteTemplateRequest true "Template Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteTemplateDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/templates [post]
+func (h *TemplateHandler) Create(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteTemplateRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	template, err := h.App.TemplateService.Create(c.Request.Context(), uid, params)
+	if err != nil {
+		h.logError(c.Request.Context(), "TemplateHandler.Create", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(template))
+}
+
+// Update updates a note template
+// @Summary Update a note template
+// @Tags Template
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param params body dto.NoteTemplateRequest true "Template Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.NoteTemplateDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/templates/{id} [put]
+func (h *TemplateHandler) Update(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.NoteTemplateRequest{}
+
+	id, ok := templateID(c)
+	if !ok {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails("invalid id"))
+		return
+	}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	template, err := h.App.TemplateService.Update(c.Request.Context(), uid, id, params)
+	if err != nil {
+		h.logError(c.Request.Context(), "TemplateHandler.Update", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessUpdate.WithData(template))
+}
+
+// Delete deletes a note template
+// @Summary Delete a note template
+// @Tags Template
+// @Security UserAuthToken
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/templates/{id} [delete]
+func (h *TemplateHandler) Delete(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	id, ok := templateID(c)
+	if !ok {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails("invalid id"))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	if err := h.App.TemplateService.Delete(c.Request.Context(), uid, id); err != nil {
+		h.logError(c.Request.Context(), "TemplateHandler.Delete", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessDelete)
+}
+
+// templateID parses the positive template ID of the :id path parameter
+// templateID 解析 :id 路径参数中的正整数模板 ID
+func templateID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// logError logs an error with the trace ID of the request
+// logError 记录带请求追踪 ID 的错误日志
+func (h *TemplateHandler) logError(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
@@ -0,0 +1,177 @@
+package api_router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// TwoFactorHandler two-factor authentication API router handler
+// TwoFactorHandler 两步验证 API 路由处理器
+type TwoFactorHandler struct {
+	*Handler
+}
+
+// NewTwoFactorHandler creates TwoFactorHandler instance
+// NewTwoFactorHandler 创建 TwoFactorHandler 实例
+func NewTwoFactorHandler(a *app.App) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// Status retrieves the current user's 2FA status
+// @Summary Get 2FA status
+// @Description Get whether TOTP two-factor authentication is enabled for the current user
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.TwoFactorStatusDTO} "Success"
+// @Router /api/user/totp [get]
+func (h *TwoFactorHandler) Status(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("TwoFactorHandler.Status err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	status, err := h.App.TwoFactorService.Status(ctx, uid)
+	if err != nil {
+		h.logError(ctx, "TwoFactorHandler.Status", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(status))
+}
+
+// Setup generates a new TOTP secret and provisioning URI
+// @Summary Set up 2FA
+// @Description Generate a new TOTP secret and otpauth:// URI for QR display; 2FA stays off until confirmed via enable
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.TwoFactorSetupDTO} "Success"
+// @Router /api/user/totp/setup [post]
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("TwoFactorHandler.Setup err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	setup, err := h.App.TwoFactorService.Setup(ctx, uid)
+	if err != nil {
+		h.logError(ctx, "TwoFactorHandler.Setup", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(setup))
+}
+
+// Enable confirms the pending secret and returns recovery codes
+// @Summary Enable 2FA
+// @Description Confirm the pending TOTP secret with a code; returns one-time recovery codes that are shown only once
+// @Tags User
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.TwoFactorCodeRequest true "TOTP Code"
+// @Success 200 {object} pkgapp.Res{data=dto.TwoFactorEnableDTO} "Success"
+// @Router /api/user/totp/enable [post]
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.TwoFactorCodeRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("TwoFactorHandler.Enable.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("TwoFactorHandler.Enable err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	result, err := h.App.TwoFactorService.Enable(ctx, uid, params.Code)
+	if err != nil {
+		h.logError(ctx, "TwoFactorHandler.Enable", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(result))
+}
+
+// Disable turns off 2FA for the current user
+// @Summary Disable 2FA
+// @Description Disable TOTP two-factor authentication after verifying a TOTP or recovery code
+// @Tags User
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.TwoFactorCodeRequest true "TOTP or Recovery Code"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Router /api/user/totp/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.TwoFactorCodeRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("TwoFactorHandler.Disable.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("TwoFactorHandler.Disable err uid=0")
+		response.ToResponse(code.ErrorInvalidUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	if err := h.App.TwoFactorService.Disable(ctx, uid, params.Code); err != nil {
+		h.logError(ctx, "TwoFactorHandler.Disable", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success)
+}
+
+// logError records error log with Trace ID
+// logError 记录错误日志，包含 Trace ID
+func (h *TwoFactorHandler) logError(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
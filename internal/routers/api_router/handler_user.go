@@ -2,9 +2,14 @@ package api_router
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
@@ -31,14 +36,14 @@ func NewUserHandler(a *app.App) *UserHandler {
 
 // Register user registration
 // @Summary User registration
-// @Description Handle user registration HTTP request, validate parameters and call UserService. Registration may be disabled in server settings.
-// @Description 处理用户注册 HTTP 请求，验证参数并调用 UserService。注册功能可能在服务器设置中被禁用。
+// @Description Handle user registration HTTP request, validate parameters and call UserService. Registration may be disabled in server settings, an admin invitation code still admits the user then. With user.register-approval on, uninvited registrations wait for the admin and the response carries no user.
+// @Description 处理用户注册 HTTP 请求，验证参数并调用 UserService。注册功能可能在服务器设置中被禁用，此时仍可凭管理员发放的邀请码注册。开启 user.register-approval 时，无邀请码的注册需等待管理员审核，响应中不含用户信息。
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param params body dto.UserCreateRequest true "Register Parameters"
 // @Success 200 {object} pkgapp.Res{data=dto.UserDTO} "Success"
-// @Failure 400 {object} pkgapp.Res "Invalid Parameters / Registration Disabled / User Already Exists"
+// @Failure 400 {object} pkgapp.Res "Invalid Parameters / Registration Disabled / Invalid Invitation Code / User Already Exists"
 // @Router /api/user/register [post]
 func (h *UserHandler) Register(c *gin.Context) {
 	response := pkgapp.NewResponse(c)
@@ -68,6 +73,10 @@ func (h *UserHandler) Register(c *gin.Context) {
 		apperrors.ErrorResponse(c, err)
 		return
 	}
+	if userDTO == nil {
+		response.ToResponse(code.SuccessAwaitApproval)
+		return
+	}
 
 	response.ToResponse(code.Success.WithData(userDTO))
 }
@@ -103,14 +112,56 @@ func (h *UserHandler) Login(c *gin.Context) {
 	clientType := c.GetHeader("x-client")
 	userAgent := c.GetHeader("User-Agent")
 
+	// Refuse locked out clients and accounts before checking the password
+	// 校验密码前拒绝已被锁定的客户端与账号
+	guard := h.App.LoginGuard()
+	if guard != nil {
+		if left := guard.Locked(clientIP, params.Credentials); left > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(left.Round(time.Second).Seconds())))
+			response.ToResponse(code.ErrorUserLoginLocked.WithDetails("retry after " + left.Round(time.Second).String()))
+			return
+		}
+		if guard.NeedsCaptcha(clientIP, params.Credentials) {
+			if params.CaptchaToken == "" {
+				response.ToResponse(code.ErrorCaptchaRequired)
+				return
+			}
+			ok, err := guard.VerifyCaptcha(ctx, params.CaptchaToken, clientIP)
+			if err != nil {
+				h.logError(ctx, "UserHandler.Login.VerifyCaptcha", err)
+			}
+			if !ok {
+				response.ToResponse(code.ErrorCaptchaInvalid)
+				return
+			}
+		}
+	}
+
 	// Call UserService to perform login
 	// 调用 UserService 执行登录
 	userDTO, err := h.App.UserService.Login(ctx, params, clientIP, clientType, userAgent)
 	if err != nil {
 		h.logError(ctx, "UserHandler.Login", err)
+		// A missing TOTP code is the first step of a two-factor login, not a failed attempt
+		// 缺少动态码是两步验证登录的第一步，不算失败的登录尝试
+		if !errors.Is(err, code.ErrorUserTOTPRequired) {
+			h.audit(c, 0, domain.AuditActionLoginFailed, params.Credentials, err.Error())
+		}
+		// Wrong passwords and wrong second factors count towards the lockout
+		// 密码错误与第二因素错误计入锁定次数
+		if guard != nil && (errors.Is(err, code.ErrorUserLoginPasswordFailed) || errors.Is(err, code.ErrorUserTOTPInvalid)) {
+			for _, lock := range guard.Fail(clientIP, params.Credentials) {
+				h.audit(c, 0, domain.AuditActionLoginLocked, lock.Scope+":"+lock.Key,
+					fmt.Sprintf("scope=%s failures=%d lockout=%s", lock.Scope, lock.Failures, lock.Duration))
+			}
+		}
 		apperrors.ErrorResponse(c, err)
 		return
 	}
+	if guard != nil {
+		guard.Succeed(params.Credentials)
+	}
+	h.audit(c, userDTO.UID, domain.AuditActionLogin, userDTO.Username, "")
 
 	response.ToResponse(code.Success.WithData(userDTO))
 }
@@ -144,6 +195,36 @@ func (h *UserHandler) Logout(c *gin.Context) {
 	response.ToResponse(code.Success)
 }
 
+// LogoutAll logs the user out of every device
+// @Summary Log out of all devices
+// @Description Revoke every auth token of the current user, including the one of this request, and close their connections.
+// @Description 注销当前用户的所有认证 Token（包括本次请求使用的 Token），并关闭其连接。
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 401 {object} pkgapp.Res "Unauthorized"
+// @Router /api/auth/logout_all [post]
+func (h *UserHandler) LogoutAll(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("UserHandler.LogoutAll err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.App.TokenService.RevokeAll(ctx, uid); err != nil {
+		h.logError(ctx, "UserHandler.LogoutAll", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success)
+}
+
 // UserChangePassword changes user password
 // @Summary Change user password
 // @Description Handle password change request for current user, validate old password and update new password.
@@ -234,6 +315,213 @@ func (h *UserHandler) UserInfo(c *gin.Context) {
 	response.ToResponse(code.Success.WithData(userDTO))
 }
 
+// Capabilities returns the experimental features enabled for the current user
+// @Summary Get user capabilities
+// @Description Return the server-evaluated experimental feature flags of the current user. Operators roll features out to canary accounts in the feature-flags config before enabling them globally.
+// @Description 返回服务端为当前用户判断的实验性功能开关。运维人员可先在 feature-flags 配置中为灰度账号开启功能，再全局开启。
+// @Tags User
+// @Produce json
+// @Security UserAuthToken
+// @Success 200 {object} pkgapp.Res{data=dto.UserCapabilitiesDTO} "Success"
+// @Failure 401 {object} pkgapp.Res "Unauthorized"
+// @Router /api/user/capabilities [get]
+func (h *UserHandler) Capabilities(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("UserHandler.Capabilities err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(h.App.FeatureFlagService.Capabilities(c.Request.Context(), uid)))
+}
+
+// DataInventory returns the data inventory of the current user, or of another user for the admin
+// @Summary Get personal data inventory
+// @Description Return a machine-readable summary of every category of personal data stored for the user (account, notes, attachments, history, logs, shares, tokens, backup targets, Git remotes) to answer data-subject (GDPR) requests. Only counts and locations are returned, never content or credentials. The admin may pass uid to inspect another user.
+// @Description 返回为用户存储的各类个人数据的机器可读摘要（账号、笔记、附件、历史、日志、分享、Token、备份目标、Git 远端），用于答复数据主体（GDPR）请求。仅返回数量与位置，不含内容或凭据。管理员可传入 uid 查看其他用户。
+// @Tags User
+// @Produce json
+// @Security UserAuthToken
+// @Param uid query int64 false "User ID (admin only)"
+// @Success 200 {object} pkgapp.Res{data=dto.DataInventoryDTO} "Success"
+// @Failure 401 {object} pkgapp.Res "Unauthorized"
+// @Failure 403 {object} pkgapp.Res "Not Admin"
+// @Router /api/user/data-inventory [get]
+func (h *UserHandler) DataInventory(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("UserHandler.DataInventory err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	target := uid
+	if raw := c.Query("uid"); raw != "" {
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v <= 0 {
+			response.ToResponse(code.ErrorInvalidParams.WithDetails("uid"))
+			return
+		}
+		if adminUID := h.App.Config().User.AdminUID; v != uid && adminUID != 0 && uid != int64(adminUID) {
+			response.ToResponse(code.ErrorUserIsNotAdmin)
+			return
+		}
+		target = v
+	}
+
+	ctx := c.Request.Context()
+	inventory, err := h.App.DataInventoryService.Get(ctx, target)
+	if err != nil {
+		h.logError(ctx, "UserHandler.DataInventory", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(inventory))
+}
+
+// Usage returns the disk usage of the current user
+// @Summary Get disk usage
+// @Description Get the bytes the current user uses on disk for note content files, attachments, history snapshots, settings, in-progress backups and the SQLite databases, with the content size of each vault. The numbers are kept up to date incrementally instead of walking the storage on every call.
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.UserUsageDTO} "Success"
+// @Failure 401 {object} pkgapp.Res "Unauthorized"
+// @Router /api/user/usage [get]
+func (h *UserHandler) Usage(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("UserHandler.Usage err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	usage, err := h.App.UsageService.User(ctx, uid)
+	if err != nil {
+		h.logError(ctx, "UserHandler.Usage", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(usage))
+}
+
+// Heatmap returns the writing activity heatmap of the current user
+// @Summary Get writing activity heatmap
+// @Description Note edits saved per day across all vaults of the current user, GitHub contributions style: one entry per day up to today (default 365 days) with an intensity level from 0 to 4, plus totals and the current and longest writing streaks. Days are in server local time.
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.UserHeatmapRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.UserHeatmapDTO} "Success"
+// @Failure 401 {object} pkgapp.Res "Unauthorized"
+// @Router /api/user/heatmap [get]
+func (h *UserHandler) Heatmap(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.UserHeatmapRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("UserHandler.Heatmap.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("UserHandler.Heatmap err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	heatmap, err := h.App.NoteStatsService.Heatmap(ctx, uid, params)
+	if err != nil {
+		h.logError(ctx, "UserHandler.Heatmap", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(heatmap))
+}
+
+// Settings returns the settings of the current user
+// @Summary Get user settings
+// @Description Get the settings of the current user. softDeleteRetentionTime is the user-wide retention of soft-deleted notes, attachments and settings, empty when the server default applies; vaults may override it again through /api/vault/retention.
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.UserSettingsDTO} "Success"
+// @Router /api/user/settings [get]
+func (h *UserHandler) Settings(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("UserHandler.Settings err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	settings, err := h.App.RetentionService.GetUserSettings(ctx, uid)
+	if err != nil {
+		h.logError(ctx, "UserHandler.Settings", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(settings))
+}
+
+// UpdateSettings updates the settings of the current user
+// @Summary Update user settings
+// @Description Update the settings of the current user, fields left out are not changed. softDeleteRetentionTime takes durations like 30d or 12h, 0 keeps soft-deleted items forever and an empty string falls back to the server default.
+// @Tags User
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.UserSettingsRequest true "Settings"
+// @Success 200 {object} pkgapp.Res{data=dto.UserSettingsDTO} "Success"
+// @Router /api/user/settings [post]
+func (h *UserHandler) UpdateSettings(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.UserSettingsRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("UserHandler.UpdateSettings.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("UserHandler.UpdateSettings err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	settings, err := h.App.RetentionService.UpdateUserSettings(ctx, uid, params)
+	if err != nil {
+		h.logError(ctx, "UserHandler.UpdateSettings", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(settings))
+}
+
 // logError records error log, including Trace ID
 // logError 记录错误日志，包含 Trace ID
 func (h *UserHandler) logError(ctx context.Context, method string, err error) {
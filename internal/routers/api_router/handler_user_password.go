@@ -0,0 +1,139 @@
+package api_router
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// PasswordResetSend emails a password reset link
+// @Summary Send password reset email
+// @Description Email a reset link to the account of the address. Always succeeds for unknown addresses so registered emails cannot be discovered. Needs alert.smtp and server.ext-api-url to be configured.
+// @Description 向该地址的账号发送密码重置链接。地址未注册时同样返回成功，避免探测已注册邮箱。需配置 alert.smtp 与 server.ext-api-url。
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param params body dto.UserPasswordResetSendRequest true "Reset Email Parameters"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Parameters / Reset Disabled"
+// @Router /api/user/password/forgot [post]
+func (h *UserHandler) PasswordResetSend(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.UserPasswordResetSendRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("UserHandler.PasswordResetSend.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.App.UserService.RequestPasswordReset(ctx, params.Email); err != nil {
+		h.logError(ctx, "UserHandler.PasswordResetSend", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success)
+}
+
+// PasswordReset sets a new password with the token of a reset link
+// @Summary Reset password
+// @Description Set a new password with the token of a reset email. The token stops working once the password has changed, and every token of the user is revoked.
+// @Description 使用重置邮件中的令牌设置新密码。密码修改后令牌即失效，并撤销该用户的全部 Token。
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param params body dto.UserResetPasswordRequest true "Reset Password Parameters"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Parameters / Invalid Or Expired Link"
+// @Router /api/user/password/reset [post]
+func (h *UserHandler) PasswordReset(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.UserResetPasswordRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("UserHandler.PasswordReset.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.App.UserService.ResetPassword(ctx, params); err != nil {
+		h.logError(ctx, "UserHandler.PasswordReset", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessPasswordUpdate)
+}
+
+// PasswordResetPage serves the page the reset emails link to
+// @Summary Password reset page
+// @Description HTML page opened by the link of a reset email, it reads the token from the query and posts the new password to /api/user/password/reset.
+// @Description 重置邮件链接打开的 HTML 页面，从查询参数读取令牌并将新密码提交到 /api/user/password/reset。
+// @Tags User
+// @Produce html
+// @Param token query string true "Reset token"
+// @Success 200 {string} string "HTML page"
+// @Router /api/user/password/reset [get]
+func (h *UserHandler) PasswordResetPage(c *gin.Context) {
+	// The token never reaches the markup, the script reads it from the address bar
+	// 令牌不会写入页面内容，由脚本从地址栏读取
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(passwordResetHTML))
+}
+
+const passwordResetHTML = `<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex, nofollow">
+<title>Reset password / 重置密码</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 360px; margin: 10vh auto; padding: 0 16px; }
+input, button { display: block; width: 100%; box-sizing: border-box; margin: 8px 0; padding: 8px; }
+#msg { min-height: 1.5em; }
+</style>
+</head>
+<body>
+<h2>Reset password / 重置密码</h2>
+<form id="form">
+<input id="password" type="password" placeholder="New password / 新密码" autocomplete="new-password" required>
+<input id="confirm" type="password" placeholder="Confirm password / 确认密码" autocomplete="new-password" required>
+<button type="submit">Reset / 重置</button>
+</form>
+<p id="msg"></p>
+<script>
+const token = new URLSearchParams(location.search).get("token") || "";
+const msg = document.getElementById("msg");
+document.getElementById("form").addEventListener("submit", async (e) => {
+  e.preventDefault();
+  const res = await fetch("/api/user/password/reset", {
+    method: "POST",
+    headers: { "Content-Type": "application/json", "x-client": "webgui" },
+    body: JSON.stringify({
+      token: token,
+      password: document.getElementById("password").value,
+      confirmPassword: document.getElementById("confirm").value,
+    }),
+  });
+  const body = await res.json().catch(() => ({}));
+  msg.textContent = body.message || res.statusText;
+  if (body.status) {
+    document.getElementById("form").remove();
+    setTimeout(() => location.replace("/"), 2000);
+  }
+});
+</script>
+</body>
+</html>`
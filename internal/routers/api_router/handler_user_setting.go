@@ -0,0 +1,157 @@
+package api_router
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/routers/websocket_router"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// UserSettingHandler per-user settings API router handler
+// UserSettingHandler 用户级设置 API 路由处理器
+type UserSettingHandler struct {
+	*Handler
+}
+
+// NewUserSettingHandler creates UserSettingHandler instance
+// NewUserSettingHandler 创建 UserSettingHandler 实例
+func NewUserSettingHandler(a *app.App, wss *pkgapp.WebsocketServer) *UserSettingHandler {
+	return &UserSettingHandler{
+		Handler: NewHandlerWithWSS(a, wss),
+	}
+}
+
+// Get retrieves a per-user setting
+// @Summary Get user setting
+// @Description Get a per-user setting by key. Per-user settings are key-value pairs shared by all devices of the user, independent of vaults, e.g. theme, editor preferences or a plugin settings blob.
+// @Description 根据键获取用户级设置。用户级设置是该用户所有设备共享的键值对，与笔记库无关，例如主题、编辑器偏好或插件设置数据。
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.UserSettingGetRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.UserSettingDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Parameters / Setting Not Found"
+// @Router /api/user/setting [get]
+func (h *UserSettingHandler) Get(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.UserSettingGetRequest{}
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		h.App.Logger().Error("UserSettingHandler.Get.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	res, err := h.App.UserSettingService.Get(c.Request.Context(), uid, params)
+	if err != nil {
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(res))
+}
+
+// Sync lists the per-user settings changed since a cursor
+// @Summary Sync user settings
+// @Description List the per-user settings updated after lastTime, deletions included, and the cursor for the next call. lastTime 0 lists every current setting. The WebSocket action SettingsSync takes the same parameters and replies with the same message.
+// @Description 列出 lastTime 之后更新的用户级设置（包含删除）及下次调用的游标。lastTime 为 0 时列出全部现有设置。WebSocket 动作 SettingsSync 使用相同参数并回复相同消息。
+// @Tags User
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.UserSettingListRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.UserSettingsSyncMessage} "Success"
+// @Router /api/user/setting/sync [get]
+func (h *UserSettingHandler) Sync(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.UserSettingListRequest{}
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		h.App.Logger().Error("UserSettingHandler.Sync.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	res, err := h.App.UserSettingService.ListSince(c.Request.Context(), uid, params)
+	if err != nil {
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(res))
+}
+
+// Put writes a per-user setting
+// @Summary Write user setting
+// @Description Create or overwrite a per-user setting. version is the version the change is based on (0 for a new key); when another device has written since, the change is refused with a conflict carrying the current setting unless its mtime is newer than the stored one. Other devices of the user receive the change through the WebSocket action SettingsSync.
+// @Description 创建或覆盖用户级设置。version 为修改所基于的版本（新键为 0）；若其间已有其他设备写入，除非 mtime 晚于已存储的版本，否则拒绝修改并返回携带当前设置的冲突错误。该用户的其他设备通过 WebSocket 动作 SettingsSync 接收变更。
+// @Tags User
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.UserSettingPutRequest true "Setting"
+// @Success 200 {object} pkgapp.Res{data=dto.UserSettingDTO} "Success"
+// @Failure 400 {object} pkgapp.Res{data=dto.UserSettingDTO} "Invalid Parameters / Conflict, data holds the current setting"
+// @Router /api/user/setting [post]
+func (h *UserSettingHandler) Put(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.UserSettingPutRequest{}
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		h.App.Logger().Error("UserSettingHandler.Put.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	res, err := h.App.UserSettingService.Put(c.Request.Context(), uid, params)
+	h.respondWrite(c, response, uid, res, err)
+}
+
+// Delete deletes a per-user setting
+// @Summary Delete user setting
+// @Description Delete a per-user setting, with the same version handling as writing it. Other devices of the user receive the deletion through the WebSocket action SettingsSync.
+// @Description 删除用户级设置，版本处理与写入相同。该用户的其他设备通过 WebSocket 动作 SettingsSync 接收删除。
+// @Tags User
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.UserSettingDeleteRequest true "Setting"
+// @Success 200 {object} pkgapp.Res{data=dto.UserSettingDTO} "Success"
+// @Failure 400 {object} pkgapp.Res{data=dto.UserSettingDTO} "Invalid Parameters / Setting Not Found / Conflict, data holds the current setting"
+// @Router /api/user/setting [delete]
+func (h *UserSettingHandler) Delete(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.UserSettingDeleteRequest{}
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		h.App.Logger().Error("UserSettingHandler.Delete.BindAndValid errs", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	res, err := h.App.UserSettingService.Delete(c.Request.Context(), uid, params)
+	h.respondWrite(c, response, uid, res, err)
+}
+
+// respondWrite answers a write and pushes it to the devices of the user; a conflict carries the current setting
+// respondWrite 响应写入并推送给该用户的设备；冲突时携带当前设置
+func (h *UserSettingHandler) respondWrite(c *gin.Context, response *pkgapp.Response, uid int64, res *dto.UserSettingDTO, err error) {
+	if errors.Is(err, code.ErrorUserSettingConflict) {
+		response.ToResponse(code.ErrorUserSettingConflict.WithData(res))
+		return
+	}
+	if err != nil {
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(res))
+	h.WSS.BroadcastToUser(uid, code.Success.WithData(&dto.UserSettingsSyncMessage{
+		Settings: []*dto.UserSettingDTO{res},
+	}), websocket_router.UserSettingsSync)
+}
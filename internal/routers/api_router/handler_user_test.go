@@ -3,19 +3,26 @@
 package api_router
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
 	svcmocks "github.com/haierkeys/fast-note-sync-service/internal/service/mocks"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/loginguard"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // newUserTestContext creates a gin.Context suitable for UserHandler tests.
@@ -191,3 +198,72 @@ func TestUserHandler_ChangePassword_NoUID(t *testing.T) {
 	assertResponseCode(t, w, code.ErrorNotUserAuthToken.Code())
 	mockSvc.AssertExpectations(t)
 }
+
+// --- Capabilities ---
+
+// TestUserHandler_Capabilities_CanaryUser verifies a canary user sees the feature enabled for them.
+// TestUserHandler_Capabilities_CanaryUser 验证灰度用户能看到为其开启的功能。
+func TestUserHandler_Capabilities_CanaryUser(t *testing.T) {
+	flags := config.FeatureFlagsConfig{service.FeatureDeltaSync: {UIDs: []int64{7}}}
+	handler := NewUserHandler(app.NewTestApp(&app.Services{
+		FeatureFlagService: service.NewFeatureFlagService(&flags),
+	}))
+
+	c, w := newUserTestContext("GET", "/api/user/capabilities", "", 7)
+	handler.Capabilities(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertResponseCode(t, w, code.Success.Code())
+	assert.Contains(t, w.Body.String(), `"features":{"delta-sync":true,"semantic-search":false}`)
+	assert.Contains(t, w.Body.String(), `"enabled":["delta-sync"]`)
+}
+
+// TestUserHandler_Capabilities_NoUID verifies auth error when UID is missing.
+// TestUserHandler_Capabilities_NoUID 验证缺少 UID 时返回认证错误。
+func TestUserHandler_Capabilities_NoUID(t *testing.T) {
+	handler := NewUserHandler(app.NewTestApp(&app.Services{}))
+
+	c, w := newUserTestContext("GET", "/api/user/capabilities", "", 0)
+	handler.Capabilities(c)
+
+	assertResponseCode(t, w, code.ErrorNotUserAuthToken.Code())
+}
+
+// TestUserHandler_Login_LockoutIgnoresSpoofedForwardedFor verifies the IP lockout is keyed on the trusted client IP,
+// so a direct client cannot escape it by sending a new X-Forwarded-For on every attempt.
+// TestUserHandler_Login_LockoutIgnoresSpoofedForwardedFor 验证 IP 锁定按可信客户端 IP 计数，
+// 直连客户端无法通过每次更换 X-Forwarded-For 绕过锁定。
+func TestUserHandler_Login_LockoutIgnoresSpoofedForwardedFor(t *testing.T) {
+	mockSvc := new(svcmocks.MockUserService)
+	mockSvc.On("Login", mock.Anything, mock.AnythingOfType("*dto.UserLoginRequest"), mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, code.ErrorUserLoginPasswordFailed)
+
+	testApp := app.NewTestApp(&app.Services{UserService: mockSvc})
+	testApp.SetLoginGuard(loginguard.New(loginguard.Config{IPThreshold: 2, BaseLockout: time.Minute}, nil))
+	handler := NewUserHandler(testApp)
+
+	// gin.New trusts every proxy by default, trust loopback only like the server engine does
+	// gin.New 默认信任所有代理，与服务器引擎一样只信任本地回环地址
+	r := gin.New()
+	require.NoError(t, r.SetTrustedProxies(middleware.TrustedProxyList(nil)))
+	r.Use(middleware.Proxy(nil))
+	r.POST("/api/user/login", handler.Login)
+
+	login := func(n int) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"credentials":"user%d@example.com","password":"wrong"}`, n)
+		req := httptest.NewRequest(http.MethodPost, "/api/user/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", n))
+		req.RemoteAddr = "203.0.113.7:4000"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assertResponseCode(t, login(1), code.ErrorUserLoginPasswordFailed.Code())
+	assertResponseCode(t, login(2), code.ErrorUserLoginPasswordFailed.Code())
+	w := login(3)
+	assertResponseCode(t, w, code.ErrorUserLoginLocked.Code())
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	mockSvc.AssertNumberOfCalls(t, "Login", 2)
+}
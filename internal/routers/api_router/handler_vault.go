@@ -1,16 +1,26 @@
 package api_router
 
 import (
+	"archive/zip"
 	"context"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"github.com/haierkeys/fast-note-sync-service/pkg/jobqueue"
+	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"go.uber.org/zap"
 )
@@ -331,3 +341,688 @@ func (h *VaultHandler) ForceDeleteDataItem(c *gin.Context) {
 
 	response.ToResponse(code.SuccessDelete)
 }
+
+// Trash lists the deleted notes, files and folders of a vault
+// @Summary List vault trash
+// @Description List deleted notes, files and folders of a vault with their deletion timestamps, most recently deleted first
+// @Tags Vault
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.VaultTrashRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=[]dto.VaultTrashItemDTO} "Success"
+// @Router /api/vault/trash [get]
+func (h *VaultHandler) Trash(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultTrashRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.Trash.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.Trash err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	items, err := h.App.VaultService.Trash(ctx, uid, params.Vault)
+	if err != nil {
+		h.logError(ctx, "VaultHandler.Trash", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(items))
+}
+
+// Activity lists the recent changes of a vault grouped by day
+// @Summary Get vault activity timeline
+// @Description Recent note creations, edits, renames, deletions, attachment uploads and restores of a vault, newest first and grouped by day (server local time). Built from the current note and file records plus the audit log, so earlier edits of a path that changed again are not listed.
+// @Tags Vault
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.VaultActivityRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.VaultActivityDTO} "Success"
+// @Router /api/vault/activity [get]
+func (h *VaultHandler) Activity(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultActivityRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.Activity.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.Activity err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	activity, err := h.App.VaultService.Activity(ctx, uid, params)
+	if err != nil {
+		h.logError(ctx, "VaultHandler.Activity", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(activity))
+}
+
+// Stats returns the word count statistics of a vault
+// @Summary Get vault word count statistics
+// @Description Word and character counts of a vault: totals with estimated reading time, per folder, the longest notes and the writing activity per day (edits and words added or removed, server local time). Each CJK character counts as one word, frontmatter is not counted.
+// @Tags Vault
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.VaultStatsRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.VaultStatsDTO} "Success"
+// @Router /api/vault/stats [get]
+func (h *VaultHandler) Stats(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultStatsRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.Stats.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.Stats err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	stats, err := h.App.NoteStatsService.Stats(ctx, uid, params)
+	if err != nil {
+		h.logError(ctx, "VaultHandler.Stats", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(stats))
+}
+
+// SyncStatus reports the sync health of the vaults and devices
+// @Summary Get vault sync status
+// @Description Per vault and client: last successful sync, synced changes, bytes transferred and recent failures over the last days (default 7, at most 90). Per device: online state, last sync and the broadcast changes it has not received yet. upToDate is true when no device has pending changes and no client failed its last sync.
+// @Tags Vault
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.VaultSyncStatusRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.SyncStatusDTO} "Success"
+// @Router /api/vault/sync-status [get]
+func (h *VaultHandler) SyncStatus(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultSyncStatusRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.SyncStatus.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.SyncStatus err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	var online map[string]bool
+	var pending map[string]int64
+	if wss := h.App.GetWSS(); wss != nil {
+		online = wss.GetActiveDeviceKeys(uid)
+		pending = wss.PendingSyncEvents(uid)
+	}
+
+	ctx := c.Request.Context()
+	status, err := h.App.SyncStatusService.Status(ctx, uid, params, online, pending)
+	if err != nil {
+		h.logError(ctx, "VaultHandler.SyncStatus", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(status))
+}
+
+// Retention lists the soft delete retention of the vaults
+// @Summary List vault soft delete retention
+// @Description List every vault with its own soft delete retention, empty when it inherits the user setting, and the retention actually applied. 0 keeps soft-deleted items forever.
+// @Tags Vault
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=[]dto.VaultRetentionDTO} "Success"
+// @Router /api/vault/retention [get]
+func (h *VaultHandler) Retention(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.Retention err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	list, err := h.App.RetentionService.ListVaults(ctx, uid)
+	if err != nil {
+		h.logError(ctx, "VaultHandler.Retention", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(list))
+}
+
+// UpdateRetention sets the soft delete retention of a vault
+// @Summary Set vault soft delete retention
+// @Description Override the soft delete retention of one vault, e.g. 7d to purge a work vault aggressively or 0 to keep the deleted items of a personal vault forever. An empty retention removes the override so the vault inherits the user setting again.
+// @Tags Vault
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.VaultRetentionRequest true "Retention"
+// @Success 200 {object} pkgapp.Res{data=dto.VaultRetentionDTO} "Success"
+// @Router /api/vault/retention [post]
+func (h *VaultHandler) UpdateRetention(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultRetentionRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.UpdateRetention.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.UpdateRetention err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	item, err := h.App.RetentionService.UpdateVault(ctx, uid, params)
+	if err != nil {
+		h.logError(ctx, "VaultHandler.UpdateRetention", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(item))
+}
+
+// Graph returns the note link graph of a vault
+// @Summary Get vault link graph
+// @Description Get the notes of a vault with their tags and the [[wiki links]] between them for graph views. Folder and tag filter the notes; path with depth (default 1, at most 5) returns only the notes within that many link hops of the note, in either direction. Links to missing notes are left out and at most 5000 notes are returned. Requires a note read scope.
+// @Tags Vault
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.VaultGraphRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.VaultGraphDTO} "Success"
+// @Router /api/vault/graph [get]
+func (h *VaultHandler) Graph(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultGraphRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.Graph.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.Graph err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	graph, err := h.App.NoteLinkService.GetGraph(ctx, uid, params)
+	if err != nil {
+		h.logError(ctx, "VaultHandler.Graph", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(graph))
+}
+
+// AsOf lists the notes of a vault as they existed at a point in time
+// @Summary Browse vault as of a point in time
+// @Description List the notes that existed in a vault at the given timestamp (milliseconds), sorted by path, for a read-only time machine view; notes purged from the trash are not listed
+// @Tags Vault
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.VaultAsOfRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=[]dto.VaultAsOfItemDTO} "Success"
+// @Router /api/vault/as-of [get]
+func (h *VaultHandler) AsOf(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultAsOfRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.AsOf.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.AsOf err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	items, err := h.App.VaultService.AsOf(ctx, uid, params)
+	if err != nil {
+		h.logError(ctx, "VaultHandler.AsOf", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(items))
+}
+
+// AsOfNote gets the content of a note as it existed at a point in time
+// @Summary Get note content as of a point in time
+// @Description Get the content a note had at the given timestamp (milliseconds), reconstructed from its history versions; precision is limited to the retained history
+// @Tags Vault
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.VaultAsOfNoteRequest true "Query Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.VaultAsOfNoteDTO} "Success"
+// @Router /api/vault/as-of/note [get]
+func (h *VaultHandler) AsOfNote(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultAsOfNoteRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.AsOfNote.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.AsOfNote err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	note, err := h.App.VaultService.AsOfNote(ctx, uid, params)
+	if err != nil {
+		h.logError(ctx, "VaultHandler.AsOfNote", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(note))
+}
+
+// EmptyTrash permanently deletes selected trash items or the whole trash of a vault
+// @Summary Empty vault trash
+// @Description Permanently delete the selected notes, files and folders in the trash, or everything when all is true, instead of waiting for the soft delete retention time
+// @Tags Vault
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.VaultTrashEmptyRequest true "Purge Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.VaultTrashEmptyResult} "Success"
+// @Router /api/vault/trash/empty [post]
+func (h *VaultHandler) EmptyTrash(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultTrashEmptyRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.EmptyTrash.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.EmptyTrash err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	clientType, clientName, clientVer := h.getClientInfo(c)
+	result, err := h.App.VaultService.EmptyTrash(ctx, uid, params, clientType, clientName, clientVer)
+	if err != nil {
+		h.logError(ctx, "VaultHandler.EmptyTrash", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessDelete.WithData(result))
+}
+
+// Import imports a ZIP of Markdown files into a vault
+// @Summary Import ZIP into vault
+// @Description Import notes (.md) and attachments from a ZIP archive into a vault. The archive is either uploaded as "file" or, for admins, read from serverPath. The import runs as a background job, the request returns once it is queued. Progress and the outcome are pushed to the user's WebSocket connections as VaultImportProgress
+// @Tags Vault
+// @Security UserAuthToken
+// @Accept multipart/form-data
+// @Produce json
+// @Param vault formData string true "Vault name"
+// @Param folder formData string false "Target folder inside the vault"
+// @Param serverPath formData string false "ZIP path on the server (admin only)"
+// @Param file formData file false "ZIP archive"
+// @Success 200 {object} pkgapp.Res{data=dto.VaultImportJob} "Import queued"
+// @Router /api/vault/import [post]
+func (h *VaultHandler) Import(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultImportRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.Import.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.Import err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	zipPath := params.ServerPath
+	var cleanup func()
+
+	if zipPath != "" {
+		// Reading arbitrary server paths is restricted to the admin
+		// 读取服务器任意路径仅限管理员
+		cfg := h.App.Config()
+		if cfg.User.AdminUID != 0 && uid != int64(cfg.User.AdminUID) {
+			response.ToResponse(code.ErrorUserIsNotAdmin)
+			return
+		}
+		if info, err := os.Stat(zipPath); err != nil || info.IsDir() {
+			response.ToResponse(code.ErrorInvalidParams.WithDetails("serverPath is not a readable file"))
+			return
+		}
+	} else {
+		file, err := c.FormFile("file")
+		if err != nil {
+			response.ToResponse(code.ErrorInvalidParams.WithDetails("file or serverPath is required"))
+			return
+		}
+
+		tempDir := h.App.Config().App.TempPath
+		if tempDir == "" {
+			tempDir = "storage/temp"
+		}
+		_ = os.MkdirAll(tempDir, 0755)
+		zipPath = filepath.Join(tempDir, uuid.New().String()+".zip")
+
+		if err := c.SaveUploadedFile(file, zipPath); err != nil {
+			h.logError(ctx, "VaultHandler.Import.SaveUploadedFile", err)
+			response.ToResponse(code.Failed.WithDetails("failed to save temp file"))
+			return
+		}
+		// The uploaded archive is removed once the job is done // 上传的压缩包在任务结束后删除
+		uploaded := zipPath
+		cleanup = func() { _ = os.Remove(uploaded) }
+	}
+
+	// Reject a broken archive before queueing the job // 排队前拒绝损坏的压缩包
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		if cleanup != nil {
+			cleanup()
+		}
+		response.ToResponse(code.ErrorInvalidParams.WithDetails("invalid zip archive: " + err.Error()))
+		return
+	}
+	_ = zr.Close()
+
+	importService := h.App.ImportService.WithClient(h.getClientInfo(c))
+	jobID, err := h.runImport(uid, params.Vault, func(ctx context.Context, progress func(*dto.VaultImportProgressMessage)) error {
+		_, err := importService.ImportZip(ctx, uid, params, zipPath, progress)
+		return err
+	}, cleanup)
+	if err != nil {
+		h.logError(ctx, "VaultHandler.Import", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(&dto.VaultImportJob{JobID: jobID}))
+}
+
+// runImport runs a vault import as a background job, imports outlive the request timeout. Progress and the
+// outcome are pushed to the user's WebSocket connections as VaultImportProgress; cleanup, when not nil, runs
+// once the job is done or could not be queued
+// runImport 以后台任务执行保险库导入，导入耗时会超过请求超时。进度与结果以 VaultImportProgress 推送到用户的
+// WebSocket 连接；cleanup 不为 nil 时在任务结束或未能排队后执行
+func (h *VaultHandler) runImport(uid int64, vault string, run func(ctx context.Context, progress func(*dto.VaultImportProgressMessage)) error, cleanup func()) (string, error) {
+	wss := h.App.GetWSS()
+	progress := func(msg *dto.VaultImportProgressMessage) {
+		if wss != nil {
+			wss.BroadcastToUser(uid, code.Success.WithData(msg).WithVault(vault), "VaultImportProgress")
+		}
+	}
+	job := func(ctx context.Context) error {
+		if cleanup != nil {
+			defer cleanup()
+		}
+		err := run(ctx, progress)
+		if err != nil {
+			h.App.Logger().Error("VaultHandler.runImport", zap.Int64("uid", uid), zap.String("vault", vault), zap.Error(err))
+			progress(&dto.VaultImportProgressMessage{Done: true, Error: err.Error()})
+		}
+		return err
+	}
+
+	queue := h.App.Jobs()
+	if queue == nil {
+		safego.Go(h.App.Logger(), func() { _ = job(context.Background()) })
+		return "", nil
+	}
+	// A failed import is not retried, the entries imported so far are skipped as unchanged on the next run
+	// 失败的导入不重试，再次导入时已导入的条目会因未变化而跳过
+	jobID, err := queue.EnqueueWithOptions("vault.import", "", uid, jobqueue.JobOptions{MaxAttempts: 1, Timeout: -1}, job)
+	if err != nil && cleanup != nil {
+		cleanup()
+	}
+	return jobID, err
+}
+
+// ImportLocal imports an Obsidian vault directory on the server into a vault
+// @Summary Import local vault directory
+// @Description Import notes (.md) and attachments from a directory on the server, such as an existing Obsidian vault, keeping folder structure and file modification times. Hidden folders (.obsidian, .trash) and symlinks are skipped. Admin only. The import runs as a background job, the request returns once it is queued. Progress and the outcome are pushed to the user's WebSocket connections as VaultImportProgress
+// @Tags Vault
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.VaultImportLocalRequest true "Import Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.VaultImportJob} "Import queued"
+// @Router /api/vault/import/local [post]
+func (h *VaultHandler) ImportLocal(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultImportLocalRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.ImportLocal.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.ImportLocal err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	// Reading arbitrary server paths is restricted to the admin
+	// 读取服务器任意路径仅限管理员
+	cfg := h.App.Config()
+	if cfg.User.AdminUID != 0 && uid != int64(cfg.User.AdminUID) {
+		response.ToResponse(code.ErrorUserIsNotAdmin)
+		return
+	}
+	if info, err := os.Stat(params.Path); err != nil || !info.IsDir() {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails("path is not a readable directory"))
+		return
+	}
+
+	importParams := &dto.VaultImportRequest{Vault: params.Vault, Folder: params.Folder}
+	importService := h.App.ImportService.WithClient(h.getClientInfo(c))
+	jobID, err := h.runImport(uid, params.Vault, func(ctx context.Context, progress func(*dto.VaultImportProgressMessage)) error {
+		_, err := importService.ImportDir(ctx, uid, importParams, params.Path, progress)
+		return err
+	}, nil)
+	if err != nil {
+		h.logError(c.Request.Context(), "VaultHandler.ImportLocal", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(&dto.VaultImportJob{JobID: jobID}))
+}
+
+// ExportSite publishes a vault as a static HTML site
+// @Summary Export vault as static site
+// @Description Render the vault, or one of its folders, to a static HTML site with an index page, backlinks and a search index. The site is pushed through one of the user's storage targets under site/<vault>/<folder>, or, for admins, written to outDir on the server. Export redaction rules apply
+// @Tags Vault
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.VaultSiteExportRequest true "Export Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.VaultSiteExportResult} "Success"
+// @Router /api/vault/site [post]
+func (h *VaultHandler) ExportSite(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultSiteExportRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.ExportSite.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.ExportSite err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	if params.OutDir != "" {
+		// Writing arbitrary server paths is restricted to the admin
+		// 写入服务器任意路径仅限管理员
+		cfg := h.App.Config()
+		if cfg.User.AdminUID != 0 && uid != int64(cfg.User.AdminUID) {
+			response.ToResponse(code.ErrorUserIsNotAdmin)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.App.BackupService.ExportSite(ctx, uid, params)
+	if err != nil {
+		h.logError(ctx, "VaultHandler.ExportSite", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(result))
+}
+
+// Export downloads a vault as a ZIP archive
+// @Summary Export vault as ZIP
+// @Description Stream a ZIP of the vault's notes (.md) and attachments, optionally limited to a folder or to entries changed since a timestamp. Export redaction rules apply. An interrupted download can be resumed with Range (and If-Range set to the Last-Modified of the first response), which is served from the cached archive
+// @Tags Vault
+// @Security UserAuthToken
+// @Produce application/zip
+// @Param params query dto.VaultExportRequest true "Export Parameters"
+// @Success 200 {file} binary "ZIP archive"
+// @Success 206 {file} binary "Resumed part of the ZIP archive"
+// @Router /api/vault/export [get]
+func (h *VaultHandler) Export(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.VaultExportRequest{}
+
+	valid, errs := pkgapp.BindAndValid(c, params)
+	if !valid {
+		h.App.Logger().Error("VaultHandler.Export.BindAndValid err", zap.Error(errs))
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("VaultHandler.Export err uid=0")
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	ctx := c.Request.Context()
+	fileName := params.Vault + ".zip"
+	if folder := strings.Trim(params.Folder, "/"); folder != "" {
+		fileName = params.Vault + "-" + path.Base(folder) + ".zip"
+	}
+
+	// Resume from the cached archive of a previous export with the same filters
+	// 使用此前相同筛选条件导出的缓存压缩包续传
+	if c.GetHeader("Range") != "" {
+		if artifact, modTime, err := h.App.BackupService.ExportArtifact(ctx, uid, params); err == nil {
+			if f, err := os.Open(artifact); err == nil {
+				defer f.Close()
+				c.Header("Content-Type", "application/zip")
+				c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+				http.ServeContent(c.Writer, c.Request, fileName, modTime, f)
+				return
+			}
+		}
+	}
+
+	modTime := time.Now().Truncate(time.Second)
+	header := c.Writer.Header()
+	header.Set("Content-Type", "application/zip")
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	header.Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+	header.Set("Accept-Ranges", "bytes")
+
+	if err := h.App.BackupService.ExportZip(ctx, uid, params, modTime, c.Writer); err != nil {
+		h.logError(ctx, "VaultHandler.Export", err)
+		if !c.Writer.Written() {
+			for _, k := range []string{"Content-Type", "Content-Disposition", "Last-Modified", "Accept-Ranges"} {
+				header.Del(k)
+			}
+			apperrors.ErrorResponse(c, err)
+		}
+	}
+}
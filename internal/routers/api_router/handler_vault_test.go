@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -286,3 +288,20 @@ func TestVaultHandler_RebuildIndex_NoUID(t *testing.T) {
 	mockSvc.AssertExpectations(t)
 }
 
+
+// --- Import ---
+
+// TestVaultHandler_Import_RejectsInvalidArchive verifies that a broken ZIP is rejected before a job is queued.
+// TestVaultHandler_Import_RejectsInvalidArchive 验证损坏的 ZIP 在排队任务前即被拒绝。
+func TestVaultHandler_Import_RejectsInvalidArchive(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "broken.zip")
+	assert.NoError(t, os.WriteFile(archive, []byte("not a zip"), 0644))
+
+	handler := newVaultHandler(new(svcmocks.MockVaultService))
+	body := `{"vault":"MyVault","serverPath":"` + filepath.ToSlash(archive) + `"}`
+	c, w := newVaultTestContext("POST", "/api/vault/import", body, 1)
+	handler.Import(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertResponseCode(t, w, code.ErrorInvalidParams.Code())
+}
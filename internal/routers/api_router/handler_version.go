@@ -1,6 +1,7 @@
 package api_router
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -9,6 +10,8 @@ import (
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"github.com/haierkeys/fast-note-sync-service/pkg/fileurl"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"golang.org/x/mod/semver"
 )
 
 // VersionHandler version info API router handler
@@ -56,6 +59,63 @@ func (h *VersionHandler) ServerVersion(c *gin.Context) {
 	}))
 }
 
+// Changelog retrieves structured release notes of the running version and every newer release
+// @Summary Get changelog
+// @Description Get release notes of the running server version and all newer releases, grouped by heading, so the changes can be reviewed before upgrading. Releases come from the cached release check
+// @Tags System
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=dto.ChangelogDTO} "Success"
+// @Router /api/changelog [get]
+func (h *VersionHandler) Changelog(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	versionInfo := h.App.Version()
+	checkInfo := h.App.CheckVersion("")
+	response.ToResponse(code.Success.WithData(dto.ChangelogDTO{
+		CurrentVersion:   versionInfo.Version,
+		LatestVersion:    strings.TrimPrefix(checkInfo.VersionNewName, "v"),
+		UpgradeAvailable: checkInfo.VersionIsNew,
+		Releases:         changelogReleases(versionInfo.Version, versionInfo.Changelog, h.App.ServiceReleases()),
+	}))
+}
+
+// changelogReleases returns the releases not older than current, newest first.
+// When the running version is not among the fetched releases, its built-in changelog is used instead.
+// changelogReleases 返回不早于 current 的发布版本，最新版本在前。
+// 当前运行版本不在已获取的发布列表中时，使用其内置的更新日志。
+func changelogReleases(current, builtinChangelog string, releases []pkgapp.HistoricalVersion) []dto.ChangelogReleaseDTO {
+	current = "v" + strings.TrimPrefix(current, "v")
+	sort.SliceStable(releases, func(i, j int) bool {
+		return semver.Compare("v"+strings.TrimPrefix(releases[i].Version, "v"), "v"+strings.TrimPrefix(releases[j].Version, "v")) > 0
+	})
+
+	result := make([]dto.ChangelogReleaseDTO, 0, len(releases)+1)
+	hasCurrent := false
+	for _, r := range releases {
+		v := "v" + strings.TrimPrefix(r.Version, "v")
+		cmp := semver.Compare(v, current)
+		if cmp < 0 {
+			continue
+		}
+		hasCurrent = hasCurrent || cmp == 0
+		result = append(result, dto.ChangelogReleaseDTO{
+			Version:   strings.TrimPrefix(v, "v"),
+			IsCurrent: cmp == 0,
+			Sections:  util.ParseChangelog(r.ChangelogContent),
+			Content:   r.ChangelogContent,
+		})
+	}
+
+	if !hasCurrent && strings.TrimSpace(builtinChangelog) != "" {
+		result = append(result, dto.ChangelogReleaseDTO{
+			Version:   strings.TrimPrefix(current, "v"),
+			IsCurrent: true,
+			Sections:  util.ParseChangelog(builtinChangelog),
+			Content:   builtinChangelog,
+		})
+	}
+	return result
+}
+
 // ProbeSources probes GitHub and CNB release endpoints in parallel and reports
 // reachability + latency for each, plus the recommended source. Used by the
 // webgui settings "test latency" panel.
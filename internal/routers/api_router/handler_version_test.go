@@ -61,3 +61,38 @@ func TestVersionHandler_Support_Success(t *testing.T) {
 
 	assert.Contains(t, w.Body.String(), "User1")
 }
+
+func TestChangelogReleases(t *testing.T) {
+	releases := []pkgapp.HistoricalVersion{
+		{Version: "v2.0.9", ChangelogContent: "- old"},
+		{Version: "v2.1.0", ChangelogContent: "## Features\n- new"},
+		{Version: "v2.0.10", ChangelogContent: "- current"},
+	}
+
+	got := changelogReleases("2.0.10", "", releases)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "2.1.0", got[0].Version)
+	assert.False(t, got[0].IsCurrent)
+	assert.Equal(t, "Features", got[0].Sections[0].Title)
+	assert.Equal(t, "2.0.10", got[1].Version)
+	assert.True(t, got[1].IsCurrent)
+
+	// The built-in changelog stands in for a running version missing from the release list
+	// 运行版本不在发布列表中时使用内置更新日志
+	got = changelogReleases("2.0.11", "- built in", releases)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "2.0.11", got[1].Version)
+	assert.Equal(t, []string{"built in"}, got[1].Sections[0].Items)
+}
+
+func TestVersionHandler_Changelog_Success(t *testing.T) {
+	testApp := app.NewTestApp(nil)
+	handler := NewVersionHandler(testApp)
+	c, w := newVersionTestContext("/api/changelog")
+
+	handler.Changelog(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertResponseCode(t, w, code.Success.Code())
+	assert.Contains(t, w.Body.String(), `"currentVersion"`)
+}
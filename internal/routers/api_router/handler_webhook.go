@@ -0,0 +1,178 @@
+package api_router
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	apperrors "github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// WebhookHandler webhook notification API router handler
+// WebhookHandler Webhook 通知 API 路由处理器
+type WebhookHandler struct {
+	*Handler
+}
+
+// NewWebhookHandler creates WebhookHandler instance
+// NewWebhookHandler 创建 WebhookHandler 实例
+func NewWebhookHandler(a *app.App) *WebhookHandler {
+	return &WebhookHandler{
+		Handler: NewHandler(a),
+	}
+}
+
+// GetConfigs gets the webhooks of the current user
+// @Summary Get webhooks
+// @Tags Webhook
+// @Security UserAuthToken
+// @Produce json
+// @Success 200 {object} pkgapp.Res{data=[]dto.WebhookDTO} "Success"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/webhook/configs [get]
+func (h *WebhookHandler) GetConfigs(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	list, err := h.App.NotificationService.List(c.Request.Context(), uid)
+	if err != nil {
+		h.logError(c.Request.Context(), "WebhookHandler.GetConfigs", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.Success.WithData(list))
+}
+
+// UpdateConfig creates or updates a webhook
+// @Summary Create or update a webhook
+// @Description Events: note.created, note.deleted, backup.succeeded, backup.failed, git_sync.failed, user.login, user.login_failed; empty subscribes to all. Requests are signed with X-FNS-Signature when a secret is set.
+// @Tags Webhook
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body dto.WebhookRequest true "Webhook Parameters"
+// @Success 200 {object} pkgapp.Res{data=dto.WebhookDTO} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/webhook/config [post]
+func (h *WebhookHandler) UpdateConfig(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.WebhookRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	webhook, err := h.App.NotificationService.Save(c.Request.Context(), uid, params)
+	if err != nil {
+		h.logError(c.Request.Context(), "WebhookHandler.UpdateConfig", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessUpdate.WithData(webhook))
+}
+
+// DeleteConfig deletes a webhook and its delivery log
+// @Summary Delete a webhook
+// @Tags Webhook
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.WebhookDeleteRequest true "Webhook ID"
+// @Success 200 {object} pkgapp.Res "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/webhook/config [delete]
+func (h *WebhookHandler) DeleteConfig(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.WebhookDeleteRequest{}
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	if err := h.App.NotificationService.Delete(c.Request.Context(), uid, params.ID); err != nil {
+		h.logError(c.Request.Context(), "WebhookHandler.DeleteConfig", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponse(code.SuccessDelete)
+}
+
+// ListDeliveries gets the webhook delivery log
+// @Summary Get webhook delivery log
+// @Tags Webhook
+// @Security UserAuthToken
+// @Produce json
+// @Param params query dto.WebhookDeliveryListRequest true "Delivery Log Parameters"
+// @Param page query int false "Page"
+// @Param pageSize query int false "Page Size"
+// @Success 200 {object} pkgapp.Res{data=pkgapp.ListRes{list=[]dto.WebhookDeliveryDTO}} "Success"
+// @Failure 400 {object} pkgapp.Res "Invalid Params"
+// @Failure 401 {object} pkgapp.Res "Token Required"
+// @Failure 500 {object} pkgapp.Res "Internal Server Error"
+// @Router /api/webhook/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	response := pkgapp.NewResponse(c)
+	params := &dto.WebhookDeliveryListRequest{}
+	pager := pkgapp.NewPager(c)
+
+	if valid, errs := pkgapp.BindAndValid(c, params); !valid {
+		response.ToResponse(code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()).WithData(errs.MapsToString()))
+		return
+	}
+
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		response.ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	list, total, err := h.App.NotificationService.ListDeliveries(c.Request.Context(), uid, params.WebhookID, pager.Page, pager.PageSize)
+	if err != nil {
+		h.logError(c.Request.Context(), "WebhookHandler.ListDeliveries", err)
+		apperrors.ErrorResponse(c, err)
+		return
+	}
+
+	response.ToResponseList(code.Success, list, int(total))
+}
+
+// logError logs an error with the trace ID of the request
+// logError 记录带请求追踪 ID 的错误日志
+func (h *WebhookHandler) logError(ctx context.Context, method string, err error) {
+	traceID := middleware.GetTraceID(ctx)
+	h.App.Logger().Error(method,
+		zap.Error(err),
+		zap.String("traceId", traceID),
+	)
+}
@@ -0,0 +1,111 @@
+package dav_router
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"go.uber.org/zap"
+	"golang.org/x/net/webdav"
+)
+
+// Methods WebDAV methods served by the handler
+// Methods 处理器支持的 WebDAV 方法
+var Methods = []string{
+	http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete,
+	"PROPFIND", "PROPPATCH", "MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK",
+}
+
+// DavHandler serves the vaults of the authenticated user over WebDAV at /dav/{vault}
+// DavHandler 通过 WebDAV 在 /dav/{vault} 下提供已认证用户的仓库访问
+type DavHandler struct {
+	fs     *vaultFS
+	locks  sync.Map // uid -> webdav.LockSystem, lock tokens never leak across users // uid -> webdav.LockSystem，锁不会跨用户共享
+	logger *zap.Logger
+}
+
+// NewDavHandler creates DavHandler instance
+// NewDavHandler 创建 DavHandler 实例
+func NewDavHandler(appContainer *app.App, wss *pkgapp.WebsocketServer) *DavHandler {
+	tempPath := appContainer.Config().App.TempPath
+	if tempPath == "" {
+		tempPath = "storage/temp"
+	}
+	h := &DavHandler{
+		fs: &vaultFS{
+			vaultService:  appContainer.VaultService,
+			noteService:   appContainer.NoteService,
+			fileService:   appContainer.FileService,
+			folderService: appContainer.FolderService,
+			secretScan:    appContainer.SecretScanService,
+			noteAccess:    appContainer.NoteAccessService,
+			tempPath:      tempPath,
+		},
+		logger: appContainer.Logger(),
+	}
+	if wss != nil {
+		h.fs.wss = wss
+	}
+	return h
+}
+
+// lockSystem returns the lock system of a user
+// lockSystem 返回用户的锁系统
+func (h *DavHandler) lockSystem(uid int64) webdav.LockSystem {
+	ls, _ := h.locks.LoadOrStore(uid, webdav.NewMemLS())
+	return ls.(webdav.LockSystem)
+}
+
+// Handle serves one WebDAV request
+// Handle 处理单个 WebDAV 请求
+func (h *DavHandler) Handle(c *gin.Context) {
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	clientType := c.GetHeader("X-Client")
+	if clientType == "" {
+		clientType = middleware.DAVClientType
+	}
+	clientName := c.GetHeader("X-Client-Name")
+	if decoded, err := url.QueryUnescape(clientName); err == nil {
+		clientName = decoded
+	}
+	if clientName == "" {
+		clientName = c.GetHeader("User-Agent")
+	}
+
+	ctx := withSession(c.Request.Context(), &davSession{
+		uid:           uid,
+		vaults:        c.GetString("vaults"),
+		clientType:    clientType,
+		clientName:    clientName,
+		clientVersion: c.GetHeader("X-Client-Version"),
+		ip:            pkgapp.GetRequestIP(c),
+		userAgent:     c.Request.UserAgent(),
+	})
+
+	handler := &webdav.Handler{
+		Prefix:     middleware.DAVPrefix,
+		FileSystem: h.fs,
+		LockSystem: h.lockSystem(uid),
+		Logger: func(r *http.Request, err error) {
+			if err != nil && !os.IsNotExist(err) {
+				h.logger.Warn("DavHandler",
+					zap.Int64("uid", uid),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.Error(err),
+				)
+			}
+		},
+	}
+	handler.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+}
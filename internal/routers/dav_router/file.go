@@ -0,0 +1,214 @@
+package dav_router
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"time"
+
+	"golang.org/x/net/webdav"
+)
+
+// entryKind kind of a WebDAV resource
+// entryKind WebDAV 资源类型
+type entryKind int
+
+const (
+	kindDir  entryKind = iota // vault root, folder or the listing of vaults // 仓库根、文件夹或仓库列表
+	kindNote                  // Markdown note // Markdown 笔记
+	kindFile                  // attachment // 附件
+)
+
+// fileInfo os.FileInfo of a note, attachment or folder; also implements webdav.ContentTyper and webdav.ETager
+// so PROPFIND never has to open the content
+// fileInfo 笔记、附件或文件夹的 os.FileInfo；同时实现 webdav.ContentTyper 与 webdav.ETager，PROPFIND 无需读取内容
+type fileInfo struct {
+	path        string // vault-relative path, empty for a vault root // 仓库内相对路径，仓库根为空
+	name        string
+	kind        entryKind
+	size        int64
+	modTime     time.Time
+	ctime       int64 // creation timestamp in milliseconds // 创建时间戳（毫秒）
+	contentHash string
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return fi.kind == kindDir }
+func (fi *fileInfo) Sys() any           { return nil }
+
+func (fi *fileInfo) Mode() os.FileMode {
+	if fi.IsDir() {
+		return os.ModeDir | 0755
+	}
+	return 0644
+}
+
+// ContentType implements webdav.ContentTyper
+func (fi *fileInfo) ContentType(ctx context.Context) (string, error) {
+	if fi.kind == kindNote {
+		return "text/markdown; charset=utf-8", nil
+	}
+	if t := mime.TypeByExtension(path.Ext(fi.name)); t != "" {
+		return t, nil
+	}
+	return "application/octet-stream", nil
+}
+
+// ETag implements webdav.ETager, the content hash is stable across servers unlike the default mtime/size tag
+// ETag 实现 webdav.ETager，与默认的 mtime/size 标签不同，内容哈希在不同服务器间保持一致
+func (fi *fileInfo) ETag(ctx context.Context) (string, error) {
+	if fi.contentHash == "" {
+		return "", webdav.ErrNotImplemented
+	}
+	return fmt.Sprintf("%q", fi.contentHash), nil
+}
+
+// msTime converts a millisecond timestamp, zero stays the zero time
+// msTime 转换毫秒时间戳，0 保持为零值时间
+func msTime(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// dirFile webdav.File of a directory, entries are loaded on the first Readdir
+// dirFile 目录的 webdav.File，首次 Readdir 时加载条目
+type dirFile struct {
+	info    *fileInfo
+	load    func() ([]os.FileInfo, error)
+	entries []os.FileInfo
+	loaded  bool
+	pos     int
+}
+
+func (f *dirFile) Close() error                                 { return nil }
+func (f *dirFile) Read(p []byte) (int, error)                   { return 0, os.ErrInvalid }
+func (f *dirFile) Seek(offset int64, whence int) (int64, error) { return 0, os.ErrInvalid }
+func (f *dirFile) Write(p []byte) (int, error)                  { return 0, os.ErrPermission }
+func (f *dirFile) Stat() (os.FileInfo, error)                   { return f.info, nil }
+
+func (f *dirFile) Readdir(count int) ([]os.FileInfo, error) {
+	if !f.loaded {
+		entries, err := f.load()
+		if err != nil {
+			return nil, err
+		}
+		f.entries, f.loaded = entries, true
+	}
+
+	rest := f.entries[f.pos:]
+	if count <= 0 {
+		f.pos = len(f.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(rest) {
+		count = len(rest)
+	}
+	f.pos += count
+	return rest[:count], nil
+}
+
+// readFile read-only webdav.File of a note or attachment. The content is opened on the first Read or Seek,
+// PROPFIND opens every entry only to look for dead properties.
+// readFile 笔记或附件的只读 webdav.File。内容在首次 Read 或 Seek 时才打开，PROPFIND 打开每个条目仅为查询死属性。
+type readFile struct {
+	info *fileInfo
+	open func() (io.ReadSeekCloser, error)
+	body io.ReadSeekCloser
+}
+
+func (f *readFile) content() (io.ReadSeekCloser, error) {
+	if f.body == nil {
+		body, err := f.open()
+		if err != nil {
+			return nil, err
+		}
+		f.body = body
+	}
+	return f.body, nil
+}
+
+func (f *readFile) Read(p []byte) (int, error) {
+	body, err := f.content()
+	if err != nil {
+		return 0, err
+	}
+	return body.Read(p)
+}
+
+func (f *readFile) Seek(offset int64, whence int) (int64, error) {
+	body, err := f.content()
+	if err != nil {
+		return 0, err
+	}
+	return body.Seek(offset, whence)
+}
+
+func (f *readFile) Close() error {
+	if f.body == nil {
+		return nil
+	}
+	return f.body.Close()
+}
+
+func (f *readFile) Readdir(count int) ([]os.FileInfo, error) { return nil, os.ErrInvalid }
+func (f *readFile) Write(p []byte) (int, error)              { return 0, os.ErrPermission }
+func (f *readFile) Stat() (os.FileInfo, error)               { return f.info, nil }
+
+// nopCloser serves in-memory note content
+// nopCloser 提供内存中的笔记内容
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error { return nil }
+
+// writeFile buffers an upload in a temp file; Close commits it through NoteService or FileService
+// writeFile 将上传内容缓存到临时文件；Close 时通过 NoteService 或 FileService 提交
+type writeFile struct {
+	*os.File
+	ctx     context.Context
+	commit  func(ctx context.Context, tempPath string, size int64) error
+	info    *fileInfo
+	written int64
+	closed  bool
+}
+
+func (f *writeFile) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p)
+	f.written += int64(n)
+	return n, err
+}
+
+func (f *writeFile) Readdir(count int) ([]os.FileInfo, error) { return nil, os.ErrInvalid }
+
+func (f *writeFile) Stat() (os.FileInfo, error) {
+	info := *f.info
+	info.size = f.written
+	info.modTime = time.Now()
+	return &info, nil
+}
+
+func (f *writeFile) Close() error {
+	if f.closed {
+		return os.ErrClosed
+	}
+	f.closed = true
+
+	tempPath := f.File.Name()
+	defer os.Remove(tempPath)
+	if err := f.File.Close(); err != nil {
+		return err
+	}
+	return f.commit(f.ctx, tempPath, f.written)
+}
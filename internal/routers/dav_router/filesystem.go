@@ -0,0 +1,753 @@
+package dav_router
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"golang.org/x/net/webdav"
+	"gorm.io/gorm"
+)
+
+// davListPageSize page size used when listing folder contents
+// davListPageSize 列出文件夹内容时的分页大小
+const davListPageSize = 500
+
+// junkNames metadata files that operating systems drop next to real content, they are never stored
+// junkNames 操作系统在真实内容旁生成的元数据文件，不会被保存
+var junkNames = map[string]bool{
+	".ds_store":   true,
+	"thumbs.db":   true,
+	"desktop.ini": true,
+}
+
+// isJunkName reports whether a path is an OS metadata file such as .DS_Store or an AppleDouble ._ file
+// isJunkName 判断路径是否为 .DS_Store、AppleDouble ._ 文件等系统元数据文件
+func isJunkName(p string) bool {
+	base := strings.ToLower(path.Base(p))
+	return junkNames[base] || strings.HasPrefix(base, "._")
+}
+
+// isNotePath reports whether a vault path is stored as a note rather than an attachment
+// isNotePath 判断仓库路径是否作为笔记（而非附件）保存
+func isNotePath(p string) bool {
+	return strings.EqualFold(path.Ext(p), ".md")
+}
+
+// splitPath splits a WebDAV path into the vault name and the vault-relative path
+// splitPath 将 WebDAV 路径拆分为仓库名与仓库内相对路径
+func splitPath(name string) (vault, rel string) {
+	name = strings.Trim(path.Clean("/"+name), "/")
+	vault, rel, _ = strings.Cut(name, "/")
+	return vault, rel
+}
+
+// toFSError maps service errors to the os errors understood by webdav.Handler
+// toFSError 将服务层错误映射为 webdav.Handler 可识别的 os 错误
+func toFSError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, code.ErrorVaultNotFound),
+		errors.Is(err, code.ErrorNoteNotFound),
+		errors.Is(err, code.ErrorFileNotFound),
+		errors.Is(err, code.ErrorFolderNotFound):
+		return os.ErrNotExist
+	case errors.Is(err, code.ErrorFolderArchived),
+		errors.Is(err, code.ErrorNoteSecretDetected):
+		return os.ErrPermission
+	}
+	return err
+}
+
+// davSession identity of a WebDAV request, stored in the request context
+// davSession WebDAV 请求的身份信息，保存在请求上下文中
+type davSession struct {
+	uid           int64
+	vaults        string // vault restriction of the token, empty allows all // Token 的仓库限制，为空表示不限制
+	clientType    string
+	clientName    string
+	clientVersion string
+	ip            string
+	userAgent     string
+	// listed entries of this request; webdav.Handler stats every child again after Readdir
+	// 本次请求已列出的条目；webdav.Handler 在 Readdir 之后会再次 Stat 每个子项
+	listed map[string]*fileInfo
+}
+
+type sessionKey struct{}
+
+func withSession(ctx context.Context, s *davSession) context.Context {
+	return context.WithValue(ctx, sessionKey{}, s)
+}
+
+func sessionFrom(ctx context.Context) (*davSession, error) {
+	s, ok := ctx.Value(sessionKey{}).(*davSession)
+	if !ok || s.uid == 0 {
+		return nil, os.ErrPermission
+	}
+	return s, nil
+}
+
+func (s *davSession) canAccess(vault string) bool {
+	return util.VerifyVaultAccess(s.vaults, vault)
+}
+
+func (s *davSession) remember(vault string, entries ...*fileInfo) {
+	if s.listed == nil {
+		s.listed = make(map[string]*fileInfo)
+	}
+	for _, e := range entries {
+		if vault == "" {
+			s.listed[e.name] = e
+		} else {
+			s.listed[vault+"/"+e.path] = e
+		}
+	}
+}
+
+// forget drops the listed entries before a write so later stats see the new state
+// forget 在写入前清除已列出的条目，使后续 Stat 读取最新状态
+func (s *davSession) forget() {
+	s.listed = nil
+}
+
+// broadcaster pushes sync events to the other clients of a user
+// broadcaster 向用户的其他客户端推送同步事件
+type broadcaster interface {
+	BroadcastToUser(uid int64, code *code.Code, action string)
+}
+
+// vaultFS webdav.FileSystem over the vaults of the requesting user.
+// The first path segment is the vault, everything below it goes through NoteService, FileService and
+// FolderService so WebDAV writes are synced like writes from any other client.
+// vaultFS 基于请求用户仓库的 webdav.FileSystem。
+// 路径第一段为仓库名，其下的读写均经过 NoteService、FileService 与 FolderService，确保 WebDAV 写入与其他客户端一样参与同步。
+type vaultFS struct {
+	vaultService  service.VaultService
+	noteService   service.NoteService
+	fileService   service.FileService
+	folderService service.FolderService
+	secretScan    service.SecretScanService
+	noteAccess    service.NoteAccessService
+	wss           broadcaster
+	tempPath      string
+}
+
+func (fs *vaultFS) notes(s *davSession) service.NoteService {
+	return fs.noteService.WithClient(s.clientType, s.clientName, s.clientVersion)
+}
+
+func (fs *vaultFS) files(s *davSession) service.FileService {
+	return fs.fileService.WithClient(s.clientType, s.clientName, s.clientVersion)
+}
+
+func (fs *vaultFS) folders(s *davSession) service.FolderService {
+	return fs.folderService.WithClient(s.clientType, s.clientName, s.clientVersion)
+}
+
+func (fs *vaultFS) broadcast(s *davSession, vault string, data any, action string) {
+	if fs.wss != nil {
+		fs.wss.BroadcastToUser(s.uid, code.Success.WithData(data).WithVault(vault), action)
+	}
+}
+
+// Stat implements webdav.FileSystem
+func (fs *vaultFS) Stat(ctx context.Context, name string) (os.FileInfo, error) {
+	s, err := sessionFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vault, rel := splitPath(name)
+	info, err := fs.stat(ctx, s, vault, rel)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (fs *vaultFS) stat(ctx context.Context, s *davSession, vault, rel string) (*fileInfo, error) {
+	if vault == "" {
+		return &fileInfo{name: "/", kind: kindDir}, nil
+	}
+	// Vaults outside the token restriction are hidden rather than forbidden
+	// Token 限制之外的仓库直接隐藏而非返回禁止访问
+	if !s.canAccess(vault) {
+		return nil, os.ErrNotExist
+	}
+	if info, ok := s.listed[strings.TrimSuffix(vault+"/"+rel, "/")]; ok {
+		return info, nil
+	}
+	if rel == "" {
+		v, err := fs.vaultService.GetByName(ctx, s.uid, vault)
+		if err != nil {
+			return nil, toFSError(err)
+		}
+		return &fileInfo{name: vault, kind: kindDir, modTime: v.UpdatedAt}, nil
+	}
+
+	pathHash := util.EncodeHash32(rel)
+	if isNotePath(rel) {
+		note, err := fs.noteService.Get(ctx, s.uid, &dto.NoteGetRequest{Vault: vault, Path: rel, PathHash: pathHash})
+		if err == nil {
+			return noteInfo(note.Path, note.Size, note.Mtime, note.Ctime, note.ContentHash), nil
+		}
+		if toFSError(err) != os.ErrNotExist {
+			return nil, err
+		}
+	} else {
+		file, err := fs.fileService.Get(ctx, s.uid, &dto.FileGetRequest{Vault: vault, Path: rel, PathHash: pathHash})
+		if err == nil && file != nil && file.Action != string(domain.FileActionDelete) {
+			return attachmentInfo(file), nil
+		}
+		if err != nil && toFSError(err) != os.ErrNotExist {
+			return nil, err
+		}
+	}
+
+	folder, err := fs.folderService.Get(ctx, s.uid, &dto.FolderGetRequest{Vault: vault, Path: rel, PathHash: pathHash})
+	if err == nil && folder != nil && folder.Action != string(domain.FolderActionDelete) {
+		return folderInfo(folder), nil
+	}
+	if err != nil && toFSError(err) != os.ErrNotExist {
+		return nil, err
+	}
+	return nil, os.ErrNotExist
+}
+
+func noteInfo(p string, size, mtime, ctime int64, contentHash string) *fileInfo {
+	return &fileInfo{path: p, name: path.Base(p), kind: kindNote, size: size, modTime: msTime(mtime), ctime: ctime, contentHash: contentHash}
+}
+
+func attachmentInfo(f *dto.FileDTO) *fileInfo {
+	return &fileInfo{path: f.Path, name: path.Base(f.Path), kind: kindFile, size: f.Size, modTime: msTime(f.Mtime), ctime: f.Ctime, contentHash: f.ContentHash}
+}
+
+func folderInfo(f *dto.FolderDTO) *fileInfo {
+	mtime := f.Mtime
+	if mtime == 0 {
+		mtime = f.UpdatedTimestamp
+	}
+	return &fileInfo{path: f.Path, name: path.Base(f.Path), kind: kindDir, modTime: msTime(mtime), ctime: f.Ctime}
+}
+
+// readDir lists the folders, notes and attachments directly inside a vault folder
+// readDir 列出仓库文件夹下的直接子文件夹、笔记与附件
+func (fs *vaultFS) readDir(ctx context.Context, s *davSession, vault, rel string) ([]*fileInfo, error) {
+	var entries []*fileInfo
+
+	folders, err := fs.folderService.List(ctx, s.uid, &dto.FolderListRequest{Vault: vault, Path: rel})
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range folders {
+		entries = append(entries, folderInfo(f))
+	}
+
+	for page := 1; ; page++ {
+		notes, _, err := fs.folderService.ListNotes(ctx, s.uid, &dto.FolderContentRequest{Vault: vault, Path: rel}, &pkgapp.Pager{Page: page, PageSize: davListPageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range notes {
+			entries = append(entries, noteInfo(n.Path, n.Size, n.Mtime, n.Ctime, ""))
+		}
+		if len(notes) < davListPageSize {
+			break
+		}
+	}
+
+	for page := 1; ; page++ {
+		files, _, err := fs.folderService.ListFiles(ctx, s.uid, &dto.FolderContentRequest{Vault: vault, Path: rel}, &pkgapp.Pager{Page: page, PageSize: davListPageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			entries = append(entries, attachmentInfo(f))
+		}
+		if len(files) < davListPageSize {
+			break
+		}
+	}
+	s.remember(vault, entries...)
+	return entries, nil
+}
+
+// listVaults lists the vaults of the user allowed by the token
+// listVaults 列出 Token 允许访问的用户仓库
+func (fs *vaultFS) listVaults(ctx context.Context, s *davSession) ([]os.FileInfo, error) {
+	vaults, err := fs.vaultService.List(ctx, s.uid)
+	if err != nil {
+		return nil, err
+	}
+	var entries []os.FileInfo
+	for _, v := range vaults {
+		if !s.canAccess(v.Name) {
+			continue
+		}
+		modTime, _ := time.ParseInLocation("2006-01-02 15:04", v.UpdatedAt, time.Local)
+		info := &fileInfo{name: v.Name, kind: kindDir, modTime: modTime}
+		s.remember("", info)
+		entries = append(entries, info)
+	}
+	return entries, nil
+}
+
+// OpenFile implements webdav.FileSystem
+func (fs *vaultFS) OpenFile(ctx context.Context, name string, flag int, perm os.FileMode) (webdav.File, error) {
+	s, err := sessionFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vault, rel := splitPath(name)
+
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_APPEND|os.O_CREATE|os.O_TRUNC) != 0 {
+		return fs.openWrite(ctx, s, vault, rel)
+	}
+
+	info, err := fs.stat(ctx, s, vault, rel)
+	if err != nil {
+		return nil, err
+	}
+	switch info.kind {
+	case kindDir:
+		return &dirFile{info: info, load: func() ([]os.FileInfo, error) {
+			if vault == "" {
+				return fs.listVaults(ctx, s)
+			}
+			entries, err := fs.readDir(ctx, s, vault, rel)
+			if err != nil {
+				return nil, toFSError(err)
+			}
+			list := make([]os.FileInfo, 0, len(entries))
+			for _, e := range entries {
+				list = append(list, e)
+			}
+			return list, nil
+		}}, nil
+	case kindNote:
+		return &readFile{info: info, open: func() (io.ReadSeekCloser, error) {
+			note, err := fs.noteService.Get(ctx, s.uid, &dto.NoteGetRequest{Vault: vault, Path: rel, PathHash: util.EncodeHash32(rel)})
+			if err != nil {
+				return nil, toFSError(err)
+			}
+			if fs.noteAccess != nil {
+				fs.noteAccess.Record(s.uid, note.ID, note.Path, service.NoteAccess{
+					Channel:    domain.NoteAccessChannelWebDAV,
+					Actor:      s.clientName,
+					ClientType: s.clientType,
+					IP:         s.ip,
+					UserAgent:  s.userAgent,
+				})
+			}
+			return nopCloser{bytes.NewReader([]byte(note.Content))}, nil
+		}}, nil
+	default:
+		return &readFile{info: info, open: func() (io.ReadSeekCloser, error) {
+			file, err := fs.fileService.Get(ctx, s.uid, &dto.FileGetRequest{Vault: vault, Path: rel, PathHash: util.EncodeHash32(rel)})
+			if err != nil {
+				return nil, toFSError(err)
+			}
+			if err := fs.fileService.FetchContent(ctx, s.uid, file); err != nil {
+				return nil, err
+			}
+			f, err := atrest.Open(file.SavePath)
+			if err != nil {
+				return nil, err
+			}
+			return f, nil
+		}}, nil
+	}
+}
+
+// openWrite opens a buffered upload, the parent folder must exist
+// openWrite 打开带缓冲的上传，父文件夹必须存在
+func (fs *vaultFS) openWrite(ctx context.Context, s *davSession, vault, rel string) (webdav.File, error) {
+	s.forget()
+	if vault == "" || rel == "" || isJunkName(rel) {
+		return nil, os.ErrPermission
+	}
+
+	info, err := fs.stat(ctx, s, vault, rel)
+	switch {
+	case err == nil && info.IsDir():
+		return nil, os.ErrInvalid
+	case err == nil:
+	case err == os.ErrNotExist:
+		parent, perr := fs.stat(ctx, s, vault, parentPath(rel))
+		if perr != nil {
+			return nil, perr
+		}
+		if !parent.IsDir() {
+			return nil, os.ErrNotExist
+		}
+		kind := kindFile
+		if isNotePath(rel) {
+			kind = kindNote
+		}
+		info = &fileInfo{path: rel, name: path.Base(rel), kind: kind}
+	default:
+		return nil, err
+	}
+
+	if err := os.MkdirAll(fs.tempPath, 0755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.Create(filepath.Join(fs.tempPath, uuid.New().String()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &writeFile{
+		File: tmp,
+		ctx:  ctx,
+		info: info,
+		commit: func(ctx context.Context, tempPath string, size int64) error {
+			if info.kind == kindNote {
+				return fs.commitNote(ctx, s, vault, rel, info.ctime, tempPath)
+			}
+			return fs.commitFile(ctx, s, vault, rel, info.ctime, tempPath, size)
+		},
+	}, nil
+}
+
+// parentPath returns the vault-relative parent folder, empty for the vault root
+// parentPath 返回仓库内的父文件夹路径，仓库根为空
+func parentPath(rel string) string {
+	if i := strings.LastIndex(rel, "/"); i >= 0 {
+		return rel[:i]
+	}
+	return ""
+}
+
+// commitNote stores an uploaded note through ModifyOrCreate so it is versioned and synced like any other edit
+// commitNote 通过 ModifyOrCreate 保存上传的笔记，使其与其他编辑一样记录版本并同步
+func (fs *vaultFS) commitNote(ctx context.Context, s *davSession, vault, rel string, ctime int64, tempPath string) error {
+	data, err := os.ReadFile(tempPath)
+	if err != nil {
+		return err
+	}
+	content := string(data)
+
+	// Scan for embedded credentials, strict mode rejects the write
+	// 扫描嵌入的凭据，严格模式下拒绝写入
+	if fs.secretScan != nil {
+		if _, err := fs.secretScan.Check(ctx, s.uid, rel, content); err != nil {
+			return toFSError(err)
+		}
+	}
+
+	mtime := timex.Now().UnixMilli()
+	if ctime == 0 {
+		ctime = mtime
+	}
+	_, note, err := fs.notes(s).ModifyOrCreate(ctx, s.uid, &dto.NoteModifyOrCreateRequest{
+		Vault:       vault,
+		Path:        rel,
+		PathHash:    util.EncodeHash32(rel),
+		Content:     content,
+		ContentHash: util.EncodeHash32(content),
+		Ctime:       ctime,
+		Mtime:       mtime,
+	}, false)
+	if err != nil {
+		return toFSError(err)
+	}
+	if note != nil {
+		fs.broadcast(s, vault, note, "NoteSyncModify")
+	}
+	return nil
+}
+
+// commitFile stores an uploaded attachment, FileService moves the temp file into storage
+// commitFile 保存上传的附件，FileService 会将临时文件移入存储目录
+func (fs *vaultFS) commitFile(ctx context.Context, s *davSession, vault, rel string, ctime int64, tempPath string, size int64) error {
+	contentHash, err := util.EncodeHash32File(tempPath)
+	if err != nil {
+		return err
+	}
+
+	mtime := timex.Now().UnixMilli()
+	if ctime == 0 {
+		ctime = mtime
+	}
+	_, file, err := fs.files(s).UpdateOrCreate(ctx, s.uid, &dto.FileUpdateRequest{
+		Vault:       vault,
+		Path:        rel,
+		PathHash:    util.EncodeHash32(rel),
+		ContentHash: contentHash,
+		SavePath:    tempPath,
+		Size:        size,
+		Ctime:       ctime,
+		Mtime:       mtime,
+	}, false)
+	if err != nil {
+		return toFSError(err)
+	}
+	if file != nil {
+		fs.broadcast(s, vault, dto.FileSyncModifyMessage{
+			Path:             file.Path,
+			PathHash:         file.PathHash,
+			ContentHash:      file.ContentHash,
+			Size:             file.Size,
+			Ctime:            file.Ctime,
+			Mtime:            file.Mtime,
+			UpdatedTimestamp: file.UpdatedTimestamp,
+		}, "FileSyncUpdate")
+	}
+	return nil
+}
+
+// Mkdir implements webdav.FileSystem
+func (fs *vaultFS) Mkdir(ctx context.Context, name string, perm os.FileMode) error {
+	s, err := sessionFrom(ctx)
+	if err != nil {
+		return err
+	}
+	s.forget()
+	vault, rel := splitPath(name)
+	if vault == "" || rel == "" {
+		// Vaults are created from the web GUI or the API
+		// 仓库需通过 Web 界面或 API 创建
+		return os.ErrPermission
+	}
+
+	if _, err := fs.stat(ctx, s, vault, rel); err == nil {
+		return os.ErrExist
+	} else if err != os.ErrNotExist {
+		return err
+	}
+	parent, err := fs.stat(ctx, s, vault, parentPath(rel))
+	if err != nil {
+		return err
+	}
+	if !parent.IsDir() {
+		return os.ErrNotExist
+	}
+
+	folder, err := fs.folders(s).UpdateOrCreate(ctx, s.uid, &dto.FolderCreateRequest{
+		Vault:    vault,
+		Path:     rel,
+		PathHash: util.EncodeHash32(rel),
+	})
+	if err != nil {
+		return toFSError(err)
+	}
+	fs.broadcast(s, vault, dto.FolderSyncModifyMessage{
+		Path:             folder.Path,
+		PathHash:         folder.PathHash,
+		Ctime:            folder.Ctime,
+		Mtime:            folder.Mtime,
+		UpdatedTimestamp: folder.UpdatedTimestamp,
+	}, "FolderSyncModify")
+	return nil
+}
+
+// RemoveAll implements webdav.FileSystem, deleted items go to the vault trash
+// RemoveAll 实现 webdav.FileSystem，删除的条目进入仓库回收站
+func (fs *vaultFS) RemoveAll(ctx context.Context, name string) error {
+	s, err := sessionFrom(ctx)
+	if err != nil {
+		return err
+	}
+	s.forget()
+	vault, rel := splitPath(name)
+	if vault == "" || rel == "" {
+		return os.ErrPermission
+	}
+
+	info, err := fs.stat(ctx, s, vault, rel)
+	if err == os.ErrNotExist {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	pathHash := util.EncodeHash32(rel)
+	switch info.kind {
+	case kindDir:
+		folder, err := fs.folders(s).DeleteTree(ctx, s.uid, &dto.FolderDeleteRequest{Vault: vault, Path: rel, PathHash: pathHash})
+		if err != nil {
+			return toFSError(err)
+		}
+		fs.broadcast(s, vault, dto.FolderSyncDeleteMessage{
+			Path:             folder.Path,
+			PathHash:         folder.PathHash,
+			Ctime:            folder.Ctime,
+			Mtime:            folder.Mtime,
+			UpdatedTimestamp: folder.UpdatedTimestamp,
+		}, "FolderSyncDelete")
+	case kindNote:
+		note, err := fs.notes(s).Delete(ctx, s.uid, &dto.NoteDeleteRequest{Vault: vault, Path: rel, PathHash: pathHash})
+		if err != nil {
+			return toFSError(err)
+		}
+		fs.broadcast(s, vault, note, "NoteSyncDelete")
+	default:
+		file, err := fs.files(s).Delete(ctx, s.uid, &dto.FileDeleteRequest{Vault: vault, Path: rel, PathHash: pathHash})
+		if err != nil {
+			return toFSError(err)
+		}
+		fs.broadcast(s, vault, dto.FileSyncDeleteMessage{
+			Path:     file.Path,
+			PathHash: file.PathHash,
+			Ctime:    file.Ctime,
+			Mtime:    file.Mtime,
+			Size:     file.Size,
+		}, "FileSyncDelete")
+	}
+	return nil
+}
+
+// Rename implements webdav.FileSystem. Moves are limited to one vault and must keep
+// the note / attachment type, since the two are stored separately.
+// Rename 实现 webdav.FileSystem。移动仅限同一仓库内，且不能改变笔记/附件类型，因为两者分开存储。
+func (fs *vaultFS) Rename(ctx context.Context, oldName, newName string) error {
+	s, err := sessionFrom(ctx)
+	if err != nil {
+		return err
+	}
+	s.forget()
+	vault, oldRel := splitPath(oldName)
+	newVault, newRel := splitPath(newName)
+	if vault == "" || oldRel == "" || newRel == "" || vault != newVault {
+		return os.ErrPermission
+	}
+
+	info, err := fs.stat(ctx, s, vault, oldRel)
+	if err != nil {
+		return err
+	}
+	switch info.kind {
+	case kindDir:
+		if strings.HasPrefix(newRel+"/", oldRel+"/") {
+			return os.ErrInvalid
+		}
+		return fs.renameFolder(ctx, s, vault, oldRel, newRel)
+	case kindNote:
+		if !isNotePath(newRel) {
+			return os.ErrPermission
+		}
+		return fs.renameNote(ctx, s, vault, oldRel, newRel)
+	default:
+		if isNotePath(newRel) || isJunkName(newRel) {
+			return os.ErrPermission
+		}
+		return fs.renameFile(ctx, s, vault, oldRel, newRel)
+	}
+}
+
+func (fs *vaultFS) renameNote(ctx context.Context, s *davSession, vault, oldRel, newRel string) error {
+	oldNote, newNote, err := fs.notes(s).Rename(ctx, s.uid, &dto.NoteRenameRequest{
+		Vault:       vault,
+		Path:        newRel,
+		PathHash:    util.EncodeHash32(newRel),
+		OldPath:     oldRel,
+		OldPathHash: util.EncodeHash32(oldRel),
+	})
+	if err != nil {
+		return toFSError(err)
+	}
+	fs.broadcast(s, vault, dto.NoteSyncRenameMessage{
+		Path:             newNote.Path,
+		PathHash:         newNote.PathHash,
+		ContentHash:      newNote.ContentHash,
+		Ctime:            newNote.Ctime,
+		Mtime:            newNote.Mtime,
+		Size:             newNote.Size,
+		OldPath:          oldNote.Path,
+		OldPathHash:      oldNote.PathHash,
+		UpdatedTimestamp: newNote.UpdatedTimestamp,
+	}, "NoteSyncRename")
+	return nil
+}
+
+func (fs *vaultFS) renameFile(ctx context.Context, s *davSession, vault, oldRel, newRel string) error {
+	oldFile, newFile, err := fs.files(s).Rename(ctx, s.uid, &dto.FileRenameRequest{
+		Vault:       vault,
+		Path:        newRel,
+		PathHash:    util.EncodeHash32(newRel),
+		OldPath:     oldRel,
+		OldPathHash: util.EncodeHash32(oldRel),
+	})
+	if err != nil {
+		return toFSError(err)
+	}
+	fs.broadcast(s, vault, dto.FileSyncRenameMessage{
+		Path:             newFile.Path,
+		PathHash:         newFile.PathHash,
+		ContentHash:      newFile.ContentHash,
+		Ctime:            newFile.Ctime,
+		Mtime:            newFile.Mtime,
+		Size:             newFile.Size,
+		UpdatedTimestamp: newFile.UpdatedTimestamp,
+		OldPath:          oldFile.Path,
+		OldPathHash:      oldFile.PathHash,
+	}, "FileSyncRename")
+	return nil
+}
+
+// renameFolder moves a folder with everything below it. FolderService.Rename only moves the folder
+// record (sync clients rename the children themselves), so the children are renamed one by one first.
+// renameFolder 移动文件夹及其下全部内容。FolderService.Rename 仅移动文件夹记录（同步客户端会自行重命名子项），
+// 因此先逐个重命名子项。
+func (fs *vaultFS) renameFolder(ctx context.Context, s *davSession, vault, oldRel, newRel string) error {
+	entries, err := fs.readDir(ctx, s, vault, oldRel)
+	if err != nil {
+		return toFSError(err)
+	}
+	for _, e := range entries {
+		target := newRel + strings.TrimPrefix(e.path, oldRel)
+		switch e.kind {
+		case kindDir:
+			err = fs.renameFolder(ctx, s, vault, e.path, target)
+		case kindNote:
+			err = fs.renameNote(ctx, s, vault, e.path, target)
+		default:
+			err = fs.renameFile(ctx, s, vault, e.path, target)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	oldFolder, newFolder, err := fs.folders(s).Rename(ctx, s.uid, &dto.FolderRenameRequest{
+		Vault:       vault,
+		Path:        newRel,
+		PathHash:    util.EncodeHash32(newRel),
+		OldPath:     oldRel,
+		OldPathHash: util.EncodeHash32(oldRel),
+	})
+	if err != nil {
+		return toFSError(err)
+	}
+	if oldFolder != nil && newFolder != nil {
+		fs.broadcast(s, vault, dto.FolderSyncRenameMessage{
+			Path:             newFolder.Path,
+			PathHash:         newFolder.PathHash,
+			Ctime:            newFolder.Ctime,
+			Mtime:            newFolder.Mtime,
+			OldPath:          oldFolder.Path,
+			OldPathHash:      oldFolder.PathHash,
+			UpdatedTimestamp: newFolder.UpdatedTimestamp,
+		}, "FolderSyncRename")
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package dav_router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/service/mocks"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeBroadcaster struct {
+	actions []string
+}
+
+func (b *fakeBroadcaster) BroadcastToUser(uid int64, c *code.Code, action string) {
+	b.actions = append(b.actions, action)
+}
+
+type davMocks struct {
+	vault  *mocks.MockVaultService
+	note   *mocks.MockNoteService
+	file   *mocks.MockFileService
+	folder *mocks.MockFolderService
+	wss    *fakeBroadcaster
+}
+
+func newTestDavHandler(t *testing.T) (*DavHandler, *davMocks) {
+	m := &davMocks{
+		vault:  new(mocks.MockVaultService),
+		note:   new(mocks.MockNoteService),
+		file:   new(mocks.MockFileService),
+		folder: new(mocks.MockFolderService),
+		wss:    &fakeBroadcaster{},
+	}
+	m.note.On("WithClient", "WebDAV", mock.Anything, mock.Anything).Return(m.note).Maybe()
+	m.file.On("WithClient", "WebDAV", mock.Anything, mock.Anything).Return(m.file).Maybe()
+	m.folder.On("WithClient", "WebDAV", mock.Anything, mock.Anything).Return(m.folder).Maybe()
+
+	h := &DavHandler{
+		fs: &vaultFS{
+			vaultService:  m.vault,
+			noteService:   m.note,
+			fileService:   m.file,
+			folderService: m.folder,
+			wss:           m.wss,
+			tempPath:      t.TempDir(),
+		},
+		logger: zap.NewNop(),
+	}
+	return h, m
+}
+
+func serveDav(h *DavHandler, vaults string, req *http.Request) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handle := func(c *gin.Context) {
+		c.Set("user_token", &pkgapp.UserEntity{UID: 1})
+		c.Set("vaults", vaults)
+		h.Handle(c)
+	}
+	r.Match(Methods, "/dav", handle)
+	r.Match(Methods, "/dav/*path", handle)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestSplitPath verifies WebDAV paths are split into vault and vault-relative path.
+// TestSplitPath 验证 WebDAV 路径被拆分为仓库名与仓库内相对路径。
+func TestSplitPath(t *testing.T) {
+	cases := map[string][2]string{
+		"/":                    {"", ""},
+		"/MyVault":             {"MyVault", ""},
+		"/MyVault/":            {"MyVault", ""},
+		"/MyVault/a/b.md":      {"MyVault", "a/b.md"},
+		"MyVault//a/../c.png":  {"MyVault", "c.png"},
+		"/MyVault/../Other/x":  {"Other", "x"},
+		"/../../etc/passwd.md": {"etc", "passwd.md"},
+	}
+	for in, want := range cases {
+		vault, rel := splitPath(in)
+		assert.Equal(t, want[0], vault, in)
+		assert.Equal(t, want[1], rel, in)
+	}
+
+	assert.True(t, isNotePath("a/B.MD"))
+	assert.False(t, isNotePath("a/b.png"))
+	assert.True(t, isJunkName("a/.DS_Store"))
+	assert.True(t, isJunkName("._note.md"))
+	assert.False(t, isJunkName("note.md"))
+}
+
+// TestDav_PropfindRootListsAllowedVaults verifies the root only lists vaults allowed by the token.
+// TestDav_PropfindRootListsAllowedVaults 验证根目录只列出 Token 允许访问的仓库。
+func TestDav_PropfindRootListsAllowedVaults(t *testing.T) {
+	h, m := newTestDavHandler(t)
+	m.vault.On("List", mock.Anything, int64(1)).Return([]*dto.VaultDTO{{Name: "Work"}, {Name: "Private"}}, nil)
+
+	req := httptest.NewRequest("PROPFIND", "/dav/", nil)
+	req.Header.Set("Depth", "1")
+	w := serveDav(h, "Work", req)
+
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+	assert.Contains(t, w.Body.String(), "/dav/Work/")
+	assert.NotContains(t, w.Body.String(), "Private")
+}
+
+// TestDav_PropfindFolder verifies a folder lists its subfolders, notes and attachments.
+// TestDav_PropfindFolder 验证文件夹列出其子文件夹、笔记和附件。
+func TestDav_PropfindFolder(t *testing.T) {
+	h, m := newTestDavHandler(t)
+	m.folder.On("Get", mock.Anything, int64(1), mock.MatchedBy(func(p *dto.FolderGetRequest) bool { return p.Path == "Projects" })).
+		Return(&dto.FolderDTO{Path: "Projects", Action: "create"}, nil)
+	m.file.On("Get", mock.Anything, int64(1), mock.Anything).Return(nil, code.ErrorFileNotFound)
+	m.folder.On("List", mock.Anything, int64(1), &dto.FolderListRequest{Vault: "MyVault", Path: "Projects"}).
+		Return([]*dto.FolderDTO{{Path: "Projects/Sub"}}, nil)
+	m.folder.On("ListNotes", mock.Anything, int64(1), mock.Anything, mock.Anything).
+		Return([]*dto.NoteNoContentDTO{{Path: "Projects/Plan.md", Size: 12}}, 1, nil)
+	m.folder.On("ListFiles", mock.Anything, int64(1), mock.Anything, mock.Anything).
+		Return([]*dto.FileDTO{{Path: "Projects/logo.png", Size: 34, ContentHash: "abc"}}, 1, nil)
+
+	req := httptest.NewRequest("PROPFIND", "/dav/MyVault/Projects", nil)
+	req.Header.Set("Depth", "1")
+	w := serveDav(h, "", req)
+
+	require.Equal(t, http.StatusMultiStatus, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "/dav/MyVault/Projects/Sub/")
+	assert.Contains(t, body, "/dav/MyVault/Projects/Plan.md")
+	assert.Contains(t, body, "text/markdown")
+	assert.Contains(t, body, "/dav/MyVault/Projects/logo.png")
+	assert.Contains(t, body, `"abc"`)
+}
+
+// TestDav_GetNote verifies a note is served from its stored content.
+// TestDav_GetNote 验证笔记内容从存储中读取并返回。
+func TestDav_GetNote(t *testing.T) {
+	h, m := newTestDavHandler(t)
+	m.note.On("Get", mock.Anything, int64(1), &dto.NoteGetRequest{Vault: "MyVault", Path: "a.md", PathHash: util.EncodeHash32("a.md")}).
+		Return(&dto.NoteDTO{Path: "a.md", Content: "# Hello", Size: 7}, nil)
+
+	w := serveDav(h, "", httptest.NewRequest(http.MethodGet, "/dav/MyVault/a.md", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "# Hello", w.Body.String())
+}
+
+// TestDav_PutNote verifies an upload is funneled through ModifyOrCreate and broadcast to sync clients.
+// TestDav_PutNote 验证上传内容经由 ModifyOrCreate 保存并广播给同步客户端。
+func TestDav_PutNote(t *testing.T) {
+	h, m := newTestDavHandler(t)
+	m.note.On("Get", mock.Anything, int64(1), mock.Anything).Return(nil, code.ErrorNoteNotFound)
+	m.folder.On("Get", mock.Anything, int64(1), mock.Anything).Return(nil, code.ErrorFolderNotFound)
+	m.vault.On("GetByName", mock.Anything, int64(1), "MyVault").Return(&domain.Vault{ID: 7, Name: "MyVault"}, nil)
+	m.note.On("ModifyOrCreate", mock.Anything, int64(1), mock.MatchedBy(func(p *dto.NoteModifyOrCreateRequest) bool {
+		return p.Vault == "MyVault" && p.Path == "new.md" && p.Content == "body" && p.ContentHash == util.EncodeHash32("body")
+	}), false, mock.Anything).Return(true, &dto.NoteDTO{Path: "new.md"}, nil).Once()
+
+	w := serveDav(h, "", httptest.NewRequest(http.MethodPut, "/dav/MyVault/new.md", strings.NewReader("body")))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, []string{"NoteSyncModify"}, m.wss.actions)
+	m.note.AssertExpectations(t)
+
+	left, err := os.ReadDir(h.fs.tempPath)
+	require.NoError(t, err)
+	assert.Empty(t, left, "temp upload must be removed")
+}
+
+// TestDav_PutRejected verifies OS junk files and writes into restricted vaults are refused.
+// TestDav_PutRejected 验证系统垃圾文件及写入受限仓库的请求被拒绝。
+func TestDav_PutRejected(t *testing.T) {
+	h, m := newTestDavHandler(t)
+
+	w := serveDav(h, "", httptest.NewRequest(http.MethodPut, "/dav/MyVault/.DS_Store", strings.NewReader("x")))
+	assert.NotEqual(t, http.StatusCreated, w.Code)
+
+	w = serveDav(h, "Work", httptest.NewRequest(http.MethodPut, "/dav/MyVault/a.md", strings.NewReader("x")))
+	assert.NotEqual(t, http.StatusCreated, w.Code)
+
+	m.note.AssertNotCalled(t, "ModifyOrCreate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Empty(t, m.wss.actions)
+}
+
+// TestDav_MoveNote verifies MOVE renames the note and broadcasts the rename.
+// TestDav_MoveNote 验证 MOVE 重命名笔记并广播重命名事件。
+func TestDav_MoveNote(t *testing.T) {
+	h, m := newTestDavHandler(t)
+	m.note.On("Get", mock.Anything, int64(1), mock.MatchedBy(func(p *dto.NoteGetRequest) bool { return p.Path == "a.md" })).
+		Return(&dto.NoteDTO{Path: "a.md"}, nil)
+	m.note.On("Get", mock.Anything, int64(1), mock.MatchedBy(func(p *dto.NoteGetRequest) bool { return p.Path == "b.md" })).
+		Return(nil, code.ErrorNoteNotFound)
+	m.folder.On("Get", mock.Anything, int64(1), mock.Anything).Return(nil, code.ErrorFolderNotFound)
+	m.note.On("Rename", mock.Anything, int64(1), mock.MatchedBy(func(p *dto.NoteRenameRequest) bool {
+		return p.OldPath == "a.md" && p.Path == "b.md"
+	})).Return(&dto.NoteDTO{Path: "a.md"}, &dto.NoteDTO{Path: "b.md"}, nil).Once()
+
+	req := httptest.NewRequest("MOVE", "/dav/MyVault/a.md", nil)
+	req.Header.Set("Destination", "http://example.com/dav/MyVault/b.md")
+	w := serveDav(h, "", req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, []string{"NoteSyncRename"}, m.wss.actions)
+	m.note.AssertExpectations(t)
+}
+
+// TestToFSError verifies service not-found and permission errors map to os errors.
+// TestToFSError 验证服务层的未找到与权限错误被映射为 os 错误。
+func TestToFSError(t *testing.T) {
+	assert.Equal(t, os.ErrNotExist, toFSError(code.ErrorNoteNotFound))
+	assert.Equal(t, os.ErrNotExist, toFSError(code.ErrorVaultNotFound.WithDetails("x")))
+	assert.Equal(t, os.ErrPermission, toFSError(code.ErrorFolderArchived))
+	assert.Nil(t, toFSError(nil))
+
+	_, err := sessionFrom(context.Background())
+	assert.Equal(t, os.ErrPermission, err)
+}
@@ -0,0 +1,125 @@
+// Package graphql_router serves the read-only GraphQL API over notes, folders, files, tags, links and history
+// Package graphql_router 提供笔记、文件夹、文件、标签、链接与历史的只读 GraphQL API
+package graphql_router
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"go.uber.org/zap"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+const (
+	// maxQueryDepth bounds nested selections such as backlinks of backlinks
+	// maxQueryDepth 限制嵌套选择的深度，例如反向链接的反向链接
+	maxQueryDepth = 12
+	// maxParallelism bounds resolvers run concurrently for one query
+	// maxParallelism 限制单个查询并发执行的解析器数量
+	maxParallelism = 8
+)
+
+// Request a GraphQL request, sent as a JSON body or as query parameters on GET
+// Request GraphQL 请求，以 JSON 请求体发送，或在 GET 时以查询参数发送
+type Request struct {
+	Query         string         `json:"query" form:"query"`                 // GraphQL query document // GraphQL 查询文档
+	OperationName string         `json:"operationName" form:"operationName"` // Operation to run when the document has several // 文档包含多个操作时要执行的操作
+	Variables     map[string]any `json:"variables" form:"-"`                 // Query variables // 查询变量
+}
+
+// GraphQLHandler GraphQL API router handler
+// GraphQLHandler GraphQL API 路由处理器
+type GraphQLHandler struct {
+	App    *app.App
+	schema *graphql.Schema
+}
+
+// NewGraphQLHandler creates GraphQLHandler instance, the schema is parsed and checked against the resolvers once
+// NewGraphQLHandler 创建 GraphQLHandler 实例，schema 只解析并与解析器校验一次
+func NewGraphQLHandler(a *app.App) *GraphQLHandler {
+	return &GraphQLHandler{
+		App: a,
+		schema: graphql.MustParseSchema(schemaSDL, &rootResolver{app: a},
+			graphql.MaxDepth(maxQueryDepth),
+			graphql.MaxParallelism(maxParallelism),
+		),
+	}
+}
+
+// Handle executes a GraphQL query
+// @Summary GraphQL query
+// @Description Query notes, folders, files, tags, links and history as a typed graph, selecting only the fields needed and following nested links such as backlinks of backlinks in one request. Only queries are supported. The response follows the GraphQL over HTTP format with data and errors. File fields require a file read scope. The schema is served by GET /api/graphql/schema.
+// @Tags GraphQL
+// @Security UserAuthToken
+// @Accept json
+// @Produce json
+// @Param params body Request true "GraphQL request"
+// @Success 200 {object} map[string]interface{} "GraphQL response"
+// @Router /api/graphql [post]
+func (h *GraphQLHandler) Handle(c *gin.Context) {
+	uid := pkgapp.GetUID(c)
+	if uid == 0 {
+		h.App.Logger().Error("GraphQLHandler.Handle err uid=0")
+		pkgapp.NewResponse(c).ToResponse(code.ErrorNotUserAuthToken)
+		return
+	}
+
+	req := &Request{}
+	if c.Request.Method == http.MethodGet {
+		if err := c.ShouldBindQuery(req); err != nil {
+			h.fail(c, err.Error())
+			return
+		}
+		if v := c.Query("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				h.fail(c, "variables must be a JSON object")
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(req); err != nil {
+		h.fail(c, err.Error())
+		return
+	}
+	if req.Query == "" {
+		h.fail(c, "query is required")
+		return
+	}
+
+	ctx := withRequestInfo(c.Request.Context(), &requestInfo{
+		uid:        uid,
+		scope:      c.GetString("scope"),
+		vaults:     c.GetString("vaults"),
+		clientType: c.GetHeader("X-Client"),
+	})
+	res := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	if len(res.Errors) > 0 {
+		h.App.Logger().Debug("GraphQLHandler.Handle query errors", zap.Int64("uid", uid), zap.Any("errors", res.Errors))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Schema returns the schema in GraphQL SDL
+// @Summary GraphQL schema
+// @Description Get the GraphQL schema definition of /api/graphql
+// @Tags GraphQL
+// @Security UserAuthToken
+// @Produce plain
+// @Success 200 {string} string "Schema SDL"
+// @Router /api/graphql/schema [get]
+func (h *GraphQLHandler) Schema(c *gin.Context) {
+	c.String(http.StatusOK, schemaSDL)
+}
+
+// fail answers a request that could not be parsed, in the GraphQL error format
+// fail 以 GraphQL 错误格式响应无法解析的请求
+func (h *GraphQLHandler) fail(c *gin.Context, message string) {
+	c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": message}}})
+}
@@ -0,0 +1,122 @@
+package graphql_router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	svcmocks "github.com/haierkeys/fast-note-sync-service/internal/service/mocks"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+type gqlResponse struct {
+	Data   map[string]any   `json:"data"`
+	Errors []map[string]any `json:"errors"`
+}
+
+// execQuery posts a GraphQL query as the given user with optional token restrictions
+// execQuery 以指定用户及可选的令牌限制提交 GraphQL 查询
+func execQuery(t *testing.T, h *GraphQLHandler, query, scope, vaults string) gqlResponse {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{"query": query})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(string(body)))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_token", &pkgapp.UserEntity{UID: 1})
+	c.Set("scope", scope)
+	c.Set("vaults", vaults)
+
+	h.Handle(c)
+	require.Equal(t, http.StatusOK, w.Code)
+	var res gqlResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return res
+}
+
+func noteGet(path string) any {
+	return mock.MatchedBy(func(p *dto.NoteGetRequest) bool { return p.Path == path })
+}
+
+// TestGraphQLHandler_NestedBacklinks verifies a note, its lazily loaded tags and the notes
+// linking to it are returned in one request with only the selected fields.
+// TestGraphQLHandler_NestedBacklinks 验证一次请求即可返回笔记、延迟加载的标签及链接到它的笔记，且仅包含所选字段。
+func TestGraphQLHandler_NestedBacklinks(t *testing.T) {
+	noteSvc := new(svcmocks.MockNoteService)
+	linkSvc := new(svcmocks.MockNoteLinkService)
+	noteSvc.On("Get", mock.Anything, int64(1), noteGet("a.md")).
+		Return(&dto.NoteDTO{ID: 1, Path: "a.md", Content: "hello #work", Mtime: 1700000000000}, nil)
+	noteSvc.On("Get", mock.Anything, int64(1), noteGet("b.md")).
+		Return(&dto.NoteDTO{ID: 2, Path: "b.md", Content: "see [[a]]"}, nil)
+	linkSvc.On("GetBacklinks", mock.Anything, int64(1), mock.MatchedBy(func(p *dto.NoteLinkQueryRequest) bool { return p.Path == "a.md" })).
+		Return([]*dto.NoteLinkItem{{Path: "b.md", LinkText: "a"}}, nil)
+
+	h := NewGraphQLHandler(app.NewTestApp(&app.Services{NoteService: noteSvc, NoteLinkService: linkSvc}))
+	res := execQuery(t, h, `{ note(vault: "v", path: "a.md") { name mtime tags backlinks { linkText note { id content } } } }`, "", "")
+
+	require.Empty(t, res.Errors)
+	note := res.Data["note"].(map[string]any)
+	assert.Equal(t, "a", note["name"])
+	assert.Equal(t, float64(1700000000000), note["mtime"])
+	assert.Equal(t, []any{"work"}, note["tags"])
+	assert.NotContains(t, note, "content", "fields not selected are not returned")
+	backlinks := note["backlinks"].([]any)
+	require.Len(t, backlinks, 1)
+	source := backlinks[0].(map[string]any)["note"].(map[string]any)
+	assert.Equal(t, "2", source["id"])
+	assert.Equal(t, "see [[a]]", source["content"])
+}
+
+// TestGraphQLHandler_MissingNoteIsNull verifies a missing note resolves to null instead of an error.
+// TestGraphQLHandler_MissingNoteIsNull 验证不存在的笔记解析为 null 而非错误。
+func TestGraphQLHandler_MissingNoteIsNull(t *testing.T) {
+	noteSvc := new(svcmocks.MockNoteService)
+	noteSvc.On("Get", mock.Anything, int64(1), noteGet("gone.md")).Return(nil, code.ErrorNoteNotFound)
+
+	h := NewGraphQLHandler(app.NewTestApp(&app.Services{NoteService: noteSvc}))
+	res := execQuery(t, h, `{ note(vault: "v", path: "gone.md") { path } }`, "", "")
+
+	assert.Empty(t, res.Errors)
+	assert.Nil(t, res.Data["note"])
+}
+
+// TestGraphQLHandler_TokenRestrictions verifies vault restricted tokens and tokens without the file
+// read scope are rejected before any service is called.
+// TestGraphQLHandler_TokenRestrictions 验证受仓库限制的令牌及无文件读权限的令牌在调用任何服务前即被拒绝。
+func TestGraphQLHandler_TokenRestrictions(t *testing.T) {
+	noteSvc := new(svcmocks.MockNoteService)
+	fileSvc := new(svcmocks.MockFileService)
+	h := NewGraphQLHandler(app.NewTestApp(&app.Services{NoteService: noteSvc, FileService: fileSvc}))
+
+	res := execQuery(t, h, `{ note(vault: "private", path: "a.md") { path } }`, "", "work")
+	require.Len(t, res.Errors, 1)
+	assert.Contains(t, res.Errors[0]["message"], "private")
+
+	res = execQuery(t, h, `{ file(vault: "work", path: "a.png") { size } }`, "f:note_r", "")
+	require.Len(t, res.Errors, 1)
+	assert.Nil(t, res.Data["file"])
+
+	noteSvc.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything)
+	fileSvc.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestGraphQLHandler_RejectsMutations verifies the endpoint only serves queries.
+// TestGraphQLHandler_RejectsMutations 验证该接口仅支持查询。
+func TestGraphQLHandler_RejectsMutations(t *testing.T) {
+	h := NewGraphQLHandler(app.NewTestApp(&app.Services{}))
+	res := execQuery(t, h, `mutation { deleteNote(path: "a.md") }`, "", "")
+	assert.NotEmpty(t, res.Errors)
+}
@@ -0,0 +1,758 @@
+package graphql_router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+)
+
+// Long 64-bit integer scalar, JSON numbers keep millisecond timestamps exact
+// Long 64 位整数标量，JSON 数字可精确表示毫秒时间戳
+type Long int64
+
+// ImplementsGraphQLType maps Long to the schema scalar
+// ImplementsGraphQLType 将 Long 映射到 schema 中的标量
+func (Long) ImplementsGraphQLType(name string) bool {
+	return name == "Long"
+}
+
+// UnmarshalGraphQL parses a Long argument
+// UnmarshalGraphQL 解析 Long 参数
+func (l *Long) UnmarshalGraphQL(input any) error {
+	switch v := input.(type) {
+	case int32:
+		*l = Long(v)
+	case int64:
+		*l = Long(v)
+	case float64:
+		*l = Long(v)
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return err
+		}
+		*l = Long(n)
+	default:
+		return fmt.Errorf("wrong type for Long: %T", input)
+	}
+	return nil
+}
+
+// requestInfo the caller of a query, set by the handler
+// requestInfo 查询的调用者，由处理器设置
+type requestInfo struct {
+	uid        int64
+	scope      string
+	vaults     string
+	clientType string
+}
+
+type requestInfoKey struct{}
+
+func withRequestInfo(ctx context.Context, info *requestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+func getRequestInfo(ctx context.Context) *requestInfo {
+	if info, ok := ctx.Value(requestInfoKey{}).(*requestInfo); ok {
+		return info
+	}
+	return &requestInfo{}
+}
+
+// checkVault rejects vaults the token is not allowed to access
+// checkVault 拒绝令牌无权访问的仓库
+func checkVault(ctx context.Context, vault string) error {
+	if info := getRequestInfo(ctx); info.vaults != "" && !util.VerifyVaultAccess(info.vaults, vault) {
+		return code.ErrorAuthTokenScopeRestricted.WithDetails("Vault access restricted: " + vault)
+	}
+	return nil
+}
+
+// checkFileRead rejects file fields for tokens without the file read scope
+// checkFileRead 对没有文件读权限的令牌拒绝文件字段
+func checkFileRead(ctx context.Context) error {
+	info := getRequestInfo(ctx)
+	if !pkgapp.VerifyPermissions(info.scope, "rest", info.clientType, "file_r") {
+		return code.ErrorAuthTokenScopeRestricted.WithDetails("Permission denied: files")
+	}
+	return nil
+}
+
+// notFound reports whether err means the requested object does not exist, resolved as null
+// notFound 判断 err 是否表示请求的对象不存在，此时解析为 null
+func notFound(err error) bool {
+	var c *code.Code
+	if !errors.As(err, &c) {
+		return false
+	}
+	switch c.Code() {
+	case code.ErrorVaultNotFound.Code(), code.ErrorNoteNotFound.Code(), code.ErrorFolderNotFound.Code(),
+		code.ErrorFileNotFound.Code(), code.ErrorHistoryNotFound.Code():
+		return true
+	}
+	return false
+}
+
+// pager builds a service pager from page arguments, clamped like the REST endpoints
+// pager 根据分页参数构建服务层分页器，与 REST 接口一样进行限制
+func pager(page, pageSize int32) *pkgapp.Pager {
+	return &pkgapp.Pager{Page: pkgapp.GetPage(int(page)), PageSize: pkgapp.GetPageSize(int(pageSize))}
+}
+
+func id(n int64) graphql.ID {
+	return graphql.ID(strconv.FormatInt(n, 10))
+}
+
+func optional(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// noteName returns the file name without .md
+// noteName 返回不含 .md 的文件名
+func noteName(p string) string {
+	return strings.TrimSuffix(path.Base(p), ".md")
+}
+
+// parentPath returns the folder containing p, empty at the vault root
+// parentPath 返回包含 p 的文件夹，位于仓库根目录时为空
+func parentPath(p string) string {
+	dir := path.Dir(strings.Trim(p, "/"))
+	if dir == "." || dir == "/" {
+		return ""
+	}
+	return dir
+}
+
+// rootResolver resolves the Query type
+// rootResolver 解析 Query 类型
+type rootResolver struct {
+	app *app.App
+}
+
+func (r *rootResolver) Vaults(ctx context.Context) ([]*vaultResolver, error) {
+	info := getRequestInfo(ctx)
+	vaults, err := r.app.VaultService.List(ctx, info.uid)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*vaultResolver, 0, len(vaults))
+	for _, v := range vaults {
+		if info.vaults != "" && !util.VerifyVaultAccess(info.vaults, v.Name) {
+			continue
+		}
+		res = append(res, &vaultResolver{app: r.app, v: v})
+	}
+	return res, nil
+}
+
+func (r *rootResolver) Vault(ctx context.Context, args struct{ Name string }) (*vaultResolver, error) {
+	if err := checkVault(ctx, args.Name); err != nil {
+		return nil, err
+	}
+	v, err := r.app.VaultService.GetByName(ctx, getRequestInfo(ctx).uid, args.Name)
+	if err != nil {
+		if notFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &vaultResolver{app: r.app, v: &dto.VaultDTO{
+		ID:        v.ID,
+		Name:      v.Name,
+		NoteCount: v.NoteCount,
+		NoteSize:  v.NoteSize,
+		FileCount: v.FileCount,
+		FileSize:  v.FileSize,
+	}}, nil
+}
+
+func (r *rootResolver) Note(ctx context.Context, args struct{ Vault, Path string }) (*noteResolver, error) {
+	return getNote(ctx, r.app, args.Vault, args.Path)
+}
+
+func (r *rootResolver) Folder(ctx context.Context, args struct{ Vault, Path string }) (*folderResolver, error) {
+	return getFolder(ctx, r.app, args.Vault, args.Path)
+}
+
+func (r *rootResolver) File(ctx context.Context, args struct{ Vault, Path string }) (*fileResolver, error) {
+	return getFile(ctx, r.app, args.Vault, args.Path)
+}
+
+func (r *rootResolver) History(ctx context.Context, args struct {
+	Vault string
+	ID    graphql.ID
+}) (*historyResolver, error) {
+	if err := checkVault(ctx, args.Vault); err != nil {
+		return nil, err
+	}
+	historyID, err := strconv.ParseInt(string(args.ID), 10, 64)
+	if err != nil {
+		return nil, code.ErrorInvalidParams.WithDetails("id")
+	}
+	uid := getRequestInfo(ctx).uid
+	vaultID, err := r.app.VaultService.MustGetID(ctx, uid, args.Vault)
+	if err != nil {
+		if notFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	h, err := r.app.NoteHistoryService.Get(ctx, uid, historyID)
+	if err != nil {
+		if notFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if h.VaultID != vaultID {
+		return nil, nil
+	}
+	return newHistoryResolver(r.app, h), nil
+}
+
+// getNote resolves a note by path, null when it does not exist
+// getNote 按路径解析笔记，不存在时为 null
+func getNote(ctx context.Context, a *app.App, vault, notePath string) (*noteResolver, error) {
+	if err := checkVault(ctx, vault); err != nil {
+		return nil, err
+	}
+	n, err := a.NoteService.Get(ctx, getRequestInfo(ctx).uid, &dto.NoteGetRequest{Vault: vault, Path: notePath, PathHash: util.EncodeHash32(notePath)})
+	if err != nil {
+		if notFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return newNoteResolver(a, vault, n), nil
+}
+
+// getFolder resolves a folder by path, null when it does not exist
+// getFolder 按路径解析文件夹，不存在时为 null
+func getFolder(ctx context.Context, a *app.App, vault, folderPath string) (*folderResolver, error) {
+	if err := checkVault(ctx, vault); err != nil {
+		return nil, err
+	}
+	f, err := a.FolderService.Get(ctx, getRequestInfo(ctx).uid, &dto.FolderGetRequest{Vault: vault, Path: folderPath, PathHash: util.EncodeHash32(folderPath)})
+	if err != nil {
+		if notFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &folderResolver{app: a, vault: vault, f: f}, nil
+}
+
+// getFile resolves a file by path, null when it does not exist
+// getFile 按路径解析文件，不存在时为 null
+func getFile(ctx context.Context, a *app.App, vault, filePath string) (*fileResolver, error) {
+	if err := checkVault(ctx, vault); err != nil {
+		return nil, err
+	}
+	if err := checkFileRead(ctx); err != nil {
+		return nil, err
+	}
+	f, err := a.FileService.Get(ctx, getRequestInfo(ctx).uid, &dto.FileGetRequest{Vault: vault, Path: filePath, PathHash: util.EncodeHash32(filePath)})
+	if err != nil {
+		if notFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &fileResolver{f: f}, nil
+}
+
+// vaultResolver resolves the Vault type
+// vaultResolver 解析 Vault 类型
+type vaultResolver struct {
+	app *app.App
+	v   *dto.VaultDTO
+}
+
+func (r *vaultResolver) ID() graphql.ID  { return id(r.v.ID) }
+func (r *vaultResolver) Name() string    { return r.v.Name }
+func (r *vaultResolver) NoteCount() Long { return Long(r.v.NoteCount) }
+func (r *vaultResolver) NoteSize() Long  { return Long(r.v.NoteSize) }
+func (r *vaultResolver) FileCount() Long { return Long(r.v.FileCount) }
+func (r *vaultResolver) FileSize() Long  { return Long(r.v.FileSize) }
+
+func (r *vaultResolver) Note(ctx context.Context, args struct{ Path string }) (*noteResolver, error) {
+	return getNote(ctx, r.app, r.v.Name, args.Path)
+}
+
+func (r *vaultResolver) Folder(ctx context.Context, args struct{ Path string }) (*folderResolver, error) {
+	return getFolder(ctx, r.app, r.v.Name, args.Path)
+}
+
+func (r *vaultResolver) File(ctx context.Context, args struct{ Path string }) (*fileResolver, error) {
+	return getFile(ctx, r.app, r.v.Name, args.Path)
+}
+
+func (r *vaultResolver) Notes(ctx context.Context, args struct {
+	Keyword   *string
+	SortBy    *string
+	SortOrder *string
+	Page      int32
+	PageSize  int32
+}) (*notePageResolver, error) {
+	p := pager(args.Page, args.PageSize)
+	notes, total, err := r.app.NoteService.List(ctx, getRequestInfo(ctx).uid, &dto.NoteListRequest{
+		Vault:     r.v.Name,
+		Keyword:   optional(args.Keyword),
+		SortBy:    optional(args.SortBy),
+		SortOrder: optional(args.SortOrder),
+	}, p)
+	if err != nil {
+		return nil, err
+	}
+	return newNotePage(r.app, r.v.Name, notes, total), nil
+}
+
+func (r *vaultResolver) Files(ctx context.Context, args struct {
+	Keyword   *string
+	SortBy    *string
+	SortOrder *string
+	Page      int32
+	PageSize  int32
+}) (*filePageResolver, error) {
+	if err := checkFileRead(ctx); err != nil {
+		return nil, err
+	}
+	files, total, err := r.app.FileService.List(ctx, getRequestInfo(ctx).uid, &dto.FileListRequest{
+		Vault:     r.v.Name,
+		Keyword:   optional(args.Keyword),
+		SortBy:    optional(args.SortBy),
+		SortOrder: optional(args.SortOrder),
+	}, pager(args.Page, args.PageSize))
+	if err != nil {
+		return nil, err
+	}
+	return newFilePage(files, total), nil
+}
+
+func (r *vaultResolver) Folders(ctx context.Context) ([]*folderResolver, error) {
+	return listFolders(ctx, r.app, r.v.Name, "")
+}
+
+// Tags collects the tags of every note of the vault, most used first
+// Tags 汇总仓库中所有笔记的标签，使用最多的在前
+func (r *vaultResolver) Tags(ctx context.Context) ([]*tagResolver, error) {
+	graph, err := r.app.NoteLinkService.GetGraph(ctx, getRequestInfo(ctx).uid, &dto.VaultGraphRequest{Vault: r.v.Name})
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*tagResolver)
+	for _, node := range graph.Nodes {
+		for _, t := range node.Tags {
+			tag, ok := byName[t]
+			if !ok {
+				tag = &tagResolver{app: r.app, vault: r.v.Name, name: t}
+				byName[t] = tag
+			}
+			tag.paths = append(tag.paths, node.Path)
+		}
+	}
+	tags := make([]*tagResolver, 0, len(byName))
+	for _, tag := range byName {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if len(tags[i].paths) != len(tags[j].paths) {
+			return len(tags[i].paths) > len(tags[j].paths)
+		}
+		return tags[i].name < tags[j].name
+	})
+	return tags, nil
+}
+
+// listFolders lists the child folders of parent, the vault root when empty
+// listFolders 列出 parent 的子文件夹，为空时为仓库根目录
+func listFolders(ctx context.Context, a *app.App, vault, parent string) ([]*folderResolver, error) {
+	if err := checkVault(ctx, vault); err != nil {
+		return nil, err
+	}
+	req := &dto.FolderListRequest{Vault: vault, Path: parent}
+	if parent != "" {
+		req.PathHash = util.EncodeHash32(parent)
+	}
+	folders, err := a.FolderService.List(ctx, getRequestInfo(ctx).uid, req)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*folderResolver, 0, len(folders))
+	for _, f := range folders {
+		res = append(res, &folderResolver{app: a, vault: vault, f: f})
+	}
+	return res, nil
+}
+
+// noteResolver resolves the Note type; list results carry no content, it is loaded on first use
+// noteResolver 解析 Note 类型；列表结果不含内容，首次使用时加载
+type noteResolver struct {
+	app   *app.App
+	vault string
+	meta  *dto.NoteNoContentDTO
+
+	once sync.Once
+	full *dto.NoteDTO
+	err  error
+}
+
+func newNoteResolver(a *app.App, vault string, n *dto.NoteDTO) *noteResolver {
+	r := &noteResolver{app: a, vault: vault, full: n, meta: &dto.NoteNoContentDTO{
+		ID:       n.ID,
+		Path:     n.Path,
+		PathHash: n.PathHash,
+		Version:  n.Version,
+		Ctime:    n.Ctime,
+		Mtime:    n.Mtime,
+		Size:     n.Size,
+	}}
+	r.once.Do(func() {})
+	return r
+}
+
+// load fetches the full note, including content
+// load 获取包含内容的完整笔记
+func (r *noteResolver) load(ctx context.Context) (*dto.NoteDTO, error) {
+	r.once.Do(func() {
+		r.full, r.err = r.app.NoteService.Get(ctx, getRequestInfo(ctx).uid, &dto.NoteGetRequest{Vault: r.vault, Path: r.meta.Path, PathHash: r.meta.PathHash})
+	})
+	return r.full, r.err
+}
+
+func (r *noteResolver) ID() graphql.ID   { return id(r.meta.ID) }
+func (r *noteResolver) Path() string     { return r.meta.Path }
+func (r *noteResolver) PathHash() string { return r.meta.PathHash }
+func (r *noteResolver) Name() string     { return noteName(r.meta.Path) }
+func (r *noteResolver) Version() Long    { return Long(r.meta.Version) }
+func (r *noteResolver) Size() Long       { return Long(r.meta.Size) }
+func (r *noteResolver) Ctime() Long      { return Long(r.meta.Ctime) }
+func (r *noteResolver) Mtime() Long      { return Long(r.meta.Mtime) }
+
+func (r *noteResolver) Content(ctx context.Context) (string, error) {
+	n, err := r.load(ctx)
+	if err != nil {
+		return "", err
+	}
+	return n.Content, nil
+}
+
+func (r *noteResolver) ContentHash(ctx context.Context) (string, error) {
+	n, err := r.load(ctx)
+	if err != nil {
+		return "", err
+	}
+	return n.ContentHash, nil
+}
+
+func (r *noteResolver) Tags(ctx context.Context) ([]string, error) {
+	n, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags := util.ParseTags(n.Content)
+	if tags == nil {
+		tags = []string{}
+	}
+	return tags, nil
+}
+
+func (r *noteResolver) Folder(ctx context.Context) (*folderResolver, error) {
+	dir := parentPath(r.meta.Path)
+	if dir == "" {
+		return nil, nil
+	}
+	return getFolder(ctx, r.app, r.vault, dir)
+}
+
+func (r *noteResolver) Backlinks(ctx context.Context) ([]*linkResolver, error) {
+	links, err := r.app.NoteLinkService.GetBacklinks(ctx, getRequestInfo(ctx).uid, &dto.NoteLinkQueryRequest{Vault: r.vault, Path: r.meta.Path, PathHash: r.meta.PathHash})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*linkResolver, 0, len(links))
+	for _, l := range links {
+		res = append(res, &linkResolver{app: r.app, vault: r.vault, item: l, target: l.Path})
+	}
+	return res, nil
+}
+
+// Outlinks returns the links of the note, each resolved to the note it points to like Obsidian does
+// Outlinks 返回笔记中的链接，并与 Obsidian 一致地解析到其指向的笔记
+func (r *noteResolver) Outlinks(ctx context.Context) ([]*linkResolver, error) {
+	uid := getRequestInfo(ctx).uid
+	links, err := r.app.NoteLinkService.GetOutlinks(ctx, uid, &dto.NoteLinkQueryRequest{Vault: r.vault, Path: r.meta.Path, PathHash: r.meta.PathHash})
+	if err != nil {
+		return nil, err
+	}
+	n, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := r.app.NoteLinkService.ResolveLinks(ctx, uid, r.vault, r.meta.Path, n.Content)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*linkResolver, 0, len(links))
+	for _, l := range links {
+		target := l.Path
+		if i := strings.IndexByte(target, '#'); i >= 0 {
+			target = target[:i]
+		}
+		res = append(res, &linkResolver{app: r.app, vault: r.vault, item: l, target: resolved[strings.TrimSpace(target)]})
+	}
+	return res, nil
+}
+
+func (r *noteResolver) History(ctx context.Context, args struct {
+	Page     int32
+	PageSize int32
+}) (*historyPageResolver, error) {
+	list, total, err := r.app.NoteHistoryService.List(ctx, getRequestInfo(ctx).uid, &dto.NoteHistoryListRequest{Vault: r.vault, Path: r.meta.Path, PathHash: r.meta.PathHash}, pager(args.Page, args.PageSize))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*historyResolver, 0, len(list))
+	for _, h := range list {
+		items = append(items, &historyResolver{app: r.app, meta: h})
+	}
+	return &historyPageResolver{total: int32(total), items: items}, nil
+}
+
+// linkResolver resolves the NoteLink type
+// linkResolver 解析 NoteLink 类型
+type linkResolver struct {
+	app    *app.App
+	vault  string
+	item   *dto.NoteLinkItem
+	target string // Path of the linked note, empty when unresolved // 链接笔记的路径，未解析时为空
+}
+
+func (r *linkResolver) Path() string     { return r.item.Path }
+func (r *linkResolver) LinkText() string { return r.item.LinkText }
+func (r *linkResolver) Context() string  { return r.item.Context }
+func (r *linkResolver) IsEmbed() bool    { return r.item.IsEmbed }
+
+func (r *linkResolver) Note(ctx context.Context) (*noteResolver, error) {
+	if r.target == "" {
+		return nil, nil
+	}
+	return getNote(ctx, r.app, r.vault, r.target)
+}
+
+// folderResolver resolves the Folder type
+// folderResolver 解析 Folder 类型
+type folderResolver struct {
+	app   *app.App
+	vault string
+	f     *dto.FolderDTO
+}
+
+func (r *folderResolver) Path() string     { return r.f.Path }
+func (r *folderResolver) PathHash() string { return r.f.PathHash }
+func (r *folderResolver) Name() string     { return path.Base(r.f.Path) }
+func (r *folderResolver) Archived() bool   { return r.f.Archived }
+func (r *folderResolver) Ctime() Long      { return Long(r.f.Ctime) }
+func (r *folderResolver) Mtime() Long      { return Long(r.f.Mtime) }
+
+func (r *folderResolver) Parent(ctx context.Context) (*folderResolver, error) {
+	dir := parentPath(r.f.Path)
+	if dir == "" {
+		return nil, nil
+	}
+	return getFolder(ctx, r.app, r.vault, dir)
+}
+
+func (r *folderResolver) Folders(ctx context.Context) ([]*folderResolver, error) {
+	return listFolders(ctx, r.app, r.vault, r.f.Path)
+}
+
+func (r *folderResolver) Notes(ctx context.Context, args struct {
+	SortBy    *string
+	SortOrder *string
+	Page      int32
+	PageSize  int32
+}) (*notePageResolver, error) {
+	notes, total, err := r.app.FolderService.ListNotes(ctx, getRequestInfo(ctx).uid, &dto.FolderContentRequest{
+		Vault:     r.vault,
+		Path:      r.f.Path,
+		PathHash:  r.f.PathHash,
+		SortBy:    optional(args.SortBy),
+		SortOrder: optional(args.SortOrder),
+	}, pager(args.Page, args.PageSize))
+	if err != nil {
+		return nil, err
+	}
+	return newNotePage(r.app, r.vault, notes, total), nil
+}
+
+func (r *folderResolver) Files(ctx context.Context, args struct {
+	SortBy    *string
+	SortOrder *string
+	Page      int32
+	PageSize  int32
+}) (*filePageResolver, error) {
+	if err := checkFileRead(ctx); err != nil {
+		return nil, err
+	}
+	files, total, err := r.app.FolderService.ListFiles(ctx, getRequestInfo(ctx).uid, &dto.FolderContentRequest{
+		Vault:     r.vault,
+		Path:      r.f.Path,
+		PathHash:  r.f.PathHash,
+		SortBy:    optional(args.SortBy),
+		SortOrder: optional(args.SortOrder),
+	}, pager(args.Page, args.PageSize))
+	if err != nil {
+		return nil, err
+	}
+	return newFilePage(files, total), nil
+}
+
+// fileResolver resolves the File type
+// fileResolver 解析 File 类型
+type fileResolver struct {
+	f *dto.FileDTO
+}
+
+func (r *fileResolver) ID() graphql.ID      { return id(r.f.ID) }
+func (r *fileResolver) Path() string        { return r.f.Path }
+func (r *fileResolver) PathHash() string    { return r.f.PathHash }
+func (r *fileResolver) Name() string        { return path.Base(r.f.Path) }
+func (r *fileResolver) ContentHash() string { return r.f.ContentHash }
+func (r *fileResolver) Size() Long          { return Long(r.f.Size) }
+func (r *fileResolver) Ctime() Long         { return Long(r.f.Ctime) }
+func (r *fileResolver) Mtime() Long         { return Long(r.f.Mtime) }
+
+// tagResolver resolves the Tag type
+// tagResolver 解析 Tag 类型
+type tagResolver struct {
+	app   *app.App
+	vault string
+	name  string
+	paths []string
+}
+
+func (r *tagResolver) Name() string { return r.name }
+func (r *tagResolver) Count() int32 { return int32(len(r.paths)) }
+
+func (r *tagResolver) Notes(ctx context.Context) ([]*noteResolver, error) {
+	res := make([]*noteResolver, 0, len(r.paths))
+	for _, p := range r.paths {
+		n, err := getNote(ctx, r.app, r.vault, p)
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			res = append(res, n)
+		}
+	}
+	return res, nil
+}
+
+// historyResolver resolves the NoteHistory type; list results carry no content, it is loaded on first use
+// historyResolver 解析 NoteHistory 类型；列表结果不含内容，首次使用时加载
+type historyResolver struct {
+	app  *app.App
+	meta *dto.NoteHistoryNoContentDTO
+
+	once sync.Once
+	full *dto.NoteHistoryDTO
+	err  error
+}
+
+func newHistoryResolver(a *app.App, h *dto.NoteHistoryDTO) *historyResolver {
+	r := &historyResolver{app: a, full: h, meta: &dto.NoteHistoryNoContentDTO{
+		ID:            h.ID,
+		NoteID:        h.NoteID,
+		VaultID:       h.VaultID,
+		Path:          h.Path,
+		ClientName:    h.ClientName,
+		ClientType:    h.ClientType,
+		ClientVersion: h.ClientVersion,
+		Version:       h.Version,
+		CreatedAt:     h.CreatedAt,
+	}}
+	r.once.Do(func() {})
+	return r
+}
+
+func (r *historyResolver) ID() graphql.ID        { return id(r.meta.ID) }
+func (r *historyResolver) NoteID() graphql.ID    { return id(r.meta.NoteID) }
+func (r *historyResolver) Path() string          { return r.meta.Path }
+func (r *historyResolver) Version() Long         { return Long(r.meta.Version) }
+func (r *historyResolver) ClientName() string    { return r.meta.ClientName }
+func (r *historyResolver) ClientType() string    { return r.meta.ClientType }
+func (r *historyResolver) ClientVersion() string { return r.meta.ClientVersion }
+func (r *historyResolver) CreatedAt() string     { return r.meta.CreatedAt.String() }
+
+func (r *historyResolver) Content(ctx context.Context) (string, error) {
+	r.once.Do(func() {
+		r.full, r.err = r.app.NoteHistoryService.Get(ctx, getRequestInfo(ctx).uid, r.meta.ID)
+	})
+	if r.err != nil {
+		return "", r.err
+	}
+	return r.full.Content, nil
+}
+
+// notePageResolver resolves the NotePage type
+// notePageResolver 解析 NotePage 类型
+type notePageResolver struct {
+	total int32
+	items []*noteResolver
+}
+
+func newNotePage(a *app.App, vault string, notes []*dto.NoteNoContentDTO, total int) *notePageResolver {
+	items := make([]*noteResolver, 0, len(notes))
+	for _, n := range notes {
+		items = append(items, &noteResolver{app: a, vault: vault, meta: n})
+	}
+	return &notePageResolver{total: int32(total), items: items}
+}
+
+func (r *notePageResolver) Total() int32           { return r.total }
+func (r *notePageResolver) Items() []*noteResolver { return r.items }
+
+// filePageResolver resolves the FilePage type
+// filePageResolver 解析 FilePage 类型
+type filePageResolver struct {
+	total int32
+	items []*fileResolver
+}
+
+func newFilePage(files []*dto.FileDTO, total int) *filePageResolver {
+	items := make([]*fileResolver, 0, len(files))
+	for _, f := range files {
+		items = append(items, &fileResolver{f: f})
+	}
+	return &filePageResolver{total: int32(total), items: items}
+}
+
+func (r *filePageResolver) Total() int32           { return r.total }
+func (r *filePageResolver) Items() []*fileResolver { return r.items }
+
+// historyPageResolver resolves the HistoryPage type
+// historyPageResolver 解析 HistoryPage 类型
+type historyPageResolver struct {
+	total int32
+	items []*historyResolver
+}
+
+func (r *historyPageResolver) Total() int32              { return r.total }
+func (r *historyPageResolver) Items() []*historyResolver { return r.items }
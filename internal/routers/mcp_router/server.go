@@ -87,11 +87,31 @@ func checkVaultAccess(ctx context.Context, vault string) error {
 	return nil
 }
 
+// readOnlyGuard refuses tools not annotated read-only while the server is in read-only maintenance mode.
+// Every MCP call is a POST, so the HTTP ReadOnly middleware cannot tell reads from writes
+// readOnlyGuard 在只读维护模式下拒绝未标注为只读的工具。
+// MCP 调用均为 POST，HTTP 层的 ReadOnly 中间件无法区分读写
+func readOnlyGuard(appContainer *app.App, srv **mcpsrv.MCPServer) mcpsrv.ToolHandlerMiddleware {
+	return func(next mcpsrv.ToolHandlerFunc) mcpsrv.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if appContainer.ReadOnly().Enabled() {
+				tool := (*srv).GetTool(request.Params.Name)
+				if tool == nil || tool.Tool.Annotations.ReadOnlyHint == nil || !*tool.Tool.Annotations.ReadOnlyHint {
+					return mcp.NewToolResultError("server is in read-only maintenance mode"), nil
+				}
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
 func NewMCPServer(appContainer *app.App, wss *pkgapp.WebsocketServer) *mcpsrv.MCPServer {
 	// Create MCP server
-	srv := mcpsrv.NewMCPServer(
+	var srv *mcpsrv.MCPServer
+	srv = mcpsrv.NewMCPServer(
 		"fast-note-sync-service",
 		appContainer.Version().Version,
+		mcpsrv.WithToolHandlerMiddleware(readOnlyGuard(appContainer, &srv)),
 	)
 
 	// Note Tools
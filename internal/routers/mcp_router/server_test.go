@@ -0,0 +1,47 @@
+package mcp_router
+
+import (
+	"context"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpsrv "github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadOnlyGuard verifies write tools are refused in read-only mode while read tools still run.
+// TestReadOnlyGuard 验证只读模式下写工具被拒绝，读工具仍可执行。
+func TestReadOnlyGuard(t *testing.T) {
+	appContainer := app.NewTestApp(&app.Services{})
+	cfg := &app.AppConfig{}
+
+	var srv *mcpsrv.MCPServer
+	srv = mcpsrv.NewMCPServer("test", "0", mcpsrv.WithToolHandlerMiddleware(readOnlyGuard(appContainer, &srv)))
+	ok := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	srv.AddTool(withMCPToolMetadata(mcp.NewTool("note_get"), cfg, mcpToolMetadata{ReadOnly: true}), ok)
+	srv.AddTool(withMCPToolMetadata(mcp.NewTool("note_delete"), cfg, mcpToolMetadata{Destructive: true}), ok)
+
+	call := func(name string) *mcp.CallToolResult {
+		next := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return srv.GetTool(name).Handler(ctx, request)
+		}
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		result, err := readOnlyGuard(appContainer, &srv)(next)(context.Background(), request)
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.False(t, call("note_delete").IsError)
+
+	appContainer.ReadOnly().Set(true, "maintenance")
+	assert.False(t, call("note_get").IsError)
+	assert.True(t, call("note_delete").IsError)
+
+	appContainer.ReadOnly().Set(false, "")
+	assert.False(t, call("note_delete").IsError)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/gin-gonic/gin"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/lxzan/gws"
+	"go.uber.org/zap"
 )
 
 var methodLimiters = limiter.NewMethodLimiter().AddBuckets(
@@ -29,6 +30,18 @@ var methodLimiters = limiter.NewMethodLimiter().AddBuckets(
 		Capacity:     5,
 		Quantum:      1,
 	},
+	limiter.BucketRule{
+		Key:          "/api/user/totp/enable",
+		FillInterval: time.Second,
+		Capacity:     5,
+		Quantum:      1,
+	},
+	limiter.BucketRule{
+		Key:          "/api/user/totp/disable",
+		FillInterval: time.Second,
+		Capacity:     5,
+		Quantum:      1,
+	},
 	limiter.BucketRule{
 		Key:          "/api/share/verify",
 		FillInterval: time.Second,
@@ -56,6 +69,21 @@ func NewRouter(frontendFiles embed.FS, appContainer *app.App, uni *ut.UniversalT
 		responseHeader.Set(k, v)
 	}
 
+	// Inbound WebSocket message limiters, nil when rate limiting is disabled
+	// WebSocket 入站消息限流器，未启用限流时为 nil
+	var wsUserLimiter, wsConnLimiter *limiter.KeyedLimiter
+	if cfg.RateLimit.Enabled {
+		wsUserLimiter = limiter.NewKeyedLimiter(cfg.RateLimit.WSUserRate, cfg.RateLimit.WSUserBurst)
+		wsConnLimiter = limiter.NewKeyedLimiter(cfg.RateLimit.WSConnRate, cfg.RateLimit.WSConnBurst)
+	}
+
+	// Invalid values fall back to the default TTL of the sync journal
+	// 非法值回退到同步日志的默认保留时长
+	syncJournalTTL, _ := util.ParseDuration(cfg.App.WebSocketSyncJournalTTL)
+	// Invalid values disable the authorization timeout
+	// 非法值表示不限制鉴权时长
+	wsAuthTimeout, _ := util.ParseDuration(cfg.App.WebSocketAuthTimeout)
+
 	var wss = pkgapp.NewWebsocketServer(pkgapp.WSConfig{
 		GWSOption: gws.ServerOption{
 			ResponseHeader:   responseHeader,
@@ -81,16 +109,31 @@ func NewRouter(frontendFiles embed.FS, appContainer *app.App, uni *ut.UniversalT
 		// WriteTimeout application-layer write deadline for outbound messages, from config
 		// (already resolved: nil-vs-explicit-0 distinguished by defaults.Set on the *int field)
 		// WriteTimeout 应用层出站消息写超时，来自配置（已解析：*int 字段上 defaults.Set 已区分 nil 与显式 0）
-		WriteTimeout: time.Duration(*cfg.App.WebSocketWriteTimeout) * time.Second,
+		WriteTimeout:    time.Duration(*cfg.App.WebSocketWriteTimeout) * time.Second,
+		UserLimiter:     wsUserLimiter,
+		ConnLimiter:     wsConnLimiter,
+		SyncJournalSize: cfg.App.WebSocketSyncJournalSize,
+		SyncJournalTTL:  syncJournalTTL,
+		MsgpackEnabled:  cfg.App.WebSocketMsgpackEnabled == nil || *cfg.App.WebSocketMsgpackEnabled,
+		PingInterval:    time.Duration(cfg.App.WebSocketPingInterval),
+		PingWait:        time.Duration(cfg.App.WebSocketPingWait),
+		// Limits that keep stuck or runaway clients from exhausting connections, goroutines and memory
+		// 防止卡住或失控的客户端耗尽连接、协程与内存的限制
+		MaxConnections:        cfg.App.WebSocketMaxConnections,
+		MaxConnectionsPerUser: cfg.App.WebSocketMaxConnectionsPerUser,
+		AuthTimeout:           wsAuthTimeout,
+		SendQueueSize:         cfg.App.WebSocketSendQueueSize,
 	}, appContainer)
 	appContainer.SetWSS(wss)
+	wss.UseClusterBus(appContainer.ClusterBus())
+	wss.UseSyncTrace(appContainer.SyncTracer())
 
 	// Initialize WebSocket routes
 	// 初始化 WebSocket 路由
 	initWebSocketRoutes(wss, appContainer)
 
-	r := gin.New()
-	r.Use(middleware.Proxy(cfg.Server.TrustedProxies))
+	r := newEngine(cfg.Server.TrustedProxies, appContainer.Logger())
+	useSecurityMiddlewares(r, appContainer)
 	r.Use(middleware.Cors(cfg.Server.CORSAllowedOrigins, cfg.Server.ExtApiUrl))
 	if len(cfg.Server.CustomResponseHeaders) > 0 {
 		r.Use(middleware.CustomHeaders(cfg.Server.CustomResponseHeaders))
@@ -114,11 +157,13 @@ func NewRouter(frontendFiles embed.FS, appContainer *app.App, uni *ut.UniversalT
 	// 注册 API 路由
 	registerAPIRoutes(r, appContainer, wss, uni)
 
-	// Register OpenAPI/Swagger routes only for non ReleaseMode
-	// 注册 OpenAPI/Swagger 路由
-	if gin.Mode() != gin.ReleaseMode {
-		registerOpenAPIRoutes(r, frontendFiles)
-	}
+	// Register WebDAV routes
+	// 注册 WebDAV 路由
+	registerDAVRoutes(r, appContainer, wss)
+
+	// Register OpenAPI/Swagger routes, debug pages only for non ReleaseMode
+	// 注册 OpenAPI/Swagger 路由，调试页面仅在非 ReleaseMode 下注册
+	registerOpenAPIRoutes(r, frontendFiles, appContainer)
 
 	r.NoRoute(middleware.NoFound())
 
@@ -127,8 +172,8 @@ func NewRouter(frontendFiles embed.FS, appContainer *app.App, uni *ut.UniversalT
 
 func NewWebGuiRouter(frontendFiles embed.FS, appContainer *app.App) *gin.Engine {
 	cfg := appContainer.Config()
-	r := gin.New()
-	r.Use(middleware.Proxy(cfg.Server.TrustedProxies))
+	r := newEngine(cfg.Server.TrustedProxies, appContainer.Logger())
+	useSecurityMiddlewares(r, appContainer)
 	r.Use(middleware.Cors(cfg.Server.CORSAllowedOrigins, cfg.Server.ExtApiUrl))
 	if len(cfg.Server.CustomResponseHeaders) > 0 {
 		r.Use(middleware.CustomHeaders(cfg.Server.CustomResponseHeaders))
@@ -144,8 +189,8 @@ func NewWebGuiRouter(frontendFiles embed.FS, appContainer *app.App) *gin.Engine
 
 func NewShareRouter(frontendFiles embed.FS, appContainer *app.App) *gin.Engine {
 	cfg := appContainer.Config()
-	r := gin.New()
-	r.Use(middleware.Proxy(cfg.Server.TrustedProxies))
+	r := newEngine(cfg.Server.TrustedProxies, appContainer.Logger())
+	useSecurityMiddlewares(r, appContainer)
 	r.Use(middleware.Cors(cfg.Server.CORSAllowedOrigins, cfg.Server.ExtApiUrl))
 	if len(cfg.Server.CustomResponseHeaders) > 0 {
 		r.Use(middleware.CustomHeaders(cfg.Server.CustomResponseHeaders))
@@ -157,3 +202,42 @@ func NewShareRouter(frontendFiles embed.FS, appContainer *app.App) *gin.Engine {
 	r.NoRoute(middleware.NoFound())
 	return r
 }
+
+// newEngine creates a gin engine that honours forwarding headers (X-Forwarded-For, X-Real-IP, X-Forwarded-Proto)
+// only from trusted proxies, so ClientIP cannot be spoofed by a direct client
+// newEngine 创建只信任可信代理转发头（X-Forwarded-For、X-Real-IP、X-Forwarded-Proto）的 gin 引擎，
+// 使直连客户端无法伪造 ClientIP
+func newEngine(trustedProxies []string, logger *zap.Logger) *gin.Engine {
+	trustedProxies = middleware.TrustedProxyList(trustedProxies)
+	r := gin.New()
+	// gin stops at the first invalid entry, the proxies listed after it are not trusted
+	// gin 在第一个非法条目处停止解析，其后列出的代理不被信任
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		logger.Warn("invalid server.trusted-proxies entry, the proxies after it are not trusted", zap.Strings("trustedProxies", trustedProxies), zap.Error(err))
+	}
+	r.Use(middleware.Proxy(trustedProxies))
+	return r
+}
+
+// useSecurityMiddlewares registers the IP filter, honeypot decoys and security headers.
+// Must run after Proxy so ClientIP honours trusted proxies.
+// useSecurityMiddlewares 注册 IP 过滤、诱饵端点与安全响应头。
+// 须在 Proxy 之后注册，以便 ClientIP 正确识别可信代理。
+func useSecurityMiddlewares(r *gin.Engine, appContainer *app.App) {
+	cfg := appContainer.Config()
+
+	r.Use(middleware.IPFilter(appContainer.IPFilter()))
+
+	if cfg.Security.Honeypot.Enabled {
+		banDuration, err := util.ParseDuration(cfg.Security.Honeypot.BanDuration)
+		if err != nil {
+			appContainer.Logger().Warn("invalid security.honeypot.ban-duration, probes will only be logged",
+				zap.String("value", cfg.Security.Honeypot.BanDuration), zap.Error(err))
+		}
+		r.Use(middleware.Honeypot(appContainer.IPFilter(), cfg.Security.Honeypot.Paths, banDuration, appContainer.Logger()))
+	}
+
+	if cfg.Security.Headers.Enabled == nil || *cfg.Security.Headers.Enabled {
+		r.Use(middleware.SecurityHeaders(cfg.Security.Headers))
+	}
+}
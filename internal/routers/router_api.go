@@ -11,9 +11,29 @@ import (
 	appconfig "github.com/haierkeys/fast-note-sync-service/internal/config"
 	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
 	"github.com/haierkeys/fast-note-sync-service/internal/routers/api_router"
+	"github.com/haierkeys/fast-note-sync-service/internal/routers/graphql_router"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/limiter"
 )
 
+// readOnlyExemptPaths changing requests still served in read-only maintenance mode: the admin API that
+// runs the maintenance, signing in and out, and POST endpoints that only read
+// readOnlyExemptPaths 只读维护模式下仍然处理的修改类请求：执行维护的管理接口、登录与退出，以及仅读取数据的 POST 接口
+var readOnlyExemptPaths = []string{
+	"/api/admin/",
+	"/api/user/login",
+	"/api/auth/logout",
+	"/api/auth/logout_all",
+	"/api/graphql",
+	"/api/note/lint",
+	"/api/note/render",
+	"/api/notes/query-frontmatter",
+	"/api/storage/validate",
+	"/api/git-sync/validate",
+	"/api/alert/channel/test",
+	"/api/backup/verify",
+}
+
 func registerAPIRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.WebsocketServer, uni *ut.UniversalTranslator) {
 	cfg := appContainer.Config()
 	api := r.Group("/api")
@@ -31,37 +51,76 @@ func registerAPIRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.Websock
 		api.Use(middleware.LangWithTranslator(uni))
 		api.Use(middleware.AccessLogWithLogger(appContainer.Logger()))
 		api.Use(middleware.RecoveryWithLogger(appContainer.Logger()))
+		api.Use(middleware.ReadOnly(appContainer.ReadOnly(), readOnlyExemptPaths...))
+
+		// Per-uid (per-IP before auth) token bucket limiter
+		// 按用户（认证前按 IP）的令牌桶限流器
+		var apiLimiter *limiter.KeyedLimiter
+		if cfg.RateLimit.Enabled {
+			apiLimiter = limiter.NewKeyedLimiter(cfg.RateLimit.APIRate, cfg.RateLimit.APIBurst)
+		}
+		userRateLimiter := middleware.UserRateLimiter(apiLimiter, appContainer.Logger())
 
 		// Create Handlers (injected App Container)
 		// 创建 Handlers（注入 App Container）
 		userHandler := api_router.NewUserHandler(appContainer)
 		vaultHandler := api_router.NewVaultHandler(appContainer)
 		noteHandler := api_router.NewNoteHandler(appContainer, wss)
-		folderHandler := api_router.NewFolderHandler(appContainer)
+		folderHandler := api_router.NewFolderHandler(appContainer, wss)
 		fileHandler := api_router.NewFileHandler(appContainer, wss)
 		noteHistoryHandler := api_router.NewNoteHistoryHandler(appContainer, wss)
 		versionHandler := api_router.NewVersionHandler(appContainer)
+		metaHandler := api_router.NewMetaHandler(appContainer)
 		adminControlHandler := api_router.NewAdminControlHandler(appContainer, wss)
+		adminSnapshotHandler := api_router.NewAdminSnapshotHandler(appContainer)
+		adminMaintenanceHandler := api_router.NewAdminMaintenanceHandler(appContainer)
+		adminAuditHandler := api_router.NewAdminAuditHandler(appContainer)
+		adminLogHandler := api_router.NewAdminLogHandler(appContainer)
+		adminJobHandler := api_router.NewAdminJobHandler(appContainer)
+		adminScheduleHandler := api_router.NewAdminScheduleHandler(appContainer)
+		adminReindexHandler := api_router.NewAdminReindexHandler(appContainer)
+		adminFileGCHandler := api_router.NewAdminFileGCHandler(appContainer)
+		adminRegistrationHandler := api_router.NewAdminRegistrationHandler(appContainer)
+		adminVaultTransferHandler := api_router.NewAdminVaultTransferHandler(appContainer)
 		shareHandler := api_router.NewShareHandler(appContainer, wss)
 		storageHandler := api_router.NewStorageHandler(appContainer)
 		backupHandler := api_router.NewBackupHandler(appContainer)
 		gitSyncHandler := api_router.NewGitSyncHandler(appContainer)
 		settingHandler := api_router.NewSettingHandler(appContainer, wss)
+		userSettingHandler := api_router.NewUserSettingHandler(appContainer, wss)
 		syncLogHandler := api_router.NewSyncLogHandler(appContainer)
+		noteAccessHandler := api_router.NewNoteAccessHandler(appContainer)
+		noteMetaHandler := api_router.NewNoteMetaHandler(appContainer)
+		noteFrontmatterHandler := api_router.NewNoteFrontmatterHandler(appContainer)
+		noteLintHandler := api_router.NewNoteLintHandler(appContainer)
+		noteRenderHandler := api_router.NewNoteRenderHandler(appContainer)
+		noteCalendarHandler := api_router.NewNoteCalendarHandler(appContainer)
+		webhookHandler := api_router.NewWebhookHandler(appContainer)
+		notePolicyHandler := api_router.NewNotePolicyHandler(appContainer)
+		notePublishHandler := api_router.NewNotePublishHandler(appContainer)
+		templateHandler := api_router.NewTemplateHandler(appContainer)
+		alertHandler := api_router.NewAlertHandler(appContainer)
 		tokenHandler := api_router.NewTokenHandler(appContainer)
 		stytchOAuthHandler := api_router.NewStytchOAuthHandler(appContainer)
 		oidcHandler := api_router.NewOIDCHandler(appContainer)
+		twoFactorHandler := api_router.NewTwoFactorHandler(appContainer)
+		deviceHandler := api_router.NewDeviceHandler(appContainer)
+		graphqlHandler := graphql_router.NewGraphQLHandler(appContainer)
 
 		// No-auth WebGUI restricted routes
 		// 免认证但仅限 WebGUI 访问的路由组
 		noAuthWebgui := api.Group("")
 		noAuthWebgui.Use(middleware.RequireWebGUI())
+		noAuthWebgui.Use(userRateLimiter)
 		{
 			noAuthWebgui.POST("/user/register", userHandler.Register)
 			noAuthWebgui.POST("/user/login", userHandler.Login)
+			noAuthWebgui.POST("/user/password/forgot", userHandler.PasswordResetSend)
+			noAuthWebgui.POST("/user/password/reset", userHandler.PasswordReset)
 			noAuthWebgui.GET("/user/auth/oidc/config", oidcHandler.Config)
 			noAuthWebgui.GET("/webgui/config", adminControlHandler.Config)
 		}
+		api.GET("/user/password/reset", userRateLimiter, userHandler.PasswordResetPage)
 		api.GET("/user/auth/oidc/start", oidcHandler.Start)
 		api.GET("/user/auth/oidc/start/:providerID", oidcHandler.Start)
 		for _, route := range oidcCallbackRoutes(cfg.OIDC) {
@@ -72,8 +131,13 @@ func registerAPIRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.Websock
 		// Add server version interface (no auth required)
 		// 添加服务端版本号接口（无需认证）
 		api.GET("/version", versionHandler.ServerVersion)
+		api.GET("/changelog", versionHandler.Changelog)
 		api.GET("/support", versionHandler.Support)
 
+		// Error catalog interface (no auth required)
+		// 错误目录接口（无需认证）
+		api.GET("/meta/errors", metaHandler.Errors)
+
 		// Health check interface (no auth required)
 		// 健康检查接口（无需认证）
 		healthHandler := api_router.NewHealthHandler(appContainer)
@@ -82,22 +146,26 @@ func registerAPIRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.Websock
 		// Share routing group (controlled read-only access)
 		// 分享路由组 (受控的只读访问)
 		share := api.Group("/share")
+		share.Use(userRateLimiter)
 		share.Use(middleware.ShareAuthToken(appContainer.ShareService))
 		{
 			share.GET("/note", shareHandler.NoteGet) // Get shared note
 			// 获取分享的笔记
 			share.GET("/file", shareHandler.FileGet) // Get shared file content
 			// 获取分享的文件内容
+			share.HEAD("/file", shareHandler.FileGet)
 		}
 
 		// Auth routing group (authentication required)
 		// 需要认证的路由组
 		auth := api.Group("/")
 		auth.Use(middleware.UserAuthTokenWithConfig(cfg.Security.AuthTokenKey, appContainer.TokenService))
+		auth.Use(userRateLimiter)
 		{
 			// Create share
 			// 创建分享
 			auth.POST("/auth/logout", userHandler.Logout)
+			auth.POST("/auth/logout_all", userHandler.LogoutAll)
 			auth.POST("/share", shareHandler.Create)
 			auth.POST("/share/password", shareHandler.UpdatePassword)
 			auth.GET("/share", shareHandler.Query)
@@ -111,12 +179,26 @@ func registerAPIRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.Websock
 			auth.GET("/admin/check", adminControlHandler.CheckAdmin)
 			auth.GET("/admin/ws_clients", adminControlHandler.GetWSClients)
 			auth.DELETE("/admin/ws_client/:traceId", adminControlHandler.KickWSClient)
+			auth.GET("/admin/ip_bans", adminControlHandler.GetIPBans)
+			auth.DELETE("/admin/ip_ban/:ip", adminControlHandler.UnbanIP)
 
 			// Version source latency probe (auth required: triggers real outbound requests)
 			// 版本源延迟探测（需认证：会触发真实的外部网络请求）
 			auth.GET("/version/probe", versionHandler.ProbeSources)
 
 			auth.GET("/user/info", userHandler.UserInfo)
+			auth.GET("/user/capabilities", userHandler.Capabilities)
+			auth.GET("/user/data-inventory", userHandler.DataInventory)
+			auth.GET("/user/usage", userHandler.Usage)
+			auth.GET("/user/heatmap", userHandler.Heatmap)
+
+			// Per-user settings synced across devices
+			// 跨设备同步的用户级设置
+			auth.GET("/user/setting", userSettingHandler.Get)
+			auth.POST("/user/setting", userSettingHandler.Put)
+			auth.DELETE("/user/setting", userSettingHandler.Delete)
+			auth.GET("/user/setting/sync", userSettingHandler.Sync)
+
 			auth.POST("/oauth/stytch/authorize/start", stytchOAuthHandler.AuthorizeStart)
 			auth.POST("/oauth/stytch/authorize/submit", stytchOAuthHandler.AuthorizeSubmit)
 
@@ -125,13 +207,30 @@ func registerAPIRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.Websock
 			auth.DELETE("/note", noteHandler.Delete)
 			auth.PUT("/note/restore", noteHandler.Restore)
 			auth.POST("/note/rename", noteHandler.Rename)
+			auth.POST("/note/duplicate", noteHandler.Duplicate)
+			auth.POST("/note/lint", noteLintHandler.Lint)
+			auth.GET("/note/render", noteRenderHandler.Render)
+			auth.POST("/note/render", noteRenderHandler.Render)
 			auth.GET("/notes", noteHandler.List)
+			auth.POST("/notes/query-frontmatter", noteFrontmatterHandler.Query)
+			auth.GET("/notes/properties", noteFrontmatterHandler.Properties)
+			auth.GET("/note/calendar.ics", noteCalendarHandler.Feed)
 			auth.DELETE("/note/recycle-clear", noteHandler.RecycleClear)
 			auth.GET("/notes/share-paths", shareHandler.NoteSharePaths)
 
+			// Note template routes
+			// 笔记模板路由
+			auth.GET("/templates", templateHandler.List)
+			auth.POST("/templates", templateHandler.Create)
+			auth.GET("/templates/:id", templateHandler.Get)
+			auth.PUT("/templates/:id", templateHandler.Update)
+			auth.DELETE("/templates/:id", templateHandler.Delete)
+
 			auth.GET("/folder", folderHandler.Get)
 			auth.POST("/folder", folderHandler.Create)
 			auth.DELETE("/folder", folderHandler.Delete)
+			auth.POST("/folder/archive", folderHandler.Archive)
+			auth.POST("/folder/move", folderHandler.Move)
 			auth.GET("/folders", folderHandler.List)
 			auth.GET("/folder/notes", folderHandler.ListNotes)
 			auth.GET("/folder/files", folderHandler.ListFiles)
@@ -141,16 +240,41 @@ func registerAPIRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.Websock
 			auth.PATCH("/note/frontmatter", noteHandler.PatchFrontmatter)
 			auth.POST("/note/append", noteHandler.Append)
 			auth.POST("/note/prepend", noteHandler.Prepend)
+			auth.POST("/note/append-to-heading", noteHandler.AppendToHeading)
+			auth.POST("/note/daily", noteHandler.Daily)
+			auth.GET("/note/daily/setting", noteHandler.GetDailySetting)
+			auth.POST("/note/daily/setting", noteHandler.UpdateDailySetting)
 			auth.POST("/note/replace", noteHandler.Replace)
 
+			// Note metadata operations
+			auth.GET("/note/meta", noteMetaHandler.Get)
+			auth.POST("/note/meta", noteMetaHandler.Set)
+			auth.DELETE("/note/meta", noteMetaHandler.Delete)
+
 			// Note link operations
 			auth.GET("/note/backlinks", noteHandler.GetBacklinks)
 			auth.GET("/note/outlinks", noteHandler.GetOutlinks)
+			auth.GET("/note/unresolved-links", noteHandler.ListUnresolvedLinks)
+			auth.POST("/note/unresolved-links/create", noteHandler.CreateUnresolvedStubs)
+			auth.GET("/vault/graph", vaultHandler.Graph)
+
+			// GraphQL read-only query endpoint
+			// GraphQL 只读查询接口
+			auth.GET("/graphql", graphqlHandler.Handle)
+			auth.POST("/graphql", graphqlHandler.Handle)
+			auth.GET("/graphql/schema", graphqlHandler.Schema)
 
 			auth.GET("/file", fileHandler.GetInfo)
+			auth.HEAD("/file", fileHandler.GetInfo)
 			auth.POST("/file", fileHandler.Upload)
 			auth.OPTIONS("/file", func(c *gin.Context) { c.Status(http.StatusNoContent) })
 			auth.GET("/file/info", fileHandler.Get)
+			auth.GET("/file/thumbnail", fileHandler.Thumbnail)
+			auth.POST("/file/upload/init", fileHandler.UploadInit)
+			auth.PUT("/file/upload/chunk", fileHandler.UploadChunk)
+			auth.GET("/file/upload/status", fileHandler.UploadStatus)
+			auth.POST("/file/upload/complete", fileHandler.UploadComplete)
+			auth.DELETE("/file/upload", fileHandler.UploadAbort)
 			auth.OPTIONS("/file/info", func(c *gin.Context) { c.Status(http.StatusNoContent) })
 			auth.DELETE("/file", fileHandler.Delete)
 			auth.PUT("/file/restore", fileHandler.Restore)
@@ -177,6 +301,20 @@ func registerAPIRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.Websock
 				// User management routes
 				// 用户管理接口
 				webguiGroup.POST("/user/change_password", userHandler.UserChangePassword)
+				webguiGroup.GET("/user/settings", userHandler.Settings)
+				webguiGroup.POST("/user/settings", userHandler.UpdateSettings)
+
+				// Two-factor authentication routes
+				// 两步验证接口
+				webguiGroup.GET("/user/totp", twoFactorHandler.Status)
+				webguiGroup.POST("/user/totp/setup", twoFactorHandler.Setup)
+				webguiGroup.POST("/user/totp/enable", twoFactorHandler.Enable)
+				webguiGroup.POST("/user/totp/disable", twoFactorHandler.Disable)
+
+				// Device management routes
+				// 设备管理接口
+				webguiGroup.GET("/user/devices", deviceHandler.List)
+				webguiGroup.DELETE("/user/device", deviceHandler.Revoke)
 
 				// Vault management routes
 				// 笔记库管理接口
@@ -185,6 +323,19 @@ func registerAPIRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.Websock
 				webguiGroup.DELETE("/vault", vaultHandler.Delete)
 				webguiGroup.POST("/vault/rebuild-index", vaultHandler.RebuildIndex)
 				webguiGroup.POST("/vault/force-delete-item", vaultHandler.ForceDeleteDataItem)
+				webguiGroup.GET("/vault/trash", vaultHandler.Trash)
+				webguiGroup.GET("/vault/sync-status", vaultHandler.SyncStatus)
+				webguiGroup.GET("/vault/retention", vaultHandler.Retention)
+				webguiGroup.POST("/vault/retention", vaultHandler.UpdateRetention)
+				webguiGroup.POST("/vault/trash/empty", vaultHandler.EmptyTrash)
+				webguiGroup.GET("/vault/as-of", vaultHandler.AsOf)
+				webguiGroup.GET("/vault/as-of/note", vaultHandler.AsOfNote)
+				webguiGroup.GET("/vault/activity", vaultHandler.Activity)
+				webguiGroup.GET("/vault/stats", vaultHandler.Stats)
+				webguiGroup.POST("/vault/import", vaultHandler.Import)
+				webguiGroup.POST("/vault/import/local", vaultHandler.ImportLocal)
+				webguiGroup.GET("/vault/export", vaultHandler.Export)
+				webguiGroup.POST("/vault/site", vaultHandler.ExportSite)
 
 				// Admin config interface
 				// 管理员配置接口
@@ -195,16 +346,66 @@ func registerAPIRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.Websock
 				webguiGroup.POST("/admin/config/user_database/test", adminControlHandler.ValidateUserDatabaseConfig)
 				webguiGroup.GET("/admin/config/cloudflare", adminControlHandler.GetCloudflareConfig)
 				webguiGroup.POST("/admin/config/cloudflare", adminControlHandler.UpdateCloudflareConfig)
+				webguiGroup.GET("/admin/config/branding", adminControlHandler.GetBrandingConfig)
+				webguiGroup.POST("/admin/config/branding", adminControlHandler.UpdateBrandingConfig)
+				webguiGroup.POST("/admin/config/branding/logo", adminControlHandler.UploadBrandingLogo)
 				webguiGroup.GET("/admin/systeminfo", adminControlHandler.GetSystemInfo)
+				webguiGroup.GET("/admin/stats", adminControlHandler.GetStats)
+				webguiGroup.GET("/admin/storage-usage", adminControlHandler.GetStorageUsage)
 				webguiGroup.GET("/admin/restart", adminControlHandler.Restart)
 				webguiGroup.GET("/admin/gc", adminControlHandler.GC)
 				webguiGroup.GET("/admin/cloudflared_tunnel_download", adminControlHandler.CloudflaredTunnelDownload)
 
+				// Local snapshots
+				webguiGroup.GET("/admin/snapshots", adminSnapshotHandler.List)
+				webguiGroup.POST("/admin/snapshots", adminSnapshotHandler.Create)
+				webguiGroup.POST("/admin/snapshots/restore", adminSnapshotHandler.Restore)
+				webguiGroup.GET("/admin/db-export", adminSnapshotHandler.DatabaseExport)
+				webguiGroup.POST("/admin/db-export", adminSnapshotHandler.ExportDatabases)
+
+				// Audit log
+				webguiGroup.GET("/admin/audit", adminAuditHandler.List)
+				webguiGroup.GET("/admin/logs", adminLogHandler.Query)
+				webguiGroup.GET("/admin/sync-trace", adminLogHandler.SyncTraces)
+				webguiGroup.POST("/admin/sync-trace", adminLogHandler.StartSyncTrace)
+				webguiGroup.DELETE("/admin/sync-trace", adminLogHandler.StopSyncTrace)
+				webguiGroup.GET("/admin/jobs", adminJobHandler.List)
+				webguiGroup.POST("/admin/jobs/requeue", adminJobHandler.Requeue)
+				webguiGroup.GET("/admin/schedules", adminScheduleHandler.List)
+				webguiGroup.PUT("/admin/schedules", adminScheduleHandler.Update)
+				webguiGroup.POST("/admin/schedules/run", adminScheduleHandler.Run)
+
+				// Maintenance window
+				webguiGroup.GET("/admin/maintenance", adminMaintenanceHandler.Status)
+				webguiGroup.POST("/admin/maintenance/run", adminMaintenanceHandler.Run)
+				webguiGroup.GET("/admin/read-only", adminMaintenanceHandler.ReadOnlyStatus)
+				webguiGroup.POST("/admin/read-only", adminMaintenanceHandler.SetReadOnly)
+
+				// Full-text reindex jobs
+				webguiGroup.GET("/admin/reindex", adminReindexHandler.List)
+				webguiGroup.POST("/admin/reindex", adminReindexHandler.Start)
+				webguiGroup.POST("/admin/reindex/cancel", adminReindexHandler.Cancel)
+
+				// Attachment garbage collection
+				webguiGroup.GET("/admin/file-gc", adminFileGCHandler.DryRun)
+
+				// Vault re-ownership
+				webguiGroup.POST("/admin/vault/transfer", adminVaultTransferHandler.Transfer)
+
 				// Admin user managment
 				webguiGroup.GET("/admin/users/list", adminControlHandler.GetUsers)
 				webguiGroup.POST("/admin/users/create", adminControlHandler.CreateUser)
 				webguiGroup.POST("/admin/users/update", adminControlHandler.UpdateUser)
 
+				// Registration invitations and approval queue
+				// 注册邀请码与审核队列
+				webguiGroup.GET("/admin/users/invites", adminRegistrationHandler.ListInvites)
+				webguiGroup.POST("/admin/users/invites", adminRegistrationHandler.CreateInvite)
+				webguiGroup.DELETE("/admin/users/invites", adminRegistrationHandler.DeleteInvite)
+				webguiGroup.GET("/admin/users/pending", adminRegistrationHandler.ListPending)
+				webguiGroup.POST("/admin/users/pending/approve", adminRegistrationHandler.ApprovePending)
+				webguiGroup.POST("/admin/users/pending/reject", adminRegistrationHandler.RejectPending)
+
 				// Storage management routes
 				// 存储配置接口
 				webguiGroup.GET("/storage", storageHandler.List)
@@ -220,6 +421,7 @@ func registerAPIRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.Websock
 				webguiGroup.DELETE("/backup/config", backupHandler.DeleteConfig)
 				webguiGroup.GET("/backup/historys", backupHandler.ListHistory)
 				webguiGroup.POST("/backup/execute", backupHandler.Execute)
+				webguiGroup.POST("/backup/verify", backupHandler.Verify)
 
 				// Git sync routes
 				// Git 同步接口
@@ -235,6 +437,39 @@ func registerAPIRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.Websock
 				// 同步日志路由
 				webguiGroup.GET("/sync-logs", syncLogHandler.List)
 
+				// Note access log routes
+				// 笔记访问日志路由
+				webguiGroup.GET("/note/access-log", noteAccessHandler.GetSetting)
+				webguiGroup.POST("/note/access-log", noteAccessHandler.UpdateSetting)
+				webguiGroup.GET("/note/access-logs", noteAccessHandler.List)
+
+				// Webhook notification routes
+				// Webhook 通知路由
+				webguiGroup.GET("/webhook/configs", webhookHandler.GetConfigs)
+				webguiGroup.POST("/webhook/config", webhookHandler.UpdateConfig)
+				webguiGroup.DELETE("/webhook/config", webhookHandler.DeleteConfig)
+				webguiGroup.GET("/webhook/deliveries", webhookHandler.ListDeliveries)
+
+				// Note lifecycle policy routes
+				// 笔记生命周期策略路由
+				webguiGroup.GET("/note-policies", notePolicyHandler.GetPolicies)
+				webguiGroup.POST("/note-policy", notePolicyHandler.UpdatePolicy)
+				webguiGroup.DELETE("/note-policy", notePolicyHandler.DeletePolicy)
+				webguiGroup.GET("/note-policy/preview", notePolicyHandler.Preview)
+				webguiGroup.POST("/note-policy/execute", notePolicyHandler.Execute)
+				webguiGroup.GET("/note-policy/runs", notePolicyHandler.ListRuns)
+
+				// Scheduled note publishing routes
+				// 笔记定时发布路由
+				webguiGroup.GET("/note-publish", notePublishHandler.List)
+
+				// Backup failure alert channel routes
+				// 备份失败告警渠道路由
+				webguiGroup.GET("/alert/channels", alertHandler.GetChannels)
+				webguiGroup.POST("/alert/channel", alertHandler.UpdateChannel)
+				webguiGroup.DELETE("/alert/channel", alertHandler.DeleteChannel)
+				webguiGroup.POST("/alert/channel/test", alertHandler.TestChannel)
+
 				// Token management routes
 				// 令牌管理路由
 				webguiGroup.GET("/tokens", tokenHandler.List)
@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	"github.com/haierkeys/fast-note-sync-service/internal/routers/dav_router"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+)
+
+func registerDAVRoutes(r *gin.Engine, appContainer *app.App, wss *pkgapp.WebsocketServer) {
+	cfg := appContainer.Config()
+	if cfg.WebDAV.Enabled != nil && !*cfg.WebDAV.Enabled {
+		return
+	}
+
+	// WebDAV routes (No Timeout), uploads are streamed to a temp file
+	// WebDAV 路由 (无超时限制)，上传内容流式写入临时文件
+	davHandler := dav_router.NewDavHandler(appContainer, wss)
+	davGroup := r.Group(middleware.DAVPrefix)
+	davGroup.Use(middleware.DAVAuthWithConfig(cfg.Security.AuthTokenKey, cfg.WebDAV.Realm, appContainer.TokenService))
+	davGroup.Use(middleware.DAVReadOnly(appContainer.ReadOnly()))
+	{
+		davGroup.Match(dav_router.Methods, "", davHandler.Handle)
+		davGroup.Match(dav_router.Methods, "/*path", davHandler.Handle)
+	}
+}
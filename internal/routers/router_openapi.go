@@ -6,22 +6,47 @@ import (
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/haierkeys/fast-note-sync-service/docs"
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func registerOpenAPIRoutes(r *gin.Engine, frontendFiles embed.FS) {
+// openAPIPath path of the OpenAPI 3 spec generated by `make docs`
+// openAPIPath 由 `make docs` 生成的 OpenAPI 3 规范的路径
+const openAPIPath = "/api/openapi.json"
+
+func registerOpenAPIRoutes(r *gin.Engine, frontendFiles embed.FS, appContainer *app.App) {
+	// OpenAPI 3 spec, always public so integrations can generate clients against a release build
+	// OpenAPI 3 规范，始终公开，便于集成方基于正式版本生成客户端
+	openAPIJSON, _ := frontendFiles.ReadFile("docs/openapi.json")
+	r.GET(openAPIPath, func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", openAPIJSON)
+	})
+
 	// Swagger UI (outside auth group to ensure public access)
+	// In release mode it is served only while server.swagger-ui is on, checked per request so the admin toggle applies without a restart
 	// Swagger UI (放在 auth 组外，确保可以公开访问)
+	// release 模式下仅在开启 server.swagger-ui 时提供，每次请求时检查，使管理后台开关无需重启即可生效
+	swaggerHandler := ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL(openAPIPath), ginSwagger.PersistAuthorization(true))
+	noFound := middleware.NoFound()
 	r.GET("/docs/*any", func(c *gin.Context) {
+		if gin.Mode() == gin.ReleaseMode && !appContainer.Config().Server.SwaggerUI {
+			noFound(c)
+			return
+		}
 		p := c.Param("any")
 		if p == "" || p == "/" {
 			c.Redirect(http.StatusMovedPermanently, "/docs/index.html")
 			return
 		}
-		ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.PersistAuthorization(true))(c)
+		swaggerHandler(c)
 	})
 
+	if gin.Mode() == gin.ReleaseMode {
+		return
+	}
+
 	// Read debug page from embedded FS
 	debugPageContent, _ := frontendFiles.ReadFile("docs/test_ws_debug.html")
 	r.GET("/ws_debug", func(c *gin.Context) {
@@ -29,12 +54,11 @@ func registerOpenAPIRoutes(r *gin.Engine, frontendFiles embed.FS) {
 	})
 
 	// Read swagger files from embedded FS
-	swaggerJSON, _ := frontendFiles.ReadFile("docs/swagger.yaml")
 	r.GET("/openapi/", func(c *gin.Context) {
 		c.Redirect(http.StatusMovedPermanently, "/openapi.json")
 	})
 	r.GET("/openapi.json", func(c *gin.Context) {
-		c.Data(http.StatusOK, "application/json; charset=utf-8", swaggerJSON)
+		c.Data(http.StatusOK, "application/json; charset=utf-8", openAPIJSON)
 	})
 	swaggerYAML, _ := frontendFiles.ReadFile("docs/swagger.yaml")
 	r.GET("/openapi.yaml", func(c *gin.Context) {
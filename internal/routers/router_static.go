@@ -17,6 +17,9 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	"github.com/haierkeys/fast-note-sync-service/internal/routers/api_router"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/branding"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 )
 
@@ -41,6 +44,13 @@ func registerStaticFiles(r *gin.Engine, frontendFiles embed.FS, appContainer *ap
 	r.Group("/static", cacheMiddleware, middleware.StaticCompressMiddleware(frontendFiles)).StaticFS("/", http.FS(frontendStatic))
 	r.Group("/user_static", cacheMiddleware).Static("/", userStaticPath)
 
+	// Branding stylesheet, rendered on every request so admin changes apply without a restart
+	// 品牌样式表，每次请求时生成，管理员修改后无需重启即可生效
+	r.GET(branding.CSSPath, func(c *gin.Context) {
+		c.Header("Cache-Control", "no-cache")
+		c.Data(http.StatusOK, "text/css; charset=utf-8", []byte(branding.Branding(cfg.WebGUI.Branding).CSS()))
+	})
+
 	if *cfg.Storage.LocalFS.HttpfsIsEnable && cfg.Storage.LocalFS.IsEnabled {
 		r.StaticFS(cfg.Storage.LocalFS.SavePath, http.Dir(cfg.Storage.LocalFS.SavePath))
 		r.OPTIONS(cfg.Storage.LocalFS.SavePath+"/*filepath", func(c *gin.Context) {
@@ -143,24 +153,11 @@ func registerStaticFiles(r *gin.Engine, frontendFiles embed.FS, appContainer *ap
 			return
 		}
 
-		file, err := os.Open(savePath)
-		if err != nil {
+		// 设置强缓存，缓存一年，支持 Range 请求
+		// Set strong cache for one year, Range requests are supported
+		if err := pkgapp.ServeFile(c, savePath, fileName, contentType, etag, time.UnixMilli(mtime), pkgapp.AttachmentCacheControl); err != nil {
 			c.AbortWithStatus(http.StatusNotFound)
-			return
-		}
-		defer file.Close()
-
-		if contentType != "" {
-			c.Header("Content-Type", contentType)
 		}
-		// 设置强缓存，缓存一年
-		// Set strong cache for one year
-		c.Header("Cache-Control", "public, s-maxage=31536000, max-age=31536000, must-revalidate")
-		if etag != "" {
-			c.Header("ETag", etag)
-		}
-
-		http.ServeContent(c.Writer, c.Request, fileName, time.UnixMilli(mtime), file)
 	})
 }
 
@@ -174,7 +171,7 @@ func registerWebGuiRoutes(r *gin.Engine, frontendFiles embed.FS, appContainer *a
 	})
 
 	r.GET("/webgui/", func(c *gin.Context) {
-		renderHTMLWithAPI(c, frontendIndexContent, apiUrl)
+		renderHTMLWithAPI(c, frontendIndexContent, apiUrl, branding.Branding(cfg.WebGUI.Branding))
 	})
 }
 
@@ -188,7 +185,7 @@ func registerOAuthAuthorizePageRoutes(r *gin.Engine, frontendFiles embed.FS, app
 			c.Status(http.StatusNotFound)
 			return
 		}
-		renderHTMLWithAPI(c, frontendOAuthAuthorizeContent, apiUrl)
+		renderHTMLWithAPI(c, frontendOAuthAuthorizeContent, apiUrl, branding.Branding(cfg.WebGUI.Branding))
 	})
 }
 
@@ -198,13 +195,18 @@ func registerShareRoutes(r *gin.Engine, frontendFiles embed.FS, appContainer *ap
 	apiUrl := cfg.Server.ExtApiUrl
 
 	r.GET("/share/:side/:token", func(c *gin.Context) {
-		renderHTMLWithAPI(c, frontendShareContent, apiUrl)
+		renderHTMLWithAPI(c, frontendShareContent, apiUrl, branding.Branding(cfg.WebGUI.Branding))
 	})
+
+	// Atom feed of a shared folder, the slug is the share token followed by ".xml"
+	// 分享文件夹的 Atom 订阅源，slug 为分享 Token 加上 ".xml"
+	shareHandler := api_router.NewShareHandler(appContainer, nil)
+	r.GET("/feed/:slug", shareHandler.Feed)
 }
 
-// renderHTMLWithAPI injects API_URL into HTML
-// renderHTMLWithAPI 将 API_URL 注入到 HTML 中
-func renderHTMLWithAPI(c *gin.Context, content []byte, apiUrl string) {
+// renderHTMLWithAPI injects API_URL and the instance branding into HTML
+// renderHTMLWithAPI 将 API_URL 与实例品牌设置注入到 HTML 中
+func renderHTMLWithAPI(c *gin.Context, content []byte, apiUrl string, brand branding.Branding) {
 	var script string
 	if apiUrl == "" {
 		script = "<script>localStorage.removeItem('API_URL');</script>"
@@ -217,7 +219,7 @@ func renderHTMLWithAPI(c *gin.Context, content []byte, apiUrl string) {
 		script = fmt.Sprintf("<script>localStorage.setItem('API_URL', %s);</script>", safeUrl)
 	}
 
-	html := string(content)
+	html := brand.Apply(string(content))
 	if strings.Contains(html, "</body>") {
 		html = strings.Replace(html, "</body>", script+"</body>", 1)
 	} else {
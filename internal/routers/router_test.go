@@ -0,0 +1,56 @@
+package routers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/haierkeys/fast-note-sync-service/internal/middleware"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/ipfilter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestNewEngine_IgnoresSpoofedForwardedFor verifies X-Forwarded-For is only honoured from trusted proxies,
+// so a direct client cannot get another IP banned by the honeypot or dodge its own ban.
+// TestNewEngine_IgnoresSpoofedForwardedFor 验证只有可信代理的 X-Forwarded-For 会被采用，
+// 直连客户端无法借诱饵端点封禁他人 IP，也无法绕过对自身的封禁。
+func TestNewEngine_IgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	filter, err := ipfilter.New(nil)
+	require.NoError(t, err)
+	r := newEngine(nil, zap.NewNop())
+	r.Use(middleware.IPFilter(filter))
+	r.Use(middleware.Honeypot(filter, []string{"/wp-admin"}, time.Hour, zap.NewNop()))
+	r.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+	get := func(path, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, "203.0.113.7", get("/ip", "203.0.113.7:4000", "198.51.100.1").Body.String(), "untrusted peer")
+	assert.Equal(t, "198.51.100.1", get("/ip", "127.0.0.1:4000", "198.51.100.1").Body.String(), "trusted proxy")
+
+	// The honeypot bans the real peer, not the address it claims to forward for
+	get("/wp-admin", "203.0.113.7:4000", "198.51.100.2")
+	assert.True(t, filter.IsBlocked("203.0.113.7"))
+	assert.False(t, filter.IsBlocked("198.51.100.2"))
+
+	var res pkgapp.Res
+	require.NoError(t, json.Unmarshal(get("/ip", "203.0.113.7:4000", "198.51.100.3").Body.Bytes(), &res))
+	assert.Equal(t, code.ErrorIPBlocked.Code(), res.Code, "spoofed header does not lift the ban")
+}
@@ -3,12 +3,15 @@ package routers
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/routers/websocket_router"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"go.uber.org/zap"
 )
 
 func initWebSocketRoutes(wss *pkgapp.WebsocketServer, appContainer *app.App) {
@@ -22,6 +25,7 @@ func initWebSocketRoutes(wss *pkgapp.WebsocketServer, appContainer *app.App) {
 	folderWSHandler := websocket_router.NewFolderWSHandler(appContainer)
 	fileWSHandler := websocket_router.NewFileWSHandler(appContainer)
 	settingWSHandler := websocket_router.NewSettingWSHandler(appContainer)
+	userSettingWSHandler := websocket_router.NewUserSettingWSHandler(appContainer)
 
 	// Note
 	wss.Use(websocket_router.NoteReceiveModify, noteWSHandler.NoteModify)
@@ -48,6 +52,9 @@ func initWebSocketRoutes(wss *pkgapp.WebsocketServer, appContainer *app.App) {
 	wss.Use(websocket_router.SettingReceiveRePush, settingWSHandler.SettingRePush)
 	wss.Use(websocket_router.SettingSyncPageAck, settingWSHandler.SettingSyncPageAck)
 
+	// User settings
+	wss.Use(websocket_router.UserSettingsReceiveSync, userSettingWSHandler.SettingsSync)
+
 	// Attachment
 	wss.Use(websocket_router.FileReceiveSync, fileWSHandler.FileSync)
 	wss.Use(websocket_router.FileReceiveUploadCheck, fileWSHandler.FileUploadCheck)
@@ -60,12 +67,44 @@ func initWebSocketRoutes(wss *pkgapp.WebsocketServer, appContainer *app.App) {
 	// Attachment chunk upload
 	wss.UseBinary(websocket_router.VaultFileMsgType, fileWSHandler.FileUploadChunkBinary)
 
+	// Resumable sync cursor
+	// 可恢复同步游标
+	wss.UseSyncResume(websocket_router.SyncResumeActions...)
+
 	// Inject Message Interceptor to handle unauthenticated checks, Vault restrictions, RBAC checks, and error rollbacks
 	// 注入消息拦截器，处理未登录验证、Vault笔记库限制校验、RBAC权限检查以及写失败回滚机制
 	wss.UseInterceptor(websocket_router.NewMessageInterceptor(appContainer))
 
 	wss.UseUserVerify(noteWSHandler.UserInfo)
 
+	// Record the device of every authenticated connection for the device management API
+	// 为设备管理接口记录每个已认证连接所在的设备
+	wss.UseDeviceTracker(func(c *pkgapp.WebsocketClient) {
+		platform := make([]string, 0)
+		for flag, on := range c.ClientPlatform() {
+			if on {
+				platform = append(platform, flag)
+			}
+		}
+		sort.Strings(platform)
+
+		err := appContainer.DeviceService.Record(c.Context(), &domain.Device{
+			UID:           c.User.UID,
+			DeviceKey:     c.DeviceKey(),
+			TokenID:       c.TokenID,
+			ClientName:    c.ClientName(),
+			ClientType:    c.ClientType(),
+			ClientVersion: c.ClientVersion(),
+			Platform:      platform,
+			IP:            c.Ctx.ClientIP(),
+			UserAgent:     c.Ctx.GetHeader("User-Agent"),
+			LastSeenAt:    time.Now(),
+		})
+		if err != nil {
+			appContainer.Logger().Warn("WS record device failed", zap.Int64("uid", c.User.UID), zap.String("traceId", c.TraceID), zap.Error(err))
+		}
+	})
+
 	// Inject Token Verification to decouple pkg/app from internal/service
 	wss.UseTokenVerify(func(ctx context.Context, uid, tokenID int64, nonce string, reqClientType, reqClientName, reqClientVersion, reqUserAgent, reqIP string) (string, string, error) {
 		dbToken, err := appContainer.TokenService.GetActiveToken(ctx, uid, tokenID)
@@ -28,6 +28,13 @@ const (
 	// ClientInfo 客户端信息确认发送动作
 	ClientInfo WebSocketSendAction = "ClientInfo"
 
+	// SyncReceiveResume replay of the sync events missed since the device cursor
+	// SyncReceiveResume 重放设备游标之后错过的同步事件
+	SyncReceiveResume WebSocketReceiveAction = "SyncResume"
+	// SyncResumeEnd sync resume finished
+	// SyncResumeEnd 同步恢复结束
+	SyncResumeEnd WebSocketSendAction = "SyncResumeEnd"
+
 	// ---------------- Folder ----------------
 
 	// FolderReceiveSync folder synchronization request
@@ -105,6 +112,12 @@ const (
 	// SettingReceiveRePush setting missing pull request
 	// SettingReceiveRePush 配置缺失请求拉取
 	SettingReceiveRePush WebSocketReceiveAction = "SettingRePush"
+
+	// ---------------- User Settings ----------------
+
+	// UserSettingsReceiveSync per-user settings pull since a cursor, answered with UserSettingsSync
+	// UserSettingsReceiveSync 拉取游标之后的用户级设置，以 UserSettingsSync 回复
+	UserSettingsReceiveSync WebSocketReceiveAction = "SettingsSync"
 )
 
 const (
@@ -167,6 +180,9 @@ const (
 	// NoteSyncBatchAck note sync batch receive ack
 	// NoteSyncBatchAck 笔记分批同步接收确认，服务端接收到中间批次后发回客户端
 	NoteSyncBatchAck WebSocketSendAction = "NoteSyncBatchAck"
+	// NoteSecretWarning note content contains credentials
+	// NoteSecretWarning 笔记内容包含敏感凭据
+	NoteSecretWarning WebSocketSendAction = "NoteSecretWarning"
 
 	// ---------------- File ----------------
 
@@ -240,6 +256,12 @@ const (
 	// ShareSyncRefresh 通知客户端刷新分享状态
 	ShareSyncRefresh WebSocketSendAction = "ShareSyncRefresh"
 
+	// ---------------- User Settings ----------------
+
+	// UserSettingsSync changed per-user settings, the reply to SettingsSync and the push after every change
+	// UserSettingsSync 变更的用户级设置，既是 SettingsSync 的回复，也在每次修改后推送
+	UserSettingsSync WebSocketSendAction = "SettingsSync"
+
 	// ---------------- Page Sync ----------------
 
 	// NoteSyncPage note sync page message
@@ -269,4 +291,11 @@ const (
 	FolderSyncPageAck WebSocketReceiveAction = "FolderSyncPageAck"
 )
 
-
+// SyncResumeActions broadcast actions queued for SyncResume
+// SyncResumeActions 为 SyncResume 排队的广播动作
+var SyncResumeActions = []WebSocketSendAction{
+	NoteSyncModify, NoteSyncDelete, NoteSyncRename,
+	FileSyncUpdate, FileSyncDelete, FileSyncRename,
+	FolderSyncModify, FolderSyncDelete, FolderSyncRename,
+	SettingSyncModify, SettingSyncDelete, SettingSyncClear,
+}
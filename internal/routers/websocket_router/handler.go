@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"go.uber.org/zap"
@@ -87,6 +88,23 @@ func (h *WSHandler) logWarn(c *pkgapp.WebsocketClient, method string, fields ...
 	h.App.Logger().Warn(method, allFields...)
 }
 
+// audit records an audit log entry for the WebSocket client
+// audit 为 WebSocket 客户端记录一条审计日志
+func (h *WSHandler) audit(c *pkgapp.WebsocketClient, action domain.AuditAction, target, detail string) {
+	if h.App.AuditService == nil {
+		return
+	}
+	h.App.AuditService.Record(c.Context(), &domain.AuditLog{
+		UID:        c.User.UID,
+		Action:     action,
+		Target:     target,
+		Detail:     detail,
+		IP:         c.RemoteAddr(),
+		ClientType: c.ClientType(),
+		ClientName: c.ClientName(),
+	})
+}
+
 // extractMsgMeta extracts context/vault/path from raw WebSocket JSON payload for error tracing.
 // extractMsgMeta 从原始 WebSocket JSON 载荷中提取 context/vault/path，用于错误定位。
 func extractMsgMeta(data []byte) (msgCtx, vault, path string) {
@@ -102,6 +120,44 @@ func extractMsgMeta(data []byte) (msgCtx, vault, path string) {
 	return meta.Context, meta.Vault, meta.Path
 }
 
+// syncFailureKinds resource type and sync action recorded for a failed WebSocket request
+// syncFailureKinds WebSocket 请求失败时记录的资源类型与同步操作
+var syncFailureKinds = map[string]struct {
+	logType domain.SyncLogType
+	action  domain.SyncLogAction
+}{
+	string(NoteReceiveSync):        {domain.SyncLogTypeNote, domain.SyncLogActionSync},
+	string(NoteReceiveModify):      {domain.SyncLogTypeNote, domain.SyncLogActionModify},
+	string(NoteReceiveDelete):      {domain.SyncLogTypeNote, domain.SyncLogActionSoftDelete},
+	string(NoteReceiveRename):      {domain.SyncLogTypeNote, domain.SyncLogActionRename},
+	string(FileReceiveSync):        {domain.SyncLogTypeFile, domain.SyncLogActionSync},
+	string(FileReceiveUploadCheck): {domain.SyncLogTypeFile, domain.SyncLogActionModify},
+	string(FileReceiveDelete):      {domain.SyncLogTypeFile, domain.SyncLogActionSoftDelete},
+	string(FileReceiveRename):      {domain.SyncLogTypeFile, domain.SyncLogActionRename},
+	string(SettingReceiveSync):     {domain.SyncLogTypeSetting, domain.SyncLogActionSync},
+	string(SettingReceiveModify):   {domain.SyncLogTypeSetting, domain.SyncLogActionModify},
+	string(SettingReceiveDelete):   {domain.SyncLogTypeSetting, domain.SyncLogActionSoftDelete},
+	string(FolderReceiveSync):      {domain.SyncLogTypeFolder, domain.SyncLogActionSync},
+	string(FolderReceiveModify):    {domain.SyncLogTypeFolder, domain.SyncLogActionModify},
+	string(FolderReceiveDelete):    {domain.SyncLogTypeFolder, domain.SyncLogActionSoftDelete},
+	string(FolderReceiveRename):    {domain.SyncLogTypeFolder, domain.SyncLogActionRename},
+}
+
+// recordSyncFailure records a failed sync request in the sync logs so the sync status can report it,
+// requests of an unknown vault are left out
+// recordSyncFailure 将失败的同步请求记入同步日志以便同步状态报告；未知保险库的请求不记录
+func (h *WSHandler) recordSyncFailure(c *pkgapp.WebsocketClient, m *pkgapp.WebSocketMessage, vault, path string, err error) {
+	kind, ok := syncFailureKinds[m.Type]
+	if !ok || vault == "" || c.User == nil || h.App.SyncLogService == nil {
+		return
+	}
+	v, vErr := h.App.VaultService.GetByName(c.Context(), c.User.UID, vault)
+	if vErr != nil || v == nil {
+		return
+	}
+	h.App.SyncLogService.LogFailure(c.User.UID, v.ID, kind.logType, kind.action, path, c.ClientType(), c.ClientName(), c.ClientVersion(), err.Error())
+}
+
 // respondError unified error response method
 // Records error log and sends error response with Details to client
 // respondError 统一错误响应方法
@@ -113,6 +169,7 @@ func (h *WSHandler) respondError(c *pkgapp.WebsocketClient, codeErr *code.Code,
 	if len(msg) > 0 && msg[0] != nil {
 		m := msg[0]
 		msgCtx, vault, path := extractMsgMeta(m.Data)
+		h.recordSyncFailure(c, m, vault, path, err)
 		if cErr, ok := err.(*code.Code); ok {
 			enriched := cErr
 			if msgCtx != "" {
@@ -160,6 +217,7 @@ func (h *WSHandler) respondErrorWithData(c *pkgapp.WebsocketClient, codeErr *cod
 	if len(msg) > 0 && msg[0] != nil {
 		m := msg[0]
 		msgCtx, vault, path := extractMsgMeta(m.Data)
+		h.recordSyncFailure(c, m, vault, path, err)
 		enriched := codeErr.WithData(data)
 		if msgCtx != "" {
 			enriched = enriched.WithContext(msgCtx)
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"github.com/haierkeys/fast-note-sync-service/pkg/json"
@@ -12,9 +13,9 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-// NewMessageInterceptor 创建 WebSocket 业务消息前置拦截器，依次执行认证、Vault 和 RBAC 检查。
+// NewMessageInterceptor 创建 WebSocket 业务消息前置拦截器，依次执行认证、Vault、RBAC 和只读模式检查。
 // NewMessageInterceptor creates a WebSocket business message pre-handler interceptor
-// that sequentially enforces auth, vault access, and RBAC checks.
+// that sequentially enforces auth, vault access, RBAC and read-only mode checks.
 func NewMessageInterceptor(appContainer *app.App) func(*pkgapp.WebsocketClient, *pkgapp.WebSocketMessage) bool {
 	logger := appContainer.Logger()
 	return func(c *pkgapp.WebsocketClient, msg *pkgapp.WebSocketMessage) bool {
@@ -27,10 +28,72 @@ func NewMessageInterceptor(appContainer *app.App) func(*pkgapp.WebsocketClient,
 		if !checkRBAC(c, msg, logger) {
 			return false
 		}
+		if !checkReadOnly(c, msg, appContainer.ReadOnly().Enabled(), logger) {
+			return false
+		}
 		return true
 	}
 }
 
+// checkReadOnly 只读维护模式下拒绝写操作，以及携带客户端删除列表的同步请求；不做回滚，客户端保留本地修改，维护结束后重新同步。
+// checkReadOnly refuses write messages, and sync requests carrying deletions of the client, in read-only
+// maintenance mode. Nothing is rolled back: the client keeps its local changes and syncs them once maintenance is over.
+func checkReadOnly(c *pkgapp.WebsocketClient, msg *pkgapp.WebSocketMessage, readOnly bool, logger interface {
+	Info(string, ...zapcore.Field)
+}) bool {
+	if !readOnly {
+		return true
+	}
+	if !strings.HasSuffix(resolveRBACFunction(msg.Type), "_w") {
+		hasDeletions, err := syncHasDeletions(msg, c.UseProtobuf())
+		if err == nil && !hasDeletions {
+			return true
+		}
+	}
+	logger.Info("WS OnMessage refused in read-only mode",
+		zap.String("Type", msg.Type),
+		zap.String("uid", c.User.ID))
+	c.ToResponse(code.ErrorServerReadOnly, msg.Type+"Ack")
+	return false
+}
+
+// syncHasDeletions 判断同步请求是否携带客户端删除的条目，protobuf 连接按 protobuf 解码；无法解码时返回错误，由调用方拒绝该消息。
+// syncHasDeletions reports whether a sync request carries entries the client deleted, decoding protobuf
+// on protobuf connections. It returns an error when the request cannot be decoded, the caller refuses it then.
+func syncHasDeletions(msg *pkgapp.WebSocketMessage, protobuf bool) (bool, error) {
+	var req any
+	switch msg.Type {
+	case NoteReceiveSync:
+		req = &dto.NoteSyncRequest{}
+	case FileReceiveSync:
+		req = &dto.FileSyncRequest{}
+	case FolderReceiveSync:
+		req = &dto.FolderSyncRequest{}
+	case SettingReceiveSync:
+		req = &dto.SettingSyncRequest{}
+	default:
+		return false, nil
+	}
+	if protobuf {
+		if _, err := DeReceiveProtobufToDTO(msg.Type, msg.Data, req); err != nil {
+			return false, err
+		}
+	} else if err := json.Unmarshal(msg.Data, req); err != nil {
+		return false, err
+	}
+	switch r := req.(type) {
+	case *dto.NoteSyncRequest:
+		return len(r.DelNotes) > 0, nil
+	case *dto.FileSyncRequest:
+		return len(r.DelFiles) > 0, nil
+	case *dto.FolderSyncRequest:
+		return len(r.DelFolders) > 0, nil
+	case *dto.SettingSyncRequest:
+		return len(r.DelSettings) > 0, nil
+	}
+	return false, nil
+}
+
 // checkAuth 验证用户是否已完成身份认证。
 // checkAuth verifies that the client has a valid authenticated user session.
 func checkAuth(c *pkgapp.WebsocketClient, msg *pkgapp.WebSocketMessage, logger interface {
@@ -0,0 +1,46 @@
+package websocket_router
+
+import (
+	"testing"
+
+	v1 "github.com/haierkeys/fast-note-sync-service/internal/proto/v1"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestSyncHasDeletions verifies deletions are found in JSON and protobuf sync requests alike,
+// and that a request which cannot be decoded is reported as an error so read-only mode refuses it.
+// TestSyncHasDeletions 验证 JSON 与 protobuf 同步请求中的删除条目都能被识别，
+// 无法解码的请求返回错误，使只读模式拒绝该请求。
+func TestSyncHasDeletions(t *testing.T) {
+	hasDeletions, err := syncHasDeletions(&pkgapp.WebSocketMessage{Type: NoteReceiveSync, Data: []byte(`{"vault":"v","delNotes":[{"path":"a.md","pathHash":"h"}]}`)}, false)
+	require.NoError(t, err)
+	assert.True(t, hasDeletions)
+
+	hasDeletions, err = syncHasDeletions(&pkgapp.WebSocketMessage{Type: FileReceiveSync, Data: []byte(`{"vault":"v"}`)}, false)
+	require.NoError(t, err)
+	assert.False(t, hasDeletions)
+
+	withDeletions, err := proto.Marshal(&v1.NoteSyncRequest{Vault: "v", DelNotes: []*v1.NoteSyncDelNote{{Path: "a.md", PathHash: "h"}}})
+	require.NoError(t, err)
+	hasDeletions, err = syncHasDeletions(&pkgapp.WebSocketMessage{Type: NoteReceiveSync, Data: withDeletions}, true)
+	require.NoError(t, err)
+	assert.True(t, hasDeletions)
+
+	withoutDeletions, err := proto.Marshal(&v1.NoteSyncRequest{Vault: "v"})
+	require.NoError(t, err)
+	hasDeletions, err = syncHasDeletions(&pkgapp.WebSocketMessage{Type: NoteReceiveSync, Data: withoutDeletions}, true)
+	require.NoError(t, err)
+	assert.False(t, hasDeletions)
+
+	_, err = syncHasDeletions(&pkgapp.WebSocketMessage{Type: NoteReceiveSync, Data: []byte{0xff, 0xff}}, true)
+	assert.Error(t, err)
+	_, err = syncHasDeletions(&pkgapp.WebSocketMessage{Type: FolderReceiveSync, Data: []byte(`not json`)}, false)
+	assert.Error(t, err)
+
+	hasDeletions, err = syncHasDeletions(&pkgapp.WebSocketMessage{Type: NoteReceiveCheck, Data: []byte(`garbage`)}, false)
+	require.NoError(t, err)
+	assert.False(t, hasDeletions, "only sync requests are inspected")
+}
@@ -509,6 +509,16 @@ func DeReceiveProtobufToDTO(action WebSocketReceiveAction, data []byte, obj any)
 			dest.PageIndex = int(pbMsg.PageIndex)
 			return true, nil
 		}
+
+	// "SettingsSync" has no protobuf message, its payload is JSON for every client
+	// "SettingsSync" 没有对应的 Protobuf 消息，所有客户端的负载均为 JSON
+	case UserSettingsReceiveSync:
+		if dest, ok := obj.(*dto.UserSettingListRequest); ok {
+			if err := json.Unmarshal(data, dest); err != nil {
+				return false, err
+			}
+			return true, nil
+		}
 	}
 	return false, fmt.Errorf("unknown action: %s", action)
 }
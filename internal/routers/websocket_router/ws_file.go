@@ -15,7 +15,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
 	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
@@ -255,8 +257,10 @@ func (h *FileWSHandler) FileUploadChunkBinary(c *pkgapp.WebsocketClient, data []
 		return
 	}
 
-	session := binarySession.(*FileUploadBinaryChunkSession)
-	if session == nil {
+	// The store also holds HTTP upload sessions
+	// 会话存储中也包含 HTTP 上传会话
+	session, ok := binarySession.(*FileUploadBinaryChunkSession)
+	if !ok || session == nil {
 		h.logError(c, "websocket_router.file.FileUploadChunkBinary", fmt.Errorf("session is nil: %s", sessionID))
 		c.ToResponse(code.ErrorFileUploadSessionNotFound.WithData(map[string]string{
 			"sessionID": sessionID,
@@ -409,17 +413,35 @@ func (h *FileWSHandler) FileUploadChunkBinary(c *pkgapp.WebsocketClient, data []
 		// Broadcast file update message (only when fileSvc is available)
 		// 广播文件更新消息（仅在 fileSvc 可用时）
 		if fileSvc != nil {
-			c.BroadcastResponse(code.Success.WithData(
-				dto.FileSyncModifyMessage{
-					Path:             fileSvc.Path,
-					PathHash:         fileSvc.PathHash,
-					ContentHash:      fileSvc.ContentHash,
-					Size:             fileSvc.Size,
-					Ctime:            fileSvc.Ctime,
-					Mtime:            fileSvc.Mtime,
-					UpdatedTimestamp: fileSvc.UpdatedTimestamp,
-				},
-			).WithVault(session.Vault), true, FileSyncUpdate)
+			// Render thumbnails of uploaded images in the background
+			// 在后台为上传的图片生成缩略图
+			uid := c.User.UID
+			safego.Go(h.App.Logger(), func() {
+				if err := h.App.ThumbnailService.Prepare(context.Background(), uid, fileSvc); err != nil {
+					h.App.Logger().Warn("websocket_router.file.FileUploadChunkBinary.PrepareThumbnails err", zap.Int64("uid", uid), zap.String("path", fileSvc.Path), zap.Error(err))
+				}
+			})
+			vault := session.Vault
+			broadcast := func() {
+				c.BroadcastResponse(code.Success.WithData(
+					dto.FileSyncModifyMessage{
+						Path:             fileSvc.Path,
+						PathHash:         fileSvc.PathHash,
+						ContentHash:      fileSvc.ContentHash,
+						Size:             fileSvc.Size,
+						Ctime:            fileSvc.Ctime,
+						Mtime:            fileSvc.Mtime,
+						UpdatedTimestamp: fileSvc.UpdatedTimestamp,
+					},
+				).WithVault(vault), true, FileSyncUpdate)
+			}
+			// Config files change in bursts, other devices only get the latest update of each
+			// 配置文件会连续变更，其他设备只接收每个文件的最后一次更新
+			if isConfigFolderPath(fileSvc.Path) {
+				configBroadcasts.schedule(configBroadcastKey(c.User.ID, vault, fileSvc.PathHash), configBroadcastDelay, broadcast)
+			} else {
+				broadcast()
+			}
 		} else {
 			h.logInfo(c, "FileUploadChunkBinary: fileSvc is nil, ack sent but skipping broadcast", zap.String("path", session.Path))
 		}
@@ -459,6 +481,7 @@ func (h *FileWSHandler) FileDelete(c *pkgapp.WebsocketClient, msg *pkgapp.WebSoc
 		Path:     fileSvc.Path,
 		PathHash: fileSvc.PathHash,
 	}).WithVault(params.Vault).WithContext(params.Context), string(FileDeleteAck))
+	configBroadcasts.cancel(configBroadcastKey(c.User.ID, params.Vault, fileSvc.PathHash))
 
 	// Broadcast file deletion message
 	// 广播文件删除消息
@@ -524,6 +547,7 @@ func (h *FileWSHandler) FileRename(c *pkgapp.WebsocketClient, msg *pkgapp.WebSoc
 		Path:     newFile.Path,
 		PathHash: newFile.PathHash,
 	}).WithVault(params.Vault).WithContext(params.Context), string(FileRenameAck))
+	configBroadcasts.cancel(configBroadcastKey(c.User.ID, params.Vault, oldFile.PathHash))
 
 	c.BroadcastResponse(code.Success.WithData(
 		dto.FileSyncRenameMessage{
@@ -572,6 +596,10 @@ func (h *FileWSHandler) FileChunkDownload(c *pkgapp.WebsocketClient, msg *pkgapp
 
 	// Check if file exists on disk
 	// 检查文件是否存在于磁盘
+	if err := fileService.FetchContent(ctx, c.User.UID, fileSvc); err != nil {
+		h.respondError(c, code.ErrorFileGetFailed, err, "websocket_router.file.FileChunkDownload.FetchContent")
+		return
+	}
 	if _, err := os.Stat(fileSvc.SavePath); os.IsNotExist(err) {
 		h.respondError(c, code.ErrorFileGetFailed, fmt.Errorf("file not found on disk: %s (path: %s, pathHash: %s)", fileSvc.SavePath, fileSvc.Path, fileSvc.PathHash), "websocket_router.file.FileChunkDownload.Stat")
 		return
@@ -714,6 +742,10 @@ func (h *FileWSHandler) doFileSync(c *pkgapp.WebsocketClient, params *dto.FileSy
 
 	pkgapp.NoteModifyLog(c.TraceID, c.User.UID, "FileSync", "", params.Vault)
 
+	// Remember which config files this device syncs, so later broadcasts skip the others
+	// 记录该设备同步哪些配置文件，之后的广播将跳过其余配置文件
+	c.SetBroadcastFilter(configFileBroadcastFilter(service.NewConfigFileScope(params)))
+
 	// 获取或创建仓库
 	h.App.VaultService.GetOrCreate(ctx, c.User.UID, params.Vault)
 
@@ -799,6 +831,7 @@ func (h *FileWSHandler) doFileSync(c *pkgapp.WebsocketClient, params *dto.FileSy
 				// Record PathHash deleted by client to avoid duplicate sending
 				// 记录客户端已主动删除的 PathHash，避免重复下发
 				cDelFilesKeys[delFile.PathHash] = struct{}{}
+				configBroadcasts.cancel(configBroadcastKey(c.User.ID, params.Vault, fileSvc.PathHash))
 
 				// Broadcast deletion to other clients
 				// 将删除消息广播给其他客户端
@@ -1318,7 +1351,7 @@ func (h *FileWSHandler) handleFileChunkDownloadSendChunks(c *pkgapp.WebsocketCli
 
 	// Open file
 	// 打开文件
-	file, err := os.Open(session.SavePath)
+	file, err := atrest.Open(session.SavePath)
 	if err != nil {
 		LogErrorWithLogger(logger, c, "sendFileChunks: failed to open file", err)
 		c.ToResponse(code.ErrorFileGetFailed.WithDetails("failed to open file"))
@@ -0,0 +1,125 @@
+package websocket_router
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+)
+
+// configBroadcastDelay is how long updates of a config file are held back so that a burst of writes
+// (plugin settings, workspace layout) reaches the other devices as one update
+// configBroadcastDelay 配置文件更新的延迟时间，使连续写入（插件设置、工作区布局）只以一次更新发送给其他设备
+const configBroadcastDelay = 2 * time.Second
+
+// configFileBroadcastFilter keeps the config files a device does not sync out of the file broadcasts it receives
+// configFileBroadcastFilter 从设备接收的文件广播中剔除其不同步的配置文件
+func configFileBroadcastFilter(scope service.ConfigFileScope) pkgapp.BroadcastFilter {
+	return func(action string, content *pkgapp.Res) bool {
+		switch WebSocketSendAction(action) {
+		case FileSyncUpdate, FileSyncDelete, FileSyncRename, FileSyncMtime:
+		default:
+			return true
+		}
+		path, oldPath := broadcastFilePaths(content.Data)
+		// A rename is kept while either side is synced, so the device does not keep a stale copy
+		// 重命名只要任一侧路径参与同步即保留，避免设备保留过时的副本
+		return scope.Allows(path) || (oldPath != "" && scope.Allows(oldPath))
+	}
+}
+
+// broadcastFilePaths returns the path and, for renames, the old path carried by a file broadcast
+// broadcastFilePaths 返回文件广播携带的路径，重命名时还返回旧路径
+func broadcastFilePaths(data any) (path, oldPath string) {
+	switch d := data.(type) {
+	case dto.FileSyncModifyMessage:
+		return d.Path, ""
+	case *dto.FileSyncModifyMessage:
+		return d.Path, ""
+	case dto.FileSyncDeleteMessage:
+		return d.Path, ""
+	case *dto.FileSyncDeleteMessage:
+		return d.Path, ""
+	case dto.FileSyncMtimeMessage:
+		return d.Path, ""
+	case *dto.FileSyncMtimeMessage:
+		return d.Path, ""
+	case dto.FileSyncRenameMessage:
+		return d.Path, d.OldPath
+	case *dto.FileSyncRenameMessage:
+		return d.Path, d.OldPath
+	case dto.FileDTO:
+		return d.Path, ""
+	case *dto.FileDTO:
+		return d.Path, ""
+	case map[string]any:
+		// Relayed by another instance
+		// 由其他实例转发
+		path, _ = d["path"].(string)
+		oldPath, _ = d["oldPath"].(string)
+		return path, oldPath
+	}
+	return "", ""
+}
+
+// isConfigFolderPath reports whether the path lies in a top-level folder starting with a dot, which is
+// where Obsidian keeps the vault config (.obsidian or a custom config folder)
+// isConfigFolderPath 判断路径是否位于以点开头的顶层目录中，Obsidian 将笔记库配置保存在此类目录（.obsidian 或自定义配置目录）
+func isConfigFolderPath(path string) bool {
+	path = strings.TrimPrefix(path, "/")
+	return strings.HasPrefix(path, ".") && strings.Contains(path, "/")
+}
+
+// configBroadcastCoalescer holds back the update broadcasts of config files; within the delay only the
+// latest update of a file is sent
+// configBroadcastCoalescer 延迟配置文件的更新广播；延迟期间同一文件只发送最后一次更新
+type configBroadcastCoalescer struct {
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+}
+
+// configBroadcasts global config file broadcast coalescer
+// configBroadcasts 全局配置文件广播合并器
+var configBroadcasts = &configBroadcastCoalescer{timers: make(map[string]*time.Timer)}
+
+// schedule replaces the pending broadcast of the key with send, which runs after delay
+// schedule 用 send 替换该键待发送的广播，send 在 delay 之后执行
+func (b *configBroadcastCoalescer) schedule(key string, delay time.Duration, send func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok := b.timers[key]; ok {
+		t.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		b.mu.Lock()
+		if b.timers[key] != timer {
+			b.mu.Unlock()
+			return
+		}
+		delete(b.timers, key)
+		b.mu.Unlock()
+		send()
+	})
+	b.timers[key] = timer
+}
+
+// cancel drops the pending broadcast of the key, e.g. because the file was deleted or renamed since
+// cancel 丢弃该键待发送的广播，例如文件在此期间已被删除或重命名
+func (b *configBroadcastCoalescer) cancel(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok := b.timers[key]; ok {
+		t.Stop()
+		delete(b.timers, key)
+	}
+}
+
+// configBroadcastKey builds the coalescer key of a file
+// configBroadcastKey 构建文件在合并器中的键
+func configBroadcastKey(uid, vault, pathHash string) string {
+	return uid + "_" + vault + "_" + pathHash
+}
@@ -0,0 +1,52 @@
+package websocket_router
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConfigFileBroadcastFilter verifies devices without config sync do not receive config file broadcasts,
+// whether sent locally or relayed by another instance.
+// TestConfigFileBroadcastFilter 验证未开启配置同步的设备不会收到配置文件广播，无论广播来自本实例还是其他实例转发。
+func TestConfigFileBroadcastFilter(t *testing.T) {
+	filter := configFileBroadcastFilter(service.NewConfigFileScope(&dto.FileSyncRequest{}))
+
+	assert.True(t, filter(string(FileSyncUpdate), &pkgapp.Res{Data: dto.FileSyncModifyMessage{Path: "a.png"}}))
+	assert.False(t, filter(string(FileSyncUpdate), &pkgapp.Res{Data: dto.FileSyncModifyMessage{Path: ".obsidian/app.json"}}))
+	assert.False(t, filter(string(FileSyncDelete), &pkgapp.Res{Data: map[string]any{"path": ".obsidian/app.json"}}))
+	assert.True(t, filter(string(FileSyncRename), &pkgapp.Res{Data: dto.FileSyncRenameMessage{Path: ".obsidian/a.png", OldPath: "a.png"}}))
+	// Other actions pass untouched
+	// 其他动作不受影响
+	assert.True(t, filter(string(NoteSyncModify), &pkgapp.Res{Data: map[string]any{"path": ".obsidian/app.json"}}))
+}
+
+// TestConfigBroadcastCoalescer verifies a burst of updates is sent once, and a cancelled update not at all.
+// TestConfigBroadcastCoalescer 验证连续更新只发送一次，被取消的更新不会发送。
+func TestConfigBroadcastCoalescer(t *testing.T) {
+	b := &configBroadcastCoalescer{timers: make(map[string]*time.Timer)}
+	var sent, last atomic.Int32
+
+	for i := 1; i <= 5; i++ {
+		b.schedule("k", 20*time.Millisecond, func() {
+			sent.Add(1)
+			last.Store(int32(i))
+		})
+	}
+	b.schedule("gone", 20*time.Millisecond, func() { sent.Add(100) })
+	b.cancel("gone")
+
+	assert.Eventually(t, func() bool { return sent.Load() == 1 }, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), sent.Load())
+	assert.Equal(t, int32(5), last.Load())
+
+	assert.True(t, isConfigFolderPath(".obsidian/app.json"))
+	assert.False(t, isConfigFolderPath(".hidden.png"))
+	assert.False(t, isConfigFolderPath("Notes/.obsidian/app.json"))
+}
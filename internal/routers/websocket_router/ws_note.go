@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
@@ -24,13 +25,21 @@ import (
 // 使用 App Container 注入依赖
 type NoteWSHandler struct {
 	*WSHandler
+	echo *noteEchoFilter // nil when echo suppression is disabled // 关闭回传抑制时为 nil
 }
 
 // NewNoteWSHandler creates NoteWSHandler instance
 // NewNoteWSHandler 创建 NoteWSHandler 实例
 func NewNoteWSHandler(a *app.App) *NoteWSHandler {
+	window, err := util.ParseDuration(a.Config().App.WebSocketEchoWindow)
+	if err != nil {
+		a.Logger().Warn("invalid ws-echo-window, echo suppression disabled",
+			zap.String("value", a.Config().App.WebSocketEchoWindow), zap.Error(err))
+		window = 0
+	}
 	return &NoteWSHandler{
 		WSHandler: NewWSHandler(a),
+		echo:      newNoteEchoFilter(window),
 	}
 }
 
@@ -81,6 +90,24 @@ func (h *NoteWSHandler) NoteModify(c *pkgapp.WebsocketClient, msg *pkgapp.WebSoc
 
 	pkgapp.NoteModifyLog(c.TraceID, c.User.UID, "NoteModify", params.Path, params.Vault)
 
+	// The same content for the same note was just accepted (typically two clients re-broadcasting
+	// each other's change); acknowledge it without checking, merging or broadcasting again
+	// 同一笔记的相同内容刚被接受过（通常是两个客户端互相回传修改），直接确认，不再检查、合并或广播
+	incomingHash := params.ContentHash
+	if lastTime, ok := h.echo.recent(c.User.UID, params.Vault, params.PathHash, incomingHash); ok {
+		h.App.Logger().Debug("duplicate NoteModify within echo window, suppressed",
+			zap.String(logger.FieldTraceID, c.TraceID),
+			zap.Int64(logger.FieldUID, c.User.UID),
+			zap.String(logger.FieldPath, params.Path),
+			zap.String("contentHash", incomingHash))
+		c.ToResponse(code.Success.WithData(dto.NoteModifyAckMessage{
+			LastTime: lastTime,
+			Path:     params.Path,
+			PathHash: params.PathHash,
+		}).WithVault(params.Vault).WithContext(params.Context), string(NoteModifyAck))
+		return
+	}
+
 	ctx := c.Context()
 
 	noteSvc := h.App.GetNoteService(c.ClientType(), c.ClientName(), c.ClientVersion())
@@ -102,6 +129,29 @@ func (h *NoteWSHandler) NoteModify(c *pkgapp.WebsocketClient, msg *pkgapp.WebSoc
 
 		var isExcludeSelf bool = true
 
+		// Scan the client content for embedded credentials before any merge or conflict file
+		// touches it; strict mode rejects the write. Content the server already holds is not rescanned
+		// 在合并或生成冲突文件之前扫描客户端内容中嵌入的凭据，严格模式下拒绝写入；服务端已有的相同内容不再扫描
+		var secretFindings []dto.SecretFindingDTO
+		if nodeCheck == nil || nodeCheck.ContentHash != params.ContentHash {
+			secretFindings, err = h.App.SecretScanService.Check(ctx, c.User.UID, params.Path, params.Content)
+			if err != nil {
+				c.ToResponse(code.ErrorNoteSecretDetected.WithData(dto.NoteSecretWarningMessage{
+					Path:     params.Path,
+					PathHash: params.PathHash,
+					Blocked:  true,
+					Findings: secretFindings,
+				}).WithVault(params.Vault).WithContext(params.Context), string(NoteSecretWarning))
+				// The ack carries the rejection so the client drops the pending upload instead of re-sending it
+				// 确认消息携带拒绝码，客户端据此移除待上传条目而不是反复重传
+				c.ToResponse(code.ErrorNoteSecretDetected.WithData(dto.NoteModifyAckMessage{
+					Path:     params.Path,
+					PathHash: params.PathHash,
+				}).WithVault(params.Vault).WithContext(params.Context), string(NoteModifyAck))
+				return
+			}
+		}
+
 		// Perform conflict detection when a note with the same name exists on the server
 		// 当服务器存在同名笔记时，进行冲突检测
 		if nodeCheck != nil {
@@ -109,9 +159,12 @@ func (h *NoteWSHandler) NoteModify(c *pkgapp.WebsocketClient, msg *pkgapp.WebSoc
 			baseHash := params.BaseHash
 			contentHash := params.ContentHash
 
-			// Skip update and return success (no update) to client when content hasn't changed
-			// 当内容未变化时，跳过更新，给客户端返回成功(无更新)
+			// Skip update and return success (no update) to client when content hasn't changed.
+			// This also drops echoes of our own broadcast: nothing is written or re-broadcast.
+			// 当内容未变化时，跳过更新，给客户端返回成功(无更新)。
+			// 这同时丢弃了对服务端广播的回传：不写入也不再广播。
 			if serverHash == contentHash {
+				h.echo.record(c.User.UID, params.Vault, params.PathHash, contentHash, nodeCheck.UpdatedTimestamp)
 
 				h.App.Logger().Debug("server content equals client content, skipping update",
 					zap.String(logger.FieldTraceID, c.TraceID),
@@ -412,6 +465,15 @@ func (h *NoteWSHandler) NoteModify(c *pkgapp.WebsocketClient, msg *pkgapp.WebSoc
 			h.respondError(c, code.ErrorNoteModifyOrCreateFailed, err, "websocket_router.note.NoteModify.ModifyOrCreate")
 			return
 		}
+		h.echo.record(c.User.UID, params.Vault, params.PathHash, incomingHash, note.UpdatedTimestamp)
+
+		if len(secretFindings) > 0 {
+			c.ToResponse(code.Success.WithData(dto.NoteSecretWarningMessage{
+				Path:     note.Path,
+				PathHash: note.PathHash,
+				Findings: secretFindings,
+			}).WithVault(params.Vault).WithContext(params.Context), string(NoteSecretWarning))
+		}
 
 		// 通知发送方上传已确认，携带 lastTime 和 path 供客户端更新 hashManager
 		// Notify sender of successful write with lastTime and path for client hashManager update
@@ -429,6 +491,7 @@ func (h *NoteWSHandler) NoteModify(c *pkgapp.WebsocketClient, msg *pkgapp.WebSoc
 				Ctime:            note.Ctime,
 				Mtime:            note.Mtime,
 				UpdatedTimestamp: note.UpdatedTimestamp,
+				OriginClient:     c.TraceID,
 			},
 		).WithVault(params.Vault), isExcludeSelf, NoteSyncModify)
 		return
@@ -582,6 +645,7 @@ func (h *NoteWSHandler) NoteDelete(c *pkgapp.WebsocketClient, msg *pkgapp.WebSoc
 		h.respondError(c, code.ErrorNoteDeleteFailed, err, "websocket_router.note.handleNoteDelete.Delete")
 		return
 	}
+	h.audit(c, domain.AuditActionNoteDelete, params.Vault+"/"+note.Path, "")
 
 	c.ToResponse(code.Success.WithData(dto.NoteDeleteAckMessage{
 		LastTime: note.UpdatedTimestamp,
@@ -886,6 +950,8 @@ func (h *NoteWSHandler) doNoteSync(c *pkgapp.WebsocketClient, params *dto.NoteSy
 					continue
 				}
 
+				h.audit(c, domain.AuditActionNoteDelete, params.Vault+"/"+note.Path, "sync")
+
 				// Record PathHash deleted by client to avoid duplicate sending
 				// 记录客户端已主动删除的 PathHash，避免重复下发
 				cDelNotesKeys[delNote.PathHash] = struct{}{}
@@ -962,13 +1028,16 @@ func (h *NoteWSHandler) doNoteSync(c *pkgapp.WebsocketClient, params *dto.NoteSy
 				continue
 			}
 			if note != nil && note.Action != "delete" {
+				// 正文同样留待发送该页时回填，不在队列中常驻
+				// Content is likewise filled when its page is sent instead of staying in the queue
 				messageQueue = append(messageQueue, dto.WSQueuedMessage{
 					Context: params.Context,
 					Action:  NoteSyncModify,
+					NoteID:  note.ID,
+					Size:    note.Size,
 					Data: dto.NoteSyncModifyMessage{
 						Path:             note.Path,
 						PathHash:         note.PathHash,
-						Content:          note.Content,
 						ContentHash:      note.ContentHash,
 						Ctime:            note.Ctime,
 						Mtime:            note.Mtime,
@@ -1057,6 +1126,7 @@ func (h *NoteWSHandler) doNoteSync(c *pkgapp.WebsocketClient, params *dto.NoteSy
 								Context: params.Context,
 								Action:  NoteSyncModify,
 								NoteID:  note.ID,
+								Size:    note.Size,
 								Data: dto.NoteSyncModifyMessage{
 									Path:             note.Path,
 									PathHash:         note.PathHash,
@@ -1118,6 +1188,7 @@ func (h *NoteWSHandler) doNoteSync(c *pkgapp.WebsocketClient, params *dto.NoteSy
 					Context: params.Context,
 					Action:  NoteSyncModify,
 					NoteID:  note.ID,
+					Size:    note.Size,
 					Data: dto.NoteSyncModifyMessage{
 						Path:             note.Path,
 						PathHash:         note.PathHash,
@@ -1195,6 +1266,7 @@ func (h *NoteWSHandler) doNoteSync(c *pkgapp.WebsocketClient, params *dto.NoteSy
 			Vault:        params.Vault,
 			MessageQueue: messageQueue,
 			PageSize:     pageSize,
+			PageStarts:   splitPagesBySize(messageQueue, pageSize, util.ParseSize(h.App.Config().App.SyncDownPageBytes, 0)),
 			Window:       window,
 			FillContent: func(ctx context.Context, noteID int64) (string, error) {
 				n, err := noteSvc.GetByID(ctx, uid, noteID)
@@ -0,0 +1,87 @@
+package websocket_router
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// noteEchoSweepInterval how often expired echo entries are swept
+// noteEchoSweepInterval 过期回传记录的清理间隔
+const noteEchoSweepInterval = time.Minute
+
+// noteEchoEntry last accepted NoteModify for one note and content hash
+// noteEchoEntry 某笔记某内容哈希最近一次被接受的 NoteModify
+type noteEchoEntry struct {
+	seen     time.Time
+	lastTime int64 // UpdatedTimestamp returned in the original ack // 原始确认中返回的 UpdatedTimestamp
+}
+
+// noteEchoFilter suppresses identical NoteModify messages for the same note within a short window.
+// When two clients keep re-broadcasting each other's changes, the repeats are acknowledged from
+// this cache instead of going through update check, merge and broadcast again.
+// A nil noteEchoFilter suppresses nothing.
+// noteEchoFilter 在短时间窗口内抑制同一笔记的相同 NoteModify 消息。
+// 当两个客户端互相回传对方的修改时，重复消息直接由缓存确认，不再走更新检查、合并与广播流程。
+// nil 时不做任何抑制。
+type noteEchoFilter struct {
+	window    time.Duration
+	mu        sync.Mutex
+	entries   map[string]noteEchoEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// newNoteEchoFilter creates a noteEchoFilter, returns nil when window <= 0
+// newNoteEchoFilter 创建 noteEchoFilter，window <= 0 时返回 nil
+func newNoteEchoFilter(window time.Duration) *noteEchoFilter {
+	if window <= 0 {
+		return nil
+	}
+	return &noteEchoFilter{
+		window:  window,
+		entries: make(map[string]noteEchoEntry),
+		now:     time.Now,
+	}
+}
+
+func noteEchoKey(uid int64, vault, pathHash, contentHash string) string {
+	return strconv.FormatInt(uid, 10) + "|" + vault + "|" + pathHash + "|" + contentHash
+}
+
+// recent reports whether the same content was accepted for this note within the window,
+// returning the lastTime of that acknowledgement
+// recent 判断该笔记在窗口期内是否已接受过相同内容，并返回当时确认的 lastTime
+func (f *noteEchoFilter) recent(uid int64, vault, pathHash, contentHash string) (int64, bool) {
+	if f == nil || contentHash == "" {
+		return 0, false
+	}
+	now := f.now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, ok := f.entries[noteEchoKey(uid, vault, pathHash, contentHash)]
+	if !ok || now.Sub(e.seen) > f.window {
+		return 0, false
+	}
+	return e.lastTime, true
+}
+
+// record remembers that contentHash was accepted for this note
+// record 记录该笔记已接受 contentHash 对应的内容
+func (f *noteEchoFilter) record(uid int64, vault, pathHash, contentHash string, lastTime int64) {
+	if f == nil || contentHash == "" {
+		return
+	}
+	now := f.now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if now.Sub(f.lastSweep) > noteEchoSweepInterval {
+		for k, e := range f.entries {
+			if now.Sub(e.seen) > f.window {
+				delete(f.entries, k)
+			}
+		}
+		f.lastSweep = now
+	}
+	f.entries[noteEchoKey(uid, vault, pathHash, contentHash)] = noteEchoEntry{seen: now, lastTime: lastTime}
+}
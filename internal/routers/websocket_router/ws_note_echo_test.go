@@ -0,0 +1,40 @@
+package websocket_router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoteEchoFilter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	f := newNoteEchoFilter(time.Second)
+	f.now = func() time.Time { return now }
+
+	_, ok := f.recent(1, "v", "ph", "ch")
+	assert.False(t, ok)
+
+	f.record(1, "v", "ph", "ch", 42)
+	lastTime, ok := f.recent(1, "v", "ph", "ch")
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), lastTime)
+
+	// Different content, user or note is not suppressed
+	_, ok = f.recent(1, "v", "ph", "other")
+	assert.False(t, ok)
+	_, ok = f.recent(2, "v", "ph", "ch")
+	assert.False(t, ok)
+
+	now = now.Add(2 * time.Second)
+	_, ok = f.recent(1, "v", "ph", "ch")
+	assert.False(t, ok)
+}
+
+func TestNoteEchoFilter_Disabled(t *testing.T) {
+	f := newNoteEchoFilter(0)
+	assert.Nil(t, f)
+	f.record(1, "v", "ph", "ch", 42)
+	_, ok := f.recent(1, "v", "ph", "ch")
+	assert.False(t, ok)
+}
@@ -22,6 +22,7 @@ type syncDownloadEntry struct {
 	Vault        string                // 仓库名称
 	MessageQueue []dto.WSQueuedMessage // 待发送的全部明细队列
 	PageSize     int                   // 每页大小
+	PageStarts   []int                 // 按字节切分时每页的起始下标，nil 表示按 PageSize 等分 // start index of each page when cut by bytes, nil splits evenly by PageSize
 
 	// SentPage/AckedPage/Window 是下行窗口流水线的状态机（同步流水线设计 §4.2），
 	// 替代旧版 CurrentPage 的"发送游标"单一角色。
@@ -46,12 +47,55 @@ type syncDownloadEntry struct {
 // totalPages returns the number of pages MessageQueue splits into at PageSize.
 // totalPages 返回 MessageQueue 按 PageSize 切分后的总页数。
 func (e *syncDownloadEntry) totalPages() int {
+	if e.PageStarts != nil {
+		return len(e.PageStarts)
+	}
 	if e.PageSize <= 0 {
 		return 0
 	}
 	return (len(e.MessageQueue) + e.PageSize - 1) / e.PageSize
 }
 
+// pageRange returns the MessageQueue bounds [start, end) of a page.
+// pageRange 返回某一页在 MessageQueue 中的范围 [start, end)。
+func (e *syncDownloadEntry) pageRange(page int) (start, end int) {
+	if e.PageStarts != nil {
+		start = e.PageStarts[page]
+		if page+1 < len(e.PageStarts) {
+			return start, e.PageStarts[page+1]
+		}
+		return start, len(e.MessageQueue)
+	}
+	start = page * e.PageSize
+	end = start + e.PageSize
+	if end > len(e.MessageQueue) {
+		end = len(e.MessageQueue)
+	}
+	return start, end
+}
+
+// splitPagesBySize cuts the queue into pages of at most pageSize messages whose lazily filled
+// content stays within maxBytes, a larger note gets a page of its own. Returns the start index
+// of every page, nil when maxBytes <= 0 so the entry splits evenly by pageSize.
+// splitPagesBySize 将队列切分为每页至多 pageSize 条消息、且按需回填的正文总量不超过 maxBytes 的分页，
+// 超过 maxBytes 的笔记单独成页。返回每页的起始下标；maxBytes <= 0 时返回 nil，由 entry 按 pageSize 等分。
+func splitPagesBySize(queue []dto.WSQueuedMessage, pageSize int, maxBytes int64) []int {
+	if maxBytes <= 0 || pageSize <= 0 {
+		return nil
+	}
+	starts := make([]int, 0, len(queue)/pageSize+1)
+	count, size := 0, int64(0)
+	for i, msg := range queue {
+		if i == 0 || count == pageSize || size+msg.Size > maxBytes {
+			starts = append(starts, i)
+			count, size = 0, 0
+		}
+		count++
+		size += msg.Size
+	}
+	return starts
+}
+
 // noteContentFillPool 是用于分页发送前按需回填笔记正文的小并发 worker pool，
 // 并发度限制在个位数，避免大批量笔记回填时把磁盘 IO 打成突发洪峰。
 // noteContentFillPool is a small worker pool used to lazily fill note content right
@@ -164,11 +208,7 @@ func envelopePageIndex(c2 *code.Code, page int) *code.Code {
 // responsibilities separate. Caller must hold entry.mu (held by pump's caller).
 func sendSyncPage(c *pkgapp.WebsocketClient, entry *syncDownloadEntry) (isLast bool) {
 	page := entry.SentPage
-	start := page * entry.PageSize
-	end := start + entry.PageSize
-	if end > len(entry.MessageQueue) {
-		end = len(entry.MessageQueue)
-	}
+	start, end := entry.pageRange(page)
 
 	chunk := entry.MessageQueue[start:end]
 	isLast = end == len(entry.MessageQueue)
@@ -63,6 +63,47 @@ func TestTotalPages(t *testing.T) {
 	}
 }
 
+// TestSplitPagesBySize covers byte-bounded pages: a page closes once its content would exceed
+// maxBytes or it holds pageSize messages, an oversized note is sent alone, and maxBytes<=0 keeps
+// the even PageSize split.
+func TestSplitPagesBySize(t *testing.T) {
+	sizes := []int64{100, 300, 700, 5000, 10, 10, 10, 0}
+	queue := make([]dto.WSQueuedMessage, len(sizes))
+	for i, size := range sizes {
+		queue[i] = dto.WSQueuedMessage{Size: size}
+	}
+
+	starts := splitPagesBySize(queue, 3, 1000)
+	want := []int{0, 2, 3, 4, 7}
+	if len(starts) != len(want) {
+		t.Fatalf("splitPagesBySize() = %v, want %v", starts, want)
+	}
+	for i := range want {
+		if starts[i] != want[i] {
+			t.Fatalf("splitPagesBySize() = %v, want %v", starts, want)
+		}
+	}
+
+	e := &syncDownloadEntry{MessageQueue: queue, PageSize: 3, PageStarts: starts}
+	if got := e.totalPages(); got != 5 {
+		t.Fatalf("totalPages() = %d, want 5", got)
+	}
+	if start, end := e.pageRange(2); start != 3 || end != 4 {
+		t.Fatalf("pageRange(2) = [%d,%d), want [3,4) for the oversized note", start, end)
+	}
+	if start, end := e.pageRange(4); start != 7 || end != 8 {
+		t.Fatalf("pageRange(4) = [%d,%d), want [7,8)", start, end)
+	}
+
+	if starts := splitPagesBySize(queue, 3, 0); starts != nil {
+		t.Fatalf("splitPagesBySize() with maxBytes=0 = %v, want nil", starts)
+	}
+	e = &syncDownloadEntry{MessageQueue: queue, PageSize: 3}
+	if start, end := e.pageRange(2); start != 6 || end != 8 {
+		t.Fatalf("pageRange(2) without PageStarts = [%d,%d), want [6,8)", start, end)
+	}
+}
+
 // TestPump_WindowZero_StopAndWaitEquivalence covers design §4.2's central equivalence claim:
 // with Window=0, pump sends exactly one page per call and never runs ahead of AckedPage — this
 // is required to be message-for-message identical to pre-3.6.0 stop-and-wait behavior.
@@ -0,0 +1,42 @@
+package websocket_router
+
+import (
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+)
+
+// UserSettingWSHandler WebSocket per-user settings handler
+// UserSettingWSHandler WebSocket 用户级设置处理器
+type UserSettingWSHandler struct {
+	*WSHandler
+}
+
+// NewUserSettingWSHandler creates UserSettingWSHandler instance
+// NewUserSettingWSHandler 创建 UserSettingWSHandler 实例
+func NewUserSettingWSHandler(a *app.App) *UserSettingWSHandler {
+	return &UserSettingWSHandler{
+		WSHandler: NewWSHandler(a),
+	}
+}
+
+// SettingsSync replies with the per-user settings changed since the cursor of the client; later
+// changes are pushed with the same action by the REST API
+// SettingsSync 回复客户端游标之后变更的用户级设置；之后的变更由 REST API 以同一动作推送
+func (h *UserSettingWSHandler) SettingsSync(c *pkgapp.WebsocketClient, msg *pkgapp.WebSocketMessage) {
+	params := &dto.UserSettingListRequest{}
+	valid, errs := c.BindAndValidWithAction(msg.Type, msg.Data, params)
+	if !valid {
+		h.respondErrorWithData(c, code.ErrorInvalidParams.WithDetails(errs.ErrorsToString()), errs, errs.MapsToString(), "websocket_router.user_setting.SettingsSync.BindAndValid", msg)
+		return
+	}
+
+	res, err := h.App.UserSettingService.ListSince(c.Context(), c.User.UID, params)
+	if err != nil {
+		h.respondError(c, code.ErrorDBQuery, err, "websocket_router.user_setting.SettingsSync.ListSince", msg)
+		return
+	}
+
+	c.ToResponse(code.Success.WithData(res).WithContext(params.Context), UserSettingsSync)
+}
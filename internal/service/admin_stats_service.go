@@ -0,0 +1,180 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap"
+)
+
+// adminStatsDefaultHours default window of the hourly sync event series
+// adminStatsDefaultHours 按小时同步事件序列的默认时间范围
+const adminStatsDefaultHours = 24
+
+// adminStatsBackupWindows time windows of the backup success rates
+// adminStatsBackupWindows 备份成功率的时间窗口
+var adminStatsBackupWindows = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"24h", 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+	{"30d", 30 * 24 * time.Hour},
+}
+
+// AdminStatsService aggregates usage statistics of all users for the admin dashboard
+// AdminStatsService 汇总所有用户的用量统计，供管理后台仪表盘使用
+type AdminStatsService interface {
+	// Stats aggregates the statistics; connections holds the active WebSocket connections per user,
+	// which only the router layer knows about
+	// Stats 汇总统计数据；connections 为每个用户的活跃 WebSocket 连接数，只有路由层知道
+	Stats(ctx context.Context, params *dto.AdminStatsRequest, connections map[int64]int) (*dto.AdminStatsDTO, error)
+}
+
+// adminStatsService implementation of AdminStatsService interface
+// adminStatsService 实现 AdminStatsService 接口
+type adminStatsService struct {
+	userRepo    domain.UserRepository
+	vaultRepo   domain.VaultRepository
+	syncLogRepo domain.SyncLogRepository
+	backupRepo  domain.BackupRepository
+	logger      *zap.Logger
+}
+
+// NewAdminStatsService creates AdminStatsService instance
+// NewAdminStatsService 创建 AdminStatsService 实例
+func NewAdminStatsService(userRepo domain.UserRepository, vaultRepo domain.VaultRepository, syncLogRepo domain.SyncLogRepository, backupRepo domain.BackupRepository, logger *zap.Logger) AdminStatsService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &adminStatsService{
+		userRepo:    userRepo,
+		vaultRepo:   vaultRepo,
+		syncLogRepo: syncLogRepo,
+		backupRepo:  backupRepo,
+		logger:      logger,
+	}
+}
+
+// Stats implements AdminStatsService.
+// A user whose statistics cannot be read is reported with what could be read, so one broken
+// user database does not hide the dashboard of the whole instance.
+// 无法读取统计的用户只报告已读取到的部分，避免单个损坏的用户库导致整个实例的仪表盘不可用。
+func (s *adminStatsService) Stats(ctx context.Context, params *dto.AdminStatsRequest, connections map[int64]int) (*dto.AdminStatsDTO, error) {
+	hours := params.Hours
+	if hours <= 0 {
+		hours = adminStatsDefaultHours
+	}
+	now := time.Now()
+	seriesStart := now.Truncate(time.Hour).Add(-time.Duration(hours-1) * time.Hour)
+
+	uids, err := s.userRepo.GetAllUIDs(ctx)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	result := &dto.AdminStatsDTO{
+		Users:       make([]*dto.AdminUserStatsDTO, 0, len(uids)),
+		GeneratedAt: timex.Time(now),
+	}
+	for _, n := range connections {
+		result.Totals.Connections += n
+	}
+
+	hourly := make(map[int64]int64, hours)
+	backups := make([]map[int]int64, len(adminStatsBackupWindows))
+	for i := range backups {
+		backups[i] = map[int]int64{}
+	}
+
+	for _, uid := range uids {
+		user := &dto.AdminUserStatsDTO{UID: uid, Connections: connections[uid]}
+		if u, err := s.userRepo.GetByUID(ctx, uid, false); err == nil {
+			user.Username = u.Username
+		}
+
+		vaults, err := s.vaultRepo.List(ctx, uid)
+		if err != nil {
+			s.warn("vaults", uid, err)
+		}
+		for _, v := range vaults {
+			user.Vaults++
+			user.NoteCount += v.NoteCount
+			user.NoteSize += v.NoteSize
+			user.FileCount += v.FileCount
+			user.FileSize += v.FileSize
+		}
+
+		perHour, err := s.syncLogRepo.CountByHour(ctx, seriesStart, uid)
+		if err != nil {
+			s.warn("sync events", uid, err)
+		}
+		for hour, n := range perHour {
+			hourly[hour] += n
+			user.SyncEvents += n
+		}
+
+		for i, w := range adminStatsBackupWindows {
+			counts, err := s.backupRepo.CountHistoryByStatus(ctx, uid, now.Add(-w.duration))
+			if err != nil {
+				s.warn("backups", uid, err)
+				break
+			}
+			for status, n := range counts {
+				backups[i][status] += n
+			}
+		}
+
+		result.Totals.Users++
+		result.Totals.Vaults += user.Vaults
+		result.Totals.NoteCount += user.NoteCount
+		result.Totals.NoteSize += user.NoteSize
+		result.Totals.FileCount += user.FileCount
+		result.Totals.FileSize += user.FileSize
+		result.Totals.SyncEvents += user.SyncEvents
+		result.Users = append(result.Users, user)
+	}
+
+	sort.SliceStable(result.Users, func(i, j int) bool {
+		return result.Users[i].NoteSize+result.Users[i].FileSize > result.Users[j].NoteSize+result.Users[j].FileSize
+	})
+
+	// Every hour of the window is present so graphs do not need to fill gaps
+	// 时间范围内的每个小时都有数据点，图表无需自行补齐
+	result.SyncEventsPerHour = make([]*dto.AdminStatsHourDTO, 0, hours)
+	for h := seriesStart; !h.After(now); h = h.Add(time.Hour) {
+		result.SyncEventsPerHour = append(result.SyncEventsPerHour, &dto.AdminStatsHourDTO{Hour: h.UnixMilli(), Count: hourly[h.UnixMilli()]})
+	}
+
+	result.Backups = make([]*dto.AdminBackupStatsDTO, 0, len(adminStatsBackupWindows))
+	for i, w := range adminStatsBackupWindows {
+		stats := &dto.AdminBackupStatsDTO{
+			Window:  w.name,
+			Success: backups[i][domain.BackupStatusSuccess] + backups[i][domain.BackupStatusNoUpdate],
+			Failed:  backups[i][domain.BackupStatusFailed],
+			Stopped: backups[i][domain.BackupStatusStopped],
+		}
+		if finished := stats.Success + stats.Failed; finished > 0 {
+			stats.SuccessRate = float64(stats.Success) / float64(finished)
+		}
+		result.Backups = append(result.Backups, stats)
+	}
+	return result, nil
+}
+
+// warn logs a statistic of a user that could not be read
+// warn 记录无法读取的用户统计项
+func (s *adminStatsService) warn(item string, uid int64, err error) {
+	s.logger.Warn("admin stats: read failed", zap.String("item", item), zap.Int64("uid", uid), zap.Error(err))
+}
+
+// Ensure adminStatsService implements AdminStatsService
+// 确保 adminStatsService 实现了 AdminStatsService 接口
+var _ AdminStatsService = (*adminStatsService)(nil)
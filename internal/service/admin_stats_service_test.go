@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestAdminStatsService_Stats verifies per user usage, the hourly series and backup success rates are aggregated.
+// TestAdminStatsService_Stats 验证每用户用量、按小时序列与备份成功率的汇总。
+func TestAdminStatsService_Stats(t *testing.T) {
+	userRepo := new(domainmocks.MockUserRepository)
+	vaultRepo := new(domainmocks.MockVaultRepository)
+	syncLogRepo := new(domainmocks.MockSyncLogRepository)
+	backupRepo := new(domainmocks.MockBackupRepository)
+
+	thisHour := time.Now().Truncate(time.Hour).UnixMilli()
+	userRepo.On("GetAllUIDs", mock.Anything).Return([]int64{1, 2}, nil)
+	userRepo.On("GetByUID", mock.Anything, int64(1)).Return(&domain.User{UID: 1, Username: "alice"}, nil)
+	userRepo.On("GetByUID", mock.Anything, int64(2)).Return(&domain.User{UID: 2, Username: "bob"}, nil)
+	vaultRepo.On("List", mock.Anything, int64(1)).Return([]*domain.Vault{{NoteCount: 2, NoteSize: 10, FileCount: 1, FileSize: 5}}, nil)
+	vaultRepo.On("List", mock.Anything, int64(2)).Return([]*domain.Vault{
+		{NoteCount: 3, NoteSize: 100},
+		{FileCount: 4, FileSize: 200},
+	}, nil)
+	syncLogRepo.On("CountByHour", mock.Anything, mock.Anything, int64(1)).Return(map[int64]int64{thisHour: 3}, nil)
+	// A broken user database only hides that part of the user
+	syncLogRepo.On("CountByHour", mock.Anything, mock.Anything, int64(2)).Return(nil, errors.New("boom"))
+	backupRepo.On("CountHistoryByStatus", mock.Anything, int64(1), mock.Anything).Return(map[int]int64{
+		domain.BackupStatusSuccess:  2,
+		domain.BackupStatusNoUpdate: 1,
+		domain.BackupStatusFailed:   1,
+	}, nil)
+	backupRepo.On("CountHistoryByStatus", mock.Anything, int64(2), mock.Anything).Return(map[int]int64{}, nil)
+
+	svc := NewAdminStatsService(userRepo, vaultRepo, syncLogRepo, backupRepo, zap.NewNop())
+	stats, err := svc.Stats(context.Background(), &dto.AdminStatsRequest{Hours: 6}, map[int64]int{1: 2, 2: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, stats.Totals.Users)
+	assert.Equal(t, 3, stats.Totals.Vaults)
+	assert.Equal(t, int64(5), stats.Totals.NoteCount)
+	assert.Equal(t, int64(205), stats.Totals.FileSize)
+	assert.Equal(t, 3, stats.Totals.Connections)
+	assert.Equal(t, int64(3), stats.Totals.SyncEvents)
+
+	require.Len(t, stats.Users, 2)
+	assert.Equal(t, "bob", stats.Users[0].Username, "largest storage first")
+	assert.Equal(t, 2, stats.Users[1].Connections)
+
+	require.Len(t, stats.SyncEventsPerHour, 6)
+	last := stats.SyncEventsPerHour[len(stats.SyncEventsPerHour)-1]
+	assert.Equal(t, thisHour, last.Hour)
+	assert.Equal(t, int64(3), last.Count)
+	assert.Equal(t, int64(0), stats.SyncEventsPerHour[0].Count)
+
+	require.Len(t, stats.Backups, 3)
+	assert.Equal(t, "24h", stats.Backups[0].Window)
+	assert.Equal(t, int64(3), stats.Backups[0].Success)
+	assert.Equal(t, int64(1), stats.Backups[0].Failed)
+	assert.InDelta(t, 0.75, stats.Backups[0].SuccessRate, 1e-9)
+}
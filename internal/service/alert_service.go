@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	appconfig "github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/alert"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+// Alerter receives alerts about failing jobs
+// Alerter 接收任务失败的告警
+type Alerter interface {
+	// Alert asynchronously sends msg to the admin channels and the enabled channels of uid. Alerts sharing a key
+	// are throttled by the configured cooldown until Resolve is called.
+	// Alert 将 msg 异步发送到管理员渠道及 uid 启用的渠道。相同 key 的告警在调用 Resolve 前受冷却时间限制。
+	Alert(uid int64, key string, msg alert.Message)
+
+	// Resolve clears the cooldown of key once the job succeeds, its next failure is alerted right away
+	// Resolve 在任务成功后清除 key 的冷却，下一次失败会立即告警
+	Resolve(uid int64, key string)
+}
+
+// AlertService defines the backup failure alerting business service interface
+// AlertService 定义备份失败告警业务服务接口
+type AlertService interface {
+	Alerter
+
+	// List lists all alert channels of a user
+	// List 列出用户的全部告警渠道
+	List(ctx context.Context, uid int64) ([]*dto.AlertChannelDTO, error)
+
+	// Save creates or updates an alert channel
+	// Save 新建或更新告警渠道
+	Save(ctx context.Context, uid int64, params *dto.AlertChannelRequest) (*dto.AlertChannelDTO, error)
+
+	// Delete removes an alert channel
+	// Delete 删除告警渠道
+	Delete(ctx context.Context, uid int64, id int64) error
+
+	// Test sends a test alert through a channel and reports the outcome
+	// Test 通过渠道发送测试告警并返回结果
+	Test(ctx context.Context, uid int64, id int64) error
+
+	// Mailer returns the SMTP server of the alert channels for other server mails, nil when none is configured
+	// Mailer 返回告警渠道的 SMTP 服务器供其他服务端邮件使用，未配置时为 nil
+	Mailer() Mailer
+
+	// Shutdown waits for alerts being sent
+	// Shutdown 等待发送中的告警结束
+	Shutdown(ctx context.Context) error
+}
+
+// alertService implements AlertService
+// alertService 实现 AlertService 接口
+type alertService struct {
+	repo     domain.AlertChannelRepository
+	smtp     alert.SMTPConfig
+	admin    []alert.Channel
+	cooldown time.Duration
+	client   *http.Client
+	logger   *zap.Logger
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time // uid:key -> time of the last alert // uid:key -> 最近一次告警时间
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAlertService creates an AlertService instance; invalid admin channels are logged and skipped
+// NewAlertService 创建 AlertService 实例；无效的管理员渠道记录日志后跳过
+func NewAlertService(repo domain.AlertChannelRepository, conf *appconfig.AlertConfig, logger *zap.Logger) AlertService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &alertService{
+		repo:     repo,
+		smtp:     alert.SMTPConfig(conf.SMTP),
+		client:   &http.Client{Timeout: alert.DefaultTimeout},
+		logger:   logger,
+		lastSent: make(map[string]time.Time),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	cooldown, err := util.ParseDuration(conf.Cooldown)
+	if err != nil || cooldown < 0 {
+		logger.Warn("alert: invalid cooldown, using 1h", zap.String("cooldown", conf.Cooldown))
+		cooldown = time.Hour
+	}
+	s.cooldown = cooldown
+
+	for i, c := range conf.Channels {
+		channel, err := alert.New(alert.Config(c), s.smtp, s.client)
+		if err != nil {
+			logger.Warn("alert: admin channel skipped", zap.Int("index", i), zap.String("type", c.Type), zap.Error(err))
+			continue
+		}
+		s.admin = append(s.admin, channel)
+	}
+	return s
+}
+
+// alertChannelConfig converts the domain model to the channel config
+// alertChannelConfig 将领域模型转换为渠道配置
+func alertChannelConfig(c *domain.AlertChannel) alert.Config {
+	return alert.Config{Type: c.Type, Name: c.Name, To: c.To, URL: c.URL, Token: c.Token, ChatID: c.ChatID, Priority: c.Priority}
+}
+
+// alertChannelToDTO converts the domain model to the DTO
+// alertChannelToDTO 将领域模型转换为 DTO
+func alertChannelToDTO(c *domain.AlertChannel) *dto.AlertChannelDTO {
+	return &dto.AlertChannelDTO{
+		ID:        c.ID,
+		Name:      c.Name,
+		Type:      c.Type,
+		To:        c.To,
+		URL:       c.URL,
+		Token:     c.Token,
+		ChatID:    c.ChatID,
+		Priority:  c.Priority,
+		IsEnabled: c.IsEnabled,
+		CreatedAt: timex.Time(c.CreatedAt),
+		UpdatedAt: timex.Time(c.UpdatedAt),
+	}
+}
+
+// List lists all alert channels of a user
+// List 列出用户的全部告警渠道
+func (s *alertService) List(ctx context.Context, uid int64) ([]*dto.AlertChannelDTO, error) {
+	list, err := s.repo.List(ctx, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	results := make([]*dto.AlertChannelDTO, 0, len(list))
+	for _, c := range list {
+		results = append(results, alertChannelToDTO(c))
+	}
+	return results, nil
+}
+
+// Save creates or updates an alert channel
+// Save 新建或更新告警渠道
+func (s *alertService) Save(ctx context.Context, uid int64, params *dto.AlertChannelRequest) (*dto.AlertChannelDTO, error) {
+	c := &domain.AlertChannel{
+		ID:        params.ID,
+		UID:       uid,
+		Name:      params.Name,
+		Type:      params.Type,
+		To:        params.To,
+		URL:       params.URL,
+		Token:     params.Token,
+		ChatID:    params.ChatID,
+		Priority:  params.Priority,
+		IsEnabled: params.IsEnabled,
+	}
+	if _, err := alert.New(alertChannelConfig(c), s.smtp, s.client); err != nil {
+		return nil, code.ErrorAlertChannelInvalid.WithDetails(err.Error())
+	}
+
+	if c.ID > 0 {
+		existing, err := s.repo.Get(ctx, c.ID, uid)
+		if err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		if existing == nil {
+			return nil, code.ErrorAlertChannelNotFound
+		}
+		c.CreatedAt = existing.CreatedAt
+	}
+
+	saved, err := s.repo.Save(ctx, c, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return alertChannelToDTO(saved), nil
+}
+
+// Delete removes an alert channel
+// Delete 删除告警渠道
+func (s *alertService) Delete(ctx context.Context, uid int64, id int64) error {
+	existing, err := s.repo.Get(ctx, id, uid)
+	if err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if existing == nil {
+		return code.ErrorAlertChannelNotFound
+	}
+	if err := s.repo.Delete(ctx, id, uid); err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// Test sends a test alert through a channel and reports the outcome
+// Test 通过渠道发送测试告警并返回结果
+func (s *alertService) Test(ctx context.Context, uid int64, id int64) error {
+	c, err := s.repo.Get(ctx, id, uid)
+	if err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if c == nil {
+		return code.ErrorAlertChannelNotFound
+	}
+	channel, err := alert.New(alertChannelConfig(c), s.smtp, s.client)
+	if err != nil {
+		return code.ErrorAlertChannelInvalid.WithDetails(err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, alert.DefaultTimeout)
+	defer cancel()
+	err = channel.Send(ctx, alert.Message{
+		Title: "Fast Note Sync test alert",
+		Body:  "This channel will be alerted when a backup of user " + strconv.FormatInt(uid, 10) + " fails.",
+	})
+	if err != nil {
+		return code.ErrorAlertSendFailed.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// throttled reports whether an alert about key was sent within the cooldown, and records this one otherwise
+// throttled 判断 key 的告警是否在冷却时间内已发送过，否则记录本次告警
+func (s *alertService) throttled(uid int64, key string) bool {
+	id := strconv.FormatInt(uid, 10) + ":" + key
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.lastSent[id]; ok && now.Sub(last) < s.cooldown {
+		return true
+	}
+	s.lastSent[id] = now
+	return false
+}
+
+// Resolve clears the cooldown of key once the job succeeds
+// Resolve 在任务成功后清除 key 的冷却
+func (s *alertService) Resolve(uid int64, key string) {
+	s.mu.Lock()
+	delete(s.lastSent, strconv.FormatInt(uid, 10)+":"+key)
+	s.mu.Unlock()
+}
+
+// Alert asynchronously sends msg to the admin channels and the enabled channels of uid
+// Alert 将 msg 异步发送到管理员渠道及 uid 启用的渠道
+func (s *alertService) Alert(uid int64, key string, msg alert.Message) {
+	if s.ctx.Err() != nil || s.throttled(uid, key) {
+		return
+	}
+	s.wg.Add(1)
+	safego.Go(s.logger, func() {
+		defer s.wg.Done()
+
+		channels := append([]alert.Channel(nil), s.admin...)
+		list, err := s.repo.List(s.ctx, uid)
+		if err != nil {
+			s.logger.Warn("alert: load user channels failed", zap.Int64("uid", uid), zap.Error(err))
+		}
+		for _, c := range list {
+			if !c.IsEnabled {
+				continue
+			}
+			channel, err := alert.New(alertChannelConfig(c), s.smtp, s.client)
+			if err != nil {
+				s.logger.Warn("alert: user channel skipped", zap.Int64("uid", uid), zap.Int64("channelId", c.ID), zap.Error(err))
+				continue
+			}
+			channels = append(channels, channel)
+		}
+
+		var errs []error
+		for _, channel := range channels {
+			ctx, cancel := context.WithTimeout(s.ctx, alert.DefaultTimeout)
+			errs = append(errs, channel.Send(ctx, msg))
+			cancel()
+		}
+		if err := errors.Join(errs...); err != nil {
+			s.logger.Warn("alert: send failed", zap.Int64("uid", uid), zap.String("key", key), zap.Error(err))
+		}
+	})
+}
+
+// Mailer returns the SMTP server of the alert channels, nil when none is configured
+// Mailer 返回告警渠道的 SMTP 服务器，未配置时为 nil
+func (s *alertService) Mailer() Mailer {
+	if !s.smtp.Enabled() {
+		return nil
+	}
+	return s.smtp
+}
+
+// Shutdown waits for alerts being sent
+// Shutdown 等待发送中的告警结束
+func (s *alertService) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+// Ensure alertService implements AlertService
+// 确保 alertService 实现了 AlertService 接口
+var _ AlertService = (*alertService)(nil)
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	appconfig "github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/alert"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeAlertChannelRepo in-memory domain.AlertChannelRepository
+type fakeAlertChannelRepo struct {
+	mu       sync.Mutex
+	channels map[int64]*domain.AlertChannel
+	nextID   int64
+}
+
+func (r *fakeAlertChannelRepo) List(ctx context.Context, uid int64) ([]*domain.AlertChannel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*domain.AlertChannel
+	for _, c := range r.channels {
+		if c.UID == uid {
+			copied := *c
+			list = append(list, &copied)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeAlertChannelRepo) Get(ctx context.Context, id, uid int64) (*domain.AlertChannel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.channels[id]; ok && c.UID == uid {
+		copied := *c
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeAlertChannelRepo) Save(ctx context.Context, c *domain.AlertChannel, uid int64) (*domain.AlertChannel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *c
+	copied.UID = uid
+	if copied.ID == 0 {
+		r.nextID++
+		copied.ID = r.nextID
+	}
+	r.channels[copied.ID] = &copied
+	result := copied
+	return &result, nil
+}
+
+func (r *fakeAlertChannelRepo) Delete(ctx context.Context, id, uid int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.channels, id)
+	return nil
+}
+
+// ntfyRecorder collects the topics alerts were published to
+type ntfyRecorder struct {
+	server *httptest.Server
+	topics chan string
+}
+
+func newNtfyRecorder(t *testing.T) *ntfyRecorder {
+	rec := &ntfyRecorder{topics: make(chan string, 16)}
+	rec.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		rec.topics <- r.URL.Path
+	}))
+	t.Cleanup(rec.server.Close)
+	return rec
+}
+
+func (rec *ntfyRecorder) next(t *testing.T) string {
+	select {
+	case topic := <-rec.topics:
+		return topic
+	case <-time.After(5 * time.Second):
+		t.Fatal("alert not delivered")
+		return ""
+	}
+}
+
+func TestAlertService_AdminAndUserChannels(t *testing.T) {
+	rec := newNtfyRecorder(t)
+	repo := &fakeAlertChannelRepo{channels: map[int64]*domain.AlertChannel{}}
+	svc := NewAlertService(repo, &appconfig.AlertConfig{
+		Cooldown: "1h",
+		Channels: []appconfig.AlertChannelConfig{{Type: alert.TypeNtfy, URL: rec.server.URL + "/admin"}},
+	}, zap.NewNop())
+	t.Cleanup(func() { _ = svc.Shutdown(context.Background()) })
+
+	ctx := context.Background()
+	_, err := svc.Save(ctx, 1, &dto.AlertChannelRequest{Type: alert.TypeNtfy, URL: rec.server.URL + "/user", IsEnabled: true})
+	require.NoError(t, err)
+	_, err = svc.Save(ctx, 1, &dto.AlertChannelRequest{Type: alert.TypeNtfy, URL: rec.server.URL + "/disabled"})
+	require.NoError(t, err)
+
+	svc.Alert(1, "backup:7", alert.Message{Title: "Backup failed", Body: "boom"})
+	got := []string{rec.next(t), rec.next(t)}
+	assert.ElementsMatch(t, []string{"/admin", "/user"}, got)
+
+	// Throttled within the cooldown until the task succeeds again
+	svc.Alert(1, "backup:7", alert.Message{Title: "Backup failed"})
+	svc.Resolve(1, "backup:7")
+	svc.Alert(1, "backup:7", alert.Message{Title: "Backup failed"})
+	got = []string{rec.next(t), rec.next(t)}
+	assert.ElementsMatch(t, []string{"/admin", "/user"}, got)
+
+	require.NoError(t, svc.Shutdown(context.Background()))
+	assert.Empty(t, rec.topics)
+}
+
+func TestAlertService_SaveValidation(t *testing.T) {
+	repo := &fakeAlertChannelRepo{channels: map[int64]*domain.AlertChannel{}}
+	svc := NewAlertService(repo, &appconfig.AlertConfig{}, zap.NewNop())
+	ctx := context.Background()
+
+	_, err := svc.Save(ctx, 1, &dto.AlertChannelRequest{Type: alert.TypeEmail, To: "me@example.com"})
+	assert.ErrorIs(t, err, code.ErrorAlertChannelInvalid)
+
+	_, err = svc.Save(ctx, 1, &dto.AlertChannelRequest{ID: 9, Type: alert.TypeNtfy, URL: "https://ntfy.sh/topic"})
+	assert.ErrorIs(t, err, code.ErrorAlertChannelNotFound)
+
+	assert.ErrorIs(t, svc.Test(ctx, 1, 9), code.ErrorAlertChannelNotFound)
+}
+
+// TestAlertService_Mailer verifies other server mails share the SMTP server of the alert channels.
+// TestAlertService_Mailer 验证其他服务端邮件共用告警渠道的 SMTP 服务器。
+func TestAlertService_Mailer(t *testing.T) {
+	repo := &fakeAlertChannelRepo{channels: map[int64]*domain.AlertChannel{}}
+	assert.Nil(t, NewAlertService(repo, &appconfig.AlertConfig{}, zap.NewNop()).Mailer())
+
+	svc := NewAlertService(repo, &appconfig.AlertConfig{SMTP: appconfig.AlertSMTPConfig{Host: "smtp.example.com", Port: 465, From: "fns@example.com"}}, zap.NewNop())
+	assert.Equal(t, alert.SMTPConfig{Host: "smtp.example.com", Port: 465, From: "fns@example.com"}, svc.Mailer())
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap"
+)
+
+// auditWriteTimeout upper bound of a single audit log write
+// auditWriteTimeout 单条审计日志写入的超时时间
+const auditWriteTimeout = 5 * time.Second
+
+// AuditService defines the audit log business service interface
+// AuditService 定义审计日志业务服务接口
+type AuditService interface {
+	// Record stores an audit log entry. It never fails the audited action: errors are logged, and the
+	// write is detached from ctx so a finished request cannot cancel it.
+	// Record 存储一条审计日志。不会使被审计的操作失败：错误仅记录日志，写入与 ctx 的取消解耦，请求结束不会中断写入。
+	Record(ctx context.Context, entry *domain.AuditLog)
+
+	// List lists audit log entries matching the filter, newest first
+	// List 按时间倒序分页列出符合条件的审计日志
+	List(ctx context.Context, params *dto.AuditLogListRequest, page, pageSize int) ([]*dto.AuditLogDTO, int64, error)
+
+	// CleanupByTime removes audit log entries older than the given cutoff time
+	// CleanupByTime 清理指定截止时间之前的审计日志
+	CleanupByTime(ctx context.Context, cutoffTime int64) error
+}
+
+// auditService implements AuditService
+// auditService 实现 AuditService 接口
+type auditService struct {
+	repo   domain.AuditLogRepository
+	logger *zap.Logger
+}
+
+// NewAuditService creates an AuditService instance
+// NewAuditService 创建 AuditService 实例
+func NewAuditService(repo domain.AuditLogRepository, logger *zap.Logger) AuditService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &auditService{repo: repo, logger: logger}
+}
+
+// Record implements AuditService
+func (s *auditService) Record(ctx context.Context, entry *domain.AuditLog) {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditWriteTimeout)
+	defer cancel()
+	if err := s.repo.Create(ctx, entry); err != nil {
+		s.logger.Error("audit: record failed",
+			zap.String("action", string(entry.Action)),
+			zap.Int64("uid", entry.UID),
+			zap.String("target", entry.Target),
+			zap.Error(err))
+	}
+}
+
+// List implements AuditService
+func (s *auditService) List(ctx context.Context, params *dto.AuditLogListRequest, page, pageSize int) ([]*dto.AuditLogDTO, int64, error) {
+	filter := &domain.AuditLogFilter{
+		UID:    params.UID,
+		Action: domain.AuditAction(params.Action),
+		IP:     params.IP,
+	}
+	if params.StartTime > 0 {
+		filter.StartTime = time.UnixMilli(params.StartTime)
+	}
+	if params.EndTime > 0 {
+		filter.EndTime = time.UnixMilli(params.EndTime)
+	}
+
+	list, total, err := s.repo.List(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	results := make([]*dto.AuditLogDTO, 0, len(list))
+	for _, l := range list {
+		results = append(results, &dto.AuditLogDTO{
+			ID:         l.ID,
+			UID:        l.UID,
+			Action:     string(l.Action),
+			Target:     l.Target,
+			Detail:     l.Detail,
+			IP:         l.IP,
+			ClientType: l.ClientType,
+			ClientName: l.ClientName,
+			CreatedAt:  timex.Time(l.CreatedAt),
+		})
+	}
+	return results, total, nil
+}
+
+// CleanupByTime implements AuditService
+func (s *auditService) CleanupByTime(ctx context.Context, cutoffTime int64) error {
+	return s.repo.DeleteBefore(ctx, time.UnixMilli(cutoffTime))
+}
+
+// Ensure auditService implements AuditService
+// 确保 auditService 实现了 AuditService 接口
+var _ AuditService = (*auditService)(nil)
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeAuditLogRepo in-memory domain.AuditLogRepository
+type fakeAuditLogRepo struct {
+	logs   []*domain.AuditLog
+	filter *domain.AuditLogFilter
+	ctxErr error
+}
+
+func (r *fakeAuditLogRepo) Create(ctx context.Context, log *domain.AuditLog) error {
+	r.ctxErr = ctx.Err()
+	log.ID = int64(len(r.logs) + 1)
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func (r *fakeAuditLogRepo) List(ctx context.Context, filter *domain.AuditLogFilter, page, pageSize int) ([]*domain.AuditLog, int64, error) {
+	r.filter = filter
+	return r.logs, int64(len(r.logs)), nil
+}
+
+func (r *fakeAuditLogRepo) DeleteBefore(ctx context.Context, cutoff time.Time) error {
+	kept := r.logs[:0]
+	for _, l := range r.logs {
+		if !l.CreatedAt.Before(cutoff) {
+			kept = append(kept, l)
+		}
+	}
+	r.logs = kept
+	return nil
+}
+
+func TestAuditService_RecordListCleanup(t *testing.T) {
+	repo := &fakeAuditLogRepo{}
+	svc := NewAuditService(repo, zap.NewNop())
+
+	// A finished request must not cancel the write
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	svc.Record(ctx, &domain.AuditLog{UID: 1, Action: domain.AuditActionNoteDelete, Target: "vault/a.md", IP: "10.0.0.1"})
+	require.Len(t, repo.logs, 1)
+	assert.NoError(t, repo.ctxErr)
+	assert.False(t, repo.logs[0].CreatedAt.IsZero())
+
+	svc.Record(context.Background(), &domain.AuditLog{Action: domain.AuditActionLogin, CreatedAt: time.Now().Add(-48 * time.Hour)})
+
+	list, total, err := svc.List(context.Background(), &dto.AuditLogListRequest{UID: 1, Action: "note.delete", StartTime: 1000}, 1, 20)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, total)
+	assert.Equal(t, "note.delete", list[0].Action)
+	assert.Equal(t, "10.0.0.1", list[0].IP)
+	assert.Equal(t, int64(1), repo.filter.UID)
+	assert.Equal(t, domain.AuditActionNoteDelete, repo.filter.Action)
+	assert.Equal(t, time.UnixMilli(1000), repo.filter.StartTime)
+	assert.True(t, repo.filter.EndTime.IsZero())
+
+	require.NoError(t, svc.CleanupByTime(context.Background(), time.Now().Add(-24*time.Hour).UnixMilli()))
+	require.Len(t, repo.logs, 1)
+	assert.Equal(t, domain.AuditActionNoteDelete, repo.logs[0].Action)
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/tracer"
+	"go.uber.org/zap"
+)
+
+// dedupeChunkSize size of the blobs notes and attachments are split into
+// dedupeChunkSize 笔记和附件切分成数据块的大小
+const dedupeChunkSize = 4 << 20
+
+// dedupeManifestVersion version of the manifest format
+// dedupeManifestVersion 清单格式版本
+const dedupeManifestVersion = 1
+
+// dedupeManifest lists every file of a dedupe backup run and the blobs it is made of,
+// restoring a file means concatenating its blobs in order
+// dedupeManifest 列出去重备份某次运行的所有文件及其组成的数据块，
+// 按顺序拼接数据块即可还原文件
+type dedupeManifest struct {
+	Version   int                   `json:"version"`
+	Vault     string                `json:"vault"`
+	CreatedAt time.Time             `json:"createdAt"`
+	ChunkSize int                   `json:"chunkSize"`
+	Files     []*dedupeManifestFile `json:"files"`
+}
+
+type dedupeManifestFile struct {
+	Path  string   `json:"path"`
+	Size  int64    `json:"size"`
+	Mtime int64    `json:"mtime"`
+	Blobs []string `json:"blobs"`
+}
+
+// dedupeChunk locates the data of a blob: a slice of a note or a range of an attachment on disk
+// dedupeChunk 定位数据块的数据：笔记内容的切片或磁盘上附件的一段
+type dedupeChunk struct {
+	content []byte
+	path    string
+	offset  int64
+	size    int64
+}
+
+func (c *dedupeChunk) read() ([]byte, error) {
+	if c.path == "" {
+		return c.content, nil
+	}
+	f, err := atrest.Open(c.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	buf := make([]byte, c.size)
+	if _, err := f.ReadAt(buf, c.offset); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// dedupeSnapshot the manifest of a run and the location of every blob it references
+// dedupeSnapshot 一次运行的清单及其引用的每个数据块的位置
+type dedupeSnapshot struct {
+	manifest *dedupeManifest
+	chunks   map[string]*dedupeChunk
+	order    []string // Blob hashes in first-reference order // 按首次引用顺序排列的数据块摘要
+	count    int64
+	size     int64
+}
+
+func (sn *dedupeSnapshot) add(hash string, chunk *dedupeChunk) {
+	if _, ok := sn.chunks[hash]; !ok {
+		sn.chunks[hash] = chunk
+		sn.order = append(sn.order, hash)
+	}
+}
+
+// dedupePrefix remote directory of a dedupe config, keyed by config ID so renaming the vault keeps the blobs
+// dedupePrefix 去重配置的远端目录，以配置 ID 为键，重命名笔记库不影响已上传的数据块
+func dedupePrefix(uid, configID int64) string {
+	return fmt.Sprintf("backup_dedupe_%d_%d", uid, configID)
+}
+
+// dedupeBlobKey remote object name of a blob
+// dedupeBlobKey 数据块的远端对象名
+func dedupeBlobKey(uid, configID int64, hash string) string {
+	return fmt.Sprintf("%s/blobs/%s/%s", dedupePrefix(uid, configID), hash[:2], hash)
+}
+
+// buildDedupeSnapshot Split all notes and attachments of a vault into content-addressed blobs
+// 将笔记库的所有笔记和附件切分为内容寻址的数据块
+func (s *backupService) buildDedupeSnapshot(ctx context.Context, uid int64, vault *domain.Vault, startTime time.Time) (*dedupeSnapshot, error) {
+	sn := &dedupeSnapshot{
+		manifest: &dedupeManifest{Version: dedupeManifestVersion, Vault: vault.Name, CreatedAt: startTime, ChunkSize: dedupeChunkSize},
+		chunks:   make(map[string]*dedupeChunk),
+	}
+
+	err := s.forEachResource(ctx, uid, vault, false, time.Time{}, func(v *domain.Vault, path string, isNote bool, content []byte, localSize int64, localPath string, mtime time.Time, isDeleted bool) error {
+		if isDeleted {
+			return nil
+		}
+		file := &dedupeManifestFile{Path: path, Mtime: mtime.UnixMilli()}
+
+		if isNote {
+			for off := 0; off < len(content); off += dedupeChunkSize {
+				data := content[off:min(off+dedupeChunkSize, len(content))]
+				hash := sha256Hex(data)
+				sn.add(hash, &dedupeChunk{content: data, size: int64(len(data))})
+				file.Blobs = append(file.Blobs, hash)
+			}
+			file.Size = int64(len(content))
+		} else {
+			blobs, size, err := hashFileChunks(localPath, func(hash string, off, n int64) {
+				sn.add(hash, &dedupeChunk{path: localPath, offset: off, size: n})
+			})
+			if err != nil {
+				// Skip missing files instead of failing the entire backup, same as archive backups
+				// 与归档备份一致，跳过缺失的文件而不是让整个备份失败
+				if os.IsNotExist(err) {
+					s.logger.Warn("Skipping backup of missing file", zap.String("path", path), zap.String("localPath", localPath))
+					return nil
+				}
+				return err
+			}
+			file.Blobs, file.Size = blobs, size
+		}
+
+		sn.manifest.Files = append(sn.manifest.Files, file)
+		sn.count++
+		sn.size += file.Size
+		return nil
+	})
+	return sn, err
+}
+
+// hashFileChunks Hash a file in dedupeChunkSize pieces, calling found for every piece
+// 按 dedupeChunkSize 分段计算文件摘要，每段调用一次 found
+func hashFileChunks(path string, found func(hash string, off, n int64)) ([]string, int64, error) {
+	f, err := atrest.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var blobs []string
+	var off int64
+	buf := make([]byte, dedupeChunkSize)
+	for {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 {
+			hash := sha256Hex(buf[:n])
+			found(hash, off, int64(n))
+			blobs = append(blobs, hash)
+			off += int64(n)
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return blobs, off, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+}
+
+// runDedupe Execute dedupe backup
+// 1. Split notes and attachments into content-addressed blobs
+// 2. Upload the blobs a storage target does not have yet, then the manifest
+// 执行去重备份
+// 1. 将笔记和附件切分为内容寻址的数据块
+// 2. 仅上传存储目标尚未拥有的数据块，再上传清单
+func (s *backupService) runDedupe(ctx context.Context, config *domain.BackupConfig, startTime time.Time) (int64, int64, error) {
+	uid := config.UID
+	if config.VaultID <= 0 {
+		return 0, 0, code.ErrorBackupVaultRequired
+	}
+	vault, err := s.vaultRepo.GetByID(ctx, config.VaultID, uid)
+	if err != nil {
+		return 0, 0, err
+	}
+	if vault == nil {
+		return 0, 0, code.ErrorVaultNotFound
+	}
+
+	sn, err := s.buildDedupeSnapshot(ctx, uid, vault, startTime)
+	if err != nil {
+		return 0, 0, err
+	}
+	if sn.count == 0 {
+		s.recordNoUpdateHistory(ctx, config, startTime)
+		return 0, 0, errNoUpdates
+	}
+
+	manifest, err := json.Marshal(sn.manifest)
+	if err != nil {
+		return 0, 0, err
+	}
+	manifestKey := fmt.Sprintf("%s/manifests/%s.json", dedupePrefix(uid, config.ID), startTime.Format("20060102_150405"))
+
+	var storageIds []int64
+	if err := json.Unmarshal([]byte(config.StorageIds), &storageIds); err != nil {
+		return sn.count, sn.size, code.ErrorBackupStorageIDInvalid
+	}
+
+	targets, uploadErrors := s.enabledTargets(ctx, config, storageIds, startTime)
+	var mu sync.Mutex
+	s.forEachTarget(targets, func(st *dto.StorageDTO) {
+		if err := s.uploadDedupe(ctx, config, st, sn, manifestKey, manifest, startTime); err != nil {
+			mu.Lock()
+			uploadErrors = append(uploadErrors, fmt.Sprintf("storage %d (%s): %v", st.ID, st.Type, err))
+			mu.Unlock()
+		}
+	})
+	if len(uploadErrors) > 0 {
+		return sn.count, sn.size, fmt.Errorf("upload errors: %s", strings.Join(uploadErrors, "; "))
+	}
+	return sn.count, sn.size, nil
+}
+
+// uploadDedupe Upload the missing blobs and the manifest of a snapshot to a storage target
+// 将快照中缺失的数据块与清单上传到存储目标
+func (s *backupService) uploadDedupe(ctx context.Context, config *domain.BackupConfig, stDTO *dto.StorageDTO, sn *dedupeSnapshot, manifestKey string, manifest []byte, startTime time.Time) error {
+	uid := config.UID
+	ctx, span := tracer.Start(ctx, "BackupService.uploadDedupe", tracer.UID(uid), tracer.StorageType(stDTO.Type))
+	defer span.End()
+
+	h := &domain.BackupHistory{
+		UID:         uid,
+		ConfigID:    config.ID,
+		StorageID:   stDTO.ID,
+		Type:        config.Type,
+		StartTime:   startTime,
+		Status:      domain.BackupStatusRunning,
+		FileCount:   sn.count,
+		FileSize:    sn.size,
+		FilePath:    manifestKey,
+		ArchiveSize: int64(len(manifest)),
+		Checksum:    sha256Hex(manifest),
+	}
+	progress := &dto.BackupProgressMessage{ConfigID: config.ID, StorageID: stDTO.ID, StorageType: stDTO.Type, Type: config.Type, Status: domain.BackupStatusRunning}
+
+	h, err := s.backupRepo.CreateHistory(ctx, h, uid)
+	if err != nil {
+		s.logger.Error("Failed to create backup history", zap.Error(err))
+		return err
+	}
+
+	// uploaded blobs are registered even when the run fails, so the next run does not upload them again
+	// 即使运行失败也登记已上传的数据块，下次运行无需重复上传
+	var uploaded []*domain.BackupBlob
+	fail := func(msg string) error {
+		if err := s.blobRepo.Touch(ctx, uploaded, time.Now(), uid); err != nil {
+			s.logger.Warn("Failed to register uploaded backup blobs", zap.Int64("sid", stDTO.ID), zap.Error(err))
+		}
+		s.updateHistory(ctx, h, domain.BackupStatusFailed, msg)
+		progress.Status, progress.Message = domain.BackupStatusFailed, msg
+		s.notifyProgress(uid, progress)
+		return errors.New(msg)
+	}
+
+	client, err := s.getStorageClient(ctx, uid, stDTO)
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	hashes, err := s.blobRepo.ListHashes(ctx, config.ID, stDTO.ID, uid)
+	if err != nil {
+		return fail(fmt.Sprintf("Failed to load uploaded blobs: %v", err))
+	}
+	known := make(map[string]struct{}, len(hashes))
+	for _, hash := range hashes {
+		known[hash] = struct{}{}
+	}
+
+	var missing []string
+	for _, hash := range sn.order {
+		if _, ok := known[hash]; !ok {
+			missing = append(missing, hash)
+			progress.Total += sn.chunks[hash].size
+		}
+	}
+	s.notifyProgress(uid, progress)
+
+	var last time.Time
+	for _, hash := range missing {
+		if ctx.Err() != nil {
+			return fail(ctx.Err().Error())
+		}
+		chunk := sn.chunks[hash]
+		data, err := chunk.read()
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to read blob %s: %v", hash, err))
+		}
+		if _, err := client.SendContent(dedupeBlobKey(uid, config.ID, hash), data, startTime); err != nil {
+			return fail(fmt.Sprintf("Upload failed: %v", err))
+		}
+		uploaded = append(uploaded, &domain.BackupBlob{UID: uid, ConfigID: config.ID, StorageID: stDTO.ID, Hash: hash, Size: chunk.size})
+		progress.Processed += chunk.size
+		if now := time.Now(); now.Sub(last) >= backupProgressInterval {
+			last = now
+			msg := *progress
+			s.notifyProgress(uid, &msg)
+		}
+	}
+
+	if _, err := client.SendContent(manifestKey, manifest, startTime); err != nil {
+		return fail(fmt.Sprintf("Manifest upload failed: %v", err))
+	}
+
+	// Every blob of the snapshot is referenced by the new manifest, pruning keys off this time
+	// 快照的所有数据块都被新清单引用，清理时以该时间为准
+	refs := make([]*domain.BackupBlob, 0, len(sn.order))
+	for _, hash := range sn.order {
+		refs = append(refs, &domain.BackupBlob{UID: uid, ConfigID: config.ID, StorageID: stDTO.ID, Hash: hash, Size: sn.chunks[hash].size})
+	}
+	if err := s.blobRepo.Touch(ctx, refs, time.Now(), uid); err != nil {
+		uploaded = nil
+		return fail(fmt.Sprintf("Failed to register backup blobs: %v", err))
+	}
+
+	msg := fmt.Sprintf("%s: %d new blobs (%d bytes), %d reused", successMessage(client), len(missing), progress.Total, len(sn.order)-len(missing))
+	s.updateHistory(ctx, h, domain.BackupStatusSuccess, msg)
+	progress.Status, progress.Processed, progress.Message = domain.BackupStatusSuccess, progress.Total, msg
+	s.notifyProgress(uid, progress)
+	return nil
+}
+
+// pruneDedupeBlobs Delete blobs no longer referenced by any kept manifest from every storage target of a config.
+// A blob is kept as long as one manifest newer than the cutoff references it.
+// 从配置的所有存储目标删除不再被任何保留清单引用的数据块。
+// 只要有一个晚于截止时间的清单引用该数据块，它就会被保留。
+func (s *backupService) pruneDedupeBlobs(ctx context.Context, config *domain.BackupConfig, cutoffTime time.Time) {
+	var storageIds []int64
+	if err := json.Unmarshal([]byte(config.StorageIds), &storageIds); err != nil {
+		return
+	}
+
+	for _, sid := range storageIds {
+		blobs, err := s.blobRepo.ListUnreferenced(ctx, config.ID, sid, cutoffTime, config.UID)
+		if err != nil {
+			s.logger.Error("Failed to list unreferenced backup blobs", zap.Int64("sid", sid), zap.Error(err))
+			continue
+		}
+		if len(blobs) == 0 {
+			continue
+		}
+
+		st, err := s.storageService.Get(ctx, config.UID, sid)
+		if err != nil || st == nil || !st.IsEnabled {
+			s.logger.Warn("Could not get storage client for blob pruning or storage disabled", zap.Int64("sid", sid), zap.Error(err))
+			continue
+		}
+		client, err := s.getStorageClient(ctx, config.UID, st)
+		if err != nil {
+			s.logger.Warn("Failed to initialize storage client for blob pruning", zap.Error(err))
+			continue
+		}
+
+		// Records of blobs that failed to delete are kept, so the next run retries them
+		// 删除失败的数据块保留记录，下次运行时重试
+		ids := make([]int64, 0, len(blobs))
+		for _, b := range blobs {
+			if err := client.Delete(dedupeBlobKey(config.UID, config.ID, b.Hash)); err != nil {
+				s.logger.Warn("Failed to delete unreferenced backup blob", zap.String("hash", b.Hash), zap.Error(err))
+				continue
+			}
+			ids = append(ids, b.ID)
+		}
+		if err := s.blobRepo.Delete(ctx, ids, config.UID); err != nil {
+			s.logger.Error("Failed to delete backup blob records", zap.Error(err))
+			continue
+		}
+		s.logger.Info("Pruned unreferenced backup blobs", zap.Int64("sid", sid), zap.Int("count", len(ids)))
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackupBlobRepo in-memory domain.BackupBlobRepository
+type fakeBackupBlobRepo struct {
+	mu     sync.Mutex
+	nextID int64
+	blobs  map[string]*domain.BackupBlob // storage ID + hash -> blob
+}
+
+func newFakeBackupBlobRepo() *fakeBackupBlobRepo {
+	return &fakeBackupBlobRepo{blobs: map[string]*domain.BackupBlob{}}
+}
+
+func (r *fakeBackupBlobRepo) key(storageID int64, hash string) string {
+	return fmt.Sprintf("%d/%s", storageID, hash)
+}
+
+func (r *fakeBackupBlobRepo) ListHashes(ctx context.Context, configID, storageID, uid int64) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var hashes []string
+	for _, b := range r.blobs {
+		if b.ConfigID == configID && b.StorageID == storageID {
+			hashes = append(hashes, b.Hash)
+		}
+	}
+	return hashes, nil
+}
+
+func (r *fakeBackupBlobRepo) Touch(ctx context.Context, blobs []*domain.BackupBlob, refAt time.Time, uid int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range blobs {
+		k := r.key(b.StorageID, b.Hash)
+		if existing, ok := r.blobs[k]; ok {
+			existing.LastRefAt = refAt
+			continue
+		}
+		r.nextID++
+		stored := *b
+		stored.ID, stored.LastRefAt = r.nextID, refAt
+		r.blobs[k] = &stored
+	}
+	return nil
+}
+
+func (r *fakeBackupBlobRepo) ListUnreferenced(ctx context.Context, configID, storageID int64, before time.Time, uid int64) ([]*domain.BackupBlob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*domain.BackupBlob
+	for _, b := range r.blobs {
+		if b.ConfigID == configID && b.StorageID == storageID && b.LastRefAt.Before(before) {
+			list = append(list, b)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeBackupBlobRepo) Delete(ctx context.Context, ids []int64, uid int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		for k, b := range r.blobs {
+			if b.ID == id {
+				delete(r.blobs, k)
+			}
+		}
+	}
+	return nil
+}
+
+// TestBackupService_Dedupe_UploadsOnlyNewBlobs verifies a second run only uploads a manifest and pruning removes unreferenced blobs.
+// TestBackupService_Dedupe_UploadsOnlyNewBlobs 验证第二次运行仅上传清单，且清理会删除未被引用的数据块。
+func TestBackupService_Dedupe_UploadsOnlyNewBlobs(t *testing.T) {
+	backupRepo := new(domainmocks.MockBackupRepository)
+	vaultRepo := new(domainmocks.MockVaultRepository)
+	vaultRepo.On("GetByID", mock.Anything, int64(100), int64(1)).Return(&domain.Vault{ID: 100, Name: "v"}, nil)
+	backupRepo.On("CreateHistory", mock.Anything, mock.Anything, int64(1)).Return(&domain.BackupHistory{UID: 1}, nil)
+
+	svc := newBackupSvc(backupRepo, vaultRepo, &backupStorageStub{storages: map[int64]*dto.StorageDTO{
+		1: {ID: 1, Type: "localfs", IsEnabled: true},
+	}})
+	saveDir := t.TempDir()
+	svc.storageConfig = &config.StorageConfig{LocalFS: config.StorageLocalFSConfig{SavePath: saveDir}}
+	blobRepo := newFakeBackupBlobRepo()
+	svc.blobRepo = blobRepo
+
+	var messages []string
+	svc.SetProgressHandler(func(uid int64, msg *dto.BackupProgressMessage) {
+		if msg.Status == domain.BackupStatusSuccess {
+			messages = append(messages, msg.Message)
+		}
+	})
+
+	attachment := filepath.Join(t.TempDir(), "image.png")
+	require.NoError(t, os.WriteFile(attachment, []byte("png bytes"), 0o644))
+	notes := []*domain.Note{
+		{Path: "a.md", Content: "same content"},
+		{Path: "copy/a.md", Content: "same content"},
+	}
+	svc.noteRepo.(*domainmocks.MockNoteRepository).On("List", mock.Anything, int64(100), 1, 1000000, int64(1), "", false, "", false, "", "", []string(nil), []string(nil)).Return(notes, nil)
+	svc.fileRepo.(*domainmocks.MockFileRepository).On("List", mock.Anything, int64(100), 1, 1000000, int64(1), "", false, "", "").Return([]*domain.File{
+		{Path: "image.png", SavePath: attachment},
+	}, nil)
+
+	cfg := &domain.BackupConfig{ID: 9, UID: 1, VaultID: 100, Type: "dedupe", StorageIds: "[1]"}
+	first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+
+	count, _, err := svc.runDedupe(context.Background(), cfg, first)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.Len(t, blobRepo.blobs, 2, "duplicate notes share one blob")
+
+	_, _, err = svc.runDedupe(context.Background(), cfg, first.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.True(t, strings.HasPrefix(messages[0], "Success: 2 new blobs"), messages[0])
+	assert.True(t, strings.HasPrefix(messages[1], "Success: 0 new blobs"), messages[1])
+
+	prefix := filepath.Join(saveDir, dedupePrefix(1, 9))
+	data, err := os.ReadFile(filepath.Join(prefix, "manifests", first.Format("20060102_150405")+".json"))
+	require.NoError(t, err)
+	var manifest dedupeManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	require.Len(t, manifest.Files, 3)
+	assert.Equal(t, manifest.Files[0].Blobs, manifest.Files[1].Blobs)
+	assert.FileExists(t, filepath.Join(saveDir, dedupeBlobKey(1, 9, manifest.Files[2].Blobs[0])))
+
+	// Nothing references the blobs once every manifest is older than the cutoff
+	// 所有清单都早于截止时间后，数据块不再被引用
+	svc.pruneDedupeBlobs(context.Background(), cfg, time.Now().Add(time.Minute))
+	assert.Empty(t, blobRepo.blobs)
+	assert.NoFileExists(t, filepath.Join(saveDir, dedupeBlobKey(1, 9, manifest.Files[2].Blobs[0])))
+}
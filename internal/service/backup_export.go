@@ -0,0 +1,180 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// exportArtifactTTL how long a finished export stays available for Range resumes
+// exportArtifactTTL 导出完成后可用于 Range 续传的保留时间
+const exportArtifactTTL = 6 * time.Hour
+
+// exportMirror forwards writes to the HTTP response until it fails, then swallows them,
+// so that a disconnecting client does not abort building the cached artifact
+// exportMirror 将写入转发给 HTTP 响应，失败后静默丢弃后续写入，
+// 使客户端断开时不会中断缓存产物的构建
+type exportMirror struct {
+	w   io.Writer
+	err error
+}
+
+func (m *exportMirror) Write(p []byte) (int, error) {
+	if m.err == nil {
+		_, m.err = m.w.Write(p)
+	}
+	return len(p), nil
+}
+
+// exportDir returns the directory holding cached export artifacts
+// exportDir 返回存放导出缓存产物的目录
+func (s *backupService) exportDir() string {
+	return filepath.Join(s.backupStagingDir(), "export")
+}
+
+// exportArtifactPath returns the cached artifact path of an export, one per user and filter set
+// exportArtifactPath 返回导出的缓存产物路径，每个用户和筛选条件组合一个
+func (s *backupService) exportArtifactPath(uid int64, params *dto.VaultExportRequest) string {
+	key := util.EncodeHash32(fmt.Sprintf("%s|%s|%d", params.Vault, exportFolder(params.Folder), params.Since))
+	return filepath.Join(s.exportDir(), fmt.Sprintf("export_%d_%s.zip", uid, key))
+}
+
+// exportFolder normalizes the folder filter of an export
+// exportFolder 规范化导出的目录筛选条件
+func exportFolder(folder string) string {
+	return strings.Trim(strings.ReplaceAll(folder, "\\", "/"), "/")
+}
+
+// ExportZip streams a ZIP of the vault's notes and attachments to w.
+// The archive is written to a cached artifact at the same time and stamped with modTime, so that
+// a client resuming with Range and If-Range: <Last-Modified> can be served the identical bytes.
+// ExportZip 将保险库中的笔记和附件以 ZIP 流式写入 w。
+// 同时写入一份以 modTime 标记的缓存产物，客户端携带 Range 与 If-Range: <Last-Modified> 续传时可获得完全相同的字节。
+func (s *backupService) ExportZip(ctx context.Context, uid int64, params *dto.VaultExportRequest, modTime time.Time, w io.Writer) error {
+	vault, err := s.vaultRepo.GetByName(ctx, params.Vault, uid)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if vault == nil {
+		return code.ErrorVaultNotFound
+	}
+
+	s.sweepExportArtifacts()
+	if err := os.MkdirAll(s.exportDir(), 0o755); err != nil {
+		return err
+	}
+	artifact := s.exportArtifactPath(uid, params)
+	partial := artifact + "." + uuid.New().String() + ".partial"
+	out, err := os.Create(partial)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(partial)
+
+	// The artifact is finished even if the client goes away, that is what a resume is served from
+	// 即使客户端断开也会完成产物构建，续传请求正是由它提供
+	buildCtx := context.WithoutCancel(ctx)
+	mirror := &exportMirror{w: w}
+	zw := zip.NewWriter(io.MultiWriter(out, mirror))
+	folder := exportFolder(params.Folder)
+	count := 0
+
+	err = s.forEachResource(buildCtx, uid, vault, params.Since > 0, time.UnixMilli(params.Since), func(v *domain.Vault, path string, isNote bool, content []byte, localSize int64, localPath string, mtime time.Time, isDeleted bool) error {
+		if isDeleted || (folder != "" && !strings.HasPrefix(path, folder+"/")) {
+			return nil
+		}
+
+		var src io.Reader
+		if isNote {
+			src = bytes.NewReader(content)
+		} else {
+			f, err := atrest.Open(localPath)
+			if err != nil {
+				if os.IsNotExist(err) {
+					s.logger.Warn("Skipping export of missing file", zap.String("path", path), zap.String("localPath", localPath))
+					return nil
+				}
+				return err
+			}
+			defer f.Close()
+			src = f
+		}
+
+		entry, err := zw.CreateHeader(&zip.FileHeader{Name: path, Method: zip.Deflate, Modified: mtime})
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(entry, src); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err == nil {
+		err = zw.Close()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(partial, artifact); err != nil {
+		return err
+	}
+	_ = os.Chtimes(artifact, modTime, modTime)
+
+	s.logger.Info("vault export finished",
+		zap.Int64("uid", uid),
+		zap.String("vault", params.Vault),
+		zap.Int("entries", count),
+		zap.Bool("clientGone", mirror.err != nil))
+	return nil
+}
+
+// ExportArtifact returns the cached artifact of a previous ExportZip with the same filters
+// ExportArtifact 返回此前相同筛选条件的 ExportZip 缓存产物
+func (s *backupService) ExportArtifact(ctx context.Context, uid int64, params *dto.VaultExportRequest) (string, time.Time, error) {
+	artifact := s.exportArtifactPath(uid, params)
+	info, err := os.Stat(artifact)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if time.Since(info.ModTime()) > exportArtifactTTL {
+		_ = os.Remove(artifact)
+		return "", time.Time{}, os.ErrNotExist
+	}
+	return artifact, info.ModTime(), nil
+}
+
+// sweepExportArtifacts removes cached exports older than exportArtifactTTL
+// sweepExportArtifacts 删除超过 exportArtifactTTL 的导出缓存
+func (s *backupService) sweepExportArtifacts() {
+	entries, err := os.ReadDir(s.exportDir())
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err == nil && time.Since(info.ModTime()) > exportArtifactTTL {
+			_ = os.Remove(filepath.Join(s.exportDir(), e.Name()))
+		}
+	}
+}
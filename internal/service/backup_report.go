@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/healthping"
+	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/webhook"
+	"go.uber.org/zap"
+)
+
+// backupReportEvent event name of backup run reports
+// backupReportEvent 备份运行报告的事件名
+const backupReportEvent = "backup.report"
+
+// buildBackupReport builds the webhook report of a finished run from the saved config state
+// buildBackupReport 根据已保存的配置状态构建本次运行的 Webhook 报告
+func (s *backupService) buildBackupReport(ctx context.Context, config *domain.BackupConfig, fileCount, fileSize int64, startTime, endTime time.Time) *dto.BackupReport {
+	report := &dto.BackupReport{
+		Event:      backupReportEvent,
+		ConfigID:   config.ID,
+		Vault:      s.getVaultName(ctx, config.VaultID, config.UID),
+		Type:       config.Type,
+		Status:     config.LastStatus,
+		Success:    config.LastStatus == domain.BackupStatusSuccess || config.LastStatus == domain.BackupStatusNoUpdate,
+		Message:    config.LastMessage,
+		FileCount:  fileCount,
+		FileSize:   fileSize,
+		StartTime:  timex.Time(startTime),
+		EndTime:    timex.Time(endTime),
+		DurationMs: endTime.Sub(startTime).Milliseconds(),
+		Targets:    []dto.BackupReportTarget{},
+	}
+
+	var storageIds []int64
+	_ = json.Unmarshal([]byte(config.StorageIds), &storageIds)
+	for _, sid := range storageIds {
+		target := dto.BackupReportTarget{StorageID: sid}
+		if s.storageService != nil {
+			if st, err := s.storageService.Get(ctx, config.UID, sid); err == nil && st != nil {
+				target.Type = st.Type
+			}
+		}
+		report.Targets = append(report.Targets, target)
+	}
+	return report
+}
+
+// sendBackupReport posts the report of a finished run to the config's webhook in the background
+// sendBackupReport 在后台将本次运行报告推送到配置的 Webhook
+func (s *backupService) sendBackupReport(ctx context.Context, config *domain.BackupConfig, fileCount, fileSize int64, startTime time.Time) {
+	if config.WebhookURL == "" {
+		return
+	}
+	report := s.buildBackupReport(ctx, config, fileCount, fileSize, startTime, time.Now())
+	url, secret := config.WebhookURL, config.WebhookSecret
+
+	safego.Go(s.logger, func() {
+		sendCtx, cancel := context.WithTimeout(context.Background(), webhook.DefaultTimeout)
+		defer cancel()
+		if err := webhook.Send(sendCtx, nil, url, secret, backupReportEvent, report); err != nil {
+			s.logger.Warn("Failed to deliver backup report", zap.Int64("configId", report.ConfigID), zap.Error(err))
+		}
+	})
+}
+
+// sendBackupPing reports the outcome of a finished run to the config's dead-man switch URL.
+// Runs stopped by a shutdown are not reported, they are neither a success nor a failure of the job.
+// sendBackupPing 将本次运行结果上报到配置的失联告警地址。
+// 因关闭服务而停止的运行不上报，它既不是任务成功也不是任务失败。
+func (s *backupService) sendBackupPing(config *domain.BackupConfig) {
+	switch config.LastStatus {
+	case domain.BackupStatusSuccess, domain.BackupStatusNoUpdate:
+		healthping.Go(s.logger, config.PingURL, healthping.SignalSuccess, config.LastMessage)
+	case domain.BackupStatusFailed:
+		healthping.Go(s.logger, config.PingURL, healthping.SignalFail, config.LastMessage)
+	}
+}
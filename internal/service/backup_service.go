@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -15,15 +16,23 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/internal/config"
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/alert"
 	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/healthping"
+	"github.com/haierkeys/fast-note-sync-service/pkg/maintenance"
+	"github.com/haierkeys/fast-note-sync-service/pkg/redact"
 	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
 	"github.com/haierkeys/fast-note-sync-service/pkg/storage"
 	pkgstorage "github.com/haierkeys/fast-note-sync-service/pkg/storage"
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/tracer"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
 var errNoUpdates = errors.New("no updates found")
@@ -45,17 +54,37 @@ type BackupService interface {
 	ExecuteUserBackup(ctx context.Context, uid int64, configID int64) error
 	ExecuteTaskBackups(ctx context.Context) error
 	NotifyUpdated(uid int64)
+	ExportZip(ctx context.Context, uid int64, params *dto.VaultExportRequest, modTime time.Time, w io.Writer) error
+	ExportArtifact(ctx context.Context, uid int64, params *dto.VaultExportRequest) (string, time.Time, error)
+	// ExportSite Render the vault, or one of its folders, to a static HTML site written to a server directory or a storage target
+	// ExportSite 将保险库或其中某个文件夹渲染为静态 HTML 站点，写入服务器目录或存储目标
+	ExportSite(ctx context.Context, uid int64, params *dto.VaultSiteExportRequest) (*dto.VaultSiteExportResult, error)
+	SetProgressHandler(handler func(uid int64, msg *dto.BackupProgressMessage))
+	// SetMaintenance Set the maintenance window scheduled full backups wait for
+	// SetMaintenance 设置定时全量备份需等待的维护窗口
+	SetMaintenance(coordinator *maintenance.Coordinator)
+	// SetNotifier Set the notifier backup outcomes are reported to
+	// SetNotifier 设置接收备份结果的通知器
+	SetNotifier(notifier Notifier)
+	// SetAlerter Set the alerter failed runs are reported to
+	// SetAlerter 设置接收执行失败告警的告警器
+	SetAlerter(alerter Alerter)
+	// Verify Re-download a backup and check it against the recorded size and checksum, optionally test-extracting it
+	// Verify 重新下载备份并与记录的大小和校验和比对，可选试解压
+	Verify(ctx context.Context, uid int64, historyID int64, extract bool) (*dto.BackupVerifyDTO, error)
 	Shutdown(ctx context.Context) error
 }
 
 type backupService struct {
 	backupRepo     domain.BackupRepository
+	blobRepo       domain.BackupBlobRepository
 	noteRepo       domain.NoteRepository
 	folderRepo     domain.FolderRepository
 	fileRepo       domain.FileRepository
 	vaultRepo      domain.VaultRepository
 	storageService StorageService
 	storageConfig  *config.StorageConfig
+	redactor       *redact.Redactor
 	tempPath       string
 	logger         *zap.Logger
 	syncTimers     map[int64]*time.Timer
@@ -66,18 +95,25 @@ type backupService struct {
 	pendingSyncs   sync.Map                     // key: uid (int64), value: bool
 	runningTasks   map[int64]context.CancelFunc // key: configID
 	runningMu      sync.Mutex
+	progressMu     sync.RWMutex
+	progress       func(uid int64, msg *dto.BackupProgressMessage)
+	maintenance    *maintenance.Coordinator
+	notifier       Notifier
+	alerter        Alerter
 }
 
 // NewBackupService creates BackupService instance
 // 创建 BackupService 实例
 func NewBackupService(
 	backupRepo domain.BackupRepository,
+	blobRepo domain.BackupBlobRepository,
 	noteRepo domain.NoteRepository,
 	folderRepo domain.FolderRepository,
 	fileRepo domain.FileRepository,
 	vaultRepo domain.VaultRepository,
 	storageService StorageService,
 	storageConfig *config.StorageConfig,
+	redactor *redact.Redactor,
 	tempPath string,
 	logger *zap.Logger,
 ) BackupService {
@@ -87,12 +123,14 @@ func NewBackupService(
 	ctx, cancel := context.WithCancel(context.Background())
 	s := &backupService{
 		backupRepo:     backupRepo,
+		blobRepo:       blobRepo,
 		noteRepo:       noteRepo,
 		folderRepo:     folderRepo,
 		fileRepo:       fileRepo,
 		vaultRepo:      vaultRepo,
 		storageService: storageService,
 		storageConfig:  storageConfig,
+		redactor:       redactor,
 		tempPath:       tempPath,
 		logger:         logger,
 		syncTimers:     make(map[int64]*time.Timer),
@@ -124,7 +162,36 @@ func NewBackupService(
 // backupStagingDir 返回用于暂存备份工作目录和 zip 文件的目录。位于应用配置的临时路径下
 // （而非容器的 /tmp），可在启动时清理，不会泄漏进 docker 持久化的 overlay 层。
 func (s *backupService) backupStagingDir() string {
-	return filepath.Join(s.tempPath, "backup")
+	return backupStagingRoot(s.tempPath)
+}
+
+// backupStagingRoot returns the backup staging directory below a temp path
+// backupStagingRoot 返回临时路径下的备份暂存目录
+func backupStagingRoot(tempPath string) string {
+	if tempPath == "" {
+		tempPath = "storage/temp"
+	}
+	return filepath.Join(tempPath, "backup")
+}
+
+// backupStagingOwner returns the user of a staging entry: backup_<uid>_* working dirs,
+// backup_<type>_<uid>_*.zip archives and export_<uid>_*.zip artifacts
+// backupStagingOwner 返回暂存条目所属的用户：backup_<uid>_* 工作目录、backup_<type>_<uid>_*.zip 压缩包
+// 与 export_<uid>_*.zip 导出产物
+func backupStagingOwner(name string) (int64, bool) {
+	parts := strings.Split(name, "_")
+	if len(parts) < 3 || (parts[0] != "backup" && parts[0] != "export") {
+		return 0, false
+	}
+	if uid, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+		return uid, true
+	}
+	if parts[0] == "backup" && len(parts) > 3 {
+		if uid, err := strconv.ParseInt(parts[2], 10, 64); err == nil {
+			return uid, true
+		}
+	}
+	return 0, false
 }
 
 // GetConfigs Get user's backup configurations
@@ -188,6 +255,9 @@ func (s *backupService) UpdateConfig(ctx context.Context, uid int64, req *dto.Ba
 		RetentionDays:    retentionDays,
 		PasswordMode:     req.PasswordMode,
 		PasswordValue:    req.PasswordValue,
+		WebhookURL:       req.WebhookURL,
+		WebhookSecret:    req.WebhookSecret,
+		PingURL:          req.PingURL,
 	}
 
 	// Preserve state fields if updating existing config
@@ -270,6 +340,9 @@ func (s *backupService) configToDTO(ctx context.Context, d *domain.BackupConfig)
 		RetentionDays:    d.RetentionDays,
 		PasswordMode:     d.PasswordMode,
 		PasswordValue:    d.PasswordValue,
+		WebhookURL:       d.WebhookURL,
+		WebhookSecret:    d.WebhookSecret,
+		PingURL:          d.PingURL,
 		LastRunTime:      timex.Time(d.LastRunTime),
 		NextRunTime:      timex.Time(d.NextRunTime),
 		LastStatus:       d.LastStatus,
@@ -284,21 +357,23 @@ func (s *backupService) historyToDTO(d *domain.BackupHistory) *dto.BackupHistory
 		return nil
 	}
 	return &dto.BackupHistoryDTO{
-		ID:        d.ID,
-		UID:       d.UID,
-		ConfigID:  d.ConfigID,
-		StorageID: d.StorageID,
-		Type:      d.Type,
-		StartTime: timex.Time(d.StartTime),
-		EndTime:   timex.Time(d.EndTime),
-		Status:    d.Status,
-		FileSize:  d.FileSize,
-		FileCount: d.FileCount,
-		Message:   d.Message,
-		FilePath:  d.FilePath,
-		Password:  d.Password,
-		CreatedAt: timex.Time(d.CreatedAt),
-		UpdatedAt: timex.Time(d.UpdatedAt),
+		ID:          d.ID,
+		UID:         d.UID,
+		ConfigID:    d.ConfigID,
+		StorageID:   d.StorageID,
+		Type:        d.Type,
+		StartTime:   timex.Time(d.StartTime),
+		EndTime:     timex.Time(d.EndTime),
+		Status:      d.Status,
+		FileSize:    d.FileSize,
+		FileCount:   d.FileCount,
+		Message:     d.Message,
+		FilePath:    d.FilePath,
+		Password:    d.Password,
+		ArchiveSize: d.ArchiveSize,
+		Checksum:    d.Checksum,
+		CreatedAt:   timex.Time(d.CreatedAt),
+		UpdatedAt:   timex.Time(d.UpdatedAt),
 	}
 }
 
@@ -363,6 +438,19 @@ func (s *backupService) ExecuteTaskBackups(ctx context.Context) error {
 			shouldTrigger = true
 		}
 
+		// Scheduled full backups are heavy, outside the maintenance window they are queued for the next one
+		// 定时全量备份属于重型任务，维护窗口外排队等待下一个窗口
+		if shouldTrigger && isScheduled && config.Type == "full" {
+			key := strconv.FormatInt(config.ID, 10)
+			if !s.maintenance.Allow(false) {
+				s.maintenance.Defer("backup", key, fmt.Sprintf("Full backup of config %d", config.ID), config.UID, func(ctx context.Context) error {
+					return s.handleBackupSync(s.ctx, config, false)
+				})
+				continue
+			}
+			s.maintenance.Done("backup", key)
+		}
+
 		if shouldTrigger {
 			s.logger.Info("Triggering backup task",
 				zap.Int64("uid", config.UID),
@@ -475,7 +563,7 @@ func (s *backupService) handleBackupSync(ctx context.Context, config *domain.Bac
 
 	shouldRun := false
 	switch config.Type {
-	case "full":
+	case "full", "dedupe":
 		shouldRun = true
 	case "incremental", "sync":
 		// Exception: If it's the first run (prevRunTime is zero), we must execute to create a base backup.
@@ -498,6 +586,7 @@ func (s *backupService) handleBackupSync(ctx context.Context, config *domain.Bac
 	// 2. 设置运行状态 (Running)
 	config.LastStatus = domain.BackupStatusRunning
 	s.backupRepo.SaveConfig(taskCtx, config, uid)
+	healthping.Go(s.logger, config.PingURL, healthping.SignalStart, "")
 
 	// 3. Prepare temporary working directory
 	// 3. 准备临时工作目录
@@ -520,6 +609,8 @@ func (s *backupService) handleBackupSync(ctx context.Context, config *domain.Bac
 		fileCount, fileSize, backupErr = s.runArchive(taskCtx, config, tempDir, startTime, prevRunTime)
 	case "incremental":
 		fileCount, fileSize, backupErr = s.runArchive(taskCtx, config, tempDir, startTime, prevRunTime)
+	case "dedupe":
+		fileCount, fileSize, backupErr = s.runDedupe(taskCtx, config, startTime)
 	case "sync":
 		backupErr = s.runSync(taskCtx, config, startTime, prevRunTime)
 	}
@@ -584,6 +675,10 @@ func (s *backupService) runArchive(ctx context.Context, config *domain.BackupCon
 	if err := util.ZipWithPassword(tempDir, zipPath, password); err != nil {
 		return 0, 0, err
 	}
+	checksum, err := fileChecksum(zipPath)
+	if err != nil {
+		return 0, 0, err
+	}
 
 	// 3. Upload to all storage targets
 	// 3. 上传到所有存储目标
@@ -592,17 +687,17 @@ func (s *backupService) runArchive(ctx context.Context, config *domain.BackupCon
 		return count, size, code.ErrorBackupStorageIDInvalid
 	}
 
-	for _, sid := range storageIds {
-		st, err := s.storageService.Get(ctx, uid, sid)
-		if err != nil {
-			s.logger.Warn("Failed to get storage config, skipping", zap.Int64("sid", sid), zap.Error(err))
-			continue
-		}
-		if !st.IsEnabled {
-			s.logger.Info("Storage is disabled, skipping", zap.Int64("sid", sid))
-			continue
+	targets, uploadErrors := s.enabledTargets(ctx, config, storageIds, startTime)
+	var mu sync.Mutex
+	s.forEachTarget(targets, func(st *dto.StorageDTO) {
+		if err := s.uploadArchive(ctx, uid, config.ID, st, zipPath, zipName, config.Type, password, checksum, startTime, count, size); err != nil {
+			mu.Lock()
+			uploadErrors = append(uploadErrors, fmt.Sprintf("storage %d (%s): %v", st.ID, st.Type, err))
+			mu.Unlock()
 		}
-		s.uploadArchive(ctx, uid, config.ID, st, zipPath, zipName, config.Type, password, startTime, count, size)
+	})
+	if len(uploadErrors) > 0 {
+		return count, size, fmt.Errorf("upload errors: %s", strings.Join(uploadErrors, "; "))
 	}
 
 	return count, size, nil
@@ -629,30 +724,157 @@ func (s *backupService) runSync(ctx context.Context, config *domain.BackupConfig
 		return errNoUpdates
 	}
 
-	var syncErrors []string
+	targets, syncErrors := s.enabledTargets(ctx, config, storageIds, startTime)
+	var mu sync.Mutex
+	s.forEachTarget(targets, func(st *dto.StorageDTO) {
+		if st.Type == storage.LOCAL {
+			st.CustomPath = filepath.Join(strconv.FormatInt(config.UID, 10), strconv.FormatInt(config.VaultID, 10), st.CustomPath)
+		}
+		if _, err := s.syncFiles(ctx, config.UID, config.VaultID, config.ID, st, startTime, lastRun, config.IncludeVaultName); err != nil {
+			s.logger.Warn("Sync to storage failed", zap.Int64("sid", st.ID), zap.String("type", st.Type), zap.Error(err))
+			mu.Lock()
+			syncErrors = append(syncErrors, fmt.Sprintf("storage %d (%s): %v", st.ID, st.Type, err))
+			mu.Unlock()
+		}
+	})
+	if len(syncErrors) > 0 {
+		return fmt.Errorf("sync errors: %s", strings.Join(syncErrors, "; "))
+	}
+	return nil
+}
+
+// enabledTargets Load the enabled storage targets of a config.
+// A target whose configuration cannot be loaded gets its own failed history row and error entry, disabled targets are skipped.
+// 加载配置中已启用的存储目标。
+// 无法加载配置的目标会单独记录一条失败历史并返回错误条目，已禁用的目标被跳过。
+func (s *backupService) enabledTargets(ctx context.Context, config *domain.BackupConfig, storageIds []int64, startTime time.Time) ([]*dto.StorageDTO, []string) {
+	var targets []*dto.StorageDTO
+	var errs []string
 	for _, sid := range storageIds {
 		st, err := s.storageService.Get(ctx, config.UID, sid)
+		if err == nil && st == nil {
+			err = code.ErrorStorageNotFound
+		}
 		if err != nil {
 			s.logger.Warn("Failed to get storage config, skipping", zap.Int64("sid", sid), zap.Error(err))
-			syncErrors = append(syncErrors, fmt.Sprintf("storage %d: config error: %v", sid, err))
+			errs = append(errs, fmt.Sprintf("storage %d: config error: %v", sid, err))
+			h := &domain.BackupHistory{UID: config.UID, ConfigID: config.ID, StorageID: sid, Type: config.Type, StartTime: startTime}
+			s.updateHistory(ctx, h, domain.BackupStatusFailed, fmt.Sprintf("Storage config error: %v", err))
+			s.notifyProgress(config.UID, &dto.BackupProgressMessage{ConfigID: config.ID, StorageID: sid, Type: config.Type, Status: domain.BackupStatusFailed, Message: h.Message})
 			continue
 		}
 		if !st.IsEnabled {
 			s.logger.Info("Storage is disabled, skipping", zap.Int64("sid", sid))
 			continue
 		}
-		if st.Type == storage.LOCAL {
-			st.CustomPath = filepath.Join(strconv.FormatInt(config.UID, 10), strconv.FormatInt(config.VaultID, 10), st.CustomPath)
-		}
-		if _, err := s.syncFiles(ctx, config.UID, config.VaultID, config.ID, st, startTime, lastRun, config.IncludeVaultName); err != nil {
-			s.logger.Warn("Sync to storage failed", zap.Int64("sid", sid), zap.String("type", st.Type), zap.Error(err))
-			syncErrors = append(syncErrors, fmt.Sprintf("storage %d (%s): %v", sid, st.Type, err))
-		}
+		targets = append(targets, st)
 	}
-	if len(syncErrors) > 0 {
-		return fmt.Errorf("sync errors: %s", strings.Join(syncErrors, "; "))
+	return targets, errs
+}
+
+// uploadConcurrency Number of storage targets uploaded to at the same time
+// uploadConcurrency 同时上传的存储目标数
+func (s *backupService) uploadConcurrency() int {
+	if s.storageConfig == nil || s.storageConfig.UploadConcurrency <= 0 {
+		return 3
+	}
+	return s.storageConfig.UploadConcurrency
+}
+
+// forEachTarget Run fn for every target on a bounded worker pool, a slow target no longer blocks the others
+// forEachTarget 在有界的 worker 池中对每个目标执行 fn，慢速目标不再阻塞其他目标
+func (s *backupService) forEachTarget(targets []*dto.StorageDTO, fn func(st *dto.StorageDTO)) {
+	var g errgroup.Group
+	g.SetLimit(s.uploadConcurrency())
+	for _, st := range targets {
+		g.Go(func() error {
+			fn(st)
+			return nil
+		})
+	}
+	_ = g.Wait()
+}
+
+// SetProgressHandler Set the receiver of per-target progress messages, e.g. the WebSocket broadcaster
+// SetProgressHandler 设置单个存储目标进度消息的接收方，例如 WebSocket 广播
+func (s *backupService) SetProgressHandler(handler func(uid int64, msg *dto.BackupProgressMessage)) {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	s.progress = handler
+}
+
+// SetMaintenance Set the maintenance window scheduled full backups wait for, nil runs them when due
+// SetMaintenance 设置定时全量备份需等待的维护窗口，为 nil 时到期即执行
+func (s *backupService) SetMaintenance(coordinator *maintenance.Coordinator) {
+	s.maintenance = coordinator
+}
+
+// SetNotifier Set the notifier backup outcomes are reported to, nil disables notifications
+// SetNotifier 设置接收备份结果的通知器，为 nil 时不通知
+func (s *backupService) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
+// SetAlerter Set the alerter failed runs are reported to, nil disables alerts
+// SetAlerter 设置接收执行失败告警的告警器，为 nil 时不告警
+func (s *backupService) SetAlerter(alerter Alerter) {
+	s.alerter = alerter
+}
+
+// alertResult Alert on a failed run so silent breakage gets noticed, a successful run ends the alert cooldown
+// alertResult 执行失败时发送告警以免故障无人察觉，执行成功时结束告警冷却
+func (s *backupService) alertResult(config *domain.BackupConfig, startTime time.Time) {
+	if s.alerter == nil {
+		return
+	}
+	key := "backup:" + strconv.FormatInt(config.ID, 10)
+	switch config.LastStatus {
+	case domain.BackupStatusSuccess, domain.BackupStatusNoUpdate:
+		s.alerter.Resolve(config.UID, key)
+	case domain.BackupStatusFailed:
+		s.alerter.Alert(config.UID, key, alert.Message{
+			Title: fmt.Sprintf("Backup failed: %s backup #%d", config.Type, config.ID),
+			Body: fmt.Sprintf("User: %d\nVault ID: %d\nStorage IDs: %s\nStarted: %s\n\n%s",
+				config.UID, config.VaultID, config.StorageIds, startTime.Format(time.RFC3339), config.LastMessage),
+		})
+	}
+}
+
+// notifyResult Report a succeeded or failed run to the user's webhooks, runs without updates are not reported
+// notifyResult 将成功或失败的执行通知到用户的 Webhook，无更新的执行不通知
+func (s *backupService) notifyResult(config *domain.BackupConfig, fileCount, fileSize int64, startTime time.Time) {
+	if s.notifier == nil {
+		return
+	}
+	var event domain.WebhookEvent
+	switch config.LastStatus {
+	case domain.BackupStatusSuccess:
+		event = domain.WebhookEventBackupSucceeded
+	case domain.BackupStatusFailed:
+		event = domain.WebhookEventBackupFailed
+	default:
+		return
+	}
+	s.notifier.Notify(config.UID, event, map[string]any{
+		"configId":  config.ID,
+		"vaultId":   config.VaultID,
+		"type":      config.Type,
+		"message":   config.LastMessage,
+		"fileCount": fileCount,
+		"fileSize":  fileSize,
+		"duration":  time.Since(startTime).Milliseconds(),
+	})
+}
+
+// notifyProgress Report progress of one storage target
+// notifyProgress 上报单个存储目标的进度
+func (s *backupService) notifyProgress(uid int64, msg *dto.BackupProgressMessage) {
+	s.progressMu.RLock()
+	handler := s.progress
+	s.progressMu.RUnlock()
+	if handler != nil {
+		handler(uid, msg)
 	}
-	return nil
 }
 
 // finishTask Update final status and cleanup after task completion
@@ -685,6 +907,10 @@ func (s *backupService) finishTask(ctx context.Context, config *domain.BackupCon
 
 	s.calculateNextRunTime(config)
 	s.backupRepo.SaveConfig(saveCtx, config, config.UID)
+	s.sendBackupReport(saveCtx, config, fileCount, fileSize, startTime)
+	s.sendBackupPing(config)
+	s.notifyResult(config, fileCount, fileSize, startTime)
+	s.alertResult(config, startTime)
 
 	if config.RetentionDays != 0 {
 		var cutoffTime time.Time
@@ -733,6 +959,12 @@ func (s *backupService) finishTask(ctx context.Context, config *domain.BackupCon
 			if err := s.backupRepo.DeleteOldHistory(saveCtx, config.UID, config.ID, cutoffTime); err != nil {
 				s.logger.Error("Failed to delete old backup history records from database", zap.Error(err))
 			}
+
+			// 4. Delete blobs only referenced by the removed manifests
+			// 4. 删除仅被已移除清单引用的数据块
+			if config.Type == "dedupe" {
+				s.pruneDedupeBlobs(saveCtx, config, cutoffTime)
+			}
 		}
 	}
 
@@ -795,7 +1027,7 @@ func (s *backupService) exportArchiveFiles(ctx context.Context, uid, vaultID int
 
 // uploadArchive Upload the archived ZIP file to specified storage target
 // 将打包好的 ZIP 文件上传到指定的存储目标
-func (s *backupService) uploadArchive(ctx context.Context, uid, configId int64, stDTO *dto.StorageDTO, filePath, fileName, bType, password string, startTime time.Time, count, size int64) {
+func (s *backupService) uploadArchive(ctx context.Context, uid, configId int64, stDTO *dto.StorageDTO, filePath, fileName, bType, password, checksum string, startTime time.Time, count, size int64) error {
 	h := &domain.BackupHistory{
 		UID:       uid,
 		ConfigID:  configId,
@@ -807,42 +1039,119 @@ func (s *backupService) uploadArchive(ctx context.Context, uid, configId int64,
 		FileSize:  size,
 		FilePath:  fileName,
 		Password:  password,
+		Checksum:  checksum,
+	}
+	progress := &dto.BackupProgressMessage{ConfigID: configId, StorageID: stDTO.ID, StorageType: stDTO.Type, Type: bType, Status: domain.BackupStatusRunning}
+	fail := func(msg string) error {
+		s.updateHistory(ctx, h, domain.BackupStatusFailed, msg)
+		progress.Status, progress.Message = domain.BackupStatusFailed, msg
+		s.notifyProgress(uid, progress)
+		return errors.New(msg)
 	}
 
 	h, err := s.backupRepo.CreateHistory(ctx, h, uid)
 	if err != nil {
 		s.logger.Error("Failed to create backup history", zap.Error(err))
-		return
+		return err
 	}
 
 	client, err := s.getStorageClient(ctx, uid, stDTO)
 	if err != nil {
-		s.updateHistory(ctx, h, domain.BackupStatusFailed, err.Error())
-		return
+		return fail(err.Error())
 	}
 
 	f, err := os.Open(filePath)
 	if err != nil {
-		s.updateHistory(ctx, h, domain.BackupStatusFailed, fmt.Sprintf("Failed to open backup file: %v", err))
-		return
+		return fail(fmt.Sprintf("Failed to open backup file: %v", err))
 	}
 	defer f.Close()
 
-	_, err = client.SendFile(fileName, f, "application/zip", startTime)
+	if info, err := f.Stat(); err == nil {
+		progress.Total = info.Size()
+		h.ArchiveSize = info.Size()
+	}
+	s.notifyProgress(uid, progress)
+
+	reader := &progressReader{file: f, report: func(sent int64) {
+		msg := *progress
+		msg.Processed = sent
+		s.notifyProgress(uid, &msg)
+	}}
+	_, span := tracer.Start(ctx, "storage.SendFile", tracer.UID(uid), tracer.StorageType(stDTO.Type),
+		attribute.Int64("storage.size", progress.Total),
+	)
+	_, err = client.SendFile(fileName, reader, "application/zip", startTime)
+	tracer.End(span, err)
 	if err != nil {
-		s.updateHistory(ctx, h, domain.BackupStatusFailed, fmt.Sprintf("Upload failed: %v", err))
-		return
+		return fail(fmt.Sprintf("Upload failed: %v", err))
 	}
 
-	s.updateHistory(ctx, h, domain.BackupStatusSuccess, "Success")
+	msg := successMessage(client)
+	s.updateHistory(ctx, h, domain.BackupStatusSuccess, msg)
+	progress.Status, progress.Processed, progress.Message = domain.BackupStatusSuccess, progress.Total, msg
+	s.notifyProgress(uid, progress)
+	return nil
+}
+
+// backupProgressEvery Number of synced files between two progress messages of one target
+// backupProgressEvery 同一目标两次进度消息之间同步的文件数
+const backupProgressEvery = 50
+
+// backupProgressInterval Minimum interval between two progress messages of one target
+// backupProgressInterval 同一目标两次进度消息之间的最小间隔
+const backupProgressInterval = time.Second
+
+// progressReader Reader of an archive upload reporting the bytes sent; Seek keeps it rewindable for retries
+// progressReader 上报已发送字节数的归档上传读取器；实现 Seek 以便重试时回绕
+type progressReader struct {
+	file   *os.File
+	sent   int64
+	last   time.Time
+	report func(sent int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.file.Read(p)
+	r.sent += int64(n)
+	if now := time.Now(); now.Sub(r.last) >= backupProgressInterval {
+		r.last = now
+		r.report(r.sent)
+	}
+	return n, err
+}
+
+func (r *progressReader) Seek(offset int64, whence int) (int64, error) {
+	pos, err := r.file.Seek(offset, whence)
+	if err == nil {
+		r.sent = pos
+	}
+	return pos, err
 }
 
 // syncFiles Sync file changes to specified storage target (supports add, modify, delete)
 // returns (hasChanges, error)
 // 将文件变更同步到指定的存储目标 (支持新增、修改和删除)
 func (s *backupService) syncFiles(ctx context.Context, uid, vaultID, configId int64, stDTO *dto.StorageDTO, startTime time.Time, lastRun time.Time, includeVaultName bool) (bool, error) {
+	ctx, span := tracer.Start(ctx, "BackupService.syncFiles", tracer.UID(uid), tracer.VaultID(vaultID))
+	defer span.End()
+	if stDTO != nil {
+		span.SetAttributes(tracer.StorageType(stDTO.Type))
+	}
+
 	var h *domain.BackupHistory
 	var client pkgstorage.Storager
+	var progress *dto.BackupProgressMessage
+
+	// finish records the final status of the target, a no-op when only checking for changes
+	// finish 记录目标的最终状态，仅检查变更时不做任何事
+	finish := func(status int, msg string) {
+		if h == nil {
+			return
+		}
+		s.updateHistory(ctx, h, status, msg)
+		progress.Status, progress.Processed, progress.Message = status, h.FileCount, msg
+		s.notifyProgress(uid, progress)
+	}
 
 	if stDTO != nil {
 		h = &domain.BackupHistory{
@@ -861,31 +1170,28 @@ func (s *backupService) syncFiles(ctx context.Context, uid, vaultID, configId in
 			return false, err
 		}
 
+		progress = &dto.BackupProgressMessage{ConfigID: configId, StorageID: stDTO.ID, StorageType: stDTO.Type, Type: "sync", Status: domain.BackupStatusRunning}
+		s.notifyProgress(uid, progress)
+
 		client, err = s.getStorageClient(ctx, uid, stDTO)
 		if err != nil {
-			s.updateHistory(ctx, h, domain.BackupStatusFailed, err.Error())
+			finish(domain.BackupStatusFailed, err.Error())
 			return false, err
 		}
 	}
 
 	if vaultID <= 0 {
-		if h != nil {
-			s.updateHistory(ctx, h, domain.BackupStatusFailed, code.ErrorBackupVaultRequired.Msg())
-		}
+		finish(domain.BackupStatusFailed, code.ErrorBackupVaultRequired.Msg())
 		return false, code.ErrorBackupVaultRequired
 	}
 
 	vault, err := s.vaultRepo.GetByID(ctx, vaultID, uid)
 	if err != nil {
-		if h != nil {
-			s.updateHistory(ctx, h, domain.BackupStatusFailed, err.Error())
-		}
+		finish(domain.BackupStatusFailed, err.Error())
 		return false, err
 	}
 	if vault == nil {
-		if h != nil {
-			s.updateHistory(ctx, h, domain.BackupStatusFailed, code.ErrorVaultNotFound.Msg())
-		}
+		finish(domain.BackupStatusFailed, code.ErrorVaultNotFound.Msg())
 		return false, code.ErrorVaultNotFound
 	}
 
@@ -916,7 +1222,7 @@ func (s *backupService) syncFiles(ctx context.Context, uid, vaultID, configId in
 		if isNote {
 			_, sendErr = client.SendContent(objName, content, mtime)
 		} else {
-			if f, err := os.Open(localPath); err == nil {
+			if f, err := atrest.Open(localPath); err == nil {
 				_, sendErr = client.SendFile(objName, f, "application/octet-stream", mtime)
 				f.Close()
 			} else {
@@ -931,14 +1237,17 @@ func (s *backupService) syncFiles(ctx context.Context, uid, vaultID, configId in
 		} else {
 			totalCount++
 			totalSize += localSize
+			if totalCount%backupProgressEvery == 0 {
+				msg := *progress
+				msg.Processed = totalCount
+				s.notifyProgress(uid, &msg)
+			}
 		}
 		return nil
 	})
 
 	if err != nil {
-		if h != nil {
-			s.updateHistory(ctx, h, domain.BackupStatusFailed, err.Error())
-		}
+		finish(domain.BackupStatusFailed, err.Error())
 		return hasChanges, err
 	}
 
@@ -946,12 +1255,12 @@ func (s *backupService) syncFiles(ctx context.Context, uid, vaultID, configId in
 		h.FileCount = totalCount
 		h.FileSize = totalSize
 		if !hasChanges {
-			s.updateHistory(ctx, h, domain.BackupStatusNoUpdate, "No updates") // No updates // 无更新
+			finish(domain.BackupStatusNoUpdate, "No updates") // No updates // 无更新
 		} else if failedCount > 0 {
-			msg := fmt.Sprintf("Partial failure: %d files synced, %d files failed. Last error: %v", totalCount, failedCount, lastSendErr)
-			s.updateHistory(ctx, h, domain.BackupStatusFailed, msg)
+			msg := fmt.Sprintf("Partial failure: %d files synced, %d files failed, %d retries. Last error: %v", totalCount, failedCount, pkgstorage.Retries(client), lastSendErr)
+			finish(domain.BackupStatusFailed, msg)
 		} else {
-			s.updateHistory(ctx, h, domain.BackupStatusSuccess, "Success") // Success // 成功
+			finish(domain.BackupStatusSuccess, successMessage(client)) // Success // 成功
 		}
 	}
 
@@ -980,7 +1289,7 @@ func (s *backupService) forEachResource(ctx context.Context, uid int64, v *domai
 	} else {
 		// List notes // 列出笔记
 		// List(ctx, vaultID, page, pageSize, uid, keyword, isDeleted, sort, isAsc, tag, folder)
-		notes, err = s.noteRepo.List(ctx, v.ID, 1, 1000000, uid, "", false, "", false, "", "", nil)
+		notes, err = s.noteRepo.List(ctx, v.ID, 1, 1000000, uid, "", false, "", false, "", "", nil, nil)
 	}
 
 	if err != nil {
@@ -994,7 +1303,13 @@ func (s *backupService) forEachResource(ctx context.Context, uid int64, v *domai
 		if filepath.Ext(path) != ".md" {
 			path += ".md"
 		}
-		if err := action(v, path, true, []byte(n.Content), int64(len(n.Content)), "", time.UnixMilli(n.Mtime), n.IsDeleted()); err != nil {
+		// Apply export redaction rules so secrets never reach third-party storage
+		// 应用导出脱敏规则，避免敏感信息进入第三方存储
+		if s.redactor.Excluded(path) {
+			continue
+		}
+		content := s.redactor.Content([]byte(n.Content))
+		if err := action(v, path, true, content, int64(len(content)), "", time.UnixMilli(n.Mtime), n.IsDeleted()); err != nil {
 			return err
 		}
 	}
@@ -1015,11 +1330,18 @@ func (s *backupService) forEachResource(ctx context.Context, uid int64, v *domai
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
+		if s.redactor.Excluded(f.Path) {
+			continue
+		}
 		var size int64
 		// Check file existence/size if not deleted // 如果未删除，检查文件是否存在/大小
 		if !f.IsDeleted() {
-			if info, _ := os.Stat(f.SavePath); info != nil {
-				size = info.Size()
+			if err := s.fileRepo.FetchContent(ctx, f, uid); err != nil {
+				return err
+			}
+			if af, err := atrest.Open(f.SavePath); err == nil {
+				size = af.Size()
+				af.Close()
 			}
 		}
 		if err := action(v, f.Path, false, nil, size, f.SavePath, time.UnixMilli(f.Mtime), f.IsDeleted()); err != nil {
@@ -1034,20 +1356,57 @@ func (s *backupService) forEachResource(ctx context.Context, uid int64, v *domai
 // 获取并初始化存储客户端
 func (s *backupService) getStorageClient(ctx context.Context, uid int64, stDTO *dto.StorageDTO) (pkgstorage.Storager, error) {
 	sConfig := &pkgstorage.Config{
-		Type:            stDTO.Type,
-		CustomPath:      stDTO.CustomPath,
-		Endpoint:        stDTO.Endpoint,
-		Region:          stDTO.Region,
-		BucketName:      stDTO.BucketName,
-		AccessKeyID:     stDTO.AccessKeyID,
-		AccessKeySecret: stDTO.AccessKeySecret,
-		AccountID:       stDTO.AccountID,
-		User:            stDTO.User,
-		Password:        stDTO.Password,
-		SavePath:        s.storageConfig.LocalFS.SavePath,
-	}
-
-	return pkgstorage.NewClient(sConfig)
+		Type:               stDTO.Type,
+		CustomPath:         stDTO.CustomPath,
+		Endpoint:           stDTO.Endpoint,
+		Region:             stDTO.Region,
+		BucketName:         stDTO.BucketName,
+		AccessKeyID:        stDTO.AccessKeyID,
+		AccessKeySecret:    stDTO.AccessKeySecret,
+		AccountID:          stDTO.AccountID,
+		User:               stDTO.User,
+		Password:           stDTO.Password,
+		PathStyle:          stDTO.PathStyle,
+		CACert:             stDTO.CACert,
+		InsecureSkipVerify: stDTO.InsecureSkipVerify,
+		SavePath:           s.storageConfig.LocalFS.SavePath,
+	}
+
+	client, err := pkgstorage.NewClient(sConfig)
+	if err != nil {
+		return nil, err
+	}
+	return pkgstorage.NewRetryClient(client, stDTO.Type, s.retryConfig()), nil
+}
+
+// retryConfig Parse storage retry policy, invalid durations fall back to defaults
+// retryConfig 解析存储重试策略，非法的时长回退为默认值
+func (s *backupService) retryConfig() pkgstorage.RetryConfig {
+	cfg := pkgstorage.RetryConfig{MaxAttempts: 1}
+	if s.storageConfig == nil {
+		return cfg
+	}
+	rc := s.storageConfig.Retry
+	cfg.MaxAttempts = rc.MaxAttempts
+
+	cfg.BaseDelay = time.Second
+	if d, err := util.ParseDuration(rc.BaseDelay); err == nil && d > 0 {
+		cfg.BaseDelay = d
+	}
+	cfg.MaxDelay = 30 * time.Second
+	if d, err := util.ParseDuration(rc.MaxDelay); err == nil && d > 0 {
+		cfg.MaxDelay = d
+	}
+	return cfg
+}
+
+// successMessage History message of a finished upload, mentions retries when there were any
+// successMessage 上传完成时的历史记录消息，存在重试时注明重试次数
+func successMessage(client pkgstorage.Storager) string {
+	if n := pkgstorage.Retries(client); n > 0 {
+		return fmt.Sprintf("Success after %d retries", n)
+	}
+	return "Success"
 }
 
 func (s *backupService) updateHistory(ctx context.Context, h *domain.BackupHistory, status int, message string) {
@@ -3,13 +3,27 @@
 package service
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/maintenance"
+	"github.com/haierkeys/fast-note-sync-service/pkg/redact"
+	"github.com/haierkeys/fast-note-sync-service/pkg/webhook"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -50,11 +64,15 @@ func newBackupSvc(
 	vaultRepo *domainmocks.MockVaultRepository,
 	storageSvc *backupStorageStub,
 ) *backupService {
+	// Attachments are always on the local disk in these tests
+	// 这些测试中附件始终位于本地磁盘
+	fileRepo := new(domainmocks.MockFileRepository)
+	fileRepo.On("FetchContent", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
 	return &backupService{
 		backupRepo:     backupRepo,
 		noteRepo:       new(domainmocks.MockNoteRepository),
 		folderRepo:     new(domainmocks.MockFolderRepository),
-		fileRepo:       new(domainmocks.MockFileRepository),
+		fileRepo:       fileRepo,
 		vaultRepo:      vaultRepo,
 		storageService: storageSvc,
 		logger:         zap.NewNop(),
@@ -187,3 +205,248 @@ func TestBackupService_DeleteConfig_NotFound(t *testing.T) {
 	assert.Error(t, err)
 	backupRepo.AssertExpectations(t)
 }
+
+// --- exportArchiveFiles ---
+
+// TestBackupService_ExportArchiveFiles_Redaction verifies redaction rules are applied to exported notes.
+// TestBackupService_ExportArchiveFiles_Redaction 验证导出笔记时应用脱敏规则。
+func TestBackupService_ExportArchiveFiles_Redaction(t *testing.T) {
+	backupRepo := new(domainmocks.MockBackupRepository)
+	vaultRepo := new(domainmocks.MockVaultRepository)
+	vaultRepo.On("GetByID", mock.Anything, int64(100), int64(1)).Return(&domain.Vault{ID: 100, Name: "v"}, nil)
+
+	svc := newBackupSvc(backupRepo, vaultRepo, &backupStorageStub{})
+	redactor, err := redact.New(redact.Rules{
+		FrontmatterKeys: []string{"secret*"},
+		ExcludeFolders:  []string{"Private"},
+		MaskPatterns:    []string{`ghp_[A-Za-z0-9]+`},
+	})
+	assert.NoError(t, err)
+	svc.redactor = redactor
+
+	notes := []*domain.Note{
+		{Path: "Work/todo.md", Content: "---\ntitle: t\nsecret_token: x\n---\ntoken ghp_abc123\n"},
+		{Path: "Private/diary.md", Content: "dear diary"},
+	}
+	svc.noteRepo.(*domainmocks.MockNoteRepository).On("List", mock.Anything, int64(100), 1, 1000000, int64(1), "", false, "", false, "", "", []string(nil), []string(nil)).Return(notes, nil)
+	svc.fileRepo.(*domainmocks.MockFileRepository).On("List", mock.Anything, int64(100), 1, 1000000, int64(1), "", false, "", "").Return([]*domain.File{}, nil)
+
+	dir := t.TempDir()
+	count, _, err := svc.exportArchiveFiles(context.Background(), 1, 100, dir, false, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	data, err := os.ReadFile(filepath.Join(dir, "Work", "todo.md"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "secret_token")
+	assert.Contains(t, string(data), "token [REDACTED]")
+	assert.NoFileExists(t, filepath.Join(dir, "Private", "diary.md"))
+}
+
+// TestBackupService_ExportZip_FolderFilterAndArtifact verifies the folder filter and the cached artifact used for Range resumes.
+// TestBackupService_ExportZip_FolderFilterAndArtifact 验证目录筛选以及用于 Range 续传的缓存产物。
+func TestBackupService_ExportZip_FolderFilterAndArtifact(t *testing.T) {
+	backupRepo := new(domainmocks.MockBackupRepository)
+	vaultRepo := new(domainmocks.MockVaultRepository)
+	vaultRepo.On("GetByName", mock.Anything, "v", int64(1)).Return(&domain.Vault{ID: 100, Name: "v"}, nil)
+
+	svc := newBackupSvc(backupRepo, vaultRepo, &backupStorageStub{})
+	svc.tempPath = t.TempDir()
+
+	attachment := filepath.Join(t.TempDir(), "img.png")
+	assert.NoError(t, os.WriteFile(attachment, []byte("png"), 0o644))
+
+	notes := []*domain.Note{
+		{Path: "Work/todo.md", Content: "todo"},
+		{Path: "Home/list.md", Content: "list"},
+		{Path: "Work/old.md", Content: "gone", Action: domain.NoteActionDelete},
+	}
+	files := []*domain.File{{Path: "Work/img.png", SavePath: attachment}}
+	svc.noteRepo.(*domainmocks.MockNoteRepository).On("List", mock.Anything, int64(100), 1, 1000000, int64(1), "", false, "", false, "", "", []string(nil), []string(nil)).Return(notes, nil)
+	svc.fileRepo.(*domainmocks.MockFileRepository).On("List", mock.Anything, int64(100), 1, 1000000, int64(1), "", false, "", "").Return(files, nil)
+
+	params := &dto.VaultExportRequest{Vault: "v", Folder: "/Work/"}
+	modTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+	var buf bytes.Buffer
+	assert.NoError(t, svc.ExportZip(context.Background(), 1, params, modTime, &buf))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"Work/todo.md", "Work/img.png"}, names)
+
+	artifact, artifactTime, err := svc.ExportArtifact(context.Background(), 1, params)
+	assert.NoError(t, err)
+	assert.True(t, artifactTime.Equal(modTime))
+	data, err := os.ReadFile(artifact)
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), data)
+
+	_, _, err = svc.ExportArtifact(context.Background(), 1, &dto.VaultExportRequest{Vault: "v"})
+	assert.Error(t, err)
+}
+
+// TestBackupService_SendBackupReport verifies a signed report is posted to the config's webhook.
+// TestBackupService_SendBackupReport 验证签名报告被推送到配置的 Webhook。
+func TestBackupService_SendBackupReport(t *testing.T) {
+	received := make(chan *dto.BackupReport, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		assert.Equal(t, webhook.Sign("k", timestamp, body), r.Header.Get(webhook.HeaderSignature))
+		report := &dto.BackupReport{}
+		assert.NoError(t, json.Unmarshal(body, report))
+		received <- report
+	}))
+	defer ts.Close()
+
+	svc := newBackupSvc(new(domainmocks.MockBackupRepository), new(domainmocks.MockVaultRepository), &backupStorageStub{
+		storages: map[int64]*dto.StorageDTO{2: {ID: 2, Type: "s3"}},
+	})
+	config := &domain.BackupConfig{
+		ID: 7, UID: 1, Type: "full", StorageIds: "[2]",
+		LastStatus: domain.BackupStatusSuccess, LastMessage: "Backup completed successfully",
+		WebhookURL: ts.URL, WebhookSecret: "k",
+	}
+	svc.sendBackupReport(context.Background(), config, 3, 1024, time.Now().Add(-2*time.Second))
+
+	select {
+	case report := <-received:
+		assert.Equal(t, "backup.report", report.Event)
+		assert.Equal(t, int64(7), report.ConfigID)
+		assert.Equal(t, "all", report.Vault)
+		assert.True(t, report.Success)
+		assert.Equal(t, int64(3), report.FileCount)
+		assert.GreaterOrEqual(t, report.DurationMs, int64(2000))
+		assert.Equal(t, []dto.BackupReportTarget{{StorageID: 2, Type: "s3"}}, report.Targets)
+	case <-time.After(5 * time.Second):
+		t.Fatal("backup report not delivered")
+	}
+}
+
+// TestBackupService_SendBackupPing verifies finished runs ping the dead-man switch URL, failures on /fail,
+// and that runs stopped by a shutdown are not reported.
+// TestBackupService_SendBackupPing 验证运行结束后请求失联告警地址，失败时请求 /fail，因关闭服务而停止的运行不上报。
+func TestBackupService_SendBackupPing(t *testing.T) {
+	paths := make(chan string, 3)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+	}))
+	defer ts.Close()
+
+	svc := newBackupSvc(new(domainmocks.MockBackupRepository), new(domainmocks.MockVaultRepository), &backupStorageStub{})
+	config := &domain.BackupConfig{ID: 7, UID: 1, PingURL: ts.URL + "/check"}
+
+	for _, c := range []struct {
+		status int
+		want   string
+	}{
+		{domain.BackupStatusStopped, ""},
+		{domain.BackupStatusFailed, "/check/fail"},
+		{domain.BackupStatusNoUpdate, "/check"},
+	} {
+		config.LastStatus = c.status
+		svc.sendBackupPing(config)
+		if c.want == "" {
+			continue
+		}
+		select {
+		case p := <-paths:
+			assert.Equal(t, c.want, p)
+		case <-time.After(5 * time.Second):
+			t.Fatal("backup ping not delivered")
+		}
+	}
+	assert.Empty(t, paths)
+}
+
+// TestBackupService_ForEachTarget_BoundedParallel verifies targets run in parallel up to upload-concurrency.
+// TestBackupService_ForEachTarget_BoundedParallel 验证目标并行执行且不超过 upload-concurrency。
+func TestBackupService_ForEachTarget_BoundedParallel(t *testing.T) {
+	svc := newBackupSvc(new(domainmocks.MockBackupRepository), new(domainmocks.MockVaultRepository), &backupStorageStub{})
+	svc.storageConfig = &config.StorageConfig{UploadConcurrency: 2}
+
+	var targets []*dto.StorageDTO
+	for i := int64(1); i <= 5; i++ {
+		targets = append(targets, &dto.StorageDTO{ID: i})
+	}
+
+	var running, peak, done atomic.Int32
+	svc.forEachTarget(targets, func(st *dto.StorageDTO) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		running.Add(-1)
+		done.Add(1)
+	})
+
+	assert.Equal(t, int32(5), done.Load())
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+// TestBackupService_EnabledTargets_MissingStorage verifies a missing storage gets its own failed history and progress message.
+// TestBackupService_EnabledTargets_MissingStorage 验证缺失的存储单独记录失败历史与进度消息。
+func TestBackupService_EnabledTargets_MissingStorage(t *testing.T) {
+	backupRepo := new(domainmocks.MockBackupRepository)
+	svc := newBackupSvc(backupRepo, new(domainmocks.MockVaultRepository), &backupStorageStub{storages: map[int64]*dto.StorageDTO{
+		1: {ID: 1, Type: "localfs", IsEnabled: true},
+		2: {ID: 2, Type: "localfs", IsEnabled: false},
+	}})
+	backupRepo.On("CreateHistory", mock.Anything, mock.MatchedBy(func(h *domain.BackupHistory) bool {
+		return h.StorageID == 3 && h.Status == domain.BackupStatusFailed
+	}), int64(1)).Return(&domain.BackupHistory{}, nil).Once()
+
+	var messages []*dto.BackupProgressMessage
+	svc.SetProgressHandler(func(uid int64, msg *dto.BackupProgressMessage) {
+		messages = append(messages, msg)
+	})
+
+	targets, errs := svc.enabledTargets(context.Background(), &domain.BackupConfig{ID: 9, UID: 1, Type: "full"}, []int64{1, 2, 3}, time.Now())
+
+	assert.Len(t, targets, 1)
+	assert.Equal(t, int64(1), targets[0].ID)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "storage 3")
+	backupRepo.AssertExpectations(t)
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, int64(3), messages[0].StorageID)
+		assert.Equal(t, domain.BackupStatusFailed, messages[0].Status)
+	}
+}
+
+// TestBackupService_ExecuteTaskBackups_MaintenanceWindow verifies a scheduled full backup due outside the window is queued, not run.
+// TestBackupService_ExecuteTaskBackups_MaintenanceWindow 验证维护窗口外到期的定时全量备份进入队列而不执行。
+func TestBackupService_ExecuteTaskBackups_MaintenanceWindow(t *testing.T) {
+	backupRepo := new(domainmocks.MockBackupRepository)
+	svc := newBackupSvc(backupRepo, new(domainmocks.MockVaultRepository), &backupStorageStub{})
+
+	// A one hour window opening two hours from now is closed
+	// 两小时后开启的一小时窗口当前处于关闭状态
+	window, err := maintenance.ParseWindow(time.Now().UTC().Add(2*time.Hour).Format("15:04"), time.Hour, nil, "UTC")
+	assert.NoError(t, err)
+	svc.SetMaintenance(maintenance.New(window, nil))
+
+	backupRepo.On("ListEnabledConfigs", mock.Anything).Return([]*domain.BackupConfig{
+		{ID: 3, UID: 1, Type: "full", IsEnabled: true, NextRunTime: time.Now().Add(-time.Minute)},
+	}, nil)
+
+	assert.NoError(t, svc.ExecuteTaskBackups(context.Background()))
+
+	queued := svc.maintenance.Queued()
+	if assert.Len(t, queued, 1) {
+		assert.Equal(t, "backup:3", queued[0].ID)
+		assert.Equal(t, int64(1), queued[0].UID)
+	}
+	// Nothing else was called on the repository: the backup did not start
+	// 仓储没有其他调用：备份未开始
+	backupRepo.AssertExpectations(t)
+	backupRepo.AssertNotCalled(t, "SaveConfig", mock.Anything, mock.Anything, mock.Anything)
+}
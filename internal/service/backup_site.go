@@ -0,0 +1,175 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/site"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// siteAttachment an attachment copied next to the generated pages
+// siteAttachment 复制到生成页面旁的附件
+type siteAttachment struct {
+	path      string
+	localPath string
+	mtime     time.Time
+}
+
+// siteWriter stores a file of the generated site
+// siteWriter 保存生成站点中的一个文件
+type siteWriter func(name string, r io.Reader, modTime time.Time) error
+
+// dirSiteWriter writes the site below a server directory
+// dirSiteWriter 将站点写入服务器目录
+func dirSiteWriter(dir string) siteWriter {
+	return func(name string, r io.Reader, modTime time.Time) error {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		out, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, r)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		_ = os.Chtimes(target, modTime, modTime)
+		return nil
+	}
+}
+
+// ExportSite renders the vault, or one of its folders, to a static HTML site.
+// Export redaction rules apply like for backups and ZIP exports.
+// ExportSite 将保险库或其中某个文件夹渲染为静态 HTML 站点，与备份和 ZIP 导出一样应用导出脱敏规则。
+func (s *backupService) ExportSite(ctx context.Context, uid int64, params *dto.VaultSiteExportRequest) (*dto.VaultSiteExportResult, error) {
+	vault, err := s.vaultRepo.GetByName(ctx, params.Vault, uid)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if vault == nil {
+		return nil, code.ErrorVaultNotFound
+	}
+
+	folder := exportFolder(params.Folder)
+	result := &dto.VaultSiteExportResult{}
+	var write siteWriter
+	switch {
+	case params.OutDir != "":
+		result.Target = params.OutDir
+		write = dirSiteWriter(params.OutDir)
+	case params.StorageID != 0:
+		st, err := s.storageService.Get(ctx, uid, params.StorageID)
+		if err != nil {
+			return nil, err
+		}
+		if st == nil {
+			return nil, code.ErrorStorageNotFound
+		}
+		client, err := s.getStorageClient(ctx, uid, st)
+		if err != nil {
+			return nil, code.ErrorStorageValidateFailed.WithDetails(err.Error())
+		}
+		// Sites of different vaults and folders do not overwrite each other
+		// 不同保险库与目录的站点互不覆盖
+		result.Target = path.Join("site", vault.Name, folder)
+		write = func(name string, r io.Reader, modTime time.Time) error {
+			contentType := mime.TypeByExtension(path.Ext(name))
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+			_, err := client.SendFile(path.Join(result.Target, name), r, contentType, modTime)
+			return err
+		}
+	default:
+		return nil, code.ErrorInvalidParams.WithDetails("storageId or outDir is required")
+	}
+
+	title := params.Title
+	if title == "" {
+		title = vault.Name
+		if folder != "" {
+			title = path.Base(folder)
+		}
+	}
+	generator := site.New(title)
+	var attachments []siteAttachment
+
+	err = s.forEachResource(ctx, uid, vault, false, time.Time{}, func(v *domain.Vault, resPath string, isNote bool, content []byte, localSize int64, localPath string, mtime time.Time, isDeleted bool) error {
+		if isDeleted {
+			return nil
+		}
+		if folder != "" {
+			if !strings.HasPrefix(resPath, folder+"/") {
+				return nil
+			}
+			resPath = strings.TrimPrefix(resPath, folder+"/")
+		}
+		if isNote {
+			generator.AddNote(resPath, string(content), mtime)
+		} else {
+			generator.AddAttachment(resPath)
+			attachments = append(attachments, siteAttachment{path: resPath, localPath: localPath, mtime: mtime})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Pages, err = generator.Build(func(name string, data []byte, modTime time.Time) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return write(name, bytes.NewReader(data), modTime)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range attachments {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		f, err := atrest.Open(a.localPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				s.logger.Warn("Skipping site export of missing file", zap.String("path", a.path), zap.String("localPath", a.localPath))
+				continue
+			}
+			return nil, err
+		}
+		err = write(a.path, f, a.mtime)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		result.Files++
+	}
+
+	s.logger.Info("vault site export finished",
+		zap.Int64("uid", uid),
+		zap.String("vault", params.Vault),
+		zap.String("target", result.Target),
+		zap.Int("pages", result.Pages),
+		zap.Int("files", result.Files))
+	return result, nil
+}
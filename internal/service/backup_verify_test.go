@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newVerifySvc backupService storing backups in a temporary local directory
+func newVerifySvc(t *testing.T, backupRepo *domainmocks.MockBackupRepository) (*backupService, string) {
+	svc := newBackupSvc(backupRepo, new(domainmocks.MockVaultRepository), &backupStorageStub{storages: map[int64]*dto.StorageDTO{
+		1: {ID: 1, Type: "localfs", IsEnabled: true},
+	}})
+	saveDir := t.TempDir()
+	svc.storageConfig = &config.StorageConfig{LocalFS: config.StorageLocalFSConfig{SavePath: saveDir}}
+	svc.tempPath = t.TempDir()
+	return svc, saveDir
+}
+
+// TestBackupService_Verify_Archive verifies an intact archive passes and a corrupted copy is reported.
+// TestBackupService_Verify_Archive 验证完好的归档通过校验，损坏的副本被报告。
+func TestBackupService_Verify_Archive(t *testing.T) {
+	backupRepo := new(domainmocks.MockBackupRepository)
+	svc, saveDir := newVerifySvc(t, backupRepo)
+
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "a.md"), []byte("note"), 0o644))
+	zipPath := filepath.Join(saveDir, "backup.zip")
+	require.NoError(t, util.ZipWithPassword(src, zipPath, "pw"))
+	checksum, err := fileChecksum(zipPath)
+	require.NoError(t, err)
+	info, err := os.Stat(zipPath)
+	require.NoError(t, err)
+
+	backupRepo.On("GetHistory", mock.Anything, int64(5), int64(1)).Return(&domain.BackupHistory{
+		ID: 5, UID: 1, ConfigID: 9, StorageID: 1, Type: "full", Status: domain.BackupStatusSuccess,
+		FilePath: "backup.zip", Password: "pw", ArchiveSize: info.Size(), Checksum: checksum,
+	}, nil)
+
+	result, err := svc.Verify(context.Background(), 1, 5, true)
+	require.NoError(t, err)
+	assert.True(t, result.Verified, result.Message)
+	assert.True(t, result.Extracted)
+	assert.Equal(t, 1, result.FileCount)
+	assert.Equal(t, checksum, result.Checksum)
+
+	// Flip one byte of the stored copy
+	// 翻转存储副本中的一个字节
+	data, err := os.ReadFile(zipPath)
+	require.NoError(t, err)
+	data[len(data)/2] ^= 0xff
+	require.NoError(t, os.WriteFile(zipPath, data, 0o644))
+
+	result, err = svc.Verify(context.Background(), 1, 5, false)
+	require.NoError(t, err)
+	assert.False(t, result.Verified)
+	assert.True(t, strings.HasPrefix(result.Message, "Checksum mismatch"), result.Message)
+}
+
+// TestBackupService_Verify_DedupeMissingBlob verifies extracting a dedupe backup re-reads every blob.
+// TestBackupService_Verify_DedupeMissingBlob 验证试解压去重备份时会重新读取每个数据块。
+func TestBackupService_Verify_DedupeMissingBlob(t *testing.T) {
+	backupRepo := new(domainmocks.MockBackupRepository)
+	svc, saveDir := newVerifySvc(t, backupRepo)
+
+	blob := []byte("same content")
+	hash := sha256Hex(blob)
+	manifest := []byte(`{"version":1,"files":[{"path":"a.md","size":12,"blobs":["` + hash + `"]}]}`)
+	blobPath := filepath.Join(saveDir, dedupeBlobKey(1, 9, hash))
+	require.NoError(t, os.MkdirAll(filepath.Dir(blobPath), 0o755))
+	require.NoError(t, os.WriteFile(blobPath, blob, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(saveDir, "manifest.json"), manifest, 0o644))
+
+	backupRepo.On("GetHistory", mock.Anything, int64(6), int64(1)).Return(&domain.BackupHistory{
+		ID: 6, UID: 1, ConfigID: 9, StorageID: 1, Type: "dedupe", Status: domain.BackupStatusSuccess,
+		FilePath: "manifest.json", ArchiveSize: int64(len(manifest)), Checksum: sha256Hex(manifest),
+	}, nil)
+
+	result, err := svc.Verify(context.Background(), 1, 6, true)
+	require.NoError(t, err)
+	assert.True(t, result.Verified, result.Message)
+	assert.Equal(t, 1, result.FileCount)
+
+	require.NoError(t, os.Remove(blobPath))
+	result, err = svc.Verify(context.Background(), 1, 6, true)
+	require.NoError(t, err)
+	assert.False(t, result.Verified)
+	assert.Contains(t, result.Message, "Extraction failed")
+}
+
+// TestBackupService_Verify_Unsupported verifies sync and failed records are rejected.
+// TestBackupService_Verify_Unsupported 验证同步记录与失败记录被拒绝。
+func TestBackupService_Verify_Unsupported(t *testing.T) {
+	backupRepo := new(domainmocks.MockBackupRepository)
+	svc, _ := newVerifySvc(t, backupRepo)
+	backupRepo.On("GetHistory", mock.Anything, int64(7), int64(1)).Return(&domain.BackupHistory{ID: 7, Type: "sync", Status: domain.BackupStatusSuccess}, nil)
+	backupRepo.On("GetHistory", mock.Anything, int64(8), int64(1)).Return(nil, nil)
+
+	_, err := svc.Verify(context.Background(), 1, 7, false)
+	assert.ErrorIs(t, err, code.ErrorBackupVerifyUnsupported)
+	_, err = svc.Verify(context.Background(), 1, 8, false)
+	assert.ErrorIs(t, err, code.ErrorBackupHistoryNotFound)
+}
@@ -2,6 +2,14 @@
 // Package service 实现业务逻辑层
 package service
 
+import (
+	"context"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/jobqueue"
+	"github.com/haierkeys/fast-note-sync-service/pkg/synctrace"
+	"go.uber.org/zap"
+)
+
 // ServiceConfig service layer configuration
 // ServiceConfig 服务层配置
 type ServiceConfig struct {
@@ -13,8 +21,14 @@ type ServiceConfig struct {
 // UserServiceConfig user service configuration
 // UserServiceConfig 用户服务配置
 type UserServiceConfig struct {
-	RegisterIsEnable bool // Whether registration is enabled // 注册是否启用
-	AdminUID         int  // Admin UID, 0 means no restriction // 管理员 UID，0 表示不限制
+	RegisterIsEnable bool   // Whether registration is enabled // 注册是否启用
+	AdminUID         int    // Admin UID, 0 means no restriction // 管理员 UID，0 表示不限制
+	RequireAdminTOTP bool   // Whether the admin must enroll 2FA // 是否要求管理员启用两步验证
+	TOTPIssuer       string // Issuer name shown in authenticator apps // 身份验证器应用中显示的签发方名称
+	RegisterApproval bool   // Whether open registrations wait for admin approval // 开放注册是否需等待管理员审核
+
+	PasswordResetExpiry string // Validity of password reset links (e.g. 30m, 2h) // 密码重置链接有效期（如 30m、2h）
+	PasswordResetURL    string // Public server URL the reset links point to, empty disables resetting by email // 重置链接指向的服务器公网地址，为空时禁用邮件重置
 }
 
 // TokenServiceConfig token service configuration for WebGUI auto-issued login tokens
@@ -32,6 +46,10 @@ type AppServiceConfig struct {
 	HistorySaveDelay        string                 // History save delay (e.g., 10s, 1m, default 10s) // 历史记录保存延迟时间（支持格式：10s、1m，默认 10s）
 	ShareTokenExpiry        string                 // Share token expiry // 分享 Token 过期时间
 	ShortLink               ShortLinkServiceConfig // Short link configuration // 短链配置
+	TempPath                string                 // Temporary file path // 临时文件路径
+	Limits                  *ContentLimits         // Note and attachment limits, adjustable at runtime // 笔记与附件限制，可在运行时调整
+	SyncTrace               *synctrace.Tracer      // Per-user sync tracing switched on by the admin, may be nil // 由管理员开启的按用户同步跟踪，可能为 nil
+	Jobs                    *jobqueue.Queue        // Background job queue, may be nil // 后台任务队列，可能为 nil
 }
 
 // ShortLinkServiceConfig short link service configuration
@@ -43,3 +61,58 @@ type ShortLinkServiceConfig struct {
 	Cloaking bool   // Cloaking // 遮盖
 }
 
+// syncTrace returns the sync trace logger of uid, nil when the user is not traced
+// syncTrace 返回 uid 的同步跟踪日志器，用户未被跟踪时为 nil
+func (c *ServiceConfig) syncTrace(uid int64) *zap.Logger {
+	if c == nil {
+		return nil
+	}
+	return c.App.SyncTrace.Logger(uid)
+}
+
+// enqueue runs fn as a background job of kind, see jobqueue.Queue.Enqueue; without a queue, or once it is
+// shut down, fn runs in its own goroutine
+// enqueue 将 fn 作为 kind 类型的后台任务执行，参见 jobqueue.Queue.Enqueue；没有队列或队列已关闭时，fn 在独立的 goroutine 中执行
+func (c *ServiceConfig) enqueue(kind, key string, uid int64, fn func(context.Context) error) {
+	if c != nil && c.App.Jobs != nil {
+		if err := c.App.Jobs.Enqueue(kind, key, uid, fn); err == nil {
+			return
+		}
+	}
+	go fn(context.Background())
+}
+
+// updateCheckRecord the stored record an UpdateCheck compared the client against
+// updateCheckRecord UpdateCheck 用于与客户端比较的已存储记录
+type updateCheckRecord struct {
+	ID          int64
+	ContentHash string
+	Mtime       int64
+	Action      string
+}
+
+// traceUpdateCheck records an UpdateCheck decision in the sync trace; record is nil when the server
+// has nothing at the path, an empty decision means the client is up to date
+// traceUpdateCheck 在同步跟踪中记录一次 UpdateCheck 判定；服务端在该路径没有记录时 record 为 nil，
+// decision 为空表示客户端已是最新
+func traceUpdateCheck(trace *zap.Logger, method, vault, path, contentHash string, mtime int64, record *updateCheckRecord, decision string, err error) {
+	fields := []zap.Field{
+		zap.String("vault", vault),
+		zap.String("path", path),
+		zap.String("clientHash", contentHash),
+		zap.Int64("clientMtime", mtime),
+		zap.String("decision", decision),
+	}
+	if record != nil {
+		fields = append(fields,
+			zap.Int64("serverId", record.ID),
+			zap.String("serverHash", record.ContentHash),
+			zap.Int64("serverMtime", record.Mtime),
+			zap.String("serverAction", record.Action),
+		)
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	trace.Info(method, fields...)
+}
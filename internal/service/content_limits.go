@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+)
+
+// ContentLimits caps note size, attachment size and notes per vault, protecting small deployments from
+// runaway clients. The caps can be changed at runtime, 0 means no limit
+// ContentLimits 限制笔记大小、附件大小与每个笔记库的笔记数量，防止失控的客户端拖垮小型部署。
+// 限制可在运行时修改，0 表示不限制
+type ContentLimits struct {
+	maxNoteSize       atomic.Int64
+	maxAttachmentSize atomic.Int64
+	maxNotesPerVault  atomic.Int64
+}
+
+// NewContentLimits creates a ContentLimits instance
+// NewContentLimits 创建 ContentLimits 实例
+func NewContentLimits(maxNoteSize, maxAttachmentSize, maxNotesPerVault int64) *ContentLimits {
+	l := &ContentLimits{}
+	l.Set(maxNoteSize, maxAttachmentSize, maxNotesPerVault)
+	return l
+}
+
+// Set replaces the caps
+// Set 替换限制值
+func (l *ContentLimits) Set(maxNoteSize, maxAttachmentSize, maxNotesPerVault int64) {
+	l.maxNoteSize.Store(max(maxNoteSize, 0))
+	l.maxAttachmentSize.Store(max(maxAttachmentSize, 0))
+	l.maxNotesPerVault.Store(max(maxNotesPerVault, 0))
+}
+
+// MaxAttachmentSize returns the attachment cap in bytes, 0 when there is none
+// MaxAttachmentSize 返回附件大小上限（字节），不限制时为 0
+func (l *ContentLimits) MaxAttachmentSize() int64 {
+	if l == nil {
+		return 0
+	}
+	return l.maxAttachmentSize.Load()
+}
+
+// CheckNoteSize returns ErrorNoteTooLarge when a note of size bytes exceeds the cap
+// CheckNoteSize 当 size 字节的笔记超过限制时返回 ErrorNoteTooLarge
+func (l *ContentLimits) CheckNoteSize(size int64) error {
+	if l == nil {
+		return nil
+	}
+	if limit := l.maxNoteSize.Load(); limit > 0 && size > limit {
+		return code.ErrorNoteTooLarge.WithDetails(fmt.Sprintf("size %d exceeds %d bytes", size, limit))
+	}
+	return nil
+}
+
+// CheckAttachmentSize returns ErrorFileTooLarge when an attachment of size bytes exceeds the cap
+// CheckAttachmentSize 当 size 字节的附件超过限制时返回 ErrorFileTooLarge
+func (l *ContentLimits) CheckAttachmentSize(size int64) error {
+	if l == nil {
+		return nil
+	}
+	if limit := l.maxAttachmentSize.Load(); limit > 0 && size > limit {
+		return code.ErrorFileTooLarge.WithDetails(fmt.Sprintf("size %d exceeds %d bytes", size, limit))
+	}
+	return nil
+}
+
+// CheckNoteCount returns ErrorVaultNoteLimit when a vault holding count notes cannot take another one.
+// count is only called when a cap is set, so unlimited deployments skip the query
+// CheckNoteCount 当持有 count 篇笔记的笔记库无法再新增笔记时返回 ErrorVaultNoteLimit。
+// 仅在设置了限制时才调用 count，不限制时无需查询
+func (l *ContentLimits) CheckNoteCount(count func() (int64, error)) error {
+	if l == nil {
+		return nil
+	}
+	limit := l.maxNotesPerVault.Load()
+	if limit <= 0 {
+		return nil
+	}
+	n, err := count()
+	if err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if n >= limit {
+		return code.ErrorVaultNoteLimit.WithDetails(fmt.Sprintf("vault holds %d of %d notes", n, limit))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContentLimits verifies the caps reject oversized content and full vaults, and that 0 and a nil
+// instance leave everything allowed.
+// TestContentLimits 验证限制会拒绝超大内容与已满的笔记库，且 0 与 nil 实例不做任何限制。
+func TestContentLimits(t *testing.T) {
+	var none *ContentLimits
+	assert.NoError(t, none.CheckNoteSize(1<<40))
+	assert.NoError(t, none.CheckAttachmentSize(1<<40))
+	assert.NoError(t, none.CheckNoteCount(func() (int64, error) { return 1 << 40, nil }))
+
+	l := NewContentLimits(10, 100, 2)
+	assert.NoError(t, l.CheckNoteSize(10))
+	assert.ErrorIs(t, l.CheckNoteSize(11), code.ErrorNoteTooLarge)
+	assert.NoError(t, l.CheckAttachmentSize(100))
+	assert.ErrorIs(t, l.CheckAttachmentSize(101), code.ErrorFileTooLarge)
+	assert.NoError(t, l.CheckNoteCount(func() (int64, error) { return 1, nil }))
+	assert.ErrorIs(t, l.CheckNoteCount(func() (int64, error) { return 2, nil }), code.ErrorVaultNoteLimit)
+	assert.ErrorIs(t, l.CheckNoteCount(func() (int64, error) { return 0, errors.New("db down") }), code.ErrorDBQuery)
+
+	// Lifting the caps at runtime, the count is no longer queried
+	// 运行时取消限制后不再查询数量
+	l.Set(0, 0, 0)
+	assert.NoError(t, l.CheckNoteSize(1<<40))
+	assert.NoError(t, l.CheckAttachmentSize(1<<40))
+	assert.NoError(t, l.CheckNoteCount(func() (int64, error) {
+		t.Fatal("count queried without a cap")
+		return 0, nil
+	}))
+}
+
+// writableFolderService FolderService stub that allows every write
+// writableFolderService 允许所有写入的 FolderService 替身
+type writableFolderService struct {
+	FolderService
+}
+
+func (writableFolderService) CheckWritable(ctx context.Context, uid int64, vaultID int64, paths ...string) error {
+	return nil
+}
+
+// TestFileService_UpdateOrCreate_AttachmentCap verifies the cap is checked against the stored file, whatever
+// size the caller declares, so uploads, WebDAV, MCP, imports and migrations cannot bypass it.
+// TestFileService_UpdateOrCreate_AttachmentCap 验证大小限制按存储的文件检查而非调用方声明的大小，
+// 上传、WebDAV、MCP、导入与迁移均无法绕过。
+func TestFileService_UpdateOrCreate_AttachmentCap(t *testing.T) {
+	savePath := filepath.Join(t.TempDir(), "upload")
+	require.NoError(t, os.WriteFile(savePath, make([]byte, 101), 0644))
+
+	svc := &fileService{
+		vaultService:  &fakeVaultServiceForConflictTest{vaultID: 7},
+		folderService: writableFolderService{},
+		config:        &ServiceConfig{App: AppServiceConfig{Limits: NewContentLimits(0, 100, 0)}},
+	}
+
+	// No repository is set, reaching it would panic
+	// 未设置仓库，若执行到仓库会 panic
+	_, _, err := svc.UpdateOrCreate(context.Background(), 1, &dto.FileUpdateRequest{
+		Vault:    "main",
+		Path:     "big.png",
+		PathHash: "h",
+		SavePath: savePath,
+		Size:     1,
+	}, false)
+	assert.ErrorIs(t, err, code.ErrorFileTooLarge)
+}
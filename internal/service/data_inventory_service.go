@@ -0,0 +1,331 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/storage"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DataInventoryService builds the data inventory of a user, a summary of every category of personal data the
+// server holds for them, so operators can answer data-subject requests. Only counts and locations are reported,
+// never content or credentials.
+// DataInventoryService 生成用户的数据清单，汇总服务端为其保存的每一类个人数据，便于运维人员答复数据主体请求。
+// 仅报告数量与存放位置，不包含内容或凭据。
+type DataInventoryService interface {
+	// Get builds the data inventory of a user
+	// Get 生成用户的数据清单
+	Get(ctx context.Context, uid int64) (*dto.DataInventoryDTO, error)
+}
+
+// dataInventoryService implementation of DataInventoryService interface
+// dataInventoryService 实现 DataInventoryService 接口
+type dataInventoryService struct {
+	userRepo         domain.UserRepository
+	oidcIdentityRepo domain.OIDCIdentityRepository
+	userTOTPRepo     domain.UserTOTPRepository
+	vaultRepo        domain.VaultRepository
+	noteRepo         domain.NoteRepository
+	fileRepo         domain.FileRepository
+	settingRepo      domain.SettingRepository
+	noteHistoryRepo  domain.NoteHistoryRepository
+	shareRepo        domain.UserShareRepository
+	authTokenRepo    domain.AuthTokenRepository
+	authTokenLogRepo domain.AuthTokenLogRepository
+	syncLogRepo      domain.SyncLogRepository
+	storageRepo      domain.StorageRepository
+	backupRepo       domain.BackupRepository
+	gitSyncRepo      domain.GitSyncRepository
+	logger           *zap.Logger
+}
+
+// NewDataInventoryService creates DataInventoryService instance
+// NewDataInventoryService 创建 DataInventoryService 实例
+func NewDataInventoryService(
+	userRepo domain.UserRepository,
+	oidcIdentityRepo domain.OIDCIdentityRepository,
+	userTOTPRepo domain.UserTOTPRepository,
+	vaultRepo domain.VaultRepository,
+	noteRepo domain.NoteRepository,
+	fileRepo domain.FileRepository,
+	settingRepo domain.SettingRepository,
+	noteHistoryRepo domain.NoteHistoryRepository,
+	shareRepo domain.UserShareRepository,
+	authTokenRepo domain.AuthTokenRepository,
+	authTokenLogRepo domain.AuthTokenLogRepository,
+	syncLogRepo domain.SyncLogRepository,
+	storageRepo domain.StorageRepository,
+	backupRepo domain.BackupRepository,
+	gitSyncRepo domain.GitSyncRepository,
+	logger *zap.Logger,
+) DataInventoryService {
+	return &dataInventoryService{
+		userRepo:         userRepo,
+		oidcIdentityRepo: oidcIdentityRepo,
+		userTOTPRepo:     userTOTPRepo,
+		vaultRepo:        vaultRepo,
+		noteRepo:         noteRepo,
+		fileRepo:         fileRepo,
+		settingRepo:      settingRepo,
+		noteHistoryRepo:  noteHistoryRepo,
+		shareRepo:        shareRepo,
+		authTokenRepo:    authTokenRepo,
+		authTokenLogRepo: authTokenLogRepo,
+		syncLogRepo:      syncLogRepo,
+		storageRepo:      storageRepo,
+		backupRepo:       backupRepo,
+		gitSyncRepo:      gitSyncRepo,
+		logger:           logger,
+	}
+}
+
+// Get builds the data inventory of a user
+// Get 生成用户的数据清单
+func (s *dataInventoryService) Get(ctx context.Context, uid int64) (*dto.DataInventoryDTO, error) {
+	user, err := s.userRepo.GetByUID(ctx, uid, false)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, code.ErrorUserNotFound
+		}
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	inv := &dto.DataInventoryDTO{
+		UID:         uid,
+		GeneratedAt: timex.Now(),
+		Account: dto.DataInventoryAccountDTO{
+			Username:           user.Username,
+			Email:              user.Email,
+			HasAvatar:          user.HasAvatar(),
+			ExternalIdentities: []*dto.DataInventoryIdentityDTO{},
+			CreatedAt:          timex.Time(user.CreatedAt),
+			UpdatedAt:          timex.Time(user.UpdatedAt),
+		},
+		Vaults:   []*dto.DataInventoryVaultDTO{},
+		Storages: []*dto.DataInventoryStorageDTO{},
+		Backups:  []*dto.DataInventoryBackupDTO{},
+		GitSyncs: []*dto.DataInventoryGitSyncDTO{},
+	}
+
+	if err := s.fillAccount(ctx, uid, inv); err != nil {
+		return nil, s.dbError("fillAccount", uid, err)
+	}
+	vaultNames, err := s.fillVaults(ctx, uid, inv)
+	if err != nil {
+		return nil, s.dbError("fillVaults", uid, err)
+	}
+	if err := s.fillActivity(ctx, uid, inv); err != nil {
+		return nil, s.dbError("fillActivity", uid, err)
+	}
+	if err := s.fillTargets(ctx, uid, vaultNames, inv); err != nil {
+		return nil, s.dbError("fillTargets", uid, err)
+	}
+	return inv, nil
+}
+
+// fillAccount adds two-factor and external identity data
+// fillAccount 填充两步验证与外部身份数据
+func (s *dataInventoryService) fillAccount(ctx context.Context, uid int64, inv *dto.DataInventoryDTO) error {
+	totp, err := s.userTOTPRepo.GetByUID(ctx, uid)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	inv.Account.TwoFactorEnabled = totp != nil && totp.Enabled
+
+	identities, err := s.oidcIdentityRepo.ListByUID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	for _, identity := range identities {
+		inv.Account.ExternalIdentities = append(inv.Account.ExternalIdentities, &dto.DataInventoryIdentityDTO{
+			Issuer:    identity.Issuer,
+			Email:     identity.Email,
+			Username:  identity.Username,
+			CreatedAt: timex.Time(identity.CreatedAt),
+		})
+	}
+	return nil
+}
+
+// fillVaults counts the content of every vault and returns vault names by ID
+// fillVaults 统计每个仓库的内容并返回以 ID 为键的仓库名称
+func (s *dataInventoryService) fillVaults(ctx context.Context, uid int64, inv *dto.DataInventoryDTO) (map[int64]string, error) {
+	vaults, err := s.vaultRepo.List(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int64]string, len(vaults))
+	for _, v := range vaults {
+		names[v.ID] = v.Name
+		item := &dto.DataInventoryVaultDTO{ID: v.ID, Name: v.Name}
+
+		notes, err := s.noteRepo.CountSizeSum(ctx, v.ID, uid)
+		if err != nil {
+			return nil, err
+		}
+		item.Notes, item.NotesSize = notes.Count, notes.Size
+		if item.DeletedNotes, err = s.noteRepo.ListCount(ctx, v.ID, uid, "", true, "", false, nil, nil); err != nil {
+			return nil, err
+		}
+		if item.NoteHistory, err = s.noteHistoryRepo.CountByVaultID(ctx, v.ID, uid); err != nil {
+			return nil, err
+		}
+
+		files, err := s.fileRepo.CountSizeSum(ctx, v.ID, uid)
+		if err != nil {
+			return nil, err
+		}
+		item.Attachments, item.AttachmentsSize = files.Count, files.Size
+		if item.DeletedAttachments, err = s.fileRepo.ListCount(ctx, v.ID, uid, "", true); err != nil {
+			return nil, err
+		}
+		if item.Settings, err = s.settingRepo.ListCount(ctx, v.ID, uid, ""); err != nil {
+			return nil, err
+		}
+
+		inv.Vaults = append(inv.Vaults, item)
+		inv.Totals.Notes += item.Notes
+		inv.Totals.NotesSize += item.NotesSize
+		inv.Totals.DeletedNotes += item.DeletedNotes
+		inv.Totals.NoteHistory += item.NoteHistory
+		inv.Totals.Attachments += item.Attachments
+		inv.Totals.AttachmentsSize += item.AttachmentsSize
+		inv.Totals.DeletedAttachments += item.DeletedAttachments
+		inv.Totals.Settings += item.Settings
+	}
+	return names, nil
+}
+
+// fillActivity counts shares, tokens, token access logs and sync logs
+// fillActivity 统计分享、Token、Token 访问日志与同步日志
+func (s *dataInventoryService) fillActivity(ctx context.Context, uid int64, inv *dto.DataInventoryDTO) error {
+	var err error
+	if inv.Shares, err = s.shareRepo.CountByUID(ctx, uid); err != nil {
+		return err
+	}
+
+	tokens, err := s.authTokenRepo.ListByUID(ctx, uid)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, t := range tokens {
+		inv.AuthTokens.Total++
+		if t.Status == 1 && (t.ExpiredAt.IsZero() || t.ExpiredAt.After(now)) {
+			inv.AuthTokens.Active++
+		}
+		_, logs, err := s.authTokenLogRepo.ListByTokenID(ctx, t.ID, 1, 1)
+		if err != nil {
+			return err
+		}
+		inv.AuthTokens.AccessLogs += logs
+	}
+
+	_, inv.SyncLogs, err = s.syncLogRepo.List(ctx, uid, "", "", 1, 1)
+	return err
+}
+
+// fillTargets lists storage targets, backup configs and Git remotes holding copies of user data
+// fillTargets 列出存放用户数据副本的存储目标、备份配置与 Git 远端
+func (s *dataInventoryService) fillTargets(ctx context.Context, uid int64, vaultNames map[int64]string, inv *dto.DataInventoryDTO) error {
+	storages, err := s.storageRepo.List(ctx, uid)
+	if err != nil {
+		return err
+	}
+	for _, st := range storages {
+		inv.Storages = append(inv.Storages, &dto.DataInventoryStorageDTO{
+			ID:        st.ID,
+			Type:      st.Type,
+			Location:  storageLocation(st),
+			IsEnabled: st.IsEnabled,
+		})
+	}
+
+	configs, err := s.backupRepo.ListConfigs(ctx, uid)
+	if err != nil {
+		return err
+	}
+	for _, c := range configs {
+		item := &dto.DataInventoryBackupDTO{
+			ID:          c.ID,
+			Vault:       vaultNames[c.VaultID],
+			Type:        c.Type,
+			IsEnabled:   c.IsEnabled,
+			StorageIDs:  []int64{},
+			LastRunTime: timex.Time(c.LastRunTime),
+		}
+		_ = json.Unmarshal([]byte(c.StorageIds), &item.StorageIDs)
+		if _, item.Runs, err = s.backupRepo.ListHistory(ctx, uid, c.ID, 1, 1); err != nil {
+			return err
+		}
+		inv.Backups = append(inv.Backups, item)
+	}
+
+	gitConfigs, err := s.gitSyncRepo.List(ctx, uid)
+	if err != nil {
+		return err
+	}
+	for _, g := range gitConfigs {
+		inv.GitSyncs = append(inv.GitSyncs, &dto.DataInventoryGitSyncDTO{
+			ID:        g.ID,
+			Vault:     vaultNames[g.VaultID],
+			RepoURL:   stripURLCredentials(g.RepoURL),
+			Branch:    g.Branch,
+			IsEnabled: g.IsEnabled,
+		})
+	}
+	return nil
+}
+
+// dbError logs a failed inventory query and wraps it
+// dbError 记录清单查询失败并包装错误
+func (s *dataInventoryService) dbError(step string, uid int64, err error) error {
+	if s.logger != nil {
+		s.logger.Error("DataInventoryService.Get failed", zap.String("step", step), zap.Int64("uid", uid), zap.Error(err))
+	}
+	return code.ErrorDBQuery.WithDetails(err.Error())
+}
+
+// storageLocation describes where a storage target keeps data, without credentials
+// storageLocation 描述存储目标保存数据的位置，不含凭据
+func storageLocation(st *domain.Storage) string {
+	switch st.Type {
+	case storage.LOCAL:
+		return path.Join("localfs", st.CustomPath)
+	case storage.WebDAV:
+		return strings.TrimRight(stripURLCredentials(st.Endpoint), "/") + "/" + strings.Trim(st.CustomPath, "/")
+	case storage.R2:
+		return path.Join("r2:"+st.AccountID, st.BucketName, st.CustomPath)
+	default:
+		host := stripURLCredentials(st.Endpoint)
+		if host == "" {
+			host = st.Type + ":" + st.Region
+		}
+		return strings.TrimRight(host, "/") + "/" + path.Join(st.BucketName, st.CustomPath)
+	}
+}
+
+// stripURLCredentials removes user info from a URL, unparsable values are returned unchanged
+// stripURLCredentials 移除 URL 中的用户信息，无法解析的值原样返回
+func stripURLCredentials(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	u.User = nil
+	return u.String()
+}
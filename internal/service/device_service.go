@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap"
+)
+
+// DeviceService defines the connected device management business service interface
+// DeviceService 定义已连接设备管理业务服务接口
+type DeviceService interface {
+	// Record creates or refreshes the device a WebSocket client authenticated from
+	// Record 新建或刷新 WebSocket 客户端鉴权所在的设备
+	Record(ctx context.Context, device *domain.Device) error
+
+	// List lists the devices of a user, most recently seen first
+	// List 按最近出现时间倒序列出用户的设备
+	List(ctx context.Context, uid int64) ([]*dto.DeviceDTO, error)
+
+	// Revoke revokes the token of a device, closes its connections and removes it from the list
+	// Revoke 撤销设备的令牌，关闭其连接并将其从列表中移除
+	Revoke(ctx context.Context, uid int64, id int64) error
+
+	// SetKickHandler sets the hook closing the connections of a device
+	// SetKickHandler 设置关闭设备连接的钩子
+	SetKickHandler(handler func(uid int64, deviceKey string))
+}
+
+// deviceService implements DeviceService
+// deviceService 实现 DeviceService 接口
+type deviceService struct {
+	repo         domain.DeviceRepository
+	tokenService TokenService
+	logger       *zap.Logger
+	kickHandler  func(uid int64, deviceKey string)
+}
+
+// NewDeviceService creates a DeviceService instance
+// NewDeviceService 创建 DeviceService 实例
+func NewDeviceService(repo domain.DeviceRepository, tokenService TokenService, logger *zap.Logger) DeviceService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &deviceService{
+		repo:         repo,
+		tokenService: tokenService,
+		logger:       logger,
+	}
+}
+
+// deviceToDTO converts the domain model to the DTO
+// deviceToDTO 将领域模型转换为 DTO
+func deviceToDTO(d *domain.Device) *dto.DeviceDTO {
+	platform := d.Platform
+	if platform == nil {
+		platform = []string{}
+	}
+	return &dto.DeviceDTO{
+		ID:            d.ID,
+		DeviceKey:     d.DeviceKey,
+		TokenID:       d.TokenID,
+		ClientName:    d.ClientName,
+		ClientType:    d.ClientType,
+		ClientVersion: d.ClientVersion,
+		Platform:      platform,
+		IP:            d.IP,
+		UserAgent:     d.UserAgent,
+		LastSeenAt:    timex.Time(d.LastSeenAt),
+		CreatedAt:     timex.Time(d.CreatedAt),
+	}
+}
+
+// Record creates or refreshes the device a WebSocket client authenticated from
+// Record 新建或刷新 WebSocket 客户端鉴权所在的设备
+func (s *deviceService) Record(ctx context.Context, device *domain.Device) error {
+	if device.UID == 0 || device.DeviceKey == "" {
+		return code.ErrorInvalidParams.WithDetails("uid and device key are required")
+	}
+	if _, err := s.repo.Upsert(ctx, device); err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// List lists the devices of a user, most recently seen first
+// List 按最近出现时间倒序列出用户的设备
+func (s *deviceService) List(ctx context.Context, uid int64) ([]*dto.DeviceDTO, error) {
+	devices, err := s.repo.ListByUID(ctx, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	results := make([]*dto.DeviceDTO, 0, len(devices))
+	for _, d := range devices {
+		results = append(results, deviceToDTO(d))
+	}
+	return results, nil
+}
+
+// Revoke revokes the token of a device, closes its connections and removes it from the list
+// Revoke 撤销设备的令牌，关闭其连接并将其从列表中移除
+func (s *deviceService) Revoke(ctx context.Context, uid int64, id int64) error {
+	device, err := s.repo.Get(ctx, id, uid)
+	if err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if device == nil {
+		return code.ErrorDeviceNotFound
+	}
+
+	// A token that already expired or was revoked needs no revocation
+	// 已过期或已撤销的令牌无需再撤销
+	if device.TokenID > 0 {
+		if _, err := s.tokenService.GetActiveToken(ctx, uid, device.TokenID); err == nil {
+			if err := s.tokenService.Revoke(ctx, uid, device.TokenID); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.repo.Delete(ctx, id, uid); err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	if s.kickHandler != nil {
+		s.kickHandler(uid, device.DeviceKey)
+	}
+	s.logger.Info("device revoked",
+		zap.Int64("uid", uid),
+		zap.Int64("deviceId", id),
+		zap.String("deviceKey", device.DeviceKey),
+		zap.Int64("tokenId", device.TokenID))
+	return nil
+}
+
+// SetKickHandler sets the hook closing the connections of a device
+// SetKickHandler 设置关闭设备连接的钩子
+func (s *deviceService) SetKickHandler(handler func(uid int64, deviceKey string)) {
+	s.kickHandler = handler
+}
+
+var _ DeviceService = (*deviceService)(nil)
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeDeviceRepo is an in-memory DeviceRepository for tests.
+// fakeDeviceRepo 是用于测试的内存 DeviceRepository。
+type fakeDeviceRepo struct {
+	rows   []*domain.Device
+	nextID int64
+}
+
+func (r *fakeDeviceRepo) ListByUID(ctx context.Context, uid int64) ([]*domain.Device, error) {
+	var list []*domain.Device
+	for _, d := range r.rows {
+		if d.UID == uid {
+			list = append(list, d)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeDeviceRepo) Get(ctx context.Context, id, uid int64) (*domain.Device, error) {
+	for _, d := range r.rows {
+		if d.ID == id && d.UID == uid {
+			return d, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeDeviceRepo) Upsert(ctx context.Context, device *domain.Device) (*domain.Device, error) {
+	for i, d := range r.rows {
+		if d.UID == device.UID && d.DeviceKey == device.DeviceKey {
+			saved := *device
+			saved.ID = d.ID
+			r.rows[i] = &saved
+			return &saved, nil
+		}
+	}
+	r.nextID++
+	saved := *device
+	saved.ID = r.nextID
+	r.rows = append(r.rows, &saved)
+	return &saved, nil
+}
+
+func (r *fakeDeviceRepo) Delete(ctx context.Context, id, uid int64) error {
+	for i, d := range r.rows {
+		if d.ID == id && d.UID == uid {
+			r.rows = append(r.rows[:i], r.rows[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// fakeDeviceTokenService records revoked tokens; tokens listed in active are active.
+// fakeDeviceTokenService 记录被撤销的令牌；active 中列出的令牌为有效令牌。
+type fakeDeviceTokenService struct {
+	TokenService
+	active  map[int64]bool
+	revoked []int64
+}
+
+func (s *fakeDeviceTokenService) GetActiveToken(ctx context.Context, uid int64, tokenID int64) (*domain.AuthToken, error) {
+	if !s.active[tokenID] {
+		return nil, code.ErrorInvalidUserAuthToken
+	}
+	return &domain.AuthToken{ID: tokenID, UID: uid, Status: 1}, nil
+}
+
+func (s *fakeDeviceTokenService) Revoke(ctx context.Context, uid int64, tokenID int64) error {
+	s.revoked = append(s.revoked, tokenID)
+	delete(s.active, tokenID)
+	return nil
+}
+
+func TestDeviceService_RecordAndList(t *testing.T) {
+	repo := &fakeDeviceRepo{}
+	svc := NewDeviceService(repo, &fakeDeviceTokenService{}, zap.NewNop())
+	ctx := context.Background()
+
+	assert.Error(t, svc.Record(ctx, &domain.Device{UID: 1}), "device key is required")
+
+	seen := time.Now()
+	require.NoError(t, svc.Record(ctx, &domain.Device{UID: 1, DeviceKey: "phone", ClientVersion: "1.0", LastSeenAt: seen}))
+	require.NoError(t, svc.Record(ctx, &domain.Device{UID: 1, DeviceKey: "phone", ClientVersion: "1.1", Platform: []string{"isMobile"}, LastSeenAt: seen}))
+	require.NoError(t, svc.Record(ctx, &domain.Device{UID: 2, DeviceKey: "laptop", LastSeenAt: seen}))
+
+	list, err := svc.List(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "1.1", list[0].ClientVersion)
+	assert.Equal(t, []string{"isMobile"}, list[0].Platform)
+
+	list, err = svc.List(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, []string{}, list[0].Platform)
+}
+
+func TestDeviceService_Revoke(t *testing.T) {
+	repo := &fakeDeviceRepo{}
+	tokens := &fakeDeviceTokenService{active: map[int64]bool{7: true}}
+	svc := NewDeviceService(repo, tokens, zap.NewNop())
+	var kicked []string
+	svc.SetKickHandler(func(uid int64, deviceKey string) {
+		kicked = append(kicked, deviceKey)
+	})
+	ctx := context.Background()
+
+	require.NoError(t, svc.Record(ctx, &domain.Device{UID: 1, DeviceKey: "phone", TokenID: 7}))
+	require.NoError(t, svc.Record(ctx, &domain.Device{UID: 1, DeviceKey: "tablet", TokenID: 8}))
+
+	assert.Equal(t, code.ErrorDeviceNotFound, svc.Revoke(ctx, 2, 1), "other user's device")
+
+	require.NoError(t, svc.Revoke(ctx, 1, 1))
+	assert.Equal(t, []int64{7}, tokens.revoked)
+	assert.Equal(t, []string{"phone"}, kicked)
+
+	// An inactive token is not revoked again, the device is still removed
+	require.NoError(t, svc.Revoke(ctx, 1, 2))
+	assert.Equal(t, []int64{7}, tokens.revoked)
+	assert.Equal(t, []string{"phone", "tablet"}, kicked)
+
+	list, err := svc.List(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	assert.Equal(t, code.ErrorDeviceNotFound, svc.Revoke(ctx, 1, 1))
+}
@@ -0,0 +1,90 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+)
+
+// Experimental features that can be rolled out per user
+// 可按用户灰度开启的实验性功能
+const (
+	FeatureDeltaSync      = "delta-sync"      // Delta sync of note content // 笔记内容增量同步
+	FeatureSemanticSearch = "semantic-search" // Semantic search // 语义搜索
+)
+
+// knownFeatures features always reported by Capabilities, even when missing from the config
+// knownFeatures Capabilities 始终返回的功能，即使配置中未出现
+var knownFeatures = []string{FeatureDeltaSync, FeatureSemanticSearch}
+
+// FeatureFlagService evaluates experimental feature flags for a user.
+// A feature is on when it is enabled globally or the user is one of its canary UIDs.
+// FeatureFlagService 为用户判断实验性功能开关。
+// 功能全局开启，或用户在其灰度 UID 列表中时，视为开启。
+type FeatureFlagService interface {
+	// IsEnabled reports whether a feature is on for the user
+	// IsEnabled 判断功能是否对该用户开启
+	IsEnabled(uid int64, feature string) bool
+
+	// Capabilities returns the evaluated flags of every known and configured feature for the user
+	// Capabilities 返回所有已知及已配置功能对该用户的判断结果
+	Capabilities(ctx context.Context, uid int64) *dto.UserCapabilitiesDTO
+}
+
+// featureFlagService implementation of FeatureFlagService interface
+// featureFlagService 实现 FeatureFlagService 接口
+type featureFlagService struct {
+	config *config.FeatureFlagsConfig
+}
+
+// NewFeatureFlagService creates FeatureFlagService instance
+// NewFeatureFlagService 创建 FeatureFlagService 实例
+func NewFeatureFlagService(cfg *config.FeatureFlagsConfig) FeatureFlagService {
+	return &featureFlagService{config: cfg}
+}
+
+// IsEnabled reports whether a feature is on for the user
+// IsEnabled 判断功能是否对该用户开启
+func (s *featureFlagService) IsEnabled(uid int64, feature string) bool {
+	if s.config == nil {
+		return false
+	}
+	flag, ok := (*s.config)[feature]
+	if !ok {
+		return false
+	}
+	return flag.Enabled || (uid > 0 && slices.Contains(flag.UIDs, uid))
+}
+
+// Capabilities returns the evaluated flags of every known and configured feature for the user
+// Capabilities 返回所有已知及已配置功能对该用户的判断结果
+func (s *featureFlagService) Capabilities(ctx context.Context, uid int64) *dto.UserCapabilitiesDTO {
+	features := make(map[string]bool, len(knownFeatures))
+	for _, name := range knownFeatures {
+		features[name] = s.IsEnabled(uid, name)
+	}
+	if s.config != nil {
+		for name := range *s.config {
+			features[name] = s.IsEnabled(uid, name)
+		}
+	}
+
+	enabled := make([]string, 0, len(features))
+	for name, on := range features {
+		if on {
+			enabled = append(enabled, name)
+		}
+	}
+	sort.Strings(enabled)
+
+	return &dto.UserCapabilitiesDTO{
+		UID:      uid,
+		Features: features,
+		Enabled:  enabled,
+	}
+}
@@ -0,0 +1,39 @@
+// Package service implements the business logic layer.
+// Package service 实现业务逻辑层。
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFeatureFlagService_IsEnabled verifies global and canary evaluation of feature flags.
+// TestFeatureFlagService_IsEnabled 验证功能开关的全局开启与灰度判断。
+func TestFeatureFlagService_IsEnabled(t *testing.T) {
+	cfg := config.FeatureFlagsConfig{
+		FeatureDeltaSync:      {UIDs: []int64{2, 3}},
+		FeatureSemanticSearch: {Enabled: true},
+		"vault-graph":         {},
+	}
+	svc := NewFeatureFlagService(&cfg)
+
+	assert.True(t, svc.IsEnabled(2, FeatureDeltaSync))
+	assert.False(t, svc.IsEnabled(1, FeatureDeltaSync))
+	assert.True(t, svc.IsEnabled(1, FeatureSemanticSearch))
+	assert.False(t, svc.IsEnabled(2, "vault-graph"))
+	assert.False(t, svc.IsEnabled(2, "unknown"))
+
+	caps := svc.Capabilities(context.Background(), 3)
+	assert.Equal(t, map[string]bool{FeatureDeltaSync: true, FeatureSemanticSearch: true, "vault-graph": false}, caps.Features)
+	assert.Equal(t, []string{FeatureDeltaSync, FeatureSemanticSearch}, caps.Enabled)
+
+	// Later config changes are picked up without rebuilding the service
+	// 配置变更无需重建服务即可生效
+	cfg = config.FeatureFlagsConfig{}
+	assert.False(t, svc.IsEnabled(2, FeatureDeltaSync))
+
+	assert.False(t, NewFeatureFlagService(nil).IsEnabled(1, FeatureDeltaSync))
+}
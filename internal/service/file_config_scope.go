@@ -0,0 +1,64 @@
+package service
+
+import (
+	"path"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+)
+
+// DefaultConfigDir is the vault config folder used when the client does not name one
+// DefaultConfigDir 客户端未指定时使用的笔记库配置目录
+const DefaultConfigDir = ".obsidian"
+
+// workspaceFiles are the per-device window layout files of the config folder
+// workspaceFiles 为配置目录中各设备自身的窗口布局文件
+var workspaceFiles = map[string]bool{
+	"workspace.json":        true,
+	"workspace-mobile.json": true,
+}
+
+// ConfigFileScope decides which files of the vault config folder a device syncs. Config files travel
+// through the regular file sync as small binary files, but only to devices that opted in
+// ConfigFileScope 决定设备同步笔记库配置目录中的哪些文件。配置文件作为小型二进制文件经普通文件同步传输，
+// 但只发送给已开启该功能的设备
+type ConfigFileScope struct {
+	Enabled          bool   // Whether the device syncs the config folder // 设备是否同步配置目录
+	Dir              string // Config folder, relative to the vault root // 配置目录，相对于笔记库根目录
+	ExcludeWorkspace bool   // Whether the window layout files stay local // 窗口布局文件是否保留在本地
+}
+
+// NewConfigFileScope builds the config file scope declared in a file sync request
+// NewConfigFileScope 根据文件同步请求中声明的内容构建配置文件范围
+func NewConfigFileScope(params *dto.FileSyncRequest) ConfigFileScope {
+	dir := strings.Trim(strings.TrimSpace(params.ConfigDir), "/")
+	if dir == "" {
+		dir = DefaultConfigDir
+	}
+	return ConfigFileScope{
+		Enabled:          params.ConfigFiles,
+		Dir:              dir,
+		ExcludeWorkspace: params.ExcludeWorkspace,
+	}
+}
+
+// IsConfigFile reports whether the path lies inside the config folder
+// IsConfigFile 判断路径是否位于配置目录内
+func (s ConfigFileScope) IsConfigFile(p string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(p, "/"), s.Dir+"/")
+}
+
+// Allows reports whether the device syncs the file at the path
+// Allows 判断设备是否同步该路径的文件
+func (s ConfigFileScope) Allows(p string) bool {
+	if !s.IsConfigFile(p) {
+		return true
+	}
+	if !s.Enabled {
+		return false
+	}
+	if s.ExcludeWorkspace && path.Dir(strings.TrimPrefix(p, "/")) == s.Dir && workspaceFiles[path.Base(p)] {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConfigFileScope verifies config folder files are only synced by devices that opted in, and that
+// the window layout can stay on the device.
+// TestConfigFileScope 验证配置目录中的文件只由已开启的设备同步，且窗口布局可保留在设备本地。
+func TestConfigFileScope(t *testing.T) {
+	off := NewConfigFileScope(&dto.FileSyncRequest{})
+	assert.Equal(t, DefaultConfigDir, off.Dir)
+	assert.True(t, off.Allows("Images/a.png"))
+	assert.True(t, off.Allows(".obsidian.png"))
+	assert.False(t, off.Allows(".obsidian/app.json"))
+	assert.False(t, off.Allows("/.obsidian/plugins/x/data.json"))
+
+	on := NewConfigFileScope(&dto.FileSyncRequest{ConfigFiles: true})
+	assert.True(t, on.Allows(".obsidian/app.json"))
+	assert.True(t, on.Allows(".obsidian/workspace.json"))
+
+	local := NewConfigFileScope(&dto.FileSyncRequest{ConfigFiles: true, ConfigDir: "/.config/", ExcludeWorkspace: true})
+	assert.Equal(t, ".config", local.Dir)
+	assert.False(t, local.Allows(".config/workspace.json"))
+	assert.False(t, local.Allows(".config/workspace-mobile.json"))
+	assert.True(t, local.Allows(".config/plugins/x/workspace.json"))
+	assert.True(t, local.Allows(".config/appearance.json"))
+	// Another folder is an ordinary one
+	// 其他目录为普通目录
+	assert.True(t, local.Allows(".obsidian/workspace.json"))
+}
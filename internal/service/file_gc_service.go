@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+// FileGCService defines the attachment garbage collection business service interface
+// FileGCService 定义附件垃圾回收业务服务接口
+type FileGCService interface {
+	// Run finds the attachment folders without a file record of a user, or of every user when uid is 0,
+	// and removes the ones older than the grace period unless dryRun is set
+	// Run 查找用户（uid 为 0 时为所有用户）没有文件记录的附件目录，非试运行时删除超过宽限期的目录
+	Run(ctx context.Context, uid int64, dryRun bool) (*dto.FileGCReportDTO, error)
+}
+
+// fileGCService implements FileGCService
+// fileGCService 实现 FileGCService 接口
+type fileGCService struct {
+	fileRepo        domain.FileRepository
+	userRepo        domain.UserRepository
+	gracePeriod     time.Duration
+	gracePeriodText string
+	logger          *zap.Logger
+}
+
+// NewFileGCService creates a FileGCService instance, a grace period of 0 or an invalid one only reports orphans
+// NewFileGCService 创建 FileGCService 实例，宽限期为 0 或无效时仅报告孤立目录
+func NewFileGCService(fileRepo domain.FileRepository, userRepo domain.UserRepository, gracePeriod string, logger *zap.Logger) FileGCService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	s := &fileGCService{
+		fileRepo:        fileRepo,
+		userRepo:        userRepo,
+		gracePeriodText: "0",
+		logger:          logger,
+	}
+	if gracePeriod = strings.TrimSpace(gracePeriod); gracePeriod != "" && gracePeriod != "0" {
+		d, err := util.ParseDuration(gracePeriod)
+		if err != nil || d < 0 {
+			logger.Warn("invalid file-gc-grace-period, orphaned attachments are only reported", zap.String("value", gracePeriod), zap.Error(err))
+		} else {
+			s.gracePeriod, s.gracePeriodText = d, gracePeriod
+		}
+	}
+	return s
+}
+
+// Run implements FileGCService
+// Run 实现 FileGCService 接口
+func (s *fileGCService) Run(ctx context.Context, uid int64, dryRun bool) (*dto.FileGCReportDTO, error) {
+	uids := []int64{uid}
+	if uid == 0 {
+		var err error
+		if uids, err = s.userRepo.GetAllUIDs(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &dto.FileGCReportDTO{
+		DryRun:      dryRun || s.gracePeriod == 0,
+		GracePeriod: s.gracePeriodText,
+		Items:       []*dto.FileGCOrphanDTO{},
+	}
+	cutoff := time.Now().Add(-s.gracePeriod)
+
+	for _, u := range uids {
+		orphans, err := s.fileRepo.ListOrphanContent(ctx, u)
+		if err != nil {
+			if uid != 0 {
+				return nil, err
+			}
+			// Keep going with the other users
+			// 继续处理其他用户
+			s.logger.Warn("file gc list orphans failed", zap.Int64("uid", u), zap.Error(err))
+			continue
+		}
+		report.Users++
+
+		for _, o := range orphans {
+			item := &dto.FileGCOrphanDTO{
+				UID:     u,
+				FileID:  o.FileID,
+				Path:    o.Path,
+				Size:    o.Size,
+				ModTime: timex.Time(o.ModTime),
+				Expired: s.gracePeriod > 0 && o.ModTime.Before(cutoff),
+			}
+			report.Orphans++
+			report.OrphanSize += o.Size
+
+			if item.Expired && !report.DryRun {
+				removed, err := s.fileRepo.RemoveOrphanContent(ctx, o.FileID, u)
+				if err != nil {
+					s.logger.Warn("file gc remove orphan failed", zap.Int64("uid", u), zap.String("path", o.Path), zap.Error(err))
+				} else if removed {
+					item.Removed = true
+					report.Removed++
+					report.RemovedSize += o.Size
+				}
+			}
+			report.Items = append(report.Items, item)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestFileGCService_Run verifies only orphans older than the grace period are removed, dry runs and a
+// grace period of 0 only report, and a failing user does not stop the run for the others.
+// TestFileGCService_Run 验证只删除超过宽限期的孤立目录，试运行与宽限期为 0 时仅报告，且单个用户失败
+// 不影响其他用户。
+func TestFileGCService_Run(t *testing.T) {
+	ctx := context.Background()
+	fileRepo := new(domainmocks.MockFileRepository)
+	userRepo := new(domainmocks.MockUserRepository)
+	userRepo.On("GetAllUIDs", mock.Anything).Return([]int64{1, 2}, nil)
+	fileRepo.On("ListOrphanContent", mock.Anything, int64(1)).Return([]*domain.FileOrphan{
+		{FileID: 10, Path: "storage/vault/u_1/file/f_10", Size: 100, ModTime: time.Now().AddDate(0, 0, -30)},
+		{FileID: 11, Path: "storage/vault/u_1/file/f_11", Size: 50, ModTime: time.Now()},
+	}, nil)
+	fileRepo.On("ListOrphanContent", mock.Anything, int64(2)).Return(nil, errors.New("broken database"))
+	fileRepo.On("RemoveOrphanContent", mock.Anything, int64(10), int64(1)).Return(true, nil)
+
+	svc := NewFileGCService(fileRepo, userRepo, "7d", zap.NewNop())
+
+	report, err := svc.Run(ctx, 0, true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Users)
+	assert.Equal(t, 2, report.Orphans)
+	assert.Equal(t, int64(150), report.OrphanSize)
+	assert.Equal(t, 0, report.Removed)
+	assert.True(t, report.Items[0].Expired)
+	assert.False(t, report.Items[1].Expired)
+	fileRepo.AssertNotCalled(t, "RemoveOrphanContent", mock.Anything, mock.Anything, mock.Anything)
+
+	report, err = svc.Run(ctx, 0, false)
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 1, report.Removed)
+	assert.Equal(t, int64(100), report.RemovedSize)
+	assert.True(t, report.Items[0].Removed)
+	assert.False(t, report.Items[1].Removed)
+	fileRepo.AssertNumberOfCalls(t, "RemoveOrphanContent", 1)
+
+	// A single user reports its own failure
+	// 指定单个用户时返回该用户的错误
+	_, err = svc.Run(ctx, 2, true)
+	assert.Error(t, err)
+
+	// A grace period of 0 never removes
+	// 宽限期为 0 时从不删除
+	report, err = NewFileGCService(fileRepo, userRepo, "0", zap.NewNop()).Run(ctx, 1, false)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, "0", report.GracePeriod)
+	assert.False(t, report.Items[0].Expired)
+	fileRepo.AssertNumberOfCalls(t, "RemoveOrphanContent", 1)
+}
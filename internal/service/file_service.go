@@ -14,9 +14,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"github.com/haierkeys/fast-note-sync-service/pkg/keyedmutex"
 	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
@@ -87,12 +89,19 @@ type FileService interface {
 	// GetContentInfo 获取文件的元数据和路径，用于零拷贝下载
 	GetContentInfo(ctx context.Context, uid int64, params *dto.FileGetRequest) (savePath string, contentType string, mtime int64, etag string, fileName string, err error)
 
+	// FetchContent makes sure the content of a file returned by Get is in its local SavePath
+	// FetchContent 确保 Get 返回的文件内容位于本地 SavePath
+	FetchContent(ctx context.Context, uid int64, file *dto.FileDTO) error
+
 	// Restore restores a file (from recycle bin)
 	// Restore 恢复文件（从回收站恢复）
 	Restore(ctx context.Context, uid int64, params *dto.FileRestoreRequest) (*dto.FileDTO, error)
 	// Rename renames a file
 	// Rename 重命名文件
 	Rename(ctx context.Context, uid int64, params *dto.FileRenameRequest) (*dto.FileDTO, *dto.FileDTO, error)
+	// Copy copies a file, NewPath defaults to "Copy of <name>" in the same folder
+	// Copy 复制文件，NewPath 默认为同目录下的 "Copy of <名称>"
+	Copy(ctx context.Context, uid int64, params *dto.FileCopyRequest) (*dto.FileDTO, error)
 	// WithClient sets client info
 	// WithClient 设置客户端信息
 	WithClient(clientType, name, version string) FileService
@@ -119,6 +128,7 @@ type fileService struct {
 	vaultService   VaultService           // Vault service // 仓库服务
 	folderService  FolderService          // Folder service // 文件夹服务
 	syncLogService SyncLogService         // Sync log service // 同步日志服务
+	retention      RetentionService       // Soft delete retention overrides // 软删除保留时间覆盖
 	sf             *singleflight.Group    // Singleflight group // 并发请求合并组
 	kmu            *keyedmutex.KeyedMutex // Per-key mutex for write paths that must not share results across callers // 用于写路径的按 key 互斥锁，避免调用方之间共享结果
 	clientType     string                 // Client type // 客户端类型
@@ -132,7 +142,7 @@ type fileService struct {
 
 // NewFileService creates FileService instance
 // NewFileService 创建 FileService 实例
-func NewFileService(userRepo domain.UserRepository, fileRepo domain.FileRepository, noteRepo domain.NoteRepository, vaultSvc VaultService, folderSvc FolderService, backupSvc BackupService, gitSyncSvc GitSyncService, syncLogSvc SyncLogService, config *ServiceConfig) FileService {
+func NewFileService(userRepo domain.UserRepository, fileRepo domain.FileRepository, noteRepo domain.NoteRepository, vaultSvc VaultService, folderSvc FolderService, backupSvc BackupService, gitSyncSvc GitSyncService, syncLogSvc SyncLogService, retentionSvc RetentionService, config *ServiceConfig) FileService {
 	return &fileService{
 		userRepo:       userRepo,
 		fileRepo:       fileRepo,
@@ -142,6 +152,7 @@ func NewFileService(userRepo domain.UserRepository, fileRepo domain.FileReposito
 		backupService:  backupSvc,
 		gitSyncService: gitSyncSvc,
 		syncLogService: syncLogSvc,
+		retention:      retentionSvc,
 		sf:             &singleflight.Group{},
 		kmu:            keyedmutex.New(),
 		config:         config,
@@ -198,7 +209,25 @@ func (s *fileService) UpdateCheck(ctx context.Context, uid int64, params *dto.Fi
 		return "", nil, err
 	}
 
+	if err := s.folderService.CheckWritable(ctx, uid, vaultID, params.Path); err != nil {
+		return "", nil, err
+	}
+
 	file, _ := s.fileRepo.GetByPathHash(ctx, params.PathHash, vaultID, uid)
+	mode, fileDTO, err := s.evalUpdateCheck(ctx, uid, file, params)
+	if trace := s.config.syncTrace(uid); trace != nil {
+		var record *updateCheckRecord
+		if file != nil {
+			record = &updateCheckRecord{ID: file.ID, ContentHash: file.ContentHash, Mtime: file.Mtime, Action: string(file.Action)}
+		}
+		traceUpdateCheck(trace, "FileUpdateCheck", params.Vault, params.Path, params.ContentHash, params.Mtime, record, mode, err)
+	}
+	return mode, fileDTO, err
+}
+
+// evalUpdateCheck decides how the client copy relates to the stored file, which may be nil
+// evalUpdateCheck 判定客户端副本与已存储文件（可能为 nil）的关系
+func (s *fileService) evalUpdateCheck(ctx context.Context, uid int64, file *domain.File, params *dto.FileUpdateCheckRequest) (string, *dto.FileDTO, error) {
 	if file != nil {
 		fileDTO := s.domainToDTO(file)
 
@@ -246,6 +275,26 @@ func (s *fileService) UpdateOrCreate(ctx context.Context, uid int64, params *dto
 		return false, nil, err
 	}
 
+	if err := s.folderService.CheckWritable(ctx, uid, vaultID, params.Path); err != nil {
+		return false, nil, err
+	}
+
+	// Check the cap against the file actually stored, the size sent by the caller cannot be trusted
+	// 按实际存储的文件检查大小限制，调用方传入的大小不可信
+	size := params.Size
+	if params.SavePath != "" {
+		info, err := os.Stat(params.SavePath)
+		if err != nil {
+			return false, nil, code.ErrorFileReadFailed.WithDetails(err.Error())
+		}
+		size = info.Size()
+	}
+	if s.config != nil {
+		if err := s.config.App.Limits.CheckAttachmentSize(size); err != nil {
+			return false, nil, err
+		}
+	}
+
 	key := fmt.Sprintf("update_or_create_%d_%d_%s", uid, vaultID, params.PathHash)
 	type result struct {
 		isNew bool
@@ -313,7 +362,7 @@ func (s *fileService) UpdateOrCreate(ctx context.Context, uid int64, params *dto
 			file.PathHash = params.PathHash
 			file.ContentHash = params.ContentHash
 			file.SavePath = params.SavePath
-			file.Size = params.Size
+			file.Size = size
 			file.Mtime = params.Mtime
 			file.Ctime = params.Ctime
 			file.Action = action
@@ -350,7 +399,7 @@ func (s *fileService) UpdateOrCreate(ctx context.Context, uid int64, params *dto
 			PathHash:    params.PathHash,
 			ContentHash: params.ContentHash,
 			SavePath:    params.SavePath,
-			Size:        params.Size,
+			Size:        size,
 			Mtime:       params.Mtime,
 			Ctime:       params.Ctime,
 			Action:      domain.FileActionCreate,
@@ -402,6 +451,10 @@ func (s *fileService) Delete(ctx context.Context, uid int64, params *dto.FileDel
 		return nil, err
 	}
 
+	if err := s.folderService.CheckWritable(ctx, uid, vaultID, file.Path); err != nil {
+		return nil, err
+	}
+
 	// Update to deleted status // 更新为删除状态
 	file.Action = domain.FileActionDelete
 	file.Rename = 0
@@ -451,6 +504,10 @@ func (s *fileService) Restore(ctx context.Context, uid int64, params *dto.FileRe
 		return nil, code.ErrorNoteNotFound
 	}
 
+	if err := s.folderService.CheckWritable(ctx, uid, vaultID, file.Path); err != nil {
+		return nil, err
+	}
+
 	// Update to modified status and update modification time
 	file.Action = domain.FileActionModify
 	file.Mtime = time.Now().UnixMilli()
@@ -521,10 +578,14 @@ func (s *fileService) ListByLastTime(ctx context.Context, uid int64, params *dto
 		return nil, code.ErrorDBQuery.WithDetails(err.Error())
 	}
 
+	// Files of the config folder only go to devices that opted in
+	// 配置目录中的文件只发送给已开启配置同步的设备
+	scope := NewConfigFileScope(params)
+
 	var results []*dto.FileDTO
 	cacheList := make(map[string]bool)
 	for _, file := range files {
-		if cacheList[file.PathHash] {
+		if cacheList[file.PathHash] || !scope.Allows(file.Path) {
 			continue
 		}
 		results = append(results, s.domainToDTO(file))
@@ -550,16 +611,16 @@ func (s *fileService) CountSizeSum(ctx context.Context, vaultID int64, uid int64
 	timer := time.AfterFunc(10*time.Second, func() {
 		defer s.countTimers.Delete(key)
 
-		// Use singleflight to ensure only one actual DB query runs for same key even if debounce period ends simultaneously
-		// 使用 singleflight 确保即使防抖期同时结束，同一 key 也只有一个真实的 DB 查询
-		s.sf.Do(key, func() (any, error) {
-			result, err := s.fileRepo.CountSizeSum(context.Background(), vaultID, uid)
+		// The job queue merges counts of the same vault that are still waiting
+		// 任务队列会合并同一笔记库仍在等待的统计
+		s.config.enqueue("vault.file_count", key, uid, func(ctx context.Context) error {
+			result, err := s.fileRepo.CountSizeSum(ctx, vaultID, uid)
 			if err != nil {
-				return nil, code.ErrorDBQuery.WithDetails(err.Error())
+				return code.ErrorDBQuery.WithDetails(err.Error())
 			}
 			// Update vault stats, and removed the nested SyncResourceFID call
 			// 更新仓库统计，并移除了嵌套的 SyncResourceFID 调用
-			return nil, s.vaultService.UpdateFileStats(context.Background(), result.Size, result.Count, vaultID, uid)
+			return s.vaultService.UpdateFileStats(ctx, result.Size, result.Count, vaultID, uid)
 		})
 	})
 
@@ -573,35 +634,26 @@ func (s *fileService) Cleanup(ctx context.Context, uid int64) error {
 	if s.config == nil {
 		return nil
 	}
-	retentionTimeStr := s.config.App.SoftDeleteRetentionTime
-	if retentionTimeStr == "" || retentionTimeStr == "0" {
-		return nil
-	}
-
-	retentionDuration, err := util.ParseDuration(retentionTimeStr)
+	cutoffTime, err := softDeleteCutoff(s.config.App.SoftDeleteRetentionTime)
 	if err != nil {
 		return err
 	}
-
-	if retentionDuration <= 0 {
-		return nil
-	}
-
-	cutoffTime := time.Now().Add(-retentionDuration).UnixMilli()
-	return s.fileRepo.DeletePhysicalByTime(ctx, cutoffTime, uid)
+	return s.retention.Purge(ctx, uid, cutoffTime, s.fileRepo.DeletePhysicalByTimeInVaults)
 }
 
 // CleanupByTime cleans up expired soft-deleted files for all users by cutoff time
 // CleanupByTime 按截止时间清理所有用户的过期软删除文件
 func (s *fileService) CleanupByTime(ctx context.Context, cutoffTime int64) error {
-	return s.fileRepo.DeletePhysicalByTimeAll(ctx, cutoffTime)
+	return s.retention.PurgeAll(ctx, cutoffTime, s.fileRepo.DeletePhysicalByTimeInVaults)
 }
 
-// GetContent retrieves raw content of note or attachment file
-// GetContent 获取笔记或附件文件的原始内容
+// GetContent retrieves raw content of note or attachment file.
+// HTTP downloads use GetContentInfo with pkgapp.ServeFile instead, which also answers Range requests.
+// GetContent 获取笔记或附件文件的原始内容。
+// HTTP 下载使用 GetContentInfo 配合 pkgapp.ServeFile，后者同时支持 Range 请求。
 // Return value description:
 // 返回值说明:
-//   - []byte: Raw file data // 文件原始数据
+//   - io.ReadCloser: Open file, closed by the caller // 已打开的文件，由调用方关闭
 //   - string: MIME type (Content-Type) // MIME 类型 (Content-Type)
 //   - int64: mtime (Last-Modified) // mtime (Last-Modified)
 //   - string: etag (Content-Hash) // etag (Content-Hash)
@@ -655,7 +707,10 @@ func (s *fileService) GetContent(ctx context.Context, uid int64, params *dto.Fil
 
 			// Open file for streaming
 			// 打开文件用于流式传输
-			f, err := os.Open(file.SavePath)
+			if err := s.fileRepo.FetchContent(ctx, file, uid); err != nil {
+				return nil, "", 0, "", code.ErrorFileReadFailed.WithDetails(err.Error())
+			}
+			f, err := atrest.Open(file.SavePath)
 			if err != nil {
 				return nil, "", 0, "", code.ErrorFileReadFailed.WithDetails(err.Error())
 			}
@@ -721,6 +776,9 @@ func (s *fileService) GetContentInfo(ctx context.Context, uid int64, params *dto
 				etag = file.PathHash
 			}
 
+			if err := s.fileRepo.FetchContent(ctx, file, uid); err != nil {
+				return "", "", 0, "", "", code.ErrorFileReadFailed.WithDetails(err.Error())
+			}
 			return file.SavePath, contentType, file.Mtime, etag, filepath.Base(file.Path), nil
 		}
 	}
@@ -728,6 +786,15 @@ func (s *fileService) GetContentInfo(ctx context.Context, uid int64, params *dto
 	return "", "", 0, "", "", code.ErrorNoteNotFound
 }
 
+// FetchContent makes sure the content of a file returned by Get is in its local SavePath
+// FetchContent 确保 Get 返回的文件内容位于本地 SavePath
+func (s *fileService) FetchContent(ctx context.Context, uid int64, file *dto.FileDTO) error {
+	if file == nil {
+		return nil
+	}
+	return s.fileRepo.FetchContent(ctx, &domain.File{ID: file.ID, SavePath: file.SavePath}, uid)
+}
+
 // ResolveEmbedLinks resolves local file links in note content
 // ResolveEmbedLinks 解析笔记内容中的本地文件链接
 func (s *fileService) ResolveEmbedLinks(ctx context.Context, uid int64, vaultName string, notePath string, content string) (map[string]string, error) {
@@ -786,6 +853,11 @@ func (s *fileService) Sync(ctx context.Context, uid int64, params *dto.FileSyncR
 // UploadCheck checks file upload (alias for UpdateCheck, used for WebSocket upload check)
 // UploadCheck 检查文件上传（UpdateCheck 的别名，用于 WebSocket 上传检查）
 func (s *fileService) UploadCheck(ctx context.Context, uid int64, params *dto.FileUpdateCheckRequest) (string, *dto.FileDTO, error) {
+	if s.config != nil {
+		if err := s.config.App.Limits.CheckAttachmentSize(params.Size); err != nil {
+			return "", nil, err
+		}
+	}
 	return s.UpdateCheck(ctx, uid, params)
 }
 
@@ -795,6 +867,76 @@ func (s *fileService) UploadComplete(ctx context.Context, uid int64, params *dto
 	return s.UpdateOrCreate(ctx, uid, params, true)
 }
 
+// Copy copies a file; the stored content is duplicated so the copy is independent of the source
+// Copy 复制文件；存储内容会被完整复制，副本与源文件互不影响
+func (s *fileService) Copy(ctx context.Context, uid int64, params *dto.FileCopyRequest) (*dto.FileDTO, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.PathHash == "" {
+		params.PathHash = util.EncodeHash32(params.Path)
+	}
+
+	src, err := s.fileRepo.GetByPathHash(ctx, params.PathHash, vaultID, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, code.ErrorFileNotFound
+		}
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if src.IsDeleted() {
+		return nil, code.ErrorFileNotFound
+	}
+
+	taken := func(candidate string) bool {
+		existing, err := s.fileRepo.GetByPathHash(ctx, util.EncodeHash32(candidate), vaultID, uid)
+		return err == nil && existing != nil && !existing.IsDeleted()
+	}
+
+	newPath := strings.Trim(params.NewPath, "/")
+	if newPath == "" {
+		var ok bool
+		if newPath, ok = freeDuplicatePath(src.Path, taken); !ok {
+			return nil, code.ErrorFileExist
+		}
+	} else if taken(newPath) {
+		return nil, code.ErrorFileExist
+	}
+
+	// The repository moves SavePath into storage, so hand it a private copy of the source content
+	// 仓储层会把 SavePath 移入存储目录，因此传入源内容的独立副本
+	tempDir := "storage/temp"
+	if s.config != nil && s.config.App.TempPath != "" {
+		tempDir = s.config.App.TempPath
+	}
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return nil, err
+	}
+	tempPath := filepath.Join(tempDir, uuid.New().String())
+	defer os.Remove(tempPath)
+	if err := s.fileRepo.FetchContent(ctx, src, uid); err != nil {
+		return nil, err
+	}
+	if err := util.CopyFile(src.SavePath, tempPath); err != nil {
+		return nil, err
+	}
+
+	now := timex.Now().UnixMilli()
+	_, file, err := s.UpdateOrCreate(ctx, uid, &dto.FileUpdateRequest{
+		Vault:       params.Vault,
+		Path:        newPath,
+		PathHash:    util.EncodeHash32(newPath),
+		ContentHash: src.ContentHash,
+		SavePath:    tempPath,
+		Size:        src.Size,
+		Ctime:       now,
+		Mtime:       now,
+	}, false)
+	return file, err
+}
+
 // Rename renames a file
 // Rename 重命名文件
 func (s *fileService) Rename(ctx context.Context, uid int64, params *dto.FileRenameRequest) (*dto.FileDTO, *dto.FileDTO, error) {
@@ -815,6 +957,10 @@ func (s *fileService) Rename(ctx context.Context, uid int64, params *dto.FileRen
 		oldPathHash = util.EncodeHash32(oldPath)
 	}
 
+	if err := s.folderService.CheckWritable(ctx, uid, vaultID, oldPath, newPath); err != nil {
+		return nil, nil, err
+	}
+
 	key := fmt.Sprintf("rename_%d_%d_%s_%s", uid, vaultID, oldPathHash, newPathHash)
 	type result struct {
 		oldFile *dto.FileDTO
@@ -944,6 +1090,7 @@ func (s *fileService) WithClient(clientType, name, version string) FileService {
 		vaultService:   s.vaultService,
 		folderService:  s.folderService,
 		syncLogService: s.syncLogService,
+		retention:      s.retention,
 		sf:             s.sf,
 		kmu:            s.kmu,
 		clientType:     clientType,
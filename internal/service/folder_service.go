@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
@@ -15,6 +16,7 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"github.com/haierkeys/fast-note-sync-service/pkg/workerpool"
+	"go.uber.org/zap"
 	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
 )
@@ -28,6 +30,9 @@ type FolderService interface {
 	Delete(ctx context.Context, uid int64, params *dto.FolderDeleteRequest) (*dto.FolderDTO, error)
 	DeleteTree(ctx context.Context, uid int64, params *dto.FolderDeleteRequest) (*dto.FolderDTO, error)
 	Rename(ctx context.Context, uid int64, params *dto.FolderRenameRequest) (*dto.FolderDTO, *dto.FolderDTO, error)
+	// Move moves a folder with all its subfolders, notes and files to a new path
+	// Move 将文件夹及其所有子文件夹、笔记和文件移动到新路径
+	Move(ctx context.Context, uid int64, params *dto.FolderMoveRequest) (*dto.FolderMoveResult, error)
 	ListNotes(ctx context.Context, uid int64, params *dto.FolderContentRequest, pager *app.Pager) ([]*dto.NoteNoContentDTO, int, error)
 	ListFiles(ctx context.Context, uid int64, params *dto.FolderContentRequest, pager *app.Pager) ([]*dto.FileDTO, int, error)
 	EnsurePathFID(ctx context.Context, uid int64, vaultID int64, path string) (int64, error)
@@ -35,6 +40,15 @@ type FolderService interface {
 	SyncResourceFID(ctx context.Context, uid int64, vaultID int64, noteIDs []int64, fileIDs []int64) error
 	GetTree(ctx context.Context, uid int64, params *dto.FolderTreeRequest) (*dto.FolderTreeResponse, error)
 	CleanDuplicateFolders(ctx context.Context, uid int64, vaultID int64) error
+	// SetArchived marks a folder as archived (read-only) or clears the flag
+	// SetArchived 将文件夹标记为归档（只读）或取消归档
+	SetArchived(ctx context.Context, uid int64, params *dto.FolderArchiveRequest) (*dto.FolderDTO, error)
+	// CheckWritable returns ErrorFolderArchived if any path lies inside an archived folder
+	// CheckWritable 若任一路径位于归档文件夹内，返回 ErrorFolderArchived
+	CheckWritable(ctx context.Context, uid int64, vaultID int64, paths ...string) error
+	// ArchivedPaths returns the paths of archived folders in a vault
+	// ArchivedPaths 返回仓库中归档文件夹的路径
+	ArchivedPaths(ctx context.Context, uid int64, vaultID int64) ([]string, error)
 	WithClient(clientType, clientName, clientVersion string) FolderService
 }
 
@@ -48,6 +62,7 @@ type folderService struct {
 	gitSyncService GitSyncService
 	pool           *workerpool.Pool
 	syncLogService SyncLogService
+	archived       *archivedFolderCache // Archived folder paths per user/vault // 按用户/仓库缓存的归档文件夹路径
 	clientType     string
 	clientName     string
 	clientVersion  string
@@ -64,6 +79,7 @@ func NewFolderService(folderRepo domain.FolderRepository, noteRepo domain.NoteRe
 		syncLogService: syncLogSvc,
 		pool:           pool,
 		sf:             &singleflight.Group{},
+		archived:       &archivedFolderCache{paths: make(map[string][]string)},
 	}
 }
 
@@ -78,6 +94,7 @@ func (s *folderService) domainToDTO(f *domain.Folder) *dto.FolderDTO {
 		PathHash:         f.PathHash,
 		Level:            f.Level,
 		FID:              f.FID,
+		Archived:         f.Archived,
 		Ctime:            f.Ctime,
 		Mtime:            f.Mtime,
 		UpdatedTimestamp: f.UpdatedTimestamp,
@@ -141,6 +158,10 @@ func (s *folderService) UpdateOrCreate(ctx context.Context, uid int64, params *d
 		params.PathHash = util.EncodeHash32(params.Path)
 	}
 
+	if err := s.CheckWritable(ctx, uid, vaultID, params.Path); err != nil {
+		return nil, err
+	}
+
 	// Unified call to EnsurePathFID
 	// 统一调用 EnsurePathFID
 	fid, err := s.EnsurePathFID(ctx, uid, vaultID, params.Path)
@@ -187,6 +208,10 @@ func (s *folderService) Delete(ctx context.Context, uid int64, params *dto.Folde
 		return nil, code.ErrorFolderGetFailed.WithDetails(err.Error())
 	}
 
+	if err := s.checkFolderWritable(ctx, uid, vaultID, f.Path); err != nil {
+		return nil, err
+	}
+
 	// Update to deleted status
 	// 更新为删除状态
 	f.Action = domain.FolderActionDelete
@@ -220,6 +245,9 @@ func (s *folderService) DeleteTree(ctx context.Context, uid int64, params *dto.F
 	if params.PathHash == "" {
 		params.PathHash = util.EncodeHash32(params.Path)
 	}
+	if err := s.checkFolderWritable(ctx, uid, vaultID, params.Path); err != nil {
+		return nil, err
+	}
 
 	rootFolders, err := s.folderRepo.GetAllByPathHash(ctx, params.PathHash, vaultID, uid)
 	if err != nil {
@@ -346,6 +374,13 @@ func (s *folderService) Rename(ctx context.Context, uid int64, params *dto.Folde
 		params.OldPathHash = util.EncodeHash32(params.OldPath)
 	}
 
+	if err := s.checkFolderWritable(ctx, uid, vaultID, params.OldPath); err != nil {
+		return nil, nil, err
+	}
+	if err := s.CheckWritable(ctx, uid, vaultID, params.Path); err != nil {
+		return nil, nil, err
+	}
+
 	// Rename renames a folder
 	// Rename 重命名文件夹
 	oldFolder, err := s.folderRepo.GetByPathHash(ctx, params.OldPathHash, vaultID, uid)
@@ -412,6 +447,181 @@ func (s *folderService) Rename(ctx context.Context, uid int64, params *dto.Folde
 	return s.domainToDTO(oldFolder), s.domainToDTO(newFolderCreated), nil
 }
 
+// Move moves a folder with all its subfolders, notes and files to a new path.
+// Unlike Rename, which only renames the folder record and relies on sync clients renaming the contents
+// themselves, Move rewrites every descendant. Folders, notes and files each move in one transaction of
+// their own table; the tables live in separate databases, so when a later step fails the steps already
+// committed are moved back.
+// Move 将文件夹及其所有子文件夹、笔记和文件移动到新路径。
+// 与只重命名文件夹记录、依赖同步客户端自行重命名内容的 Rename 不同，Move 会改写所有后代资源。
+// 文件夹、笔记和文件各自在所属表的单个事务内移动；三张表位于不同数据库，因此后续步骤失败时会将已提交的步骤移回原路径。
+func (s *folderService) Move(ctx context.Context, uid int64, params *dto.FolderMoveRequest) (*dto.FolderMoveResult, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+
+	oldPath := strings.Trim(params.OldPath, "/")
+	newPath := strings.Trim(params.Path, "/")
+	if oldPath == "" || newPath == "" {
+		return nil, code.ErrorInvalidParams.WithDetails("path cannot be empty")
+	}
+	if newPath == oldPath || strings.HasPrefix(newPath, oldPath+"/") {
+		return nil, code.ErrorInvalidParams.WithDetails("cannot move a folder into itself")
+	}
+	if err := s.checkFolderWritable(ctx, uid, vaultID, oldPath); err != nil {
+		return nil, err
+	}
+	if err := s.CheckWritable(ctx, uid, vaultID, newPath); err != nil {
+		return nil, err
+	}
+
+	roots, err := s.folderRepo.GetAllByPathHash(ctx, util.EncodeHash32(oldPath), vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorFolderGetFailed.WithDetails(err.Error())
+	}
+	if len(roots) == 0 {
+		return nil, code.ErrorFolderNotFound
+	}
+
+	// The destination must not hold a folder or any resource yet
+	// 目标路径下不能已存在文件夹或任何资源
+	existing, err := s.folderRepo.GetAllByPathHash(ctx, util.EncodeHash32(newPath), vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorFolderGetFailed.WithDetails(err.Error())
+	}
+	if len(existing) > 0 {
+		return nil, code.ErrorFolderExist.WithDetails(newPath)
+	}
+	if notes, err := s.noteRepo.ListByPathPrefix(ctx, newPath, vaultID, uid); err != nil {
+		return nil, code.ErrorNoteListFailed.WithDetails(err.Error())
+	} else if len(notes) > 0 {
+		return nil, code.ErrorFolderExist.WithDetails(newPath)
+	}
+	if files, err := s.fileRepo.ListByPathPrefix(ctx, newPath, vaultID, uid); err != nil {
+		return nil, code.ErrorFileListFailed.WithDetails(err.Error())
+	} else if len(files) > 0 {
+		return nil, code.ErrorFolderExist.WithDetails(newPath)
+	}
+
+	fid, err := s.EnsurePathFID(ctx, uid, vaultID, parentPath(newPath))
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	folderMoves, err := s.folderRepo.MoveByPathPrefix(ctx, oldPath, newPath, fid, vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorFolderRenameFailed.WithDetails(err.Error())
+	}
+	fids := make(map[string]int64, len(folderMoves))
+	for _, mv := range folderMoves {
+		fids[mv.New.Path] = mv.New.ID
+	}
+	noteMoves, err := s.noteRepo.MoveByPathPrefix(ctx, oldPath, newPath, fids, vaultID, uid)
+	if err != nil {
+		s.undoMove(ctx, uid, vaultID, oldPath, newPath, false)
+		return nil, code.ErrorFolderRenameFailed.WithDetails(err.Error())
+	}
+	fileMoves, err := s.fileRepo.MoveByPathPrefix(ctx, oldPath, newPath, fids, vaultID, uid)
+	if err != nil {
+		s.undoMove(ctx, uid, vaultID, oldPath, newPath, len(noteMoves) > 0)
+		return nil, code.ErrorFolderRenameFailed.WithDetails(err.Error())
+	}
+
+	result := &dto.FolderMoveResult{
+		Folders: make([]*dto.FolderSyncRenameMessage, 0, len(folderMoves)),
+		Notes:   make([]*dto.NoteSyncRenameMessage, 0, len(noteMoves)),
+		Files:   make([]*dto.FileSyncRenameMessage, 0, len(fileMoves)),
+	}
+	for _, mv := range folderMoves {
+		result.Folders = append(result.Folders, &dto.FolderSyncRenameMessage{
+			Path:             mv.New.Path,
+			PathHash:         mv.New.PathHash,
+			Ctime:            mv.New.Ctime,
+			Mtime:            mv.New.Mtime,
+			OldPath:          mv.Old.Path,
+			OldPathHash:      mv.Old.PathHash,
+			UpdatedTimestamp: mv.New.UpdatedTimestamp,
+		})
+		if s.syncLogService != nil {
+			s.syncLogService.Log(uid, vaultID, domain.SyncLogTypeFolder, domain.SyncLogActionRename, "path", mv.New.Path, mv.New.PathHash, s.clientType, s.clientName, s.clientVersion, 0)
+		}
+	}
+
+	// Resources below folders without a record get their FID fixed in the background
+	// 位于无记录文件夹下的资源，其 FID 在后台修正
+	var orphanNoteIDs, orphanFileIDs []int64
+	for _, mv := range noteMoves {
+		result.Notes = append(result.Notes, &dto.NoteSyncRenameMessage{
+			Path:             mv.New.Path,
+			PathHash:         mv.New.PathHash,
+			ContentHash:      mv.New.ContentHash,
+			Ctime:            mv.New.Ctime,
+			Mtime:            mv.New.Mtime,
+			Size:             mv.New.Size,
+			OldPath:          mv.Old.Path,
+			OldPathHash:      mv.Old.PathHash,
+			UpdatedTimestamp: mv.New.UpdatedTimestamp,
+		})
+		if mv.New.FID == 0 {
+			orphanNoteIDs = append(orphanNoteIDs, mv.New.ID)
+		}
+		if s.syncLogService != nil {
+			s.syncLogService.Log(uid, vaultID, domain.SyncLogTypeNote, domain.SyncLogActionRename, "path", mv.New.Path, mv.New.PathHash, s.clientType, s.clientName, s.clientVersion, mv.New.Size)
+		}
+	}
+	for _, mv := range fileMoves {
+		result.Files = append(result.Files, &dto.FileSyncRenameMessage{
+			Path:             mv.New.Path,
+			PathHash:         mv.New.PathHash,
+			ContentHash:      mv.New.ContentHash,
+			Ctime:            mv.New.Ctime,
+			Mtime:            mv.New.Mtime,
+			Size:             mv.New.Size,
+			UpdatedTimestamp: mv.New.UpdatedTimestamp,
+			OldPath:          mv.Old.Path,
+			OldPathHash:      mv.Old.PathHash,
+		})
+		if mv.New.FID == 0 {
+			orphanFileIDs = append(orphanFileIDs, mv.New.ID)
+		}
+		if s.syncLogService != nil {
+			s.syncLogService.Log(uid, vaultID, domain.SyncLogTypeFile, domain.SyncLogActionRename, "path", mv.New.Path, mv.New.PathHash, s.clientType, s.clientName, s.clientVersion, mv.New.Size)
+		}
+	}
+
+	if len(noteMoves) > 0 {
+		// History, shares and links follow the new note IDs through the migration task
+		// 历史记录、分享与链接通过迁移任务跟随新的笔记 ID
+		go func() {
+			for _, mv := range noteMoves {
+				NoteMigrateChannel <- NoteMigrateMsg{OldNoteID: mv.Old.ID, NewNoteID: mv.New.ID, UID: uid}
+			}
+		}()
+	}
+	if len(orphanNoteIDs) > 0 || len(orphanFileIDs) > 0 {
+		if err := s.SyncResourceFID(ctx, uid, vaultID, orphanNoteIDs, orphanFileIDs); err != nil {
+			zap.L().Warn("folderService.Move: sync resource FID failed", zap.Int64("uid", uid), zap.Error(err))
+		}
+	}
+	if err := s.CleanupEmptyAncestors(ctx, uid, vaultID, oldPath); err != nil {
+		zap.L().Warn("folderService.Move: cleanup empty ancestor folders failed",
+			zap.Int64("uid", uid),
+			zap.Int64("vaultID", vaultID),
+			zap.String("oldPath", oldPath),
+			zap.Error(err),
+		)
+	}
+
+	if s.backupService != nil {
+		s.backupService.NotifyUpdated(uid)
+	}
+	if s.gitSyncService != nil && (len(noteMoves) > 0 || len(fileMoves) > 0) {
+		s.gitSyncService.NotifyUpdated(uid, vaultID)
+	}
+	return result, nil
+}
+
 func (s *folderService) Get(ctx context.Context, uid int64, params *dto.FolderGetRequest) (*dto.FolderDTO, error) {
 	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
 	if err != nil {
@@ -482,6 +692,7 @@ func (s *folderService) ListNotes(ctx context.Context, uid int64, params *dto.Fo
 			Version:          n.Version,
 			Ctime:            n.Ctime,
 			Mtime:            n.Mtime,
+			Size:             n.Size,
 			UpdatedTimestamp: n.UpdatedTimestamp,
 			UpdatedAt:        timex.Time(n.UpdatedAt),
 			CreatedAt:        timex.Time(n.CreatedAt),
@@ -541,100 +752,98 @@ func (s *folderService) ListFiles(ctx context.Context, uid int64, params *dto.Fo
 	return res, int(count), nil
 }
 
-// EnsurePathFID ensures all folders in path exist and returns the ID of the deepest folder
-// EnsurePathFID 确保路径中的所有文件夹都存在并返回最深文件夹的 ID
-//
-// NOTE: Race condition — when multiple notes sync concurrently (even from a
-// single device), each goroutine calls EnsurePathFID independently. The
-// check-then-create below (GetByPathHash → Create) is not atomic, so two
-// goroutines can both see "not found" and both insert a folder record for the
-// same path. This produces duplicate rows in the folder table (confirmed via
-// direct DB inspection — e.g. 3 rows for "projects" with IDs 4,5,6).
-//
-// The query-side methods (List, ListNotes, ListFiles, GetTree) handle this by
-// resolving all folder IDs per path (GetAllByPathHash + FID IN queries).
-//
-// A proper fix would be to either:
-//   - Use singleflight keyed by (vaultID, path) to coalesce concurrent creates
-//   - Add a UNIQUE constraint on (vault_id, path_hash) and handle conflict
-// ensurePathFIDSingle performs the underlying lookup or creation of a folder.
-// ensurePathFIDSingle 执行底层的文件夹查询或创建。
-func (s *folderService) ensurePathFIDSingle(ctx context.Context, uid int64, vaultID int64, pathHash string, currentPath string, currentFID int64, level int) (any, error) {
-	f, err := s.folderRepo.GetByPathHash(ctx, pathHash, vaultID, uid)
+// undoMove moves the folders, and the notes when they were moved too, from newPath back to oldPath after a
+// later step of Move failed. The moved back records reuse the tombstoned originals, so their IDs are kept.
+// undoMove 在 Move 的后续步骤失败后，将文件夹（以及已移动的笔记）从 newPath 移回 oldPath。
+// 移回的记录复用被标记删除的原记录，因此 ID 保持不变。
+func (s *folderService) undoMove(ctx context.Context, uid, vaultID int64, oldPath, newPath string, notesMoved bool) {
+	// The request may already be cancelled, the undo must still run // 请求可能已被取消，撤销仍需执行
+	ctx = context.WithoutCancel(ctx)
+	fail := func(step string, err error) {
+		zap.L().Error("folderService.Move: undo failed, resources are left under the new path",
+			zap.Int64("uid", uid),
+			zap.Int64("vaultID", vaultID),
+			zap.String("oldPath", oldPath),
+			zap.String("newPath", newPath),
+			zap.String("step", step),
+			zap.Error(err),
+		)
+	}
+
+	fid, err := s.EnsurePathFID(ctx, uid, vaultID, parentPath(oldPath))
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			newFolder := &domain.Folder{
-				VaultID:  vaultID,
-				Action:   domain.FolderActionCreate,
-				Path:     currentPath,
-				PathHash: pathHash,
-				Level:    int64(level + 1),
-				FID:      currentFID,
-				Ctime:    timex.Now().UnixMilli(),
-				Mtime:    timex.Now().UnixMilli(),
-			}
-			f, err = s.folderRepo.Create(ctx, newFolder, uid)
-			if err != nil {
-				return 0, err
-			}
-
-			if s.syncLogService != nil {
-				s.syncLogService.Log(uid, vaultID, domain.SyncLogTypeFolder, domain.SyncLogActionCreate, "", f.Path, f.PathHash, s.clientType, s.clientName, s.clientVersion, 0)
-			}
-			return f.ID, nil
-		}
-		return nil, err
-	} else if f.Action == domain.FolderActionDelete {
-		f.Action = domain.FolderActionCreate
-		f.Ctime = timex.Now().UnixMilli()
-		f.Mtime = timex.Now().UnixMilli()
-		f, err = s.folderRepo.Update(ctx, f, uid)
-		if err != nil {
-			return nil, err
-		}
-
-		if s.syncLogService != nil {
-			s.syncLogService.Log(uid, vaultID, domain.SyncLogTypeFolder, domain.SyncLogActionRestore, "", f.Path, f.PathHash, s.clientType, s.clientName, s.clientVersion, 0)
-		}
-		return f.ID, nil
+		fail("folder", err)
+		return
+	}
+	folderMoves, err := s.folderRepo.MoveByPathPrefix(ctx, newPath, oldPath, fid, vaultID, uid)
+	if err != nil {
+		fail("folder", err)
+		return
+	}
+	if !notesMoved {
+		return
+	}
+	fids := make(map[string]int64, len(folderMoves))
+	for _, mv := range folderMoves {
+		fids[mv.New.Path] = mv.New.ID
+	}
+	if _, err := s.noteRepo.MoveByPathPrefix(ctx, newPath, oldPath, fids, vaultID, uid); err != nil {
+		fail("note", err)
 	}
-	return f.ID, nil
 }
 
+// EnsurePathFID ensures all folders in path exist and returns the ID of the deepest folder
+// EnsurePathFID 确保路径中的所有文件夹都存在并返回最深文件夹的 ID
+//
+// Concurrent syncs of notes under the same directory all call EnsurePathFID. Calls for the same path are
+// coalesced by singleflight, missing levels are created in one repository transaction, and the
+// UNIQUE(vault_id, path_hash) index turns any remaining insert race into a lookup of the winning row.
+// 同一目录下笔记的并发同步都会调用 EnsurePathFID。同一路径的调用由 singleflight 合并，缺失层级在单个仓储事务内创建，
+// UNIQUE(vault_id, path_hash) 索引则将剩余的插入竞争转为查询胜出的记录。
 func (s *folderService) EnsurePathFID(ctx context.Context, uid int64, vaultID int64, path string) (int64, error) {
 	path = strings.Trim(path, "/")
 	if path == "" {
 		return 0, nil
 	}
 
-	// Decompose directory
-	// 分解目录
-	parts := strings.Split(path, "/")
-	var currentFID int64 = 0
-
-	for i := range parts {
-		currentPath := strings.Join(parts[:i+1], "/")
-		pathHash := util.EncodeHash32(currentPath)
-
-		key := fmt.Sprintf("ensure_folder_%d_%d_%s", uid, vaultID, pathHash)
-		var val any
-		var err error
-		if s.sf != nil {
-			var _ bool
-			val, err, _ = s.sf.Do(key, func() (any, error) {
-				return s.ensurePathFIDSingle(ctx, uid, vaultID, pathHash, currentPath, currentFID, i)
-			})
-		} else {
-			val, err = s.ensurePathFIDSingle(ctx, uid, vaultID, pathHash, currentPath, currentFID, i)
-		}
-
+	ensure := func() (any, error) {
+		results, err := s.folderRepo.EnsurePath(ctx, path, vaultID, uid)
 		if err != nil {
-			return 0, err
+			return int64(0), err
 		}
+		var fid int64
+		for _, res := range results {
+			f := res.Folder
+			fid = f.ID
+			switch {
+			case res.Created:
+				if s.syncLogService != nil {
+					s.syncLogService.Log(uid, vaultID, domain.SyncLogTypeFolder, domain.SyncLogActionCreate, "", f.Path, f.PathHash, s.clientType, s.clientName, s.clientVersion, 0)
+				}
+			case res.Restored:
+				if f.Archived {
+					s.archived.invalidate(uid, vaultID)
+				}
+				if s.syncLogService != nil {
+					s.syncLogService.Log(uid, vaultID, domain.SyncLogTypeFolder, domain.SyncLogActionRestore, "", f.Path, f.PathHash, s.clientType, s.clientName, s.clientVersion, 0)
+				}
+			}
+		}
+		return fid, nil
+	}
 
-		currentFID = val.(int64)
+	var val any
+	var err error
+	if s.sf != nil {
+		key := fmt.Sprintf("ensure_folder_%d_%d_%s", uid, vaultID, util.EncodeHash32(path))
+		val, err, _ = s.sf.Do(key, ensure)
+	} else {
+		val, err = ensure()
 	}
-	return currentFID, nil
+	if err != nil {
+		return 0, err
+	}
+	return val.(int64), nil
 }
 
 func (s *folderService) CleanupEmptyAncestors(ctx context.Context, uid int64, vaultID int64, resourcePath string) error {
@@ -843,9 +1052,10 @@ func (s *folderService) GetTree(ctx context.Context, uid int64, params *dto.Fold
 
 	// Deduplicate by path, collect all IDs per path for counting
 	type folderInfo struct {
-		path   string
-		ids    []int64 // all DB IDs for this path (for counting notes/files)
-		parent string  // parent path ("" for root)
+		path     string
+		ids      []int64 // all DB IDs for this path (for counting notes/files)
+		parent   string  // parent path ("" for root)
+		archived bool
 	}
 	infoByPath := make(map[string]*folderInfo)
 
@@ -863,6 +1073,7 @@ func (s *folderService) GetTree(ctx context.Context, uid int64, params *dto.Fold
 			infoByPath[f.Path] = info
 		}
 		info.ids = append(info.ids, f.ID)
+		info.archived = info.archived || f.Archived
 	}
 
 	// Count notes and files per folder (sum across all duplicate IDs).
@@ -902,6 +1113,15 @@ func (s *folderService) GetTree(ctx context.Context, uid int64, params *dto.Fold
 		rootFileCount = int(count)
 	}
 
+	var readmes map[string]*dto.FolderReadme
+	if params.IncludeReadme {
+		paths := make([]string, 0, len(infoByPath))
+		for path := range infoByPath {
+			paths = append(paths, path)
+		}
+		readmes = s.folderReadmes(ctx, uid, vaultID, paths)
+	}
+
 	// Build parent→children map by path
 	childrenByParent := make(map[string][]string)
 	for path, info := range infoByPath {
@@ -921,6 +1141,8 @@ func (s *folderService) GetTree(ctx context.Context, uid int64, params *dto.Fold
 			Name:      name,
 			NoteCount: noteCountByPath[path],
 			FileCount: fileCountByPath[path],
+			Archived:  infoByPath[path].archived,
+			Readme:    readmes[path],
 		}
 
 		if params.Depth > 0 && currentDepth >= params.Depth {
@@ -947,6 +1169,73 @@ func (s *folderService) GetTree(ctx context.Context, uid int64, params *dto.Fold
 	}, nil
 }
 
+// folderReadmeNames note names treated as a folder's README, in priority order
+// folderReadmeNames 视为文件夹 README 的笔记名，按优先级排列
+var folderReadmeNames = []string{"README.md", "Readme.md", "readme.md", "index.md", "Index.md"}
+
+const (
+	// folderReadmeExcerptRunes maximum length of a folder README excerpt
+	// folderReadmeExcerptRunes 文件夹 README 摘要的最大长度
+	folderReadmeExcerptRunes = 280
+	// folderReadmeBatch path hashes per existence query
+	// folderReadmeBatch 每次存在性查询的路径哈希数量
+	folderReadmeBatch = 500
+)
+
+// folderReadmes finds the README.md / index.md of each folder and builds its summary.
+// Existence is checked in batched metadata queries; content is only loaded for folders that have one.
+// folderReadmes 查找每个文件夹的 README.md / index.md 并生成摘要。
+// 通过批量元数据查询判断是否存在，仅对存在 README 的文件夹加载正文。
+func (s *folderService) folderReadmes(ctx context.Context, uid, vaultID int64, folderPaths []string) map[string]*dto.FolderReadme {
+	result := make(map[string]*dto.FolderReadme)
+	if len(folderPaths) == 0 {
+		return result
+	}
+
+	hashes := make([]string, 0, len(folderPaths)*len(folderReadmeNames))
+	for _, folderPath := range folderPaths {
+		for _, name := range folderReadmeNames {
+			hashes = append(hashes, util.EncodeHash32(folderPath+"/"+name))
+		}
+	}
+
+	found := make(map[string]*domain.Note, len(folderPaths))
+	for start := 0; start < len(hashes); start += folderReadmeBatch {
+		end := min(start+folderReadmeBatch, len(hashes))
+		metas, err := s.noteRepo.ListByPathHashesMeta(ctx, hashes[start:end], vaultID, uid)
+		if err != nil {
+			return result
+		}
+		for hash, note := range metas {
+			found[hash] = note
+		}
+	}
+
+	for _, folderPath := range folderPaths {
+		for _, name := range folderReadmeNames {
+			meta := found[util.EncodeHash32(folderPath+"/"+name)]
+			if meta == nil || meta.IsDeleted() || meta.Path != folderPath+"/"+name {
+				continue
+			}
+			note, err := s.noteRepo.GetByID(ctx, meta.ID, uid)
+			if err != nil || note == nil {
+				break
+			}
+			title, excerpt := util.MarkdownExcerpt(note.Content, folderReadmeExcerptRunes)
+			result[folderPath] = &dto.FolderReadme{
+				Path:             note.Path,
+				Title:            title,
+				Excerpt:          excerpt,
+				Size:             note.Size,
+				Mtime:            note.Mtime,
+				UpdatedTimestamp: note.UpdatedTimestamp,
+			}
+			break
+		}
+	}
+	return result
+}
+
 func (s *folderService) CleanDuplicateFolders(ctx context.Context, uid int64, vaultID int64) error {
 	// 1. Get all folder records (including deleted ones for logical cleanup)
 	// 1. 获取所有文件夹记录（包含已删除的，以便按逻辑清理）
@@ -1013,3 +1302,152 @@ func (s *folderService) CleanDuplicateFolders(ctx context.Context, uid int64, va
 
 	return nil
 }
+
+// archivedFolderCache caches archived folder paths keyed by uid and vault ID
+// archivedFolderCache 按 uid 与仓库 ID 缓存归档文件夹路径
+type archivedFolderCache struct {
+	mu    sync.RWMutex
+	paths map[string][]string
+}
+
+func archivedCacheKey(uid, vaultID int64) string {
+	return fmt.Sprintf("%d_%d", uid, vaultID)
+}
+
+func (c *archivedFolderCache) get(uid, vaultID int64) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	paths, ok := c.paths[archivedCacheKey(uid, vaultID)]
+	return paths, ok
+}
+
+func (c *archivedFolderCache) set(uid, vaultID int64, paths []string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.paths[archivedCacheKey(uid, vaultID)] = paths
+	c.mu.Unlock()
+}
+
+func (c *archivedFolderCache) invalidate(uid, vaultID int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.paths, archivedCacheKey(uid, vaultID))
+	c.mu.Unlock()
+}
+
+// SetArchived marks a folder as archived (read-only) or clears the flag
+// SetArchived 将文件夹标记为归档（只读）或取消归档
+func (s *folderService) SetArchived(ctx context.Context, uid int64, params *dto.FolderArchiveRequest) (*dto.FolderDTO, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+
+	params.Path = strings.Trim(params.Path, "/")
+	if params.Path == "" {
+		return nil, code.ErrorInvalidParams.WithDetails("path cannot be empty")
+	}
+	if params.PathHash == "" {
+		params.PathHash = util.EncodeHash32(params.Path)
+	}
+
+	folders, err := s.folderRepo.GetAllByPathHash(ctx, params.PathHash, vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorFolderGetFailed.WithDetails(err.Error())
+	}
+	if len(folders) == 0 {
+		return nil, code.ErrorFolderNotFound
+	}
+
+	// Archiving inside an archived folder is redundant, unarchiving inside one is not allowed
+	// 在归档文件夹内部再归档没有意义，在其内部取消归档不被允许
+	if err := s.CheckWritable(ctx, uid, vaultID, parentPath(params.Path)); err != nil {
+		return nil, err
+	}
+
+	if err := s.folderRepo.SetArchived(ctx, params.PathHash, vaultID, params.Archived, uid); err != nil {
+		return nil, code.ErrorFolderModifyOrCreateFailed.WithDetails(err.Error())
+	}
+	s.archived.invalidate(uid, vaultID)
+
+	f, err := s.folderRepo.GetByID(ctx, folders[0].ID, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	if s.syncLogService != nil {
+		s.syncLogService.Log(uid, vaultID, domain.SyncLogTypeFolder, domain.SyncLogActionModify, "archived", f.Path, f.PathHash, s.clientType, s.clientName, s.clientVersion, 0)
+	}
+
+	return s.domainToDTO(f), nil
+}
+
+// ArchivedPaths returns the paths of archived folders in a vault
+// ArchivedPaths 返回仓库中归档文件夹的路径
+func (s *folderService) ArchivedPaths(ctx context.Context, uid int64, vaultID int64) ([]string, error) {
+	if paths, ok := s.archived.get(uid, vaultID); ok {
+		return paths, nil
+	}
+
+	folders, err := s.folderRepo.ListArchived(ctx, vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorFolderListFailed.WithDetails(err.Error())
+	}
+
+	seen := make(map[string]bool, len(folders))
+	paths := make([]string, 0, len(folders))
+	for _, f := range folders {
+		p := strings.Trim(f.Path, "/")
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+	s.archived.set(uid, vaultID, paths)
+	return paths, nil
+}
+
+// CheckWritable returns ErrorFolderArchived if any path lies inside an archived folder
+// CheckWritable 若任一路径位于归档文件夹内，返回 ErrorFolderArchived
+func (s *folderService) CheckWritable(ctx context.Context, uid int64, vaultID int64, paths ...string) error {
+	archived, err := s.ArchivedPaths(ctx, uid, vaultID)
+	if err != nil || len(archived) == 0 {
+		return err
+	}
+	for _, p := range paths {
+		p = strings.Trim(p, "/")
+		if p == "" {
+			continue
+		}
+		for _, a := range archived {
+			if p == a || strings.HasPrefix(p, a+"/") {
+				return code.ErrorFolderArchived.WithDetails(a)
+			}
+		}
+	}
+	return nil
+}
+
+// checkFolderWritable is CheckWritable for folder operations, which also affect archived subfolders
+// checkFolderWritable 用于文件夹操作的 CheckWritable，同时考虑其下的归档子文件夹
+func (s *folderService) checkFolderWritable(ctx context.Context, uid int64, vaultID int64, path string) error {
+	archived, err := s.ArchivedPaths(ctx, uid, vaultID)
+	if err != nil {
+		return err
+	}
+	path = strings.Trim(path, "/")
+	for _, a := range archived {
+		if path == "" || path == a || strings.HasPrefix(path, a+"/") || strings.HasPrefix(a, path+"/") {
+			return code.ErrorFolderArchived.WithDetails(a)
+		}
+	}
+	return nil
+}
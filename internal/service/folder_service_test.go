@@ -4,11 +4,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -106,6 +108,7 @@ func TestFolderService_DeleteTree_RecursivelySoftDeletesChildrenAndResources(t *
 	folderRepo.On("ListByPathPrefix", mock.Anything, "Projects", vaultID, uid).Return([]*domain.Folder{child}, nil)
 	noteRepo.On("ListByPathPrefix", mock.Anything, "Projects", vaultID, uid).Return([]*domain.Note{note}, nil)
 	fileRepo.On("ListByPathPrefix", mock.Anything, "Projects", vaultID, uid).Return([]*domain.File{file}, nil)
+	folderRepo.On("ListArchived", mock.Anything, vaultID, uid).Return([]*domain.Folder{}, nil)
 
 	folderRepo.On("Update", mock.Anything, mock.MatchedBy(func(f *domain.Folder) bool {
 		return f.ID == child.ID && f.Action == domain.FolderActionDelete
@@ -200,3 +203,242 @@ func TestFolderService_CleanupEmptyAncestors_StopsAtFirstNonEmptyFolder(t *testi
 	noteRepo.AssertExpectations(t)
 	fileRepo.AssertExpectations(t)
 }
+
+func TestFolderService_CheckWritable_RejectsPathsInsideArchivedFolder(t *testing.T) {
+	ctx := context.Background()
+	uid := int64(1)
+	vaultID := int64(9)
+
+	folderRepo := new(domainmocks.MockFolderRepository)
+	folderRepo.On("ListArchived", mock.Anything, vaultID, uid).Return([]*domain.Folder{
+		{ID: 10, VaultID: vaultID, Path: "Archive/2023", Archived: true},
+	}, nil).Once()
+
+	svc := &folderService{
+		folderRepo: folderRepo,
+		archived:   &archivedFolderCache{paths: make(map[string][]string)},
+	}
+
+	err := svc.CheckWritable(ctx, uid, vaultID, "Archive/2023/plan.md")
+	assert.ErrorIs(t, err, code.ErrorFolderArchived)
+
+	assert.NoError(t, svc.CheckWritable(ctx, uid, vaultID, "Archive/2023-old/plan.md", "Archive/readme.md"))
+	assert.ErrorIs(t, svc.CheckWritable(ctx, uid, vaultID, "notes/a.md", "/Archive/2023/"), code.ErrorFolderArchived)
+
+	// Deleting a parent folder would take the archived subfolder with it
+	// 删除父文件夹会连带删除其下的归档子文件夹
+	assert.ErrorIs(t, svc.checkFolderWritable(ctx, uid, vaultID, "Archive"), code.ErrorFolderArchived)
+	assert.NoError(t, svc.checkFolderWritable(ctx, uid, vaultID, "Projects"))
+
+	// Archived paths are cached, so the repository is queried only once
+	// 归档路径已缓存，仓储只查询一次
+	folderRepo.AssertExpectations(t)
+}
+
+func TestFolderService_SetArchived_InvalidatesCache(t *testing.T) {
+	ctx := context.Background()
+	uid := int64(1)
+	vaultID := int64(9)
+	vaultName := "vault"
+
+	folderRepo := new(domainmocks.MockFolderRepository)
+	vaultRepo := new(domainmocks.MockVaultRepository)
+
+	folder := &domain.Folder{ID: 10, VaultID: vaultID, Action: domain.FolderActionCreate, Path: "Archive/2023", PathHash: util.EncodeHash32("Archive/2023")}
+	archived := *folder
+	archived.Archived = true
+
+	vaultRepo.On("GetByName", mock.Anything, vaultName, uid).Return(&domain.Vault{ID: vaultID, Name: vaultName}, nil)
+	folderRepo.On("GetAllByPathHash", mock.Anything, folder.PathHash, vaultID, uid).Return([]*domain.Folder{folder}, nil)
+	folderRepo.On("ListArchived", mock.Anything, vaultID, uid).Return([]*domain.Folder{}, nil).Once()
+	folderRepo.On("SetArchived", mock.Anything, folder.PathHash, vaultID, true, uid).Return(nil)
+	folderRepo.On("GetByID", mock.Anything, folder.ID, uid).Return(&archived, nil)
+
+	svc := &folderService{
+		folderRepo:   folderRepo,
+		vaultService: NewVaultService(vaultRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop()),
+		archived:     &archivedFolderCache{paths: make(map[string][]string)},
+		sf:           &singleflight.Group{},
+	}
+
+	got, err := svc.SetArchived(ctx, uid, &dto.FolderArchiveRequest{Vault: vaultName, Path: "/Archive/2023/", Archived: true})
+	assert.NoError(t, err)
+	assert.True(t, got.Archived)
+
+	folderRepo.On("ListArchived", mock.Anything, vaultID, uid).Return([]*domain.Folder{&archived}, nil).Once()
+	assert.ErrorIs(t, svc.CheckWritable(ctx, uid, vaultID, "Archive/2023/plan.md"), code.ErrorFolderArchived)
+	folderRepo.AssertExpectations(t)
+}
+
+func TestFolderService_FolderReadmes_PicksReadmeAndBuildsExcerpt(t *testing.T) {
+	ctx := context.Background()
+	uid := int64(1)
+	vaultID := int64(9)
+
+	readmeHash := util.EncodeHash32("docs/README.md")
+	indexHash := util.EncodeHash32("docs/index.md")
+
+	noteRepo := new(domainmocks.MockNoteRepository)
+	noteRepo.On("ListByPathHashesMeta", ctx, mock.Anything, vaultID, uid).Return(map[string]*domain.Note{
+		readmeHash: {ID: 5, Path: "docs/README.md", PathHash: readmeHash, Action: domain.NoteActionModify},
+		indexHash:  {ID: 6, Path: "docs/index.md", PathHash: indexHash, Action: domain.NoteActionModify},
+	}, nil)
+	noteRepo.On("GetByID", ctx, int64(5), uid).Return(&domain.Note{
+		ID:      5,
+		Path:    "docs/README.md",
+		Content: "# Docs\nEverything about the **project**.",
+		Size:    40,
+		Mtime:   1700000000,
+	}, nil)
+
+	svc := &folderService{noteRepo: noteRepo}
+	readmes := svc.folderReadmes(ctx, uid, vaultID, []string{"docs", "empty"})
+
+	assert.Len(t, readmes, 1)
+	assert.Equal(t, &dto.FolderReadme{
+		Path:    "docs/README.md",
+		Title:   "Docs",
+		Excerpt: "Everything about the project.",
+		Size:    40,
+		Mtime:   1700000000,
+	}, readmes["docs"])
+	noteRepo.AssertNotCalled(t, "GetByID", ctx, int64(6), uid)
+}
+
+// TestFolderService_Move_MovesDescendantsAndQueuesMigration verifies the folder, note and file moves are
+// chained through the new folder IDs and note history migration is queued for every moved note.
+// TestFolderService_Move_MovesDescendantsAndQueuesMigration 验证文件夹、笔记与文件的移动通过新文件夹 ID 串联，
+// 并为每条移动的笔记投递历史迁移任务。
+func TestFolderService_Move_MovesDescendantsAndQueuesMigration(t *testing.T) {
+	ctx := context.Background()
+	uid := int64(1)
+	vaultID := int64(9)
+	vaultName := "vault"
+
+	folderRepo := new(domainmocks.MockFolderRepository)
+	noteRepo := new(domainmocks.MockNoteRepository)
+	fileRepo := new(domainmocks.MockFileRepository)
+	vaultRepo := new(domainmocks.MockVaultRepository)
+
+	root := &domain.Folder{ID: 10, VaultID: vaultID, Action: domain.FolderActionCreate, Path: "Projects", PathHash: util.EncodeHash32("Projects")}
+	dest := &domain.Folder{ID: 5, VaultID: vaultID, Action: domain.FolderActionCreate, Path: "Archive", PathHash: util.EncodeHash32("Archive")}
+
+	vaultRepo.On("GetByName", mock.Anything, vaultName, uid).Return(&domain.Vault{ID: vaultID, Name: vaultName}, nil)
+	folderRepo.On("ListArchived", mock.Anything, vaultID, uid).Return([]*domain.Folder{}, nil)
+	folderRepo.On("GetAllByPathHash", mock.Anything, root.PathHash, vaultID, uid).Return([]*domain.Folder{root}, nil)
+	folderRepo.On("GetAllByPathHash", mock.Anything, util.EncodeHash32("Archive/Projects"), vaultID, uid).Return([]*domain.Folder{}, nil)
+	noteRepo.On("ListByPathPrefix", mock.Anything, "Archive/Projects", vaultID, uid).Return([]*domain.Note{}, nil)
+	fileRepo.On("ListByPathPrefix", mock.Anything, "Archive/Projects", vaultID, uid).Return([]*domain.File{}, nil)
+	folderRepo.On("EnsurePath", mock.Anything, "Archive", vaultID, uid).Return([]*domain.FolderEnsureResult{{Folder: dest}}, nil)
+
+	folderRepo.On("MoveByPathPrefix", mock.Anything, "Projects", "Archive/Projects", dest.ID, vaultID, uid).Return([]*domain.FolderMove{
+		{Old: root, New: &domain.Folder{ID: 12, Path: "Archive/Projects", FID: dest.ID}},
+		{Old: &domain.Folder{ID: 11, Path: "Projects/Docs"}, New: &domain.Folder{ID: 13, Path: "Archive/Projects/Docs", FID: 12}},
+	}, nil)
+	fids := map[string]int64{"Archive/Projects": 12, "Archive/Projects/Docs": 13}
+	noteRepo.On("MoveByPathPrefix", mock.Anything, "Projects", "Archive/Projects", fids, vaultID, uid).Return([]*domain.NoteMove{
+		{Old: &domain.Note{ID: 20, Path: "Projects/Docs/a.md"}, New: &domain.Note{ID: 21, Path: "Archive/Projects/Docs/a.md", FID: 13}},
+	}, nil)
+	fileRepo.On("MoveByPathPrefix", mock.Anything, "Projects", "Archive/Projects", fids, vaultID, uid).Return([]*domain.FileMove{
+		{Old: &domain.File{ID: 30, Path: "Projects/img.png"}, New: &domain.File{ID: 31, Path: "Archive/Projects/img.png", FID: 12}},
+	}, nil)
+
+	svc := &folderService{
+		folderRepo:   folderRepo,
+		noteRepo:     noteRepo,
+		fileRepo:     fileRepo,
+		vaultService: NewVaultService(vaultRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop()),
+		sf:           &singleflight.Group{},
+		archived:     &archivedFolderCache{paths: make(map[string][]string)},
+	}
+
+	got, err := svc.Move(ctx, uid, &dto.FolderMoveRequest{Vault: vaultName, OldPath: "Projects", Path: "/Archive/Projects/"})
+
+	assert.NoError(t, err)
+	assert.Len(t, got.Folders, 2)
+	assert.Equal(t, "Projects/Docs", got.Folders[1].OldPath)
+	assert.Equal(t, "Archive/Projects/Docs/a.md", got.Notes[0].Path)
+	assert.Equal(t, "Projects/img.png", got.Files[0].OldPath)
+	assert.Equal(t, NoteMigrateMsg{OldNoteID: 20, NewNoteID: 21, UID: uid}, <-NoteMigrateChannel)
+	folderRepo.AssertExpectations(t)
+	noteRepo.AssertExpectations(t)
+	fileRepo.AssertExpectations(t)
+}
+
+// TestFolderService_Move_UndoesCommittedStepsOnFailure verifies a failed file move moves the folders and
+// notes already moved back to the old path.
+// TestFolderService_Move_UndoesCommittedStepsOnFailure 验证文件移动失败时，已移动的文件夹与笔记被移回原路径。
+func TestFolderService_Move_UndoesCommittedStepsOnFailure(t *testing.T) {
+	ctx := context.Background()
+	uid := int64(1)
+	vaultID := int64(9)
+	vaultName := "vault"
+
+	folderRepo := new(domainmocks.MockFolderRepository)
+	noteRepo := new(domainmocks.MockNoteRepository)
+	fileRepo := new(domainmocks.MockFileRepository)
+	vaultRepo := new(domainmocks.MockVaultRepository)
+
+	root := &domain.Folder{ID: 10, VaultID: vaultID, Action: domain.FolderActionCreate, Path: "Projects", PathHash: util.EncodeHash32("Projects")}
+	dest := &domain.Folder{ID: 5, VaultID: vaultID, Action: domain.FolderActionCreate, Path: "Archive", PathHash: util.EncodeHash32("Archive")}
+
+	vaultRepo.On("GetByName", mock.Anything, vaultName, uid).Return(&domain.Vault{ID: vaultID, Name: vaultName}, nil)
+	folderRepo.On("ListArchived", mock.Anything, vaultID, uid).Return([]*domain.Folder{}, nil)
+	folderRepo.On("GetAllByPathHash", mock.Anything, root.PathHash, vaultID, uid).Return([]*domain.Folder{root}, nil)
+	folderRepo.On("GetAllByPathHash", mock.Anything, util.EncodeHash32("Archive/Projects"), vaultID, uid).Return([]*domain.Folder{}, nil)
+	noteRepo.On("ListByPathPrefix", mock.Anything, "Archive/Projects", vaultID, uid).Return([]*domain.Note{}, nil)
+	fileRepo.On("ListByPathPrefix", mock.Anything, "Archive/Projects", vaultID, uid).Return([]*domain.File{}, nil)
+	folderRepo.On("EnsurePath", mock.Anything, "Archive", vaultID, uid).Return([]*domain.FolderEnsureResult{{Folder: dest}}, nil)
+
+	folderRepo.On("MoveByPathPrefix", mock.Anything, "Projects", "Archive/Projects", dest.ID, vaultID, uid).Return([]*domain.FolderMove{
+		{Old: root, New: &domain.Folder{ID: 12, Path: "Archive/Projects", FID: dest.ID}},
+	}, nil)
+	noteRepo.On("MoveByPathPrefix", mock.Anything, "Projects", "Archive/Projects", map[string]int64{"Archive/Projects": 12}, vaultID, uid).Return([]*domain.NoteMove{
+		{Old: &domain.Note{ID: 20, Path: "Projects/a.md"}, New: &domain.Note{ID: 21, Path: "Archive/Projects/a.md", FID: 12}},
+	}, nil)
+	fileRepo.On("MoveByPathPrefix", mock.Anything, "Projects", "Archive/Projects", map[string]int64{"Archive/Projects": 12}, vaultID, uid).Return(nil, errors.New("disk full"))
+
+	// Undo: folders back first, then the notes onto the restored folder IDs
+	// 撤销：先移回文件夹，再将笔记移回到恢复后的文件夹 ID 下
+	folderRepo.On("MoveByPathPrefix", mock.Anything, "Archive/Projects", "Projects", int64(0), vaultID, uid).Return([]*domain.FolderMove{
+		{Old: &domain.Folder{ID: 12, Path: "Archive/Projects"}, New: &domain.Folder{ID: 10, Path: "Projects"}},
+	}, nil).Once()
+	noteRepo.On("MoveByPathPrefix", mock.Anything, "Archive/Projects", "Projects", map[string]int64{"Projects": 10}, vaultID, uid).Return([]*domain.NoteMove{
+		{Old: &domain.Note{ID: 21, Path: "Archive/Projects/a.md"}, New: &domain.Note{ID: 20, Path: "Projects/a.md", FID: 10}},
+	}, nil).Once()
+
+	svc := &folderService{
+		folderRepo:   folderRepo,
+		noteRepo:     noteRepo,
+		fileRepo:     fileRepo,
+		vaultService: NewVaultService(vaultRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop()),
+		sf:           &singleflight.Group{},
+		archived:     &archivedFolderCache{paths: make(map[string][]string)},
+	}
+
+	_, err := svc.Move(ctx, uid, &dto.FolderMoveRequest{Vault: vaultName, OldPath: "Projects", Path: "Archive/Projects"})
+
+	assert.ErrorIs(t, err, code.ErrorFolderRenameFailed)
+	folderRepo.AssertExpectations(t)
+	noteRepo.AssertExpectations(t)
+	fileRepo.AssertExpectations(t)
+}
+
+// TestFolderService_Move_RejectsMoveIntoItself verifies a folder cannot be moved below its own path.
+// TestFolderService_Move_RejectsMoveIntoItself 验证文件夹不能移动到自身路径之下。
+func TestFolderService_Move_RejectsMoveIntoItself(t *testing.T) {
+	vaultRepo := new(domainmocks.MockVaultRepository)
+	vaultRepo.On("GetByName", mock.Anything, "vault", int64(1)).Return(&domain.Vault{ID: 9, Name: "vault"}, nil)
+	folderRepo := new(domainmocks.MockFolderRepository)
+
+	svc := &folderService{
+		folderRepo:   folderRepo,
+		vaultService: NewVaultService(vaultRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop()),
+		sf:           &singleflight.Group{},
+	}
+
+	_, err := svc.Move(context.Background(), 1, &dto.FolderMoveRequest{Vault: "vault", OldPath: "Projects", Path: "Projects/Sub"})
+
+	assert.ErrorIs(t, err, code.ErrorInvalidParams)
+	folderRepo.AssertNotCalled(t, "MoveByPathPrefix", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -25,7 +25,9 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/healthping"
 	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
 	"go.uber.org/zap"
@@ -50,6 +52,9 @@ type GitSyncService interface {
 	CleanWorkspace(ctx context.Context, uid int64, configID int64) error
 	ListHistory(ctx context.Context, uid int64, configID int64, pager *pkgapp.Pager) ([]*dto.GitSyncHistoryDTO, int64, error)
 	NotifyUpdated(uid int64, vaultID int64)
+	// SetNotifier sets the notifier failed syncs are reported to
+	// SetNotifier 设置接收同步失败的通知器
+	SetNotifier(notifier Notifier)
 	Shutdown(ctx context.Context) error
 }
 
@@ -97,6 +102,7 @@ type gitSyncService struct {
 	enabledCache sync.Map    // gitSyncEnabledCacheKey -> *gitSyncEnabledCacheEntry
 	gcTimer      *time.Timer // Timer for delayed GC // 延迟 GC 定时器
 	gcMu         sync.Mutex  // Mutex for gcTimer // 保护 gcTimer 的互斥锁
+	notifier     Notifier    // Webhook notifier, may be nil // Webhook 通知器，可为 nil
 }
 
 // NewGitSyncService creates a GitSyncService instance
@@ -137,6 +143,7 @@ func (s *gitSyncService) domainToDTO(conf *domain.GitSyncConfig) *dto.GitSyncCon
 		LastMessage:     conf.LastMessage,
 		IncludeConfig:   conf.IncludeConfig,
 		ConfigSyncRules: conf.ConfigSyncRules,
+		PingURL:         conf.PingURL,
 		CreatedAt:       timex.Time(conf.CreatedAt),
 		UpdatedAt:       timex.Time(conf.UpdatedAt),
 	}
@@ -227,6 +234,7 @@ func (s *gitSyncService) UpdateConfig(ctx context.Context, uid int64, params *dt
 	conf.RetentionDays = params.RetentionDays
 	conf.IncludeConfig = params.IncludeConfig
 	conf.ConfigSyncRules = params.ConfigSyncRules
+	conf.PingURL = params.PingURL
 
 	saved, err := s.repo.Save(ctx, conf, uid)
 	if err != nil {
@@ -529,6 +537,12 @@ func (s *gitSyncService) ListHistory(ctx context.Context, uid int64, configID in
 	return res, count, nil
 }
 
+// SetNotifier sets the notifier failed syncs are reported to, nil disables notifications
+// SetNotifier 设置接收同步失败的通知器，为 nil 时不通知
+func (s *gitSyncService) SetNotifier(notifier Notifier) {
+	s.notifier = notifier
+}
+
 func (s *gitSyncService) Shutdown(ctx context.Context) error {
 	s.cancel()
 
@@ -597,6 +611,7 @@ func (s *gitSyncService) syncTask(ctx context.Context, conf *domain.GitSyncConfi
 	// Update Config Status to Running
 	conf.LastStatus = domain.GitSyncStatusRunning
 	_, _ = s.repo.Save(ctx, conf, conf.UID)
+	healthping.Go(s.logger, conf.PingURL, healthping.SignalStart, "")
 
 	err := s.doSync(ctx, conf)
 
@@ -608,6 +623,7 @@ func (s *gitSyncService) syncTask(ctx context.Context, conf *domain.GitSyncConfi
 		s.logger.Info("No changes found, skipping history and status update", zap.Int64("configId", conf.ID))
 		conf.LastStatus = prevStatus
 		_, _ = s.repo.Save(context.Background(), conf, conf.UID)
+		healthping.Go(s.logger, conf.PingURL, healthping.SignalSuccess, "No changes")
 		return
 	}
 
@@ -637,6 +653,23 @@ func (s *gitSyncService) syncTask(ctx context.Context, conf *domain.GitSyncConfi
 	conf.LastMessage = message
 	_, _ = s.repo.Save(context.Background(), conf, conf.UID)
 
+	// A shutdown is neither a success nor a failure of the job, it is not reported
+	// 关闭服务既不是任务成功也不是任务失败，不上报
+	switch finalStatus {
+	case domain.GitSyncStatusSuccess:
+		healthping.Go(s.logger, conf.PingURL, healthping.SignalSuccess, message)
+	case domain.GitSyncStatusFailed:
+		healthping.Go(s.logger, conf.PingURL, healthping.SignalFail, message)
+		if s.notifier != nil {
+			s.notifier.Notify(conf.UID, domain.WebhookEventGitSyncFailed, map[string]any{
+				"configId": conf.ID,
+				"vaultId":  conf.VaultID,
+				"branch":   conf.Branch,
+				"message":  message,
+			})
+		}
+	}
+
 	// Create History Record
 	h := &domain.GitSyncHistory{
 		ConfigID:  conf.ID,
@@ -1052,6 +1085,13 @@ func (s *gitSyncService) processFilesBatch(files []*domain.File, conf *domain.Gi
 
 		_ = os.MkdirAll(filepath.Dir(fullPath), 0755)
 
+		if err := s.fileRepo.FetchContent(context.Background(), f, conf.UID); err != nil {
+			s.logger.Warn("Failed to fetch attachment content from the content store",
+				zap.Int64("uid", conf.UID),
+				zap.String("path", f.Path),
+				zap.Error(err))
+		}
+
 		// Add physical file existence check to prevent source not existing from causing copyFileIfDifferent error interruption
 		// 增加物理文件存在性检查，防止 src 不存在导致 copyFileIfDifferent 报错中断
 		if _, err := os.Stat(f.SavePath); os.IsNotExist(err) {
@@ -1079,14 +1119,15 @@ func (s *gitSyncService) processFilesBatch(files []*domain.File, conf *domain.Gi
 }
 
 func (s *gitSyncService) copyFileIfDifferent(src, dst string) (bool, error) {
-	srcInfo, err := os.Stat(src)
+	srcFile, err := atrest.Open(src)
 	if err != nil {
 		return false, err
 	}
+	defer srcFile.Close()
 
 	dstInfo, err := os.Stat(dst)
 	if err == nil {
-		if srcInfo.Size() == dstInfo.Size() {
+		if srcFile.Size() == dstInfo.Size() {
 			// Sizes match, we could do deep comparison, but for sync service
 			// relying on size and potentially mtime/hash in DB is safer and faster.
 			// Here we assume if size matches, it's likely same (simplification to avoid full read).
@@ -1096,12 +1137,6 @@ func (s *gitSyncService) copyFileIfDifferent(src, dst string) (bool, error) {
 	}
 
 	// Streaming copy
-	srcFile, err := os.Open(src)
-	if err != nil {
-		return false, err
-	}
-	defer srcFile.Close()
-
 	dstFile, err := os.Create(dst)
 	if err != nil {
 		return false, err
@@ -0,0 +1,440 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// importMaxNoteSize notes larger than this are rejected instead of being read into memory
+	// importMaxNoteSize 超过该大小的笔记直接拒绝，避免整体读入内存
+	importMaxNoteSize = 32 * 1024 * 1024
+	// importProgressEvery report progress after this many processed entries
+	// importProgressEvery 每处理该数量的条目上报一次进度
+	importProgressEvery = 20
+	// importMaxErrors failure reasons kept in the result
+	// importMaxErrors 结果中保留的失败原因条数
+	importMaxErrors = 50
+)
+
+// ImportService defines the vault bulk import service interface
+// ImportService 定义保险库批量导入服务接口
+type ImportService interface {
+	// ImportZip imports the Markdown notes and attachments of a ZIP archive into a vault.
+	// progress, when not nil, is called periodically and once more with Done set at the end.
+	// ImportZip 将 ZIP 压缩包中的 Markdown 笔记和附件导入保险库。
+	// progress 不为 nil 时会被周期性调用，结束时再以 Done=true 调用一次。
+	ImportZip(ctx context.Context, uid int64, params *dto.VaultImportRequest, zipPath string, progress func(*dto.VaultImportProgressMessage)) (*dto.VaultImportResult, error)
+
+	// ImportDir imports the Markdown notes and attachments of a directory on the server, such as an
+	// Obsidian vault folder, keeping folder structure and file modification times. Hidden folders
+	// (.obsidian, .trash) and symlinks are skipped. progress behaves as in ImportZip.
+	// ImportDir 将服务器上某个目录（如 Obsidian 保险库目录）中的 Markdown 笔记和附件导入保险库，保留目录结构与文件修改时间。
+	// 隐藏目录（.obsidian、.trash）与符号链接会被跳过。progress 的行为与 ImportZip 相同。
+	ImportDir(ctx context.Context, uid int64, params *dto.VaultImportRequest, dirPath string, progress func(*dto.VaultImportProgressMessage)) (*dto.VaultImportResult, error)
+
+	// WithClient sets client info
+	// WithClient 设置客户端信息
+	WithClient(clientType, name, version string) ImportService
+}
+
+type importService struct {
+	vaultService  VaultService
+	noteService   NoteService
+	fileService   FileService
+	folderService FolderService
+	limits        *ContentLimits
+	tempPath      string
+	logger        *zap.Logger
+}
+
+// NewImportService creates ImportService instance
+// NewImportService 创建 ImportService 实例
+func NewImportService(vaultService VaultService, noteService NoteService, fileService FileService, folderService FolderService, limits *ContentLimits, tempPath string, logger *zap.Logger) ImportService {
+	if tempPath == "" {
+		tempPath = "storage/temp"
+	}
+	return &importService{
+		vaultService:  vaultService,
+		noteService:   noteService,
+		fileService:   fileService,
+		folderService: folderService,
+		limits:        limits,
+		tempPath:      tempPath,
+		logger:        logger,
+	}
+}
+
+// WithClient implements ImportService
+func (s *importService) WithClient(clientType, name, version string) ImportService {
+	ns := *s
+	ns.noteService = s.noteService.WithClient(clientType, name, version)
+	ns.fileService = s.fileService.WithClient(clientType, name, version)
+	ns.folderService = s.folderService.WithClient(clientType, name, version)
+	return &ns
+}
+
+// importEntry a note, attachment or directory to import, read from a ZIP archive or a local directory
+// importEntry 待导入的笔记、附件或目录，来自 ZIP 压缩包或本地目录
+type importEntry struct {
+	name  string                        // Name shown in failure reasons // 失败原因中显示的名称
+	rel   string                        // Normalized path below the import root, "" when not imported // 导入根目录下的规范化路径，不导入时为空
+	isDir bool                          // Entry is a directory // 条目为目录
+	size  int64                         // Uncompressed size // 未压缩大小
+	mtime int64                         // Modification time in milliseconds // 修改时间（毫秒）
+	open  func() (io.ReadCloser, error) // Opens the content // 打开内容
+}
+
+// ImportZip implements ImportService
+func (s *importService) ImportZip(ctx context.Context, uid int64, params *dto.VaultImportRequest, zipPath string, progress func(*dto.VaultImportProgressMessage)) (*dto.VaultImportResult, error) {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, code.ErrorInvalidParams.WithDetails("invalid zip archive: " + err.Error())
+	}
+	defer zr.Close()
+
+	root := importCommonRoot(zr.File)
+	entries := make([]*importEntry, 0, len(zr.File))
+	for _, f := range zr.File {
+		entries = append(entries, &importEntry{
+			name:  f.Name,
+			rel:   importEntryPath(strings.TrimPrefix(strings.ReplaceAll(f.Name, "\\", "/"), root)),
+			isDir: f.FileInfo().IsDir(),
+			size:  int64(f.UncompressedSize64),
+			mtime: importEntryMtime(f),
+			open:  f.Open,
+		})
+	}
+	return s.importEntries(ctx, uid, params, entries, progress)
+}
+
+// ImportDir implements ImportService
+func (s *importService) ImportDir(ctx context.Context, uid int64, params *dto.VaultImportRequest, dirPath string, progress func(*dto.VaultImportProgressMessage)) (*dto.VaultImportResult, error) {
+	if info, err := os.Stat(dirPath); err != nil || !info.IsDir() {
+		return nil, code.ErrorInvalidParams.WithDetails("not a readable directory: " + dirPath)
+	}
+	entries, err := importDirEntries(dirPath)
+	if err != nil {
+		return nil, code.ErrorInvalidParams.WithDetails("failed to read directory: " + err.Error())
+	}
+	return s.importEntries(ctx, uid, params, entries, progress)
+}
+
+// importDirEntries walks a vault directory and returns its entries in walk order
+// importDirEntries 遍历保险库目录，按遍历顺序返回其中的条目
+func importDirEntries(dirPath string) ([]*importEntry, error) {
+	var entries []*importEntry
+	err := filepath.WalkDir(dirPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dirPath {
+			return nil
+		}
+		rel, err := filepath.Rel(dirPath, p)
+		if err != nil {
+			return err
+		}
+		rel = importEntryPath(filepath.ToSlash(rel))
+		// Hidden folders such as .obsidian and .trash are not walked at all
+		// .obsidian、.trash 等隐藏目录不会被遍历
+		if rel == "" && d.IsDir() {
+			return filepath.SkipDir
+		}
+		// Symlinks and special files may point outside the vault, they are skipped
+		// 符号链接与特殊文件可能指向保险库之外，予以跳过
+		if !d.IsDir() && !d.Type().IsRegular() {
+			rel = ""
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, &importEntry{
+			name:  filepath.ToSlash(p),
+			rel:   rel,
+			isDir: d.IsDir(),
+			size:  info.Size(),
+			mtime: info.ModTime().UnixMilli(),
+			open:  func() (io.ReadCloser, error) { return os.Open(p) },
+		})
+		return nil
+	})
+	return entries, err
+}
+
+// importEntries imports the entries of an archive or directory into a vault, keeping their folder structure
+// importEntries 将压缩包或目录中的条目导入保险库，保留其目录结构
+func (s *importService) importEntries(ctx context.Context, uid int64, params *dto.VaultImportRequest, entries []*importEntry, progress func(*dto.VaultImportProgressMessage)) (*dto.VaultImportResult, error) {
+	vault, err := s.vaultService.GetOrCreate(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+
+	targetFolder := importEntryPath(params.Folder)
+	result := &dto.VaultImportResult{}
+	ensured := make(map[string]bool)
+
+	report := func(processed int, done bool) {
+		if progress == nil {
+			return
+		}
+		msg := &dto.VaultImportProgressMessage{
+			Total:     len(entries),
+			Processed: processed,
+			Notes:     result.Notes,
+			Files:     result.Files,
+			Skipped:   result.Skipped,
+			Failed:    result.Failed,
+			Done:      done,
+		}
+		if done {
+			msg.Errors = result.Errors
+		}
+		progress(msg)
+	}
+	fail := func(name string, err error) {
+		result.Failed++
+		if len(result.Errors) < importMaxErrors {
+			result.Errors = append(result.Errors, name+": "+err.Error())
+		}
+	}
+	ensureDir := func(dir string) error {
+		if dir == "" || dir == "." || ensured[dir] {
+			return nil
+		}
+		if _, err := s.folderService.EnsurePathFID(ctx, uid, vault.ID, dir); err != nil {
+			return err
+		}
+		ensured[dir] = true
+		result.Folders++
+		return nil
+	}
+
+	for i, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if i > 0 && i%importProgressEvery == 0 {
+			report(i, false)
+		}
+
+		if e.rel == "" {
+			if !e.isDir {
+				result.Skipped++
+			}
+			continue
+		}
+		vaultPath := path.Join(targetFolder, e.rel)
+
+		if e.isDir {
+			if err := ensureDir(vaultPath); err != nil {
+				fail(e.name, err)
+			}
+			continue
+		}
+		if err := ensureDir(path.Dir(vaultPath)); err != nil {
+			fail(e.name, err)
+			continue
+		}
+
+		var changed bool
+		if strings.EqualFold(path.Ext(vaultPath), ".md") {
+			changed, err = s.importNote(ctx, uid, params.Vault, vaultPath, e)
+			if err == nil && changed {
+				result.Notes++
+			}
+		} else {
+			changed, err = s.importFile(ctx, uid, params.Vault, vaultPath, e)
+			if err == nil && changed {
+				result.Files++
+			}
+		}
+		if err != nil {
+			fail(e.name, err)
+			continue
+		}
+		if !changed {
+			result.Skipped++
+		}
+	}
+
+	report(len(entries), true)
+
+	s.logger.Info("vault import finished",
+		zap.Int64("uid", uid),
+		zap.String("vault", params.Vault),
+		zap.Int("notes", result.Notes),
+		zap.Int("files", result.Files),
+		zap.Int("folders", result.Folders),
+		zap.Int("skipped", result.Skipped),
+		zap.Int("failed", result.Failed))
+
+	return result, nil
+}
+
+// importNote imports a single Markdown entry, returns false when the note was unchanged
+// importNote 导入单个 Markdown 条目，笔记未变化时返回 false
+func (s *importService) importNote(ctx context.Context, uid int64, vault string, vaultPath string, e *importEntry) (bool, error) {
+	if e.size > importMaxNoteSize {
+		return false, fmt.Errorf("note exceeds %d bytes", importMaxNoteSize)
+	}
+	rc, err := e.open()
+	if err != nil {
+		return false, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, importMaxNoteSize+1))
+	if err != nil {
+		return false, err
+	}
+	if len(data) > importMaxNoteSize {
+		return false, fmt.Errorf("note exceeds %d bytes", importMaxNoteSize)
+	}
+
+	content := string(data)
+	_, note, err := s.noteService.ModifyOrCreate(ctx, uid, &dto.NoteModifyOrCreateRequest{
+		Vault:       vault,
+		Path:        vaultPath,
+		PathHash:    util.EncodeHash32(vaultPath),
+		Content:     content,
+		ContentHash: util.EncodeHash32(content),
+		Ctime:       e.mtime,
+		Mtime:       e.mtime,
+	}, true)
+	if err != nil {
+		return false, err
+	}
+	return note != nil, nil
+}
+
+// importFile streams a single attachment entry to a temp file and stores it via FileService
+// importFile 将单个附件条目流式写入临时文件后通过 FileService 保存
+func (s *importService) importFile(ctx context.Context, uid int64, vault string, vaultPath string, e *importEntry) (bool, error) {
+	rc, err := e.open()
+	if err != nil {
+		return false, err
+	}
+	defer rc.Close()
+
+	if err := os.MkdirAll(s.tempPath, 0755); err != nil {
+		return false, err
+	}
+	tempPath := filepath.Join(s.tempPath, uuid.New().String())
+	out, err := os.Create(tempPath)
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tempPath)
+
+	// Stop one byte past the cap, an oversized entry must not fill the disk before it is rejected
+	// 读到超出上限一个字节即停止，超大条目不应在被拒绝前占满磁盘
+	var src io.Reader = rc
+	if limit := s.limits.MaxAttachmentSize(); limit > 0 {
+		src = io.LimitReader(rc, limit+1)
+	}
+	size, err := io.Copy(out, src)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := s.limits.CheckAttachmentSize(size); err != nil {
+		return false, err
+	}
+
+	contentHash, err := util.EncodeHash32File(tempPath)
+	if err != nil {
+		return false, err
+	}
+
+	_, _, err = s.fileService.UpdateOrCreate(ctx, uid, &dto.FileUpdateRequest{
+		Vault:       vault,
+		Path:        vaultPath,
+		PathHash:    util.EncodeHash32(vaultPath),
+		ContentHash: contentHash,
+		SavePath:    tempPath,
+		Size:        size,
+		Ctime:       e.mtime,
+		Mtime:       e.mtime,
+	}, true)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// importEntryMtime returns the archive entry modification time in milliseconds, or now if unset
+// importEntryMtime 返回压缩包条目的修改时间（毫秒），未设置时返回当前时间
+func importEntryMtime(f *zip.File) int64 {
+	if f.Modified.IsZero() || f.Modified.Year() < 1981 {
+		return timex.Now().UnixMilli()
+	}
+	return f.Modified.UnixMilli()
+}
+
+// importEntryPath normalizes an archive entry name into a vault path.
+// Returns "" for unsafe paths (absolute or escaping the root), hidden entries (".obsidian", ".trash", ".DS_Store")
+// and macOS resource forks, which are not imported.
+// importEntryPath 将压缩包条目名规范化为保险库路径。
+// 不安全路径（绝对路径或越出根目录）、隐藏条目（".obsidian"、".trash"、".DS_Store"）及 macOS 资源分支返回 ""，不予导入。
+func importEntryPath(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(name, "/") {
+		return ""
+	}
+	cleaned := path.Clean(name)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return ""
+	}
+	for _, seg := range strings.Split(cleaned, "/") {
+		if strings.HasPrefix(seg, ".") || seg == "__MACOSX" {
+			return ""
+		}
+	}
+	return cleaned
+}
+
+// importCommonRoot returns the single top-level directory shared by every entry (with trailing "/"),
+// so that an archive of a zipped vault folder imports its contents rather than the folder itself
+// importCommonRoot 返回所有条目共享的唯一顶层目录（带末尾 "/"），
+// 使对整个保险库目录打包的压缩包导入其内容而不是该目录本身
+func importCommonRoot(files []*zip.File) string {
+	root := ""
+	for _, f := range files {
+		name := strings.ReplaceAll(f.Name, "\\", "/")
+		if strings.HasPrefix(name, "__MACOSX/") {
+			continue
+		}
+		idx := strings.Index(name, "/")
+		if idx <= 0 {
+			return ""
+		}
+		if root == "" {
+			root = name[:idx+1]
+		} else if name[:idx+1] != root {
+			return ""
+		}
+	}
+	return root
+}
+
+var _ ImportService = (*importService)(nil)
@@ -0,0 +1,129 @@
+// Package service implements the business logic layer.
+// Package service 实现业务逻辑层。
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestImportEntryPath verifies archive entry names are normalized and unsafe or hidden entries dropped.
+// TestImportEntryPath 验证压缩包条目名被规范化，且不安全或隐藏条目被丢弃。
+func TestImportEntryPath(t *testing.T) {
+	cases := map[string]string{
+		"notes/a.md":            "notes/a.md",
+		"notes\\sub\\b.md":      "notes/sub/b.md",
+		"./c.md":                "c.md",
+		"notes/../d.md":         "d.md",
+		"../escape.md":          "",
+		"/abs/e.md":             "",
+		".obsidian/app.json":    "",
+		"notes/.DS_Store":       "",
+		"__MACOSX/notes/._a.md": "",
+		"":                      "",
+	}
+	for in, want := range cases {
+		assert.Equal(t, want, importEntryPath(in), in)
+	}
+}
+
+// TestImportCommonRoot verifies a single wrapping directory is detected and stripped.
+// TestImportCommonRoot 验证能识别并剥离唯一的外层目录。
+func TestImportCommonRoot(t *testing.T) {
+	files := func(names ...string) []*zip.File {
+		res := make([]*zip.File, 0, len(names))
+		for _, n := range names {
+			res = append(res, &zip.File{FileHeader: zip.FileHeader{Name: n}})
+		}
+		return res
+	}
+
+	assert.Equal(t, "MyVault/", importCommonRoot(files("MyVault/", "MyVault/a.md", "MyVault/img/p.png", "__MACOSX/MyVault/._a.md")))
+	assert.Equal(t, "", importCommonRoot(files("MyVault/a.md", "b.md")))
+	assert.Equal(t, "", importCommonRoot(files("A/a.md", "B/b.md")))
+}
+
+// TestImportDirEntries verifies a vault directory is walked with relative paths and mtimes, skipping hidden folders and symlinks.
+// TestImportDirEntries 验证保险库目录按相对路径与修改时间遍历，并跳过隐藏目录与符号链接。
+func TestImportDirEntries(t *testing.T) {
+	dir := t.TempDir()
+	mtime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	write := func(name string) {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(name), 0644))
+		require.NoError(t, os.Chtimes(p, mtime, mtime))
+	}
+	write("a.md")
+	write("notes/b.md")
+	write("notes/img/p.png")
+	write(".obsidian/app.json")
+	write(".trash/old.md")
+	require.NoError(t, os.Symlink(filepath.Join(dir, "a.md"), filepath.Join(dir, "link.md")))
+
+	entries, err := importDirEntries(dir)
+	require.NoError(t, err)
+
+	got := map[string]*importEntry{}
+	skipped := 0
+	for _, e := range entries {
+		if e.rel == "" {
+			skipped++
+			continue
+		}
+		got[e.rel] = e
+	}
+	assert.Equal(t, 1, skipped)
+	require.Len(t, got, 5)
+	assert.True(t, got["notes"].isDir)
+	assert.True(t, got["notes/img"].isDir)
+	assert.Equal(t, mtime.UnixMilli(), got["notes/img/p.png"].mtime)
+	assert.Equal(t, int64(len("notes/b.md")), got["notes/b.md"].size)
+
+	rc, err := got["a.md"].open()
+	require.NoError(t, err)
+	defer rc.Close()
+	buf := make([]byte, 8)
+	n, _ := rc.Read(buf)
+	assert.Equal(t, "a.md", string(buf[:n]))
+}
+
+// TestImportFile_AttachmentCap verifies an oversized attachment entry is rejected after reading one byte past
+// the cap, before anything is stored.
+// TestImportFile_AttachmentCap 验证超大附件条目在读到超出上限一个字节后即被拒绝，不会存储任何内容。
+func TestImportFile_AttachmentCap(t *testing.T) {
+	var read int64
+	entry := &importEntry{name: "big.png", open: func() (io.ReadCloser, error) {
+		return io.NopCloser(&countingReader{r: strings.NewReader(strings.Repeat("x", 1000)), n: &read}), nil
+	}}
+
+	// No file service is set, reaching it would panic
+	// 未设置文件服务，若执行到它会 panic
+	svc := &importService{limits: NewContentLimits(0, 100, 0), tempPath: t.TempDir()}
+	_, err := svc.importFile(context.Background(), 1, "main", "big.png", entry)
+	assert.ErrorIs(t, err, code.ErrorFileTooLarge)
+	assert.Equal(t, int64(101), read)
+}
+
+// countingReader counts the bytes read from r
+// countingReader 统计从 r 读取的字节数
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	*c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/logbuf"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap/zapcore"
+)
+
+// defaultLogQueryLimit entries returned when the query sets no limit
+// defaultLogQueryLimit 查询未指定数量时返回的条目数
+const defaultLogQueryLimit = 200
+
+// LogService defines the log viewer business service interface
+// LogService 定义日志查看业务服务接口
+type LogService interface {
+	// Query returns the newest entries matching the filter, oldest first
+	// Query 返回符合条件的最新条目，按时间升序
+	Query(params *dto.LogQueryRequest) ([]*dto.LogEntryDTO, error)
+
+	// Follow sends the entries Query returns, then every new matching entry until ctx is done or send fails.
+	// send receives nil once the earlier entries are sent and every keepAlive while no entry arrives, so the
+	// caller can flush and keep the connection open.
+	// Follow 先发送 Query 返回的条目，再持续发送新的匹配条目，直到 ctx 结束或 send 失败。
+	// 已有条目发送完毕时以及没有新条目时每隔 keepAlive 以 nil 调用 send，便于调用方刷新并保持连接。
+	Follow(ctx context.Context, params *dto.LogQueryRequest, keepAlive time.Duration, send func(entry *dto.LogEntryDTO) error) error
+}
+
+// logService implements LogService
+// logService 实现 LogService 接口
+type logService struct {
+	buf *logbuf.Buffer
+}
+
+// NewLogService creates a LogService instance, buf is nil when the log viewer is disabled
+// NewLogService 创建 LogService 实例，日志查看关闭时 buf 为 nil
+func NewLogService(buf *logbuf.Buffer) LogService {
+	return &logService{buf: buf}
+}
+
+// Query implements LogService
+func (s *logService) Query(params *dto.LogQueryRequest) ([]*dto.LogEntryDTO, error) {
+	filter, limit, err := s.filter(params)
+	if err != nil {
+		return nil, err
+	}
+	entries := s.buf.Query(filter, limit)
+	result := make([]*dto.LogEntryDTO, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, logEntryToDTO(e))
+	}
+	return result, nil
+}
+
+// Follow implements LogService
+func (s *logService) Follow(ctx context.Context, params *dto.LogQueryRequest, keepAlive time.Duration, send func(entry *dto.LogEntryDTO) error) error {
+	filter, limit, err := s.filter(params)
+	if err != nil {
+		return err
+	}
+	backlog, live, stop := s.buf.Follow(filter, limit)
+	defer stop()
+
+	for _, e := range backlog {
+		if err := send(logEntryToDTO(e)); err != nil {
+			return err
+		}
+	}
+	if err := send(nil); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case e := <-live:
+			if err := send(logEntryToDTO(e)); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := send(nil); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// filter converts the query parameters into a buffer filter and limit
+// filter 将查询参数转换为缓冲区筛选条件与数量
+func (s *logService) filter(params *dto.LogQueryRequest) (logbuf.Filter, int, error) {
+	if s.buf == nil {
+		return logbuf.Filter{}, 0, code.ErrorLogBufferDisabled
+	}
+	filter := logbuf.Filter{
+		MinLevel: zapcore.DebugLevel,
+		Module:   params.Module,
+		Keyword:  params.Keyword,
+		AfterSeq: params.AfterSeq,
+	}
+	if params.Level != "" {
+		level, err := zapcore.ParseLevel(params.Level)
+		if err != nil {
+			return logbuf.Filter{}, 0, code.ErrorInvalidParams.WithDetails("invalid level")
+		}
+		filter.MinLevel = level
+	}
+	if params.StartTime > 0 {
+		filter.Since = time.UnixMilli(params.StartTime)
+	}
+	if params.EndTime > 0 {
+		filter.Until = time.UnixMilli(params.EndTime)
+	}
+	if params.UID > 0 {
+		filter.UID = strconv.FormatInt(params.UID, 10)
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultLogQueryLimit
+	}
+	return filter, limit, nil
+}
+
+// logEntryToDTO converts a buffered entry to its DTO
+// logEntryToDTO 将缓冲的条目转换为 DTO
+func logEntryToDTO(e logbuf.Entry) *dto.LogEntryDTO {
+	return &dto.LogEntryDTO{
+		Seq:     e.Seq,
+		Time:    timex.Time(e.Time),
+		Level:   e.Level.String(),
+		Module:  e.Module,
+		Message: e.Message,
+		UID:     e.UID,
+		Caller:  e.Caller,
+		Fields:  e.Fields,
+	}
+}
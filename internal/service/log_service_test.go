@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/logbuf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogService_QueryAndFollow(t *testing.T) {
+	buf := logbuf.New(100)
+	logger := zap.New(buf.Core(zapcore.DebugLevel))
+	logger.Debug("WebsocketServer OnMessage", zap.Int64("uid", 1))
+	logger.Warn("WebsocketServer OnMessage", zap.Int64("uid", 1))
+	logger.Warn("apiRouter.WebGUI.UpdateConfig err", zap.Int64("uid", 2))
+
+	svc := NewLogService(buf)
+	entries, err := svc.Query(&dto.LogQueryRequest{Level: "warn", UID: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "warn", entries[0].Level)
+	assert.Equal(t, "WebsocketServer", entries[0].Module)
+
+	entries, err = svc.Query(&dto.LogQueryRequest{Module: "apirouter"})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// Follow sends the backlog, the ready marker, then new entries
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var got []string
+	stopSending := errors.New("done")
+	err = svc.Follow(ctx, &dto.LogQueryRequest{Keyword: "onmessage", Limit: 1}, time.Hour, func(entry *dto.LogEntryDTO) error {
+		if entry == nil {
+			got = append(got, "ready")
+			logger.Info("WebsocketServer OnMessage live")
+			return nil
+		}
+		got = append(got, entry.Message)
+		if len(got) == 3 {
+			return stopSending
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stopSending)
+	assert.Equal(t, []string{"WebsocketServer OnMessage", "ready", "WebsocketServer OnMessage live"}, got)
+
+	_, err = NewLogService(nil).Query(&dto.LogQueryRequest{})
+	assert.ErrorIs(t, err, code.ErrorLogBufferDisabled)
+}
@@ -0,0 +1,267 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+)
+
+// migrateRemotePageSize items requested per page, the maximum page size of the API
+// migrateRemotePageSize 每页请求的条目数，即 API 允许的最大分页大小
+const migrateRemotePageSize = 100
+
+// migrateRemote reads the data of one user from the REST API of another instance
+// migrateRemote 通过另一个实例的 REST API 读取某个用户的数据
+type migrateRemote struct {
+	baseURL string
+	token   string
+	client  string
+	http    *http.Client
+}
+
+// migrateRemoteNote a note of the source server, see dto.NoteDTO
+// migrateRemoteNote 源服务器上的笔记，参见 dto.NoteDTO
+type migrateRemoteNote struct {
+	Path        string `json:"path"`
+	Content     string `json:"content"`
+	ContentHash string `json:"contentHash"`
+	Version     int64  `json:"version"`
+	Ctime       int64  `json:"ctime"`
+	Mtime       int64  `json:"mtime"`
+}
+
+// migrateRemoteHistory a note history version of the source server, see dto.NoteHistoryDTO
+// migrateRemoteHistory 源服务器上的笔记历史版本，参见 dto.NoteHistoryDTO
+type migrateRemoteHistory struct {
+	ID            int64  `json:"id"`
+	Path          string `json:"path"`
+	Content       string `json:"content"`
+	ContentHash   string `json:"contentHash"`
+	ClientName    string `json:"clientName"`
+	ClientType    string `json:"clientType"`
+	ClientVersion string `json:"clientVersion"`
+	Version       int64  `json:"version"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+// migrateRemoteFile an attachment of the source server, see dto.FileDTO
+// migrateRemoteFile 源服务器上的附件，参见 dto.FileDTO
+type migrateRemoteFile struct {
+	Path        string `json:"path"`
+	ContentHash string `json:"contentHash"`
+	Size        int64  `json:"size"`
+	Ctime       int64  `json:"ctime"`
+	Mtime       int64  `json:"mtime"`
+}
+
+// migrateRemoteRes the response envelope of the API, see pkgapp.Res
+// migrateRemoteRes API 的响应信封，参见 pkgapp.Res
+type migrateRemoteRes struct {
+	Code    int             `json:"code"`
+	Status  bool            `json:"status"`
+	Message any             `json:"message"`
+	Details any             `json:"details"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// migrateRemoteList the list payload of paged endpoints, see pkgapp.ListRes
+// migrateRemoteList 分页接口的列表数据，参见 pkgapp.ListRes
+type migrateRemoteList[T any] struct {
+	List  []T `json:"list"`
+	Pager struct {
+		TotalRows int `json:"totalRows"`
+	} `json:"pager"`
+}
+
+// newMigrateRemote creates the API reader of a source server
+// newMigrateRemote 创建源服务器的 API 读取器
+func newMigrateRemote(baseURL, token, client string) *migrateRemote {
+	return &migrateRemote{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		client:  client,
+		http:    &http.Client{Timeout: 10 * time.Minute},
+	}
+}
+
+// request performs a GET request against the source server
+// request 向源服务器发起 GET 请求
+func (r *migrateRemote) request(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	u := r.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.token)
+	if r.client != "" {
+		req.Header.Set("x-client", r.client)
+	}
+	req.Header.Set("x-client-name", "migrate")
+	return r.http.Do(req)
+}
+
+// getJSON fetches an endpoint and decodes the data of its response envelope into out
+// getJSON 请求接口并将响应信封中的数据解码到 out
+func (r *migrateRemote) getJSON(ctx context.Context, path string, query url.Values, out any) error {
+	resp, err := r.request(ctx, path, query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var res migrateRemoteRes
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("GET %s: unexpected response (HTTP %d): %w", path, resp.StatusCode, err)
+	}
+	if !res.Status {
+		return fmt.Errorf("GET %s: %s", path, res.describe())
+	}
+	if out == nil || len(res.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(res.Data, out)
+}
+
+// describe formats the error of a failed response
+// describe 格式化失败响应中的错误
+func (res *migrateRemoteRes) describe() string {
+	msg := fmt.Sprintf("%v (code %d)", res.Message, res.Code)
+	if res.Details != nil {
+		msg += fmt.Sprintf(": %v", res.Details)
+	}
+	return msg
+}
+
+// getMigrateRemoteList fetches every page of a paged endpoint
+// getMigrateRemoteList 获取分页接口的全部分页
+func getMigrateRemoteList[T any](ctx context.Context, r *migrateRemote, path string, query url.Values) ([]T, error) {
+	var all []T
+	for page := 1; ; page++ {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Set("page", strconv.Itoa(page))
+		q.Set("pageSize", strconv.Itoa(migrateRemotePageSize))
+
+		var res migrateRemoteList[T]
+		if err := r.getJSON(ctx, path, q, &res); err != nil {
+			return nil, err
+		}
+		all = append(all, res.List...)
+		if len(res.List) < migrateRemotePageSize || len(all) >= res.Pager.TotalRows {
+			return all, nil
+		}
+	}
+}
+
+// Vaults lists the vault names of the source user
+// Vaults 列出源用户的保险库名称
+func (r *migrateRemote) Vaults(ctx context.Context) ([]string, error) {
+	var vaults []struct {
+		Name string `json:"vault"`
+	}
+	if err := r.getJSON(ctx, "/api/vault", nil, &vaults); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(vaults))
+	for _, v := range vaults {
+		names = append(names, v.Name)
+	}
+	return names, nil
+}
+
+// NotePaths lists the paths of the notes of a vault
+// NotePaths 列出保险库中笔记的路径
+func (r *migrateRemote) NotePaths(ctx context.Context, vault string) ([]string, error) {
+	notes, err := getMigrateRemoteList[struct {
+		Path string `json:"path"`
+	}](ctx, r, "/api/notes", url.Values{"vault": {vault}})
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(notes))
+	for _, n := range notes {
+		paths = append(paths, n.Path)
+	}
+	return paths, nil
+}
+
+// Note fetches a note with its content
+// Note 获取笔记及其正文
+func (r *migrateRemote) Note(ctx context.Context, vault, path string) (*migrateRemoteNote, error) {
+	note := &migrateRemoteNote{}
+	if err := r.getJSON(ctx, "/api/note", url.Values{"vault": {vault}, "path": {path}}, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+// Histories fetches the history versions of a note with their content, oldest first
+// Histories 获取笔记的历史版本及其内容，按从旧到新排列
+func (r *migrateRemote) Histories(ctx context.Context, vault, path string) ([]*migrateRemoteHistory, error) {
+	list, err := getMigrateRemoteList[migrateRemoteHistory](ctx, r, "/api/note/histories", url.Values{"vault": {vault}, "path": {path}})
+	if err != nil {
+		return nil, err
+	}
+	histories := make([]*migrateRemoteHistory, 0, len(list))
+	for i := len(list) - 1; i >= 0; i-- {
+		h := &migrateRemoteHistory{}
+		if err := r.getJSON(ctx, "/api/note/history", url.Values{"id": {strconv.FormatInt(list[i].ID, 10)}}, h); err != nil {
+			return nil, err
+		}
+		histories = append(histories, h)
+	}
+	return histories, nil
+}
+
+// Files lists the attachments of a vault
+// Files 列出保险库中的附件
+func (r *migrateRemote) Files(ctx context.Context, vault string) ([]migrateRemoteFile, error) {
+	return getMigrateRemoteList[migrateRemoteFile](ctx, r, "/api/files", url.Values{"vault": {vault}})
+}
+
+// Download streams the content of an attachment to w
+// Download 将附件内容流式写入 w
+func (r *migrateRemote) Download(ctx context.Context, vault, path string, w io.Writer) (int64, error) {
+	resp, err := r.request(ctx, "/api/file", url.Values{"vault": {vault}, "path": {path}})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Errors are answered with the JSON envelope instead of the file, served files always carry an ETag
+	// 出错时返回 JSON 信封而不是文件内容，正常返回的文件总是带有 ETag
+	isEnvelope := resp.Header.Get("ETag") == "" && strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json")
+	if resp.StatusCode != http.StatusOK || isEnvelope {
+		var res migrateRemoteRes
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+			return 0, fmt.Errorf("GET /api/file: unexpected response (HTTP %d)", resp.StatusCode)
+		}
+		return 0, fmt.Errorf("GET /api/file: %s", res.describe())
+	}
+	return io.Copy(w, resp.Body)
+}
+
+// createdAt parses the creation time of a history version, the API formats it in the server's local time
+// createdAt 解析历史版本的创建时间，API 以服务器本地时间格式化
+func (h *migrateRemoteHistory) createdAt() time.Time {
+	t, err := time.ParseInLocation(timex.TimeFormat, h.CreatedAt, time.Local)
+	if err != nil {
+		return time.Now()
+	}
+	return t
+}
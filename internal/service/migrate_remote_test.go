@@ -0,0 +1,115 @@
+// Package service implements the business logic layer.
+// Package service 实现业务逻辑层。
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// migrateTestServer serves a fake source instance with 150 notes, one note history and one attachment.
+// migrateTestServer 提供一个假的源实例，包含 150 篇笔记、一条笔记历史与一个附件。
+func migrateTestServer(t *testing.T) *httptest.Server {
+	ok := func(w http.ResponseWriter, data any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 1, "status": true, "data": data})
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 505, "status": false, "message": "invalid token"})
+			return
+		}
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/notes":
+			page, _ := strconv.Atoi(q.Get("page"))
+			pageSize, _ := strconv.Atoi(q.Get("pageSize"))
+			var list []map[string]any
+			for i := (page - 1) * pageSize; i < 150 && i < page*pageSize; i++ {
+				list = append(list, map[string]any{"path": fmt.Sprintf("n%03d.md", i)})
+			}
+			ok(w, map[string]any{"list": list, "pager": map[string]any{"totalRows": 150}})
+		case "/api/note/histories":
+			ok(w, map[string]any{"list": []map[string]any{{"id": 8, "version": 2}, {"id": 7, "version": 1}}, "pager": map[string]any{"totalRows": 2}})
+		case "/api/note/history":
+			id, _ := strconv.Atoi(q.Get("id"))
+			ok(w, map[string]any{"id": id, "content": "v" + q.Get("id"), "createdAt": "2024-05-01 10:00:00"})
+		case "/api/file":
+			if q.Get("path") == "missing.png" {
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]any{"code": 434, "status": false, "message": "file not found"})
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("ETag", `"abc"`)
+			_, _ = w.Write([]byte(`{"attachment":true}`))
+		}
+	}))
+}
+
+// TestMigrateRemote_Lists verifies paged listings are followed to the end and history versions come back oldest first.
+// TestMigrateRemote_Lists 验证分页列表会读取到最后一页，且历史版本按从旧到新返回。
+func TestMigrateRemote_Lists(t *testing.T) {
+	ts := migrateTestServer(t)
+	defer ts.Close()
+	remote := newMigrateRemote(ts.URL+"/", "secret", "webgui")
+	ctx := context.Background()
+
+	paths, err := remote.NotePaths(ctx, "v")
+	require.NoError(t, err)
+	require.Len(t, paths, 150)
+	assert.Equal(t, "n149.md", paths[149])
+
+	histories, err := remote.Histories(ctx, "v", "n000.md")
+	require.NoError(t, err)
+	require.Len(t, histories, 2)
+	assert.Equal(t, "v7", histories[0].Content)
+	assert.Equal(t, 2024, histories[0].createdAt().Year())
+
+	_, err = newMigrateRemote(ts.URL, "wrong", "").NotePaths(ctx, "v")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid token")
+}
+
+// TestMigrateRemote_Download verifies a JSON attachment is downloaded while an error envelope is reported.
+// TestMigrateRemote_Download 验证 JSON 附件可正常下载，而错误信封会被报告为错误。
+func TestMigrateRemote_Download(t *testing.T) {
+	ts := migrateTestServer(t)
+	defer ts.Close()
+	remote := newMigrateRemote(ts.URL, "secret", "webgui")
+
+	var buf bytes.Buffer
+	n, err := remote.Download(context.Background(), "v", "data.json", &buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Equal(t, `{"attachment":true}`, buf.String())
+
+	_, err = remote.Download(context.Background(), "v", "missing.png", &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "file not found")
+}
+
+// TestMigrateDiffPatch verifies the stored patch turns a version into the next one.
+// TestMigrateDiffPatch 验证保存的补丁能把一个版本变为下一个版本。
+func TestMigrateDiffPatch(t *testing.T) {
+	from, to := "# Title\n\nold line\n", "# Title\n\nnew line\nmore\n"
+	dmp := diffmatchpatch.New()
+	patches, err := dmp.PatchFromText(migrateDiffPatch(from, to))
+	require.NoError(t, err)
+	got, applied := dmp.PatchApply(patches, from)
+	assert.Equal(t, to, got)
+	for _, a := range applied {
+		assert.True(t, a)
+	}
+}
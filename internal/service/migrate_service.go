@@ -0,0 +1,308 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"go.uber.org/zap"
+)
+
+// migrateMaxErrors failure reasons kept in the result
+// migrateMaxErrors 结果中保留的失败原因条数
+const migrateMaxErrors = 50
+
+// MigrateService defines the server-to-server migration service interface
+// MigrateService 定义服务器间迁移服务接口
+type MigrateService interface {
+	// MigrateRemote copies the vaults, notes with their history and attachments of a user on another
+	// instance into uid, keeping mtimes, ctimes and note versions. Entries unchanged since a previous run
+	// are skipped, so an interrupted migration can simply be run again.
+	// MigrateRemote 将另一个实例上某个用户的保险库、笔记及其历史与附件复制到 uid 下，保留修改时间、创建时间与笔记版本号。
+	// 自上次运行以来未变化的条目会被跳过，中断的迁移可以直接重新运行。
+	MigrateRemote(ctx context.Context, uid int64, params *dto.MigrateRemoteRequest, progress func(*dto.MigrateProgressMessage)) (*dto.MigrateRemoteResult, error)
+
+	// TransferVault moves a vault with its notes, note history, attachments and client config from one user
+	// to another on this instance and deletes it from the source user once everything arrived. A dry run only
+	// reports what would be moved and which paths of an existing target vault conflict.
+	// TransferVault 将本实例上某个用户的保险库及其笔记、笔记历史、附件与客户端配置转移给另一个用户，全部转移成功后从源用户删除。
+	// 试运行仅报告将要转移的内容以及与已有目标保险库冲突的路径。
+	TransferVault(ctx context.Context, params *dto.VaultTransferRequest) (*dto.VaultTransferReportDTO, error)
+
+	// WithClient sets client info
+	// WithClient 设置客户端信息
+	WithClient(clientType, name, version string) MigrateService
+}
+
+type migrateService struct {
+	vaultService VaultService
+	noteService  NoteService
+	fileService  FileService
+	noteRepo     domain.NoteRepository
+	historyRepo  domain.NoteHistoryRepository
+	fileRepo     domain.FileRepository
+	settingRepo  domain.SettingRepository
+	userRepo     domain.UserRepository
+	tempPath     string
+	logger       *zap.Logger
+}
+
+// NewMigrateService creates MigrateService instance
+// NewMigrateService 创建 MigrateService 实例
+func NewMigrateService(vaultService VaultService, noteService NoteService, fileService FileService, noteRepo domain.NoteRepository, historyRepo domain.NoteHistoryRepository, fileRepo domain.FileRepository, settingRepo domain.SettingRepository, userRepo domain.UserRepository, tempPath string, logger *zap.Logger) MigrateService {
+	if tempPath == "" {
+		tempPath = "storage/temp"
+	}
+	return &migrateService{
+		vaultService: vaultService,
+		noteService:  noteService,
+		fileService:  fileService,
+		noteRepo:     noteRepo,
+		historyRepo:  historyRepo,
+		fileRepo:     fileRepo,
+		settingRepo:  settingRepo,
+		userRepo:     userRepo,
+		tempPath:     tempPath,
+		logger:       logger,
+	}
+}
+
+// WithClient implements MigrateService
+func (s *migrateService) WithClient(clientType, name, version string) MigrateService {
+	ns := *s
+	ns.noteService = s.noteService.WithClient(clientType, name, version)
+	ns.fileService = s.fileService.WithClient(clientType, name, version)
+	return &ns
+}
+
+// MigrateRemote implements MigrateService
+func (s *migrateService) MigrateRemote(ctx context.Context, uid int64, params *dto.MigrateRemoteRequest, progress func(*dto.MigrateProgressMessage)) (*dto.MigrateRemoteResult, error) {
+	if params.BaseURL == "" || params.Token == "" {
+		return nil, code.ErrorInvalidParams.WithDetails("source server address and token are required")
+	}
+	remote := newMigrateRemote(params.BaseURL, params.Token, params.Client)
+
+	vaults := params.Vaults
+	if len(vaults) == 0 {
+		var err error
+		if vaults, err = remote.Vaults(ctx); err != nil {
+			return nil, fmt.Errorf("list source vaults: %w", err)
+		}
+	}
+
+	result := &dto.MigrateRemoteResult{}
+	fail := func(name string, err error) {
+		result.Failed++
+		if len(result.Errors) < migrateMaxErrors {
+			result.Errors = append(result.Errors, name+": "+err.Error())
+		}
+	}
+
+	for _, vaultName := range vaults {
+		report := func(processed int, force bool) {
+			if progress != nil && (force || processed%importProgressEvery == 0) {
+				progress(&dto.MigrateProgressMessage{
+					Vault:     vaultName,
+					Notes:     result.Notes,
+					Histories: result.Histories,
+					Files:     result.Files,
+					Skipped:   result.Skipped,
+					Failed:    result.Failed,
+				})
+			}
+		}
+
+		vault, err := s.vaultService.GetOrCreate(ctx, uid, vaultName)
+		if err != nil {
+			return result, err
+		}
+		paths, err := remote.NotePaths(ctx, vaultName)
+		if err != nil {
+			return result, fmt.Errorf("list notes of vault %s: %w", vaultName, err)
+		}
+		files, err := remote.Files(ctx, vaultName)
+		if err != nil {
+			return result, fmt.Errorf("list files of vault %s: %w", vaultName, err)
+		}
+		result.Vaults++
+
+		processed := 0
+		for _, notePath := range paths {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			histories, changed, err := s.migrateNote(ctx, remote, uid, vault, notePath)
+			switch {
+			case err != nil:
+				fail(vaultName+"/"+notePath, err)
+			case changed:
+				result.Notes++
+				result.Histories += histories
+			default:
+				result.Skipped++
+			}
+			processed++
+			report(processed, false)
+		}
+
+		for _, f := range files {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			changed, err := s.migrateFile(ctx, remote, uid, vaultName, f)
+			switch {
+			case err != nil:
+				fail(vaultName+"/"+f.Path, err)
+			case changed:
+				result.Files++
+			default:
+				result.Skipped++
+			}
+			processed++
+			report(processed, false)
+		}
+		report(processed, true)
+	}
+	return result, nil
+}
+
+// migrateNote copies a note and the history versions it does not have locally yet, returns the number
+// of copied versions and false when the note was unchanged
+// migrateNote 复制笔记及本地尚未存在的历史版本，返回复制的版本数，笔记未变化时返回 false
+func (s *migrateService) migrateNote(ctx context.Context, remote *migrateRemote, uid int64, vault *domain.Vault, notePath string) (int, bool, error) {
+	note, err := remote.Note(ctx, vault.Name, notePath)
+	if err != nil {
+		return 0, false, err
+	}
+	contentHash := util.EncodeHash32(note.Content)
+	_, local, err := s.noteService.ModifyOrCreate(ctx, uid, &dto.NoteModifyOrCreateRequest{
+		Vault:       vault.Name,
+		Path:        notePath,
+		PathHash:    util.EncodeHash32(notePath),
+		Content:     note.Content,
+		ContentHash: contentHash,
+		Ctime:       note.Ctime,
+		Mtime:       note.Mtime,
+	}, true)
+	if err != nil {
+		return 0, false, err
+	}
+	if local == nil {
+		return 0, false, nil
+	}
+
+	// Keep the version of the source; with the snapshot equal to the content the history task has
+	// nothing to record for the write above
+	// 保留源笔记的版本号；快照与正文一致时，历史任务不会为上面的写入生成记录
+	if err := s.noteRepo.UpdateSnapshot(ctx, note.Content, contentHash, note.Version, local.ID, uid); err != nil {
+		return 0, true, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	histories, err := remote.Histories(ctx, vault.Name, notePath)
+	if err != nil {
+		return 0, true, err
+	}
+	sort.Slice(histories, func(i, j int) bool { return histories[i].Version < histories[j].Version })
+	latest, err := s.historyRepo.GetLatestVersion(ctx, local.ID, uid)
+	if err != nil {
+		return 0, true, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	copied := 0
+	for i, h := range histories {
+		if h.Version <= latest {
+			continue
+		}
+		// A version stores the content before a change and the patch leading to the next version
+		// 每个版本保存变更前的内容，以及通往下一个版本的补丁
+		next := note.Content
+		if i+1 < len(histories) {
+			next = histories[i+1].Content
+		}
+		createdAt := h.createdAt()
+		_, err := s.historyRepo.Create(ctx, &domain.NoteHistory{
+			NoteID:        local.ID,
+			VaultID:       vault.ID,
+			Path:          h.Path,
+			DiffPatch:     migrateDiffPatch(h.Content, next),
+			Content:       h.Content,
+			ContentHash:   util.EncodeHash32(h.Content),
+			ClientName:    h.ClientName,
+			ClientType:    h.ClientType,
+			ClientVersion: h.ClientVersion,
+			Version:       h.Version,
+			CreatedAt:     createdAt,
+			UpdatedAt:     createdAt,
+		}, uid)
+		if err != nil {
+			return copied, true, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		copied++
+	}
+	return copied, true, nil
+}
+
+// migrateFile downloads an attachment to a temp file and stores it via FileService, returns false
+// when the local copy is already up to date
+// migrateFile 将附件下载到临时文件后通过 FileService 保存，本地副本已是最新时返回 false
+func (s *migrateService) migrateFile(ctx context.Context, remote *migrateRemote, uid int64, vault string, f migrateRemoteFile) (bool, error) {
+	pathHash := util.EncodeHash32(f.Path)
+	if local, err := s.fileService.Get(ctx, uid, &dto.FileGetRequest{Vault: vault, Path: f.Path, PathHash: pathHash}); err == nil && local != nil &&
+		local.ContentHash == f.ContentHash && local.Mtime == f.Mtime {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(s.tempPath, 0755); err != nil {
+		return false, err
+	}
+	tempPath := filepath.Join(s.tempPath, uuid.New().String())
+	out, err := os.Create(tempPath)
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tempPath)
+
+	size, err := remote.Download(ctx, vault, f.Path, out)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return false, err
+	}
+
+	contentHash, err := util.EncodeHash32File(tempPath)
+	if err != nil {
+		return false, err
+	}
+	_, _, err = s.fileService.UpdateOrCreate(ctx, uid, &dto.FileUpdateRequest{
+		Vault:       vault,
+		Path:        f.Path,
+		PathHash:    pathHash,
+		ContentHash: contentHash,
+		SavePath:    tempPath,
+		Size:        size,
+		Ctime:       f.Ctime,
+		Mtime:       f.Mtime,
+	}, true)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// migrateDiffPatch returns the patch turning from into to, in the format NoteHistoryService stores
+// migrateDiffPatch 返回将 from 变为 to 的补丁，格式与 NoteHistoryService 保存的一致
+func migrateDiffPatch(from, to string) string {
+	dmp := diffmatchpatch.New()
+	return dmp.PatchToText(dmp.PatchMake(from, dmp.DiffMain(from, to, false)))
+}
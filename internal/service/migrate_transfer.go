@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// vaultTransferPlan what a vault transfer moves, worked out before anything is written
+// vaultTransferPlan 写入之前计算出的保险库转移内容
+type vaultTransferPlan struct {
+	notes     []*domain.Note          // Live notes of the source vault // 源保险库中未删除的笔记
+	existing  map[string]*domain.Note // Target notes with the same content, by path hash // 目标保险库中内容相同的笔记，按路径哈希索引
+	replaced  map[string]*domain.Note // Target notes with other content, replaced when overwriting, by path hash // 目标保险库中内容不同、覆盖时被替换的笔记，按路径哈希索引
+	files     []*domain.File          // Live attachments the target vault does not have with the same content // 目标保险库中没有相同内容的未删除附件
+	identical int                     // Entries the target vault has with the same content // 目标保险库中内容相同的条目数
+	trashed   int                     // Entries of the source vault in the trash // 源保险库回收站中的条目数
+	conflicts []*dto.VaultTransferConflictDTO
+}
+
+// planVaultTransfer compares the live entries of the source vault with the target vault; entries the target
+// has with other content are conflicts, entries it has with the same content are not written again
+// planVaultTransfer 比较源保险库与目标保险库的未删除条目；目标中内容不同的条目为冲突，内容相同的条目不再重复写入
+func planVaultTransfer(notes []*domain.Note, files []*domain.File, targetNotes []*domain.Note, targetFiles []*domain.File) *vaultTransferPlan {
+	plan := &vaultTransferPlan{existing: make(map[string]*domain.Note), replaced: make(map[string]*domain.Note)}
+
+	byPathHash := make(map[string]*domain.Note, len(targetNotes))
+	for _, n := range targetNotes {
+		if !n.IsDeleted() {
+			byPathHash[n.PathHash] = n
+		}
+	}
+	for _, n := range notes {
+		if n.IsDeleted() {
+			plan.trashed++
+			continue
+		}
+		plan.notes = append(plan.notes, n)
+		target, ok := byPathHash[n.PathHash]
+		switch {
+		case !ok:
+		case target.ContentHash == n.ContentHash:
+			plan.identical++
+			plan.existing[n.PathHash] = target
+		default:
+			plan.replaced[n.PathHash] = target
+			plan.conflicts = append(plan.conflicts, &dto.VaultTransferConflictDTO{Type: "note", Path: n.Path})
+		}
+	}
+
+	filesByPathHash := make(map[string]*domain.File, len(targetFiles))
+	for _, f := range targetFiles {
+		if !f.IsDeleted() {
+			filesByPathHash[f.PathHash] = f
+		}
+	}
+	for _, f := range files {
+		if f.IsDeleted() {
+			plan.trashed++
+			continue
+		}
+		target, ok := filesByPathHash[f.PathHash]
+		switch {
+		case !ok:
+		case target.ContentHash == f.ContentHash:
+			plan.identical++
+			continue
+		default:
+			plan.conflicts = append(plan.conflicts, &dto.VaultTransferConflictDTO{Type: "file", Path: f.Path})
+		}
+		plan.files = append(plan.files, f)
+	}
+
+	sort.Slice(plan.conflicts, func(i, j int) bool {
+		if plan.conflicts[i].Type != plan.conflicts[j].Type {
+			return plan.conflicts[i].Type > plan.conflicts[j].Type
+		}
+		return plan.conflicts[i].Path < plan.conflicts[j].Path
+	})
+	return plan
+}
+
+// TransferVault implements MigrateService
+func (s *migrateService) TransferVault(ctx context.Context, params *dto.VaultTransferRequest) (*dto.VaultTransferReportDTO, error) {
+	if params.FromUID == params.ToUID {
+		return nil, code.ErrorInvalidParams.WithDetails("source and target user must differ")
+	}
+	targetName := params.TargetVault
+	if targetName == "" {
+		targetName = params.Vault
+	}
+
+	source, err := s.vaultService.GetByName(ctx, params.FromUID, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.userRepo.GetByUID(ctx, params.ToUID, true); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, code.ErrorUserNotFound
+		}
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	notes, err := s.noteRepo.ListByUpdatedTimestamp(ctx, 0, source.ID, params.FromUID)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	files, err := s.fileRepo.ListByUpdatedTimestamp(ctx, 0, source.ID, params.FromUID)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	settings, err := s.settingRepo.ListByUpdatedTimestamp(ctx, 0, source.ID, params.FromUID)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	report := &dto.VaultTransferReportDTO{
+		Vault:       params.Vault,
+		TargetVault: targetName,
+		FromUID:     params.FromUID,
+		ToUID:       params.ToUID,
+		DryRun:      params.DryRun,
+		Conflicts:   []*dto.VaultTransferConflictDTO{},
+	}
+
+	var targetNotes []*domain.Note
+	var targetFiles []*domain.File
+	targetSettings := make(map[string]bool)
+	target, err := s.vaultService.GetByName(ctx, params.ToUID, targetName)
+	switch {
+	case err == nil:
+		report.TargetExists = true
+		if targetNotes, err = s.noteRepo.ListByUpdatedTimestamp(ctx, 0, target.ID, params.ToUID); err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		if targetFiles, err = s.fileRepo.ListByUpdatedTimestamp(ctx, 0, target.ID, params.ToUID); err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		existing, err := s.settingRepo.ListByUpdatedTimestamp(ctx, 0, target.ID, params.ToUID)
+		if err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		for _, st := range existing {
+			if !st.IsDeleted() {
+				targetSettings[st.PathHash] = true
+			}
+		}
+	case !errors.Is(err, code.ErrorVaultNotFound):
+		return nil, err
+	}
+
+	plan := planVaultTransfer(notes, files, targetNotes, targetFiles)
+	histories := make(map[int64][]*domain.NoteHistory, len(plan.notes))
+	for _, n := range plan.notes {
+		report.Size += n.Size
+		// A replaced note keeps the history of the target
+		// 被替换的笔记保留目标笔记的历史
+		if plan.replaced[n.PathHash] != nil {
+			continue
+		}
+		versions, err := s.historyRepo.ListVersions(ctx, n.ID, params.FromUID)
+		if err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		histories[n.ID] = versions
+		report.Histories += len(versions)
+	}
+	for _, f := range plan.files {
+		report.Size += f.Size
+	}
+	var newSettings []*domain.Setting
+	for _, st := range settings {
+		if !st.IsDeleted() && !targetSettings[st.PathHash] {
+			newSettings = append(newSettings, st)
+		}
+	}
+	report.Notes = len(plan.notes) - len(plan.existing)
+	report.Files = len(plan.files)
+	report.Settings = len(newSettings)
+	report.Identical = plan.identical
+	report.Trashed = plan.trashed
+	report.Conflicts = append(report.Conflicts, plan.conflicts...)
+
+	if params.DryRun {
+		return report, nil
+	}
+	if len(plan.conflicts) > 0 && !params.Overwrite {
+		return nil, code.ErrorVaultTransferConflict.WithData(report)
+	}
+
+	if target, err = s.vaultService.GetOrCreate(ctx, params.ToUID, targetName); err != nil {
+		return nil, err
+	}
+	fail := func(name string, err error) {
+		report.Failed++
+		if len(report.Errors) < migrateMaxErrors {
+			report.Errors = append(report.Errors, name+": "+err.Error())
+		}
+	}
+
+	for _, n := range plan.notes {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		var err error
+		if plan.replaced[n.PathHash] != nil {
+			err = s.replaceNote(ctx, n, target, params)
+		} else {
+			err = s.transferNote(ctx, n, plan.existing[n.PathHash], histories[n.ID], target, params)
+		}
+		if err != nil {
+			fail(n.Path, err)
+		}
+	}
+	for _, f := range plan.files {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		if err := s.transferFile(ctx, f, target, params); err != nil {
+			fail(f.Path, err)
+		}
+	}
+	for _, st := range newSettings {
+		_, err := s.settingRepo.Create(ctx, &domain.Setting{
+			VaultID:     target.ID,
+			Action:      domain.SettingActionCreate,
+			Path:        st.Path,
+			PathHash:    st.PathHash,
+			Content:     st.Content,
+			ContentHash: st.ContentHash,
+			Size:        st.Size,
+			Ctime:       st.Ctime,
+			Mtime:       st.Mtime,
+		}, params.ToUID)
+		if err != nil {
+			fail(st.Path, code.ErrorDBQuery.WithDetails(err.Error()))
+		}
+	}
+
+	// The source vault is only dropped once everything arrived, a failed transfer can be run again
+	// 仅在全部内容转移成功后删除源保险库，失败的转移可以重新运行
+	if report.Failed > 0 {
+		return report, nil
+	}
+	if err := s.vaultService.Delete(ctx, params.FromUID, source.ID); err != nil {
+		return report, err
+	}
+	report.Transferred = true
+
+	s.logger.Info("MigrateService.TransferVault",
+		zap.String("vault", params.Vault),
+		zap.Int64("fromUid", params.FromUID),
+		zap.Int64("toUid", params.ToUID),
+		zap.String("targetVault", targetName),
+		zap.Int("notes", report.Notes),
+		zap.Int("files", report.Files))
+	return report, nil
+}
+
+// transferNote writes a note into the target vault unless it has the same content already, keeping the
+// version of the source, and copies the history versions the target note does not have yet
+// transferNote 将笔记写入目标保险库（内容相同时跳过写入），保留源笔记的版本号，并复制目标笔记尚未拥有的历史版本
+func (s *migrateService) transferNote(ctx context.Context, note, existing *domain.Note, versions []*domain.NoteHistory, target *domain.Vault, params *dto.VaultTransferRequest) error {
+	localID := int64(0)
+	if existing != nil {
+		localID = existing.ID
+	} else {
+		_, local, err := s.noteService.ModifyOrCreate(ctx, params.ToUID, &dto.NoteModifyOrCreateRequest{
+			Vault:       target.Name,
+			Path:        note.Path,
+			PathHash:    note.PathHash,
+			Content:     note.Content,
+			ContentHash: note.ContentHash,
+			Ctime:       note.Ctime,
+			Mtime:       note.Mtime,
+		}, false)
+		if err != nil {
+			return err
+		}
+		localID = local.ID
+
+		// With the snapshot equal to the content the history task has nothing to record for the write above
+		// 快照与正文一致时，历史任务不会为上面的写入生成记录
+		if err := s.noteRepo.UpdateSnapshot(ctx, note.Content, note.ContentHash, note.Version, localID, params.ToUID); err != nil {
+			return code.ErrorDBQuery.WithDetails(err.Error())
+		}
+	}
+
+	latest, err := s.historyRepo.GetLatestVersion(ctx, localID, params.ToUID)
+	if err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	// Versions are listed newest first
+	// 版本按从新到旧排列
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].Version <= latest {
+			continue
+		}
+		h, err := s.historyRepo.GetByID(ctx, versions[i].ID, params.FromUID)
+		if err != nil {
+			return code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		_, err = s.historyRepo.Create(ctx, &domain.NoteHistory{
+			NoteID:        localID,
+			VaultID:       target.ID,
+			Path:          h.Path,
+			DiffPatch:     h.DiffPatch,
+			Content:       h.Content,
+			ContentHash:   h.ContentHash,
+			ClientName:    h.ClientName,
+			ClientType:    h.ClientType,
+			ClientVersion: h.ClientVersion,
+			Version:       h.Version,
+			CreatedAt:     h.CreatedAt,
+			UpdatedAt:     h.UpdatedAt,
+		}, params.ToUID)
+		if err != nil {
+			return code.ErrorDBQuery.WithDetails(err.Error())
+		}
+	}
+	return nil
+}
+
+// replaceNote overwrites a target note that has other content; the write is recorded as a new version on top
+// of the history of the target, the history of the source is not copied
+// replaceNote 覆盖目标保险库中内容不同的笔记；该写入作为目标笔记历史之上的新版本记录，不复制源笔记的历史
+func (s *migrateService) replaceNote(ctx context.Context, note *domain.Note, target *domain.Vault, params *dto.VaultTransferRequest) error {
+	_, _, err := s.noteService.ModifyOrCreate(ctx, params.ToUID, &dto.NoteModifyOrCreateRequest{
+		Vault:       target.Name,
+		Path:        note.Path,
+		PathHash:    note.PathHash,
+		Content:     note.Content,
+		ContentHash: note.ContentHash,
+		Ctime:       note.Ctime,
+		Mtime:       note.Mtime,
+	}, false)
+	return err
+}
+
+// transferFile copies the content of an attachment to a temp file and stores it in the target vault via FileService
+// transferFile 将附件内容复制到临时文件后通过 FileService 保存到目标保险库
+func (s *migrateService) transferFile(ctx context.Context, file *domain.File, target *domain.Vault, params *dto.VaultTransferRequest) error {
+	if err := s.fileRepo.FetchContent(ctx, file, params.FromUID); err != nil {
+		return err
+	}
+	src, err := atrest.Open(file.SavePath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	if err := os.MkdirAll(s.tempPath, 0755); err != nil {
+		return err
+	}
+	tempPath := filepath.Join(s.tempPath, uuid.New().String())
+	out, err := os.Create(tempPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempPath)
+
+	_, err = io.Copy(out, src)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.fileService.UpdateOrCreate(ctx, params.ToUID, &dto.FileUpdateRequest{
+		Vault:       target.Name,
+		Path:        file.Path,
+		PathHash:    file.PathHash,
+		ContentHash: file.ContentHash,
+		SavePath:    tempPath,
+		Size:        file.Size,
+		Ctime:       file.Ctime,
+		Mtime:       file.Mtime,
+	}, false)
+	return err
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestPlanVaultTransfer verifies entries the target vault has with other content are conflicts, entries it has
+// with the same content are not written again and trashed entries stay behind.
+// TestPlanVaultTransfer 验证目标保险库中内容不同的条目为冲突，内容相同的条目不再重复写入，回收站中的条目不转移。
+func TestPlanVaultTransfer(t *testing.T) {
+	notes := []*domain.Note{
+		{ID: 1, Path: "new.md", PathHash: "h1", ContentHash: "c1"},
+		{ID: 2, Path: "same.md", PathHash: "h2", ContentHash: "c2"},
+		{ID: 3, Path: "other.md", PathHash: "h3", ContentHash: "c3"},
+		{ID: 4, Path: "trash.md", PathHash: "h4", Action: domain.NoteActionDelete},
+	}
+	files := []*domain.File{
+		{ID: 1, Path: "a.png", PathHash: "f1", ContentHash: "x1"},
+		{ID: 2, Path: "b.png", PathHash: "f2", ContentHash: "x2"},
+		{ID: 3, Path: "c.png", PathHash: "f3", ContentHash: "x3"},
+	}
+	targetNotes := []*domain.Note{
+		{ID: 10, Path: "same.md", PathHash: "h2", ContentHash: "c2"},
+		{ID: 11, Path: "other.md", PathHash: "h3", ContentHash: "zz"},
+		{ID: 12, Path: "new.md", PathHash: "h1", ContentHash: "zz", Action: domain.NoteActionDelete},
+	}
+	targetFiles := []*domain.File{
+		{ID: 10, Path: "b.png", PathHash: "f2", ContentHash: "x2"},
+		{ID: 11, Path: "c.png", PathHash: "f3", ContentHash: "zz"},
+	}
+
+	plan := planVaultTransfer(notes, files, targetNotes, targetFiles)
+	assert.Len(t, plan.notes, 3)
+	require.Contains(t, plan.existing, "h2")
+	assert.Equal(t, int64(10), plan.existing["h2"].ID)
+	require.Len(t, plan.replaced, 1)
+	assert.Equal(t, int64(11), plan.replaced["h3"].ID)
+	require.Len(t, plan.files, 2)
+	assert.Equal(t, "a.png", plan.files[0].Path)
+	assert.Equal(t, "c.png", plan.files[1].Path)
+	assert.Equal(t, 2, plan.identical)
+	assert.Equal(t, 1, plan.trashed)
+
+	require.Len(t, plan.conflicts, 2)
+	assert.Equal(t, "note", plan.conflicts[0].Type)
+	assert.Equal(t, "other.md", plan.conflicts[0].Path)
+	assert.Equal(t, "file", plan.conflicts[1].Type)
+	assert.Equal(t, "c.png", plan.conflicts[1].Path)
+}
+
+// transferVaultService VaultService stub holding the source vault of user 1 and the target vault of user 2
+// transferVaultService 持有用户 1 的源保险库与用户 2 的目标保险库的 VaultService 替身
+type transferVaultService struct {
+	VaultService
+	source, target *domain.Vault
+	targetExists   bool
+	deleted        []int64
+}
+
+func (f *transferVaultService) GetByName(ctx context.Context, uid int64, name string) (*domain.Vault, error) {
+	if uid == f.source.UID {
+		return f.source, nil
+	}
+	if !f.targetExists {
+		return nil, code.ErrorVaultNotFound
+	}
+	return f.target, nil
+}
+
+func (f *transferVaultService) GetOrCreate(ctx context.Context, uid int64, name string) (*domain.Vault, error) {
+	return f.target, nil
+}
+
+func (f *transferVaultService) Delete(ctx context.Context, uid int64, id int64) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+// transferNoteService NoteService stub recording the writes, the stored note IDs are given by path
+// transferNoteService 记录写入的 NoteService 替身，存储的笔记 ID 按路径给出
+type transferNoteService struct {
+	NoteService
+	ids    map[string]int64
+	err    error
+	writes []*dto.NoteModifyOrCreateRequest
+}
+
+func (f *transferNoteService) ModifyOrCreate(ctx context.Context, uid int64, params *dto.NoteModifyOrCreateRequest, mtimeCheck bool, existingNote ...*domain.Note) (bool, *dto.NoteDTO, error) {
+	f.writes = append(f.writes, params)
+	if f.err != nil {
+		return false, nil, f.err
+	}
+	return true, &dto.NoteDTO{ID: f.ids[params.Path], Path: params.Path}, nil
+}
+
+// transferFixture source vault with a new note carrying history and a note the target has with other content
+// transferFixture 源保险库含一篇带历史的新笔记，以及一篇目标保险库中内容不同的笔记
+type transferFixture struct {
+	svc         *migrateService
+	vaults      *transferVaultService
+	notes       *transferNoteService
+	noteRepo    *domainmocks.MockNoteRepository
+	historyRepo *domainmocks.MockNoteHistoryRepository
+}
+
+func newTransferFixture() *transferFixture {
+	f := &transferFixture{
+		vaults: &transferVaultService{
+			source:       &domain.Vault{ID: 100, UID: 1, Name: "main"},
+			target:       &domain.Vault{ID: 200, UID: 2, Name: "main"},
+			targetExists: true,
+		},
+		notes:       &transferNoteService{ids: map[string]int64{"new.md": 21, "other.md": 11}},
+		noteRepo:    new(domainmocks.MockNoteRepository),
+		historyRepo: new(domainmocks.MockNoteHistoryRepository),
+	}
+	userRepo := new(domainmocks.MockUserRepository)
+	fileRepo := new(domainmocks.MockFileRepository)
+	settingRepo := new(domainmocks.MockSettingRepository)
+
+	userRepo.On("GetByUID", mock.Anything, int64(2)).Return(&domain.User{UID: 2}, nil)
+	f.noteRepo.On("ListByUpdatedTimestamp", mock.Anything, int64(0), int64(100), int64(1)).Return([]*domain.Note{
+		{ID: 1, Path: "new.md", PathHash: "h1", Content: "new", ContentHash: "c1", Version: 3},
+		{ID: 3, Path: "other.md", PathHash: "h3", Content: "mine", ContentHash: "c3", Version: 7},
+	}, nil)
+	f.noteRepo.On("ListByUpdatedTimestamp", mock.Anything, int64(0), int64(200), int64(2)).Return([]*domain.Note{
+		{ID: 11, Path: "other.md", PathHash: "h3", Content: "theirs", ContentHash: "zz", Version: 2},
+	}, nil)
+	f.noteRepo.On("UpdateSnapshot", mock.Anything, "new", "c1", int64(3), int64(21), int64(2)).Return(nil)
+	fileRepo.On("ListByUpdatedTimestamp", mock.Anything, int64(0), mock.Anything, mock.Anything).Return(nil, nil)
+	settingRepo.On("ListByUpdatedTimestamp", mock.Anything, int64(0), mock.Anything, mock.Anything).Return(nil, nil)
+
+	f.historyRepo.On("ListVersions", mock.Anything, int64(1), int64(1)).Return([]*domain.NoteHistory{
+		{ID: 50, NoteID: 1, Version: 2},
+		{ID: 49, NoteID: 1, Version: 1},
+	}, nil)
+	f.historyRepo.On("GetLatestVersion", mock.Anything, int64(21), int64(2)).Return(int64(0), nil)
+	f.historyRepo.On("GetByID", mock.Anything, int64(49), int64(1)).Return(&domain.NoteHistory{ID: 49, Content: "v1", Version: 1}, nil)
+	f.historyRepo.On("GetByID", mock.Anything, int64(50), int64(1)).Return(&domain.NoteHistory{ID: 50, Content: "v2", Version: 2}, nil)
+	f.historyRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.NoteHistory"), int64(2)).Return(&domain.NoteHistory{}, nil)
+
+	f.svc = &migrateService{
+		vaultService: f.vaults,
+		noteService:  f.notes,
+		noteRepo:     f.noteRepo,
+		historyRepo:  f.historyRepo,
+		fileRepo:     fileRepo,
+		settingRepo:  settingRepo,
+		userRepo:     userRepo,
+		logger:       zap.NewNop(),
+	}
+	return f
+}
+
+// TestTransferVault_Overwrite verifies an overwritten note becomes a new version on top of the history of the
+// target without the version or history of the source, while new notes keep theirs, and that the source vault
+// is dropped once everything arrived.
+// TestTransferVault_Overwrite 验证被覆盖的笔记作为目标笔记历史之上的新版本写入，不带源笔记的版本号与历史，
+// 新笔记保留自身的版本与历史，且全部转移成功后删除源保险库。
+func TestTransferVault_Overwrite(t *testing.T) {
+	f := newTransferFixture()
+
+	report, err := f.svc.TransferVault(context.Background(), &dto.VaultTransferRequest{Vault: "main", FromUID: 1, ToUID: 2, Overwrite: true})
+	require.NoError(t, err)
+	assert.True(t, report.Transferred)
+	assert.Equal(t, 2, report.Histories)
+	assert.Zero(t, report.Failed)
+	assert.Equal(t, []int64{100}, f.vaults.deleted)
+
+	require.Len(t, f.notes.writes, 2)
+	assert.Equal(t, "mine", f.notes.writes[1].Content)
+
+	f.noteRepo.AssertNumberOfCalls(t, "UpdateSnapshot", 1)
+	f.historyRepo.AssertNotCalled(t, "ListVersions", mock.Anything, int64(3), int64(1))
+	f.historyRepo.AssertNotCalled(t, "GetLatestVersion", mock.Anything, int64(11), int64(2))
+	var versions []int64
+	for _, call := range f.historyRepo.Calls {
+		if call.Method == "Create" {
+			h := call.Arguments.Get(1).(*domain.NoteHistory)
+			assert.Equal(t, int64(21), h.NoteID)
+			versions = append(versions, h.Version)
+		}
+	}
+	assert.Equal(t, []int64{1, 2}, versions)
+}
+
+// TestTransferVault_KeepsSource verifies the source vault stays when the transfer stops on conflicts or a write fails.
+// TestTransferVault_KeepsSource 验证转移因冲突中止或写入失败时保留源保险库。
+func TestTransferVault_KeepsSource(t *testing.T) {
+	f := newTransferFixture()
+	_, err := f.svc.TransferVault(context.Background(), &dto.VaultTransferRequest{Vault: "main", FromUID: 1, ToUID: 2})
+	assert.ErrorIs(t, err, code.ErrorVaultTransferConflict)
+	assert.Empty(t, f.notes.writes)
+	assert.Empty(t, f.vaults.deleted)
+
+	f = newTransferFixture()
+	f.notes.err = errors.New("disk full")
+	report, err := f.svc.TransferVault(context.Background(), &dto.VaultTransferRequest{Vault: "main", FromUID: 1, ToUID: 2, Overwrite: true})
+	require.NoError(t, err)
+	assert.False(t, report.Transferred)
+	assert.Equal(t, 2, report.Failed)
+	assert.Empty(t, f.vaults.deleted)
+}
@@ -4,10 +4,13 @@ package mocks
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/internal/service"
 	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/maintenance"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -64,7 +67,49 @@ func (m *MockBackupService) NotifyUpdated(uid int64) {
 	m.Called(uid)
 }
 
+func (m *MockBackupService) ExportZip(ctx context.Context, uid int64, params *dto.VaultExportRequest, modTime time.Time, w io.Writer) error {
+	args := m.Called(ctx, uid, params, modTime, w)
+	return args.Error(0)
+}
+
+func (m *MockBackupService) ExportArtifact(ctx context.Context, uid int64, params *dto.VaultExportRequest) (string, time.Time, error) {
+	args := m.Called(ctx, uid, params)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockBackupService) ExportSite(ctx context.Context, uid int64, params *dto.VaultSiteExportRequest) (*dto.VaultSiteExportResult, error) {
+	args := m.Called(ctx, uid, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.VaultSiteExportResult), args.Error(1)
+}
+
 func (m *MockBackupService) Shutdown(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
 }
+
+func (m *MockBackupService) SetProgressHandler(handler func(uid int64, msg *dto.BackupProgressMessage)) {
+	m.Called(handler)
+}
+
+func (m *MockBackupService) SetMaintenance(coordinator *maintenance.Coordinator) {
+	m.Called(coordinator)
+}
+
+func (m *MockBackupService) SetNotifier(notifier service.Notifier) {
+	m.Called(notifier)
+}
+
+func (m *MockBackupService) SetAlerter(alerter service.Alerter) {
+	m.Called(alerter)
+}
+
+func (m *MockBackupService) Verify(ctx context.Context, uid int64, historyID int64, extract bool) (*dto.BackupVerifyDTO, error) {
+	args := m.Called(ctx, uid, historyID, extract)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.BackupVerifyDTO), args.Error(1)
+}
@@ -123,6 +123,11 @@ func (m *MockFileService) GetContentInfo(ctx context.Context, uid int64, params
 	return args.String(0), args.String(1), args.Get(2).(int64), args.String(3), args.String(4), args.Error(5)
 }
 
+func (m *MockFileService) FetchContent(ctx context.Context, uid int64, file *dto.FileDTO) error {
+	args := m.Called(ctx, uid, file)
+	return args.Error(0)
+}
+
 func (m *MockFileService) Restore(ctx context.Context, uid int64, params *dto.FileRestoreRequest) (*dto.FileDTO, error) {
 	args := m.Called(ctx, uid, params)
 	if v := args.Get(0); v != nil {
@@ -131,6 +136,14 @@ func (m *MockFileService) Restore(ctx context.Context, uid int64, params *dto.Fi
 	return nil, args.Error(1)
 }
 
+func (m *MockFileService) Copy(ctx context.Context, uid int64, params *dto.FileCopyRequest) (*dto.FileDTO, error) {
+	args := m.Called(ctx, uid, params)
+	if v := args.Get(0); v != nil {
+		return v.(*dto.FileDTO), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockFileService) Rename(ctx context.Context, uid int64, params *dto.FileRenameRequest) (*dto.FileDTO, *dto.FileDTO, error) {
 	args := m.Called(ctx, uid, params)
 	var old, newF *dto.FileDTO
@@ -81,6 +81,14 @@ func (m *MockFolderService) Rename(ctx context.Context, uid int64, params *dto.F
 	return old, newF, args.Error(2)
 }
 
+func (m *MockFolderService) Move(ctx context.Context, uid int64, params *dto.FolderMoveRequest) (*dto.FolderMoveResult, error) {
+	args := m.Called(ctx, uid, params)
+	if v := args.Get(0); v != nil {
+		return v.(*dto.FolderMoveResult), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockFolderService) ListNotes(ctx context.Context, uid int64, params *dto.FolderContentRequest, pager *app.Pager) ([]*dto.NoteNoContentDTO, int, error) {
 	args := m.Called(ctx, uid, params, pager)
 	if v := args.Get(0); v != nil {
@@ -125,6 +133,27 @@ func (m *MockFolderService) CleanDuplicateFolders(ctx context.Context, uid int64
 	return args.Error(0)
 }
 
+func (m *MockFolderService) SetArchived(ctx context.Context, uid int64, params *dto.FolderArchiveRequest) (*dto.FolderDTO, error) {
+	args := m.Called(ctx, uid, params)
+	if v := args.Get(0); v != nil {
+		return v.(*dto.FolderDTO), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFolderService) CheckWritable(ctx context.Context, uid int64, vaultID int64, paths ...string) error {
+	args := m.Called(ctx, uid, vaultID, paths)
+	return args.Error(0)
+}
+
+func (m *MockFolderService) ArchivedPaths(ctx context.Context, uid int64, vaultID int64) ([]string, error) {
+	args := m.Called(ctx, uid, vaultID)
+	if v := args.Get(0); v != nil {
+		return v.([]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockFolderService) WithClient(clientType, name, version string) service.FolderService {
 	args := m.Called(clientType, name, version)
 	return args.Get(0).(service.FolderService)
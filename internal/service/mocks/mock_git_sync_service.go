@@ -77,6 +77,10 @@ func (m *MockGitSyncService) NotifyUpdated(uid int64, vaultID int64) {
 	m.Called(uid, vaultID)
 }
 
+func (m *MockGitSyncService) SetNotifier(notifier service.Notifier) {
+	m.Called(notifier)
+}
+
 func (m *MockGitSyncService) Shutdown(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -4,6 +4,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/internal/service"
@@ -67,3 +68,12 @@ func (m *MockNoteHistoryService) CleanupByTime(ctx context.Context, cutoffTime i
 	args := m.Called(ctx, cutoffTime, keepVersions)
 	return args.Error(0)
 }
+
+func (m *MockNoteHistoryService) Compact(ctx context.Context, maxAge time.Duration, keepVersions int) error {
+	args := m.Called(ctx, maxAge, keepVersions)
+	return args.Error(0)
+}
+
+func (m *MockNoteHistoryService) SetSecretScan(secretScan service.SecretScanService) {
+	m.Called(secretScan)
+}
@@ -35,3 +35,27 @@ func (m *MockNoteLinkService) GetOutlinks(ctx context.Context, uid int64, params
 	}
 	return nil, args.Error(1)
 }
+
+func (m *MockNoteLinkService) ResolveLinks(ctx context.Context, uid int64, vaultName string, notePath string, content string) (map[string]string, error) {
+	args := m.Called(ctx, uid, vaultName, notePath, content)
+	if v := args.Get(0); v != nil {
+		return v.(map[string]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteLinkService) GetGraph(ctx context.Context, uid int64, params *dto.VaultGraphRequest) (*dto.VaultGraphDTO, error) {
+	args := m.Called(ctx, uid, params)
+	if v := args.Get(0); v != nil {
+		return v.(*dto.VaultGraphDTO), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteLinkService) ListUnresolved(ctx context.Context, uid int64, vaultName string) ([]*dto.NoteUnresolvedLinkItem, error) {
+	args := m.Called(ctx, uid, vaultName)
+	if v := args.Get(0); v != nil {
+		return v.([]*dto.NoteUnresolvedLinkItem), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
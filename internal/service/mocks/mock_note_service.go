@@ -164,6 +164,10 @@ func (m *MockNoteService) WithClient(clientType, name, version string) service.N
 	return args.Get(0).(service.NoteService)
 }
 
+func (m *MockNoteService) SetSecretScan(secretScan service.SecretScanService) {
+	m.Called(secretScan)
+}
+
 func (m *MockNoteService) PatchFrontmatter(ctx context.Context, uid int64, params *dto.NotePatchFrontmatterRequest) (*dto.NoteDTO, error) {
 	args := m.Called(ctx, uid, params)
 	if v := args.Get(0); v != nil {
@@ -180,6 +184,14 @@ func (m *MockNoteService) AppendContent(ctx context.Context, uid int64, params *
 	return nil, args.Error(1)
 }
 
+func (m *MockNoteService) Duplicate(ctx context.Context, uid int64, params *dto.NoteDuplicateRequest, fileLinks map[string]string) (*dto.NoteDTO, error) {
+	args := m.Called(ctx, uid, params, fileLinks)
+	if v := args.Get(0); v != nil {
+		return v.(*dto.NoteDTO), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockNoteService) PrependContent(ctx context.Context, uid int64, params *dto.NotePrependRequest) (*dto.NoteDTO, error) {
 	args := m.Called(ctx, uid, params)
 	if v := args.Get(0); v != nil {
@@ -188,6 +200,14 @@ func (m *MockNoteService) PrependContent(ctx context.Context, uid int64, params
 	return nil, args.Error(1)
 }
 
+func (m *MockNoteService) AppendToHeading(ctx context.Context, uid int64, params *dto.NoteAppendToHeadingRequest) (*dto.NoteAppendToHeadingResponse, error) {
+	args := m.Called(ctx, uid, params)
+	if v := args.Get(0); v != nil {
+		return v.(*dto.NoteAppendToHeadingResponse), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockNoteService) ReplaceContent(ctx context.Context, uid int64, params *dto.NoteReplaceRequest) (*dto.NoteReplaceResponse, error) {
 	args := m.Called(ctx, uid, params)
 	if v := args.Get(0); v != nil {
@@ -196,10 +216,30 @@ func (m *MockNoteService) ReplaceContent(ctx context.Context, uid int64, params
 	return nil, args.Error(1)
 }
 
+func (m *MockNoteService) IndexNote(uid, vaultID, noteID int64, path, content string) {
+	m.Called(uid, vaultID, noteID, path, content)
+}
+
 func (m *MockNoteService) UpdateNoteLinks(ctx context.Context, noteID int64, content string, vaultID, uid int64) {
 	m.Called(ctx, noteID, content, vaultID, uid)
 }
 
+func (m *MockNoteService) UpdateNoteProperties(ctx context.Context, noteID int64, content string, vaultID, uid int64) {
+	m.Called(ctx, noteID, content, vaultID, uid)
+}
+
+func (m *MockNoteService) UpdateNoteStats(ctx context.Context, noteID int64, path, content string, vaultID, uid int64) {
+	m.Called(ctx, noteID, path, content, vaultID, uid)
+}
+
+func (m *MockNoteService) UpdateRenamedLinks(ctx context.Context, uid int64, vaultName string, oldPath string, newPath string) ([]*dto.NoteDTO, error) {
+	args := m.Called(ctx, uid, vaultName, oldPath, newPath)
+	if v := args.Get(0); v != nil {
+		return v.([]*dto.NoteDTO), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockNoteService) RecycleClear(ctx context.Context, uid int64, params *dto.NoteRecycleClearRequest) error {
 	args := m.Called(ctx, uid, params)
 	return args.Error(0)
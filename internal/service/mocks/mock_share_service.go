@@ -60,6 +60,14 @@ func (m *MockShareService) GetSharedFileInfo(ctx context.Context, shareToken str
 	return args.String(0), args.String(1), args.Get(2).(int64), args.String(3), args.String(4), args.Error(5)
 }
 
+func (m *MockShareService) GetSharedFolderFeed(ctx context.Context, shareToken string, password string) (*dto.ShareFolderFeed, error) {
+	args := m.Called(ctx, shareToken, password)
+	if v := args.Get(0); v != nil {
+		return v.(*dto.ShareFolderFeed), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockShareService) RecordView(uid int64, id int64) {
 	m.Called(uid, id)
 }
@@ -91,6 +91,32 @@ func (m *MockUserService) GetList(ctx context.Context, pager *pkgapp.Pager) ([]*
 	return args.Get(0).([]*dto.UserDTO), int64(args.Int(1)), args.Error(2)
 }
 
+// RequestPasswordReset emails a reset link.
+// RequestPasswordReset 发送密码重置链接。
+func (m *MockUserService) RequestPasswordReset(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+// ResetPassword sets a new password with a reset token.
+// ResetPassword 使用重置令牌设置新密码。
+func (m *MockUserService) ResetPassword(ctx context.Context, params *dto.UserResetPasswordRequest) error {
+	args := m.Called(ctx, params)
+	return args.Error(0)
+}
+
+// SetMailer sets the mailer of the reset emails.
+// SetMailer 设置发送重置邮件的邮件发送器。
+func (m *MockUserService) SetMailer(mailer service.Mailer) {
+	m.Called(mailer)
+}
+
+// SetRegistration sets the invitation and approval service.
+// SetRegistration 设置邀请码与审核服务。
+func (m *MockUserService) SetRegistration(registration service.RegistrationService) {
+	m.Called(registration)
+}
+
 // IsRegisterEnabled checks if registration is allowed.
 // IsRegisterEnabled 检查是否允许注册。
 func (m *MockUserService) IsRegisterEnabled(ctx context.Context) bool {
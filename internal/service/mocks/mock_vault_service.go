@@ -118,6 +118,56 @@ func (m *MockVaultService) ForceDeleteDataItem(ctx context.Context, uid int64, v
 	return args.Error(0)
 }
 
+// Trash mock implementation.
+func (m *MockVaultService) Trash(ctx context.Context, uid int64, vault string) ([]*dto.VaultTrashItemDTO, error) {
+	args := m.Called(ctx, uid, vault)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*dto.VaultTrashItemDTO), args.Error(1)
+}
+
+// EmptyTrash mock implementation.
+func (m *MockVaultService) EmptyTrash(ctx context.Context, uid int64, params *dto.VaultTrashEmptyRequest, clientType, clientName, clientVersion string) (*dto.VaultTrashEmptyResult, error) {
+	args := m.Called(ctx, uid, params, clientType, clientName, clientVersion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.VaultTrashEmptyResult), args.Error(1)
+}
+
+// AsOf mock implementation.
+func (m *MockVaultService) AsOf(ctx context.Context, uid int64, params *dto.VaultAsOfRequest) ([]*dto.VaultAsOfItemDTO, error) {
+	args := m.Called(ctx, uid, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*dto.VaultAsOfItemDTO), args.Error(1)
+}
+
+// AsOfNote mock implementation.
+func (m *MockVaultService) AsOfNote(ctx context.Context, uid int64, params *dto.VaultAsOfNoteRequest) (*dto.VaultAsOfNoteDTO, error) {
+	args := m.Called(ctx, uid, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.VaultAsOfNoteDTO), args.Error(1)
+}
+
+// Activity mock implementation.
+func (m *MockVaultService) Activity(ctx context.Context, uid int64, params *dto.VaultActivityRequest) (*dto.VaultActivityDTO, error) {
+	args := m.Called(ctx, uid, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.VaultActivityDTO), args.Error(1)
+}
+
+// SetAuditLogRepository mock implementation.
+func (m *MockVaultService) SetAuditLogRepository(repo domain.AuditLogRepository) {
+	m.Called(repo)
+}
+
 
 // Compile-time check: MockVaultService must implement service.VaultService.
 // 编译时检查：MockVaultService 必须实现 service.VaultService 接口。
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// NoteAccess describes who read a note and through which channel
+// NoteAccess 描述谁通过哪个渠道读取了笔记
+type NoteAccess struct {
+	Channel    domain.NoteAccessChannel
+	Actor      string // Share ID, token ID or client name // 分享 ID、令牌 ID 或客户端名称
+	ClientType string
+	IP         string
+	UserAgent  string
+}
+
+// ShareActor actor of a read through a share link
+// ShareActor 通过分享链接读取时的读取者标识
+func ShareActor(shareID int64) string {
+	return "share:" + strconv.FormatInt(shareID, 10)
+}
+
+// TokenActor actor of a read with a user or API token
+// TokenActor 使用用户或 API 令牌读取时的读取者标识
+func TokenActor(tokenID int64) string {
+	return "token:" + strconv.FormatInt(tokenID, 10)
+}
+
+// NoteAccessService defines the opt-in per-note access log business service interface
+// NoteAccessService 定义按笔记开启的访问日志业务服务接口
+type NoteAccessService interface {
+	// Get returns whether access logging is enabled for a note
+	// Get 返回笔记是否开启了访问日志
+	Get(ctx context.Context, uid int64, params *dto.NoteAccessRequest) (*dto.NoteAccessSettingDTO, error)
+
+	// Set enables or disables access logging of a note
+	// Set 开启或关闭笔记的访问日志
+	Set(ctx context.Context, uid int64, params *dto.NoteAccessSettingRequest) (*dto.NoteAccessSettingDTO, error)
+
+	// List retrieves the access log of a note with pagination, newest first
+	// List 按时间倒序分页查询笔记的访问日志
+	List(ctx context.Context, uid int64, params *dto.NoteAccessRequest, page, pageSize int) ([]*dto.NoteAccessLogDTO, int64, error)
+
+	// Record asynchronously logs a read of a note; reads of notes without access logging cost one map lookup
+	// Record 异步记录一次笔记读取；未开启访问日志的笔记只需一次 map 查询
+	Record(uid, noteID int64, path string, access NoteAccess)
+
+	// CleanupByTime removes access log entries older than the given cutoff time for all users
+	// CleanupByTime 清理所有用户在指定截止时间之前的访问日志
+	CleanupByTime(ctx context.Context, cutoffTime int64) error
+}
+
+// noteAccessService implements NoteAccessService
+// noteAccessService 实现 NoteAccessService 接口
+type noteAccessService struct {
+	repo         domain.NoteAccessRepository
+	noteRepo     domain.NoteRepository
+	vaultService VaultService
+	logger       *zap.Logger
+
+	mu      sync.RWMutex
+	watched map[int64]map[int64]int64 // uid -> note ID -> vault ID, loaded on first use, inner maps are never mutated // uid -> 笔记 ID -> 笔记库 ID，首次使用时加载，内层 map 不会被修改
+}
+
+// NewNoteAccessService creates a NoteAccessService instance
+// NewNoteAccessService 创建 NoteAccessService 实例
+func NewNoteAccessService(repo domain.NoteAccessRepository, noteRepo domain.NoteRepository, vaultSvc VaultService, logger *zap.Logger) NoteAccessService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &noteAccessService{
+		repo:         repo,
+		noteRepo:     noteRepo,
+		vaultService: vaultSvc,
+		logger:       logger,
+		watched:      make(map[int64]map[int64]int64),
+	}
+}
+
+// watches returns the watched notes of a user, loading them from the repository once
+// watches 返回用户开启访问日志的笔记，仅从仓储加载一次
+func (s *noteAccessService) watches(ctx context.Context, uid int64) (map[int64]int64, error) {
+	s.mu.RLock()
+	notes, ok := s.watched[uid]
+	s.mu.RUnlock()
+	if ok {
+		return notes, nil
+	}
+
+	list, err := s.repo.ListWatches(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	notes = make(map[int64]int64, len(list))
+	for _, w := range list {
+		notes[w.NoteID] = w.VaultID
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.watched[uid]; ok {
+		return existing, nil
+	}
+	s.watched[uid] = notes
+	return notes, nil
+}
+
+// isWatched reports whether a note is watched, using only the in-memory cache
+// isWatched 判断笔记是否开启访问日志，仅查询内存缓存
+func (s *noteAccessService) isWatched(uid, noteID int64) (vaultID int64, watched, loaded bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	notes, loaded := s.watched[uid]
+	if !loaded {
+		return 0, false, false
+	}
+	vaultID, watched = notes[noteID]
+	return vaultID, watched, true
+}
+
+// resolveNote finds the note addressed by vault and path
+// resolveNote 根据笔记库与路径查找笔记
+func (s *noteAccessService) resolveNote(ctx context.Context, uid int64, vault, path, pathHash string) (*domain.Note, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, vault)
+	if err != nil {
+		return nil, err
+	}
+	if pathHash == "" {
+		pathHash = util.EncodeHash32(path)
+	}
+	note, err := s.noteRepo.GetByPathHash(ctx, pathHash, vaultID, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, code.ErrorNoteNotFound
+		}
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return note, nil
+}
+
+// Get returns whether access logging is enabled for a note
+// Get 返回笔记是否开启了访问日志
+func (s *noteAccessService) Get(ctx context.Context, uid int64, params *dto.NoteAccessRequest) (*dto.NoteAccessSettingDTO, error) {
+	note, err := s.resolveNote(ctx, uid, params.Vault, params.Path, params.PathHash)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.watches(ctx, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	_, enabled := notes[note.ID]
+	return &dto.NoteAccessSettingDTO{NoteID: note.ID, Path: note.Path, Enabled: enabled}, nil
+}
+
+// Set enables or disables access logging of a note
+// Set 开启或关闭笔记的访问日志
+func (s *noteAccessService) Set(ctx context.Context, uid int64, params *dto.NoteAccessSettingRequest) (*dto.NoteAccessSettingDTO, error) {
+	note, err := s.resolveNote(ctx, uid, params.Vault, params.Path, params.PathHash)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.watches(ctx, uid); err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	if params.Enabled {
+		err = s.repo.Watch(ctx, &domain.NoteAccessWatch{UID: uid, VaultID: note.VaultID, NoteID: note.ID, CreatedAt: time.Now()}, uid)
+	} else {
+		err = s.repo.Unwatch(ctx, note.ID, uid)
+	}
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	// Copy on write so readers holding the old map never see a concurrent write
+	// 写时复制，持有旧 map 的读者不会遇到并发写入
+	s.mu.Lock()
+	notes := make(map[int64]int64, len(s.watched[uid])+1)
+	for id, vid := range s.watched[uid] {
+		notes[id] = vid
+	}
+	if params.Enabled {
+		notes[note.ID] = note.VaultID
+	} else {
+		delete(notes, note.ID)
+	}
+	s.watched[uid] = notes
+	s.mu.Unlock()
+
+	return &dto.NoteAccessSettingDTO{NoteID: note.ID, Path: note.Path, Enabled: params.Enabled}, nil
+}
+
+// List retrieves the access log of a note with pagination, newest first
+// List 按时间倒序分页查询笔记的访问日志
+func (s *noteAccessService) List(ctx context.Context, uid int64, params *dto.NoteAccessRequest, page, pageSize int) ([]*dto.NoteAccessLogDTO, int64, error) {
+	note, err := s.resolveNote(ctx, uid, params.Vault, params.Path, params.PathHash)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	logs, total, err := s.repo.ListLogs(ctx, note.ID, uid, page, pageSize)
+	if err != nil {
+		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	results := make([]*dto.NoteAccessLogDTO, 0, len(logs))
+	for _, l := range logs {
+		results = append(results, &dto.NoteAccessLogDTO{
+			Path:       l.Path,
+			Channel:    string(l.Channel),
+			Actor:      l.Actor,
+			ClientType: l.ClientType,
+			IP:         l.IP,
+			UserAgent:  l.UA,
+			CreatedAt:  timex.Time(l.CreatedAt),
+		})
+	}
+	return results, total, nil
+}
+
+// Record asynchronously logs a read of a note; reads of notes without access logging cost one map lookup
+// Record 异步记录一次笔记读取；未开启访问日志的笔记只需一次 map 查询
+func (s *noteAccessService) Record(uid, noteID int64, path string, access NoteAccess) {
+	if uid == 0 || noteID == 0 {
+		return
+	}
+	vaultID, watched, loaded := s.isWatched(uid, noteID)
+	if loaded && !watched {
+		return
+	}
+
+	now := time.Now()
+	safego.Go(s.logger, func() {
+		ctx := context.Background()
+		if !loaded {
+			notes, err := s.watches(ctx, uid)
+			if err != nil {
+				s.logger.Warn("NoteAccessService.Record: load watched notes failed", zap.Int64("uid", uid), zap.Error(err))
+				return
+			}
+			vaultID, watched = notes[noteID]
+			if !watched {
+				return
+			}
+		}
+
+		err := s.repo.CreateLog(ctx, &domain.NoteAccessLog{
+			UID:        uid,
+			VaultID:    vaultID,
+			NoteID:     noteID,
+			Path:       path,
+			Channel:    access.Channel,
+			Actor:      access.Actor,
+			ClientType: access.ClientType,
+			IP:         access.IP,
+			UA:         access.UserAgent,
+			CreatedAt:  now,
+		}, uid)
+		if err != nil {
+			s.logger.Warn("NoteAccessService.Record: write access log failed",
+				zap.Int64("uid", uid),
+				zap.Int64("noteID", noteID),
+				zap.String("channel", string(access.Channel)),
+				zap.Error(err),
+			)
+		}
+	})
+}
+
+// CleanupByTime removes access log entries older than the given cutoff time for all users
+// CleanupByTime 清理所有用户在指定截止时间之前的访问日志
+func (s *noteAccessService) CleanupByTime(ctx context.Context, cutoffTime int64) error {
+	return s.repo.CleanupByTimeAll(ctx, cutoffTime)
+}
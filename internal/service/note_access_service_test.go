@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeNoteAccessRepo in-memory domain.NoteAccessRepository
+type fakeNoteAccessRepo struct {
+	mu        sync.Mutex
+	watches   map[int64]*domain.NoteAccessWatch
+	logs      []*domain.NoteAccessLog
+	listCalls int
+	logged    chan struct{}
+}
+
+func newFakeNoteAccessRepo() *fakeNoteAccessRepo {
+	return &fakeNoteAccessRepo{watches: map[int64]*domain.NoteAccessWatch{}, logged: make(chan struct{}, 16)}
+}
+
+func (r *fakeNoteAccessRepo) Watch(ctx context.Context, w *domain.NoteAccessWatch, uid int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.watches[w.NoteID] = w
+	return nil
+}
+
+func (r *fakeNoteAccessRepo) Unwatch(ctx context.Context, noteID, uid int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.watches, noteID)
+	return nil
+}
+
+func (r *fakeNoteAccessRepo) ListWatches(ctx context.Context, uid int64) ([]*domain.NoteAccessWatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listCalls++
+	var list []*domain.NoteAccessWatch
+	for _, w := range r.watches {
+		list = append(list, w)
+	}
+	return list, nil
+}
+
+func (r *fakeNoteAccessRepo) CreateLog(ctx context.Context, log *domain.NoteAccessLog, uid int64) error {
+	r.mu.Lock()
+	r.logs = append(r.logs, log)
+	r.mu.Unlock()
+	r.logged <- struct{}{}
+	return nil
+}
+
+func (r *fakeNoteAccessRepo) ListLogs(ctx context.Context, noteID, uid int64, page, pageSize int) ([]*domain.NoteAccessLog, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*domain.NoteAccessLog
+	for _, l := range r.logs {
+		if l.NoteID == noteID {
+			list = append(list, l)
+		}
+	}
+	return list, int64(len(list)), nil
+}
+
+func (r *fakeNoteAccessRepo) CleanupByTimeAll(ctx context.Context, timestamp int64) error {
+	return nil
+}
+
+func (r *fakeNoteAccessRepo) waitLogged(t *testing.T) {
+	t.Helper()
+	select {
+	case <-r.logged:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected an access log entry to be written")
+	}
+}
+
+func newTestNoteAccessService(repo *fakeNoteAccessRepo) (*noteAccessService, *domainmocks.MockNoteRepository) {
+	noteRepo := new(domainmocks.MockNoteRepository)
+	noteRepo.On("GetByPathHash", mock.Anything, util.EncodeHash32("secret.md"), int64(7), int64(1)).
+		Return(&domain.Note{ID: 11, VaultID: 7, Path: "secret.md"}, nil)
+	noteRepo.On("GetByPathHash", mock.Anything, mock.Anything, int64(7), int64(1)).
+		Return(nil, gorm.ErrRecordNotFound)
+	svc := NewNoteAccessService(repo, noteRepo, &fakeVaultServiceForConflictTest{vaultID: 7}, zap.NewNop()).(*noteAccessService)
+	return svc, noteRepo
+}
+
+// TestNoteAccessService_RecordsOnlyWatchedNotes verifies reads are logged only after the owner opted in.
+// TestNoteAccessService_RecordsOnlyWatchedNotes 验证仅在所有者开启后才记录读取。
+func TestNoteAccessService_RecordsOnlyWatchedNotes(t *testing.T) {
+	repo := newFakeNoteAccessRepo()
+	svc, _ := newTestNoteAccessService(repo)
+	ctx := context.Background()
+
+	setting, err := svc.Get(ctx, 1, &dto.NoteAccessRequest{Vault: "v", Path: "secret.md"})
+	require.NoError(t, err)
+	assert.False(t, setting.Enabled)
+
+	// Not watched: the cache is loaded, so Record returns without touching the repository
+	// 未开启：缓存已加载，Record 不访问仓储直接返回
+	svc.Record(1, 11, "secret.md", NoteAccess{Channel: domain.NoteAccessChannelAPI})
+
+	setting, err = svc.Set(ctx, 1, &dto.NoteAccessSettingRequest{Vault: "v", Path: "secret.md", Enabled: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), setting.NoteID)
+	assert.True(t, setting.Enabled)
+
+	svc.Record(1, 11, "secret.md", NoteAccess{Channel: domain.NoteAccessChannelShare, Actor: ShareActor(5), IP: "10.0.0.1"})
+	repo.waitLogged(t)
+
+	logs, total, err := svc.List(ctx, 1, &dto.NoteAccessRequest{Vault: "v", Path: "secret.md"}, 1, 20)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, "share", logs[0].Channel)
+	assert.Equal(t, "share:5", logs[0].Actor)
+	assert.Equal(t, "10.0.0.1", logs[0].IP)
+	assert.Equal(t, 1, repo.listCalls, "watched notes must be loaded once")
+
+	_, err = svc.Set(ctx, 1, &dto.NoteAccessSettingRequest{Vault: "v", Path: "secret.md", Enabled: false})
+	require.NoError(t, err)
+	svc.Record(1, 11, "secret.md", NoteAccess{Channel: domain.NoteAccessChannelAPI})
+	assert.Len(t, repo.logs, 1)
+}
+
+// TestNoteAccessService_RecordLoadsCacheLazily verifies the first read after startup still logs watched notes.
+// TestNoteAccessService_RecordLoadsCacheLazily 验证启动后的首次读取仍会记录受监控笔记。
+func TestNoteAccessService_RecordLoadsCacheLazily(t *testing.T) {
+	repo := newFakeNoteAccessRepo()
+	repo.watches[11] = &domain.NoteAccessWatch{UID: 1, VaultID: 7, NoteID: 11}
+	svc, _ := newTestNoteAccessService(repo)
+
+	svc.Record(1, 11, "secret.md", NoteAccess{Channel: domain.NoteAccessChannelWebDAV, Actor: "Finder"})
+	repo.waitLogged(t)
+
+	require.Len(t, repo.logs, 1)
+	assert.Equal(t, int64(7), repo.logs[0].VaultID)
+	assert.Equal(t, domain.NoteAccessChannelWebDAV, repo.logs[0].Channel)
+}
+
+// TestNoteAccessService_NoteNotFound verifies settings of unknown notes are rejected.
+// TestNoteAccessService_NoteNotFound 验证不存在的笔记无法设置。
+func TestNoteAccessService_NoteNotFound(t *testing.T) {
+	svc, _ := newTestNoteAccessService(newFakeNoteAccessRepo())
+
+	_, err := svc.Set(context.Background(), 1, &dto.NoteAccessSettingRequest{Vault: "v", Path: "missing.md", Enabled: true})
+	assert.Equal(t, code.ErrorNoteNotFound, err)
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+var (
+	// calendarStartKeys frontmatter properties holding the date of a note, first match wins
+	// calendarStartKeys 表示笔记日期的 frontmatter 属性，按顺序取第一个匹配
+	calendarStartKeys = []string{"date", "start", "due", "scheduled"}
+	// calendarEndKeys frontmatter properties holding the end of a note's event
+	// calendarEndKeys 表示笔记事件结束时间的 frontmatter 属性
+	calendarEndKeys = []string{"end", "end-date", "endDate"}
+
+	// calendarDateLayouts accepted date and time layouts, layouts without a zone give floating times
+	// calendarDateLayouts 支持的日期与时间格式，不含时区的格式得到浮动时间
+	calendarDateLayouts = []struct {
+		layout string
+		allDay bool
+		zoned  bool
+	}{
+		{"2006-01-02", true, false},
+		{time.RFC3339, false, true},
+		{"2006-01-02T15:04Z07:00", false, true},
+		{"2006-01-02T15:04:05", false, false},
+		{"2006-01-02T15:04", false, false},
+		{"2006-01-02 15:04:05", false, false},
+		{"2006-01-02 15:04", false, false},
+	}
+
+	// calendarTaskRegex matches a Markdown task
+	// Group 1: status character // 状态字符
+	// Group 2: task text // 任务文本
+	// calendarTaskRegex 匹配 Markdown 任务
+	calendarTaskRegex = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[(.)\]\s+(.+)$`)
+	// calendarDueRegex matches the due date of a task in the Tasks plugin (📅) or Dataview (due::) format
+	// calendarDueRegex 匹配 Tasks 插件 (📅) 或 Dataview (due::) 格式的任务截止日期
+	calendarDueRegex = regexp.MustCompile(`(?:📅\x{FE0F}?|\bdue::)\s*(\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2})?)?)`)
+	// calendarTaskFieldRegex matches the date fields of a task, stripped from the event title
+	// calendarTaskFieldRegex 匹配任务的日期字段，从事件标题中去除
+	calendarTaskFieldRegex = regexp.MustCompile(`\s*(?:\[\w+::[^\]]*\]|\(\w+::[^)]*\)|\b\w+::\s*\S+|[📅⏳🛫✅➕❌]\x{FE0F}?\s*\d{4}-\d{2}-\d{2})`)
+)
+
+// NoteCalendarService defines the calendar feed service of dated notes and tasks
+// NoteCalendarService 定义带日期笔记与任务的日历订阅源服务接口
+type NoteCalendarService interface {
+	// Events lists the events of the given vaults: notes with a date in their frontmatter and
+	// tasks with a due date, sorted by start
+	// Events 列出指定保险库的事件：frontmatter 中带日期的笔记与带截止日期的任务，按开始时间排序
+	Events(ctx context.Context, uid int64, vaults []string, params *dto.NoteCalendarRequest) ([]*dto.NoteCalendarEvent, error)
+}
+
+// noteCalendarService implements NoteCalendarService
+// noteCalendarService 实现 NoteCalendarService 接口
+type noteCalendarService struct {
+	noteRepo     domain.NoteRepository
+	vaultService VaultService
+	logger       *zap.Logger
+}
+
+// NewNoteCalendarService creates a NoteCalendarService instance
+// NewNoteCalendarService 创建 NoteCalendarService 实例
+func NewNoteCalendarService(noteRepo domain.NoteRepository, vaultSvc VaultService, logger *zap.Logger) NoteCalendarService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &noteCalendarService{noteRepo: noteRepo, vaultService: vaultSvc, logger: logger}
+}
+
+// Events implements NoteCalendarService
+func (s *noteCalendarService) Events(ctx context.Context, uid int64, vaults []string, params *dto.NoteCalendarRequest) ([]*dto.NoteCalendarEvent, error) {
+	var events []*dto.NoteCalendarEvent
+	for _, vault := range vaults {
+		vaultID, err := s.vaultService.MustGetID(ctx, uid, vault)
+		if err != nil {
+			return nil, err
+		}
+		notes, err := s.noteRepo.ListByUpdatedTimestamp(ctx, 0, vaultID, uid)
+		if err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		for _, n := range notes {
+			if n.IsDeleted() || n.Content == "" {
+				continue
+			}
+			events = append(events, noteCalendarEvents(vault, n, params.Done)...)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Start.Equal(events[j].Start) {
+			return events[i].Start.Before(events[j].Start)
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+// noteCalendarEvents returns the frontmatter event and the dated tasks of a note
+// noteCalendarEvents 返回笔记的 frontmatter 事件及带日期的任务
+func noteCalendarEvents(vault string, n *domain.Note, includeDone bool) []*dto.NoteCalendarEvent {
+	yamlData, body, _ := util.ParseFrontmatter(n.Content)
+	tags := util.ParseTags(n.Content)
+	idPrefix := vault + "\x00" + n.Path
+
+	var events []*dto.NoteCalendarEvent
+	for _, key := range calendarStartKeys {
+		start, allDay, floating, ok := parseCalendarDate(yamlData[key])
+		if !ok {
+			continue
+		}
+		title, _ := util.MarkdownExcerpt(n.Content, 1)
+		if title == "" {
+			title = strings.TrimSuffix(path.Base(n.Path), path.Ext(n.Path))
+		}
+		event := &dto.NoteCalendarEvent{
+			ID:       util.EncodeHash32(idPrefix),
+			Vault:    vault,
+			Path:     n.Path,
+			Title:    title,
+			Tags:     tags,
+			Start:    start,
+			AllDay:   allDay,
+			Floating: floating,
+			Mtime:    n.Mtime,
+		}
+		for _, endKey := range calendarEndKeys {
+			end, endAllDay, _, ok := parseCalendarDate(yamlData[endKey])
+			if !ok || end.Before(start) {
+				continue
+			}
+			// An all-day end date is inclusive in notes and exclusive in calendars
+			// 全天结束日期在笔记中包含当天，在日历中不包含
+			if allDay && endAllDay {
+				end = end.AddDate(0, 0, 1)
+			}
+			if allDay == endAllDay {
+				event.End = end
+			}
+			break
+		}
+		events = append(events, event)
+		break
+	}
+
+	seen := make(map[string]int)
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		m := calendarTaskRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		done := m[1] == "x" || m[1] == "X"
+		if m[1] == "-" || (done && !includeDone) {
+			continue
+		}
+		due := calendarDueRegex.FindStringSubmatch(m[2])
+		if due == nil {
+			continue
+		}
+		start, allDay, floating, ok := parseCalendarDate(due[1])
+		if !ok {
+			continue
+		}
+		title := strings.TrimSpace(calendarTaskFieldRegex.ReplaceAllString(m[2], ""))
+		if title == "" {
+			continue
+		}
+
+		// Identical tasks in one note are told apart by their occurrence
+		// 同一笔记中相同的任务按出现次序区分
+		key := idPrefix + "\x00" + title
+		seen[key]++
+		if seen[key] > 1 {
+			key += "\x00" + strconv.Itoa(seen[key])
+		}
+		events = append(events, &dto.NoteCalendarEvent{
+			ID:       util.EncodeHash32(key),
+			Vault:    vault,
+			Path:     n.Path,
+			Title:    title,
+			Tags:     tags,
+			IsTask:   true,
+			Done:     done,
+			Start:    start,
+			AllDay:   allDay,
+			Floating: floating,
+			Mtime:    n.Mtime,
+		})
+	}
+	return events
+}
+
+// parseCalendarDate parses a frontmatter or task date, dates without a time of day are all-day
+// parseCalendarDate 解析 frontmatter 或任务中的日期，不含时刻的日期视为全天
+func parseCalendarDate(v interface{}) (t time.Time, allDay, floating, ok bool) {
+	switch val := v.(type) {
+	case time.Time:
+		if val.Hour() == 0 && val.Minute() == 0 && val.Second() == 0 && val.Location() == time.UTC {
+			return val, true, false, true
+		}
+		return val, false, false, true
+	case string:
+		s := strings.TrimSpace(val)
+		for _, l := range calendarDateLayouts {
+			if t, err := time.Parse(l.layout, s); err == nil {
+				return t, l.allDay, !l.allDay && !l.zoned, true
+			}
+		}
+	}
+	return time.Time{}, false, false, false
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestNoteCalendarService(t *testing.T, notes ...*domain.Note) NoteCalendarService {
+	t.Helper()
+	noteRepo := new(domainmocks.MockNoteRepository)
+	noteRepo.On("ListByUpdatedTimestamp", mock.Anything, int64(0), int64(7), int64(1)).Return(notes, nil)
+	return NewNoteCalendarService(noteRepo, &fakeVaultServiceForConflictTest{vaultID: 7}, zap.NewNop())
+}
+
+// TestNoteCalendarService_Frontmatter verifies frontmatter dates become all-day, floating or zoned events
+// and an inclusive all-day end date is made exclusive.
+// TestNoteCalendarService_Frontmatter 验证 frontmatter 日期转换为全天、浮动或带时区的事件，且包含当天的全天结束日期转换为不包含。
+func TestNoteCalendarService_Frontmatter(t *testing.T) {
+	svc := newTestNoteCalendarService(t,
+		&domain.Note{Path: "Daily/2024-03-02.md", Content: "---\ndate: 2024-03-02\n---\nbody #log"},
+		&domain.Note{Path: "Trip.md", Content: "---\nstart: 2024-03-05\nend: 2024-03-07\n---\n# Lisbon trip\n"},
+		&domain.Note{Path: "Meeting.md", Content: "---\ndate: 2024-03-01 15:30\n---\n"},
+		&domain.Note{Path: "Call.md", Content: "---\ndate: 2024-03-01T09:00:00+01:00\n---\n"},
+		&domain.Note{Path: "Plain.md", Content: "---\ncreated: 2024-03-01\n---\nno date"},
+		&domain.Note{Path: "Gone.md", Content: "---\ndate: 2024-03-01\n---\n", Action: domain.NoteActionDelete},
+	)
+
+	events, err := svc.Events(context.Background(), 1, []string{"v"}, &dto.NoteCalendarRequest{})
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+
+	call, meeting, daily, trip := events[0], events[1], events[2], events[3]
+	assert.Equal(t, "Call", call.Title)
+	assert.False(t, call.AllDay || call.Floating)
+	assert.Equal(t, time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC), call.Start.UTC())
+
+	assert.Equal(t, "Meeting", meeting.Title)
+	assert.True(t, meeting.Floating)
+	assert.Equal(t, 15, meeting.Start.Hour())
+
+	assert.Equal(t, "2024-03-02", daily.Title)
+	assert.True(t, daily.AllDay)
+	assert.Equal(t, []string{"log"}, daily.Tags)
+
+	assert.Equal(t, "Lisbon trip", trip.Title)
+	assert.Equal(t, time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), trip.End)
+}
+
+// TestNoteCalendarService_Tasks verifies tasks with a due date become events with the date fields stripped,
+// completed tasks only when requested, and tasks in code blocks are ignored.
+// TestNoteCalendarService_Tasks 验证带截止日期的任务转换为去除日期字段的事件，已完成任务仅在请求时包含，代码块中的任务被忽略。
+func TestNoteCalendarService_Tasks(t *testing.T) {
+	note := &domain.Note{Path: "Work.md", Content: "# Work\n\n" +
+		"- [ ] Send report 📅 2024-03-04 ⏳ 2024-03-03\n" +
+		"- [x] Book room 📅 2024-03-02 ✅ 2024-03-01\n" +
+		"* [ ] Call Bob [due:: 2024-03-05]\n" +
+		"- [ ] Someday\n" +
+		"- [-] Dropped 📅 2024-03-06\n" +
+		"```\n- [ ] Example 📅 2024-03-07\n```\n"}
+
+	events, err := newTestNoteCalendarService(t, note).Events(context.Background(), 1, []string{"v"}, &dto.NoteCalendarRequest{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "Send report", events[0].Title)
+	assert.True(t, events[0].IsTask && events[0].AllDay)
+	assert.Equal(t, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), events[0].Start)
+	assert.Equal(t, "Call Bob", events[1].Title)
+
+	events, err = newTestNoteCalendarService(t, note).Events(context.Background(), 1, []string{"v"}, &dto.NoteCalendarRequest{Done: true})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "Book room", events[0].Title)
+	assert.True(t, events[0].Done)
+	assert.NotEqual(t, events[0].ID, events[1].ID)
+}
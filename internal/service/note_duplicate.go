@@ -0,0 +1,172 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"gorm.io/gorm"
+)
+
+// maxDuplicateNameAttempts numbered "Copy of" names tried before giving up
+// maxDuplicateNameAttempts 放弃前尝试的带编号 "Copy of" 名称数量
+const maxDuplicateNameAttempts = 100
+
+// duplicateCopyPath returns the n-th candidate path for a copy of p in the same folder:
+// "Copy of name.ext", then "Copy of name 2.ext", "Copy of name 3.ext"...
+// duplicateCopyPath 返回 p 在同目录下第 n 个副本候选路径：
+// "Copy of name.ext"，其后依次为 "Copy of name 2.ext"、"Copy of name 3.ext"……
+func duplicateCopyPath(p string, n int) string {
+	dir, base := path.Split(p)
+	ext := path.Ext(base)
+	name := "Copy of " + strings.TrimSuffix(base, ext)
+	if n > 1 {
+		name += " " + strconv.Itoa(n)
+	}
+	return dir + name + ext
+}
+
+// freeDuplicatePath returns the first duplicateCopyPath candidate for which taken reports false
+// freeDuplicatePath 返回第一个 taken 判定为未占用的 duplicateCopyPath 候选路径
+func freeDuplicatePath(p string, taken func(candidate string) bool) (string, bool) {
+	for n := 1; n <= maxDuplicateNameAttempts; n++ {
+		candidate := duplicateCopyPath(p, n)
+		if !taken(candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// rewriteDuplicatedFileLinks points attachment embeds of a duplicated note at the copied attachments.
+// fileLinks maps the raw reference found in the content to the vault path of the copy; since copies
+// live next to their originals, only the file name of each reference is replaced, which keeps
+// relative, absolute and shortest-path forms intact.
+// rewriteDuplicatedFileLinks 将复制笔记中的附件嵌入指向复制得到的附件。
+// fileLinks 为内容中的原始引用到副本仓库路径的映射；由于副本与原文件位于同一目录，
+// 只替换每个引用中的文件名，从而保留相对路径、绝对路径与最短路径等写法。
+func rewriteDuplicatedFileLinks(content string, fileLinks map[string]string) string {
+	if len(fileLinks) == 0 {
+		return content
+	}
+	replaceRef := func(ref string, escapeSpaces bool) (string, bool) {
+		newPath, ok := fileLinks[strings.TrimSpace(ref)]
+		if !ok {
+			return ref, false
+		}
+		ref = strings.TrimSpace(ref)
+		name := path.Base(newPath)
+		if escapeSpaces {
+			name = strings.ReplaceAll(name, " ", "%20")
+		}
+		if idx := strings.LastIndex(ref, "/"); idx != -1 {
+			return ref[:idx+1] + name, true
+		}
+		return name, true
+	}
+
+	content = attachmentRegex.ReplaceAllStringFunc(content, func(match string) string {
+		inner := attachmentRegex.FindStringSubmatch(match)[1]
+		ref := extractObsidianEmbedPath(inner)
+		newRef, ok := replaceRef(ref, false)
+		if !ok {
+			return match
+		}
+		rest := ""
+		if idx := strings.IndexAny(inner, "|#"); idx != -1 {
+			rest = inner[idx:]
+		}
+		return "![[" + newRef + rest + "]]"
+	})
+
+	content = markdownImageRegex.ReplaceAllStringFunc(content, func(match string) string {
+		submatches := markdownImageRegex.FindStringSubmatch(match)
+		target, start, end := parseMarkdownLinkTarget(submatches[2])
+		if target == "" || start < 0 || end < 0 {
+			return match
+		}
+		rawTarget := submatches[2][start:end]
+		angled := strings.HasPrefix(rawTarget, "<") && strings.HasSuffix(rawTarget, ">")
+		newRef, ok := replaceRef(target, !angled)
+		if !ok {
+			return match
+		}
+		if angled {
+			newRef = "<" + newRef + ">"
+		}
+		return "![" + submatches[1] + "](" + submatches[2][:start] + newRef + submatches[2][end:] + ")"
+	})
+
+	return htmlImageRegex.ReplaceAllStringFunc(content, func(match string) string {
+		submatches := htmlImageRegex.FindStringSubmatch(match)
+		newRef, ok := replaceRef(submatches[3], true)
+		if !ok {
+			return match
+		}
+		return "<img" + submatches[1] + "src=" + submatches[2] + newRef + submatches[2] + submatches[4] + ">"
+	})
+}
+
+// Duplicate implements NoteService
+func (s *noteService) Duplicate(ctx context.Context, uid int64, params *dto.NoteDuplicateRequest, fileLinks map[string]string) (*dto.NoteDTO, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.PathHash == "" {
+		params.PathHash = util.EncodeHash32(params.Path)
+	}
+
+	note, err := s.noteRepo.GetByPathHash(ctx, params.PathHash, vaultID, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, code.ErrorNoteNotFound
+		}
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if note.IsDeleted() {
+		return nil, code.ErrorNoteNotFound
+	}
+
+	taken := func(candidate string) bool {
+		existing, err := s.noteRepo.GetByPathHash(ctx, util.EncodeHash32(candidate), vaultID, uid)
+		return err == nil && existing != nil && !existing.IsDeleted()
+	}
+
+	newPath := strings.Trim(params.NewPath, "/")
+	if newPath == "" {
+		var ok bool
+		if newPath, ok = freeDuplicatePath(note.Path, taken); !ok {
+			return nil, code.ErrorNoteExist
+		}
+	} else {
+		if !strings.HasSuffix(strings.ToLower(newPath), ".md") {
+			newPath += ".md"
+		}
+		if taken(newPath) {
+			return nil, code.ErrorNoteExist
+		}
+	}
+
+	content := rewriteDuplicatedFileLinks(note.Content, fileLinks)
+	now := timex.Now().UnixMilli()
+	_, result, err := s.ModifyOrCreate(ctx, uid, &dto.NoteModifyOrCreateRequest{
+		Vault:       params.Vault,
+		Path:        newPath,
+		PathHash:    util.EncodeHash32(newPath),
+		Content:     content,
+		ContentHash: util.EncodeHash32(content),
+		Ctime:       now,
+		Mtime:       now,
+	}, false)
+	return result, err
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateCopyPath(t *testing.T) {
+	assert.Equal(t, "Notes/Copy of Plan.md", duplicateCopyPath("Notes/Plan.md", 1))
+	assert.Equal(t, "Notes/Copy of Plan 3.md", duplicateCopyPath("Notes/Plan.md", 3))
+	assert.Equal(t, "Copy of image.png", duplicateCopyPath("image.png", 1))
+
+	taken := map[string]bool{"Copy of a.md": true, "Copy of a 2.md": true}
+	got, ok := freeDuplicatePath("a.md", func(p string) bool { return taken[p] })
+	assert.True(t, ok)
+	assert.Equal(t, "Copy of a 3.md", got)
+}
+
+func TestRewriteDuplicatedFileLinks(t *testing.T) {
+	links := map[string]string{
+		"img.png":             "assets/Copy of img.png",
+		"assets/img.png":      "assets/Copy of img.png",
+		"../assets/photo.jpg": "assets/Copy of photo.jpg",
+	}
+	content := "![[img.png|200]]\n![[assets/img.png#page=1]]\n![alt](../assets/photo.jpg \"t\")\n![x](<img.png>)\n<img src=\"img.png\">\n![[other.png]]"
+	want := "![[Copy of img.png|200]]\n![[assets/Copy of img.png#page=1]]\n![alt](../assets/Copy%20of%20photo.jpg \"t\")\n![x](<Copy of img.png>)\n<img src=\"Copy%20of%20img.png\">\n![[other.png]]"
+
+	assert.Equal(t, want, rewriteDuplicatedFileLinks(content, links))
+	assert.Equal(t, content, rewriteDuplicatedFileLinks(content, nil))
+}
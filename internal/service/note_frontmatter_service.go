@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+// NoteFrontmatterService defines the frontmatter query service interface; the property index is updated
+// when notes are written and caught up with the notes changed since the last query
+// NoteFrontmatterService 定义 frontmatter 查询服务接口；属性索引在写入笔记时更新，并在每次查询前补齐上次查询后变更的笔记
+type NoteFrontmatterService interface {
+	// Query lists the notes of a vault whose frontmatter matches all predicates, newest first
+	// Query 列出保险库中 frontmatter 满足全部条件的笔记，按新到旧排序
+	Query(ctx context.Context, uid int64, params *dto.NoteFrontmatterQueryRequest, pager *app.Pager) ([]*dto.NoteFrontmatterItem, int, error)
+
+	// Properties lists the frontmatter properties used in a vault with their type, by name
+	// Properties 按名称列出保险库中使用的 frontmatter 属性及其类型
+	Properties(ctx context.Context, uid int64, params *dto.NotePropertiesRequest) ([]*dto.NotePropertyKeyDTO, error)
+}
+
+// noteFrontmatterService implements NoteFrontmatterService
+// noteFrontmatterService 实现 NoteFrontmatterService 接口
+type noteFrontmatterService struct {
+	repo         domain.NotePropertyRepository
+	noteRepo     domain.NoteRepository
+	vaultService VaultService
+	logger       *zap.Logger
+}
+
+// NewNoteFrontmatterService creates a NoteFrontmatterService instance
+// NewNoteFrontmatterService 创建 NoteFrontmatterService 实例
+func NewNoteFrontmatterService(repo domain.NotePropertyRepository, noteRepo domain.NoteRepository, vaultSvc VaultService, logger *zap.Logger) NoteFrontmatterService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &noteFrontmatterService{
+		repo:         repo,
+		noteRepo:     noteRepo,
+		vaultService: vaultSvc,
+		logger:       logger,
+	}
+}
+
+// Query implements NoteFrontmatterService
+// Query 实现 NoteFrontmatterService
+func (s *noteFrontmatterService) Query(ctx context.Context, uid int64, params *dto.NoteFrontmatterQueryRequest, pager *app.Pager) ([]*dto.NoteFrontmatterItem, int, error) {
+	predicates := make([]*domain.NotePropertyPredicate, 0, len(params.Where))
+	for _, w := range params.Where {
+		p, err := frontmatterPredicate(w)
+		if err != nil {
+			return nil, 0, err
+		}
+		predicates = append(predicates, p)
+	}
+
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.refresh(ctx, uid, vaultID); err != nil {
+		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	ids, total, err := s.repo.Query(ctx, vaultID, predicates, app.GetPageOffset(pager.Page, pager.PageSize), pager.PageSize, uid)
+	if err != nil {
+		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	notes, err := s.noteRepo.ListByIDs(ctx, ids, uid)
+	if err != nil {
+		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	byID := make(map[int64]*domain.Note, len(notes))
+	for _, n := range notes {
+		byID[n.ID] = n
+	}
+
+	items := make([]*dto.NoteFrontmatterItem, 0, len(ids))
+	for _, id := range ids {
+		n, ok := byID[id]
+		if !ok {
+			continue
+		}
+		yamlData, _, _ := util.ParseFrontmatter(n.Content)
+		items = append(items, &dto.NoteFrontmatterItem{
+			ID:               n.ID,
+			Path:             n.Path,
+			PathHash:         n.PathHash,
+			Version:          n.Version,
+			Size:             n.Size,
+			Ctime:            n.Ctime,
+			Mtime:            n.Mtime,
+			UpdatedTimestamp: n.UpdatedTimestamp,
+			Frontmatter:      yamlData,
+		})
+	}
+	return items, int(total), nil
+}
+
+// Properties implements NoteFrontmatterService
+// Properties 实现 NoteFrontmatterService
+func (s *noteFrontmatterService) Properties(ctx context.Context, uid int64, params *dto.NotePropertiesRequest) ([]*dto.NotePropertyKeyDTO, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refresh(ctx, uid, vaultID); err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	keys, err := s.repo.ListKeys(ctx, vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	result := make([]*dto.NotePropertyKeyDTO, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, &dto.NotePropertyKeyDTO{Key: k.Key, Type: string(k.Type), IsList: k.IsList, NoteCount: k.NoteCount})
+	}
+	return result, nil
+}
+
+// refresh indexes the frontmatter of the vault's notes changed since the last refresh;
+// deleted notes and notes without frontmatter leave the index
+// refresh 索引保险库中自上次刷新后变更的笔记的 frontmatter；已删除及没有 frontmatter 的笔记移出索引
+func (s *noteFrontmatterService) refresh(ctx context.Context, uid, vaultID int64) error {
+	indexedUntil, err := s.repo.IndexedUntil(ctx, vaultID, uid)
+	if err != nil {
+		return err
+	}
+	notes, err := s.noteRepo.ListByUpdatedTimestamp(ctx, indexedUntil, vaultID, uid)
+	if err != nil || len(notes) == 0 {
+		return err
+	}
+
+	noteIDs := make([]int64, 0, len(notes))
+	var props []*domain.NoteProperty
+	for _, n := range notes {
+		noteIDs = append(noteIDs, n.ID)
+		indexedUntil = max(indexedUntil, n.UpdatedTimestamp)
+		if n.IsDeleted() || n.Content == "" {
+			continue
+		}
+		props = append(props, notePropertiesOf(n.ID, n.Content)...)
+	}
+
+	s.logger.Debug("NoteFrontmatterService.refresh",
+		zap.Int64("uid", uid),
+		zap.Int64("vaultId", vaultID),
+		zap.Int("notes", len(noteIDs)),
+		zap.Int("properties", len(props)))
+	return s.repo.Reindex(ctx, vaultID, noteIDs, props, indexedUntil, uid)
+}
+
+// frontmatterPredicate validates a query condition and converts its operand
+// frontmatterPredicate 校验查询条件并转换其操作数
+func frontmatterPredicate(w *dto.NoteFrontmatterPredicate) (*domain.NotePropertyPredicate, error) {
+	p := &domain.NotePropertyPredicate{Key: strings.TrimSpace(w.Key), Op: domain.NotePropertyOp(w.Op)}
+	if p.Key == "" {
+		return nil, code.ErrorInvalidParams.WithDetails("key is required")
+	}
+	if p.Op == domain.NotePropertyOpExists || p.Op == domain.NotePropertyOpNotExists {
+		return p, nil
+	}
+
+	switch val := w.Value.(type) {
+	case nil:
+		return nil, code.ErrorInvalidParams.WithDetails("value is required for " + w.Op)
+	case float64:
+		v := notePropertyValue(val)
+		p.Text, p.Number = v.Text, v.Number
+	case string:
+		v := notePropertyValue(val)
+		p.Text, p.Time = v.Text, v.Time
+		if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil && p.Time == nil {
+			p.Number = &f
+		}
+	case bool:
+		p.Text = strconv.FormatBool(val)
+	default:
+		return nil, code.ErrorInvalidParams.WithDetails("value of " + p.Key + " must be a string, number or boolean")
+	}
+
+	switch p.Op {
+	case domain.NotePropertyOpGt, domain.NotePropertyOpGte, domain.NotePropertyOpLt, domain.NotePropertyOpLte:
+		if p.Number == nil && p.Time == nil {
+			return nil, code.ErrorInvalidParams.WithDetails(w.Op + " on " + p.Key + " needs a number or a date")
+		}
+	}
+	return p, nil
+}
+
+// Ensure noteFrontmatterService implements NoteFrontmatterService
+// 确保 noteFrontmatterService 实现了 NoteFrontmatterService 接口
+var _ NoteFrontmatterService = (*noteFrontmatterService)(nil)
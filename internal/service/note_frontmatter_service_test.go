@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeNotePropertyRepo in-memory domain.NotePropertyRepository recording the last write
+type fakeNotePropertyRepo struct {
+	indexedUntil int64
+	noteIDs      []int64
+	fields       []*domain.NoteProperty
+	predicates   []*domain.NotePropertyPredicate
+}
+
+func (r *fakeNotePropertyRepo) ReplaceByNoteID(ctx context.Context, vaultID, noteID int64, props []*domain.NoteProperty, uid int64) error {
+	r.noteIDs, r.fields = []int64{noteID}, props
+	return nil
+}
+
+func (r *fakeNotePropertyRepo) ListKeys(ctx context.Context, vaultID, uid int64) ([]*domain.NotePropertyKey, error) {
+	return nil, nil
+}
+
+func (r *fakeNotePropertyRepo) IndexedUntil(ctx context.Context, vaultID, uid int64) (int64, error) {
+	return r.indexedUntil, nil
+}
+
+func (r *fakeNotePropertyRepo) Reindex(ctx context.Context, vaultID int64, noteIDs []int64, fields []*domain.NoteProperty, indexedUntil, uid int64) error {
+	r.noteIDs, r.fields, r.indexedUntil = noteIDs, fields, indexedUntil
+	return nil
+}
+
+func (r *fakeNotePropertyRepo) Query(ctx context.Context, vaultID int64, predicates []*domain.NotePropertyPredicate, offset, limit int, uid int64) ([]int64, int64, error) {
+	r.predicates = predicates
+	return []int64{1}, 1, nil
+}
+
+// TestNoteFrontmatterService_Query verifies the index catches up with changed notes before a query,
+// lists become one field per element, deleted notes leave the index and operands are typed.
+// TestNoteFrontmatterService_Query 验证查询前索引会补齐变更的笔记、列表按元素展开为字段、
+// 已删除笔记移出索引且操作数带有类型。
+func TestNoteFrontmatterService_Query(t *testing.T) {
+	vaultRepo := newVaultMockRepo()
+	vaultRepo.On("GetByName", mock.Anything, "Work", int64(1)).Return(newVault(1, "Work"), nil)
+	note := &domain.Note{ID: 1, Path: "a.md", Content: "---\nstatus: done\ntags: [a, b]\nrating: 4\ndue: 2026-03-01\n---\nbody", UpdatedTimestamp: 300}
+	noteRepo := new(domainmocks.MockNoteRepository)
+	noteRepo.On("ListByUpdatedTimestamp", mock.Anything, int64(100), int64(1), int64(1)).Return([]*domain.Note{
+		note,
+		{ID: 2, Path: "b.md", Action: domain.NoteActionDelete, UpdatedTimestamp: 200},
+	}, nil)
+	noteRepo.On("ListByIDs", mock.Anything, []int64{1}, int64(1)).Return([]*domain.Note{note}, nil)
+	repo := &fakeNotePropertyRepo{indexedUntil: 100}
+	svc := NewNoteFrontmatterService(repo, noteRepo, newVaultSvc(vaultRepo), zap.NewNop())
+
+	items, total, err := svc.Query(context.Background(), 1, &dto.NoteFrontmatterQueryRequest{
+		Vault: "Work",
+		Where: []*dto.NoteFrontmatterPredicate{
+			{Key: "rating", Op: "gte", Value: "3"},
+			{Key: "due", Op: "lt", Value: "2026-04-01"},
+			{Key: "tags", Op: "contains", Value: "a"},
+		},
+	}, &app.Pager{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, items, 1)
+	assert.Equal(t, "done", items[0].Frontmatter["status"])
+
+	assert.Equal(t, int64(300), repo.indexedUntil)
+	assert.Equal(t, []int64{1, 2}, repo.noteIDs)
+	var keys []string
+	var due int64
+	for _, f := range repo.fields {
+		assert.Equal(t, int64(1), f.NoteID)
+		keys = append(keys, f.Key)
+		switch f.Key {
+		case "rating":
+			require.NotNil(t, f.Number)
+			assert.Equal(t, float64(4), *f.Number)
+		case "due":
+			require.NotNil(t, f.Time)
+			assert.Equal(t, "2026-03-01", f.Text)
+			due = *f.Time
+		case "tags":
+			assert.True(t, f.InList)
+		}
+	}
+	sort.Strings(keys)
+	assert.Equal(t, []string{"due", "rating", "status", "tags", "tags"}, keys)
+
+	require.Len(t, repo.predicates, 3)
+	require.NotNil(t, repo.predicates[0].Number)
+	assert.Nil(t, repo.predicates[0].Time)
+	require.NotNil(t, repo.predicates[1].Time)
+	assert.Less(t, due, *repo.predicates[1].Time)
+
+	_, _, err = svc.Query(context.Background(), 1, &dto.NoteFrontmatterQueryRequest{
+		Vault: "Work",
+		Where: []*dto.NoteFrontmatterPredicate{{Key: "status", Op: "gt", Value: "done"}},
+	}, &app.Pager{Page: 1, PageSize: 10})
+	assert.ErrorIs(t, err, code.ErrorInvalidParams)
+}
@@ -5,6 +5,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -51,8 +52,28 @@ type NoteHistoryService interface {
 	// CleanupByTime cleans up history records by cutoff time, keeping recent N versions per note
 	// CleanupByTime 按截止时间清理历史记录，保留每个笔记最近 N 个版本
 	CleanupByTime(ctx context.Context, cutoffTime int64, keepVersions int) error
+
+	// Compact thins the history of all users: versions older than 30 days are kept one per day,
+	// older than 6 months one per week and older than maxAge (0 for no limit) not at all; the recent
+	// keepVersions versions of each note are never touched
+	// Compact 压缩所有用户的历史记录：超过 30 天的版本每天保留一个，超过 6 个月的每周保留一个，
+	// 超过 maxAge（0 表示不限）的全部删除；每个笔记最近 keepVersions 个版本始终保留
+	Compact(ctx context.Context, maxAge time.Duration, keepVersions int) error
+
+	// SetSecretScan sets the secret scanner whose strict mode also guards restored content
+	// SetSecretScan 设置敏感凭据扫描器，其严格模式同样约束恢复的内容
+	SetSecretScan(secretScan SecretScanService)
 }
 
+const (
+	// historyDailyAfter versions older than this are thinned to the last one of each day
+	// historyDailyAfter 早于此时长的版本每天仅保留最后一个
+	historyDailyAfter = 30 * 24 * time.Hour
+	// historyWeeklyAfter versions older than this are thinned to the last one of each week
+	// historyWeeklyAfter 早于此时长的版本每周仅保留最后一个
+	historyWeeklyAfter = 180 * 24 * time.Hour
+)
+
 // noteHistoryService implementation of NoteHistoryService interface
 // noteHistoryService 实现 NoteHistoryService 接口
 type noteHistoryService struct {
@@ -64,6 +85,7 @@ type noteHistoryService struct {
 	noteService    NoteService                  // Note service // 笔记服务
 	backupService  BackupService                // Backup service // 备份服务
 	gitSyncService GitSyncService               // Git sync service // Git 同步服务
+	secretScan     SecretScanService            // Strict secret scanning of restored content, may be nil // 恢复内容的严格凭据扫描，可为 nil
 	sf             *singleflight.Group          // Singleflight group // 并发请求合并组
 	logger         *zap.Logger                  // Logger // 日志对象
 	config         *AppServiceConfig            // Service configuration // 服务配置
@@ -303,6 +325,12 @@ func (s *noteHistoryService) Migrate(ctx context.Context, oldNoteID, newNoteID i
 	return s.historyRepo.Migrate(ctx, oldNoteID, newNoteID, uid)
 }
 
+// SetSecretScan sets the secret scanner used to guard restored content
+// SetSecretScan 设置用于约束恢复内容的敏感凭据扫描器
+func (s *noteHistoryService) SetSecretScan(secretScan SecretScanService) {
+	s.secretScan = secretScan
+}
+
 // RestoreFromHistory restores note content from a history version
 // Restores to the content after the modification of this history version
 // RestoreFromHistory 从历史版本恢复笔记内容
@@ -373,6 +401,17 @@ func (s *noteHistoryService) RestoreFromHistory(ctx context.Context, uid int64,
 		zap.Int("afterContentLen", len(restoredContent)),
 	)
 
+	if err := s.folderService.CheckWritable(ctx, uid, history.VaultID, note.Path); err != nil {
+		return nil, err
+	}
+	// A restore writes the note directly, so it is held to strict secret scanning like ModifyOrCreate
+	// 恢复直接写入笔记，因此与 ModifyOrCreate 一样受严格凭据扫描约束
+	if s.secretScan != nil {
+		if err := s.secretScan.Guard(ctx, uid, note.Path, restoredContent); err != nil {
+			return nil, err
+		}
+	}
+
 	// 5. Update note with restored content and set modification time
 	// 5. 使用恢复的内容更新笔记, 并设置修改时间
 	note.Content = restoredContent
@@ -389,9 +428,7 @@ func (s *noteHistoryService) RestoreFromHistory(ctx context.Context, uid int64,
 	}
 
 	vaultID := history.VaultID
-	go s.folderService.SyncResourceFID(context.Background(), uid, vaultID, []int64{updated.ID}, nil)
-	go s.noteService.CountSizeSum(context.Background(), vaultID, uid)
-	go s.noteService.UpdateNoteLinks(context.Background(), updated.ID, updated.Content, vaultID, uid)
+	s.noteService.IndexNote(uid, vaultID, updated.ID, updated.Path, updated.Content)
 
 	NoteHistoryDelayPush(updated.ID, uid)
 
@@ -476,6 +513,98 @@ func (s *noteHistoryService) CleanupByTime(ctx context.Context, cutoffTime int64
 	return nil
 }
 
+// Compact implements NoteHistoryService
+// Compact 实现 NoteHistoryService
+func (s *noteHistoryService) Compact(ctx context.Context, maxAge time.Duration, keepVersions int) error {
+	uids, err := s.userRepo.GetAllUIDs(ctx)
+	if err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	// Only notes with versions old enough to be thinned are looked at
+	// 仅处理存在需压缩的旧版本的笔记
+	now := time.Now()
+	scanCutoff := now.Add(-historyDailyAfter).UnixMilli()
+	if maxAge > 0 && maxAge < historyDailyAfter {
+		scanCutoff = now.Add(-maxAge).UnixMilli()
+	}
+
+	var totalDeleted int
+	for i, uid := range uids {
+		// 增加错峰延迟，避免瞬间触发大量写事务
+		if i > 0 {
+			time.Sleep(500 * time.Millisecond)
+		}
+		noteIDs, err := s.historyRepo.GetNoteIDsWithOldHistory(ctx, scanCutoff, uid)
+		if err != nil {
+			s.logger.Error("failed to get note IDs with old history",
+				zap.Int64("uid", uid),
+				zap.Error(err))
+			continue
+		}
+
+		for _, noteID := range noteIDs {
+			versions, err := s.historyRepo.ListVersions(ctx, noteID, uid)
+			if err != nil {
+				s.logger.Error("failed to list history versions",
+					zap.Int64("uid", uid),
+					zap.Int64("noteID", noteID),
+					zap.Error(err))
+				continue
+			}
+			ids := historyCompactionVictims(versions, now, maxAge, keepVersions)
+			if err := s.historyRepo.DeleteByIDs(ctx, ids, uid); err != nil {
+				s.logger.Error("failed to compact history",
+					zap.Int64("uid", uid),
+					zap.Int64("noteID", noteID),
+					zap.Error(err))
+				continue
+			}
+			totalDeleted += len(ids)
+		}
+	}
+
+	s.logger.Info("note history compaction completed",
+		zap.Duration("maxAge", maxAge),
+		zap.Int("keepVersions", keepVersions),
+		zap.Int("versionsDeleted", totalDeleted))
+
+	return nil
+}
+
+// historyCompactionVictims returns the IDs of the versions to delete; versions are sorted newest first
+// so the version kept in a day or week is the last one of it
+// historyCompactionVictims 返回需要删除的版本 ID；versions 按新到旧排序，因此每天或每周保留的是其最后一个版本
+func historyCompactionVictims(versions []*domain.NoteHistory, now time.Time, maxAge time.Duration, keepVersions int) []int64 {
+	var ids []int64
+	kept := make(map[string]bool)
+	for i, h := range versions {
+		if i < keepVersions {
+			continue
+		}
+		age := now.Sub(h.CreatedAt)
+		var bucket string
+		switch {
+		case maxAge > 0 && age > maxAge:
+			ids = append(ids, h.ID)
+			continue
+		case age > historyWeeklyAfter:
+			year, week := h.CreatedAt.ISOWeek()
+			bucket = fmt.Sprintf("week %d-%d", year, week)
+		case age > historyDailyAfter:
+			bucket = "day " + h.CreatedAt.Format(time.DateOnly)
+		default:
+			continue
+		}
+		if kept[bucket] {
+			ids = append(ids, h.ID)
+		} else {
+			kept[bucket] = true
+		}
+	}
+	return ids
+}
+
 // cleanupExcessVersions cleans up history records exceeding version count limit
 // Delete oldest version when note history versions exceed HistoryKeepVersions
 // cleanupExcessVersions 清理超过版本数量限制的历史记录
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHistoryCompactionVictims verifies old versions are thinned to the last one per day after 30 days
+// and per week after 6 months, versions beyond the maximum age are dropped and the recent ones kept.
+// TestHistoryCompactionVictims 验证旧版本在 30 天后每天、6 个月后每周只保留最后一个，超过最长保留时间的
+// 版本被删除，且最近的版本始终保留。
+func TestHistoryCompactionVictims(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	at := func(d time.Duration) time.Time { return now.Add(-d) }
+
+	// Newest first, as returned by ListVersions
+	// 按新到旧排序，与 ListVersions 的返回一致
+	versions := []*domain.NoteHistory{
+		{ID: 10, Version: 10, CreatedAt: at(40 * day)},
+		{ID: 9, Version: 9, CreatedAt: at(5 * day)},
+		{ID: 8, Version: 8, CreatedAt: at(5*day + time.Hour)},
+		{ID: 7, Version: 7, CreatedAt: at(40 * day)},
+		{ID: 6, Version: 6, CreatedAt: at(40*day + time.Hour)},
+		{ID: 5, Version: 5, CreatedAt: at(41 * day)},
+		{ID: 4, Version: 4, CreatedAt: at(200 * day)},
+		{ID: 3, Version: 3, CreatedAt: at(201 * day)},
+		{ID: 2, Version: 2, CreatedAt: at(300 * day)},
+		{ID: 1, Version: 1, CreatedAt: at(400 * day)},
+	}
+
+	// Version 10 is protected by keepVersions, the recent ones are untouched
+	// 版本 10 受 keepVersions 保护，近期版本不受影响
+	assert.Equal(t, []int64{6, 3}, historyCompactionVictims(versions, now, 0, 1))
+	assert.Equal(t, []int64{6, 3, 1}, historyCompactionVictims(versions, now, 365*day, 1))
+	assert.Empty(t, historyCompactionVictims(versions, now, 0, len(versions)))
+}
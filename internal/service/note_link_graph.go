@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"gorm.io/gorm"
+)
+
+// vaultGraphMaxNodes largest number of notes returned by GetGraph
+// vaultGraphMaxNodes GetGraph 返回的最大笔记数量
+const vaultGraphMaxNodes = 5000
+
+// noteGraphIndex resolves stored link targets to the notes of a vault
+// noteGraphIndex 将存储的链接目标解析为仓库中的笔记
+type noteGraphIndex struct {
+	byPath map[string]*domain.Note
+	byName map[string]*domain.Note
+}
+
+func newNoteGraphIndex(notes []*domain.Note) *noteGraphIndex {
+	idx := &noteGraphIndex{
+		byPath: make(map[string]*domain.Note, len(notes)),
+		byName: make(map[string]*domain.Note, len(notes)),
+	}
+	for _, n := range notes {
+		idx.byPath[n.Path] = n
+		// A bare name resolves to the note closest to the vault root, like in Obsidian
+		// 与 Obsidian 一致，仅有名称时解析为最靠近仓库根目录的笔记
+		name := strings.ToLower(path.Base(n.Path))
+		if prev, ok := idx.byName[name]; !ok || strings.Count(n.Path, "/") < strings.Count(prev.Path, "/") ||
+			(strings.Count(n.Path, "/") == strings.Count(prev.Path, "/") && n.Path < prev.Path) {
+			idx.byName[name] = n
+		}
+	}
+	return idx
+}
+
+// resolve mirrors ResolveLinks: next to the source note, then from the vault root, then by name
+// resolve 与 ResolveLinks 一致：先在源笔记所在目录查找，再从仓库根目录查找，最后按名称查找
+func (idx *noteGraphIndex) resolve(sourcePath, target string) *domain.Note {
+	if i := strings.IndexByte(target, '#'); i >= 0 {
+		target = target[:i]
+	}
+	name := strings.TrimPrefix(strings.TrimSpace(strings.ReplaceAll(target, "\\", "/")), "/")
+	if name == "" {
+		return nil
+	}
+	if !strings.HasSuffix(strings.ToLower(name), ".md") {
+		name += ".md"
+	}
+	if dir := path.Dir(sourcePath); dir != "." && dir != "/" {
+		if n, ok := idx.byPath[path.Join(dir, name)]; ok {
+			return n
+		}
+	}
+	if n, ok := idx.byPath[name]; ok {
+		return n
+	}
+	if !strings.Contains(name, "/") {
+		return idx.byName[strings.ToLower(name)]
+	}
+	return nil
+}
+
+// GetGraph returns the notes of a vault and the links between them.
+// Folder and tag filters drop notes, and the links touching them; with a center note only the notes
+// within depth link hops of it, in either direction, are returned, the center itself always included.
+// GetGraph 返回仓库中的笔记及其之间的链接。
+// 目录与标签过滤会移除笔记及与其相关的链接；指定中心笔记时仅返回在任意方向上距其不超过 depth 跳的笔记，中心笔记始终包含在内。
+func (s *noteLinkService) GetGraph(ctx context.Context, uid int64, params *dto.VaultGraphRequest) (*dto.VaultGraphDTO, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := s.noteRepo.ListByUpdatedTimestamp(ctx, 0, vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	notes := make([]*domain.Note, 0, len(all))
+	for _, n := range all {
+		if !n.IsDeleted() {
+			notes = append(notes, n)
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool { return notes[i].Path < notes[j].Path })
+
+	links, err := s.noteLinkRepo.ListByVaultID(ctx, vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	// Resolve links to note IDs, dropping unresolved targets, self links and duplicates
+	// 将链接解析为笔记 ID，忽略无法解析的目标、自链接与重复链接
+	byID := make(map[int64]*domain.Note, len(notes))
+	for _, n := range notes {
+		byID[n.ID] = n
+	}
+	idx := newNoteGraphIndex(notes)
+	type edgeKey struct {
+		source, target int64
+		embed          bool
+	}
+	seen := make(map[edgeKey]bool)
+	var edges []*dto.VaultGraphEdge
+	for _, link := range links {
+		source, ok := byID[link.SourceNoteID]
+		if !ok {
+			continue
+		}
+		target := idx.resolve(source.Path, link.TargetPath)
+		if target == nil || target.ID == source.ID {
+			continue
+		}
+		key := edgeKey{source.ID, target.ID, link.IsEmbed}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		edges = append(edges, &dto.VaultGraphEdge{Source: source.ID, Target: target.ID, IsEmbed: link.IsEmbed})
+	}
+
+	// Neighborhood mode: breadth-first walk from the center note over links in both directions
+	// 邻域模式：从中心笔记出发沿双向链接进行广度优先遍历
+	var centerID int64
+	var within map[int64]bool
+	if params.Path != "" || params.PathHash != "" {
+		pathHash := params.PathHash
+		if pathHash == "" {
+			pathHash = util.EncodeHash32(params.Path)
+		}
+		center, err := s.noteRepo.GetByPathHash(ctx, pathHash, vaultID, uid)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, code.ErrorNoteNotFound
+			}
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		if center.IsDeleted() {
+			return nil, code.ErrorNoteNotFound
+		}
+		depth := params.Depth
+		if depth == 0 {
+			depth = 1
+		}
+
+		adjacent := make(map[int64][]int64)
+		for _, e := range edges {
+			adjacent[e.Source] = append(adjacent[e.Source], e.Target)
+			adjacent[e.Target] = append(adjacent[e.Target], e.Source)
+		}
+		centerID = center.ID
+		within = map[int64]bool{centerID: true}
+		frontier := []int64{centerID}
+		for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+			var next []int64
+			for _, id := range frontier {
+				for _, neighbor := range adjacent[id] {
+					if !within[neighbor] {
+						within[neighbor] = true
+						next = append(next, neighbor)
+					}
+				}
+			}
+			frontier = next
+		}
+	}
+
+	folder := strings.Trim(params.Folder, "/")
+	result := &dto.VaultGraphDTO{Nodes: []*dto.VaultGraphNode{}, Edges: []*dto.VaultGraphEdge{}}
+	included := make(map[int64]bool)
+	for _, n := range notes {
+		if within != nil && !within[n.ID] {
+			continue
+		}
+		if n.ID != centerID {
+			if folder != "" && !strings.HasPrefix(n.Path, folder+"/") {
+				continue
+			}
+			if params.Tag != "" && !util.HasTag(n.Content, params.Tag) {
+				continue
+			}
+		}
+		if len(result.Nodes) >= vaultGraphMaxNodes {
+			result.Truncated = true
+			break
+		}
+		tags := util.ParseTags(n.Content)
+		if tags == nil {
+			tags = []string{}
+		}
+		result.Nodes = append(result.Nodes, &dto.VaultGraphNode{
+			ID:   n.ID,
+			Path: n.Path,
+			Name: strings.TrimSuffix(path.Base(n.Path), ".md"),
+			Tags: tags,
+		})
+		included[n.ID] = true
+	}
+	for _, e := range edges {
+		if included[e.Source] && included[e.Target] {
+			result.Edges = append(result.Edges, e)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newTestGraphService(t *testing.T) NoteLinkService {
+	t.Helper()
+	notes := []*domain.Note{
+		{ID: 1, Path: "Home.md", Content: "[[Projects/Plan]] #home"},
+		{ID: 2, Path: "Projects/Plan.md", Content: "---\ntags: [project]\n---\n[[Task]] ![[Home]] [[Missing]]"},
+		{ID: 3, Path: "Projects/Task.md", Content: "#project/sub"},
+		{ID: 4, Path: "Archive/Old.md"},
+		{ID: 5, Path: "Gone.md", Action: domain.NoteActionDelete},
+	}
+	noteRepo := new(domainmocks.MockNoteRepository)
+	noteRepo.On("ListByUpdatedTimestamp", mock.Anything, int64(0), int64(7), int64(1)).Return(notes, nil)
+	noteRepo.On("GetByPathHash", mock.Anything, util.EncodeHash32("Projects/Task.md"), int64(7), int64(1)).Return(notes[2], nil)
+	noteRepo.On("GetByPathHash", mock.Anything, mock.Anything, int64(7), int64(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	linkRepo := new(domainmocks.MockNoteLinkRepository)
+	linkRepo.On("ListByVaultID", mock.Anything, int64(7), int64(1)).Return([]*domain.NoteLink{
+		{SourceNoteID: 1, TargetPath: "Projects/Plan"},
+		{SourceNoteID: 1, TargetPath: "Projects/Plan#Goals"},
+		{SourceNoteID: 2, TargetPath: "Task"},
+		{SourceNoteID: 2, TargetPath: "Home", IsEmbed: true},
+		{SourceNoteID: 2, TargetPath: "Missing"},
+		{SourceNoteID: 5, TargetPath: "Home"},
+	}, nil)
+
+	return NewNoteLinkService(linkRepo, noteRepo, &fakeVaultServiceForConflictTest{vaultID: 7})
+}
+
+func graphNodeIDs(g *dto.VaultGraphDTO) []int64 {
+	ids := make([]int64, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
+
+// TestNoteLinkService_GetGraph verifies links resolve to notes and filters drop notes with their links.
+// TestNoteLinkService_GetGraph 验证链接解析为笔记，过滤条件会移除笔记及其链接。
+func TestNoteLinkService_GetGraph(t *testing.T) {
+	svc := newTestGraphService(t)
+	ctx := context.Background()
+
+	g, err := svc.GetGraph(ctx, 1, &dto.VaultGraphRequest{Vault: "v"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 1, 2, 3}, graphNodeIDs(g), "deleted notes are left out, nodes are ordered by path")
+	assert.Equal(t, []*dto.VaultGraphEdge{
+		{Source: 1, Target: 2},
+		{Source: 2, Target: 3},
+		{Source: 2, Target: 1, IsEmbed: true},
+	}, g.Edges)
+	assert.Equal(t, "Plan", g.Nodes[2].Name)
+	assert.Equal(t, []string{"project"}, g.Nodes[2].Tags)
+	assert.False(t, g.Truncated)
+
+	g, err = svc.GetGraph(ctx, 1, &dto.VaultGraphRequest{Vault: "v", Folder: "/Projects/"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, graphNodeIDs(g))
+	assert.Equal(t, []*dto.VaultGraphEdge{{Source: 2, Target: 3}}, g.Edges)
+
+	g, err = svc.GetGraph(ctx, 1, &dto.VaultGraphRequest{Vault: "v", Tag: "#project"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, graphNodeIDs(g), "nested tags match their parent")
+}
+
+// TestNoteLinkService_GetGraph_Neighborhood verifies the depth-limited walk follows links both ways.
+// TestNoteLinkService_GetGraph_Neighborhood 验证限定深度的遍历沿双向链接进行。
+func TestNoteLinkService_GetGraph_Neighborhood(t *testing.T) {
+	svc := newTestGraphService(t)
+	ctx := context.Background()
+
+	g, err := svc.GetGraph(ctx, 1, &dto.VaultGraphRequest{Vault: "v", Path: "Projects/Task.md"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, graphNodeIDs(g))
+
+	g, err = svc.GetGraph(ctx, 1, &dto.VaultGraphRequest{Vault: "v", Path: "Projects/Task.md", Depth: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, graphNodeIDs(g))
+	assert.Len(t, g.Edges, 3)
+
+	// The center note survives filters that would drop it
+	g, err = svc.GetGraph(ctx, 1, &dto.VaultGraphRequest{Vault: "v", Path: "Projects/Task.md", Depth: 2, Tag: "home"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, graphNodeIDs(g))
+	assert.Empty(t, g.Edges)
+
+	_, err = svc.GetGraph(ctx, 1, &dto.VaultGraphRequest{Vault: "v", Path: "Nope.md"})
+	assert.Equal(t, code.ErrorNoteNotFound, err)
+}
@@ -5,6 +5,7 @@ package service
 import (
 	"context"
 	"errors"
+	"path"
 	"strings"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
@@ -24,6 +25,18 @@ type NoteLinkService interface {
 	// GetOutlinks gets all links from a source note
 	// GetOutlinks 获取源笔记中的所有链接
 	GetOutlinks(ctx context.Context, uid int64, params *dto.NoteLinkQueryRequest) ([]*dto.NoteLinkItem, error)
+
+	// ResolveLinks resolves the [[wiki link]] targets in note content to the paths of existing notes
+	// ResolveLinks 将笔记内容中的 [[维基链接]] 目标解析为已存在笔记的路径
+	ResolveLinks(ctx context.Context, uid int64, vaultName string, notePath string, content string) (map[string]string, error)
+
+	// GetGraph returns the notes of a vault and the links between them, optionally filtered or limited to the neighborhood of a note
+	// GetGraph 返回仓库中的笔记及其之间的链接，可按条件过滤或限定为某篇笔记的邻域
+	GetGraph(ctx context.Context, uid int64, params *dto.VaultGraphRequest) (*dto.VaultGraphDTO, error)
+
+	// ListUnresolved lists the link targets of a vault that match no existing note
+	// ListUnresolved 列出仓库中未匹配任何已存在笔记的链接目标
+	ListUnresolved(ctx context.Context, uid int64, vaultName string) ([]*dto.NoteUnresolvedLinkItem, error)
 }
 
 // noteLinkService implements NoteLinkService interface
@@ -220,6 +233,74 @@ func (s *noteLinkService) extractLinkContext(content, targetPath string) string
 	return context
 }
 
+// ResolveLinks resolves the [[wiki link]] targets in note content to the paths of existing notes.
+// Like Obsidian, a target is looked up next to the note first, then from the vault root, and a bare
+// note name finally matches a note of that name in any folder. Unresolved targets are left out.
+// ResolveLinks 将笔记内容中的 [[维基链接]] 目标解析为已存在笔记的路径。
+// 与 Obsidian 一致，先在笔记所在目录查找，再从仓库根目录查找，仅有笔记名时最后匹配任意目录下的同名笔记。
+// 无法解析的目标不会出现在结果中。
+func (s *noteLinkService) ResolveLinks(ctx context.Context, uid int64, vaultName string, notePath string, content string) (map[string]string, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, vaultName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	for _, link := range util.ParseWikiLinks(content) {
+		target := link.Path
+		if i := strings.IndexByte(target, '#'); i >= 0 {
+			target = target[:i]
+		}
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+		if _, ok := result[target]; ok {
+			continue
+		}
+		// Embedded attachments are resolved by FileService.ResolveEmbedLinks
+		// 嵌入的附件由 FileService.ResolveEmbedLinks 解析
+		if ext := strings.ToLower(path.Ext(target)); link.IsEmbed && ext != "" && ext != ".md" {
+			continue
+		}
+		if resolved := s.resolveLinkTarget(ctx, uid, vaultID, notePath, target); resolved != "" {
+			result[target] = resolved
+		}
+	}
+	return result, nil
+}
+
+// resolveLinkTarget returns the path of the note a wiki link target points to, empty when there is none
+// resolveLinkTarget 返回维基链接目标指向的笔记路径，不存在时返回空
+func (s *noteLinkService) resolveLinkTarget(ctx context.Context, uid int64, vaultID int64, notePath string, target string) string {
+	name := strings.TrimPrefix(strings.ReplaceAll(target, "\\", "/"), "/")
+	if !strings.HasSuffix(strings.ToLower(name), ".md") {
+		name += ".md"
+	}
+
+	candidates := []string{name}
+	if dir := path.Dir(notePath); dir != "." && dir != "/" {
+		candidates = []string{path.Join(dir, name), name}
+	}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, "../") {
+			continue
+		}
+		note, err := s.noteRepo.GetByPathHash(ctx, util.EncodeHash32(candidate), vaultID, uid)
+		if err == nil && note != nil && !note.IsDeleted() {
+			return note.Path
+		}
+	}
+
+	if !strings.Contains(name, "/") {
+		note, err := s.noteRepo.GetByPathLike(ctx, "/"+name, vaultID, uid)
+		if err == nil && note != nil && !note.IsDeleted() {
+			return note.Path
+		}
+	}
+	return ""
+}
+
 // Ensure noteLinkService implements NoteLinkService interface
 // 确保 noteLinkService 实现了 NoteLinkService 接口
 var _ NoteLinkService = (*noteLinkService)(nil)
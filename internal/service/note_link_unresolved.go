@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/markdown"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+)
+
+// unresolvedLinkTarget normalizes a stored link target: heading and block references, a leading
+// slash and the .md extension are dropped. Attachment targets return an empty string.
+// unresolvedLinkTarget 规范化存储的链接目标：去掉标题与块引用、开头的斜杠以及 .md 扩展名。附件目标返回空字符串。
+func unresolvedLinkTarget(target string) string {
+	if i := strings.IndexByte(target, '#'); i >= 0 {
+		target = target[:i]
+	}
+	target = strings.Trim(strings.TrimSpace(strings.ReplaceAll(target, "\\", "/")), "/")
+	if target == "" || markdown.IsAttachment(target) {
+		return ""
+	}
+	return strings.TrimSuffix(target, ".md")
+}
+
+// ListUnresolved returns the link targets of a vault whose path hash matches no existing note.
+// Targets are matched the way backlinks are, against every path suffix of the notes, so creating
+// a note at StubPath makes the links resolve without touching the link index.
+// ListUnresolved 返回仓库中路径哈希未匹配任何已存在笔记的链接目标。
+// 目标按与反向链接相同的方式与笔记路径的各级后缀进行匹配，因此在 StubPath 创建笔记即可使链接得到解析，无需修改链接索引。
+func (s *noteLinkService) ListUnresolved(ctx context.Context, uid int64, vaultName string) ([]*dto.NoteUnresolvedLinkItem, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, vaultName)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.noteRepo.ListByUpdatedTimestamp(ctx, 0, vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	known := make(map[string]bool)
+	sourcePaths := make(map[int64]string, len(notes))
+	for _, n := range notes {
+		if n.IsDeleted() {
+			continue
+		}
+		sourcePaths[n.ID] = n.Path
+		for _, variation := range util.GeneratePathVariations(n.Path) {
+			known[util.EncodeHash32(variation)] = true
+		}
+	}
+
+	links, err := s.noteLinkRepo.ListByVaultID(ctx, vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	byTarget := make(map[string]*dto.NoteUnresolvedLinkItem)
+	for _, link := range links {
+		sourcePath, ok := sourcePaths[link.SourceNoteID]
+		if !ok || known[link.TargetPathHash] {
+			continue
+		}
+		// Links with a heading or an explicit .md are stored under a hash no note path produces
+		// 带标题或显式 .md 的链接，其存储的哈希不会由任何笔记路径生成
+		target := unresolvedLinkTarget(link.TargetPath)
+		if target == "" || known[util.EncodeHash32(target)] {
+			continue
+		}
+
+		item, ok := byTarget[target]
+		if !ok {
+			item = &dto.NoteUnresolvedLinkItem{Target: target, StubPath: target + ".md", Sources: []string{}}
+			byTarget[target] = item
+		}
+		item.Count++
+		item.Sources = append(item.Sources, sourcePath)
+	}
+
+	items := make([]*dto.NoteUnresolvedLinkItem, 0, len(byTarget))
+	for _, item := range byTarget {
+		sort.Strings(item.Sources)
+		item.Sources = dedupSorted(item.Sources)
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Target < items[j].Target })
+	return items, nil
+}
+
+// dedupSorted removes adjacent duplicates from a sorted slice
+// dedupSorted 移除已排序切片中相邻的重复项
+func dedupSorted(values []string) []string {
+	out := values[:0]
+	for i, v := range values {
+		if i == 0 || v != values[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
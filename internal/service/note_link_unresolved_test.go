@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func testNoteLink(sourceID int64, target string) *domain.NoteLink {
+	return &domain.NoteLink{SourceNoteID: sourceID, TargetPath: target, TargetPathHash: util.EncodeHash32(target)}
+}
+
+// TestNoteLinkService_ListUnresolved verifies only links matching no note path suffix are reported.
+// TestNoteLinkService_ListUnresolved 验证仅报告未匹配任何笔记路径后缀的链接。
+func TestNoteLinkService_ListUnresolved(t *testing.T) {
+	noteRepo := new(domainmocks.MockNoteRepository)
+	noteRepo.On("ListByUpdatedTimestamp", mock.Anything, int64(0), int64(7), int64(1)).Return([]*domain.Note{
+		{ID: 1, Path: "Home.md"},
+		{ID: 2, Path: "Projects/Plan.md"},
+		{ID: 3, Path: "Gone.md", Action: domain.NoteActionDelete},
+	}, nil)
+
+	linkRepo := new(domainmocks.MockNoteLinkRepository)
+	linkRepo.On("ListByVaultID", mock.Anything, int64(7), int64(1)).Return([]*domain.NoteLink{
+		testNoteLink(1, "Plan"),
+		testNoteLink(1, "Projects/Plan#Goals"),
+		testNoteLink(1, "Home.md"),
+		testNoteLink(1, "pic.png"),
+		testNoteLink(1, "Ideas"),
+		testNoteLink(1, "Ideas#Later"),
+		testNoteLink(2, "Ideas"),
+		testNoteLink(2, "Areas/Health"),
+		testNoteLink(2, "Gone"),
+		testNoteLink(3, "Orphaned by a deleted note"),
+	}, nil)
+
+	svc := NewNoteLinkService(linkRepo, noteRepo, &fakeVaultServiceForConflictTest{vaultID: 7})
+	items, err := svc.ListUnresolved(context.Background(), 1, "v")
+	require.NoError(t, err)
+	assert.Equal(t, []*dto.NoteUnresolvedLinkItem{
+		{Target: "Areas/Health", StubPath: "Areas/Health.md", Count: 1, Sources: []string{"Projects/Plan.md"}},
+		{Target: "Gone", StubPath: "Gone.md", Count: 1, Sources: []string{"Projects/Plan.md"}},
+		{Target: "Ideas", StubPath: "Ideas.md", Count: 3, Sources: []string{"Home.md", "Projects/Plan.md"}},
+	}, items)
+}
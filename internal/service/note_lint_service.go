@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/lint"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// LintCheckerLanguageTool checks are proxied to a LanguageTool server
+	// LintCheckerLanguageTool 检查请求代理到 LanguageTool 服务器
+	LintCheckerLanguageTool = "languagetool"
+	// LintCheckerWordList checks use the bundled word-list spellchecker
+	// LintCheckerWordList 使用内置词表拼写检查器
+	LintCheckerWordList = "wordlist"
+)
+
+// NoteLintService defines the spelling and grammar check service interface
+// NoteLintService 定义拼写与语法检查服务接口
+type NoteLintService interface {
+	// Enabled reports whether a checker is configured
+	// Enabled 判断是否配置了检查器
+	Enabled() bool
+
+	// Lint checks the raw text of the request, or the stored note it addresses when the text is empty
+	// Lint 检查请求中的原始文本，文本为空时检查其指定的已存储笔记
+	Lint(ctx context.Context, uid int64, params *dto.NoteLintRequest) (*dto.NoteLintDTO, error)
+}
+
+// noteLintService implements NoteLintService
+// noteLintService 实现 NoteLintService 接口
+type noteLintService struct {
+	checker      lint.Checker
+	name         string
+	config       *config.LintConfig
+	noteRepo     domain.NoteRepository
+	vaultService VaultService
+	logger       *zap.Logger
+}
+
+// NewNoteLintService creates a NoteLintService instance; LanguageTool is preferred over the word list
+// NewNoteLintService 创建 NoteLintService 实例；优先使用 LanguageTool，其次使用词表
+func NewNoteLintService(cfg *config.LintConfig, noteRepo domain.NoteRepository, vaultSvc VaultService, logger *zap.Logger) NoteLintService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	s := &noteLintService{config: cfg, noteRepo: noteRepo, vaultService: vaultSvc, logger: logger}
+	if cfg == nil || !cfg.IsEnabled {
+		return s
+	}
+
+	switch {
+	case cfg.LanguageToolURL != "":
+		timeout, err := util.ParseDuration(cfg.Timeout)
+		if err != nil {
+			timeout = 10 * time.Second
+		}
+		s.checker, s.name = lint.NewLanguageTool(cfg.LanguageToolURL, cfg.Username, cfg.APIKey, timeout), LintCheckerLanguageTool
+	case cfg.Dictionary != "":
+		wl, err := lint.LoadWordList(cfg.Dictionary)
+		if err != nil {
+			logger.Error("NoteLintService: load dictionary failed, lint disabled", zap.String("dictionary", cfg.Dictionary), zap.Error(err))
+			return s
+		}
+		s.checker, s.name = wl, LintCheckerWordList
+	default:
+		logger.Warn("NoteLintService: lint is enabled but neither languagetool-url nor dictionary is set")
+	}
+	return s
+}
+
+// Enabled reports whether a checker is configured
+// Enabled 判断是否配置了检查器
+func (s *noteLintService) Enabled() bool {
+	return s.checker != nil
+}
+
+// Lint checks the raw text of the request, or the stored note it addresses when the text is empty
+// Lint 检查请求中的原始文本，文本为空时检查其指定的已存储笔记
+func (s *noteLintService) Lint(ctx context.Context, uid int64, params *dto.NoteLintRequest) (*dto.NoteLintDTO, error) {
+	if s.checker == nil {
+		return nil, code.ErrorLintDisabled
+	}
+
+	result := &dto.NoteLintDTO{Checker: s.name, Issues: []*dto.NoteLintIssueDTO{}}
+	text := params.Text
+	if text == "" {
+		if params.Vault == "" || params.Path == "" {
+			return nil, code.ErrorInvalidParams.WithDetails("text or vault and path are required")
+		}
+		vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+		if err != nil {
+			return nil, err
+		}
+		pathHash := params.PathHash
+		if pathHash == "" {
+			pathHash = util.EncodeHash32(params.Path)
+		}
+		note, err := s.noteRepo.GetByPathHash(ctx, pathHash, vaultID, uid)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, code.ErrorNoteNotFound
+			}
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		if note.IsDeleted() {
+			return nil, code.ErrorNoteNotFound
+		}
+		text, result.Path = note.Content, note.Path
+	}
+	if s.config.MaxTextLength > 0 && len(text) > s.config.MaxTextLength {
+		return nil, code.ErrorLintTextTooLong
+	}
+	if text == "" {
+		return result, nil
+	}
+
+	language := params.Language
+	if language == "" {
+		language = s.config.DefaultLanguage
+	}
+	issues, err := s.checker.Check(ctx, text, language)
+	if err != nil {
+		s.logger.Warn("NoteLintService.Lint: check failed", zap.Int64("uid", uid), zap.String("checker", s.name), zap.Error(err))
+		return nil, code.ErrorLintFailed.WithDetails(err.Error())
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Offset < issues[j].Offset })
+	for _, issue := range issues {
+		result.Issues = append(result.Issues, &dto.NoteLintIssueDTO{
+			Offset:       issue.Offset,
+			Length:       issue.Length,
+			Message:      issue.Message,
+			ShortMessage: issue.ShortMessage,
+			Rule:         issue.Rule,
+			Category:     issue.Category,
+			Replacements: issue.Replacements,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestNoteLintService(t *testing.T, cfg *config.LintConfig) NoteLintService {
+	t.Helper()
+	noteRepo := new(domainmocks.MockNoteRepository)
+	noteRepo.On("GetByPathHash", mock.Anything, util.EncodeHash32("draft.md"), int64(7), int64(1)).
+		Return(&domain.Note{ID: 3, VaultID: 7, Path: "draft.md", Content: "Helo world"}, nil)
+	return NewNoteLintService(cfg, noteRepo, &fakeVaultServiceForConflictTest{vaultID: 7}, zap.NewNop())
+}
+
+// TestNoteLintService_WordList verifies stored notes and raw text are checked with the bundled word list.
+// TestNoteLintService_WordList 验证使用内置词表检查已存储笔记与原始文本。
+func TestNoteLintService_WordList(t *testing.T) {
+	dict := filepath.Join(t.TempDir(), "words.txt")
+	require.NoError(t, os.WriteFile(dict, []byte("hello\nworld\n"), 0o644))
+	svc := newTestNoteLintService(t, &config.LintConfig{IsEnabled: true, Dictionary: dict, MaxTextLength: 20})
+	require.True(t, svc.Enabled())
+	ctx := context.Background()
+
+	res, err := svc.Lint(ctx, 1, &dto.NoteLintRequest{Vault: "v", Path: "draft.md"})
+	require.NoError(t, err)
+	assert.Equal(t, "draft.md", res.Path)
+	assert.Equal(t, LintCheckerWordList, res.Checker)
+	require.Len(t, res.Issues, 1)
+	assert.Equal(t, 0, res.Issues[0].Offset)
+	assert.Equal(t, []string{"Hello"}, res.Issues[0].Replacements)
+
+	res, err = svc.Lint(ctx, 1, &dto.NoteLintRequest{Text: "hello world"})
+	require.NoError(t, err)
+	assert.Empty(t, res.Issues)
+
+	_, err = svc.Lint(ctx, 1, &dto.NoteLintRequest{Text: "this text is longer than twenty bytes"})
+	assert.Equal(t, code.ErrorLintTextTooLong, err)
+
+	_, err = svc.Lint(ctx, 1, &dto.NoteLintRequest{Vault: "v"})
+	assert.ErrorIs(t, err, code.ErrorInvalidParams)
+}
+
+// TestNoteLintService_Disabled verifies the endpoint reports a disabled checker.
+// TestNoteLintService_Disabled 验证未开启检查器时返回对应错误。
+func TestNoteLintService_Disabled(t *testing.T) {
+	for _, cfg := range []*config.LintConfig{
+		{IsEnabled: false, LanguageToolURL: "http://localhost:8010"},
+		{IsEnabled: true},
+		{IsEnabled: true, Dictionary: filepath.Join(t.TempDir(), "missing.txt")},
+	} {
+		svc := newTestNoteLintService(t, cfg)
+		assert.False(t, svc.Enabled())
+		_, err := svc.Lint(context.Background(), 1, &dto.NoteLintRequest{Text: "hello"})
+		assert.Equal(t, code.ErrorLintDisabled, err)
+	}
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// noteMetaMaxKeys entries a note may carry
+	// noteMetaMaxKeys 每个笔记可携带的元数据条数上限
+	noteMetaMaxKeys = 64
+	// noteMetaMaxValueSize encoded size of a value in bytes
+	// noteMetaMaxValueSize 单个值编码后的字节数上限
+	noteMetaMaxValueSize = 4096
+)
+
+// NoteMetaService defines the note metadata business service interface; metadata are typed
+// key-value pairs kept on the server apart from the frontmatter, e.g. a color, an icon or client flags
+// NoteMetaService 定义笔记元数据业务服务接口；元数据是独立于 frontmatter 保存在服务端的带类型键值对，
+// 如颜色、图标或客户端标记
+type NoteMetaService interface {
+	// Get returns the metadata of a note
+	// Get 返回笔记的元数据
+	Get(ctx context.Context, uid int64, params *dto.NoteMetaRequest) (*dto.NoteMetaDTO, error)
+
+	// Set creates or replaces one entry of a note, the type following the JSON value
+	// Set 新建或替换笔记的一条元数据，类型取决于 JSON 值
+	Set(ctx context.Context, uid int64, params *dto.NoteMetaSetRequest) (*dto.NoteMetaDTO, error)
+
+	// Delete removes one entry of a note
+	// Delete 删除笔记的一条元数据
+	Delete(ctx context.Context, uid int64, params *dto.NoteMetaDeleteRequest) (*dto.NoteMetaDTO, error)
+
+	// MapByNoteIDs returns the metadata of the given notes by note ID, notes without any left out
+	// MapByNoteIDs 按笔记 ID 返回指定笔记的元数据，没有元数据的笔记不在结果中
+	MapByNoteIDs(ctx context.Context, uid int64, noteIDs []int64) (map[int64]map[string]any, error)
+
+	// Migrate moves the metadata of a renamed note to its new note ID
+	// Migrate 将重命名笔记的元数据迁移到新的笔记 ID
+	Migrate(ctx context.Context, oldNoteID, newNoteID, uid int64) error
+}
+
+// noteMetaService implements NoteMetaService
+// noteMetaService 实现 NoteMetaService 接口
+type noteMetaService struct {
+	repo         domain.NoteMetaRepository
+	noteRepo     domain.NoteRepository
+	vaultService VaultService
+	logger       *zap.Logger
+}
+
+// NewNoteMetaService creates a NoteMetaService instance
+// NewNoteMetaService 创建 NoteMetaService 实例
+func NewNoteMetaService(repo domain.NoteMetaRepository, noteRepo domain.NoteRepository, vaultSvc VaultService, logger *zap.Logger) NoteMetaService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &noteMetaService{
+		repo:         repo,
+		noteRepo:     noteRepo,
+		vaultService: vaultSvc,
+		logger:       logger,
+	}
+}
+
+// resolveNote finds the note addressed by vault and path
+// resolveNote 根据笔记库与路径查找笔记
+func (s *noteMetaService) resolveNote(ctx context.Context, uid int64, vault, path, pathHash string) (*domain.Note, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, vault)
+	if err != nil {
+		return nil, err
+	}
+	if pathHash == "" {
+		pathHash = util.EncodeHash32(path)
+	}
+	note, err := s.noteRepo.GetByPathHash(ctx, pathHash, vaultID, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, code.ErrorNoteNotFound
+		}
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return note, nil
+}
+
+// encodeNoteMeta encodes a decoded JSON value with its type
+// encodeNoteMeta 将解码后的 JSON 值连同类型一起编码
+func encodeNoteMeta(value any) (domain.NoteMetaType, string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", "", code.ErrorInvalidParams.WithDetails("value is required, delete the key to remove it")
+	case string:
+		return domain.NoteMetaTypeString, v, nil
+	case float64:
+		return domain.NoteMetaTypeNumber, strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return domain.NoteMetaTypeBool, strconv.FormatBool(v), nil
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return "", "", code.ErrorInvalidParams.WithDetails("value is not valid JSON")
+		}
+		return domain.NoteMetaTypeJSON, string(raw), nil
+	}
+}
+
+// decodeNoteMeta returns the typed value of an entry, falling back to the raw string
+// decodeNoteMeta 返回元数据的带类型值，无法解析时退回原始字符串
+func decodeNoteMeta(m *domain.NoteMeta) any {
+	switch m.Type {
+	case domain.NoteMetaTypeNumber:
+		if v, err := strconv.ParseFloat(m.Value, 64); err == nil {
+			return v
+		}
+	case domain.NoteMetaTypeBool:
+		if v, err := strconv.ParseBool(m.Value); err == nil {
+			return v
+		}
+	case domain.NoteMetaTypeJSON:
+		if json.Valid([]byte(m.Value)) {
+			return json.RawMessage(m.Value)
+		}
+	}
+	return m.Value
+}
+
+// toDTO builds the metadata of a note
+// toDTO 构建笔记的元数据
+func (s *noteMetaService) toDTO(ctx context.Context, uid int64, note *domain.Note) (*dto.NoteMetaDTO, error) {
+	metas, err := s.MapByNoteIDs(ctx, uid, []int64{note.ID})
+	if err != nil {
+		return nil, err
+	}
+	meta := metas[note.ID]
+	if meta == nil {
+		meta = map[string]any{}
+	}
+	return &dto.NoteMetaDTO{NoteID: note.ID, Path: note.Path, Meta: meta}, nil
+}
+
+// Get implements NoteMetaService
+// Get 实现 NoteMetaService
+func (s *noteMetaService) Get(ctx context.Context, uid int64, params *dto.NoteMetaRequest) (*dto.NoteMetaDTO, error) {
+	note, err := s.resolveNote(ctx, uid, params.Vault, params.Path, params.PathHash)
+	if err != nil {
+		return nil, err
+	}
+	return s.toDTO(ctx, uid, note)
+}
+
+// Set implements NoteMetaService
+// Set 实现 NoteMetaService
+func (s *noteMetaService) Set(ctx context.Context, uid int64, params *dto.NoteMetaSetRequest) (*dto.NoteMetaDTO, error) {
+	key := strings.TrimSpace(params.Key)
+	if key == "" {
+		return nil, code.ErrorInvalidParams.WithDetails("key is required")
+	}
+	valueType, value, err := encodeNoteMeta(params.Value)
+	if err != nil {
+		return nil, err
+	}
+	if len(value) > noteMetaMaxValueSize {
+		return nil, code.ErrorInvalidParams.WithDetails("value exceeds " + strconv.Itoa(noteMetaMaxValueSize) + " bytes")
+	}
+
+	note, err := s.resolveNote(ctx, uid, params.Vault, params.Path, params.PathHash)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.MapByNoteIDs(ctx, uid, []int64{note.ID})
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := existing[note.ID][key]; !ok && len(existing[note.ID]) >= noteMetaMaxKeys {
+		return nil, code.ErrorInvalidParams.WithDetails("a note carries at most " + strconv.Itoa(noteMetaMaxKeys) + " metadata keys")
+	}
+
+	now := time.Now()
+	meta := &domain.NoteMeta{NoteID: note.ID, VaultID: note.VaultID, Key: key, Type: valueType, Value: value, CreatedAt: now, UpdatedAt: now}
+	if err := s.repo.Set(ctx, meta, uid); err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return s.toDTO(ctx, uid, note)
+}
+
+// Delete implements NoteMetaService
+// Delete 实现 NoteMetaService
+func (s *noteMetaService) Delete(ctx context.Context, uid int64, params *dto.NoteMetaDeleteRequest) (*dto.NoteMetaDTO, error) {
+	note, err := s.resolveNote(ctx, uid, params.Vault, params.Path, params.PathHash)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Delete(ctx, note.ID, strings.TrimSpace(params.Key), uid); err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return s.toDTO(ctx, uid, note)
+}
+
+// MapByNoteIDs implements NoteMetaService
+// MapByNoteIDs 实现 NoteMetaService
+func (s *noteMetaService) MapByNoteIDs(ctx context.Context, uid int64, noteIDs []int64) (map[int64]map[string]any, error) {
+	list, err := s.repo.ListByNoteIDs(ctx, noteIDs, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	result := make(map[int64]map[string]any)
+	for _, m := range list {
+		if result[m.NoteID] == nil {
+			result[m.NoteID] = make(map[string]any)
+		}
+		result[m.NoteID][m.Key] = decodeNoteMeta(m)
+	}
+	return result, nil
+}
+
+// Migrate implements NoteMetaService
+// Migrate 实现 NoteMetaService
+func (s *noteMetaService) Migrate(ctx context.Context, oldNoteID, newNoteID, uid int64) error {
+	if err := s.repo.Migrate(ctx, oldNoteID, newNoteID, uid); err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// Ensure noteMetaService implements NoteMetaService
+// 确保 noteMetaService 实现了 NoteMetaService 接口
+var _ NoteMetaService = (*noteMetaService)(nil)
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeNoteMetaRepo in-memory domain.NoteMetaRepository in insertion order
+type fakeNoteMetaRepo struct {
+	metas []*domain.NoteMeta
+}
+
+func (r *fakeNoteMetaRepo) ListByNoteIDs(ctx context.Context, noteIDs []int64, uid int64) ([]*domain.NoteMeta, error) {
+	var list []*domain.NoteMeta
+	for _, m := range r.metas {
+		for _, id := range noteIDs {
+			if m.NoteID == id {
+				list = append(list, m)
+			}
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeNoteMetaRepo) Set(ctx context.Context, meta *domain.NoteMeta, uid int64) error {
+	for _, m := range r.metas {
+		if m.NoteID == meta.NoteID && m.Key == meta.Key {
+			m.Type, m.Value = meta.Type, meta.Value
+			return nil
+		}
+	}
+	r.metas = append(r.metas, meta)
+	return nil
+}
+
+func (r *fakeNoteMetaRepo) Delete(ctx context.Context, noteID int64, key string, uid int64) error {
+	for i, m := range r.metas {
+		if m.NoteID == noteID && m.Key == key {
+			r.metas = append(r.metas[:i], r.metas[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *fakeNoteMetaRepo) Migrate(ctx context.Context, oldNoteID, newNoteID int64, uid int64) error {
+	for _, m := range r.metas {
+		if m.NoteID == oldNoteID {
+			m.NoteID = newNoteID
+		}
+	}
+	return nil
+}
+
+// TestNoteMetaService verifies values keep their JSON type across a round trip, entries are
+// replaced and deleted by key, and metadata follow a renamed note.
+// TestNoteMetaService 验证值在往返后保持 JSON 类型、按键替换与删除元数据，且元数据跟随重命名的笔记。
+func TestNoteMetaService(t *testing.T) {
+	vaultRepo := newVaultMockRepo()
+	vaultRepo.On("GetByName", mock.Anything, "Work", int64(1)).Return(newVault(1, "Work"), nil)
+	noteRepo := new(domainmocks.MockNoteRepository)
+	noteRepo.On("GetByPathHash", mock.Anything, util.EncodeHash32("a.md"), int64(1), int64(1)).
+		Return(&domain.Note{ID: 10, VaultID: 1, Path: "a.md"}, nil)
+	repo := &fakeNoteMetaRepo{}
+	svc := NewNoteMetaService(repo, noteRepo, newVaultSvc(vaultRepo), zap.NewNop())
+	ctx := context.Background()
+
+	set := func(key string, value any) (*dto.NoteMetaDTO, error) {
+		return svc.Set(ctx, 1, &dto.NoteMetaSetRequest{Vault: "Work", Path: "a.md", Key: key, Value: value})
+	}
+
+	_, err := set("color", "#ff8800")
+	require.NoError(t, err)
+	_, err = set("pinned", true)
+	require.NoError(t, err)
+	_, err = set("rating", float64(4.5))
+	require.NoError(t, err)
+	result, err := set("tags", []any{"a", "b"})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(10), result.NoteID)
+	assert.Equal(t, "#ff8800", result.Meta["color"])
+	assert.Equal(t, true, result.Meta["pinned"])
+	assert.Equal(t, 4.5, result.Meta["rating"])
+	assert.Equal(t, json.RawMessage(`["a","b"]`), result.Meta["tags"])
+
+	result, err = set("pinned", false)
+	require.NoError(t, err)
+	assert.Equal(t, false, result.Meta["pinned"])
+	assert.Len(t, repo.metas, 4)
+
+	_, err = set("empty", nil)
+	assert.ErrorIs(t, err, code.ErrorInvalidParams)
+
+	result, err = svc.Delete(ctx, 1, &dto.NoteMetaDeleteRequest{Vault: "Work", Path: "a.md", Key: "color"})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Meta, "color")
+
+	require.NoError(t, svc.Migrate(ctx, 10, 11, 1))
+	metas, err := svc.MapByNoteIDs(ctx, 1, []int64{10, 11})
+	require.NoError(t, err)
+	assert.NotContains(t, metas, int64(10))
+	assert.Len(t, metas[11], 3)
+}
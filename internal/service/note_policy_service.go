@@ -0,0 +1,476 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// notePolicyInterval scheduled runs of a policy are at least this far apart
+	// notePolicyInterval 同一策略两次定时运行的最小间隔
+	notePolicyInterval = 24 * time.Hour
+	// notePolicyMaxNotesPerRun notes processed per run, the rest is left for the next run
+	// notePolicyMaxNotesPerRun 每次运行处理的笔记数上限，其余留待下次运行
+	notePolicyMaxNotesPerRun = 1000
+	// notePolicyMaxErrors failures reported in the message of a run
+	// notePolicyMaxErrors 运行记录消息中报告的失败数上限
+	notePolicyMaxErrors = 20
+	// notePolicyClientType / notePolicyClientName client recorded on notes changed by a policy
+	// notePolicyClientType / notePolicyClientName 记录在被策略修改的笔记上的客户端信息
+	notePolicyClientType = "server"
+	notePolicyClientName = "NotePolicy"
+)
+
+// NotePolicyService defines the note lifecycle policy business service interface
+// NotePolicyService 定义笔记生命周期策略业务服务接口
+type NotePolicyService interface {
+	// List lists all policies of a user
+	// List 列出用户的全部策略
+	List(ctx context.Context, uid int64) ([]*dto.NotePolicyDTO, error)
+
+	// Save creates or updates a policy
+	// Save 新建或更新策略
+	Save(ctx context.Context, uid int64, params *dto.NotePolicyRequest) (*dto.NotePolicyDTO, error)
+
+	// Delete removes a policy together with its run history
+	// Delete 删除策略及其运行历史
+	Delete(ctx context.Context, uid int64, id int64) error
+
+	// Preview lists the notes a run of the policy would process without changing anything
+	// Preview 列出策略运行时将处理的笔记，不做任何修改
+	Preview(ctx context.Context, uid int64, id int64) (*dto.NotePolicyPreviewDTO, error)
+
+	// Execute runs a policy immediately, enabled or not
+	// Execute 立即运行策略，无论是否启用
+	Execute(ctx context.Context, uid int64, id int64) (*dto.NotePolicyRunDTO, error)
+
+	// ListRuns retrieves the run history with pagination, newest first
+	// ListRuns 按时间倒序分页查询运行历史
+	ListRuns(ctx context.Context, uid int64, policyID int64, page, pageSize int) ([]*dto.NotePolicyRunDTO, int64, error)
+
+	// ExecuteScheduled runs every enabled policy whose last run is older than a day
+	// ExecuteScheduled 运行所有上次运行已超过一天的已启用策略
+	ExecuteScheduled(ctx context.Context) error
+}
+
+// notePolicyService implements NotePolicyService
+// notePolicyService 实现 NotePolicyService 接口
+type notePolicyService struct {
+	repo         domain.NotePolicyRepository
+	noteRepo     domain.NoteRepository
+	vaultService VaultService
+	noteService  NoteService
+	logger       *zap.Logger
+
+	runMu sync.Mutex // Serializes runs so a manual and a scheduled run never race // 串行化运行，避免手动与定时运行相互竞争
+}
+
+// NewNotePolicyService creates a NotePolicyService instance
+// NewNotePolicyService 创建 NotePolicyService 实例
+func NewNotePolicyService(repo domain.NotePolicyRepository, noteRepo domain.NoteRepository, vaultService VaultService, noteService NoteService, logger *zap.Logger) NotePolicyService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &notePolicyService{
+		repo:         repo,
+		noteRepo:     noteRepo,
+		vaultService: vaultService,
+		noteService:  noteService.WithClient(notePolicyClientType, notePolicyClientName, ""),
+		logger:       logger,
+	}
+}
+
+// notePolicyToDTO converts the domain model to the DTO
+// notePolicyToDTO 将领域模型转换为 DTO
+func notePolicyToDTO(p *domain.NotePolicy, vault string) *dto.NotePolicyDTO {
+	return &dto.NotePolicyDTO{
+		ID:            p.ID,
+		Vault:         vault,
+		Name:          p.Name,
+		Folder:        p.Folder,
+		Tag:           p.Tag,
+		AgeBasis:      string(p.AgeBasis),
+		OlderThanDays: p.OlderThanDays,
+		Action:        string(p.Action),
+		ArchiveFolder: p.ArchiveFolder,
+		IsEnabled:     p.IsEnabled,
+		LastRunTime:   timex.Time(p.LastRunTime),
+		CreatedAt:     timex.Time(p.CreatedAt),
+		UpdatedAt:     timex.Time(p.UpdatedAt),
+	}
+}
+
+// notePolicyRunToDTO converts the domain model to the DTO
+// notePolicyRunToDTO 将领域模型转换为 DTO
+func notePolicyRunToDTO(r *domain.NotePolicyRun) *dto.NotePolicyRunDTO {
+	return &dto.NotePolicyRunDTO{
+		ID:        r.ID,
+		PolicyID:  r.PolicyID,
+		Trigger:   string(r.Trigger),
+		Matched:   r.Matched,
+		Succeeded: r.Succeeded,
+		Failed:    r.Failed,
+		Paths:     r.Paths,
+		Message:   r.Message,
+		StartTime: timex.Time(r.StartTime),
+		EndTime:   timex.Time(r.EndTime),
+	}
+}
+
+// inPolicyFolder reports whether path lies inside folder; an empty folder is the whole vault
+// inPolicyFolder 判断 path 是否位于 folder 内；folder 为空表示整个仓库
+func inPolicyFolder(path, folder string) bool {
+	return folder == "" || strings.HasPrefix(path, folder+"/")
+}
+
+// vaultName returns the name of the policy's vault, empty when the vault is gone
+// vaultName 返回策略所属仓库的名称，仓库不存在时返回空
+func (s *notePolicyService) vaultName(ctx context.Context, p *domain.NotePolicy) string {
+	vault, err := s.vaultService.Get(ctx, p.UID, p.VaultID)
+	if err != nil {
+		return ""
+	}
+	return vault.Name
+}
+
+// get returns a policy of the user or ErrorNotePolicyNotFound
+// get 获取用户的策略，不存在时返回 ErrorNotePolicyNotFound
+func (s *notePolicyService) get(ctx context.Context, uid, id int64) (*domain.NotePolicy, error) {
+	p, err := s.repo.Get(ctx, id, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if p == nil {
+		return nil, code.ErrorNotePolicyNotFound
+	}
+	return p, nil
+}
+
+// List lists all policies of a user
+// List 列出用户的全部策略
+func (s *notePolicyService) List(ctx context.Context, uid int64) ([]*dto.NotePolicyDTO, error) {
+	list, err := s.repo.List(ctx, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	names := make(map[int64]string)
+	results := make([]*dto.NotePolicyDTO, 0, len(list))
+	for _, p := range list {
+		name, ok := names[p.VaultID]
+		if !ok {
+			name = s.vaultName(ctx, p)
+			names[p.VaultID] = name
+		}
+		results = append(results, notePolicyToDTO(p, name))
+	}
+	return results, nil
+}
+
+// Save creates or updates a policy.
+// The archive folder is excluded from matching, so archived notes are not archived again.
+// 归档目录不参与匹配，已归档的笔记不会被再次归档。
+func (s *notePolicyService) Save(ctx context.Context, uid int64, params *dto.NotePolicyRequest) (*dto.NotePolicyDTO, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &domain.NotePolicy{
+		ID:            params.ID,
+		UID:           uid,
+		VaultID:       vaultID,
+		Name:          params.Name,
+		Folder:        strings.Trim(params.Folder, "/"),
+		Tag:           strings.TrimPrefix(strings.TrimSpace(params.Tag), "#"),
+		AgeBasis:      domain.NotePolicyAgeBasis(params.AgeBasis),
+		OlderThanDays: params.OlderThanDays,
+		Action:        domain.NotePolicyAction(params.Action),
+		ArchiveFolder: strings.Trim(params.ArchiveFolder, "/"),
+		IsEnabled:     params.IsEnabled,
+	}
+	if p.AgeBasis == "" {
+		p.AgeBasis = domain.NotePolicyAgeModified
+	}
+	if !p.Action.IsValid() || !p.AgeBasis.IsValid() || p.OlderThanDays < 1 {
+		return nil, code.ErrorNotePolicyInvalid
+	}
+	switch p.Action {
+	case domain.NotePolicyActionArchive:
+		if p.ArchiveFolder == "" {
+			return nil, code.ErrorNotePolicyInvalid.WithDetails("archiveFolder is required for the archive action")
+		}
+		if p.ArchiveFolder == p.Folder {
+			return nil, code.ErrorNotePolicyInvalid.WithDetails("archiveFolder must differ from folder")
+		}
+	case domain.NotePolicyActionDelete:
+		p.ArchiveFolder = ""
+	}
+
+	if p.ID > 0 {
+		existing, err := s.get(ctx, uid, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		p.CreatedAt = existing.CreatedAt
+		p.LastRunTime = existing.LastRunTime
+	}
+
+	saved, err := s.repo.Save(ctx, p, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return notePolicyToDTO(saved, params.Vault), nil
+}
+
+// Delete removes a policy together with its run history
+// Delete 删除策略及其运行历史
+func (s *notePolicyService) Delete(ctx context.Context, uid int64, id int64) error {
+	if _, err := s.get(ctx, uid, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id, uid); err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// match returns the notes the policy applies to at now, sorted by path and capped at
+// notePolicyMaxNotesPerRun, together with the number of matching notes before capping.
+// Ages fall back to the server timestamps for notes without client times.
+// match 返回策略在 now 时刻作用的笔记（按路径排序并截断至 notePolicyMaxNotesPerRun），以及截断前的匹配数。
+// 没有客户端时间的笔记使用服务端时间计算年龄。
+func (s *notePolicyService) match(ctx context.Context, p *domain.NotePolicy, now time.Time) ([]*domain.Note, int, error) {
+	notes, err := s.noteRepo.ListByUpdatedTimestampMeta(ctx, 0, p.VaultID, p.UID)
+	if err != nil {
+		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	cutoff := now.AddDate(0, 0, -p.OlderThanDays).UnixMilli()
+	var matched []*domain.Note
+	for _, n := range notes {
+		if n.IsDeleted() || !inPolicyFolder(n.Path, p.Folder) {
+			continue
+		}
+		if p.Action == domain.NotePolicyActionArchive && inPolicyFolder(n.Path, p.ArchiveFolder) {
+			continue
+		}
+
+		age := n.Mtime
+		if age == 0 {
+			age = n.UpdatedAt.UnixMilli()
+		}
+		if p.AgeBasis == domain.NotePolicyAgeCreated {
+			age = n.Ctime
+			if age == 0 {
+				age = n.CreatedAt.UnixMilli()
+			}
+		}
+		if age <= 0 || age > cutoff {
+			continue
+		}
+
+		if p.Tag != "" {
+			// The meta listing has no content, tags need the full note
+			// 元数据列表不含内容，标签匹配需要读取完整笔记
+			full, err := s.noteRepo.GetByID(ctx, n.ID, p.UID)
+			if err != nil || !util.HasTag(full.Content, p.Tag) {
+				continue
+			}
+		}
+		matched = append(matched, n)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].Path < matched[j].Path
+	})
+	total := len(matched)
+	if total > notePolicyMaxNotesPerRun {
+		matched = matched[:notePolicyMaxNotesPerRun]
+	}
+	return matched, total, nil
+}
+
+// archiveTarget returns the path a note is archived to, keeping its folder structure
+// archiveTarget 返回笔记归档后的路径，保留其目录结构
+func archiveTarget(p *domain.NotePolicy, path string) string {
+	return p.ArchiveFolder + "/" + path
+}
+
+// Preview lists the notes a run of the policy would process without changing anything
+// Preview 列出策略运行时将处理的笔记，不做任何修改
+func (s *notePolicyService) Preview(ctx context.Context, uid int64, id int64) (*dto.NotePolicyPreviewDTO, error) {
+	p, err := s.get(ctx, uid, id)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, total, err := s.match(ctx, p, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.NotePolicyPreviewDTO{
+		PolicyID: p.ID,
+		Action:   string(p.Action),
+		Matched:  total,
+		Items:    make([]*dto.NotePolicyPreviewItemDTO, 0, len(notes)),
+	}
+	for _, n := range notes {
+		item := &dto.NotePolicyPreviewItemDTO{Path: n.Path, Mtime: n.Mtime, Ctime: n.Ctime}
+		if p.Action == domain.NotePolicyActionArchive {
+			item.Target = archiveTarget(p, n.Path)
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result, nil
+}
+
+// Execute runs a policy immediately, enabled or not
+// Execute 立即运行策略，无论是否启用
+func (s *notePolicyService) Execute(ctx context.Context, uid int64, id int64) (*dto.NotePolicyRunDTO, error) {
+	p, err := s.get(ctx, uid, id)
+	if err != nil {
+		return nil, err
+	}
+	run, err := s.run(ctx, p, domain.NotePolicyTriggerManual)
+	if err != nil {
+		return nil, err
+	}
+	return notePolicyRunToDTO(run), nil
+}
+
+// run applies the policy and stores the run in its history.
+// Archiving renames the note and deleting moves it into the trash, both through NoteService so sync
+// logs, history and folder bookkeeping stay consistent and clients pick the changes up on their next sync.
+// run 执行策略并将本次运行写入历史。
+// 归档即重命名笔记，删除即移入回收站，均通过 NoteService 完成，使同步日志、历史版本与目录信息保持一致，
+// 客户端在下次同步时获取这些变更。
+func (s *notePolicyService) run(ctx context.Context, p *domain.NotePolicy, trigger domain.NotePolicyTrigger) (*domain.NotePolicyRun, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	vault := s.vaultName(ctx, p)
+	if vault == "" {
+		return nil, code.ErrorVaultNotFound
+	}
+
+	run := &domain.NotePolicyRun{
+		UID:       p.UID,
+		PolicyID:  p.ID,
+		Trigger:   trigger,
+		StartTime: time.Now(),
+	}
+
+	notes, total, err := s.match(ctx, p, run.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	run.Matched = total
+
+	var failures []string
+	for _, n := range notes {
+		if ctx.Err() != nil {
+			break
+		}
+		var err error
+		switch p.Action {
+		case domain.NotePolicyActionArchive:
+			_, _, err = s.noteService.Rename(ctx, p.UID, &dto.NoteRenameRequest{
+				Vault:       vault,
+				Path:        archiveTarget(p, n.Path),
+				OldPath:     n.Path,
+				OldPathHash: n.PathHash,
+			})
+		case domain.NotePolicyActionDelete:
+			_, err = s.noteService.Delete(ctx, p.UID, &dto.NoteDeleteRequest{
+				Vault:    vault,
+				Path:     n.Path,
+				PathHash: n.PathHash,
+			})
+		}
+		if err != nil {
+			run.Failed++
+			if len(failures) < notePolicyMaxErrors {
+				failures = append(failures, fmt.Sprintf("%s: %s", n.Path, err.Error()))
+			}
+			continue
+		}
+		run.Succeeded++
+		run.Paths = append(run.Paths, n.Path)
+	}
+	run.Message = strings.Join(failures, "\n")
+	run.EndTime = time.Now()
+
+	if err := s.repo.UpdateLastRunTime(ctx, p.ID, p.UID, run.StartTime); err != nil {
+		s.logger.Warn("note policy: update last run time failed", zap.Int64("uid", p.UID), zap.Int64("policyId", p.ID), zap.Error(err))
+	}
+	saved, err := s.repo.CreateRun(ctx, run, p.UID)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return saved, nil
+}
+
+// ListRuns retrieves the run history with pagination, newest first
+// ListRuns 按时间倒序分页查询运行历史
+func (s *notePolicyService) ListRuns(ctx context.Context, uid int64, policyID int64, page, pageSize int) ([]*dto.NotePolicyRunDTO, int64, error) {
+	runs, total, err := s.repo.ListRuns(ctx, policyID, uid, page, pageSize)
+	if err != nil {
+		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	results := make([]*dto.NotePolicyRunDTO, 0, len(runs))
+	for _, r := range runs {
+		results = append(results, notePolicyRunToDTO(r))
+	}
+	return results, total, nil
+}
+
+// ExecuteScheduled runs every enabled policy whose last run is older than a day.
+// A failing policy is logged and does not stop the others.
+// ExecuteScheduled 运行所有上次运行已超过一天的已启用策略。失败的策略仅记录日志，不影响其他策略。
+func (s *notePolicyService) ExecuteScheduled(ctx context.Context) error {
+	policies, err := s.repo.ListEnabled(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, p := range policies {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !p.LastRunTime.IsZero() && now.Sub(p.LastRunTime) < notePolicyInterval {
+			continue
+		}
+		run, err := s.run(ctx, p, domain.NotePolicyTriggerSchedule)
+		if err != nil {
+			s.logger.Warn("note policy: run failed", zap.Int64("uid", p.UID), zap.Int64("policyId", p.ID), zap.Error(err))
+			continue
+		}
+		if run.Succeeded > 0 || run.Failed > 0 {
+			s.logger.Info("note policy: run finished",
+				zap.Int64("uid", p.UID),
+				zap.Int64("policyId", p.ID),
+				zap.String("action", string(p.Action)),
+				zap.Int("succeeded", run.Succeeded),
+				zap.Int("failed", run.Failed),
+			)
+		}
+	}
+	return nil
+}
+
+// Ensure notePolicyService implements NotePolicyService
+// 确保 notePolicyService 实现了 NotePolicyService 接口
+var _ NotePolicyService = (*notePolicyService)(nil)
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeNotePolicyRepo in-memory domain.NotePolicyRepository
+type fakeNotePolicyRepo struct {
+	policies map[int64]*domain.NotePolicy
+	runs     []*domain.NotePolicyRun
+}
+
+func (r *fakeNotePolicyRepo) List(ctx context.Context, uid int64) ([]*domain.NotePolicy, error) {
+	var list []*domain.NotePolicy
+	for _, p := range r.policies {
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+func (r *fakeNotePolicyRepo) ListEnabled(ctx context.Context) ([]*domain.NotePolicy, error) {
+	var list []*domain.NotePolicy
+	for _, p := range r.policies {
+		if p.IsEnabled {
+			list = append(list, p)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeNotePolicyRepo) Get(ctx context.Context, id, uid int64) (*domain.NotePolicy, error) {
+	return r.policies[id], nil
+}
+
+func (r *fakeNotePolicyRepo) Save(ctx context.Context, policy *domain.NotePolicy, uid int64) (*domain.NotePolicy, error) {
+	if policy.ID == 0 {
+		policy.ID = int64(len(r.policies) + 1)
+	}
+	r.policies[policy.ID] = policy
+	return policy, nil
+}
+
+func (r *fakeNotePolicyRepo) Delete(ctx context.Context, id, uid int64) error {
+	delete(r.policies, id)
+	return nil
+}
+
+func (r *fakeNotePolicyRepo) UpdateLastRunTime(ctx context.Context, id, uid int64, lastRun time.Time) error {
+	r.policies[id].LastRunTime = lastRun
+	return nil
+}
+
+func (r *fakeNotePolicyRepo) CreateRun(ctx context.Context, run *domain.NotePolicyRun, uid int64) (*domain.NotePolicyRun, error) {
+	run.ID = int64(len(r.runs) + 1)
+	r.runs = append(r.runs, run)
+	return run, nil
+}
+
+func (r *fakeNotePolicyRepo) ListRuns(ctx context.Context, policyID, uid int64, page, pageSize int) ([]*domain.NotePolicyRun, int64, error) {
+	return r.runs, int64(len(r.runs)), nil
+}
+
+// fakePolicyNoteService records the renames and deletes of a policy run.
+// service/mocks imports service, so the NoteService mock cannot be used here.
+type fakePolicyNoteService struct {
+	NoteService
+	renamed map[string]string
+	deleted []string
+}
+
+func (f *fakePolicyNoteService) WithClient(clientType, name, version string) NoteService {
+	return f
+}
+
+func (f *fakePolicyNoteService) Rename(ctx context.Context, uid int64, params *dto.NoteRenameRequest) (*dto.NoteDTO, *dto.NoteDTO, error) {
+	if params.OldPath == "Projects/taken.md" {
+		return nil, nil, code.ErrorNoteExist
+	}
+	f.renamed[params.OldPath] = params.Path
+	return nil, nil, nil
+}
+
+func (f *fakePolicyNoteService) Delete(ctx context.Context, uid int64, params *dto.NoteDeleteRequest) (*dto.NoteDTO, error) {
+	f.deleted = append(f.deleted, params.Path)
+	return nil, nil
+}
+
+func newNotePolicySvc(t *testing.T) (NotePolicyService, *fakeNotePolicyRepo, *fakePolicyNoteService) {
+	t.Helper()
+	vaultRepo := new(domainmocks.MockVaultRepository)
+	noteRepo := new(domainmocks.MockNoteRepository)
+	vaultRepo.On("GetByName", mock.Anything, "MyVault", int64(1)).Return(newVault(7, "MyVault"), nil)
+	vaultRepo.On("GetByID", mock.Anything, int64(7), int64(1)).Return(newVault(7, "MyVault"), nil)
+
+	day := 24 * time.Hour
+	old := time.Now().Add(-400 * day).UnixMilli()
+	recent := time.Now().Add(-10 * day).UnixMilli()
+	noteRepo.On("ListByUpdatedTimestampMeta", mock.Anything, int64(0), int64(7), int64(1)).Return([]*domain.Note{
+		{ID: 1, Path: "Projects/stale.md", Action: domain.NoteActionModify, Mtime: old, Ctime: old},
+		{ID: 2, Path: "Projects/fresh.md", Action: domain.NoteActionModify, Mtime: recent, Ctime: old},
+		{ID: 3, Path: "Projects/taken.md", Action: domain.NoteActionModify, Mtime: old, Ctime: old},
+		{ID: 4, Path: "Archive/Projects/done.md", Action: domain.NoteActionCreate, Mtime: old, Ctime: old},
+		{ID: 5, Path: "Inbox/scratch.md", Action: domain.NoteActionCreate, Mtime: old, Ctime: old},
+		{ID: 6, Path: "Inbox/keep.md", Action: domain.NoteActionCreate, Mtime: old, Ctime: old},
+		{ID: 7, Path: "Projects/gone.md", Action: domain.NoteActionDelete, Mtime: old, Ctime: old},
+	}, nil)
+	noteRepo.On("GetByID", mock.Anything, int64(5), int64(1)).Return(&domain.Note{ID: 5, Content: "Scratch #temp"}, nil)
+	noteRepo.On("GetByID", mock.Anything, int64(6), int64(1)).Return(&domain.Note{ID: 6, Content: "Keep me"}, nil)
+
+	repo := &fakeNotePolicyRepo{policies: map[int64]*domain.NotePolicy{}}
+	notes := &fakePolicyNoteService{renamed: map[string]string{}}
+	vaultSvc := NewVaultService(vaultRepo, noteRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	return NewNotePolicyService(repo, noteRepo, vaultSvc, notes, zap.NewNop()), repo, notes
+}
+
+// TestNotePolicyService_Archive verifies the dry run lists stale notes outside the archive and a run moves them.
+// TestNotePolicyService_Archive 验证试运行列出归档目录外的过期笔记，运行时将其移动。
+func TestNotePolicyService_Archive(t *testing.T) {
+	svc, repo, notes := newNotePolicySvc(t)
+	ctx := context.Background()
+
+	saved, err := svc.Save(ctx, 1, &dto.NotePolicyRequest{Vault: "MyVault", Folder: "/", OlderThanDays: 365, Action: "archive", ArchiveFolder: "Archive/"})
+	require.NoError(t, err)
+	assert.Equal(t, "modified", saved.AgeBasis)
+	assert.Equal(t, "Archive", saved.ArchiveFolder)
+
+	preview, err := svc.Preview(ctx, 1, saved.ID)
+	require.NoError(t, err)
+	require.Equal(t, 4, preview.Matched)
+	assert.Equal(t, "Inbox/keep.md", preview.Items[0].Path)
+	assert.Equal(t, "Archive/Projects/stale.md", preview.Items[2].Target)
+	assert.Empty(t, notes.renamed, "a preview changes nothing")
+	assert.Empty(t, repo.runs)
+
+	run, err := svc.Execute(ctx, 1, saved.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "manual", run.Trigger)
+	assert.Equal(t, 4, run.Matched)
+	assert.Equal(t, 3, run.Succeeded)
+	assert.Equal(t, 1, run.Failed)
+	assert.Contains(t, run.Message, "Projects/taken.md")
+	assert.Equal(t, "Archive/Projects/stale.md", notes.renamed["Projects/stale.md"])
+	assert.False(t, repo.policies[saved.ID].LastRunTime.IsZero())
+}
+
+// TestNotePolicyService_DeleteByTag verifies only tagged notes are deleted and due policies run once a day.
+// TestNotePolicyService_DeleteByTag 验证只删除带标签的笔记，且到期策略每天只运行一次。
+func TestNotePolicyService_DeleteByTag(t *testing.T) {
+	svc, repo, notes := newNotePolicySvc(t)
+	ctx := context.Background()
+
+	saved, err := svc.Save(ctx, 1, &dto.NotePolicyRequest{Vault: "MyVault", Folder: "Inbox", Tag: "#temp", OlderThanDays: 30, Action: "delete", ArchiveFolder: "ignored", IsEnabled: true})
+	require.NoError(t, err)
+	assert.Equal(t, "temp", saved.Tag)
+	assert.Empty(t, saved.ArchiveFolder)
+
+	require.NoError(t, svc.ExecuteScheduled(ctx))
+	assert.Equal(t, []string{"Inbox/scratch.md"}, notes.deleted)
+	require.Len(t, repo.runs, 1)
+	assert.Equal(t, domain.NotePolicyTriggerSchedule, repo.runs[0].Trigger)
+
+	require.NoError(t, svc.ExecuteScheduled(ctx))
+	assert.Len(t, repo.runs, 1, "ran within the last day")
+}
+
+// TestNotePolicyService_SaveInvalid verifies policies that cannot run are rejected.
+// TestNotePolicyService_SaveInvalid 验证无法运行的策略被拒绝。
+func TestNotePolicyService_SaveInvalid(t *testing.T) {
+	svc, _, _ := newNotePolicySvc(t)
+	ctx := context.Background()
+
+	_, err := svc.Save(ctx, 1, &dto.NotePolicyRequest{Vault: "MyVault", OlderThanDays: 30, Action: "archive"})
+	assert.ErrorIs(t, err, code.ErrorNotePolicyInvalid)
+
+	_, err = svc.Save(ctx, 1, &dto.NotePolicyRequest{Vault: "MyVault", Folder: "Archive", OlderThanDays: 30, Action: "archive", ArchiveFolder: "Archive"})
+	assert.ErrorIs(t, err, code.ErrorNotePolicyInvalid)
+
+	_, err = svc.Save(ctx, 1, &dto.NotePolicyRequest{ID: 9, Vault: "MyVault", OlderThanDays: 30, Action: "delete"})
+	assert.ErrorIs(t, err, code.ErrorNotePolicyNotFound)
+}
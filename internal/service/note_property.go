@@ -0,0 +1,87 @@
+package service
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+)
+
+// notePropertiesOf parses the frontmatter of a note into index properties, one per list element;
+// a note without frontmatter has none
+// notePropertiesOf 将笔记的 frontmatter 解析为索引属性，列表的每个元素各为一条；没有 frontmatter 的笔记没有属性
+func notePropertiesOf(noteID int64, content string) []*domain.NoteProperty {
+	yamlData, _, ok := util.ParseFrontmatter(content)
+	if !ok {
+		return nil
+	}
+	var props []*domain.NoteProperty
+	for key, v := range yamlData {
+		list, isList := v.([]interface{})
+		if !isList {
+			p := notePropertyValue(v)
+			p.NoteID, p.Key = noteID, key
+			props = append(props, p)
+			continue
+		}
+		// An empty list still marks the key as present
+		// 空列表同样表示该键存在
+		if len(list) == 0 {
+			props = append(props, &domain.NoteProperty{NoteID: noteID, Key: key, Type: domain.NotePropertyTypeNull, InList: true})
+		}
+		for _, item := range list {
+			p := notePropertyValue(item)
+			p.NoteID, p.Key, p.InList = noteID, key, true
+			props = append(props, p)
+		}
+	}
+	return props
+}
+
+// notePropertyValue returns the typed text, numeric and date forms of a YAML value;
+// strings holding a date are dates, nested values are kept as JSON text
+// notePropertyValue 返回 YAML 值带类型的文本、数值与日期形式；内容为日期的字符串视为日期，嵌套值保存为 JSON 文本
+func notePropertyValue(v interface{}) *domain.NoteProperty {
+	p := &domain.NoteProperty{Type: domain.NotePropertyTypeString}
+	switch val := v.(type) {
+	case nil:
+		p.Type = domain.NotePropertyTypeNull
+		return p
+	case string:
+		p.Text = val
+	case bool:
+		p.Type, p.Text = domain.NotePropertyTypeBool, strconv.FormatBool(val)
+		return p
+	case int:
+		return numberProperty(float64(val), strconv.Itoa(val))
+	case int64:
+		return numberProperty(float64(val), strconv.FormatInt(val, 10))
+	case uint64:
+		return numberProperty(float64(val), strconv.FormatUint(val, 10))
+	case float64:
+		return numberProperty(val, strconv.FormatFloat(val, 'g', -1, 64))
+	case time.Time:
+		if _, allDay, _, _ := parseCalendarDate(val); allDay {
+			p.Text = val.Format("2006-01-02")
+		} else {
+			p.Text = val.Format(time.RFC3339)
+		}
+	default:
+		raw, _ := json.Marshal(val)
+		p.Type, p.Text = domain.NotePropertyTypeObject, string(raw)
+		return p
+	}
+	if d, _, _, ok := parseCalendarDate(v); ok {
+		ms := d.UnixMilli()
+		p.Type, p.Time = domain.NotePropertyTypeDate, &ms
+	}
+	return p
+}
+
+// numberProperty returns a number property
+// numberProperty 返回数字属性
+func numberProperty(f float64, text string) *domain.NoteProperty {
+	return &domain.NoteProperty{Type: domain.NotePropertyTypeNumber, Text: text, Number: &f}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNotePropertiesOf verifies frontmatter values are indexed with their type, lists give one
+// property per element and notes without frontmatter give none.
+// TestNotePropertiesOf 验证 frontmatter 值按类型索引、列表的每个元素各为一条属性，且没有 frontmatter 的笔记没有属性。
+func TestNotePropertiesOf(t *testing.T) {
+	content := "---\ntitle: Plan\nrating: 4.5\ncount: 3\ndone: true\ndue: 2026-03-01\nstart: \"2026-03-01T09:30\"\ntags: [a, 2]\naliases: []\nowner:\nextra:\n  k: v\n---\nbody"
+	props := notePropertiesOf(9, content)
+	sort.SliceStable(props, func(i, j int) bool { return props[i].Key < props[j].Key })
+
+	type shape struct {
+		key    string
+		typ    domain.NotePropertyType
+		text   string
+		inList bool
+	}
+	var got []shape
+	for _, p := range props {
+		assert.Equal(t, int64(9), p.NoteID)
+		got = append(got, shape{p.Key, p.Type, p.Text, p.InList})
+		switch p.Type {
+		case domain.NotePropertyTypeNumber:
+			require.NotNil(t, p.Number)
+		case domain.NotePropertyTypeDate:
+			require.NotNil(t, p.Time)
+		}
+	}
+	assert.Equal(t, []shape{
+		{"aliases", domain.NotePropertyTypeNull, "", true},
+		{"count", domain.NotePropertyTypeNumber, "3", false},
+		{"done", domain.NotePropertyTypeBool, "true", false},
+		{"due", domain.NotePropertyTypeDate, "2026-03-01", false},
+		{"extra", domain.NotePropertyTypeObject, `{"k":"v"}`, false},
+		{"owner", domain.NotePropertyTypeNull, "", false},
+		{"rating", domain.NotePropertyTypeNumber, "4.5", false},
+		{"start", domain.NotePropertyTypeDate, "2026-03-01T09:30", false},
+		{"tags", domain.NotePropertyTypeString, "a", true},
+		{"tags", domain.NotePropertyTypeNumber, "2", true},
+		{"title", domain.NotePropertyTypeString, "Plan", false},
+	}, got)
+
+	assert.Empty(t, notePropertiesOf(9, "no frontmatter"))
+}
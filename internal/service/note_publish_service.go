@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+// notePublishTimeLayouts layouts accepted for publish_at besides RFC 3339, read in the server's time zone
+// notePublishTimeLayouts 除 RFC 3339 外 publish_at 支持的格式，按服务器时区解析
+var notePublishTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// NotePublishService defines the scheduled note publishing business service interface.
+// A note is published through a share once its frontmatter sets publish: true and publish_at, if any, has passed.
+// NotePublishService 定义笔记定时发布业务服务接口。
+// 笔记 frontmatter 设置 publish: true 且 publish_at（如有）已到达时，通过分享发布该笔记。
+type NotePublishService interface {
+	// List retrieves the publishing states of a vault with pagination
+	// List 分页查询仓库的发布状态
+	List(ctx context.Context, uid int64, params *dto.NotePublishListRequest, page, pageSize int) ([]*dto.NotePublishDTO, int64, error)
+
+	// ExecuteScheduled picks up frontmatter changes of every user and publishes the due notes
+	// ExecuteScheduled 扫描所有用户的 frontmatter 变更并发布到期的笔记
+	ExecuteScheduled(ctx context.Context) error
+}
+
+// notePublishService implements NotePublishService
+// notePublishService 实现 NotePublishService 接口
+type notePublishService struct {
+	repo         domain.NotePublishRepository
+	noteRepo     domain.NoteRepository
+	vaultService VaultService
+	shareService ShareService
+	logger       *zap.Logger
+
+	mu         sync.Mutex       // Serializes scheduled runs // 串行化定时运行
+	watermarks map[string]int64 // "uid:vaultID" -> newest UpdatedTimestamp scanned // "uid:vaultID" -> 已扫描的最新 UpdatedTimestamp
+}
+
+// NewNotePublishService creates a NotePublishService instance
+// NewNotePublishService 创建 NotePublishService 实例
+func NewNotePublishService(repo domain.NotePublishRepository, noteRepo domain.NoteRepository, vaultService VaultService, shareService ShareService, logger *zap.Logger) NotePublishService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &notePublishService{
+		repo:         repo,
+		noteRepo:     noteRepo,
+		vaultService: vaultService,
+		shareService: shareService,
+		logger:       logger,
+		watermarks:   make(map[string]int64),
+	}
+}
+
+// notePublishToDTO converts the domain model to the DTO
+// notePublishToDTO 将领域模型转换为 DTO
+func notePublishToDTO(p *domain.NotePublish, vault string) *dto.NotePublishDTO {
+	return &dto.NotePublishDTO{
+		ID:          p.ID,
+		Vault:       vault,
+		Path:        p.Path,
+		PathHash:    p.PathHash,
+		Status:      string(p.Status),
+		PublishAt:   timex.Time(p.PublishAt),
+		PublishedAt: timex.Time(p.PublishedAt),
+		ShareID:     p.ShareID,
+		ShareToken:  p.ShareToken,
+		Message:     p.Message,
+		UpdatedAt:   timex.Time(p.UpdatedAt),
+	}
+}
+
+// notePublishSchedule reads the publish and publish_at keys of a note's frontmatter.
+// A missing publish_at publishes at once, a zero time is returned for it.
+// notePublishSchedule 读取笔记 frontmatter 中的 publish 与 publish_at 键，
+// 未设置 publish_at 时立即发布，返回零值时间。
+func notePublishSchedule(content string, loc *time.Location) (publish bool, at time.Time, err error) {
+	fm, _, ok := util.ParseFrontmatter(content)
+	if !ok {
+		return false, time.Time{}, nil
+	}
+	switch v := fm["publish"].(type) {
+	case bool:
+		publish = v
+	case string:
+		publish, _ = strconv.ParseBool(strings.TrimSpace(v))
+		publish = publish || strings.EqualFold(strings.TrimSpace(v), "yes")
+	}
+	if !publish {
+		return false, time.Time{}, nil
+	}
+
+	switch v := fm["publish_at"].(type) {
+	case nil:
+		return true, time.Time{}, nil
+	case time.Time:
+		// yaml decodes unquoted timestamps; a bare date or a time without zone comes back as UTC
+		// yaml 会解析未加引号的时间戳；仅日期或不带时区的时间被解析为 UTC
+		return true, v, nil
+	case string:
+		s := strings.TrimSpace(v)
+		if s == "" {
+			return true, time.Time{}, nil
+		}
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return true, t, nil
+		}
+		for _, layout := range notePublishTimeLayouts {
+			if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+				return true, t, nil
+			}
+		}
+		return true, time.Time{}, fmt.Errorf("invalid publish_at %q", s)
+	default:
+		return true, time.Time{}, fmt.Errorf("invalid publish_at %v", v)
+	}
+}
+
+// List retrieves the publishing states of a vault with pagination
+// List 分页查询仓库的发布状态
+func (s *notePublishService) List(ctx context.Context, uid int64, params *dto.NotePublishListRequest, page, pageSize int) ([]*dto.NotePublishDTO, int64, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, 0, err
+	}
+	status := domain.NotePublishStatus(params.Status)
+	if status != "" && !status.IsValid() {
+		return nil, 0, code.ErrorInvalidParams.WithDetails("unknown status " + params.Status)
+	}
+
+	list, total, err := s.repo.List(ctx, vaultID, status, uid, page, pageSize)
+	if err != nil {
+		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	results := make([]*dto.NotePublishDTO, 0, len(list))
+	for _, p := range list {
+		results = append(results, notePublishToDTO(p, params.Vault))
+	}
+	return results, total, nil
+}
+
+// scan updates the publishing states from the notes changed since the last scan of the vault.
+// The first scan after a restart reads the whole vault.
+// scan 根据仓库上次扫描后变更的笔记更新发布状态，重启后的首次扫描读取整个仓库。
+func (s *notePublishService) scan(ctx context.Context, uid int64, vault *dto.VaultDTO) error {
+	key := strconv.FormatInt(uid, 10) + ":" + strconv.FormatInt(vault.ID, 10)
+	since := s.watermarks[key]
+	notes, err := s.noteRepo.ListByUpdatedTimestamp(ctx, since, vault.ID, uid)
+	if err != nil {
+		return err
+	}
+
+	newest := since
+	for _, n := range notes {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if n.UpdatedTimestamp > newest {
+			newest = n.UpdatedTimestamp
+		}
+		if err := s.apply(ctx, uid, n); err != nil {
+			s.logger.Warn("note publish: update state failed", zap.Int64("uid", uid), zap.String("path", n.Path), zap.Error(err))
+		}
+	}
+	s.watermarks[key] = newest
+	return nil
+}
+
+// apply updates the publishing state of one changed note
+// apply 更新单篇变更笔记的发布状态
+func (s *notePublishService) apply(ctx context.Context, uid int64, n *domain.Note) error {
+	current, err := s.repo.GetByPathHash(ctx, n.VaultID, n.PathHash, uid)
+	if err != nil {
+		return err
+	}
+
+	var publish bool
+	var at time.Time
+	var parseErr error
+	if !n.IsDeleted() {
+		publish, at, parseErr = notePublishSchedule(n.Content, time.Local)
+	}
+
+	if !publish {
+		if current == nil || current.Status == domain.NotePublishStatusUnpublished {
+			return nil
+		}
+		if current.Status == domain.NotePublishStatusPublished && current.ShareID != 0 {
+			if err := s.shareService.StopShare(ctx, uid, current.ShareID); err != nil {
+				return err
+			}
+		}
+		current.Status = domain.NotePublishStatusUnpublished
+		current.Message = ""
+		_, err := s.repo.Save(ctx, current, uid)
+		return err
+	}
+
+	if current == nil {
+		current = &domain.NotePublish{UID: uid, VaultID: n.VaultID, PathHash: n.PathHash}
+	}
+	current.Path = n.Path
+	current.NoteID = n.ID
+	current.PublishAt = at
+	current.Message = ""
+	switch {
+	case parseErr != nil:
+		current.Status = domain.NotePublishStatusFailed
+		current.Message = parseErr.Error()
+	case current.Status != domain.NotePublishStatusPublished:
+		// An edit of a published note keeps its share, the share always serves the latest content
+		// 编辑已发布的笔记保留其分享，分享始终提供最新内容
+		current.Status = domain.NotePublishStatusScheduled
+	}
+	_, err = s.repo.Save(ctx, current, uid)
+	return err
+}
+
+// publish creates the share of a due note and records the outcome
+// publish 为到期笔记创建分享并记录结果
+func (s *notePublishService) publish(ctx context.Context, uid int64, vaultName string, p *domain.NotePublish) error {
+	share, err := s.shareService.ShareGenerate(ctx, uid, vaultName, p.Path, p.PathHash, "", 0)
+	if err == nil {
+		var us *domain.UserShare
+		us, err = s.shareService.GetShareByPath(ctx, uid, vaultName, p.PathHash)
+		if err == nil && us == nil {
+			err = fmt.Errorf("share of %s not found", p.Path)
+		}
+		if err == nil {
+			p.Status = domain.NotePublishStatusPublished
+			p.PublishedAt = time.Now()
+			p.ShareID = us.ID
+			p.ShareToken = share.Token
+			p.Message = ""
+		}
+	}
+	if err != nil {
+		p.Status = domain.NotePublishStatusFailed
+		p.Message = err.Error()
+	}
+	_, saveErr := s.repo.Save(ctx, p, uid)
+	if err != nil {
+		return err
+	}
+	return saveErr
+}
+
+// ExecuteScheduled picks up frontmatter changes of every user and publishes the due notes.
+// A failing note is logged and retried once it changes again.
+// ExecuteScheduled 扫描所有用户的 frontmatter 变更并发布到期的笔记。失败的笔记仅记录日志，再次变更后重试。
+func (s *notePublishService) ExecuteScheduled(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uids, err := s.repo.ListUIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, uid := range uids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		vaults, err := s.vaultService.List(ctx, uid)
+		if err != nil {
+			s.logger.Warn("note publish: list vaults failed", zap.Int64("uid", uid), zap.Error(err))
+			continue
+		}
+		names := make(map[int64]string, len(vaults))
+		for _, v := range vaults {
+			names[v.ID] = v.Name
+			if err := s.scan(ctx, uid, v); err != nil {
+				s.logger.Warn("note publish: scan failed", zap.Int64("uid", uid), zap.String("vault", v.Name), zap.Error(err))
+			}
+		}
+
+		due, err := s.repo.ListDue(ctx, time.Now(), uid)
+		if err != nil {
+			s.logger.Warn("note publish: list due notes failed", zap.Int64("uid", uid), zap.Error(err))
+			continue
+		}
+		for _, p := range due {
+			name, ok := names[p.VaultID]
+			if !ok {
+				continue
+			}
+			if err := s.publish(ctx, uid, name, p); err != nil {
+				s.logger.Warn("note publish: publish failed", zap.Int64("uid", uid), zap.String("path", p.Path), zap.Error(err))
+				continue
+			}
+			s.logger.Info("note publish: note published", zap.Int64("uid", uid), zap.String("vault", name), zap.String("path", p.Path))
+		}
+	}
+	return nil
+}
+
+// Ensure notePublishService implements NotePublishService
+// 确保 notePublishService 实现了 NotePublishService 接口
+var _ NotePublishService = (*notePublishService)(nil)
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeNotePublishRepo in-memory domain.NotePublishRepository
+type fakeNotePublishRepo struct {
+	states map[string]*domain.NotePublish
+}
+
+func (r *fakeNotePublishRepo) ListUIDs(ctx context.Context) ([]int64, error) {
+	return []int64{1}, nil
+}
+
+func (r *fakeNotePublishRepo) GetByPathHash(ctx context.Context, vaultID int64, pathHash string, uid int64) (*domain.NotePublish, error) {
+	if p, ok := r.states[pathHash]; ok {
+		copied := *p
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeNotePublishRepo) Save(ctx context.Context, p *domain.NotePublish, uid int64) (*domain.NotePublish, error) {
+	if p.ID == 0 {
+		p.ID = int64(len(r.states) + 1)
+	}
+	copied := *p
+	r.states[p.PathHash] = &copied
+	return p, nil
+}
+
+func (r *fakeNotePublishRepo) ListDue(ctx context.Context, now time.Time, uid int64) ([]*domain.NotePublish, error) {
+	var list []*domain.NotePublish
+	for _, p := range r.states {
+		if p.Status == domain.NotePublishStatusScheduled && !p.PublishAt.After(now) {
+			copied := *p
+			list = append(list, &copied)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeNotePublishRepo) List(ctx context.Context, vaultID int64, status domain.NotePublishStatus, uid int64, page, pageSize int) ([]*domain.NotePublish, int64, error) {
+	var list []*domain.NotePublish
+	for _, p := range r.states {
+		if status == "" || p.Status == status {
+			list = append(list, p)
+		}
+	}
+	return list, int64(len(list)), nil
+}
+
+// fakePublishShareService records the shares created and revoked by the scheduler.
+// service/mocks imports service, so the ShareService mock cannot be used here.
+type fakePublishShareService struct {
+	ShareService
+	active  map[string]int64
+	stopped []int64
+	nextID  int64
+}
+
+func (f *fakePublishShareService) ShareGenerate(ctx context.Context, uid int64, vaultName string, path string, pathHash string, password string, expireAt int64) (*dto.ShareCreateResponse, error) {
+	f.nextID++
+	f.active[pathHash] = f.nextID
+	return &dto.ShareCreateResponse{Type: "note", Token: "token-" + path}, nil
+}
+
+func (f *fakePublishShareService) GetShareByPath(ctx context.Context, uid int64, vaultName string, pathHash string) (*domain.UserShare, error) {
+	return &domain.UserShare{ID: f.active[pathHash]}, nil
+}
+
+func (f *fakePublishShareService) StopShare(ctx context.Context, uid int64, id int64) error {
+	f.stopped = append(f.stopped, id)
+	return nil
+}
+
+// TestNotePublishSchedule verifies the publish and publish_at frontmatter keys are read.
+// TestNotePublishSchedule 验证 publish 与 publish_at frontmatter 键的解析。
+func TestNotePublishSchedule(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	cases := []struct {
+		content string
+		publish bool
+		at      time.Time
+		invalid bool
+	}{
+		{content: "# No frontmatter"},
+		{content: "---\npublish: false\n---\nbody"},
+		{content: "---\npublish: true\n---\nbody", publish: true},
+		{content: "---\npublish: \"yes\"\npublish_at: \"2030-01-02 08:30\"\n---\n", publish: true, at: time.Date(2030, 1, 2, 8, 30, 0, 0, loc)},
+		{content: "---\npublish: true\npublish_at: 2030-01-02T08:30:00+02:00\n---\n", publish: true, at: time.Date(2030, 1, 2, 6, 30, 0, 0, time.UTC)},
+		{content: "---\npublish: true\npublish_at: next week\n---\n", publish: true, invalid: true},
+	}
+	for _, tc := range cases {
+		publish, at, err := notePublishSchedule(tc.content, loc)
+		assert.Equal(t, tc.publish, publish, tc.content)
+		assert.Equal(t, tc.invalid, err != nil, tc.content)
+		assert.True(t, tc.at.Equal(at), "%s: got %v", tc.content, at)
+	}
+}
+
+// TestNotePublishService_ExecuteScheduled verifies due notes are shared, future ones wait and unpublished ones are revoked.
+// TestNotePublishService_ExecuteScheduled 验证到期笔记被分享、未到期笔记等待、取消发布的笔记被撤销分享。
+func TestNotePublishService_ExecuteScheduled(t *testing.T) {
+	vaultRepo := new(domainmocks.MockVaultRepository)
+	noteRepo := new(domainmocks.MockNoteRepository)
+	vaultRepo.On("List", mock.Anything, int64(1)).Return([]*domain.Vault{newVault(7, "MyVault")}, nil)
+	vaultRepo.On("GetByName", mock.Anything, "MyVault", int64(1)).Return(newVault(7, "MyVault"), nil)
+
+	noteRepo.On("ListByUpdatedTimestamp", mock.Anything, int64(0), int64(7), int64(1)).Return([]*domain.Note{
+		{ID: 1, VaultID: 7, Path: "now.md", PathHash: "h1", Action: domain.NoteActionCreate, UpdatedTimestamp: 10, Content: "---\npublish: true\n---\nNow"},
+		{ID: 2, VaultID: 7, Path: "later.md", PathHash: "h2", Action: domain.NoteActionCreate, UpdatedTimestamp: 11, Content: "---\npublish: true\npublish_at: \"2999-01-01\"\n---\nLater"},
+		{ID: 3, VaultID: 7, Path: "draft.md", PathHash: "h3", Action: domain.NoteActionCreate, UpdatedTimestamp: 12, Content: "Draft"},
+	}, nil).Once()
+	noteRepo.On("ListByUpdatedTimestamp", mock.Anything, int64(12), int64(7), int64(1)).Return([]*domain.Note{
+		{ID: 1, VaultID: 7, Path: "now.md", PathHash: "h1", Action: domain.NoteActionModify, UpdatedTimestamp: 20, Content: "---\npublish: false\n---\nNow"},
+	}, nil).Once()
+
+	repo := &fakeNotePublishRepo{states: map[string]*domain.NotePublish{}}
+	shares := &fakePublishShareService{active: map[string]int64{}}
+	vaultSvc := NewVaultService(vaultRepo, noteRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	svc := NewNotePublishService(repo, noteRepo, vaultSvc, shares, zap.NewNop())
+	ctx := context.Background()
+
+	require.NoError(t, svc.ExecuteScheduled(ctx))
+	require.Len(t, repo.states, 2, "a note without publish gets no state")
+	assert.Equal(t, domain.NotePublishStatusPublished, repo.states["h1"].Status)
+	assert.Equal(t, int64(1), repo.states["h1"].ShareID)
+	assert.Equal(t, "token-now.md", repo.states["h1"].ShareToken)
+	assert.Equal(t, domain.NotePublishStatusScheduled, repo.states["h2"].Status)
+
+	list, total, err := svc.List(ctx, 1, &dto.NotePublishListRequest{Vault: "MyVault", Status: "scheduled"}, 1, 20)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, "later.md", list[0].Path)
+
+	require.NoError(t, svc.ExecuteScheduled(ctx))
+	assert.Equal(t, domain.NotePublishStatusUnpublished, repo.states["h1"].Status)
+	assert.Equal(t, []int64{1}, shares.stopped)
+	noteRepo.AssertExpectations(t)
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenamedLinkForms(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"Plan":          "Roadmap",
+		"Projects/Plan": "Archive/Roadmap",
+	}, renamedLinkForms("Projects/Plan.md", "Archive/Roadmap.md"))
+
+	// Moving keeps the name, so only links with a folder change
+	assert.Equal(t, map[string]string{"Projects/Plan": "Archive/Plan"}, renamedLinkForms("Projects/Plan.md", "Archive/Plan.md"))
+}
+
+func TestRenamedLinkPattern(t *testing.T) {
+	rewrite := func(content, form, replacement string) string {
+		find, replace := renamedLinkPattern(form, replacement)
+		return regexp.MustCompile(find).ReplaceAllString(content, replace)
+	}
+
+	content := "[[Plan]] [[Plan|the plan]] ![[Plan#Goals]] [[Plan.md]] [[Planning]] `Plan`"
+	assert.Equal(t, "[[Road $1]] [[Road $1|the plan]] ![[Road $1#Goals]] [[Road $1.md]] [[Planning]] `Plan`",
+		rewrite(content, "Plan", "Road $1"))
+	assert.Equal(t, "[[Archive/Roadmap]] [[Plan]]", rewrite("[[Projects/Plan]] [[Plan]]", "Projects/Plan", "Archive/Roadmap"))
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/markdown"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// NoteRenderService defines the note Markdown rendering service interface
+// NoteRenderService 定义笔记 Markdown 渲染服务接口
+type NoteRenderService interface {
+	// Render renders a stored note, or the unsaved content of the request, to sanitized HTML
+	// Render 将已存储的笔记或请求中的未保存内容渲染为经过清洗的 HTML
+	Render(ctx context.Context, uid int64, params *dto.NoteRenderRequest) (*dto.NoteRenderDTO, error)
+}
+
+// noteRenderService implements NoteRenderService
+// noteRenderService 实现 NoteRenderService 接口
+type noteRenderService struct {
+	noteRepo        domain.NoteRepository
+	vaultService    VaultService
+	fileService     FileService
+	noteLinkService NoteLinkService
+	logger          *zap.Logger
+}
+
+// NewNoteRenderService creates a NoteRenderService instance
+// NewNoteRenderService 创建 NoteRenderService 实例
+func NewNoteRenderService(noteRepo domain.NoteRepository, vaultSvc VaultService, fileSvc FileService, noteLinkSvc NoteLinkService, logger *zap.Logger) NoteRenderService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &noteRenderService{
+		noteRepo:        noteRepo,
+		vaultService:    vaultSvc,
+		fileService:     fileSvc,
+		noteLinkService: noteLinkSvc,
+		logger:          logger,
+	}
+}
+
+// noteRenderResolver points wiki links at note paths and embeds at the file API
+// noteRenderResolver 将维基链接指向笔记路径，将嵌入指向文件接口
+type noteRenderResolver struct {
+	vault     string
+	noteLinks map[string]string
+	fileLinks map[string]string
+}
+
+// ResolveLink returns the note path, escaped per segment, clients map it to their own note route
+// ResolveLink 返回按路径段转义的笔记路径，由客户端映射到各自的笔记路由
+func (r *noteRenderResolver) ResolveLink(target string) (string, bool) {
+	notePath, ok := r.noteLinks[target]
+	if !ok {
+		return target, false
+	}
+	segments := strings.Split(notePath, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/"), true
+}
+
+// ResolveEmbed returns the /api/file URL of the attachment
+// ResolveEmbed 返回附件的 /api/file 地址
+func (r *noteRenderResolver) ResolveEmbed(target string) (string, bool) {
+	filePath, ok := r.fileLinks[target]
+	if !ok {
+		return target, false
+	}
+	return "/api/file?" + url.Values{"vault": {r.vault}, "path": {filePath}}.Encode(), true
+}
+
+// Render renders a stored note, or the unsaved content of the request, to sanitized HTML
+// Render 将已存储的笔记或请求中的未保存内容渲染为经过清洗的 HTML
+func (s *noteRenderService) Render(ctx context.Context, uid int64, params *dto.NoteRenderRequest) (*dto.NoteRenderDTO, error) {
+	result := &dto.NoteRenderDTO{Path: params.Path}
+
+	var content string
+	if params.Content != nil {
+		content = *params.Content
+	} else {
+		vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+		if err != nil {
+			return nil, err
+		}
+		pathHash := params.PathHash
+		if pathHash == "" {
+			pathHash = util.EncodeHash32(params.Path)
+		}
+		note, err := s.noteRepo.GetByPathHash(ctx, pathHash, vaultID, uid)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, code.ErrorNoteNotFound
+			}
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		if note.IsDeleted() {
+			return nil, code.ErrorNoteNotFound
+		}
+		content, result.Path = note.Content, note.Path
+	}
+
+	frontmatter, body, _ := util.ParseFrontmatter(content)
+	result.Frontmatter = frontmatter
+
+	fileLinks, err := s.fileService.ResolveEmbedLinks(ctx, uid, params.Vault, result.Path, body)
+	if err != nil {
+		return nil, err
+	}
+	noteLinks, err := s.noteLinkService.ResolveLinks(ctx, uid, params.Vault, result.Path, body)
+	if err != nil {
+		return nil, err
+	}
+	result.FileLinks, result.NoteLinks = fileLinks, noteLinks
+
+	html, err := markdown.Render([]byte(body), &noteRenderResolver{vault: params.Vault, noteLinks: noteLinks, fileLinks: fileLinks})
+	if err != nil {
+		s.logger.Warn("NoteRenderService.Render: render failed", zap.Int64("uid", uid), zap.String("path", result.Path), zap.Error(err))
+		return nil, code.ErrorNoteRenderFailed.WithDetails(err.Error())
+	}
+	result.HTML = html
+	return result, nil
+}
+
+var _ NoteRenderService = (*noteRenderService)(nil)
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeRenderFileService resolves every embed to an attachment in the Assets folder
+// fakeRenderFileService 将所有嵌入解析为 Assets 目录中的附件
+type fakeRenderFileService struct {
+	FileService
+}
+
+func (s *fakeRenderFileService) ResolveEmbedLinks(ctx context.Context, uid int64, vaultName string, notePath string, content string) (map[string]string, error) {
+	return map[string]string{"pic.png": "Assets/pic.png"}, nil
+}
+
+func newTestNoteRenderService(t *testing.T) NoteRenderService {
+	t.Helper()
+	noteRepo := new(domainmocks.MockNoteRepository)
+	noteRepo.On("GetByPathHash", mock.Anything, util.EncodeHash32("Daily/today.md"), int64(7), int64(1)).
+		Return(&domain.Note{ID: 3, VaultID: 7, Path: "Daily/today.md", Content: "---\ntags: [a]\n---\n# Today\n\nSee [[Other]], [[Missing]] and ![[pic.png]]\n"}, nil)
+	noteRepo.On("GetByPathLike", mock.Anything, "/Other.md", int64(7), int64(1)).
+		Return(&domain.Note{ID: 4, VaultID: 7, Path: "Projects/My Other.md"}, nil)
+	noteRepo.On("GetByPathHash", mock.Anything, mock.Anything, int64(7), int64(1)).Return(nil, gorm.ErrRecordNotFound)
+	noteRepo.On("GetByPathLike", mock.Anything, mock.Anything, int64(7), int64(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	vaultSvc := &fakeVaultServiceForConflictTest{vaultID: 7}
+	linkSvc := NewNoteLinkService(nil, noteRepo, vaultSvc)
+	return NewNoteRenderService(noteRepo, vaultSvc, &fakeRenderFileService{}, linkSvc, zap.NewNop())
+}
+
+// TestNoteRenderService_Render verifies stored notes render with resolved links and embeds.
+// TestNoteRenderService_Render 验证已存储笔记渲染时解析链接与嵌入。
+func TestNoteRenderService_Render(t *testing.T) {
+	svc := newTestNoteRenderService(t)
+
+	res, err := svc.Render(context.Background(), 1, &dto.NoteRenderRequest{Vault: "v", Path: "Daily/today.md"})
+	require.NoError(t, err)
+	assert.Equal(t, "Daily/today.md", res.Path)
+	assert.Equal(t, []interface{}{"a"}, res.Frontmatter["tags"])
+	assert.NotContains(t, res.HTML, "tags:")
+	assert.Contains(t, res.HTML, `<h1 id="today">Today</h1>`)
+	assert.Equal(t, map[string]string{"Other": "Projects/My Other.md"}, res.NoteLinks)
+	assert.Contains(t, res.HTML, `<a class="internal-link" href="Projects/My%20Other.md">Other</a>`)
+	assert.Contains(t, res.HTML, `<a class="internal-link is-unresolved" href="Missing">Missing</a>`)
+	assert.Contains(t, res.HTML, `<img class="internal-embed" src="/api/file?path=Assets%2Fpic.png&amp;vault=v" alt="pic.png">`)
+}
+
+// TestNoteRenderService_Preview verifies unsaved content is rendered instead of the stored note.
+// TestNoteRenderService_Preview 验证渲染未保存内容而非已存储笔记。
+func TestNoteRenderService_Preview(t *testing.T) {
+	svc := newTestNoteRenderService(t)
+	content := "**draft**"
+
+	res, err := svc.Render(context.Background(), 1, &dto.NoteRenderRequest{Vault: "v", Path: "New.md", Content: &content})
+	require.NoError(t, err)
+	assert.Equal(t, "New.md", res.Path)
+	assert.Equal(t, "<p><strong>draft</strong></p>\n", res.HTML)
+
+	_, err = svc.Render(context.Background(), 1, &dto.NoteRenderRequest{Vault: "v", Path: "Gone.md"})
+	assert.Equal(t, code.ErrorNoteNotFound, err)
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -18,7 +19,9 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/pkg/keyedmutex"
 	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/tracer"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
@@ -112,6 +115,10 @@ type NoteService interface {
 	// WithClient 设置客户端信息
 	WithClient(clientType, name, version string) NoteService
 
+	// SetSecretScan sets the secret scanner whose strict mode guards every content write
+	// SetSecretScan 设置敏感凭据扫描器，其严格模式约束所有正文写入
+	SetSecretScan(secretScan SecretScanService)
+
 	// PatchFrontmatter patches note frontmatter
 	// PatchFrontmatter 修改笔记 Frontmatter
 	PatchFrontmatter(ctx context.Context, uid int64, params *dto.NotePatchFrontmatterRequest) (*dto.NoteDTO, error)
@@ -124,14 +131,39 @@ type NoteService interface {
 	// PrependContent 在笔记开头插入内容
 	PrependContent(ctx context.Context, uid int64, params *dto.NotePrependRequest) (*dto.NoteDTO, error)
 
+	// AppendToHeading inserts content under a heading of a note, adding the heading when missing
+	// AppendToHeading 在笔记的指定标题下插入内容，标题不存在时添加该标题
+	AppendToHeading(ctx context.Context, uid int64, params *dto.NoteAppendToHeadingRequest) (*dto.NoteAppendToHeadingResponse, error)
+
 	// ReplaceContent performs find/replace in a note
 	// ReplaceContent 在笔记中执行替换
 	ReplaceContent(ctx context.Context, uid int64, params *dto.NoteReplaceRequest) (*dto.NoteReplaceResponse, error)
 
+	// Duplicate creates a copy of a note; fileLinks maps attachment references in the content
+	// to copied attachment paths so that the copy embeds the copies
+	// Duplicate 复制笔记；fileLinks 将内容中的附件引用映射到复制后的附件路径，使副本嵌入复制的附件
+	Duplicate(ctx context.Context, uid int64, params *dto.NoteDuplicateRequest, fileLinks map[string]string) (*dto.NoteDTO, error)
+
+	// IndexNote queues the folder binding, vault counts and link, property and word count indexing of a written note
+	// IndexNote 将已写入笔记的文件夹绑定、笔记库统计以及链接、属性与字数索引加入后台任务队列
+	IndexNote(uid, vaultID, noteID int64, path, content string)
+
 	// UpdateNoteLinks extracts wiki links from content and updates the link index
 	// UpdateNoteLinks 从内容中提取 Wiki 链接并更新链接索引
 	UpdateNoteLinks(ctx context.Context, noteID int64, content string, vaultID, uid int64)
 
+	// UpdateNoteProperties parses the frontmatter of content and updates the property index
+	// UpdateNoteProperties 解析内容的 frontmatter 并更新属性索引
+	UpdateNoteProperties(ctx context.Context, noteID int64, content string, vaultID, uid int64)
+
+	// UpdateNoteStats counts the words of content, stores them for the note and adds the change to today's writing activity
+	// UpdateNoteStats 统计内容字数并保存到笔记，同时将变化计入当天的写作活动
+	UpdateNoteStats(ctx context.Context, noteID int64, path, content string, vaultID, uid int64)
+
+	// UpdateRenamedLinks rewrites the wiki links pointing at oldPath in other notes to point at newPath
+	// UpdateRenamedLinks 将其他笔记中指向 oldPath 的 Wiki 链接改写为指向 newPath
+	UpdateRenamedLinks(ctx context.Context, uid int64, vaultName string, oldPath string, newPath string) ([]*dto.NoteDTO, error)
+
 	// RecycleClear cleans up the recycle bin
 	// RecycleClear 清理回收站
 	RecycleClear(ctx context.Context, uid int64, params *dto.NoteRecycleClearRequest) error
@@ -148,32 +180,39 @@ type NoteService interface {
 // noteService implementation of NoteService interface
 // noteService 实现 NoteService 接口
 type noteService struct {
-	userRepo       domain.UserRepository      // User repository // 用户仓库
-	noteRepo       domain.NoteRepository      // Note repository // 笔记仓库
-	noteLinkRepo   domain.NoteLinkRepository  // Note link repository // 笔记链接仓库
-	fileRepo       domain.FileRepository      // File repository // 文件仓库
-	shareRepo      domain.UserShareRepository // Share repository for auto-revoke on delete // 分享仓库（删除时自动撤销）
-	vaultService   VaultService               // Vault service // 仓库服务
-	folderService  FolderService              // Folder service // 文件夹服务
-	syncLogService SyncLogService             // Sync log service // 同步日志服务
-	sf             *singleflight.Group        // Singleflight group // 并发请求合并组
-	kmu            *keyedmutex.KeyedMutex     // Per-key mutex for write paths that must not share results across callers // 用于写路径的按 key 互斥锁，避免调用方之间共享结果
-	clientType     string                     // Client type // 客户端类型
-	clientName     string                     // Client name // 客户端名称
-	clientVer      string                     // Client version // 客户端版本
-	config         *ServiceConfig             // Service configuration // 服务配置
-	backupService  BackupService              // Backup service // 备份服务
-	gitSyncService GitSyncService             // Git sync service // Git 同步服务
-	countTimers    *sync.Map                  // Timers for CountSizeSum debounce // CountSizeSum 防抖计时器
+	userRepo       domain.UserRepository         // User repository // 用户仓库
+	noteRepo       domain.NoteRepository         // Note repository // 笔记仓库
+	noteLinkRepo   domain.NoteLinkRepository     // Note link repository // 笔记链接仓库
+	propertyRepo   domain.NotePropertyRepository // Frontmatter property index // frontmatter 属性索引
+	statRepo       domain.NoteStatRepository     // Word count statistics // 字数统计
+	fileRepo       domain.FileRepository         // File repository // 文件仓库
+	shareRepo      domain.UserShareRepository    // Share repository for auto-revoke on delete // 分享仓库（删除时自动撤销）
+	vaultService   VaultService                  // Vault service // 仓库服务
+	folderService  FolderService                 // Folder service // 文件夹服务
+	syncLogService SyncLogService                // Sync log service // 同步日志服务
+	retention      RetentionService              // Soft delete retention overrides // 软删除保留时间覆盖
+	sf             *singleflight.Group           // Singleflight group // 并发请求合并组
+	kmu            *keyedmutex.KeyedMutex        // Per-key mutex for write paths that must not share results across callers // 用于写路径的按 key 互斥锁，避免调用方之间共享结果
+	clientType     string                        // Client type // 客户端类型
+	clientName     string                        // Client name // 客户端名称
+	clientVer      string                        // Client version // 客户端版本
+	config         *ServiceConfig                // Service configuration // 服务配置
+	backupService  BackupService                 // Backup service // 备份服务
+	gitSyncService GitSyncService                // Git sync service // Git 同步服务
+	notifier       Notifier                      // Webhook notifier, may be nil // Webhook 通知器，可为 nil
+	secretScan     SecretScanService             // Strict secret scanning of written content, may be nil // 写入内容的严格凭据扫描，可为 nil
+	countTimers    *sync.Map                     // Timers for CountSizeSum debounce // CountSizeSum 防抖计时器
 }
 
 // NewNoteService creates NoteService instance
 // NewNoteService 创建 NoteService 实例
-func NewNoteService(userRepo domain.UserRepository, noteRepo domain.NoteRepository, noteLinkRepo domain.NoteLinkRepository, fileRepo domain.FileRepository, shareRepo domain.UserShareRepository, vaultSvc VaultService, folderSvc FolderService, backupSvc BackupService, gitSyncSvc GitSyncService, syncLogSvc SyncLogService, config *ServiceConfig) NoteService {
+func NewNoteService(userRepo domain.UserRepository, noteRepo domain.NoteRepository, noteLinkRepo domain.NoteLinkRepository, propertyRepo domain.NotePropertyRepository, statRepo domain.NoteStatRepository, fileRepo domain.FileRepository, shareRepo domain.UserShareRepository, vaultSvc VaultService, folderSvc FolderService, backupSvc BackupService, gitSyncSvc GitSyncService, syncLogSvc SyncLogService, retentionSvc RetentionService, notifier Notifier, config *ServiceConfig) NoteService {
 	return &noteService{
 		userRepo:       userRepo,
 		noteRepo:       noteRepo,
 		noteLinkRepo:   noteLinkRepo,
+		propertyRepo:   propertyRepo,
+		statRepo:       statRepo,
 		fileRepo:       fileRepo,
 		shareRepo:      shareRepo,
 		vaultService:   vaultSvc,
@@ -181,6 +220,8 @@ func NewNoteService(userRepo domain.UserRepository, noteRepo domain.NoteReposito
 		backupService:  backupSvc,
 		gitSyncService: gitSyncSvc,
 		syncLogService: syncLogSvc,
+		retention:      retentionSvc,
+		notifier:       notifier,
 		sf:             &singleflight.Group{},
 		kmu:            keyedmutex.New(),
 		config:         config,
@@ -194,11 +235,14 @@ func (s *noteService) WithClient(clientType, name, version string) NoteService {
 	return &noteService{
 		noteRepo:       s.noteRepo,
 		noteLinkRepo:   s.noteLinkRepo,
+		propertyRepo:   s.propertyRepo,
+		statRepo:       s.statRepo,
 		fileRepo:       s.fileRepo,
 		shareRepo:      s.shareRepo,
 		vaultService:   s.vaultService,
 		folderService:  s.folderService,
 		syncLogService: s.syncLogService,
+		retention:      s.retention,
 		sf:             s.sf,
 		kmu:            s.kmu,
 		clientType:     clientType,
@@ -207,10 +251,17 @@ func (s *noteService) WithClient(clientType, name, version string) NoteService {
 		config:         s.config,
 		backupService:  s.backupService,
 		gitSyncService: s.gitSyncService,
+		notifier:       s.notifier,
+		secretScan:     s.secretScan,
 		countTimers:    s.countTimers, // Share the same timer map // 共享同一个计时器 map
 	}
 }
 
+// SetSecretScan implements NoteService
+func (s *noteService) SetSecretScan(secretScan SecretScanService) {
+	s.secretScan = secretScan
+}
+
 // domainToDTO converts domain model to DTO
 // domainToDTO 将领域模型转换为 DTO
 func (s *noteService) domainToDTO(note *domain.Note) *dto.NoteDTO {
@@ -294,6 +345,7 @@ func (s *noteService) UpdateCheck(ctx context.Context, uid int64, params *dto.No
 
 	note, _ := s.noteRepo.GetAllByPathHash(ctx, params.PathHash, vaultID, uid)
 	mode, noteDTO, err := s.evalUpdateCheck(ctx, uid, note, params)
+	s.traceUpdateCheck(uid, note, params, mode, err)
 	return mode, noteDTO, err
 }
 
@@ -310,6 +362,7 @@ func (s *noteService) UpdateCheckWithNote(ctx context.Context, uid int64, params
 
 	note, _ := s.noteRepo.GetAllByPathHash(ctx, params.PathHash, vaultID, uid)
 	mode, noteDTO, err := s.evalUpdateCheck(ctx, uid, note, params)
+	s.traceUpdateCheck(uid, note, params, mode, err)
 	return mode, note, noteDTO, err
 }
 
@@ -349,11 +402,73 @@ func (s *noteService) evalUpdateCheck(ctx context.Context, uid int64, note *doma
 	return "Create", nil, nil
 }
 
+// IndexNote queues the background jobs that follow a note write: folder binding, vault counts, and the
+// link, property and word count indexes. Jobs of the same note still waiting run once with the latest content.
+// IndexNote 将笔记写入后的后台任务排队：文件夹绑定、笔记库统计，以及链接、属性与字数索引。同一笔记仍在等待的任务以最新内容只执行一次。
+func (s *noteService) IndexNote(uid, vaultID, noteID int64, path, content string) {
+	key := strconv.FormatInt(noteID, 10)
+	s.syncResourceFID(uid, vaultID, noteID)
+	s.CountSizeSum(context.Background(), vaultID, uid)
+	s.config.enqueue("note.links", key, uid, func(ctx context.Context) error {
+		return s.replaceNoteLinks(ctx, noteID, content, vaultID, uid)
+	})
+	s.config.enqueue("note.properties", key, uid, func(ctx context.Context) error {
+		return s.replaceNoteProperties(ctx, noteID, content, vaultID, uid)
+	})
+	// Every edit counts towards the writing activity, so word counts are never merged
+	// 每次编辑都计入写作活动，因此字数统计不合并
+	s.config.enqueue("note.stats", "", uid, func(ctx context.Context) error {
+		return s.saveNoteStats(ctx, noteID, path, content, vaultID, uid)
+	})
+}
+
+// syncResourceFID queues binding a note to the folder of its path
+// syncResourceFID 将笔记绑定到其路径所在文件夹的任务排队
+func (s *noteService) syncResourceFID(uid, vaultID, noteID int64) {
+	s.config.enqueue("note.resource_fid", strconv.FormatInt(noteID, 10), uid, func(ctx context.Context) error {
+		return s.folderService.SyncResourceFID(ctx, uid, vaultID, []int64{noteID}, nil)
+	})
+}
+
+// traceUpdateCheck records the UpdateCheck decision when the user is traced
+// traceUpdateCheck 用户被跟踪时记录 UpdateCheck 判定
+func (s *noteService) traceUpdateCheck(uid int64, note *domain.Note, params *dto.NoteUpdateCheckRequest, mode string, err error) {
+	trace := s.config.syncTrace(uid)
+	if trace == nil {
+		return
+	}
+	var record *updateCheckRecord
+	if note != nil {
+		record = &updateCheckRecord{ID: note.ID, ContentHash: note.ContentHash, Mtime: note.Mtime, Action: string(note.Action)}
+	}
+	traceUpdateCheck(trace, "NoteUpdateCheck", params.Vault, params.Path, params.ContentHash, params.Mtime, record, mode, err)
+}
+
+// limits returns the configured content limits, nil when none are configured
+// limits 返回配置的内容限制，未配置时为 nil
+func (s *noteService) limits() *ContentLimits {
+	if s.config == nil {
+		return nil
+	}
+	return s.config.App.Limits
+}
+
 // ModifyOrCreate creates or modifies a note. existingNote is an optional already-fetched
 // note (e.g. from UpdateCheckWithNote for the same pathHash) to reuse instead of querying again.
 // ModifyOrCreate 创建或修改笔记。existingNote 为可选的已查到的 note（例如来自同一 pathHash 的
 // UpdateCheckWithNote），复用以避免重复查询。
 func (s *noteService) ModifyOrCreate(ctx context.Context, uid int64, params *dto.NoteModifyOrCreateRequest, mtimeCheck bool, existingNote ...*domain.Note) (bool, *dto.NoteDTO, error) {
+	if err := s.limits().CheckNoteSize(int64(len(params.Content))); err != nil {
+		return false, nil, err
+	}
+	// Every content write passes here, so strict secret scanning cannot be bypassed by any write path
+	// 所有正文写入都经过此处，任何写入路径都无法绕过严格凭据扫描
+	if s.secretScan != nil {
+		if err := s.secretScan.Guard(ctx, uid, params.Path, params.Content); err != nil {
+			return false, nil, err
+		}
+	}
+
 	// Use VaultService.MustGetID to retrieve VaultID
 	// 使用 VaultService.MustGetID 获取 VaultID
 	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
@@ -361,6 +476,10 @@ func (s *noteService) ModifyOrCreate(ctx context.Context, uid int64, params *dto
 		return false, nil, err
 	}
 
+	if err := s.folderService.CheckWritable(ctx, uid, vaultID, params.Path); err != nil {
+		return false, nil, err
+	}
+
 	var preFetchedNote *domain.Note
 	if len(existingNote) > 0 {
 		preFetchedNote = existingNote[0]
@@ -396,6 +515,20 @@ func (s *noteService) ModifyOrCreate(ctx context.Context, uid int64, params *dto
 			note, _ = s.noteRepo.GetAllByPathHash(ctx, params.PathHash, vaultID, uid)
 		}
 
+		// Creating a note or restoring a deleted one adds to the vault's note count
+		// 新建笔记或恢复已删除的笔记会增加笔记库的笔记数量
+		if note == nil || note.Action == domain.NoteActionDelete {
+			if err := s.limits().CheckNoteCount(func() (int64, error) {
+				result, err := s.noteRepo.CountSizeSum(ctx, vaultID, uid)
+				if err != nil {
+					return 0, err
+				}
+				return result.Count, nil
+			}); err != nil {
+				return nil, err
+			}
+		}
+
 		if note != nil {
 			isNew = false
 
@@ -457,9 +590,7 @@ func (s *noteService) ModifyOrCreate(ctx context.Context, uid int64, params *dto
 				s.syncLogService.Log(uid, vaultID, domain.SyncLogTypeNote, domain.SyncLogActionModify, "content,mtime", updated.Path, updated.PathHash, s.clientType, s.clientName, s.clientVer, updated.Size)
 			}
 
-			go s.folderService.SyncResourceFID(context.Background(), uid, vaultID, []int64{updated.ID}, nil)
-			go s.CountSizeSum(context.Background(), vaultID, uid)
-			go s.UpdateNoteLinks(context.Background(), updated.ID, params.Content, vaultID, uid)
+			s.IndexNote(uid, vaultID, updated.ID, updated.Path, params.Content)
 			NoteHistoryDelayPush(updated.ID, uid)
 
 			if s.backupService != nil {
@@ -499,9 +630,7 @@ func (s *noteService) ModifyOrCreate(ctx context.Context, uid int64, params *dto
 			s.syncLogService.Log(uid, vaultID, domain.SyncLogTypeNote, domain.SyncLogActionCreate, "", created.Path, created.PathHash, s.clientType, s.clientName, s.clientVer, created.Size)
 		}
 
-		go s.folderService.SyncResourceFID(context.Background(), uid, vaultID, []int64{created.ID}, nil)
-		go s.CountSizeSum(context.Background(), vaultID, uid)
-		go s.UpdateNoteLinks(context.Background(), created.ID, params.Content, vaultID, uid)
+		s.IndexNote(uid, vaultID, created.ID, created.Path, params.Content)
 		NoteHistoryDelayPush(created.ID, uid)
 		if s.backupService != nil {
 			go s.backupService.NotifyUpdated(uid)
@@ -510,6 +639,7 @@ func (s *noteService) ModifyOrCreate(ctx context.Context, uid int64, params *dto
 		if s.gitSyncService != nil {
 			go s.gitSyncService.NotifyUpdated(uid, vaultID)
 		}
+		s.notify(uid, domain.WebhookEventNoteCreated, params.Vault, created)
 
 		return &result{isNew: isNew, dto: s.domainToDTO(created)}, nil
 	}
@@ -540,6 +670,10 @@ func (s *noteService) Delete(ctx context.Context, uid int64, params *dto.NoteDel
 		return nil, code.ErrorDBQuery.WithDetails(err.Error())
 	}
 
+	if err := s.folderService.CheckWritable(ctx, uid, vaultID, note.Path); err != nil {
+		return nil, err
+	}
+
 	// Update to deleted status // 更新为删除状态
 	note.Action = domain.NoteActionDelete
 	note.ClientName = s.clientName
@@ -578,10 +712,27 @@ func (s *noteService) Delete(ctx context.Context, uid int64, params *dto.NoteDel
 	if s.gitSyncService != nil {
 		go s.gitSyncService.NotifyUpdated(uid, vaultID)
 	}
+	s.notify(uid, domain.WebhookEventNoteDeleted, params.Vault, note)
 
 	return s.domainToDTO(note), nil
 }
 
+// notify reports a note event to the user's webhooks
+// notify 将笔记事件通知到用户的 Webhook
+func (s *noteService) notify(uid int64, event domain.WebhookEvent, vault string, note *domain.Note) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.Notify(uid, event, map[string]any{
+		"vault":      vault,
+		"path":       note.Path,
+		"pathHash":   note.PathHash,
+		"size":       note.Size,
+		"clientType": s.clientType,
+		"clientName": s.clientName,
+	})
+}
+
 // Restore restores a note (from recycle bin)
 // Restore 恢复笔记（从回收站恢复）
 func (s *noteService) Restore(ctx context.Context, uid int64, params *dto.NoteRestoreRequest) (*dto.NoteDTO, error) {
@@ -605,6 +756,10 @@ func (s *noteService) Restore(ctx context.Context, uid int64, params *dto.NoteRe
 		return nil, code.ErrorNoteNotFound
 	}
 
+	if err := s.folderService.CheckWritable(ctx, uid, vaultID, note.Path); err != nil {
+		return nil, err
+	}
+
 	// Update to modified status and update modification time // 更新为修改状态 并更新修改时间
 	note.Action = domain.NoteActionModify
 	note.ClientName = s.clientName
@@ -630,9 +785,7 @@ func (s *noteService) Restore(ctx context.Context, uid int64, params *dto.NoteRe
 		return nil, code.ErrorDBQuery.WithDetails(err.Error())
 	}
 
-	go s.folderService.SyncResourceFID(context.Background(), uid, vaultID, []int64{updated.ID}, nil)
-	go s.CountSizeSum(context.Background(), vaultID, uid)
-	go s.UpdateNoteLinks(context.Background(), updated.ID, updated.Content, vaultID, uid)
+	s.IndexNote(uid, vaultID, updated.ID, updated.Path, updated.Content)
 
 	NoteHistoryDelayPush(updated.ID, uid)
 	if s.backupService != nil {
@@ -659,6 +812,10 @@ func (s *noteService) Rename(ctx context.Context, uid int64, params *dto.NoteRen
 		newPathHash = util.EncodeHash32(newPath)
 	}
 
+	if err := s.folderService.CheckWritable(ctx, uid, vaultID, params.OldPath, newPath); err != nil {
+		return nil, nil, err
+	}
+
 	key := fmt.Sprintf("rename_%d_%d_%s_%s", uid, vaultID, params.OldPathHash, newPathHash)
 	type result struct {
 		oldNote *dto.NoteDTO
@@ -761,7 +918,7 @@ func (s *noteService) Rename(ctx context.Context, uid int64, params *dto.NoteRen
 			s.syncLogService.Log(uid, vaultID, domain.SyncLogTypeNote, domain.SyncLogActionRename, "path", newNoteCreated.Path, newNoteCreated.PathHash, s.clientType, s.clientName, s.clientVer, newNoteCreated.Size)
 		}
 
-		go s.folderService.SyncResourceFID(context.Background(), uid, vaultID, []int64{newNoteCreated.ID}, nil)
+		s.syncResourceFID(uid, vaultID, newNoteCreated.ID)
 		if err := s.folderService.CleanupEmptyAncestors(ctx, uid, vaultID, oldPath); err != nil {
 			zap.L().Warn("noteService.Rename: cleanup empty ancestor folders failed",
 				zap.Int64("uid", uid),
@@ -770,7 +927,10 @@ func (s *noteService) Rename(ctx context.Context, uid int64, params *dto.NoteRen
 				zap.Error(err),
 			)
 		}
-		go s.Migrate(context.Background(), n.ID, newNoteCreated.ID, uid)
+		oldNoteID, newNoteID := n.ID, newNoteCreated.ID
+		s.config.enqueue("note.migrate", "", uid, func(ctx context.Context) error {
+			return s.Migrate(ctx, oldNoteID, newNoteID, uid)
+		})
 		if s.backupService != nil {
 			go s.backupService.NotifyUpdated(uid)
 		}
@@ -792,6 +952,12 @@ func (s *noteService) Rename(ctx context.Context, uid int64, params *dto.NoteRen
 // List retrieves note list
 // List 获取笔记列表
 func (s *noteService) List(ctx context.Context, uid int64, params *dto.NoteListRequest, pager *app.Pager) ([]*dto.NoteNoContentDTO, int, error) {
+	ctx, span := tracer.Start(ctx, "NoteService.List", tracer.UID(uid), tracer.Vault(params.Vault),
+		attribute.Bool("note.keyword", params.Keyword != ""),
+		attribute.String("note.search_mode", params.SearchMode),
+	)
+	defer span.End()
+
 	// Use VaultService.MustGetID to retrieve VaultID
 	// 使用 VaultService.MustGetID 获取 VaultID
 	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
@@ -810,24 +976,131 @@ func (s *noteService) List(ctx context.Context, uid int64, params *dto.NoteListR
 		}
 	}
 
-	notes, err := s.noteRepo.List(ctx, vaultID, pager.Page, pager.PageSize, uid, params.Keyword, params.IsRecycle, params.SearchMode, params.SearchContent, params.SortBy, params.SortOrder, paths)
+	// Keyword searches skip archived folders unless explicitly requested
+	// 关键词搜索默认跳过归档文件夹，除非显式要求包含
+	var excludePrefixes []string
+	if params.Keyword != "" && !params.IncludeArchived {
+		excludePrefixes, err = s.folderService.ArchivedPaths(ctx, uid, vaultID)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if params.SearchMode == "regex" && params.Keyword != "" && len(paths) == 0 {
+		return s.listByContentRegex(ctx, uid, vaultID, params, pager, excludePrefixes)
+	}
+
+	notes, err := s.noteRepo.List(ctx, vaultID, pager.Page, pager.PageSize, uid, params.Keyword, params.IsRecycle, params.SearchMode, params.SearchContent, params.SortBy, params.SortOrder, paths, excludePrefixes)
 	if err != nil {
 		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
 	}
 
-	count, err := s.noteRepo.ListCount(ctx, vaultID, uid, params.Keyword, params.IsRecycle, params.SearchMode, params.SearchContent, paths)
+	count, err := s.noteRepo.ListCount(ctx, vaultID, uid, params.Keyword, params.IsRecycle, params.SearchMode, params.SearchContent, paths, excludePrefixes)
 	if err != nil {
 		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
 	}
 
 	var result []*dto.NoteNoContentDTO
 	for _, n := range notes {
-		result = append(result, s.domainToNoContentDTO(n))
+		item := s.domainToNoContentDTO(n)
+		if params.Highlight && params.Keyword != "" {
+			item.Snippet = noteSearchSnippet(n, params.Keyword)
+		}
+		result = append(result, item)
 	}
 
 	return result, int(count), nil
 }
 
+// Limits for regex content search: notes above the size are skipped, and the scan stops at the
+// result cap or time budget
+// 正则正文搜索的限制：超过该大小的笔记跳过，扫描达到结果上限或时间预算时停止
+const (
+	noteRegexMaxFileSize = 2 << 20
+	noteRegexMaxResults  = 1000
+	noteRegexTimeout     = 10 * time.Second
+)
+
+// listByContentRegex runs a regex search over note content and pages the matches in memory
+// listByContentRegex 对笔记正文执行正则搜索，并在内存中对命中结果分页
+func (s *noteService) listByContentRegex(ctx context.Context, uid, vaultID int64, params *dto.NoteListRequest, pager *app.Pager, excludePrefixes []string) ([]*dto.NoteNoContentDTO, int, error) {
+	re, err := regexp.Compile(params.Keyword)
+	if err != nil {
+		return nil, 0, code.ErrorInvalidRegex.WithDetails(err.Error())
+	}
+
+	matches, truncated, err := s.noteRepo.ListByContentRegex(ctx, vaultID, uid, &domain.NoteRegexQuery{
+		Pattern:         re,
+		IsRecycle:       params.IsRecycle,
+		SortBy:          params.SortBy,
+		SortOrder:       params.SortOrder,
+		ExcludePrefixes: excludePrefixes,
+		MaxFileSize:     noteRegexMaxFileSize,
+		MaxResults:      noteRegexMaxResults,
+		Timeout:         noteRegexTimeout,
+	})
+	if err != nil {
+		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if truncated {
+		zap.L().Warn("regex note search stopped early",
+			zap.Int64(logger.FieldUID, uid),
+			zap.Int64("vaultId", vaultID),
+			zap.Int("matches", len(matches)),
+			zap.String(logger.FieldMethod, "NoteService.List"),
+		)
+	}
+
+	start := min(app.GetPageOffset(pager.Page, pager.PageSize), len(matches))
+	end := min(start+pager.PageSize, len(matches))
+
+	var result []*dto.NoteNoContentDTO
+	for _, m := range matches[start:end] {
+		item := s.domainToNoContentDTO(m.Note)
+		item.MatchCount = m.MatchCount
+		if params.Highlight {
+			if text, ranges, ok := util.RegexSnippet(m.Note.Content, re, noteSnippetRadius); ok {
+				item.Snippet = newNoteSearchSnippet("content", text, ranges)
+			}
+		}
+		result = append(result, item)
+	}
+
+	return result, len(matches), nil
+}
+
+// noteSnippetRadius is the number of characters kept on each side of a search match
+// noteSnippetRadius 是搜索匹配两侧各保留的字符数
+const noteSnippetRadius = 60
+
+// noteSearchSnippet builds the match context for a keyword search hit, preferring content over path
+// noteSearchSnippet 为关键词搜索结果构建匹配上下文，优先使用正文，其次为路径
+func noteSearchSnippet(note *domain.Note, keyword string) *dto.NoteSearchSnippet {
+	for _, field := range []struct{ name, text string }{{"content", note.Content}, {"path", note.Path}} {
+		text, ranges, ok := util.SearchSnippet(field.text, keyword, noteSnippetRadius)
+		if !ok {
+			continue
+		}
+		return newNoteSearchSnippet(field.name, text, ranges)
+	}
+	return nil
+}
+
+// newNoteSearchSnippet converts an extracted snippet into its DTO
+// newNoteSearchSnippet 将提取出的片段转换为 DTO
+func newNoteSearchSnippet(field, text string, ranges []util.SnippetRange) *dto.NoteSearchSnippet {
+	matches := make([]dto.NoteSnippetRange, 0, len(ranges))
+	for _, r := range ranges {
+		matches = append(matches, dto.NoteSnippetRange{Start: r.Start, End: r.End})
+	}
+	return &dto.NoteSearchSnippet{
+		Field:   field,
+		Text:    text,
+		HTML:    util.HighlightSnippet(text, ranges),
+		Matches: matches,
+	}
+}
+
 // ListByLastTime retrieves notes updated after lastTime
 // ListByLastTime 获取在 lastTime 之后更新的笔记
 func (s *noteService) ListByLastTime(ctx context.Context, uid int64, params *dto.NoteSyncRequest) ([]*dto.NoteDTO, error) {
@@ -912,14 +1185,14 @@ func (s *noteService) CountSizeSum(ctx context.Context, vaultID int64, uid int64
 	timer := time.AfterFunc(10*time.Second, func() {
 		defer s.countTimers.Delete(key)
 
-		// Use singleflight to ensure only one actual DB query runs for same key even if debounce period ends simultaneously
-		// 使用 singleflight 确保即使防抖期同时结束，同一 key 也只有一个真实的 DB 查询
-		s.sf.Do(key, func() (any, error) {
-			result, err := s.noteRepo.CountSizeSum(context.Background(), vaultID, uid)
+		// The job queue merges counts of the same vault that are still waiting
+		// 任务队列会合并同一笔记库仍在等待的统计
+		s.config.enqueue("vault.note_count", key, uid, func(ctx context.Context) error {
+			result, err := s.noteRepo.CountSizeSum(ctx, vaultID, uid)
 			if err != nil {
-				return nil, code.ErrorDBQuery.WithDetails(err.Error())
+				return code.ErrorDBQuery.WithDetails(err.Error())
 			}
-			return nil, s.vaultService.UpdateNoteStats(context.Background(), result.Size, result.Count, vaultID, uid)
+			return s.vaultService.UpdateNoteStats(ctx, result.Size, result.Count, vaultID, uid)
 		})
 	})
 
@@ -933,32 +1206,19 @@ func (s *noteService) Cleanup(ctx context.Context, uid int64) error {
 	if s.config == nil {
 		return nil
 	}
-	retentionTimeStr := s.config.App.SoftDeleteRetentionTime
-	if retentionTimeStr == "" || retentionTimeStr == "0" {
-		return nil
-	}
-
-	retentionDuration, err := util.ParseDuration(retentionTimeStr)
+	cutoffTime, err := softDeleteCutoff(s.config.App.SoftDeleteRetentionTime)
 	if err != nil {
 		return code.ErrorInvalidParams.WithDetails("invalid SoftDeleteRetentionTime")
 	}
-
-	if retentionDuration <= 0 {
-		return nil
-	}
-
-	cutoffTime := time.Now().Add(-retentionDuration).UnixMilli()
-	err = s.noteRepo.DeletePhysicalByTime(ctx, cutoffTime, uid)
-	if err != nil {
-		return code.ErrorDBQuery.WithDetails(err.Error())
-	}
-	return nil
+	// User and vault overrides apply even when the server default keeps deleted notes forever
+	// 即使服务器默认永久保留，用户与保险库的覆盖值仍然生效
+	return s.retention.Purge(ctx, uid, cutoffTime, s.noteRepo.DeletePhysicalByTimeInVaults)
 }
 
 // CleanupByTime cleans up expired soft-deleted notes for all users by cutoff time
 // CleanupByTime 按截止时间清理所有用户的过期软删除笔记
 func (s *noteService) CleanupByTime(ctx context.Context, cutoffTime int64) error {
-	return s.noteRepo.DeletePhysicalByTimeAll(ctx, cutoffTime)
+	return s.retention.PurgeAll(ctx, cutoffTime, s.noteRepo.DeletePhysicalByTimeInVaults)
 }
 
 // ListNeedSnapshot retrieves notes that need snapshot
@@ -1178,6 +1438,46 @@ func (s *noteService) PrependContent(ctx context.Context, uid int64, params *dto
 	return result, err
 }
 
+// AppendToHeading inserts content under a heading of a note, adding the heading at the end when missing
+// AppendToHeading 在笔记的指定标题下插入内容，标题不存在时将其添加到末尾
+func (s *noteService) AppendToHeading(ctx context.Context, uid int64, params *dto.NoteAppendToHeadingRequest) (*dto.NoteAppendToHeadingResponse, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.PathHash == "" {
+		params.PathHash = util.EncodeHash32(params.Path)
+	}
+
+	note, err := s.noteRepo.GetByPathHash(ctx, params.PathHash, vaultID, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, code.ErrorNoteNotFound
+		}
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	newContent, created := util.InsertUnderMarkdownHeading(note.Content, params.Heading, params.Content, params.Position == "start")
+
+	// Save via ModifyOrCreate
+	modifyParams := &dto.NoteModifyOrCreateRequest{
+		Vault:       params.Vault,
+		Path:        params.Path,
+		PathHash:    params.PathHash,
+		Content:     newContent,
+		ContentHash: util.EncodeHash32(newContent),
+		Mtime:       time.Now().UnixMilli(),
+		Ctime:       note.Ctime,
+	}
+
+	_, result, err := s.ModifyOrCreate(ctx, uid, modifyParams, false)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.NoteAppendToHeadingResponse{Note: result, HeadingCreated: created}, nil
+}
+
 // ReplaceContent performs find/replace in a note
 // ReplaceContent 在笔记中执行查找/替换
 func (s *noteService) ReplaceContent(ctx context.Context, uid int64, params *dto.NoteReplaceRequest) (*dto.NoteReplaceResponse, error) {
@@ -1283,21 +1583,29 @@ func (s *noteService) ReplaceContent(ctx context.Context, uid int64, params *dto
 // UpdateNoteLinks extracts wiki links from content and updates the link index
 // UpdateNoteLinks 从内容中提取 Wiki 链接并更新链接索引
 func (s *noteService) UpdateNoteLinks(ctx context.Context, noteID int64, content string, vaultID, uid int64) {
+	_ = s.replaceNoteLinks(ctx, noteID, content, vaultID, uid)
+}
+
+// replaceNoteLinks replaces the link index of a note with the wiki links of content
+// replaceNoteLinks 用内容中的 Wiki 链接替换笔记的链接索引
+func (s *noteService) replaceNoteLinks(ctx context.Context, noteID int64, content string, vaultID, uid int64) error {
 	if s.noteLinkRepo == nil {
-		return
+		return nil
 	}
 
 	// Delete existing links for this note
 	// Delete existing links for this note
 	// 删除该笔记现有的链接
-	_ = s.noteLinkRepo.DeleteBySourceNoteID(ctx, noteID, uid)
+	if err := s.noteLinkRepo.DeleteBySourceNoteID(ctx, noteID, uid); err != nil {
+		return err
+	}
 
 	// Parse wiki links from content
 	// Parse wiki links from content
 	// 从内容中解析 Wiki 链接
 	links := util.ParseWikiLinks(content)
 	if len(links) == 0 {
-		return
+		return nil
 	}
 
 	// Create new link records
@@ -1315,7 +1623,194 @@ func (s *noteService) UpdateNoteLinks(ctx context.Context, noteID int64, content
 		})
 	}
 
-	_ = s.noteLinkRepo.CreateBatch(ctx, noteLinks, uid)
+	return s.noteLinkRepo.CreateBatch(ctx, noteLinks, uid)
+}
+
+// UpdateNoteProperties parses the frontmatter of content and updates the property index; the query API
+// catches up with notes written by other paths, so failures are only logged
+// UpdateNoteProperties 解析内容的 frontmatter 并更新属性索引；查询接口会补齐其他途径写入的笔记，因此失败时仅记录日志
+func (s *noteService) UpdateNoteProperties(ctx context.Context, noteID int64, content string, vaultID, uid int64) {
+	if err := s.replaceNoteProperties(ctx, noteID, content, vaultID, uid); err != nil {
+		zap.L().Warn("UpdateNoteProperties failed",
+			zap.Int64(logger.FieldUID, uid),
+			zap.Int64("noteId", noteID),
+			zap.String(logger.FieldMethod, "NoteService.UpdateNoteProperties"),
+			zap.Error(err),
+		)
+	}
+}
+
+// replaceNoteProperties replaces the property index of a note with the frontmatter of content
+// replaceNoteProperties 用内容的 frontmatter 替换笔记的属性索引
+func (s *noteService) replaceNoteProperties(ctx context.Context, noteID int64, content string, vaultID, uid int64) error {
+	if s.propertyRepo == nil {
+		return nil
+	}
+	return s.propertyRepo.ReplaceByNoteID(ctx, vaultID, noteID, notePropertiesOf(noteID, content), uid)
+}
+
+// UpdateNoteStats counts the words of content, stores them for the note and adds the change to today's
+// writing activity; the stats API catches up with notes written other ways, so failures are only logged
+// UpdateNoteStats 统计内容字数并保存到笔记，同时将变化计入当天的写作活动；统计接口会补齐其他途径写入的笔记，因此失败时仅记录日志
+func (s *noteService) UpdateNoteStats(ctx context.Context, noteID int64, path, content string, vaultID, uid int64) {
+	if err := s.saveNoteStats(ctx, noteID, path, content, vaultID, uid); err != nil {
+		zap.L().Warn("UpdateNoteStats failed",
+			zap.Int64(logger.FieldUID, uid),
+			zap.Int64("noteId", noteID),
+			zap.String(logger.FieldMethod, "NoteService.UpdateNoteStats"),
+			zap.Error(err),
+		)
+	}
+}
+
+// saveNoteStats stores the word count of content for the note and adds the change to today's writing activity
+// saveNoteStats 保存笔记内容的字数，并将变化计入当天的写作活动
+func (s *noteService) saveNoteStats(ctx context.Context, noteID int64, path, content string, vaultID, uid int64) error {
+	if s.statRepo == nil {
+		return nil
+	}
+	words, chars := util.CountWords(content)
+	previous, err := s.statRepo.Save(ctx, &domain.NoteStat{NoteID: noteID, VaultID: vaultID, Path: path, Words: words, Chars: chars}, uid)
+	if err == nil {
+		day := &domain.NoteStatDay{VaultID: vaultID, Day: time.Now().Format(time.DateOnly), Edits: 1}
+		delta := words
+		if previous != nil {
+			delta -= previous.Words
+		}
+		if delta > 0 {
+			day.WordsAdded = delta
+		} else {
+			day.WordsRemoved = -delta
+		}
+		err = s.statRepo.AddDay(ctx, day, uid)
+	}
+	return err
+}
+
+// UpdateRenamedLinks rewrites the wiki links pointing at oldPath in other notes to point at newPath.
+// Bare name links keep using the bare name, links with a folder get the full new path. A link form
+// that still matches another note is left alone. Notes are rewritten through ReplaceContent, so the
+// link index and history follow; failures are logged and skipped since the rename itself already succeeded.
+// UpdateRenamedLinks 将其他笔记中指向 oldPath 的 Wiki 链接改写为指向 newPath。
+// 仅含名称的链接继续使用名称，带目录的链接改为完整的新路径；仍能匹配其他笔记的链接形式保持不变。
+// 笔记通过 ReplaceContent 改写，链接索引与历史随之更新；由于重命名本身已成功，失败时仅记录日志并跳过。
+func (s *noteService) UpdateRenamedLinks(ctx context.Context, uid int64, vaultName string, oldPath string, newPath string) ([]*dto.NoteDTO, error) {
+	if s.noteLinkRepo == nil {
+		return nil, nil
+	}
+
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, vaultName)
+	if err != nil {
+		return nil, err
+	}
+
+	variations := util.GeneratePathVariations(oldPath)
+	if len(variations) == 0 {
+		return nil, nil
+	}
+	hashes := make([]string, 0, len(variations))
+	for _, variation := range variations {
+		hashes = append(hashes, util.EncodeHash32(variation))
+	}
+	links, err := s.noteLinkRepo.GetBacklinksByHashes(ctx, hashes, vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	forms := renamedLinkForms(oldPath, newPath)
+	for form := range forms {
+		if s.linkFormTaken(ctx, form, vaultID, uid) {
+			delete(forms, form)
+		}
+	}
+	if len(forms) == 0 {
+		return nil, nil
+	}
+
+	var updated []*dto.NoteDTO
+	for _, link := range links {
+		source, err := s.noteRepo.GetByID(ctx, link.SourceNoteID, uid)
+		if err != nil || source.IsDeleted() {
+			continue
+		}
+
+		var result *dto.NoteDTO
+		done := make(map[string]bool)
+		for _, wikiLink := range util.ParseWikiLinks(source.Content) {
+			form := wikiLink.Path
+			if i := strings.IndexByte(form, '#'); i >= 0 {
+				form = form[:i]
+			}
+			form = strings.TrimSuffix(form, ".md")
+			replacement, ok := forms[form]
+			if !ok || done[form] {
+				continue
+			}
+			done[form] = true
+
+			find, replace := renamedLinkPattern(form, replacement)
+			res, err := s.ReplaceContent(ctx, uid, &dto.NoteReplaceRequest{
+				Vault:    vaultName,
+				Path:     source.Path,
+				PathHash: source.PathHash,
+				Find:     find,
+				Replace:  replace,
+				Regex:    true,
+				All:      true,
+			})
+			if err != nil {
+				zap.L().Warn("noteService.UpdateRenamedLinks: rewrite links failed",
+					zap.Int64(logger.FieldUID, uid),
+					zap.String("path", source.Path),
+					zap.Error(err))
+				break
+			}
+			result = res.Note
+		}
+		if result != nil {
+			updated = append(updated, result)
+		}
+	}
+	return updated, nil
+}
+
+// renamedLinkForms maps each form a link to oldPath can take to its form for newPath.
+// The bare name stays a bare name, forms with a folder become the full new path.
+// renamedLinkForms 将指向 oldPath 的链接可能的各种形式映射为指向 newPath 的形式。
+// 仅含名称的形式仍为名称，带目录的形式改为完整的新路径。
+func renamedLinkForms(oldPath, newPath string) map[string]string {
+	newTarget := strings.TrimSuffix(newPath, ".md")
+	forms := make(map[string]string)
+	for i, variation := range util.GeneratePathVariations(oldPath) {
+		replacement := newTarget
+		if i == 0 {
+			replacement = newTarget[strings.LastIndex(newTarget, "/")+1:]
+		}
+		if replacement != variation {
+			forms[variation] = replacement
+		}
+	}
+	return forms
+}
+
+// renamedLinkPattern returns the ReplaceContent regex and replacement rewriting [[form]] links,
+// keeping the embed marker, an explicit .md, headings and aliases
+// renamedLinkPattern 返回改写 [[form]] 链接的 ReplaceContent 正则与替换内容，保留嵌入标记、显式 .md、标题与别名
+func renamedLinkPattern(form, replacement string) (string, string) {
+	return `(!?\[\[)` + regexp.QuoteMeta(form) + `(\.md)?([#|\]])`, "${1}" + strings.ReplaceAll(replacement, "$", "$$") + "${2}${3}"
+}
+
+// linkFormTaken reports whether a link form still resolves to an existing note
+// linkFormTaken 判断链接形式是否仍能解析到已存在的笔记
+func (s *noteService) linkFormTaken(ctx context.Context, form string, vaultID, uid int64) bool {
+	if note, err := s.noteRepo.GetByPathHash(ctx, util.EncodeHash32(form+".md"), vaultID, uid); err == nil && note != nil && !note.IsDeleted() {
+		return true
+	}
+	note, err := s.noteRepo.GetByPathLike(ctx, "/"+form+".md", vaultID, uid)
+	return err == nil && note != nil
 }
 
 // RecycleClear 清理回收站
@@ -1343,7 +1838,7 @@ func (s *noteService) RecycleClear(ctx context.Context, uid int64, params *dto.N
 	} else {
 		// Clear all: retrieve all notes in recycle bin (using a large page size)
 		// 清理全部：获取回收站中的所有笔记（使用较大的分页限制）
-		notesToDelete, _ = s.noteRepo.List(ctx, vaultID, 1, 10000, uid, "", true, "", false, "", "", nil)
+		notesToDelete, _ = s.noteRepo.List(ctx, vaultID, 1, 10000, uid, "", true, "", false, "", "", nil, nil)
 	}
 
 	err = s.noteRepo.RecycleClear(ctx, params.Path, params.PathHash, vaultID, uid)
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// statsDefaultTop longest notes listed when the request does not set a number
+	// statsDefaultTop 请求未指定时列出的最长笔记数量
+	statsDefaultTop = 10
+
+	// statsDefaultDays days of writing activity when the request does not set a number
+	// statsDefaultDays 请求未指定时的写作活动天数
+	statsDefaultDays = 30
+
+	// heatmapDefaultDays days of the heatmap when the request does not set a number
+	// heatmapDefaultDays 请求未指定时热力图的天数
+	heatmapDefaultDays = 365
+
+	// heatmapLevels intensity levels of an active day
+	// heatmapLevels 活跃日期的强度等级数
+	heatmapLevels = 4
+)
+
+// NoteStatsService defines the word count statistics service interface; counts are stored when notes are
+// written and caught up with the notes changed since the last query
+// NoteStatsService 定义字数统计服务接口；字数在写入笔记时保存，并在每次查询前补齐上次查询后变更的笔记
+type NoteStatsService interface {
+	// Stats returns the word counts of a vault: totals, per folder, the longest notes and the writing activity per day
+	// Stats 返回保险库的字数统计：合计、按文件夹统计、最长的笔记以及每日写作活动
+	Stats(ctx context.Context, uid int64, params *dto.VaultStatsRequest) (*dto.VaultStatsDTO, error)
+
+	// Heatmap returns the note edits per day across all vaults of the user with the writing streaks
+	// Heatmap 返回用户全部保险库每天的笔记编辑次数及连续写作天数
+	Heatmap(ctx context.Context, uid int64, params *dto.UserHeatmapRequest) (*dto.UserHeatmapDTO, error)
+}
+
+// noteStatsService implements NoteStatsService
+// noteStatsService 实现 NoteStatsService 接口
+type noteStatsService struct {
+	repo         domain.NoteStatRepository
+	noteRepo     domain.NoteRepository
+	vaultService VaultService
+	logger       *zap.Logger
+}
+
+// NewNoteStatsService creates a NoteStatsService instance
+// NewNoteStatsService 创建 NoteStatsService 实例
+func NewNoteStatsService(repo domain.NoteStatRepository, noteRepo domain.NoteRepository, vaultSvc VaultService, logger *zap.Logger) NoteStatsService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &noteStatsService{
+		repo:         repo,
+		noteRepo:     noteRepo,
+		vaultService: vaultSvc,
+		logger:       logger,
+	}
+}
+
+// Stats implements NoteStatsService
+// Stats 实现 NoteStatsService
+func (s *noteStatsService) Stats(ctx context.Context, uid int64, params *dto.VaultStatsRequest) (*dto.VaultStatsDTO, error) {
+	vaultID, err := s.vaultService.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refresh(ctx, uid, vaultID); err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	stats, err := s.repo.ListByVault(ctx, vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	days := params.Days
+	if days < 1 {
+		days = statsDefaultDays
+	}
+	today := time.Now()
+	from := today.AddDate(0, 0, -(days - 1)).Format(time.DateOnly)
+	activity, err := s.repo.ListDays(ctx, vaultID, from, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	top := params.Top
+	if top < 1 {
+		top = statsDefaultTop
+	}
+	result := &dto.VaultStatsDTO{
+		Folders:  statsByFolder(stats),
+		Longest:  longestNotes(stats, top),
+		Activity: statsActivity(activity, today, days),
+	}
+	for _, st := range stats {
+		result.Notes++
+		result.Words += st.Words
+		result.Chars += st.Chars
+	}
+	result.ReadingMinutes = util.ReadingMinutes(result.Words)
+	return result, nil
+}
+
+// refresh counts the words of the vault's notes changed since the last refresh; deleted notes leave the statistics
+// refresh 统计保险库中自上次刷新后变更的笔记的字数；已删除的笔记移出统计
+func (s *noteStatsService) refresh(ctx context.Context, uid, vaultID int64) error {
+	indexedUntil, err := s.repo.IndexedUntil(ctx, vaultID, uid)
+	if err != nil {
+		return err
+	}
+	notes, err := s.noteRepo.ListByUpdatedTimestamp(ctx, indexedUntil, vaultID, uid)
+	if err != nil || len(notes) == 0 {
+		return err
+	}
+
+	noteIDs := make([]int64, 0, len(notes))
+	var stats []*domain.NoteStat
+	for _, n := range notes {
+		noteIDs = append(noteIDs, n.ID)
+		indexedUntil = max(indexedUntil, n.UpdatedTimestamp)
+		if n.IsDeleted() {
+			continue
+		}
+		words, chars := util.CountWords(n.Content)
+		stats = append(stats, &domain.NoteStat{NoteID: n.ID, VaultID: vaultID, Path: n.Path, Words: words, Chars: chars})
+	}
+
+	s.logger.Debug("NoteStatsService.refresh",
+		zap.Int64("uid", uid),
+		zap.Int64("vaultId", vaultID),
+		zap.Int("notes", len(noteIDs)))
+	return s.repo.Reindex(ctx, vaultID, noteIDs, stats, indexedUntil, uid)
+}
+
+// statsByFolder sums the counts of the notes directly in each folder, most words first
+// statsByFolder 汇总直接位于各文件夹中的笔记的字数，字数多的排在前面
+func statsByFolder(stats []*domain.NoteStat) []*dto.VaultStatsFolderDTO {
+	byFolder := make(map[string]*dto.VaultStatsFolderDTO)
+	for _, st := range stats {
+		folder := path.Dir(st.Path)
+		if folder == "." {
+			folder = ""
+		}
+		f, ok := byFolder[folder]
+		if !ok {
+			f = &dto.VaultStatsFolderDTO{Folder: folder}
+			byFolder[folder] = f
+		}
+		f.Notes++
+		f.Words += st.Words
+		f.Chars += st.Chars
+	}
+
+	folders := make([]*dto.VaultStatsFolderDTO, 0, len(byFolder))
+	for _, f := range byFolder {
+		folders = append(folders, f)
+	}
+	sort.Slice(folders, func(i, j int) bool {
+		if folders[i].Words != folders[j].Words {
+			return folders[i].Words > folders[j].Words
+		}
+		return folders[i].Folder < folders[j].Folder
+	})
+	return folders
+}
+
+// longestNotes returns the top notes with the most words
+// longestNotes 返回字数最多的前 top 篇笔记
+func longestNotes(stats []*domain.NoteStat, top int) []*dto.VaultStatsNoteDTO {
+	sorted := make([]*domain.NoteStat, len(stats))
+	copy(sorted, stats)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Words != sorted[j].Words {
+			return sorted[i].Words > sorted[j].Words
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	notes := make([]*dto.VaultStatsNoteDTO, 0, min(top, len(sorted)))
+	for _, st := range sorted[:min(top, len(sorted))] {
+		notes = append(notes, &dto.VaultStatsNoteDTO{
+			Path:           st.Path,
+			PathHash:       util.EncodeHash32(st.Path),
+			Words:          st.Words,
+			Chars:          st.Chars,
+			ReadingMinutes: util.ReadingMinutes(st.Words),
+		})
+	}
+	return notes
+}
+
+// statsActivity lists the writing activity of the last days up to today, days without edits included
+// statsActivity 列出截至今天最近若干天的写作活动，包含没有编辑的日期
+func statsActivity(activity []*domain.NoteStatDay, today time.Time, days int) []*dto.VaultStatsDayDTO {
+	byDay := make(map[string]*domain.NoteStatDay, len(activity))
+	for _, d := range activity {
+		byDay[d.Day] = d
+	}
+
+	result := make([]*dto.VaultStatsDayDTO, 0, days)
+	for i := days - 1; i >= 0; i-- {
+		date := today.AddDate(0, 0, -i).Format(time.DateOnly)
+		day := &dto.VaultStatsDayDTO{Date: date}
+		if d, ok := byDay[date]; ok {
+			day.Edits = d.Edits
+			day.WordsAdded = d.WordsAdded
+			day.WordsRemoved = d.WordsRemoved
+		}
+		result = append(result, day)
+	}
+	return result
+}
+
+// Heatmap implements NoteStatsService
+// Heatmap 实现 NoteStatsService
+func (s *noteStatsService) Heatmap(ctx context.Context, uid int64, params *dto.UserHeatmapRequest) (*dto.UserHeatmapDTO, error) {
+	days := params.Days
+	if days < 1 {
+		days = heatmapDefaultDays
+	}
+	today := time.Now()
+	activity, err := s.repo.ListUserDays(ctx, today.AddDate(0, 0, -(days-1)).Format(time.DateOnly), uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return buildHeatmap(statsActivity(activity, today, days)), nil
+}
+
+// buildHeatmap turns the daily activity, oldest first and ending today, into heatmap cells and streaks
+// buildHeatmap 将按日期升序且截至今天的每日活动转换为热力图单元格与连续天数
+func buildHeatmap(activity []*dto.VaultStatsDayDTO) *dto.UserHeatmapDTO {
+	heatmap := &dto.UserHeatmapDTO{Days: make([]*dto.UserHeatmapDayDTO, 0, len(activity))}
+	run := 0
+	for _, d := range activity {
+		heatmap.Days = append(heatmap.Days, &dto.UserHeatmapDayDTO{Date: d.Date, Count: d.Edits})
+		heatmap.Total += d.Edits
+		heatmap.MaxCount = max(heatmap.MaxCount, d.Edits)
+		if d.Edits > 0 {
+			heatmap.ActiveDays++
+			run++
+			heatmap.LongestStreak = max(heatmap.LongestStreak, run)
+		} else {
+			run = 0
+		}
+	}
+
+	// A streak is still alive while today has no edit yet
+	// 今天尚无编辑时，连续记录仍然有效
+	for i := len(heatmap.Days) - 1; i >= 0; i-- {
+		if heatmap.Days[i].Count > 0 {
+			heatmap.CurrentStreak++
+		} else if i < len(heatmap.Days)-1 || heatmap.CurrentStreak > 0 {
+			break
+		}
+	}
+
+	for _, d := range heatmap.Days {
+		if d.Count > 0 {
+			d.Level = int((d.Count*heatmapLevels + heatmap.MaxCount - 1) / heatmap.MaxCount)
+		}
+	}
+	return heatmap
+}
+
+// Ensure noteStatsService implements NoteStatsService
+// 确保 noteStatsService 实现了 NoteStatsService 接口
+var _ NoteStatsService = (*noteStatsService)(nil)
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeNoteStatRepo in-memory domain.NoteStatRepository
+type fakeNoteStatRepo struct {
+	stats        map[int64]*domain.NoteStat
+	days         map[string]*domain.NoteStatDay
+	indexedUntil int64
+}
+
+func newFakeNoteStatRepo() *fakeNoteStatRepo {
+	return &fakeNoteStatRepo{stats: make(map[int64]*domain.NoteStat), days: make(map[string]*domain.NoteStatDay)}
+}
+
+func (r *fakeNoteStatRepo) Save(ctx context.Context, stat *domain.NoteStat, uid int64) (*domain.NoteStat, error) {
+	previous := r.stats[stat.NoteID]
+	r.stats[stat.NoteID] = stat
+	return previous, nil
+}
+
+func (r *fakeNoteStatRepo) AddDay(ctx context.Context, day *domain.NoteStatDay, uid int64) error {
+	d, ok := r.days[day.Day]
+	if !ok {
+		d = &domain.NoteStatDay{VaultID: day.VaultID, Day: day.Day}
+		r.days[day.Day] = d
+	}
+	d.Edits += day.Edits
+	d.WordsAdded += day.WordsAdded
+	d.WordsRemoved += day.WordsRemoved
+	return nil
+}
+
+func (r *fakeNoteStatRepo) IndexedUntil(ctx context.Context, vaultID, uid int64) (int64, error) {
+	return r.indexedUntil, nil
+}
+
+func (r *fakeNoteStatRepo) Reindex(ctx context.Context, vaultID int64, noteIDs []int64, stats []*domain.NoteStat, indexedUntil, uid int64) error {
+	for _, id := range noteIDs {
+		delete(r.stats, id)
+	}
+	for _, st := range stats {
+		r.stats[st.NoteID] = st
+	}
+	r.indexedUntil = indexedUntil
+	return nil
+}
+
+func (r *fakeNoteStatRepo) ListByVault(ctx context.Context, vaultID, uid int64) ([]*domain.NoteStat, error) {
+	stats := make([]*domain.NoteStat, 0, len(r.stats))
+	for _, st := range r.stats {
+		stats = append(stats, st)
+	}
+	return stats, nil
+}
+
+func (r *fakeNoteStatRepo) ListUserDays(ctx context.Context, from string, uid int64) ([]*domain.NoteStatDay, error) {
+	return r.ListDays(ctx, 0, from, uid)
+}
+
+func (r *fakeNoteStatRepo) ListDays(ctx context.Context, vaultID int64, from string, uid int64) ([]*domain.NoteStatDay, error) {
+	var days []*domain.NoteStatDay
+	for _, d := range r.days {
+		if d.Day >= from {
+			days = append(days, d)
+		}
+	}
+	return days, nil
+}
+
+// TestNoteService_UpdateNoteStats verifies saving a note stores its counts and adds the word change to today's activity.
+// TestNoteService_UpdateNoteStats 验证保存笔记会存储其字数并将字数变化计入当天的写作活动。
+func TestNoteService_UpdateNoteStats(t *testing.T) {
+	repo := newFakeNoteStatRepo()
+	svc := &noteService{statRepo: repo}
+
+	svc.UpdateNoteStats(context.Background(), 1, "a.md", "one two three", 7, 1)
+	svc.UpdateNoteStats(context.Background(), 1, "a.md", "one", 7, 1)
+
+	assert.Equal(t, int64(1), repo.stats[1].Words)
+	day := repo.days[time.Now().Format(time.DateOnly)]
+	require.NotNil(t, day)
+	assert.Equal(t, int64(2), day.Edits)
+	assert.Equal(t, int64(3), day.WordsAdded)
+	assert.Equal(t, int64(2), day.WordsRemoved)
+}
+
+// TestNoteStatsService_Stats verifies changed notes are counted before the statistics are built,
+// deleted notes drop out, and folders, longest notes and the daily activity are reported.
+// TestNoteStatsService_Stats 验证生成统计前会统计变更的笔记、已删除的笔记会移出统计，并返回文件夹、最长笔记与每日活动。
+func TestNoteStatsService_Stats(t *testing.T) {
+	vaultRepo := newVaultMockRepo()
+	vaultRepo.On("GetByName", mock.Anything, "Work", int64(1)).Return(newVault(1, "Work"), nil)
+	noteRepo := new(domainmocks.MockNoteRepository)
+	noteRepo.On("ListByUpdatedTimestamp", mock.Anything, int64(100), int64(1), int64(1)).Return([]*domain.Note{
+		{ID: 1, Path: "Daily/a.md", Content: "---\ntitle: x\n---\none two three", UpdatedTimestamp: 300},
+		{ID: 2, Path: "b.md", Content: "你好世界", UpdatedTimestamp: 200},
+		{ID: 3, Path: "Daily/old.md", Action: domain.NoteActionDelete, UpdatedTimestamp: 250},
+	}, nil)
+
+	repo := newFakeNoteStatRepo()
+	repo.indexedUntil = 100
+	repo.stats[3] = &domain.NoteStat{NoteID: 3, Path: "Daily/old.md", Words: 50}
+	repo.stats[4] = &domain.NoteStat{NoteID: 4, Path: "Daily/c.md", Words: 1, Chars: 3}
+	today := time.Now().Format(time.DateOnly)
+	repo.days[today] = &domain.NoteStatDay{Day: today, Edits: 2, WordsAdded: 7}
+
+	svc := NewNoteStatsService(repo, noteRepo, newVaultSvc(vaultRepo), zap.NewNop())
+	stats, err := svc.Stats(context.Background(), 1, &dto.VaultStatsRequest{Vault: "Work", Top: 2, Days: 7})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(300), repo.indexedUntil)
+	assert.Equal(t, int64(3), stats.Notes)
+	assert.Equal(t, int64(8), stats.Words)
+	assert.Equal(t, int64(1), stats.ReadingMinutes)
+
+	require.Len(t, stats.Folders, 2)
+	assert.Equal(t, "", stats.Folders[0].Folder)
+	assert.Equal(t, int64(4), stats.Folders[0].Words)
+	assert.Equal(t, "Daily", stats.Folders[1].Folder)
+	assert.Equal(t, int64(2), stats.Folders[1].Notes)
+
+	require.Len(t, stats.Longest, 2)
+	assert.Equal(t, "b.md", stats.Longest[0].Path)
+	assert.Equal(t, "Daily/a.md", stats.Longest[1].Path)
+
+	require.Len(t, stats.Activity, 7)
+	assert.Equal(t, today, stats.Activity[6].Date)
+	assert.Equal(t, int64(2), stats.Activity[6].Edits)
+	assert.Equal(t, int64(0), stats.Activity[0].Edits)
+}
+
+// TestBuildHeatmap verifies levels are relative to the busiest day and a streak stays alive while today has no edit yet.
+// TestBuildHeatmap 验证强度等级相对于最忙的一天，且今天尚无编辑时连续记录仍然有效。
+func TestBuildHeatmap(t *testing.T) {
+	days := func(counts ...int64) []*dto.VaultStatsDayDTO {
+		activity := make([]*dto.VaultStatsDayDTO, 0, len(counts))
+		for i, c := range counts {
+			activity = append(activity, &dto.VaultStatsDayDTO{Date: time.Date(2024, 1, i+1, 0, 0, 0, 0, time.Local).Format(time.DateOnly), Edits: c})
+		}
+		return activity
+	}
+
+	heatmap := buildHeatmap(days(1, 8, 0, 2, 3, 4, 0))
+	assert.Equal(t, int64(18), heatmap.Total)
+	assert.Equal(t, int64(8), heatmap.MaxCount)
+	assert.Equal(t, 5, heatmap.ActiveDays)
+	assert.Equal(t, 3, heatmap.LongestStreak)
+	assert.Equal(t, 3, heatmap.CurrentStreak)
+	assert.Equal(t, []int{1, 4, 0, 1, 2, 2, 0}, []int{
+		heatmap.Days[0].Level, heatmap.Days[1].Level, heatmap.Days[2].Level, heatmap.Days[3].Level,
+		heatmap.Days[4].Level, heatmap.Days[5].Level, heatmap.Days[6].Level,
+	})
+
+	heatmap = buildHeatmap(days(5, 0, 0))
+	assert.Equal(t, 0, heatmap.CurrentStreak)
+	assert.Equal(t, 1, heatmap.LongestStreak)
+
+	heatmap = buildHeatmap(days(0, 0, 0))
+	assert.Equal(t, 0, heatmap.Days[0].Level)
+	assert.Equal(t, 0, heatmap.CurrentStreak)
+}
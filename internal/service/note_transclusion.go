@@ -0,0 +1,149 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+	"html"
+	"path"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+)
+
+// maxTransclusionDepth maximum nesting of ![[Note]] embeds that are expanded
+// maxTransclusionDepth 展开 ![[Note]] 嵌入的最大嵌套层数
+const maxTransclusionDepth = 5
+
+// noteTransclusionExpander replaces Obsidian note embeds (![[Note]], ![[Note#Heading]],
+// ![[Note#^block]]) with the referenced content, recursively.
+// Cycles and embeds nested deeper than maxTransclusionDepth are left as the original link.
+// noteTransclusionExpander 递归地将 Obsidian 笔记嵌入（![[Note]]、![[Note#Heading]]、![[Note#^block]]）
+// 替换为被引用的内容。循环引用及超过 maxTransclusionDepth 层的嵌入保留原始链接。
+type noteTransclusionExpander struct {
+	noteRepo domain.NoteRepository
+	uid      int64
+	vaultID  int64
+	cache    map[string]*domain.Note // Resolved notes keyed by "fromDir|ref" // 按 "来源目录|引用" 缓存的已解析笔记
+}
+
+func newNoteTransclusionExpander(noteRepo domain.NoteRepository, uid, vaultID int64) *noteTransclusionExpander {
+	return &noteTransclusionExpander{
+		noteRepo: noteRepo,
+		uid:      uid,
+		vaultID:  vaultID,
+		cache:    make(map[string]*domain.Note),
+	}
+}
+
+// Expand expands note embeds in content, which belongs to the note at notePath
+// Expand 展开 content（属于 notePath 对应笔记）中的笔记嵌入
+func (e *noteTransclusionExpander) Expand(ctx context.Context, notePath string, content string) string {
+	return e.expand(ctx, notePath, content, map[string]bool{notePath + "#": true}, 0)
+}
+
+func (e *noteTransclusionExpander) expand(ctx context.Context, notePath string, content string, stack map[string]bool, depth int) string {
+	if !strings.Contains(content, "![[") {
+		return content
+	}
+
+	return attachmentRegex.ReplaceAllStringFunc(content, func(match string) string {
+		submatches := attachmentRegex.FindStringSubmatch(match)
+		if len(submatches) < 2 {
+			return match
+		}
+
+		target := submatches[1]
+		if idx := strings.Index(target, "|"); idx != -1 {
+			target = target[:idx]
+		}
+		ref, anchor := target, ""
+		if idx := strings.Index(target, "#"); idx != -1 {
+			ref, anchor = target[:idx], target[idx+1:]
+		}
+		ref = strings.TrimSpace(ref)
+
+		// Attachments (images, PDFs, media...) are handled by the share file pipeline
+		// 附件（图片、PDF、媒体等）由分享文件流程处理
+		if ext := strings.ToLower(path.Ext(ref)); ext != "" && ext != ".md" {
+			return match
+		}
+
+		var note *domain.Note
+		if ref == "" {
+			// ![[#Heading]] embeds a section of the current note
+			// ![[#Heading]] 嵌入当前笔记中的某个章节
+			if anchor == "" {
+				return match
+			}
+			note = e.resolve(ctx, notePath, path.Base(notePath))
+		} else {
+			note = e.resolve(ctx, notePath, ref)
+		}
+		if note == nil {
+			return match
+		}
+		// Cycle detection is per note and anchor, so a note may still embed its own sections
+		// 循环检测以笔记加锚点为单位，因此笔记仍可嵌入自身的章节
+		key := note.Path + "#" + anchor
+		if depth >= maxTransclusionDepth || stack[key] {
+			return match
+		}
+
+		_, body, _ := util.ParseFrontmatter(note.Content)
+		if anchor != "" {
+			section, ok := util.ExtractMarkdownSection(body, anchor)
+			if !ok {
+				return match
+			}
+			body = section
+		}
+
+		stack[key] = true
+		body = e.expand(ctx, note.Path, body, stack, depth+1)
+		delete(stack, key)
+
+		// Blank lines around the body keep it parsed as markdown inside the HTML block
+		// 正文前后的空行保证其在 HTML 块内仍按 markdown 解析
+		return `<div class="markdown-embed" data-path="` + html.EscapeString(note.Path) + `">` + "\n\n" + strings.TrimSpace(body) + "\n\n</div>"
+	})
+}
+
+// resolve finds the note an embed reference points to, relative to fromPath first and then
+// anywhere in the vault by file name, as Obsidian does for shortest-path links
+// resolve 查找嵌入引用指向的笔记：先按相对 fromPath 的路径查找，再像 Obsidian 最短路径链接那样按文件名在整个仓库中查找
+func (e *noteTransclusionExpander) resolve(ctx context.Context, fromPath string, ref string) *domain.Note {
+	if !strings.HasSuffix(strings.ToLower(ref), ".md") {
+		ref += ".md"
+	}
+	key := path.Dir(fromPath) + "|" + ref
+	if note, ok := e.cache[key]; ok {
+		return note
+	}
+
+	var found *domain.Note
+	candidates := buildSharePathCandidates(fromPath, ref)
+	if normalized := normalizeShareVaultPath(ref); normalized != "" {
+		candidates = append(candidates, normalized)
+	}
+	for _, candidate := range candidates {
+		note, err := e.noteRepo.GetByPath(ctx, candidate, e.vaultID, e.uid)
+		if err == nil && note != nil && !note.IsDeleted() {
+			found = note
+			break
+		}
+	}
+
+	if found == nil {
+		if normalized := normalizeShareVaultPath(ref); normalized != "" && !strings.Contains(normalized, "/") {
+			note, err := e.noteRepo.GetByPathLike(ctx, "/"+normalized, e.vaultID, e.uid)
+			if err == nil && note != nil {
+				found = note
+			}
+		}
+	}
+
+	e.cache[key] = found
+	return found
+}
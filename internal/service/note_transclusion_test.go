@@ -0,0 +1,52 @@
+// Package service implements the business logic layer.
+// Package service 实现业务逻辑层。
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestNoteTransclusionExpander verifies recursive embeds, heading sections and cycle detection.
+// TestNoteTransclusionExpander 验证递归嵌入、标题章节及循环检测。
+func TestNoteTransclusionExpander(t *testing.T) {
+	notes := map[string]*domain.Note{
+		"docs/A.md": {Path: "docs/A.md", Content: "A body ![[B]] ![[C#Part]]"},
+		"docs/B.md": {Path: "docs/B.md", Content: "---\ntags: [x]\n---\nB body ![[A]]"},
+		"C.md":      {Path: "C.md", Content: "# Top\n## Part\nC part\n## Other\nskip"},
+	}
+
+	noteRepo := new(domainmocks.MockNoteRepository)
+	for p, n := range notes {
+		noteRepo.On("GetByPath", mock.Anything, p, int64(1), int64(7)).Return(n, nil).Maybe()
+	}
+	noteRepo.On("GetByPath", mock.Anything, mock.Anything, int64(1), int64(7)).Return(nil, errors.New("not found")).Maybe()
+	noteRepo.On("GetByPathLike", mock.Anything, mock.Anything, int64(1), int64(7)).Return(nil, errors.New("not found")).Maybe()
+
+	e := newNoteTransclusionExpander(noteRepo, 7, 1)
+	got := e.Expand(context.Background(), "docs/A.md", notes["docs/A.md"].Content)
+
+	assert.Contains(t, got, `<div class="markdown-embed" data-path="docs/B.md">`)
+	assert.Contains(t, got, "B body ![[A]]", "cycle back to the root note must stay a link")
+	assert.NotContains(t, got, "tags: [x]", "frontmatter is stripped from embeds")
+	assert.Contains(t, got, "## Part\nC part")
+	assert.NotContains(t, got, "skip")
+}
+
+// TestNoteTransclusionExpander_KeepsAttachmentsAndMissingNotes verifies non-note embeds are untouched.
+// TestNoteTransclusionExpander_KeepsAttachmentsAndMissingNotes 验证非笔记嵌入保持不变。
+func TestNoteTransclusionExpander_KeepsAttachmentsAndMissingNotes(t *testing.T) {
+	noteRepo := new(domainmocks.MockNoteRepository)
+	noteRepo.On("GetByPath", mock.Anything, mock.Anything, int64(1), int64(7)).Return(nil, errors.New("not found"))
+	noteRepo.On("GetByPathLike", mock.Anything, mock.Anything, int64(1), int64(7)).Return(nil, errors.New("not found"))
+
+	content := "![[photo.png|200]] ![[Missing]]"
+	got := newNoteTransclusionExpander(noteRepo, 7, 1).Expand(context.Background(), "A.md", content)
+	assert.Equal(t, content, got)
+}
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/webhook"
+	"go.uber.org/zap"
+)
+
+const (
+	// webhookMaxAttempts attempts per delivery, including the first one
+	// webhookMaxAttempts 每次投递的尝试次数（含首次）
+	webhookMaxAttempts = 5
+	// webhookRetryDelay delay before the first retry, doubled after every failed attempt
+	// webhookRetryDelay 首次重试前的等待时间，每次失败后翻倍
+	webhookRetryDelay = 5 * time.Second
+	// webhookMaxConcurrency deliveries in flight at the same time across all users
+	// webhookMaxConcurrency 所有用户同时进行中的投递数上限
+	webhookMaxConcurrency = 16
+)
+
+// Notifier receives business events and forwards them to the user's webhooks
+// Notifier 接收业务事件并转发到用户的 Webhook
+type Notifier interface {
+	// Notify asynchronously delivers event to every webhook of uid subscribed to it
+	// Notify 将事件异步投递到 uid 订阅了该事件的所有 Webhook
+	Notify(uid int64, event domain.WebhookEvent, data any)
+}
+
+// NotificationService defines the per-user webhook notification business service interface
+// NotificationService 定义按用户配置的 Webhook 通知业务服务接口
+type NotificationService interface {
+	Notifier
+
+	// List lists all webhooks of a user
+	// List 列出用户的全部 Webhook
+	List(ctx context.Context, uid int64) ([]*dto.WebhookDTO, error)
+
+	// Save creates or updates a webhook
+	// Save 新建或更新 Webhook
+	Save(ctx context.Context, uid int64, params *dto.WebhookRequest) (*dto.WebhookDTO, error)
+
+	// Delete removes a webhook together with its delivery log
+	// Delete 删除 Webhook 及其投递日志
+	Delete(ctx context.Context, uid int64, id int64) error
+
+	// ListDeliveries retrieves the delivery log with pagination, newest first
+	// ListDeliveries 按时间倒序分页查询投递日志
+	ListDeliveries(ctx context.Context, uid int64, webhookID int64, page, pageSize int) ([]*dto.WebhookDeliveryDTO, int64, error)
+
+	// CleanupByTime removes deliveries older than the given cutoff time for all users
+	// CleanupByTime 清理所有用户在指定截止时间之前的投递记录
+	CleanupByTime(ctx context.Context, cutoffTime int64) error
+
+	// Shutdown stops pending retries and waits for in-flight deliveries
+	// Shutdown 停止等待中的重试并等待进行中的投递结束
+	Shutdown(ctx context.Context) error
+}
+
+// notificationService implements NotificationService
+// notificationService 实现 NotificationService 接口
+type notificationService struct {
+	repo       domain.WebhookRepository
+	client     *http.Client
+	logger     *zap.Logger
+	retryDelay time.Duration
+
+	mu       sync.RWMutex
+	webhooks map[int64][]*domain.Webhook // uid -> webhooks, loaded on first use, slices are never mutated // uid -> Webhook 列表，首次使用时加载，切片不会被修改
+
+	sem    chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewNotificationService creates a NotificationService instance
+// NewNotificationService 创建 NotificationService 实例
+func NewNotificationService(repo domain.WebhookRepository, logger *zap.Logger) NotificationService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &notificationService{
+		repo:       repo,
+		client:     &http.Client{Timeout: webhook.DefaultTimeout},
+		logger:     logger,
+		retryDelay: webhookRetryDelay,
+		webhooks:   make(map[int64][]*domain.Webhook),
+		sem:        make(chan struct{}, webhookMaxConcurrency),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// webhookToDTO converts the domain model to the DTO
+// webhookToDTO 将领域模型转换为 DTO
+func webhookToDTO(w *domain.Webhook) *dto.WebhookDTO {
+	events := make([]string, 0, len(w.Events))
+	for _, event := range w.Events {
+		events = append(events, string(event))
+	}
+	return &dto.WebhookDTO{
+		ID:        w.ID,
+		Name:      w.Name,
+		URL:       w.URL,
+		Secret:    w.Secret,
+		Events:    events,
+		IsEnabled: w.IsEnabled,
+		CreatedAt: timex.Time(w.CreatedAt),
+		UpdatedAt: timex.Time(w.UpdatedAt),
+	}
+}
+
+// List lists all webhooks of a user
+// List 列出用户的全部 Webhook
+func (s *notificationService) List(ctx context.Context, uid int64) ([]*dto.WebhookDTO, error) {
+	list, err := s.repo.List(ctx, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	results := make([]*dto.WebhookDTO, 0, len(list))
+	for _, w := range list {
+		results = append(results, webhookToDTO(w))
+	}
+	return results, nil
+}
+
+// Save creates or updates a webhook
+// Save 新建或更新 Webhook
+func (s *notificationService) Save(ctx context.Context, uid int64, params *dto.WebhookRequest) (*dto.WebhookDTO, error) {
+	u, err := url.Parse(params.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, code.ErrorWebhookURLInvalid
+	}
+
+	w := &domain.Webhook{
+		ID:        params.ID,
+		UID:       uid,
+		Name:      params.Name,
+		URL:       params.URL,
+		Secret:    params.Secret,
+		IsEnabled: params.IsEnabled,
+	}
+	for _, name := range params.Events {
+		event := domain.WebhookEvent(name)
+		if !event.IsValid() {
+			return nil, code.ErrorWebhookEventUnknown.WithDetails(name)
+		}
+		w.Events = append(w.Events, event)
+	}
+
+	if w.ID > 0 {
+		existing, err := s.repo.Get(ctx, w.ID, uid)
+		if err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		if existing == nil {
+			return nil, code.ErrorWebhookNotFound
+		}
+		w.CreatedAt = existing.CreatedAt
+	}
+
+	saved, err := s.repo.Save(ctx, w, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	s.invalidate(uid)
+	return webhookToDTO(saved), nil
+}
+
+// Delete removes a webhook together with its delivery log
+// Delete 删除 Webhook 及其投递日志
+func (s *notificationService) Delete(ctx context.Context, uid int64, id int64) error {
+	existing, err := s.repo.Get(ctx, id, uid)
+	if err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if existing == nil {
+		return code.ErrorWebhookNotFound
+	}
+	if err := s.repo.Delete(ctx, id, uid); err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	s.invalidate(uid)
+	return nil
+}
+
+// ListDeliveries retrieves the delivery log with pagination, newest first
+// ListDeliveries 按时间倒序分页查询投递日志
+func (s *notificationService) ListDeliveries(ctx context.Context, uid int64, webhookID int64, page, pageSize int) ([]*dto.WebhookDeliveryDTO, int64, error) {
+	list, total, err := s.repo.ListDeliveries(ctx, webhookID, uid, page, pageSize)
+	if err != nil {
+		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	results := make([]*dto.WebhookDeliveryDTO, 0, len(list))
+	for _, d := range list {
+		results = append(results, &dto.WebhookDeliveryDTO{
+			ID:        d.ID,
+			WebhookID: d.WebhookID,
+			Event:     string(d.Event),
+			Payload:   d.Payload,
+			Status:    string(d.Status),
+			Attempts:  d.Attempts,
+			Error:     d.Error,
+			CreatedAt: timex.Time(d.CreatedAt),
+			UpdatedAt: timex.Time(d.UpdatedAt),
+		})
+	}
+	return results, total, nil
+}
+
+// CleanupByTime removes deliveries older than the given cutoff time for all users
+// CleanupByTime 清理所有用户在指定截止时间之前的投递记录
+func (s *notificationService) CleanupByTime(ctx context.Context, cutoffTime int64) error {
+	return s.repo.CleanupDeliveriesByTimeAll(ctx, cutoffTime)
+}
+
+// invalidate drops the cached webhooks of a user
+// invalidate 清除用户的 Webhook 缓存
+func (s *notificationService) invalidate(uid int64) {
+	s.mu.Lock()
+	delete(s.webhooks, uid)
+	s.mu.Unlock()
+}
+
+// subscribers returns the webhooks of a user subscribed to event, loading them from the repository once
+// subscribers 返回用户订阅了 event 的 Webhook，仅从仓储加载一次
+func (s *notificationService) subscribers(uid int64, event domain.WebhookEvent) ([]*domain.Webhook, error) {
+	s.mu.RLock()
+	list, ok := s.webhooks[uid]
+	s.mu.RUnlock()
+	if !ok {
+		var err error
+		if list, err = s.repo.List(s.ctx, uid); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.webhooks[uid] = list
+		s.mu.Unlock()
+	}
+
+	var results []*domain.Webhook
+	for _, w := range list {
+		if w.Subscribes(event) {
+			results = append(results, w)
+		}
+	}
+	return results, nil
+}
+
+// Notify asynchronously delivers event to every webhook of uid subscribed to it
+// Notify 将事件异步投递到 uid 订阅了该事件的所有 Webhook
+func (s *notificationService) Notify(uid int64, event domain.WebhookEvent, data any) {
+	if uid == 0 || s.ctx.Err() != nil {
+		return
+	}
+	now := time.Now()
+	s.wg.Add(1)
+	safego.Go(s.logger, func() {
+		defer s.wg.Done()
+		list, err := s.subscribers(uid, event)
+		if err != nil {
+			s.logger.Warn("notification: load webhooks failed", zap.Int64("uid", uid), zap.Error(err))
+			return
+		}
+		for _, w := range list {
+			s.wg.Add(1)
+			safego.Go(s.logger, func() {
+				defer s.wg.Done()
+				s.deliver(w, event, data, now)
+			})
+		}
+	})
+}
+
+// deliver records a delivery and POSTs it, retrying with exponential backoff until it succeeds or the
+// attempts are used up
+// deliver 记录投递并发送，失败时按指数退避重试，直到成功或用完尝试次数
+func (s *notificationService) deliver(w *domain.Webhook, event domain.WebhookEvent, data any, at time.Time) {
+	delivery, err := s.repo.CreateDelivery(s.ctx, &domain.WebhookDelivery{
+		UID:       w.UID,
+		WebhookID: w.ID,
+		Event:     event,
+		Status:    domain.WebhookDeliveryPending,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}, w.UID)
+	if err != nil {
+		s.logger.Warn("notification: create delivery failed", zap.Int64("webhookId", w.ID), zap.Error(err))
+		return
+	}
+
+	// The payload carries the delivery ID so receivers can drop retries they already processed
+	// 负载包含投递 ID，接收方可据此丢弃已处理过的重试
+	payload := &dto.WebhookPayload{
+		DeliveryID: delivery.ID,
+		Event:      string(event),
+		UID:        w.UID,
+		Timestamp:  at.UnixMilli(),
+		Data:       data,
+	}
+	if body, err := json.Marshal(payload); err == nil {
+		delivery.Payload = string(body)
+	}
+
+	delay := s.retryDelay
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		select {
+		case s.sem <- struct{}{}:
+		case <-s.ctx.Done():
+			return
+		}
+		err = webhook.Send(s.ctx, s.client, w.URL, w.Secret, string(event), payload)
+		<-s.sem
+
+		delivery.Attempts = attempt
+		switch {
+		case err == nil:
+			delivery.Status = domain.WebhookDeliverySuccess
+			delivery.Error = ""
+		case attempt == webhookMaxAttempts:
+			delivery.Status = domain.WebhookDeliveryFailed
+			delivery.Error = err.Error()
+		default:
+			delivery.Error = err.Error()
+		}
+		// The record outlives the service context so the last attempt is kept on shutdown
+		// 记录使用独立的上下文，关闭服务时也能保存最后一次尝试
+		if uerr := s.repo.UpdateDelivery(context.Background(), delivery, w.UID); uerr != nil {
+			s.logger.Warn("notification: update delivery failed", zap.Int64("deliveryId", delivery.ID), zap.Error(uerr))
+		}
+		if err == nil || attempt == webhookMaxAttempts {
+			break
+		}
+
+		s.logger.Info("notification: delivery failed, retrying",
+			zap.Int64("webhookId", w.ID), zap.Int64("deliveryId", delivery.ID), zap.Int("attempt", attempt), zap.Error(err))
+		select {
+		case <-time.After(delay):
+		case <-s.ctx.Done():
+			return
+		}
+		delay *= 2
+	}
+}
+
+// Shutdown stops pending retries and waits for in-flight deliveries
+// Shutdown 停止等待中的重试并等待进行中的投递结束
+func (s *notificationService) Shutdown(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ensure notificationService implements NotificationService
+// 确保 notificationService 实现了 NotificationService 接口
+var _ NotificationService = (*notificationService)(nil)
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeWebhookRepo in-memory domain.WebhookRepository
+type fakeWebhookRepo struct {
+	mu         sync.Mutex
+	webhooks   map[int64]*domain.Webhook
+	deliveries map[int64]*domain.WebhookDelivery
+	nextID     int64
+	finished   chan *domain.WebhookDelivery
+}
+
+func newFakeWebhookRepo() *fakeWebhookRepo {
+	return &fakeWebhookRepo{
+		webhooks:   map[int64]*domain.Webhook{},
+		deliveries: map[int64]*domain.WebhookDelivery{},
+		finished:   make(chan *domain.WebhookDelivery, 16),
+	}
+}
+
+func (r *fakeWebhookRepo) List(ctx context.Context, uid int64) ([]*domain.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*domain.Webhook
+	for _, w := range r.webhooks {
+		if w.UID == uid {
+			copied := *w
+			list = append(list, &copied)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeWebhookRepo) Get(ctx context.Context, id, uid int64) (*domain.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if w, ok := r.webhooks[id]; ok && w.UID == uid {
+		copied := *w
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeWebhookRepo) Save(ctx context.Context, w *domain.Webhook, uid int64) (*domain.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *w
+	copied.UID = uid
+	if copied.ID == 0 {
+		r.nextID++
+		copied.ID = r.nextID
+	}
+	r.webhooks[copied.ID] = &copied
+	result := copied
+	return &result, nil
+}
+
+func (r *fakeWebhookRepo) Delete(ctx context.Context, id, uid int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.webhooks, id)
+	return nil
+}
+
+func (r *fakeWebhookRepo) CreateDelivery(ctx context.Context, d *domain.WebhookDelivery, uid int64) (*domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	copied := *d
+	copied.ID = r.nextID
+	r.deliveries[copied.ID] = &copied
+	result := copied
+	return &result, nil
+}
+
+func (r *fakeWebhookRepo) UpdateDelivery(ctx context.Context, d *domain.WebhookDelivery, uid int64) error {
+	r.mu.Lock()
+	copied := *d
+	r.deliveries[d.ID] = &copied
+	r.mu.Unlock()
+	if d.Status != domain.WebhookDeliveryPending {
+		r.finished <- &copied
+	}
+	return nil
+}
+
+func (r *fakeWebhookRepo) ListDeliveries(ctx context.Context, webhookID, uid int64, page, pageSize int) ([]*domain.WebhookDelivery, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*domain.WebhookDelivery
+	for _, d := range r.deliveries {
+		if webhookID == 0 || d.WebhookID == webhookID {
+			list = append(list, d)
+		}
+	}
+	return list, int64(len(list)), nil
+}
+
+func (r *fakeWebhookRepo) CleanupDeliveriesByTimeAll(ctx context.Context, timestamp int64) error {
+	return nil
+}
+
+func (r *fakeWebhookRepo) waitFinished(t *testing.T) *domain.WebhookDelivery {
+	t.Helper()
+	select {
+	case d := <-r.finished:
+		return d
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a delivery to finish")
+		return nil
+	}
+}
+
+func newTestNotificationService(repo *fakeWebhookRepo) *notificationService {
+	svc := NewNotificationService(repo, zap.NewNop()).(*notificationService)
+	svc.retryDelay = time.Millisecond
+	return svc
+}
+
+// TestNotificationService_SignedDelivery verifies subscribed events are POSTed with a valid signature.
+// TestNotificationService_SignedDelivery 验证订阅的事件以有效签名 POST 发送。
+func TestNotificationService_SignedDelivery(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{header: r.Header.Clone(), body: body}
+	}))
+	defer server.Close()
+
+	repo := newFakeWebhookRepo()
+	svc := newTestNotificationService(repo)
+	defer svc.Shutdown(context.Background())
+
+	_, err := svc.Save(context.Background(), 1, &dto.WebhookRequest{
+		URL: server.URL, Secret: "s3cret", Events: []string{"note.created"}, IsEnabled: true,
+	})
+	require.NoError(t, err)
+
+	// Not subscribed: nothing is sent
+	// 未订阅：不发送
+	svc.Notify(1, domain.WebhookEventNoteDeleted, nil)
+	svc.Notify(1, domain.WebhookEventNoteCreated, map[string]string{"path": "a.md"})
+
+	d := repo.waitFinished(t)
+	assert.Equal(t, domain.WebhookDeliverySuccess, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+
+	r := <-got
+	assert.Equal(t, "note.created", r.header.Get(webhook.HeaderEvent))
+	ts, err := strconv.ParseInt(r.header.Get(webhook.HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.Sign("s3cret", ts, r.body), r.header.Get(webhook.HeaderSignature))
+
+	var payload dto.WebhookPayload
+	require.NoError(t, json.Unmarshal(r.body, &payload))
+	assert.Equal(t, d.ID, payload.DeliveryID)
+	assert.Equal(t, "note.created", payload.Event)
+	assert.Equal(t, map[string]any{"path": "a.md"}, payload.Data)
+
+	require.NoError(t, svc.Shutdown(context.Background()))
+	assert.Empty(t, got, "unsubscribed events must not be delivered")
+}
+
+// TestNotificationService_Retry verifies failed attempts are retried until the endpoint accepts them.
+// TestNotificationService_Retry 验证失败的尝试会重试直到端点接受。
+func TestNotificationService_Retry(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	repo := newFakeWebhookRepo()
+	svc := newTestNotificationService(repo)
+	defer svc.Shutdown(context.Background())
+
+	_, err := svc.Save(context.Background(), 1, &dto.WebhookRequest{URL: server.URL, IsEnabled: true})
+	require.NoError(t, err)
+
+	svc.Notify(1, domain.WebhookEventBackupFailed, nil)
+	d := repo.waitFinished(t)
+	assert.Equal(t, domain.WebhookDeliverySuccess, d.Status)
+	assert.Equal(t, 3, d.Attempts)
+	assert.Empty(t, d.Error)
+}
+
+// TestNotificationService_GivesUp verifies a delivery is marked failed after the last attempt.
+// TestNotificationService_GivesUp 验证最后一次尝试失败后投递被标记为失败。
+func TestNotificationService_GivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo := newFakeWebhookRepo()
+	svc := newTestNotificationService(repo)
+	defer svc.Shutdown(context.Background())
+
+	_, err := svc.Save(context.Background(), 1, &dto.WebhookRequest{URL: server.URL, IsEnabled: true})
+	require.NoError(t, err)
+
+	svc.Notify(1, domain.WebhookEventUserLogin, nil)
+	d := repo.waitFinished(t)
+	assert.Equal(t, domain.WebhookDeliveryFailed, d.Status)
+	assert.Equal(t, webhookMaxAttempts, d.Attempts)
+	assert.Contains(t, d.Error, "500")
+}
+
+// TestNotificationService_Validation verifies invalid URLs, unknown events and missing webhooks are rejected.
+// TestNotificationService_Validation 验证无效地址、未知事件与不存在的 Webhook 会被拒绝。
+func TestNotificationService_Validation(t *testing.T) {
+	svc := newTestNotificationService(newFakeWebhookRepo())
+	defer svc.Shutdown(context.Background())
+	ctx := context.Background()
+
+	_, err := svc.Save(ctx, 1, &dto.WebhookRequest{URL: "ftp://example.com/hook"})
+	assert.Equal(t, code.ErrorWebhookURLInvalid, err)
+
+	_, err = svc.Save(ctx, 1, &dto.WebhookRequest{URL: "https://example.com/hook", Events: []string{"note.renamed"}})
+	require.IsType(t, &code.Code{}, err)
+	assert.Equal(t, code.ErrorWebhookEventUnknown.Code(), err.(*code.Code).Code())
+
+	_, err = svc.Save(ctx, 1, &dto.WebhookRequest{ID: 42, URL: "https://example.com/hook"})
+	assert.Equal(t, code.ErrorWebhookNotFound, err)
+
+	assert.Equal(t, code.ErrorWebhookNotFound, svc.Delete(ctx, 1, 42))
+}
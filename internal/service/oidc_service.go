@@ -36,19 +36,29 @@ type OIDCServiceConfig struct {
 
 type loginTokenIssuer interface {
 	CreateForLogin(ctx context.Context, uid int64, clientType, ip, userAgent string) (*domain.AuthToken, string, error)
+	CreateForTOTPSetup(ctx context.Context, uid int64, clientType, ip, userAgent string) (*domain.AuthToken, string, error)
+}
+
+// loginSecondFactor second factor state checked before an OIDC login issues a token
+// loginSecondFactor OIDC 登录签发 Token 前检查的第二因素状态
+type loginSecondFactor interface {
+	IsEnabled(ctx context.Context, uid int64) (bool, error)
+	IsRequired(uid int64) bool
 }
 
 type oidcService struct {
 	userRepo     domain.UserRepository
 	identityRepo domain.OIDCIdentityRepository
 	tokenService loginTokenIssuer
+	twoFactor    loginSecondFactor // may be nil // 可能为 nil
 }
 
-func NewOIDCService(userRepo domain.UserRepository, identityRepo domain.OIDCIdentityRepository, tokenService loginTokenIssuer) OIDCService {
+func NewOIDCService(userRepo domain.UserRepository, identityRepo domain.OIDCIdentityRepository, tokenService loginTokenIssuer, twoFactor loginSecondFactor) OIDCService {
 	return &oidcService{
 		userRepo:     userRepo,
 		identityRepo: identityRepo,
 		tokenService: tokenService,
+		twoFactor:    twoFactor,
 	}
 }
 
@@ -84,23 +94,44 @@ func (s *oidcService) Authenticate(ctx context.Context, config OIDCServiceConfig
 		}
 	}
 
+	// The OIDC flow has no step to ask for a TOTP code, users who enabled 2FA sign in with the password form,
+	// an admin who must enroll only gets a setup token like the password login does
+	// OIDC 流程无法询问 TOTP 验证码，已启用两步验证的用户需使用密码登录；
+	// 需要设置两步验证的管理员与密码登录一样只获得设置令牌
+	issue := s.tokenService.CreateForLogin
+	totpSetupRequired := false
+	if s.twoFactor != nil {
+		enabled, err := s.twoFactor.IsEnabled(ctx, user.UID)
+		if err != nil {
+			return nil, err
+		}
+		if enabled {
+			return nil, code.ErrorUserTOTPRequired.WithDetails("two-factor authentication is enabled, sign in with password and TOTP code")
+		}
+		if s.twoFactor.IsRequired(user.UID) {
+			issue = s.tokenService.CreateForTOTPSetup
+			totpSetupRequired = true
+		}
+	}
+
 	if clientType == "" {
 		clientType = "WebGUI"
 	}
-	token, tokenStr, err := s.tokenService.CreateForLogin(ctx, user.UID, clientType, clientIP, userAgent)
+	token, tokenStr, err := issue(ctx, user.UID, clientType, clientIP, userAgent)
 	if err != nil {
 		return nil, code.ErrorTokenGenerate.WithDetails(err.Error())
 	}
 
 	return &dto.UserDTO{
-		UID:       user.UID,
-		Email:     user.Email,
-		Username:  user.Username,
-		Avatar:    user.Avatar,
-		Token:     tokenStr,
-		TokenID:   token.ID,
-		UpdatedAt: timex.Time(user.UpdatedAt),
-		CreatedAt: timex.Time(user.CreatedAt),
+		UID:               user.UID,
+		Email:             user.Email,
+		Username:          user.Username,
+		Avatar:            user.Avatar,
+		Token:             tokenStr,
+		TokenID:           token.ID,
+		UpdatedAt:         timex.Time(user.UpdatedAt),
+		CreatedAt:         timex.Time(user.CreatedAt),
+		TOTPSetupRequired: totpSetupRequired,
 	}, nil
 }
 
@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	internaloidc "github.com/haierkeys/fast-note-sync-service/internal/oidc"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"gorm.io/gorm"
 )
 
@@ -22,7 +24,7 @@ func TestOIDCServiceAuthenticatesExistingIdentity(t *testing.T) {
 		},
 	}
 	tokenSvc := &fakeOIDCTokenService{}
-	svc := NewOIDCService(userRepo, identityRepo, tokenSvc)
+	svc := NewOIDCService(userRepo, identityRepo, tokenSvc, nil)
 	providerConfig := OIDCServiceConfig{
 		AutoRegister: false,
 		Issuer:       "https://issuer.example",
@@ -54,7 +56,7 @@ func TestOIDCServiceBindsExistingEmailWhenIdentityMissing(t *testing.T) {
 		},
 	}
 	identityRepo := &fakeOIDCIdentityRepo{byIssuerSubject: map[string]*domain.OIDCIdentity{}}
-	svc := NewOIDCService(userRepo, identityRepo, &fakeOIDCTokenService{})
+	svc := NewOIDCService(userRepo, identityRepo, &fakeOIDCTokenService{}, nil)
 	providerConfig := OIDCServiceConfig{
 		Issuer: "https://issuer.example",
 		UserMapping: OIDCUserMappingConfig{
@@ -81,7 +83,7 @@ func TestOIDCServiceBindsExistingEmailWhenIdentityMissing(t *testing.T) {
 func TestOIDCServiceAutoRegistersWhenNoUserMatches(t *testing.T) {
 	userRepo := &fakeOIDCUserRepo{byEmail: map[string]*domain.User{}}
 	identityRepo := &fakeOIDCIdentityRepo{byIssuerSubject: map[string]*domain.OIDCIdentity{}}
-	svc := NewOIDCService(userRepo, identityRepo, &fakeOIDCTokenService{})
+	svc := NewOIDCService(userRepo, identityRepo, &fakeOIDCTokenService{}, nil)
 	providerConfig := OIDCServiceConfig{
 		AutoRegister: true,
 		Issuer:       "https://issuer.example",
@@ -111,7 +113,7 @@ func TestOIDCServiceAutoRegistersWhenNoUserMatches(t *testing.T) {
 func TestOIDCServiceAutoRegisterFallsBackToDisplayNameForUsername(t *testing.T) {
 	userRepo := &fakeOIDCUserRepo{byEmail: map[string]*domain.User{}}
 	identityRepo := &fakeOIDCIdentityRepo{byIssuerSubject: map[string]*domain.OIDCIdentity{}}
-	svc := NewOIDCService(userRepo, identityRepo, &fakeOIDCTokenService{})
+	svc := NewOIDCService(userRepo, identityRepo, &fakeOIDCTokenService{}, nil)
 	providerConfig := OIDCServiceConfig{
 		AutoRegister: true,
 		Issuer:       "https://issuer.example",
@@ -141,7 +143,7 @@ func TestOIDCServiceAutoRegisterFallsBackToDisplayNameForUsername(t *testing.T)
 func TestOIDCServiceRejectsUnknownUserWhenAutoRegisterDisabled(t *testing.T) {
 	userRepo := &fakeOIDCUserRepo{byEmail: map[string]*domain.User{}}
 	identityRepo := &fakeOIDCIdentityRepo{byIssuerSubject: map[string]*domain.OIDCIdentity{}}
-	svc := NewOIDCService(userRepo, identityRepo, &fakeOIDCTokenService{})
+	svc := NewOIDCService(userRepo, identityRepo, &fakeOIDCTokenService{}, nil)
 	providerConfig := OIDCServiceConfig{
 		AutoRegister: false,
 		Issuer:       "https://issuer.example",
@@ -227,8 +229,68 @@ func (r *fakeOIDCIdentityRepo) Create(ctx context.Context, identity *domain.OIDC
 	return &created, nil
 }
 
+func (r *fakeOIDCIdentityRepo) ListByUID(ctx context.Context, uid int64) ([]*domain.OIDCIdentity, error) {
+	var list []*domain.OIDCIdentity
+	for _, identity := range r.created {
+		if identity.UID == uid {
+			list = append(list, identity)
+		}
+	}
+	return list, nil
+}
+
+func TestOIDCServiceAppliesSecondFactor(t *testing.T) {
+	newSvc := func(twoFactor *fakeOIDCSecondFactor) OIDCService {
+		userRepo := &fakeOIDCUserRepo{
+			byUID: map[int64]*domain.User{
+				42: {UID: 42, Email: "oidc@example.com", Username: "oidc-user"},
+			},
+		}
+		identityRepo := &fakeOIDCIdentityRepo{
+			byIssuerSubject: map[string]*domain.OIDCIdentity{
+				"https://issuer.example|subject-1": {UID: 42, Issuer: "https://issuer.example", Subject: "subject-1"},
+			},
+		}
+		return NewOIDCService(userRepo, identityRepo, &fakeOIDCTokenService{}, twoFactor)
+	}
+	providerConfig := OIDCServiceConfig{Issuer: "https://issuer.example"}
+	claims := internaloidc.Claims{Subject: "subject-1", Email: "oidc@example.com"}
+
+	// Enrolled users cannot skip the TOTP code through OIDC
+	_, err := newSvc(&fakeOIDCSecondFactor{enabled: true}).Authenticate(context.Background(), providerConfig, claims, "127.0.0.1", "WebGUI", "test-agent")
+	if !errors.Is(err, code.ErrorUserTOTPRequired) {
+		t.Fatalf("Authenticate() error = %v, want ErrorUserTOTPRequired", err)
+	}
+
+	// An admin who must enroll only gets a setup token
+	dto, err := newSvc(&fakeOIDCSecondFactor{required: true}).Authenticate(context.Background(), providerConfig, claims, "127.0.0.1", "WebGUI", "test-agent")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if dto.Token != "setup-token-for-42" || !dto.TOTPSetupRequired {
+		t.Fatalf("Authenticate() = %#v", dto)
+	}
+}
+
 type fakeOIDCTokenService struct{}
 
 func (s *fakeOIDCTokenService) CreateForLogin(ctx context.Context, uid int64, clientType, ip, userAgent string) (*domain.AuthToken, string, error) {
 	return &domain.AuthToken{ID: 100, UID: uid}, "token-for-42", nil
 }
+
+func (s *fakeOIDCTokenService) CreateForTOTPSetup(ctx context.Context, uid int64, clientType, ip, userAgent string) (*domain.AuthToken, string, error) {
+	return &domain.AuthToken{ID: 101, UID: uid}, "setup-token-for-42", nil
+}
+
+type fakeOIDCSecondFactor struct {
+	enabled  bool
+	required bool
+}
+
+func (f *fakeOIDCSecondFactor) IsEnabled(ctx context.Context, uid int64) (bool, error) {
+	return f.enabled, nil
+}
+
+func (f *fakeOIDCSecondFactor) IsRequired(uid int64) bool {
+	return f.required
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// inviteCodeLength length of generated invitation codes
+// inviteCodeLength 生成的邀请码长度
+const inviteCodeLength = 12
+
+// RegistrationService defines the invitation code and registration approval business service interface
+// RegistrationService 定义邀请码与注册审核业务服务接口
+type RegistrationService interface {
+	// CreateInvite generates an invitation code
+	// CreateInvite 生成邀请码
+	CreateInvite(ctx context.Context, uid int64, params *dto.UserInviteCreateRequest) (*dto.UserInviteDTO, error)
+
+	// ListInvites lists all invitation codes, newest first
+	// ListInvites 按时间倒序列出全部邀请码
+	ListInvites(ctx context.Context) ([]*dto.UserInviteDTO, error)
+
+	// DeleteInvite deletes an invitation code
+	// DeleteInvite 删除邀请码
+	DeleteInvite(ctx context.Context, id int64) error
+
+	// UseInvite counts one registration against a code, ErrorInviteCodeInvalid when it cannot admit one
+	// UseInvite 为邀请码计入一次注册，无法再注册时返回 ErrorInviteCodeInvalid
+	UseInvite(ctx context.Context, inviteCode string) error
+
+	// ReleaseInvite gives back the use counted by UseInvite when the registration fails afterwards
+	// ReleaseInvite 在随后注册失败时退还 UseInvite 计入的使用次数
+	ReleaseInvite(ctx context.Context, inviteCode string) error
+
+	// Enqueue queues a registration for approval
+	// Enqueue 将注册加入审核队列
+	Enqueue(ctx context.Context, user *domain.PendingUser) error
+
+	// IsPending reports whether a queued registration uses the email or the username
+	// IsPending 判断是否有排队中的注册使用了该邮箱或用户名
+	IsPending(ctx context.Context, email, username string) (bool, error)
+
+	// ListPending lists the registrations waiting for approval, oldest first
+	// ListPending 按时间正序列出等待审核的注册
+	ListPending(ctx context.Context, pager *app.Pager) ([]*dto.PendingUserDTO, int64, error)
+
+	// Approve creates the user of a queued registration
+	// Approve 为排队中的注册创建用户
+	Approve(ctx context.Context, id int64) (*dto.UserDTO, error)
+
+	// Reject drops a queued registration
+	// Reject 丢弃排队中的注册
+	Reject(ctx context.Context, id int64) error
+}
+
+// registrationService implements RegistrationService
+// registrationService 实现 RegistrationService 接口
+type registrationService struct {
+	inviteRepo  domain.UserInviteRepository  // Invitation repository // 邀请码仓库
+	pendingRepo domain.PendingUserRepository // Pending registration repository // 待审核注册仓库
+	userRepo    domain.UserRepository        // User repository // 用户仓库
+	logger      *zap.Logger                  // Logger // 日志器
+}
+
+// NewRegistrationService creates a RegistrationService instance
+// NewRegistrationService 创建 RegistrationService 实例
+func NewRegistrationService(inviteRepo domain.UserInviteRepository, pendingRepo domain.PendingUserRepository, userRepo domain.UserRepository, logger *zap.Logger) RegistrationService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &registrationService{
+		inviteRepo:  inviteRepo,
+		pendingRepo: pendingRepo,
+		userRepo:    userRepo,
+		logger:      logger,
+	}
+}
+
+func inviteToDTO(invite *domain.UserInvite, now time.Time) *dto.UserInviteDTO {
+	d := &dto.UserInviteDTO{
+		ID:        invite.ID,
+		Code:      invite.Code,
+		MaxUses:   invite.MaxUses,
+		Uses:      invite.Uses,
+		Usable:    invite.Usable(now),
+		Note:      invite.Note,
+		CreatedBy: invite.CreatedBy,
+		CreatedAt: timex.Time(invite.CreatedAt),
+	}
+	if !invite.ExpiresAt.IsZero() {
+		expiresAt := timex.Time(invite.ExpiresAt)
+		d.ExpiresAt = &expiresAt
+	}
+	return d
+}
+
+// CreateInvite implements RegistrationService
+func (s *registrationService) CreateInvite(ctx context.Context, uid int64, params *dto.UserInviteCreateRequest) (*dto.UserInviteDTO, error) {
+	invite := &domain.UserInvite{
+		Code:      util.GetRandomString(inviteCodeLength),
+		MaxUses:   params.MaxUses,
+		Note:      params.Note,
+		CreatedBy: uid,
+	}
+	if params.ExpiresIn != "" {
+		d, err := util.ParseDuration(params.ExpiresIn)
+		if err != nil || d <= 0 {
+			return nil, code.ErrorInvalidParams.WithDetails("expiresIn format invalid, e.g. 24h, 7d")
+		}
+		invite.ExpiresAt = time.Now().Add(d)
+	}
+
+	created, err := s.inviteRepo.Create(ctx, invite)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return inviteToDTO(created, time.Now()), nil
+}
+
+// ListInvites implements RegistrationService
+func (s *registrationService) ListInvites(ctx context.Context) ([]*dto.UserInviteDTO, error) {
+	invites, err := s.inviteRepo.List(ctx)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	now := time.Now()
+	results := make([]*dto.UserInviteDTO, 0, len(invites))
+	for _, invite := range invites {
+		results = append(results, inviteToDTO(invite, now))
+	}
+	return results, nil
+}
+
+// DeleteInvite implements RegistrationService
+func (s *registrationService) DeleteInvite(ctx context.Context, id int64) error {
+	if err := s.inviteRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.ErrorInviteNotFound
+		}
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// UseInvite implements RegistrationService
+func (s *registrationService) UseInvite(ctx context.Context, inviteCode string) error {
+	invite, err := s.inviteRepo.GetByCode(ctx, strings.TrimSpace(inviteCode))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.ErrorInviteCodeInvalid
+		}
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if !invite.Usable(time.Now()) {
+		return code.ErrorInviteCodeInvalid
+	}
+	// The conditional update settles concurrent registrations racing for the last use
+	// 条件更新决定并发注册中谁获得最后一次使用机会
+	ok, err := s.inviteRepo.Use(ctx, invite.ID)
+	if err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if !ok {
+		return code.ErrorInviteCodeInvalid
+	}
+	return nil
+}
+
+// ReleaseInvite implements RegistrationService
+func (s *registrationService) ReleaseInvite(ctx context.Context, inviteCode string) error {
+	invite, err := s.inviteRepo.GetByCode(ctx, strings.TrimSpace(inviteCode))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// A code deleted meanwhile has nothing to give back
+			// 期间已删除的邀请码无需退还
+			return nil
+		}
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if err := s.inviteRepo.Release(ctx, invite.ID); err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// Enqueue implements RegistrationService
+func (s *registrationService) Enqueue(ctx context.Context, user *domain.PendingUser) error {
+	if _, err := s.pendingRepo.Create(ctx, user); err != nil {
+		return code.ErrorUserRegister.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// IsPending implements RegistrationService
+func (s *registrationService) IsPending(ctx context.Context, email, username string) (bool, error) {
+	pending, err := s.pendingRepo.Exists(ctx, email, username)
+	if err != nil {
+		return false, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return pending, nil
+}
+
+// ListPending implements RegistrationService
+func (s *registrationService) ListPending(ctx context.Context, pager *app.Pager) ([]*dto.PendingUserDTO, int64, error) {
+	users, total, err := s.pendingRepo.List(ctx, app.GetPageOffset(pager.Page, pager.PageSize), pager.PageSize)
+	if err != nil {
+		return nil, 0, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	results := make([]*dto.PendingUserDTO, 0, len(users))
+	for _, u := range users {
+		results = append(results, &dto.PendingUserDTO{
+			ID:        u.ID,
+			Email:     u.Email,
+			Username:  u.Username,
+			IP:        u.IP,
+			CreatedAt: timex.Time(u.CreatedAt),
+		})
+	}
+	return results, total, nil
+}
+
+// Approve implements RegistrationService
+func (s *registrationService) Approve(ctx context.Context, id int64) (*dto.UserDTO, error) {
+	pending, err := s.pendingRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, code.ErrorPendingUserNotFound
+		}
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	// The admin may have created a clashing account while the registration waited
+	// 注册等待期间管理员可能已创建了冲突的账号
+	if u, err := s.userRepo.GetByEmail(ctx, pending.Email); err == nil && u != nil {
+		return nil, code.ErrorUserEmailAlreadyExists
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, code.ErrorDBQuery
+	}
+	if u, err := s.userRepo.GetByUsername(ctx, pending.Username); err == nil && u != nil {
+		return nil, code.ErrorUserAlreadyExists
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, code.ErrorDBQuery
+	}
+
+	user, err := s.userRepo.Create(ctx, &domain.User{
+		Username: pending.Username,
+		Email:    pending.Email,
+		Password: pending.Password,
+	})
+	if err != nil {
+		return nil, code.ErrorUserRegister.WithDetails(err.Error())
+	}
+	if err := s.pendingRepo.Delete(ctx, id); err != nil {
+		s.logger.Warn("RegistrationService.Approve delete pending failed",
+			zap.Int64("id", id),
+			zap.Error(err),
+		)
+	}
+	return &dto.UserDTO{
+		UID:       user.UID,
+		Email:     user.Email,
+		Username:  user.Username,
+		Avatar:    user.Avatar,
+		UpdatedAt: timex.Time(user.UpdatedAt),
+		CreatedAt: timex.Time(user.CreatedAt),
+	}, nil
+}
+
+// Reject implements RegistrationService
+func (s *registrationService) Reject(ctx context.Context, id int64) error {
+	if err := s.pendingRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.ErrorPendingUserNotFound
+		}
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// Ensure registrationService implements RegistrationService
+// 确保 registrationService 实现了 RegistrationService 接口
+var _ RegistrationService = (*registrationService)(nil)
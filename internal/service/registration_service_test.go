@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memInviteRepo in-memory UserInviteRepository
+type memInviteRepo struct {
+	mu      sync.Mutex
+	invites []*domain.UserInvite
+}
+
+func (r *memInviteRepo) Create(ctx context.Context, invite *domain.UserInvite) (*domain.UserInvite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	invite.ID = int64(len(r.invites) + 1)
+	invite.CreatedAt = time.Now()
+	r.invites = append(r.invites, invite)
+	return invite, nil
+}
+
+func (r *memInviteRepo) GetByCode(ctx context.Context, code string) (*domain.UserInvite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.invites {
+		if i.Code == code {
+			c := *i
+			return &c, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memInviteRepo) List(ctx context.Context) ([]*domain.UserInvite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*domain.UserInvite(nil), r.invites...), nil
+}
+
+func (r *memInviteRepo) Use(ctx context.Context, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.invites {
+		if i.ID == id && (i.MaxUses == 0 || i.Uses < i.MaxUses) {
+			i.Uses++
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memInviteRepo) Release(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.invites {
+		if i.ID == id && i.Uses > 0 {
+			i.Uses--
+		}
+	}
+	return nil
+}
+
+func (r *memInviteRepo) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n, i := range r.invites {
+		if i.ID == id {
+			r.invites = append(r.invites[:n], r.invites[n+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// memPendingRepo in-memory PendingUserRepository
+type memPendingRepo struct {
+	mu     sync.Mutex
+	nextID int64
+	users  []*domain.PendingUser
+}
+
+func (r *memPendingRepo) Create(ctx context.Context, user *domain.PendingUser) (*domain.PendingUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	user.ID = r.nextID
+	r.users = append(r.users, user)
+	return user, nil
+}
+
+func (r *memPendingRepo) GetByID(ctx context.Context, id int64) (*domain.PendingUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memPendingRepo) Exists(ctx context.Context, email, username string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Email == email || u.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memPendingRepo) List(ctx context.Context, offset, limit int) ([]*domain.PendingUser, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	total := int64(len(r.users))
+	if offset >= len(r.users) {
+		return nil, total, nil
+	}
+	end := min(offset+limit, len(r.users))
+	return append([]*domain.PendingUser(nil), r.users[offset:end]...), total, nil
+}
+
+func (r *memPendingRepo) Delete(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n, u := range r.users {
+		if u.ID == id {
+			r.users = append(r.users[:n], r.users[n+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func newRegistrationSvc(repo domain.UserRepository, registerEnabled, approval bool) (UserService, RegistrationService, *memPendingRepo) {
+	pending := &memPendingRepo{}
+	registration := NewRegistrationService(&memInviteRepo{}, pending, repo, zap.NewNop())
+	svc := NewUserService(repo, &mockTokenManager{}, &mockUserTokenService{}, nil, nil, zap.NewNop(), &ServiceConfig{
+		User: UserServiceConfig{RegisterIsEnable: registerEnabled, RegisterApproval: approval, AdminUID: 1},
+	})
+	svc.SetRegistration(registration)
+	return svc, registration, pending
+}
+
+func registerParams(name, inviteCode string) *dto.UserCreateRequest {
+	return &dto.UserCreateRequest{
+		Email:           name + "@example.com",
+		Username:        name,
+		Password:        "password123",
+		ConfirmPassword: "password123",
+		InviteCode:      inviteCode,
+	}
+}
+
+func expectNewUser(repo *domainmocks.MockUserRepository, name string, uid int64) {
+	repo.On("GetByEmail", mock.Anything, name+"@example.com").Return(nil, gorm.ErrRecordNotFound).Once()
+	repo.On("GetByUsername", mock.Anything, name).Return(nil, gorm.ErrRecordNotFound).Once()
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(u *domain.User) bool { return u.Username == name })).
+		Return(&domain.User{UID: uid, Email: name + "@example.com", Username: name}, nil).Once()
+}
+
+// TestUserService_Register_Invite verifies an invitation code admits registrations while open
+// registration is off, up to its maximum number of uses.
+// TestUserService_Register_Invite 验证开放注册关闭时邀请码仍可注册，且不超过其最大使用次数。
+func TestUserService_Register_Invite(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(domainmocks.MockUserRepository)
+	svc, registration, _ := newRegistrationSvc(mockRepo, false, true)
+
+	invite, err := registration.CreateInvite(ctx, 1, &dto.UserInviteCreateRequest{MaxUses: 1})
+	require.NoError(t, err)
+	assert.Len(t, invite.Code, inviteCodeLength)
+	assert.Nil(t, invite.ExpiresAt)
+
+	_, err = svc.Register(ctx, registerParams("alice", ""), "127.0.0.1", "WebGui", "")
+	assert.ErrorIs(t, err, code.ErrorUserRegisterIsDisable)
+
+	// The invited user is created right away even in approval mode
+	// 即使处于审核模式，受邀用户也会立即创建
+	expectNewUser(mockRepo, "alice", 2)
+	user, err := svc.Register(ctx, registerParams("alice", invite.Code), "127.0.0.1", "WebGui", "")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	assert.Equal(t, int64(2), user.UID)
+
+	mockRepo.On("GetByEmail", mock.Anything, "bob@example.com").Return(nil, gorm.ErrRecordNotFound).Once()
+	mockRepo.On("GetByUsername", mock.Anything, "bob").Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err = svc.Register(ctx, registerParams("bob", invite.Code), "127.0.0.1", "WebGui", "")
+	assert.ErrorIs(t, err, code.ErrorInviteCodeInvalid)
+
+	invites, err := registration.ListInvites(ctx)
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	assert.Equal(t, int64(1), invites[0].Uses)
+	assert.False(t, invites[0].Usable)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestUserService_Register_InviteReleasedOnFailure verifies a failed user insert gives the invitation use back.
+// TestUserService_Register_InviteReleasedOnFailure 验证用户插入失败时会退还邀请码的使用次数。
+func TestUserService_Register_InviteReleasedOnFailure(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(domainmocks.MockUserRepository)
+	svc, registration, _ := newRegistrationSvc(mockRepo, false, false)
+
+	invite, err := registration.CreateInvite(ctx, 1, &dto.UserInviteCreateRequest{MaxUses: 1})
+	require.NoError(t, err)
+
+	mockRepo.On("GetByEmail", mock.Anything, "alice@example.com").Return(nil, gorm.ErrRecordNotFound).Once()
+	mockRepo.On("GetByUsername", mock.Anything, "alice").Return(nil, gorm.ErrRecordNotFound).Once()
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("UNIQUE constraint failed: user.email")).Once()
+	_, err = svc.Register(ctx, registerParams("alice", invite.Code), "127.0.0.1", "WebGui", "")
+	assert.ErrorIs(t, err, code.ErrorUserRegister)
+
+	invites, err := registration.ListInvites(ctx)
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	assert.Zero(t, invites[0].Uses)
+	assert.True(t, invites[0].Usable)
+
+	// The code still admits the registration it was issued for
+	// 邀请码仍可用于其本应接纳的注册
+	expectNewUser(mockRepo, "alice", 2)
+	user, err := svc.Register(ctx, registerParams("alice", invite.Code), "127.0.0.1", "WebGui", "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), user.UID)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestRegistrationService_Invite verifies expired, deleted and unknown codes are refused.
+// TestRegistrationService_Invite 验证已过期、已删除与不存在的邀请码会被拒绝。
+func TestRegistrationService_Invite(t *testing.T) {
+	ctx := context.Background()
+	inviteRepo := &memInviteRepo{}
+	registration := NewRegistrationService(inviteRepo, &memPendingRepo{}, nil, zap.NewNop())
+
+	_, err := registration.CreateInvite(ctx, 1, &dto.UserInviteCreateRequest{ExpiresIn: "soon"})
+	assert.ErrorIs(t, err, code.ErrorInvalidParams)
+
+	invite, err := registration.CreateInvite(ctx, 1, &dto.UserInviteCreateRequest{ExpiresIn: "7d", Note: "team"})
+	require.NoError(t, err)
+	require.NotNil(t, invite.ExpiresAt)
+	assert.True(t, invite.Usable)
+
+	// Unlimited codes keep working
+	// 不限次数的邀请码可持续使用
+	require.NoError(t, registration.UseInvite(ctx, invite.Code))
+	require.NoError(t, registration.UseInvite(ctx, " "+invite.Code+" "))
+
+	inviteRepo.invites[0].ExpiresAt = time.Now().Add(-time.Second)
+	assert.ErrorIs(t, registration.UseInvite(ctx, invite.Code), code.ErrorInviteCodeInvalid)
+	assert.ErrorIs(t, registration.UseInvite(ctx, "unknown"), code.ErrorInviteCodeInvalid)
+
+	require.NoError(t, registration.DeleteInvite(ctx, invite.ID))
+	assert.ErrorIs(t, registration.DeleteInvite(ctx, invite.ID), code.ErrorInviteNotFound)
+	assert.ErrorIs(t, registration.UseInvite(ctx, invite.Code), code.ErrorInviteCodeInvalid)
+}
+
+// TestUserService_Register_Approval verifies registrations wait in the queue until approved or
+// rejected, and that queued names cannot be registered twice.
+// TestUserService_Register_Approval 验证注册在审核队列中等待通过或拒绝，且排队中的名称不能重复注册。
+func TestUserService_Register_Approval(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(domainmocks.MockUserRepository)
+	svc, registration, pending := newRegistrationSvc(mockRepo, true, true)
+
+	for _, name := range []string{"alice", "bob"} {
+		mockRepo.On("GetByEmail", mock.Anything, name+"@example.com").Return(nil, gorm.ErrRecordNotFound).Once()
+		mockRepo.On("GetByUsername", mock.Anything, name).Return(nil, gorm.ErrRecordNotFound).Once()
+		user, err := svc.Register(ctx, registerParams(name, ""), "10.0.0.1", "WebGui", "")
+		require.NoError(t, err)
+		assert.Nil(t, user)
+	}
+	assert.NotEqual(t, "password123", pending.users[0].Password)
+
+	mockRepo.On("GetByEmail", mock.Anything, "alice@example.com").Return(nil, gorm.ErrRecordNotFound).Once()
+	mockRepo.On("GetByUsername", mock.Anything, "alice").Return(nil, gorm.ErrRecordNotFound).Once()
+	_, err := svc.Register(ctx, registerParams("alice", ""), "10.0.0.1", "WebGui", "")
+	assert.ErrorIs(t, err, code.ErrorUserAlreadyExists)
+
+	list, total, err := registration.ListPending(ctx, &app.Pager{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, list, 2)
+	assert.Equal(t, "alice", list[0].Username)
+	assert.Equal(t, "10.0.0.1", list[0].IP)
+
+	expectNewUser(mockRepo, "alice", 2)
+	user, err := registration.Approve(ctx, list[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), user.UID)
+
+	require.NoError(t, registration.Reject(ctx, list[1].ID))
+	assert.Empty(t, pending.users)
+	assert.ErrorIs(t, registration.Reject(ctx, list[1].ID), code.ErrorPendingUserNotFound)
+	_, err = registration.Approve(ctx, list[0].ID)
+	assert.ErrorIs(t, err, code.ErrorPendingUserNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+// TestRegistrationService_Approve_Conflict verifies an approval is refused when the name was taken
+// while the registration waited.
+// TestRegistrationService_Approve_Conflict 验证注册等待期间名称已被占用时拒绝通过审核。
+func TestRegistrationService_Approve_Conflict(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(domainmocks.MockUserRepository)
+	pending := &memPendingRepo{}
+	registration := NewRegistrationService(&memInviteRepo{}, pending, mockRepo, zap.NewNop())
+	require.NoError(t, registration.Enqueue(ctx, &domain.PendingUser{Email: "alice@example.com", Username: "alice", Password: "hash"}))
+
+	mockRepo.On("GetByEmail", mock.Anything, "alice@example.com").Return(&domain.User{UID: 3}, nil)
+	_, err := registration.Approve(ctx, 1)
+	assert.ErrorIs(t, err, code.ErrorUserEmailAlreadyExists)
+	assert.Len(t, pending.users, 1)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ReindexService defines the background full-text index rebuild business service interface
+// ReindexService 定义后台全文索引重建业务服务接口
+type ReindexService interface {
+	// Start queues a rebuild of all vaults of a user, or of every user when uid is 0
+	// Start 为用户的全部仓库加入索引重建任务，uid 为 0 时为所有用户加入
+	Start(ctx context.Context, uid int64) ([]*dto.ReindexJobDTO, error)
+
+	// List returns the latest reindex job of every user, most recently queued first
+	// List 返回所有用户最近一次的索引重建任务，按入队时间倒序
+	List(ctx context.Context) ([]*dto.ReindexJobDTO, error)
+
+	// Get returns the latest reindex job of a user
+	// Get 返回用户最近一次的索引重建任务
+	Get(ctx context.Context, uid int64) (*dto.ReindexJobDTO, error)
+
+	// Cancel cancels a user's queued or running reindex job
+	// Cancel 取消用户排队或运行中的索引重建任务
+	Cancel(ctx context.Context, uid int64) (*dto.ReindexJobDTO, error)
+}
+
+// reindexService implements ReindexService
+// reindexService 实现 ReindexService 接口
+type reindexService struct {
+	ftsRepo  domain.NoteFTSRepository
+	userRepo domain.UserRepository
+	enabled  bool
+	logger   *zap.Logger
+}
+
+// NewReindexService creates a ReindexService instance
+// NewReindexService 创建 ReindexService 实例
+func NewReindexService(ftsRepo domain.NoteFTSRepository, userRepo domain.UserRepository, enabled bool, logger *zap.Logger) ReindexService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &reindexService{
+		ftsRepo:  ftsRepo,
+		userRepo: userRepo,
+		enabled:  enabled,
+		logger:   logger,
+	}
+}
+
+// reindexJobToDTO converts the domain model to the DTO
+// reindexJobToDTO 将领域模型转换为 DTO
+func reindexJobToDTO(job *domain.FTSReindexJob) *dto.ReindexJobDTO {
+	res := &dto.ReindexJobDTO{
+		UID:         job.UID,
+		Status:      string(job.Status),
+		VaultsTotal: job.VaultsTotal,
+		VaultsDone:  job.VaultsDone,
+		NotesTotal:  job.NotesTotal,
+		NotesDone:   job.NotesDone,
+		Error:       job.Error,
+		QueuedAt:    timex.Time(job.QueuedAt),
+	}
+	if !job.StartedAt.IsZero() {
+		startedAt := timex.Time(job.StartedAt)
+		res.StartedAt = &startedAt
+	}
+	if !job.FinishedAt.IsZero() {
+		finishedAt := timex.Time(job.FinishedAt)
+		res.FinishedAt = &finishedAt
+	}
+	return res
+}
+
+// Start queues a rebuild of all vaults of a user, or of every user when uid is 0
+// Start 为用户的全部仓库加入索引重建任务，uid 为 0 时为所有用户加入
+func (s *reindexService) Start(ctx context.Context, uid int64) ([]*dto.ReindexJobDTO, error) {
+	if !s.enabled {
+		return nil, code.ErrorSearchIndexDisabled
+	}
+
+	uids := []int64{uid}
+	if uid == 0 {
+		all, err := s.userRepo.GetAllUIDs(ctx)
+		if err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		uids = all
+	} else if _, err := s.userRepo.GetByUID(ctx, uid, false); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, code.ErrorUserNotFound
+		}
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	jobs := make([]*dto.ReindexJobDTO, 0, len(uids))
+	for _, id := range uids {
+		jobs = append(jobs, reindexJobToDTO(s.ftsRepo.EnqueueReindex(id, nil)))
+	}
+	s.logger.Info("fts reindex queued", zap.Int64("uid", uid), zap.Int("jobs", len(jobs)))
+	return jobs, nil
+}
+
+// List returns the latest reindex job of every user, most recently queued first
+// List 返回所有用户最近一次的索引重建任务，按入队时间倒序
+func (s *reindexService) List(ctx context.Context) ([]*dto.ReindexJobDTO, error) {
+	jobs := s.ftsRepo.ReindexJobs()
+	res := make([]*dto.ReindexJobDTO, 0, len(jobs))
+	for _, job := range jobs {
+		res = append(res, reindexJobToDTO(job))
+	}
+	return res, nil
+}
+
+// Get returns the latest reindex job of a user
+// Get 返回用户最近一次的索引重建任务
+func (s *reindexService) Get(ctx context.Context, uid int64) (*dto.ReindexJobDTO, error) {
+	job, ok := s.ftsRepo.ReindexJob(uid)
+	if !ok {
+		return nil, code.ErrorReindexJobNotFound
+	}
+	return reindexJobToDTO(job), nil
+}
+
+// Cancel cancels a user's queued or running reindex job
+// Cancel 取消用户排队或运行中的索引重建任务
+func (s *reindexService) Cancel(ctx context.Context, uid int64) (*dto.ReindexJobDTO, error) {
+	if !s.ftsRepo.CancelReindex(uid) {
+		return nil, code.ErrorReindexJobNotFound
+	}
+	return s.Get(ctx, uid)
+}
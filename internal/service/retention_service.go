@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// SoftDeletePurgeFunc physically deletes the soft-deleted records of a user older than timestamp,
+// only in vaultIDs or, with exclude, outside them
+// SoftDeletePurgeFunc 物理删除用户早于 timestamp 的软删除记录，仅限 vaultIDs 中的保险库，exclude 时则排除这些保险库
+type SoftDeletePurgeFunc func(ctx context.Context, timestamp int64, vaultIDs []int64, exclude bool, uid int64) error
+
+// RetentionService resolves the soft delete retention of users and vaults, the server default
+// being overridden per user and then per vault
+// RetentionService 解析用户与保险库的软删除保留时间，服务器默认值可被用户级、再被保险库级覆盖
+type RetentionService interface {
+	// GetUserSettings returns the settings of a user
+	// GetUserSettings 返回用户设置
+	GetUserSettings(ctx context.Context, uid int64) (*dto.UserSettingsDTO, error)
+
+	// UpdateUserSettings updates the settings of a user
+	// UpdateUserSettings 更新用户设置
+	UpdateUserSettings(ctx context.Context, uid int64, params *dto.UserSettingsRequest) (*dto.UserSettingsDTO, error)
+
+	// ListVaults returns the retention of every vault of a user
+	// ListVaults 返回用户每个保险库的保留时间
+	ListVaults(ctx context.Context, uid int64) ([]*dto.VaultRetentionDTO, error)
+
+	// UpdateVault sets or, with an empty retention, removes the override of a vault
+	// UpdateVault 设置保险库的覆盖值，保留时间为空时删除覆盖
+	UpdateVault(ctx context.Context, uid int64, params *dto.VaultRetentionRequest) (*dto.VaultRetentionDTO, error)
+
+	// Purge purges the soft-deleted records of a user; defaultCutoff is the cutoff of the server
+	// default in milliseconds, 0 when it keeps deleted records forever
+	// Purge 清理用户的软删除记录；defaultCutoff 为服务器默认值对应的截止时间（毫秒），永久保留时为 0
+	Purge(ctx context.Context, uid int64, defaultCutoff int64, purge SoftDeletePurgeFunc) error
+
+	// PurgeAll purges the soft-deleted records of all users, see Purge
+	// PurgeAll 清理所有用户的软删除记录，参见 Purge
+	PurgeAll(ctx context.Context, defaultCutoff int64, purge SoftDeletePurgeFunc) error
+}
+
+// retentionService implements RetentionService
+// retentionService 实现 RetentionService 接口
+type retentionService struct {
+	repo      domain.RetentionPolicyRepository
+	vaultRepo domain.VaultRepository
+	userRepo  domain.UserRepository
+	config    *ServiceConfig // Read on every call so a changed server default applies at once // 每次调用时读取，服务器默认值修改后立即生效
+	logger    *zap.Logger
+}
+
+// NewRetentionService creates a RetentionService instance
+// NewRetentionService 创建 RetentionService 实例
+func NewRetentionService(repo domain.RetentionPolicyRepository, vaultRepo domain.VaultRepository, userRepo domain.UserRepository, logger *zap.Logger, config *ServiceConfig) RetentionService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	s := &retentionService{
+		repo:      repo,
+		vaultRepo: vaultRepo,
+		userRepo:  userRepo,
+		config:    config,
+		logger:    logger,
+	}
+	return s
+}
+
+// defaultRetention returns the server-wide soft delete retention
+// defaultRetention 返回服务器级软删除保留时间
+func (s *retentionService) defaultRetention() string {
+	if s.config == nil {
+		return ""
+	}
+	return s.config.App.SoftDeleteRetentionTime
+}
+
+// normalizeRetention validates a retention and returns it trimmed, empty meaning no override
+// normalizeRetention 校验保留时间并返回去除空白后的值，空值表示不覆盖
+func normalizeRetention(retention string) (string, error) {
+	retention = strings.TrimSpace(retention)
+	if retention == "" {
+		return "", nil
+	}
+	d, err := util.ParseDuration(retention)
+	if err != nil || d < 0 {
+		return "", code.ErrorInvalidParams.WithDetails("invalid softDeleteRetentionTime")
+	}
+	return retention, nil
+}
+
+// retentionCutoff returns the cutoff in milliseconds of a retention, 0 when deleted records are kept forever
+// retentionCutoff 返回保留时间对应的截止时间（毫秒），永久保留时为 0
+func retentionCutoff(retention string, now time.Time) int64 {
+	d, err := util.ParseDuration(retention)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return now.Add(-d).UnixMilli()
+}
+
+// softDeleteCutoff returns the cutoff in milliseconds of the server default retention, 0 when
+// deleted records are kept forever
+// softDeleteCutoff 返回服务器默认保留时间对应的截止时间（毫秒），永久保留时为 0
+func softDeleteCutoff(retention string) (int64, error) {
+	if retention == "" || retention == "0" {
+		return 0, nil
+	}
+	if _, err := util.ParseDuration(retention); err != nil {
+		return 0, err
+	}
+	return retentionCutoff(retention, time.Now()), nil
+}
+
+// displayRetention returns a retention as shown to users, "0" for the ones keeping deleted records forever
+// displayRetention 返回展示给用户的保留时间，永久保留统一为 "0"
+func displayRetention(retention string) string {
+	if d, err := util.ParseDuration(retention); err != nil || d <= 0 {
+		return "0"
+	}
+	return retention
+}
+
+// userOverride returns the user-wide override and the overrides per vault
+// userOverride 返回用户级覆盖值与各保险库的覆盖值
+func (s *retentionService) userOverride(ctx context.Context, uid int64) (string, map[int64]string, error) {
+	policies, err := s.repo.List(ctx, uid)
+	if err != nil {
+		return "", nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	user := ""
+	vaults := make(map[int64]string, len(policies))
+	for _, p := range policies {
+		if p.VaultID == 0 {
+			user = p.SoftDeleteRetention
+		} else {
+			vaults[p.VaultID] = p.SoftDeleteRetention
+		}
+	}
+	return user, vaults, nil
+}
+
+// userSettingsDTO builds the settings of a user from its user-wide override
+// userSettingsDTO 根据用户级覆盖值构建用户设置
+func (s *retentionService) userSettingsDTO(user string) *dto.UserSettingsDTO {
+	result := &dto.UserSettingsDTO{
+		SoftDeleteRetentionTime:        user,
+		DefaultSoftDeleteRetentionTime: displayRetention(s.defaultRetention()),
+	}
+	result.EffectiveSoftDeleteRetentionTime = result.DefaultSoftDeleteRetentionTime
+	if user != "" {
+		result.EffectiveSoftDeleteRetentionTime = displayRetention(user)
+	}
+	return result
+}
+
+// GetUserSettings implements RetentionService
+// GetUserSettings 实现 RetentionService
+func (s *retentionService) GetUserSettings(ctx context.Context, uid int64) (*dto.UserSettingsDTO, error) {
+	user, _, err := s.userOverride(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return s.userSettingsDTO(user), nil
+}
+
+// UpdateUserSettings implements RetentionService
+// UpdateUserSettings 实现 RetentionService
+func (s *retentionService) UpdateUserSettings(ctx context.Context, uid int64, params *dto.UserSettingsRequest) (*dto.UserSettingsDTO, error) {
+	if params.SoftDeleteRetentionTime != nil {
+		retention, err := normalizeRetention(*params.SoftDeleteRetentionTime)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.save(ctx, 0, retention, uid); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetUserSettings(ctx, uid)
+}
+
+// save stores or, when empty, removes an override
+// save 保存覆盖值，为空时删除
+func (s *retentionService) save(ctx context.Context, vaultID int64, retention string, uid int64) error {
+	var err error
+	if retention == "" {
+		err = s.repo.Delete(ctx, vaultID, uid)
+	} else {
+		err = s.repo.Save(ctx, vaultID, retention, uid)
+	}
+	if err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// ListVaults implements RetentionService
+// ListVaults 实现 RetentionService
+func (s *retentionService) ListVaults(ctx context.Context, uid int64) ([]*dto.VaultRetentionDTO, error) {
+	vaults, err := s.vaultRepo.List(ctx, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	user, overrides, err := s.userOverride(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	inherited := s.userSettingsDTO(user).EffectiveSoftDeleteRetentionTime
+
+	list := make([]*dto.VaultRetentionDTO, 0, len(vaults))
+	for _, v := range vaults {
+		item := &dto.VaultRetentionDTO{
+			VaultID:                          v.ID,
+			Vault:                            v.Name,
+			SoftDeleteRetentionTime:          overrides[v.ID],
+			EffectiveSoftDeleteRetentionTime: inherited,
+		}
+		if item.SoftDeleteRetentionTime != "" {
+			item.EffectiveSoftDeleteRetentionTime = displayRetention(item.SoftDeleteRetentionTime)
+		}
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// UpdateVault implements RetentionService
+// UpdateVault 实现 RetentionService
+func (s *retentionService) UpdateVault(ctx context.Context, uid int64, params *dto.VaultRetentionRequest) (*dto.VaultRetentionDTO, error) {
+	retention, err := normalizeRetention(params.SoftDeleteRetentionTime)
+	if err != nil {
+		return nil, err
+	}
+	vault, err := s.vaultRepo.GetByName(ctx, params.Vault, uid)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if vault == nil {
+		return nil, code.ErrorVaultNotFound
+	}
+	if err := s.save(ctx, vault.ID, retention, uid); err != nil {
+		return nil, err
+	}
+
+	list, err := s.ListVaults(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range list {
+		if item.VaultID == vault.ID {
+			return item, nil
+		}
+	}
+	return nil, code.ErrorVaultNotFound
+}
+
+// Purge implements RetentionService
+// Purge 实现 RetentionService
+func (s *retentionService) Purge(ctx context.Context, uid int64, defaultCutoff int64, purge SoftDeletePurgeFunc) error {
+	policies, err := s.repo.List(ctx, uid)
+	if err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	now := time.Now()
+	userCutoff := defaultCutoff
+	var overridden []int64
+	for _, p := range policies {
+		if p.VaultID == 0 {
+			userCutoff = retentionCutoff(p.SoftDeleteRetention, now)
+		} else {
+			overridden = append(overridden, p.VaultID)
+		}
+	}
+
+	// Vaults with their own retention are left out of the user-wide purge and purged one by one
+	// 单独设置了保留时间的保险库不参与用户级清理，而是逐个清理
+	var errs []error
+	if userCutoff > 0 {
+		if err := purge(ctx, userCutoff, overridden, true, uid); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, p := range policies {
+		if p.VaultID == 0 {
+			continue
+		}
+		cutoff := retentionCutoff(p.SoftDeleteRetention, now)
+		if cutoff == 0 {
+			continue
+		}
+		if err := purge(ctx, cutoff, []int64{p.VaultID}, false, uid); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return code.ErrorDBQuery.WithDetails(errors.Join(errs...).Error())
+	}
+	return nil
+}
+
+// PurgeAll implements RetentionService
+// PurgeAll 实现 RetentionService
+func (s *retentionService) PurgeAll(ctx context.Context, defaultCutoff int64, purge SoftDeletePurgeFunc) error {
+	uids, err := s.userRepo.GetAllUIDs(ctx)
+	if err != nil {
+		return err
+	}
+	// Errors are logged and the other users still purged
+	// 记录错误但继续处理其他用户
+	for _, uid := range uids {
+		if err := s.Purge(ctx, uid, defaultCutoff, purge); err != nil {
+			s.logger.Warn("RetentionService.PurgeAll failed", zap.Int64("uid", uid), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// Ensure retentionService implements RetentionService
+// 确保 retentionService 实现了 RetentionService 接口
+var _ RetentionService = (*retentionService)(nil)
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeRetentionPolicyRepo in-memory domain.RetentionPolicyRepository keyed by vault ID
+type fakeRetentionPolicyRepo struct {
+	policies map[int64]string
+}
+
+func (r *fakeRetentionPolicyRepo) List(ctx context.Context, uid int64) ([]*domain.RetentionPolicy, error) {
+	var list []*domain.RetentionPolicy
+	for vaultID, retention := range r.policies {
+		list = append(list, &domain.RetentionPolicy{UID: uid, VaultID: vaultID, SoftDeleteRetention: retention})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].VaultID < list[j].VaultID })
+	return list, nil
+}
+
+func (r *fakeRetentionPolicyRepo) Save(ctx context.Context, vaultID int64, retention string, uid int64) error {
+	r.policies[vaultID] = retention
+	return nil
+}
+
+func (r *fakeRetentionPolicyRepo) Delete(ctx context.Context, vaultID int64, uid int64) error {
+	delete(r.policies, vaultID)
+	return nil
+}
+
+// purgeCall a recorded call of a SoftDeletePurgeFunc
+type purgeCall struct {
+	cutoff   int64
+	vaultIDs []int64
+	exclude  bool
+}
+
+// TestRetentionService_Purge verifies the vaults with their own retention are left out of the
+// user-wide purge and purged with their own cutoff, never when kept forever.
+// TestRetentionService_Purge 验证单独设置保留时间的保险库不参与用户级清理，而是按各自的截止时间清理，
+// 永久保留时不清理。
+func TestRetentionService_Purge(t *testing.T) {
+	repo := &fakeRetentionPolicyRepo{policies: map[int64]string{1: "7d", 2: "0"}}
+	svc := NewRetentionService(repo, nil, nil, zap.NewNop(), nil)
+
+	var calls []purgeCall
+	purge := func(ctx context.Context, timestamp int64, vaultIDs []int64, exclude bool, uid int64) error {
+		calls = append(calls, purgeCall{cutoff: timestamp, vaultIDs: vaultIDs, exclude: exclude})
+		return nil
+	}
+
+	defaultCutoff := time.Now().AddDate(0, 0, -90).UnixMilli()
+	require.NoError(t, svc.Purge(context.Background(), 1, defaultCutoff, purge))
+	require.Len(t, calls, 2)
+	assert.Equal(t, purgeCall{cutoff: defaultCutoff, vaultIDs: []int64{1, 2}, exclude: true}, calls[0])
+	assert.Equal(t, []int64{1}, calls[1].vaultIDs)
+	assert.False(t, calls[1].exclude)
+	assert.InDelta(t, time.Now().AddDate(0, 0, -7).UnixMilli(), calls[1].cutoff, float64(time.Minute.Milliseconds()))
+
+	// A user-wide override of 0 keeps everything but the vault overrides, even with a server default
+	// 用户级覆盖为 0 时，即使服务器有默认值也只清理设置了覆盖的保险库
+	repo.policies[0] = "0"
+	calls = nil
+	require.NoError(t, svc.Purge(context.Background(), 1, defaultCutoff, purge))
+	require.Len(t, calls, 1)
+	assert.Equal(t, []int64{1}, calls[0].vaultIDs)
+
+	// Without a server default the user-wide override still applies
+	// 服务器默认永久保留时，用户级覆盖仍然生效
+	repo.policies = map[int64]string{0: "30d"}
+	calls = nil
+	require.NoError(t, svc.Purge(context.Background(), 1, 0, purge))
+	require.Len(t, calls, 1)
+	assert.True(t, calls[0].exclude)
+	assert.Empty(t, calls[0].vaultIDs)
+}
+
+// TestRetentionService_Settings verifies the user and vault overrides are validated, stored and
+// resolved against the server default.
+// TestRetentionService_Settings 验证用户与保险库覆盖值的校验、保存以及相对服务器默认值的解析。
+func TestRetentionService_Settings(t *testing.T) {
+	repo := &fakeRetentionPolicyRepo{policies: map[int64]string{}}
+	vaultRepo := newVaultMockRepo()
+	vaultRepo.On("List", mock.Anything, int64(1)).Return([]*domain.Vault{newVault(1, "Work"), newVault(2, "Home")}, nil)
+	vaultRepo.On("GetByName", mock.Anything, "Work", int64(1)).Return(newVault(1, "Work"), nil)
+	vaultRepo.On("GetByName", mock.Anything, "Missing", int64(1)).Return(nil, nil)
+	svc := NewRetentionService(repo, vaultRepo, nil, zap.NewNop(), &ServiceConfig{App: AppServiceConfig{SoftDeleteRetentionTime: "90d"}})
+	ctx := context.Background()
+
+	settings, err := svc.GetUserSettings(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "", settings.SoftDeleteRetentionTime)
+	assert.Equal(t, "90d", settings.EffectiveSoftDeleteRetentionTime)
+
+	invalid := "soon"
+	_, err = svc.UpdateUserSettings(ctx, 1, &dto.UserSettingsRequest{SoftDeleteRetentionTime: &invalid})
+	assert.ErrorIs(t, err, code.ErrorInvalidParams)
+
+	forever := "0"
+	settings, err = svc.UpdateUserSettings(ctx, 1, &dto.UserSettingsRequest{SoftDeleteRetentionTime: &forever})
+	require.NoError(t, err)
+	assert.Equal(t, "0", settings.EffectiveSoftDeleteRetentionTime)
+
+	item, err := svc.UpdateVault(ctx, 1, &dto.VaultRetentionRequest{Vault: "Work", SoftDeleteRetentionTime: "7d"})
+	require.NoError(t, err)
+	assert.Equal(t, "7d", item.EffectiveSoftDeleteRetentionTime)
+
+	list, err := svc.ListVaults(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "7d", list[0].SoftDeleteRetentionTime)
+	assert.Equal(t, "", list[1].SoftDeleteRetentionTime)
+	assert.Equal(t, "0", list[1].EffectiveSoftDeleteRetentionTime)
+
+	item, err = svc.UpdateVault(ctx, 1, &dto.VaultRetentionRequest{Vault: "Work"})
+	require.NoError(t, err)
+	assert.Equal(t, "0", item.EffectiveSoftDeleteRetentionTime)
+	assert.NotContains(t, repo.policies, int64(1))
+
+	_, err = svc.UpdateVault(ctx, 1, &dto.VaultRetentionRequest{Vault: "Missing", SoftDeleteRetentionTime: "7d"})
+	assert.ErrorIs(t, err, code.ErrorVaultNotFound)
+}
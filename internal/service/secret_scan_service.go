@@ -0,0 +1,88 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/secretscan"
+	"go.uber.org/zap"
+)
+
+// SecretScanService defines the note secret scanning service interface
+// SecretScanService 定义笔记敏感凭据扫描服务接口
+type SecretScanService interface {
+	// Check scans note content for credentials. Findings are always returned so the caller
+	// can warn the user; in strict mode a non-empty result also returns ErrorNoteSecretDetected.
+	// Check 扫描笔记内容中的凭据。始终返回结果以便调用方提醒用户；
+	// 严格模式下若存在结果，同时返回 ErrorNoteSecretDetected。
+	Check(ctx context.Context, uid int64, path, content string) ([]dto.SecretFindingDTO, error)
+
+	// Guard rejects content holding credentials with ErrorNoteSecretDetected in strict mode and
+	// does nothing otherwise; it backs every note write so no write path can skip strict mode
+	// Guard 严格模式下对包含凭据的内容返回 ErrorNoteSecretDetected，否则不做任何处理；
+	// 所有笔记写入都经过它，任何写入路径都无法绕过严格模式
+	Guard(ctx context.Context, uid int64, path, content string) error
+}
+
+type secretScanService struct {
+	scanner *secretscan.Scanner // nil when scanning is disabled // 关闭扫描时为 nil
+	strict  bool
+	logger  *zap.Logger
+}
+
+// NewSecretScanService creates SecretScanService instance, a nil scanner disables scanning
+// NewSecretScanService 创建 SecretScanService 实例，scanner 为 nil 时关闭扫描
+func NewSecretScanService(scanner *secretscan.Scanner, strict bool, logger *zap.Logger) SecretScanService {
+	return &secretScanService{
+		scanner: scanner,
+		strict:  strict,
+		logger:  logger,
+	}
+}
+
+// Check implements SecretScanService
+func (s *secretScanService) Check(ctx context.Context, uid int64, path, content string) ([]dto.SecretFindingDTO, error) {
+	findings := s.scanner.Scan(content)
+	if len(findings) == 0 {
+		return nil, nil
+	}
+
+	result := make([]dto.SecretFindingDTO, 0, len(findings))
+	rules := make([]string, 0, len(findings))
+	for _, f := range findings {
+		result = append(result, dto.SecretFindingDTO{
+			Rule:   f.Rule,
+			Line:   f.Line,
+			Column: f.Column,
+			Match:  f.Match,
+		})
+		rules = append(rules, f.Rule)
+	}
+
+	// Never log the matched values themselves
+	// 不记录匹配到的原始值
+	s.logger.Warn("secret scan: credentials detected in note",
+		zap.Int64("uid", uid),
+		zap.String("path", path),
+		zap.Strings("rules", rules),
+		zap.Bool("blocked", s.strict))
+
+	if s.strict {
+		return result, code.ErrorNoteSecretDetected
+	}
+	return result, nil
+}
+
+// Guard implements SecretScanService
+func (s *secretScanService) Guard(ctx context.Context, uid int64, path, content string) error {
+	if !s.strict {
+		return nil
+	}
+	_, err := s.Check(ctx, uid, path, content)
+	return err
+}
+
+var _ SecretScanService = (*secretScanService)(nil)
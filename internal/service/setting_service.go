@@ -6,7 +6,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
@@ -14,7 +13,6 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
-	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"go.uber.org/zap"
 	"golang.org/x/sync/singleflight"
 	"gorm.io/gorm"
@@ -90,6 +88,7 @@ type settingService struct {
 	settingRepo    domain.SettingRepository // Setting repository // 配置仓库
 	vaultService   VaultService             // Vault service // 仓库服务
 	syncLogService SyncLogService           // Sync log service // 同步日志服务
+	retention      RetentionService         // Soft delete retention overrides // 软删除保留时间覆盖
 	sf             *singleflight.Group      // Singleflight group // 并发请求合并组
 	clientType     string                   // Client type // 客户端类型
 	clientName     string                   // Client name // 客户端名称
@@ -99,11 +98,12 @@ type settingService struct {
 
 // NewSettingService creates SettingService instance
 // NewSettingService 创建 SettingService 实例
-func NewSettingService(settingRepo domain.SettingRepository, vaultSvc VaultService, syncLogSvc SyncLogService, config *ServiceConfig) SettingService {
+func NewSettingService(settingRepo domain.SettingRepository, vaultSvc VaultService, syncLogSvc SyncLogService, retentionSvc RetentionService, config *ServiceConfig) SettingService {
 	return &settingService{
 		settingRepo:    settingRepo,
 		vaultService:   vaultSvc,
 		syncLogService: syncLogSvc,
+		retention:      retentionSvc,
 		sf:             &singleflight.Group{},
 		config:         config,
 	}
@@ -116,6 +116,7 @@ func (s *settingService) WithClient(clientType, name, version string) SettingSer
 		settingRepo:    s.settingRepo,
 		vaultService:   s.vaultService,
 		syncLogService: s.syncLogService,
+		retention:      s.retention,
 		sf:             s.sf,
 		clientType:     clientType,
 		clientName:     name,
@@ -156,6 +157,20 @@ func (s *settingService) UpdateCheck(ctx context.Context, uid int64, params *dto
 	}
 
 	setting, _ := s.settingRepo.GetByPathHash(ctx, params.PathHash, vaultID, uid)
+	mode, settingDTO, err := s.evalUpdateCheck(ctx, uid, setting, params)
+	if trace := s.config.syncTrace(uid); trace != nil {
+		var record *updateCheckRecord
+		if setting != nil {
+			record = &updateCheckRecord{ID: setting.ID, ContentHash: setting.ContentHash, Mtime: setting.Mtime, Action: string(setting.Action)}
+		}
+		traceUpdateCheck(trace, "SettingUpdateCheck", params.Vault, params.Path, params.ContentHash, params.Mtime, record, mode, err)
+	}
+	return mode, settingDTO, err
+}
+
+// evalUpdateCheck decides how the client copy relates to the stored setting, which may be nil
+// evalUpdateCheck 判定客户端副本与已存储配置（可能为 nil）的关系
+func (s *settingService) evalUpdateCheck(ctx context.Context, uid int64, setting *domain.Setting, params *dto.SettingUpdateCheckRequest) (string, *dto.SettingDTO, error) {
 	if setting != nil {
 		settingDTO := s.domainToDTO(setting)
 
@@ -511,28 +526,17 @@ func (s *settingService) Cleanup(ctx context.Context, uid int64) error {
 	if s.config == nil {
 		return nil
 	}
-	retentionTimeStr := s.config.App.SoftDeleteRetentionTime
-	if retentionTimeStr == "" || retentionTimeStr == "0" {
-		return nil
-	}
-
-	retentionDuration, err := util.ParseDuration(retentionTimeStr)
+	cutoffTime, err := softDeleteCutoff(s.config.App.SoftDeleteRetentionTime)
 	if err != nil {
 		return err
 	}
-
-	if retentionDuration <= 0 {
-		return nil
-	}
-
-	cutoffTime := time.Now().Add(-retentionDuration).UnixMilli()
-	return s.settingRepo.DeletePhysicalByTime(ctx, cutoffTime, uid)
+	return s.retention.Purge(ctx, uid, cutoffTime, s.settingRepo.DeletePhysicalByTimeInVaults)
 }
 
 // CleanupByTime cleans up expired soft-deleted configurations for all users by cutoff time
 // CleanupByTime 按截止时间清理所有用户的过期软删除配置
 func (s *settingService) CleanupByTime(ctx context.Context, cutoffTime int64) error {
-	return s.settingRepo.DeletePhysicalByTimeAll(ctx, cutoffTime)
+	return s.retention.PurgeAll(ctx, cutoffTime, s.settingRepo.DeletePhysicalByTimeInVaults)
 }
 
 // ClearByVault clears all settings for a specific vault of a user
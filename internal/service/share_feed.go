@@ -0,0 +1,129 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/markdown"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// shareFeedMaxEntries most recently modified notes listed in a folder feed
+	// shareFeedMaxEntries 文件夹订阅源中列出的最近修改笔记数量
+	shareFeedMaxEntries = 20
+	// shareFeedSummaryRunes length of the plain-text summary of a feed entry
+	// shareFeedSummaryRunes 订阅源条目纯文本摘要的长度
+	shareFeedSummaryRunes = 280
+)
+
+// shareFeedResolver renders links as plain anchors, notes outside the feed and attachments are not shared
+// shareFeedResolver 将链接渲染为普通锚点，订阅源之外的笔记与附件均未分享
+type shareFeedResolver struct{}
+
+// ResolveLink implements markdown.Resolver
+// ResolveLink 实现 markdown.Resolver
+func (shareFeedResolver) ResolveLink(target string) (string, bool) {
+	return target, false
+}
+
+// ResolveEmbed implements markdown.Resolver
+// ResolveEmbed 实现 markdown.Resolver
+func (shareFeedResolver) ResolveEmbed(target string) (string, bool) {
+	return target, false
+}
+
+// GetSharedFolderFeed lists the most recently modified notes below a shared folder, rendered to HTML
+// GetSharedFolderFeed 列出分享文件夹下最近修改的笔记，并渲染为 HTML
+func (s *shareService) GetSharedFolderFeed(ctx context.Context, shareToken string, password string) (*dto.ShareFolderFeed, error) {
+	entity, err := s.tokenManager.ShareParse(shareToken)
+	if err != nil {
+		return nil, err
+	}
+	share, err := s.repo.GetByID(ctx, entity.UID, entity.SID)
+	if err != nil {
+		return nil, err
+	}
+	if share.ResType != "folder" {
+		return nil, domain.ErrShareCancelled
+	}
+	if _, err := s.VerifyShare(ctx, shareToken, strconv.FormatInt(share.ResID, 10), "folder", password); err != nil {
+		return nil, err
+	}
+
+	uid := share.UID
+	folder, err := s.folderRepo.GetByID(ctx, share.ResID, uid)
+	if err != nil || folder == nil || folder.IsDeleted() {
+		return nil, domain.ErrShareCancelled
+	}
+	vault, err := s.vaultRepo.GetByID(ctx, folder.VaultID, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := s.noteRepo.ListByUpdatedTimestamp(ctx, 0, folder.VaultID, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	prefix := folder.Path + "/"
+	var notes []*domain.Note
+	for _, n := range all {
+		if !n.IsDeleted() && strings.HasPrefix(n.Path, prefix) {
+			notes = append(notes, n)
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		if notes[i].Mtime != notes[j].Mtime {
+			return notes[i].Mtime > notes[j].Mtime
+		}
+		return notes[i].Path < notes[j].Path
+	})
+	if len(notes) > shareFeedMaxEntries {
+		notes = notes[:shareFeedMaxEntries]
+	}
+
+	feed := &dto.ShareFolderFeed{
+		ShareID:   share.ID,
+		Vault:     vault.Name,
+		Folder:    folder.Path,
+		Title:     path.Base(folder.Path),
+		Updated:   share.CreatedAt,
+		CreatedAt: share.CreatedAt,
+		Entries:   make([]*dto.ShareFeedEntry, 0, len(notes)),
+	}
+	for _, n := range notes {
+		_, body, _ := util.ParseFrontmatter(n.Content)
+		html, err := markdown.Render([]byte(body), shareFeedResolver{})
+		if err != nil {
+			s.logger.Warn("GetSharedFolderFeed render failed", zap.Error(err), zap.String("notePath", n.Path))
+			continue
+		}
+		title, summary := util.MarkdownExcerpt(n.Content, shareFeedSummaryRunes)
+		if title == "" {
+			title = strings.TrimSuffix(path.Base(n.Path), path.Ext(n.Path))
+		}
+		feed.Entries = append(feed.Entries, &dto.ShareFeedEntry{
+			NoteID:  n.ID,
+			Path:    strings.TrimPrefix(n.Path, prefix),
+			Title:   title,
+			Summary: summary,
+			HTML:    html,
+			Ctime:   n.Ctime,
+			Mtime:   n.Mtime,
+		})
+		if mtime := time.UnixMilli(n.Mtime); mtime.After(feed.Updated) {
+			feed.Updated = mtime
+		}
+	}
+	return feed, nil
+}
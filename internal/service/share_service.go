@@ -8,7 +8,6 @@ import (
 	"mime"
 	"net/http"
 	"net/url"
-	"os"
 	"path"
 	"path/filepath"
 	"regexp"
@@ -21,6 +20,7 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"github.com/haierkeys/fast-note-sync-service/pkg/shortlink"
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
@@ -91,6 +91,10 @@ type ShareService interface {
 	// GetSharedFileInfo 获取分享文件的元数据和路径，用于零拷贝下载
 	GetSharedFileInfo(ctx context.Context, shareToken string, fileID int64, password string) (savePath string, contentType string, mtime int64, etag string, fileName string, err error)
 
+	// GetSharedFolderFeed lists the most recently modified notes below a shared folder, rendered to HTML
+	// GetSharedFolderFeed 列出分享文件夹下最近修改的笔记，并渲染为 HTML
+	GetSharedFolderFeed(ctx context.Context, shareToken string, password string) (*dto.ShareFolderFeed, error)
+
 	// RecordView aggregates access statistics in memory
 	// RecordView 在内存中聚合访问统计
 	RecordView(uid int64, id int64)
@@ -143,6 +147,7 @@ type shareService struct {
 	tokenManager pkgapp.TokenManager        // Token manager // Token 管理器
 	noteRepo     domain.NoteRepository      // Note repository // 笔记仓库
 	fileRepo     domain.FileRepository      // File repository // 文件仓库
+	folderRepo   domain.FolderRepository    // Folder repository // 文件夹仓库
 	vaultRepo    domain.VaultRepository     // Vault repository // 仓库仓库
 	logger       *zap.Logger                // Logger // 日志器
 	config       *ServiceConfig             // Service configuration // 服务配置
@@ -158,12 +163,13 @@ type shareService struct {
 
 // NewShareService creates ShareService instance
 // NewShareService 创建 ShareService 实例
-func NewShareService(repo domain.UserShareRepository, tokenManager pkgapp.TokenManager, noteRepo domain.NoteRepository, fileRepo domain.FileRepository, vaultRepo domain.VaultRepository, logger *zap.Logger, config *ServiceConfig) ShareService {
+func NewShareService(repo domain.UserShareRepository, tokenManager pkgapp.TokenManager, noteRepo domain.NoteRepository, fileRepo domain.FileRepository, folderRepo domain.FolderRepository, vaultRepo domain.VaultRepository, logger *zap.Logger, config *ServiceConfig) ShareService {
 	s := &shareService{
 		repo:         repo,
 		tokenManager: tokenManager,
 		noteRepo:     noteRepo,
 		fileRepo:     fileRepo,
+		folderRepo:   folderRepo,
 		vaultRepo:    vaultRepo,
 		logger:       logger,
 		config:       config,
@@ -231,6 +237,12 @@ func (s *shareService) ShareGenerate(ctx context.Context, uid int64, vaultName s
 			mainType = "file"
 			fileIDStr := strconv.FormatInt(file.ID, 10)
 			resolvedResources["file"] = []string{fileIDStr}
+		} else if folder, err := s.folderRepo.GetByPathHash(ctx, pathHash, vaultID, uid); err == nil && folder != nil && !folder.IsDeleted() {
+			// A shared folder publishes the notes below it as a feed, they are resolved when the feed is read
+			// 分享的文件夹以订阅源形式发布其下的笔记，笔记在读取订阅源时解析
+			mainID = folder.ID
+			mainType = "folder"
+			resolvedResources["folder"] = []string{strconv.FormatInt(folder.ID, 10)}
 		} else {
 			return nil, code.ErrorFileNotFound.WithDetails("file not found: " + path)
 		}
@@ -567,6 +579,9 @@ func (s *shareService) ListShares(ctx context.Context, uid int64, sortBy string,
 		token, err := s.tokenManager.ShareGenerate(share.ID, uid, share.Resources)
 		if err == nil {
 			item.URL = "/share/" + strconv.FormatInt(share.ResID, 10) + "/" + token
+			if share.ResType == "folder" {
+				item.URL = "/feed/" + token + ".xml"
+			}
 		}
 
 		// Fill title from preloaded maps, no extra DB queries
@@ -587,6 +602,16 @@ func (s *shareService) ListShares(ctx context.Context, uid int64, sortBy string,
 			if file, ok := fileMap[share.ResID]; ok {
 				item.Title = filepath.Base(file.Path)
 			}
+		case "folder":
+			if folder, err := s.folderRepo.GetByID(ctx, share.ResID, uid); err == nil && folder != nil && !folder.IsDeleted() {
+				item.Title = path.Base(folder.Path)
+				if name, ok := vaultNameCache[folder.VaultID]; ok {
+					item.VaultName = name
+				} else if v, err := s.vaultRepo.GetByID(ctx, folder.VaultID, uid); err == nil && v != nil {
+					vaultNameCache[folder.VaultID] = v.Name
+					item.VaultName = v.Name
+				}
+			}
 		}
 
 		items = append(items, item)
@@ -616,6 +641,9 @@ func (s *shareService) GetShareByPath(ctx context.Context, uid int64, vaultName
 		if err == nil && file != nil {
 			resID = file.ID
 			resType = "file"
+		} else if folder, err := s.folderRepo.GetByPathHash(ctx, pathHash, vault.ID, uid); err == nil && folder != nil {
+			resID = folder.ID
+			resType = "folder"
 		}
 	}
 
@@ -784,6 +812,11 @@ func (s *shareService) GetSharedNote(ctx context.Context, shareToken string, not
 		CreatedAt:        timex.Time(note.CreatedAt),
 	}
 
+	// Expand ![[Note]] / ![[Note#Heading]] embeds first so attachments inside embedded notes
+	// go through the same resolve and rewrite steps below
+	// 先展开 ![[Note]] / ![[Note#Heading]] 嵌入，使被嵌入笔记中的附件同样经过下方的解析与改写
+	noteDTO.Content = newNoteTransclusionExpander(s.noteRepo, shareEntity.UID, note.VaultID).Expand(ctx, note.Path, noteDTO.Content)
+
 	fileRefs, err := s.resolveSharedNoteFiles(ctx, shareEntity.UID, note.VaultID, note.Path, noteDTO.Content)
 	if err != nil {
 		s.logger.Warn("GetSharedNote resolveSharedNoteFiles failed", zap.Error(err), zap.String("notePath", note.Path))
@@ -1342,7 +1375,10 @@ func (s *shareService) GetSharedFile(ctx context.Context, shareToken string, fil
 
 	// Read physical file content
 	// 读取物理文件内容
-	content, err = os.ReadFile(file.SavePath)
+	if err := s.fileRepo.FetchContent(ctx, file, ownerUID); err != nil {
+		return nil, "", 0, "", "", code.ErrorFileReadFailed.WithDetails(err.Error())
+	}
+	content, err = atrest.ReadFile(file.SavePath)
 	if err != nil {
 		return nil, "", 0, "", "", code.ErrorFileReadFailed.WithDetails(err.Error())
 	}
@@ -1403,6 +1439,9 @@ func (s *shareService) GetSharedFileInfo(ctx context.Context, shareToken string,
 		etag = file.PathHash
 	}
 
+	if err := s.fileRepo.FetchContent(ctx, file, ownerUID); err != nil {
+		return "", "", 0, "", "", code.ErrorFileReadFailed.WithDetails(err.Error())
+	}
 	return file.SavePath, contentType, file.Mtime, etag, filepath.Base(file.Path), nil
 }
 
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap"
+)
+
+// databaseExportManifestName manifest file inside the export directory
+// databaseExportManifestName 导出目录内的清单文件
+const databaseExportManifestName = "export.json"
+
+// databaseExportManifest describes the content of the export directory
+// databaseExportManifest 描述导出目录的内容
+type databaseExportManifest struct {
+	ExportedAt time.Time                   `json:"exportedAt"`
+	Databases  []*databaseExportManifestDB `json:"databases"`
+}
+
+// databaseExportManifestDB an exported database file
+// databaseExportManifestDB 一个导出的数据库文件
+type databaseExportManifestDB struct {
+	Key  string `json:"key"`
+	UID  int64  `json:"uid"`
+	File string `json:"file"`
+	Size int64  `json:"size"`
+}
+
+func (m *databaseExportManifest) toDTO(dir string) *dto.DatabaseExportDTO {
+	result := &dto.DatabaseExportDTO{
+		Path:       dir,
+		ExportedAt: timex.Time(m.ExportedAt),
+		Databases:  make([]*dto.DatabaseExportFileDTO, 0, len(m.Databases)),
+	}
+	for _, db := range m.Databases {
+		result.Size += db.Size
+		result.Databases = append(result.Databases, &dto.DatabaseExportFileDTO{UID: db.UID, File: db.File, Size: db.Size})
+	}
+	return result
+}
+
+// databaseExportDir returns the directory holding the exported databases
+// databaseExportDir 返回存放导出数据库的目录
+func databaseExportDir(cfg *config.SnapshotConfig) string {
+	if cfg == nil || cfg.DatabaseExport.SavePath == "" {
+		return filepath.Join("storage", "db-export")
+	}
+	return cfg.DatabaseExport.SavePath
+}
+
+// ExportDatabases implements SnapshotService.
+// Each database is vacuumed into a partial file which then replaces the previous copy with a rename,
+// so a reader of the export directory never sees a half written database. A database failing to
+// export keeps its previous copy, the others are still refreshed.
+// 每个数据库先 VACUUM INTO 到临时文件，再通过重命名替换旧副本，读取导出目录时不会看到写了一半的数据库。
+// 导出失败的数据库保留旧副本，其余数据库照常刷新。
+func (s *snapshotService) ExportDatabases(ctx context.Context) (*dto.DatabaseExportDTO, error) {
+	if !s.exporting.TryLock() {
+		return nil, code.ErrorDatabaseExportRunning
+	}
+	defer s.exporting.Unlock()
+
+	dir := databaseExportDir(s.config)
+	start := time.Now()
+
+	dbs, err := s.repo.Databases(ctx)
+	if err != nil {
+		return nil, code.ErrorDatabaseExportFailed.WithDetails(err.Error())
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, code.ErrorDatabaseExportFailed.WithDetails(err.Error())
+	}
+
+	previous := map[string]*databaseExportManifestDB{}
+	if old, err := readDatabaseExportManifest(dir); err == nil {
+		for _, db := range old.Databases {
+			previous[db.File] = db
+		}
+	}
+
+	manifest := &databaseExportManifest{ExportedAt: start}
+	var errs []error
+	for _, db := range dbs {
+		file := filepath.Base(db.Path)
+		if db.UID > 0 {
+			file = filepath.Join("user_"+strconv.FormatInt(db.UID, 10), file)
+		}
+		entry := &databaseExportManifestDB{Key: db.Key, UID: db.UID, File: file}
+		size, err := s.exportDatabase(ctx, db.Key, filepath.Join(dir, file))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("export %s: %w", db.Path, err))
+			if old, ok := previous[file]; ok {
+				manifest.Databases = append(manifest.Databases, old)
+				delete(previous, file)
+			}
+			continue
+		}
+		entry.Size = size
+		manifest.Databases = append(manifest.Databases, entry)
+		delete(previous, file)
+	}
+
+	// Copies of databases that no longer exist, e.g. of deleted users; only files listed by the
+	// previous manifest are removed so a misconfigured save path never loses foreign files
+	// 已不存在的数据库（例如已删除用户）的副本；只删除上一份清单中记录的文件，避免保存路径配置错误时误删其他文件
+	for file := range previous {
+		if err := os.Remove(filepath.Join(dir, file)); err != nil && !os.IsNotExist(err) {
+			s.logger.Warn("remove stale database export failed", zap.String("file", file), zap.Error(err))
+		}
+	}
+
+	if err := writeDatabaseExportManifest(dir, manifest); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		s.logger.Error("database export failed", zap.String("path", dir), zap.Error(err))
+		return nil, code.ErrorDatabaseExportFailed.WithDetails(err.Error())
+	}
+
+	s.logger.Info("database export finished",
+		zap.String("path", dir),
+		zap.Int("databases", len(manifest.Databases)),
+		zap.Duration("duration", time.Since(start)))
+	return manifest.toDTO(dir), nil
+}
+
+// exportDatabase vacuums the database of key into dst through a partial file, returning the size of the copy
+// exportDatabase 通过临时文件将 key 对应的数据库 VACUUM INTO 到 dst，返回副本大小
+func (s *snapshotService) exportDatabase(ctx context.Context, key string, dst string) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return 0, err
+	}
+	// VACUUM INTO refuses to overwrite an existing file
+	// VACUUM INTO 不会覆盖已存在的文件
+	partial := dst + ".partial"
+	_ = os.Remove(partial)
+	if err := s.repo.VacuumInto(ctx, key, partial); err != nil {
+		_ = os.Remove(partial)
+		return 0, err
+	}
+	info, err := os.Stat(partial)
+	if err == nil {
+		err = os.Rename(partial, dst)
+	}
+	if err != nil {
+		_ = os.Remove(partial)
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// DatabaseExport implements SnapshotService
+func (s *snapshotService) DatabaseExport(ctx context.Context) (*dto.DatabaseExportDTO, error) {
+	dir := databaseExportDir(s.config)
+	manifest, err := readDatabaseExportManifest(dir)
+	if os.IsNotExist(err) {
+		return (&databaseExportManifest{}).toDTO(dir), nil
+	}
+	if err != nil {
+		return nil, code.ErrorDatabaseExportFailed.WithDetails(err.Error())
+	}
+	return manifest.toDTO(dir), nil
+}
+
+// readDatabaseExportManifest reads the manifest of the export directory
+// readDatabaseExportManifest 读取导出目录的清单
+func readDatabaseExportManifest(dir string) (*databaseExportManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, databaseExportManifestName))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &databaseExportManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// writeDatabaseExportManifest replaces the manifest of the export directory
+// writeDatabaseExportManifest 替换导出目录的清单
+func writeDatabaseExportManifest(dir string, manifest *databaseExportManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(dir, databaseExportManifestName)
+	if err := os.WriteFile(target+".partial", data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(target+".partial", target)
+}
@@ -0,0 +1,447 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/fileurl"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+const (
+	// snapshotPrefix directory name prefix of a finished snapshot
+	// snapshotPrefix 已完成快照的目录名前缀
+	snapshotPrefix = "snapshot_"
+	// snapshotTimeLayout time layout used in snapshot names
+	// snapshotTimeLayout 快照名称中使用的时间格式
+	snapshotTimeLayout = "20060102T150405"
+	// snapshotManifestName manifest file inside a snapshot directory
+	// snapshotManifestName 快照目录内的清单文件
+	snapshotManifestName = "snapshot.json"
+	// snapshotRestoreMarker file naming the snapshot to restore on next start
+	// snapshotRestoreMarker 记录下次启动时需恢复的快照名称的文件
+	snapshotRestoreMarker = "RESTORE"
+	// snapshotDatabaseDir / snapshotContentDir sub directories of a snapshot
+	// snapshotDatabaseDir / snapshotContentDir 快照的子目录
+	snapshotDatabaseDir = "database"
+	snapshotContentDir  = "vault"
+)
+
+// snapshotContentPath folder holding note, file, setting and history content of all users
+// snapshotContentPath 存放所有用户笔记、附件、配置与历史内容的目录
+var snapshotContentPath = filepath.Join("storage", "vault")
+
+// SnapshotService defines the local snapshot service interface.
+// A snapshot is a consistent copy of every SQLite database plus the content folder, kept under the
+// snapshot save path independently of user backup configs.
+// SnapshotService 定义本地快照服务接口。
+// 快照是所有 SQLite 数据库的一致性副本加上内容目录，独立于用户备份配置保存在快照目录下。
+type SnapshotService interface {
+	// Create takes a snapshot now and rotates old ones
+	// Create 立即创建快照并轮转旧快照
+	Create(ctx context.Context) (*dto.SnapshotDTO, error)
+
+	// List returns the available snapshots, newest first
+	// List 返回可用快照列表，最新的在前
+	List(ctx context.Context) ([]*dto.SnapshotDTO, error)
+
+	// Restore schedules a snapshot to be restored on the next start, see ApplyPendingSnapshotRestore
+	// Restore 安排在下次启动时恢复快照，见 ApplyPendingSnapshotRestore
+	Restore(ctx context.Context, name string) error
+
+	// ExportDatabases refreshes the hot export of every SQLite database now
+	// ExportDatabases 立即刷新所有 SQLite 数据库的热导出
+	ExportDatabases(ctx context.Context) (*dto.DatabaseExportDTO, error)
+
+	// DatabaseExport returns the content of the hot export directory
+	// DatabaseExport 返回热导出目录的内容
+	DatabaseExport(ctx context.Context) (*dto.DatabaseExportDTO, error)
+}
+
+// snapshotManifest describes the content of a snapshot directory
+// snapshotManifest 描述快照目录的内容
+type snapshotManifest struct {
+	Name        string                `json:"name"`
+	CreatedAt   time.Time             `json:"createdAt"`
+	ContentPath string                `json:"contentPath"`
+	Databases   []*snapshotManifestDB `json:"databases"`
+	Files       int                   `json:"files"`
+	Size        int64                 `json:"size"`
+}
+
+// snapshotManifestDB a database file of a snapshot and where it is restored to
+// snapshotManifestDB 快照中的数据库文件及其恢复位置
+type snapshotManifestDB struct {
+	Key  string `json:"key"`
+	Path string `json:"path"`
+	File string `json:"file"`
+}
+
+func (m *snapshotManifest) toDTO() *dto.SnapshotDTO {
+	return &dto.SnapshotDTO{
+		Name:      m.Name,
+		CreatedAt: timex.Time(m.CreatedAt),
+		Databases: len(m.Databases),
+		Files:     m.Files,
+		Size:      m.Size,
+	}
+}
+
+type snapshotService struct {
+	repo      domain.SnapshotRepository
+	config    *config.SnapshotConfig
+	running   sync.Mutex
+	exporting sync.Mutex
+	logger    *zap.Logger
+}
+
+// NewSnapshotService creates SnapshotService instance
+// NewSnapshotService 创建 SnapshotService 实例
+func NewSnapshotService(repo domain.SnapshotRepository, cfg *config.SnapshotConfig, logger *zap.Logger) SnapshotService {
+	return &snapshotService{
+		repo:   repo,
+		config: cfg,
+		logger: logger,
+	}
+}
+
+// snapshotDir returns the directory holding the snapshots
+// snapshotDir 返回存放快照的目录
+func snapshotDir(cfg *config.SnapshotConfig) string {
+	if cfg == nil || cfg.SavePath == "" {
+		return filepath.Join("storage", "snapshots")
+	}
+	return cfg.SavePath
+}
+
+// Create implements SnapshotService
+func (s *snapshotService) Create(ctx context.Context) (*dto.SnapshotDTO, error) {
+	if !s.running.TryLock() {
+		return nil, code.ErrorSnapshotRunning
+	}
+	defer s.running.Unlock()
+
+	dir := snapshotDir(s.config)
+	now := time.Now()
+	name := snapshotPrefix + now.Format(snapshotTimeLayout)
+	for n := 2; fileurl.IsExist(filepath.Join(dir, name)); n++ {
+		name = fmt.Sprintf("%s%s_%d", snapshotPrefix, now.Format(snapshotTimeLayout), n)
+	}
+
+	// Build into a partial directory so that List and Restore never see an incomplete snapshot
+	// 先写入临时目录，使 List 与 Restore 永远看不到未完成的快照
+	partial := filepath.Join(dir, name+".partial")
+	_ = os.RemoveAll(partial)
+	if err := os.MkdirAll(filepath.Join(partial, snapshotDatabaseDir), 0o755); err != nil {
+		return nil, code.ErrorSnapshotFailed.WithDetails(err.Error())
+	}
+
+	manifest, err := s.build(ctx, partial, name, now)
+	if err == nil {
+		err = os.Rename(partial, filepath.Join(dir, name))
+	}
+	if err != nil {
+		_ = os.RemoveAll(partial)
+		s.logger.Error("snapshot failed", zap.String("name", name), zap.Error(err))
+		return nil, code.ErrorSnapshotFailed.WithDetails(err.Error())
+	}
+
+	s.rotate()
+
+	s.logger.Info("snapshot finished",
+		zap.String("name", name),
+		zap.Int("databases", len(manifest.Databases)),
+		zap.Int("files", manifest.Files),
+		zap.Int64("size", manifest.Size),
+		zap.Duration("duration", time.Since(now)))
+	return manifest.toDTO(), nil
+}
+
+// build writes the databases, the content folder and the manifest of a snapshot into target
+// build 将快照的数据库、内容目录与清单写入 target
+func (s *snapshotService) build(ctx context.Context, target string, name string, now time.Time) (*snapshotManifest, error) {
+	manifest := &snapshotManifest{
+		Name:        name,
+		CreatedAt:   now,
+		ContentPath: snapshotContentPath,
+	}
+
+	dbs, err := s.repo.Databases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(dbs) == 0 {
+		s.logger.Warn("snapshot contains no database, only SQLite databases can be snapshotted")
+	}
+	for _, db := range dbs {
+		file := filepath.Base(db.Path)
+		dst := filepath.Join(target, snapshotDatabaseDir, file)
+		if err := s.repo.VacuumInto(ctx, db.Key, dst); err != nil {
+			return nil, fmt.Errorf("vacuum %s: %w", db.Path, err)
+		}
+		if info, err := os.Stat(dst); err == nil {
+			manifest.Size += info.Size()
+		}
+		manifest.Databases = append(manifest.Databases, &snapshotManifestDB{Key: db.Key, Path: db.Path, File: file})
+	}
+
+	if fileurl.IsExist(snapshotContentPath) {
+		files, size, err := copySnapshotTree(ctx, snapshotContentPath, filepath.Join(target, snapshotContentDir))
+		if err != nil {
+			return nil, fmt.Errorf("copy content: %w", err)
+		}
+		manifest.Files = files
+		manifest.Size += size
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(target, snapshotManifestName), data, 0o644); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// rotate removes the oldest snapshots beyond the configured keep count
+// rotate 删除超出保留数量的最旧快照
+func (s *snapshotService) rotate() {
+	if s.config == nil || s.config.Keep <= 0 {
+		return
+	}
+	names := listSnapshotNames(snapshotDir(s.config))
+	for i := 0; i < len(names)-s.config.Keep; i++ {
+		if err := os.RemoveAll(filepath.Join(snapshotDir(s.config), names[i])); err != nil {
+			s.logger.Warn("remove old snapshot failed", zap.String("name", names[i]), zap.Error(err))
+		}
+	}
+}
+
+// List implements SnapshotService
+func (s *snapshotService) List(ctx context.Context) ([]*dto.SnapshotDTO, error) {
+	dir := snapshotDir(s.config)
+	names := listSnapshotNames(dir)
+	result := make([]*dto.SnapshotDTO, 0, len(names))
+	for i := len(names) - 1; i >= 0; i-- {
+		manifest, err := readSnapshotManifest(dir, names[i])
+		if err != nil {
+			s.logger.Warn("skipping unreadable snapshot", zap.String("name", names[i]), zap.Error(err))
+			continue
+		}
+		result = append(result, manifest.toDTO())
+	}
+	return result, nil
+}
+
+// Restore implements SnapshotService
+func (s *snapshotService) Restore(ctx context.Context, name string) error {
+	dir := snapshotDir(s.config)
+	if !strings.HasPrefix(name, snapshotPrefix) || filepath.Base(name) != name {
+		return code.ErrorSnapshotNotFound
+	}
+	if _, err := readSnapshotManifest(dir, name); err != nil {
+		return code.ErrorSnapshotNotFound
+	}
+	if err := os.WriteFile(filepath.Join(dir, snapshotRestoreMarker), []byte(name), 0o644); err != nil {
+		return code.ErrorSnapshotFailed.WithDetails(err.Error())
+	}
+	s.logger.Info("snapshot restore scheduled for next start", zap.String("name", name))
+	return nil
+}
+
+// ApplyPendingSnapshotRestore restores the snapshot scheduled by SnapshotService.Restore.
+// It must run before any database is opened. The replaced databases and content folder are
+// moved to a "pre-restore_<time>" directory next to the snapshots rather than deleted.
+// ApplyPendingSnapshotRestore 恢复由 SnapshotService.Restore 安排的快照。
+// 必须在打开任何数据库之前执行。被替换的数据库与内容目录不会被删除，而是移动到快照目录下的 "pre-restore_<time>" 目录中。
+func ApplyPendingSnapshotRestore(cfg *config.SnapshotConfig, logger *zap.Logger) error {
+	dir := snapshotDir(cfg)
+	marker := filepath.Join(dir, snapshotRestoreMarker)
+	data, err := os.ReadFile(marker)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	// The marker is removed up front, a failing restore must not be retried on every start
+	// 预先删除标记，恢复失败时不会在每次启动时重复尝试
+	if err := os.Remove(marker); err != nil {
+		return err
+	}
+
+	name := strings.TrimSpace(string(data))
+	manifest, err := readSnapshotManifest(dir, name)
+	if err != nil {
+		return fmt.Errorf("snapshot %q: %w", name, err)
+	}
+	source := filepath.Join(dir, name)
+	aside := filepath.Join(dir, "pre-restore_"+time.Now().Format(snapshotTimeLayout))
+	if err := os.MkdirAll(filepath.Join(aside, snapshotDatabaseDir), 0o755); err != nil {
+		return err
+	}
+
+	// Move the current state aside first, remembering how to put it back
+	// 先将当前状态移开，并记录如何还原
+	var moved [][2]string
+	rollback := func() {
+		for i := len(moved) - 1; i >= 0; i-- {
+			_ = os.RemoveAll(moved[i][0])
+			_ = moveSnapshotPath(moved[i][1], moved[i][0])
+		}
+	}
+	moveAside := func(from, to string) error {
+		if !fileurl.IsExist(from) {
+			return nil
+		}
+		if err := moveSnapshotPath(from, to); err != nil {
+			return err
+		}
+		moved = append(moved, [2]string{from, to})
+		return nil
+	}
+
+	for _, db := range manifest.Databases {
+		// Stale WAL and shared memory files would be replayed onto the restored database
+		// 残留的 WAL 与共享内存文件会被重放到恢复后的数据库上
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			if err := moveAside(db.Path+suffix, filepath.Join(aside, snapshotDatabaseDir, db.File+suffix)); err != nil {
+				rollback()
+				return err
+			}
+		}
+	}
+	if err := moveAside(manifest.ContentPath, filepath.Join(aside, snapshotContentDir)); err != nil {
+		rollback()
+		return err
+	}
+
+	for _, db := range manifest.Databases {
+		if err := os.MkdirAll(filepath.Dir(db.Path), 0o755); err == nil {
+			err = util.CopyFile(filepath.Join(source, snapshotDatabaseDir, db.File), db.Path)
+		}
+		if err != nil {
+			rollback()
+			return fmt.Errorf("restore %s: %w", db.Path, err)
+		}
+	}
+	if fileurl.IsExist(filepath.Join(source, snapshotContentDir)) {
+		if _, _, err := copySnapshotTree(context.Background(), filepath.Join(source, snapshotContentDir), manifest.ContentPath); err != nil {
+			rollback()
+			return fmt.Errorf("restore content: %w", err)
+		}
+	}
+
+	logger.Info("snapshot restored",
+		zap.String("name", name),
+		zap.Int("databases", len(manifest.Databases)),
+		zap.String("previousState", aside))
+	return nil
+}
+
+// listSnapshotNames returns the names of finished snapshots in dir, oldest first
+// listSnapshotNames 返回 dir 中已完成快照的名称，最旧的在前
+func listSnapshotNames(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), snapshotPrefix) && !strings.HasSuffix(e.Name(), ".partial") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readSnapshotManifest reads the manifest of the snapshot called name
+// readSnapshotManifest 读取名为 name 的快照清单
+func readSnapshotManifest(dir string, name string) (*snapshotManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, name, snapshotManifestName))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &snapshotManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// copySnapshotTree copies the directory tree src to dst, returning the number and total size of copied files
+// copySnapshotTree 将目录树 src 复制到 dst，返回复制的文件数量与总大小
+func copySnapshotTree(ctx context.Context, src string, dst string) (int, int64, error) {
+	files := 0
+	var size int64
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := util.CopyFile(p, target); err != nil {
+			return err
+		}
+		_ = os.Chtimes(target, info.ModTime(), info.ModTime())
+		files++
+		size += info.Size()
+		return nil
+	})
+	return files, size, err
+}
+
+// moveSnapshotPath moves a file or directory, falling back to copy and delete across devices
+// moveSnapshotPath 移动文件或目录，跨设备时退回复制后删除
+func moveSnapshotPath(from string, to string) error {
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+	info, err := os.Stat(from)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return util.MoveFile(from, to)
+	}
+	if _, _, err := copySnapshotTree(context.Background(), from, to); err != nil {
+		return err
+	}
+	return os.RemoveAll(from)
+}
+
+var _ SnapshotService = (*snapshotService)(nil)
@@ -0,0 +1,144 @@
+// Package service implements the business logic layer.
+// Package service 实现业务逻辑层。
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestSnapshotService_CreateRotateRestore verifies snapshots are rotated and a scheduled restore
+// puts the databases and content back while keeping the replaced state aside.
+// TestSnapshotService_CreateRotateRestore 验证快照会被轮转，且已安排的恢复会还原数据库与内容并保留被替换的状态。
+func TestSnapshotService_CreateRotateRestore(t *testing.T) {
+	tmp := t.TempDir()
+	oldContentPath := snapshotContentPath
+	snapshotContentPath = filepath.Join(tmp, "vault")
+	defer func() { snapshotContentPath = oldContentPath }()
+
+	notePath := filepath.Join(snapshotContentPath, "u_1", "note", "n_1", "content.txt")
+	dbPath := filepath.Join(tmp, "database", "db_user_note_1.sqlite3")
+	require.NoError(t, os.MkdirAll(filepath.Dir(notePath), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Dir(dbPath), 0o755))
+	require.NoError(t, os.WriteFile(notePath, []byte("hello"), 0o644))
+	require.NoError(t, os.WriteFile(dbPath, []byte("live"), 0o644))
+
+	repo := new(domainmocks.MockSnapshotRepository)
+	repo.On("Databases", mock.Anything).Return([]*domain.SnapshotDatabase{{Key: "user_note_1", Path: dbPath}}, nil)
+	repo.On("VacuumInto", mock.Anything, "user_note_1", mock.Anything).
+		Run(func(args mock.Arguments) {
+			require.NoError(t, os.WriteFile(args.String(2), []byte("snap"), 0o644))
+		}).
+		Return(nil)
+
+	cfg := &config.SnapshotConfig{Keep: 2, SavePath: filepath.Join(tmp, "snapshots")}
+	svc := NewSnapshotService(repo, cfg, zap.NewNop())
+	ctx := context.Background()
+
+	var last string
+	for i := 0; i < 3; i++ {
+		snap, err := svc.Create(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, snap.Databases)
+		assert.Equal(t, 1, snap.Files)
+		last = snap.Name
+	}
+
+	list, err := svc.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, last, list[0].Name)
+
+	assert.Equal(t, code.ErrorSnapshotNotFound, svc.Restore(ctx, "../"+last))
+	assert.Equal(t, code.ErrorSnapshotNotFound, svc.Restore(ctx, "snapshot_missing"))
+	require.NoError(t, svc.Restore(ctx, last))
+
+	require.NoError(t, os.WriteFile(dbPath, []byte("broken"), 0o644))
+	require.NoError(t, os.WriteFile(dbPath+"-wal", []byte("stale"), 0o644))
+	require.NoError(t, os.WriteFile(notePath, []byte("changed"), 0o644))
+
+	require.NoError(t, ApplyPendingSnapshotRestore(cfg, zap.NewNop()))
+
+	data, err := os.ReadFile(dbPath)
+	require.NoError(t, err)
+	assert.Equal(t, "snap", string(data))
+	assert.NoFileExists(t, dbPath+"-wal")
+	data, err = os.ReadFile(notePath)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.NoFileExists(t, filepath.Join(cfg.SavePath, snapshotRestoreMarker))
+
+	aside, err := filepath.Glob(filepath.Join(cfg.SavePath, "pre-restore_*", snapshotDatabaseDir, "db_user_note_1.sqlite3"))
+	require.NoError(t, err)
+	require.Len(t, aside, 1)
+	data, err = os.ReadFile(aside[0])
+	require.NoError(t, err)
+	assert.Equal(t, "broken", string(data))
+
+	// Nothing is scheduled any more
+	// 已无待执行的恢复
+	require.NoError(t, ApplyPendingSnapshotRestore(cfg, zap.NewNop()))
+}
+
+// TestSnapshotService_ExportDatabases verifies exports replace the previous copies, keep the copy of a
+// database failing to export and drop copies of databases that are gone.
+// TestSnapshotService_ExportDatabases 验证导出会替换旧副本，导出失败的数据库保留旧副本，已不存在的数据库副本会被删除。
+func TestSnapshotService_ExportDatabases(t *testing.T) {
+	tmp := t.TempDir()
+	cfg := &config.SnapshotConfig{DatabaseExport: config.DatabaseExportConfig{SavePath: filepath.Join(tmp, "export")}}
+
+	repo := new(domainmocks.MockSnapshotRepository)
+	mainDB := &domain.SnapshotDatabase{Key: "", Path: filepath.Join(tmp, "db.sqlite3")}
+	noteDB := &domain.SnapshotDatabase{Key: "user_note_1", Path: filepath.Join(tmp, "db_user_note_1.sqlite3"), UID: 1}
+	oldDB := &domain.SnapshotDatabase{Key: "user_note_2", Path: filepath.Join(tmp, "db_user_note_2.sqlite3"), UID: 2}
+	repo.On("Databases", mock.Anything).Return([]*domain.SnapshotDatabase{mainDB, noteDB, oldDB}, nil).Once()
+	repo.On("Databases", mock.Anything).Return([]*domain.SnapshotDatabase{mainDB, noteDB}, nil)
+
+	round := "first"
+	write := func(args mock.Arguments) {
+		assert.NoFileExists(t, args.String(2))
+		require.NoError(t, os.WriteFile(args.String(2), []byte(round+":"+args.String(1)), 0o644))
+	}
+	// The main database only exports in the first round
+	// 主库只在第一轮导出成功
+	repo.On("VacuumInto", mock.Anything, "", mock.Anything).Run(write).Return(nil).Once()
+	repo.On("VacuumInto", mock.Anything, "", mock.Anything).Return(assert.AnError)
+	repo.On("VacuumInto", mock.Anything, mock.Anything, mock.Anything).Run(write).Return(nil)
+
+	svc := NewSnapshotService(repo, cfg, zap.NewNop())
+	ctx := context.Background()
+
+	export, err := svc.ExportDatabases(ctx)
+	require.NoError(t, err)
+	require.Len(t, export.Databases, 3)
+	assert.Equal(t, filepath.Join("user_1", "db_user_note_1.sqlite3"), export.Databases[1].File)
+
+	round = "second"
+	_, err = svc.ExportDatabases(ctx)
+	assert.ErrorIs(t, err, code.ErrorDatabaseExportFailed)
+
+	dir := cfg.DatabaseExport.SavePath
+	data, err := os.ReadFile(filepath.Join(dir, "db.sqlite3"))
+	require.NoError(t, err)
+	assert.Equal(t, "first:", string(data))
+	data, err = os.ReadFile(filepath.Join(dir, "user_1", "db_user_note_1.sqlite3"))
+	require.NoError(t, err)
+	assert.Equal(t, "second:user_note_1", string(data))
+	assert.NoFileExists(t, filepath.Join(dir, "user_2", "db_user_note_2.sqlite3"))
+	assert.NoFileExists(t, filepath.Join(dir, "db.sqlite3.partial"))
+
+	export, err = svc.DatabaseExport(ctx)
+	require.NoError(t, err)
+	assert.Len(t, export.Databases, 2)
+}
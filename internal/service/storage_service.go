@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +13,7 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"github.com/haierkeys/fast-note-sync-service/pkg/storage"
+	"github.com/haierkeys/fast-note-sync-service/pkg/storage/aws_s3"
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
 	"gorm.io/gorm"
 )
@@ -60,23 +62,26 @@ func (s *storageService) domainToDTO(m *domain.Storage) *dto.StorageDTO {
 		return nil
 	}
 	return &dto.StorageDTO{
-		ID:              m.ID,
-		UID:             m.UID,
-		Type:            m.Type,
-		Endpoint:        m.Endpoint,
-		Region:          m.Region,
-		AccountID:       m.AccountID,
-		BucketName:      m.BucketName,
-		AccessKeyID:     m.AccessKeyID,
-		AccessKeySecret: m.AccessKeySecret,
-		CustomPath:      m.CustomPath,
-		AccessURLPrefix: m.AccessURLPrefix,
-		User:            m.User,
-		Password:        m.Password,
-		IsEnabled:       m.IsEnabled,
-		IsDeleted:       m.IsDeleted,
-		CreatedAt:       timex.Time(m.CreatedAt),
-		UpdatedAt:       timex.Time(m.UpdatedAt),
+		ID:                 m.ID,
+		UID:                m.UID,
+		Type:               m.Type,
+		Endpoint:           m.Endpoint,
+		Region:             m.Region,
+		AccountID:          m.AccountID,
+		BucketName:         m.BucketName,
+		AccessKeyID:        m.AccessKeyID,
+		AccessKeySecret:    m.AccessKeySecret,
+		CustomPath:         m.CustomPath,
+		AccessURLPrefix:    m.AccessURLPrefix,
+		User:               m.User,
+		Password:           m.Password,
+		PathStyle:          m.PathStyle,
+		CACert:             m.CACert,
+		InsecureSkipVerify: m.InsecureSkipVerify,
+		IsEnabled:          m.IsEnabled,
+		IsDeleted:          m.IsDeleted,
+		CreatedAt:          timex.Time(m.CreatedAt),
+		UpdatedAt:          timex.Time(m.UpdatedAt),
 	}
 }
 
@@ -85,21 +90,24 @@ func (s *storageService) dtoToDomain(d *dto.StorageDTO) *domain.Storage {
 		return nil
 	}
 	return &domain.Storage{
-		ID:              d.ID,
-		UID:             d.UID,
-		Type:            d.Type,
-		Endpoint:        d.Endpoint,
-		Region:          d.Region,
-		AccountID:       d.AccountID,
-		BucketName:      d.BucketName,
-		AccessKeyID:     d.AccessKeyID,
-		AccessKeySecret: d.AccessKeySecret,
-		CustomPath:      d.CustomPath,
-		AccessURLPrefix: d.AccessURLPrefix,
-		User:            d.User,
-		Password:        d.Password,
-		IsEnabled:       d.IsEnabled,
-		IsDeleted:       d.IsDeleted,
+		ID:                 d.ID,
+		UID:                d.UID,
+		Type:               d.Type,
+		Endpoint:           d.Endpoint,
+		Region:             d.Region,
+		AccountID:          d.AccountID,
+		BucketName:         d.BucketName,
+		AccessKeyID:        d.AccessKeyID,
+		AccessKeySecret:    d.AccessKeySecret,
+		CustomPath:         d.CustomPath,
+		AccessURLPrefix:    d.AccessURLPrefix,
+		User:               d.User,
+		Password:           d.Password,
+		PathStyle:          d.PathStyle,
+		CACert:             d.CACert,
+		InsecureSkipVerify: d.InsecureSkipVerify,
+		IsEnabled:          d.IsEnabled,
+		IsDeleted:          d.IsDeleted,
 	}
 }
 
@@ -108,19 +116,22 @@ func (s *storageService) postRequestToDomain(req *dto.StoragePostRequest) *domai
 		return nil
 	}
 	return &domain.Storage{
-		ID:              req.ID,
-		Type:            req.Type,
-		Endpoint:        req.Endpoint,
-		Region:          req.Region,
-		AccountID:       req.AccountID,
-		BucketName:      req.BucketName,
-		AccessKeyID:     req.AccessKeyID,
-		AccessKeySecret: req.AccessKeySecret,
-		CustomPath:      req.CustomPath,
-		AccessURLPrefix: req.AccessURLPrefix,
-		User:            req.User,
-		Password:        req.Password,
-		IsEnabled:       req.IsEnabled == 1,
+		ID:                 req.ID,
+		Type:               req.Type,
+		Endpoint:           req.Endpoint,
+		Region:             req.Region,
+		AccountID:          req.AccountID,
+		BucketName:         req.BucketName,
+		AccessKeyID:        req.AccessKeyID,
+		AccessKeySecret:    req.AccessKeySecret,
+		CustomPath:         req.CustomPath,
+		AccessURLPrefix:    req.AccessURLPrefix,
+		User:               req.User,
+		Password:           req.Password,
+		PathStyle:          req.PathStyle == 1,
+		CACert:             req.CACert,
+		InsecureSkipVerify: req.InsecureSkipVerify == 1,
+		IsEnabled:          req.IsEnabled == 1,
 	}
 }
 
@@ -132,6 +143,10 @@ func (s *storageService) CreateOrUpdate(ctx context.Context, uid int64, id int64
 		return nil, code.ErrorStorageTypeDisabled
 	}
 
+	if err := validateS3Options(req); err != nil {
+		return nil, err
+	}
+
 	storage := s.postRequestToDomain(req)
 	storage.UID = uid
 
@@ -233,23 +248,52 @@ func (s *storageService) isStorageTypeEnabled(t string) bool {
 	}
 }
 
+// validateS3Options checks the custom endpoint and CA bundle of an S3 config and normalizes the endpoint;
+// TLS options are cleared for other types
+// validateS3Options 校验 S3 配置的自定义端点与 CA 证书并规范化端点；其他类型会清空 TLS 选项
+func validateS3Options(req *dto.StoragePostRequest) error {
+	if req.Type != storage.S3 {
+		req.PathStyle, req.CACert, req.InsecureSkipVerify = 0, "", 0
+		return nil
+	}
+
+	endpoint, err := aws_s3.NormalizeEndpoint(req.Endpoint)
+	if err != nil {
+		return code.ErrorInvalidParams.WithDetails(err.Error())
+	}
+	req.Endpoint = endpoint
+
+	if strings.TrimSpace(req.CACert) != "" {
+		if _, err := aws_s3.CertPool(req.CACert); err != nil {
+			return code.ErrorInvalidParams.WithDetails(err.Error())
+		}
+	}
+	return nil
+}
+
 func (s *storageService) Validate(ctx context.Context, req *dto.StoragePostRequest) error {
 	if !s.isStorageTypeEnabled(req.Type) {
 		return code.ErrorStorageTypeDisabled
 	}
+	if err := validateS3Options(req); err != nil {
+		return err
+	}
 
 	sConfig := &storage.Config{
-		Type:            req.Type,
-		CustomPath:      req.CustomPath,
-		Endpoint:        req.Endpoint,
-		Region:          req.Region,
-		BucketName:      req.BucketName,
-		AccessKeyID:     req.AccessKeyID,
-		AccessKeySecret: req.AccessKeySecret,
-		AccountID:       req.AccountID,
-		User:            req.User,
-		Password:        req.Password,
-		SavePath:        s.config.LocalFS.SavePath,
+		Type:               req.Type,
+		CustomPath:         req.CustomPath,
+		Endpoint:           req.Endpoint,
+		Region:             req.Region,
+		BucketName:         req.BucketName,
+		AccessKeyID:        req.AccessKeyID,
+		AccessKeySecret:    req.AccessKeySecret,
+		AccountID:          req.AccountID,
+		User:               req.User,
+		Password:           req.Password,
+		PathStyle:          req.PathStyle == 1,
+		CACert:             req.CACert,
+		InsecureSkipVerify: req.InsecureSkipVerify == 1,
+		SavePath:           s.config.LocalFS.SavePath,
 	}
 
 	client, err := storage.NewClient(sConfig)
@@ -48,6 +48,20 @@ type SyncLogService interface {
 		size int64,
 	)
 
+	// LogFailure asynchronously records a failed sync of a resource, does not block the caller
+	// LogFailure 异步记录一次资源同步失败，不阻塞调用方
+	LogFailure(
+		uid int64,
+		vaultID int64,
+		logType domain.SyncLogType,
+		action domain.SyncLogAction,
+		path string,
+		clientType string,
+		clientName string,
+		clientVersion string,
+		message string,
+	)
+
 	// List retrieves sync logs with pagination
 	// List 分页查询同步日志
 	List(ctx context.Context, uid int64, vaultID int64, logType, action string, page, pageSize int) ([]*dto.SyncLogDTO, int64, error)
@@ -130,16 +144,49 @@ func (s *syncLogService) Log(
 		Status:        1, // success // 成功
 		CreatedAt:     timex.Now(),
 	}
+	s.enqueue(uid, entry)
+}
+
+// LogFailure enqueues a failed sync entry like Log does
+// LogFailure 与 Log 一样将同步失败条目加入队列
+func (s *syncLogService) LogFailure(
+	uid int64,
+	vaultID int64,
+	logType domain.SyncLogType,
+	action domain.SyncLogAction,
+	path string,
+	clientType string,
+	clientName string,
+	clientVersion string,
+	message string,
+) {
+	s.enqueue(uid, &domain.SyncLog{
+		UID:           uid,
+		VaultID:       vaultID,
+		Type:          logType,
+		Action:        action,
+		Path:          path,
+		ClientType:    clientType,
+		ClientName:    clientName,
+		ClientVersion: clientVersion,
+		Status:        2, // failed // 失败
+		Message:       message,
+		CreatedAt:     timex.Now(),
+	})
+}
 
+// enqueue pushes an entry to the batch worker, dropping it when the queue is full
+// enqueue 将条目推送给批处理 worker，队列已满时丢弃
+func (s *syncLogService) enqueue(uid int64, entry *domain.SyncLog) {
 	select {
 	case s.ch <- syncLogQueueItem{uid: uid, entry: entry}:
 	default:
 		s.logger.Warn("SyncLogService.Log: queue full, dropping sync log entry",
 			zap.Int64("uid", uid),
-			zap.Int64("vaultID", vaultID),
-			zap.String("type", string(logType)),
-			zap.String("action", string(action)),
-			zap.String("path", path),
+			zap.Int64("vaultID", entry.VaultID),
+			zap.String("type", string(entry.Type)),
+			zap.String("action", string(entry.Action)),
+			zap.String("path", entry.Path),
 		)
 	}
 }
@@ -233,7 +280,7 @@ func (s *syncLogService) List(ctx context.Context, uid int64, vaultID int64, log
 		if vaultID > 0 && l.VaultID != vaultID {
 			continue
 		}
-		result = append(result, s.domainToDTO(l))
+		result = append(result, syncLogToDTO(l))
 	}
 	return result, total, nil
 }
@@ -244,9 +291,9 @@ func (s *syncLogService) CleanupByTime(ctx context.Context, cutoffTime int64) er
 	return s.repo.CleanupByTimeAll(ctx, cutoffTime)
 }
 
-// domainToDTO converts domain SyncLog to DTO
-// domainToDTO 将领域模型转换为 DTO
-func (s *syncLogService) domainToDTO(l *domain.SyncLog) *dto.SyncLogDTO {
+// syncLogToDTO converts domain SyncLog to DTO
+// syncLogToDTO 将领域模型转换为 DTO
+func syncLogToDTO(l *domain.SyncLog) *dto.SyncLogDTO {
 	return &dto.SyncLogDTO{
 		ID:            l.ID,
 		VaultID:       l.VaultID,
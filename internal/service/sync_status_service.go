@@ -0,0 +1,215 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// syncStatusDefaultDays default period of sync logs covered by the status
+// syncStatusDefaultDays 同步状态默认统计的同步日志天数
+const syncStatusDefaultDays = 7
+
+// syncStatusRecentErrors failures kept per vault
+// syncStatusRecentErrors 每个保险库保留的失败记录数
+const syncStatusRecentErrors = 10
+
+// SyncStatusService reports the sync health of the vaults and devices of a user
+// SyncStatusService 报告用户保险库与设备的同步健康状态
+type SyncStatusService interface {
+	// Status aggregates the sync logs per vault and client and the state of every device; online and
+	// pending hold per device key the connection state and the changes not received yet, which only
+	// the router layer knows about
+	// Status 按保险库与客户端汇总同步日志并给出每个设备的状态；online 与 pending 按设备标识给出
+	// 连接状态与尚未收到的变更数，只有路由层知道
+	Status(ctx context.Context, uid int64, params *dto.VaultSyncStatusRequest, online map[string]bool, pending map[string]int64) (*dto.SyncStatusDTO, error)
+}
+
+// syncStatusService implementation of SyncStatusService interface
+// syncStatusService 实现 SyncStatusService 接口
+type syncStatusService struct {
+	vaultRepo   domain.VaultRepository
+	syncLogRepo domain.SyncLogRepository
+	deviceRepo  domain.DeviceRepository
+	logger      *zap.Logger
+}
+
+// NewSyncStatusService creates SyncStatusService instance
+// NewSyncStatusService 创建 SyncStatusService 实例
+func NewSyncStatusService(vaultRepo domain.VaultRepository, syncLogRepo domain.SyncLogRepository, deviceRepo domain.DeviceRepository, logger *zap.Logger) SyncStatusService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &syncStatusService{
+		vaultRepo:   vaultRepo,
+		syncLogRepo: syncLogRepo,
+		deviceRepo:  deviceRepo,
+		logger:      logger,
+	}
+}
+
+// syncClientKey identifies a client in the sync logs, which do not record the device key
+// syncClientKey 在同步日志中标识客户端，同步日志不记录设备标识
+type syncClientKey struct {
+	clientType string
+	clientName string
+}
+
+// Status implements SyncStatusService
+// Status 实现 SyncStatusService
+func (s *syncStatusService) Status(ctx context.Context, uid int64, params *dto.VaultSyncStatusRequest, online map[string]bool, pending map[string]int64) (*dto.SyncStatusDTO, error) {
+	days := params.Days
+	if days <= 0 {
+		days = syncStatusDefaultDays
+	}
+	since := time.Now().AddDate(0, 0, -days)
+
+	var vaults []*domain.Vault
+	if params.Vault != "" {
+		vault, err := s.vaultRepo.GetByName(ctx, params.Vault, uid)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		if vault == nil {
+			return nil, code.ErrorVaultNotFound
+		}
+		vaults = []*domain.Vault{vault}
+	} else {
+		list, err := s.vaultRepo.List(ctx, uid)
+		if err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		vaults = list
+	}
+
+	result := &dto.SyncStatusDTO{
+		Since:   timex.Time(since),
+		Vaults:  make([]*dto.VaultSyncStatusDTO, 0, len(vaults)),
+		Devices: []*dto.DeviceSyncStatusDTO{},
+	}
+	byID := make(map[int64]*dto.VaultSyncStatusDTO, len(vaults))
+	for _, v := range vaults {
+		status := &dto.VaultSyncStatusDTO{
+			VaultID:      v.ID,
+			Vault:        v.Name,
+			Clients:      []*dto.SyncClientStatusDTO{},
+			RecentErrors: []*dto.SyncLogDTO{},
+		}
+		byID[v.ID] = status
+		result.Vaults = append(result.Vaults, status)
+	}
+
+	logs, err := s.syncLogRepo.ListSince(ctx, since, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	// Logs come oldest first, so the last one seen of a client is its latest
+	// 日志按时间正序返回，客户端最后出现的一条即为其最新记录
+	clients := make(map[int64]map[syncClientKey]*dto.SyncClientStatusDTO)
+	lastSync := make(map[syncClientKey]timex.Time)
+	lastFailed := make(map[syncClientKey]bool)
+	for _, l := range logs {
+		vault, ok := byID[l.VaultID]
+		if !ok {
+			continue
+		}
+		key := syncClientKey{clientType: l.ClientType, clientName: l.ClientName}
+		if clients[l.VaultID] == nil {
+			clients[l.VaultID] = make(map[syncClientKey]*dto.SyncClientStatusDTO)
+		}
+		client, ok := clients[l.VaultID][key]
+		if !ok {
+			client = &dto.SyncClientStatusDTO{ClientName: l.ClientName, ClientType: l.ClientType}
+			clients[l.VaultID][key] = client
+			vault.Clients = append(vault.Clients, client)
+		}
+		if l.ClientVersion != "" {
+			client.ClientVersion = l.ClientVersion
+		}
+
+		if l.Status == 2 {
+			vault.Failures++
+			client.Failures++
+			client.LastErrorAt = l.CreatedAt
+			client.LastError = l.Message
+			vault.RecentErrors = append(vault.RecentErrors, syncLogToDTO(l))
+			lastFailed[key] = true
+			continue
+		}
+		vault.Changes++
+		vault.Bytes += l.Size
+		vault.LastSyncAt = l.CreatedAt
+		client.Changes++
+		client.Bytes += l.Size
+		client.LastSyncAt = l.CreatedAt
+		lastSync[key] = l.CreatedAt
+		lastFailed[key] = false
+	}
+
+	for _, vault := range result.Vaults {
+		sort.SliceStable(vault.Clients, func(i, j int) bool {
+			return syncClientActivity(vault.Clients[i]).After(syncClientActivity(vault.Clients[j]))
+		})
+		errs := vault.RecentErrors
+		for i, j := 0, len(errs)-1; i < j; i, j = i+1, j-1 {
+			errs[i], errs[j] = errs[j], errs[i]
+		}
+		if len(errs) > syncStatusRecentErrors {
+			vault.RecentErrors = errs[:syncStatusRecentErrors]
+		}
+	}
+
+	devices, err := s.deviceRepo.ListByUID(ctx, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	result.UpToDate = true
+	for _, failed := range lastFailed {
+		if failed {
+			result.UpToDate = false
+		}
+	}
+	for _, d := range devices {
+		key := syncClientKey{clientType: d.ClientType, clientName: d.ClientName}
+		status := &dto.DeviceSyncStatusDTO{
+			DeviceKey:      d.DeviceKey,
+			ClientName:     d.ClientName,
+			ClientType:     d.ClientType,
+			ClientVersion:  d.ClientVersion,
+			IsOnline:       online[d.DeviceKey],
+			LastSeenAt:     timex.Time(d.LastSeenAt),
+			LastSyncAt:     lastSync[key],
+			PendingChanges: pending[d.DeviceKey],
+		}
+		status.UpToDate = status.PendingChanges == 0 && !lastFailed[key]
+		if !status.UpToDate {
+			result.UpToDate = false
+		}
+		result.Devices = append(result.Devices, status)
+	}
+	return result, nil
+}
+
+// syncClientActivity returns the time of the latest sync of a client, failed or not
+// syncClientActivity 返回客户端最近一次同步（无论成功与否）的时间
+func syncClientActivity(c *dto.SyncClientStatusDTO) time.Time {
+	if time.Time(c.LastErrorAt).After(time.Time(c.LastSyncAt)) {
+		return time.Time(c.LastErrorAt)
+	}
+	return time.Time(c.LastSyncAt)
+}
+
+// Ensure syncStatusService implements SyncStatusService
+// 确保 syncStatusService 实现了 SyncStatusService 接口
+var _ SyncStatusService = (*syncStatusService)(nil)
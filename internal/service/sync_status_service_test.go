@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestSyncStatusService_Status verifies the logs are aggregated per vault and client and the devices
+// are up to date only without pending changes and failed last syncs.
+// TestSyncStatusService_Status 验证日志按保险库与客户端汇总，且设备仅在没有待同步变更、
+// 最近一次同步未失败时才视为最新。
+func TestSyncStatusService_Status(t *testing.T) {
+	vaultRepo := newVaultMockRepo()
+	vaultRepo.On("List", mock.Anything, int64(1)).Return([]*domain.Vault{newVault(1, "Work"), newVault(2, "Home")}, nil)
+
+	start := time.Now().Add(-time.Hour)
+	at := func(minutes int) timex.Time {
+		return timex.Time(start.Add(time.Duration(minutes) * time.Minute))
+	}
+	syncLogRepo := new(domainmocks.MockSyncLogRepository)
+	syncLogRepo.On("ListSince", mock.Anything, mock.Anything, int64(1)).Return([]*domain.SyncLog{
+		{ID: 1, VaultID: 1, ClientType: "desktop", ClientName: "Mac", ClientVersion: "1.0", Size: 100, Status: 1, CreatedAt: at(1)},
+		{ID: 2, VaultID: 1, ClientType: "mobile", ClientName: "iPhone", Size: 50, Status: 1, CreatedAt: at(2)},
+		{ID: 3, VaultID: 1, ClientType: "mobile", ClientName: "iPhone", Path: "a.md", Status: 2, Message: "disk full", CreatedAt: at(3)},
+		{ID: 4, VaultID: 1, ClientType: "desktop", ClientName: "Mac", ClientVersion: "1.1", Size: 20, Status: 1, CreatedAt: at(4)},
+		{ID: 5, VaultID: 9, ClientType: "desktop", ClientName: "Mac", Size: 999, Status: 1, CreatedAt: at(5)},
+	}, nil)
+
+	deviceRepo := &fakeDeviceRepo{rows: []*domain.Device{
+		{ID: 1, UID: 1, DeviceKey: "mac", ClientType: "desktop", ClientName: "Mac"},
+		{ID: 2, UID: 1, DeviceKey: "phone", ClientType: "mobile", ClientName: "iPhone"},
+		{ID: 3, UID: 1, DeviceKey: "tablet", ClientType: "mobile", ClientName: "iPad"},
+	}}
+
+	svc := NewSyncStatusService(vaultRepo, syncLogRepo, deviceRepo, zap.NewNop())
+	status, err := svc.Status(context.Background(), 1, &dto.VaultSyncStatusRequest{}, map[string]bool{"mac": true}, map[string]int64{"tablet": 3})
+	require.NoError(t, err)
+	assert.False(t, status.UpToDate)
+
+	require.Len(t, status.Vaults, 2)
+	work := status.Vaults[0]
+	assert.Equal(t, int64(3), work.Changes)
+	assert.Equal(t, int64(170), work.Bytes)
+	assert.Equal(t, int64(1), work.Failures)
+	assert.Equal(t, at(4), work.LastSyncAt)
+	require.Len(t, work.Clients, 2)
+	assert.Equal(t, "Mac", work.Clients[0].ClientName)
+	assert.Equal(t, "1.1", work.Clients[0].ClientVersion)
+	assert.Equal(t, "disk full", work.Clients[1].LastError)
+	require.Len(t, work.RecentErrors, 1)
+	assert.Equal(t, "a.md", work.RecentErrors[0].Path)
+	assert.Zero(t, status.Vaults[1].Changes)
+	assert.Empty(t, status.Vaults[1].Clients)
+
+	require.Len(t, status.Devices, 3)
+	assert.True(t, status.Devices[0].IsOnline)
+	assert.True(t, status.Devices[0].UpToDate)
+	assert.Equal(t, at(4), status.Devices[0].LastSyncAt)
+	assert.False(t, status.Devices[1].UpToDate)
+	assert.False(t, status.Devices[2].UpToDate)
+	assert.Equal(t, int64(3), status.Devices[2].PendingChanges)
+}
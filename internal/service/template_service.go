@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+// templateVariable matches {{date}}, {{time}} and {{title}}, optionally with a format such as {{date:YYYY-MM-DD}}
+// templateVariable 匹配 {{date}}、{{time}} 与 {{title}}，可带格式，如 {{date:YYYY-MM-DD}}
+var templateVariable = regexp.MustCompile(`\{\{\s*(date|time|title)\s*(?::([^}]*))?\}\}`)
+
+// dailyPatternToken matches a {{FORMAT}} date token of a daily note path pattern
+// dailyPatternToken 匹配每日笔记路径模式中的 {{FORMAT}} 日期标记
+var dailyPatternToken = regexp.MustCompile(`\{\{[^}]+\}\}`)
+
+// defaultDailyPattern daily note path used until the user configures one, the default of Obsidian's daily notes
+// defaultDailyPattern 用户未配置时使用的每日笔记路径，与 Obsidian 每日笔记的默认值一致
+const defaultDailyPattern = "{{YYYY-MM-DD}}.md"
+
+// momentTokens Moment.js format tokens used by Obsidian templates and their Go layouts, longest first
+// momentTokens Obsidian 模板使用的 Moment.js 格式标记及对应的 Go 布局，较长的标记在前
+var momentTokens = []struct{ moment, layout string }{
+	{"YYYY", "2006"}, {"YY", "06"},
+	{"MMMM", "January"}, {"MMM", "Jan"}, {"MM", "01"}, {"M", "1"},
+	{"dddd", "Monday"}, {"ddd", "Mon"},
+	{"DD", "02"}, {"D", "2"},
+	{"HH", "15"}, {"hh", "03"}, {"h", "3"},
+	{"mm", "04"}, {"m", "4"},
+	{"ss", "05"}, {"s", "5"},
+	{"A", "PM"}, {"a", "pm"},
+}
+
+// TemplateService defines the note template business service interface
+// TemplateService 定义笔记模板业务服务接口
+type TemplateService interface {
+	// List lists all templates of a user
+	// List 列出用户的全部模板
+	List(ctx context.Context, uid int64) ([]*dto.NoteTemplateDTO, error)
+
+	// Get returns a template
+	// Get 获取模板
+	Get(ctx context.Context, uid int64, id int64) (*dto.NoteTemplateDTO, error)
+
+	// Create creates a template, names are unique per user
+	// Create 新建模板，名称在用户内唯一
+	Create(ctx context.Context, uid int64, params *dto.NoteTemplateRequest) (*dto.NoteTemplateDTO, error)
+
+	// Update updates a template
+	// Update 更新模板
+	Update(ctx context.Context, uid int64, id int64, params *dto.NoteTemplateRequest) (*dto.NoteTemplateDTO, error)
+
+	// Delete removes a template
+	// Delete 删除模板
+	Delete(ctx context.Context, uid int64, id int64) error
+
+	// Expand returns the content of the named template with its variables expanded for the note at notePath and time at
+	// Expand 返回指定名称模板的内容，变量按 notePath 处的笔记与时间 at 展开
+	Expand(ctx context.Context, uid int64, name string, notePath string, at time.Time) (string, error)
+
+	// GetDailySetting returns the daily note setting of a user, defaults when it was never saved
+	// GetDailySetting 获取用户的每日笔记设置，从未保存时返回默认值
+	GetDailySetting(ctx context.Context, uid int64) (*dto.NoteDailySettingDTO, error)
+
+	// SaveDailySetting validates and stores the daily note setting of a user
+	// SaveDailySetting 校验并存储用户的每日笔记设置
+	SaveDailySetting(ctx context.Context, uid int64, params *dto.NoteDailySettingRequest) (*dto.NoteDailySettingDTO, error)
+
+	// ResolveDaily returns the path of the daily note of date (YYYY-MM-DD, empty for today) and the content a missing note is created with
+	// ResolveDaily 返回 date（YYYY-MM-DD，为空表示今天）对应的每日笔记路径，以及笔记缺失时用于创建的内容
+	ResolveDaily(ctx context.Context, uid int64, date string) (string, string, error)
+}
+
+// templateService implements TemplateService
+// templateService 实现 TemplateService 接口
+type templateService struct {
+	repo   domain.NoteTemplateRepository
+	logger *zap.Logger
+}
+
+// NewTemplateService creates a TemplateService instance
+// NewTemplateService 创建 TemplateService 实例
+func NewTemplateService(repo domain.NoteTemplateRepository, logger *zap.Logger) TemplateService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &templateService{repo: repo, logger: logger}
+}
+
+// noteTemplateToDTO converts the domain model to the DTO
+// noteTemplateToDTO 将领域模型转换为 DTO
+func noteTemplateToDTO(t *domain.NoteTemplate) *dto.NoteTemplateDTO {
+	return &dto.NoteTemplateDTO{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Content:     t.Content,
+		CreatedAt:   timex.Time(t.CreatedAt),
+		UpdatedAt:   timex.Time(t.UpdatedAt),
+	}
+}
+
+// get returns a template of the user or ErrorNoteTemplateNotFound
+// get 获取用户的模板，不存在时返回 ErrorNoteTemplateNotFound
+func (s *templateService) get(ctx context.Context, uid, id int64) (*domain.NoteTemplate, error) {
+	t, err := s.repo.Get(ctx, id, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if t == nil {
+		return nil, code.ErrorNoteTemplateNotFound
+	}
+	return t, nil
+}
+
+// checkName returns ErrorNoteTemplateExist when another template of the user already has the name
+// checkName 当用户的其他模板已使用该名称时返回 ErrorNoteTemplateExist
+func (s *templateService) checkName(ctx context.Context, uid int64, name string, id int64) error {
+	existing, err := s.repo.GetByName(ctx, name, uid)
+	if err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if existing != nil && existing.ID != id {
+		return code.ErrorNoteTemplateExist
+	}
+	return nil
+}
+
+// List lists all templates of a user
+// List 列出用户的全部模板
+func (s *templateService) List(ctx context.Context, uid int64) ([]*dto.NoteTemplateDTO, error) {
+	list, err := s.repo.List(ctx, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	results := make([]*dto.NoteTemplateDTO, 0, len(list))
+	for _, t := range list {
+		results = append(results, noteTemplateToDTO(t))
+	}
+	return results, nil
+}
+
+// Get returns a template
+// Get 获取模板
+func (s *templateService) Get(ctx context.Context, uid int64, id int64) (*dto.NoteTemplateDTO, error) {
+	t, err := s.get(ctx, uid, id)
+	if err != nil {
+		return nil, err
+	}
+	return noteTemplateToDTO(t), nil
+}
+
+// Create creates a template, names are unique per user
+// Create 新建模板，名称在用户内唯一
+func (s *templateService) Create(ctx context.Context, uid int64, params *dto.NoteTemplateRequest) (*dto.NoteTemplateDTO, error) {
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		return nil, code.ErrorInvalidParams.WithDetails("name is required")
+	}
+	if err := s.checkName(ctx, uid, name, 0); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.repo.Save(ctx, &domain.NoteTemplate{
+		UID:         uid,
+		Name:        name,
+		Description: params.Description,
+		Content:     params.Content,
+	}, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return noteTemplateToDTO(saved), nil
+}
+
+// Update updates a template
+// Update 更新模板
+func (s *templateService) Update(ctx context.Context, uid int64, id int64, params *dto.NoteTemplateRequest) (*dto.NoteTemplateDTO, error) {
+	existing, err := s.get(ctx, uid, id)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(params.Name)
+	if name == "" {
+		return nil, code.ErrorInvalidParams.WithDetails("name is required")
+	}
+	if err := s.checkName(ctx, uid, name, id); err != nil {
+		return nil, err
+	}
+
+	existing.Name = name
+	existing.Description = params.Description
+	existing.Content = params.Content
+	saved, err := s.repo.Save(ctx, existing, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return noteTemplateToDTO(saved), nil
+}
+
+// Delete removes a template
+// Delete 删除模板
+func (s *templateService) Delete(ctx context.Context, uid int64, id int64) error {
+	if _, err := s.get(ctx, uid, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id, uid); err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// Expand returns the content of the named template with its variables expanded for the note at notePath and time at.
+// {{title}} is the note file name without extension.
+// {{title}} 为不含扩展名的笔记文件名。
+func (s *templateService) Expand(ctx context.Context, uid int64, name string, notePath string, at time.Time) (string, error) {
+	t, err := s.repo.GetByName(ctx, strings.TrimSpace(name), uid)
+	if err != nil {
+		return "", code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if t == nil {
+		return "", code.ErrorNoteTemplateNotFound.WithDetails(name)
+	}
+	title := strings.TrimSuffix(path.Base(notePath), path.Ext(notePath))
+	return expandNoteTemplate(t.Content, title, at), nil
+}
+
+// GetDailySetting returns the daily note setting of a user, defaults when it was never saved
+// GetDailySetting 获取用户的每日笔记设置，从未保存时返回默认值
+func (s *templateService) GetDailySetting(ctx context.Context, uid int64) (*dto.NoteDailySettingDTO, error) {
+	setting, err := s.dailySetting(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.NoteDailySettingDTO{
+		Pattern:   setting.Pattern,
+		Template:  setting.Template,
+		Timezone:  setting.Timezone,
+		UpdatedAt: timex.Time(setting.UpdatedAt),
+	}, nil
+}
+
+// SaveDailySetting validates and stores the daily note setting of a user.
+// The pattern must contain a date token and resolve to a valid path; ".md" is added when missing.
+// 路径模式必须包含日期标记并解析为合法路径；缺少 ".md" 时自动补全。
+func (s *templateService) SaveDailySetting(ctx context.Context, uid int64, params *dto.NoteDailySettingRequest) (*dto.NoteDailySettingDTO, error) {
+	pattern := strings.Trim(strings.TrimSpace(params.Pattern), "/")
+	if pattern == "" {
+		pattern = defaultDailyPattern
+	}
+	if !strings.HasSuffix(strings.ToLower(pattern), ".md") {
+		pattern += ".md"
+	}
+	if !dailyPatternToken.MatchString(pattern) || !util.ValidatePath(expandDailyPattern(pattern, time.Now())) {
+		return nil, code.ErrorInvalidParams.WithDetails("pattern must contain a date token such as {{YYYY-MM-DD}} and resolve to a valid path")
+	}
+
+	timezone := strings.TrimSpace(params.Timezone)
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, code.ErrorInvalidParams.WithDetails("unknown timezone: " + timezone)
+	}
+
+	template := strings.TrimSpace(params.Template)
+	if template != "" {
+		t, err := s.repo.GetByName(ctx, template, uid)
+		if err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		if t == nil {
+			return nil, code.ErrorNoteTemplateNotFound.WithDetails(template)
+		}
+	}
+
+	saved, err := s.repo.SaveDailySetting(ctx, &domain.NoteDailySetting{
+		UID:      uid,
+		Pattern:  pattern,
+		Template: template,
+		Timezone: timezone,
+	}, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return &dto.NoteDailySettingDTO{
+		Pattern:   saved.Pattern,
+		Template:  saved.Template,
+		Timezone:  saved.Timezone,
+		UpdatedAt: timex.Time(saved.UpdatedAt),
+	}, nil
+}
+
+// ResolveDaily returns the path of the daily note of date (YYYY-MM-DD, empty for today) and the content a
+// missing note is created with. Template variables are expanded for the day of the note, not the time of the call.
+// ResolveDaily 返回 date（YYYY-MM-DD，为空表示今天）对应的每日笔记路径，以及笔记缺失时用于创建的内容。模板变量按笔记所属日期展开，而非调用时间。
+func (s *templateService) ResolveDaily(ctx context.Context, uid int64, date string) (string, string, error) {
+	setting, err := s.dailySetting(ctx, uid)
+	if err != nil {
+		return "", "", err
+	}
+	loc, err := time.LoadLocation(setting.Timezone)
+	if err != nil {
+		loc = time.Local
+	}
+
+	day := time.Now().In(loc)
+	if date != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", date, loc)
+		if err != nil {
+			return "", "", code.ErrorInvalidParams.WithDetails("date must be YYYY-MM-DD")
+		}
+		// Keep the current time of day so {{time}} stays meaningful for other days
+		// 保留当前时刻，使 {{time}} 对其他日期同样有意义
+		day = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), day.Hour(), day.Minute(), day.Second(), 0, loc)
+	}
+
+	notePath := expandDailyPattern(setting.Pattern, day)
+	if !util.ValidatePath(notePath) {
+		return "", "", code.ErrorInvalidPath
+	}
+	if setting.Template == "" {
+		return notePath, "", nil
+	}
+	content, err := s.Expand(ctx, uid, setting.Template, notePath, day)
+	if err != nil {
+		return "", "", err
+	}
+	return notePath, content, nil
+}
+
+// dailySetting returns the stored daily note setting of a user or the defaults
+// dailySetting 返回用户已存储的每日笔记设置或默认值
+func (s *templateService) dailySetting(ctx context.Context, uid int64) (*domain.NoteDailySetting, error) {
+	setting, err := s.repo.GetDailySetting(ctx, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if setting == nil {
+		setting = &domain.NoteDailySetting{UID: uid}
+	}
+	if setting.Pattern == "" {
+		setting.Pattern = defaultDailyPattern
+	}
+	return setting, nil
+}
+
+// expandDailyPattern replaces every {{FORMAT}} of pattern with at formatted by the Moment.js format
+// expandDailyPattern 将 pattern 中的每个 {{FORMAT}} 替换为按 Moment.js 格式格式化的 at
+func expandDailyPattern(pattern string, at time.Time) string {
+	return dailyPatternToken.ReplaceAllStringFunc(pattern, func(match string) string {
+		return at.Format(momentLayout(strings.TrimSpace(match[2 : len(match)-2])))
+	})
+}
+
+// expandNoteTemplate replaces the {{date}}, {{time}} and {{title}} variables of content.
+// {{date}} defaults to YYYY-MM-DD and {{time}} to HH:mm; both accept a Moment.js format after a colon.
+// expandNoteTemplate 替换 content 中的 {{date}}、{{time}}、{{title}} 变量。
+// {{date}} 默认格式为 YYYY-MM-DD，{{time}} 默认为 HH:mm；两者均可在冒号后指定 Moment.js 格式。
+func expandNoteTemplate(content, title string, now time.Time) string {
+	return templateVariable.ReplaceAllStringFunc(content, func(match string) string {
+		sub := templateVariable.FindStringSubmatch(match)
+		format := strings.TrimSpace(sub[2])
+		switch sub[1] {
+		case "title":
+			return title
+		case "date":
+			if format == "" {
+				format = "YYYY-MM-DD"
+			}
+		case "time":
+			if format == "" {
+				format = "HH:mm"
+			}
+		}
+		return now.Format(momentLayout(format))
+	})
+}
+
+// momentLayout converts a Moment.js format into a Go time layout, unknown characters are kept as they are
+// momentLayout 将 Moment.js 格式转换为 Go 时间布局，未知字符原样保留
+func momentLayout(format string) string {
+	var b strings.Builder
+	for i := 0; i < len(format); {
+		matched := false
+		for _, tok := range momentTokens {
+			if strings.HasPrefix(format[i:], tok.moment) {
+				b.WriteString(tok.layout)
+				i += len(tok.moment)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(format[i])
+			i++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeNoteTemplateRepo in-memory domain.NoteTemplateRepository
+type fakeNoteTemplateRepo struct {
+	templates map[int64]*domain.NoteTemplate
+	nextID    int64
+	daily     *domain.NoteDailySetting
+}
+
+func (r *fakeNoteTemplateRepo) List(ctx context.Context, uid int64) ([]*domain.NoteTemplate, error) {
+	var list []*domain.NoteTemplate
+	for _, t := range r.templates {
+		list = append(list, t)
+	}
+	return list, nil
+}
+
+func (r *fakeNoteTemplateRepo) Get(ctx context.Context, id, uid int64) (*domain.NoteTemplate, error) {
+	return r.templates[id], nil
+}
+
+func (r *fakeNoteTemplateRepo) GetByName(ctx context.Context, name string, uid int64) (*domain.NoteTemplate, error) {
+	for _, t := range r.templates {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeNoteTemplateRepo) Save(ctx context.Context, template *domain.NoteTemplate, uid int64) (*domain.NoteTemplate, error) {
+	if template.ID == 0 {
+		r.nextID++
+		template.ID = r.nextID
+	}
+	r.templates[template.ID] = template
+	return template, nil
+}
+
+func (r *fakeNoteTemplateRepo) Delete(ctx context.Context, id, uid int64) error {
+	delete(r.templates, id)
+	return nil
+}
+
+func (r *fakeNoteTemplateRepo) GetDailySetting(ctx context.Context, uid int64) (*domain.NoteDailySetting, error) {
+	return r.daily, nil
+}
+
+func (r *fakeNoteTemplateRepo) SaveDailySetting(ctx context.Context, setting *domain.NoteDailySetting, uid int64) (*domain.NoteDailySetting, error) {
+	r.daily = setting
+	return setting, nil
+}
+
+func TestExpandNoteTemplate(t *testing.T) {
+	now := time.Date(2026, 3, 7, 9, 5, 30, 0, time.Local)
+	content := "# {{title}}\n{{date}} {{time}}\n{{ date:dddd, MMMM D YYYY }} {{time:HH:mm:ss}}\n{{unknown}}"
+
+	got := expandNoteTemplate(content, "Standup", now)
+	assert.Equal(t, "# Standup\n2026-03-07 09:05\nSaturday, March 7 2026 09:05:30\n{{unknown}}", got)
+}
+
+func TestTemplateService_CreateRejectsDuplicateNameAndExpands(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeNoteTemplateRepo{templates: map[int64]*domain.NoteTemplate{}}
+	svc := NewTemplateService(repo, zap.NewNop())
+
+	created, err := svc.Create(ctx, 1, &dto.NoteTemplateRequest{Name: " Daily ", Content: "# {{title}}"})
+	require.NoError(t, err)
+	assert.Equal(t, "Daily", created.Name)
+
+	_, err = svc.Create(ctx, 1, &dto.NoteTemplateRequest{Name: "Daily"})
+	assert.ErrorIs(t, err, code.ErrorNoteTemplateExist)
+
+	other, err := svc.Create(ctx, 1, &dto.NoteTemplateRequest{Name: "Meeting"})
+	require.NoError(t, err)
+	_, err = svc.Update(ctx, 1, other.ID, &dto.NoteTemplateRequest{Name: "Daily"})
+	assert.ErrorIs(t, err, code.ErrorNoteTemplateExist)
+
+	content, err := svc.Expand(ctx, 1, "Daily", "Journal/2026-03-07.md", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "# 2026-03-07", content)
+
+	_, err = svc.Expand(ctx, 1, "Missing", "a.md", time.Now())
+	assert.ErrorIs(t, err, code.ErrorNoteTemplateNotFound)
+}
+
+func TestTemplateService_ResolveDaily(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeNoteTemplateRepo{templates: map[int64]*domain.NoteTemplate{}}
+	svc := NewTemplateService(repo, zap.NewNop())
+
+	notePath, content, err := svc.ResolveDaily(ctx, 1, "2026-03-07")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-07.md", notePath, "the default pattern applies until one is saved")
+	assert.Empty(t, content)
+
+	_, err = svc.SaveDailySetting(ctx, 1, &dto.NoteDailySettingRequest{Pattern: "Journal/{{YYYY-MM-DD}}", Template: "Daily"})
+	assert.ErrorIs(t, err, code.ErrorNoteTemplateNotFound)
+	_, err = svc.SaveDailySetting(ctx, 1, &dto.NoteDailySettingRequest{Pattern: "Journal/today"})
+	assert.ErrorIs(t, err, code.ErrorInvalidParams)
+	_, err = svc.SaveDailySetting(ctx, 1, &dto.NoteDailySettingRequest{Timezone: "Mars/Olympus"})
+	assert.ErrorIs(t, err, code.ErrorInvalidParams)
+
+	_, err = svc.Create(ctx, 1, &dto.NoteTemplateRequest{Name: "Daily", Content: "# {{date:dddd}} {{title}}"})
+	require.NoError(t, err)
+	setting, err := svc.SaveDailySetting(ctx, 1, &dto.NoteDailySettingRequest{Pattern: "/Journal/{{YYYY}}/{{YYYY-MM-DD}}", Template: "Daily", Timezone: "Asia/Shanghai"})
+	require.NoError(t, err)
+	assert.Equal(t, "Journal/{{YYYY}}/{{YYYY-MM-DD}}.md", setting.Pattern)
+
+	notePath, content, err = svc.ResolveDaily(ctx, 1, "2026-03-07")
+	require.NoError(t, err)
+	assert.Equal(t, "Journal/2026/2026-03-07.md", notePath)
+	assert.Equal(t, "# Saturday 2026-03-07", content, "template dates follow the day of the note")
+
+	_, _, err = svc.ResolveDaily(ctx, 1, "07/03/2026")
+	assert.ErrorIs(t, err, code.ErrorInvalidParams)
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/keyedmutex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/thumbnail"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+// defaultThumbnailWidths widths used when the config lists none
+// defaultThumbnailWidths 配置中未设置宽度时使用的默认宽度
+var defaultThumbnailWidths = []int{256, 1024}
+
+// Thumbnail a rendered thumbnail in the cache directory
+// Thumbnail 缓存目录中已生成的缩略图
+type Thumbnail struct {
+	Path        string        // Cached file // 缓存文件
+	Name        string        // File name offered to the client // 提供给客户端的文件名
+	ContentType string        // MIME type // MIME 类型
+	ETag        string        // Quoted entity tag // 带引号的实体标签
+	ModTime     time.Time     // Modification time of the source attachment // 源附件的修改时间
+	CacheMaxAge time.Duration // max-age of the Cache-Control header // Cache-Control 头的 max-age
+}
+
+// ThumbnailService defines the image thumbnail service interface.
+// Thumbnails are cached per user under the thumbnail save path, keyed by the content of the source,
+// so an edited image gets new thumbnails and unchanged ones are never rendered twice.
+// ThumbnailService 定义图片缩略图服务接口。
+// 缩略图按用户缓存在缩略图目录下，以源文件内容为键，图片修改后会生成新的缩略图，未修改的图片不会重复生成。
+type ThumbnailService interface {
+	// Get returns the thumbnail of an image attachment, rendering it on first request
+	// Get 返回图片附件的缩略图，首次请求时生成
+	Get(ctx context.Context, uid int64, params *dto.FileThumbnailRequest) (*Thumbnail, error)
+
+	// Prepare renders every configured width of a freshly uploaded attachment when generate-on-upload is on;
+	// attachments that are not images are ignored
+	// Prepare 开启 generate-on-upload 时为刚上传的附件生成所有配置宽度的缩略图，非图片附件将被忽略
+	Prepare(ctx context.Context, uid int64, file *dto.FileDTO) error
+}
+
+type thumbnailService struct {
+	fileService   FileService
+	config        *config.ThumbnailConfig
+	widths        []int
+	maxSourceSize int64
+	cacheMaxAge   time.Duration
+	locks         *keyedmutex.KeyedMutex
+	logger        *zap.Logger
+}
+
+// NewThumbnailService creates ThumbnailService instance
+// NewThumbnailService 创建 ThumbnailService 实例
+func NewThumbnailService(fileService FileService, cfg *config.ThumbnailConfig, logger *zap.Logger) ThumbnailService {
+	widths := make([]int, 0, len(cfg.Widths))
+	for _, w := range cfg.Widths {
+		if w > 0 {
+			widths = append(widths, w)
+		}
+	}
+	if len(widths) == 0 {
+		widths = append(widths, defaultThumbnailWidths...)
+	}
+	sort.Ints(widths)
+
+	cacheMaxAge, err := util.ParseDuration(cfg.CacheMaxAge)
+	if err != nil || cacheMaxAge < 0 {
+		cacheMaxAge = time.Hour
+	}
+
+	return &thumbnailService{
+		fileService:   fileService,
+		config:        cfg,
+		widths:        widths,
+		maxSourceSize: util.ParseSize(cfg.MaxSourceSize, 50*1024*1024),
+		cacheMaxAge:   cacheMaxAge,
+		locks:         keyedmutex.New(),
+		logger:        logger,
+	}
+}
+
+// width picks the smallest configured width not below the requested one, the largest when none is
+// width 选择不小于所请求宽度的最小配置宽度，均小于请求宽度时选择最大宽度
+func (s *thumbnailService) width(requested int) int {
+	for _, w := range s.widths {
+		if w >= requested {
+			return w
+		}
+	}
+	return s.widths[len(s.widths)-1]
+}
+
+// cachePath returns the cache file of a thumbnail. The key combines the content hash and the
+// modification time of the source, so it changes whenever the attachment does.
+// cachePath 返回缩略图的缓存文件路径，键由源文件内容哈希与修改时间组成，附件变化时键随之变化。
+func (s *thumbnailService) cachePath(uid int64, etag string, mtime int64, width int, ext string) (string, string) {
+	key := util.EncodeHash32(fmt.Sprintf("%s:%d", etag, mtime))
+	name := fmt.Sprintf("%s_%d%s", key, width, ext)
+	return filepath.Join(s.config.SavePath, strconv.FormatInt(uid, 10), name), fmt.Sprintf(`"%s-%d"`, key, width)
+}
+
+// render writes the thumbnail of savePath to cachePath unless it is cached already
+// render 将 savePath 的缩略图写入 cachePath，已缓存时跳过
+func (s *thumbnailService) render(savePath, name string, width int, cachePath string) error {
+	unlock := s.locks.Lock(cachePath)
+	defer unlock()
+
+	if _, err := os.Stat(cachePath); err == nil {
+		return nil
+	}
+
+	src, err := atrest.Open(savePath)
+	if err != nil {
+		return code.ErrorFileReadFailed.WithDetails(err.Error())
+	}
+	defer src.Close()
+
+	if src.Size() > s.maxSourceSize {
+		return code.ErrorFileThumbnailUnsupported.WithDetails("image exceeds max-source-size")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return code.ErrorFileThumbnailFailed.WithDetails(err.Error())
+	}
+
+	// Render into a temp file first so a concurrent reader never sees a partial thumbnail
+	// 先写入临时文件，避免并发读取到不完整的缩略图
+	tmp, err := os.CreateTemp(filepath.Dir(cachePath), ".thumb-*")
+	if err != nil {
+		return code.ErrorFileThumbnailFailed.WithDetails(err.Error())
+	}
+	defer os.Remove(tmp.Name())
+
+	// Thumbnails are derived from attachment content, so they are stored encrypted as well
+	// 缩略图源自附件内容，因此同样加密存储
+	w, err := atrest.NewWriter(tmp)
+	if err == nil {
+		err = thumbnail.Render(src, name, width, w)
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if errors.Is(err, thumbnail.ErrTooLarge) {
+		return code.ErrorFileThumbnailUnsupported.WithDetails(err.Error())
+	}
+	if err != nil {
+		return code.ErrorFileThumbnailFailed.WithDetails(err.Error())
+	}
+
+	if err := os.Rename(tmp.Name(), cachePath); err != nil {
+		return code.ErrorFileThumbnailFailed.WithDetails(err.Error())
+	}
+	return nil
+}
+
+// Get returns the thumbnail of an image attachment, rendering it on first request
+// Get 返回图片附件的缩略图，首次请求时生成
+func (s *thumbnailService) Get(ctx context.Context, uid int64, params *dto.FileThumbnailRequest) (*Thumbnail, error) {
+	format, err := thumbnail.OutputFormat(params.Path)
+	if err != nil {
+		return nil, code.ErrorFileThumbnailUnsupported
+	}
+
+	savePath, _, mtime, etag, fileName, err := s.fileService.GetContentInfo(ctx, uid, &dto.FileGetRequest{
+		Vault:    params.Vault,
+		Path:     params.Path,
+		PathHash: params.PathHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The fallback lookup by file name may resolve to another attachment, check its type again
+	// 按文件名回退查找可能解析到其他附件，需再次检查其类型
+	if format, err = thumbnail.OutputFormat(fileName); err != nil {
+		return nil, code.ErrorFileThumbnailUnsupported
+	}
+
+	width := s.width(params.Width)
+	cachePath, cacheETag := s.cachePath(uid, etag, mtime, width, format.Ext)
+	if err := s.render(savePath, fileName, width, cachePath); err != nil {
+		return nil, err
+	}
+
+	return &Thumbnail{
+		Path:        cachePath,
+		Name:        fmt.Sprintf("%s_%d%s", strings.TrimSuffix(fileName, filepath.Ext(fileName)), width, format.Ext),
+		ContentType: format.ContentType,
+		ETag:        cacheETag,
+		ModTime:     time.UnixMilli(mtime),
+		CacheMaxAge: s.cacheMaxAge,
+	}, nil
+}
+
+// Prepare renders every configured width of a freshly uploaded attachment when generate-on-upload is on
+// Prepare 开启 generate-on-upload 时为刚上传的附件生成所有配置宽度的缩略图
+func (s *thumbnailService) Prepare(ctx context.Context, uid int64, file *dto.FileDTO) error {
+	if file == nil || s.config.GenerateOnUpload == nil || !*s.config.GenerateOnUpload {
+		return nil
+	}
+	format, err := thumbnail.OutputFormat(file.Path)
+	if err != nil {
+		return nil
+	}
+
+	etag := file.ContentHash
+	if etag == "" {
+		etag = file.PathHash
+	}
+	for _, width := range s.widths {
+		cachePath, _ := s.cachePath(uid, etag, file.Mtime, width, format.Ext)
+		if err := s.render(file.SavePath, filepath.Base(file.Path), width, cachePath); err != nil {
+			return err
+		}
+	}
+	s.logger.Debug("thumbnails prepared", zap.Int64("uid", uid), zap.String("path", file.Path), zap.Ints("widths", s.widths))
+	return nil
+}
+
+var _ ThumbnailService = (*thumbnailService)(nil)
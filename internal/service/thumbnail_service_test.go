@@ -0,0 +1,134 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/config"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeThumbnailFileService serves a single attachment stored at savePath
+// fakeThumbnailFileService 提供保存在 savePath 的单个附件
+type fakeThumbnailFileService struct {
+	FileService
+	savePath string
+	name     string
+	etag     string
+	mtime    int64
+	calls    int
+}
+
+func (s *fakeThumbnailFileService) GetContentInfo(ctx context.Context, uid int64, params *dto.FileGetRequest) (string, string, int64, string, string, error) {
+	s.calls++
+	if params.Path != s.name {
+		return "", "", 0, "", "", code.ErrorFileNotFound
+	}
+	return s.savePath, "image/png", s.mtime, s.etag, s.name, nil
+}
+
+func newThumbnailTestService(t *testing.T, widths []int) (*fakeThumbnailFileService, ThumbnailService, string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 2000, 1000))))
+	savePath := filepath.Join(dir, "file.dat")
+	require.NoError(t, os.WriteFile(savePath, buf.Bytes(), 0644))
+
+	files := &fakeThumbnailFileService{savePath: savePath, name: "Photo.png", etag: "hash1", mtime: 1700000000000}
+	generate := true
+	cfg := &config.ThumbnailConfig{
+		Widths:           widths,
+		SavePath:         filepath.Join(dir, "thumbs"),
+		GenerateOnUpload: &generate,
+		MaxSourceSize:    "10MB",
+		CacheMaxAge:      "2h",
+	}
+	return files, NewThumbnailService(files, cfg, zap.NewNop()), cfg.SavePath
+}
+
+func decodeThumbnail(t *testing.T, path string) image.Rectangle {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	img, err := png.Decode(f)
+	require.NoError(t, err)
+	return img.Bounds()
+}
+
+func TestThumbnailService_Get(t *testing.T) {
+	files, svc, cacheDir := newThumbnailTestService(t, []int{1024, 128})
+	ctx := context.Background()
+
+	// Requested widths snap to the smallest configured width not below them
+	thumb, err := svc.Get(ctx, 1, &dto.FileThumbnailRequest{Vault: "v", Path: "Photo.png", Width: 200})
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", thumb.ContentType)
+	assert.Equal(t, "Photo_1024.png", thumb.Name)
+	assert.Equal(t, image.Rect(0, 0, 1024, 512), decodeThumbnail(t, thumb.Path))
+	assert.True(t, strings.HasPrefix(thumb.Path, filepath.Join(cacheDir, "1")))
+
+	small, err := svc.Get(ctx, 1, &dto.FileThumbnailRequest{Vault: "v", Path: "Photo.png"})
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 128, 64), decodeThumbnail(t, small.Path))
+	assert.NotEqual(t, thumb.ETag, small.ETag)
+
+	// A cached thumbnail is served without rendering the source again
+	require.NoError(t, os.Remove(files.savePath))
+	again, err := svc.Get(ctx, 1, &dto.FileThumbnailRequest{Vault: "v", Path: "Photo.png", Width: 5000})
+	require.NoError(t, err)
+	assert.Equal(t, thumb.Path, again.Path)
+	assert.Equal(t, thumb.ETag, again.ETag)
+
+	// A changed attachment is rendered again, which fails now that its source is gone
+	files.etag = "hash2"
+	_, err = svc.Get(ctx, 1, &dto.FileThumbnailRequest{Vault: "v", Path: "Photo.png"})
+	require.Error(t, err)
+	assert.Equal(t, code.ErrorFileReadFailed.Code(), err.(*code.Code).Code())
+}
+
+func TestThumbnailService_Unsupported(t *testing.T) {
+	files, svc, _ := newThumbnailTestService(t, nil)
+	ctx := context.Background()
+
+	_, err := svc.Get(ctx, 1, &dto.FileThumbnailRequest{Vault: "v", Path: "Doc.pdf"})
+	assert.Equal(t, code.ErrorFileThumbnailUnsupported, err)
+	assert.Zero(t, files.calls, "unsupported types are refused before the lookup")
+
+	_, err = svc.Get(ctx, 1, &dto.FileThumbnailRequest{Vault: "v", Path: "Missing.png"})
+	assert.Equal(t, code.ErrorFileNotFound, err)
+}
+
+func TestThumbnailService_Prepare(t *testing.T) {
+	files, svc, cacheDir := newThumbnailTestService(t, nil)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Prepare(ctx, 7, &dto.FileDTO{Path: "Notes/a.md", SavePath: files.savePath}))
+	require.NoError(t, svc.Prepare(ctx, 7, &dto.FileDTO{
+		Path:        "Photo.png",
+		ContentHash: files.etag,
+		SavePath:    files.savePath,
+		Mtime:       files.mtime,
+	}))
+
+	entries, err := os.ReadDir(filepath.Join(cacheDir, "7"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "one thumbnail per default width")
+
+	// Get finds the prepared thumbnails without touching the source
+	require.NoError(t, os.Remove(files.savePath))
+	_, err = svc.Get(ctx, 7, &dto.FileThumbnailRequest{Vault: "v", Path: "Photo.png", Width: 1024})
+	require.NoError(t, err)
+}
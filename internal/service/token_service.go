@@ -12,6 +12,7 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
 	"github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/revocation"
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"go.uber.org/zap"
@@ -24,12 +25,18 @@ type TokenService interface {
 	Create(ctx context.Context, uid int64, params *dto.TokenIssueRequest) (*dto.TokenCreateResponse, error)
 	// CreateForLogin creates a token during the login flow
 	CreateForLogin(ctx context.Context, uid int64, clientType, ip, userAgent string) (*domain.AuthToken, string, error)
+	// CreateForTOTPSetup creates a login token that only reaches the TOTP enrollment routes
+	// CreateForTOTPSetup 创建仅能访问 TOTP 设置接口的登录令牌
+	CreateForTOTPSetup(ctx context.Context, uid int64, clientType, ip, userAgent string) (*domain.AuthToken, string, error)
 	// ListByUser lists all active tokens for a user
 	ListByUser(ctx context.Context, uid int64) ([]*dto.TokenResponse, error)
 	// Update updates a token's properties
 	Update(ctx context.Context, uid int64, tokenID int64, params *dto.TokenUpdateRequest) error
 	// Revoke revokes a token
 	Revoke(ctx context.Context, uid int64, tokenID int64) error
+	// RevokeAll revokes every token of a user, e.g. on logout from all devices or when the user is disabled
+	// RevokeAll 注销用户的所有令牌，例如退出所有设备或用户被禁用时
+	RevokeAll(ctx context.Context, uid int64) error
 	// Rotate rotates a token (generates new JWT and invalidates old ones)
 	Rotate(ctx context.Context, uid int64, tokenID int64) (*dto.TokenCreateResponse, error)
 	// RotateForLogin rotates a login token for webgui
@@ -45,6 +52,12 @@ type TokenService interface {
 	UpdateLastUsedAt(ctx context.Context, tokenID int64) error
 	// SetSyncHandler sets the sync hook
 	SetSyncHandler(handler func(uid int64, tokenID int64, scope string, kick bool))
+	// SetDenylist sets the list the revoked tokens are added to and checked against
+	// SetDenylist 设置记录并校验已撤销令牌的拒绝列表
+	SetDenylist(list revocation.List)
+	// SetConfig replaces the token config after the admin changed it
+	// SetConfig 管理员修改配置后替换 Token 配置
+	SetConfig(config TokenServiceConfig)
 	// GetRecentClients gets unique client names for all tokens of a user in the last duration
 	// GetRecentClients 获取用户所有令牌在最近一段时间内的唯一客户端名称
 	GetRecentClients(ctx context.Context, uid int64, duration time.Duration) (map[int64][]string, error)
@@ -58,8 +71,10 @@ type tokenService struct {
 	logRepo      domain.AuthTokenLogRepository
 	tokenManager app.TokenManager
 	logger       *zap.Logger
+	configMu     sync.RWMutex                                            // Guards config // 保护 config
 	config       TokenServiceConfig                                      // Token config // Token 配置
 	lastLogMap   sync.Map                                                // TokenID -> time.Time (for 30s rate limiting)
+	denylist     revocation.List                                         // Revoked tokens, nil when not configured // 已撤销令牌，未配置时为 nil
 	SyncHandler  func(uid int64, tokenID int64, scope string, kick bool) // Hook for syncing to other modules (like WS)
 }
 
@@ -156,19 +171,28 @@ func (s *tokenService) Create(ctx context.Context, uid int64, params *dto.TokenI
 
 func (s *tokenService) CreateForLogin(ctx context.Context, uid int64, clientType, ip, userAgent string) (*domain.AuthToken, string, error) {
 	// Restrict to REST protocol and bind to clientType
-	scope := "p:rest c:" + clientType + " f:*"
+	return s.createLoginToken(ctx, uid, "p:rest c:"+clientType+" f:*", clientType, ip, userAgent)
+}
+
+func (s *tokenService) CreateForTOTPSetup(ctx context.Context, uid int64, clientType, ip, userAgent string) (*domain.AuthToken, string, error) {
+	return s.createLoginToken(ctx, uid, "p:rest c:"+clientType+" f:"+app.ScopeFunctionTOTPSetup, clientType, ip, userAgent)
+}
 
+// createLoginToken creates a login token with the given scope
+// createLoginToken 创建指定作用域的登录令牌
+func (s *tokenService) createLoginToken(ctx context.Context, uid int64, scope, clientType, ip, userAgent string) (*domain.AuthToken, string, error) {
 	// Resolve expiry from config, fallback to 7 days
 	// 从配置读取有效期，默认 7 天
 	expiry := 7 * 24 * time.Hour
-	if d, err := util.ParseDuration(s.config.WebGUILoginTokenExpiry); err == nil && d > 0 {
+	config := s.currentConfig()
+	if d, err := util.ParseDuration(config.WebGUILoginTokenExpiry); err == nil && d > 0 {
 		expiry = d
 	}
 
 	// Bind IP only if configured
 	// 根据配置决定是否绑定 IP
 	boundIP := ""
-	if s.config.WebGUILoginTokenBindIP {
+	if config.WebGUILoginTokenBindIP {
 		boundIP = ip
 	}
 
@@ -300,6 +324,11 @@ func (s *tokenService) Revoke(ctx context.Context, uid int64, tokenID int64) err
 	if err != nil {
 		return code.ErrorDBQuery.WithDetails(err.Error())
 	}
+	if s.denylist != nil {
+		if err := s.denylist.RevokeToken(ctx, tokenID, token.ExpiredAt); err != nil {
+			s.logDenylistError("RevokeToken", uid, err)
+		}
+	}
 
 	// Trigger sync hook (scope empty means revoked/no permission)
 	if s.SyncHandler != nil {
@@ -308,6 +337,50 @@ func (s *tokenService) Revoke(ctx context.Context, uid int64, tokenID int64) err
 	return nil
 }
 
+// RevokeAll revokes every token of a user and closes their connections. The denylist entry covers
+// the tokens up to the highest active ID and lives until the last of them would have expired.
+// RevokeAll 注销用户的所有令牌并关闭其连接。拒绝列表条目覆盖截至最大活跃 ID 的令牌，
+// 并保留到其中最晚过期的令牌过期为止。
+func (s *tokenService) RevokeAll(ctx context.Context, uid int64) error {
+	tokens, err := s.tokenRepo.ListByUID(ctx, uid)
+	if err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	if err := s.tokenRepo.RevokeAllByUID(ctx, uid); err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	var maxID int64
+	var expiry time.Time
+	for _, t := range tokens {
+		maxID = max(maxID, t.ID)
+		if t.ExpiredAt.After(expiry) {
+			expiry = t.ExpiredAt
+		}
+	}
+	if s.denylist != nil && maxID > 0 && expiry.After(time.Now()) {
+		if err := s.denylist.RevokeUser(ctx, uid, maxID, expiry); err != nil {
+			s.logDenylistError("RevokeUser", uid, err)
+		}
+	}
+
+	if s.SyncHandler != nil {
+		for _, t := range tokens {
+			s.SyncHandler(uid, t.ID, "", true)
+		}
+	}
+	return nil
+}
+
+// logDenylistError logs a failed denylist call, the token status in the database still applies
+// logDenylistError 记录拒绝列表调用失败，数据库中的令牌状态仍然生效
+func (s *tokenService) logDenylistError(method string, uid int64, err error) {
+	if s.logger != nil {
+		s.logger.Warn("tokenService.denylist."+method+" failed", zap.Int64("uid", uid), zap.Error(err))
+	}
+}
+
 func (s *tokenService) Rotate(ctx context.Context, uid int64, tokenID int64) (*dto.TokenCreateResponse, error) {
 	token, err := s.tokenRepo.GetByID(ctx, tokenID)
 	if err != nil {
@@ -382,17 +455,24 @@ func (s *tokenService) RotateForLogin(ctx context.Context, uid int64, tokenID in
 		return nil, "", code.ErrorInvalidAuthToken.WithDetails("Token is not active")
 	}
 
+	// A TOTP setup token is replaced by a full login token instead of keeping its limited scope
+	// TOTP 设置令牌不沿用其受限作用域，需改为签发完整的登录令牌
+	if app.IsTOTPSetupScope(token.Scope) {
+		return nil, "", code.ErrorInvalidAuthToken.WithDetails("TOTP setup tokens cannot be rotated")
+	}
+
 	// Resolve expiry duration from config, fallback to 7 days
 	// 从配置解析过期时长，默认 7 天
 	expiry := 7 * 24 * time.Hour
-	if d, err := util.ParseDuration(s.config.WebGUILoginTokenExpiry); err == nil && d > 0 {
+	config := s.currentConfig()
+	if d, err := util.ParseDuration(config.WebGUILoginTokenExpiry); err == nil && d > 0 {
 		expiry = d
 	}
 
 	// Update bound IP if configured
 	// 若配置了绑定 IP 则进行更新
 	boundIP := ""
-	if s.config.WebGUILoginTokenBindIP {
+	if config.WebGUILoginTokenBindIP {
 		boundIP = ip
 	}
 
@@ -490,6 +570,14 @@ func (s *tokenService) GetActiveToken(ctx context.Context, uid int64, tokenID in
 	if token.UID != uid {
 		return nil, code.ErrorInvalidUserAuthToken.WithDetails("Token does not belong to the authenticated user")
 	}
+	if s.denylist != nil {
+		revoked, err := s.denylist.IsRevoked(ctx, uid, tokenID)
+		if err != nil {
+			s.logDenylistError("IsRevoked", uid, err)
+		} else if revoked {
+			return nil, code.ErrorInvalidUserAuthToken.WithDetails("Token has been revoked or no longer exists")
+		}
+	}
 	if token.Status != 1 {
 		return nil, code.ErrorInvalidUserAuthToken.WithDetails("Token has been revoked or no longer exists")
 	}
@@ -506,6 +594,24 @@ func (s *tokenService) SetSyncHandler(handler func(uid int64, tokenID int64, sco
 	s.SyncHandler = handler
 }
 
+func (s *tokenService) SetDenylist(list revocation.List) {
+	s.denylist = list
+}
+
+func (s *tokenService) SetConfig(config TokenServiceConfig) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+	s.config = config
+}
+
+// currentConfig returns the token config in effect
+// currentConfig 返回当前生效的 Token 配置
+func (s *tokenService) currentConfig() TokenServiceConfig {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.config
+}
+
 func (s *tokenService) GetRecentClients(ctx context.Context, uid int64, duration time.Duration) (map[int64][]string, error) {
 	return s.logRepo.ListRecentClientsByUID(ctx, uid, duration)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/revocation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
@@ -17,6 +18,8 @@ import (
 type stubAuthTokenRepository struct {
 	getByIDToken *domain.AuthToken
 	getByIDErr   error
+	active       []*domain.AuthToken
+	revokedAll   bool
 }
 
 func (r *stubAuthTokenRepository) Create(ctx context.Context, token *domain.AuthToken) (*domain.AuthToken, error) {
@@ -32,7 +35,7 @@ func (r *stubAuthTokenRepository) GetByTokenString(ctx context.Context, tokenStr
 }
 
 func (r *stubAuthTokenRepository) ListByUID(ctx context.Context, uid int64) ([]*domain.AuthToken, error) {
-	return nil, errors.New("not implemented")
+	return r.active, nil
 }
 
 func (r *stubAuthTokenRepository) Update(ctx context.Context, token *domain.AuthToken) error {
@@ -52,7 +55,8 @@ func (r *stubAuthTokenRepository) Revoke(ctx context.Context, id int64) error {
 }
 
 func (r *stubAuthTokenRepository) RevokeAllByUID(ctx context.Context, uid int64) error {
-	return errors.New("not implemented")
+	r.revokedAll = true
+	return nil
 }
 
 func (r *stubAuthTokenRepository) RevokeExpiredByUID(ctx context.Context, uid int64, issueType int) error {
@@ -181,3 +185,38 @@ func TestTokenService_GetActiveToken_Valid(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Same(t, want, got)
 }
+
+// TestTokenService_RevokeAll_DeniesIssuedTokens verifies logging out everywhere denies the tokens issued
+// so far even while the database still reports them active, kicks their connections and leaves the
+// tokens issued afterwards valid.
+// TestTokenService_RevokeAll_DeniesIssuedTokens 验证退出所有设备后，即使数据库仍显示令牌有效，已签发的令牌也会被拒绝，
+// 其连接被踢下线，之后签发的令牌仍然有效。
+func TestTokenService_RevokeAll_DeniesIssuedTokens(t *testing.T) {
+	expiry := time.Now().Add(time.Hour)
+	repo := &stubAuthTokenRepository{active: []*domain.AuthToken{
+		{ID: 2, UID: 1, Status: 1, ExpiredAt: expiry},
+		{ID: 5, UID: 1, Status: 1, ExpiredAt: expiry},
+	}}
+	svc := NewTokenService(repo, nil, app.NewTokenManager(app.TokenConfig{SecretKey: "test-secret"}), nil, TokenServiceConfig{})
+	list, err := revocation.NewMemory("")
+	require.NoError(t, err)
+	svc.SetDenylist(list)
+	var kicked []int64
+	svc.SetSyncHandler(func(uid int64, tokenID int64, scope string, kick bool) {
+		if kick {
+			kicked = append(kicked, tokenID)
+		}
+	})
+
+	require.NoError(t, svc.RevokeAll(context.Background(), 1))
+	assert.True(t, repo.revokedAll)
+	assert.Equal(t, []int64{2, 5}, kicked)
+
+	repo.getByIDToken = &domain.AuthToken{ID: 5, UID: 1, Status: 1, ExpiredAt: expiry}
+	_, err = svc.GetActiveToken(context.Background(), 1, 5)
+	assert.Equal(t, code.ErrorInvalidUserAuthToken.Code(), tokenErrorCode(t, err))
+
+	repo.getByIDToken = &domain.AuthToken{ID: 6, UID: 1, Status: 1, ExpiredAt: expiry}
+	_, err = svc.GetActiveToken(context.Background(), 1, 6)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/totp"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// totpSkew accepted clock drift in time steps on either side
+	// totpSkew 允许前后偏差的时间步数
+	totpSkew = 1
+	// recoveryCodeCount number of recovery codes issued on enable
+	// recoveryCodeCount 启用时签发的恢复码数量
+	recoveryCodeCount = 10
+	// recoveryCodeLength length of a recovery code without separator
+	// recoveryCodeLength 恢复码长度（不含分隔符）
+	recoveryCodeLength = 10
+)
+
+// TwoFactorService defines the TOTP two-factor authentication service interface
+// TwoFactorService 定义 TOTP 两步验证服务接口
+type TwoFactorService interface {
+	// Status returns the 2FA status of a user
+	// Status 获取用户两步验证状态
+	Status(ctx context.Context, uid int64) (*dto.TwoFactorStatusDTO, error)
+
+	// Setup generates a new pending secret and provisioning URI
+	// Setup 生成新的待确认密钥及配置 URI
+	Setup(ctx context.Context, uid int64) (*dto.TwoFactorSetupDTO, error)
+
+	// Enable confirms the pending secret with a code and issues recovery codes
+	// Enable 使用动态码确认待定密钥并签发恢复码
+	Enable(ctx context.Context, uid int64, code string) (*dto.TwoFactorEnableDTO, error)
+
+	// Disable turns 2FA off after verifying a TOTP or recovery code
+	// Disable 校验动态码或恢复码后关闭两步验证
+	Disable(ctx context.Context, uid int64, code string) error
+
+	// IsEnabled reports whether 2FA is enabled for a user
+	// IsEnabled 判断用户是否已启用两步验证
+	IsEnabled(ctx context.Context, uid int64) (bool, error)
+
+	// IsRequired reports whether 2FA is mandatory for a user
+	// IsRequired 判断用户是否被强制要求启用两步验证
+	IsRequired(uid int64) bool
+
+	// Verify checks a TOTP or recovery code for a user with 2FA enabled; recovery codes are consumed
+	// Verify 校验已启用两步验证用户的动态码或恢复码；恢复码使用后即失效
+	Verify(ctx context.Context, uid int64, code string) error
+}
+
+// twoFactorService implementation of TwoFactorService interface
+// twoFactorService 实现 TwoFactorService 接口
+type twoFactorService struct {
+	totpRepo domain.UserTOTPRepository // TOTP repository // TOTP 仓库
+	userRepo domain.UserRepository     // User repository // 用户仓库
+	logger   *zap.Logger               // Logger // 日志器
+	config   *ServiceConfig            // Service configuration // 服务配置
+	now      func() time.Time          // Clock, replaceable in tests // 时钟，测试中可替换
+}
+
+// NewTwoFactorService creates TwoFactorService instance
+// NewTwoFactorService 创建 TwoFactorService 实例
+func NewTwoFactorService(totpRepo domain.UserTOTPRepository, userRepo domain.UserRepository, logger *zap.Logger, config *ServiceConfig) TwoFactorService {
+	return &twoFactorService{
+		totpRepo: totpRepo,
+		userRepo: userRepo,
+		logger:   logger,
+		config:   config,
+		now:      time.Now,
+	}
+}
+
+// get loads the enrollment of a user, returning nil when none exists
+// get 加载用户的两步验证记录，不存在时返回 nil
+func (s *twoFactorService) get(ctx context.Context, uid int64) (*domain.UserTOTP, error) {
+	t, err := s.totpRepo.GetByUID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	return t, nil
+}
+
+// Status returns the 2FA status of a user
+// Status 获取用户两步验证状态
+func (s *twoFactorService) Status(ctx context.Context, uid int64) (*dto.TwoFactorStatusDTO, error) {
+	t, err := s.get(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	status := &dto.TwoFactorStatusDTO{Required: s.IsRequired(uid)}
+	if t != nil && t.Enabled {
+		status.Enabled = true
+		status.RecoveryCodesRemaining = len(t.RecoveryCodes)
+	}
+	return status, nil
+}
+
+// Setup generates a new pending secret and provisioning URI
+// Setup 生成新的待确认密钥及配置 URI
+func (s *twoFactorService) Setup(ctx context.Context, uid int64) (*dto.TwoFactorSetupDTO, error) {
+	t, err := s.get(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if t != nil && t.Enabled {
+		return nil, code.ErrorUserTOTPAlreadyEnabled
+	}
+
+	user, err := s.userRepo.GetByUID(ctx, uid, true)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, code.ErrorUserNotFound
+		}
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, code.ErrorServerInternal.WithDetails(err.Error())
+	}
+
+	// Overwrite any previous pending secret; it only becomes active after Enable
+	// 覆盖之前未确认的密钥；仅在 Enable 后生效
+	if t == nil {
+		t = &domain.UserTOTP{UID: uid}
+	}
+	t.Secret = secret
+	t.Enabled = false
+	t.RecoveryCodes = nil
+	t.LastCounter = 0
+	if _, err := s.totpRepo.Save(ctx, t); err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	account := user.Email
+	if account == "" {
+		account = user.Username
+	}
+	return &dto.TwoFactorSetupDTO{
+		Secret: secret,
+		URI:    totp.ProvisioningURI(s.config.User.TOTPIssuer, account, secret),
+	}, nil
+}
+
+// Enable confirms the pending secret with a code and issues recovery codes
+// Enable 使用动态码确认待定密钥并签发恢复码
+func (s *twoFactorService) Enable(ctx context.Context, uid int64, totpCode string) (*dto.TwoFactorEnableDTO, error) {
+	t, err := s.get(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil || t.Secret == "" {
+		return nil, code.ErrorUserTOTPNotSetup
+	}
+	if t.Enabled {
+		return nil, code.ErrorUserTOTPAlreadyEnabled
+	}
+
+	counter, ok := totp.Validate(t.Secret, totpCode, s.now(), totpSkew)
+	if !ok {
+		return nil, code.ErrorUserTOTPInvalid
+	}
+
+	plain := make([]string, 0, recoveryCodeCount)
+	hashed := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := strings.ToLower(util.GetRandomString(recoveryCodeLength))
+		plain = append(plain, raw[:recoveryCodeLength/2]+"-"+raw[recoveryCodeLength/2:])
+		hashed = append(hashed, hashRecoveryCode(raw))
+	}
+
+	t.Enabled = true
+	t.RecoveryCodes = hashed
+	t.LastCounter = counter
+	if _, err := s.totpRepo.Save(ctx, t); err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	s.logger.Info("TwoFactorService.Enable", zap.Int64("uid", uid))
+	return &dto.TwoFactorEnableDTO{RecoveryCodes: plain}, nil
+}
+
+// Disable turns 2FA off after verifying a TOTP or recovery code
+// Disable 校验动态码或恢复码后关闭两步验证
+func (s *twoFactorService) Disable(ctx context.Context, uid int64, totpCode string) error {
+	if err := s.Verify(ctx, uid, totpCode); err != nil {
+		return err
+	}
+	if err := s.totpRepo.DeleteByUID(ctx, uid); err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	s.logger.Info("TwoFactorService.Disable", zap.Int64("uid", uid))
+	return nil
+}
+
+// IsEnabled reports whether 2FA is enabled for a user
+// IsEnabled 判断用户是否已启用两步验证
+func (s *twoFactorService) IsEnabled(ctx context.Context, uid int64) (bool, error) {
+	t, err := s.get(ctx, uid)
+	if err != nil {
+		return false, err
+	}
+	return t != nil && t.Enabled, nil
+}
+
+// IsRequired reports whether 2FA is mandatory for a user.
+// Only applies when an explicit admin UID is configured.
+// IsRequired 判断用户是否被强制要求启用两步验证。
+// 仅在配置了明确的管理员 UID 时生效。
+func (s *twoFactorService) IsRequired(uid int64) bool {
+	if s.config == nil || !s.config.User.RequireAdminTOTP {
+		return false
+	}
+	return s.config.User.AdminUID != 0 && uid == int64(s.config.User.AdminUID)
+}
+
+// Verify checks a TOTP or recovery code for a user with 2FA enabled; recovery codes are consumed
+// Verify 校验已启用两步验证用户的动态码或恢复码；恢复码使用后即失效
+func (s *twoFactorService) Verify(ctx context.Context, uid int64, totpCode string) error {
+	t, err := s.get(ctx, uid)
+	if err != nil {
+		return err
+	}
+	if t == nil || !t.Enabled {
+		return code.ErrorUserTOTPNotSetup
+	}
+
+	totpCode = strings.TrimSpace(totpCode)
+	if totpCode == "" {
+		return code.ErrorUserTOTPRequired
+	}
+
+	// TOTP code: reject reuse of an already accepted time step
+	// 动态码：拒绝重复使用已接受过的时间步
+	if counter, ok := totp.Validate(t.Secret, totpCode, s.now(), totpSkew); ok {
+		if counter <= t.LastCounter {
+			return code.ErrorUserTOTPInvalid
+		}
+		t.LastCounter = counter
+		if _, err := s.totpRepo.Save(ctx, t); err != nil {
+			return code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		return nil
+	}
+
+	// Recovery code: single use
+	// 恢复码：一次性使用
+	hash := hashRecoveryCode(totpCode)
+	for i, stored := range t.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			t.RecoveryCodes = append(t.RecoveryCodes[:i], t.RecoveryCodes[i+1:]...)
+			if _, err := s.totpRepo.Save(ctx, t); err != nil {
+				return code.ErrorDBQuery.WithDetails(err.Error())
+			}
+			s.logger.Info("TwoFactorService.Verify recovery code used",
+				zap.Int64("uid", uid),
+				zap.Int("remaining", len(t.RecoveryCodes)),
+			)
+			return nil
+		}
+	}
+
+	return code.ErrorUserTOTPInvalid
+}
+
+// hashRecoveryCode normalizes and hashes a recovery code for storage
+// hashRecoveryCode 规范化并哈希恢复码用于存储
+func hashRecoveryCode(c string) string {
+	c = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(c))
+	sum := sha256.Sum256([]byte(c))
+	return hex.EncodeToString(sum[:])
+}
+
+var _ TwoFactorService = (*twoFactorService)(nil)
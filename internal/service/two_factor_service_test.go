@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeTOTPRepo is an in-memory UserTOTPRepository for tests.
+// fakeTOTPRepo 是用于测试的内存 UserTOTPRepository。
+type fakeTOTPRepo struct {
+	rows map[int64]domain.UserTOTP
+}
+
+func newFakeTOTPRepo() *fakeTOTPRepo {
+	return &fakeTOTPRepo{rows: make(map[int64]domain.UserTOTP)}
+}
+
+func (r *fakeTOTPRepo) GetByUID(ctx context.Context, uid int64) (*domain.UserTOTP, error) {
+	row, ok := r.rows[uid]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	row.RecoveryCodes = append([]string(nil), row.RecoveryCodes...)
+	return &row, nil
+}
+
+func (r *fakeTOTPRepo) Save(ctx context.Context, t *domain.UserTOTP) (*domain.UserTOTP, error) {
+	row := *t
+	row.RecoveryCodes = append([]string(nil), t.RecoveryCodes...)
+	r.rows[t.UID] = row
+	return t, nil
+}
+
+func (r *fakeTOTPRepo) DeleteByUID(ctx context.Context, uid int64) error {
+	delete(r.rows, uid)
+	return nil
+}
+
+// newTwoFactorSvc creates a twoFactorService with a fixed clock.
+// newTwoFactorSvc 创建使用固定时钟的 twoFactorService。
+func newTwoFactorSvc(repo domain.UserTOTPRepository, userRepo domain.UserRepository, now time.Time, cfg UserServiceConfig) *twoFactorService {
+	svc := NewTwoFactorService(repo, userRepo, zap.NewNop(), &ServiceConfig{User: cfg}).(*twoFactorService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+// enrollTOTP runs setup + enable and returns the secret and recovery codes.
+// enrollTOTP 执行 setup + enable，返回密钥与恢复码。
+func enrollTOTP(t *testing.T, svc *twoFactorService, uid int64, now time.Time) (string, []string) {
+	t.Helper()
+	setup, err := svc.Setup(context.Background(), uid)
+	assert.NoError(t, err)
+	c, _ := totp.GenerateCode(setup.Secret, totp.Counter(now)-1)
+	res, err := svc.Enable(context.Background(), uid, c)
+	assert.NoError(t, err)
+	return setup.Secret, res.RecoveryCodes
+}
+
+// TestTwoFactorService_EnableAndVerify verifies enrollment, replay rejection and recovery code consumption.
+// TestTwoFactorService_EnableAndVerify 验证启用流程、重放拒绝及恢复码消耗。
+func TestTwoFactorService_EnableAndVerify(t *testing.T) {
+	userRepo := new(domainmocks.MockUserRepository)
+	userRepo.On("GetByUID", mock.Anything, int64(1)).
+		Return(&domain.User{UID: 1, Email: "a@example.com"}, nil)
+
+	now := time.Unix(1700000000, 0)
+	repo := newFakeTOTPRepo()
+	svc := newTwoFactorSvc(repo, userRepo, now, UserServiceConfig{TOTPIssuer: "FNS"})
+	ctx := context.Background()
+
+	secret, recovery := enrollTOTP(t, svc, 1, now)
+	assert.Len(t, recovery, recoveryCodeCount)
+	for _, stored := range repo.rows[1].RecoveryCodes {
+		assert.NotContains(t, recovery, stored, "recovery codes must be stored hashed")
+	}
+
+	// Code already used for enabling must not be accepted again
+	// 已用于启用的动态码不能再次使用
+	used, _ := totp.GenerateCode(secret, totp.Counter(now)-1)
+	assert.Equal(t, code.ErrorUserTOTPInvalid, svc.Verify(ctx, 1, used))
+
+	current, _ := totp.GenerateCode(secret, totp.Counter(now))
+	assert.NoError(t, svc.Verify(ctx, 1, current))
+	assert.Equal(t, code.ErrorUserTOTPInvalid, svc.Verify(ctx, 1, current))
+
+	// Recovery codes are single use
+	// 恢复码仅可使用一次
+	assert.NoError(t, svc.Verify(ctx, 1, recovery[0]))
+	assert.Equal(t, code.ErrorUserTOTPInvalid, svc.Verify(ctx, 1, recovery[0]))
+
+	status, err := svc.Status(ctx, 1)
+	assert.NoError(t, err)
+	assert.True(t, status.Enabled)
+	assert.Equal(t, recoveryCodeCount-1, status.RecoveryCodesRemaining)
+
+	assert.Equal(t, code.ErrorUserTOTPRequired, svc.Verify(ctx, 1, ""))
+	_, err = svc.Setup(ctx, 1)
+	assert.Equal(t, code.ErrorUserTOTPAlreadyEnabled, err)
+}
+
+// TestTwoFactorService_Disable verifies disabling requires a valid code.
+// TestTwoFactorService_Disable 验证关闭需要有效验证码。
+func TestTwoFactorService_Disable(t *testing.T) {
+	userRepo := new(domainmocks.MockUserRepository)
+	userRepo.On("GetByUID", mock.Anything, int64(1)).
+		Return(&domain.User{UID: 1, Username: "alice"}, nil)
+
+	now := time.Unix(1700000000, 0)
+	svc := newTwoFactorSvc(newFakeTOTPRepo(), userRepo, now, UserServiceConfig{})
+	ctx := context.Background()
+
+	assert.Equal(t, code.ErrorUserTOTPNotSetup, svc.Disable(ctx, 1, "123456"))
+
+	_, recovery := enrollTOTP(t, svc, 1, now)
+	assert.Equal(t, code.ErrorUserTOTPInvalid, svc.Disable(ctx, 1, "000000-bad"))
+	assert.NoError(t, svc.Disable(ctx, 1, recovery[1]))
+
+	enabled, err := svc.IsEnabled(ctx, 1)
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+}
+
+// TestTwoFactorService_IsRequired verifies the admin requirement only applies to the configured admin UID.
+// TestTwoFactorService_IsRequired 验证管理员强制要求仅作用于配置的管理员 UID。
+func TestTwoFactorService_IsRequired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	svc := newTwoFactorSvc(newFakeTOTPRepo(), nil, now, UserServiceConfig{AdminUID: 1, RequireAdminTOTP: true})
+	assert.True(t, svc.IsRequired(1))
+	assert.False(t, svc.IsRequired(2))
+
+	svc = newTwoFactorSvc(newFakeTOTPRepo(), nil, now, UserServiceConfig{AdminUID: 0, RequireAdminTOTP: true})
+	assert.False(t, svc.IsRequired(1))
+}
+
+// TestUserService_Login_TwoFactor verifies login enforces the second factor before issuing a token.
+// TestUserService_Login_TwoFactor 验证登录在签发 Token 前校验第二因素。
+func TestUserService_Login_TwoFactor(t *testing.T) {
+	user := &domain.User{
+		UID:      1,
+		Email:    "test@example.com",
+		Password: "$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi", // "password"
+	}
+	userRepo := new(domainmocks.MockUserRepository)
+	userRepo.On("GetByEmail", mock.Anything, "test@example.com").Return(user, nil)
+	userRepo.On("GetByUID", mock.Anything, int64(1)).Return(user, nil)
+
+	now := time.Unix(1700000000, 0)
+	cfg := &ServiceConfig{User: UserServiceConfig{AdminUID: 1, RequireAdminTOTP: true}}
+	twoFactor := newTwoFactorSvc(newFakeTOTPRepo(), userRepo, now, cfg.User)
+	svc := NewUserService(userRepo, &mockTokenManager{}, &mockUserTokenService{}, twoFactor, nil, zap.NewNop(), cfg)
+	ctx := context.Background()
+	req := func(c string) *dto.UserLoginRequest {
+		return &dto.UserLoginRequest{Credentials: "test@example.com", Password: "password", TOTPCode: c}
+	}
+
+	// Not enrolled yet: admin can log in but only gets a setup token, also when rotating a login token
+	// 尚未启用：管理员可登录，但只获得设置令牌，轮转登录令牌时同样如此
+	result, err := svc.Login(ctx, req(""), "127.0.0.1", "WebGui", "test-agent")
+	assert.NoError(t, err)
+	assert.True(t, result.TOTPSetupRequired)
+	assert.Equal(t, "totp-setup-token", result.Token)
+
+	rotate := req("")
+	rotate.TokenID = 5
+	result, err = svc.Login(ctx, rotate, "127.0.0.1", "WebGui", "test-agent")
+	assert.NoError(t, err)
+	assert.Equal(t, "totp-setup-token", result.Token)
+
+	secret, _ := enrollTOTP(t, twoFactor, 1, now)
+
+	_, err = svc.Login(ctx, req(""), "127.0.0.1", "WebGui", "test-agent")
+	assert.Equal(t, code.ErrorUserTOTPRequired, err)
+
+	_, err = svc.Login(ctx, req("000000"), "127.0.0.1", "WebGui", "test-agent")
+	assert.Equal(t, code.ErrorUserTOTPInvalid, err)
+
+	c, _ := totp.GenerateCode(secret, totp.Counter(now))
+	result, err = svc.Login(ctx, req(c), "127.0.0.1", "WebGui", "test-agent")
+	assert.NoError(t, err)
+	assert.Equal(t, "test-token", result.Token)
+	assert.False(t, result.TOTPSetupRequired)
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap"
+)
+
+// UsageService defines the disk usage business service interface
+// UsageService 定义磁盘用量业务服务接口
+type UsageService interface {
+	// User returns the disk usage of a user with the content size of each vault
+	// User 返回用户的磁盘用量及各仓库的内容大小
+	User(ctx context.Context, uid int64) (*dto.UserUsageDTO, error)
+
+	// All returns the disk usage of every user, largest first
+	// All 返回所有用户的磁盘用量，按大小倒序
+	All(ctx context.Context) (*dto.StorageUsageDTO, error)
+}
+
+// usageService implements UsageService
+// usageService 实现 UsageService 接口
+type usageService struct {
+	usageRepo domain.UsageRepository
+	userRepo  domain.UserRepository
+	vaultRepo domain.VaultRepository
+	tempPath  string
+	logger    *zap.Logger
+}
+
+// NewUsageService creates a UsageService instance
+// NewUsageService 创建 UsageService 实例
+func NewUsageService(usageRepo domain.UsageRepository, userRepo domain.UserRepository, vaultRepo domain.VaultRepository, tempPath string, logger *zap.Logger) UsageService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &usageService{
+		usageRepo: usageRepo,
+		userRepo:  userRepo,
+		vaultRepo: vaultRepo,
+		tempPath:  tempPath,
+		logger:    logger,
+	}
+}
+
+// User implements UsageService
+// User 实现 UsageService 接口
+func (s *usageService) User(ctx context.Context, uid int64) (*dto.UserUsageDTO, error) {
+	return s.user(ctx, uid, s.backupTempUsage(), false)
+}
+
+// All implements UsageService.
+// A user whose usage cannot be read is reported with what could be read, so one broken user
+// does not hide the usage of the whole instance.
+// 无法读取用量的用户只报告已读取到的部分，避免单个用户出错导致整个实例的用量不可用。
+func (s *usageService) All(ctx context.Context) (*dto.StorageUsageDTO, error) {
+	uids, err := s.userRepo.GetAllUIDs(ctx)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	staging := s.backupTempUsage()
+	result := &dto.StorageUsageDTO{
+		Users:       make([]*dto.UserUsageDTO, 0, len(uids)),
+		GeneratedAt: timex.Time(time.Now()),
+	}
+	for _, uid := range uids {
+		user, _ := s.user(ctx, uid, staging, true)
+		if u, err := s.userRepo.GetByUID(ctx, uid, false); err == nil {
+			user.Username = u.Username
+		}
+		result.Totals.Notes += user.Usage.Notes
+		result.Totals.Attachments += user.Usage.Attachments
+		result.Totals.History += user.Usage.History
+		result.Totals.Settings += user.Usage.Settings
+		result.Totals.BackupTemp += user.Usage.BackupTemp
+		result.Totals.Database += user.Usage.Database
+		result.Totals.Total += user.Usage.Total
+		result.Users = append(result.Users, user)
+	}
+
+	sort.SliceStable(result.Users, func(i, j int) bool {
+		return result.Users[i].Usage.Total > result.Users[j].Usage.Total
+	})
+	return result, nil
+}
+
+// user collects the usage of a user; with lenient set read failures are logged and skipped
+// user 收集用户的用量；lenient 为 true 时读取失败只记录日志并跳过
+func (s *usageService) user(ctx context.Context, uid int64, staging map[int64]int64, lenient bool) (*dto.UserUsageDTO, error) {
+	res := &dto.UserUsageDTO{UID: uid, Vaults: []*dto.VaultUsageDTO{}}
+
+	content, err := s.usageRepo.ContentUsage(ctx, uid)
+	if err != nil {
+		if !lenient {
+			return nil, err
+		}
+		s.warn("content", uid, err)
+		content = &domain.ContentUsage{}
+	}
+	dbSize, _, err := s.usageRepo.DatabaseSize(ctx, uid)
+	if err != nil {
+		if !lenient {
+			return nil, err
+		}
+		s.warn("database", uid, err)
+	}
+
+	res.Usage = dto.UsageBytesDTO{
+		Notes:       content.Notes,
+		Attachments: content.Files,
+		History:     content.History,
+		Settings:    content.Settings,
+		BackupTemp:  staging[uid],
+		Database:    dbSize,
+	}
+	res.Usage.Total = res.Usage.Notes + res.Usage.Attachments + res.Usage.History + res.Usage.Settings + res.Usage.BackupTemp + res.Usage.Database
+
+	vaults, err := s.vaultRepo.List(ctx, uid)
+	if err != nil {
+		if !lenient {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		s.warn("vaults", uid, err)
+	}
+	for _, v := range vaults {
+		res.Vaults = append(res.Vaults, &dto.VaultUsageDTO{
+			VaultID:   v.ID,
+			Vault:     v.Name,
+			NoteCount: v.NoteCount,
+			NoteSize:  v.NoteSize,
+			FileCount: v.FileCount,
+			FileSize:  v.FileSize,
+			Size:      v.NoteSize + v.FileSize,
+		})
+	}
+	return res, nil
+}
+
+// backupTempUsage returns the bytes of the backup staging area by user. The area only holds
+// in-progress backups and cached exports and is wiped on startup, so it is small enough to walk.
+// backupTempUsage 返回备份暂存区按用户统计的字节数。暂存区只包含进行中的备份与缓存的导出，
+// 且启动时会被清空，数据量小，可直接遍历。
+func (s *usageService) backupTempUsage() map[int64]int64 {
+	usage := make(map[int64]int64)
+	root := backupStagingRoot(s.tempPath)
+	for _, dir := range []string{root, filepath.Join(root, "export")} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			uid, ok := backupStagingOwner(e.Name())
+			if !ok {
+				continue
+			}
+			_ = filepath.WalkDir(filepath.Join(dir, e.Name()), func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return nil
+				}
+				if info, err := d.Info(); err == nil {
+					usage[uid] += info.Size()
+				}
+				return nil
+			})
+		}
+	}
+	return usage
+}
+
+// warn logs a usage item of a user that could not be read
+// warn 记录无法读取的用户用量项
+func (s *usageService) warn(item string, uid int64, err error) {
+	s.logger.Warn("storage usage: read failed", zap.String("item", item), zap.Int64("uid", uid), zap.Error(err))
+}
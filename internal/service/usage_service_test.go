@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeUsageRepo fixed domain.UsageRepository keyed by UID
+type fakeUsageRepo struct {
+	content map[int64]*domain.ContentUsage
+}
+
+func (r *fakeUsageRepo) ContentUsage(ctx context.Context, uid int64) (*domain.ContentUsage, error) {
+	if c, ok := r.content[uid]; ok {
+		return c, nil
+	}
+	return &domain.ContentUsage{}, nil
+}
+
+func (r *fakeUsageRepo) DatabaseSize(ctx context.Context, uid int64) (int64, bool, error) {
+	return 100, true, nil
+}
+
+// TestBackupStagingOwner verifies working dirs, archives and export artifacts are attributed to their user.
+// TestBackupStagingOwner 验证工作目录、压缩包与导出产物归属到对应用户。
+func TestBackupStagingOwner(t *testing.T) {
+	for name, want := range map[string]int64{
+		"backup_7_123456": 7,
+		"backup_full_7_My_Vault_20260101_120000.zip": 7,
+		"export_7_abcd.zip":                          7,
+	} {
+		uid, ok := backupStagingOwner(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, want, uid, name)
+	}
+	for _, name := range []string{"verify_3_123", "export", "backup_full"} {
+		_, ok := backupStagingOwner(name)
+		assert.False(t, ok, name)
+	}
+}
+
+// TestUsageService_All verifies the usage of every user is summed with its backup temp files
+// and ordered largest first.
+// TestUsageService_All 验证每个用户的用量与其备份临时文件合计，并按大小倒序排列。
+func TestUsageService_All(t *testing.T) {
+	tempPath := t.TempDir()
+	staging := backupStagingRoot(tempPath)
+	require.NoError(t, os.MkdirAll(filepath.Join(staging, "backup_2_1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(staging, "backup_2_1", "a.md"), []byte("12345"), 0644))
+
+	userRepo := new(domainmocks.MockUserRepository)
+	userRepo.On("GetAllUIDs", mock.Anything).Return([]int64{1, 2}, nil)
+	userRepo.On("GetByUID", mock.Anything, mock.Anything).Return(&domain.User{Username: "u"}, nil)
+	vaultRepo := newVaultMockRepo()
+	vaultRepo.On("List", mock.Anything, int64(1)).Return([]*domain.Vault{newVault(1, "Work")}, nil)
+	vaultRepo.On("List", mock.Anything, int64(2)).Return([]*domain.Vault{}, nil)
+	usageRepo := &fakeUsageRepo{content: map[int64]*domain.ContentUsage{1: {Notes: 10}, 2: {Notes: 10, Files: 20}}}
+
+	svc := NewUsageService(usageRepo, userRepo, vaultRepo, tempPath, zap.NewNop())
+	usage, err := svc.All(context.Background())
+	require.NoError(t, err)
+	require.Len(t, usage.Users, 2)
+	assert.Equal(t, int64(2), usage.Users[0].UID)
+	assert.Equal(t, int64(5), usage.Users[0].Usage.BackupTemp)
+	assert.Equal(t, int64(135), usage.Users[0].Usage.Total)
+	assert.Equal(t, int64(110), usage.Users[1].Usage.Total)
+	assert.Len(t, usage.Users[1].Vaults, 1)
+	assert.Equal(t, int64(245), usage.Totals.Total)
+	assert.Equal(t, int64(200), usage.Totals.Database)
+}
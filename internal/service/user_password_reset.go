@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// passwordResetPath page of the server the reset links open
+// passwordResetPath 重置链接打开的服务器页面
+const passwordResetPath = "/api/user/password/reset"
+
+// passwordResetCooldown minimum interval between two reset emails to the same user
+// passwordResetCooldown 向同一用户发送两封重置邮件的最小间隔
+const passwordResetCooldown = time.Minute
+
+// defaultPasswordResetExpiry validity of a reset link when none is configured
+// defaultPasswordResetExpiry 未配置时重置链接的有效期
+const defaultPasswordResetExpiry = 30 * time.Minute
+
+// Mailer sends HTML emails, satisfied by alert.SMTPConfig
+// Mailer 发送 HTML 邮件，alert.SMTPConfig 满足该接口
+type Mailer interface {
+	SendMail(to []string, subject, body string) error
+}
+
+// SetMailer sets the mailer of the reset emails, nil disables resetting by email
+// SetMailer 设置发送重置邮件的邮件发送器，为 nil 时禁用邮件重置
+func (s *userService) SetMailer(mailer Mailer) {
+	s.mailer = mailer
+}
+
+// passwordResetEnabled reports whether reset links can be signed and delivered
+// passwordResetEnabled 判断是否能签发并投递重置链接
+func (s *userService) passwordResetEnabled() bool {
+	return s.mailer != nil && s.tokenManager != nil && s.config != nil && s.config.User.PasswordResetURL != ""
+}
+
+func (s *userService) passwordResetExpiry() time.Duration {
+	if d, err := util.ParseDuration(s.config.User.PasswordResetExpiry); err == nil && d > 0 {
+		return d
+	}
+	return defaultPasswordResetExpiry
+}
+
+// signPasswordReset signs the reset token of a user. The signature covers the current password hash,
+// so the token stops working as soon as the password changes and no server-side state is needed
+// signPasswordReset 签发用户的重置令牌。签名覆盖当前密码哈希，密码一旦修改令牌即失效，无需服务端保存状态
+func (s *userService) signPasswordReset(user *domain.User, expiry time.Time) string {
+	payload := strconv.FormatInt(user.UID, 10) + "." + strconv.FormatInt(expiry.Unix(), 10)
+	return payload + "." + s.passwordResetMAC(payload, user.Password)
+}
+
+func (s *userService) passwordResetMAC(payload, passwordHash string) string {
+	mac := hmac.New(sha256.New, []byte("password-reset_"+s.tokenManager.GetSecretKey()))
+	mac.Write([]byte(payload + "." + passwordHash))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// RequestPasswordReset emails a reset link to the account of the address. Unknown addresses succeed
+// silently so the endpoint cannot be used to find registered emails
+// RequestPasswordReset 向该地址的账号发送重置链接。未知地址同样静默成功，避免借此探测已注册邮箱
+func (s *userService) RequestPasswordReset(ctx context.Context, email string) error {
+	if !s.passwordResetEnabled() {
+		return code.ErrorPasswordResetDisabled
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(email)))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if user.IsDeleted || !user.HasEmail() {
+		return nil
+	}
+
+	// Limit how often anyone can fill the mailbox of a user
+	// 限制任何人向同一用户邮箱发信的频率
+	now := time.Now()
+	if last, ok := s.resetSent.Load(user.UID); ok && now.Sub(last.(time.Time)) < passwordResetCooldown {
+		return nil
+	}
+	s.resetSent.Store(user.UID, now)
+
+	token := s.signPasswordReset(user, now.Add(s.passwordResetExpiry()))
+	link := strings.TrimSuffix(s.config.User.PasswordResetURL, "/") + passwordResetPath + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf(`<p>Hello %s,</p>
+<p>A password reset was requested for your Fast Note Sync account. Open the link below to choose a new password, it is valid for %s:</p>
+<p><a href="%s">%s</a></p>
+<p>If you did not request it, ignore this email and your password stays unchanged.</p>
+<hr>
+<p>%s 您好，</p>
+<p>您的 Fast Note Sync 账号申请了重置密码。请在 %s 内打开上方链接设置新密码；如非本人操作，请忽略此邮件，密码不会改变。</p>`,
+		html.EscapeString(user.Username), s.passwordResetExpiry(), html.EscapeString(link), html.EscapeString(link),
+		html.EscapeString(user.Username), s.passwordResetExpiry())
+
+	// Send in the background so the response time does not reveal whether the address is registered
+	// 后台发送，避免响应耗时暴露该地址是否已注册
+	to := user.Email
+	safego.Go(s.logger, func() {
+		if err := s.mailer.SendMail([]string{to}, "Fast Note Sync password reset / 重置密码", body); err != nil && s.logger != nil {
+			s.logger.Warn("UserService.RequestPasswordReset send mail failed",
+				zap.Int64("uid", user.UID),
+				zap.Error(err),
+			)
+		}
+	})
+	return nil
+}
+
+// ResetPassword sets a new password with a reset token and signs the user out everywhere
+// ResetPassword 使用重置令牌设置新密码，并使该用户在所有设备上退出登录
+func (s *userService) ResetPassword(ctx context.Context, params *dto.UserResetPasswordRequest) error {
+	if !s.passwordResetEnabled() {
+		return code.ErrorPasswordResetDisabled
+	}
+	if params.Password != params.ConfirmPassword {
+		return code.ErrorUserPasswordNotMatch
+	}
+
+	parts := strings.Split(params.Token, ".")
+	if len(parts) != 3 {
+		return code.ErrorPasswordResetInvalid
+	}
+	uid, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return code.ErrorPasswordResetInvalid
+	}
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expiry {
+		return code.ErrorPasswordResetInvalid
+	}
+
+	user, err := s.userRepo.GetByUID(ctx, uid, true)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return code.ErrorPasswordResetInvalid
+		}
+		return code.ErrorDBQuery
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(s.passwordResetMAC(parts[0]+"."+parts[1], user.Password))) {
+		return code.ErrorPasswordResetInvalid
+	}
+
+	password, err := util.GeneratePasswordHash(params.Password)
+	if err != nil {
+		return code.ErrorPasswordNotValid
+	}
+	if err := s.userRepo.UpdatePassword(ctx, password, uid); err != nil {
+		return code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	// Whoever knew the old password must not stay signed in
+	// 知道旧密码的人不能继续保持登录
+	if s.tokenService != nil {
+		if err := s.tokenService.RevokeAll(ctx, uid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
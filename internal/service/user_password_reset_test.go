@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// chanMailer hands the sent bodies to the test
+type chanMailer struct {
+	to   chan []string
+	body chan string
+}
+
+func (m *chanMailer) SendMail(to []string, subject, body string) error {
+	m.to <- to
+	m.body <- body
+	return nil
+}
+
+func newResetSvc(repo domain.UserRepository) (UserService, *chanMailer) {
+	svc := NewUserService(repo, &mockTokenManager{}, &mockUserTokenService{}, nil, nil, zap.NewNop(), &ServiceConfig{
+		User: UserServiceConfig{PasswordResetURL: "https://notes.example.com/"},
+	})
+	mailer := &chanMailer{to: make(chan []string, 1), body: make(chan string, 1)}
+	svc.SetMailer(mailer)
+	return svc, mailer
+}
+
+var resetTokenPattern = regexp.MustCompile(`https://notes\.example\.com/api/user/password/reset\?token=([^"&]+)`)
+
+// TestUserService_PasswordReset verifies the emailed link resets the password once, and that
+// unknown addresses succeed without sending anything.
+// TestUserService_PasswordReset 验证邮件中的链接只能重置一次密码，未知地址成功返回且不发送邮件。
+func TestUserService_PasswordReset(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(domainmocks.MockUserRepository)
+	user := &domain.User{UID: 1, Email: "alice@example.com", Username: "alice", Password: "old-hash"}
+	mockRepo.On("GetByEmail", mock.Anything, "alice@example.com").Return(user, nil)
+	mockRepo.On("GetByEmail", mock.Anything, "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("GetByUID", mock.Anything, int64(1)).Return(user, nil)
+	mockRepo.On("UpdatePassword", mock.Anything, mock.AnythingOfType("string"), int64(1)).
+		Run(func(args mock.Arguments) { user.Password = args.String(1) }).
+		Return(nil)
+
+	svc, mailer := newResetSvc(mockRepo)
+	require.NoError(t, svc.RequestPasswordReset(ctx, "nobody@example.com"))
+	require.NoError(t, svc.RequestPasswordReset(ctx, " Alice@Example.com "))
+
+	var body string
+	select {
+	case to := <-mailer.to:
+		assert.Equal(t, []string{"alice@example.com"}, to)
+		body = <-mailer.body
+	case <-time.After(5 * time.Second):
+		t.Fatal("reset email was not sent")
+	}
+	match := resetTokenPattern.FindStringSubmatch(body)
+	require.Len(t, match, 2)
+	token := match[1]
+
+	// A second request within the cooldown sends nothing
+	// 冷却时间内的第二次请求不发送邮件
+	require.NoError(t, svc.RequestPasswordReset(ctx, "alice@example.com"))
+	assert.Empty(t, mailer.to)
+
+	err := svc.ResetPassword(ctx, &dto.UserResetPasswordRequest{Token: token, Password: "new-pass", ConfirmPassword: "other"})
+	assert.ErrorIs(t, err, code.ErrorUserPasswordNotMatch)
+
+	require.NoError(t, svc.ResetPassword(ctx, &dto.UserResetPasswordRequest{Token: token, Password: "new-pass", ConfirmPassword: "new-pass"}))
+	assert.NotEqual(t, "old-hash", user.Password)
+
+	// The link no longer works once the password changed
+	// 密码修改后链接失效
+	err = svc.ResetPassword(ctx, &dto.UserResetPasswordRequest{Token: token, Password: "again", ConfirmPassword: "again"})
+	assert.ErrorIs(t, err, code.ErrorPasswordResetInvalid)
+}
+
+// TestUserService_PasswordReset_InvalidToken verifies tampered, expired and malformed tokens are refused.
+// TestUserService_PasswordReset_InvalidToken 验证被篡改、已过期与格式错误的令牌会被拒绝。
+func TestUserService_PasswordReset_InvalidToken(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(domainmocks.MockUserRepository)
+	user := &domain.User{UID: 1, Email: "alice@example.com", Password: "hash"}
+	mockRepo.On("GetByUID", mock.Anything, int64(1)).Return(user, nil)
+	mockRepo.On("GetByUID", mock.Anything, int64(2)).Return(nil, gorm.ErrRecordNotFound)
+
+	svc, _ := newResetSvc(mockRepo)
+	us := svc.(*userService)
+	valid := us.signPasswordReset(user, time.Now().Add(time.Hour))
+	expired := us.signPasswordReset(user, time.Now().Add(-time.Second))
+	other := us.signPasswordReset(&domain.User{UID: 2, Password: "hash"}, time.Now().Add(time.Hour))
+
+	for _, token := range []string{"", "1.2", "x.y.z", valid + "x", "2" + valid[1:], expired, other} {
+		err := svc.ResetPassword(ctx, &dto.UserResetPasswordRequest{Token: token, Password: "p", ConfirmPassword: "p"})
+		assert.ErrorIs(t, err, code.ErrorPasswordResetInvalid, token)
+	}
+}
+
+// TestUserService_PasswordReset_Disabled verifies resetting by email is refused without a mailer.
+// TestUserService_PasswordReset_Disabled 验证未配置邮件发送器时拒绝邮件重置。
+func TestUserService_PasswordReset_Disabled(t *testing.T) {
+	mockRepo := new(domainmocks.MockUserRepository)
+	svc := newUserSvc(mockRepo, true)
+
+	assert.ErrorIs(t, svc.RequestPasswordReset(context.Background(), "alice@example.com"), code.ErrorPasswordResetDisabled)
+	err := svc.ResetPassword(context.Background(), &dto.UserResetPasswordRequest{Token: "1.2.3", Password: "p", ConfirmPassword: "p"})
+	assert.ErrorIs(t, err, code.ErrorPasswordResetDisabled)
+	mockRepo.AssertExpectations(t)
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"sync"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/domain"
 	"github.com/haierkeys/fast-note-sync-service/internal/dto"
@@ -20,8 +21,8 @@ import (
 // UserService defines the user business service interface
 // UserService 定义用户业务服务接口
 type UserService interface {
-	// Register user registration
-	// Register 用户注册
+	// Register user registration, returns a nil user when the registration waits for admin approval
+	// Register 用户注册，注册需等待管理员审核时返回 nil 用户
 	Register(ctx context.Context, params *dto.UserCreateRequest, clientIP string, clientType string, userAgent string) (*dto.UserDTO, error)
 
 	// Create user
@@ -38,6 +39,22 @@ type UserService interface {
 	// ChangePassword 修改密码
 	ChangePassword(ctx context.Context, uid int64, params *dto.UserChangePasswordRequest) error
 
+	// RequestPasswordReset emails a reset link to the account of the address
+	// RequestPasswordReset 向该地址的账号发送密码重置链接
+	RequestPasswordReset(ctx context.Context, email string) error
+
+	// ResetPassword sets a new password with a reset token
+	// ResetPassword 使用重置令牌设置新密码
+	ResetPassword(ctx context.Context, params *dto.UserResetPasswordRequest) error
+
+	// SetMailer sets the mailer of the reset emails
+	// SetMailer 设置发送重置邮件的邮件发送器
+	SetMailer(mailer Mailer)
+
+	// SetRegistration sets the invitation and approval service used by Register
+	// SetRegistration 设置 Register 使用的邀请码与审核服务
+	SetRegistration(registration RegistrationService)
+
 	// GetInfo retrieves user information
 	// GetInfo 获取用户信息
 	GetInfo(ctx context.Context, uid int64) (*dto.UserDTO, error)
@@ -60,22 +77,35 @@ type userService struct {
 	userRepo     domain.UserRepository // User repository // 用户仓库
 	tokenManager app.TokenManager      // Token manager // Token 管理器
 	tokenService TokenService          // Token service // Token 服务
+	twoFactor    TwoFactorService      // Two-factor service, may be nil // 两步验证服务，可为 nil
+	notifier     Notifier              // Webhook notifier, may be nil // Webhook 通知器，可为 nil
 	logger       *zap.Logger           // Logger // 日志器
 	config       *ServiceConfig        // Service configuration // 服务配置
+	mailer       Mailer                // Reset email sender, may be nil // 重置邮件发送器，可为 nil
+	registration RegistrationService   // Invitations and approval queue, may be nil // 邀请码与审核队列，可为 nil
+	resetSent    sync.Map              // uid -> time of the last reset email // uid -> 最近一次发送重置邮件的时间
 }
 
 // NewUserService creates UserService instance
 // NewUserService 创建 UserService 实例
-func NewUserService(userRepo domain.UserRepository, tokenManager app.TokenManager, tokenService TokenService, logger *zap.Logger, config *ServiceConfig) UserService {
+func NewUserService(userRepo domain.UserRepository, tokenManager app.TokenManager, tokenService TokenService, twoFactor TwoFactorService, notifier Notifier, logger *zap.Logger, config *ServiceConfig) UserService {
 	return &userService{
 		userRepo:     userRepo,
 		tokenManager: tokenManager,
 		tokenService: tokenService,
+		twoFactor:    twoFactor,
+		notifier:     notifier,
 		logger:       logger,
 		config:       config,
 	}
 }
 
+// SetRegistration sets the invitation and approval service used by Register
+// SetRegistration 设置 Register 使用的邀请码与审核服务
+func (s *userService) SetRegistration(registration RegistrationService) {
+	s.registration = registration
+}
+
 // domainToDTO converts domain model to DTO
 // domainToDTO 将领域模型转换为 DTO
 func (s *userService) domainToDTO(user *domain.User) *dto.UserDTO {
@@ -99,7 +129,14 @@ func (s *userService) domainToDTO(user *domain.User) *dto.UserDTO {
 func (s *userService) Register(ctx context.Context, params *dto.UserCreateRequest, clientIP string, clientType string, userAgent string) (*dto.UserDTO, error) {
 	// Check if registration is enabled
 	// 检查注册是否启用
-	if !s.IsRegisterEnabled(ctx) {
+	// An invitation code admits the registration even while open registration is off
+	// 持有邀请码时，即使开放注册关闭也允许注册
+	invited := strings.TrimSpace(params.InviteCode) != ""
+	if invited {
+		if s.registration == nil {
+			return nil, code.ErrorInviteCodeInvalid
+		}
+	} else if !s.IsRegisterEnabled(ctx) {
 		return nil, code.ErrorUserRegisterIsDisable
 	}
 
@@ -136,6 +173,18 @@ func (s *userService) Register(ctx context.Context, params *dto.UserCreateReques
 		return nil, code.ErrorUserAlreadyExists
 	}
 
+	// Names held by registrations waiting for approval are taken as well
+	// 等待审核的注册占用的名称同样不可用
+	if s.registration != nil {
+		pending, err := s.registration.IsPending(ctx, params.Email, params.Username)
+		if err != nil {
+			return nil, err
+		}
+		if pending {
+			return nil, code.ErrorUserAlreadyExists
+		}
+	}
+
 	// Generate password hash
 	// 生成密码哈希
 	password, err := util.GeneratePasswordHash(params.Password)
@@ -143,6 +192,24 @@ func (s *userService) Register(ctx context.Context, params *dto.UserCreateReques
 		return nil, code.ErrorPasswordNotValid
 	}
 
+	if invited {
+		if err := s.registration.UseInvite(ctx, params.InviteCode); err != nil {
+			return nil, err
+		}
+	} else if s.registration != nil && s.config.User.RegisterApproval {
+		// Invited users were vetted by the admin already, everyone else waits for approval
+		// 受邀用户已由管理员把关，其他人需等待审核
+		if err := s.registration.Enqueue(ctx, &domain.PendingUser{
+			Email:    params.Email,
+			Username: params.Username,
+			Password: password,
+			IP:       clientIP,
+		}); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
 	// Create user
 	// 创建用户
 	newUser := &domain.User{
@@ -153,11 +220,24 @@ func (s *userService) Register(ctx context.Context, params *dto.UserCreateReques
 
 	user, err := s.userRepo.Create(ctx, newUser)
 	if err != nil {
+		// The invitation was counted before the insert, give the use back so the code is not burnt
+		// 邀请码在插入前已计数，退还该次使用以免邀请码被白白消耗
+		if invited {
+			if releaseErr := s.registration.ReleaseInvite(context.WithoutCancel(ctx), params.InviteCode); releaseErr != nil {
+				s.logger.Warn("UserService.Register: failed to release invitation code", zap.Error(releaseErr))
+			}
+		}
 		return nil, code.ErrorUserRegister.WithDetails(err.Error())
 	}
 
-	// Generate Token with proper IP and UA binding
-	token, tokenStr, err := s.tokenService.CreateForLogin(ctx, user.UID, clientType, clientIP, userAgent)
+	// Generate Token with proper IP and UA binding, an admin who must enroll TOTP only gets a setup token
+	// 生成绑定 IP 与 UA 的 Token；需要设置 TOTP 的管理员只获得设置令牌
+	issue := s.tokenService.CreateForLogin
+	totpSetupRequired := s.twoFactor != nil && s.twoFactor.IsRequired(user.UID)
+	if totpSetupRequired {
+		issue = s.tokenService.CreateForTOTPSetup
+	}
+	token, tokenStr, err := issue(ctx, user.UID, clientType, clientIP, userAgent)
 	if err != nil {
 		return nil, code.ErrorTokenGenerate.WithDetails(err.Error())
 	}
@@ -165,6 +245,7 @@ func (s *userService) Register(ctx context.Context, params *dto.UserCreateReques
 	dto := s.domainToDTO(user)
 	dto.Token = tokenStr
 	dto.TokenID = token.ID
+	dto.TOTPSetupRequired = totpSetupRequired
 	return dto, nil
 }
 
@@ -295,6 +376,14 @@ func (s *userService) Update(ctx context.Context, params *dto.UserUpdateRequest)
 		return code.ErrorUserUpdate.WithDetails(err.Error())
 	}
 
+	// A disabled user is signed out everywhere at once, not when the tokens expire
+	// 用户被禁用时立即在所有设备上退出登录，而不是等到令牌过期
+	if params.IsDeleted && !currentUser.IsDeleted && s.tokenService != nil {
+		if err := s.tokenService.RevokeAll(ctx, params.UID); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -322,16 +411,39 @@ func (s *userService) Login(ctx context.Context, params *dto.UserLoginRequest, c
 	// Validate password
 	// 验证密码
 	if !util.CheckPasswordHash(user.Password, params.Password) {
+		s.notifyLogin(user.UID, domain.WebhookEventUserLoginFailed, user.Username, clientIP, clientType, userAgent)
 		return nil, code.ErrorUserLoginPasswordFailed
 	}
 
+	// Verify second factor before issuing a token
+	// 签发 Token 前校验第二因素
+	totpSetupRequired := false
+	if s.twoFactor != nil {
+		enabled, err := s.twoFactor.IsEnabled(ctx, user.UID)
+		if err != nil {
+			return nil, err
+		}
+		if enabled {
+			if err := s.twoFactor.Verify(ctx, user.UID, params.TOTPCode); err != nil {
+				return nil, err
+			}
+		} else if s.twoFactor.IsRequired(user.UID) {
+			// Let the admin in so they can enroll instead of being locked out,
+			// the token only reaches the TOTP enrollment routes until they sign in again
+			// 允许管理员登录以完成设置，避免被锁在门外；在重新登录前该令牌只能访问 TOTP 设置接口
+			totpSetupRequired = true
+		}
+	}
+
 	// Generate Token via TokenService
 	// 生成 Token
 	var token *domain.AuthToken
 	var tokenStr string
 	var errToken error
 
-	if params.TokenID > 0 && strings.ToLower(clientType) == "webgui" {
+	if totpSetupRequired {
+		token, tokenStr, err = s.tokenService.CreateForTOTPSetup(ctx, user.UID, clientType, clientIP, userAgent)
+	} else if params.TokenID > 0 && strings.ToLower(clientType) == "webgui" {
 		// Attempt to rotate existing login token
 		// 尝试轮转现有的登录令牌
 		token, tokenStr, errToken = s.tokenService.RotateForLogin(ctx, user.UID, params.TokenID, clientIP, userAgent)
@@ -354,9 +466,25 @@ func (s *userService) Login(ctx context.Context, params *dto.UserLoginRequest, c
 	dto := s.domainToDTO(user)
 	dto.Token = tokenStr
 	dto.TokenID = token.ID
+	dto.TOTPSetupRequired = totpSetupRequired
+	s.notifyLogin(user.UID, domain.WebhookEventUserLogin, user.Username, clientIP, clientType, userAgent)
 	return dto, nil
 }
 
+// notifyLogin reports a login attempt to the user's webhooks
+// notifyLogin 将登录尝试通知到用户的 Webhook
+func (s *userService) notifyLogin(uid int64, event domain.WebhookEvent, username, clientIP, clientType, userAgent string) {
+	if s.notifier == nil {
+		return
+	}
+	s.notifier.Notify(uid, event, map[string]string{
+		"username":   username,
+		"ip":         clientIP,
+		"clientType": clientType,
+		"userAgent":  userAgent,
+	})
+}
+
 // ChangePassword change password
 // ChangePassword 修改密码
 func (s *userService) ChangePassword(ctx context.Context, uid int64, params *dto.UserChangePasswordRequest) error {
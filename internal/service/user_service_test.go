@@ -14,6 +14,7 @@ import (
 	"github.com/haierkeys/fast-note-sync-service/pkg/app"
 	pkgapp "github.com/haierkeys/fast-note-sync-service/pkg/app"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/revocation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -49,6 +50,10 @@ func (m *mockUserTokenService) CreateForLogin(ctx context.Context, uid int64, cl
 	return &domain.AuthToken{ID: 1, UID: uid, Status: 1}, "test-token", nil
 }
 
+func (m *mockUserTokenService) CreateForTOTPSetup(ctx context.Context, uid int64, clientType, ip, userAgent string) (*domain.AuthToken, string, error) {
+	return &domain.AuthToken{ID: 2, UID: uid, Status: 1}, "totp-setup-token", nil
+}
+
 func (m *mockUserTokenService) RotateForLogin(ctx context.Context, uid int64, tokenID int64, ip, userAgent string) (*domain.AuthToken, string, error) {
 	if tokenID == 999 {
 		return nil, "", errors.New("mock rotate error")
@@ -88,6 +93,14 @@ func (m *mockUserTokenService) UpdateLastUsedAt(ctx context.Context, tokenID int
 	return errors.New("not implemented")
 }
 
+func (m *mockUserTokenService) RevokeAll(ctx context.Context, uid int64) error {
+	return nil
+}
+
+func (m *mockUserTokenService) SetDenylist(list revocation.List) {}
+
+func (m *mockUserTokenService) SetConfig(config TokenServiceConfig) {}
+
 func (m *mockUserTokenService) SetSyncHandler(handler func(uid int64, tokenID int64, scope string, kick bool)) {
 }
 
@@ -102,7 +115,7 @@ func (m *mockUserTokenService) CleanExpired(ctx context.Context, uid int64, issu
 // newUserSvc creates a userService with mocked dependencies for testing.
 // newUserSvc 创建带 mock 依赖的 userService 用于测试。
 func newUserSvc(repo domain.UserRepository, registerEnabled bool) UserService {
-	return NewUserService(repo, &mockTokenManager{}, &mockUserTokenService{}, zap.NewNop(), &ServiceConfig{
+	return NewUserService(repo, &mockTokenManager{}, &mockUserTokenService{}, nil, nil, zap.NewNop(), &ServiceConfig{
 		User: UserServiceConfig{RegisterIsEnable: registerEnabled, AdminUID: 1},
 	})
 }
@@ -707,7 +720,7 @@ func TestUserService_GetList(t *testing.T) {
 func TestUserService_IsRegisterEnabled(t *testing.T) {
 	t.Run("ConfigDisabled", func(t *testing.T) {
 		mockRepo := new(domainmocks.MockUserRepository)
-		svc := NewUserService(mockRepo, &mockTokenManager{}, &mockUserTokenService{}, zap.NewNop(), &ServiceConfig{
+		svc := NewUserService(mockRepo, &mockTokenManager{}, &mockUserTokenService{}, nil, nil, zap.NewNop(), &ServiceConfig{
 			User: UserServiceConfig{RegisterIsEnable: false, AdminUID: 0},
 		})
 		assert.False(t, svc.IsRegisterEnabled(context.Background()))
@@ -715,7 +728,7 @@ func TestUserService_IsRegisterEnabled(t *testing.T) {
 
 	t.Run("AdminUIDSet_Enabled", func(t *testing.T) {
 		mockRepo := new(domainmocks.MockUserRepository)
-		svc := NewUserService(mockRepo, &mockTokenManager{}, &mockUserTokenService{}, zap.NewNop(), &ServiceConfig{
+		svc := NewUserService(mockRepo, &mockTokenManager{}, &mockUserTokenService{}, nil, nil, zap.NewNop(), &ServiceConfig{
 			User: UserServiceConfig{RegisterIsEnable: true, AdminUID: 1},
 		})
 		assert.True(t, svc.IsRegisterEnabled(context.Background()))
@@ -724,7 +737,7 @@ func TestUserService_IsRegisterEnabled(t *testing.T) {
 	t.Run("AdminUIDZero_NoUsers", func(t *testing.T) {
 		mockRepo := new(domainmocks.MockUserRepository)
 		mockRepo.On("GetAllUIDs", mock.Anything).Return([]int64{}, nil)
-		svc := NewUserService(mockRepo, &mockTokenManager{}, &mockUserTokenService{}, zap.NewNop(), &ServiceConfig{
+		svc := NewUserService(mockRepo, &mockTokenManager{}, &mockUserTokenService{}, nil, nil, zap.NewNop(), &ServiceConfig{
 			User: UserServiceConfig{RegisterIsEnable: true, AdminUID: 0},
 		})
 		assert.True(t, svc.IsRegisterEnabled(context.Background()))
@@ -734,7 +747,7 @@ func TestUserService_IsRegisterEnabled(t *testing.T) {
 	t.Run("AdminUIDZero_WithUsers", func(t *testing.T) {
 		mockRepo := new(domainmocks.MockUserRepository)
 		mockRepo.On("GetAllUIDs", mock.Anything).Return([]int64{1}, nil)
-		svc := NewUserService(mockRepo, &mockTokenManager{}, &mockUserTokenService{}, zap.NewNop(), &ServiceConfig{
+		svc := NewUserService(mockRepo, &mockTokenManager{}, &mockUserTokenService{}, nil, nil, zap.NewNop(), &ServiceConfig{
 			User: UserServiceConfig{RegisterIsEnable: true, AdminUID: 0},
 		})
 		assert.False(t, svc.IsRegisterEnabled(context.Background()))
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// UserSettingService defines the per-user settings business service interface. Settings are plain
+// key-value pairs shared by all devices of a user, independent of any vault
+// UserSettingService 定义用户级设置业务服务接口。设置为该用户所有设备共享的键值对，与笔记库无关
+type UserSettingService interface {
+	// Get retrieves a setting by key
+	// Get 根据键获取设置
+	Get(ctx context.Context, uid int64, params *dto.UserSettingGetRequest) (*dto.UserSettingDTO, error)
+
+	// ListSince lists the settings updated after the given server timestamp, deletions included
+	// ListSince 列出指定服务端时间戳之后更新的设置，包含删除
+	ListSince(ctx context.Context, uid int64, params *dto.UserSettingListRequest) (*dto.UserSettingsSyncMessage, error)
+
+	// Put writes a setting. On a conflict it returns the stored setting together with ErrorUserSettingConflict
+	// Put 写入设置。冲突时返回已存储的设置以及 ErrorUserSettingConflict
+	Put(ctx context.Context, uid int64, params *dto.UserSettingPutRequest) (*dto.UserSettingDTO, error)
+
+	// Delete deletes a setting. On a conflict it returns the stored setting together with ErrorUserSettingConflict
+	// Delete 删除设置。冲突时返回已存储的设置以及 ErrorUserSettingConflict
+	Delete(ctx context.Context, uid int64, params *dto.UserSettingDeleteRequest) (*dto.UserSettingDTO, error)
+
+	// CleanupByTime physically removes the deletions older than cutoffTime, 0 keeps them forever
+	// CleanupByTime 物理删除早于 cutoffTime 的删除记录，为 0 时永久保留
+	CleanupByTime(ctx context.Context, cutoffTime int64) error
+}
+
+// userSettingService implements UserSettingService
+// userSettingService 实现 UserSettingService 接口
+type userSettingService struct {
+	repo   domain.UserSettingRepository // User setting repository // 用户设置仓库
+	logger *zap.Logger                  // Logger // 日志器
+}
+
+// NewUserSettingService creates a UserSettingService instance
+// NewUserSettingService 创建 UserSettingService 实例
+func NewUserSettingService(repo domain.UserSettingRepository, logger *zap.Logger) UserSettingService {
+	if logger == nil {
+		logger = zap.L()
+	}
+	return &userSettingService{repo: repo, logger: logger}
+}
+
+func userSettingToDTO(s *domain.UserSetting) *dto.UserSettingDTO {
+	d := &dto.UserSettingDTO{
+		Key:              s.Key,
+		Version:          s.Version,
+		Mtime:            s.Mtime,
+		Deleted:          s.Deleted,
+		UpdatedTimestamp: s.UpdatedTimestamp,
+		UpdatedAt:        timex.Time(s.UpdatedAt),
+	}
+	if !s.Deleted {
+		d.Value = s.Value
+	}
+	return d
+}
+
+// Get implements UserSettingService
+func (s *userSettingService) Get(ctx context.Context, uid int64, params *dto.UserSettingGetRequest) (*dto.UserSettingDTO, error) {
+	setting, err := s.repo.Get(ctx, uid, params.Key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, code.ErrorUserSettingNotFound
+		}
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if setting.Deleted {
+		return nil, code.ErrorUserSettingNotFound
+	}
+	return userSettingToDTO(setting), nil
+}
+
+// ListSince implements UserSettingService
+func (s *userSettingService) ListSince(ctx context.Context, uid int64, params *dto.UserSettingListRequest) (*dto.UserSettingsSyncMessage, error) {
+	settings, err := s.repo.ListSince(ctx, uid, params.LastTime)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	msg := &dto.UserSettingsSyncMessage{
+		Settings: make([]*dto.UserSettingDTO, 0, len(settings)),
+		LastTime: params.LastTime,
+		Context:  params.Context,
+	}
+	for _, setting := range settings {
+		msg.LastTime = max(msg.LastTime, setting.UpdatedTimestamp)
+		// A full listing has nothing to delete on the client
+		// 全量列表无需让客户端删除任何内容
+		if params.LastTime == 0 && setting.Deleted {
+			continue
+		}
+		msg.Settings = append(msg.Settings, userSettingToDTO(setting))
+	}
+	return msg, nil
+}
+
+// Put implements UserSettingService
+func (s *userSettingService) Put(ctx context.Context, uid int64, params *dto.UserSettingPutRequest) (*dto.UserSettingDTO, error) {
+	return s.write(ctx, &domain.UserSetting{
+		UID:   uid,
+		Key:   params.Key,
+		Value: params.Value,
+		Mtime: params.Mtime,
+	}, params.Version)
+}
+
+// Delete implements UserSettingService
+func (s *userSettingService) Delete(ctx context.Context, uid int64, params *dto.UserSettingDeleteRequest) (*dto.UserSettingDTO, error) {
+	return s.write(ctx, &domain.UserSetting{
+		UID:     uid,
+		Key:     params.Key,
+		Mtime:   params.Mtime,
+		Deleted: true,
+	}, params.Version)
+}
+
+// write stores a change made on top of version baseVersion. A change based on an older version is a
+// conflict unless it was made later than the stored one (its mtime is newer), in which case the later
+// edit wins
+// write 存储基于 baseVersion 版本所做的修改。基于旧版本的修改视为冲突，除非其修改时间晚于已存储的版本，
+// 此时以较晚的修改为准
+func (s *userSettingService) write(ctx context.Context, change *domain.UserSetting, baseVersion int64) (*dto.UserSettingDTO, error) {
+	now := timex.Now()
+	if change.Mtime == 0 {
+		change.Mtime = now.UnixMilli()
+	}
+	change.UpdatedTimestamp = now.UnixMilli()
+	change.UpdatedAt = time.Time(now)
+
+	current, err := s.repo.Get(ctx, change.UID, change.Key)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	if current == nil {
+		if change.Deleted {
+			return nil, code.ErrorUserSettingNotFound
+		}
+		change.Version = 1
+		change.CreatedAt = change.UpdatedAt
+		created, err := s.repo.Create(ctx, change)
+		if err == nil {
+			return userSettingToDTO(created), nil
+		}
+		// Another device created the key at the same moment
+		// 另一台设备同时创建了该键
+		current, err = s.repo.Get(ctx, change.UID, change.Key)
+		if err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		return userSettingToDTO(current), code.ErrorUserSettingConflict
+	}
+
+	if current.Deleted && change.Deleted {
+		return userSettingToDTO(current), nil
+	}
+	// Writing a deleted key from scratch (version 0) simply recreates it
+	// 从零（版本 0）写入已删除的键即重新创建该键
+	recreate := current.Deleted && baseVersion == 0
+	if !recreate && baseVersion != current.Version && change.Mtime <= current.Mtime {
+		return userSettingToDTO(current), code.ErrorUserSettingConflict
+	}
+
+	change.ID = current.ID
+	change.Version = current.Version + 1
+	change.UpdatedTimestamp = max(change.UpdatedTimestamp, current.UpdatedTimestamp+1)
+	ok, err := s.repo.UpdateIfVersion(ctx, change, current.Version)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	if !ok {
+		// Another device wrote in between, hand its value back so the client can merge
+		// 其间另一台设备已写入，返回其值以便客户端合并
+		current, err = s.repo.Get(ctx, change.UID, change.Key)
+		if err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		return userSettingToDTO(current), code.ErrorUserSettingConflict
+	}
+	change.CreatedAt = current.CreatedAt
+	return userSettingToDTO(change), nil
+}
+
+// CleanupByTime implements UserSettingService
+func (s *userSettingService) CleanupByTime(ctx context.Context, cutoffTime int64) error {
+	if cutoffTime <= 0 {
+		return nil
+	}
+	return s.repo.DeletePhysicalByTime(ctx, cutoffTime)
+}
+
+// Ensure userSettingService implements UserSettingService
+// 确保 userSettingService 实现了 UserSettingService 接口
+var _ UserSettingService = (*userSettingService)(nil)
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// memUserSettingRepo in-memory UserSettingRepository
+type memUserSettingRepo struct {
+	mu       sync.Mutex
+	settings []*domain.UserSetting
+}
+
+func (r *memUserSettingRepo) Get(ctx context.Context, uid int64, key string) (*domain.UserSetting, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.settings {
+		if s.UID == uid && s.Key == key {
+			c := *s
+			return &c, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memUserSettingRepo) ListSince(ctx context.Context, uid int64, timestamp int64) ([]*domain.UserSetting, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var list []*domain.UserSetting
+	for _, s := range r.settings {
+		if s.UID == uid && s.UpdatedTimestamp > timestamp {
+			c := *s
+			list = append(list, &c)
+		}
+	}
+	return list, nil
+}
+
+func (r *memUserSettingRepo) Create(ctx context.Context, setting *domain.UserSetting) (*domain.UserSetting, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := *setting
+	c.ID = int64(len(r.settings) + 1)
+	r.settings = append(r.settings, &c)
+	out := c
+	return &out, nil
+}
+
+func (r *memUserSettingRepo) UpdateIfVersion(ctx context.Context, setting *domain.UserSetting, version int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n, s := range r.settings {
+		if s.ID == setting.ID && s.Version == version {
+			c := *setting
+			c.CreatedAt = s.CreatedAt
+			r.settings[n] = &c
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memUserSettingRepo) DeletePhysicalByTime(ctx context.Context, timestamp int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.settings[:0]
+	for _, s := range r.settings {
+		if !s.Deleted || s.UpdatedTimestamp >= timestamp {
+			kept = append(kept, s)
+		}
+	}
+	r.settings = kept
+	return nil
+}
+
+// TestUserSettingService_Conflict verifies writes based on an outdated version are refused with the
+// current setting, unless they were made later than the stored value.
+// TestUserSettingService_Conflict 验证基于过期版本的写入会被拒绝并返回当前设置，除非其修改时间晚于已存储的值。
+func TestUserSettingService_Conflict(t *testing.T) {
+	ctx := context.Background()
+	svc := NewUserSettingService(&memUserSettingRepo{}, zap.NewNop())
+
+	created, err := svc.Put(ctx, 1, &dto.UserSettingPutRequest{Key: "theme", Value: "dark", Mtime: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created.Version)
+
+	// Device A updates on top of version 1
+	// 设备 A 基于版本 1 修改
+	updated, err := svc.Put(ctx, 1, &dto.UserSettingPutRequest{Key: "theme", Value: "light", Version: 1, Mtime: 2000})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+
+	// Device B still holds version 1 and edited earlier
+	// 设备 B 仍持有版本 1 且修改时间更早
+	current, err := svc.Put(ctx, 1, &dto.UserSettingPutRequest{Key: "theme", Value: "solarized", Version: 1, Mtime: 1500})
+	assert.ErrorIs(t, err, code.ErrorUserSettingConflict)
+	require.NotNil(t, current)
+	assert.Equal(t, "light", current.Value)
+	assert.Equal(t, int64(2), current.Version)
+
+	// A later edit wins even on an outdated version
+	// 较晚的修改即使基于过期版本也会生效
+	later, err := svc.Put(ctx, 1, &dto.UserSettingPutRequest{Key: "theme", Value: "solarized", Version: 1, Mtime: 3000})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), later.Version)
+	assert.Greater(t, later.UpdatedTimestamp, updated.UpdatedTimestamp)
+
+	// Creating a key that another device created first conflicts as well
+	// 创建已被其他设备先创建的键同样冲突
+	_, err = svc.Put(ctx, 1, &dto.UserSettingPutRequest{Key: "theme", Value: "x", Mtime: 10})
+	assert.ErrorIs(t, err, code.ErrorUserSettingConflict)
+
+	// Settings are per user
+	// 设置按用户隔离
+	_, err = svc.Get(ctx, 2, &dto.UserSettingGetRequest{Key: "theme"})
+	assert.ErrorIs(t, err, code.ErrorUserSettingNotFound)
+}
+
+// TestUserSettingService_Sync verifies the cursor listing returns changes and deletions once, and
+// that a deleted key can be created again.
+// TestUserSettingService_Sync 验证游标列表只返回一次修改与删除，且已删除的键可重新创建。
+func TestUserSettingService_Sync(t *testing.T) {
+	ctx := context.Background()
+	repo := &memUserSettingRepo{}
+	svc := NewUserSettingService(repo, zap.NewNop())
+
+	_, err := svc.Put(ctx, 1, &dto.UserSettingPutRequest{Key: "theme", Value: "dark"})
+	require.NoError(t, err)
+	plugin, err := svc.Put(ctx, 1, &dto.UserSettingPutRequest{Key: "plugin", Value: `{"a":1}`})
+	require.NoError(t, err)
+
+	all, err := svc.ListSince(ctx, 1, &dto.UserSettingListRequest{Context: "c1"})
+	require.NoError(t, err)
+	assert.Len(t, all.Settings, 2)
+	assert.Equal(t, "c1", all.Context)
+	assert.Positive(t, all.LastTime)
+
+	_, err = svc.Delete(ctx, 1, &dto.UserSettingDeleteRequest{Key: "missing"})
+	assert.ErrorIs(t, err, code.ErrorUserSettingNotFound)
+	deleted, err := svc.Delete(ctx, 1, &dto.UserSettingDeleteRequest{Key: "plugin", Version: plugin.Version})
+	require.NoError(t, err)
+	assert.True(t, deleted.Deleted)
+	assert.Empty(t, deleted.Value)
+
+	changes, err := svc.ListSince(ctx, 1, &dto.UserSettingListRequest{LastTime: all.LastTime})
+	require.NoError(t, err)
+	require.Len(t, changes.Settings, 1)
+	assert.Equal(t, "plugin", changes.Settings[0].Key)
+	assert.True(t, changes.Settings[0].Deleted)
+
+	// A full listing leaves the deletion out
+	// 全量列表不包含删除
+	all, err = svc.ListSince(ctx, 1, &dto.UserSettingListRequest{})
+	require.NoError(t, err)
+	require.Len(t, all.Settings, 1)
+	assert.Equal(t, "theme", all.Settings[0].Key)
+
+	_, err = svc.Get(ctx, 1, &dto.UserSettingGetRequest{Key: "plugin"})
+	assert.ErrorIs(t, err, code.ErrorUserSettingNotFound)
+	recreated, err := svc.Put(ctx, 1, &dto.UserSettingPutRequest{Key: "plugin", Value: `{"b":2}`})
+	require.NoError(t, err)
+	assert.False(t, recreated.Deleted)
+
+	// Tombstones are removed once older than the cutoff
+	// 删除标记早于截止时间后被清除
+	_, err = svc.Delete(ctx, 1, &dto.UserSettingDeleteRequest{Key: "theme", Version: 1})
+	require.NoError(t, err)
+	require.NoError(t, svc.CleanupByTime(ctx, 0))
+	assert.Len(t, repo.settings, 2)
+	require.NoError(t, svc.CleanupByTime(ctx, changes.LastTime+1_000_000))
+	assert.Len(t, repo.settings, 1)
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+)
+
+const (
+	// activityDefaultPageSize events per page when the request does not set one
+	// activityDefaultPageSize 请求未指定时的每页事件数
+	activityDefaultPageSize = 50
+
+	// activityScanLimit upper bound of rows loaded per source, deeper pages are cut off
+	// activityScanLimit 每个数据源加载的记录上限，更深的分页将被截断
+	activityScanLimit = 5000
+
+	// activityMatchWindow how far apart in milliseconds two records of the same change may be,
+	// e.g. the two rows of a rename or a row and its audit entry
+	// activityMatchWindow 同一次变更的两条记录最多相隔的毫秒数，例如重命名的两行记录或记录与其审计日志
+	activityMatchWindow = 5000
+)
+
+// Activity kinds
+// 活动类型
+const (
+	ActivityKindCreate  = "create"
+	ActivityKindModify  = "modify"
+	ActivityKindUpload  = "upload"
+	ActivityKindRename  = "rename"
+	ActivityKindDelete  = "delete"
+	ActivityKindRestore = "restore"
+)
+
+// activityAuditActions audit actions that appear in the activity timeline
+// activityAuditActions 出现在活动时间线中的审计操作
+var activityAuditActions = []domain.AuditAction{
+	domain.AuditActionNoteDelete,
+	domain.AuditActionNoteRestore,
+	domain.AuditActionFileRestore,
+}
+
+// SetAuditLogRepository implements VaultService
+func (s *vaultService) SetAuditLogRepository(repo domain.AuditLogRepository) {
+	s.auditRepo = repo
+}
+
+// Activity implements VaultService
+func (s *vaultService) Activity(ctx context.Context, uid int64, params *dto.VaultActivityRequest) (*dto.VaultActivityDTO, error) {
+	vaultID, err := s.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+
+	page, pageSize := params.Page, params.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = activityDefaultPageSize
+	}
+	// One extra event tells whether another page follows
+	// 多取一条事件用于判断是否还有下一页
+	limit := min(page*pageSize+1, activityScanLimit)
+
+	notes, err := s.noteRepo.ListByUpdatedTimestampPageMeta(ctx, 0, vaultID, uid, 0, limit)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	files, err := s.fileRepo.ListByUpdatedTimestampPage(ctx, 0, vaultID, uid, 0, limit)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	var audits []*domain.AuditLog
+	if s.auditRepo != nil {
+		audits, _, err = s.auditRepo.List(ctx, &domain.AuditLogFilter{
+			UID:          uid,
+			Actions:      activityAuditActions,
+			TargetPrefix: params.Vault + "/",
+		}, 1, limit)
+		if err != nil {
+			return nil, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+	}
+
+	events := append(noteActivity(notes), fileActivity(files)...)
+	events = mergeAuditActivity(events, auditActivity(audits, params.Vault))
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp > events[j].Timestamp
+	})
+
+	result := &dto.VaultActivityDTO{Days: []*dto.VaultActivityDayDTO{}, Page: page, PageSize: pageSize}
+	start := (page - 1) * pageSize
+	if start >= len(events) {
+		return result, nil
+	}
+	end := min(start+pageSize, len(events))
+	result.HasMore = len(events) > end
+	result.Days = groupActivityByDay(events[start:end])
+	return result, nil
+}
+
+// noteActivity turns the note rows of a vault into timeline events
+// noteActivity 将保险库的笔记记录转换为时间线事件
+func noteActivity(notes []*domain.Note) []*dto.VaultActivityItemDTO {
+	rows := make([]activityRow, 0, len(notes))
+	for _, n := range notes {
+		rows = append(rows, activityRow{
+			kind:        noteActivityKind(n.Action),
+			renamed:     n.Action == domain.NoteActionDelete && n.Rename != 0,
+			path:        n.Path,
+			pathHash:    n.PathHash,
+			contentHash: n.ContentHash,
+			size:        n.Size,
+			client:      n.ClientName,
+			timestamp:   n.UpdatedTimestamp,
+		})
+	}
+	return rowActivity("note", rows)
+}
+
+// fileActivity turns the file rows of a vault into timeline events, creations and edits are both uploads
+// fileActivity 将保险库的文件记录转换为时间线事件，新建与修改均视为上传
+func fileActivity(files []*domain.File) []*dto.VaultActivityItemDTO {
+	rows := make([]activityRow, 0, len(files))
+	for _, f := range files {
+		kind := ActivityKindUpload
+		if f.Action == domain.FileActionDelete {
+			kind = ActivityKindDelete
+		}
+		rows = append(rows, activityRow{
+			kind:        kind,
+			renamed:     f.Action == domain.FileActionDelete && f.Rename != 0,
+			path:        f.Path,
+			pathHash:    f.PathHash,
+			contentHash: f.ContentHash,
+			size:        f.Size,
+			timestamp:   f.UpdatedTimestamp,
+		})
+	}
+	return rowActivity("file", rows)
+}
+
+// noteActivityKind maps the action of a note row to an activity kind
+// noteActivityKind 将笔记记录的操作映射为活动类型
+func noteActivityKind(action domain.NoteAction) string {
+	switch action {
+	case domain.NoteActionCreate:
+		return ActivityKindCreate
+	case domain.NoteActionDelete:
+		return ActivityKindDelete
+	default:
+		return ActivityKindModify
+	}
+}
+
+// activityRow the fields of a note or file row the timeline needs
+// activityRow 时间线所需的笔记或文件记录字段
+type activityRow struct {
+	kind        string
+	renamed     bool // Old row left behind by a rename // 重命名遗留的旧记录
+	path        string
+	pathHash    string
+	contentHash string
+	size        int64
+	client      string
+	timestamp   int64
+}
+
+// rowActivity builds the events of the rows of one type. A rename leaves the old row deleted and
+// creates a new one with the same content, the pair is reported as a single rename
+// rowActivity 构建同一类型记录的事件。重命名会将旧记录标记为删除并新建内容相同的记录，这一对记录合并为一次重命名
+func rowActivity(typ string, rows []activityRow) []*dto.VaultActivityItemDTO {
+	renamedFrom := make(map[string][]*activityRow)
+	for i := range rows {
+		if rows[i].renamed {
+			renamedFrom[rows[i].contentHash] = append(renamedFrom[rows[i].contentHash], &rows[i])
+		}
+	}
+	paired := make(map[*activityRow]bool)
+
+	events := make([]*dto.VaultActivityItemDTO, 0, len(rows))
+	for i := range rows {
+		r := &rows[i]
+		if r.renamed {
+			continue
+		}
+		event := &dto.VaultActivityItemDTO{
+			Type:      typ,
+			Kind:      r.kind,
+			Path:      r.path,
+			PathHash:  r.pathHash,
+			Size:      r.size,
+			Client:    r.client,
+			Timestamp: r.timestamp,
+		}
+		if r.kind != ActivityKindDelete {
+			for _, old := range renamedFrom[r.contentHash] {
+				if !paired[old] && abs64(old.timestamp-r.timestamp) <= activityMatchWindow {
+					paired[old] = true
+					event.Kind = ActivityKindRename
+					event.OldPath = old.path
+					break
+				}
+			}
+		}
+		events = append(events, event)
+	}
+
+	// The new row changed again since the rename, only the old path is known
+	// 重命名后新记录又发生了变更，仅能得知旧路径
+	for i := range rows {
+		r := &rows[i]
+		if r.renamed && !paired[r] {
+			events = append(events, &dto.VaultActivityItemDTO{
+				Type:      typ,
+				Kind:      ActivityKindRename,
+				OldPath:   r.path,
+				Size:      r.size,
+				Client:    r.client,
+				Timestamp: r.timestamp,
+			})
+		}
+	}
+	return events
+}
+
+// auditActivity turns the audit entries of a vault into timeline events
+// auditActivity 将保险库的审计日志转换为时间线事件
+func auditActivity(audits []*domain.AuditLog, vault string) []*dto.VaultActivityItemDTO {
+	events := make([]*dto.VaultActivityItemDTO, 0, len(audits))
+	for _, a := range audits {
+		path, ok := strings.CutPrefix(a.Target, vault+"/")
+		if !ok || path == "" {
+			continue
+		}
+		event := &dto.VaultActivityItemDTO{
+			Type:      "note",
+			Path:      path,
+			PathHash:  util.EncodeHash32(path),
+			Client:    a.ClientName,
+			Timestamp: a.CreatedAt.UnixMilli(),
+		}
+		switch a.Action {
+		case domain.AuditActionNoteDelete:
+			event.Kind = ActivityKindDelete
+		case domain.AuditActionNoteRestore:
+			event.Kind = ActivityKindRestore
+		case domain.AuditActionFileRestore:
+			event.Type = "file"
+			event.Kind = ActivityKindRestore
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// mergeAuditActivity adds the audit events to the row events. A row only keeps the latest change of
+// its path, so audit events it also covers are dropped; a restore turns the matching row event into one
+// mergeAuditActivity 将审计事件并入记录事件。记录只保留路径的最后一次变更，已被记录覆盖的审计事件将被丢弃；
+// 恢复操作会将对应的记录事件标记为恢复
+func mergeAuditActivity(events, audits []*dto.VaultActivityItemDTO) []*dto.VaultActivityItemDTO {
+	byPath := make(map[string]*dto.VaultActivityItemDTO, len(events))
+	for _, e := range events {
+		if e.Path != "" {
+			byPath[e.Type+"|"+e.Path] = e
+		}
+	}
+	for _, a := range audits {
+		row, ok := byPath[a.Type+"|"+a.Path]
+		if ok && abs64(row.Timestamp-a.Timestamp) <= activityMatchWindow {
+			if a.Kind == ActivityKindRestore && row.Kind != ActivityKindDelete {
+				row.Kind = ActivityKindRestore
+				if row.Client == "" {
+					row.Client = a.Client
+				}
+				continue
+			}
+			if a.Kind == row.Kind {
+				continue
+			}
+		}
+		events = append(events, a)
+	}
+	return events
+}
+
+// groupActivityByDay groups events sorted newest first by day in server local time
+// groupActivityByDay 按服务器本地时间的日期对已按时间倒序排列的事件分组
+func groupActivityByDay(events []*dto.VaultActivityItemDTO) []*dto.VaultActivityDayDTO {
+	var days []*dto.VaultActivityDayDTO
+	for _, e := range events {
+		date := time.UnixMilli(e.Timestamp).Format(time.DateOnly)
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, &dto.VaultActivityDayDTO{Date: date})
+		}
+		days[len(days)-1].Items = append(days[len(days)-1].Items, e)
+	}
+	return days
+}
+
+// abs64 absolute value of an int64
+// abs64 int64 的绝对值
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
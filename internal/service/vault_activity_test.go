@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// activityDay returns the millisecond timestamp of hour:00 on the given day of January 2024 in local time
+func activityDay(day, hour int) int64 {
+	return time.Date(2024, 1, day, hour, 0, 0, 0, time.Local).UnixMilli()
+}
+
+func newActivitySvc(notes []*domain.Note, files []*domain.File, audits []*domain.AuditLog) (VaultService, *fakeAuditLogRepo) {
+	vaultRepo := new(domainmocks.MockVaultRepository)
+	noteRepo := new(domainmocks.MockNoteRepository)
+	fileRepo := new(domainmocks.MockFileRepository)
+	vaultRepo.On("GetByName", mock.Anything, "MyVault", int64(1)).Return(newVault(7, "MyVault"), nil)
+	noteRepo.On("ListByUpdatedTimestampPageMeta", mock.Anything, int64(0), int64(7), int64(1), 0, mock.Anything).Return(notes, nil)
+	fileRepo.On("ListByUpdatedTimestampPage", mock.Anything, int64(0), int64(7), int64(1), 0, mock.Anything).Return(files, nil)
+
+	auditRepo := &fakeAuditLogRepo{logs: audits}
+	svc := NewVaultService(vaultRepo, noteRepo, fileRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	svc.SetAuditLogRepository(auditRepo)
+	return svc, auditRepo
+}
+
+// TestVaultService_Activity verifies rows and audit entries are merged into one timeline grouped by day:
+// a rename pair becomes one rename, a restore marks its row and an older deletion comes from the audit log.
+// TestVaultService_Activity 验证记录与审计日志合并为按天分组的时间线：重命名的一对记录合并为一次重命名，
+// 恢复操作标记对应记录，较早的删除来自审计日志。
+func TestVaultService_Activity(t *testing.T) {
+	svc, auditRepo := newActivitySvc(
+		[]*domain.Note{
+			{Path: "new.md", PathHash: "hn", Action: domain.NoteActionCreate, ContentHash: "c1", ClientName: "Desktop", UpdatedTimestamp: activityDay(2, 10) + 1},
+			{Path: "old.md", PathHash: "ho", Action: domain.NoteActionDelete, Rename: 1, ContentHash: "c1", UpdatedTimestamp: activityDay(2, 10)},
+			{Path: "back.md", PathHash: "hb", Action: domain.NoteActionModify, Size: 5, UpdatedTimestamp: activityDay(2, 9)},
+			{Path: "gone.md", PathHash: "hg", Action: domain.NoteActionDelete, UpdatedTimestamp: activityDay(1, 12)},
+		},
+		[]*domain.File{
+			{Path: "a.png", PathHash: "hp", Action: domain.FileActionModify, Size: 20, UpdatedTimestamp: activityDay(2, 11)},
+		},
+		[]*domain.AuditLog{
+			{Action: domain.AuditActionNoteRestore, Target: "MyVault/back.md", ClientName: "WebGUI", CreatedAt: time.UnixMilli(activityDay(2, 9) + 100)},
+			{Action: domain.AuditActionNoteDelete, Target: "MyVault/gone.md", CreatedAt: time.UnixMilli(activityDay(1, 12))},
+			{Action: domain.AuditActionNoteDelete, Target: "MyVault/draft.md", CreatedAt: time.UnixMilli(activityDay(1, 8))},
+		},
+	)
+
+	result, err := svc.Activity(context.Background(), 1, &dto.VaultActivityRequest{Vault: "MyVault"})
+	require.NoError(t, err)
+	assert.Equal(t, "MyVault/", auditRepo.filter.TargetPrefix)
+	assert.Equal(t, int64(1), auditRepo.filter.UID)
+	assert.False(t, result.HasMore)
+	require.Len(t, result.Days, 2)
+
+	day2 := result.Days[0]
+	assert.Equal(t, "2024-01-02", day2.Date)
+	require.Len(t, day2.Items, 3)
+	assert.Equal(t, "file", day2.Items[0].Type)
+	assert.Equal(t, ActivityKindUpload, day2.Items[0].Kind)
+	assert.Equal(t, ActivityKindRename, day2.Items[1].Kind)
+	assert.Equal(t, "new.md", day2.Items[1].Path)
+	assert.Equal(t, "old.md", day2.Items[1].OldPath)
+	assert.Equal(t, ActivityKindRestore, day2.Items[2].Kind)
+	assert.Equal(t, "back.md", day2.Items[2].Path)
+
+	day1 := result.Days[1]
+	assert.Equal(t, "2024-01-01", day1.Date)
+	require.Len(t, day1.Items, 2)
+	assert.Equal(t, ActivityKindDelete, day1.Items[0].Kind)
+	assert.Equal(t, "gone.md", day1.Items[0].Path)
+	assert.Equal(t, ActivityKindDelete, day1.Items[1].Kind)
+	assert.Equal(t, "draft.md", day1.Items[1].Path)
+}
+
+// TestVaultService_Activity_Pages verifies pages are cut from the merged timeline.
+// TestVaultService_Activity_Pages 验证分页基于合并后的时间线。
+func TestVaultService_Activity_Pages(t *testing.T) {
+	notes := []*domain.Note{
+		{Path: "c.md", Action: domain.NoteActionModify, UpdatedTimestamp: activityDay(3, 10)},
+		{Path: "b.md", Action: domain.NoteActionModify, UpdatedTimestamp: activityDay(2, 10)},
+		{Path: "a.md", Action: domain.NoteActionCreate, UpdatedTimestamp: activityDay(1, 10)},
+	}
+
+	svc, _ := newActivitySvc(notes, nil, nil)
+	result, err := svc.Activity(context.Background(), 1, &dto.VaultActivityRequest{Vault: "MyVault", Page: 2, PageSize: 2})
+	require.NoError(t, err)
+	assert.False(t, result.HasMore)
+	require.Len(t, result.Days, 1)
+	assert.Equal(t, "a.md", result.Days[0].Items[0].Path)
+
+	svc, _ = newActivitySvc(notes, nil, nil)
+	result, err = svc.Activity(context.Background(), 1, &dto.VaultActivityRequest{Vault: "MyVault", Page: 1, PageSize: 2})
+	require.NoError(t, err)
+	assert.True(t, result.HasMore)
+	require.Len(t, result.Days, 2)
+
+	svc, _ = newActivitySvc(notes, nil, nil)
+	result, err = svc.Activity(context.Background(), 1, &dto.VaultActivityRequest{Vault: "MyVault", Page: 5, PageSize: 2})
+	require.NoError(t, err)
+	assert.Empty(t, result.Days)
+}
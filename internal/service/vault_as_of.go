@@ -0,0 +1,128 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"gorm.io/gorm"
+)
+
+// Content sources of a note as of a point in time
+// 笔记在某一时间点内容的来源
+const (
+	asOfSourceHistory = "history" // Snapshot stored by a later history version // 来自之后某个历史版本保存的快照
+	asOfSourceNote    = "note"    // The note itself, unchanged since then // 来自笔记本身，此后未被修改
+)
+
+// existedAt reports whether the note existed at the point in time at (milliseconds):
+// created before it and not yet deleted.
+// existedAt 判断笔记在时间点 at（毫秒）是否存在：在此之前创建且尚未删除。
+func existedAt(n *domain.Note, at int64) bool {
+	if n.CreatedAt.UnixMilli() > at {
+		return false
+	}
+	return !n.IsDeleted() || n.UpdatedTimestamp > at
+}
+
+// AsOf implements VaultService.
+// Notes physically purged from the trash are gone and cannot be listed.
+// 已从回收站彻底删除的笔记无法再列出。
+func (s *vaultService) AsOf(ctx context.Context, uid int64, params *dto.VaultAsOfRequest) ([]*dto.VaultAsOfItemDTO, error) {
+	vaultID, err := s.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.noteRepo.ListByUpdatedTimestampMeta(ctx, 0, vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	items := make([]*dto.VaultAsOfItemDTO, 0, len(notes))
+	for _, n := range notes {
+		if !existedAt(n, params.Timestamp) {
+			continue
+		}
+		items = append(items, &dto.VaultAsOfItemDTO{
+			Path:     n.Path,
+			PathHash: n.PathHash,
+			Changed:  n.UpdatedTimestamp > params.Timestamp,
+			Deleted:  n.IsDeleted(),
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Path < items[j].Path
+	})
+	return items, nil
+}
+
+// AsOfNote implements VaultService.
+// A history version stores the content before its modification, so the first version created after
+// the point in time holds the content at that time. Without such a version the note has not been
+// modified since, except for changes whose history version is still pending, which are covered by the
+// last snapshot of the note.
+// 历史版本保存的是修改前的内容，因此时间点之后创建的第一个历史版本即为该时间点的内容。
+// 没有这样的版本时笔记此后未被修改；尚未生成历史版本的修改由笔记的最后快照覆盖。
+func (s *vaultService) AsOfNote(ctx context.Context, uid int64, params *dto.VaultAsOfNoteRequest) (*dto.VaultAsOfNoteDTO, error) {
+	vaultID, err := s.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+
+	pathHash := params.PathHash
+	if pathHash == "" {
+		if params.Path == "" {
+			return nil, code.ErrorInvalidParams.WithDetails("path or pathHash is required")
+		}
+		pathHash = util.EncodeHash32(params.Path)
+	}
+
+	candidates, err := s.noteRepo.ListByPathHash(ctx, pathHash, vaultID, uid)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	var note *domain.Note
+	for _, n := range candidates {
+		if existedAt(n, params.Timestamp) {
+			note = n
+			break
+		}
+	}
+	if note == nil {
+		return nil, code.ErrorNoteNotFound
+	}
+
+	result := &dto.VaultAsOfNoteDTO{
+		Path:      note.Path,
+		PathHash:  note.PathHash,
+		Timestamp: params.Timestamp,
+	}
+
+	history, err := s.historyRepo.GetFirstAfter(ctx, note.ID, time.UnixMilli(params.Timestamp), uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	switch {
+	case history != nil:
+		result.Content = history.Content
+		result.Source = asOfSourceHistory
+		result.Version = history.Version
+	case note.UpdatedAt.UnixMilli() > params.Timestamp && note.ContentLastSnapshotHash != "":
+		result.Content = note.ContentLastSnapshot
+		result.Source = asOfSourceNote
+	default:
+		result.Content = note.Content
+		result.Source = asOfSourceNote
+	}
+	result.ContentHash = util.EncodeHash32(result.Content)
+	return result, nil
+}
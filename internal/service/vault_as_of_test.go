@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newAsOfSvc() (VaultService, *domainmocks.MockNoteRepository, *domainmocks.MockNoteHistoryRepository) {
+	vaultRepo := new(domainmocks.MockVaultRepository)
+	noteRepo := new(domainmocks.MockNoteRepository)
+	historyRepo := new(domainmocks.MockNoteHistoryRepository)
+	vaultRepo.On("GetByName", mock.Anything, "MyVault", int64(1)).Return(newVault(7, "MyVault"), nil)
+	svc := NewVaultService(vaultRepo, noteRepo, nil, nil, nil, historyRepo, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	return svc, noteRepo, historyRepo
+}
+
+// TestVaultService_AsOf verifies only notes created before and not deleted at the point in time are listed.
+// TestVaultService_AsOf 验证只列出在时间点之前创建且当时未删除的笔记。
+func TestVaultService_AsOf(t *testing.T) {
+	svc, noteRepo, _ := newAsOfSvc()
+	at := time.UnixMilli(1000)
+	noteRepo.On("ListByUpdatedTimestampMeta", mock.Anything, int64(0), int64(7), int64(1)).Return([]*domain.Note{
+		{Path: "z.md", Action: domain.NoteActionModify, CreatedAt: at.Add(-time.Second), UpdatedTimestamp: 2000},
+		{Path: "a.md", Action: domain.NoteActionCreate, CreatedAt: at.Add(-time.Second), UpdatedTimestamp: 500},
+		{Path: "later.md", Action: domain.NoteActionCreate, CreatedAt: at.Add(time.Second), UpdatedTimestamp: 2000},
+		{Path: "gone.md", Action: domain.NoteActionDelete, CreatedAt: at.Add(-time.Second), UpdatedTimestamp: 900},
+		{Path: "removed.md", Action: domain.NoteActionDelete, CreatedAt: at.Add(-time.Second), UpdatedTimestamp: 1500},
+	}, nil)
+
+	items, err := svc.AsOf(context.Background(), 1, &dto.VaultAsOfRequest{Vault: "MyVault", Timestamp: 1000})
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Equal(t, "a.md", items[0].Path)
+	assert.False(t, items[0].Changed)
+	assert.Equal(t, "removed.md", items[1].Path)
+	assert.True(t, items[1].Deleted)
+	assert.Equal(t, "z.md", items[2].Path)
+	assert.True(t, items[2].Changed)
+}
+
+// TestVaultService_AsOfNote verifies the content comes from the first later history version, or the note itself.
+// TestVaultService_AsOfNote 验证内容取自之后的第一个历史版本，否则取自笔记本身。
+func TestVaultService_AsOfNote(t *testing.T) {
+	svc, noteRepo, historyRepo := newAsOfSvc()
+	note := &domain.Note{
+		ID: 3, Path: "a.md", PathHash: util.EncodeHash32("a.md"), Action: domain.NoteActionModify,
+		Content: "v3", ContentLastSnapshot: "v2", ContentLastSnapshotHash: "h2",
+		CreatedAt: time.UnixMilli(100), UpdatedAt: time.UnixMilli(3000), UpdatedTimestamp: 3000,
+	}
+	noteRepo.On("ListByPathHash", mock.Anything, note.PathHash, int64(7), int64(1)).Return([]*domain.Note{note}, nil)
+	historyRepo.On("GetFirstAfter", mock.Anything, int64(3), time.UnixMilli(1000), int64(1)).Return(&domain.NoteHistory{Content: "v1", Version: 2}, nil)
+	historyRepo.On("GetFirstAfter", mock.Anything, int64(3), mock.Anything, int64(1)).Return(nil, nil)
+
+	ctx := context.Background()
+	got, err := svc.AsOfNote(ctx, 1, &dto.VaultAsOfNoteRequest{Vault: "MyVault", Path: "a.md", Timestamp: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, "v1", got.Content)
+	assert.Equal(t, "history", got.Source)
+	assert.Equal(t, int64(2), got.Version)
+	assert.Equal(t, util.EncodeHash32("v1"), got.ContentHash)
+
+	// Modified after the point in time, history version still pending
+	got, err = svc.AsOfNote(ctx, 1, &dto.VaultAsOfNoteRequest{Vault: "MyVault", Path: "a.md", Timestamp: 2000})
+	require.NoError(t, err)
+	assert.Equal(t, "v2", got.Content)
+	assert.Equal(t, "note", got.Source)
+
+	got, err = svc.AsOfNote(ctx, 1, &dto.VaultAsOfNoteRequest{Vault: "MyVault", Path: "a.md", Timestamp: 4000})
+	require.NoError(t, err)
+	assert.Equal(t, "v3", got.Content)
+
+	_, err = svc.AsOfNote(ctx, 1, &dto.VaultAsOfNoteRequest{Vault: "MyVault", Path: "a.md", Timestamp: 50})
+	assert.ErrorIs(t, err, code.ErrorNoteNotFound)
+}
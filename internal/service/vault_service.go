@@ -71,6 +71,30 @@ type VaultService interface {
 	// ForceDeleteDataItem permanently deletes a single note or file and writes a sync log
 	// ForceDeleteDataItem 强制物理删除单个笔记或附件数据并记录同步更新日志
 	ForceDeleteDataItem(ctx context.Context, uid int64, vaultID int64, itemType string, itemID int64, clientType, clientName, clientVersion string) error
+
+	// Trash lists deleted notes, files and folders of a vault, most recently deleted first
+	// Trash 获取仓库回收站中已删除的笔记、文件和文件夹，最近删除的排在前面
+	Trash(ctx context.Context, uid int64, vault string) ([]*dto.VaultTrashItemDTO, error)
+
+	// EmptyTrash permanently deletes the selected trash items, or the whole trash, without waiting for the retention time
+	// EmptyTrash 立即彻底删除选中的回收站条目或整个回收站，无需等待保留期限
+	EmptyTrash(ctx context.Context, uid int64, params *dto.VaultTrashEmptyRequest, clientType, clientName, clientVersion string) (*dto.VaultTrashEmptyResult, error)
+
+	// AsOf lists the notes of a vault that existed at a point in time, sorted by path
+	// AsOf 列出保险库在某一时间点存在的笔记，按路径排序
+	AsOf(ctx context.Context, uid int64, params *dto.VaultAsOfRequest) ([]*dto.VaultAsOfItemDTO, error)
+
+	// AsOfNote gets the content of a note as it existed at a point in time, from its history versions
+	// AsOfNote 根据历史版本获取笔记在某一时间点的内容
+	AsOfNote(ctx context.Context, uid int64, params *dto.VaultAsOfNoteRequest) (*dto.VaultAsOfNoteDTO, error)
+
+	// Activity lists the recent changes of a vault, newest first, grouped by day
+	// Activity 获取保险库最近的变更，按时间倒序并按天分组
+	Activity(ctx context.Context, uid int64, params *dto.VaultActivityRequest) (*dto.VaultActivityDTO, error)
+
+	// SetAuditLogRepository sets the audit log the activity timeline reads deletions and restores from
+	// SetAuditLogRepository 设置活动时间线读取删除与恢复记录的审计日志
+	SetAuditLogRepository(repo domain.AuditLogRepository)
 }
 
 // vaultService implementation of VaultService interface
@@ -88,6 +112,7 @@ type vaultService struct {
 	shareRepo   domain.UserShareRepository
 	gitRepo     domain.GitSyncRepository
 	backupRepo  domain.BackupRepository
+	auditRepo   domain.AuditLogRepository
 	logger      *zap.Logger
 	sf          *singleflight.Group
 }
@@ -0,0 +1,202 @@
+// Package service implements the business logic layer
+// Package service 实现业务逻辑层
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
+	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+)
+
+// trashListLimit upper bound of trash entries loaded per type, same as the recycle-clear listing
+// trashListLimit 每种类型加载的回收站条目上限，与清理回收站时的列表一致
+const trashListLimit = 10000
+
+// trashContent deleted notes, files and folders of one vault
+// trashContent 单个仓库中已删除的笔记、文件和文件夹
+type trashContent struct {
+	notes   []*domain.Note
+	files   []*domain.File
+	folders []*domain.Folder
+}
+
+// loadTrash loads every item currently in the trash of a vault
+// loadTrash 加载仓库回收站中的全部条目
+func (s *vaultService) loadTrash(ctx context.Context, uid, vaultID int64) (*trashContent, error) {
+	notes, err := s.noteRepo.List(ctx, vaultID, 1, trashListLimit, uid, "", true, "", false, "", "", nil, nil)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	files, err := s.fileRepo.List(ctx, vaultID, 1, trashListLimit, uid, "", true, "", "")
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+	folders, err := s.folderRepo.List(ctx, vaultID, uid)
+	if err != nil {
+		return nil, code.ErrorDBQuery.WithDetails(err.Error())
+	}
+
+	t := &trashContent{notes: notes, files: files}
+	for _, f := range folders {
+		if f.IsDeleted() {
+			t.folders = append(t.folders, f)
+		}
+	}
+	return t, nil
+}
+
+// selectTrash resolves the requested items against the trash, skipping items that are not in it
+// selectTrash 在回收站中查找请求的条目，不在回收站中的条目将被跳过
+func (s *vaultService) selectTrash(ctx context.Context, uid, vaultID int64, items []dto.VaultTrashItem) *trashContent {
+	t := &trashContent{}
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		pathHash := item.PathHash
+		if pathHash == "" {
+			pathHash = util.EncodeHash32(item.Path)
+		}
+		if seen[item.Type+"|"+pathHash] {
+			continue
+		}
+		seen[item.Type+"|"+pathHash] = true
+
+		switch item.Type {
+		case "note":
+			if n, err := s.noteRepo.GetByPathHashIncludeRecycle(ctx, pathHash, vaultID, uid, true); err == nil && n != nil {
+				t.notes = append(t.notes, n)
+			}
+		case "file":
+			if f, err := s.fileRepo.GetByPathHash(ctx, pathHash, vaultID, uid); err == nil && f != nil && f.IsDeleted() && f.Rename == 0 {
+				t.files = append(t.files, f)
+			}
+		case "folder":
+			if f, err := s.folderRepo.GetByPathHash(ctx, pathHash, vaultID, uid); err == nil && f != nil && f.IsDeleted() {
+				t.folders = append(t.folders, f)
+			}
+		}
+	}
+	return t
+}
+
+// Trash implements VaultService
+func (s *vaultService) Trash(ctx context.Context, uid int64, vault string) ([]*dto.VaultTrashItemDTO, error) {
+	vaultID, err := s.MustGetID(ctx, uid, vault)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := s.loadTrash(ctx, uid, vaultID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*dto.VaultTrashItemDTO, 0, len(t.notes)+len(t.files)+len(t.folders))
+	for _, n := range t.notes {
+		items = append(items, &dto.VaultTrashItemDTO{Type: "note", ID: n.ID, Path: n.Path, PathHash: n.PathHash, Size: n.Size, DeletedAt: n.UpdatedTimestamp})
+	}
+	for _, f := range t.files {
+		items = append(items, &dto.VaultTrashItemDTO{Type: "file", ID: f.ID, Path: f.Path, PathHash: f.PathHash, Size: f.Size, DeletedAt: f.UpdatedTimestamp})
+	}
+	for _, f := range t.folders {
+		items = append(items, &dto.VaultTrashItemDTO{Type: "folder", ID: f.ID, Path: f.Path, PathHash: f.PathHash, DeletedAt: f.UpdatedTimestamp})
+	}
+
+	// Most recently deleted first
+	// 最近删除的排在前面
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt > items[j].DeletedAt
+	})
+	return items, nil
+}
+
+// EmptyTrash implements VaultService
+func (s *vaultService) EmptyTrash(ctx context.Context, uid int64, params *dto.VaultTrashEmptyRequest, clientType, clientName, clientVersion string) (*dto.VaultTrashEmptyResult, error) {
+	if !params.All && len(params.Items) == 0 {
+		return nil, code.ErrorInvalidParams.WithDetails("items is empty and all is false")
+	}
+
+	vaultID, err := s.MustGetID(ctx, uid, params.Vault)
+	if err != nil {
+		return nil, err
+	}
+
+	var t *trashContent
+	if params.All {
+		if t, err = s.loadTrash(ctx, uid, vaultID); err != nil {
+			return nil, err
+		}
+	} else {
+		t = s.selectTrash(ctx, uid, vaultID, params.Items)
+	}
+
+	result := &dto.VaultTrashEmptyResult{}
+	for _, n := range t.notes {
+		if err := s.noteRepo.Delete(ctx, n.ID, vaultID, uid); err != nil {
+			return result, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		s.logTrashPurge(ctx, uid, vaultID, domain.SyncLogTypeNote, n.Path, n.PathHash, n.Size, clientType, clientName, clientVersion)
+		result.Notes++
+	}
+	for _, f := range t.files {
+		if err := s.fileRepo.Delete(ctx, f.ID, uid); err != nil {
+			return result, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		s.logTrashPurge(ctx, uid, vaultID, domain.SyncLogTypeFile, f.Path, f.PathHash, f.Size, clientType, clientName, clientVersion)
+		result.Files++
+	}
+	for _, f := range t.folders {
+		if err := s.folderRepo.Delete(ctx, f.ID, uid); err != nil {
+			return result, code.ErrorDBQuery.WithDetails(err.Error())
+		}
+		s.logTrashPurge(ctx, uid, vaultID, domain.SyncLogTypeFolder, f.Path, f.PathHash, 0, clientType, clientName, clientVersion)
+		result.Folders++
+	}
+
+	// Async update vault size stats
+	// 异步更新库大小统计
+	if result.Notes > 0 {
+		safego.Go(s.logger, func() {
+			res, err := s.noteRepo.CountSizeSum(context.Background(), vaultID, uid)
+			if err == nil && res != nil {
+				_ = s.UpdateNoteStats(context.Background(), res.Size, res.Count, vaultID, uid)
+			}
+		})
+	}
+	if result.Files > 0 {
+		safego.Go(s.logger, func() {
+			res, err := s.fileRepo.CountSizeSum(context.Background(), vaultID, uid)
+			if err == nil && res != nil {
+				_ = s.UpdateFileStats(context.Background(), res.Size, res.Count, vaultID, uid)
+			}
+		})
+	}
+	return result, nil
+}
+
+// logTrashPurge writes the SyncLogActionDelete entry of a purged trash item
+// logTrashPurge 为被彻底删除的回收站条目记录 SyncLogActionDelete
+func (s *vaultService) logTrashPurge(ctx context.Context, uid, vaultID int64, typ domain.SyncLogType, path, pathHash string, size int64, clientType, clientName, clientVersion string) {
+	if s.logRepo == nil {
+		return
+	}
+	_ = s.logRepo.Create(ctx, &domain.SyncLog{
+		UID:           uid,
+		VaultID:       vaultID,
+		Type:          typ,
+		Action:        domain.SyncLogActionDelete,
+		Path:          path,
+		PathHash:      pathHash,
+		Size:          size,
+		ClientType:    clientType,
+		ClientName:    clientName,
+		ClientVersion: clientVersion,
+		Status:        1, // success // 成功
+		CreatedAt:     timex.Now(),
+	}, uid)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/domain"
+	domainmocks "github.com/haierkeys/fast-note-sync-service/internal/domain/mocks"
+	"github.com/haierkeys/fast-note-sync-service/internal/dto"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type trashMocks struct {
+	vault  *domainmocks.MockVaultRepository
+	note   *domainmocks.MockNoteRepository
+	file   *domainmocks.MockFileRepository
+	folder *domainmocks.MockFolderRepository
+	log    *domainmocks.MockSyncLogRepository
+}
+
+func newTrashSvc() (VaultService, *trashMocks) {
+	m := &trashMocks{
+		vault:  new(domainmocks.MockVaultRepository),
+		note:   new(domainmocks.MockNoteRepository),
+		file:   new(domainmocks.MockFileRepository),
+		folder: new(domainmocks.MockFolderRepository),
+		log:    new(domainmocks.MockSyncLogRepository),
+	}
+	m.vault.On("GetByName", mock.Anything, "MyVault", int64(1)).Return(newVault(7, "MyVault"), nil)
+	// Stats are refreshed asynchronously after a purge
+	// 彻底删除后异步刷新统计
+	m.note.On("CountSizeSum", mock.Anything, int64(7), int64(1)).Return(nil, gorm.ErrRecordNotFound).Maybe()
+	m.file.On("CountSizeSum", mock.Anything, int64(7), int64(1)).Return(nil, gorm.ErrRecordNotFound).Maybe()
+
+	svc := NewVaultService(m.vault, m.note, m.file, m.folder, m.log, nil, nil, nil, nil, nil, nil, nil, zap.NewNop())
+	return svc, m
+}
+
+func (m *trashMocks) expectTrash(notes []*domain.Note, files []*domain.File, folders []*domain.Folder) {
+	m.note.On("List", mock.Anything, int64(7), 1, trashListLimit, int64(1), "", true, "", false, "", "", mock.Anything, mock.Anything).Return(notes, nil)
+	m.file.On("List", mock.Anything, int64(7), 1, trashListLimit, int64(1), "", true, "", "").Return(files, nil)
+	m.folder.On("List", mock.Anything, int64(7), int64(1)).Return(folders, nil)
+}
+
+// TestVaultService_Trash verifies notes, files and deleted folders are merged, newest deletion first.
+// TestVaultService_Trash 验证笔记、文件和已删除文件夹被合并，且按删除时间倒序排列。
+func TestVaultService_Trash(t *testing.T) {
+	svc, m := newTrashSvc()
+	m.expectTrash(
+		[]*domain.Note{{ID: 1, Path: "a.md", PathHash: "ha", Size: 10, UpdatedTimestamp: 100}},
+		[]*domain.File{{ID: 2, Path: "b.png", PathHash: "hb", Size: 20, UpdatedTimestamp: 300}},
+		[]*domain.Folder{
+			{ID: 3, Path: "Old", PathHash: "hc", Action: domain.FolderActionDelete, UpdatedTimestamp: 200},
+			{ID: 4, Path: "Live", PathHash: "hd", Action: domain.FolderActionCreate, UpdatedTimestamp: 400},
+		},
+	)
+
+	items, err := svc.Trash(context.Background(), 1, "MyVault")
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.Equal(t, "file", items[0].Type)
+	assert.Equal(t, int64(300), items[0].DeletedAt)
+	assert.Equal(t, "folder", items[1].Type)
+	assert.Equal(t, "Old", items[1].Path)
+	assert.Equal(t, "note", items[2].Type)
+	assert.Equal(t, int64(10), items[2].Size)
+}
+
+// TestVaultService_EmptyTrash_All verifies every trash item is physically deleted and logged.
+// TestVaultService_EmptyTrash_All 验证回收站全部条目被物理删除并记录同步日志。
+func TestVaultService_EmptyTrash_All(t *testing.T) {
+	svc, m := newTrashSvc()
+	m.expectTrash(
+		[]*domain.Note{{ID: 1, Path: "a.md", PathHash: "ha"}},
+		[]*domain.File{{ID: 2, Path: "b.png", PathHash: "hb"}},
+		[]*domain.Folder{{ID: 3, Path: "Old", PathHash: "hc", Action: domain.FolderActionDelete}},
+	)
+	m.note.On("Delete", mock.Anything, int64(1), int64(7), int64(1)).Return(nil).Once()
+	m.file.On("Delete", mock.Anything, int64(2), int64(1)).Return(nil).Once()
+	m.folder.On("Delete", mock.Anything, int64(3), int64(1)).Return(nil).Once()
+	m.log.On("Create", mock.Anything, mock.MatchedBy(func(l *domain.SyncLog) bool {
+		return l.Action == domain.SyncLogActionDelete && l.ClientType == "WebGui"
+	}), int64(1)).Return(nil).Times(3)
+
+	result, err := svc.EmptyTrash(context.Background(), 1, &dto.VaultTrashEmptyRequest{Vault: "MyVault", All: true}, "WebGui", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, &dto.VaultTrashEmptyResult{Notes: 1, Files: 1, Folders: 1}, result)
+	m.note.AssertExpectations(t)
+	m.file.AssertExpectations(t)
+	m.folder.AssertExpectations(t)
+	m.log.AssertExpectations(t)
+}
+
+// TestVaultService_EmptyTrash_Selected verifies only selected items still in the trash are purged.
+// TestVaultService_EmptyTrash_Selected 验证只彻底删除仍在回收站中的选中条目。
+func TestVaultService_EmptyTrash_Selected(t *testing.T) {
+	svc, m := newTrashSvc()
+	m.note.On("GetByPathHashIncludeRecycle", mock.Anything, util.EncodeHash32("a.md"), int64(7), int64(1), true).
+		Return(&domain.Note{ID: 1, Path: "a.md"}, nil).Once()
+	m.file.On("GetByPathHash", mock.Anything, "hb", int64(7), int64(1)).
+		Return(&domain.File{ID: 2, Path: "b.png", Action: domain.FileActionCreate}, nil).Once()
+	m.note.On("Delete", mock.Anything, int64(1), int64(7), int64(1)).Return(nil).Once()
+	m.log.On("Create", mock.Anything, mock.Anything, int64(1)).Return(nil).Once()
+
+	result, err := svc.EmptyTrash(context.Background(), 1, &dto.VaultTrashEmptyRequest{
+		Vault: "MyVault",
+		Items: []dto.VaultTrashItem{
+			{Type: "note", Path: "a.md"},
+			{Type: "note", Path: "a.md"},
+			{Type: "file", PathHash: "hb"},
+		},
+	}, "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, &dto.VaultTrashEmptyResult{Notes: 1}, result)
+	m.note.AssertExpectations(t)
+	m.file.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestVaultService_EmptyTrash_NothingSelected verifies a request without items or all is rejected.
+// TestVaultService_EmptyTrash_NothingSelected 验证既无条目也未设置 all 的请求被拒绝。
+func TestVaultService_EmptyTrash_NothingSelected(t *testing.T) {
+	svc, _ := newTrashSvc()
+	_, err := svc.EmptyTrash(context.Background(), 1, &dto.VaultTrashEmptyRequest{Vault: "MyVault"}, "", "", "")
+	assert.ErrorIs(t, err, code.ErrorInvalidParams)
+}
@@ -15,8 +15,19 @@ type Manager struct {
 
 // NewManager 创建任务管理器
 func NewManager(logger *zap.Logger, sc *safe_close.SafeClose, appContainer *app.App) *Manager {
+	scheduler := NewScheduler(logger, sc)
+	if appContainer != nil {
+		scheduler.SetPingURLs(appContainer.Config().Task.PingURLs)
+		scheduler.SetMaintenance(appContainer.Maintenance())
+		scheduler.SetSchedules(appContainer.Schedules())
+		scheduler.SetSpecs(appContainer.Config().Task.Schedules)
+		appContainer.OnConfigChange("task", func(cfg *app.AppConfig) {
+			scheduler.SetSpecs(cfg.Task.Schedules)
+			scheduler.Reload()
+		})
+	}
 	return &Manager{
-		scheduler: NewScheduler(logger, sc),
+		scheduler: scheduler,
 		logger:    logger,
 		app:       appContainer,
 	}
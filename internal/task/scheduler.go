@@ -4,7 +4,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/haierkeys/fast-note-sync-service/pkg/healthping"
+	"github.com/haierkeys/fast-note-sync-service/pkg/maintenance"
 	"github.com/haierkeys/fast-note-sync-service/pkg/safe_close"
+	"github.com/haierkeys/fast-note-sync-service/pkg/schedule"
 	"go.uber.org/zap"
 )
 
@@ -16,28 +19,141 @@ type Task interface {
 	IsStartupRun() bool            // 是否立即执行一次
 }
 
-// Scheduler 任务调度器
+// HeavyTask 可选接口，重型任务只在维护窗口内执行，窗口外到期时排队等待下一个窗口
+type HeavyTask interface {
+	IsHeavy() bool // 是否为重型任务
+}
+
+// ReloadableTask 可选接口，配置变更后重新读取设置；执行间隔随之变化时调度器按新间隔重新计时
+type ReloadableTask interface {
+	Reload() error // 重新读取设置，失败时保留原设置
+}
+
+// stopTimeout 关闭时等待运行中任务退出的最长时间
+const stopTimeout = 30 * time.Second
+
+// Scheduler 任务调度器，任务的调度、运行状态与手动触发由 schedule.Scheduler 统一管理
 type Scheduler struct {
-	logger *zap.Logger
-	tasks  []Task
-	sc     *safe_close.SafeClose
+	logger    *zap.Logger
+	tasks     []Task
+	sc        *safe_close.SafeClose
+	pingURLs  map[string]string   // 以任务名为键的失联告警地址
+	schedules *schedule.Scheduler // 统一调度器，与管理接口共享
+
+	maintenance *maintenance.Coordinator // 重型任务的维护窗口
 }
 
 // NewScheduler 创建任务调度器
 func NewScheduler(logger *zap.Logger, sc *safe_close.SafeClose) *Scheduler {
 	return &Scheduler{
-		logger: logger,
-		tasks:  make([]Task, 0),
-		sc:     sc,
+		logger:    logger,
+		tasks:     make([]Task, 0),
+		sc:        sc,
+		schedules: schedule.New(logger),
+	}
+}
+
+// SetPingURLs 设置以任务名为键的失联告警地址（兼容 healthchecks.io）
+func (s *Scheduler) SetPingURLs(urls map[string]string) {
+	s.pingURLs = urls
+}
+
+// SetMaintenance 设置重型任务的维护窗口
+func (s *Scheduler) SetMaintenance(coordinator *maintenance.Coordinator) {
+	s.maintenance = coordinator
+}
+
+// SetSchedules 使用 App Container 中的统一调度器，使管理接口能查看与触发任务；须在添加任务前调用
+func (s *Scheduler) SetSchedules(schedules *schedule.Scheduler) {
+	if schedules != nil {
+		s.schedules = schedules
+	}
+}
+
+// SetSpecs 设置以任务名为键、覆盖默认执行间隔的 cron 表达式，无效的表达式记录日志后忽略
+func (s *Scheduler) SetSpecs(specs map[string]string) {
+	if err := s.schedules.SetSpecs(specs); err != nil {
+		s.logger.Warn("invalid task schedules ignored", zap.Error(err))
+	}
+}
+
+// runTask 执行任务；重型任务在维护窗口外到期时排入队列，由 MaintenanceWindow 任务在窗口开启后执行，手动触发的除外
+func (s *Scheduler) runTask(ctx context.Context, task Task) error {
+	if heavy, ok := task.(HeavyTask); ok && heavy.IsHeavy() {
+		if !s.maintenance.Allow(schedule.Manual(ctx)) {
+			s.maintenance.Defer("task", task.Name(), "Scheduled task "+task.Name(), 0, func(ctx context.Context) error {
+				return s.execTask(ctx, task)
+			})
+			return schedule.ErrDeferred
+		}
+		s.maintenance.Done("task", task.Name())
+	}
+	return s.execTask(ctx, task)
+}
+
+// execTask 执行任务，并在开始、成功与失败时请求任务的失联告警地址
+func (s *Scheduler) execTask(ctx context.Context, task Task) error {
+	pingURL := s.pingURLs[task.Name()]
+	if pingURL == "" {
+		return task.Run(ctx)
+	}
+
+	s.ping(ctx, task, pingURL, healthping.SignalStart, "")
+	err := task.Run(ctx)
+	if err != nil {
+		s.ping(ctx, task, pingURL, healthping.SignalFail, err.Error())
+	} else {
+		s.ping(ctx, task, pingURL, healthping.SignalSuccess, "")
+	}
+	return err
+}
+
+// ping 同步请求失联告警地址，失败只记录日志
+func (s *Scheduler) ping(ctx context.Context, task Task, pingURL string, signal healthping.Signal, message string) {
+	pingCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), healthping.DefaultTimeout)
+	defer cancel()
+	if err := healthping.Ping(pingCtx, nil, pingURL, signal, message); err != nil {
+		s.logger.Warn("task health ping failed", zap.String("name", task.Name()), zap.String("signal", string(signal)), zap.Error(err))
 	}
 }
 
 // AddTask 添加任务
 func (s *Scheduler) AddTask(task Task) {
+	heavy, _ := task.(HeavyTask)
+	err := s.schedules.Add(schedule.Task{
+		Name:       task.Name(),
+		Interval:   task.LoopInterval(),
+		StartupRun: task.IsStartupRun(),
+		Heavy:      heavy != nil && heavy.IsHeavy(),
+		Run: func(ctx context.Context) error {
+			return s.runTask(ctx, task)
+		},
+	})
+	if err != nil {
+		s.logger.Warn("task not added", zap.String("name", task.Name()), zap.Error(err))
+		return
+	}
 	s.tasks = append(s.tasks, task)
 }
 
-// Start 启动所有任务
+// Reload 配置变更后让可重新加载的任务重新读取设置，并按新的执行间隔重新计时
+func (s *Scheduler) Reload() {
+	for _, task := range s.tasks {
+		reloadable, ok := task.(ReloadableTask)
+		if !ok {
+			continue
+		}
+		if err := reloadable.Reload(); err != nil {
+			s.logger.Warn("task reload failed, keeping previous settings", zap.String("name", task.Name()), zap.Error(err))
+			continue
+		}
+		if s.schedules.SetInterval(task.Name(), task.LoopInterval()) {
+			s.logger.Info("task rescheduled", zap.String("name", task.Name()), zap.Duration("loop", task.LoopInterval()))
+		}
+	}
+}
+
+// Start 启动所有任务，收到关闭信号时取消运行中的任务并等待其退出
 func (s *Scheduler) Start() {
 	if len(s.tasks) == 0 {
 		s.logger.Info("no tasks to schedule")
@@ -46,83 +162,16 @@ func (s *Scheduler) Start() {
 
 	s.logger.Info("tasks starting ", zap.Int("count", len(s.tasks)))
 
-	for _, task := range s.tasks {
-		s.startTask(task)
-	}
-}
-
-// startTask 启动单个任务
-func (s *Scheduler) startTask(task Task) {
-
 	s.sc.Attach(func(done func(), closeSignal <-chan struct{}) {
 		defer done()
+		s.schedules.Start()
+		<-closeSignal
 
-		// 如果任务需要立即执行
-		// Use a context derived from closeSignal so the task can respond to shutdown signals.
-		// 使用从 closeSignal 派生的 context，使任务能在关闭时正确退出
-		if task.IsStartupRun() {
-			s.logger.Info("task running", zap.String("name", task.Name()), zap.Bool("startupRun", true))
-			taskCtx, taskCancel := context.WithCancel(context.Background())
-			go func() {
-				// Forward the close signal to the task's context.
-				// 将 closeSignal 转发给任务 context
-				select {
-				case <-closeSignal:
-					taskCancel()
-				case <-taskCtx.Done():
-				}
-			}()
-			go func() {
-				defer taskCancel()
-				defer func() {
-					if r := recover(); r != nil {
-						s.logger.Error("task startupRun panic",
-							zap.String("name", task.Name()),
-							zap.Any("panic", r),
-							zap.Stack("stack"))
-					}
-				}()
-				if err := task.Run(taskCtx); err != nil {
-					s.logger.Error("task running error",
-						zap.String("name", task.Name()),
-						zap.Bool("startupRun", true),
-						zap.Error(err))
-				}
-			}()
-		}
-
-		if task.LoopInterval() <= 0 {
-			return
-		}
-
-		ticker := time.NewTicker(task.LoopInterval())
-		defer ticker.Stop()
-
-		// 定时执行
-		for {
-			select {
-			case <-ticker.C:
-				func() {
-					defer func() {
-						if r := recover(); r != nil {
-							s.logger.Error("task loopRun panic",
-								zap.String("name", task.Name()),
-								zap.Any("panic", r),
-								zap.Stack("stack"))
-						}
-					}()
-					s.logger.Info("task running", zap.String("name", task.Name()), zap.Bool("loopRun", true))
-					if err := task.Run(context.Background()); err != nil {
-						s.logger.Error("task running error",
-							zap.String("name", task.Name()),
-							zap.Bool("loopRun", true),
-							zap.Error(err))
-					}
-				}()
-			case <-closeSignal:
-				s.logger.Info("task stopped", zap.String("name", task.Name()), zap.Bool("loopRun", true))
-				return
-			}
+		ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+		defer cancel()
+		if err := s.schedules.Stop(ctx); err != nil {
+			s.logger.Warn("tasks still running after stop timeout", zap.Error(err))
 		}
+		s.logger.Info("tasks stopped")
 	})
 }
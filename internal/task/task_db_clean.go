@@ -2,6 +2,7 @@ package task
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
@@ -11,11 +12,23 @@ import (
 
 // DbCleanTask 清理任务
 type DbCleanTask struct {
-	app                      *app.App
-	logger                   *zap.Logger
+	app    *app.App
+	logger *zap.Logger
+
+	mu        sync.Mutex
+	retention dbCleanRetention // 管理员修改配置后重新加载
+}
+
+// dbCleanRetention 从配置解析出的保留设置
+type dbCleanRetention struct {
 	retentionDuration        time.Duration
 	syncLogRetentionDuration time.Duration
+	accessLogRetention       time.Duration
+	webhookDeliveryRetention time.Duration
+	auditLogRetention        time.Duration
 	historyKeepVersions      int
+	historyCompact           bool
+	historyKeepDuration      time.Duration
 }
 
 // Name 返回任务名称
@@ -33,10 +46,22 @@ func (t *DbCleanTask) IsStartupRun() bool {
 	return true
 }
 
+// IsHeavy 清理会扫描全部数据，只在维护窗口内执行
+func (t *DbCleanTask) IsHeavy() bool {
+	return true
+}
+
 // Run 执行清理任务
 func (t *DbCleanTask) Run(ctx context.Context) error {
-	// 计算截止时间
-	cutoffTime := time.Now().Add(-t.retentionDuration).UnixMilli()
+	t.mu.Lock()
+	r := t.retention
+	t.mu.Unlock()
+
+	// 计算截止时间，服务器默认永久保留时为 0，此时只按用户与保险库的覆盖值清理
+	var cutoffTime int64
+	if r.retentionDuration > 0 {
+		cutoffTime = time.Now().Add(-r.retentionDuration).UnixMilli()
+	}
 
 	var errs []error
 
@@ -77,21 +102,59 @@ func (t *DbCleanTask) Run(ctx context.Context) error {
 			zap.String("service", "SettingService"))
 	}
 
-	// 清理 NoteHistory
-	if err := t.app.NoteHistoryService.CleanupByTime(ctx, cutoffTime, t.historyKeepVersions); err != nil {
+	if err := t.app.UserSettingService.CleanupByTime(ctx, cutoffTime); err != nil {
 		errs = append(errs, err)
 		t.logger.Error("cleanup failed",
 			zap.String("task", t.Name()),
-			zap.String("service", "NoteHistoryService"),
+			zap.String("service", "UserSettingService"),
 			zap.Error(err))
 	} else {
 		t.logger.Info("cleanup success",
 			zap.String("task", t.Name()),
-			zap.String("service", "NoteHistoryService"))
+			zap.String("service", "UserSettingService"))
+	}
+
+	// 配置了历史版本最长保留时间时按年龄压缩历史记录，不依赖软删除保留时间
+	if r.historyCompact {
+		if err := t.app.NoteHistoryService.Compact(ctx, r.historyKeepDuration, r.historyKeepVersions); err != nil {
+			errs = append(errs, err)
+			t.logger.Error("cleanup failed",
+				zap.String("task", t.Name()),
+				zap.String("service", "NoteHistoryService"),
+				zap.Error(err))
+		} else {
+			t.logger.Info("cleanup success",
+				zap.String("task", t.Name()),
+				zap.String("service", "NoteHistoryService"))
+		}
+	}
+
+	// 服务器默认永久保留时，其余数据均不清理
+	if r.retentionDuration <= 0 {
+		t.app.Dao.CleanupConnections(time.Hour)
+		if len(errs) > 0 {
+			return errs[0]
+		}
+		return nil
+	}
+
+	// 清理 NoteHistory，未配置历史版本最长保留时间时跟随软删除保留时间
+	if !r.historyCompact {
+		if err := t.app.NoteHistoryService.CleanupByTime(ctx, cutoffTime, r.historyKeepVersions); err != nil {
+			errs = append(errs, err)
+			t.logger.Error("cleanup failed",
+				zap.String("task", t.Name()),
+				zap.String("service", "NoteHistoryService"),
+				zap.Error(err))
+		} else {
+			t.logger.Info("cleanup success",
+				zap.String("task", t.Name()),
+				zap.String("service", "NoteHistoryService"))
+		}
 	}
 
 	// 清理 SyncLog
-	syncLogCutoffTime := time.Now().Add(-t.syncLogRetentionDuration).UnixMilli()
+	syncLogCutoffTime := time.Now().Add(-r.syncLogRetentionDuration).UnixMilli()
 	if err := t.app.SyncLogService.CleanupByTime(ctx, syncLogCutoffTime); err != nil {
 		errs = append(errs, err)
 		t.logger.Error("cleanup failed",
@@ -104,6 +167,54 @@ func (t *DbCleanTask) Run(ctx context.Context) error {
 			zap.String("service", "SyncLogService"))
 	}
 
+	// 清理 NoteAccessLog
+	if t.app.NoteAccessService != nil {
+		accessLogCutoffTime := time.Now().Add(-r.accessLogRetention).UnixMilli()
+		if err := t.app.NoteAccessService.CleanupByTime(ctx, accessLogCutoffTime); err != nil {
+			errs = append(errs, err)
+			t.logger.Error("cleanup failed",
+				zap.String("task", t.Name()),
+				zap.String("service", "NoteAccessService"),
+				zap.Error(err))
+		} else {
+			t.logger.Info("cleanup success",
+				zap.String("task", t.Name()),
+				zap.String("service", "NoteAccessService"))
+		}
+	}
+
+	// 清理 WebhookDelivery
+	if t.app.NotificationService != nil {
+		deliveryCutoffTime := time.Now().Add(-r.webhookDeliveryRetention).UnixMilli()
+		if err := t.app.NotificationService.CleanupByTime(ctx, deliveryCutoffTime); err != nil {
+			errs = append(errs, err)
+			t.logger.Error("cleanup failed",
+				zap.String("task", t.Name()),
+				zap.String("service", "NotificationService"),
+				zap.Error(err))
+		} else {
+			t.logger.Info("cleanup success",
+				zap.String("task", t.Name()),
+				zap.String("service", "NotificationService"))
+		}
+	}
+
+	// 清理审计日志，保留时间为 0 时永久保留
+	if t.app.AuditService != nil && r.auditLogRetention > 0 {
+		auditCutoffTime := time.Now().Add(-r.auditLogRetention).UnixMilli()
+		if err := t.app.AuditService.CleanupByTime(ctx, auditCutoffTime); err != nil {
+			errs = append(errs, err)
+			t.logger.Error("cleanup failed",
+				zap.String("task", t.Name()),
+				zap.String("service", "AuditService"),
+				zap.Error(err))
+		} else {
+			t.logger.Info("cleanup success",
+				zap.String("task", t.Name()),
+				zap.String("service", "AuditService"))
+		}
+	}
+
 	// 清理重复记录 (按 Path)
 	if err := t.app.NoteService.CleanDuplicateNotesAll(ctx); err != nil {
 		errs = append(errs, err)
@@ -139,23 +250,46 @@ func (t *DbCleanTask) Run(ctx context.Context) error {
 	return nil
 }
 
+// Reload 管理员修改配置后重新解析保留设置，解析失败时保留原设置
+func (t *DbCleanTask) Reload() error {
+	retention, err := parseDbCleanRetention(t.app.Config())
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	t.retention = retention
+	t.mu.Unlock()
+	return nil
+}
+
 // NewDbCleanTask 创建清理任务
 func NewDbCleanTask(appContainer *app.App) (Task, error) {
-	retentionTimeStr := appContainer.Config().App.SoftDeleteRetentionTime
-	if retentionTimeStr == "" {
-		return nil, nil
-	}
-	duration, err := util.ParseDuration(retentionTimeStr)
+	// 服务器默认永久保留时仍然注册任务，用户与保险库可以覆盖保留时间
+	retention, err := parseDbCleanRetention(appContainer.Config())
 	if err != nil {
 		return nil, err
 	}
 
-	if duration <= 0 {
-		return nil, nil
+	return &DbCleanTask{
+		app:       appContainer,
+		logger:    appContainer.Logger(),
+		retention: retention,
+	}, nil
+}
+
+// parseDbCleanRetention 从配置解析保留设置
+func parseDbCleanRetention(cfg *app.AppConfig) (dbCleanRetention, error) {
+	var duration time.Duration
+	if retentionTimeStr := cfg.App.SoftDeleteRetentionTime; retentionTimeStr != "" {
+		d, err := util.ParseDuration(retentionTimeStr)
+		if err != nil {
+			return dbCleanRetention{}, err
+		}
+		duration = d
 	}
 
 	// 解析同步日志保留时间
-	syncLogRetentionTimeStr := appContainer.Config().App.SyncLogRetentionTime
+	syncLogRetentionTimeStr := cfg.App.SyncLogRetentionTime
 	if syncLogRetentionTimeStr == "" {
 		syncLogRetentionTimeStr = "30d" // Default
 	}
@@ -164,21 +298,52 @@ func NewDbCleanTask(appContainer *app.App) (Task, error) {
 		syncLogDuration = 30 * 24 * time.Hour // Fallback
 	}
 
+	// 解析笔记访问日志保留时间
+	accessLogDuration, err := util.ParseDuration(cfg.App.NoteAccessLogRetentionTime)
+	if err != nil || accessLogDuration <= 0 {
+		accessLogDuration = 90 * 24 * time.Hour // Fallback
+	}
+
+	// 解析 Webhook 投递日志保留时间
+	webhookDeliveryDuration, err := util.ParseDuration(cfg.App.WebhookDeliveryRetentionTime)
+	if err != nil || webhookDeliveryDuration <= 0 {
+		webhookDeliveryDuration = 30 * 24 * time.Hour // Fallback
+	}
+
+	// 解析审计日志保留时间，显式配置 0 表示永久保留
+	auditLogDuration, err := util.ParseDuration(cfg.App.AuditLogRetentionTime)
+	if err != nil || auditLogDuration < 0 {
+		auditLogDuration = 180 * 24 * time.Hour // Fallback
+	}
+
 	// 获取历史记录保留版本数，未配置时默认 10；显式配置 0 表示不做版本数下限保护
 	historyKeepVersions := 10
-	if hv := appContainer.Config().App.HistoryKeepVersions; hv != nil {
+	if hv := cfg.App.HistoryKeepVersions; hv != nil {
 		historyKeepVersions = *hv
 	}
 	if historyKeepVersions < 0 {
 		historyKeepVersions = 10
 	}
 
-	return &DbCleanTask{
-		app:                      appContainer,
-		logger:                   appContainer.Logger(),
+	// 解析历史版本最长保留时间，为空时不压缩历史记录，0 表示只压缩不按年龄删除
+	var historyKeepDuration time.Duration
+	historyKeepDurationStr := cfg.App.HistoryKeepDuration
+	if historyKeepDurationStr != "" {
+		historyKeepDuration, err = util.ParseDuration(historyKeepDurationStr)
+		if err != nil {
+			return dbCleanRetention{}, err
+		}
+	}
+
+	return dbCleanRetention{
 		retentionDuration:        duration,
 		syncLogRetentionDuration: syncLogDuration,
+		accessLogRetention:       accessLogDuration,
+		webhookDeliveryRetention: webhookDeliveryDuration,
+		auditLogRetention:        auditLogDuration,
 		historyKeepVersions:      historyKeepVersions,
+		historyCompact:           historyKeepDurationStr != "",
+		historyKeepDuration:      historyKeepDuration,
 	}, nil
 }
 
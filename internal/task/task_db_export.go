@@ -0,0 +1,95 @@
+package task
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+// DatabaseExportTask refreshes the hot export of the SQLite databases for host backup tools.
+// It is not a heavy task: a stale export defeats its purpose, so it is never deferred to the maintenance window.
+// DatabaseExportTask 定时刷新供主机备份工具使用的 SQLite 数据库热导出。
+// 过期的导出失去意义，因此不是重型任务，不会推迟到维护窗口执行。
+type DatabaseExportTask struct {
+	app    *app.App
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	interval time.Duration
+}
+
+// Name returns the task name
+func (t *DatabaseExportTask) Name() string {
+	return "DatabaseExport"
+}
+
+// LoopInterval returns the configured export interval
+func (t *DatabaseExportTask) LoopInterval() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.interval
+}
+
+// Reload re-reads the export interval after the configuration changed, 0 pauses the exports
+func (t *DatabaseExportTask) Reload() error {
+	interval, err := databaseExportInterval(t.app.Config())
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	t.interval = interval
+	t.mu.Unlock()
+	return nil
+}
+
+// IsStartupRun returns whether to run on startup, so the export is fresh right after an upgrade or restore
+func (t *DatabaseExportTask) IsStartupRun() bool {
+	return true
+}
+
+// Run refreshes the export
+func (t *DatabaseExportTask) Run(ctx context.Context) error {
+	_, err := t.app.SnapshotService.ExportDatabases(ctx)
+	return err
+}
+
+// NewDatabaseExportTask creates a new DatabaseExportTask instance, returns nil when scheduled exports are disabled
+func NewDatabaseExportTask(appContainer *app.App) (Task, error) {
+	interval, err := databaseExportInterval(appContainer.Config())
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		return nil, nil
+	}
+
+	return &DatabaseExportTask{
+		app:      appContainer,
+		logger:   appContainer.Logger(),
+		interval: interval,
+	}, nil
+}
+
+// databaseExportInterval returns the configured export interval, 0 when scheduled exports are disabled
+func databaseExportInterval(appConfig *app.AppConfig) (time.Duration, error) {
+	cfg := appConfig.Snapshot.DatabaseExport
+	if !cfg.IsEnabled || cfg.Interval == "" {
+		return 0, nil
+	}
+	interval, err := util.ParseDuration(cfg.Interval)
+	if err != nil {
+		return 0, err
+	}
+	return max(interval, 0), nil
+}
+
+// init registers the database export task
+func init() {
+	RegisterWithApp(func(appContainer *app.App) (Task, error) {
+		return NewDatabaseExportTask(appContainer)
+	})
+}
@@ -0,0 +1,70 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"go.uber.org/zap"
+)
+
+// FileGCTask removes attachment folders left without a file record once they pass the grace period
+// FileGCTask 删除超过宽限期且没有文件记录的附件目录
+type FileGCTask struct {
+	app    *app.App
+	logger *zap.Logger
+}
+
+// Name returns the task name
+func (t *FileGCTask) Name() string {
+	return "FileGC"
+}
+
+// LoopInterval returns the execution interval (once a day)
+func (t *FileGCTask) LoopInterval() time.Duration {
+	return 24 * time.Hour
+}
+
+// IsStartupRun returns whether to run on startup
+func (t *FileGCTask) IsStartupRun() bool {
+	return false
+}
+
+// IsHeavy returns true, the collection walks every attachment folder and waits for the maintenance window
+func (t *FileGCTask) IsHeavy() bool {
+	return true
+}
+
+// Run executes the garbage collection for every user
+func (t *FileGCTask) Run(ctx context.Context) error {
+	if t.app.FileGCService == nil {
+		return nil
+	}
+	report, err := t.app.FileGCService.Run(ctx, 0, false)
+	if err != nil {
+		return err
+	}
+	t.logger.Info("task log",
+		zap.String("task", t.Name()),
+		zap.Int("users", report.Users),
+		zap.Int("orphans", report.Orphans),
+		zap.Int64("orphanSize", report.OrphanSize),
+		zap.Int("removed", report.Removed),
+		zap.Int64("removedSize", report.RemovedSize))
+	return nil
+}
+
+// NewFileGCTask creates a new FileGCTask instance
+func NewFileGCTask(appContainer *app.App) (Task, error) {
+	return &FileGCTask{
+		app:    appContainer,
+		logger: appContainer.Logger(),
+	}, nil
+}
+
+// init registers the attachment garbage collection task
+func init() {
+	RegisterWithApp(func(appContainer *app.App) (Task, error) {
+		return NewFileGCTask(appContainer)
+	})
+}
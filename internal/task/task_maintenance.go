@@ -0,0 +1,57 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"go.uber.org/zap"
+)
+
+// MaintenanceTask runs the heavy jobs queued for the maintenance window once it opens
+// MaintenanceTask 在维护窗口开启后执行排队的重型任务
+type MaintenanceTask struct {
+	app    *app.App
+	logger *zap.Logger
+}
+
+// Name returns the task name
+func (t *MaintenanceTask) Name() string {
+	return "MaintenanceWindow"
+}
+
+// LoopInterval returns the execution interval (every minute)
+func (t *MaintenanceTask) LoopInterval() time.Duration {
+	return 1 * time.Minute
+}
+
+// IsStartupRun returns whether to run on startup
+func (t *MaintenanceTask) IsStartupRun() bool {
+	return false
+}
+
+// Run executes the queued jobs when the window is open
+func (t *MaintenanceTask) Run(ctx context.Context) error {
+	if n := t.app.Maintenance().RunDue(ctx); n > 0 {
+		t.logger.Info("maintenance window jobs finished", zap.Int("count", n))
+	}
+	return nil
+}
+
+// NewMaintenanceTask creates a new MaintenanceTask instance, returns nil when no maintenance window is configured
+func NewMaintenanceTask(appContainer *app.App) (Task, error) {
+	if !appContainer.Maintenance().Enabled() {
+		return nil, nil
+	}
+	return &MaintenanceTask{
+		app:    appContainer,
+		logger: appContainer.Logger(),
+	}, nil
+}
+
+// init registers the maintenance window task
+func init() {
+	RegisterWithApp(func(appContainer *app.App) (Task, error) {
+		return NewMaintenanceTask(appContainer)
+	})
+}
@@ -2,6 +2,7 @@ package task
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"sync"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/haierkeys/fast-note-sync-service/internal/app"
 	"github.com/haierkeys/fast-note-sync-service/internal/service"
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"github.com/haierkeys/fast-note-sync-service/pkg/util"
 	"go.uber.org/zap"
 )
@@ -126,8 +128,18 @@ func (t *NoteHistoryTask) handleNoteHistoryProcess(noteID, uid int64, key string
 		return
 	}
 
-	// 使用 App Container 中的 NoteHistoryService
-	ctx := context.Background()
+	// 通过任务队列执行，失败时重试并可在管理接口中查看
+	process := func(ctx context.Context) error {
+		return t.processNoteHistory(ctx, noteID, uid)
+	}
+	if jobs := t.app.Jobs(); jobs != nil && jobs.Enqueue("note.history", key, uid, process) == nil {
+		return
+	}
+	_ = process(context.Background())
+}
+
+// processNoteHistory 使用 App Container 中的 NoteHistoryService 保存历史并记录结果
+func (t *NoteHistoryTask) processNoteHistory(ctx context.Context, noteID, uid int64) error {
 	err := t.app.NoteHistoryService.ProcessDelay(ctx, noteID, uid)
 	if err != nil {
 		t.logger.Error("task log",
@@ -146,6 +158,11 @@ func (t *NoteHistoryTask) handleNoteHistoryProcess(noteID, uid int64, key string
 			zap.Int64("uid", uid),
 			zap.String("msg", "success"))
 	}
+	// 笔记已删除时重试无意义
+	if errors.Is(err, code.ErrorNoteNotFound) {
+		return nil
+	}
+	return err
 }
 
 // handleNoteRenameMigrate 处理笔记重命名迁移
@@ -196,6 +213,28 @@ func (t *NoteHistoryTask) handleNoteRenameMigrate(oldNoteID, newNoteID, uid int6
 			zap.String("event", "processMigrate success"),
 			zap.String("msg", "success"))
 	}
+
+	err = t.app.NoteMetaService.Migrate(ctx, oldNoteID, newNoteID, uid)
+	if err != nil {
+		t.logger.Error("task log",
+			zap.String("task", "NoteHistory"),
+			zap.String("type", "startupRun"),
+			zap.Int64("oldNoteID", oldNoteID),
+			zap.Int64("newNoteID", newNoteID),
+			zap.Int64("uid", uid),
+			zap.String("reason", "NoteMetaMigrate failed"),
+			zap.String("msg", "failed"),
+			zap.Error(err))
+	} else {
+		t.logger.Info("task log",
+			zap.String("task", "NoteHistory"),
+			zap.String("type", "startupRun"),
+			zap.Int64("oldNoteID", oldNoteID),
+			zap.Int64("newNoteID", newNoteID),
+			zap.Int64("uid", uid),
+			zap.String("event", "NoteMetaMigrate success"),
+			zap.String("msg", "success"))
+	}
 }
 
 // resumeTasks 扫描并恢复中断的任务
@@ -0,0 +1,59 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"go.uber.org/zap"
+)
+
+// NotePolicyTask runs the note lifecycle policies (auto-archive / auto-delete)
+// NotePolicyTask 运行笔记生命周期策略（自动归档 / 自动删除）
+type NotePolicyTask struct {
+	app    *app.App
+	logger *zap.Logger
+}
+
+// Name returns the task name
+func (t *NotePolicyTask) Name() string {
+	return "NotePolicy"
+}
+
+// LoopInterval returns the execution interval (every hour, each policy runs at most once a day)
+func (t *NotePolicyTask) LoopInterval() time.Duration {
+	return 1 * time.Hour
+}
+
+// IsStartupRun returns whether to run on startup
+func (t *NotePolicyTask) IsStartupRun() bool {
+	return false
+}
+
+// IsHeavy returns true, policies scan whole vaults and move notes in bulk, so they wait for the maintenance window
+func (t *NotePolicyTask) IsHeavy() bool {
+	return true
+}
+
+// Run executes the due policies
+func (t *NotePolicyTask) Run(ctx context.Context) error {
+	if t.app.NotePolicyService == nil {
+		return nil
+	}
+	return t.app.NotePolicyService.ExecuteScheduled(ctx)
+}
+
+// NewNotePolicyTask creates a new NotePolicyTask instance
+func NewNotePolicyTask(appContainer *app.App) (Task, error) {
+	return &NotePolicyTask{
+		app:    appContainer,
+		logger: appContainer.Logger(),
+	}, nil
+}
+
+// init registers the note policy task
+func init() {
+	RegisterWithApp(func(appContainer *app.App) (Task, error) {
+		return NewNotePolicyTask(appContainer)
+	})
+}
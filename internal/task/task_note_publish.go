@@ -0,0 +1,59 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"go.uber.org/zap"
+)
+
+// NotePublishTask publishes the notes scheduled through their frontmatter (publish / publish_at)
+// NotePublishTask 发布通过 frontmatter（publish / publish_at）计划发布的笔记
+type NotePublishTask struct {
+	app    *app.App
+	logger *zap.Logger
+}
+
+// Name returns the task name
+func (t *NotePublishTask) Name() string {
+	return "NotePublish"
+}
+
+// LoopInterval returns the execution interval (every minute, so publish_at is honoured to the minute)
+func (t *NotePublishTask) LoopInterval() time.Duration {
+	return 1 * time.Minute
+}
+
+// IsStartupRun returns whether to run on startup
+func (t *NotePublishTask) IsStartupRun() bool {
+	return true
+}
+
+// IsHeavy returns false, after the first run only the notes changed since the previous run are read
+func (t *NotePublishTask) IsHeavy() bool {
+	return false
+}
+
+// Run picks up frontmatter changes and publishes the due notes
+func (t *NotePublishTask) Run(ctx context.Context) error {
+	if t.app.NotePublishService == nil {
+		return nil
+	}
+	return t.app.NotePublishService.ExecuteScheduled(ctx)
+}
+
+// NewNotePublishTask creates a new NotePublishTask instance
+func NewNotePublishTask(appContainer *app.App) (Task, error) {
+	return &NotePublishTask{
+		app:    appContainer,
+		logger: appContainer.Logger(),
+	}, nil
+}
+
+// init registers the note publish task
+func init() {
+	RegisterWithApp(func(appContainer *app.App) (Task, error) {
+		return NewNotePublishTask(appContainer)
+	})
+}
@@ -0,0 +1,72 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/internal/app"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+	"go.uber.org/zap"
+)
+
+// SnapshotTask takes scheduled local snapshots of the databases and content folders
+// SnapshotTask 定时对数据库与内容目录做本地快照
+type SnapshotTask struct {
+	app      *app.App
+	logger   *zap.Logger
+	interval time.Duration
+}
+
+// Name returns the task name
+func (t *SnapshotTask) Name() string {
+	return "LocalSnapshot"
+}
+
+// LoopInterval returns the configured snapshot interval
+func (t *SnapshotTask) LoopInterval() time.Duration {
+	return t.interval
+}
+
+// IsStartupRun returns whether to run on startup
+func (t *SnapshotTask) IsStartupRun() bool {
+	return false
+}
+
+// IsHeavy returns true, snapshots copy every database and content folder and wait for the maintenance window
+func (t *SnapshotTask) IsHeavy() bool {
+	return true
+}
+
+// Run takes a snapshot
+func (t *SnapshotTask) Run(ctx context.Context) error {
+	_, err := t.app.SnapshotService.Create(ctx)
+	return err
+}
+
+// NewSnapshotTask creates a new SnapshotTask instance, returns nil when scheduled snapshots are disabled
+func NewSnapshotTask(appContainer *app.App) (Task, error) {
+	cfg := appContainer.Config().Snapshot
+	if !cfg.IsEnabled || cfg.Interval == "" {
+		return nil, nil
+	}
+	interval, err := util.ParseDuration(cfg.Interval)
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		return nil, nil
+	}
+
+	return &SnapshotTask{
+		app:      appContainer,
+		logger:   appContainer.Logger(),
+		interval: interval,
+	}, nil
+}
+
+// init registers the snapshot task
+func init() {
+	RegisterWithApp(func(appContainer *app.App) (Task, error) {
+		return NewSnapshotTask(appContainer)
+	})
+}
@@ -0,0 +1,175 @@
+// Package alert sends short human-readable alerts through email, Telegram, Gotify and ntfy
+// Package alert 通过邮件、Telegram、Gotify 与 ntfy 发送简短的可读告警
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/email"
+)
+
+// Channel types
+// 渠道类型
+const (
+	TypeEmail    = "email"
+	TypeTelegram = "telegram"
+	TypeGotify   = "gotify"
+	TypeNtfy     = "ntfy"
+)
+
+// Types all supported channel types
+// Types 支持的全部渠道类型
+var Types = []string{TypeEmail, TypeTelegram, TypeGotify, TypeNtfy}
+
+// DefaultTimeout timeout of a single alert
+// DefaultTimeout 单次告警的超时时间
+const DefaultTimeout = 15 * time.Second
+
+// ErrInvalidConfig is wrapped by every configuration error returned by New
+// ErrInvalidConfig 包装 New 返回的所有配置错误
+var ErrInvalidConfig = errors.New("alert: invalid channel config")
+
+// Message an alert
+// Message 一条告警
+type Message struct {
+	Title string // Short summary, used as subject // 简短摘要，用作标题
+	Body  string // Plain text details // 纯文本详情
+}
+
+// Channel delivers alerts to one destination
+// Channel 向一个目标投递告警
+type Channel interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config destination of a channel; which fields are used depends on Type
+// Config 渠道目标，使用哪些字段取决于 Type
+type Config struct {
+	Type     string // email, telegram, gotify or ntfy // email、telegram、gotify 或 ntfy
+	Name     string // Display name // 显示名称
+	To       string // email: comma separated recipients // email：逗号分隔的收件人
+	URL      string // gotify: server URL; ntfy: topic URL; telegram: optional Bot API root // gotify：服务器地址；ntfy：主题地址；telegram：可选的 Bot API 根地址
+	Token    string // telegram: bot token; gotify: application token; ntfy: optional access token // telegram：机器人令牌；gotify：应用令牌；ntfy：可选访问令牌
+	ChatID   string // telegram: chat, group or channel ID // telegram：会话、群组或频道 ID
+	Priority int    // gotify (0-10) and ntfy (1-5) priority, 0 uses the server default // gotify（0-10）与 ntfy（1-5）优先级，0 使用服务器默认值
+}
+
+// SMTPConfig mail server used by email channels
+// SMTPConfig email 渠道使用的邮件服务器
+type SMTPConfig struct {
+	Host               string // SMTP host, empty disables email channels // SMTP 主机，为空时禁用 email 渠道
+	Port               int    // SMTP port, 465 uses implicit TLS // SMTP 端口，465 使用隐式 TLS
+	Username           string // Login user // 登录用户名
+	Password           string // Login password // 登录密码
+	From               string // Sender address // 发件人地址
+	InsecureSkipVerify bool   // Skip TLS certificate verification // 跳过 TLS 证书校验
+}
+
+// Enabled reports whether a mail server is configured
+// Enabled 判断是否配置了邮件服务器
+func (c SMTPConfig) Enabled() bool {
+	return c.Host != "" && c.From != ""
+}
+
+// SendMail sends an HTML email through the server, shared by the email channels and other server mails
+// SendMail 通过该服务器发送 HTML 邮件，供 email 渠道与其他服务端邮件共用
+func (c SMTPConfig) SendMail(to []string, subject, body string) error {
+	return email.NewEmail(&email.SMTPInfo{
+		Host: c.Host,
+		Port: c.Port,
+		// SMTPInfo.IsSSL only switches off certificate verification
+		// SMTPInfo.IsSSL 仅用于关闭证书校验
+		IsSSL:    c.InsecureSkipVerify,
+		UserName: c.Username,
+		Password: c.Password,
+		From:     c.From,
+	}).SendMail(to, subject, body)
+}
+
+// New validates cfg and creates its channel. smtp is only used by email channels, client by the others
+// (nil uses a client with DefaultTimeout).
+// New 校验 cfg 并创建对应渠道。smtp 仅用于 email 渠道，client 用于其他渠道（为 nil 时使用 DefaultTimeout 的客户端）。
+func New(cfg Config, smtp SMTPConfig, client *http.Client) (Channel, error) {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	switch cfg.Type {
+	case TypeEmail:
+		if !smtp.Enabled() {
+			return nil, fmt.Errorf("%w: no SMTP server is configured", ErrInvalidConfig)
+		}
+		to, err := parseRecipients(cfg.To)
+		if err != nil {
+			return nil, err
+		}
+		return &emailChannel{smtp: smtp, to: to}, nil
+	case TypeTelegram:
+		if cfg.Token == "" || cfg.ChatID == "" {
+			return nil, fmt.Errorf("%w: telegram needs a bot token and a chat ID", ErrInvalidConfig)
+		}
+		api := "https://api.telegram.org"
+		if cfg.URL != "" {
+			if err := checkURL(cfg.URL); err != nil {
+				return nil, err
+			}
+			api = strings.TrimRight(cfg.URL, "/")
+		}
+		return &telegramChannel{client: client, api: api, token: cfg.Token, chatID: cfg.ChatID}, nil
+	case TypeGotify:
+		if err := checkURL(cfg.URL); err != nil {
+			return nil, err
+		}
+		if cfg.Token == "" {
+			return nil, fmt.Errorf("%w: gotify needs an application token", ErrInvalidConfig)
+		}
+		return &gotifyChannel{client: client, url: strings.TrimRight(cfg.URL, "/"), token: cfg.Token, priority: cfg.Priority}, nil
+	case TypeNtfy:
+		if err := checkURL(cfg.URL); err != nil {
+			return nil, err
+		}
+		if cfg.Priority < 0 || cfg.Priority > 5 {
+			return nil, fmt.Errorf("%w: ntfy priority must be between 1 and 5", ErrInvalidConfig)
+		}
+		return &ntfyChannel{client: client, url: cfg.URL, token: cfg.Token, priority: cfg.Priority}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidConfig, cfg.Type)
+	}
+}
+
+// checkURL requires an absolute http(s) URL
+// checkURL 要求绝对的 http(s) 地址
+func checkURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %q is not an http(s) URL", ErrInvalidConfig, raw)
+	}
+	return nil
+}
+
+// parseRecipients splits and validates a comma separated recipient list
+// parseRecipients 拆分并校验逗号分隔的收件人列表
+func parseRecipients(raw string) ([]string, error) {
+	var to []string
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		addr, err := mail.ParseAddress(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid recipient %q", ErrInvalidConfig, part)
+		}
+		to = append(to, addr.Address)
+	}
+	if len(to) == 0 {
+		return nil, fmt.Errorf("%w: email needs at least one recipient", ErrInvalidConfig)
+	}
+	return to, nil
+}
@@ -0,0 +1,101 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_Validation(t *testing.T) {
+	smtp := SMTPConfig{Host: "smtp.example.com", Port: 587, From: "fns@example.com"}
+	for name, cfg := range map[string]Config{
+		"unknown type":       {Type: "pager"},
+		"email without smtp": {Type: TypeEmail, To: "a@example.com"},
+		"telegram no chat":   {Type: TypeTelegram, Token: "t"},
+		"gotify no token":    {Type: TypeGotify, URL: "https://gotify.example.com"},
+		"ntfy bad url":       {Type: TypeNtfy, URL: "ntfy.sh/topic"},
+		"ntfy bad priority":  {Type: TypeNtfy, URL: "https://ntfy.sh/topic", Priority: 8},
+	} {
+		s := smtp
+		if name == "email without smtp" {
+			s = SMTPConfig{}
+		}
+		_, err := New(cfg, s, nil)
+		assert.ErrorIs(t, err, ErrInvalidConfig, name)
+	}
+
+	_, err := New(Config{Type: TypeEmail, To: "a@example.com, not-an-address"}, smtp, nil)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	ch, err := New(Config{Type: TypeEmail, To: "Ops <ops@example.com>, b@example.com"}, smtp, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ops@example.com", "b@example.com"}, ch.(*emailChannel).to)
+}
+
+func TestTelegram(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/bot123:abc/sendMessage", r.URL.Path)
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	ch, err := New(Config{Type: TypeTelegram, URL: server.URL, Token: "123:abc", ChatID: "-100"}, SMTPConfig{}, nil)
+	require.NoError(t, err)
+	require.NoError(t, ch.Send(context.Background(), Message{Title: "Backup failed", Body: "details"}))
+	assert.Equal(t, "-100", got["chat_id"])
+	assert.Equal(t, "Backup failed\n\ndetails", got["text"])
+}
+
+func TestTelegram_ErrorHidesToken(t *testing.T) {
+	ch, err := New(Config{Type: TypeTelegram, URL: "http://127.0.0.1:1", Token: "secret-token", ChatID: "1"}, SMTPConfig{}, nil)
+	require.NoError(t, err)
+	err = ch.Send(context.Background(), Message{Title: "x"})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-token")
+}
+
+func TestGotify(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/message", r.URL.Path)
+		assert.Equal(t, "app-token", r.Header.Get("X-Gotify-Key"))
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+
+	ch, err := New(Config{Type: TypeGotify, URL: server.URL + "/", Token: "app-token", Priority: 8}, SMTPConfig{}, nil)
+	require.NoError(t, err)
+	require.NoError(t, ch.Send(context.Background(), Message{Title: "Backup failed", Body: "details"}))
+	assert.Equal(t, "Backup failed", got["title"])
+	assert.Equal(t, float64(8), got["priority"])
+}
+
+func TestNtfy(t *testing.T) {
+	var title, body, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		title, auth = r.Header.Get("Title"), r.Header.Get("Authorization")
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer server.Close()
+
+	ch, err := New(Config{Type: TypeNtfy, URL: server.URL + "/fns", Token: "tk"}, SMTPConfig{}, nil)
+	require.NoError(t, err)
+	require.NoError(t, ch.Send(context.Background(), Message{Title: "备份失败", Body: "details"}))
+	assert.Equal(t, "=?UTF-8?b?5aSH5Lu95aSx6LSl?=", title)
+	assert.Equal(t, "details", body)
+	assert.Equal(t, "Bearer tk", auth)
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	assert.ErrorContains(t, ch.Send(context.Background(), Message{Title: "x"}), "403")
+}
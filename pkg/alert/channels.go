@@ -0,0 +1,152 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// emailChannel sends alerts by mail through the configured SMTP server
+// emailChannel 通过配置的 SMTP 服务器以邮件发送告警
+type emailChannel struct {
+	smtp SMTPConfig
+	to   []string
+}
+
+func (c *emailChannel) Send(ctx context.Context, msg Message) error {
+	// The mailer sends HTML, keep the plain text layout
+	// 邮件以 HTML 发送，保留纯文本排版
+	body := "<pre>" + html.EscapeString(msg.Body) + "</pre>"
+	return c.smtp.SendMail(c.to, msg.Title, body)
+}
+
+// telegramChannel sends alerts through a Telegram bot
+// telegramChannel 通过 Telegram 机器人发送告警
+type telegramChannel struct {
+	client *http.Client
+	api    string
+	token  string
+	chatID string
+}
+
+func (c *telegramChannel) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(map[string]any{
+		"chat_id":                  c.chatID,
+		"text":                     msg.Title + "\n\n" + msg.Body,
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.api+"/bot"+c.token+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// The request URL carries the bot token, never return it in the error
+		// 请求地址包含机器人令牌，错误中不能返回该地址
+		return fmt.Errorf("alert: telegram request failed: %w", unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&result)
+	if resp.StatusCode != http.StatusOK || !result.OK {
+		return fmt.Errorf("alert: telegram responded %s: %s", resp.Status, result.Description)
+	}
+	return nil
+}
+
+// gotifyChannel sends alerts to a Gotify server
+// gotifyChannel 向 Gotify 服务器发送告警
+type gotifyChannel struct {
+	client   *http.Client
+	url      string
+	token    string
+	priority int
+}
+
+func (c *gotifyChannel) Send(ctx context.Context, msg Message) error {
+	payload := map[string]any{"title": msg.Title, "message": msg.Body}
+	if c.priority > 0 {
+		payload["priority"] = c.priority
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+"/message", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", c.token)
+	return do(c.client, req, "gotify")
+}
+
+// ntfyChannel publishes alerts to an ntfy topic
+// ntfyChannel 向 ntfy 主题发布告警
+type ntfyChannel struct {
+	client   *http.Client
+	url      string
+	token    string
+	priority int
+}
+
+func (c *ntfyChannel) Send(ctx context.Context, msg Message) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, strings.NewReader(msg.Body))
+	if err != nil {
+		return err
+	}
+	// Header values must be ASCII, ntfy decodes RFC 2047 encoded titles
+	// 请求头只能是 ASCII，ntfy 会解码 RFC 2047 编码的标题
+	req.Header.Set("Title", mime.BEncoding.Encode("UTF-8", msg.Title))
+	req.Header.Set("Tags", "warning")
+	if c.priority > 0 {
+		req.Header.Set("Priority", strconv.Itoa(c.priority))
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return do(c.client, req, "ntfy")
+}
+
+// do sends req and turns non-2xx responses into errors
+// do 发送 req，并将非 2xx 响应转换为错误
+func do(client *http.Client, req *http.Request, service string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("alert: %s request failed: %w", service, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alert: %s responded %s", service, resp.Status)
+	}
+	return nil
+}
+
+// unwrapURLError drops the request URL from a transport error
+// unwrapURLError 去除传输错误中的请求地址
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
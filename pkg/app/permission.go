@@ -131,3 +131,19 @@ func MatchWildcard(pattern, value string) bool {
 func Is3DRBACScope(scope string) bool {
 	return strings.Contains(scope, "p:") || strings.Contains(scope, "c:") || strings.Contains(scope, "f:")
 }
+
+// ScopeFunctionTOTPSetup function of the login token issued to an administrator who must enroll TOTP first,
+// the token only reaches the TOTP enrollment routes
+// ScopeFunctionTOTPSetup 需要先设置 TOTP 的管理员登录时所获令牌的功能，该令牌只能访问 TOTP 设置接口
+const ScopeFunctionTOTPSetup = "totp_setup"
+
+// IsTOTPSetupScope checks if the scope is limited to TOTP enrollment
+// IsTOTPSetupScope 检查作用域是否仅限 TOTP 设置
+func IsTOTPSetupScope(scope string) bool {
+	for _, part := range strings.Fields(scope) {
+		if strings.HasPrefix(part, "f:") && strings.EqualFold(part[2:], ScopeFunctionTOTPSetup) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/atrest"
+)
+
+// AttachmentCacheControl Cache-Control of attachment downloads. Attachments are revalidated with their
+// ETag, which is derived from the content, so a year-long max-age never serves stale content for long.
+// AttachmentCacheControl 附件下载的 Cache-Control。附件通过基于内容生成的 ETag 重新验证，
+// 因此一年的 max-age 不会长期返回过期内容。
+const AttachmentCacheControl = "public, s-maxage=31536000, max-age=31536000, must-revalidate"
+
+// QuoteETag returns etag as a strong entity tag. net/http only matches If-None-Match and If-Range
+// against quoted tags, a bare content hash would turn every conditional or resumed request into a full download.
+// QuoteETag 将 etag 转换为强实体标签。net/http 只会将 If-None-Match 与 If-Range 与带引号的标签比较，
+// 直接使用内容哈希会让所有条件请求与续传请求退化为完整下载。
+func QuoteETag(etag string) string {
+	if etag == "" || strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + strings.ReplaceAll(etag, `"`, "") + `"`
+}
+
+// ServeFile streams the file at path without loading it into memory. Range requests are answered with
+// 206 Partial Content so audio and video can be scrubbed and interrupted downloads resumed,
+// and If-None-Match, If-Modified-Since and If-Range are honored. Empty contentType, etag and cacheControl are skipped.
+// An error is returned, before anything is written, when the file cannot be opened.
+// ServeFile 以流式方式输出 path 处的文件，不会将其整体读入内存。Range 请求返回 206 Partial Content，
+// 使音视频可以拖动进度、中断的下载可以续传，并支持 If-None-Match、If-Modified-Since 与 If-Range。
+// contentType、etag 与 cacheControl 为空时不设置；文件无法打开时在写入任何内容之前返回错误。
+func ServeFile(c *gin.Context, path, name, contentType, etag string, modTime time.Time, cacheControl string) error {
+	file, err := atrest.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if contentType != "" {
+		c.Header("Content-Type", contentType)
+	}
+	if cacheControl != "" {
+		c.Header("Cache-Control", cacheControl)
+	}
+	if etag != "" {
+		c.Header("ETag", QuoteETag(etag))
+	}
+
+	http.ServeContent(c.Writer, c.Request, name, modTime, file)
+	return nil
+}
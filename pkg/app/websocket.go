@@ -11,10 +11,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/haierkeys/fast-note-sync-service/pkg/cluster"
 	"github.com/haierkeys/fast-note-sync-service/pkg/code"
 	"github.com/haierkeys/fast-note-sync-service/pkg/json"
+	"github.com/haierkeys/fast-note-sync-service/pkg/limiter"
 	"github.com/haierkeys/fast-note-sync-service/pkg/logger"
-	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
+	"github.com/haierkeys/fast-note-sync-service/pkg/synctrace"
 	"github.com/haierkeys/fast-note-sync-service/pkg/timex"
 	"golang.org/x/sync/singleflight"
 
@@ -235,6 +237,33 @@ type WSConfig struct {
 	// 调用方已在配置层解析好 nil 与显式 0 的区别，这里 0 就表示"不设超时"（旧行为），
 	// 不会再被内部默认值覆盖。
 	WriteTimeout time.Duration
+	// UserLimiter per-uid inbound message limiter shared by all of a user's connections, nil disables
+	// UserLimiter 按用户的入站消息限流器，由该用户所有连接共享，nil 表示不限制
+	UserLimiter *limiter.KeyedLimiter
+	// ConnLimiter per-connection inbound message limiter, nil disables
+	// ConnLimiter 按连接的入站消息限流器，nil 表示不限制
+	ConnLimiter *limiter.KeyedLimiter
+	// SyncJournalSize sync events queued per user for SyncResume, 0 uses DefaultSyncJournalSize
+	// SyncJournalSize 每个用户为 SyncResume 排队的同步事件数，0 表示使用 DefaultSyncJournalSize
+	SyncJournalSize int
+	// SyncJournalTTL how long queued events and idle device cursors are kept, 0 uses DefaultSyncJournalTTL
+	// SyncJournalTTL 排队事件与空闲设备游标的保留时长，0 表示使用 DefaultSyncJournalTTL
+	SyncJournalTTL time.Duration
+	// MsgpackEnabled lets clients switch to MessagePack with the "protocol=msgpack" handshake query
+	// MsgpackEnabled 允许客户端通过握手 query "protocol=msgpack" 切换为 MessagePack
+	MsgpackEnabled bool
+	// MaxConnections connections accepted in total, 0 means unlimited
+	// MaxConnections 总共接受的连接数，0 表示不限制
+	MaxConnections int
+	// MaxConnectionsPerUser authenticated connections per user, 0 means unlimited
+	// MaxConnectionsPerUser 每个用户已认证的连接数，0 表示不限制
+	MaxConnectionsPerUser int
+	// AuthTimeout closes connections that did not authorize in time, 0 disables
+	// AuthTimeout 关闭未在该时长内完成鉴权的连接，0 表示不限制
+	AuthTimeout time.Duration
+	// SendQueueSize outbound frames queued per connection before it is closed as a slow consumer, 0 uses DefaultSendQueueSize
+	// SendQueueSize 每个连接排队的出站帧数，超出后作为慢消费者关闭，0 表示使用 DefaultSendQueueSize
+	SendQueueSize int
 }
 
 // SessionCleaner interface, used to clean up session resources when the connection is disconnected
@@ -285,21 +314,27 @@ type WebsocketClient struct {
 	clientVersion       string                    // Client version number (e.g., "1.2.4"); access via ClientVersion() // 客户端版本号；请通过 ClientVersion() 访问
 	offlineSyncStrategy string                    // Offline device sync strategy "newTimeMerge" | "ignoreTimeMerge"; access via OfflineSyncStrategy() // 离线设备同步策略；请通过 OfflineSyncStrategy() 访问
 	useProtobuf         bool                      // Whether to use protobuf protocol; access via UseProtobuf() // 是否使用 protobuf 协议；请通过 UseProtobuf() 访问
+	useMsgpack          bool                      // Whether to use MessagePack; access via UseMsgpack() // 是否使用 MessagePack；请通过 UseMsgpack() 访问
+	broadcastFilter     BroadcastFilter           // Decides which broadcasts reach this connection, nil accepts all; set via SetBroadcastFilter() // 决定哪些广播发送到该连接，为 nil 时全部接收；请通过 SetBroadcastFilter() 设置
 	StartTime           timex.Time                // Connection start time // 连接开始时间
 	IsFirstSync         bool                      // Whether it's the first sync // 是否是第一次同步过
 	DiffMergePaths      map[string]DiffMergeEntry // File paths needing merging // 需要合并的文件路径，包含创建时间用于超时清理
 	DiffMergePathsMu    sync.RWMutex              // Mutex lock to prevent concurrency conflicts // 互斥锁，防止并发冲突
 	failCount           atomic.Int32              // Consecutive broadcast failure counter; connection closed when exceeding threshold // 连续广播失败计数器，超过阈值时主动关闭连接
 	lastPongAt          atomic.Int64                    // Unix timestamp of last received pong; used to detect zombie connections // 最后一次收到 pong 的 Unix 时间戳，用于检测僵尸连接
+	outbox              chan outboundFrame        // Bounded send queue drained by writeLoop, nil writes directly // 由 writeLoop 写出的有界发送队列，为 nil 时直接写入
+	slowConsumer        atomic.Bool               // Set once the send queue overflowed; queued frames are dropped until the connection closes // 发送队列溢出后置位，连接关闭前排队的帧均被丢弃
+	authTimer           *time.Timer               // Closes the connection when it does not authorize in time // 未及时鉴权时关闭连接
 	TokenID             int64                     // Bound Token ID // 绑定的令牌 ID
 	Scope               string                    // Token Scope // 令牌权限范围
 	Vaults              string                    // Restrict Vaults // 限制笔记库
 	Lang                string                    // Language preference // 语言偏好
-	Protocol            string                    // Protocol "protobuf" or other // 协议 "protobuf" 或其他
+	Protocol            string                    // Protocol "protobuf", "msgpack" or other // 协议 "protobuf"、"msgpack" 或其他
 	ProtoVersion        int                       // Client-declared handshake protocol version, from URL query "pv"; >=2 means the client supports v2 negotiation (negotiation block in auth response, window pipelining, early pb upgrade) // 客户端声明的握手协议版本，来自 URL query "pv"；>=2 表示客户端支持 v2 协商（auth 响应携带协商块、窗口流水线、pb 提前升级）
 	PbEnabled           bool                      // Client's local protobufEnabled setting, from URL query "pb" (1/0); only meaningful when ProtoVersion>=2 // 客户端本地 protobufEnabled 设置，来自 URL query "pb"（1/0）；仅在 ProtoVersion>=2 时有意义
 	currentAction       string                    // Current action type being processed // Current action type being processed // 当前正在处理的动作类型
 	remoteAddr          string                    // Client real IP address, extracted from HTTP headers / 客户端真实 IP 地址，从 HTTP 头部提取
+	DeviceID            string                    // Stable device ID from URL query "deviceId", keys the resumable sync cursor // 来自 URL query "deviceId" 的稳定设备 ID，用作可恢复同步游标的键
 }
 
 // ClientName returns the client-reported name (e.g. "Mac", "Windows", "iPhone").
@@ -320,6 +355,12 @@ func (c *WebsocketClient) ClientType() string {
 	return c.clientType
 }
 
+// RemoteAddr returns the real IP address of the client, set once when the connection is accepted.
+// RemoteAddr 返回客户端真实 IP 地址，在连接建立时设置且之后不再修改。
+func (c *WebsocketClient) RemoteAddr() string {
+	return c.remoteAddr
+}
+
 // ClientVersion returns the client-reported version (e.g. "1.2.4").
 // ClientVersion 返回客户端上报的版本号（例如 "1.2.4"）。
 func (c *WebsocketClient) ClientVersion() string {
@@ -339,6 +380,16 @@ func (c *WebsocketClient) ClientPlatform() map[string]bool {
 	return c.clientPlatform
 }
 
+// DeviceKey returns the stable identifier of the client device: the declared deviceId, otherwise
+// derived from the token and the client-reported type and name.
+// DeviceKey 返回客户端设备的稳定标识：优先使用声明的 deviceId，否则由令牌与客户端上报的类型、名称生成。
+func (c *WebsocketClient) DeviceKey() string {
+	if c.DeviceID != "" {
+		return c.DeviceID
+	}
+	return fmt.Sprintf("token:%d:%s:%s", c.TokenID, c.ClientType(), c.ClientName())
+}
+
 // OfflineSyncStrategy returns the client-reported offline sync strategy.
 // OfflineSyncStrategy 返回客户端上报的离线同步策略。
 func (c *WebsocketClient) OfflineSyncStrategy() string {
@@ -586,7 +637,7 @@ func (c *WebsocketClient) PingLoop(PingInterval time.Duration) {
 			if pingSent {
 				lastPong := c.lastPongAt.Load()
 				elapsed := time.Since(time.Unix(lastPong, 0))
-				if elapsed > time.Duration(WSPingWait)*time.Second {
+				if elapsed > c.Server.config.PingWait*time.Second {
 					log(LogWarn, "WS Client: no pong received within deadline, force closing",
 						zap.Duration("sinceLastPong", elapsed),
 						zap.String("traceID", c.TraceID))
@@ -665,6 +716,7 @@ func (c *WebsocketClient) ToResponse(code *code.Code, action ...string) {
 	}
 
 	if c.app.IsReturnSuccess() || actionType != "" || code.Code() > 200 || code.HaveData() || code.HaveDetails() {
+		c.traceOutbound(actionType, &content)
 		if c.UseProtobuf() && c.Server.ProtobufEncoder != nil && actionType != "" {
 			pbBytes, err := c.Server.ProtobufEncoder(actionType, &content)
 			if err == nil {
@@ -678,6 +730,14 @@ func (c *WebsocketClient) ToResponse(code *code.Code, action ...string) {
 				return "Guest"
 			}()))
 		}
+		if c.UseMsgpack() && actionType != "" {
+			mpBytes, err := encodeMsgpackFrame(actionType, &content)
+			if err == nil {
+				c.writeMessage(gws.OpcodeBinary, mpBytes)
+				return
+			}
+			log(LogError, "WS Msgpack encode failed, falling back to JSON", zap.Error(err), zap.String("action", actionType))
+		}
 
 		responseBytes, _ = json.Marshal(content)
 		if actionType != "" {
@@ -742,14 +802,14 @@ func (c *WebsocketClient) sendMessage(payload []byte) {
 	c.writeMessage(gws.OpcodeText, payload)
 }
 
-// writeMessage writes an application-layer message under the configured write deadline
+// writeDirect writes an application-layer message under the configured write deadline
 // (ws-write-timeout, default 10s), so a stalled/zombie connection cannot block WriteMessage
 // indefinitely and stall the write lock (see P9). The deadline is cleared after the write
 // completes, matching PingLoop's SetWriteDeadline/clear usage.
-// writeMessage 在配置的应用层写超时（ws-write-timeout，默认 10s）保护下写入消息，
+// writeDirect 在配置的应用层写超时（ws-write-timeout，默认 10s）保护下写入消息，
 // 避免僵尸/卡顿连接让 WriteMessage 无限阻塞并拖住写锁（见 P9）。写完后清空 deadline，
 // 用法与 PingLoop 的 SetWriteDeadline/清空一致。
-func (c *WebsocketClient) writeMessage(opcode gws.Opcode, payload []byte) error {
+func (c *WebsocketClient) writeDirect(opcode gws.Opcode, payload []byte) error {
 	if c.conn == nil {
 		return fmt.Errorf("connection is nil")
 	}
@@ -764,6 +824,27 @@ func (c *WebsocketClient) writeMessage(opcode gws.Opcode, payload []byte) error
 	return err
 }
 
+// writeMessage writes a message through the send queue of the connection and waits for the write,
+// keeping it in order with the queued broadcasts
+// writeMessage 经由连接的发送队列写入消息并等待写出，保证与排队中的广播保持顺序
+func (c *WebsocketClient) writeMessage(opcode gws.Opcode, payload []byte) error {
+	if c.outbox == nil {
+		return c.writeDirect(opcode, payload)
+	}
+	f := outboundFrame{opcode: opcode, payload: payload, result: make(chan error, 1)}
+	select {
+	case c.outbox <- f:
+	case <-c.WsCtx.Done():
+		return gws.ErrConnClosed
+	}
+	select {
+	case err := <-f.result:
+		return err
+	case <-c.WsCtx.Done():
+		return gws.ErrConnClosed
+	}
+}
+
 func (c *WebsocketClient) sendBroadcast(content *Res, actionType string, isExcludeSelf bool) {
 	// 持锁期间只拷贝目标连接列表，随后立即释放锁——WriteMessage 本身可能阻塞（慢设备/网络抖动），
 	// 不应该在持有 c.Server.mu 期间发生，否则会拖慢该用户下所有其他并发操作。
@@ -782,6 +863,28 @@ func (c *WebsocketClient) sendBroadcast(content *Res, actionType string, isExclu
 		targets = append(targets, uc)
 	}
 	c.Server.mu.RUnlock()
+	targets, skipped := filterBroadcastTargets(targets, actionType, content)
+
+	var seq uint64
+	if c.User != nil {
+		c.Server.publishCluster(&clusterEvent{Kind: clusterEventBroadcast, UID: c.User.ID, Action: actionType, Content: content})
+		seq = c.Server.recordSync(c.User.ID, actionType, content)
+		if isExcludeSelf {
+			// The originating device already has the change
+			// 发起变更的设备本身已包含该变更
+			c.Server.syncDelivered(c, seq, true)
+		}
+		// Connections that left the change out on purpose are up to date as well
+		// 有意不接收该变更的连接同样视为已同步
+		for _, uc := range skipped {
+			c.Server.syncDelivered(uc, seq, true)
+		}
+	}
+
+	trace := c.syncTrace()
+	if trace != nil {
+		traceBroadcast(trace, actionType, seq, content, targets, skipped)
+	}
 
 	if len(targets) == 0 {
 		return
@@ -795,41 +898,43 @@ func (c *WebsocketClient) sendBroadcast(content *Res, actionType string, isExclu
 		jsonBytes, _ = json.Marshal(content)
 	}
 
-	// 逐连接并发扇出：gws Conn.WriteMessage 内部对同一连接的写入用 c.mu 做了互斥
-	// （已查证 github.com/lxzan/gws@v1.9.1 writer.go doWrite），不同连接之间互不影响，
-	// 因此可以安全地并发写入，避免一台慢设备拖慢同用户下其他设备的广播。
-	// Fan out concurrently per connection: gws Conn.WriteMessage internally serializes
-	// writes to the same connection via c.mu (verified in
-	// github.com/lxzan/gws@v1.9.1 writer.go doWrite); different connections don't share
-	// that lock, so concurrent writes across connections are safe and prevent one slow
-	// device from stalling the broadcast to the user's other devices.
-	var wg sync.WaitGroup
+	// 逐连接入队扇出：广播帧进入各连接的有界发送队列后立即返回，由各连接的写协程写出，
+	// 一台慢设备既不会拖慢同用户下其他设备的广播，也不会拖住发起广播的请求；
+	// 队列写满的连接作为慢消费者被关闭，重连后补齐错过的变更。
+	// Fan out by queueing: the broadcast frame goes into the bounded send queue of each
+	// connection and returns right away, each connection's writer writes it out, so a slow
+	// device neither stalls the broadcast to the user's other devices nor the request that
+	// triggered it; a connection whose queue is full is closed as a slow consumer and
+	// catches up on the missed changes after reconnecting.
+	// Protobuf and MessagePack frames are shared by every target that negotiated them, encoded on first use
+	// Protobuf 与 MessagePack 帧由所有协商了对应协议的目标共享，首次使用时编码
+	pbFrame := sync.OnceValues(func() ([]byte, error) {
+		return c.Server.ProtobufEncoder(actionType, content)
+	})
+	mpFrame := sync.OnceValues(func() ([]byte, error) {
+		return encodeMsgpackFrame(actionType, content)
+	})
+
 	for _, uc := range targets {
-		wg.Add(1)
-		safego.Go(wsLogger, func() {
-			defer wg.Done()
-
-			var err error
-			if uc.UseProtobuf() && uc.Server.ProtobufEncoder != nil && actionType != "" {
-				var pbBytes []byte
-				pbBytes, err = uc.Server.ProtobufEncoder(actionType, content)
-				if err == nil {
-					err = uc.writeMessage(gws.OpcodeBinary, pbBytes)
-				}
-			} else {
-				err = uc.writeMessage(gws.OpcodeText, jsonBytes)
+		switch {
+		case uc.UseProtobuf() && uc.Server.ProtobufEncoder != nil && actionType != "":
+			pbBytes, err := pbFrame()
+			if err != nil {
+				uc.broadcastWritten(seq, actionType, err)
+				continue
 			}
-
+			uc.queueBroadcast(gws.OpcodeBinary, pbBytes, seq, actionType)
+		case uc.UseMsgpack() && actionType != "":
+			mpBytes, err := mpFrame()
 			if err != nil {
-				if uc.failCount.Add(1) == 4 {
-					uc.conn.WriteClose(1000, []byte("broadcast failed"))
-				}
-			} else {
-				uc.failCount.Store(0)
+				uc.broadcastWritten(seq, actionType, err)
+				continue
 			}
-		})
+			uc.queueBroadcast(gws.OpcodeBinary, mpBytes, seq, actionType)
+		default:
+			uc.queueBroadcast(gws.OpcodeText, jsonBytes, seq, actionType)
+		}
 	}
-	wg.Wait()
 }
 
 // SendBinary sends binary messages
@@ -919,6 +1024,7 @@ type WebsocketServer struct {
 	clients           ConnStorage
 	userClients       map[string]ConnStorage
 	connWg            sync.WaitGroup
+	connCount         atomic.Int64 // Open connections counted against MaxConnections // 计入 MaxConnections 的已打开连接数
 	mu                sync.RWMutex
 	up                *gws.Upgrader
 	config            *WSConfig
@@ -929,6 +1035,10 @@ type WebsocketServer struct {
 	EnvelopeDecoder     func(data []byte) (string, []byte, error)               // Protobuf envelope decoder // Protobuf 信封解包钩子
 	ProtobufDecoder     func(action string, data []byte, obj any) (bool, error) // Protobuf decoder hook // Protobuf 解码钩子
 	ProtobufEncoder     func(action string, res *Res) ([]byte, error)           // Protobuf encoder hook // Protobuf 编码钩子
+	journal             *syncJournal                                            // Resumable sync cursor, nil until UseSyncResume // 可恢复同步游标，调用 UseSyncResume 之前为 nil
+	clusterBus          cluster.Bus                                             // Relays broadcasts and kicks to the other instances, nil until UseClusterBus // 向其他实例转发广播与踢下线，调用 UseClusterBus 之前为 nil
+	deviceTracker       func(*WebsocketClient)                                  // Device tracking hook, called after auth and ClientInfo // 设备跟踪钩子，在鉴权与 ClientInfo 之后调用
+	syncTrace           *synctrace.Tracer                                       // Per-user sync tracing, nil until UseSyncTrace // 按用户同步跟踪，调用 UseSyncTrace 之前为 nil
 }
 
 // WSClientInfo WebSocket client information for API responses
@@ -972,6 +1082,20 @@ func (w *WebsocketServer) GetClients() []WSClientInfo {
 	return clients
 }
 
+// ConnectionsByUser returns the number of authenticated WebSocket connections per user
+// ConnectionsByUser 返回每个用户已认证的 WebSocket 连接数
+func (w *WebsocketServer) ConnectionsByUser() map[int64]int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	counts := make(map[int64]int)
+	for _, c := range w.clients {
+		if c.User != nil {
+			counts[c.User.UID]++
+		}
+	}
+	return counts
+}
+
 // KickClient closes a WebSocket connection by TraceID
 // KickClient 通过 TraceID 关闭 WebSocket 连接
 func (w *WebsocketServer) KickClient(traceID string) bool {
@@ -1013,6 +1137,14 @@ func NewWebsocketServer(c WSConfig, app AppContainer) *WebsocketServer {
 	if c.PingWait == 0 {
 		c.PingWait = WSPingWait
 	}
+	// A wait not above the interval would drop healthy connections between two pings
+	// 等待时长不大于间隔时，健康的连接会在两次 ping 之间被断开
+	if c.PingWait <= c.PingInterval {
+		c.PingWait = c.PingInterval * 2
+	}
+	if c.SendQueueSize <= 0 {
+		c.SendQueueSize = DefaultSendQueueSize
+	}
 
 	// Set logger for WebSocket module
 	// 设置 WebSocket 模块的日志器
@@ -1055,9 +1187,16 @@ func (w *WebsocketServer) Run() gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 
+		if !w.acquireConn() {
+			log(LogWarn, "WS Start refused: too many connections", zap.Int("max", w.config.MaxConnections), zap.String("ip", c.ClientIP()))
+			NewResponse(c).ToResponse(code.ErrorWSConnectionLimit)
+			return
+		}
+
 		w.Upgrade()
 		socket, err := w.up.Upgrade(c.Writer, c.Request)
 		if err != nil {
+			w.releaseConn()
 			log(LogError, "WS Start err", zap.Error(err))
 			return
 		}
@@ -1085,6 +1224,7 @@ func (w *WebsocketServer) Run() gin.HandlerFunc {
 		client.clientName = c.Query("clientName")
 		client.clientVersion = c.Query("clientVersion")
 		client.Protocol = c.Query("protocol")
+		client.DeviceID = c.Query("deviceId")
 
 		// v2 handshake capability declaration (§2.2): pv = protocol version the client
 		// supports, pb = client's local protobufEnabled setting. Missing/invalid values
@@ -1108,6 +1248,8 @@ func (w *WebsocketServer) Run() gin.HandlerFunc {
 		// Initialize long-lifecycle context for WebSocket connection
 		// 初始化 WebSocket 连接的长生命周期 context
 		client.initContext(traceID)
+		client.startWriteLoop(w.config.SendQueueSize)
+		client.startAuthTimer(w.config.AuthTimeout)
 
 		w.AddClient(client)
 		w.connWg.Add(1)
@@ -1153,6 +1295,13 @@ func (w *WebsocketServer) UseTokenVerify(handler func(ctx context.Context, uid i
 	w.tokenVerifyHandler = handler
 }
 
+// UseDeviceTracker registers the hook recording the device of a client after Authorization and
+// after every ClientInfo of an authenticated client
+// UseDeviceTracker 注册设备记录钩子，在 Authorization 之后以及已认证客户端每次上报 ClientInfo 之后调用
+func (w *WebsocketServer) UseDeviceTracker(handler func(*WebsocketClient)) {
+	w.deviceTracker = handler
+}
+
 func (w *WebsocketServer) UseBinary(prefix string, handler func(*WebsocketClient, []byte)) {
 	if len(prefix) != 2 {
 		panic("binary message prefix must be 2 characters")
@@ -1227,9 +1376,21 @@ func (w *WebsocketServer) Authorization(c *WebsocketClient, msg *WebSocketMessag
 		user.Nickname = userSelect.Nickname
 		c.TokenID = user.TokenID
 
+		userClients, ok := w.addUserClientWithLimit(c, user)
+		if !ok {
+			log(LogWarn, "WS Authorization FAILD: too many connections", zap.String("uid", user.ID), zap.Int("max", w.config.MaxConnectionsPerUser))
+			c.ToResponse(code.ErrorWSUserConnectionLimit.WithDetails(strconv.Itoa(w.config.MaxConnectionsPerUser)), "Authorization")
+			c.conn.WriteClose(wsClosePolicyViolation, []byte("too many connections"))
+			return
+		}
+		c.stopAuthTimer()
+
 		log(LogInfo, "WS Authorization", zap.String("uid", user.ID), zap.String("Nickname", user.Nickname), zap.Int64("TokenID", c.TokenID))
-		c.User = user
-		c.UserClients = w.AddUserClient(c)
+		c.UserClients = userClients
+
+		if w.deviceTracker != nil {
+			w.deviceTracker(c)
+		}
 
 		versionInfo := w.app.Version()
 
@@ -1250,6 +1411,12 @@ func (w *WebsocketServer) Authorization(c *WebsocketClient, msg *WebSocketMessag
 		}
 
 		protobufAck := c.Protocol == "protobuf" && c.PbEnabled
+		msgpackAck := c.Protocol == ProtocolMsgpack && w.config.MsgpackEnabled
+		if msgpackAck {
+			// Only clients that asked for MessagePack see the key
+			// 仅请求了 MessagePack 的客户端会看到该字段
+			authData["msgpackAck"] = true
+		}
 
 		if c.ProtoVersion >= 2 {
 			syncUpChunkNum, syncDownChunkNum := w.app.SyncChunkNums()
@@ -1259,6 +1426,15 @@ func (w *WebsocketServer) Authorization(c *WebsocketClient, msg *WebSocketMessag
 			authData["pipelineWindowUp"] = pipelineWindowUp
 			authData["pipelineWindowDown"] = pipelineWindowDown
 			authData["protobufAck"] = protobufAck
+			// Lets the client recognise its own changes via NoteSyncModify.originClient
+			// 便于客户端通过 NoteSyncModify.originClient 识别自身产生的修改
+			authData["clientId"] = c.TraceID
+		}
+
+		// Devices that declared a deviceId can replay missed events with SyncResume instead of a full sync
+		// 声明了 deviceId 的设备可以通过 SyncResume 重放错过的事件，而无需完整同步
+		if cursor, ok := w.registerSyncDevice(c); ok {
+			authData["syncCursor"] = cursor
 		}
 
 		c.ToResponse(code.Success.WithData(authData), "Authorization")
@@ -1269,6 +1445,11 @@ func (w *WebsocketServer) Authorization(c *WebsocketClient, msg *WebSocketMessag
 		if c.ProtoVersion >= 2 {
 			c.setUseProtobuf(protobufAck)
 		}
+		// MessagePack needs no ClientInfo round trip, it applies to every message after the auth response
+		// MessagePack 无需 ClientInfo 往返，auth 响应之后的所有消息均生效
+		if msgpackAck {
+			c.setUseMsgpack(true)
+		}
 
 		log(LogInfo, "WS User Enter", zap.String("uid", c.User.ID), zap.String("Nickname", c.User.Nickname), zap.Int("Count", len(c.UserClients)))
 		go c.PingLoop(w.config.PingInterval)
@@ -1323,6 +1504,10 @@ func (w *WebsocketServer) ClientInfo(c *WebsocketClient, msg *WebSocketMessage)
 		return "Guest"
 	}()), zap.String("name", c.ClientName()), zap.String("version", c.ClientVersion()), zap.String("offlineSyncStrategy", c.OfflineSyncStrategy()))
 
+	if c.User != nil && w.deviceTracker != nil {
+		w.deviceTracker(c)
+	}
+
 	checkVersionInfo := w.app.CheckVersion(c.ClientVersion())
 
 	c.ToResponse(code.Success.WithData(checkVersionInfo), "ClientInfo")
@@ -1398,6 +1583,19 @@ func (w *WebsocketServer) GetActiveTokenIDs(uid int64) map[int64]bool {
 	return activeTokens
 }
 
+// GetActiveDeviceKeys gets the device keys of all active connections of a specific user
+// GetActiveDeviceKeys 获取特定用户所有活动连接的设备标识
+func (w *WebsocketServer) GetActiveDeviceKeys(uid int64) map[string]bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	keys := make(map[string]bool)
+	for _, client := range w.userClients[strconv.FormatInt(uid, 10)] {
+		keys[client.DeviceKey()] = true
+	}
+	return keys
+}
+
 // GetActiveTokenClients gets all active token IDs and their client names for a specific user
 // GetActiveTokenClients 获取特定用户的所有活动令牌 ID 及其对应的客户端名称
 func (w *WebsocketServer) GetActiveTokenClients(uid int64) map[int64][]string {
@@ -1433,6 +1631,11 @@ func (w *WebsocketServer) GetActiveTokenClients(uid int64) map[int64][]string {
 // UpdateTokenScope updates the scope of all active connections for a specific token
 // UpdateTokenScope 更新特定令牌所有活动连接的权限范围
 func (w *WebsocketServer) UpdateTokenScope(uid int64, tokenID int64, newScope string) {
+	w.publishCluster(&clusterEvent{Kind: clusterEventTokenScope, UID: strconv.FormatInt(uid, 10), TokenID: tokenID, Scope: newScope})
+	w.updateTokenScope(uid, tokenID, newScope)
+}
+
+func (w *WebsocketServer) updateTokenScope(uid int64, tokenID int64, newScope string) {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
@@ -1450,6 +1653,11 @@ func (w *WebsocketServer) UpdateTokenScope(uid int64, tokenID int64, newScope st
 // KickToken closes all connections for a specific token
 // KickToken 关闭特定令牌的所有连接
 func (w *WebsocketServer) KickToken(uid int64, tokenID int64) {
+	w.publishCluster(&clusterEvent{Kind: clusterEventKickToken, UID: strconv.FormatInt(uid, 10), TokenID: tokenID})
+	w.kickToken(uid, tokenID)
+}
+
+func (w *WebsocketServer) kickToken(uid int64, tokenID int64) {
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
@@ -1464,6 +1672,25 @@ func (w *WebsocketServer) KickToken(uid int64, tokenID int64) {
 	}
 }
 
+// KickDevice closes all connections of a specific device
+// KickDevice 关闭特定设备的所有连接
+func (w *WebsocketServer) KickDevice(uid int64, deviceKey string) {
+	w.publishCluster(&clusterEvent{Kind: clusterEventKickDevice, UID: strconv.FormatInt(uid, 10), DeviceKey: deviceKey})
+	w.kickDevice(uid, deviceKey)
+}
+
+func (w *WebsocketServer) kickDevice(uid int64, deviceKey string) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for _, client := range w.userClients[strconv.FormatInt(uid, 10)] {
+		if client.DeviceKey() == deviceKey {
+			log(LogInfo, "WS KickDevice", zap.Int64("uid", uid), zap.String("deviceKey", deviceKey))
+			client.conn.WriteClose(1000, []byte("DeviceRevoked"))
+		}
+	}
+}
+
 // CloseAllConnections sends a close frame to all active WebSocket connections.
 // This must be called before shutting down the Worker Pool and Write Queue Manager
 // to ensure hijacked WebSocket connections are properly terminated.
@@ -1630,6 +1857,7 @@ func isNormalDisconnectError(err error) bool {
 
 func (w *WebsocketServer) OnClose(conn *gws.Conn, err error) {
 	defer w.connWg.Done()
+	defer w.releaseConn()
 
 	c := w.GetClient(conn)
 	if c == nil {
@@ -1641,8 +1869,10 @@ func (w *WebsocketServer) OnClose(conn *gws.Conn, err error) {
 	// This must be performed before cleaning up other resources to ensure that all operations dependent on the context can receive the cancellation signal
 	// 这必须在清理其他 resource 之前执行，以确保所有依赖 context 的操作能够收到取消信号
 	c.cancelContext()
+	c.stopAuthTimer()
 
 	w.RemoveClient(conn)
+	w.config.ConnLimiter.Remove(c.TraceID)
 
 	if c.User != nil {
 		select {
@@ -1731,6 +1961,9 @@ func (w *WebsocketServer) OnMessage(conn *gws.Conn, message *gws.Message) {
 		copy(payloadCopy, payload)
 
 		if handler, ok := w.binaryHandlers[prefix]; ok {
+			if trace := c.syncTrace(); trace != nil {
+				trace.Debug("WS receive binary", zap.String("prefix", prefix), zap.Int("size", len(payloadCopy)))
+			}
 			// Submit task through Worker Pool
 			// 通过 Worker Pool 提交任务
 			err := w.app.SubmitTaskAsync(c.Context(), func(ctx context.Context) error {
@@ -1780,6 +2013,11 @@ func (w *WebsocketServer) OnMessage(conn *gws.Conn, message *gws.Message) {
 				Type: action,
 				Data: innerPayload,
 			}
+			c.traceInbound(&msg, "protobuf")
+
+			if !w.allowMessage(c, msg.Type) {
+				return
+			}
 
 			if noAuthHandler, exists := w.noAuthHandlers[msg.Type]; exists {
 				noAuthHandler(c, &msg)
@@ -1803,6 +2041,34 @@ func (w *WebsocketServer) OnMessage(conn *gws.Conn, message *gws.Message) {
 					return "Guest"
 				}()))
 			}
+		} else if prefix == msgpackPrefix {
+			if !c.UseMsgpack() {
+				log(LogWarn, "WS OnMessage received Msgpack but UseMsgpack is false", zap.String("uid", c.User.ID))
+				return
+			}
+
+			msg, err := decodeMsgpackMessage(payloadCopy)
+			if err != nil {
+				log(LogError, "WS OnMessage Msgpack decode failed", zap.Error(err), zap.String("uid", c.User.ID))
+				return
+			}
+			c.traceInbound(&msg, "msgpack")
+
+			if !w.allowMessage(c, msg.Type) {
+				return
+			}
+
+			for _, interceptor := range w.interceptors {
+				if !interceptor(c, &msg) {
+					return
+				}
+			}
+
+			if handler, exists := w.handlers[msg.Type]; exists {
+				handler(c, &msg)
+			} else {
+				log(LogError, "WS Unknown Message (Msgpack)", zap.String("Type", msg.Type), zap.String("uid", c.User.ID))
+			}
 		} else {
 			log(LogWarn, "WS OnMessage Unknown Binary Prefix", zap.String("prefix", prefix))
 		}
@@ -1824,6 +2090,11 @@ func (w *WebsocketServer) OnMessage(conn *gws.Conn, message *gws.Message) {
 		log(LogError, "WS OnMessage", zap.String("type", "Illegal message type"), zap.String("uid", c.User.ID))
 		return
 	}
+	c.traceInbound(&msg, "json")
+
+	if !w.allowMessage(c, msg.Type) {
+		return
+	}
 
 	// Prioritize matching and executing unauthenticated handlers
 	// 优先匹配并执行免登录鉴权的消息处理器
@@ -1857,17 +2128,38 @@ func (w *WebsocketServer) OnMessage(conn *gws.Conn, message *gws.Message) {
 	}
 }
 
-func (w *WebsocketServer) BroadcastToUser(uid int64, code *code.Code, action string) {
-	uidStr := strconv.FormatInt(uid, 10)
-	w.mu.RLock()
-	defer w.mu.RUnlock()
+// allowMessage applies the per-connection and per-user rate limits to an inbound text/protobuf
+// message. Binary file chunks are not limited, they are already bounded by the worker pool.
+// allowMessage 对入站文本/Protobuf 消息应用按连接和按用户的限流。
+// 二进制附件分片不限流，其并发已由 Worker Pool 约束。
+func (w *WebsocketServer) allowMessage(c *WebsocketClient, action string) bool {
+	ok, first := w.config.ConnLimiter.Take(c.TraceID)
+	scope := "conn"
+	if ok && c.User != nil {
+		ok, first = w.config.UserLimiter.Take(c.User.ID)
+		scope = "user"
+	}
+	if ok {
+		return true
+	}
 
-	userClients, ok := w.userClients[uidStr]
-	if !ok || len(userClients) == 0 {
-		return
+	if first {
+		uid := "Guest"
+		if c.User != nil {
+			uid = c.User.ID
+		}
+		log(LogWarn, "WS OnMessage rate limit exceeded",
+			zap.String("scope", scope),
+			zap.String("action", action),
+			zap.String("uid", uid),
+			zap.String("traceID", c.TraceID))
 	}
+	c.ToResponse(code.ErrorTooManyRequests.WithDetails("action: " + action))
+	return false
+}
 
-	var responseBytes []byte
+func (w *WebsocketServer) BroadcastToUser(uid int64, code *code.Code, action string) {
+	uidStr := strconv.FormatInt(uid, 10)
 	content := Res{
 		Code:    code.Code(),
 		Status:  code.Status(),
@@ -1883,26 +2175,43 @@ func (w *WebsocketServer) BroadcastToUser(uid int64, code *code.Code, action str
 		content.Vault = code.Vault()
 	}
 
-	responseBytes, _ = json.Marshal(content)
+	// The user's other devices may be connected to another instance
+	// 用户的其他设备可能连接在其他实例上
+	w.publishCluster(&clusterEvent{Kind: clusterEventBroadcast, UID: uidStr, Action: action, Content: &content})
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	userClients, ok := w.userClients[uidStr]
+	if !ok || len(userClients) == 0 {
+		return
+	}
+
+	responseBytes, _ := json.Marshal(content)
 
 	if action != "" {
 		responseBytes = []byte(fmt.Sprintf(`%s|%s`, action, string(responseBytes)))
 	}
 
-	var b = gws.NewBroadcaster(gws.OpcodeText, responseBytes)
-	defer b.Close()
+	seq := w.recordSync(uidStr, action, &content)
+
+	trace := w.syncTrace.Logger(uid)
+	var targets, skipped []*WebsocketClient
+	if trace != nil {
+		defer func() { traceBroadcast(trace, action, seq, &content, targets, skipped) }()
+	}
 
 	for _, uc := range userClients {
 		if uc.conn == nil {
 			continue
 		}
-		if err := b.Broadcast(uc.conn); err != nil {
-			if uc.failCount.Add(1) == 4 {
-				uc.conn.WriteClose(1000, []byte("broadcast failed"))
-			}
-		} else {
-			uc.failCount.Store(0)
+		if !uc.acceptsBroadcast(action, &content) {
+			w.syncDelivered(uc, seq, true)
+			skipped = append(skipped, uc)
+			continue
 		}
+		targets = append(targets, uc)
+		uc.queueBroadcast(gws.OpcodeText, responseBytes, seq, action)
 	}
 }
 
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/cluster"
+	"github.com/haierkeys/fast-note-sync-service/pkg/json"
+	"github.com/lxzan/gws"
+	"go.uber.org/zap"
+)
+
+// clusterTopic bus topic of the WebSocket events relayed between instances
+// clusterTopic 实例间转发 WebSocket 事件所用的总线主题
+const clusterTopic = "ws"
+
+// Kinds of the relayed WebSocket events
+// 转发的 WebSocket 事件类型
+const (
+	clusterEventBroadcast  = "broadcast"
+	clusterEventKickToken  = "kickToken"
+	clusterEventKickDevice = "kickDevice"
+	clusterEventTokenScope = "tokenScope"
+)
+
+// clusterEvent WebSocket event relayed to the connections of the user on the other instances
+// clusterEvent 转发给用户在其他实例上连接的 WebSocket 事件
+type clusterEvent struct {
+	Kind      string `json:"kind"`
+	UID       string `json:"uid"`
+	Action    string `json:"action,omitempty"`
+	Content   *Res   `json:"content,omitempty"`
+	TokenID   int64  `json:"tokenId,omitempty"`
+	DeviceKey string `json:"deviceKey,omitempty"`
+	Scope     string `json:"scope,omitempty"`
+}
+
+// UseClusterBus relays broadcasts, token scope changes and kicks to the instances sharing the bus,
+// so a user's devices get the same sync events whichever instance they are connected to
+// UseClusterBus 通过总线向其他实例转发广播、令牌权限变更与踢下线，
+// 使用户的设备无论连接在哪个实例上都能收到相同的同步事件
+func (w *WebsocketServer) UseClusterBus(bus cluster.Bus) {
+	if bus == nil {
+		return
+	}
+	w.clusterBus = bus
+	bus.Subscribe(clusterTopic, w.onClusterEvent)
+}
+
+// publishCluster relays an event to the other instances, a failure only affects their devices
+// publishCluster 向其他实例转发事件，失败只影响连接在其他实例上的设备
+func (w *WebsocketServer) publishCluster(ev *clusterEvent) {
+	if w.clusterBus == nil {
+		return
+	}
+	payload, err := json.Marshal(ev)
+	if err == nil {
+		err = w.clusterBus.Publish(clusterTopic, payload)
+	}
+	if err != nil {
+		log(LogWarn, "WS cluster publish failed", zap.String("kind", ev.Kind), zap.String("uid", ev.UID), zap.Error(err))
+	}
+}
+
+// onClusterEvent applies an event relayed by another instance to the local connections
+// onClusterEvent 将其他实例转发的事件应用到本地连接
+func (w *WebsocketServer) onClusterEvent(payload []byte) {
+	var ev clusterEvent
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		log(LogWarn, "WS cluster event malformed", zap.Error(err))
+		return
+	}
+	uid, err := strconv.ParseInt(ev.UID, 10, 64)
+	if err != nil {
+		return
+	}
+
+	switch ev.Kind {
+	case clusterEventBroadcast:
+		if ev.Content != nil {
+			w.deliverClusterBroadcast(ev.UID, ev.Action, ev.Content)
+		}
+	case clusterEventKickToken:
+		w.kickToken(uid, ev.TokenID)
+	case clusterEventKickDevice:
+		w.kickDevice(uid, ev.DeviceKey)
+	case clusterEventTokenScope:
+		w.updateTokenScope(uid, ev.TokenID, ev.Scope)
+	}
+}
+
+// deliverClusterBroadcast writes a relayed broadcast to every local connection of the user.
+// The data was decoded from JSON and lost its Go type, so protobuf connections get the JSON frame.
+// deliverClusterBroadcast 将转发的广播写入该用户的所有本地连接。
+// 数据经 JSON 解码后已失去 Go 类型，因此 protobuf 连接收到 JSON 帧。
+func (w *WebsocketServer) deliverClusterBroadcast(uid, action string, content *Res) {
+	w.mu.RLock()
+	targets := make([]*WebsocketClient, 0, len(w.userClients[uid]))
+	for _, uc := range w.userClients[uid] {
+		if uc.conn != nil {
+			targets = append(targets, uc)
+		}
+	}
+	w.mu.RUnlock()
+	targets, skipped := filterBroadcastTargets(targets, action, content)
+
+	seq := w.recordSync(uid, action, content)
+	for _, uc := range skipped {
+		w.syncDelivered(uc, seq, true)
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	mBytes, err := json.Marshal(content)
+	if err != nil {
+		return
+	}
+	jsonBytes := mBytes
+	if action != "" {
+		jsonBytes = []byte(fmt.Sprintf(`%s|%s`, action, string(mBytes)))
+	}
+	mpFrame := sync.OnceValues(func() ([]byte, error) {
+		return encodeMsgpackFrame(action, content)
+	})
+
+	for _, uc := range targets {
+		if uc.UseMsgpack() && action != "" {
+			mpBytes, err := mpFrame()
+			if err != nil {
+				uc.broadcastWritten(seq, action, err)
+				continue
+			}
+			uc.queueBroadcast(gws.OpcodeBinary, mpBytes, seq, action)
+		} else {
+			uc.queueBroadcast(gws.OpcodeText, jsonBytes, seq, action)
+		}
+	}
+}
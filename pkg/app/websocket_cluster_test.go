@@ -0,0 +1,71 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBus in-process cluster.Bus delivering to the peers of a group, never back to the sender
+type memoryBus struct {
+	id       string
+	peers    *[]*memoryBus
+	handlers map[string]func([]byte)
+}
+
+func newMemoryBuses(ids ...string) []*memoryBus {
+	group := make([]*memoryBus, 0, len(ids))
+	for _, id := range ids {
+		group = append(group, &memoryBus{id: id, handlers: map[string]func([]byte){}})
+	}
+	for _, b := range group {
+		b.peers = &group
+	}
+	return group
+}
+
+func (b *memoryBus) Publish(topic string, payload []byte) error {
+	for _, p := range *b.peers {
+		if p != b && p.handlers[topic] != nil {
+			p.handlers[topic](payload)
+		}
+	}
+	return nil
+}
+
+func (b *memoryBus) Subscribe(topic string, handler func([]byte)) { b.handlers[topic] = handler }
+func (b *memoryBus) InstanceID() string                           { return b.id }
+func (b *memoryBus) Close() error                                 { return nil }
+
+func newClusterTestServer() *WebsocketServer {
+	return &WebsocketServer{
+		userClients: make(map[string]ConnStorage),
+		config:      &WSConfig{},
+		journal:     newSyncJournal(10, time.Hour, []string{"NoteSyncModify"}),
+	}
+}
+
+// TestWebsocketServer_ClusterBroadcast verifies a broadcast made on one instance is queued for the
+// user's devices on the other instance, even when the sending instance has no connection of the user.
+// TestWebsocketServer_ClusterBroadcast 验证在一个实例上发起的广播会为用户在另一实例上的设备入队，
+// 即使发起实例上没有该用户的连接。
+func TestWebsocketServer_ClusterBroadcast(t *testing.T) {
+	buses := newMemoryBuses("a", "b")
+	a, b := newClusterTestServer(), newClusterTestServer()
+	a.UseClusterBus(buses[0])
+	b.UseClusterBus(buses[1])
+
+	b.journal.register("1", "phone")
+
+	a.BroadcastToUser(1, code.Success.WithData(map[string]any{"path": "a.md"}), "NoteSyncModify")
+
+	events, _, complete := b.journal.since("1", "phone")
+	require.True(t, complete)
+	require.Len(t, events, 1)
+	assert.Equal(t, "NoteSyncModify", events[0].action)
+	assert.Equal(t, map[string]any{"path": "a.md"}, events[0].content.Data)
+
+}
@@ -0,0 +1,39 @@
+package app
+
+// BroadcastFilter decides whether a broadcast reaches a connection, e.g. to keep files a device does not
+// sync away from it. Broadcasts relayed by other instances carry data decoded from JSON
+// BroadcastFilter 决定一条广播是否发送到某个连接，例如不向设备发送其不同步的文件。
+// 其他实例转发的广播所携带的数据为 JSON 解码结果
+type BroadcastFilter func(action string, content *Res) bool
+
+// SetBroadcastFilter sets the broadcast filter of the connection, nil accepts every broadcast
+// SetBroadcastFilter 设置连接的广播过滤器，为 nil 时接收所有广播
+func (c *WebsocketClient) SetBroadcastFilter(filter BroadcastFilter) {
+	c.infoMu.Lock()
+	defer c.infoMu.Unlock()
+	c.broadcastFilter = filter
+}
+
+// acceptsBroadcast reports whether the broadcast is delivered to the connection
+// acceptsBroadcast 判断广播是否发送到该连接
+func (c *WebsocketClient) acceptsBroadcast(action string, content *Res) bool {
+	c.infoMu.RLock()
+	filter := c.broadcastFilter
+	c.infoMu.RUnlock()
+	return filter == nil || filter(action, content)
+}
+
+// filterBroadcastTargets splits the targets of a broadcast into the connections that receive it and
+// those that left it out
+// filterBroadcastTargets 将广播目标拆分为接收该广播的连接与不接收的连接
+func filterBroadcastTargets(targets []*WebsocketClient, action string, content *Res) (accepted, skipped []*WebsocketClient) {
+	accepted = targets[:0]
+	for _, uc := range targets {
+		if uc.acceptsBroadcast(action, content) {
+			accepted = append(accepted, uc)
+		} else {
+			skipped = append(skipped, uc)
+		}
+	}
+	return accepted, skipped
+}
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"errors"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
+	"github.com/lxzan/gws"
+	"go.uber.org/zap"
+)
+
+// DefaultSendQueueSize outbound frames queued per connection when WSConfig.SendQueueSize is 0
+// DefaultSendQueueSize WSConfig.SendQueueSize 为 0 时每个连接排队的出站帧数
+const DefaultSendQueueSize = 256
+
+const (
+	// wsClosePolicyViolation close status sent to connections refused or dropped by a server limit
+	// wsClosePolicyViolation 因服务端限制被拒绝或断开的连接收到的关闭状态码
+	wsClosePolicyViolation = 1008
+	// forceCloseGrace how long a forced close waits for the close frame before dropping the connection
+	// forceCloseGrace 强制关闭时等待关闭帧写出的时长，超时后直接断开连接
+	forceCloseGrace = time.Second
+)
+
+// errSlowConsumer returned for frames discarded after the connection was found too slow
+// errSlowConsumer 连接被判定为慢消费者后，被丢弃的帧返回该错误
+var errSlowConsumer = errors.New("websocket slow consumer")
+
+// outboundFrame one frame waiting in the send queue of a connection
+// outboundFrame 连接发送队列中等待写出的一帧
+type outboundFrame struct {
+	opcode  gws.Opcode
+	payload []byte
+	seq     uint64     // Sync journal sequence of a broadcast, 0 when not journaled // 广播在同步日志中的序号，未记录时为 0
+	action  string     // Broadcast action, for tracing // 广播动作，用于跟踪
+	result  chan error // Receives the write error of a direct write, nil for broadcasts // 接收直接写入的写错误，广播为 nil
+}
+
+// acquireConn reserves a connection slot, false when the server already holds MaxConnections connections
+// acquireConn 占用一个连接名额；服务器已有 MaxConnections 个连接时返回 false
+func (w *WebsocketServer) acquireConn() bool {
+	n := w.connCount.Add(1)
+	if max := w.config.MaxConnections; max > 0 && n > int64(max) {
+		w.connCount.Add(-1)
+		return false
+	}
+	return true
+}
+
+// releaseConn frees the slot taken by acquireConn
+// releaseConn 释放 acquireConn 占用的名额
+func (w *WebsocketServer) releaseConn() {
+	w.connCount.Add(-1)
+}
+
+// addUserClientWithLimit binds the user to the client and adds it to the user's connections,
+// false when the user already holds MaxConnectionsPerUser connections
+// addUserClientWithLimit 将用户绑定到连接并加入该用户的连接池；用户已有 MaxConnectionsPerUser 个连接时返回 false
+func (w *WebsocketServer) addUserClientWithLimit(c *WebsocketClient, user *UserEntity) (ConnStorage, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	clients := w.userClients[user.ID]
+	if _, exists := clients[c.conn]; !exists {
+		if max := w.config.MaxConnectionsPerUser; max > 0 && len(clients) >= max {
+			return nil, false
+		}
+	}
+	if clients == nil {
+		clients = make(ConnStorage)
+		w.userClients[user.ID] = clients
+	}
+	c.User = user
+	clients[c.conn] = c
+	return clients, true
+}
+
+// startAuthTimer closes the connection when it has not authorized within timeout, 0 disables
+// startAuthTimer 连接在 timeout 内未完成鉴权时关闭连接，0 表示不限制
+func (c *WebsocketClient) startAuthTimer(timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	c.authTimer = time.AfterFunc(timeout, func() {
+		log(LogWarn, "WS Client authorization timeout, closing", zap.Duration("timeout", timeout), zap.String("traceID", c.TraceID))
+		c.forceClose(wsClosePolicyViolation, "authorization timeout")
+	})
+}
+
+// stopAuthTimer stops the timer started by startAuthTimer
+// stopAuthTimer 停止 startAuthTimer 启动的计时器
+func (c *WebsocketClient) stopAuthTimer() {
+	if c.authTimer != nil {
+		c.authTimer.Stop()
+	}
+}
+
+// forceClose sends a close frame and drops the connection when the frame is not written in time,
+// so a client that stopped reading cannot keep it open
+// forceClose 发送关闭帧，若关闭帧未能及时写出则直接断开连接，避免停止读取的客户端让连接一直保持
+func (c *WebsocketClient) forceClose(status uint16, reason string) {
+	conn := c.conn
+	if conn == nil {
+		return
+	}
+	time.AfterFunc(forceCloseGrace, func() {
+		_ = conn.NetConn().Close()
+	})
+	safego.Go(wsLogger, func() {
+		_ = conn.WriteClose(status, []byte(reason))
+	})
+}
+
+// startWriteLoop creates the bounded send queue of the connection and starts its writer,
+// every outbound message then goes through the queue in order
+// startWriteLoop 创建连接的有界发送队列并启动写协程，之后所有出站消息按顺序经由该队列写出
+func (c *WebsocketClient) startWriteLoop(size int) {
+	c.outbox = make(chan outboundFrame, size)
+	go c.writeLoop()
+}
+
+// writeLoop writes queued frames until the connection context is cancelled
+// writeLoop 写出排队的帧，直到连接 context 被取消
+func (c *WebsocketClient) writeLoop() {
+	for {
+		select {
+		case <-c.WsCtx.Done():
+			c.drainOutbox()
+			return
+		case f := <-c.outbox:
+			var err error
+			if c.slowConsumer.Load() {
+				err = errSlowConsumer
+			} else {
+				err = c.writeDirect(f.opcode, f.payload)
+			}
+			if f.result != nil {
+				f.result <- err
+			} else {
+				c.broadcastWritten(f.seq, f.action, err)
+			}
+		}
+	}
+}
+
+// drainOutbox fails the frames left in the queue of a closed connection, the broadcasts among them are replayed on resume
+// drainOutbox 使已关闭连接队列中剩余的帧失败，其中的广播会在恢复同步时重放
+func (c *WebsocketClient) drainOutbox() {
+	for {
+		select {
+		case f := <-c.outbox:
+			if f.result != nil {
+				f.result <- gws.ErrConnClosed
+			} else {
+				c.Server.syncDelivered(c, f.seq, false)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// queueBroadcast queues a broadcast frame without waiting for the write. A full queue means the client
+// cannot keep up: the frame is dropped and the connection closed, the device catches up with SyncResume
+// or a full sync after reconnecting
+// queueBroadcast 将广播帧加入队列，不等待写出。队列已满说明客户端跟不上：丢弃该帧并关闭连接，
+// 设备重连后通过 SyncResume 或完整同步补齐
+func (c *WebsocketClient) queueBroadcast(opcode gws.Opcode, payload []byte, seq uint64, action string) {
+	if c.outbox == nil {
+		c.broadcastWritten(seq, action, c.writeDirect(opcode, payload))
+		return
+	}
+	if c.WsCtx.Err() != nil {
+		c.Server.syncDelivered(c, seq, false)
+		return
+	}
+	select {
+	case c.outbox <- outboundFrame{opcode: opcode, payload: payload, seq: seq, action: action}:
+	default:
+		c.Server.syncDelivered(c, seq, false)
+		if c.slowConsumer.CompareAndSwap(false, true) {
+			log(LogWarn, "WS slow consumer, closing", zap.Int("queue", cap(c.outbox)), zap.String("action", action), zap.String("traceID", c.TraceID))
+			if trace := c.syncTrace(); trace != nil {
+				trace.Warn("WS slow consumer, broadcasts dropped until reconnect", zap.String("action", action), zap.Uint64("seq", seq))
+			}
+			c.forceClose(wsClosePolicyViolation, "slow consumer")
+		}
+	}
+}
+
+// broadcastWritten records the outcome of a broadcast write, closing the connection after repeated failures
+// broadcastWritten 记录广播写入结果，连续失败多次后关闭连接
+func (c *WebsocketClient) broadcastWritten(seq uint64, action string, err error) {
+	c.Server.syncDelivered(c, seq, err == nil)
+	if err == nil {
+		c.failCount.Store(0)
+		return
+	}
+	if trace := c.syncTrace(); trace != nil {
+		trace.Warn("WS broadcast delivery failed", zap.String("action", action), zap.Error(err))
+	}
+	if c.failCount.Add(1) == 4 {
+		c.forceClose(1000, "broadcast failed")
+	}
+}
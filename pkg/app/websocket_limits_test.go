@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/lxzan/gws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWSUser(uid int64) *UserEntity {
+	user := &UserEntity{UID: uid}
+	user.ID = strconv.FormatInt(uid, 10)
+	return user
+}
+
+func TestWebsocketServer_ConnectionLimits(t *testing.T) {
+	w := newClusterTestServer()
+	w.config.MaxConnections = 2
+	w.config.MaxConnectionsPerUser = 2
+
+	require.True(t, w.acquireConn())
+	require.True(t, w.acquireConn())
+	assert.False(t, w.acquireConn(), "server is full")
+	w.releaseConn()
+	assert.True(t, w.acquireConn())
+
+	user := testWSUser(1)
+	first := &WebsocketClient{conn: &gws.Conn{}}
+	second := &WebsocketClient{conn: &gws.Conn{}}
+	third := &WebsocketClient{conn: &gws.Conn{}}
+
+	_, ok := w.addUserClientWithLimit(first, user)
+	require.True(t, ok)
+	clients, ok := w.addUserClientWithLimit(second, user)
+	require.True(t, ok)
+	assert.Len(t, clients, 2)
+
+	_, ok = w.addUserClientWithLimit(third, user)
+	assert.False(t, ok, "user is at the limit")
+	assert.Nil(t, third.User)
+
+	// Authorizing again on a connection already counted is not refused
+	_, ok = w.addUserClientWithLimit(first, user)
+	assert.True(t, ok)
+
+	_, ok = w.addUserClientWithLimit(third, testWSUser(2))
+	assert.True(t, ok, "limit is per user")
+}
+
+// TestWebsocketClient_SlowConsumer verifies a broadcast that does not fit the send queue is dropped,
+// the connection is flagged as a slow consumer, and the device replays the dropped event on resume.
+// TestWebsocketClient_SlowConsumer 验证发送队列放不下的广播会被丢弃、连接被标记为慢消费者，
+// 且设备在恢复同步时会重放被丢弃的事件。
+func TestWebsocketClient_SlowConsumer(t *testing.T) {
+	w := newClusterTestServer()
+	w.journal.register("1", "phone")
+	w.journal.commit("1", "phone", 0)
+
+	c := &WebsocketClient{Server: w, User: testWSUser(1), DeviceID: "phone"}
+	c.initContext("trace")
+	c.outbox = make(chan outboundFrame, 1)
+
+	first := w.recordSync("1", "NoteSyncModify", &Res{})
+	c.queueBroadcast(gws.OpcodeText, []byte("first"), first, "NoteSyncModify")
+	assert.False(t, c.slowConsumer.Load())
+
+	second := w.recordSync("1", "NoteSyncModify", &Res{})
+	c.queueBroadcast(gws.OpcodeText, []byte("second"), second, "NoteSyncModify")
+	assert.True(t, c.slowConsumer.Load())
+
+	// The queued frame fails when the connection closes
+	c.cancelContext()
+	c.drainOutbox()
+	assert.Empty(t, c.outbox)
+
+	events, _, complete := w.journal.since("1", "phone")
+	require.True(t, complete)
+	assert.Equal(t, []uint64{first, second}, journalSeqs(events))
+
+	// Direct writes do not block on a closed connection
+	assert.ErrorIs(t, c.writeMessage(gws.OpcodeText, []byte("late")), gws.ErrConnClosed)
+	assert.ErrorIs(t, c.WsCtx.Err(), context.Canceled)
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/json"
+	"github.com/ugorji/go/codec"
+)
+
+// ProtocolMsgpack value of the "protocol" handshake query that switches a connection to MessagePack.
+// After the Authorization response, which stays a JSON text frame, messages carrying an action are
+// exchanged as binary frames: the 2-byte prefix "mp", the action, "|" and the MessagePack body.
+// The body holds the same fields as the JSON message.
+// ProtocolMsgpack 握手 query "protocol" 取该值时连接切换为 MessagePack。
+// Authorization 响应仍为 JSON 文本帧，此后带动作的消息以二进制帧收发：2 字节前缀 "mp"、动作、"|" 与 MessagePack 正文，
+// 正文字段与 JSON 消息相同。
+const ProtocolMsgpack = "msgpack"
+
+// msgpackPrefix binary frame prefix of MessagePack messages
+// msgpackPrefix MessagePack 消息的二进制帧前缀
+const msgpackPrefix = "mp"
+
+// msgpackHandle decodes maps with string keys and strings as Go strings, so bodies convert back to JSON
+// msgpackHandle 将映射解码为字符串键、字符串解码为 Go 字符串，使正文可转换回 JSON
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{}
+	h.WriteExt = true
+	h.RawToString = true
+	h.MapType = reflect.TypeOf(map[string]any(nil))
+	return h
+}()
+
+// UseMsgpack reports whether this connection negotiated MessagePack.
+// UseMsgpack 返回该连接是否已协商使用 MessagePack。
+func (c *WebsocketClient) UseMsgpack() bool {
+	c.infoMu.RLock()
+	defer c.infoMu.RUnlock()
+	return c.useMsgpack
+}
+
+// setUseMsgpack sets the useMsgpack field under infoMu
+// setUseMsgpack 在 infoMu 保护下设置 useMsgpack 字段
+func (c *WebsocketClient) setUseMsgpack(useMsgpack bool) {
+	c.infoMu.Lock()
+	defer c.infoMu.Unlock()
+	c.useMsgpack = useMsgpack
+}
+
+// encodeMsgpackFrame encodes a response as a MessagePack binary frame.
+// The response goes through JSON first, so custom JSON marshalers (timex.Time etc.) keep their format.
+// encodeMsgpackFrame 将响应编码为 MessagePack 二进制帧。
+// 响应先经过 JSON 编码，自定义 JSON 序列化（timex.Time 等）保持原有格式。
+func encodeMsgpackFrame(action string, content *Res) ([]byte, error) {
+	jsonBytes, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	dec := stdjson.NewDecoder(bytes.NewReader(jsonBytes))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	if err := codec.NewEncoderBytes(&body, msgpackHandle).Encode(msgpackNumbers(value)); err != nil {
+		return nil, err
+	}
+	frame := make([]byte, 0, len(msgpackPrefix)+len(action)+1+len(body))
+	frame = append(frame, msgpackPrefix...)
+	frame = append(frame, action...)
+	frame = append(frame, '|')
+	return append(frame, body...), nil
+}
+
+// msgpackNumbers replaces the json.Number values with integers, or floats when they have a fraction
+// msgpackNumbers 将 json.Number 替换为整数，带小数时替换为浮点数
+func msgpackNumbers(value any) any {
+	switch v := value.(type) {
+	case stdjson.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, item := range v {
+			v[k] = msgpackNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = msgpackNumbers(item)
+		}
+	}
+	return value
+}
+
+// decodeMsgpackMessage splits the payload of an inbound MessagePack frame (prefix removed)
+// and converts its body to JSON, so handlers bind it like a text message
+// decodeMsgpackMessage 拆分入站 MessagePack 帧（已去除前缀）的负载并将正文转换为 JSON，使处理器可像文本消息一样绑定
+func decodeMsgpackMessage(payload []byte) (WebSocketMessage, error) {
+	index := bytes.IndexByte(payload, '|')
+	if index <= 0 {
+		return WebSocketMessage{}, fmt.Errorf("msgpack message without action")
+	}
+	var value any
+	if err := codec.NewDecoderBytes(payload[index+1:], msgpackHandle).Decode(&value); err != nil {
+		return WebSocketMessage{}, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return WebSocketMessage{}, err
+	}
+	return WebSocketMessage{Type: string(payload[:index]), Data: data}, nil
+}
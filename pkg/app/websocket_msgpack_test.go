@@ -0,0 +1,41 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+func TestEncodeMsgpackFrame(t *testing.T) {
+	content := &Res{Code: 1, Status: true, Vault: "MyVault", Data: map[string]any{"path": "a.md", "mtime": int64(1714557600123), "ratio": 0.5}}
+	frame, err := encodeMsgpackFrame("NoteSyncModify", content)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(frame), "mpNoteSyncModify|"))
+
+	var got map[string]any
+	body := frame[len("mpNoteSyncModify|"):]
+	require.NoError(t, codec.NewDecoderBytes(body, msgpackHandle).Decode(&got))
+	assert.Equal(t, "MyVault", got["vault"])
+	assert.Equal(t, true, got["status"])
+	data := got["data"].(map[string]any)
+	assert.Equal(t, "a.md", data["path"])
+	assert.EqualValues(t, 1714557600123, data["mtime"], "integers keep their precision")
+	assert.Equal(t, 0.5, data["ratio"])
+	assert.NotContains(t, got, "details", "omitted JSON fields stay omitted")
+}
+
+func TestDecodeMsgpackMessage(t *testing.T) {
+	var body []byte
+	require.NoError(t, codec.NewEncoderBytes(&body, msgpackHandle).Encode(map[string]any{"vault": "MyVault", "lastTime": int64(42), "tags": []any{"a"}}))
+
+	msg, err := decodeMsgpackMessage(append([]byte("NoteSync|"), body...))
+	require.NoError(t, err)
+	assert.Equal(t, "NoteSync", msg.Type)
+	assert.JSONEq(t, `{"vault":"MyVault","lastTime":42,"tags":["a"]}`, string(msg.Data))
+
+	_, err = decodeMsgpackMessage(body)
+	assert.Error(t, err)
+}
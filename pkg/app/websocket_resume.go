@@ -0,0 +1,364 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/code"
+	"github.com/haierkeys/fast-note-sync-service/pkg/json"
+	"github.com/lxzan/gws"
+	"go.uber.org/zap"
+)
+
+// SyncResume actions: the client asks for a replay of the events missed since its device cursor,
+// the server answers with the replayed events followed by SyncResumeEnd
+// SyncResume 动作：客户端请求重放其设备游标之后错过的事件，服务端依次发送重放事件与 SyncResumeEnd
+const (
+	SyncResumeAction    = "SyncResume"
+	SyncResumeEndAction = "SyncResumeEnd"
+)
+
+// Default sync journal limits, used when WSConfig leaves them unset
+// 同步日志默认限制，WSConfig 未设置时使用
+const (
+	DefaultSyncJournalSize = 500
+	DefaultSyncJournalTTL  = 24 * time.Hour
+)
+
+// SyncResumeEndMessage result of a SyncResume request
+// SyncResumeEndMessage SyncResume 请求的结果
+type SyncResumeEndMessage struct {
+	Cursor       uint64 `json:"cursor"`       // Device cursor after the replay // 重放后的设备游标
+	Replayed     int    `json:"replayed"`     // Number of replayed events // 重放的事件数
+	NeedFullSync bool   `json:"needFullSync"` // Events since the cursor are no longer queued, the client must run a full sync from its lastTime // 游标之后的事件已不在队列中，客户端需要从 lastTime 执行完整同步
+}
+
+// syncEvent one broadcast sync event queued for devices that are offline
+// syncEvent 为离线设备排队的一条同步广播事件
+type syncEvent struct {
+	seq     uint64
+	action  string
+	content *Res
+	at      time.Time
+}
+
+// deviceCursor last event delivered to a device
+// deviceCursor 已投递给设备的最后一条事件
+type deviceCursor struct {
+	seq uint64
+	// frozen stops live deliveries from moving the cursor while the device may have missed events:
+	// from auth until its SyncResume, and after a failed delivery
+	// frozen 在设备可能错过事件期间阻止实时投递推进游标：从鉴权到 SyncResume 之间，以及投递失败之后
+	frozen bool
+	seenAt time.Time
+}
+
+// userJournal queued events and device cursors of one user
+// userJournal 单个用户的事件队列与设备游标
+type userJournal struct {
+	seq     uint64
+	events  []syncEvent
+	devices map[string]*deviceCursor
+}
+
+// syncJournal per-user bounded queue of sync events with a cursor per device, letting a reconnecting
+// device replay only what it missed instead of running a full NoteSync/FileSync
+// syncJournal 按用户保存的有界同步事件队列，每个设备一个游标，
+// 使重连的设备只需重放错过的事件，而无需执行完整的 NoteSync/FileSync
+type syncJournal struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	actions map[string]bool
+	users   map[string]*userJournal
+	now     func() time.Time
+}
+
+func newSyncJournal(size int, ttl time.Duration, actions []string) *syncJournal {
+	if size <= 0 {
+		size = DefaultSyncJournalSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultSyncJournalTTL
+	}
+	j := &syncJournal{
+		size:    size,
+		ttl:     ttl,
+		actions: make(map[string]bool, len(actions)),
+		users:   make(map[string]*userJournal),
+		now:     time.Now,
+	}
+	for _, a := range actions {
+		j.actions[a] = true
+	}
+	return j
+}
+
+// user returns the journal of uid, creating it if needed (requires mu lock)
+func (j *syncJournal) user(uid string) *userJournal {
+	u, ok := j.users[uid]
+	if !ok {
+		u = &userJournal{devices: make(map[string]*deviceCursor)}
+		j.users[uid] = u
+	}
+	return u
+}
+
+// prune drops expired events and device cursors (requires mu lock)
+func (j *syncJournal) prune(uid string, u *userJournal) {
+	deadline := j.now().Add(-j.ttl)
+	drop := 0
+	for drop < len(u.events) && u.events[drop].at.Before(deadline) {
+		drop++
+	}
+	if over := len(u.events) - drop - j.size; over > 0 {
+		drop += over
+	}
+	if drop > 0 {
+		u.events = append(u.events[:0:0], u.events[drop:]...)
+	}
+	for id, d := range u.devices {
+		if d.seenAt.Before(deadline) {
+			delete(u.devices, id)
+		}
+	}
+	if len(u.events) == 0 && len(u.devices) == 0 {
+		delete(j.users, uid)
+	}
+}
+
+// record queues a broadcast event and returns its sequence number, 0 when the action is not resumable
+// or no device of the user has registered a cursor
+// record 将广播事件入队并返回其序号；动作不可重放或该用户没有注册游标的设备时返回 0
+func (j *syncJournal) record(uid, action string, content *Res) uint64 {
+	if !j.actions[action] {
+		return 0
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	u, ok := j.users[uid]
+	if !ok {
+		return 0
+	}
+	u.seq++
+	u.events = append(u.events, syncEvent{seq: u.seq, action: action, content: content, at: j.now()})
+	j.prune(uid, u)
+	return u.seq
+}
+
+// register binds a device to the journal at auth and returns its cursor; the cursor is frozen until
+// the device resumes, so live events delivered before SyncResume cannot skip the missed ones
+// register 在鉴权时将设备绑定到日志并返回其游标；游标在设备恢复前保持冻结，
+// 避免 SyncResume 之前投递的实时事件越过错过的事件
+func (j *syncJournal) register(uid, device string) uint64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	u := j.user(uid)
+	d, ok := u.devices[device]
+	if !ok {
+		// A new device starts at the head, it has to run a full sync anyway
+		// 新设备从队列头开始，它本就需要执行一次完整同步
+		d = &deviceCursor{seq: u.seq}
+		u.devices[device] = d
+	}
+	d.frozen = true
+	d.seenAt = j.now()
+	return d.seq
+}
+
+// delivered moves the cursor of a device after a live delivery of event seq
+// delivered 在事件 seq 实时投递后推进设备游标
+func (j *syncJournal) delivered(uid, device string, seq uint64, ok bool) {
+	if seq == 0 || device == "" {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	u, exists := j.users[uid]
+	if !exists {
+		return
+	}
+	d, exists := u.devices[device]
+	if !exists {
+		return
+	}
+	d.seenAt = j.now()
+	if !ok {
+		d.seq = min(d.seq, seq-1)
+		d.frozen = true
+		return
+	}
+	if !d.frozen && seq > d.seq {
+		d.seq = seq
+	}
+}
+
+// since returns the events queued after the cursor of a device, complete is false when some of them
+// were already dropped or the device is unknown
+// since 返回设备游标之后排队的事件；部分事件已被丢弃或设备未知时 complete 为 false
+func (j *syncJournal) since(uid, device string) (events []syncEvent, head uint64, complete bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	u, ok := j.users[uid]
+	if !ok {
+		return nil, 0, false
+	}
+	j.prune(uid, u)
+	d, ok := u.devices[device]
+	if !ok {
+		return nil, u.seq, false
+	}
+	if d.seq < u.seq && (len(u.events) == 0 || u.events[0].seq > d.seq+1) {
+		return nil, u.seq, false
+	}
+	for _, e := range u.events {
+		if e.seq > d.seq {
+			events = append(events, e)
+		}
+	}
+	return events, u.seq, true
+}
+
+// commit sets the cursor of a device after a replay or a full sync and resumes live tracking
+// commit 在重放或完整同步后设置设备游标，并恢复实时跟踪
+func (j *syncJournal) commit(uid, device string, seq uint64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	u, ok := j.users[uid]
+	if !ok {
+		return
+	}
+	d, ok := u.devices[device]
+	if !ok {
+		return
+	}
+	d.seq = seq
+	d.frozen = false
+	d.seenAt = j.now()
+}
+
+// pending returns per device of uid the number of events queued since its cursor, dropped ones included
+// pending 返回 uid 每个设备游标之后排队的事件数量，包含已被丢弃的事件
+func (j *syncJournal) pending(uid string) map[string]int64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	u, ok := j.users[uid]
+	if !ok {
+		return nil
+	}
+	j.prune(uid, u)
+	counts := make(map[string]int64, len(u.devices))
+	for id, d := range u.devices {
+		counts[id] = int64(u.seq - d.seq)
+	}
+	return counts
+}
+
+// UseSyncResume enables the resumable sync cursor for the given broadcast actions and registers the
+// SyncResume handler; connections opened with a deviceId query parameter get a cursor at auth
+// UseSyncResume 为指定的广播动作启用可恢复同步游标并注册 SyncResume 处理器；
+// 携带 deviceId 查询参数建立的连接会在鉴权时获得游标
+func (w *WebsocketServer) UseSyncResume(actions ...string) {
+	w.journal = newSyncJournal(w.config.SyncJournalSize, w.config.SyncJournalTTL, actions)
+	w.Use(SyncResumeAction, w.SyncResume)
+}
+
+// recordSync queues a broadcast event for resumable devices, returns 0 when nothing was queued
+// recordSync 为可恢复的设备将广播事件入队，未入队时返回 0
+func (w *WebsocketServer) recordSync(uid, action string, content *Res) uint64 {
+	if w.journal == nil || action == "" {
+		return 0
+	}
+	return w.journal.record(uid, action, content)
+}
+
+// syncDelivered moves the cursor of the client's device after a live delivery
+// syncDelivered 在实时投递后推进客户端所属设备的游标
+func (w *WebsocketServer) syncDelivered(c *WebsocketClient, seq uint64, ok bool) {
+	if w.journal == nil || c.User == nil {
+		return
+	}
+	w.journal.delivered(c.User.ID, c.DeviceID, seq, ok)
+}
+
+// PendingSyncEvents returns per device key the number of broadcast sync events a device of the user
+// has not received yet, nil when resumable sync is disabled
+// PendingSyncEvents 返回用户每个设备（按设备标识）尚未收到的同步广播事件数量，未启用可恢复同步时返回 nil
+func (w *WebsocketServer) PendingSyncEvents(uid int64) map[string]int64 {
+	if w.journal == nil {
+		return nil
+	}
+	return w.journal.pending(strconv.FormatInt(uid, 10))
+}
+
+// SyncResume replays the sync events queued since the device cursor of the client, or tells it to fall
+// back to a full sync when they are no longer available
+// SyncResume 重放客户端设备游标之后排队的同步事件；事件已不可用时通知客户端回退到完整同步
+func (w *WebsocketServer) SyncResume(c *WebsocketClient, msg *WebSocketMessage) {
+	if w.journal == nil || c.User == nil {
+		return
+	}
+	if c.DeviceID == "" {
+		c.ToResponse(code.ErrorInvalidParams.WithDetails("deviceId is required to resume sync"), SyncResumeEndAction)
+		return
+	}
+
+	events, head, complete := w.journal.since(c.User.ID, c.DeviceID)
+	if !complete {
+		// The full sync the client runs next covers every event up to now, later ones arrive live
+		// 客户端随后执行的完整同步覆盖截至目前的所有事件，之后的事件会实时到达
+		w.journal.commit(c.User.ID, c.DeviceID, head)
+		log(LogInfo, "WS SyncResume needs full sync", zap.String("uid", c.User.ID), zap.String("deviceId", c.DeviceID), zap.String("traceID", c.TraceID))
+		c.ToResponse(code.Success.WithData(&SyncResumeEndMessage{Cursor: head, NeedFullSync: true}), SyncResumeEndAction)
+		return
+	}
+
+	for _, e := range events {
+		if !c.acceptsBroadcast(e.action, e.content) {
+			continue
+		}
+		if err := c.writeSyncEvent(e.action, e.content); err != nil {
+			// The cursor stays frozen before the failed event, the next SyncResume replays it again
+			// 游标保持冻结在失败事件之前，下一次 SyncResume 会再次重放
+			log(LogWarn, "WS SyncResume replay failed", zap.String("uid", c.User.ID), zap.String("deviceId", c.DeviceID), zap.Uint64("seq", e.seq), zap.Error(err))
+			w.journal.delivered(c.User.ID, c.DeviceID, e.seq, false)
+			return
+		}
+	}
+	w.journal.commit(c.User.ID, c.DeviceID, head)
+
+	log(LogInfo, "WS SyncResume", zap.String("uid", c.User.ID), zap.String("deviceId", c.DeviceID), zap.Int("replayed", len(events)), zap.Uint64("cursor", head))
+	c.ToResponse(code.Success.WithData(&SyncResumeEndMessage{Cursor: head, Replayed: len(events)}), SyncResumeEndAction)
+}
+
+// registerSyncDevice binds the device of an authenticated client to the journal, returns false
+// when resumable sync is disabled or the client did not declare a device
+// registerSyncDevice 将已认证客户端的设备绑定到日志；未启用可恢复同步或客户端未声明设备时返回 false
+func (w *WebsocketServer) registerSyncDevice(c *WebsocketClient) (uint64, bool) {
+	if w.journal == nil || c.User == nil || c.DeviceID == "" {
+		return 0, false
+	}
+	return w.journal.register(c.User.ID, c.DeviceID), true
+}
+
+// writeSyncEvent writes one broadcast event to the client, protobuf or MessagePack encoded when negotiated
+// writeSyncEvent 向客户端写入一条广播事件，已协商 protobuf 或 MessagePack 时使用对应编码
+func (c *WebsocketClient) writeSyncEvent(action string, content *Res) error {
+	if c.UseProtobuf() && c.Server.ProtobufEncoder != nil {
+		if pbBytes, err := c.Server.ProtobufEncoder(action, content); err == nil {
+			return c.writeMessage(gws.OpcodeBinary, pbBytes)
+		}
+	}
+	if c.UseMsgpack() {
+		if mpBytes, err := encodeMsgpackFrame(action, content); err == nil {
+			return c.writeMessage(gws.OpcodeBinary, mpBytes)
+		}
+	}
+	mBytes, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	return c.writeMessage(gws.OpcodeText, []byte(fmt.Sprintf(`%s|%s`, action, string(mBytes))))
+}
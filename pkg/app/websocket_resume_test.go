@@ -0,0 +1,99 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func journalSeqs(events []syncEvent) []uint64 {
+	seqs := make([]uint64, 0, len(events))
+	for _, e := range events {
+		seqs = append(seqs, e.seq)
+	}
+	return seqs
+}
+
+func TestSyncJournal_ResumeReplaysMissedEvents(t *testing.T) {
+	j := newSyncJournal(10, time.Hour, []string{"NoteSyncModify"})
+
+	// Users without a registered device queue nothing, other actions are never queued
+	assert.Zero(t, j.record("1", "NoteSyncModify", &Res{}))
+	assert.Equal(t, uint64(0), j.register("1", "phone"))
+	assert.Zero(t, j.record("1", "BackupProgress", &Res{}))
+
+	// Live deliveries before SyncResume must not move the frozen cursor
+	seq := j.record("1", "NoteSyncModify", &Res{Vault: "v"})
+	require.Equal(t, uint64(1), seq)
+	j.delivered("1", "phone", seq, true)
+
+	events, head, complete := j.since("1", "phone")
+	require.True(t, complete)
+	assert.Equal(t, uint64(1), head)
+	assert.Equal(t, []uint64{1}, journalSeqs(events))
+	assert.Equal(t, "v", events[0].content.Vault)
+
+	j.commit("1", "phone", head)
+	j.delivered("1", "phone", j.record("1", "NoteSyncModify", &Res{}), true)
+
+	// The device goes offline, then comes back
+	j.record("1", "NoteSyncModify", &Res{})
+	j.record("1", "NoteSyncModify", &Res{})
+	assert.Equal(t, uint64(2), j.register("1", "phone"))
+
+	events, head, complete = j.since("1", "phone")
+	require.True(t, complete)
+	assert.Equal(t, uint64(4), head)
+	assert.Equal(t, []uint64{3, 4}, journalSeqs(events))
+	assert.Equal(t, map[string]int64{"phone": 2}, j.pending("1"))
+	assert.Nil(t, j.pending("2"))
+}
+
+func TestSyncJournal_FailedDeliveryIsReplayed(t *testing.T) {
+	j := newSyncJournal(10, time.Hour, []string{"FileSyncUpdate"})
+	j.register("1", "desktop")
+	j.commit("1", "desktop", 0)
+
+	first := j.record("1", "FileSyncUpdate", &Res{})
+	second := j.record("1", "FileSyncUpdate", &Res{})
+	j.delivered("1", "desktop", second, true)
+	j.delivered("1", "desktop", first, false)
+	j.delivered("1", "desktop", j.record("1", "FileSyncUpdate", &Res{}), true)
+
+	events, _, complete := j.since("1", "desktop")
+	require.True(t, complete)
+	assert.Equal(t, []uint64{1, 2, 3}, journalSeqs(events))
+}
+
+func TestSyncJournal_NeedsFullSync(t *testing.T) {
+	now := time.Now()
+	j := newSyncJournal(2, time.Hour, []string{"NoteSyncDelete"})
+	j.now = func() time.Time { return now }
+
+	_, _, complete := j.since("1", "tablet")
+	assert.False(t, complete, "unknown user")
+
+	j.register("1", "tablet")
+	j.register("1", "phone")
+	for i := 0; i < 3; i++ {
+		j.record("1", "NoteSyncDelete", &Res{})
+	}
+
+	// Event 1 was dropped by the size limit
+	_, head, complete := j.since("1", "tablet")
+	assert.False(t, complete)
+	assert.Equal(t, uint64(3), head)
+
+	_, _, complete = j.since("1", "laptop")
+	assert.False(t, complete, "unknown device")
+
+	// Expired events and idle devices are forgotten
+	j.commit("1", "tablet", 3)
+	j.record("1", "NoteSyncDelete", &Res{})
+	now = now.Add(2 * time.Hour)
+	_, _, complete = j.since("1", "tablet")
+	assert.False(t, complete)
+	assert.Empty(t, j.users)
+}
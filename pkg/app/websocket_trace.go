@@ -0,0 +1,89 @@
+package app
+
+import (
+	"github.com/haierkeys/fast-note-sync-service/pkg/synctrace"
+	"go.uber.org/zap"
+)
+
+// traceMaxPayload longest message payload copied into a sync trace
+// traceMaxPayload 复制到同步跟踪中的消息内容最大长度
+const traceMaxPayload = 4096
+
+// UseSyncTrace writes the messages, responses and broadcasts of the users traced by tracer to their
+// trace files
+// UseSyncTrace 将 tracer 跟踪的用户的消息、响应与广播写入其跟踪文件
+func (w *WebsocketServer) UseSyncTrace(tracer *synctrace.Tracer) {
+	w.syncTrace = tracer
+}
+
+// syncTrace returns the sync trace logger of the connected user, nil when the user is not traced
+// syncTrace 返回已连接用户的同步跟踪日志器，用户未被跟踪时为 nil
+func (c *WebsocketClient) syncTrace() *zap.Logger {
+	if c.Server == nil || c.User == nil {
+		return nil
+	}
+	if trace := c.Server.syncTrace.Logger(c.User.UID); trace != nil {
+		return trace.With(zap.String("traceID", c.TraceID), zap.String("client", c.ClientName()))
+	}
+	return nil
+}
+
+// traceInbound records a message received from the client; JSON payloads are kept at debug level
+// traceInbound 记录从客户端收到的消息；JSON 内容在 debug 级别保留
+func (c *WebsocketClient) traceInbound(msg *WebSocketMessage, encoding string) {
+	trace := c.syncTrace()
+	if trace == nil {
+		return
+	}
+	trace.Info("WS receive", zap.String("action", msg.Type), zap.String("encoding", encoding), zap.Int("size", len(msg.Data)))
+	if encoding != "protobuf" {
+		trace.Debug("WS receive payload", zap.String("action", msg.Type), zap.ByteString("data", truncatePayload(msg.Data)))
+	}
+}
+
+// traceOutbound records a response sent to the client; its data is kept at debug level
+// traceOutbound 记录发送给客户端的响应；响应数据在 debug 级别保留
+func (c *WebsocketClient) traceOutbound(action string, content *Res) {
+	trace := c.syncTrace()
+	if trace == nil {
+		return
+	}
+	trace.Info("WS send",
+		zap.String("action", action),
+		zap.Int("code", content.Code),
+		zap.Any("details", content.Details),
+		zap.Any("path", content.Path))
+	trace.Debug("WS send payload", zap.String("action", action), zap.Any("data", content.Data))
+}
+
+// traceBroadcast records the fan-out of a broadcast: the connections it is sent to and those that
+// left it out
+// traceBroadcast 记录广播的扇出：发送到的连接与不接收的连接
+func traceBroadcast(trace *zap.Logger, action string, seq uint64, content *Res, targets, skipped []*WebsocketClient) {
+	trace.Info("WS broadcast",
+		zap.String("action", action),
+		zap.Uint64("seq", seq),
+		zap.Any("vault", content.Vault),
+		zap.Strings("targets", traceClients(targets)),
+		zap.Strings("skipped", traceClients(skipped)))
+	trace.Debug("WS broadcast payload", zap.String("action", action), zap.Any("data", content.Data))
+}
+
+// traceClients names connections as traceID/client name
+// traceClients 以 traceID/客户端名称 标识连接
+func traceClients(clients []*WebsocketClient) []string {
+	names := make([]string, 0, len(clients))
+	for _, uc := range clients {
+		names = append(names, uc.TraceID+"/"+uc.ClientName())
+	}
+	return names
+}
+
+// truncatePayload cuts payloads longer than traceMaxPayload
+// truncatePayload 截断超过 traceMaxPayload 的内容
+func truncatePayload(data []byte) []byte {
+	if len(data) > traceMaxPayload {
+		return data[:traceMaxPayload]
+	}
+	return data
+}
@@ -0,0 +1,74 @@
+// Package atom writes Atom (RFC 4287) syndication feeds.
+// Package atom 生成 Atom (RFC 4287) 订阅源。
+package atom
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Namespace Atom XML namespace
+// Namespace Atom XML 命名空间
+const Namespace = "http://www.w3.org/2005/Atom"
+
+// Feed an Atom feed
+// Feed Atom 订阅源
+type Feed struct {
+	XMLName   xml.Name `xml:"feed"`
+	Namespace string   `xml:"xmlns,attr"`
+	ID        string   `xml:"id"`
+	Title     string   `xml:"title"`
+	Subtitle  string   `xml:"subtitle,omitempty"`
+	Updated   Time     `xml:"updated"`
+	Generator string   `xml:"generator,omitempty"`
+	Links     []Link   `xml:"link"`
+	Entries   []*Entry `xml:"entry"`
+}
+
+// Entry an Atom entry
+// Entry Atom 条目
+type Entry struct {
+	ID        string `xml:"id"`
+	Title     string `xml:"title"`
+	Published Time   `xml:"published"`
+	Updated   Time   `xml:"updated"`
+	Links     []Link `xml:"link,omitempty"`
+	Summary   *Text  `xml:"summary,omitempty"`
+	Content   *Text  `xml:"content,omitempty"`
+}
+
+// Link an Atom link, Rel defaults to "alternate" when empty
+// Link Atom 链接，Rel 为空时默认为 "alternate"
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// Text a text construct, Type is "text" or "html"
+// Text 文本结构，Type 为 "text" 或 "html"
+type Text struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Time a timestamp encoded in RFC 3339
+// Time 以 RFC 3339 编码的时间戳
+type Time time.Time
+
+// MarshalText implements encoding.TextMarshaler
+// MarshalText 实现 encoding.TextMarshaler
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(time.Time(t).UTC().Format(time.RFC3339)), nil
+}
+
+// Marshal encodes the feed as an indented XML document
+// Marshal 将订阅源编码为带缩进的 XML 文档
+func (f *Feed) Marshal() ([]byte, error) {
+	f.Namespace = Namespace
+	out, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
@@ -0,0 +1,42 @@
+package atom
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFeed_Marshal verifies the feed carries the Atom namespace, UTC RFC 3339 timestamps and escaped HTML content.
+// TestFeed_Marshal 验证订阅源包含 Atom 命名空间、UTC RFC 3339 时间戳以及转义后的 HTML 内容。
+func TestFeed_Marshal(t *testing.T) {
+	updated := time.Date(2024, 3, 2, 9, 30, 0, 0, time.FixedZone("", 3600))
+	feed := &Feed{
+		ID:      "tag:example.com,2024-03-01:share/1",
+		Title:   "Blog",
+		Updated: Time(updated),
+		Links:   []Link{{Href: "https://example.com/feed/t.xml", Rel: "self", Type: "application/atom+xml"}},
+		Entries: []*Entry{{
+			ID:        "tag:example.com,2024-03-01:share/1/note/7",
+			Title:     "Hello & welcome",
+			Published: Time(updated),
+			Updated:   Time(updated),
+			Summary:   &Text{Type: "text", Body: "Hi"},
+			Content:   &Text{Type: "html", Body: "<p>Hi</p>"},
+		}},
+	}
+
+	out, err := feed.Marshal()
+	require.NoError(t, err)
+	s := string(out)
+
+	assert.True(t, strings.HasPrefix(s, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, s, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, s, `<updated>2024-03-02T08:30:00Z</updated>`)
+	assert.Contains(t, s, `<link href="https://example.com/feed/t.xml" rel="self" type="application/atom+xml"></link>`)
+	assert.Contains(t, s, `<title>Hello &amp; welcome</title>`)
+	assert.Contains(t, s, `<content type="html">&lt;p&gt;Hi&lt;/p&gt;</content>`)
+	assert.NotContains(t, s, "<subtitle>")
+}
@@ -0,0 +1,551 @@
+// Package atrest encrypts files stored on disk (encryption at rest).
+// Files are written in a chunked AES-256-GCM format that supports random access, so encrypted
+// attachments can still be served with Range requests. Plaintext files written before encryption
+// was enabled stay readable; the reencrypt command migrates them.
+// Package atrest 对落盘文件进行静态加密。
+// 文件以分块 AES-256-GCM 格式写入，支持随机访问，加密后的附件仍可按 Range 请求输出。
+// 启用加密前写入的明文文件仍可读取，可通过 reencrypt 命令迁移。
+package atrest
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// KeySize is the length of a master key in bytes
+	// KeySize 主密钥字节长度
+	KeySize = 32
+
+	headerSize = 32
+	chunkSize  = 64 << 10
+	tagSize    = 16
+	version    = 1
+)
+
+var magic = [4]byte{'F', 'N', 'S', 'E'}
+
+var (
+	// ErrNoKey is returned when reading an encrypted file while no key is configured
+	// ErrNoKey 未配置密钥时读取加密文件返回
+	ErrNoKey = errors.New("atrest: file is encrypted but no encryption key is configured")
+	// ErrUnknownKey is returned when a file was encrypted with a key that is not in the keyring
+	// ErrUnknownKey 文件使用的密钥不在密钥环中时返回
+	ErrUnknownKey = errors.New("atrest: file is encrypted with an unknown key")
+	// ErrCorrupt is returned when an encrypted file fails authentication or is truncated
+	// ErrCorrupt 加密文件校验失败或被截断时返回
+	ErrCorrupt = errors.New("atrest: encrypted file is corrupt or truncated")
+)
+
+// KeyID identifies a master key without revealing it
+// KeyID 标识主密钥而不泄露密钥本身
+type KeyID [8]byte
+
+// String returns the hex form of the key ID
+// String 返回密钥 ID 的十六进制形式
+func (id KeyID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Key is a master key used to derive per-file keys
+// Key 用于派生单文件密钥的主密钥
+type Key struct {
+	secret []byte
+	id     KeyID
+}
+
+// NewKey creates a key from KeySize raw bytes
+// NewKey 由 KeySize 字节原始数据创建密钥
+func NewKey(secret []byte) (*Key, error) {
+	if len(secret) != KeySize {
+		return nil, fmt.Errorf("atrest: key must be %d bytes, got %d", KeySize, len(secret))
+	}
+	sum := sha256.Sum256(append([]byte("fns-at-rest-key-id:"), secret...))
+	k := &Key{secret: append([]byte(nil), secret...)}
+	copy(k.id[:], sum[:])
+	return k, nil
+}
+
+// ParseKey parses a key written as base64 or 64 hex characters
+// ParseKey 解析 base64 或 64 位十六进制形式的密钥
+func ParseKey(s string) (*Key, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == hex.EncodedLen(KeySize) {
+		if b, err := hex.DecodeString(s); err == nil {
+			return NewKey(b)
+		}
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(s); err == nil && len(b) == KeySize {
+			return NewKey(b)
+		}
+	}
+	return nil, fmt.Errorf("atrest: key must be %d bytes encoded as base64 or hex", KeySize)
+}
+
+// LoadKeyFile reads a key file holding the key as base64, hex or KeySize raw bytes
+// LoadKeyFile 读取密钥文件，内容可为 base64、十六进制或 KeySize 字节原始数据
+func LoadKeyFile(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == KeySize {
+		return NewKey(data)
+	}
+	return ParseKey(string(data))
+}
+
+// GenerateKey returns a new random key encoded as base64
+// GenerateKey 生成新的随机密钥，以 base64 编码返回
+func GenerateKey() (string, error) {
+	b := make([]byte, KeySize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// ID returns the key ID recorded in the header of files it encrypts
+// ID 返回写入加密文件头部的密钥 ID
+func (k *Key) ID() KeyID {
+	return k.id
+}
+
+// fileAEAD derives the per-file AEAD from the salt in the file header
+// fileAEAD 由文件头中的盐派生单文件 AEAD
+func (k *Key) fileAEAD(salt []byte) (cipher.AEAD, error) {
+	fileKey := make([]byte, KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, k.secret, salt, []byte("fns-at-rest-v1")), fileKey); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(fileKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Keyring holds the key new files are encrypted with and older keys still accepted for reading
+// A nil *Keyring means encryption is disabled: files are written as plaintext.
+// Keyring 保存用于加密新文件的当前密钥，以及仍可用于读取的旧密钥
+// nil *Keyring 表示未启用加密：文件以明文写入。
+type Keyring struct {
+	current *Key
+	keys    map[KeyID]*Key
+}
+
+// NewKeyring creates a keyring encrypting with current and also decrypting with previous
+// NewKeyring 创建使用 current 加密、同时可用 previous 解密的密钥环
+func NewKeyring(current *Key, previous ...*Key) *Keyring {
+	kr := &Keyring{current: current, keys: make(map[KeyID]*Key)}
+	for _, k := range append(previous, current) {
+		if k != nil {
+			kr.keys[k.id] = k
+		}
+	}
+	return kr
+}
+
+// Current returns the key new files are encrypted with
+// Current 返回用于加密新文件的密钥
+func (kr *Keyring) Current() *Key {
+	if kr == nil {
+		return nil
+	}
+	return kr.current
+}
+
+// With returns a copy of the keyring that also decrypts with keys, the current key is kept
+// With 返回同时可使用 keys 解密的密钥环副本，保留当前密钥
+func (kr *Keyring) With(keys ...*Key) *Keyring {
+	out := NewKeyring(kr.Current(), keys...)
+	if kr != nil {
+		for id, k := range kr.keys {
+			out.keys[id] = k
+		}
+	}
+	return out
+}
+
+func (kr *Keyring) lookup(id KeyID) (*Key, error) {
+	if kr == nil {
+		return nil, ErrNoKey
+	}
+	k, ok := kr.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w (key id %s)", ErrUnknownKey, id)
+	}
+	return k, nil
+}
+
+var defaultKeyring atomic.Pointer[Keyring]
+
+// SetDefault sets the keyring used by the package-level helpers; nil disables encryption
+// SetDefault 设置包级辅助函数使用的密钥环；nil 表示禁用加密
+func SetDefault(kr *Keyring) {
+	defaultKeyring.Store(kr)
+}
+
+// Default returns the keyring used by the package-level helpers
+// Default 返回包级辅助函数使用的密钥环
+func Default() *Keyring {
+	return defaultKeyring.Load()
+}
+
+// Enabled reports whether new files are encrypted
+// Enabled 返回新写入的文件是否加密
+func Enabled() bool {
+	return Default().Current() != nil
+}
+
+// header is the fixed-size prefix of an encrypted file
+// magic(4) | version(1) | reserved(3) | key id(8) | salt(16)
+// header 加密文件的定长前缀
+type header struct {
+	keyID KeyID
+	salt  [16]byte
+}
+
+func (h *header) marshal() []byte {
+	b := make([]byte, headerSize)
+	copy(b[0:4], magic[:])
+	b[4] = version
+	copy(b[8:16], h.keyID[:])
+	copy(b[16:32], h.salt[:])
+	return b
+}
+
+// parseHeader parses b as a file header, ok is false for plaintext
+// parseHeader 将 b 解析为文件头，明文时 ok 为 false
+func parseHeader(b []byte) (h header, ok bool) {
+	if len(b) < headerSize || [4]byte(b[0:4]) != magic || b[4] != version {
+		return h, false
+	}
+	copy(h.keyID[:], b[8:16])
+	copy(h.salt[:], b[16:32])
+	return h, true
+}
+
+// IsEncrypted reports whether data starts with an encrypted file header
+// IsEncrypted 判断 data 是否以加密文件头开头
+func IsEncrypted(data []byte) bool {
+	_, ok := parseHeader(data)
+	return ok
+}
+
+// FileKeyID returns the key ID of an encrypted file, ok is false for plaintext files
+// FileKeyID 返回加密文件的密钥 ID，明文文件时 ok 为 false
+func FileKeyID(path string) (id KeyID, ok bool, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return id, false, err
+	}
+	defer f.Close()
+	b := make([]byte, headerSize)
+	if _, err := io.ReadFull(f, b); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return id, false, nil
+		}
+		return id, false, err
+	}
+	h, ok := parseHeader(b)
+	return h.keyID, ok, nil
+}
+
+func chunkNonce(aead cipher.AEAD, idx uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], idx)
+	return nonce
+}
+
+func chunkAAD(final bool) []byte {
+	if final {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// writer encrypts a stream chunk by chunk; the last chunk is flagged so truncation is detected
+// writer 按块加密数据流；最后一块带有结束标记，可检测截断
+type writer struct {
+	dst  io.Writer
+	aead cipher.AEAD
+	buf  []byte
+	idx  uint64
+	err  error
+}
+
+// NewWriter returns a writer encrypting into dst with the current key; Close must be called to
+// write the final chunk. A nil keyring returns a pass-through writer.
+// NewWriter 返回使用当前密钥加密写入 dst 的 writer；必须调用 Close 写入最后一块。nil 密钥环返回直通 writer。
+func (kr *Keyring) NewWriter(dst io.Writer) (io.WriteCloser, error) {
+	if kr == nil || kr.current == nil {
+		return nopWriteCloser{dst}, nil
+	}
+	h := header{keyID: kr.current.id}
+	if _, err := rand.Read(h.salt[:]); err != nil {
+		return nil, err
+	}
+	aead, err := kr.current.fileAEAD(h.salt[:])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dst.Write(h.marshal()); err != nil {
+		return nil, err
+	}
+	return &writer{dst: dst, aead: aead, buf: make([]byte, 0, chunkSize)}, nil
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n := 0
+	for len(p) > 0 {
+		// A full buffer is only flushed once more data arrives, so the final chunk is never left empty
+		// 缓冲区满后仅在有更多数据时才写出，保证最后一块不会为空
+		if len(w.buf) == chunkSize {
+			if w.err = w.flush(false); w.err != nil {
+				return n, w.err
+			}
+		}
+		m := copy(w.buf[len(w.buf):chunkSize], p)
+		w.buf = w.buf[:len(w.buf)+m]
+		p = p[m:]
+		n += m
+	}
+	return n, nil
+}
+
+func (w *writer) flush(final bool) error {
+	ct := w.aead.Seal(nil, chunkNonce(w.aead, w.idx), w.buf, chunkAAD(final))
+	w.idx++
+	w.buf = w.buf[:0]
+	_, err := w.dst.Write(ct)
+	return err
+}
+
+func (w *writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	w.err = w.flush(true)
+	if w.err == nil {
+		w.err = os.ErrClosed
+		return nil
+	}
+	return w.err
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// Reader decrypts an encrypted file with random access
+// Reader 以随机访问方式解密加密文件
+type Reader struct {
+	src    io.ReaderAt
+	aead   cipher.AEAD
+	size   int64
+	chunks int64
+	lastCT int64
+	off    int64
+
+	buf    []byte
+	bufIdx int64
+}
+
+// NewReader returns a reader over src, an encrypted file of encSize bytes
+// NewReader 返回 src 的解密 reader，src 为 encSize 字节的加密文件
+func (kr *Keyring) NewReader(src io.ReaderAt, encSize int64) (*Reader, error) {
+	hb := make([]byte, headerSize)
+	if _, err := src.ReadAt(hb, 0); err != nil {
+		return nil, ErrCorrupt
+	}
+	h, ok := parseHeader(hb)
+	if !ok {
+		return nil, ErrCorrupt
+	}
+	key, err := kr.lookup(h.keyID)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := key.fileAEAD(h.salt[:])
+	if err != nil {
+		return nil, err
+	}
+
+	body := encSize - headerSize
+	fullCT := int64(chunkSize + tagSize)
+	if body < tagSize {
+		return nil, ErrCorrupt
+	}
+	chunks := (body + fullCT - 1) / fullCT
+	lastCT := body - (chunks-1)*fullCT
+	if lastCT < tagSize {
+		return nil, ErrCorrupt
+	}
+	return &Reader{
+		src:    src,
+		aead:   aead,
+		size:   body - chunks*tagSize,
+		chunks: chunks,
+		lastCT: lastCT,
+		bufIdx: -1,
+	}, nil
+}
+
+// Size returns the plaintext size
+// Size 返回明文大小
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+func (r *Reader) load(idx int64) error {
+	if idx == r.bufIdx {
+		return nil
+	}
+	n := int64(chunkSize + tagSize)
+	final := idx == r.chunks-1
+	if final {
+		n = r.lastCT
+	}
+	ct := make([]byte, n)
+	if _, err := r.src.ReadAt(ct, headerSize+idx*int64(chunkSize+tagSize)); err != nil && !(errors.Is(err, io.EOF) && final) {
+		return err
+	}
+	pt, err := r.aead.Open(ct[:0], chunkNonce(r.aead, uint64(idx)), ct, chunkAAD(final))
+	if err != nil {
+		return ErrCorrupt
+	}
+	r.buf, r.bufIdx = pt, idx
+	return nil
+}
+
+// Read reads plaintext from the current offset
+// Read 从当前偏移读取明文
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.off >= r.size {
+		return 0, io.EOF
+	}
+	idx := r.off / chunkSize
+	if err := r.load(idx); err != nil {
+		return 0, err
+	}
+	n := copy(p, r.buf[r.off-idx*chunkSize:])
+	r.off += int64(n)
+	return n, nil
+}
+
+// ReadAt reads plaintext at off without moving the offset; it is not safe for concurrent use
+// ReadAt 在 off 处读取明文，不改变当前偏移；不可并发调用
+func (r *Reader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("atrest: negative offset")
+	}
+	n := 0
+	for n < len(p) {
+		if off >= r.size {
+			return n, io.EOF
+		}
+		idx := off / chunkSize
+		if err := r.load(idx); err != nil {
+			return n, err
+		}
+		m := copy(p[n:], r.buf[off-idx*chunkSize:])
+		n += m
+		off += int64(m)
+	}
+	return n, nil
+}
+
+// Seek sets the plaintext offset
+// Seek 设置明文偏移
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.off
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.New("atrest: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("atrest: negative position")
+	}
+	r.off = offset
+	return offset, nil
+}
+
+// Encrypt encrypts data with the current key; a nil keyring returns data unchanged
+// Encrypt 使用当前密钥加密 data；nil 密钥环时原样返回
+func (kr *Keyring) Encrypt(data []byte) ([]byte, error) {
+	if kr == nil || kr.current == nil {
+		return data, nil
+	}
+	var out sliceWriter
+	out.b = make([]byte, 0, headerSize+len(data)+(len(data)/chunkSize+1)*tagSize)
+	w, err := kr.NewWriter(&out)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return out.b, nil
+}
+
+// Decrypt decrypts data if it is encrypted, plaintext is returned unchanged
+// Decrypt 若 data 已加密则解密，明文原样返回
+func (kr *Keyring) Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	r, err := kr.NewReader(byteReaderAt(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, r.Size())
+	if _, err := io.ReadFull(r, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type sliceWriter struct{ b []byte }
+
+func (w *sliceWriter) Write(p []byte) (int, error) {
+	w.b = append(w.b, p...)
+	return len(p), nil
+}
+
+type byteReaderAt []byte
+
+func (b byteReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(b)) {
+		return 0, io.EOF
+	}
+	n := copy(p, b[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
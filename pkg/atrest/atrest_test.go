@@ -0,0 +1,183 @@
+package atrest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func mustKeyring(t *testing.T, previous ...*Key) *Keyring {
+	t.Helper()
+	encoded, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := ParseKey(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewKeyring(k, previous...)
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	t.Helper()
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestKeyring_RoundTrip verifies data of sizes around the chunk boundary survives encryption.
+func TestKeyring_RoundTrip(t *testing.T) {
+	kr := mustKeyring(t)
+	for _, n := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3*chunkSize + 17} {
+		plain := randomBytes(t, n)
+		enc, err := kr.Encrypt(plain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsEncrypted(enc) {
+			t.Fatalf("size %d: output lacks the header", n)
+		}
+		if n > 16 && bytes.Contains(enc, plain[:16]) {
+			t.Fatalf("size %d: plaintext leaked into the ciphertext", n)
+		}
+		got, err := kr.Decrypt(enc)
+		if err != nil {
+			t.Fatalf("size %d: %v", n, err)
+		}
+		if !bytes.Equal(got, plain) {
+			t.Fatalf("size %d: round trip mismatch", n)
+		}
+	}
+}
+
+// TestReader_Seek verifies random access reads across chunk boundaries.
+func TestReader_Seek(t *testing.T) {
+	kr := mustKeyring(t)
+	plain := randomBytes(t, 2*chunkSize+100)
+	enc, err := kr.Encrypt(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := kr.NewReader(bytes.NewReader(enc), int64(len(enc)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Size() != int64(len(plain)) {
+		t.Fatalf("Size = %d; want %d", r.Size(), len(plain))
+	}
+
+	off := int64(chunkSize - 10)
+	if _, err := r.Seek(off, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 30)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, plain[off:off+30]) {
+		t.Fatal("read across the chunk boundary returned wrong bytes")
+	}
+
+	tail := make([]byte, 200)
+	n, err := r.ReadAt(tail, int64(len(plain)-150))
+	if n != 150 || err != io.EOF || !bytes.Equal(tail[:n], plain[len(plain)-150:]) {
+		t.Fatalf("ReadAt at the tail = %d, %v", n, err)
+	}
+}
+
+// TestReader_DetectsTamperingAndTruncation verifies modified or chunk-aligned truncated files are rejected.
+func TestReader_DetectsTamperingAndTruncation(t *testing.T) {
+	kr := mustKeyring(t)
+	enc, err := kr.Encrypt(randomBytes(t, 2*chunkSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := append([]byte(nil), enc...)
+	tampered[headerSize+5] ^= 1
+	if _, err := kr.Decrypt(tampered); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("tampered: err = %v; want ErrCorrupt", err)
+	}
+
+	truncated := enc[:headerSize+chunkSize+tagSize]
+	if _, err := kr.Decrypt(truncated); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("truncated: err = %v; want ErrCorrupt", err)
+	}
+}
+
+// TestKeyring_Keys verifies plaintext passes through, unknown keys are rejected and previous keys still decrypt.
+func TestKeyring_Keys(t *testing.T) {
+	old := mustKeyring(t)
+	enc, err := old.Encrypt([]byte("secret note"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var disabled *Keyring
+	if got, err := disabled.Decrypt([]byte("plain")); err != nil || string(got) != "plain" {
+		t.Fatalf("plaintext = %q, %v", got, err)
+	}
+	if _, err := disabled.Decrypt(enc); !errors.Is(err, ErrNoKey) {
+		t.Fatalf("no key: err = %v; want ErrNoKey", err)
+	}
+	if _, err := mustKeyring(t).Decrypt(enc); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("other key: err = %v; want ErrUnknownKey", err)
+	}
+
+	rotated := mustKeyring(t, old.Current())
+	got, err := rotated.Decrypt(enc)
+	if err != nil || string(got) != "secret note" {
+		t.Fatalf("previous key = %q, %v", got, err)
+	}
+	if got, err := disabled.With(old.Current()).Decrypt(enc); err != nil || string(got) != "secret note" {
+		t.Fatalf("With = %q, %v", got, err)
+	}
+}
+
+// TestKeyring_RewriteAndMove verifies files are migrated in place and moved into the store encrypted.
+func TestKeyring_RewriteAndMove(t *testing.T) {
+	dir := t.TempDir()
+	kr := mustKeyring(t)
+	path := filepath.Join(dir, "content.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := (*Keyring)(nil).Rewrite(path, kr); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok, err := FileKeyID(path); err != nil || !ok || id != kr.Current().ID() {
+		t.Fatalf("FileKeyID = %v, %v, %v", id, ok, err)
+	}
+	if got, err := kr.ReadFile(path); err != nil || string(got) != "hello" {
+		t.Fatalf("ReadFile = %q, %v", got, err)
+	}
+
+	src := filepath.Join(dir, "upload")
+	if err := os.WriteFile(src, []byte("attachment"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(dir, "stored")
+	if err := kr.MoveFile(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Fatal("the source should be removed after the move")
+	}
+	f, err := kr.Open(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil || string(data) != "attachment" || f.Size() != int64(len("attachment")) {
+		t.Fatalf("Open = %q (size %d), %v", data, f.Size(), err)
+	}
+}
@@ -0,0 +1,195 @@
+package atrest
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// File is an opened file that reads as plaintext whether or not it is encrypted on disk
+// File 已打开的文件，无论磁盘上是否加密都以明文读取
+type File struct {
+	f    *os.File
+	r    readSeekerAt
+	size int64
+}
+
+type readSeekerAt interface {
+	io.ReadSeeker
+	io.ReaderAt
+}
+
+// Open opens path for reading with the keyring; plaintext files are read as is
+// Open 使用密钥环打开 path 用于读取；明文文件原样读取
+func (kr *Keyring) Open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	hb := make([]byte, headerSize)
+	n, _ := f.ReadAt(hb, 0)
+	if !IsEncrypted(hb[:n]) {
+		return &File{f: f, r: f, size: info.Size()}, nil
+	}
+	r, err := kr.NewReader(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &File{f: f, r: r, size: r.Size()}, nil
+}
+
+// Read reads plaintext
+// Read 读取明文
+func (f *File) Read(p []byte) (int, error) {
+	return f.r.Read(p)
+}
+
+// ReadAt reads plaintext at off; for encrypted files it is not safe for concurrent use
+// ReadAt 在 off 处读取明文；加密文件时不可并发调用
+func (f *File) ReadAt(p []byte, off int64) (int, error) {
+	return f.r.ReadAt(p, off)
+}
+
+// Seek sets the plaintext offset
+// Seek 设置明文偏移
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	return f.r.Seek(offset, whence)
+}
+
+// Close closes the underlying file
+// Close 关闭底层文件
+func (f *File) Close() error {
+	return f.f.Close()
+}
+
+// Size returns the plaintext size
+// Size 返回明文大小
+func (f *File) Size() int64 {
+	return f.size
+}
+
+// Stat returns the file info of the underlying file, its size is the on-disk size
+// Stat 返回底层文件信息，其大小为磁盘上的大小
+func (f *File) Stat() (os.FileInfo, error) {
+	return f.f.Stat()
+}
+
+// ReadFile reads path as plaintext with the keyring
+// ReadFile 使用密钥环以明文读取 path
+func (kr *Keyring) ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return kr.Decrypt(data)
+}
+
+// WriteFile writes data to path, encrypted with the current key when the keyring is set
+// WriteFile 将 data 写入 path，设置了密钥环时使用当前密钥加密
+func (kr *Keyring) WriteFile(path string, data []byte, perm os.FileMode) error {
+	data, err := kr.Encrypt(data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+// MoveFile moves src to dst, encrypting it on the way when the keyring is set
+// Without a keyring it is a plain rename. The encrypted copy is written next to dst and renamed
+// into place, so dst is never left partially written.
+// MoveFile 将 src 移动到 dst，设置了密钥环时在移动过程中加密
+// 未设置密钥环时为普通重命名。加密副本先写在 dst 旁再重命名到位，dst 不会处于写入一半的状态。
+func (kr *Keyring) MoveFile(src, dst string) error {
+	if kr == nil || kr.current == nil {
+		return os.Rename(src, dst)
+	}
+	if err := kr.rewrite(src, dst, kr); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+// Rewrite re-encodes path in place: it is decrypted with the keyring and written with target,
+// a nil target writes plaintext. The modification time is preserved.
+// Rewrite 原地重新编码 path：使用密钥环解密并以 target 写入，target 为 nil 时写入明文。保留修改时间。
+func (kr *Keyring) Rewrite(path string, target *Keyring) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := kr.rewrite(path, path, target); err != nil {
+		return err
+	}
+	return os.Chtimes(path, info.ModTime(), info.ModTime())
+}
+
+// rewrite streams src as plaintext into dst encoded with target, through a temp file beside dst
+// rewrite 将 src 以明文流式写入 dst 并以 target 编码，经由 dst 旁的临时文件
+func (kr *Keyring) rewrite(src, dst string, target *Keyring) error {
+	in, err := kr.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".atrest-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w, err := target.NewWriter(tmp)
+	if err == nil {
+		_, err = io.Copy(w, in)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// Open opens path with the default keyring
+// Open 使用默认密钥环打开 path
+func Open(path string) (*File, error) {
+	return Default().Open(path)
+}
+
+// ReadFile reads path as plaintext with the default keyring
+// ReadFile 使用默认密钥环以明文读取 path
+func ReadFile(path string) ([]byte, error) {
+	return Default().ReadFile(path)
+}
+
+// WriteFile writes data to path with the default keyring
+// WriteFile 使用默认密钥环将 data 写入 path
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	return Default().WriteFile(path, data, perm)
+}
+
+// MoveFile moves src to dst with the default keyring
+// MoveFile 使用默认密钥环将 src 移动到 dst
+func MoveFile(src, dst string) error {
+	return Default().MoveFile(src, dst)
+}
+
+// NewWriter returns an encrypting writer over dst with the default keyring
+// NewWriter 使用默认密钥环返回写入 dst 的加密 writer
+func NewWriter(dst io.Writer) (io.WriteCloser, error) {
+	return Default().NewWriter(dst)
+}
@@ -0,0 +1,140 @@
+// Package branding applies per-instance branding (name, logo, colors, custom CSS) to the embedded frontend pages.
+// Package branding 将实例品牌设置（名称、标志、颜色、自定义样式）应用到内嵌前端页面。
+package branding
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// DefaultName product name replaced by the instance name in page titles
+// DefaultName 页面标题中被实例名称替换的产品名称
+const DefaultName = "Fast Note Sync"
+
+// DefaultIcon icon shipped with the frontend, replaced by the logo
+// DefaultIcon 前端自带的图标，被标志替换
+const DefaultIcon = "/static/images/icon.svg"
+
+// CSSPath path the generated stylesheet is served at
+// CSSPath 生成的样式表的访问路径
+const CSSPath = "/branding.css"
+
+// Branding instance branding, empty fields keep the defaults
+// Branding 实例品牌设置，留空字段保持默认
+type Branding struct {
+	InstanceName string
+	LogoURL      string
+	PrimaryColor string
+	AccentColor  string
+	LoginMessage string
+	CustomCSS    string
+}
+
+var colorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// ValidColor reports whether s is empty or a hex color such as #3b82f6
+// ValidColor 判断 s 是否为空或 #3b82f6 之类的十六进制颜色
+func ValidColor(s string) bool {
+	return s == "" || colorPattern.MatchString(s)
+}
+
+// ValidLogoURL reports whether s is empty, a path on this server or an http(s) URL
+// ValidLogoURL 判断 s 是否为空、本服务上的路径或 http(s) URL
+func ValidLogoURL(s string) bool {
+	if s == "" {
+		return true
+	}
+	if strings.ContainsAny(s, "\"'<> \t\r\n") {
+		return false
+	}
+	if strings.HasPrefix(s, "/") {
+		return !strings.HasPrefix(s, "//")
+	}
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// HasStylesheet reports whether there is anything to serve at CSSPath
+// HasStylesheet 判断 CSSPath 是否有内容可提供
+func (b Branding) HasStylesheet() bool {
+	return b.PrimaryColor != "" || b.AccentColor != "" || strings.TrimSpace(b.CustomCSS) != ""
+}
+
+// CSS stylesheet exposing the colors as CSS variables, followed by the custom CSS
+// CSS 以 CSS 变量提供颜色的样式表，其后为自定义样式
+func (b Branding) CSS() string {
+	var sb strings.Builder
+	if b.PrimaryColor != "" || b.AccentColor != "" {
+		sb.WriteString(":root {\n")
+		// Colors are validated on save, checked again so a hand-edited config cannot break out of the rule
+		// 颜色在保存时已校验，此处再次检查，避免手工编辑的配置跳出规则
+		if b.PrimaryColor != "" && ValidColor(b.PrimaryColor) {
+			fmt.Fprintf(&sb, "  --fns-brand-primary: %s;\n", b.PrimaryColor)
+		}
+		if b.AccentColor != "" && ValidColor(b.AccentColor) {
+			fmt.Fprintf(&sb, "  --fns-brand-accent: %s;\n", b.AccentColor)
+		}
+		sb.WriteString("}\n")
+	}
+	if css := strings.TrimSpace(b.CustomCSS); css != "" {
+		sb.WriteString(css)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Version short hash of the stylesheet, used to bust caches when it changes
+// Version 样式表的短摘要，内容变化时用于使缓存失效
+func (b Branding) Version() string {
+	sum := sha256.Sum256([]byte(b.CSS()))
+	return hex.EncodeToString(sum[:4])
+}
+
+// Apply brands an HTML page: the instance name goes into the title, the logo replaces the favicon,
+// the stylesheet is linked and the branding is exposed to scripts as window.FNS_BRANDING
+// Apply 为 HTML 页面应用品牌：实例名称写入标题，标志替换站点图标，
+// 链接样式表，并通过 window.FNS_BRANDING 提供给脚本
+func (b Branding) Apply(page string) string {
+	if b.InstanceName != "" {
+		if start := strings.Index(page, "<title>"); start >= 0 {
+			if end := strings.Index(page[start:], "</title>"); end >= 0 {
+				title := page[start : start+end]
+				page = page[:start] + strings.Replace(title, DefaultName, html.EscapeString(b.InstanceName), 1) + page[start+end:]
+			}
+		}
+	}
+
+	if b.LogoURL != "" && ValidLogoURL(b.LogoURL) {
+		logo := html.EscapeString(b.LogoURL)
+		// The logo is not necessarily an SVG
+		// 标志不一定是 SVG
+		page = strings.ReplaceAll(page, `type="image/svg+xml" href="`+DefaultIcon+`"`, `href="`+logo+`"`)
+		page = strings.ReplaceAll(page, `href="`+DefaultIcon+`"`, `href="`+logo+`"`)
+	}
+
+	var head strings.Builder
+	if b.HasStylesheet() {
+		fmt.Fprintf(&head, `<link rel="stylesheet" href="%s?v=%s">`, CSSPath, b.Version())
+	}
+	data, _ := json.Marshal(map[string]string{
+		"instanceName": b.InstanceName,
+		"logoUrl":      b.LogoURL,
+		"primaryColor": b.PrimaryColor,
+		"accentColor":  b.AccentColor,
+		"loginMessage": b.LoginMessage,
+	})
+	// json.Marshal escapes <, > and &, so the data cannot close the script element
+	// json.Marshal 会转义 <、> 与 &，数据无法闭合 script 元素
+	fmt.Fprintf(&head, "<script>window.FNS_BRANDING = %s;</script>", data)
+
+	if strings.Contains(page, "</head>") {
+		return strings.Replace(page, "</head>", head.String()+"</head>", 1)
+	}
+	return head.String() + page
+}
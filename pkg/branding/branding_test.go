@@ -0,0 +1,54 @@
+package branding
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const page = `<html><head><title>Shared Note - Fast Note Sync</title>` +
+	`<link id="favicon" rel="icon" type="image/svg+xml" href="/static/images/icon.svg">` +
+	`<link id="apple-touch-icon" rel="apple-touch-icon" href="/static/images/icon.svg"></head><body></body></html>`
+
+func TestApply_Defaults(t *testing.T) {
+	out := Branding{}.Apply(page)
+	assert.Contains(t, out, "<title>Shared Note - Fast Note Sync</title>")
+	assert.Contains(t, out, `href="/static/images/icon.svg"`)
+	assert.NotContains(t, out, CSSPath)
+	assert.Contains(t, out, "window.FNS_BRANDING")
+}
+
+func TestApply_Branded(t *testing.T) {
+	b := Branding{InstanceName: "ACME <Notes>", LogoURL: "/user_static/branding/logo.png", PrimaryColor: "#123456", LoginMessage: "</script><script>alert(1)</script>"}
+	out := b.Apply(page)
+
+	assert.Contains(t, out, "<title>Shared Note - ACME &lt;Notes&gt;</title>")
+	assert.Contains(t, out, `<link id="favicon" rel="icon" href="/user_static/branding/logo.png">`)
+	assert.Contains(t, out, `rel="apple-touch-icon" href="/user_static/branding/logo.png"`)
+	assert.Contains(t, out, `<link rel="stylesheet" href="/branding.css?v=`+b.Version()+`">`)
+	assert.Equal(t, 1, strings.Count(out, "<script>"), "the login message must not open a script element")
+	assert.Less(t, strings.Index(out, "window.FNS_BRANDING"), strings.Index(out, "</head>"))
+}
+
+func TestCSS(t *testing.T) {
+	b := Branding{PrimaryColor: "#3b82f6", AccentColor: "red;}body{display:none", CustomCSS: " .login { color: red; } "}
+	css := b.CSS()
+	assert.Contains(t, css, "--fns-brand-primary: #3b82f6;")
+	assert.NotContains(t, css, "--fns-brand-accent", "invalid colors are dropped")
+	assert.True(t, strings.HasSuffix(css, ".login { color: red; }\n"))
+	assert.NotEqual(t, b.Version(), Branding{PrimaryColor: "#000"}.Version())
+}
+
+func TestValidLogoURL(t *testing.T) {
+	for s, want := range map[string]bool{
+		"":                                 true,
+		"/user_static/branding/logo.png":   true,
+		"https://cdn.example.com/logo.svg": true,
+		"//evil.example.com/logo.png":      false,
+		"javascript:alert(1)":              false,
+		`/logo.png" onerror="alert(1)`:     false,
+	} {
+		assert.Equal(t, want, ValidLogoURL(s), s)
+	}
+}
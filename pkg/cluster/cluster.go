@@ -0,0 +1,23 @@
+// Package cluster relays in-process events between the instances of a multi-instance deployment,
+// so every instance behind a load balancer sees the sync events and cache invalidations of the others.
+// Package cluster 在多实例部署的各实例之间转发进程内事件，
+// 使负载均衡后的每个实例都能收到其他实例的同步事件与缓存失效通知。
+package cluster
+
+// Bus publish/subscribe bridge between instances. Messages published by an instance are delivered
+// to the subscribers of every other instance, never back to itself.
+// Bus 实例间的发布/订阅桥。实例发布的消息会投递给其他所有实例的订阅者，不会回送给自身。
+type Bus interface {
+	// Publish sends payload on topic to the other instances
+	// Publish 将 payload 通过 topic 发送给其他实例
+	Publish(topic string, payload []byte) error
+	// Subscribe registers the handler of a topic, handlers run on the receiving goroutine and must not block
+	// Subscribe 注册 topic 的处理函数，处理函数在接收协程中执行，不得阻塞
+	Subscribe(topic string, handler func(payload []byte))
+	// InstanceID identifier of this instance
+	// InstanceID 当前实例的标识
+	InstanceID() string
+	// Close stops receiving and releases the connection
+	// Close 停止接收并释放连接
+	Close() error
+}
@@ -0,0 +1,178 @@
+package cluster
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/json"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// RedisConfig Redis server used as the pub/sub bridge
+// RedisConfig 用作发布/订阅桥的 Redis 服务
+type RedisConfig struct {
+	Addr     string // host:port // 地址 host:port
+	Username string // ACL user name, empty for the default user // ACL 用户名，默认用户留空
+	Password string // Password // 密码
+	DB       int    // Database number, pub/sub channels are not scoped by it // 数据库编号，发布/订阅频道不受其隔离
+	Channel  string // Channel prefix, instances of one deployment must share it // 频道前缀，同一部署的实例必须一致
+	TLS      bool   // Connect with TLS // 使用 TLS 连接
+}
+
+// envelope message on the wire, the origin lets an instance skip its own messages
+// envelope 线上传输的消息，origin 用于让实例跳过自身发出的消息
+type envelope struct {
+	Origin  string `json:"o"`
+	Payload []byte `json:"p"`
+}
+
+// Redis Bus backed by Redis pub/sub, the client reconnects and resubscribes on its own
+// Redis 基于 Redis 发布/订阅的 Bus，客户端会自行重连并重新订阅
+type Redis struct {
+	client     *redis.Client
+	pubsub     *redis.PubSub
+	prefix     string
+	instanceID string
+	logger     *zap.Logger
+
+	mu       sync.RWMutex
+	handlers map[string]func(payload []byte)
+
+	done chan struct{}
+}
+
+// NewRedis connects to Redis and starts receiving
+// NewRedis 连接 Redis 并开始接收消息
+func NewRedis(cfg RedisConfig, instanceID string, logger *zap.Logger) (*Redis, error) {
+	if strings.TrimSpace(cfg.Addr) == "" {
+		return nil, errors.New("cluster: redis addr is required")
+	}
+	if instanceID == "" {
+		return nil, errors.New("cluster: instance id is required")
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	prefix := cfg.Channel
+	if prefix == "" {
+		prefix = "fns"
+	}
+
+	opts := &redis.Options{
+		Addr:     cfg.Addr,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	}
+	if cfg.TLS {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, errors.Wrap(err, "cluster: redis ping")
+	}
+
+	r := &Redis{
+		client:     client,
+		pubsub:     client.Subscribe(context.Background()),
+		prefix:     prefix,
+		instanceID: instanceID,
+		logger:     logger,
+		handlers:   make(map[string]func(payload []byte)),
+		done:       make(chan struct{}),
+	}
+	go r.receive()
+	return r, nil
+}
+
+func (r *Redis) channel(topic string) string {
+	return r.prefix + ":" + topic
+}
+
+// Publish sends payload on topic to the other instances
+// Publish 将 payload 通过 topic 发送给其他实例
+func (r *Redis) Publish(topic string, payload []byte) error {
+	msg, err := json.Marshal(envelope{Origin: r.instanceID, Payload: payload})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return r.client.Publish(ctx, r.channel(topic), msg).Err()
+}
+
+// Subscribe registers the handler of a topic
+// Subscribe 注册 topic 的处理函数
+func (r *Redis) Subscribe(topic string, handler func(payload []byte)) {
+	r.mu.Lock()
+	r.handlers[r.channel(topic)] = handler
+	r.mu.Unlock()
+	if err := r.pubsub.Subscribe(context.Background(), r.channel(topic)); err != nil {
+		r.logger.Error("cluster: redis subscribe failed", zap.String("topic", topic), zap.Error(err))
+	}
+}
+
+// InstanceID identifier of this instance
+// InstanceID 当前实例的标识
+func (r *Redis) InstanceID() string {
+	return r.instanceID
+}
+
+// Close stops receiving and releases the connection
+// Close 停止接收并释放连接
+func (r *Redis) Close() error {
+	select {
+	case <-r.done:
+		return nil
+	default:
+		close(r.done)
+	}
+	err := r.pubsub.Close()
+	if cerr := r.client.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (r *Redis) receive() {
+	ch := r.pubsub.Channel()
+	for {
+		select {
+		case <-r.done:
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			r.dispatch(msg.Channel, []byte(msg.Payload))
+		}
+	}
+}
+
+// dispatch hands a received message to its topic handler, messages of this instance are skipped
+// dispatch 将收到的消息交给对应 topic 的处理函数，跳过本实例发出的消息
+func (r *Redis) dispatch(channel string, msg []byte) {
+	var env envelope
+	if err := json.Unmarshal(msg, &env); err != nil {
+		r.logger.Warn("cluster: malformed message", zap.String("channel", channel), zap.Error(err))
+		return
+	}
+	if env.Origin == r.instanceID {
+		return
+	}
+	r.mu.RLock()
+	handler := r.handlers[channel]
+	r.mu.RUnlock()
+	if handler != nil {
+		handler(env.Payload)
+	}
+}
@@ -0,0 +1,33 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestRedis_Dispatch verifies messages reach the handler of their topic and messages of the
+// instance itself are skipped.
+// TestRedis_Dispatch 验证消息被投递到对应 topic 的处理函数，且本实例发出的消息被跳过。
+func TestRedis_Dispatch(t *testing.T) {
+	r := &Redis{prefix: "fns", instanceID: "a", logger: zap.NewNop(), handlers: map[string]func([]byte){}}
+
+	var got []string
+	r.handlers[r.channel("ws")] = func(payload []byte) { got = append(got, string(payload)) }
+
+	encode := func(origin, payload string) []byte {
+		msg, err := json.Marshal(envelope{Origin: origin, Payload: []byte(payload)})
+		require.NoError(t, err)
+		return msg
+	}
+
+	r.dispatch("fns:ws", encode("b", "from b"))
+	r.dispatch("fns:ws", encode("a", "from self"))
+	r.dispatch("fns:other", encode("b", "unhandled"))
+	r.dispatch("fns:ws", []byte("garbage"))
+
+	assert.Equal(t, []string{"from b"}, got)
+}
@@ -0,0 +1,142 @@
+package code
+
+import "sort"
+
+// Error catalog categories, one per code range of common.go
+// 错误目录分类，与 common.go 中的错误码区间一一对应
+const (
+	CategoryGeneral    = "general"
+	CategoryRequest    = "request"
+	CategoryUser       = "user"
+	CategoryVault      = "vault"
+	CategoryNote       = "note"
+	CategoryFolder     = "folder"
+	CategoryFile       = "file"
+	CategorySetting    = "setting"
+	CategoryShare      = "share"
+	CategorySync       = "sync"
+	CategoryStorage    = "storage"
+	CategoryGitSync    = "git_sync"
+	CategoryCloudflare = "cloudflare"
+	CategoryConflict   = "conflict"
+	CategorySnapshot   = "snapshot"
+	CategoryLint       = "lint"
+	CategoryBackup     = "backup"
+	CategoryWebhook    = "webhook"
+	CategoryAlert      = "alert"
+	CategoryNotePolicy = "note_policy"
+	CategoryDevice     = "device"
+	CategoryTemplate   = "note_template"
+	CategorySearch     = "search_index"
+	CategoryRegister   = "registration"
+	CategoryPrefs      = "user_setting"
+	CategoryLimit      = "content_limit"
+)
+
+// categoryRange code range of a category, both ends included
+// categoryRange 分类的错误码区间，包含两端
+type categoryRange struct {
+	from, to int
+	name     string
+}
+
+var categoryRanges = []categoryRange{
+	{0, 99, CategoryGeneral},
+	{300, 399, CategoryRequest},
+	{400, 419, CategoryUser},
+	{420, 429, CategoryVault},
+	{430, 444, CategoryNote},
+	{445, 454, CategoryFolder},
+	{455, 469, CategoryFile},
+	{470, 479, CategorySetting},
+	{480, 489, CategoryShare},
+	{490, 499, CategorySync},
+	{500, 509, CategoryStorage},
+	{510, 519, CategoryGitSync},
+	{520, 529, CategoryCloudflare},
+	{530, 539, CategoryConflict},
+	{540, 549, CategorySnapshot},
+	{550, 559, CategoryLint},
+	{560, 569, CategoryBackup},
+	{570, 579, CategoryWebhook},
+	{580, 589, CategoryAlert},
+	{590, 599, CategoryNotePolicy},
+	{600, 609, CategoryDevice},
+	{620, 629, CategoryTemplate},
+	{630, 639, CategorySearch},
+	{640, 649, CategoryRegister},
+	{650, 659, CategoryPrefs},
+	{660, 669, CategoryLimit},
+}
+
+// CatalogEntry one code of the error catalog
+// CatalogEntry 错误目录中的一个错误码
+type CatalogEntry struct {
+	Code       int    // Numeric code // 数字错误码
+	Name       string // Stable identifier, e.g. ErrorNoteNotFound // 稳定标识符，如 ErrorNoteNotFound
+	Category   string // Category of the code range // 错误码区间所属分类
+	Status     bool   // true for success codes // 成功码为 true
+	HTTPStatus int    // HTTP status of the response carrying the code // 携带该错误码的响应的 HTTP 状态码
+	Message    string // Message template // 消息模板
+	Hint       string // Remediation hint, empty for success codes // 处理建议，成功码为空
+}
+
+// Category returns the catalog category of the code
+// Category 返回错误码所属的目录分类
+func (e *Code) Category() string {
+	return categoryOf(e.code)
+}
+
+func categoryOf(code int) string {
+	for _, r := range categoryRanges {
+		if code >= r.from && code <= r.to {
+			return r.name
+		}
+	}
+	return CategoryGeneral
+}
+
+// Hint returns the remediation hint of the code in the specified language
+// Hint 返回错误码在指定语言下的处理建议
+func (e *Code) Hint(language string) string {
+	if e.status {
+		return ""
+	}
+	return hintOf(e.code, language)
+}
+
+func hintOf(code int, language string) string {
+	if en_hints[code] != "" {
+		return lang{en: en_hints[code], zh_cn: zh_cn_hints[code]}.GetMessageIn(language)
+	}
+	category := categoryOf(code)
+	if en_category_hints[category] != "" {
+		return lang{en: en_category_hints[category], zh_cn: zh_cn_category_hints[category]}.GetMessageIn(language)
+	}
+	return ""
+}
+
+// Catalog returns every code registered in common.go sorted by code, with messages and hints in the specified language
+// Catalog 返回 common.go 中注册的所有错误码（按错误码排序），消息与处理建议使用指定语言
+func Catalog(language string) []CatalogEntry {
+	entries := make([]CatalogEntry, 0, len(names))
+	for c, name := range names {
+		_, status := sussCodes[c]
+		entry := CatalogEntry{
+			Code:       c,
+			Name:       name,
+			Category:   categoryOf(c),
+			Status:     status,
+			HTTPStatus: (&Code{code: c, status: status}).StatusCode(),
+			Message:    getLang(c).GetMessageIn(language),
+		}
+		if !status {
+			entry.Hint = hintOf(c, language)
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Code < entries[j].Code
+	})
+	return entries
+}
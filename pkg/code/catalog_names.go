@@ -0,0 +1,177 @@
+package code
+
+// names maps every code registered in common.go to its Go identifier, the stable machine-readable
+// name of the code in the error catalog. TestCatalogNames keeps it in sync with common.go.
+// names 将 common.go 中注册的每个错误码映射到其 Go 标识符，作为错误目录中稳定的机器可读名称。
+// TestCatalogNames 保证其与 common.go 保持一致。
+var names = map[int]string{
+	0:   "Failed",
+	1:   "Success",
+	2:   "SuccessCreate",
+	3:   "SuccessUpdate",
+	4:   "SuccessDelete",
+	5:   "SuccessPasswordUpdate",
+	6:   "SuccessNoUpdate",
+	7:   "SuccessAwaitApproval",
+	300: "ErrorServerInternal",
+	301: "ErrorDBQuery",
+	302: "ErrorServerBusy",
+	303: "ErrorTooManyRequests",
+	304: "ErrorNotFoundAPI",
+	305: "ErrorInvalidParams",
+	306: "ErrorInvalidAuthToken",
+	307: "ErrorNotUserAuthToken",
+	308: "ErrorInvalidUserAuthToken",
+	309: "ErrorInvalidToken",
+	310: "ErrorTokenExpired",
+	311: "ErrorTokenGenerate",
+	312: "ErrorAuthTokenIPRestricted",
+	313: "ErrorAuthTokenUARestricted",
+	314: "ErrorAuthTokenClientRestricted",
+	315: "ErrorAuthTokenScopeRestricted",
+	316: "ErrorIPBlocked",
+	317: "ErrorCaptchaRequired",
+	318: "ErrorCaptchaInvalid",
+	319: "ErrorPasswordResetDisabled",
+	320: "ErrorPasswordResetInvalid",
+	321: "ErrorServerReadOnly",
+	400: "ErrorUserRegister",
+	401: "ErrorUserLoginFailed",
+	402: "ErrorUserLoginPasswordFailed",
+	403: "ErrorUserNotFound",
+	404: "ErrorUserAlreadyExists",
+	405: "ErrorUserEmailAlreadyExists",
+	406: "ErrorUserUsernameNotValid",
+	407: "ErrorPasswordNotValid",
+	408: "ErrorUserOldPasswordFailed",
+	409: "ErrorUserPasswordNotMatch",
+	410: "ErrorUserRegisterIsDisable",
+	411: "ErrorUserIsNotAdmin",
+	412: "ErrorUserLocalFSDisabled",
+	413: "ErrorUserUpdate",
+	414: "ErrorUserAdminBlock",
+	415: "ErrorUserTOTPRequired",
+	416: "ErrorUserTOTPInvalid",
+	417: "ErrorUserTOTPNotSetup",
+	418: "ErrorUserTOTPAlreadyEnabled",
+	419: "ErrorUserLoginLocked",
+	420: "ErrorVaultNotFound",
+	421: "ErrorVaultExist",
+	422: "ErrorInvalidStorageType",
+	423: "ErrorInvalidCloudStorageType",
+	424: "ErrorVaultTransferConflict",
+	430: "ErrorNoteNotFound",
+	431: "ErrorNoteExist",
+	432: "ErrorNoteGetFailed",
+	433: "ErrorNoteModifyOrCreateFailed",
+	434: "ErrorNoteContentModifyFailed",
+	435: "ErrorNoteDeleteFailed",
+	436: "ErrorNoteListFailed",
+	437: "ErrorNoteRenameFailed",
+	438: "ErrorRenameNoteTargetExist",
+	439: "ErrorNoteSyncFailed",
+	440: "ErrorNoteUpdateCheckFailed",
+	441: "ErrorNoteConflict",
+	442: "ErrorNoMatchFound",
+	443: "ErrorInvalidRegex",
+	444: "ErrorInvalidPath",
+	445: "ErrorFolderNotFound",
+	446: "ErrorFolderExist",
+	447: "ErrorFolderGetFailed",
+	448: "ErrorFolderModifyOrCreateFailed",
+	449: "ErrorFolderDeleteFailed",
+	450: "ErrorFolderListFailed",
+	451: "ErrorFolderRenameFailed",
+	452: "ErrorFolderArchived",
+	455: "ErrorFileNotFound",
+	456: "ErrorFileGetFailed",
+	457: "ErrorFileModifyOrCreateFailed",
+	458: "ErrorFileContentModifyFailed",
+	459: "ErrorFileDeleteFailed",
+	460: "ErrorFileListFailed",
+	461: "ErrorFileUploadFailed",
+	462: "ErrorFileUploadCheckFailed",
+	463: "ErrorFileUploadSessionNotFound",
+	464: "ErrorFileSaveFailed",
+	465: "ErrorFileRenameFailed",
+	466: "ErrorFileReadFailed",
+	467: "ErrorFileExist",
+	468: "ErrorFileThumbnailUnsupported",
+	469: "ErrorFileThumbnailFailed",
+	470: "ErrorSettingNotFound",
+	471: "ErrorSettingExist",
+	472: "ErrorSettingGetFailed",
+	473: "ErrorSettingModifyOrCreateFailed",
+	474: "ErrorSettingContentModifyFailed",
+	475: "ErrorSettingDeleteFailed",
+	476: "ErrorSettingListFailed",
+	477: "ErrorSettingSyncFailed",
+	478: "ErrorSettingUpdateCheckFailed",
+	479: "ErrorConfigSaveFailed",
+	480: "ErrorShareNotFound",
+	481: "ErrorShareExpired",
+	482: "ErrorShareRevoked",
+	483: "ErrorSharePasswordRequired",
+	484: "ErrorSharePasswordInvalid",
+	491: "ErrorHistoryNotFound",
+	492: "ErrorHistoryRestoreFailed",
+	493: "ErrorBackupConfigNotFound",
+	494: "ErrorBackupTaskFailed",
+	495: "ErrorBackupTypeUnknown",
+	496: "ErrorBackupExecuteIDReq",
+	497: "ErrorBackupVaultRequired",
+	498: "ErrorBackupStorageIDInvalid",
+	499: "ErrorBackupConfigDisabled",
+	500: "ErrorStorageNotFound",
+	501: "ErrorStorageTypeDisabled",
+	502: "ErrorStorageValidateFailed",
+	510: "ErrorGitSyncNotFound",
+	511: "ErrorGitSyncTaskRunning",
+	512: "ErrorGitSyncValidateFailed",
+	520: "ErrorCloudflaredDownloadFailed",
+	521: "ErrorCloudflaredBinaryNotFound",
+	530: "ErrorSyncConflict",
+	531: "ErrorNoteSecretDetected",
+	540: "ErrorSnapshotNotFound",
+	541: "ErrorSnapshotRunning",
+	542: "ErrorSnapshotFailed",
+	543: "ErrorDatabaseExportRunning",
+	544: "ErrorDatabaseExportFailed",
+	550: "ErrorLintDisabled",
+	551: "ErrorLintFailed",
+	552: "ErrorLintTextTooLong",
+	560: "ErrorBackupHistoryNotFound",
+	561: "ErrorBackupVerifyUnsupported",
+	570: "ErrorWebhookNotFound",
+	571: "ErrorWebhookURLInvalid",
+	572: "ErrorWebhookEventUnknown",
+	580: "ErrorAlertChannelNotFound",
+	581: "ErrorAlertChannelInvalid",
+	582: "ErrorAlertSendFailed",
+	590: "ErrorNotePolicyNotFound",
+	591: "ErrorNotePolicyInvalid",
+	600: "ErrorDeviceNotFound",
+	610: "ErrorNoteRenderFailed",
+	620: "ErrorNoteTemplateNotFound",
+	621: "ErrorNoteTemplateExist",
+	630: "ErrorSearchIndexDisabled",
+	631: "ErrorReindexJobNotFound",
+	640: "ErrorInviteCodeInvalid",
+	641: "ErrorInviteNotFound",
+	642: "ErrorPendingUserNotFound",
+	650: "ErrorUserSettingNotFound",
+	651: "ErrorUserSettingConflict",
+	660: "ErrorNoteTooLarge",
+	661: "ErrorFileTooLarge",
+	662: "ErrorVaultNoteLimit",
+	670: "ErrorLogBufferDisabled",
+	671: "ErrorSyncTraceFailed",
+	680: "ErrorJobQueueDisabled",
+	681: "ErrorJobNotFound",
+	682: "ErrorJobNotFailed",
+	690: "ErrorScheduleTaskNotFound",
+	691: "ErrorScheduleInvalid",
+	692: "ErrorScheduleTaskRunning",
+	700: "ErrorWSConnectionLimit",
+	701: "ErrorWSUserConnectionLimit",
+}
@@ -0,0 +1,91 @@
+package code
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commonCodes parses common.go and returns the identifier of every registered code
+func commonCodes(t *testing.T) map[int]string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "common.go", nil, 0)
+	require.NoError(t, err)
+
+	found := map[int]string{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok || len(spec.Values) != 1 {
+			return true
+		}
+		call, ok := spec.Values[0].(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		fn, ok := call.Fun.(*ast.Ident)
+		lit, isLit := call.Args[0].(*ast.BasicLit)
+		if !ok || !isLit || (fn.Name != "NewError" && fn.Name != "NewSuss") {
+			return true
+		}
+		c, err := strconv.Atoi(lit.Value)
+		require.NoError(t, err)
+		found[c] = spec.Names[0].Name
+		return true
+	})
+	return found
+}
+
+// TestCatalogNames verifies names lists exactly the codes of common.go
+func TestCatalogNames(t *testing.T) {
+	assert.Equal(t, commonCodes(t), names, "update catalog_names.go after changing common.go")
+}
+
+func TestCatalog(t *testing.T) {
+	entries := Catalog("zh_cn")
+	require.Len(t, entries, len(names))
+
+	for i, e := range entries {
+		if i > 0 {
+			assert.Less(t, entries[i-1].Code, e.Code)
+		}
+		assert.NotEmpty(t, e.Message, "code %d", e.Code)
+		assert.Equal(t, 200, e.HTTPStatus)
+		if e.Status {
+			assert.Empty(t, e.Hint, "code %d", e.Code)
+		} else {
+			assert.NotEmpty(t, e.Hint, "code %d", e.Code)
+		}
+	}
+
+	byCode := map[int]CatalogEntry{}
+	for _, e := range Catalog("en") {
+		byCode[e.Code] = e
+	}
+	assert.Equal(t, "ErrorNoteNotFound", byCode[430].Name)
+	assert.Equal(t, CategoryNote, byCode[430].Category)
+	assert.Equal(t, en_hints[430], byCode[430].Hint)
+	assert.Equal(t, en_category_hints[CategoryUser], byCode[400].Hint)
+	assert.True(t, byCode[1].Status)
+	assert.Equal(t, CategoryNotePolicy, ErrorNotePolicyInvalid.Category())
+	assert.Equal(t, zh_cn_hints[591], ErrorNotePolicyInvalid.Hint("zh_cn"))
+}
+
+// TestCatalogHints verifies every hint has a translation and belongs to a registered code
+func TestCatalogHints(t *testing.T) {
+	for c := range en_hints {
+		assert.NotEmpty(t, zh_cn_hints[c], "code %d", c)
+		assert.Contains(t, names, c, "hint of an unregistered code")
+	}
+	for c := range zh_cn_hints {
+		assert.NotEmpty(t, en_hints[c], "code %d", c)
+	}
+	for _, r := range categoryRanges {
+		assert.NotEmpty(t, en_category_hints[r.name], r.name)
+		assert.NotEmpty(t, zh_cn_category_hints[r.name], r.name)
+	}
+}
@@ -8,6 +8,7 @@ var (
 	SuccessDelete         = NewSuss(4)
 	SuccessPasswordUpdate = NewSuss(5)
 	SuccessNoUpdate       = NewSuss(6)
+	SuccessAwaitApproval  = NewSuss(7)
 
 	ErrorServerInternal            = NewError(300, true)
 	ErrorDBQuery                   = NewError(301, true)
@@ -25,6 +26,12 @@ var (
 	ErrorAuthTokenUARestricted     = NewError(313)
 	ErrorAuthTokenClientRestricted = NewError(314)
 	ErrorAuthTokenScopeRestricted  = NewError(315)
+	ErrorIPBlocked                 = NewError(316)
+	ErrorCaptchaRequired           = NewError(317)
+	ErrorCaptchaInvalid            = NewError(318)
+	ErrorPasswordResetDisabled     = NewError(319)
+	ErrorPasswordResetInvalid      = NewError(320)
+	ErrorServerReadOnly            = NewError(321)
 
 	// --- User Related (400-419) ---
 	ErrorUserRegister            = NewError(400)
@@ -42,12 +49,18 @@ var (
 	ErrorUserLocalFSDisabled     = NewError(412)
 	ErrorUserUpdate              = NewError(413)
 	ErrorUserAdminBlock          = NewError(414)
+	ErrorUserTOTPRequired        = NewError(415)
+	ErrorUserTOTPInvalid         = NewError(416)
+	ErrorUserTOTPNotSetup        = NewError(417)
+	ErrorUserTOTPAlreadyEnabled  = NewError(418)
+	ErrorUserLoginLocked         = NewError(419)
 
 	// --- Vault Related (420-429) ---
 	ErrorVaultNotFound           = NewError(420)
 	ErrorVaultExist              = NewError(421)
 	ErrorInvalidStorageType      = NewError(422)
 	ErrorInvalidCloudStorageType = NewError(423)
+	ErrorVaultTransferConflict   = NewError(424)
 
 	// --- Note Related (430-444) ---
 	ErrorNoteNotFound             = NewError(430)
@@ -74,6 +87,7 @@ var (
 	ErrorFolderDeleteFailed         = NewError(449)
 	ErrorFolderListFailed           = NewError(450)
 	ErrorFolderRenameFailed         = NewError(451)
+	ErrorFolderArchived             = NewError(452)
 
 	// --- File/Attachment Related (455-469) ---
 	ErrorFileNotFound              = NewError(455)
@@ -89,6 +103,8 @@ var (
 	ErrorFileRenameFailed          = NewError(465)
 	ErrorFileReadFailed            = NewError(466)
 	ErrorFileExist                 = NewError(467)
+	ErrorFileThumbnailUnsupported  = NewError(468)
+	ErrorFileThumbnailFailed       = NewError(469)
 
 	// --- Setting Related (470-479) ---
 	ErrorSettingNotFound             = NewError(470)
@@ -135,5 +151,82 @@ var (
 	ErrorCloudflaredBinaryNotFound = NewError(521)
 
 	// --- Sync Conflict Related (530-539) ---
-	ErrorSyncConflict = NewError(530)
+	ErrorSyncConflict       = NewError(530)
+	ErrorNoteSecretDetected = NewError(531)
+
+	// --- Snapshot Related (540-549) ---
+	ErrorSnapshotNotFound      = NewError(540)
+	ErrorSnapshotRunning       = NewError(541)
+	ErrorSnapshotFailed        = NewError(542)
+	ErrorDatabaseExportRunning = NewError(543)
+	ErrorDatabaseExportFailed  = NewError(544)
+
+	// --- Lint Related (550-559) ---
+	ErrorLintDisabled    = NewError(550)
+	ErrorLintFailed      = NewError(551)
+	ErrorLintTextTooLong = NewError(552)
+
+	// --- Backup Verify Related (560-569) ---
+	ErrorBackupHistoryNotFound   = NewError(560)
+	ErrorBackupVerifyUnsupported = NewError(561)
+
+	// --- Webhook Related (570-579) ---
+	ErrorWebhookNotFound     = NewError(570)
+	ErrorWebhookURLInvalid   = NewError(571)
+	ErrorWebhookEventUnknown = NewError(572)
+
+	// --- Alert Related (580-589) ---
+	ErrorAlertChannelNotFound = NewError(580)
+	ErrorAlertChannelInvalid  = NewError(581)
+	ErrorAlertSendFailed      = NewError(582)
+
+	// --- Note Policy Related (590-599) ---
+	ErrorNotePolicyNotFound = NewError(590)
+	ErrorNotePolicyInvalid  = NewError(591)
+
+	// --- Device Related (600-609) ---
+	ErrorDeviceNotFound = NewError(600)
+
+	// --- Note Render Related (610-619) ---
+	ErrorNoteRenderFailed = NewError(610)
+
+	// --- Note Template Related (620-629) ---
+	ErrorNoteTemplateNotFound = NewError(620)
+	ErrorNoteTemplateExist    = NewError(621)
+
+	// --- Search Index Related (630-639) ---
+	ErrorSearchIndexDisabled = NewError(630)
+	ErrorReindexJobNotFound  = NewError(631)
+
+	// --- Registration Related (640-649) ---
+	ErrorInviteCodeInvalid   = NewError(640)
+	ErrorInviteNotFound      = NewError(641)
+	ErrorPendingUserNotFound = NewError(642)
+
+	// --- User Setting Related (650-659) ---
+	ErrorUserSettingNotFound = NewError(650)
+	ErrorUserSettingConflict = NewError(651)
+
+	// --- Content Limit Related (660-669) ---
+	ErrorNoteTooLarge   = NewError(660)
+	ErrorFileTooLarge   = NewError(661)
+	ErrorVaultNoteLimit = NewError(662)
+
+	// --- Log Viewer Related (670-679) ---
+	ErrorLogBufferDisabled = NewError(670)
+	ErrorSyncTraceFailed   = NewError(671)
+
+	// --- Job Queue Related (680-689) ---
+	ErrorJobQueueDisabled = NewError(680)
+	ErrorJobNotFound      = NewError(681)
+	ErrorJobNotFailed     = NewError(682)
+
+	// --- Scheduled Task Related (690-699) ---
+	ErrorScheduleTaskNotFound = NewError(690)
+	ErrorScheduleInvalid      = NewError(691)
+	ErrorScheduleTaskRunning  = NewError(692)
+
+	// --- WebSocket Connection Related (700-709) ---
+	ErrorWSConnectionLimit     = NewError(700)
+	ErrorWSUserConnectionLimit = NewError(701)
 )
@@ -0,0 +1,149 @@
+package code
+
+// en_hints remediation hints of codes that need more than the hint of their category
+// en_hints 需要比所属分类提示更具体说明的错误码的处理建议（英文）
+var en_hints = map[int]string{
+	300: "Retry the request; if it keeps failing, send the traceId of the response to the administrator so it can be found in the server log.",
+	301: "The server could not read or write its database. Retry later; the administrator should check disk space and the database settings.",
+	302: "The server is handling too much work at the moment. Retry after a short delay.",
+	303: "The rate limit was exceeded. Wait a moment and slow down automated requests.",
+	304: "Check the method and path against the API documentation; the endpoint may not exist in this server version.",
+	305: "Fix the parameters named in details and send the request again.",
+	306: "Log in again to get a new token.",
+	307: "Send a token in the Authorization header or the token query parameter.",
+	308: "The session is no longer valid. Log in again.",
+	309: "Log in first; this endpoint needs a user token.",
+	310: "The token has expired. Log in again or create a new API token.",
+	312: "The token is bound to another IP address. Use it from the allowed address or create a token without the IP restriction.",
+	313: "The token is bound to another browser. Use it from the same browser or log in again.",
+	314: "The token is restricted to other clients. Use an allowed client or change the token restrictions.",
+	315: "The token scope does not cover this operation. Create a token with the required scope.",
+	316: "Your IP address is blocked. Ask the administrator to review the IP allow and deny lists.",
+	317: "Complete the captcha shown on the login page and sign in again.",
+	318: "Solve the captcha again; responses expire after a few minutes and can be used only once.",
+	319: "The administrator must configure alert.smtp and server.ext-api-url; until then ask the administrator to reset the password.",
+	320: "Request a new reset email; links expire and stop working once the password has been changed.",
+	321: "Changes are paused during an upgrade, restore or migration. Keep your local changes and sync again once maintenance is over.",
+	402: "Check the username and password; accounts may be locked for a while after repeated failures.",
+	403: "Check the username or register a new account.",
+	404: "Choose another username.",
+	405: "This email is already registered. Log in or use another email.",
+	406: "Use 3-15 letters, digits or underscores.",
+	407: "Choose a longer password that follows the password rules.",
+	408: "Enter your current password correctly.",
+	409: "Enter the same new password in both fields.",
+	410: "Registration is disabled. Ask the administrator to create the account or enable user.register-is-enable.",
+	411: "Log in with the administrator account configured in user.admin-uid.",
+	412: "The administrator has disabled local file system access for users.",
+	414: "Administrators cannot be blocked; change the admin-uid setting first.",
+	415: "Send the code of your authenticator app together with the password.",
+	416: "Check the clock of the device running the authenticator app and enter the current code, or use a recovery code.",
+	417: "Set up two-factor authentication before enabling or verifying it.",
+	418: "Disable two-factor authentication first if you want to set it up again.",
+	419: "Wait until the lockout ends before trying again, or ask the administrator to reset the password.",
+	420: "Check the vault name; vaults are created on the first sync of a client.",
+	421: "Choose another vault name.",
+	424: "Run a dry run to list the conflicting paths, then transfer into another vault name or enable overwrite.",
+	430: "The note may have been deleted or renamed on another device. Sync again to refresh the note list.",
+	431: "A note with this path exists already. Choose another path or modify the existing note.",
+	438: "Rename to a path that is not used by another note.",
+	441: "Choose another destination path.",
+	443: "Fix the regular expression syntax.",
+	444: "Use a path inside the vault without '..' segments.",
+	445: "The folder may have been deleted or renamed on another device. Sync again.",
+	446: "Choose another folder name.",
+	452: "Unarchive the folder before changing its content.",
+	455: "The attachment may have been deleted on another device. Sync again.",
+	461: "Retry the upload; check the size limit and the free disk space of the server.",
+	462: "Retry the upload; the server could not prepare it.",
+	463: "The upload session expired or was finished already. Start the upload again.",
+	467: "An attachment with this path exists already. Choose another path.",
+	468: "Only JPEG, PNG, GIF, BMP and WebP images have thumbnails, request the attachment itself instead.",
+	479: "Check the permissions of the config file and the free disk space of the server.",
+	480: "Check the share link; it may have been deleted.",
+	481: "Ask the owner for a new share link.",
+	482: "The owner revoked this share. Ask for a new link.",
+	483: "Enter the share password.",
+	484: "Check the share password with the owner.",
+	491: "The history version may have been cleaned up by the retention settings.",
+	493: "Check the task ID; the task may have been deleted.",
+	495: "Use one of the supported backup types.",
+	496: "Send the ID of the task to run.",
+	497: "Select a vault for the task.",
+	498: "Select existing storage targets for the task.",
+	499: "Enable the task before running it.",
+	500: "Check the storage ID; the storage may have been deleted.",
+	501: "Pick another storage type or ask the administrator to enable this one.",
+	502: "Check the endpoint, bucket and credentials in details, then test again.",
+	510: "Check the Git sync ID; the configuration may have been deleted.",
+	511: "Wait for the running sync to finish.",
+	512: "Check the repository URL, branch and credentials in details.",
+	520: "Check the network access of the server to GitHub, or install cloudflared manually.",
+	521: "Download the tunnel program from the admin panel first.",
+	530: "Open the conflict copy, merge the changes by hand and delete the copy.",
+	531: "Remove the credentials from the note, or ask the administrator to switch secret scanning to warning mode.",
+	541: "Wait for the running snapshot to finish.",
+	543: "Wait for the running database export to finish.",
+	550: "Ask the administrator to enable lint in the server config.",
+	552: "Check a shorter part of the text.",
+	561: "Only successful full, incremental and dedupe backups can be verified.",
+	571: "Use an absolute http:// or https:// URL.",
+	572: "Use the events listed in the webhook documentation.",
+	581: "Check the settings named in details.",
+	582: "Check the channel settings and that the server can reach the service.",
+	591: "Check the settings named in details; archive policies need an archive folder different from the policy folder.",
+	600: "The device may already have been revoked, reload the device list.",
+	620: "Check the template id or name, the template may have been deleted.",
+	621: "Choose another name or update the existing template.",
+	630: "Enable fts-bleve-enabled in the server config and restart.",
+	631: "Start a reindex job for the user first, or reload the job list.",
+	640: "Check the code for typos or ask the administrator for a new invitation.",
+	641: "Reload the invitation list; the code may have been deleted.",
+	642: "Reload the pending list; the registration may already have been approved or rejected.",
+	650: "Check the setting key; the setting may have been deleted on another device.",
+	651: "Merge your change into the current value returned with the error, then retry with its version.",
+	660: "Split the note into smaller notes, or ask the administrator to raise max-note-size.",
+	661: "Keep the attachment out of the vault, or ask the administrator to raise max-attachment-size.",
+	662: "Delete notes you no longer need, or ask the administrator to raise max-notes-per-vault.",
+	670: "Set log.buffer-size above 0 and restart the server, or read the log file instead.",
+	671: "Make sure the server can write to log.sync-trace-dir.",
+	680: "The server is shutting down, try again after it restarts.",
+	681: "The job finished or was dropped, reload the job list.",
+	682: "Only failed jobs can be requeued, reload the job list.",
+	690: "Check the task name in GET /api/admin/schedules; in a cluster, tasks only exist on the instance that runs them.",
+	691: "Use a 5-field cron expression such as 30 4 * * *, a descriptor such as @daily or @every 90m, or off.",
+	692: "Wait for the current run to finish and try again.",
+	700: "Reconnect later, or ask the administrator to raise ws-max-connections.",
+	701: "Close the app on devices or browser tabs you no longer use, or ask the administrator to raise ws-max-connections-per-user.",
+}
+
+// en_category_hints remediation hints shared by all codes of a category
+// en_category_hints 分类下所有错误码共用的处理建议（英文）
+var en_category_hints = map[string]string{
+	CategoryGeneral:    "Retry the request; send the traceId to the administrator if it keeps failing.",
+	CategoryRequest:    "Check the request and retry; send the traceId to the administrator if it keeps failing.",
+	CategoryUser:       "Check the account details and retry.",
+	CategoryVault:      "Check the vault name and retry.",
+	CategoryNote:       "Sync again to refresh the note, then retry.",
+	CategoryFolder:     "Sync again to refresh the folder, then retry.",
+	CategoryFile:       "Sync again to refresh the attachment, then retry.",
+	CategorySetting:    "Sync the settings again, then retry.",
+	CategoryShare:      "Ask the owner of the share for a working link.",
+	CategorySync:       "Check the task settings and its history, then retry.",
+	CategoryStorage:    "Check the storage settings and test the connection.",
+	CategoryGitSync:    "Check the Git sync settings and its history.",
+	CategoryCloudflare: "Check the tunnel settings in the admin panel.",
+	CategoryConflict:   "Resolve the conflict, then sync again.",
+	CategorySnapshot:   "Check the snapshot settings and the free disk space of the server.",
+	CategoryLint:       "Retry later or ask the administrator to check the lint settings.",
+	CategoryBackup:     "Check the backup history entry and retry.",
+	CategoryWebhook:    "Check the webhook settings.",
+	CategoryAlert:      "Check the alert channel settings.",
+	CategoryNotePolicy: "Check the note policy settings.",
+	CategoryDevice:     "Check the device list.",
+	CategoryTemplate:   "Check the note template list.",
+	CategorySearch:     "Check the reindex job list.",
+	CategoryRegister:   "Check the invitation codes and the pending registrations.",
+	CategoryPrefs:      "Pull the latest user settings and retry.",
+	CategoryLimit:      "Reduce the content, or ask the administrator to adjust the content limits.",
+}
@@ -0,0 +1,147 @@
+package code
+
+// zh_cn_hints 需要比所属分类提示更具体说明的错误码的处理建议（中文）
+var zh_cn_hints = map[int]string{
+	300: "请重试；若持续失败，请将响应中的 traceId 发送给管理员以便在服务端日志中定位。",
+	301: "服务端无法读写数据库。请稍后重试；管理员应检查磁盘空间与数据库配置。",
+	302: "服务端当前负载过高，请稍候重试。",
+	303: "已超出请求频率限制，请稍候并降低自动化请求的频率。",
+	304: "请对照 API 文档检查请求方法与路径，当前服务端版本可能不存在该接口。",
+	305: "请根据 details 中列出的参数修正后重新请求。",
+	306: "请重新登录以获取新令牌。",
+	307: "请在 Authorization 请求头或 token 查询参数中携带令牌。",
+	308: "会话已失效，请重新登录。",
+	309: "请先登录，该接口需要用户令牌。",
+	310: "令牌已过期，请重新登录或创建新的 API 令牌。",
+	312: "令牌绑定了其他 IP 地址。请在允许的地址使用，或创建不限制 IP 的令牌。",
+	313: "令牌绑定了其他浏览器。请在同一浏览器中使用或重新登录。",
+	314: "令牌仅限其他客户端使用。请使用允许的客户端或修改令牌限制。",
+	315: "令牌的权限范围不包含该操作。请创建具有所需权限范围的令牌。",
+	316: "你的 IP 地址已被封禁，请联系管理员检查 IP 允许与拒绝列表。",
+	317: "请完成登录页面显示的人机验证后重新登录。",
+	318: "请重新完成人机验证；验证结果几分钟后过期且只能使用一次。",
+	319: "管理员需配置 alert.smtp 与 server.ext-api-url；在此之前请联系管理员重置密码。",
+	320: "请重新申请重置邮件；链接会过期，且密码修改后即失效。",
+	321: "升级、恢复或迁移期间暂停修改。请保留本地修改，维护结束后重新同步。",
+	402: "请检查用户名和密码；多次失败后账户可能会被暂时锁定。",
+	403: "请检查用户名或注册新账户。",
+	404: "请更换用户名。",
+	405: "该邮箱已注册，请直接登录或更换邮箱。",
+	406: "请使用 3-15 位字母、数字或下划线。",
+	407: "请按密码规则设置更长的密码。",
+	408: "请正确输入当前密码。",
+	409: "请在两个输入框中输入相同的新密码。",
+	410: "注册已关闭。请联系管理员创建账户或开启 user.register-is-enable。",
+	411: "请使用 user.admin-uid 中配置的管理员账户登录。",
+	412: "管理员已禁止用户访问本地文件系统。",
+	414: "无法封禁管理员，请先修改 admin-uid 配置。",
+	415: "请在提交密码的同时提交身份验证器应用中的验证码。",
+	416: "请检查运行身份验证器应用的设备时间并输入当前验证码，或使用恢复码。",
+	417: "请先设置双因素认证，再启用或验证。",
+	418: "如需重新设置双因素认证，请先将其关闭。",
+	419: "请等待锁定结束后再试，或联系管理员重置密码。",
+	420: "请检查仓库名称；仓库会在客户端首次同步时创建。",
+	421: "请更换仓库名称。",
+	424: "请先试运行以列出冲突路径，然后转移到其他仓库名称或启用覆盖。",
+	430: "笔记可能已在其他设备上被删除或重命名，请重新同步以刷新笔记列表。",
+	431: "该路径已存在笔记，请更换路径或直接修改现有笔记。",
+	438: "请重命名为未被其他笔记占用的路径。",
+	441: "请更换目标路径。",
+	443: "请修正正则表达式语法。",
+	444: "请使用仓库内不含 '..' 的路径。",
+	445: "目录可能已在其他设备上被删除或重命名，请重新同步。",
+	446: "请更换目录名称。",
+	452: "请先取消归档该目录再修改其内容。",
+	455: "附件可能已在其他设备上被删除，请重新同步。",
+	461: "请重试上传，并检查大小限制与服务端剩余磁盘空间。",
+	462: "请重试上传，服务端未能完成上传准备。",
+	463: "上传会话已过期或已完成，请重新开始上传。",
+	467: "该路径已存在附件，请更换路径。",
+	468: "仅 JPEG、PNG、GIF、BMP 与 WebP 图片支持缩略图，请直接请求附件本身。",
+	479: "请检查配置文件权限与服务端剩余磁盘空间。",
+	480: "请检查分享链接，该分享可能已被删除。",
+	481: "请向分享者索取新的分享链接。",
+	482: "分享者已撤销该分享，请索取新链接。",
+	483: "请输入分享密码。",
+	484: "请向分享者确认分享密码。",
+	491: "历史版本可能已按保留策略被清理。",
+	493: "请检查任务 ID，该任务可能已被删除。",
+	495: "请使用支持的备份类型。",
+	496: "请提供要执行的任务 ID。",
+	497: "请为任务选择仓库。",
+	498: "请为任务选择已存在的存储目标。",
+	499: "请先启用该任务再执行。",
+	500: "请检查存储 ID，该存储可能已被删除。",
+	501: "请选择其他存储类型，或联系管理员启用该类型。",
+	502: "请根据 details 检查端点、存储桶与凭据后重新测试。",
+	510: "请检查 Git 同步 ID，该配置可能已被删除。",
+	511: "请等待正在运行的同步完成。",
+	512: "请根据 details 检查仓库地址、分支与凭据。",
+	520: "请检查服务端访问 GitHub 的网络，或手动安装 cloudflared。",
+	521: "请先在管理面板中下载隧道程序。",
+	530: "请打开冲突副本，手动合并修改后删除副本。",
+	531: "请从笔记中移除凭据，或联系管理员将密钥扫描切换为警告模式。",
+	541: "请等待正在运行的快照完成。",
+	543: "请等待正在运行的数据库导出完成。",
+	550: "请联系管理员在服务端配置中启用 lint。",
+	552: "请检查较短的文本片段。",
+	561: "只能校验成功的全量、增量与去重备份。",
+	571: "请使用完整的 http:// 或 https:// 地址。",
+	572: "请使用 Webhook 文档中列出的事件。",
+	581: "请检查 details 中列出的配置项。",
+	582: "请检查渠道配置，并确认服务端能够访问该服务。",
+	591: "请检查 details 中列出的配置项；归档策略需要与策略目录不同的归档目录。",
+	600: "该设备可能已被撤销，请刷新设备列表。",
+	620: "请检查模板 ID 或名称，该模板可能已被删除。",
+	621: "请使用其他名称，或更新已有的模板。",
+	630: "请在服务端配置中开启 fts-bleve-enabled 并重启。",
+	631: "请先为该用户启动索引重建任务，或刷新任务列表。",
+	640: "请检查邀请码是否输入有误，或向管理员申请新的邀请码。",
+	641: "请刷新邀请码列表，该邀请码可能已被删除。",
+	642: "请刷新待审核列表，该注册可能已被批准或拒绝。",
+	650: "请检查设置键名，该设置可能已在其他设备上被删除。",
+	651: "请将修改合并到错误中返回的当前值，并使用其版本号重试。",
+	660: "请将笔记拆分为多篇较小的笔记，或请管理员调高 max-note-size。",
+	661: "请勿将该附件放入笔记库，或请管理员调高 max-attachment-size。",
+	662: "请删除不再需要的笔记，或请管理员调高 max-notes-per-vault。",
+	670: "请将 log.buffer-size 设为大于 0 并重启服务，或直接查看日志文件。",
+	671: "请确认服务有权限写入 log.sync-trace-dir 目录。",
+	680: "服务正在关闭，请在重启后重试。",
+	681: "任务已完成或已被丢弃，请刷新任务列表。",
+	682: "仅失败的任务可以重新排队，请刷新任务列表。",
+	690: "请在 GET /api/admin/schedules 中确认任务名称；集群部署时任务只存在于执行任务的实例上。",
+	691: "请使用 5 段 cron 表达式（如 30 4 * * *）、@daily 或 @every 90m 等描述符，或 off。",
+	692: "请等待当前执行结束后重试。",
+	700: "请稍后重连，或请管理员调高 ws-max-connections。",
+	701: "请关闭不再使用的设备或浏览器标签页中的应用，或请管理员调高 ws-max-connections-per-user。",
+}
+
+// zh_cn_category_hints 分类下所有错误码共用的处理建议（中文）
+var zh_cn_category_hints = map[string]string{
+	CategoryGeneral:    "请重试；若持续失败，请将 traceId 发送给管理员。",
+	CategoryRequest:    "请检查请求后重试；若持续失败，请将 traceId 发送给管理员。",
+	CategoryUser:       "请检查账户信息后重试。",
+	CategoryVault:      "请检查仓库名称后重试。",
+	CategoryNote:       "请重新同步以刷新笔记后重试。",
+	CategoryFolder:     "请重新同步以刷新目录后重试。",
+	CategoryFile:       "请重新同步以刷新附件后重试。",
+	CategorySetting:    "请重新同步配置后重试。",
+	CategoryShare:      "请向分享者索取可用的链接。",
+	CategorySync:       "请检查任务配置及其历史记录后重试。",
+	CategoryStorage:    "请检查存储配置并测试连接。",
+	CategoryGitSync:    "请检查 Git 同步配置及其历史记录。",
+	CategoryCloudflare: "请在管理面板中检查隧道配置。",
+	CategoryConflict:   "请解决冲突后重新同步。",
+	CategorySnapshot:   "请检查快照配置与服务端剩余磁盘空间。",
+	CategoryLint:       "请稍后重试或联系管理员检查 lint 配置。",
+	CategoryBackup:     "请检查该备份历史记录后重试。",
+	CategoryWebhook:    "请检查 Webhook 配置。",
+	CategoryAlert:      "请检查告警渠道配置。",
+	CategoryNotePolicy: "请检查笔记策略配置。",
+	CategoryDevice:     "请检查设备列表。",
+	CategoryTemplate:   "请检查笔记模板列表。",
+	CategorySearch:     "请检查索引重建任务列表。",
+	CategoryRegister:   "请检查邀请码与待审核的注册。",
+	CategoryPrefs:      "请拉取最新的用户设置后重试。",
+	CategoryLimit:      "请缩小内容，或请管理员调整内容限制。",
+}
@@ -8,6 +8,7 @@ var en_messages = map[int]string{
 	4:   "Delete Success",
 	5:   "Password Update Success",
 	6:   "No Update",
+	7:   "Registration submitted, waiting for administrator approval",
 	300: "Server Internal Error",
 	301: "Database query failed",
 	302: "Server busy, please try again later",
@@ -24,6 +25,12 @@ var en_messages = map[int]string{
 	313: "Auth token Browser (UA) restricted",
 	314: "Auth token Client restricted",
 	315: "Auth token Scope restricted",
+	316: "Access from this IP address is denied",
+	317: "Captcha verification required",
+	318: "Captcha verification failed",
+	319: "Password reset by email is not available",
+	320: "The password reset link is invalid or has expired",
+	321: "Server is in read-only maintenance mode",
 
 	// --- User Related (400-419) ---
 	400: "User registration failed",
@@ -41,12 +48,18 @@ var en_messages = map[int]string{
 	412: "User local file system is disabled",
 	413: "User update failed",
 	414: "Cannot block an administrator",
+	415: "Two-factor authentication code required",
+	416: "Invalid two-factor authentication code",
+	417: "Two-factor authentication has not been set up",
+	418: "Two-factor authentication is already enabled",
+	419: "Too many failed logins, login is temporarily locked",
 
 	// --- Vault Related (420-429) ---
 	420: "Note Vault does not exist",
 	421: "Note Vault already exists",
 	422: "Invalid storage type",
 	423: "Invalid cloud storage type",
+	424: "Vault transfer conflicts with entries of the target vault",
 
 	// --- Note Related (430-444) ---
 	430: "Note does not exist",
@@ -73,6 +86,7 @@ var en_messages = map[int]string{
 	449: "Delete folder failed",
 	450: "Folder list get failed",
 	451: "Folder rename failed",
+	452: "Folder is archived and read-only",
 
 	// --- File/Attachment Related (455-469) ---
 	455: "File does not exist",
@@ -88,6 +102,8 @@ var en_messages = map[int]string{
 	465: "File move failed",
 	466: "File read failed",
 	467: "File already exists",
+	468: "Thumbnails are not supported for this file type",
+	469: "Thumbnail generation failed",
 
 	// --- Setting Related (470-479) ---
 	470: "Setting does not exist",
@@ -130,4 +146,47 @@ var en_messages = map[int]string{
 	520: "Cloudflared download failed",
 	521: "Cloudflared binary not found, please download the tunnel program first",
 	530: "Sync conflict detected, a conflict copy has been created",
+	531: "Note contains credentials and was rejected by secret scanning",
+	540: "Snapshot not found",
+	541: "A snapshot is already running",
+	542: "Snapshot failed",
+	543: "A database export is already running",
+	544: "Database export failed",
+	550: "Spellcheck is not enabled on this server",
+	551: "Spellcheck failed",
+	552: "Text is too long for spellcheck",
+	560: "Backup history not found",
+	561: "Only successful archive and dedupe backups can be verified",
+	570: "Webhook not found",
+	571: "Webhook URL must be an http or https URL",
+	572: "Unknown webhook event",
+	580: "Alert channel not found",
+	581: "Invalid alert channel settings",
+	582: "Failed to send the test alert",
+	590: "Note policy not found",
+	591: "Invalid note policy settings",
+	600: "Device not found",
+	610: "Failed to render note",
+	620: "Note template not found",
+	621: "A note template with this name already exists",
+	630: "Full-text search is disabled",
+	631: "No reindex job found for this user",
+	640: "The invitation code is invalid, expired or used up",
+	641: "Invitation code not found",
+	642: "Pending registration not found",
+	650: "User setting not found",
+	651: "User setting was changed by another device",
+	660: "Note exceeds the maximum note size",
+	661: "Attachment exceeds the maximum attachment size",
+	662: "Vault has reached the maximum number of notes",
+	670: "Log viewer is disabled",
+	671: "Failed to start sync tracing",
+	680: "Background job queue is not running",
+	681: "Background job not found",
+	682: "Background job has not failed",
+	690: "Scheduled task not found",
+	691: "Invalid task schedule",
+	692: "Scheduled task is already running",
+	700: "Server has reached the maximum number of connections",
+	701: "User has reached the maximum number of connections",
 }
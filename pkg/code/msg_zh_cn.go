@@ -8,6 +8,7 @@ var zh_cn_messages = map[int]string{
 	4:   "删除成功",
 	5:   "密码修改成功",
 	6:   "无更新",
+	7:   "注册已提交，等待管理员审核",
 	300: "服务器内部错误",
 	301: "数据库查询失败",
 	302: "服务器繁忙，请稍后重试",
@@ -24,6 +25,12 @@ var zh_cn_messages = map[int]string{
 	313: "安全令牌浏览器 (UA) 访问受限",
 	314: "安全令牌客户端 (Client) 访问受限",
 	315: "安全令牌内容权限 (Scope) 访问受限",
+	316: "该 IP 地址已被禁止访问",
+	317: "需要完成人机验证",
+	318: "人机验证未通过",
+	319: "邮件重置密码功能不可用",
+	320: "密码重置链接无效或已过期",
+	321: "服务器处于只读维护模式",
 
 	// --- User Related (400-419) ---
 	// --- 用户相关 (400-419) ---
@@ -42,6 +49,11 @@ var zh_cn_messages = map[int]string{
 	412: "用户本地文件系统已禁用",
 	413: "用户更新失败",
 	414: "无法拉黑管理员",
+	415: "需要输入两步验证码",
+	416: "两步验证码无效",
+	417: "尚未设置两步验证",
+	418: "两步验证已启用",
+	419: "登录失败次数过多，登录已被暂时锁定",
 
 	// --- Vault Related (420-429) ---
 	// --- 仓库相关 (420-429) ---
@@ -49,6 +61,7 @@ var zh_cn_messages = map[int]string{
 	421: "笔记仓库已经存在",
 	422: "存储类型无效",
 	423: "云存储类型无效",
+	424: "笔记仓库转移与目标仓库中的条目冲突",
 
 	// --- Note Related (430-444) ---
 	// --- 笔记相关 (430-444) ---
@@ -77,6 +90,7 @@ var zh_cn_messages = map[int]string{
 	449: "文件夹删除失败",
 	450: "文件夹列表获取失败",
 	451: "文件夹重命名失败",
+	452: "文件夹已归档，处于只读状态",
 
 	// --- Attachment/File Related (455-469) ---
 	// --- 附件/文件相关 (455-469) ---
@@ -93,6 +107,8 @@ var zh_cn_messages = map[int]string{
 	465: "文件移动失败",
 	466: "文件读取失败",
 	467: "文件已经存在",
+	468: "该文件类型不支持缩略图",
+	469: "缩略图生成失败",
 
 	// --- Config Related (470-479) ---
 	// --- 配置相关 (470-479) ---
@@ -139,4 +155,47 @@ var zh_cn_messages = map[int]string{
 	520: "Cloudflared 下载失败",
 	521: "Cloudflared 隧道程序未找到，请先下载隧道程序",
 	530: "检测到同步冲突，已生成冲突副本",
+	531: "笔记包含敏感凭据，已被密钥扫描拦截",
+	540: "快照不存在",
+	541: "已有快照正在执行",
+	542: "快照失败",
+	543: "已有数据库导出正在执行",
+	544: "数据库导出失败",
+	550: "服务器未开启拼写检查",
+	551: "拼写检查失败",
+	552: "文本过长，无法进行拼写检查",
+	560: "备份历史记录不存在",
+	561: "仅可校验成功的归档备份和去重备份",
+	570: "Webhook 不存在",
+	571: "Webhook 地址必须是 http 或 https URL",
+	572: "未知的 Webhook 事件",
+	580: "告警渠道不存在",
+	581: "告警渠道配置无效",
+	582: "测试告警发送失败",
+	590: "笔记策略不存在",
+	591: "笔记策略配置无效",
+	600: "设备不存在",
+	610: "笔记渲染失败",
+	620: "笔记模板不存在",
+	621: "同名笔记模板已存在",
+	630: "全文搜索未启用",
+	631: "该用户没有索引重建任务",
+	640: "邀请码无效、已过期或已用完",
+	641: "邀请码不存在",
+	642: "待审核的注册不存在",
+	650: "用户设置不存在",
+	651: "用户设置已被其他设备修改",
+	660: "笔记超过最大笔记大小",
+	661: "附件超过最大附件大小",
+	662: "笔记库笔记数量已达上限",
+	670: "日志查看已关闭",
+	671: "开启同步跟踪失败",
+	680: "后台任务队列未运行",
+	681: "后台任务不存在",
+	682: "后台任务未失败",
+	690: "定时任务不存在",
+	691: "任务调度格式无效",
+	692: "定时任务正在执行",
+	700: "服务器连接数已达上限",
+	701: "用户连接数已达上限",
 }
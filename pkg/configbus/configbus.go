@@ -0,0 +1,63 @@
+// Package configbus notifies the running services after the configuration changed, so they re-read their
+// settings without a restart
+// Package configbus 在配置变更后通知运行中的服务，使其无需重启即可重新读取设置
+package configbus
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Bus delivers every published configuration to the subscribers, safe for concurrent use
+// Bus 将发布的配置分发给所有订阅者，可并发使用
+type Bus[T any] struct {
+	logger *zap.Logger
+
+	mu          sync.RWMutex
+	subscribers []subscriber[T]
+}
+
+type subscriber[T any] struct {
+	name   string
+	reload func(T)
+}
+
+// New creates a Bus
+// New 创建 Bus
+func New[T any](logger *zap.Logger) *Bus[T] {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Bus[T]{logger: logger}
+}
+
+// Subscribe registers reload under name, it runs on every publish after the ones registered before it
+// Subscribe 以 name 注册 reload，每次发布时在先注册的订阅者之后执行
+func (b *Bus[T]) Subscribe(name string, reload func(T)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, subscriber[T]{name: name, reload: reload})
+}
+
+// Publish hands cfg to every subscriber before it returns, so the change is live once the caller responds;
+// a panicking subscriber is logged and does not keep the others from reloading
+// Publish 在返回前将 cfg 交给所有订阅者，调用方响应时变更即已生效；订阅者 panic 时记录日志，不影响其他订阅者
+func (b *Bus[T]) Publish(cfg T) {
+	b.mu.RLock()
+	subscribers := append([]subscriber[T](nil), b.subscribers...)
+	b.mu.RUnlock()
+
+	for _, s := range subscribers {
+		b.deliver(s, cfg)
+	}
+}
+
+func (b *Bus[T]) deliver(s subscriber[T], cfg T) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error("config reload panic", zap.String("subscriber", s.name), zap.Any("panic", r), zap.Stack("stack"))
+		}
+	}()
+	s.reload(cfg)
+}
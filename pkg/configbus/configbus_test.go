@@ -0,0 +1,24 @@
+package configbus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus_PublishInOrder(t *testing.T) {
+	bus := New[int](nil)
+	var got []string
+	bus.Subscribe("first", func(v int) { got = append(got, "first") })
+	bus.Subscribe("broken", func(v int) { panic("boom") })
+	bus.Subscribe("last", func(v int) {
+		assert.Equal(t, 7, v)
+		got = append(got, "last")
+	})
+
+	bus.Publish(7)
+	assert.Equal(t, []string{"first", "last"}, got)
+
+	// Nothing subscribed is fine
+	New[string](nil).Publish("unused")
+}
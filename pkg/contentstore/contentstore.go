@@ -0,0 +1,73 @@
+// Package contentstore keeps the per-user content files (note content, history, settings and attachments)
+// in a pluggable store, so the local storage/vault tree can act as a cache on ephemeral disks.
+// Package contentstore 将用户内容文件（笔记内容、历史、配置与附件）保存到可插拔的存储中，
+// 使本地 storage/vault 目录在临时磁盘上仅作为缓存使用。
+package contentstore
+
+import (
+	"io"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/storage/aws_s3"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TypeLocal content is only kept on the local disk, optionally mirrored to another directory
+	// TypeLocal 内容仅保存在本地磁盘，可选镜像到另一个目录
+	TypeLocal = "local"
+	// TypeS3 content is stored in an S3-compatible bucket
+	// TypeS3 内容保存在 S3 兼容的存储桶中
+	TypeS3 = "s3"
+)
+
+// Store content store backend. Keys are slash separated paths like vault/u_1/note/n_2/content.txt.
+// Store 内容存储后端。键为以斜杠分隔的路径，如 vault/u_1/note/n_2/content.txt。
+type Store interface {
+	// Put stores the content read from r under key, replacing the existing one
+	// Put 将从 r 读取的内容保存到 key，替换已存在的内容
+	Put(key string, r io.Reader) error
+	// Open opens the content of key, fs.ErrNotExist is returned when it is missing
+	// Open 打开 key 对应的内容，不存在时返回 fs.ErrNotExist
+	Open(key string) (io.ReadCloser, error)
+	// Delete removes the content of key, a missing key is not an error
+	// Delete 删除 key 对应的内容，不存在不视为错误
+	Delete(key string) error
+	// DeletePrefix removes every key below the folder prefix
+	// DeletePrefix 删除 prefix 目录下的所有键
+	DeletePrefix(prefix string) error
+	// List calls fn with every key below the folder prefix, stopping at the first error fn returns
+	// List 对 prefix 目录下的每个键调用 fn，fn 返回错误时停止
+	List(prefix string, fn func(key string) error) error
+}
+
+// Config content store configuration
+// Config 内容存储配置
+type Config struct {
+	Type      string         // local or s3 // local 或 s3
+	LocalPath string         // Mirror directory of the local backend, empty disables the store // 本地后端的镜像目录，为空时不启用
+	S3        *aws_s3.Config // S3 backend configuration // S3 后端配置
+}
+
+// New creates the configured store, nil is returned when the content only lives in storage/vault
+// New 创建配置的存储，内容仅保存在 storage/vault 时返回 nil
+func New(cfg Config) (Store, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Type)) {
+	case "", TypeLocal:
+		if strings.TrimSpace(cfg.LocalPath) == "" {
+			return nil, nil
+		}
+		return NewLocal(cfg.LocalPath), nil
+	case TypeS3:
+		if cfg.S3 == nil || cfg.S3.BucketName == "" {
+			return nil, errors.New("contentstore: s3 bucket-name is required")
+		}
+		store, err := NewS3(cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, errors.Errorf("contentstore: unsupported type %q", cfg.Type)
+	}
+}
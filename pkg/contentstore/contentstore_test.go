@@ -0,0 +1,79 @@
+package contentstore
+
+import (
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLocal_RoundTrip verifies content can be stored, read back and removed by key and by folder prefix.
+// TestLocal_RoundTrip 验证内容可按键存储、读取，并可按键与目录前缀删除。
+func TestLocal_RoundTrip(t *testing.T) {
+	store := NewLocal(t.TempDir())
+
+	require.NoError(t, store.Put("vault/u_1/file/f_1/file.dat", strings.NewReader("one")))
+	require.NoError(t, store.Put("vault/u_1/file/f_10/file.dat", strings.NewReader("ten")))
+
+	rc, err := store.Open("vault/u_1/file/f_1/file.dat")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "one", string(data))
+
+	require.NoError(t, store.DeletePrefix("vault/u_1/file/f_1/"))
+	_, err = store.Open("vault/u_1/file/f_1/file.dat")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	rc, err = store.Open("vault/u_1/file/f_10/file.dat")
+	require.NoError(t, err)
+	rc.Close()
+
+	require.NoError(t, store.Delete("vault/u_1/file/f_10/file.dat"))
+	require.NoError(t, store.Delete("vault/u_1/file/f_10/file.dat"))
+	_, err = store.Open("vault/u_1/file/f_10/file.dat")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+// TestNew verifies no store is created without a remote location and unknown types are rejected.
+// TestNew 验证未配置远端位置时不创建存储，未知类型会被拒绝。
+func TestNew(t *testing.T) {
+	store, err := New(Config{Type: TypeLocal})
+	require.NoError(t, err)
+	assert.Nil(t, store)
+
+	store, err = New(Config{Type: TypeLocal, LocalPath: t.TempDir()})
+	require.NoError(t, err)
+	assert.IsType(t, &Local{}, store)
+
+	_, err = New(Config{Type: TypeS3})
+	assert.Error(t, err)
+
+	_, err = New(Config{Type: "ftp"})
+	assert.Error(t, err)
+}
+
+// TestLocal_List verifies every key below a folder prefix is listed and a missing folder lists nothing.
+// TestLocal_List 验证会列出目录前缀下的所有键，目录不存在时不返回任何键。
+func TestLocal_List(t *testing.T) {
+	store := NewLocal(t.TempDir())
+	require.NoError(t, store.Put("vault/u_1/note/n_1/content.txt", strings.NewReader("a")))
+	require.NoError(t, store.Put("vault/u_1/file/f_1/file.dat", strings.NewReader("b")))
+	require.NoError(t, store.Put("vault/u_2/note/n_2/content.txt", strings.NewReader("c")))
+
+	var keys []string
+	require.NoError(t, store.List("vault/u_1/", func(key string) error {
+		keys = append(keys, key)
+		return nil
+	}))
+	assert.ElementsMatch(t, []string{"vault/u_1/note/n_1/content.txt", "vault/u_1/file/f_1/file.dat"}, keys)
+
+	require.NoError(t, store.List("vault/u_3/", func(key string) error {
+		t.Errorf("unexpected key %s", key)
+		return nil
+	}))
+}
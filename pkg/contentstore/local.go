@@ -0,0 +1,96 @@
+package contentstore
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local keeps the content below a directory, e.g. a mounted persistent volume
+// Local 将内容保存在某个目录下，例如挂载的持久卷
+type Local struct {
+	root string
+}
+
+// NewLocal creates a store rooted at dir
+// NewLocal 创建以 dir 为根目录的存储
+func NewLocal(dir string) *Local {
+	return &Local{root: dir}
+}
+
+func (l *Local) path(key string) string {
+	return filepath.Join(l.root, filepath.FromSlash(key))
+}
+
+// Put writes the content to a temporary file first and moves it into place
+// Put 先写入临时文件再移动到目标位置
+func (l *Local) Put(key string, r io.Reader) error {
+	dst := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".put-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// Open opens the content file, os.Open already reports fs.ErrNotExist
+// Open 打开内容文件，os.Open 已返回 fs.ErrNotExist
+func (l *Local) Open(key string) (io.ReadCloser, error) {
+	return os.Open(l.path(key))
+}
+
+// Delete removes the content file
+// Delete 删除内容文件
+func (l *Local) Delete(key string) error {
+	if err := os.Remove(l.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// DeletePrefix removes a content folder, the prefix must end with a slash
+// DeletePrefix 删除内容目录，prefix 须以斜杠结尾
+func (l *Local) DeletePrefix(prefix string) error {
+	return os.RemoveAll(l.path(prefix))
+}
+
+// List walks the content folder, temporary files left by an interrupted Put are skipped
+// List 遍历内容目录，跳过中断的 Put 遗留的临时文件
+func (l *Local) List(prefix string, fn func(key string) error) error {
+	root := l.path(prefix)
+	if _, err := os.Stat(root); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, path)
+		if err != nil {
+			return err
+		}
+		return fn(filepath.ToSlash(rel))
+	})
+}
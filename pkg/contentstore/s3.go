@@ -0,0 +1,68 @@
+package contentstore
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/haierkeys/fast-note-sync-service/pkg/storage/aws_s3"
+)
+
+// S3 keeps the content in an S3-compatible bucket, uploads are streamed by the transfer manager
+// S3 将内容保存在 S3 兼容的存储桶中，上传由 transfer manager 流式完成
+type S3 struct {
+	client *aws_s3.S3
+}
+
+// NewS3 creates a store on the bucket of conf, the custom path is used as the key prefix
+// NewS3 在 conf 指定的存储桶上创建存储，自定义路径作为键前缀
+func NewS3(conf *aws_s3.Config) (*S3, error) {
+	client, err := aws_s3.NewClient(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &S3{client: client}, nil
+}
+
+func (s *S3) Put(key string, r io.Reader) error {
+	_, err := s.client.SendFile(key, r, "application/octet-stream", time.Time{})
+	return err
+}
+
+func (s *S3) Open(key string) (io.ReadCloser, error) {
+	rc, err := s.client.Open(key)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fs.ErrNotExist
+		}
+		return nil, err
+	}
+	return rc, nil
+}
+
+func (s *S3) Delete(key string) error {
+	return s.client.Delete(key)
+}
+
+func (s *S3) DeletePrefix(prefix string) error {
+	return s.client.DeletePrefix(prefix)
+}
+
+func (s *S3) List(prefix string, fn func(key string) error) error {
+	return s.client.List(prefix, fn)
+}
+
+// isNotFound reports a missing object, S3-compatible servers do not always send NoSuchKey
+// isNotFound 判断对象是否不存在，S3 兼容服务不一定返回 NoSuchKey
+func isNotFound(err error) bool {
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return true
+	}
+	var respErr *awshttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound
+}
@@ -0,0 +1,104 @@
+// Package healthping reports job runs to dead-man switch services such as healthchecks.io
+// Package healthping 向 healthchecks.io 等失联告警（Dead-man switch）服务上报任务运行情况
+package healthping
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/safego"
+	"go.uber.org/zap"
+)
+
+// Signal kind of ping, appended to the ping URL path the way healthchecks.io expects
+// Signal Ping 类型，按 healthchecks.io 的约定追加到 Ping 地址路径后
+type Signal string
+
+const (
+	// SignalStart the job started, "<url>/start"
+	// SignalStart 任务开始，"<url>/start"
+	SignalStart Signal = "start"
+	// SignalSuccess the job finished successfully, "<url>"
+	// SignalSuccess 任务成功结束，"<url>"
+	SignalSuccess Signal = ""
+	// SignalFail the job failed, "<url>/fail"
+	// SignalFail 任务失败，"<url>/fail"
+	SignalFail Signal = "fail"
+)
+
+// DefaultTimeout timeout of a single ping
+// DefaultTimeout 单次 Ping 的超时时间
+const DefaultTimeout = 10 * time.Second
+
+// maxBodySize ping bodies are cut to this size, healthchecks.io keeps at most 100KB
+// maxBodySize Ping 请求体截断到该大小，healthchecks.io 最多保存 100KB
+const maxBodySize = 10 * 1024
+
+// URL returns the ping URL of signal for base, keeping any query string of base
+// URL 返回 base 对应 signal 的 Ping 地址，保留 base 中的查询参数
+func URL(base string, signal Signal) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	if signal != SignalSuccess {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + string(signal)
+	}
+	return u.String(), nil
+}
+
+// Ping sends signal to base. message, when not empty, is posted as the plain text body
+// so that it shows up in the check's event log. Any non-2xx response is reported as an error.
+// Ping 向 base 发送 signal。message 不为空时作为纯文本请求体提交，显示在检查的事件日志中。
+// 非 2xx 响应视为错误。
+func Ping(ctx context.Context, client *http.Client, base string, signal Signal, message string) error {
+	target, err := URL(base, signal)
+	if err != nil {
+		return err
+	}
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if len(message) > maxBodySize {
+		message = message[:maxBodySize]
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("User-Agent", "fast-note-sync-service")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("healthping: %s responded %s", target, resp.Status)
+	}
+	return nil
+}
+
+// Go sends a ping in the background and logs a failed delivery; an empty base is a no-op
+// Go 在后台发送 Ping 并记录投递失败；base 为空时不做任何事
+func Go(logger *zap.Logger, base string, signal Signal, message string) {
+	if base == "" {
+		return
+	}
+	safego.Go(logger, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+		defer cancel()
+		if err := Ping(ctx, nil, base, signal, message); err != nil {
+			logger.Warn("Failed to deliver health ping", zap.String("signal", string(signal)), zap.Error(err))
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package healthping
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURL(t *testing.T) {
+	cases := []struct {
+		base   string
+		signal Signal
+		want   string
+	}{
+		{"https://hc-ping.com/uuid", SignalSuccess, "https://hc-ping.com/uuid"},
+		{"https://hc-ping.com/uuid", SignalStart, "https://hc-ping.com/uuid/start"},
+		{"https://hc-ping.com/uuid/", SignalFail, "https://hc-ping.com/uuid/fail"},
+		{"https://hc-ping.com/uuid?rid=1", SignalStart, "https://hc-ping.com/uuid/start?rid=1"},
+	}
+	for _, c := range cases {
+		got, err := URL(c.base, c.signal)
+		assert.NoError(t, err)
+		assert.Equal(t, c.want, got)
+	}
+}
+
+func TestPing(t *testing.T) {
+	var gotPath, gotBody string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotBody = r.URL.Path, string(body)
+		if r.URL.Path == "/down/fail" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	assert.NoError(t, Ping(context.Background(), nil, ts.URL+"/check", SignalFail, "boom"))
+	assert.Equal(t, "/check/fail", gotPath)
+	assert.Equal(t, "boom", gotBody)
+
+	assert.Error(t, Ping(context.Background(), nil, ts.URL+"/down", SignalFail, ""))
+}
@@ -0,0 +1,141 @@
+// Package ical writes iCalendar (RFC 5545) feeds that calendar apps can subscribe to.
+// Package ical 生成可供日历应用订阅的 iCalendar (RFC 5545) 订阅源。
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxLineOctets longest content line before it is folded onto a continuation line
+	// maxLineOctets 内容行折行前的最大长度
+	maxLineOctets = 75
+
+	dateFormat     = "20060102"
+	dateTimeFormat = "20060102T150405"
+)
+
+// Calendar a calendar feed
+// Calendar 日历订阅源
+type Calendar struct {
+	ProdID string // Product identifier, such as "-//Vendor//Product//EN" // 产品标识，例如 "-//Vendor//Product//EN"
+	Name   string // Display name shown by calendar apps // 日历应用显示的名称
+	Events []*Event
+}
+
+// Event a calendar event.
+// All-day events only use the date of Start and End, and End is exclusive.
+// Floating events have no time zone and are shown at the same wall clock time in every zone.
+// Event 日历事件。
+// 全天事件仅使用 Start 与 End 的日期，End 不包含在内。
+// 浮动事件没有时区，在所有时区均显示为相同的本地时间。
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Categories  []string
+	Start       time.Time
+	End         time.Time // Zero for events without a duration // 没有持续时间的事件为零值
+	AllDay      bool
+	Floating    bool
+	Stamp       time.Time // Last modification of the source // 来源的最后修改时间
+}
+
+// Marshal encodes the calendar with CRLF line endings and folded lines
+// Marshal 以 CRLF 换行及折行规则编码日历
+func (c *Calendar) Marshal() []byte {
+	var b bytes.Buffer
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+c.ProdID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+	for _, e := range c.Events {
+		e.write(&b)
+	}
+	writeLine(&b, "END:VCALENDAR")
+	return b.Bytes()
+}
+
+func (e *Event) write(b *bytes.Buffer) {
+	writeLine(b, "BEGIN:VEVENT")
+	writeLine(b, "UID:"+e.UID)
+	writeLine(b, "DTSTAMP:"+e.Stamp.UTC().Format(dateTimeFormat)+"Z")
+	writeLine(b, "DTSTART"+e.formatTime(e.Start))
+	if !e.End.IsZero() {
+		writeLine(b, "DTEND"+e.formatTime(e.End))
+	} else if e.AllDay {
+		writeLine(b, "DTEND"+e.formatTime(e.Start.AddDate(0, 0, 1)))
+	}
+	writeLine(b, "SUMMARY:"+escapeText(e.Summary))
+	if e.Description != "" {
+		writeLine(b, "DESCRIPTION:"+escapeText(e.Description))
+	}
+	if e.URL != "" {
+		writeLine(b, "URL:"+e.URL)
+	}
+	if len(e.Categories) > 0 {
+		cats := make([]string, len(e.Categories))
+		for i, c := range e.Categories {
+			cats[i] = escapeText(c)
+		}
+		writeLine(b, "CATEGORIES:"+strings.Join(cats, ","))
+	}
+	if e.AllDay {
+		writeLine(b, "TRANSP:TRANSPARENT")
+	}
+	writeLine(b, "END:VEVENT")
+}
+
+// formatTime returns the value part of a DTSTART or DTEND property, including its parameters
+// formatTime 返回 DTSTART 或 DTEND 属性的值部分，包含其参数
+func (e *Event) formatTime(t time.Time) string {
+	switch {
+	case e.AllDay:
+		return ";VALUE=DATE:" + t.Format(dateFormat)
+	case e.Floating:
+		return ":" + t.Format(dateTimeFormat)
+	default:
+		return ":" + t.UTC().Format(dateTimeFormat) + "Z"
+	}
+}
+
+// escapeText escapes a TEXT value
+// escapeText 转义 TEXT 类型的值
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
+
+// writeLine writes a content line, folding it every 75 octets without splitting a UTF-8 sequence
+// writeLine 写入内容行，每 75 个字节折行且不拆分 UTF-8 字符
+func writeLine(b *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space that counts toward their length
+		// 续行以空格开头，空格计入行长度
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,53 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCalendar_Marshal verifies all-day, floating and zoned events encode with the right DTSTART forms.
+// TestCalendar_Marshal 验证全天、浮动与带时区事件以正确的 DTSTART 形式编码。
+func TestCalendar_Marshal(t *testing.T) {
+	stamp := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	cal := &Calendar{ProdID: "-//Test//EN", Name: "Notes, work", Events: []*Event{
+		{UID: "a@test", Summary: "Daily; review", Start: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), AllDay: true, Stamp: stamp},
+		{UID: "b@test", Summary: "Meeting", Start: time.Date(2024, 3, 2, 15, 30, 0, 0, time.UTC), Floating: true, Stamp: stamp},
+		{UID: "c@test", Summary: "Call", Description: "line1\nline2", Start: time.Date(2024, 3, 2, 9, 0, 0, 0, time.FixedZone("", 3600)), Stamp: stamp, Categories: []string{"work", "a,b"}},
+	}}
+	out := string(cal.Marshal())
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Contains(t, out, "X-WR-CALNAME:Notes\\, work\r\n")
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20240302\r\nDTEND;VALUE=DATE:20240303\r\nSUMMARY:Daily\\; review\r\n")
+	assert.Contains(t, out, "DTSTART:20240302T153000\r\nSUMMARY:Meeting\r\n")
+	assert.Contains(t, out, "DTSTART:20240302T080000Z\r\n")
+	assert.Contains(t, out, "DESCRIPTION:line1\\nline2\r\n")
+	assert.Contains(t, out, "CATEGORIES:work,a\\,b\r\n")
+	assert.Contains(t, out, "DTSTAMP:20240301T080000Z\r\n")
+	assert.Equal(t, 3, strings.Count(out, "BEGIN:VEVENT"))
+}
+
+// TestWriteLine_Folds verifies long lines fold at 75 octets without splitting multi-byte characters.
+// TestWriteLine_Folds 验证长行按 75 字节折行且不拆分多字节字符。
+func TestWriteLine_Folds(t *testing.T) {
+	cal := &Calendar{ProdID: "-//Test//EN", Events: []*Event{
+		{UID: "a@test", Summary: strings.Repeat("日记", 40), Start: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), AllDay: true},
+	}}
+	out := string(cal.Marshal())
+
+	var unfolded strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), 75)
+		if strings.HasPrefix(line, " ") {
+			unfolded.WriteString(line[1:])
+			continue
+		}
+		unfolded.WriteString("\n" + line)
+	}
+	assert.Contains(t, unfolded.String(), "\nSUMMARY:"+strings.Repeat("日记", 40)+"\n")
+}
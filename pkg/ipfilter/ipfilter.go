@@ -0,0 +1,159 @@
+// Package ipfilter provides a static CIDR deny list plus temporary IP bans
+// Package ipfilter 提供静态 CIDR 黑名单及临时 IP 封禁
+package ipfilter
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ban a temporary ban entry
+// Ban 临时封禁记录
+type Ban struct {
+	IP        string    `json:"ip"`        // Banned IP // 被封禁的 IP
+	Reason    string    `json:"reason"`    // Ban reason // 封禁原因
+	CreatedAt time.Time `json:"createdAt"` // Ban time // 封禁时间
+	ExpiresAt time.Time `json:"expiresAt"` // Expiry time // 到期时间
+}
+
+// Filter decides whether a client IP may access the server
+// Filter 判断客户端 IP 是否允许访问
+type Filter struct {
+	mu   sync.RWMutex
+	deny []*net.IPNet
+	bans map[string]Ban
+	now  func() time.Time
+}
+
+// New creates a Filter from a static deny list of IPs or CIDRs
+// New 根据静态 IP/CIDR 黑名单创建 Filter
+func New(deny []string) (*Filter, error) {
+	f := &Filter{
+		bans: make(map[string]Ban),
+		now:  time.Now,
+	}
+	for _, entry := range deny {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		network, err := parseNetwork(entry)
+		if err != nil {
+			return nil, err
+		}
+		f.deny = append(f.deny, network)
+	}
+	return f, nil
+}
+
+// parseNetwork parses a single IP or CIDR into a network
+// parseNetwork 将单个 IP 或 CIDR 解析为网段
+func parseNetwork(entry string) (*net.IPNet, error) {
+	if !strings.Contains(entry, "/") {
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, fmt.Errorf("ipfilter: invalid IP %q", entry)
+		}
+		if v4 := ip.To4(); v4 != nil {
+			return &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, network, err := net.ParseCIDR(entry)
+	if err != nil {
+		return nil, fmt.Errorf("ipfilter: invalid CIDR %q: %w", entry, err)
+	}
+	return network, nil
+}
+
+// IsBlocked reports whether ip is denied statically or currently banned
+// IsBlocked 判断 IP 是否被静态拒绝或处于封禁期
+func (f *Filter) IsBlocked(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	f.mu.RLock()
+	ban, banned := f.bans[parsed.String()]
+	deny := f.deny
+	f.mu.RUnlock()
+
+	if banned {
+		if f.now().Before(ban.ExpiresAt) {
+			return true
+		}
+		f.mu.Lock()
+		if cur, ok := f.bans[parsed.String()]; ok && !f.now().Before(cur.ExpiresAt) {
+			delete(f.bans, parsed.String())
+		}
+		f.mu.Unlock()
+	}
+
+	for _, network := range deny {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Ban blocks ip for duration d. Loopback addresses are never banned.
+// Ban 封禁 IP d 时长，回环地址永不封禁。
+func (f *Filter) Ban(ip string, d time.Duration, reason string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.IsLoopback() || d <= 0 {
+		return false
+	}
+	now := f.now()
+	f.mu.Lock()
+	f.bans[parsed.String()] = Ban{
+		IP:        parsed.String(),
+		Reason:    reason,
+		CreatedAt: now,
+		ExpiresAt: now.Add(d),
+	}
+	f.mu.Unlock()
+	return true
+}
+
+// Unban lifts a temporary ban, returning false if ip was not banned
+// Unban 解除临时封禁，若未封禁返回 false
+func (f *Filter) Unban(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.bans[parsed.String()]; !ok {
+		return false
+	}
+	delete(f.bans, parsed.String())
+	return true
+}
+
+// Bans returns the active bans sorted by creation time, pruning expired ones
+// Bans 返回按封禁时间排序的有效封禁列表，并清理已过期记录
+func (f *Filter) Bans() []Ban {
+	now := f.now()
+	f.mu.Lock()
+	list := make([]Ban, 0, len(f.bans))
+	for ip, ban := range f.bans {
+		if !now.Before(ban.ExpiresAt) {
+			delete(f.bans, ip)
+			continue
+		}
+		list = append(list, ban)
+	}
+	f.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
@@ -0,0 +1,45 @@
+package ipfilter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_DenyList(t *testing.T) {
+	f, err := New([]string{"10.0.0.0/8", "192.168.1.7", "2001:db8::/32"})
+	assert.NoError(t, err)
+
+	assert.True(t, f.IsBlocked("10.1.2.3"))
+	assert.True(t, f.IsBlocked("192.168.1.7"))
+	assert.False(t, f.IsBlocked("192.168.1.8"))
+	assert.True(t, f.IsBlocked("2001:db8::1"))
+	assert.False(t, f.IsBlocked("not-an-ip"))
+
+	_, err = New([]string{"10.0.0.0/99"})
+	assert.Error(t, err)
+}
+
+func TestFilter_BanExpiry(t *testing.T) {
+	f, _ := New(nil)
+	now := time.Unix(1700000000, 0)
+	f.now = func() time.Time { return now }
+
+	assert.True(t, f.Ban("203.0.113.9", time.Hour, "honeypot"))
+	assert.False(t, f.Ban("127.0.0.1", time.Hour, "honeypot"))
+	assert.True(t, f.IsBlocked("203.0.113.9"))
+	assert.Len(t, f.Bans(), 1)
+
+	now = now.Add(2 * time.Hour)
+	assert.False(t, f.IsBlocked("203.0.113.9"))
+	assert.Empty(t, f.Bans())
+}
+
+func TestFilter_Unban(t *testing.T) {
+	f, _ := New(nil)
+	f.Ban("203.0.113.9", time.Hour, "test")
+	assert.True(t, f.Unban("203.0.113.9"))
+	assert.False(t, f.Unban("203.0.113.9"))
+	assert.False(t, f.IsBlocked("203.0.113.9"))
+}
@@ -0,0 +1,530 @@
+// Package jobqueue runs background jobs on a bounded set of workers with retries, coalescing and metrics,
+// and keeps the queued, running and failed jobs visible for the admin API
+// Package jobqueue 以有限数量的 worker 执行后台任务，支持重试、合并与指标统计，
+// 并保留排队、运行与失败的任务供管理接口查看
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Error definitions
+// 错误定义
+var (
+	// ErrQueueClosed returned when the queue is shut down
+	// ErrQueueClosed 队列已关闭时返回
+	ErrQueueClosed = errors.New("job queue is closed")
+	// ErrJobNotFound returned when no kept job has the ID
+	// ErrJobNotFound 没有保留该 ID 的任务时返回
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotFailed returned when requeueing a job that did not fail
+	// ErrJobNotFailed 重新排队的任务并未失败时返回
+	ErrJobNotFailed = errors.New("job has not failed")
+)
+
+// Config job queue configuration
+// Config 任务队列配置
+type Config struct {
+	// Workers jobs running at the same time, default 4
+	// Workers 同时运行的任务数，默认 4
+	Workers int
+	// QueueSize jobs waiting for a worker; when full the caller runs the job itself, default 1000
+	// QueueSize 等待 worker 的任务数；队列满时由调用方自行执行任务，默认 1000
+	QueueSize int
+	// MaxAttempts runs of a failing job before it is kept as failed, default 3
+	// MaxAttempts 失败任务在保留为失败之前的执行次数，默认 3
+	MaxAttempts int
+	// RetryDelay wait before the first retry, doubled for every further one, default 2s
+	// RetryDelay 首次重试前的等待时间，之后每次翻倍，默认 2s
+	RetryDelay time.Duration
+	// JobTimeout longest run of a job, default 5 minutes
+	// JobTimeout 单个任务的最长运行时间，默认 5 分钟
+	JobTimeout time.Duration
+	// FailedKeep failed jobs kept for requeueing, the oldest are dropped first, default 100
+	// FailedKeep 保留供重新排队的失败任务数，超出时先丢弃最早的，默认 100
+	FailedKeep int
+}
+
+// DefaultConfig returns default configuration
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	return Config{
+		Workers:     4,
+		QueueSize:   1000,
+		MaxAttempts: 3,
+		RetryDelay:  2 * time.Second,
+		JobTimeout:  5 * time.Minute,
+		FailedKeep:  100,
+	}
+}
+
+// State state of a job
+// State 任务状态
+type State string
+
+const (
+	StateQueued   State = "queued"   // Waiting for a worker // 等待 worker
+	StateRunning  State = "running"  // Being run // 运行中
+	StateRetrying State = "retrying" // Failed, waiting to run again // 失败后等待再次运行
+	StateFailed   State = "failed"   // Out of attempts, kept for requeueing // 已用完重试次数，保留以便重新排队
+)
+
+// Job snapshot of a job
+// Job 任务快照
+type Job struct {
+	ID         string    // Job ID // 任务 ID
+	Kind       string    // Job kind, e.g. note.links // 任务类型，如 note.links
+	Key        string    // Coalescing key within the kind, empty when the job is never coalesced // 类型内的合并键，为空时不合并
+	UID        int64     // Owner, 0 for server jobs // 所属用户，服务器级任务为 0
+	State      State     // Job state // 任务状态
+	Attempts   int       // Runs so far // 已运行次数
+	LastError  string    // Error of the last run // 最近一次运行的错误
+	EnqueuedAt time.Time // Time the job was queued // 入队时间
+	StartedAt  time.Time // Start of the last run // 最近一次运行的开始时间
+	FinishedAt time.Time // End of the last run // 最近一次运行的结束时间
+	NextRunAt  time.Time // Time of the next retry // 下一次重试时间
+}
+
+// KindMetrics counters of one job kind since the server started
+// KindMetrics 服务启动以来某类任务的计数
+type KindMetrics struct {
+	Kind          string        // Job kind // 任务类型
+	Enqueued      uint64        // Jobs queued // 入队的任务数
+	Coalesced     uint64        // Jobs merged into a queued job with the same key // 合并到同键排队任务中的任务数
+	Inline        uint64        // Jobs run by the caller because the queue was full // 因队列已满由调用方执行的任务数
+	Succeeded     uint64        // Successful runs // 成功的运行次数
+	Retried       uint64        // Failed runs that were retried // 失败后重试的运行次数
+	Failed        uint64        // Jobs out of attempts // 用完重试次数的任务数
+	TotalDuration time.Duration // Time spent running // 运行总耗时
+}
+
+// Metrics queue state and per-kind counters
+// Metrics 队列状态与按类型的计数
+type Metrics struct {
+	Workers   int           // Configured workers // 配置的 worker 数
+	QueueSize int           // Configured queue size // 配置的队列大小
+	Queued    int           // Jobs waiting for a worker // 等待 worker 的任务数
+	Running   int           // Jobs being run // 运行中的任务数
+	Retrying  int           // Jobs waiting to be retried // 等待重试的任务数
+	Failed    int           // Failed jobs kept // 保留的失败任务数
+	Kinds     []KindMetrics // Counters by kind, ordered by kind // 按类型的计数，按类型排序
+}
+
+// JobOptions overrides the queue configuration for one job
+// JobOptions 为单个任务覆盖队列配置
+type JobOptions struct {
+	// MaxAttempts runs of the job before it is kept as failed, 0 uses the queue's MaxAttempts
+	// MaxAttempts 任务保留为失败之前的执行次数，0 表示使用队列的 MaxAttempts
+	MaxAttempts int
+	// Timeout longest run of the job, 0 uses the queue's JobTimeout, negative runs it until the queue shuts down
+	// Timeout 任务的最长运行时间，0 表示使用队列的 JobTimeout，负数表示一直运行到队列关闭
+	Timeout time.Duration
+}
+
+// job a job with its function
+// job 带执行函数的任务
+type job struct {
+	Job
+	fn   func(context.Context) error
+	opts JobOptions
+}
+
+// Queue bounded background job queue, safe for concurrent use
+// Queue 有界后台任务队列，可并发使用
+type Queue struct {
+	config Config
+	logger *zap.Logger
+	now    func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []*job
+	jobs    map[string]*job // Every job that is not done // 所有未完成的任务
+	keyed   map[string]*job // Queued jobs by kind and key // 按类型与键索引的排队任务
+	failed  []*job          // Failed jobs, oldest first // 失败任务，按时间升序
+	kinds   map[string]*KindMetrics
+	running int
+	nextID  uint64
+	closed  bool
+}
+
+// New creates a Queue and starts its workers
+// New 创建 Queue 并启动其 worker
+// cfg: configuration, if nil use default configuration
+// cfg: 配置，如果为 nil 则使用默认配置
+// logger: zap logger, if nil use nop logger
+// logger: zap 日志器，如果为 nil 则使用 nop logger
+func New(cfg *Config, logger *zap.Logger) *Queue {
+	config := DefaultConfig()
+	if cfg != nil {
+		if cfg.Workers > 0 {
+			config.Workers = cfg.Workers
+		}
+		if cfg.QueueSize > 0 {
+			config.QueueSize = cfg.QueueSize
+		}
+		if cfg.MaxAttempts > 0 {
+			config.MaxAttempts = cfg.MaxAttempts
+		}
+		if cfg.RetryDelay > 0 {
+			config.RetryDelay = cfg.RetryDelay
+		}
+		if cfg.JobTimeout > 0 {
+			config.JobTimeout = cfg.JobTimeout
+		}
+		if cfg.FailedKeep > 0 {
+			config.FailedKeep = cfg.FailedKeep
+		}
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{
+		config: config,
+		logger: logger,
+		now:    time.Now,
+		ctx:    ctx,
+		cancel: cancel,
+		jobs:   make(map[string]*job),
+		keyed:  make(map[string]*job),
+		kinds:  make(map[string]*KindMetrics),
+	}
+	q.cond = sync.NewCond(&q.mu)
+
+	for i := 0; i < config.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+	return q
+}
+
+// Enqueue queues fn as a job of kind. A job whose key matches a job of the same kind that is still
+// queued replaces that job's function instead, so only the latest one runs. When the queue is full
+// the caller runs the job itself, slowing producers down instead of dropping work.
+// Enqueue 将 fn 作为 kind 类型的任务排队。键与同类型仍在排队的任务相同时，改为替换该任务的函数，只运行最新的一个。
+// 队列已满时由调用方自行执行任务，以减慢生产者而不是丢弃任务。
+func (q *Queue) Enqueue(kind, key string, uid int64, fn func(context.Context) error) error {
+	_, err := q.EnqueueWithOptions(kind, key, uid, JobOptions{}, fn)
+	return err
+}
+
+// EnqueueWithOptions queues fn like Enqueue with options overriding the queue configuration, and returns
+// the ID of the job, or of the queued job it was merged into
+// EnqueueWithOptions 与 Enqueue 相同地将 fn 排队，并以 opts 覆盖队列配置；返回该任务或其合并入的排队任务的 ID
+func (q *Queue) EnqueueWithOptions(kind, key string, uid int64, opts JobOptions, fn func(context.Context) error) (string, error) {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return "", ErrQueueClosed
+	}
+	metrics := q.kindLocked(kind)
+
+	if key != "" {
+		if queued, ok := q.keyed[kind+"\x00"+key]; ok {
+			queued.fn = fn
+			queued.opts = opts
+			metrics.Coalesced++
+			q.mu.Unlock()
+			return queued.ID, nil
+		}
+	}
+
+	q.nextID++
+	j := &job{
+		Job:  Job{ID: strconv.FormatUint(q.nextID, 10), Kind: kind, Key: key, UID: uid, EnqueuedAt: q.now()},
+		fn:   fn,
+		opts: opts,
+	}
+	q.jobs[j.ID] = j
+	metrics.Enqueued++
+
+	if len(q.pending) >= q.config.QueueSize {
+		metrics.Inline++
+		j.State = StateRunning
+		q.running++
+		q.mu.Unlock()
+
+		q.logger.Warn("job queue full, running job on the caller",
+			zap.String("kind", kind), zap.Int("queueSize", q.config.QueueSize))
+		q.run(j)
+		return j.ID, nil
+	}
+
+	q.pushLocked(j)
+	q.mu.Unlock()
+	return j.ID, nil
+}
+
+// Requeue queues failed jobs again with fresh attempts, every failed job when ids is empty; returns the
+// requeued jobs
+// Requeue 以重置的重试次数重新排队失败任务，ids 为空时重新排队全部失败任务；返回重新排队的任务
+func (q *Queue) Requeue(ids ...string) ([]Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil, ErrQueueClosed
+	}
+
+	var targets []*job
+	if len(ids) == 0 {
+		targets = append(targets, q.failed...)
+	} else {
+		for _, id := range ids {
+			j, ok := q.jobs[id]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+			}
+			if j.State != StateFailed {
+				return nil, fmt.Errorf("%w: %s", ErrJobNotFailed, id)
+			}
+			targets = append(targets, j)
+		}
+	}
+
+	requeued := make([]Job, 0, len(targets))
+	for _, j := range targets {
+		q.removeFailedLocked(j)
+		j.Attempts = 0
+		j.LastError = ""
+		j.EnqueuedAt = q.now()
+		q.pushLocked(j)
+		requeued = append(requeued, j.Job)
+	}
+	return requeued, nil
+}
+
+// Jobs returns the jobs that are not done, filtered by state when one is given, oldest first
+// Jobs 返回未完成的任务，指定状态时按状态过滤，按入队时间升序
+func (q *Queue) Jobs(state State) []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	list := make([]Job, 0, len(q.jobs))
+	for _, j := range q.jobs {
+		if state == "" || j.State == state {
+			list = append(list, j.Job)
+		}
+	}
+	sort.Slice(list, func(a, b int) bool {
+		if !list[a].EnqueuedAt.Equal(list[b].EnqueuedAt) {
+			return list[a].EnqueuedAt.Before(list[b].EnqueuedAt)
+		}
+		idA, _ := strconv.ParseUint(list[a].ID, 10, 64)
+		idB, _ := strconv.ParseUint(list[b].ID, 10, 64)
+		return idA < idB
+	})
+	return list
+}
+
+// Metrics returns the queue state and the per-kind counters
+// Metrics 返回队列状态与按类型的计数
+func (q *Queue) Metrics() Metrics {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	m := Metrics{
+		Workers:   q.config.Workers,
+		QueueSize: q.config.QueueSize,
+		Queued:    len(q.pending),
+		Running:   q.running,
+		Failed:    len(q.failed),
+		Kinds:     make([]KindMetrics, 0, len(q.kinds)),
+	}
+	for _, j := range q.jobs {
+		if j.State == StateRetrying {
+			m.Retrying++
+		}
+	}
+	for _, k := range q.kinds {
+		m.Kinds = append(m.Kinds, *k)
+	}
+	sort.Slice(m.Kinds, func(a, b int) bool { return m.Kinds[a].Kind < m.Kinds[b].Kind })
+	return m
+}
+
+// Shutdown stops accepting jobs and waits for the queued ones to finish; when ctx ends first the running
+// jobs are cancelled. Jobs waiting for a retry are dropped.
+// Shutdown 停止接收任务并等待排队中的任务完成；ctx 先结束时取消运行中的任务。等待重试的任务被丢弃。
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		return ctx.Err()
+	}
+}
+
+// worker runs queued jobs until the queue is shut down and drained
+// worker 执行排队任务，直到队列关闭且清空
+func (q *Queue) worker() {
+	defer q.wg.Done()
+	for {
+		q.mu.Lock()
+		for len(q.pending) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if len(q.pending) == 0 {
+			q.mu.Unlock()
+			return
+		}
+		j := q.pending[0]
+		q.pending[0] = nil
+		q.pending = q.pending[1:]
+		if j.Key != "" {
+			delete(q.keyed, j.Kind+"\x00"+j.Key)
+		}
+		j.State = StateRunning
+		q.running++
+		q.mu.Unlock()
+
+		q.run(j)
+	}
+}
+
+// run runs a job once and records the outcome; the caller has marked it running
+// run 执行一次任务并记录结果；调用方已将其标记为运行中
+func (q *Queue) run(j *job) {
+	q.mu.Lock()
+	j.Attempts++
+	j.StartedAt = q.now()
+	j.NextRunAt = time.Time{}
+	fn := j.fn
+	timeout := j.opts.Timeout
+	q.mu.Unlock()
+
+	if timeout == 0 {
+		timeout = q.config.JobTimeout
+	}
+	ctx, cancel := context.WithCancel(q.ctx)
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(q.ctx, timeout)
+	}
+	err := call(ctx, fn)
+	cancel()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.running--
+	j.FinishedAt = q.now()
+	metrics := q.kindLocked(j.Kind)
+	metrics.TotalDuration += j.FinishedAt.Sub(j.StartedAt)
+
+	if err == nil {
+		metrics.Succeeded++
+		delete(q.jobs, j.ID)
+		return
+	}
+
+	j.LastError = err.Error()
+	maxAttempts := j.opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = q.config.MaxAttempts
+	}
+	if j.Attempts < maxAttempts && !q.closed {
+		metrics.Retried++
+		delay := q.config.RetryDelay << (j.Attempts - 1)
+		j.State = StateRetrying
+		j.NextRunAt = j.FinishedAt.Add(delay)
+		time.AfterFunc(delay, func() { q.retry(j) })
+		return
+	}
+
+	metrics.Failed++
+	j.State = StateFailed
+	q.failed = append(q.failed, j)
+	if len(q.failed) > q.config.FailedKeep {
+		delete(q.jobs, q.failed[0].ID)
+		q.failed = q.failed[1:]
+	}
+	q.logger.Warn("background job failed",
+		zap.String("kind", j.Kind),
+		zap.String("key", j.Key),
+		zap.Int64("uid", j.UID),
+		zap.Int("attempts", j.Attempts),
+		zap.Error(err))
+}
+
+// retry queues a job again once its retry delay has passed
+// retry 在重试延迟结束后重新排队任务
+func (q *Queue) retry(j *job) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed || j.State != StateRetrying {
+		delete(q.jobs, j.ID)
+		return
+	}
+	q.pushLocked(j)
+}
+
+// pushLocked appends a job to the pending list and wakes a worker
+// pushLocked 将任务追加到等待列表并唤醒一个 worker
+func (q *Queue) pushLocked(j *job) {
+	j.State = StateQueued
+	if j.Key != "" {
+		q.keyed[j.Kind+"\x00"+j.Key] = j
+	}
+	q.pending = append(q.pending, j)
+	q.cond.Signal()
+}
+
+// removeFailedLocked takes a job off the failed list
+// removeFailedLocked 将任务移出失败列表
+func (q *Queue) removeFailedLocked(j *job) {
+	for i, f := range q.failed {
+		if f == j {
+			q.failed = append(q.failed[:i], q.failed[i+1:]...)
+			return
+		}
+	}
+}
+
+// kindLocked returns the counters of a kind, creating them on first use
+// kindLocked 返回某类型的计数，首次使用时创建
+func (q *Queue) kindLocked(kind string) *KindMetrics {
+	metrics, ok := q.kinds[kind]
+	if !ok {
+		metrics = &KindMetrics{Kind: kind}
+		q.kinds[kind] = metrics
+	}
+	return metrics
+}
+
+// call runs fn, turning a panic into an error
+// call 执行 fn，并将 panic 转换为错误
+func call(ctx context.Context, fn func(context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
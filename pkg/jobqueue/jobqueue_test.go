@@ -0,0 +1,118 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue_RetryFailAndRequeue(t *testing.T) {
+	q := New(&Config{Workers: 1, MaxAttempts: 2, RetryDelay: time.Millisecond}, nil)
+	defer q.Shutdown(context.Background())
+
+	var runs atomic.Int32
+	var healthy atomic.Bool
+	require.NoError(t, q.Enqueue("note.links", "7", 1, func(ctx context.Context) error {
+		runs.Add(1)
+		if healthy.Load() {
+			return nil
+		}
+		return errors.New("database is locked")
+	}))
+
+	require.Eventually(t, func() bool { return len(q.Jobs(StateFailed)) == 1 }, time.Second, time.Millisecond)
+	failed := q.Jobs(StateFailed)[0]
+	assert.Equal(t, "note.links", failed.Kind)
+	assert.Equal(t, 2, failed.Attempts)
+	assert.Equal(t, "database is locked", failed.LastError)
+	assert.EqualValues(t, 2, runs.Load())
+
+	_, err := q.Requeue("404")
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	healthy.Store(true)
+	requeued, err := q.Requeue(failed.ID)
+	require.NoError(t, err)
+	require.Len(t, requeued, 1)
+
+	require.Eventually(t, func() bool { return len(q.Jobs("")) == 0 }, time.Second, time.Millisecond)
+	metrics := q.Metrics()
+	require.Len(t, metrics.Kinds, 1)
+	assert.EqualValues(t, 1, metrics.Kinds[0].Succeeded)
+	assert.EqualValues(t, 1, metrics.Kinds[0].Retried)
+	assert.EqualValues(t, 1, metrics.Kinds[0].Failed)
+}
+
+func TestQueue_CoalesceAndInline(t *testing.T) {
+	q := New(&Config{Workers: 1, QueueSize: 2}, nil)
+
+	// Hold the only worker so the next jobs stay queued
+	release := make(chan struct{})
+	started := make(chan struct{})
+	require.NoError(t, q.Enqueue("block", "", 0, func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}))
+	<-started
+
+	var got atomic.Value
+	require.NoError(t, q.Enqueue("vault.count", "1_2", 1, func(ctx context.Context) error { got.Store("first"); return nil }))
+	require.NoError(t, q.Enqueue("vault.count", "1_2", 1, func(ctx context.Context) error { got.Store("latest"); return nil }))
+	require.NoError(t, q.Enqueue("note.stats", "", 1, func(ctx context.Context) error { return nil }))
+	assert.Len(t, q.Jobs(StateQueued), 2)
+
+	// The queue is full, the caller runs the job
+	inline := false
+	require.NoError(t, q.Enqueue("note.stats", "", 1, func(ctx context.Context) error { inline = true; return nil }))
+	assert.True(t, inline)
+
+	close(release)
+	require.NoError(t, q.Shutdown(context.Background()))
+	assert.Equal(t, "latest", got.Load())
+
+	for _, k := range q.Metrics().Kinds {
+		switch k.Kind {
+		case "vault.count":
+			assert.EqualValues(t, 1, k.Coalesced)
+			assert.EqualValues(t, 1, k.Succeeded)
+		case "note.stats":
+			assert.EqualValues(t, 1, k.Inline)
+			assert.EqualValues(t, 2, k.Succeeded)
+		}
+	}
+	assert.ErrorIs(t, q.Enqueue("note.stats", "", 1, func(ctx context.Context) error { return nil }), ErrQueueClosed)
+}
+
+func TestQueue_EnqueueWithOptions(t *testing.T) {
+	q := New(&Config{Workers: 1, MaxAttempts: 3, RetryDelay: time.Millisecond, JobTimeout: time.Millisecond}, nil)
+
+	// One attempt and no deadline despite the short queue timeout
+	var runs atomic.Int32
+	id, err := q.EnqueueWithOptions("vault.import", "", 1, JobOptions{MaxAttempts: 1, Timeout: -1}, func(ctx context.Context) error {
+		runs.Add(1)
+		if _, ok := ctx.Deadline(); ok {
+			return errors.New("unexpected deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+		return ctx.Err()
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, id)
+	require.Eventually(t, func() bool { return len(q.Jobs("")) == 0 }, time.Second, time.Millisecond)
+	assert.EqualValues(t, 1, runs.Load())
+
+	_, err = q.EnqueueWithOptions("vault.import", "", 1, JobOptions{MaxAttempts: 1, Timeout: -1}, func(ctx context.Context) error {
+		return errors.New("invalid zip archive")
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(q.Jobs(StateFailed)) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, 1, q.Jobs(StateFailed)[0].Attempts)
+
+	require.NoError(t, q.Shutdown(context.Background()))
+}
@@ -0,0 +1,99 @@
+package limiter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/ratelimit"
+)
+
+// keyedIdleTTL buckets unused for this long are evicted
+// keyedIdleTTL 超过该时长未使用的令牌桶会被回收
+const keyedIdleTTL = 10 * time.Minute
+
+type keyedBucket struct {
+	bucket   *ratelimit.Bucket
+	lastSeen time.Time
+	limited  bool // Whether the last request was rejected // 上一次请求是否被拒绝
+}
+
+// KeyedLimiter token bucket limiter with one bucket per key (uid, connection, IP...).
+// A nil KeyedLimiter allows everything.
+// KeyedLimiter 按 key（uid、连接、IP 等）分别维护令牌桶的限流器，nil 时不做限制。
+type KeyedLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	capacity  int64
+	buckets   map[string]*keyedBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewKeyedLimiter creates a KeyedLimiter refilling rate tokens per second up to burst.
+// Returns nil when rate <= 0, which disables limiting.
+// NewKeyedLimiter 创建每秒补充 rate 个令牌、容量为 burst 的 KeyedLimiter，rate <= 0 时返回 nil 表示不限流。
+func NewKeyedLimiter(rate float64, burst int64) *KeyedLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = int64(rate)
+		if burst < 1 {
+			burst = 1
+		}
+	}
+	return &KeyedLimiter{
+		rate:     rate,
+		capacity: burst,
+		buckets:  make(map[string]*keyedBucket),
+		now:      time.Now,
+	}
+}
+
+// Take consumes one token for key. ok reports whether the request is allowed; first is
+// true only for the first rejection after an allowed request, so callers can log once per burst.
+// Take 为 key 消耗一个令牌。ok 表示是否放行；first 仅在放行后的首次拒绝时为 true，便于调用方每轮只记录一次日志。
+func (l *KeyedLimiter) Take(key string) (ok bool, first bool) {
+	if l == nil {
+		return true, false
+	}
+
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > keyedIdleTTL {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastSeen) > keyedIdleTTL {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &keyedBucket{bucket: ratelimit.NewBucketWithRate(l.rate, l.capacity)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	if b.bucket.TakeAvailable(1) > 0 {
+		b.limited = false
+		return true, false
+	}
+	first = !b.limited
+	b.limited = true
+	return false, first
+}
+
+// Remove drops the bucket for key, e.g. when a connection closes
+// Remove 删除 key 对应的令牌桶，例如连接关闭时
+func (l *KeyedLimiter) Remove(key string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	delete(l.buckets, key)
+	l.mu.Unlock()
+}
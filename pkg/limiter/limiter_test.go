@@ -76,3 +76,37 @@ func TestMethodLimiter_Buckets(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, int64(5), bucket2.Capacity())
 }
+
+func TestKeyedLimiter_Take(t *testing.T) {
+	l := NewKeyedLimiter(1, 2)
+
+	ok, _ := l.Take("uid:1")
+	assert.True(t, ok)
+	ok, _ = l.Take("uid:1")
+	assert.True(t, ok)
+
+	// Bucket exhausted: only the first rejection is flagged
+	ok, first := l.Take("uid:1")
+	assert.False(t, ok)
+	assert.True(t, first)
+	ok, first = l.Take("uid:1")
+	assert.False(t, ok)
+	assert.False(t, first)
+
+	// Other keys have their own bucket
+	ok, _ = l.Take("uid:2")
+	assert.True(t, ok)
+
+	l.Remove("uid:1")
+	ok, _ = l.Take("uid:1")
+	assert.True(t, ok)
+}
+
+func TestKeyedLimiter_Disabled(t *testing.T) {
+	l := NewKeyedLimiter(0, 10)
+	assert.Nil(t, l)
+	for i := 0; i < 100; i++ {
+		ok, _ := l.Take("any")
+		assert.True(t, ok)
+	}
+}
@@ -0,0 +1,114 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LanguageTool client of the LanguageTool HTTP API (self-hosted or languagetoolplus.com)
+// LanguageTool LanguageTool HTTP API 客户端（自托管或 languagetoolplus.com）
+type LanguageTool struct {
+	BaseURL  string
+	Username string // Premium account, optional // 高级版账号，可选
+	APIKey   string // Premium API key, optional // 高级版 API 密钥，可选
+	client   *http.Client
+}
+
+// NewLanguageTool creates a LanguageTool client, baseURL is the server root such as http://localhost:8010
+// NewLanguageTool 创建 LanguageTool 客户端，baseURL 为服务器根地址，例如 http://localhost:8010
+func NewLanguageTool(baseURL, username, apiKey string, timeout time.Duration) *LanguageTool {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &LanguageTool{
+		BaseURL:  strings.TrimRight(baseURL, "/"),
+		Username: username,
+		APIKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+type languageToolResponse struct {
+	Matches []struct {
+		Message      string `json:"message"`
+		ShortMessage string `json:"shortMessage"`
+		Offset       int    `json:"offset"`
+		Length       int    `json:"length"`
+		Replacements []struct {
+			Value string `json:"value"`
+		} `json:"replacements"`
+		Rule struct {
+			ID       string `json:"id"`
+			Category struct {
+				Name string `json:"name"`
+			} `json:"category"`
+		} `json:"rule"`
+	} `json:"matches"`
+}
+
+// maxReplacements replacements kept per issue, LanguageTool may suggest dozens
+// maxReplacements 每个问题保留的替换建议数，LanguageTool 可能给出几十个
+const maxReplacements = 5
+
+// Check sends the text to /v2/check
+// Check 将文本发送到 /v2/check
+func (c *LanguageTool) Check(ctx context.Context, text, language string) ([]Issue, error) {
+	if language == "" {
+		language = "auto"
+	}
+	form := url.Values{"text": {text}, "language": {language}}
+	if c.Username != "" && c.APIKey != "" {
+		form.Set("username", c.Username)
+		form.Set("apiKey", c.APIKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseURL+"/v2/check", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("languagetool api error: status=%d, body=%s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var res languageToolResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+
+	issues := make([]Issue, 0, len(res.Matches))
+	for _, m := range res.Matches {
+		issue := Issue{
+			Offset:       m.Offset,
+			Length:       m.Length,
+			Message:      m.Message,
+			ShortMessage: m.ShortMessage,
+			Rule:         m.Rule.ID,
+			Category:     m.Rule.Category.Name,
+			Replacements: []string{},
+		}
+		for i, r := range m.Replacements {
+			if i == maxReplacements {
+				break
+			}
+			issue.Replacements = append(issue.Replacements, r.Value)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
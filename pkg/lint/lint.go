@@ -0,0 +1,40 @@
+// Package lint checks note text for spelling and grammar issues,
+// either through a LanguageTool server or a bundled word-list spellchecker.
+// Package lint 检查笔记文本中的拼写与语法问题，
+// 可使用 LanguageTool 服务器或内置的词表拼写检查器。
+package lint
+
+import (
+	"context"
+	"unicode/utf16"
+)
+
+// Issue a spelling or grammar issue found in the checked text.
+// Offset and Length count UTF-16 code units, the same unit as JavaScript string indices and LanguageTool.
+// Issue 在被检查文本中发现的拼写或语法问题。
+// Offset 与 Length 以 UTF-16 码元计数，与 JavaScript 字符串下标及 LanguageTool 一致。
+type Issue struct {
+	Offset       int      `json:"offset"`
+	Length       int      `json:"length"`
+	Message      string   `json:"message"`
+	ShortMessage string   `json:"shortMessage"`
+	Rule         string   `json:"rule"`
+	Category     string   `json:"category"`
+	Replacements []string `json:"replacements"`
+}
+
+// Checker checks text in the given language, "auto" lets the checker detect it
+// Checker 按指定语言检查文本，"auto" 表示由检查器自动识别
+type Checker interface {
+	Check(ctx context.Context, text, language string) ([]Issue, error)
+}
+
+// utf16Len length of s in UTF-16 code units
+// utf16Len 返回 s 的 UTF-16 码元长度
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
@@ -0,0 +1,72 @@
+package lint
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguageTool_Check(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/check", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "This are bad.", r.PostForm.Get("text"))
+		assert.Equal(t, "en-US", r.PostForm.Get("language"))
+		assert.Empty(t, r.PostForm.Get("apiKey"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"matches":[{"message":"Use 'is'.","shortMessage":"Grammar","offset":5,"length":3,
+			"replacements":[{"value":"is"},{"value":"was"}],
+			"rule":{"id":"THIS_NNS","category":{"id":"GRAMMAR","name":"Grammar"}}}]}`))
+	}))
+	defer server.Close()
+
+	issues, err := NewLanguageTool(server.URL+"/", "", "", time.Second).Check(context.Background(), "This are bad.", "en-US")
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, Issue{
+		Offset:       5,
+		Length:       3,
+		Message:      "Use 'is'.",
+		ShortMessage: "Grammar",
+		Rule:         "THIS_NNS",
+		Category:     "Grammar",
+		Replacements: []string{"is", "was"},
+	}, issues[0])
+}
+
+func TestLanguageTool_CheckError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "text too long", http.StatusRequestEntityTooLarge)
+	}))
+	defer server.Close()
+
+	_, err := NewLanguageTool(server.URL, "", "", time.Second).Check(context.Background(), "x", "")
+	assert.ErrorContains(t, err, "status=413")
+}
+
+func TestWordList_Check(t *testing.T) {
+	wl, err := ReadWordList(strings.NewReader("4\nhello/S\nworld\nword\ndon't\n"))
+	require.NoError(t, err)
+
+	text := "---\ntitle: helo\n---\n你好 Hello wrold, don't `wrold` [[Wrold]] https://wrold.example foo_wrold NASA\n```\nwrold\n```\nwordd\n"
+	issues, err := wl.Check(context.Background(), text, "auto")
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+
+	// Offsets count UTF-16 code units: 你好 is 2 units
+	// 偏移以 UTF-16 码元计数：你好 占 2 个码元
+	line := strings.Index(text, "你好")
+	assert.Equal(t, line+len([]rune("你好 Hello ")), issues[0].Offset)
+	assert.Equal(t, 5, issues[0].Length)
+	assert.Equal(t, []string{"world"}, issues[0].Replacements)
+
+	assert.Equal(t, utf16Len(text[:strings.Index(text, "wordd")]), issues[1].Offset)
+	assert.Equal(t, []string{"word", "world"}, issues[1].Replacements)
+}
@@ -0,0 +1,291 @@
+package lint
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WordList bundled spellchecker flagging words missing from a word list.
+// It only knows spelling, grammar needs a LanguageTool server.
+// WordList 内置拼写检查器，标记词表中不存在的单词。
+// 仅检查拼写，语法检查需要 LanguageTool 服务器。
+type WordList struct {
+	words    map[string]struct{}
+	alphabet []rune
+	scripts  []*unicode.RangeTable // Scripts covered by the list, words of other scripts are not checked // 词表覆盖的文字，其他文字的单词不检查
+}
+
+// wordListScripts scripts a word list may cover
+// wordListScripts 词表可能覆盖的文字
+var wordListScripts = []*unicode.RangeTable{unicode.Latin, unicode.Cyrillic, unicode.Greek}
+
+// maxSuggestions replacements suggested per misspelled word
+// maxSuggestions 每个拼写错误给出的替换建议数
+const maxSuggestions = 5
+
+// LoadWordList reads a word list file: one word per line, Hunspell .dic files are accepted as well
+// LoadWordList 读取词表文件：每行一个单词，也可直接使用 Hunspell .dic 文件
+func LoadWordList(path string) (*WordList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadWordList(f)
+}
+
+// ReadWordList reads a word list from r, see LoadWordList
+// ReadWordList 从 r 读取词表，参见 LoadWordList
+func ReadWordList(r io.Reader) (*WordList, error) {
+	wl := &WordList{words: make(map[string]struct{})}
+	runes := make(map[rune]struct{})
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || isDigits(line) {
+			continue // Comments and the Hunspell entry count // 注释与 Hunspell 词条数
+		}
+		// Hunspell affix flags: word/FLAGS
+		// Hunspell 词缀标记：word/FLAGS
+		if i := strings.IndexByte(line, '/'); i > 0 {
+			line = line[:i]
+		}
+		wl.words[line] = struct{}{}
+		wl.words[strings.ToLower(line)] = struct{}{}
+		for _, r := range strings.ToLower(line) {
+			runes[r] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for r := range runes {
+		if unicode.IsLetter(r) {
+			wl.alphabet = append(wl.alphabet, r)
+		}
+	}
+	sort.Slice(wl.alphabet, func(i, j int) bool { return wl.alphabet[i] < wl.alphabet[j] })
+	for _, script := range wordListScripts {
+		for _, r := range wl.alphabet {
+			if unicode.Is(script, r) {
+				wl.scripts = append(wl.scripts, script)
+				break
+			}
+		}
+	}
+	return wl, nil
+}
+
+// Check flags words missing from the list, the language is ignored
+// Check 标记词表中不存在的单词，忽略语言参数
+func (wl *WordList) Check(ctx context.Context, text, language string) ([]Issue, error) {
+	issues := []Issue{}
+	forEachWord(text, func(word string, offset int) {
+		if !wl.checks(word) || wl.known(word) {
+			return
+		}
+		issues = append(issues, Issue{
+			Offset:       offset,
+			Length:       utf16Len(word),
+			Message:      "Possible spelling mistake found.",
+			ShortMessage: "Spelling mistake",
+			Rule:         "WORDLIST_SPELLING",
+			Category:     "Possible Typo",
+			Replacements: wl.suggest(word),
+		})
+	})
+	return issues, ctx.Err()
+}
+
+// checks reports whether the word is written in a script covered by the list;
+// single letters and all-caps abbreviations are never flagged
+// checks 判断单词是否使用词表覆盖的文字书写；单个字母与全大写缩写从不标记
+func (wl *WordList) checks(word string) bool {
+	if utf8.RuneCountInString(word) < 2 || strings.ToUpper(word) == word {
+		return false
+	}
+	first, _ := utf8.DecodeRuneInString(word)
+	for _, script := range wl.scripts {
+		if unicode.Is(script, first) {
+			return true
+		}
+	}
+	return false
+}
+
+func (wl *WordList) known(word string) bool {
+	if _, ok := wl.words[word]; ok {
+		return true
+	}
+	_, ok := wl.words[strings.ToLower(word)]
+	return ok
+}
+
+// suggest returns known words one edit away: a deletion, transposition, replacement or insertion
+// suggest 返回一次编辑可达的已知单词：删除、交换、替换或插入一个字母
+func (wl *WordList) suggest(word string) []string {
+	lower := []rune(strings.ToLower(word))
+	found := make(map[string]struct{})
+	try := func(candidate []rune) {
+		if _, ok := wl.words[string(candidate)]; ok {
+			found[string(candidate)] = struct{}{}
+		}
+	}
+
+	buf := make([]rune, 0, len(lower)+1)
+	for i := range lower {
+		try(append(append(buf[:0], lower[:i]...), lower[i+1:]...))
+		if i+1 < len(lower) {
+			swapped := append(buf[:0], lower...)
+			swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+			try(swapped)
+		}
+	}
+	for i := 0; i <= len(lower); i++ {
+		for _, r := range wl.alphabet {
+			if i < len(lower) && r != lower[i] {
+				replaced := append(buf[:0], lower...)
+				replaced[i] = r
+				try(replaced)
+			}
+			try(append(append(append(buf[:0], lower[:i]...), r), lower[i:]...))
+		}
+	}
+
+	suggestions := make([]string, 0, len(found))
+	for w := range found {
+		suggestions = append(suggestions, matchCase(w, word))
+	}
+	sort.Strings(suggestions)
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
+
+// matchCase capitalizes the suggestion when the checked word is capitalized
+// matchCase 被检查的单词首字母大写时，建议也首字母大写
+func matchCase(suggestion, word string) string {
+	first, _ := utf8.DecodeRuneInString(word)
+	if !unicode.IsUpper(first) {
+		return suggestion
+	}
+	r, size := utf8.DecodeRuneInString(suggestion)
+	return string(unicode.ToUpper(r)) + suggestion[size:]
+}
+
+// forEachWord calls fn for every word of a Markdown text with its UTF-16 offset,
+// skipping front matter, code, URLs and wiki-link targets
+// forEachWord 对 Markdown 文本中的每个单词及其 UTF-16 偏移调用 fn，
+// 跳过 front matter、代码、URL 与双链目标
+func forEachWord(text string, fn func(word string, offset int)) {
+	offset := 0
+	inFence, inFrontMatter := false, strings.HasPrefix(text, "---\n") || strings.HasPrefix(text, "---\r\n")
+	first := true
+
+	for line := range strings.SplitAfterSeq(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inFrontMatter:
+			if !first && trimmed == "---" {
+				inFrontMatter = false
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			inFence = !inFence
+		case !inFence:
+			forEachWordInLine(line, offset, fn)
+		}
+		first = false
+		offset += utf16Len(line)
+	}
+}
+
+func forEachWordInLine(line string, offset int, fn func(word string, offset int)) {
+	i := 0
+	for i < len(line) {
+		rest := line[i:]
+		switch {
+		case rest[0] == '`':
+			i += skipUntil(rest, 1, "`")
+			continue
+		case strings.HasPrefix(rest, "[["):
+			i += skipUntil(rest, 2, "]]")
+			continue
+		case strings.HasPrefix(rest, "http://") || strings.HasPrefix(rest, "https://"):
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			i += end
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(rest)
+		if !unicode.IsLetter(r) {
+			i += size
+			continue
+		}
+
+		end := wordEnd(rest)
+		word := rest[:end]
+		prev, _ := utf8.DecodeLastRuneInString(line[:i])
+		next, _ := utf8.DecodeRuneInString(rest[end:])
+		// Identifiers such as foo_bar or v2beta are not words
+		// foo_bar、v2beta 之类的标识符不是单词
+		if !isIdentifierRune(prev) && !isIdentifierRune(next) {
+			fn(word, offset+utf16Len(line[:i]))
+		}
+		i += end
+	}
+}
+
+// wordEnd byte length of the word at the start of s, letters joined by single apostrophes
+// wordEnd 返回 s 开头单词的字节长度，单词由字母及其间的单个撇号组成
+func wordEnd(s string) int {
+	end := 0
+	for end < len(s) {
+		r, size := utf8.DecodeRuneInString(s[end:])
+		if unicode.IsLetter(r) || unicode.Is(unicode.Mn, r) {
+			end += size
+			continue
+		}
+		if r == '\'' || r == '’' {
+			if next, _ := utf8.DecodeRuneInString(s[end+size:]); unicode.IsLetter(next) {
+				end += size
+				continue
+			}
+		}
+		break
+	}
+	return end
+}
+
+// skipUntil bytes to skip past the closing marker, the rest of s when it is not closed
+// skipUntil 返回跳过闭合标记所需的字节数，未闭合时跳过 s 的剩余部分
+func skipUntil(s string, open int, marker string) int {
+	if end := strings.Index(s[open:], marker); end >= 0 {
+		return open + end + len(marker)
+	}
+	return len(s)
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || unicode.IsDigit(r)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,243 @@
+// Package logbuf keeps the most recent log entries in memory so they can be queried and followed without
+// access to the log file
+// Package logbuf 在内存中保存最近的日志条目，无需访问日志文件即可查询与跟踪
+package logbuf
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// followBuffer entries a follower may lag behind before new ones are dropped for it
+// followBuffer 跟踪者可积压的条目数，超出后丢弃新条目
+const followBuffer = 256
+
+// Entry a recorded log entry
+// Entry 一条记录的日志
+type Entry struct {
+	Seq     uint64         // Increasing sequence number // 递增序号
+	Time    time.Time      // Log time // 日志时间
+	Level   zapcore.Level  // Log level // 日志级别
+	Module  string         // Logger name, or the message up to the first space or dot // 日志器名称，或消息中第一个空格或点号之前的部分
+	Message string         // Log message // 日志消息
+	UID     string         // Value of the uid field, empty when absent // uid 字段的值，不存在时为空
+	Caller  string         // Source location, empty when not recorded // 源码位置，未记录时为空
+	Fields  map[string]any // Structured fields // 结构化字段
+}
+
+// Filter selects entries, zero fields other than MinLevel match everything
+// Filter 条目筛选条件，除 MinLevel 外零值字段不限制
+type Filter struct {
+	MinLevel zapcore.Level // Lowest level included, zapcore.DebugLevel for all // 包含的最低级别，zapcore.DebugLevel 表示全部
+	Since    time.Time     // Inclusive start // 开始时间（含）
+	Until    time.Time     // Exclusive end // 结束时间（不含）
+	UID      string        // Exact uid // 精确匹配的 uid
+	Module   string        // Module, case-insensitive // 模块，不区分大小写
+	Keyword  string        // Substring of the message, case-insensitive // 消息中包含的文本，不区分大小写
+	AfterSeq uint64        // Only entries after this sequence number // 仅包含该序号之后的条目
+}
+
+// Match reports whether e passes the filter
+// Match 判断 e 是否满足筛选条件
+func (f Filter) Match(e Entry) bool {
+	switch {
+	case e.Level < f.MinLevel:
+		return false
+	case e.Seq <= f.AfterSeq:
+		return false
+	case !f.Since.IsZero() && e.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !e.Time.Before(f.Until):
+		return false
+	case f.UID != "" && e.UID != f.UID:
+		return false
+	case f.Module != "" && !strings.EqualFold(e.Module, f.Module):
+		return false
+	case f.Keyword != "" && !strings.Contains(strings.ToLower(e.Message), strings.ToLower(f.Keyword)):
+		return false
+	}
+	return true
+}
+
+// Buffer ring of the most recent entries, safe for concurrent use
+// Buffer 保存最近条目的环形缓冲区，可并发使用
+type Buffer struct {
+	mu        sync.RWMutex
+	entries   []Entry
+	next      int
+	full      bool
+	seq       uint64
+	followers map[*follower]struct{}
+}
+
+type follower struct {
+	filter Filter
+	ch     chan Entry
+}
+
+// New creates a Buffer keeping size entries, a nil Buffer when size is not positive
+// New 创建保存 size 条记录的 Buffer，size 不为正数时返回 nil
+func New(size int) *Buffer {
+	if size <= 0 {
+		return nil
+	}
+	return &Buffer{entries: make([]Entry, size), followers: make(map[*follower]struct{})}
+}
+
+// Core returns a core recording into the buffer every entry enab lets through, to be teed with the
+// logger's own core
+// Core 返回将 enab 放行的条目记录到缓冲区的 core，与日志器自身的 core 组合使用
+func (b *Buffer) Core(enab zapcore.LevelEnabler) zapcore.Core {
+	return &core{LevelEnabler: enab, buf: b}
+}
+
+// add stores e and hands it to the matching followers
+// add 保存 e 并分发给匹配的跟踪者
+func (b *Buffer) add(e Entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seq++
+	e.Seq = b.seq
+	b.entries[b.next] = e
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+	for f := range b.followers {
+		if !f.filter.Match(e) {
+			continue
+		}
+		select {
+		case f.ch <- e:
+		default:
+		}
+	}
+}
+
+// Query returns up to limit of the newest matching entries, oldest first; limit <= 0 returns all of them
+// Query 返回最多 limit 条最新的匹配条目，按时间升序；limit <= 0 时返回全部
+func (b *Buffer) Query(filter Filter, limit int) []Entry {
+	if b == nil {
+		return nil
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.queryLocked(filter, limit)
+}
+
+func (b *Buffer) queryLocked(filter Filter, limit int) []Entry {
+	size := b.next
+	if b.full {
+		size = len(b.entries)
+	}
+	var matched []Entry
+	for i := 1; i <= size; i++ {
+		e := b.entries[(b.next-i+len(b.entries))%len(b.entries)]
+		if !filter.Match(e) {
+			continue
+		}
+		matched = append(matched, e)
+		if limit > 0 && len(matched) == limit {
+			break
+		}
+	}
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+	return matched
+}
+
+// Follow returns up to limit of the newest matching entries and a channel receiving the ones recorded
+// afterwards, without gaps or repeats in between; entries are dropped while the reader lags behind.
+// stop must be called to release the channel.
+// Follow 返回最多 limit 条最新的匹配条目，以及接收此后记录的条目的通道，两者之间既无遗漏也无重复；
+// 读取方积压时丢弃条目。必须调用 stop 释放通道。
+func (b *Buffer) Follow(filter Filter, limit int) (backlog []Entry, live <-chan Entry, stop func()) {
+	if b == nil {
+		return nil, nil, func() {}
+	}
+	f := &follower{filter: filter, ch: make(chan Entry, followBuffer)}
+	b.mu.Lock()
+	backlog = b.queryLocked(filter, limit)
+	b.followers[f] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return backlog, f.ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.followers, f)
+			b.mu.Unlock()
+		})
+	}
+}
+
+// core zapcore.Core recording into a Buffer
+// core 记录到 Buffer 的 zapcore.Core
+type core struct {
+	zapcore.LevelEnabler
+	buf    *Buffer
+	fields []zapcore.Field
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{
+		LevelEnabler: c.LevelEnabler,
+		buf:          c.buf,
+		fields:       append(append([]zapcore.Field(nil), c.fields...), fields...),
+	}
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	e := Entry{
+		Time:    ent.Time,
+		Level:   ent.Level,
+		Module:  ent.LoggerName,
+		Message: ent.Message,
+		Fields:  enc.Fields,
+	}
+	if e.Module == "" {
+		e.Module = messageModule(ent.Message)
+	}
+	if uid, ok := enc.Fields["uid"]; ok {
+		e.UID = fmt.Sprint(uid)
+	}
+	if ent.Caller.Defined {
+		e.Caller = ent.Caller.TrimmedPath()
+	}
+	c.buf.add(e)
+	return nil
+}
+
+func (c *core) Sync() error {
+	return nil
+}
+
+// messageModule returns the message up to the first space or dot, e.g. WebsocketServer for
+// "WebsocketServer OnMessage" and apiRouter for "apiRouter.WebGUI.UpdateConfig err"
+// messageModule 返回消息中第一个空格或点号之前的部分
+func messageModule(message string) string {
+	if i := strings.IndexAny(message, " ."); i >= 0 {
+		return message[:i]
+	}
+	return message
+}
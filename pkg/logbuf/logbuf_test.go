@@ -0,0 +1,65 @@
+package logbuf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestBuffer_QueryWrapsAndFilters(t *testing.T) {
+	buf := New(3)
+	logger := zap.New(buf.Core(zapcore.DebugLevel))
+
+	logger.Debug("dropped by the ring")
+	logger.Info("WebsocketServer OnMessage", zap.String("uid", "7"))
+	logger.With(zap.Int64("uid", 8)).Warn("apiRouter.WebGUI.UpdateConfig err")
+	logger.Named("task").Error("cleanup failed", zap.Error(assert.AnError))
+
+	all := buf.Query(Filter{MinLevel: zapcore.DebugLevel}, 0)
+	require.Len(t, all, 3)
+	assert.Equal(t, []string{"WebsocketServer", "apiRouter", "task"}, []string{all[0].Module, all[1].Module, all[2].Module})
+	assert.Equal(t, uint64(2), all[0].Seq)
+	assert.Equal(t, "8", all[1].UID)
+	assert.Equal(t, assert.AnError.Error(), all[2].Fields["error"])
+
+	assert.Len(t, buf.Query(Filter{MinLevel: zapcore.WarnLevel}, 0), 2)
+	assert.Len(t, buf.Query(Filter{UID: "7"}, 0), 1)
+	assert.Len(t, buf.Query(Filter{Module: "websocketserver", Keyword: "onmessage"}, 0), 1)
+	assert.Len(t, buf.Query(Filter{AfterSeq: 3}, 0), 1)
+	assert.Empty(t, buf.Query(Filter{Until: all[0].Time}, 0))
+
+	newest := buf.Query(Filter{}, 1)
+	require.Len(t, newest, 1)
+	assert.Equal(t, "cleanup failed", newest[0].Message)
+
+	var disabled *Buffer
+	assert.Nil(t, New(0))
+	assert.Empty(t, disabled.Query(Filter{}, 0))
+}
+
+func TestBuffer_Follow(t *testing.T) {
+	buf := New(10)
+	logger := zap.New(buf.Core(zapcore.InfoLevel))
+	logger.Info("before", zap.String("uid", "1"))
+
+	backlog, live, stop := buf.Follow(Filter{UID: "1"}, 10)
+	require.Len(t, backlog, 1)
+
+	logger.Info("other user", zap.String("uid", "2"))
+	logger.Info("after", zap.String("uid", "1"))
+	select {
+	case e := <-live:
+		assert.Equal(t, "after", e.Message)
+	case <-time.After(time.Second):
+		t.Fatal("followed entry not delivered")
+	}
+
+	stop()
+	stop()
+	logger.Info("after stop", zap.String("uid", "1"))
+	assert.Empty(t, live)
+}
@@ -50,16 +50,18 @@ var (
 	nop = zap.NewNop()
 )
 
+// NewLogger creates a logger sharing the level of the global logger, so SetLevel changes it at runtime.
 func NewLogger(lc Config) (*zap.Logger, error) {
 
 	if !fileurl.IsExist(lc.File) {
 		fileurl.CreatePath(lc.File, os.ModePerm)
 	}
 
-	lvl, err := zapcore.ParseLevel(lc.Level)
+	level, err := zapcore.ParseLevel(lc.Level)
 	if err != nil {
 		return nil, fmt.Errorf("invalid log level: %w", err)
 	}
+	lvl.SetLevel(level)
 
 	var fileOut zapcore.WriteSyncer
 	if lf := lc.File; len(lf) > 0 {
@@ -41,4 +41,9 @@ func TestNewLogger(t *testing.T) {
 	stat, err := os.Stat(logFile)
 	assert.NoError(t, err)
 	assert.True(t, stat.Size() > 0)
+
+	// The level follows SetLevel without recreating the logger
+	assert.False(t, log.Core().Enabled(zapcore.DebugLevel))
+	SetLevel(zapcore.DebugLevel)
+	assert.True(t, log.Core().Enabled(zapcore.DebugLevel))
 }
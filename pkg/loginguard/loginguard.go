@@ -0,0 +1,222 @@
+// Package loginguard counts failed logins per client IP and per account and locks them out for an
+// exponentially growing time once a threshold is reached
+// Package loginguard 按客户端 IP 与账号统计登录失败次数，达到阈值后锁定，锁定时长按指数增长
+package loginguard
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scope of a counter
+// 计数器的范围
+const (
+	ScopeIP      = "ip"
+	ScopeAccount = "account"
+)
+
+// Config thresholds of the guard
+// Config 防护阈值
+type Config struct {
+	IPThreshold      int           // Failures of one IP before it is locked, 0 disables // 单个 IP 被锁定前允许的失败次数，0 表示不限制
+	AccountThreshold int           // Failures of one account before it is locked, 0 disables // 单个账号被锁定前允许的失败次数，0 表示不限制
+	BaseLockout      time.Duration // First lockout, doubled on every further one // 首次锁定时长，此后每次翻倍
+	MaxLockout       time.Duration // Upper bound of a lockout // 锁定时长上限
+	ResetAfter       time.Duration // Quiet time after which the counters start over // 无失败多久后计数器清零
+	CaptchaAfter     int           // Failures before the captcha is required, 0 requires it on every login // 需要验证码前允许的失败次数，0 表示每次登录都需要
+}
+
+// Lockout a lockout started by a failed login
+// Lockout 一次登录失败触发的锁定
+type Lockout struct {
+	Scope    string        // ScopeIP or ScopeAccount // ScopeIP 或 ScopeAccount
+	Key      string        // IP or account // IP 或账号
+	Failures int           // Failures that led to it // 触发锁定的失败次数
+	Duration time.Duration // Lockout duration // 锁定时长
+}
+
+// entry failure counter of one IP or account
+// entry 单个 IP 或账号的失败计数
+type entry struct {
+	failures    int       // Failures since the last lockout // 上次锁定以来的失败次数
+	total       int       // Failures since the counter started // 计数开始以来的失败总数
+	lockouts    int       // Lockouts so far, the exponent of the next one // 已锁定次数，即下次锁定的指数
+	lastFailure time.Time // Time of the last failure // 最后一次失败时间
+	lockedUntil time.Time // End of the current lockout // 当前锁定的结束时间
+}
+
+// Guard failed login counters, safe for concurrent use; the counters live in memory and are per instance
+// Guard 登录失败计数器，可并发使用；计数保存在内存中，按实例独立统计
+type Guard struct {
+	cfg     Config
+	captcha Captcha
+	now     func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*entry
+	lastPrune time.Time
+}
+
+// New creates a guard, captcha may be nil when no captcha provider is configured
+// New 创建防护器，未配置验证码服务时 captcha 可为 nil
+func New(cfg Config, captcha Captcha) *Guard {
+	if cfg.BaseLockout <= 0 {
+		cfg.BaseLockout = time.Minute
+	}
+	if cfg.MaxLockout < cfg.BaseLockout {
+		cfg.MaxLockout = cfg.BaseLockout
+	}
+	if cfg.ResetAfter <= 0 {
+		cfg.ResetAfter = 24 * time.Hour
+	}
+	return &Guard{
+		cfg:     cfg,
+		captcha: captcha,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+func counterKey(scope, key string) string {
+	if scope == ScopeAccount {
+		key = strings.ToLower(strings.TrimSpace(key))
+	}
+	return scope + ":" + key
+}
+
+// get returns the live entry of a counter, dropping it once it has been quiet long enough; the caller holds the lock
+// get 返回计数器的有效条目，静默足够久后将其丢弃；调用方需持有锁
+func (g *Guard) get(scope, key string, now time.Time) *entry {
+	k := counterKey(scope, key)
+	e := g.entries[k]
+	if e != nil && now.After(e.lockedUntil) && now.Sub(e.lastFailure) > g.cfg.ResetAfter {
+		delete(g.entries, k)
+		return nil
+	}
+	return e
+}
+
+// Locked returns how long the IP or the account is still locked out, 0 when neither is
+// Locked 返回 IP 或账号剩余的锁定时长，均未锁定时返回 0
+func (g *Guard) Locked(ip, account string) time.Duration {
+	now := g.now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var left time.Duration
+	for _, c := range [][2]string{{ScopeIP, ip}, {ScopeAccount, account}} {
+		if e := g.get(c[0], c[1], now); e != nil && e.lockedUntil.After(now) {
+			left = max(left, e.lockedUntil.Sub(now))
+		}
+	}
+	return left
+}
+
+// Fail records a failed login and returns the lockouts it started
+// Fail 记录一次登录失败，并返回由此触发的锁定
+func (g *Guard) Fail(ip, account string) []Lockout {
+	now := g.now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.prune(now)
+
+	var started []Lockout
+	for _, c := range []struct {
+		scope, key string
+		threshold  int
+	}{
+		{ScopeIP, ip, g.cfg.IPThreshold},
+		{ScopeAccount, account, g.cfg.AccountThreshold},
+	} {
+		if c.key == "" {
+			continue
+		}
+		e := g.get(c.scope, c.key, now)
+		if e == nil {
+			e = &entry{}
+			g.entries[counterKey(c.scope, c.key)] = e
+		}
+		e.failures++
+		e.total++
+		e.lastFailure = now
+		if c.threshold <= 0 || e.failures < c.threshold {
+			continue
+		}
+
+		d := g.cfg.BaseLockout << min(e.lockouts, 30)
+		if d <= 0 || d > g.cfg.MaxLockout {
+			d = g.cfg.MaxLockout
+		}
+		e.lockouts++
+		e.failures = 0
+		e.lockedUntil = now.Add(d)
+		started = append(started, Lockout{Scope: c.scope, Key: c.key, Failures: e.total, Duration: d})
+	}
+	return started
+}
+
+// Succeed clears the counter of the account after a successful login; the IP keeps its count
+// so one valid account cannot be used to reset guessing from the same address
+// Succeed 登录成功后清除账号的计数；IP 计数保留，避免利用一个有效账号重置同一地址的猜测次数
+func (g *Guard) Succeed(account string) {
+	g.mu.Lock()
+	delete(g.entries, counterKey(ScopeAccount, account))
+	g.mu.Unlock()
+}
+
+// NeedsCaptcha reports whether the next login of the IP or the account must pass the captcha
+// NeedsCaptcha 判断该 IP 或账号的下一次登录是否需要通过验证码
+func (g *Guard) NeedsCaptcha(ip, account string) bool {
+	if g.captcha == nil {
+		return false
+	}
+	if g.cfg.CaptchaAfter <= 0 {
+		return true
+	}
+	now := g.now()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, c := range [][2]string{{ScopeIP, ip}, {ScopeAccount, account}} {
+		if e := g.get(c[0], c[1], now); e != nil && e.total >= g.cfg.CaptchaAfter {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyCaptcha checks the captcha response of the client
+// VerifyCaptcha 校验客户端提交的验证码响应
+func (g *Guard) VerifyCaptcha(ctx context.Context, response, ip string) (bool, error) {
+	if g.captcha == nil {
+		return true, nil
+	}
+	if response == "" {
+		return false, nil
+	}
+	return g.captcha.Verify(ctx, response, ip)
+}
+
+// CaptchaSiteKey public site key of the captcha widget, empty when no captcha is configured
+// CaptchaSiteKey 验证码组件的公开站点密钥，未配置验证码时为空
+func (g *Guard) CaptchaSiteKey() (provider, siteKey string) {
+	if g.captcha == nil {
+		return "", ""
+	}
+	return g.captcha.Provider(), g.captcha.SiteKey()
+}
+
+// prune drops the quiet counters at most once a minute so guessing from many addresses cannot grow the map
+// forever; the caller holds the lock
+// prune 每分钟至多一次清理静默的计数器，避免大量地址的猜测使表无限增长；调用方需持有锁
+func (g *Guard) prune(now time.Time) {
+	if now.Sub(g.lastPrune) < time.Minute {
+		return
+	}
+	g.lastPrune = now
+	for k, e := range g.entries {
+		if now.After(e.lockedUntil) && now.Sub(e.lastFailure) > g.cfg.ResetAfter {
+			delete(g.entries, k)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package loginguard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestGuard creates a guard with a controllable clock
+func newTestGuard(cfg Config, captcha Captcha) (*Guard, *time.Time) {
+	g := New(cfg, captcha)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+	return g, &now
+}
+
+// TestGuard_Lockout verifies an account is locked after the threshold, each further lockout doubles
+// up to the maximum and the counters start over after the quiet time.
+// TestGuard_Lockout 验证账号达到阈值后被锁定，后续每次锁定时长翻倍直到上限，静默一段时间后计数重置。
+func TestGuard_Lockout(t *testing.T) {
+	g, now := newTestGuard(Config{AccountThreshold: 3, BaseLockout: time.Minute, MaxLockout: 3 * time.Minute, ResetAfter: time.Hour}, nil)
+
+	assert.Empty(t, g.Fail("1.1.1.1", "Alice"))
+	assert.Empty(t, g.Fail("1.1.1.2", "alice"))
+	locks := g.Fail("1.1.1.3", "alice ")
+	require.Len(t, locks, 1)
+	assert.Equal(t, Lockout{Scope: ScopeAccount, Key: "alice ", Failures: 3, Duration: time.Minute}, locks[0])
+	assert.Equal(t, time.Minute, g.Locked("9.9.9.9", "ALICE"))
+	assert.Zero(t, g.Locked("9.9.9.9", "bob"))
+
+	durations := []time.Duration{2 * time.Minute, 3 * time.Minute, 3 * time.Minute}
+	for _, want := range durations {
+		*now = now.Add(5 * time.Minute)
+		assert.Zero(t, g.Locked("", "alice"))
+		g.Fail("", "alice")
+		g.Fail("", "alice")
+		locks = g.Fail("", "alice")
+		require.Len(t, locks, 1)
+		assert.Equal(t, want, locks[0].Duration)
+	}
+
+	*now = now.Add(2 * time.Hour)
+	g.Fail("", "alice")
+	g.Fail("", "alice")
+	locks = g.Fail("", "alice")
+	require.Len(t, locks, 1)
+	assert.Equal(t, time.Minute, locks[0].Duration)
+
+	// A successful login clears the account
+	// 登录成功后清除账号计数
+	*now = now.Add(5 * time.Minute)
+	g.Fail("", "alice")
+	g.Succeed("alice")
+	g.Fail("", "alice")
+	assert.Empty(t, g.Fail("", "alice"))
+}
+
+// TestGuard_IP verifies the IP counter spans accounts and survives a successful login.
+// TestGuard_IP 验证 IP 计数跨账号累计，且登录成功后仍然保留。
+func TestGuard_IP(t *testing.T) {
+	g, _ := newTestGuard(Config{IPThreshold: 3, BaseLockout: time.Minute}, nil)
+
+	g.Fail("1.1.1.1", "a")
+	g.Fail("1.1.1.1", "b")
+	g.Succeed("c")
+	locks := g.Fail("1.1.1.1", "c")
+	require.Len(t, locks, 1)
+	assert.Equal(t, ScopeIP, locks[0].Scope)
+	assert.Equal(t, time.Minute, g.Locked("1.1.1.1", "d"))
+	assert.Zero(t, g.Locked("1.1.1.2", "a"))
+}
+
+// stubCaptcha accepts the response "ok"
+type stubCaptcha struct{}
+
+func (stubCaptcha) Verify(ctx context.Context, response, ip string) (bool, error) {
+	return response == "ok", nil
+}
+func (stubCaptcha) Provider() string { return CaptchaTurnstile }
+func (stubCaptcha) SiteKey() string  { return "site" }
+
+// TestGuard_Captcha verifies the captcha is required after the configured failures, only when a provider is set.
+// TestGuard_Captcha 验证达到配置的失败次数后需要验证码，且仅在配置了验证码服务时生效。
+func TestGuard_Captcha(t *testing.T) {
+	g, _ := newTestGuard(Config{CaptchaAfter: 2}, stubCaptcha{})
+	assert.False(t, g.NeedsCaptcha("1.1.1.1", "alice"))
+	g.Fail("1.1.1.1", "alice")
+	g.Fail("1.1.1.2", "alice")
+	assert.True(t, g.NeedsCaptcha("1.1.1.3", "alice"))
+	assert.False(t, g.NeedsCaptcha("1.1.1.3", "bob"))
+
+	ok, err := g.VerifyCaptcha(context.Background(), "ok", "1.1.1.3")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = g.VerifyCaptcha(context.Background(), "", "1.1.1.3")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	always, _ := newTestGuard(Config{}, stubCaptcha{})
+	assert.True(t, always.NeedsCaptcha("1.1.1.1", "alice"))
+
+	none, _ := newTestGuard(Config{}, nil)
+	assert.False(t, none.NeedsCaptcha("1.1.1.1", "alice"))
+}
+
+// TestSiteVerify verifies the secret, response and client IP are posted and the success flag is read.
+// TestSiteVerify 验证提交了密钥、响应与客户端 IP，并读取 success 字段。
+func TestSiteVerify(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		assert.Equal(t, "1.1.1.1", r.PostForm.Get("remoteip"))
+		if r.PostForm.Get("response") == "good" {
+			w.Write([]byte(`{"success":true}`))
+			return
+		}
+		w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewCaptcha(CaptchaHCaptcha, "site", "secret")
+	require.NoError(t, err)
+	c.(*SiteVerify).verifyURL = srv.URL
+
+	ok, err := c.Verify(context.Background(), "good", "1.1.1.1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = c.Verify(context.Background(), "bad", "1.1.1.1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = NewCaptcha("unknown", "site", "secret")
+	assert.Error(t, err)
+	none, err := NewCaptcha("", "", "")
+	require.NoError(t, err)
+	assert.Nil(t, none)
+}
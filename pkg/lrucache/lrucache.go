@@ -0,0 +1,124 @@
+// Package lrucache provides a small thread-safe LRU cache with optional expiry.
+// Package lrucache 提供一个小型的并发安全 LRU 缓存，支持可选的过期时间。
+package lrucache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache keeps at most capacity entries, evicting the least recently used one when full.
+// Entries older than ttl are treated as missing, which bounds how long a missed
+// invalidation can serve stale data; ttl <= 0 keeps entries until evicted or removed.
+// Cache 最多保存 capacity 个条目，满时淘汰最久未使用的条目。
+// 超过 ttl 的条目视为不存在，以限制遗漏失效时读到旧数据的时长；ttl <= 0 表示条目一直保留到被淘汰或移除。
+type Cache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	ll       *list.List
+	items    map[K]*list.Element
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	addedAt time.Time
+}
+
+// New creates a cache holding up to capacity entries (minimum 1).
+// New 创建最多保存 capacity 个条目的缓存（最少为 1）。
+func New[K comparable, V any](capacity int, ttl time.Duration) *Cache[K, V] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Cache[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		ll:       list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+// Get returns the value for key and marks it as recently used.
+// Get 返回 key 对应的值，并将其标记为最近使用。
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	e := el.Value.(*entry[K, V])
+	if c.ttl > 0 && time.Since(e.addedAt) > c.ttl {
+		c.removeElement(el)
+		return zero, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// Add stores value for key, evicting the least recently used entry when full.
+// Add 保存 key 对应的值，满时淘汰最久未使用的条目。
+func (c *Cache[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value = value
+		e.addedAt = time.Now()
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.ll.PushFront(&entry[K, V]{key: key, value: value, addedAt: time.Now()})
+	if c.ll.Len() > c.capacity {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// Remove drops key from the cache.
+// Remove 从缓存中移除 key。
+func (c *Cache[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// RemoveFunc drops every entry for which match returns true and reports how many were removed.
+// RemoveFunc 移除 match 返回 true 的所有条目，并返回移除数量。
+func (c *Cache[K, V]) RemoveFunc(match func(key K, value V) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for el := c.ll.Front(); el != nil; {
+		next := el.Next()
+		e := el.Value.(*entry[K, V])
+		if match(e.key, e.value) {
+			c.removeElement(el)
+			removed++
+		}
+		el = next
+	}
+	return removed
+}
+
+// Len returns the number of cached entries, including expired ones not yet dropped.
+// Len 返回缓存的条目数量，包含尚未清理的过期条目。
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *Cache[K, V]) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry[K, V]).key)
+}
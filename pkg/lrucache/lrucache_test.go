@@ -0,0 +1,60 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCache_EvictsLeastRecentlyUsed verifies a full cache drops the entry that was used longest ago.
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[string, int](2, 0)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a should be cached")
+	}
+	c.Add("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("b was least recently used and should have been evicted")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("a = %v, %v; want 1, true", v, ok)
+	}
+	if v, ok := c.Get("c"); !ok || v != 3 {
+		t.Fatalf("c = %v, %v; want 3, true", v, ok)
+	}
+}
+
+// TestCache_Expiry verifies entries older than the ttl are treated as missing.
+func TestCache_Expiry(t *testing.T) {
+	c := New[string, int](4, 10*time.Millisecond)
+	c.Add("a", 1)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("a should have expired")
+	}
+	if c.Len() != 0 {
+		t.Fatalf("Len = %d; the expired entry should be dropped on read", c.Len())
+	}
+}
+
+// TestCache_RemoveFunc verifies matching entries are dropped and the rest kept.
+func TestCache_RemoveFunc(t *testing.T) {
+	c := New[int, string](8, 0)
+	for i := 0; i < 6; i++ {
+		c.Add(i, "v")
+	}
+	c.Remove(5)
+
+	if n := c.RemoveFunc(func(k int, _ string) bool { return k%2 == 0 }); n != 3 {
+		t.Fatalf("RemoveFunc removed %d entries; want 3", n)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("Len = %d; want 2", c.Len())
+	}
+	if _, ok := c.Get(1); !ok {
+		t.Fatal("odd keys should remain")
+	}
+}
@@ -0,0 +1,287 @@
+// Package maintenance confines heavy jobs (full backups, cleanup, upgrades) to a recurring maintenance window
+// Package maintenance 将重型任务（全量备份、清理、升级）限制在周期性的维护窗口内执行
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxDuration longest window supported, a window never overlaps the next day's window
+// maxDuration 支持的最长窗口，窗口不会与次日窗口重叠
+const maxDuration = 24 * time.Hour
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Window a daily or weekly maintenance window
+// Window 每日或每周的维护窗口
+type Window struct {
+	startMinute int            // Minutes after midnight the window opens // 窗口开启时刻（距零点的分钟数）
+	duration    time.Duration  // Window length // 窗口时长
+	days        []time.Weekday // Days the window opens on, empty means every day // 窗口开启的星期，为空表示每天
+	loc         *time.Location // Time zone the window is defined in // 窗口所在时区
+}
+
+// ParseWindow parses a window opening at start (HH:MM) for duration on the given days (sun..sat, empty for
+// every day) in the given IANA time zone (empty for the server's local time)
+// ParseWindow 解析维护窗口：在指定星期（sun..sat，为空表示每天）的 start（HH:MM）开启并持续 duration，
+// timezone 为 IANA 时区（为空时使用服务器本地时间）
+func ParseWindow(start string, duration time.Duration, days []string, timezone string) (*Window, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(start))
+	if err != nil {
+		return nil, fmt.Errorf("maintenance: invalid start %q, expected HH:MM", start)
+	}
+	if duration <= 0 || duration > maxDuration {
+		return nil, fmt.Errorf("maintenance: duration %s must be between 1m and 24h", duration)
+	}
+
+	w := &Window{startMinute: t.Hour()*60 + t.Minute(), duration: duration, loc: time.Local}
+	if timezone != "" {
+		if w.loc, err = time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("maintenance: invalid timezone %q: %w", timezone, err)
+		}
+	}
+	for _, d := range days {
+		// Accept both "sat" and "saturday"
+		// 同时接受 "sat" 与 "saturday"
+		name := strings.ToLower(strings.TrimSpace(d))
+		if len(name) > 3 {
+			name = name[:3]
+		}
+		wd, ok := weekdays[name]
+		if !ok {
+			return nil, fmt.Errorf("maintenance: invalid day %q", d)
+		}
+		w.days = append(w.days, wd)
+	}
+	return w, nil
+}
+
+// opensOn reports whether the window opens on the given weekday
+// opensOn 判断窗口是否在指定星期开启
+func (w *Window) opensOn(d time.Weekday) bool {
+	if len(w.days) == 0 {
+		return true
+	}
+	for _, wd := range w.days {
+		if wd == d {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the window that is open at now, or else the next one to open
+// Next 返回 now 时刻正在开启的窗口，否则返回下一个窗口
+func (w *Window) Next(now time.Time) (start, end time.Time) {
+	local := now.In(w.loc)
+	// Start one day back so a window that opened yesterday and crosses midnight is found
+	// 从前一天开始查找，以便找到昨天开启且跨越零点的窗口
+	for offset := -1; offset <= 7; offset++ {
+		day := time.Date(local.Year(), local.Month(), local.Day()+offset, 0, 0, 0, 0, w.loc)
+		if !w.opensOn(day.Weekday()) {
+			continue
+		}
+		start = day.Add(time.Duration(w.startMinute) * time.Minute)
+		end = start.Add(w.duration)
+		if end.After(now) {
+			return start, end
+		}
+	}
+	return time.Time{}, time.Time{}
+}
+
+// Contains reports whether the window is open at now
+// Contains 判断 now 时刻窗口是否开启
+func (w *Window) Contains(now time.Time) bool {
+	start, end := w.Next(now)
+	return !start.After(now) && end.After(now)
+}
+
+// Job a heavy job waiting for the maintenance window
+// Job 等待维护窗口的重型任务
+type Job struct {
+	ID          string    `json:"id"`          // Kind:Key, a job queued twice is kept once // Kind:Key，重复排队的任务只保留一个
+	Kind        string    `json:"kind"`        // Job kind, e.g. backup, task, upgrade // 任务类型，例如 backup、task、upgrade
+	Key         string    `json:"key"`         // Identifies the job within its kind // 任务在同类型中的标识
+	Description string    `json:"description"` // Human readable description // 任务描述
+	UID         int64     `json:"uid"`         // Owner, 0 for server jobs // 所属用户，服务器级任务为 0
+	QueuedAt    time.Time `json:"queuedAt"`    // First time the job was deferred // 首次被推迟的时间
+
+	run func(ctx context.Context) error
+}
+
+// Coordinator decides when heavy jobs may run and holds the ones deferred to the next window.
+// A nil window means no maintenance window is configured and every job runs right away.
+// Coordinator 决定重型任务何时可以执行，并保存被推迟到下一个窗口的任务。
+// window 为 nil 表示未配置维护窗口，所有任务立即执行。
+type Coordinator struct {
+	window *Window
+	logger *zap.Logger
+	now    func() time.Time
+
+	mu    sync.Mutex
+	queue map[string]*Job
+	runMu sync.Mutex
+}
+
+// New creates a Coordinator, window may be nil
+// New 创建 Coordinator，window 可为 nil
+func New(window *Window, logger *zap.Logger) *Coordinator {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Coordinator{window: window, logger: logger, now: time.Now, queue: make(map[string]*Job)}
+}
+
+// Enabled reports whether a maintenance window is configured
+// Enabled 判断是否配置了维护窗口
+func (c *Coordinator) Enabled() bool {
+	return c != nil && c.window != nil
+}
+
+// InWindow reports whether heavy jobs may run now without being forced
+// InWindow 判断当前是否可以不经强制直接执行重型任务
+func (c *Coordinator) InWindow() bool {
+	return !c.Enabled() || c.window.Contains(c.now())
+}
+
+// Allow reports whether a heavy job may run now: no window is configured, the window is open or the job is forced
+// Allow 判断重型任务当前是否可以执行：未配置窗口、窗口已开启或任务被强制执行
+func (c *Coordinator) Allow(force bool) bool {
+	return force || c.InWindow()
+}
+
+// NextWindow returns the open window or the next one, zero times when no window is configured
+// NextWindow 返回当前开启的窗口或下一个窗口，未配置窗口时返回零值
+func (c *Coordinator) NextWindow() (start, end time.Time) {
+	if !c.Enabled() {
+		return time.Time{}, time.Time{}
+	}
+	return c.window.Next(c.now())
+}
+
+// Defer queues run for the next window. Queuing a job with the same kind and key again replaces its
+// run function but keeps the original queue time.
+// Defer 将 run 排入下一个窗口执行。再次排入相同类型与标识的任务会替换执行函数，但保留最初的排队时间。
+func (c *Coordinator) Defer(kind, key, description string, uid int64, run func(ctx context.Context) error) Job {
+	id := kind + ":" + key
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	job, ok := c.queue[id]
+	if !ok {
+		job = &Job{ID: id, Kind: kind, Key: key, QueuedAt: c.now()}
+		c.queue[id] = job
+		c.logger.Info("maintenance job deferred to the next window", zap.String("id", id), zap.String("description", description))
+	}
+	job.Description = description
+	job.UID = uid
+	job.run = run
+	return *job
+}
+
+// Done drops a queued job, called when the job ran through its own schedule
+// Done 移除排队中的任务，在任务已通过自身调度执行时调用
+func (c *Coordinator) Done(kind, key string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.queue, kind+":"+key)
+	c.mu.Unlock()
+}
+
+// Queued returns the jobs waiting for the next window, oldest first
+// Queued 返回等待下一个窗口的任务，按排队时间升序
+func (c *Coordinator) Queued() []Job {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	jobs := make([]Job, 0, len(c.queue))
+	for _, job := range c.queue {
+		jobs = append(jobs, *job)
+	}
+	c.mu.Unlock()
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].QueuedAt.Equal(jobs[j].QueuedAt) {
+			return jobs[i].ID < jobs[j].ID
+		}
+		return jobs[i].QueuedAt.Before(jobs[j].QueuedAt)
+	})
+	return jobs
+}
+
+// RunDue runs every queued job when the window is open, returns the number of jobs run
+// RunDue 在窗口开启时执行所有排队任务，返回执行的任务数
+func (c *Coordinator) RunDue(ctx context.Context) int {
+	if !c.InWindow() {
+		return 0
+	}
+	return c.run(ctx, c.Queued())
+}
+
+// RunNow runs queued jobs regardless of the window; ids selects jobs, empty runs all of them.
+// Returns the number of jobs run.
+// RunNow 忽略窗口立即执行排队任务；ids 指定任务，为空时执行全部。返回执行的任务数。
+func (c *Coordinator) RunNow(ctx context.Context, ids []string) int {
+	jobs := c.Queued()
+	if len(ids) > 0 {
+		wanted := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			wanted[id] = true
+		}
+		selected := jobs[:0]
+		for _, job := range jobs {
+			if wanted[job.ID] {
+				selected = append(selected, job)
+			}
+		}
+		jobs = selected
+	}
+	return c.run(ctx, jobs)
+}
+
+// run executes jobs one after another, heavy jobs never run concurrently with each other
+// run 依次执行任务，重型任务之间不会并发
+func (c *Coordinator) run(ctx context.Context, jobs []Job) int {
+	if c == nil {
+		return 0
+	}
+	c.runMu.Lock()
+	defer c.runMu.Unlock()
+
+	count := 0
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+		// Take the job off the queue first, a job that ran through its own schedule meanwhile is skipped
+		// 先将任务移出队列，期间已通过自身调度执行的任务会被跳过
+		c.mu.Lock()
+		queued, ok := c.queue[job.ID]
+		if ok {
+			delete(c.queue, job.ID)
+		}
+		c.mu.Unlock()
+		if !ok || queued.run == nil {
+			continue
+		}
+
+		count++
+		c.logger.Info("maintenance job running", zap.String("id", job.ID), zap.String("description", queued.Description))
+		if err := queued.run(ctx); err != nil {
+			c.logger.Error("maintenance job failed", zap.String("id", job.ID), zap.Error(err))
+		}
+	}
+	return count
+}
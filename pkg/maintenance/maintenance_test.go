@@ -0,0 +1,107 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindow_CrossesMidnight(t *testing.T) {
+	w, err := ParseWindow("23:00", 3*time.Hour, nil, "UTC")
+	require.NoError(t, err)
+
+	at := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return v
+	}
+	assert.True(t, w.Contains(at("2026-03-10T23:30:00Z")))
+	assert.True(t, w.Contains(at("2026-03-11T01:59:00Z")))
+	assert.False(t, w.Contains(at("2026-03-11T02:00:00Z")))
+
+	start, end := w.Next(at("2026-03-11T12:00:00Z"))
+	assert.Equal(t, at("2026-03-11T23:00:00Z"), start)
+	assert.Equal(t, at("2026-03-12T02:00:00Z"), end)
+}
+
+func TestWindow_Days(t *testing.T) {
+	w, err := ParseWindow("02:00", time.Hour, []string{"Saturday", "sun"}, "UTC")
+	require.NoError(t, err)
+
+	// 2026-03-11 is a Wednesday
+	start, _ := w.Next(time.Date(2026, 3, 11, 2, 30, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 3, 14, 2, 0, 0, 0, time.UTC), start)
+	assert.False(t, w.Contains(time.Date(2026, 3, 11, 2, 30, 0, 0, time.UTC)))
+	assert.True(t, w.Contains(time.Date(2026, 3, 15, 2, 30, 0, 0, time.UTC)))
+}
+
+func TestParseWindow_Invalid(t *testing.T) {
+	_, err := ParseWindow("25:00", time.Hour, nil, "")
+	assert.Error(t, err)
+	_, err = ParseWindow("02:00", 0, nil, "")
+	assert.Error(t, err)
+	_, err = ParseWindow("02:00", time.Hour, []string{"someday"}, "")
+	assert.Error(t, err)
+	_, err = ParseWindow("02:00", time.Hour, nil, "Mars/Olympus")
+	assert.Error(t, err)
+}
+
+func TestCoordinator_DeferAndRunDue(t *testing.T) {
+	w, err := ParseWindow("02:00", time.Hour, nil, "UTC")
+	require.NoError(t, err)
+	c := New(w, nil)
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	assert.False(t, c.Allow(false))
+	assert.True(t, c.Allow(true))
+
+	runs := map[string]int{}
+	c.Defer("backup", "1", "first", 7, func(ctx context.Context) error { runs["backup"]++; return nil })
+	c.Defer("backup", "1", "replaced", 7, func(ctx context.Context) error { runs["backup"] += 10; return nil })
+	c.Defer("task", "DbCleanup", "cleanup", 0, func(ctx context.Context) error { runs["task"]++; return nil })
+	c.Defer("upgrade", "server", "upgrade", 1, func(ctx context.Context) error { runs["upgrade"]++; return nil })
+	c.Done("upgrade", "server")
+
+	queued := c.Queued()
+	require.Len(t, queued, 2)
+	assert.Equal(t, "replaced", queued[0].Description)
+
+	assert.Equal(t, 0, c.RunDue(context.Background()), "jobs wait while the window is closed")
+
+	now = time.Date(2026, 3, 12, 2, 10, 0, 0, time.UTC)
+	assert.Equal(t, 2, c.RunDue(context.Background()))
+	assert.Equal(t, map[string]int{"backup": 10, "task": 1}, runs)
+	assert.Empty(t, c.Queued())
+}
+
+func TestCoordinator_NoWindow(t *testing.T) {
+	c := New(nil, nil)
+	assert.False(t, c.Enabled())
+	assert.True(t, c.Allow(false))
+	start, _ := c.NextWindow()
+	assert.True(t, start.IsZero())
+
+	var nilCoordinator *Coordinator
+	assert.True(t, nilCoordinator.Allow(false))
+	assert.Zero(t, nilCoordinator.RunDue(context.Background()))
+}
+
+func TestCoordinator_RunNow(t *testing.T) {
+	w, err := ParseWindow("02:00", time.Hour, nil, "UTC")
+	require.NoError(t, err)
+	c := New(w, nil)
+	c.now = func() time.Time { return time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC) }
+
+	ran := ""
+	c.Defer("backup", "1", "", 1, func(ctx context.Context) error { ran += "1"; return nil })
+	c.Defer("backup", "2", "", 1, func(ctx context.Context) error { ran += "2"; return nil })
+
+	assert.Equal(t, 1, c.RunNow(context.Background(), []string{"backup:2"}))
+	assert.Equal(t, "2", ran)
+	require.Len(t, c.Queued(), 1)
+	assert.Equal(t, "backup:1", c.Queued()[0].ID)
+}
@@ -0,0 +1,277 @@
+// Package markdown renders note Markdown to sanitized HTML, including Obsidian
+// [[wiki links]] and ![[embeds]] resolved through a caller supplied Resolver.
+// Package markdown 将笔记 Markdown 渲染为经过清洗的 HTML，
+// 包括通过调用方提供的 Resolver 解析的 Obsidian [[维基链接]] 与 ![[嵌入]]。
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Resolver maps link targets found in a note to URLs
+// Resolver 将笔记中的链接目标映射为 URL
+type Resolver interface {
+	// ResolveLink returns the href of the note a [[wiki link]] points to, ok is false when it does not exist
+	// ResolveLink 返回 [[维基链接]] 指向的笔记地址，笔记不存在时 ok 为 false
+	ResolveLink(target string) (href string, ok bool)
+
+	// ResolveEmbed returns the URL of the attachment an ![[embed]] or a local image points to,
+	// ok is false when it does not exist
+	// ResolveEmbed 返回 ![[嵌入]] 或本地图片指向的附件地址，附件不存在时 ok 为 false
+	ResolveEmbed(target string) (src string, ok bool)
+}
+
+// KindWikiLink node kind of a [[wiki link]] or an ![[embed]]
+// KindWikiLink [[维基链接]] 或 ![[嵌入]] 的节点类型
+var KindWikiLink = ast.NewNodeKind("WikiLink")
+
+// WikiLink a [[target#fragment|label]] link, or an embed when prefixed with "!"
+// WikiLink [[target#fragment|label]] 链接，以 "!" 开头时为嵌入
+type WikiLink struct {
+	ast.BaseInline
+	Target   string // Linked note or attachment // 链接的笔记或附件
+	Fragment string // Heading or block after "#" // "#" 之后的标题或块
+	Label    string // Text after "|" // "|" 之后的文本
+	Embed    bool   // Prefixed with "!" // 以 "!" 开头
+}
+
+// Kind implements ast.Node
+// Kind 实现 ast.Node
+func (n *WikiLink) Kind() ast.NodeKind {
+	return KindWikiLink
+}
+
+// Dump implements ast.Node
+// Dump 实现 ast.Node
+func (n *WikiLink) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Target":   n.Target,
+		"Fragment": n.Fragment,
+		"Label":    n.Label,
+	}, nil)
+}
+
+// wikiLinkParser parses [[wiki links]] and ![[embeds]] before the standard link parser sees them
+// wikiLinkParser 在标准链接解析器之前解析 [[维基链接]] 与 ![[嵌入]]
+type wikiLinkParser struct{}
+
+func (p *wikiLinkParser) Trigger() []byte {
+	return []byte{'!', '['}
+}
+
+func (p *wikiLinkParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	start := 0
+	if len(line) > 0 && line[0] == '!' {
+		start = 1
+	}
+	if !bytes.HasPrefix(line[start:], []byte("[[")) {
+		return nil
+	}
+	end := bytes.Index(line[start+2:], []byte("]]"))
+	if end <= 0 {
+		return nil
+	}
+	inner := string(line[start+2 : start+2+end])
+	if strings.ContainsAny(inner, "[\n") {
+		return nil
+	}
+
+	link := &WikiLink{Embed: start == 1}
+	target := inner
+	if i := strings.IndexByte(target, '|'); i >= 0 {
+		target, link.Label = target[:i], strings.TrimSpace(target[i+1:])
+	}
+	if i := strings.IndexByte(target, '#'); i >= 0 {
+		target, link.Fragment = target[:i], strings.TrimSpace(target[i+1:])
+	}
+	link.Target = strings.TrimSpace(target)
+	if link.Target == "" && link.Fragment == "" {
+		return nil
+	}
+
+	block.Advance(start + 2 + end + 2)
+	return link
+}
+
+// imageResolver points local images at their attachment URL
+// imageResolver 将本地图片指向其附件地址
+type imageResolver struct {
+	resolver Resolver
+}
+
+func (t *imageResolver) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if img, ok := n.(*ast.Image); ok {
+			if src, ok := t.resolver.ResolveEmbed(string(img.Destination)); ok {
+				img.Destination = []byte(src)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+// wikiLinkRenderer writes WikiLink nodes, links get the class "internal-link" and embeds "internal-embed",
+// targets that cannot be resolved additionally get "is-unresolved" like in Obsidian
+// wikiLinkRenderer 输出 WikiLink 节点，链接使用 "internal-link" 类，嵌入使用 "internal-embed" 类，
+// 与 Obsidian 一致，无法解析的目标额外带有 "is-unresolved" 类
+type wikiLinkRenderer struct {
+	resolver Resolver
+}
+
+func (r *wikiLinkRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindWikiLink, r.render)
+}
+
+func (r *wikiLinkRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*WikiLink)
+
+	label := n.Label
+	if label == "" {
+		label = strings.TrimSuffix(n.Target, ".md")
+		if n.Target == "" {
+			label = n.Fragment
+		} else if n.Fragment != "" {
+			label += " > " + n.Fragment
+		}
+	}
+
+	if n.Embed && IsAttachment(n.Target) {
+		src, ok := r.resolver.ResolveEmbed(n.Target)
+		if !ok {
+			_, _ = w.WriteString(`<span class="internal-embed is-unresolved">` + html.EscapeString(label) + `</span>`)
+			return ast.WalkSkipChildren, nil
+		}
+		src = html.EscapeString(src)
+		switch MediaType(n.Target) {
+		case "image":
+			// ![[image.png|300]] and ![[image.png|300x200]] set the width like in Obsidian
+			// 与 Obsidian 一致，![[image.png|300]] 与 ![[image.png|300x200]] 设置宽度
+			alt, width := n.Label, ""
+			if alt != "" && strings.Trim(alt, "0123456789x") == "" {
+				alt, width = "", strings.SplitN(n.Label, "x", 2)[0]
+			}
+			if alt == "" {
+				alt = path.Base(n.Target)
+			}
+			_, _ = w.WriteString(`<img class="internal-embed" src="` + src + `" alt="` + html.EscapeString(alt) + `"`)
+			if width != "" {
+				_, _ = w.WriteString(` width="` + width + `"`)
+			}
+			_, _ = w.WriteString(`>`)
+		case "audio":
+			_, _ = w.WriteString(`<audio class="internal-embed" controls src="` + src + `"></audio>`)
+		case "video":
+			_, _ = w.WriteString(`<video class="internal-embed" controls src="` + src + `"></video>`)
+		default:
+			_, _ = w.WriteString(`<a class="internal-embed" href="` + src + `">` + html.EscapeString(label) + `</a>`)
+		}
+		return ast.WalkSkipChildren, nil
+	}
+
+	// Links, and embedded notes which are linked rather than transcluded
+	// 链接，以及以链接形式呈现（不内联展开）的嵌入笔记
+	class := "internal-link"
+	if n.Embed {
+		class = "internal-embed"
+	}
+	href, ok := "", n.Target == ""
+	if n.Target != "" {
+		href, ok = r.resolver.ResolveLink(n.Target)
+	}
+	if n.Fragment != "" {
+		href += "#" + n.Fragment
+	}
+	if !ok {
+		class += " is-unresolved"
+	}
+	_, _ = w.WriteString(`<a class="` + class + `" href="` + html.EscapeString(href) + `">` + html.EscapeString(label) + `</a>`)
+	return ast.WalkSkipChildren, nil
+}
+
+// mediaTypes attachment extensions rendered inline when embedded
+// mediaTypes 嵌入时内联显示的附件扩展名
+var mediaTypes = map[string]string{
+	".png": "image", ".jpg": "image", ".jpeg": "image", ".gif": "image", ".bmp": "image",
+	".svg": "image", ".webp": "image", ".avif": "image",
+	".mp3": "audio", ".wav": "audio", ".m4a": "audio", ".ogg": "audio", ".flac": "audio", ".3gp": "audio",
+	".mp4": "video", ".webm": "video", ".ogv": "video", ".mov": "video", ".mkv": "video",
+	".pdf": "file",
+}
+
+// MediaType returns "image", "audio", "video" or "file" for attachments, "" for notes
+// MediaType 对附件返回 "image"、"audio"、"video" 或 "file"，对笔记返回 ""
+func MediaType(target string) string {
+	ext := strings.ToLower(path.Ext(target))
+	if t, ok := mediaTypes[ext]; ok {
+		return t
+	}
+	if ext == "" || ext == ".md" {
+		return ""
+	}
+	return "file"
+}
+
+// IsAttachment reports whether the target is an attachment rather than a note
+// IsAttachment 判断目标是否为附件而非笔记
+func IsAttachment(target string) bool {
+	return MediaType(target) != ""
+}
+
+// policy allows what Markdown renders to plus the attributes of wiki links, embeds and task lists.
+// Raw HTML in notes passes through the same policy, so scripts, event handlers and javascript: URLs never survive.
+// policy 允许 Markdown 渲染产生的内容以及维基链接、嵌入与任务列表的属性。
+// 笔记中的原始 HTML 也经过同一策略，脚本、事件处理器与 javascript: URL 均会被移除。
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.RequireNoFollowOnLinks(false)
+	p.RequireNoFollowOnFullyQualifiedLinks(true)
+	p.AllowAttrs("class").OnElements("a", "span", "img", "audio", "video", "code", "pre", "div", "li", "ul", "input")
+	p.AllowAttrs("controls", "src").OnElements("audio", "video")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}()
+
+// Render renders Markdown to sanitized HTML, GitHub flavored extensions and footnotes included
+// Render 将 Markdown 渲染为经过清洗的 HTML，支持 GitHub 风格扩展与脚注
+func Render(source []byte, resolver Resolver) (string, error) {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM, extension.Footnote),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithInlineParsers(util.Prioritized(&wikiLinkParser{}, 199)),
+			parser.WithASTTransformers(util.Prioritized(&imageResolver{resolver: resolver}, 100)),
+		),
+		goldmark.WithRendererOptions(
+			gmhtml.WithUnsafe(),
+			renderer.WithNodeRenderers(util.Prioritized(&wikiLinkRenderer{resolver: resolver}, 100)),
+		),
+	)
+
+	var buf bytes.Buffer
+	if err := md.Convert(source, &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
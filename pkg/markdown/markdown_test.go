@@ -0,0 +1,81 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mapResolver resolves targets listed in its maps
+// mapResolver 解析映射表中列出的目标
+type mapResolver struct {
+	links  map[string]string
+	embeds map[string]string
+}
+
+func (r mapResolver) ResolveLink(target string) (string, bool) {
+	href, ok := r.links[target]
+	return href, ok
+}
+
+func (r mapResolver) ResolveEmbed(target string) (string, bool) {
+	src, ok := r.embeds[target]
+	return src, ok
+}
+
+func render(t *testing.T, source string) string {
+	t.Helper()
+	out, err := Render([]byte(source), mapResolver{
+		links:  map[string]string{"Other": "Folder/Other.md"},
+		embeds: map[string]string{"pic.png": "/api/file?path=pic.png", "clip.mp4": "/api/file?path=clip.mp4", "doc.pdf": "/api/file?path=doc.pdf"},
+	})
+	require.NoError(t, err)
+	return out
+}
+
+func TestRender_Markdown(t *testing.T) {
+	out := render(t, "# Title\n\n**bold** ~~gone~~\n\n- [x] done\n\n| a |\n|---|\n| 1 |\n")
+	assert.Contains(t, out, `<h1 id="title">Title</h1>`)
+	assert.Contains(t, out, "<strong>bold</strong>")
+	assert.Contains(t, out, "<del>gone</del>")
+	assert.Contains(t, out, `<input checked="" disabled="" type="checkbox"`)
+	assert.Contains(t, out, "<table>")
+}
+
+func TestRender_WikiLinks(t *testing.T) {
+	out := render(t, "See [[Other]], [[Other#Intro|the intro]] and [[Missing]].")
+	assert.Contains(t, out, `<a class="internal-link" href="Folder/Other.md">Other</a>`)
+	assert.Contains(t, out, `<a class="internal-link" href="Folder/Other.md#Intro">the intro</a>`)
+	assert.Contains(t, out, `<a class="internal-link is-unresolved">Missing</a>`)
+
+	// Wiki links inside code are left alone
+	out = render(t, "`[[Other]]`")
+	assert.Contains(t, out, "<code>[[Other]]</code>")
+}
+
+func TestRender_Embeds(t *testing.T) {
+	out := render(t, "![[pic.png|300]]\n\n![[clip.mp4]]\n\n![[doc.pdf]]\n\n![[gone.png]]\n\n![alt](pic.png)")
+	assert.Contains(t, out, `<img class="internal-embed" src="/api/file?path=pic.png" alt="pic.png" width="300">`)
+	assert.Contains(t, out, `<video class="internal-embed" controls="" src="/api/file?path=clip.mp4"></video>`)
+	assert.Contains(t, out, `<a class="internal-embed" href="/api/file?path=doc.pdf">doc.pdf</a>`)
+	assert.Contains(t, out, `<span class="internal-embed is-unresolved">gone.png</span>`)
+	assert.Contains(t, out, `<img src="/api/file?path=pic.png" alt="alt">`)
+}
+
+func TestRender_Sanitized(t *testing.T) {
+	out := render(t, "<script>alert(1)</script>\n\n<img src=x onerror=alert(1)>\n\n[x](javascript:alert(1))\n\n[[Other|<b onclick=x>]]")
+	assert.NotContains(t, out, "<script")
+	assert.NotContains(t, out, "onerror")
+	assert.NotContains(t, out, "javascript:")
+	assert.NotContains(t, out, "<b onclick")
+}
+
+func TestMediaType(t *testing.T) {
+	assert.Equal(t, "image", MediaType("a/B.PNG"))
+	assert.Equal(t, "video", MediaType("clip.mp4"))
+	assert.Equal(t, "file", MediaType("archive.zip"))
+	assert.Equal(t, "", MediaType("Note"))
+	assert.Equal(t, "", MediaType("Note.md"))
+	assert.False(t, IsAttachment("Note.md"))
+}
@@ -0,0 +1,69 @@
+package readonly
+
+import (
+	"github.com/haierkeys/fast-note-sync-service/pkg/cluster"
+	"github.com/haierkeys/fast-note-sync-service/pkg/json"
+	"go.uber.org/zap"
+)
+
+// clusterTopic bus topic of the admin toggle shared between instances
+// clusterTopic 实例间共享管理员开关所用的总线主题
+const clusterTopic = "readonly"
+
+// clusterToggle admin toggle relayed to the other instances, or a query for it from a starting instance
+// clusterToggle 转发给其他实例的管理员开关，或启动中的实例对开关状态的查询
+type clusterToggle struct {
+	Query   bool   `json:"query,omitempty"`
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// UseClusterBus shares the admin toggle with the instances on the bus and asks them for the current one,
+// so the whole deployment turns read-only together; holds stay local to the instance running the operation
+// UseClusterBus 通过总线与其他实例共享管理员开关并向其查询当前状态，使整个部署同时进入只读；
+// 操作持有的只读仍只作用于执行该操作的实例
+func (m *Mode) UseClusterBus(bus cluster.Bus, logger *zap.Logger) {
+	if bus == nil {
+		return
+	}
+	m.mu.Lock()
+	m.bus = bus
+	m.logger = logger
+	m.mu.Unlock()
+	bus.Subscribe(clusterTopic, m.onClusterToggle)
+	m.publishCluster(clusterToggle{Query: true})
+}
+
+// publishCluster relays a toggle to the other instances, a failure leaves them in their previous state
+// publishCluster 向其他实例转发开关，失败时它们保持原状态
+func (m *Mode) publishCluster(ev clusterToggle) {
+	m.mu.RLock()
+	bus, logger := m.bus, m.logger
+	m.mu.RUnlock()
+	if bus == nil {
+		return
+	}
+	payload, err := json.Marshal(ev)
+	if err == nil {
+		err = bus.Publish(clusterTopic, payload)
+	}
+	if err != nil && logger != nil {
+		logger.Warn("read-only cluster publish failed", zap.Bool("enabled", ev.Enabled), zap.Error(err))
+	}
+}
+
+// onClusterToggle applies a toggle relayed by another instance, or answers its query when the toggle is on
+// onClusterToggle 应用其他实例转发的开关，或在开关开启时回应其查询
+func (m *Mode) onClusterToggle(payload []byte) {
+	var ev clusterToggle
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return
+	}
+	if ev.Query {
+		if status := m.Status(); status.Manual {
+			m.publishCluster(clusterToggle{Enabled: true, Reason: status.Reason})
+		}
+		return
+	}
+	m.set(ev.Enabled, ev.Reason)
+}
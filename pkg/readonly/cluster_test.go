@@ -0,0 +1,64 @@
+package readonly
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// memoryBus delivers messages synchronously to the other buses of its group
+// memoryBus 将消息同步投递给同组的其他总线
+type memoryBus struct {
+	peers    *[]*memoryBus
+	handlers map[string]func([]byte)
+}
+
+func newMemoryBuses(n int) []*memoryBus {
+	group := make([]*memoryBus, 0, n)
+	for i := 0; i < n; i++ {
+		group = append(group, &memoryBus{handlers: map[string]func([]byte){}})
+	}
+	for _, b := range group {
+		b.peers = &group
+	}
+	return group
+}
+
+func (b *memoryBus) Publish(topic string, payload []byte) error {
+	for _, p := range *b.peers {
+		if p != b && p.handlers[topic] != nil {
+			p.handlers[topic](payload)
+		}
+	}
+	return nil
+}
+
+func (b *memoryBus) Subscribe(topic string, handler func([]byte)) { b.handlers[topic] = handler }
+func (b *memoryBus) InstanceID() string                           { return "" }
+func (b *memoryBus) Close() error                                 { return nil }
+
+func TestMode_ClusterToggle(t *testing.T) {
+	buses := newMemoryBuses(3)
+	a, b := New(), New()
+	a.UseClusterBus(buses[0], zap.NewNop())
+	b.UseClusterBus(buses[1], zap.NewNop())
+
+	a.Set(true, "db upgrade")
+	assert.True(t, b.Enabled())
+	assert.Equal(t, "db upgrade", b.Status().Reason)
+
+	// Holds are not shared
+	release := b.Hold("snapshot restore")
+	b.Set(false, "")
+	assert.False(t, a.Enabled())
+	assert.True(t, b.Enabled())
+	release()
+
+	// An instance starting while the toggle is on picks it up
+	a.Set(true, "db upgrade")
+	c := New()
+	c.UseClusterBus(buses[2], zap.NewNop())
+	assert.True(t, c.Enabled())
+	assert.Equal(t, "db upgrade", c.Status().Reason)
+}
@@ -0,0 +1,133 @@
+// Package readonly switches the server into a read-only maintenance mode: clients keep connecting and
+// downloading while every change is refused
+// Package readonly 将服务器切换到只读维护模式：客户端仍可连接与下载，所有修改均被拒绝
+package readonly
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/cluster"
+	"go.uber.org/zap"
+)
+
+// Status state of the read-only mode
+// Status 只读模式的状态
+type Status struct {
+	Enabled bool      // Changes are refused // 拒绝修改
+	Manual  bool      // Switched on by the admin // 由管理员开启
+	Reason  string    // Reason given by the admin // 管理员填写的原因
+	Since   time.Time // Start of the current read-only period, zero when writable // 本次只读开始时间，可写时为零值
+	Holds   []string  // Operations keeping the server read-only, e.g. a restore // 保持只读的操作，如恢复
+}
+
+// Mode read-only switch, safe for concurrent use; the state lives in memory, the admin toggle is shared
+// with the other instances once UseClusterBus is called
+// Mode 只读开关，可并发使用；状态保存在内存中，调用 UseClusterBus 后管理员开关与其他实例共享
+type Mode struct {
+	now    func() time.Time
+	bus    cluster.Bus // Shares the admin toggle, nil when running alone // 共享管理员开关，单实例运行时为 nil
+	logger *zap.Logger
+
+	mu     sync.RWMutex
+	manual bool
+	reason string
+	since  time.Time
+	holds  map[int]string
+	nextID int
+}
+
+// New creates a writable Mode
+// New 创建处于可写状态的 Mode
+func New() *Mode {
+	return &Mode{now: time.Now, holds: make(map[int]string)}
+}
+
+// Enabled reports whether changes are refused; a nil Mode is always writable
+// Enabled 返回是否拒绝修改；nil Mode 始终可写
+func (m *Mode) Enabled() bool {
+	if m == nil {
+		return false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabledLocked()
+}
+
+func (m *Mode) enabledLocked() bool {
+	return m.manual || len(m.holds) > 0
+}
+
+// Set switches the admin toggle on every instance; the server stays read-only while an operation holds it
+// Set 在所有实例上切换管理员开关；仍有操作持有只读时服务器保持只读
+func (m *Mode) Set(enabled bool, reason string) {
+	m.set(enabled, reason)
+	m.publishCluster(clusterToggle{Enabled: enabled, Reason: reason})
+}
+
+// set switches the admin toggle of this instance
+// set 切换本实例的管理员开关
+func (m *Mode) set(enabled bool, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.update(func() {
+		m.manual = enabled
+		m.reason = ""
+		if enabled {
+			m.reason = reason
+		}
+	})
+}
+
+// Hold keeps the server read-only until the returned release is called, for upgrades, restores and
+// migrations; release may be called more than once
+// Hold 使服务器保持只读直到调用返回的 release，用于升级、恢复与迁移；release 可重复调用
+func (m *Mode) Hold(operation string) (release func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.nextID
+	m.nextID++
+	m.update(func() { m.holds[id] = operation })
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.update(func() { delete(m.holds, id) })
+		})
+	}
+}
+
+// update applies change and keeps the start of the read-only period, the caller holds the lock
+// update 执行 change 并维护本次只读的开始时间，调用方需持有锁
+func (m *Mode) update(change func()) {
+	was := m.enabledLocked()
+	change()
+	switch is := m.enabledLocked(); {
+	case is && !was:
+		m.since = m.now()
+	case !is:
+		m.since = time.Time{}
+	}
+}
+
+// Status returns the current state
+// Status 返回当前状态
+func (m *Mode) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	status := Status{
+		Enabled: m.enabledLocked(),
+		Manual:  m.manual,
+		Reason:  m.reason,
+		Since:   m.since,
+		Holds:   make([]string, 0, len(m.holds)),
+	}
+	for _, operation := range m.holds {
+		status.Holds = append(status.Holds, operation)
+	}
+	sort.Strings(status.Holds)
+	return status
+}
@@ -0,0 +1,40 @@
+package readonly
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMode_ToggleAndHolds(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	m := New()
+	m.now = func() time.Time { return now }
+	assert.False(t, m.Enabled())
+
+	m.Set(true, "upgrade to 2.0")
+	status := m.Status()
+	assert.True(t, status.Enabled)
+	assert.Equal(t, "upgrade to 2.0", status.Reason)
+	assert.Equal(t, now, status.Since)
+
+	// A hold keeps the server read-only after the toggle is switched off, and the period goes on
+	now = now.Add(time.Minute)
+	release := m.Hold("snapshot restore")
+	m.Set(false, "ignored")
+	status = m.Status()
+	assert.True(t, status.Enabled)
+	assert.False(t, status.Manual)
+	assert.Empty(t, status.Reason)
+	assert.Equal(t, []string{"snapshot restore"}, status.Holds)
+	assert.Equal(t, now.Add(-time.Minute), status.Since)
+
+	release()
+	release()
+	assert.False(t, m.Enabled())
+	assert.True(t, m.Status().Since.IsZero())
+
+	var nilMode *Mode
+	assert.False(t, nilMode.Enabled())
+}
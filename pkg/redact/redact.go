@@ -0,0 +1,136 @@
+// Package redact strips secrets from note content before it leaves the server
+// Package redact 在笔记内容离开服务器前剔除其中的敏感信息
+package redact
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+)
+
+// DefaultMask replacement used when Rules.Mask is empty
+// DefaultMask Rules.Mask 为空时使用的替换文本
+const DefaultMask = "[REDACTED]"
+
+// Rules redaction rules
+// Rules 脱敏规则
+type Rules struct {
+	FrontmatterKeys []string // Frontmatter key glob patterns to strip, e.g. "api_*" // 需移除的 frontmatter 键通配模式，如 "api_*"
+	ExcludeFolders  []string // Vault-relative folders excluded entirely // 整体排除的 Vault 相对目录
+	MaskPatterns    []string // Regular expressions whose matches are masked // 匹配内容将被遮盖的正则表达式
+	Mask            string   // Replacement text for masked matches // 遮盖替换文本
+}
+
+// Redactor applies compiled redaction rules. A nil Redactor is a no-op.
+// Redactor 应用已编译的脱敏规则，nil Redactor 不做任何处理。
+type Redactor struct {
+	keys    []string
+	folders []string
+	masks   []*regexp.Regexp
+	mask    string
+}
+
+// New compiles rules into a Redactor, returning nil when no rule is configured
+// New 将规则编译为 Redactor，未配置任何规则时返回 nil
+func New(rules Rules) (*Redactor, error) {
+	r := &Redactor{mask: rules.Mask}
+	if r.mask == "" {
+		r.mask = DefaultMask
+	}
+
+	for _, key := range rules.FrontmatterKeys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		if _, err := path.Match(key, ""); err != nil {
+			return nil, fmt.Errorf("redact: invalid frontmatter key pattern %q: %w", key, err)
+		}
+		r.keys = append(r.keys, key)
+	}
+
+	for _, folder := range rules.ExcludeFolders {
+		folder = strings.Trim(strings.TrimSpace(strings.ReplaceAll(folder, "\\", "/")), "/")
+		if folder == "" {
+			continue
+		}
+		r.folders = append(r.folders, folder)
+	}
+
+	for _, pattern := range rules.MaskPatterns {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redact: invalid mask pattern %q: %w", pattern, err)
+		}
+		r.masks = append(r.masks, re)
+	}
+
+	if len(r.keys) == 0 && len(r.folders) == 0 && len(r.masks) == 0 {
+		return nil, nil
+	}
+	return r, nil
+}
+
+// Excluded reports whether a vault-relative path lies in an excluded folder
+// Excluded 判断 Vault 相对路径是否位于排除目录中
+func (r *Redactor) Excluded(p string) bool {
+	if r == nil {
+		return false
+	}
+	p = strings.TrimLeft(strings.ReplaceAll(p, "\\", "/"), "/")
+	for _, folder := range r.folders {
+		if p == folder || strings.HasPrefix(p, folder+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Content strips matching frontmatter keys and masks sensitive matches in note content
+// Content 移除匹配的 frontmatter 键并遮盖笔记内容中的敏感匹配项
+func (r *Redactor) Content(content []byte) []byte {
+	if r == nil || len(content) == 0 {
+		return content
+	}
+
+	text := string(content)
+	if len(r.keys) > 0 {
+		if yamlData, body, ok := util.ParseFrontmatter(text); ok {
+			removed := false
+			for key := range yamlData {
+				if r.matchKey(key) {
+					delete(yamlData, key)
+					removed = true
+				}
+			}
+			// Only rebuild when needed, re-marshalling reorders the remaining keys
+			// 仅在需要时重建，重新序列化会改变剩余键的顺序
+			if removed {
+				text = util.ReconstructContent(yamlData, body)
+			}
+		}
+	}
+
+	for _, re := range r.masks {
+		text = re.ReplaceAllLiteralString(text, r.mask)
+	}
+	return []byte(text)
+}
+
+// matchKey reports whether a frontmatter key matches any strip pattern (case-insensitive)
+// matchKey 判断 frontmatter 键是否匹配任一移除模式（不区分大小写）
+func (r *Redactor) matchKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range r.keys {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
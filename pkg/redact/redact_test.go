@@ -0,0 +1,57 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_NoRules(t *testing.T) {
+	r, err := New(Rules{FrontmatterKeys: []string{" "}})
+	require.NoError(t, err)
+	assert.Nil(t, r)
+
+	// A nil Redactor passes everything through
+	assert.False(t, r.Excluded("Private/a.md"))
+	assert.Equal(t, []byte("x"), r.Content([]byte("x")))
+}
+
+func TestNew_InvalidPattern(t *testing.T) {
+	_, err := New(Rules{MaskPatterns: []string{"("}})
+	assert.Error(t, err)
+
+	_, err = New(Rules{FrontmatterKeys: []string{"["}})
+	assert.Error(t, err)
+}
+
+func TestRedactor_Excluded(t *testing.T) {
+	r, err := New(Rules{ExcludeFolders: []string{"/Private/", "Work\\Secrets"}})
+	require.NoError(t, err)
+
+	assert.True(t, r.Excluded("Private/a.md"))
+	assert.True(t, r.Excluded("Private"))
+	assert.True(t, r.Excluded("Work/Secrets/keys.md"))
+	assert.False(t, r.Excluded("PrivateNotes/a.md"))
+	assert.False(t, r.Excluded("Work/a.md"))
+}
+
+func TestRedactor_Content(t *testing.T) {
+	r, err := New(Rules{
+		FrontmatterKeys: []string{"api_*", "Password"},
+		MaskPatterns:    []string{`sk-[A-Za-z0-9]{8,}`},
+	})
+	require.NoError(t, err)
+
+	in := "---\ntitle: Hello\napi_key: abc\npassword: hunter2\n---\nToken sk-ABCDEFGH123 here\n"
+	out := string(r.Content([]byte(in)))
+
+	assert.Contains(t, out, "title: Hello")
+	assert.NotContains(t, out, "api_key")
+	assert.NotContains(t, out, "hunter2")
+	assert.Contains(t, out, "Token [REDACTED] here")
+
+	// Content without matching keys is left byte-for-byte intact
+	plain := "---\ntitle: Hello\ntags: [a]\n---\nbody\n"
+	assert.Equal(t, plain, string(r.Content([]byte(plain))))
+}
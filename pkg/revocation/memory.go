@@ -0,0 +1,162 @@
+package revocation
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/json"
+)
+
+// userEntry user-wide revocation, the tokens with an ID up to MaxTokenID are revoked
+// userEntry 用户级撤销，ID 不大于 MaxTokenID 的令牌均被撤销
+type userEntry struct {
+	MaxTokenID int64 `json:"maxTokenId"` // Highest revoked token ID // 被撤销的最大令牌 ID
+	Expiry     int64 `json:"expiry"`     // Entry expiry in Unix milliseconds // 条目过期时间，Unix 毫秒
+}
+
+// snapshot file layout of the persisted list
+// snapshot 持久化列表的文件结构
+type snapshot struct {
+	Tokens map[int64]int64     `json:"tokens"` // Token ID -> expiry in Unix milliseconds // 令牌 ID -> 过期时间（Unix 毫秒）
+	Users  map[int64]userEntry `json:"users"`  // UID -> user-wide revocation // UID -> 用户级撤销
+}
+
+// Memory List kept in memory, written to a file after every change when a path is given so the
+// revocations survive a restart
+// Memory 保存在内存中的 List，指定路径时每次变更后写入文件，使撤销记录在重启后仍然有效
+type Memory struct {
+	path string
+
+	mu     sync.RWMutex
+	tokens map[int64]int64
+	users  map[int64]userEntry
+}
+
+// NewMemory creates the list and loads the entries saved at path, an empty path keeps it in memory only
+// NewMemory 创建列表并加载 path 中保存的条目，path 为空时仅保存在内存中
+func NewMemory(path string) (*Memory, error) {
+	m := &Memory{
+		path:   path,
+		tokens: make(map[int64]int64),
+		users:  make(map[int64]userEntry),
+	}
+	if path == "" {
+		return m, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	for id, expiry := range snap.Tokens {
+		if expiry > now {
+			m.tokens[id] = expiry
+		}
+	}
+	for uid, entry := range snap.Users {
+		if entry.Expiry > now {
+			m.users[uid] = entry
+		}
+	}
+	return m, nil
+}
+
+// RevokeToken revokes a single token until expiry
+// RevokeToken 撤销单个令牌直到过期时间
+func (m *Memory) RevokeToken(ctx context.Context, tokenID int64, expiry time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if expiry.UnixMilli() > m.tokens[tokenID] {
+		m.tokens[tokenID] = expiry.UnixMilli()
+	}
+	return m.save()
+}
+
+// RevokeUser revokes every token of the user with an ID up to maxTokenID until expiry
+// RevokeUser 撤销用户 ID 不大于 maxTokenID 的所有令牌直到过期时间
+func (m *Memory) RevokeUser(ctx context.Context, uid int64, maxTokenID int64, expiry time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := m.users[uid]
+	entry.MaxTokenID = max(entry.MaxTokenID, maxTokenID)
+	entry.Expiry = max(entry.Expiry, expiry.UnixMilli())
+	m.users[uid] = entry
+	return m.save()
+}
+
+// IsRevoked reports whether the token of the user was revoked
+// IsRevoked 判断用户的令牌是否已被撤销
+func (m *Memory) IsRevoked(ctx context.Context, uid int64, tokenID int64) (bool, error) {
+	now := time.Now().UnixMilli()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if expiry, ok := m.tokens[tokenID]; ok && expiry > now {
+		return true, nil
+	}
+	if entry, ok := m.users[uid]; ok && entry.Expiry > now && tokenID <= entry.MaxTokenID {
+		return true, nil
+	}
+	return false, nil
+}
+
+// Close has nothing to release, every change is already saved
+// Close 无需释放资源，所有变更均已保存
+func (m *Memory) Close() error {
+	return nil
+}
+
+// save drops the expired entries and writes the list to a temporary file moved into place, the caller holds the lock
+// save 清理过期条目，并将列表写入临时文件后移动到目标位置，调用方需持有锁
+func (m *Memory) save() error {
+	now := time.Now().UnixMilli()
+	for id, expiry := range m.tokens {
+		if expiry <= now {
+			delete(m.tokens, id)
+		}
+	}
+	for uid, entry := range m.users {
+		if entry.Expiry <= now {
+			delete(m.users, uid)
+		}
+	}
+	if m.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(snapshot{Tokens: m.tokens, Users: m.users})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), ".revocation-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), m.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
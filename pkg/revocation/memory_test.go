@@ -0,0 +1,65 @@
+package revocation
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMemory_Revoke verifies single tokens and user-wide cutoffs are revoked, tokens issued after
+// the cutoff stay valid and expired entries no longer match.
+// TestMemory_Revoke 验证单个令牌与用户级截止 ID 的撤销，之后签发的令牌仍然有效，过期条目不再生效。
+func TestMemory_Revoke(t *testing.T) {
+	ctx := context.Background()
+	m, err := NewMemory("")
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, m.RevokeToken(ctx, 7, now.Add(time.Hour)))
+	require.NoError(t, m.RevokeToken(ctx, 8, now.Add(-time.Second)))
+	require.NoError(t, m.RevokeUser(ctx, 2, 10, now.Add(time.Hour)))
+
+	check := func(uid, tokenID int64) bool {
+		revoked, err := m.IsRevoked(ctx, uid, tokenID)
+		require.NoError(t, err)
+		return revoked
+	}
+	assert.True(t, check(1, 7))
+	assert.False(t, check(1, 8))
+	assert.True(t, check(2, 9))
+	assert.True(t, check(2, 10))
+	assert.False(t, check(2, 11))
+	assert.False(t, check(3, 9))
+
+	// A lower cutoff never undoes a higher one
+	// 较小的截止 ID 不会覆盖较大的截止 ID
+	require.NoError(t, m.RevokeUser(ctx, 2, 5, now.Add(time.Minute)))
+	assert.True(t, check(2, 10))
+}
+
+// TestMemory_Persist verifies the revocations are reloaded from the file, without the expired ones.
+// TestMemory_Persist 验证撤销记录可从文件重新加载，且不包含已过期的条目。
+func TestMemory_Persist(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "revocation.json")
+	m, err := NewMemory(path)
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, m.RevokeToken(ctx, 7, now.Add(time.Hour)))
+	require.NoError(t, m.RevokeToken(ctx, 8, now.Add(50*time.Millisecond)))
+	require.NoError(t, m.RevokeUser(ctx, 2, 10, now.Add(time.Hour)))
+	require.NoError(t, m.Close())
+
+	time.Sleep(100 * time.Millisecond)
+	reloaded, err := NewMemory(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[int64]int64{7: now.Add(time.Hour).UnixMilli()}, reloaded.tokens)
+	revoked, err := reloaded.IsRevoked(ctx, 2, 9)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
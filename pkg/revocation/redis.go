@@ -0,0 +1,130 @@
+package revocation
+
+import (
+	"context"
+	"crypto/tls"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisConfig Redis server holding the list
+// RedisConfig 保存列表的 Redis 服务
+type RedisConfig struct {
+	Addr      string // host:port // 地址 host:port
+	Username  string // ACL user name, empty for the default user // ACL 用户名，默认用户留空
+	Password  string // Password // 密码
+	DB        int    // Database number // 数据库编号
+	KeyPrefix string // Key prefix, instances of one deployment must share it // 键前缀，同一部署的实例必须一致
+	TLS       bool   // Connect with TLS // 使用 TLS 连接
+}
+
+// Redis List kept in Redis, shared by every instance of a deployment; the entries expire with their keys
+// Redis 保存在 Redis 中的 List，由同一部署的所有实例共享；条目随键过期
+type Redis struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedis connects to Redis
+// NewRedis 连接 Redis
+func NewRedis(cfg RedisConfig) (*Redis, error) {
+	if strings.TrimSpace(cfg.Addr) == "" {
+		return nil, errors.New("revocation: redis addr is required")
+	}
+	prefix := cfg.KeyPrefix
+	if prefix == "" {
+		prefix = "fns"
+	}
+
+	opts := &redis.Options{
+		Addr:     cfg.Addr,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	}
+	if cfg.TLS {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, errors.Wrap(err, "revocation: redis ping")
+	}
+	return &Redis{client: client, prefix: prefix}, nil
+}
+
+func (r *Redis) tokenKey(tokenID int64) string {
+	return r.prefix + ":revoked:token:" + strconv.FormatInt(tokenID, 10)
+}
+
+func (r *Redis) userKey(uid int64) string {
+	return r.prefix + ":revoked:user:" + strconv.FormatInt(uid, 10)
+}
+
+// RevokeToken revokes a single token until expiry
+// RevokeToken 撤销单个令牌直到过期时间
+func (r *Redis) RevokeToken(ctx context.Context, tokenID int64, expiry time.Time) error {
+	ttl := time.Until(expiry)
+	if ttl <= 0 {
+		return nil
+	}
+	return r.client.Set(ctx, r.tokenKey(tokenID), 1, ttl).Err()
+}
+
+// RevokeUser revokes every token of the user with an ID up to maxTokenID until expiry.
+// The cutoff only grows and the key only lives longer, so concurrent revocations cannot undo each other.
+// RevokeUser 撤销用户 ID 不大于 maxTokenID 的所有令牌直到过期时间。
+// 截止 ID 只增不减、键的存活时间只会延长，并发撤销不会相互覆盖。
+func (r *Redis) RevokeUser(ctx context.Context, uid int64, maxTokenID int64, expiry time.Time) error {
+	ttl := time.Until(expiry)
+	if ttl <= 0 {
+		return nil
+	}
+	return revokeUserScript.Run(ctx, r.client, []string{r.userKey(uid)}, maxTokenID, ttl.Milliseconds()).Err()
+}
+
+// revokeUserScript keeps the larger cutoff and the longer TTL
+// revokeUserScript 保留较大的截止 ID 与较长的存活时间
+var revokeUserScript = redis.NewScript(`
+local cur = tonumber(redis.call('GET', KEYS[1]) or '0')
+local maxID = tonumber(ARGV[1])
+if maxID < cur then maxID = cur end
+local ttl = tonumber(ARGV[2])
+local left = redis.call('PTTL', KEYS[1])
+if left > ttl then ttl = left end
+redis.call('SET', KEYS[1], maxID, 'PX', ttl)
+return 1
+`)
+
+// IsRevoked reports whether the token of the user was revoked
+// IsRevoked 判断用户的令牌是否已被撤销
+func (r *Redis) IsRevoked(ctx context.Context, uid int64, tokenID int64) (bool, error) {
+	values, err := r.client.MGet(ctx, r.tokenKey(tokenID), r.userKey(uid)).Result()
+	if err != nil {
+		return false, err
+	}
+	if values[0] != nil {
+		return true, nil
+	}
+	if s, ok := values[1].(string); ok {
+		maxTokenID, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return false, err
+		}
+		return tokenID <= maxTokenID, nil
+	}
+	return false, nil
+}
+
+// Close releases the connection
+// Close 释放连接
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,36 @@
+// Package revocation keeps the auth tokens revoked before their expiry, so the auth middleware can
+// reject them without trusting the signature alone.
+// Package revocation 记录在过期前被撤销的认证令牌，使认证中间件无需仅依赖签名即可拒绝它们。
+package revocation
+
+import (
+	"context"
+	"time"
+)
+
+// List token denylist. A token is revoked on its own by ID, or together with every other token of
+// its user up to a token ID; token IDs only grow, so the tokens issued later are not affected.
+// Entries are kept until the given expiry, which should be the expiry of the longest lived token they can match.
+// List 令牌拒绝列表。令牌可按 ID 单独撤销，也可与其用户截至某个令牌 ID 的所有令牌一起撤销；
+// 令牌 ID 只增不减，之后签发的令牌不受影响。条目保留到给定的过期时间，该时间应为其可能匹配的最长令牌的过期时间。
+type List interface {
+	// RevokeToken revokes a single token until expiry
+	// RevokeToken 撤销单个令牌直到过期时间
+	RevokeToken(ctx context.Context, tokenID int64, expiry time.Time) error
+	// RevokeUser revokes every token of the user with an ID up to maxTokenID until expiry
+	// RevokeUser 撤销用户 ID 不大于 maxTokenID 的所有令牌直到过期时间
+	RevokeUser(ctx context.Context, uid int64, maxTokenID int64, expiry time.Time) error
+	// IsRevoked reports whether the token of the user was revoked
+	// IsRevoked 判断用户的令牌是否已被撤销
+	IsRevoked(ctx context.Context, uid int64, tokenID int64) (bool, error)
+	// Close releases the resources of the list
+	// Close 释放列表占用的资源
+	Close() error
+}
+
+// Backend types
+// 后端类型
+const (
+	TypeMemory = "memory"
+	TypeRedis  = "redis"
+)
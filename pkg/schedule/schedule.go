@@ -0,0 +1,457 @@
+// Package schedule runs named background tasks on an interval or a cron expression, reports their last and
+// next runs and lets them be triggered by hand
+// Package schedule 按间隔或 cron 表达式运行命名的后台任务，报告其上次与下次运行，并支持手动触发
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// Off schedule that switches the scheduled runs of a task off; startup and manual runs still happen
+// Off 关闭任务定时运行的调度；启动运行与手动运行不受影响
+const Off = "off"
+
+// Error definitions
+// 错误定义
+var (
+	// ErrTaskNotFound returned when no task has the name
+	// ErrTaskNotFound 没有该名称的任务时返回
+	ErrTaskNotFound = errors.New("scheduled task not found")
+	// ErrTaskRunning returned when triggering a task that is still running
+	// ErrTaskRunning 触发仍在运行的任务时返回
+	ErrTaskRunning = errors.New("scheduled task is running")
+	// ErrTaskExists returned when adding a task whose name is taken
+	// ErrTaskExists 添加的任务名称已存在时返回
+	ErrTaskExists = errors.New("scheduled task already exists")
+	// ErrDeferred returned by a task run that was put off, e.g. to the maintenance window
+	// ErrDeferred 任务运行被推迟（例如推迟到维护窗口）时由任务返回
+	ErrDeferred = errors.New("scheduled run deferred")
+)
+
+// Trigger what started a run
+// Trigger 运行的触发方式
+type Trigger string
+
+const (
+	TriggerStartup  Trigger = "startup"  // Server start // 服务启动
+	TriggerSchedule Trigger = "schedule" // Interval or cron expression // 间隔或 cron 表达式
+	TriggerManual   Trigger = "manual"   // Triggered by hand // 手动触发
+)
+
+// Result outcome of a run
+// Result 运行结果
+type Result string
+
+const (
+	ResultSuccess  Result = "success"  // The run succeeded // 运行成功
+	ResultFailed   Result = "failed"   // The run returned an error or panicked // 运行返回错误或 panic
+	ResultDeferred Result = "deferred" // The run was put off // 运行被推迟
+)
+
+// Task a named task
+// Task 命名任务
+type Task struct {
+	Name       string                          // Unique task name // 唯一的任务名称
+	Interval   time.Duration                   // Default schedule, 0 when the task has none // 默认调度间隔，为 0 时无默认调度
+	StartupRun bool                            // Run once when the scheduler starts // 调度器启动时运行一次
+	Heavy      bool                            // Runs only in the maintenance window, for reporting // 仅在维护窗口内运行，用于展示
+	Run        func(ctx context.Context) error // Task body // 任务内容
+}
+
+// Status schedule and last run of a task
+// Status 任务的调度与上次运行情况
+type Status struct {
+	Name           string        // Task name // 任务名称
+	Default        string        // Default schedule, empty when the task only runs on startup or by hand // 默认调度，仅在启动时或手动运行时为空
+	Schedule       string        // Schedule in effect // 生效的调度
+	Custom         bool          // Schedule overrides the default // 调度覆盖了默认值
+	StartupRun     bool          // Runs once when the scheduler starts // 调度器启动时运行一次
+	Heavy          bool          // Runs only in the maintenance window // 仅在维护窗口内运行
+	Running        bool          // A run is in progress // 正在运行
+	Runs           uint64        // Finished runs since the server started // 服务启动以来完成的运行次数
+	Failures       uint64        // Failed runs since the server started // 服务启动以来失败的运行次数
+	LastTrigger    Trigger       // What started the last run // 上次运行的触发方式
+	LastStartedAt  time.Time     // Start of the last run // 上次运行的开始时间
+	LastFinishedAt time.Time     // End of the last run // 上次运行的结束时间
+	LastDuration   time.Duration // Duration of the last run // 上次运行的耗时
+	LastResult     Result        // Outcome of the last run // 上次运行的结果
+	LastError      string        // Error of the last failed run // 上次失败运行的错误
+	NextRunAt      time.Time     // Next scheduled run, zero when none // 下次定时运行时间，没有时为零值
+}
+
+type manualKey struct{}
+
+// Manual reports whether ctx belongs to a run triggered by hand
+// Manual 判断 ctx 是否属于手动触发的运行
+func Manual(ctx context.Context) bool {
+	manual, _ := ctx.Value(manualKey{}).(bool)
+	return manual
+}
+
+// Parse parses a schedule: a standard 5-field cron expression, a descriptor such as @daily or @every 90m,
+// or Off; Off yields a nil schedule
+// Parse 解析调度：标准 5 段 cron 表达式、@daily 或 @every 90m 等描述符，或 Off；Off 返回 nil 调度
+func Parse(spec string) (cron.Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == Off {
+		return nil, nil
+	}
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, fmt.Errorf("schedule: invalid schedule %q: %w", spec, err)
+	}
+	return schedule, nil
+}
+
+// every runs a task at a fixed interval; unlike cron.Every it keeps intervals below a second
+// every 以固定间隔运行任务；与 cron.Every 不同，它保留小于一秒的间隔
+type every time.Duration
+
+// Next returns the time one interval after t
+// Next 返回 t 之后一个间隔的时间
+func (d every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(d))
+}
+
+// entry a task with its schedule and status
+// entry 带调度与状态的任务
+type entry struct {
+	Task
+	status   Status
+	schedule cron.Schedule
+	wake     chan struct{}
+}
+
+// Scheduler runs tasks on their schedules, safe for concurrent use
+// Scheduler 按调度运行任务，可并发使用
+type Scheduler struct {
+	logger *zap.Logger
+	now    func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	order   []string
+	specs   map[string]string // Schedules overriding the defaults, by task name // 覆盖默认值的调度，以任务名为键
+	started bool
+}
+
+// New creates a Scheduler
+// New 创建 Scheduler
+func New(logger *zap.Logger) *Scheduler {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		logger:  logger,
+		now:     time.Now,
+		ctx:     ctx,
+		cancel:  cancel,
+		entries: make(map[string]*entry),
+		specs:   make(map[string]string),
+	}
+}
+
+// Add adds a task; once the scheduler is started the task is scheduled right away
+// Add 添加任务；调度器已启动时立即开始调度该任务
+func (s *Scheduler) Add(task Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[task.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrTaskExists, task.Name)
+	}
+
+	e := &entry{
+		Task: task,
+		status: Status{
+			Name:       task.Name,
+			StartupRun: task.StartupRun,
+			Heavy:      task.Heavy,
+		},
+		wake: make(chan struct{}, 1),
+	}
+	s.entries[task.Name] = e
+	s.order = append(s.order, task.Name)
+	s.rescheduleLocked(e)
+	if s.started {
+		s.startLocked(e)
+	}
+	return nil
+}
+
+// Start starts scheduling the tasks and runs the startup tasks; later calls do nothing
+// Start 开始调度任务并运行启动任务；重复调用不做任何事
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+	for _, name := range s.order {
+		s.startLocked(s.entries[name])
+	}
+}
+
+// startLocked starts the loop of a task and its startup run
+// startLocked 启动任务的调度循环及其启动运行
+func (s *Scheduler) startLocked(e *entry) {
+	if e.StartupRun {
+		s.runLocked(e, TriggerStartup)
+	}
+	s.wg.Add(1)
+	go s.loop(e)
+}
+
+// Stop cancels the running tasks and waits for them to return, or for ctx to end
+// Stop 取消运行中的任务并等待其返回，或等待 ctx 结束
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.cancel()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SetSpecs replaces the schedules overriding the defaults, keyed by task name; invalid schedules are
+// skipped and reported in the returned error
+// SetSpecs 替换覆盖默认值的调度（以任务名为键）；无效的调度被跳过并在返回的错误中报告
+func (s *Scheduler) SetSpecs(specs map[string]string) error {
+	var errs []error
+	valid := make(map[string]string, len(specs))
+	for name, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		if _, err := Parse(spec); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		valid[name] = spec
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.specs = valid
+	for _, name := range s.order {
+		s.rescheduleLocked(s.entries[name])
+	}
+	return errors.Join(errs...)
+}
+
+// SetInterval changes the default interval of a task, reports whether it changed
+// SetInterval 修改任务的默认间隔，返回是否有变化
+func (s *Scheduler) SetInterval(name string, interval time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[name]
+	if !ok || e.Interval == interval {
+		return false
+	}
+	e.Interval = interval
+	s.rescheduleLocked(e)
+	return true
+}
+
+// rescheduleLocked recomputes the schedule of a task and wakes its loop when the schedule changed
+// rescheduleLocked 重新计算任务的调度，调度变化时唤醒其调度循环
+func (s *Scheduler) rescheduleLocked(e *entry) {
+	def := ""
+	if e.Interval > 0 {
+		def = "@every " + e.Interval.String()
+	}
+	spec, custom := s.specs[e.Name]
+	if !custom {
+		spec = def
+	}
+	if spec == e.status.Schedule && def == e.status.Default && custom == e.status.Custom {
+		return
+	}
+
+	e.status.Default = def
+	e.status.Schedule = spec
+	e.status.Custom = custom
+	e.schedule = nil
+	if custom {
+		// Specs were validated by SetSpecs
+		// 调度已由 SetSpecs 校验
+		e.schedule, _ = Parse(spec)
+	} else if e.Interval > 0 {
+		e.schedule = every(e.Interval)
+	}
+	e.status.NextRunAt = time.Time{}
+	if e.schedule != nil {
+		e.status.NextRunAt = e.schedule.Next(s.now())
+	}
+
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+// loop runs a task whenever its next run is due
+// loop 在任务的下次运行到期时运行任务
+func (s *Scheduler) loop(e *entry) {
+	defer s.wg.Done()
+	for {
+		s.mu.Lock()
+		next := e.status.NextRunAt
+		s.mu.Unlock()
+
+		var due <-chan time.Time
+		var timer *time.Timer
+		if !next.IsZero() {
+			timer = time.NewTimer(next.Sub(s.now()))
+			due = timer.C
+		}
+
+		select {
+		case <-due:
+			s.mu.Lock()
+			// A reschedule racing the timer moved the next run
+			// 与计时器并发的重新调度已改变下次运行时间
+			if e.schedule != nil && e.status.NextRunAt.Equal(next) {
+				e.status.NextRunAt = e.schedule.Next(s.now())
+				s.runLocked(e, TriggerSchedule)
+			}
+			s.mu.Unlock()
+		case <-e.wake:
+		case <-s.ctx.Done():
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if s.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// Trigger runs a task now, outside its schedule
+// Trigger 立即运行任务，不受调度限制
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, name)
+	}
+	if !s.runLocked(e, TriggerManual) {
+		return fmt.Errorf("%w: %s", ErrTaskRunning, name)
+	}
+	return nil
+}
+
+// runLocked starts a run unless the task is still running, reports whether it started
+// runLocked 在任务未运行时开始一次运行，返回是否已开始
+func (s *Scheduler) runLocked(e *entry, trigger Trigger) bool {
+	if e.status.Running {
+		s.logger.Warn("task still running, run skipped", zap.String("name", e.Name), zap.String("trigger", string(trigger)))
+		return false
+	}
+	if s.ctx.Err() != nil {
+		return false
+	}
+	e.status.Running = true
+	e.status.LastTrigger = trigger
+	e.status.LastStartedAt = s.now()
+
+	ctx := s.ctx
+	if trigger == TriggerManual {
+		ctx = context.WithValue(ctx, manualKey{}, true)
+	}
+	s.wg.Add(1)
+	go s.run(ctx, e, trigger)
+	return true
+}
+
+// run runs the task and records the outcome
+// run 运行任务并记录结果
+func (s *Scheduler) run(ctx context.Context, e *entry, trigger Trigger) {
+	defer s.wg.Done()
+	s.logger.Info("task running", zap.String("name", e.Name), zap.String("trigger", string(trigger)))
+
+	started := s.now()
+	err := s.call(ctx, e)
+	finished := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e.status.Running = false
+	e.status.LastFinishedAt = finished
+	e.status.LastDuration = finished.Sub(started)
+	switch {
+	case err == nil:
+		e.status.Runs++
+		e.status.LastResult = ResultSuccess
+		e.status.LastError = ""
+	case errors.Is(err, ErrDeferred):
+		e.status.LastResult = ResultDeferred
+		e.status.LastError = ""
+	default:
+		e.status.Runs++
+		e.status.Failures++
+		e.status.LastResult = ResultFailed
+		e.status.LastError = err.Error()
+		s.logger.Error("task running error", zap.String("name", e.Name), zap.String("trigger", string(trigger)), zap.Error(err))
+	}
+}
+
+// Status returns the status of a task
+// Status 返回任务的状态
+func (s *Scheduler) Status(name string) (Status, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[name]
+	if !ok {
+		return Status{}, fmt.Errorf("%w: %s", ErrTaskNotFound, name)
+	}
+	return e.status, nil
+}
+
+// List returns the status of every task ordered by name
+// List 返回所有任务的状态，按名称排序
+func (s *Scheduler) List() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]Status, 0, len(s.entries))
+	for _, e := range s.entries {
+		list = append(list, e.status)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// call runs the task, turning a panic into an error
+// call 运行任务，将 panic 转换为错误
+func (s *Scheduler) call(ctx context.Context, e *entry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("task panic", zap.String("name", e.Name), zap.Any("panic", r), zap.Stack("stack"))
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return e.Run(ctx)
+}
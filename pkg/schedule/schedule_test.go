@@ -0,0 +1,96 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_IntervalTriggerAndStatus(t *testing.T) {
+	s := New(nil)
+	defer s.Stop(context.Background())
+
+	var runs atomic.Int32
+	var manual atomic.Bool
+	release := make(chan struct{})
+	require.NoError(t, s.Add(Task{Name: "Cleanup", Interval: 10 * time.Millisecond, Run: func(ctx context.Context) error {
+		if Manual(ctx) {
+			manual.Store(true)
+			<-release
+			return errors.New("disk full")
+		}
+		runs.Add(1)
+		return nil
+	}}))
+	assert.ErrorIs(t, s.Add(Task{Name: "Cleanup"}), ErrTaskExists)
+	s.Start()
+
+	require.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond)
+	status, err := s.Status("Cleanup")
+	require.NoError(t, err)
+	assert.Equal(t, "@every 10ms", status.Schedule)
+	assert.False(t, status.Custom)
+	assert.False(t, status.NextRunAt.IsZero())
+
+	// Switch scheduled runs off, then run by hand
+	require.NoError(t, s.SetSpecs(map[string]string{"Cleanup": Off}))
+	require.Eventually(t, func() bool {
+		status, _ := s.Status("Cleanup")
+		return !status.Running
+	}, time.Second, time.Millisecond)
+	status, _ = s.Status("Cleanup")
+	assert.True(t, status.Custom)
+	assert.True(t, status.NextRunAt.IsZero())
+
+	require.NoError(t, s.Trigger("Cleanup"))
+	require.Eventually(t, manual.Load, time.Second, time.Millisecond)
+	assert.ErrorIs(t, s.Trigger("Cleanup"), ErrTaskRunning)
+	close(release)
+
+	require.Eventually(t, func() bool {
+		status, _ := s.Status("Cleanup")
+		return status.LastResult == ResultFailed
+	}, time.Second, time.Millisecond)
+	status, _ = s.Status("Cleanup")
+	assert.Equal(t, TriggerManual, status.LastTrigger)
+	assert.Equal(t, "disk full", status.LastError)
+	assert.EqualValues(t, 1, status.Failures)
+
+	assert.ErrorIs(t, s.Trigger("Missing"), ErrTaskNotFound)
+}
+
+func TestScheduler_SpecsAndDeferred(t *testing.T) {
+	s := New(nil)
+	require.NoError(t, s.Add(Task{Name: "Snapshot", StartupRun: true, Heavy: true, Run: func(ctx context.Context) error {
+		return ErrDeferred
+	}}))
+
+	err := s.SetSpecs(map[string]string{"Snapshot": "0 3 * * *", "DbCleanup": "every day"})
+	assert.ErrorContains(t, err, "DbCleanup")
+	status, _ := s.Status("Snapshot")
+	assert.Equal(t, "0 3 * * *", status.Schedule)
+	assert.Equal(t, 3, status.NextRunAt.Hour())
+
+	s.Start()
+	require.Eventually(t, func() bool {
+		status, _ := s.Status("Snapshot")
+		return status.LastResult == ResultDeferred
+	}, time.Second, time.Millisecond)
+	status, _ = s.Status("Snapshot")
+	assert.Equal(t, TriggerStartup, status.LastTrigger)
+	assert.Zero(t, status.Runs)
+
+	require.NoError(t, s.SetSpecs(nil))
+	status, _ = s.Status("Snapshot")
+	assert.Empty(t, status.Schedule)
+	assert.True(t, status.NextRunAt.IsZero())
+
+	require.NoError(t, s.Stop(context.Background()))
+	_, err = Parse("@every 90m")
+	assert.NoError(t, err)
+}
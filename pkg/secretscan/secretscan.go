@@ -0,0 +1,106 @@
+// Package secretscan detects credentials embedded in note content
+// Package secretscan 检测笔记内容中嵌入的凭据
+package secretscan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaxFindings upper bound of findings returned by a single scan
+// MaxFindings 单次扫描返回的最大结果数
+const MaxFindings = 20
+
+// Rule a named credential pattern
+// Rule 具名凭据匹配规则
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultRules built-in credential patterns
+// DefaultRules 内置凭据匹配规则
+var DefaultRules = []Rule{
+	{Name: "aws-access-key-id", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[A-Z0-9]{16}\b`)},
+	{Name: "aws-secret-access-key", Pattern: regexp.MustCompile(`(?i)aws_?secret_?access_?key\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}\b`)},
+	{Name: "private-key", Pattern: regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{Name: "github-token", Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})\b`)},
+	{Name: "gitlab-token", Pattern: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20}\b`)},
+	{Name: "slack-token", Pattern: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{Name: "google-api-key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{Name: "stripe-secret-key", Pattern: regexp.MustCompile(`\b(?:sk|rk)_live_[0-9A-Za-z]{24,}\b`)},
+	{Name: "openai-api-key", Pattern: regexp.MustCompile(`\bsk-(?:proj-|ant-)?[A-Za-z0-9_-]{32,}\b`)},
+	{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\b`)},
+}
+
+// Finding a single credential match
+// Finding 单个凭据匹配结果
+type Finding struct {
+	Rule   string // Matched rule name // 命中的规则名称
+	Line   int    // 1-based line number // 行号（从 1 开始）
+	Column int    // 1-based column in runes // 列号（按字符计，从 1 开始）
+	Match  string // Masked match preview // 遮盖后的匹配预览
+}
+
+// Scanner scans content against a fixed rule set
+// Scanner 使用固定规则集扫描内容
+type Scanner struct {
+	rules []Rule
+}
+
+// New creates a Scanner with the built-in rules plus extra custom patterns
+// New 使用内置规则及额外自定义模式创建 Scanner
+func New(extra []string) (*Scanner, error) {
+	rules := append([]Rule(nil), DefaultRules...)
+	for i, pattern := range extra {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("secretscan: invalid pattern %q: %w", pattern, err)
+		}
+		rules = append(rules, Rule{Name: fmt.Sprintf("custom-%d", i+1), Pattern: re})
+	}
+	return &Scanner{rules: rules}, nil
+}
+
+// Scan returns findings ordered by position, at most MaxFindings
+// Scan 返回按位置排序的匹配结果，最多 MaxFindings 条
+func (s *Scanner) Scan(content string) []Finding {
+	if s == nil || content == "" {
+		return nil
+	}
+
+	var findings []Finding
+	lineNo := 0
+	for _, line := range strings.Split(content, "\n") {
+		lineNo++
+		for _, rule := range s.rules {
+			for _, loc := range rule.Pattern.FindAllStringIndex(line, -1) {
+				findings = append(findings, Finding{
+					Rule:   rule.Name,
+					Line:   lineNo,
+					Column: len([]rune(line[:loc[0]])) + 1,
+					Match:  Mask(line[loc[0]:loc[1]]),
+				})
+				if len(findings) >= MaxFindings {
+					return findings
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// Mask keeps a short prefix of a secret and hides the rest
+// Mask 保留密钥的少量前缀并隐藏其余部分
+func Mask(secret string) string {
+	runes := []rune(secret)
+	keep := 4
+	if len(runes) <= keep*2 {
+		keep = len(runes) / 4
+	}
+	return string(runes[:keep]) + strings.Repeat("*", 8)
+}
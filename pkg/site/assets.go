@@ -0,0 +1,92 @@
+package site
+
+import "html/template"
+
+// pageTemplate layout shared by the note pages and the index page
+// pageTemplate 笔记页面与索引页共用的布局
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if .Index}}{{.SiteTitle}}{{else}}{{.Title}} - {{.SiteTitle}}{{end}}</title>
+<link rel="stylesheet" href="{{.Root}}assets/site.css">
+</head>
+<body data-root="{{.Root}}">
+<header>
+<a class="site-title" href="{{.Root}}index.html">{{.SiteTitle}}</a>
+<div class="search"><input id="search" type="search" placeholder="Search" autocomplete="off"><ul id="search-results"></ul></div>
+</header>
+<main>
+{{if and (not .Index) (ne .Folder ".")}}<div class="folder">{{.Folder}}</div>{{end}}
+<article>{{.Content}}</article>
+{{if .Backlinks}}<section class="backlinks">
+<h2>Backlinks</h2>
+<ul>{{range .Backlinks}}<li><a href="{{.Href}}">{{.Title}}</a></li>{{end}}</ul>
+</section>{{end}}
+{{if .Updated}}<footer>Updated {{.Updated}}</footer>{{end}}
+</main>
+<script src="{{.Root}}search-index.js"></script>
+<script src="{{.Root}}assets/search.js"></script>
+</body>
+</html>
+`))
+
+// siteCSS stylesheet of the generated site
+// siteCSS 生成站点的样式表
+const siteCSS = `body{margin:0;font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;line-height:1.6;color:#222;background:#fff}
+header{display:flex;align-items:center;justify-content:space-between;gap:1em;padding:.75em 1.5em;border-bottom:1px solid #e5e5e5}
+.site-title{font-weight:600;color:inherit;text-decoration:none}
+.search{position:relative}
+#search{width:16em;padding:.3em .5em;border:1px solid #ccc;border-radius:4px}
+#search-results{position:absolute;right:0;z-index:1;width:24em;margin:.25em 0 0;padding:0;list-style:none;background:#fff;box-shadow:0 2px 8px rgba(0,0,0,.15)}
+#search-results li{padding:.4em .75em;border-bottom:1px solid #eee}
+#search-results small{display:block;color:#777}
+main{max-width:48em;margin:0 auto;padding:1em 1.5em 3em}
+.folder,footer{color:#777;font-size:.9em}
+a{color:#705dcf}
+a.is-unresolved{color:#999;text-decoration:none;cursor:default}
+img,video{max-width:100%}
+pre{overflow:auto;padding:.75em;background:#f6f6f6;border-radius:4px}
+code{background:#f6f6f6;padding:.1em .3em;border-radius:3px}
+blockquote{margin:0;padding-left:1em;border-left:3px solid #ddd;color:#555}
+table{border-collapse:collapse}
+th,td{border:1px solid #ddd;padding:.3em .6em}
+.backlinks{margin-top:3em;padding-top:1em;border-top:1px solid #e5e5e5}
+.backlinks h2{font-size:1em}
+.tree ul{list-style:none;padding-left:1.2em}
+.tree>ul{padding-left:0}
+.tree .folder>span{font-weight:600}
+`
+
+// searchJS client-side search over window.SITE_SEARCH_INDEX
+// searchJS 基于 window.SITE_SEARCH_INDEX 的客户端搜索
+const searchJS = `(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("search-results");
+  var root = document.body.getAttribute("data-root") || "";
+  var index = window.SITE_SEARCH_INDEX || [];
+  input.addEventListener("input", function () {
+    var terms = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    results.innerHTML = "";
+    if (!terms.length) return;
+    var hits = index.filter(function (e) {
+      var hay = (e.title + " " + e.text).toLowerCase();
+      return terms.every(function (t) { return hay.indexOf(t) >= 0; });
+    }).slice(0, 20);
+    hits.forEach(function (e) {
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.href = root + e.url;
+      a.textContent = e.title;
+      li.appendChild(a);
+      if (e.text) {
+        var small = document.createElement("small");
+        small.textContent = e.text.slice(0, 120);
+        li.appendChild(small);
+      }
+      results.appendChild(li);
+    });
+  });
+})();
+`
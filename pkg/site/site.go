@@ -0,0 +1,322 @@
+// Package site renders a set of notes into a static HTML site with an index page, backlinks
+// and a client-side search index, ready to be published by any static file host.
+// Package site 将一组笔记渲染为静态 HTML 站点，包含索引页、反向链接与客户端搜索索引，
+// 可直接由任意静态文件服务器发布。
+package site
+
+import (
+	"bytes"
+	"encoding/json"
+	"html"
+	"html/template"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/markdown"
+	"github.com/haierkeys/fast-note-sync-service/pkg/util"
+)
+
+// searchTextRunes length of the plain text of a page kept in the search index
+// searchTextRunes 搜索索引中每个页面保留的纯文本长度
+const searchTextRunes = 1000
+
+// WriteFunc stores a generated file under its slash separated name
+// WriteFunc 以斜杠分隔的名称保存生成的文件
+type WriteFunc func(name string, data []byte, modTime time.Time) error
+
+// Site collects notes and attachments and renders them
+// Site 收集笔记与附件并进行渲染
+type Site struct {
+	title      string
+	notes      map[string]*page
+	notesByKey map[string][]string
+	files      map[string]bool
+	filesByKey map[string][]string
+}
+
+// page a note to render
+// page 待渲染的笔记
+type page struct {
+	path    string
+	title   string
+	text    string
+	body    string
+	mtime   time.Time
+	backers map[string]bool
+}
+
+// link an anchor on a generated page
+// link 生成页面上的链接
+type link struct {
+	Href  string
+	Title string
+}
+
+// searchEntry an entry of search-index.js
+// searchEntry search-index.js 中的条目
+type searchEntry struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Text  string `json:"text"`
+}
+
+// New creates an empty site
+// New 创建空站点
+func New(title string) *Site {
+	return &Site{
+		title:      title,
+		notes:      make(map[string]*page),
+		notesByKey: make(map[string][]string),
+		files:      make(map[string]bool),
+		filesByKey: make(map[string][]string),
+	}
+}
+
+// AddNote adds a Markdown note, notePath is relative to the site root
+// AddNote 添加 Markdown 笔记，notePath 相对于站点根目录
+func (s *Site) AddNote(notePath string, content string, mtime time.Time) {
+	_, body, _ := util.ParseFrontmatter(content)
+	title, text := util.MarkdownExcerpt(content, searchTextRunes)
+	if title == "" {
+		title = strings.TrimSuffix(path.Base(notePath), path.Ext(notePath))
+	}
+	s.notes[notePath] = &page{path: notePath, title: title, text: text, body: body, mtime: mtime, backers: make(map[string]bool)}
+	key := strings.ToLower(strings.TrimSuffix(path.Base(notePath), ".md"))
+	s.notesByKey[key] = append(s.notesByKey[key], notePath)
+}
+
+// AddAttachment registers an attachment so embeds can point at it, the caller copies its content to filePath
+// AddAttachment 登记附件以便嵌入指向它，附件内容由调用方复制到 filePath
+func (s *Site) AddAttachment(filePath string) {
+	s.files[filePath] = true
+	key := strings.ToLower(path.Base(filePath))
+	s.filesByKey[key] = append(s.filesByKey[key], filePath)
+}
+
+// PageName returns the name of the HTML page generated for a note
+// PageName 返回为笔记生成的 HTML 页面名称
+func PageName(notePath string) string {
+	return strings.TrimSuffix(notePath, ".md") + ".html"
+}
+
+// resolveNote finds the note a wiki link target points to: the path relative to the linking note,
+// the full path, then the shortest path with the same name like Obsidian
+// resolveNote 查找维基链接目标指向的笔记：依次尝试相对于链接所在笔记的路径、完整路径，
+// 以及与 Obsidian 一致的同名最短路径
+func (s *Site) resolveNote(from, target string) (string, bool) {
+	target = strings.TrimPrefix(strings.TrimSuffix(target, ".md"), "/") + ".md"
+	for _, candidate := range []string{path.Join(path.Dir(from), target), target} {
+		if _, ok := s.notes[candidate]; ok {
+			return candidate, true
+		}
+	}
+	return shortest(s.notesByKey[strings.ToLower(strings.TrimSuffix(path.Base(target), ".md"))])
+}
+
+// resolveFile finds the attachment an embed or a local image points to, in the same order as resolveNote
+// resolveFile 查找嵌入或本地图片指向的附件，查找顺序与 resolveNote 相同
+func (s *Site) resolveFile(from, target string) (string, bool) {
+	if strings.Contains(target, "://") {
+		return "", false
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	target = strings.TrimPrefix(target, "/")
+	for _, candidate := range []string{path.Join(path.Dir(from), target), target} {
+		if s.files[candidate] {
+			return candidate, true
+		}
+	}
+	return shortest(s.filesByKey[strings.ToLower(path.Base(target))])
+}
+
+// shortest returns the shortest of the paths, ties broken alphabetically
+// shortest 返回最短的路径，长度相同时按字母序
+func shortest(paths []string) (string, bool) {
+	if len(paths) == 0 {
+		return "", false
+	}
+	best := paths[0]
+	for _, p := range paths[1:] {
+		if len(p) < len(best) || (len(p) == len(best) && p < best) {
+			best = p
+		}
+	}
+	return best, true
+}
+
+// relURL returns the URL of name relative to the directory of the page from
+// relURL 返回 name 相对于页面 from 所在目录的 URL
+func relURL(from, name string) string {
+	dirs := strings.Split(from, "/")
+	dirs = dirs[:len(dirs)-1]
+	segments := strings.Split(name, "/")
+	common := 0
+	for common < len(dirs) && common < len(segments)-1 && dirs[common] == segments[common] {
+		common++
+	}
+	segments = segments[common:]
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Repeat("../", len(dirs)-common) + strings.Join(segments, "/")
+}
+
+// pageResolver resolves the links of one page to relative URLs
+// pageResolver 将单个页面中的链接解析为相对 URL
+type pageResolver struct {
+	site *Site
+	from string
+}
+
+// ResolveLink implements markdown.Resolver
+// ResolveLink 实现 markdown.Resolver
+func (r *pageResolver) ResolveLink(target string) (string, bool) {
+	notePath, ok := r.site.resolveNote(r.from, target)
+	if !ok {
+		return target, false
+	}
+	return relURL(r.from, PageName(notePath)), true
+}
+
+// ResolveEmbed implements markdown.Resolver
+// ResolveEmbed 实现 markdown.Resolver
+func (r *pageResolver) ResolveEmbed(target string) (string, bool) {
+	filePath, ok := r.site.resolveFile(r.from, target)
+	if !ok {
+		return target, false
+	}
+	return relURL(r.from, filePath), true
+}
+
+// Build renders every note, the index page, the search index and the assets through write.
+// Attachments are not written, see AddAttachment.
+// Build 通过 write 输出所有笔记页面、索引页、搜索索引与静态资源，附件不会被写出，参见 AddAttachment。
+func (s *Site) Build(write WriteFunc) (int, error) {
+	paths := make([]string, 0, len(s.notes))
+	for p := range s.notes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	// Backlinks include embedded notes, like in Obsidian
+	// 与 Obsidian 一致，反向链接包含嵌入的笔记
+	latest := time.Time{}
+	for _, from := range paths {
+		p := s.notes[from]
+		if p.mtime.After(latest) {
+			latest = p.mtime
+		}
+		for _, l := range util.ParseWikiLinks(p.body) {
+			target := l.Path
+			if i := strings.Index(target, "#"); i >= 0 {
+				target = target[:i]
+			}
+			if target == "" || markdown.IsAttachment(target) {
+				continue
+			}
+			if to, ok := s.resolveNote(from, target); ok && to != from {
+				s.notes[to].backers[from] = true
+			}
+		}
+	}
+
+	search := make([]searchEntry, 0, len(paths))
+	for _, notePath := range paths {
+		p := s.notes[notePath]
+		name := PageName(notePath)
+		content, err := markdown.Render([]byte(p.body), &pageResolver{site: s, from: notePath})
+		if err != nil {
+			return 0, err
+		}
+
+		backlinks := make([]link, 0, len(p.backers))
+		for from := range p.backers {
+			backlinks = append(backlinks, link{Href: relURL(notePath, PageName(from)), Title: s.notes[from].title})
+		}
+		sort.Slice(backlinks, func(i, j int) bool { return backlinks[i].Title < backlinks[j].Title })
+
+		var buf bytes.Buffer
+		err = pageTemplate.Execute(&buf, map[string]any{
+			"SiteTitle": s.title,
+			"Title":     p.title,
+			"Root":      strings.Repeat("../", strings.Count(notePath, "/")),
+			"Folder":    strings.ReplaceAll(path.Dir(notePath), "/", " / "),
+			"Content":   template.HTML(content),
+			"Backlinks": backlinks,
+			"Updated":   p.mtime.Format("2006-01-02 15:04"),
+		})
+		if err != nil {
+			return 0, err
+		}
+		if err := write(name, buf.Bytes(), p.mtime); err != nil {
+			return 0, err
+		}
+		search = append(search, searchEntry{Title: p.title, URL: relURL("", name), Text: p.text})
+	}
+
+	var buf bytes.Buffer
+	err := pageTemplate.Execute(&buf, map[string]any{
+		"SiteTitle": s.title,
+		"Title":     s.title,
+		"Root":      "",
+		"Content":   template.HTML(s.tree(paths)),
+		"Index":     true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := write("index.html", buf.Bytes(), latest); err != nil {
+		return 0, err
+	}
+
+	// A script rather than JSON, so search also works when the site is opened from disk
+	// 使用脚本而非 JSON，使站点从本地磁盘打开时搜索同样可用
+	index, err := json.Marshal(search)
+	if err != nil {
+		return 0, err
+	}
+	if err := write("search-index.js", append(append([]byte("window.SITE_SEARCH_INDEX = "), index...), ";\n"...), latest); err != nil {
+		return 0, err
+	}
+	if err := write("assets/site.css", []byte(siteCSS), latest); err != nil {
+		return 0, err
+	}
+	if err := write("assets/search.js", []byte(searchJS), latest); err != nil {
+		return 0, err
+	}
+	return len(paths), nil
+}
+
+// tree renders the navigation of the index page as nested lists following the folders
+// tree 将索引页的导航按文件夹渲染为嵌套列表
+func (s *Site) tree(paths []string) string {
+	var b strings.Builder
+	b.WriteString(`<nav class="tree"><ul>`)
+	var open []string
+	for _, notePath := range paths {
+		dirs := strings.Split(notePath, "/")
+		dirs = dirs[:len(dirs)-1]
+
+		common := 0
+		for common < len(open) && common < len(dirs) && open[common] == dirs[common] {
+			common++
+		}
+		for len(open) > common {
+			b.WriteString(`</ul></li>`)
+			open = open[:len(open)-1]
+		}
+		for _, dir := range dirs[common:] {
+			b.WriteString(`<li class="folder"><span>` + html.EscapeString(dir) + `</span><ul>`)
+			open = append(open, dir)
+		}
+		b.WriteString(`<li><a href="` + html.EscapeString(relURL("", PageName(notePath))) + `">` + html.EscapeString(s.notes[notePath].title) + `</a></li>`)
+	}
+	b.WriteString(strings.Repeat(`</ul></li>`, len(open)))
+	b.WriteString(`</ul></nav>`)
+	return b.String()
+}
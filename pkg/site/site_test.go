@@ -0,0 +1,66 @@
+package site
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// build renders a small site and returns the generated files
+// build 渲染一个小型站点并返回生成的文件
+func build(t *testing.T) map[string]string {
+	s := New("My Notes")
+	mtime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	s.AddNote("Home.md", "# Welcome\n\nSee [[Projects/Plan|the plan]] and ![[diagram.png]].\n", mtime)
+	s.AddNote("Projects/Plan.md", "---\ntitle: The Plan\n---\nBack to [[Home]], missing [[Nowhere]].\n\n![local](img/diagram.png)\n", mtime)
+	s.AddNote("Projects/Notes/Log.md", "Links to [[Plan#Goals]].\n", mtime)
+	s.AddAttachment("Projects/img/diagram.png")
+
+	files := map[string]string{}
+	n, err := s.Build(func(name string, data []byte, modTime time.Time) error {
+		files[name] = string(data)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	return files
+}
+
+// TestBuild_Pages verifies every note, the index, the search index and the assets are written.
+// TestBuild_Pages 验证所有笔记、索引页、搜索索引与静态资源均被写出。
+func TestBuild_Pages(t *testing.T) {
+	files := build(t)
+	for _, name := range []string{"Home.html", "Projects/Plan.html", "Projects/Notes/Log.html", "index.html", "search-index.js", "assets/site.css", "assets/search.js"} {
+		assert.Contains(t, files, name)
+	}
+	assert.Contains(t, files["index.html"], `<a href="Projects/Plan.html">The Plan</a>`)
+	assert.Contains(t, files["search-index.js"], `"title":"The Plan","url":"Projects/Plan.html"`)
+	assert.Contains(t, files["Projects/Notes/Log.html"], `href="../../assets/site.css"`)
+}
+
+// TestBuild_Links verifies wiki links, embeds and local images resolve to relative URLs.
+// TestBuild_Links 验证维基链接、嵌入与本地图片被解析为相对 URL。
+func TestBuild_Links(t *testing.T) {
+	files := build(t)
+	assert.Contains(t, files["Home.html"], `href="Projects/Plan.html"`)
+	assert.Contains(t, files["Home.html"], `src="Projects/img/diagram.png"`)
+	assert.Contains(t, files["Projects/Plan.html"], `href="../Home.html"`)
+	assert.Contains(t, files["Projects/Plan.html"], `src="img/diagram.png"`)
+	assert.Contains(t, files["Projects/Plan.html"], `is-unresolved`)
+	assert.Contains(t, files["Projects/Notes/Log.html"], `href="../Plan.html#Goals"`)
+}
+
+// TestBuild_Backlinks verifies backlinks list the linking notes with relative URLs.
+// TestBuild_Backlinks 验证反向链接以相对 URL 列出引用笔记。
+func TestBuild_Backlinks(t *testing.T) {
+	files := build(t)
+	plan := files["Projects/Plan.html"]
+	require.Contains(t, plan, "Backlinks")
+	backlinks := plan[strings.Index(plan, "Backlinks"):]
+	assert.Contains(t, backlinks, `<a href="../Home.html">Welcome</a>`)
+	assert.Contains(t, backlinks, `<a href="Notes/Log.html">Log</a>`)
+	assert.NotContains(t, files["Projects/Notes/Log.html"], "Backlinks")
+}
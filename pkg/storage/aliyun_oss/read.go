@@ -0,0 +1,24 @@
+package aliyun_oss
+
+import (
+	"context"
+	"io"
+	"path"
+
+	"github.com/aliyun/alibabacloud-oss-go-sdk-v2/oss"
+)
+
+// Open read a stored object
+// Open 读取已存储的对象
+func (p *OSS) Open(fileKey string) (io.ReadCloser, error) {
+	fileKey = path.Join(p.Config.CustomPath, fileKey)
+
+	result, err := p.Client.GetObject(context.Background(), &oss.GetObjectRequest{
+		Bucket: oss.Ptr(p.Config.BucketName),
+		Key:    oss.Ptr(fileKey),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Body, nil
+}
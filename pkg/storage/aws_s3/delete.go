@@ -7,6 +7,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 func (p *S3) Delete(fileKey string) error {
@@ -19,3 +20,38 @@ func (p *S3) Delete(fileKey string) error {
 	})
 	return err
 }
+
+// DeletePrefix delete every object below the folder prefix, a list page holds at most as many keys as one batch delete
+// DeletePrefix 删除 prefix 目录下的所有对象，每页列出的键数不超过一次批量删除的上限
+func (p *S3) DeletePrefix(prefix string) error {
+	ctx := context.Background()
+	bucket := p.GetBucket("")
+	// path.Join drops the trailing slash, keep it so f_1/ does not match f_10/
+	// path.Join 会去掉末尾斜杠，需补回以免 f_1/ 匹配到 f_10/
+	prefix = path.Join(p.Config.CustomPath, prefix) + "/"
+
+	paginator := s3.NewListObjectsV2Paginator(p.S3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+		objects := make([]types.ObjectIdentifier, 0, len(page.Contents))
+		for _, obj := range page.Contents {
+			objects = append(objects, types.ObjectIdentifier{Key: obj.Key})
+		}
+		if _, err := p.S3Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package aws_s3
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// List calls fn with the key of every object below the folder prefix, relative to the custom path
+// List 对 prefix 目录下的每个对象调用 fn，传入相对于自定义路径的键
+func (p *S3) List(prefix string, fn func(fileKey string) error) error {
+	ctx := context.Background()
+	bucket := p.GetBucket("")
+	base := path.Join(p.Config.CustomPath)
+	if base != "" && !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	// path.Join drops the trailing slash, keep it so f_1/ does not match f_10/
+	// path.Join 会去掉末尾斜杠，需补回以免 f_1/ 匹配到 f_10/
+	full := path.Join(p.Config.CustomPath, prefix) + "/"
+
+	paginator := s3.NewListObjectsV2Paginator(p.S3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(full),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, obj := range page.Contents {
+			if err := fn(strings.TrimPrefix(aws.ToString(obj.Key), base)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
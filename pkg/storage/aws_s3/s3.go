@@ -2,7 +2,17 @@ package aws_s3
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/transfermanager"
@@ -11,7 +21,9 @@ import (
 )
 
 func cacheKey(conf *Config) string {
-	return conf.AccessKeyID + ":" + conf.AccessKeySecret + ":" + conf.Region
+	ca := sha256.Sum256([]byte(conf.CACert))
+	return conf.AccessKeyID + ":" + conf.AccessKeySecret + ":" + conf.Region + ":" + conf.Endpoint + ":" +
+		strconv.FormatBool(conf.PathStyle) + ":" + strconv.FormatBool(conf.InsecureSkipVerify) + ":" + hex.EncodeToString(ca[:8])
 }
 
 type Config struct {
@@ -20,6 +32,13 @@ type Config struct {
 	AccessKeyID     string `yaml:"access-key-id"`
 	AccessKeySecret string `yaml:"access-key-secret"`
 	CustomPath      string `yaml:"custom-path"`
+
+	// S3-compatible servers (MinIO, Ceph RGW, ...)
+	// S3 兼容服务（MinIO、Ceph RGW 等）
+	Endpoint           string `yaml:"endpoint"`             // Custom endpoint, host[:port] or URL, empty for AWS // 自定义端点，host[:port] 或 URL，AWS 留空
+	PathStyle          bool   `yaml:"path-style"`           // Address buckets as endpoint/bucket instead of bucket.endpoint // 以 endpoint/bucket 而非 bucket.endpoint 方式访问存储桶
+	CACert             string `yaml:"ca-cert"`              // PEM CA bundle trusted in addition to the system roots // 在系统根证书之外额外信任的 PEM CA 证书
+	InsecureSkipVerify bool   `yaml:"insecure-skip-verify"` // Skip TLS certificate verification // 跳过 TLS 证书校验
 }
 
 type S3 struct {
@@ -30,6 +49,77 @@ type S3 struct {
 
 var clients = make(map[string]*S3)
 
+// NormalizeEndpoint validates a custom endpoint and returns it as a URL, https is assumed when the scheme is missing
+// NormalizeEndpoint 校验自定义端点并以 URL 形式返回，未指定协议时默认 https
+func NormalizeEndpoint(endpoint string) (string, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return "", nil
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid endpoint")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", errors.Errorf("invalid endpoint scheme %q", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return "", errors.New("invalid endpoint: missing host")
+	}
+	if port := u.Port(); port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", errors.Errorf("invalid endpoint port %q", port)
+		}
+	} else if strings.HasSuffix(u.Host, ":") {
+		return "", errors.New("invalid endpoint: empty port")
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", errors.New("invalid endpoint: query and fragment are not allowed")
+	}
+	return strings.TrimRight(u.Scheme+"://"+u.Host+u.Path, "/"), nil
+}
+
+// CertPool returns the system roots plus the certificates of a PEM bundle
+// CertPool 返回系统根证书加上 PEM 证书包中的证书
+func CertPool(caCert string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM([]byte(caCert)) {
+		return nil, errors.New("invalid ca cert: no PEM certificate found")
+	}
+	return pool, nil
+}
+
+// httpClient builds an HTTP client honouring the custom CA and TLS verification settings, nil keeps the SDK default
+// httpClient 构建遵循自定义 CA 与 TLS 校验设置的 HTTP 客户端，返回 nil 表示使用 SDK 默认客户端
+func httpClient(conf *Config) (aws.HTTPClient, error) {
+	if strings.TrimSpace(conf.CACert) == "" && !conf.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: conf.InsecureSkipVerify,
+	}
+	if strings.TrimSpace(conf.CACert) != "" {
+		pool, err := CertPool(conf.CACert)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+		tr.TLSClientConfig = tlsConfig
+	}), nil
+}
+
 func NewClient(conf *Config) (*S3, error) {
 	var region = conf.Region
 	var accessKeyId = conf.AccessKeyID
@@ -40,15 +130,34 @@ func NewClient(conf *Config) (*S3, error) {
 		return clients[key], nil
 	}
 
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
+	endpoint, err := NormalizeEndpoint(conf.Endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "aws_s3")
+	}
+	hc, err := httpClient(conf)
+	if err != nil {
+		return nil, errors.Wrap(err, "aws_s3")
+	}
+
+	opts := []func(*config.LoadOptions) error{
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKeyId, accessKeySecret, "")),
 		config.WithRegion(region),
-	)
+	}
+	if hc != nil {
+		opts = append(opts, config.WithHTTPClient(hc))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.TODO(), opts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "aws_s3")
 	}
 
-	client := s3.NewFromConfig(cfg, func(o *s3.Options) {})
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+		o.UsePathStyle = conf.PathStyle
+	})
 
 	clients[key] = &S3{
 		S3Client:        client,
@@ -1,7 +1,15 @@
 package aws_s3
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -25,3 +33,68 @@ func TestNewClient(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, client, client2)
 }
+
+func TestNormalizeEndpoint(t *testing.T) {
+	cases := map[string]string{
+		"":                              "",
+		"minio.local:9000":              "https://minio.local:9000",
+		"http://10.0.0.5:7480/":         "http://10.0.0.5:7480",
+		"https://ceph.example.com/rgw/": "https://ceph.example.com/rgw",
+	}
+	for in, want := range cases {
+		got, err := NormalizeEndpoint(in)
+		assert.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"ftp://minio.local", "minio.local:70000", "minio.local:", "https://:9000", "https://h/?a=b"} {
+		_, err := NormalizeEndpoint(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestNewClientCustomEndpoint(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	_, err = CertPool(caCert)
+	assert.NoError(t, err)
+	_, err = CertPool("not a certificate")
+	assert.Error(t, err)
+
+	config := &Config{
+		Region:          "us-east-1",
+		BucketName:      "test-bucket",
+		AccessKeyID:     "ceph-test-key",
+		AccessKeySecret: "ceph-test-secret",
+		Endpoint:        "ceph.local:7480",
+		PathStyle:       true,
+		CACert:          caCert,
+	}
+	client, err := NewClient(config)
+	assert.NoError(t, err)
+	assert.True(t, client.S3Client.Options().UsePathStyle)
+	assert.Equal(t, "https://ceph.local:7480", *client.S3Client.Options().BaseEndpoint)
+
+	// TLS options are part of the cache key
+	insecure := *config
+	insecure.CACert = ""
+	insecure.InsecureSkipVerify = true
+	client2, err := NewClient(&insecure)
+	assert.NoError(t, err)
+	assert.NotSame(t, client, client2)
+
+	_, err = NewClient(&Config{AccessKeyID: "bad", CACert: "not a certificate"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,13 @@
+package local_fs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Open read a stored file
+// Open 读取已存储的文件
+func (p *LocalFS) Open(fileKey string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(p.getSavePath(), fileKey))
+}
@@ -0,0 +1,26 @@
+package minio
+
+import (
+	"context"
+	"io"
+	"path"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Open read a stored object
+// Open 读取已存储的对象
+func (p *MinIO) Open(fileKey string) (io.ReadCloser, error) {
+	bucket := p.GetBucket("")
+	fileKey = path.Join(p.Config.CustomPath, fileKey)
+
+	out, err := p.S3Client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(fileKey),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/aliyun/alibabacloud-oss-go-sdk-v2/oss"
+	"github.com/studio-b12/gowebdav"
+)
+
+// RetryConfig retry policy of storage uploads and deletes
+// RetryConfig 存储上传与删除的重试策略
+type RetryConfig struct {
+	MaxAttempts int           // Attempts including the first one, <= 1 disables retries // 含首次在内的尝试次数，<= 1 表示不重试
+	BaseDelay   time.Duration // Delay before the first retry, doubled on every further retry // 首次重试前的等待时间，之后每次翻倍
+	MaxDelay    time.Duration // Upper bound of a single delay // 单次等待时间上限
+}
+
+// RetryError is returned when an operation still failed after every attempt
+// RetryError 所有尝试均失败后返回的错误
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error { return e.Err }
+
+// retryableStatus HTTP status codes worth retrying
+// retryableStatus 值得重试的 HTTP 状态码
+var retryableStatus = map[int]bool{
+	http.StatusRequestTimeout:      true,
+	http.StatusTooEarly:            true,
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+}
+
+// IsRetryable reports whether an error of the given storage type is transient
+// IsRetryable 判断指定存储类型的错误是否为临时性错误
+func IsRetryable(cType Type, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	switch cType {
+	case LOCAL:
+		// Local disk errors (permission, no space) do not heal by waiting
+		// 本地磁盘错误（权限、空间不足）不会因等待而恢复
+		return false
+	case S3, R2, MinIO:
+		var se interface{ HTTPStatusCode() int }
+		if errors.As(err, &se) && se.HTTPStatusCode() != 0 {
+			return retryableStatus[se.HTTPStatusCode()]
+		}
+	case OSS:
+		var se *oss.ServiceError
+		if errors.As(err, &se) {
+			return retryableStatus[se.HttpStatusCode()]
+		}
+	case WebDAV:
+		var se gowebdav.StatusError
+		if errors.As(err, &se) {
+			return retryableStatus[se.Status]
+		}
+	}
+
+	return isTransientNetError(err)
+}
+
+// isTransientNetError reports connection level failures shared by every remote storage
+// isTransientNetError 判断所有远程存储共有的连接层临时错误
+func isTransientNetError(err error) bool {
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// retryClient Storager decorator retrying transient failures with exponential backoff
+// retryClient 以指数退避重试临时错误的 Storager 装饰器
+type retryClient struct {
+	Storager
+	cType   Type
+	config  RetryConfig
+	retries atomic.Int64
+	sleep   func(time.Duration)
+}
+
+// NewRetryClient wraps a client so transient failures are retried, see IsRetryable
+// NewRetryClient 包装客户端以重试临时错误，见 IsRetryable
+func NewRetryClient(client Storager, cType Type, config RetryConfig) Storager {
+	if config.MaxAttempts <= 1 {
+		return client
+	}
+	return &retryClient{Storager: client, cType: cType, config: config, sleep: time.Sleep}
+}
+
+// Retries returns how many retries a client made so far, 0 for clients without a retry policy
+// Retries 返回客户端至今的重试次数，未配置重试策略的客户端返回 0
+func Retries(client Storager) int64 {
+	if rc, ok := client.(*retryClient); ok {
+		return rc.retries.Load()
+	}
+	return 0
+}
+
+// delay returns the wait before the given retry (1-based): exponential, capped, with jitter
+// delay 返回第 n 次重试（从 1 开始）前的等待时间：指数增长、有上限、带抖动
+func (c *retryClient) delay(retry int) time.Duration {
+	d := c.config.BaseDelay
+	for i := 1; i < retry && (c.config.MaxDelay <= 0 || d < c.config.MaxDelay); i++ {
+		d *= 2
+	}
+	if c.config.MaxDelay > 0 && d > c.config.MaxDelay {
+		d = c.config.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Half fixed, half random so concurrent uploads do not retry in lockstep
+	// 一半固定一半随机，避免并发上传同步重试
+	return d/2 + rand.N(d/2+1)
+}
+
+// do runs op until it succeeds, fails permanently or runs out of attempts
+// do 执行 op 直至成功、遇到永久性错误或用尽尝试次数
+func (c *retryClient) do(op func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = op(); err == nil {
+			return nil
+		}
+		if attempt >= c.config.MaxAttempts || !IsRetryable(c.cType, err) {
+			if attempt > 1 {
+				return &RetryError{Attempts: attempt, Err: err}
+			}
+			return err
+		}
+		c.retries.Add(1)
+		c.sleep(c.delay(attempt))
+	}
+}
+
+// SendFile retries only when the reader can be rewound, a partially consumed stream cannot be sent again
+// SendFile 仅在读取器可回绕时重试，已部分读取的流无法再次发送
+func (c *retryClient) SendFile(pathKey string, file io.Reader, cType string, modTime time.Time) (string, error) {
+	seeker, ok := file.(io.Seeker)
+	if !ok {
+		return c.Storager.SendFile(pathKey, file, cType, modTime)
+	}
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return c.Storager.SendFile(pathKey, file, cType, modTime)
+	}
+
+	var key string
+	first := true
+	err = c.do(func() error {
+		if !first {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return err
+			}
+		}
+		first = false
+		var sendErr error
+		key, sendErr = c.Storager.SendFile(pathKey, file, cType, modTime)
+		return sendErr
+	})
+	return key, err
+}
+
+func (c *retryClient) SendContent(pathKey string, content []byte, modTime time.Time) (string, error) {
+	var key string
+	err := c.do(func() error {
+		var sendErr error
+		key, sendErr = c.Storager.SendContent(pathKey, content, modTime)
+		return sendErr
+	})
+	return key, err
+}
+
+func (c *retryClient) Delete(pathKey string) error {
+	return c.do(func() error {
+		return c.Storager.Delete(pathKey)
+	})
+}
@@ -0,0 +1,166 @@
+package storage_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haierkeys/fast-note-sync-service/pkg/storage"
+	"github.com/studio-b12/gowebdav"
+)
+
+// flakyStorager fails the first `failures` calls with err, then succeeds
+type flakyStorager struct {
+	failures int
+	err      error
+	calls    int
+	bodies   []string
+}
+
+func (f *flakyStorager) next() error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func (f *flakyStorager) SendFile(pathKey string, file io.Reader, cType string, modTime time.Time) (string, error) {
+	b, _ := io.ReadAll(file)
+	f.bodies = append(f.bodies, string(b))
+	return pathKey, f.next()
+}
+
+func (f *flakyStorager) SendContent(pathKey string, content []byte, modTime time.Time) (string, error) {
+	return pathKey, f.next()
+}
+
+func (f *flakyStorager) Delete(pathKey string) error {
+	return f.next()
+}
+
+// noDelay keeps tests fast, a zero base delay never sleeps
+var noDelay = storage.RetryConfig{MaxAttempts: 3}
+
+func TestRetryClient_RetriesTransientStatus(t *testing.T) {
+	inner := &flakyStorager{failures: 2, err: &os.PathError{Op: "PUT", Path: "a.md", Err: gowebdav.StatusError{Status: 429}}}
+	client := storage.NewRetryClient(inner, storage.WebDAV, noDelay)
+
+	if _, err := client.SendContent("a.md", []byte("x"), time.Now()); err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if inner.calls != 3 {
+		t.Errorf("expected 3 calls, got %d", inner.calls)
+	}
+	if n := storage.Retries(client); n != 2 {
+		t.Errorf("expected 2 retries, got %d", n)
+	}
+}
+
+func TestRetryClient_GivesUpAfterMaxAttempts(t *testing.T) {
+	inner := &flakyStorager{failures: 10, err: gowebdav.StatusError{Status: 503}}
+	client := storage.NewRetryClient(inner, storage.WebDAV, noDelay)
+
+	err := client.Delete("a.md")
+	var re *storage.RetryError
+	if !errors.As(err, &re) {
+		t.Fatalf("expected RetryError, got %v", err)
+	}
+	if re.Attempts != 3 || inner.calls != 3 {
+		t.Errorf("expected 3 attempts, got %d (calls %d)", re.Attempts, inner.calls)
+	}
+	var se gowebdav.StatusError
+	if !errors.As(err, &se) || se.Status != 503 {
+		t.Errorf("expected wrapped status error, got %v", err)
+	}
+}
+
+func TestRetryClient_NoRetryForPermanentErrors(t *testing.T) {
+	inner := &flakyStorager{failures: 1, err: gowebdav.StatusError{Status: 403}}
+	client := storage.NewRetryClient(inner, storage.WebDAV, noDelay)
+	if _, err := client.SendContent("a.md", nil, time.Now()); err == nil {
+		t.Fatal("expected 403 to fail without retry")
+	}
+	if inner.calls != 1 {
+		t.Errorf("expected 1 call, got %d", inner.calls)
+	}
+
+	local := &flakyStorager{failures: 1, err: io.ErrUnexpectedEOF}
+	client = storage.NewRetryClient(local, storage.LOCAL, noDelay)
+	if _, err := client.SendContent("a.md", nil, time.Now()); err == nil {
+		t.Fatal("expected local error to fail without retry")
+	}
+	if local.calls != 1 {
+		t.Errorf("expected local storage never to retry, got %d calls", local.calls)
+	}
+}
+
+func TestRetryClient_SendFileRewinds(t *testing.T) {
+	inner := &flakyStorager{failures: 1, err: io.ErrUnexpectedEOF}
+	client := storage.NewRetryClient(inner, storage.S3, noDelay)
+
+	if _, err := client.SendFile("a.zip", strings.NewReader("payload"), "application/zip", time.Now()); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if len(inner.bodies) != 2 || inner.bodies[1] != "payload" {
+		t.Errorf("expected the full body to be resent, got %q", inner.bodies)
+	}
+
+	// A stream that cannot be rewound is sent once
+	// 无法回绕的流只发送一次
+	stream := &flakyStorager{failures: 1, err: io.ErrUnexpectedEOF}
+	client = storage.NewRetryClient(stream, storage.S3, noDelay)
+	if _, err := client.SendFile("a.zip", io.LimitReader(strings.NewReader("payload"), 7), "", time.Now()); err == nil {
+		t.Fatal("expected non-seekable upload to fail without retry")
+	}
+	if stream.calls != 1 {
+		t.Errorf("expected 1 call, got %d", stream.calls)
+	}
+}
+
+func TestNewRetryClient_Disabled(t *testing.T) {
+	inner := &flakyStorager{}
+	if client := storage.NewRetryClient(inner, storage.S3, storage.RetryConfig{MaxAttempts: 1}); client != storage.Storager(inner) {
+		t.Error("expected the client to be returned unchanged when retries are disabled")
+	}
+	if storage.Retries(inner) != 0 {
+		t.Error("expected 0 retries for a plain client")
+	}
+}
+
+// readableStorager flakyStorager that can read objects back
+type readableStorager struct {
+	flakyStorager
+}
+
+func (r *readableStorager) Open(pathKey string) (io.ReadCloser, error) {
+	if err := r.next(); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(strings.NewReader(pathKey)), nil
+}
+
+func TestOpen_RetriesThroughRetryClient(t *testing.T) {
+	inner := &readableStorager{flakyStorager{failures: 1, err: gowebdav.StatusError{Status: 503}}}
+	client := storage.NewRetryClient(inner, storage.WebDAV, noDelay)
+
+	body, err := storage.Open(client, "backup.zip")
+	if err != nil {
+		t.Fatalf("expected success after retry, got %v", err)
+	}
+	defer body.Close()
+	b, _ := io.ReadAll(body)
+	if string(b) != "backup.zip" || inner.calls != 2 {
+		t.Fatalf("unexpected body %q after %d calls", b, inner.calls)
+	}
+}
+
+func TestOpen_NotSupported(t *testing.T) {
+	client := storage.NewRetryClient(&flakyStorager{}, storage.WebDAV, noDelay)
+	if _, err := storage.Open(client, "backup.zip"); !errors.Is(err, storage.ErrReadNotSupported) {
+		t.Fatalf("expected ErrReadNotSupported, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"errors"
 	"io"
 	"strings"
 	"time"
@@ -55,6 +56,11 @@ type Config struct {
 	AccessKeySecret string `yaml:"access-key-secret"`
 	AccountID       string `yaml:"account-id"` // Cloudflare R2 specific
 
+	// S3-compatible servers behind self-signed TLS (S3)
+	PathStyle          bool   `yaml:"path-style"`
+	CACert             string `yaml:"ca-cert"`
+	InsecureSkipVerify bool   `yaml:"insecure-skip-verify"`
+
 	// WebDAV
 	User     string `yaml:"user"`
 	Password string `yaml:"password"`
@@ -69,6 +75,38 @@ type Storager interface {
 	Delete(pathKey string) error
 }
 
+// Reader is implemented by storages able to read stored objects back, used to verify backups
+// Reader 由可读回已存储对象的存储实现，用于校验备份
+type Reader interface {
+	Open(pathKey string) (io.ReadCloser, error)
+}
+
+// ErrReadNotSupported the storage cannot read stored objects back
+// ErrReadNotSupported 存储不支持读回已存储的对象
+var ErrReadNotSupported = errors.New("storage does not support reading objects")
+
+// Open opens a stored object for reading, retrying transient failures when the client has a retry policy
+// Open 打开已存储的对象用于读取，客户端配置了重试策略时重试临时错误
+func Open(client Storager, pathKey string) (io.ReadCloser, error) {
+	rc, retry := client.(*retryClient)
+	if retry {
+		client = rc.Storager
+	}
+	r, ok := client.(Reader)
+	if !ok {
+		return nil, ErrReadNotSupported
+	}
+	if !retry {
+		return r.Open(pathKey)
+	}
+	var body io.ReadCloser
+	err := rc.do(func() (err error) {
+		body, err = r.Open(pathKey)
+		return err
+	})
+	return body, err
+}
+
 func NewClient(config *Config) (Storager, error) {
 	if config == nil {
 		return nil, code.ErrorInvalidStorageType
@@ -104,11 +142,15 @@ func NewClient(config *Config) (Storager, error) {
 		return cloudflare_r2.NewClient(cfg)
 	} else if cType == S3 {
 		cfg := &aws_s3.Config{
-			Region:          config.Region,
-			BucketName:      config.BucketName,
-			AccessKeyID:     config.AccessKeyID,
-			AccessKeySecret: config.AccessKeySecret,
-			CustomPath:      config.CustomPath,
+			Region:             config.Region,
+			BucketName:         config.BucketName,
+			AccessKeyID:        config.AccessKeyID,
+			AccessKeySecret:    config.AccessKeySecret,
+			CustomPath:         config.CustomPath,
+			Endpoint:           config.Endpoint,
+			PathStyle:          config.PathStyle,
+			CACert:             config.CACert,
+			InsecureSkipVerify: config.InsecureSkipVerify,
 		}
 		return aws_s3.NewClient(cfg)
 	} else if cType == MinIO {
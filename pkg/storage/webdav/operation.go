@@ -12,7 +12,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/haierkeys/fast-note-sync-service/pkg/errors"
+	"github.com/pkg/errors"
 )
 
 func (w *WebDAV) setModifiedTime(pathKey string, modTime time.Time) error {
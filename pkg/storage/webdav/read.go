@@ -0,0 +1,13 @@
+package webdav
+
+import (
+	"io"
+	"path"
+)
+
+// Open read a stored file
+// Open 读取已存储的文件
+func (w *WebDAV) Open(fileKey string) (io.ReadCloser, error) {
+	fileKey = path.Join("/", w.Config.CustomPath, fileKey)
+	return w.Client.ReadStream(fileKey)
+}
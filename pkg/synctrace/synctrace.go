@@ -0,0 +1,178 @@
+// Package synctrace writes verbose sync tracing for selected users to separate files: every WebSocket
+// message, UpdateCheck decision and broadcast fan-out of that user, until the trace expires
+// Package synctrace 将选定用户的详细同步跟踪写入独立文件：该用户的每条 WebSocket 消息、UpdateCheck 判定与
+// 广播扇出，直到跟踪过期
+package synctrace
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Session an active trace of one user
+// Session 一个用户正在进行的跟踪
+type Session struct {
+	UID       int64         // Traced user // 被跟踪的用户
+	Level     zapcore.Level // Lowest level written; debug adds message payloads // 写入的最低级别；debug 额外记录消息内容
+	File      string        // Trace file // 跟踪文件
+	StartedAt time.Time     // Start of the trace // 跟踪开始时间
+	ExpiresAt time.Time     // The trace stops by itself at this time // 跟踪在此时间自动停止
+}
+
+type session struct {
+	Session
+	file   *os.File
+	logger *zap.Logger
+	timer  *time.Timer
+}
+
+// Tracer per-user sync tracing, safe for concurrent use; a nil Tracer traces nobody
+// Tracer 按用户的同步跟踪，可并发使用；nil Tracer 不跟踪任何用户
+type Tracer struct {
+	dir string
+	now func() time.Time
+
+	// active counts the sessions so untraced users skip the lock
+	// active 记录会话数量，使未被跟踪的用户无需加锁
+	active   atomic.Int32
+	mu       sync.RWMutex
+	sessions map[int64]*session
+}
+
+// New creates a Tracer writing its files into dir
+// New 创建将文件写入 dir 的 Tracer
+func New(dir string) *Tracer {
+	return &Tracer{dir: dir, now: time.Now, sessions: make(map[int64]*session)}
+}
+
+// Enable starts tracing uid for ttl at the given level; enabling a traced user restarts the trace
+// with the new level and ttl
+// Enable 以指定级别开始跟踪 uid，持续 ttl；对已在跟踪的用户调用时以新的级别与时长重新开始跟踪
+func (t *Tracer) Enable(uid int64, level zapcore.Level, ttl time.Duration) (Session, error) {
+	if ttl <= 0 {
+		return Session{}, fmt.Errorf("synctrace: ttl must be positive")
+	}
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if s, ok := t.sessions[uid]; ok {
+		t.stopLocked(uid, s, "")
+	}
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return Session{}, err
+	}
+	name := filepath.Join(t.dir, fmt.Sprintf("uid-%d-%s.log", uid, now.Format("20060102-150405")))
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return Session{}, err
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(file), level)
+
+	s := &session{
+		Session: Session{UID: uid, Level: level, File: name, StartedAt: now, ExpiresAt: now.Add(ttl)},
+		file:    file,
+		// Entries racing a stop hit the closed file, their write errors are dropped
+		// 与停止并发的记录会写入已关闭的文件，其写入错误被丢弃
+		logger: zap.New(core, zap.ErrorOutput(zapcore.AddSync(io.Discard))).With(zap.Int64("uid", uid)),
+	}
+	s.timer = time.AfterFunc(ttl, func() { t.expire(uid, s) })
+	t.sessions[uid] = s
+	t.active.Add(1)
+
+	s.logger.Info("sync trace started", zap.Stringer("level", level), zap.Time("expiresAt", s.ExpiresAt))
+	return s.Session, nil
+}
+
+// Disable stops tracing uid, reports whether a trace was running
+// Disable 停止跟踪 uid，返回是否存在进行中的跟踪
+func (t *Tracer) Disable(uid int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.sessions[uid]
+	if ok {
+		t.stopLocked(uid, s, "sync trace stopped")
+	}
+	return ok
+}
+
+// expire ends the session when its timer fires, unless it was replaced or stopped meanwhile
+// expire 在计时器触发时结束会话，期间已被替换或停止的会话除外
+func (t *Tracer) expire(uid int64, s *session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessions[uid] == s {
+		t.stopLocked(uid, s, "sync trace expired")
+	}
+}
+
+// stopLocked removes and closes a session, writing a final entry when msg is set
+// stopLocked 移除并关闭会话，msg 非空时写入最后一条记录
+func (t *Tracer) stopLocked(uid int64, s *session, msg string) {
+	s.timer.Stop()
+	if msg != "" {
+		s.logger.Info(msg)
+	}
+	_ = s.logger.Sync()
+	_ = s.file.Close()
+	delete(t.sessions, uid)
+	t.active.Add(-1)
+}
+
+// Logger returns the trace logger of uid, nil when the user is not traced
+// Logger 返回 uid 的跟踪日志器，用户未被跟踪时为 nil
+func (t *Tracer) Logger(uid int64) *zap.Logger {
+	if t == nil || t.active.Load() == 0 {
+		return nil
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	s, ok := t.sessions[uid]
+	if !ok || !t.now().Before(s.ExpiresAt) {
+		return nil
+	}
+	return s.logger
+}
+
+// List returns the active traces ordered by uid
+// List 返回进行中的跟踪，按 uid 排序
+func (t *Tracer) List() []Session {
+	if t == nil {
+		return nil
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	list := make([]Session, 0, len(t.sessions))
+	for _, s := range t.sessions {
+		list = append(list, s.Session)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].UID < list[j].UID })
+	return list
+}
+
+// Close stops every trace
+// Close 停止所有跟踪
+func (t *Tracer) Close() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for uid, s := range t.sessions {
+		t.stopLocked(uid, s, "sync trace stopped")
+	}
+}
@@ -0,0 +1,60 @@
+package synctrace
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestTracer_EnableLogDisable(t *testing.T) {
+	tracer := New(t.TempDir())
+	assert.Nil(t, tracer.Logger(7))
+
+	session, err := tracer.Enable(7, zapcore.InfoLevel, time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, tracer.Logger(8))
+
+	log := tracer.Logger(7)
+	require.NotNil(t, log)
+	log.Info("UpdateCheck", zap.String("decision", "UpdateContent"))
+	log.Debug("payload left out at info level")
+
+	assert.Len(t, tracer.List(), 1)
+	assert.True(t, tracer.Disable(7))
+	assert.False(t, tracer.Disable(7))
+	assert.Nil(t, tracer.Logger(7))
+	assert.Empty(t, tracer.List())
+
+	content, err := os.ReadFile(session.File)
+	require.NoError(t, err)
+	text := string(content)
+	assert.Contains(t, text, `"decision":"UpdateContent"`)
+	assert.Contains(t, text, `"uid":7`)
+	assert.Contains(t, text, "sync trace stopped")
+	assert.NotContains(t, text, "payload left out")
+
+	var nilTracer *Tracer
+	assert.Nil(t, nilTracer.Logger(7))
+}
+
+func TestTracer_Expiry(t *testing.T) {
+	tracer := New(t.TempDir())
+	session, err := tracer.Enable(3, zapcore.DebugLevel, 20*time.Millisecond)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return len(tracer.List()) == 0 }, time.Second, 5*time.Millisecond)
+	assert.Nil(t, tracer.Logger(3))
+
+	content, err := os.ReadFile(session.File)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(content), "sync trace expired"))
+
+	_, err = tracer.Enable(3, zapcore.DebugLevel, 0)
+	assert.Error(t, err)
+}
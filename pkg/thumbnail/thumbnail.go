@@ -0,0 +1,146 @@
+// Package thumbnail renders downscaled previews of images
+// Package thumbnail 生成图片的缩小预览图
+package thumbnail
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// MaxPixels largest source image, in pixels, that is decoded; larger images are refused
+// to keep a single request from allocating gigabytes of memory
+// MaxPixels 允许解码的最大源图片像素数；超出的图片会被拒绝，避免单个请求占用数 GB 内存
+const MaxPixels = 64 << 20
+
+// jpegQuality quality of JPEG thumbnails
+// jpegQuality JPEG 缩略图的质量
+const jpegQuality = 82
+
+// ErrUnsupported the file is not an image format thumbnails can be rendered for
+// ErrUnsupported 文件不是可生成缩略图的图片格式
+var ErrUnsupported = errors.New("thumbnail: unsupported image format")
+
+// ErrTooLarge the source image exceeds MaxPixels
+// ErrTooLarge 源图片超过 MaxPixels
+var ErrTooLarge = errors.New("thumbnail: image too large")
+
+// Format output format of a thumbnail
+// Format 缩略图的输出格式
+type Format struct {
+	Ext         string // File extension including the dot // 含点号的文件扩展名
+	ContentType string // MIME type // MIME 类型
+}
+
+var (
+	formatJPEG = Format{Ext: ".jpg", ContentType: "image/jpeg"}
+	formatPNG  = Format{Ext: ".png", ContentType: "image/png"}
+)
+
+// sourceFormat decoder of a source extension and the format its thumbnails are written in.
+// Formats that may carry transparency keep it by rendering to PNG.
+// sourceFormat 源扩展名对应的解码器及其缩略图的输出格式，可能带透明通道的格式输出为 PNG 以保留透明度。
+type sourceFormat struct {
+	decode       func(io.Reader) (image.Image, error)
+	decodeConfig func(io.Reader) (image.Config, error)
+	output       Format
+}
+
+var sourceFormats = map[string]sourceFormat{
+	".jpg":  {jpeg.Decode, jpeg.DecodeConfig, formatJPEG},
+	".jpeg": {jpeg.Decode, jpeg.DecodeConfig, formatJPEG},
+	".bmp":  {bmp.Decode, bmp.DecodeConfig, formatJPEG},
+	".png":  {png.Decode, png.DecodeConfig, formatPNG},
+	".gif":  {gif.Decode, gif.DecodeConfig, formatPNG},
+	".webp": {webp.Decode, webp.DecodeConfig, formatPNG},
+}
+
+func lookup(name string) (sourceFormat, bool) {
+	f, ok := sourceFormats[strings.ToLower(filepath.Ext(name))]
+	return f, ok
+}
+
+// Supported reports whether thumbnails can be rendered for a file with the given name
+// Supported 判断是否可以为给定文件名的文件生成缩略图
+func Supported(name string) bool {
+	_, ok := lookup(name)
+	return ok
+}
+
+// OutputFormat returns the format thumbnails of the named file are written in
+// OutputFormat 返回给定文件的缩略图输出格式
+func OutputFormat(name string) (Format, error) {
+	f, ok := lookup(name)
+	if !ok {
+		return Format{}, ErrUnsupported
+	}
+	return f.output, nil
+}
+
+// Render decodes the image named name from src and writes a preview at most width pixels wide to dst,
+// keeping the aspect ratio. Images narrower than width are re-encoded at their own size.
+// Only the first frame of animated images is used.
+// Render 从 src 解码名为 name 的图片，并将宽度不超过 width 像素、保持宽高比的预览图写入 dst。
+// 宽度小于 width 的图片按原尺寸重新编码，动图仅使用第一帧。
+func Render(src io.ReadSeeker, name string, width int, dst io.Writer) error {
+	f, ok := lookup(name)
+	if !ok {
+		return ErrUnsupported
+	}
+	if width <= 0 {
+		return fmt.Errorf("thumbnail: invalid width %d", width)
+	}
+
+	cfg, err := f.decodeConfig(src)
+	if err != nil {
+		return fmt.Errorf("thumbnail: decode config: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return fmt.Errorf("thumbnail: invalid image size %dx%d", cfg.Width, cfg.Height)
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return ErrTooLarge
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	img, err := f.decode(src)
+	if err != nil {
+		return fmt.Errorf("thumbnail: decode: %w", err)
+	}
+
+	out := Scale(img, width)
+	switch f.output {
+	case formatJPEG:
+		return jpeg.Encode(dst, out, &jpeg.Options{Quality: jpegQuality})
+	default:
+		return png.Encode(dst, out)
+	}
+}
+
+// Scale returns img downscaled to width pixels, keeping the aspect ratio; narrower images are returned as is
+// Scale 返回按宽高比缩小到 width 像素宽的图片，宽度不足的图片原样返回
+func Scale(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= width {
+		return img
+	}
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(out, out.Bounds(), img, b, draw.Src, nil)
+	return out
+}
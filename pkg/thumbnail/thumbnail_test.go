@@ -0,0 +1,65 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, 0, color.NRGBA{R: 255, A: 128})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestSupported(t *testing.T) {
+	assert.True(t, Supported("Attachments/Photo.JPG"))
+	assert.True(t, Supported("a.webp"))
+	assert.False(t, Supported("a.svg"))
+	assert.False(t, Supported("README"))
+
+	f, err := OutputFormat("a.gif")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", f.ContentType)
+	_, err = OutputFormat("a.pdf")
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestRender_Downscales(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, Render(bytes.NewReader(encodePNG(t, 400, 200)), "a.png", 100, &out))
+
+	img, err := png.Decode(&out)
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), img.Bounds())
+}
+
+func TestRender_KeepsSmallImages(t *testing.T) {
+	var src bytes.Buffer
+	require.NoError(t, jpeg.Encode(&src, image.NewRGBA(image.Rect(0, 0, 40, 30)), nil))
+
+	var out bytes.Buffer
+	require.NoError(t, Render(bytes.NewReader(src.Bytes()), "a.jpeg", 256, &out))
+
+	img, err := jpeg.Decode(&out)
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 40, 30), img.Bounds())
+}
+
+func TestRender_Errors(t *testing.T) {
+	var out bytes.Buffer
+	assert.ErrorIs(t, Render(bytes.NewReader(nil), "a.txt", 100, &out), ErrUnsupported)
+	assert.Error(t, Render(bytes.NewReader([]byte("not an image")), "a.png", 100, &out))
+	assert.Error(t, Render(bytes.NewReader(encodePNG(t, 4, 4)), "a.png", 0, &out))
+}
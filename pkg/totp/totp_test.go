@@ -0,0 +1,64 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA1 seed from RFC 6238 Appendix B ("12345678901234567890")
+// rfcSecret 为 RFC 6238 附录 B 中的 SHA1 种子
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestGenerateCode_RFC6238Vectors(t *testing.T) {
+	// RFC vectors are 8 digits; the 6-digit code is the last 6 digits
+	// RFC 向量为 8 位；6 位验证码取其后 6 位
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for ts, want := range cases {
+		got, err := GenerateCode(rfcSecret, Counter(time.Unix(ts, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, "ts=%d", ts)
+	}
+}
+
+func TestValidate_Skew(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	prev, _ := GenerateCode(secret, Counter(now)-1)
+	old, _ := GenerateCode(secret, Counter(now)-3)
+
+	counter, ok := Validate(secret, prev, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Counter(now)-1, counter)
+
+	_, ok = Validate(secret, old, now, 1)
+	assert.False(t, ok)
+
+	_, ok = Validate(secret, "12345", now, 1)
+	assert.False(t, ok)
+}
+
+func TestValidate_LowercaseSecret(t *testing.T) {
+	now := time.Unix(59, 0)
+	_, ok := Validate(strings.ToLower(rfcSecret), "287082", now, 0)
+	assert.True(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Fast Note Sync", "alice@example.com", "JBSWY3DPEHPK3PXP")
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Fast%20Note%20Sync:alice@example.com?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=Fast+Note+Sync")
+	assert.Contains(t, uri, "digits=6")
+}
@@ -0,0 +1,83 @@
+package tracer
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	gormSpanKey        = "otel:span"
+	callbackBeforeName = "otel:before"
+	callbackAfterName  = "otel:after"
+)
+
+// GormPlugin creates a span for every query issued with a context carrying a span,
+// queries of background work without a parent span are not traced.
+// Only the SQL text is recorded, bound values may contain note content.
+// GormPlugin 为每个携带链路上下文的查询创建链路，无父链路的后台查询不追踪。
+// 只记录 SQL 文本，绑定值可能包含笔记内容。
+type GormPlugin struct{}
+
+var _ gorm.Plugin = (*GormPlugin)(nil)
+
+func (p *GormPlugin) Name() string {
+	return "otel"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	errs := []error{
+		cb.Create().Before("gorm:create").Register(callbackBeforeName, gormBefore("gorm.Create")),
+		cb.Query().Before("gorm:query").Register(callbackBeforeName, gormBefore("gorm.Query")),
+		cb.Update().Before("gorm:update").Register(callbackBeforeName, gormBefore("gorm.Update")),
+		cb.Delete().Before("gorm:delete").Register(callbackBeforeName, gormBefore("gorm.Delete")),
+		cb.Row().Before("gorm:row").Register(callbackBeforeName, gormBefore("gorm.Row")),
+		cb.Raw().Before("gorm:raw").Register(callbackBeforeName, gormBefore("gorm.Raw")),
+		cb.Create().After("gorm:create").Register(callbackAfterName, gormAfter),
+		cb.Query().After("gorm:query").Register(callbackAfterName, gormAfter),
+		cb.Update().After("gorm:update").Register(callbackAfterName, gormAfter),
+		cb.Delete().After("gorm:delete").Register(callbackAfterName, gormAfter),
+		cb.Row().After("gorm:row").Register(callbackAfterName, gormAfter),
+		cb.Raw().After("gorm:raw").Register(callbackAfterName, gormAfter),
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func gormBefore(name string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+			return
+		}
+		_, span := Tracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func gormAfter(db *gorm.DB) {
+	v, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("db.system.name", db.Dialector.Name()),
+		attribute.String("db.collection.name", db.Statement.Table),
+		attribute.String("db.query.text", db.Statement.SQL.String()),
+		attribute.Int64("db.response.returned_rows", db.Statement.RowsAffected),
+	)
+	if db.Error == gorm.ErrRecordNotFound {
+		span.End()
+		return
+	}
+	End(span, db.Error)
+}
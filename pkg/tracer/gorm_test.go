@@ -0,0 +1,50 @@
+package tracer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+)
+
+type tracedRow struct {
+	ID   int64
+	Name string
+}
+
+// TestGormPlugin verifies queries under a span are traced without their bound values, orphan queries are not
+func TestGormPlugin(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(&GormPlugin{}))
+	require.NoError(t, db.AutoMigrate(&tracedRow{}))
+	require.NoError(t, db.Create(&tracedRow{Name: "secret"}).Error)
+	assert.Empty(t, exporter.GetSpans(), "no parent span")
+
+	ctx, span := Start(context.Background(), "NoteService.List")
+	var rows []tracedRow
+	require.NoError(t, db.WithContext(ctx).Where("name = ?", "secret").Find(&rows).Error)
+	span.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "gorm.Query", spans[0].Name)
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	for _, attr := range spans[0].Attributes {
+		if attr.Key == "db.query.text" {
+			assert.Contains(t, attr.Value.AsString(), "name = ?")
+			assert.NotContains(t, attr.Value.AsString(), "secret")
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package tracer
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName name of the tracer creating the spans of this service
+// instrumentationName 创建本服务链路的 tracer 名称
+const instrumentationName = "github.com/haierkeys/fast-note-sync-service"
+
+// Span attribute keys shared by handlers, services and repositories
+// 处理器、服务与仓储共用的链路属性键
+const (
+	AttrUID         = attribute.Key("app.uid")
+	AttrVault       = attribute.Key("app.vault")
+	AttrVaultID     = attribute.Key("app.vault_id")
+	AttrStorageType = attribute.Key("app.storage_type")
+)
+
+// OTLPOptions OTLP/HTTP exporter options
+// OTLPOptions OTLP/HTTP 导出器选项
+type OTLPOptions struct {
+	Endpoint       string            // Collector host:port // 采集器 host:port
+	URLPath        string            // Trace receiver path, default /v1/traces // 链路接收路径，默认 /v1/traces
+	Insecure       bool              // Plain HTTP instead of HTTPS // 使用 HTTP 而非 HTTPS
+	Headers        map[string]string // Extra request headers // 额外请求头
+	ServiceName    string            // service.name resource attribute // service.name 资源属性
+	ServiceVersion string            // service.version resource attribute // service.version 资源属性
+	SampleRatio    float64           // Share of new traces recorded, <= 0 or >= 1 records all // 新链路记录比例，<= 0 或 >= 1 时全部记录
+}
+
+// NewOTLPProvider creates a tracer provider exporting spans over OTLP/HTTP and installs it
+// together with the W3C trace context propagator as the global OpenTelemetry provider.
+// The caller shuts the provider down on exit to flush the buffered spans.
+// NewOTLPProvider 创建通过 OTLP/HTTP 导出链路的 tracer provider，并与 W3C trace context 传播器
+// 一起设置为全局 OpenTelemetry provider。调用方需在退出时关闭 provider 以刷新缓冲的链路。
+func NewOTLPProvider(ctx context.Context, opts OTLPOptions) (*sdktrace.TracerProvider, error) {
+	exporterOpts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(opts.Endpoint),
+	}
+	if opts.URLPath != "" {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithURLPath(opts.URLPath))
+	}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+	}
+	if len(opts.Headers) > 0 {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithHeaders(opts.Headers))
+	}
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+		semconv.ServiceVersion(opts.ServiceVersion),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if opts.SampleRatio > 0 && opts.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(opts.SampleRatio)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider, nil
+}
+
+// Tracer returns the tracer of this service, a no-op tracer until NewOTLPProvider was called
+// Tracer 返回本服务的 tracer，调用 NewOTLPProvider 之前为空实现
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts an internal span as child of the span in ctx
+// Start 以 ctx 中的链路为父级开始一个内部链路
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if any, and ends it
+// End 记录链路上的错误（如有）并结束链路
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceID returns the OpenTelemetry trace ID of the span in ctx, empty without a valid span
+// TraceID 返回 ctx 中链路的 OpenTelemetry trace ID，无有效链路时为空
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// UID user ID attribute
+// UID 用户 ID 属性
+func UID(uid int64) attribute.KeyValue {
+	return AttrUID.Int64(uid)
+}
+
+// Vault vault name attribute
+// Vault 仓库名称属性
+func Vault(name string) attribute.KeyValue {
+	return AttrVault.String(strings.TrimSpace(name))
+}
+
+// VaultID vault ID attribute
+// VaultID 仓库 ID 属性
+func VaultID(id int64) attribute.KeyValue {
+	return AttrVaultID.Int64(id)
+}
+
+// StorageType storage type attribute
+// StorageType 存储类型属性
+func StorageType(storageType string) attribute.KeyValue {
+	return AttrStorageType.String(storageType)
+}
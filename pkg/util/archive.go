@@ -1,6 +1,7 @@
 package util
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -122,3 +123,36 @@ func ZipBytes(files map[string][]byte, target string) error {
 
 	return nil
 }
+
+// VerifyZip reads every file of a zip archive back, checking its CRC32, and returns the number of files read.
+// A password is only needed for encrypted archives.
+// VerifyZip 读回 zip 归档中的每个文件并校验 CRC32，返回读取的文件数。
+// 仅加密归档需要密码。
+func VerifyZip(source, password string) (int, error) {
+	reader, err := zip.OpenReader(source)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	count := 0
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if f.IsEncrypted() {
+			f.SetPassword(password)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return count, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		_, err = io.Copy(io.Discard, rc)
+		rc.Close()
+		if err != nil {
+			return count, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		count++
+	}
+	return count, nil
+}
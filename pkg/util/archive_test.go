@@ -0,0 +1,35 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyZip(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "dir"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "a.md"), []byte("note a"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "dir", "b.md"), []byte("note b"), 0o644))
+
+	target := filepath.Join(t.TempDir(), "backup.zip")
+	require.NoError(t, ZipWithPassword(src, target, "secret"))
+
+	count, err := VerifyZip(target, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = VerifyZip(target, "wrong")
+	assert.Error(t, err, "wrong password must fail")
+
+	// Truncated archives cannot be opened
+	// 截断的归档无法打开
+	data, err := os.ReadFile(target)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(target, data[:len(data)/2], 0o644))
+	_, err = VerifyZip(target, "secret")
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+// Package util provides common utility functions
+// Package util 提供通用工具函数
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// changelogBulletRegex matches a top-level list item of a release note
+// changelogBulletRegex 匹配发布说明中的顶层列表项
+var changelogBulletRegex = regexp.MustCompile(`^(?:[-*+]|\d+[.)])[ \t]+`)
+
+// ChangelogSection is one titled group of entries of a release note
+// ChangelogSection 发布说明中带标题的一组条目
+type ChangelogSection struct {
+	Title string   `json:"title"` // Section heading, empty for entries before the first heading // 分组标题，首个标题之前的条目为空
+	Items []string `json:"items"` // Entries of the section // 分组内的条目
+}
+
+// ParseChangelog splits a Markdown release note into sections by heading.
+// Every top-level list item and every paragraph becomes one entry; continuation lines and nested
+// items are folded into the entry above them. Fenced code blocks are skipped.
+// ParseChangelog 按标题将 Markdown 发布说明拆分为若干分组。
+// 每个顶层列表项和每个段落为一个条目；续行与嵌套列表项并入上方条目，围栏代码块会被跳过。
+func ParseChangelog(body string) []ChangelogSection {
+	var sections []ChangelogSection
+	current := ChangelogSection{}
+	inItem := false
+	inFence := false
+
+	flush := func() {
+		if len(current.Items) > 0 {
+			sections = append(sections, current)
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		if isMarkdownFence(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if m := markdownHeadingRegex.FindStringSubmatch(line); m != nil {
+			flush()
+			current = ChangelogSection{Title: strings.TrimSpace(m[2])}
+			inItem = false
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			inItem = false
+			continue
+		}
+		if trimmed == "---" || trimmed == "***" {
+			continue
+		}
+
+		indented := line[0] == ' ' || line[0] == '\t'
+		switch loc := changelogBulletRegex.FindStringIndex(line); {
+		case loc != nil:
+			current.Items = append(current.Items, strings.TrimSpace(line[loc[1]:]))
+		case (inItem || indented) && len(current.Items) > 0:
+			last := len(current.Items) - 1
+			current.Items[last] += " " + changelogBulletRegex.ReplaceAllString(trimmed, "")
+		default:
+			current.Items = append(current.Items, trimmed)
+		}
+		inItem = true
+	}
+	flush()
+	return sections
+}
@@ -0,0 +1,21 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseChangelog(t *testing.T) {
+	body := "Highlights of this release.\r\n\r\n## Features\n- Add trash API\n  with bulk empty\n  - nested detail\n* Add changelog\n\n### Bug Fixes\n1. Fix restore\n```\n- not an item\n```\n\n## Empty\n"
+	want := []ChangelogSection{
+		{Title: "", Items: []string{"Highlights of this release."}},
+		{Title: "Features", Items: []string{"Add trash API with bulk empty nested detail", "Add changelog"}},
+		{Title: "Bug Fixes", Items: []string{"Fix restore"}},
+	}
+	if got := ParseChangelog(body); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseChangelog() = %#v, want %#v", got, want)
+	}
+	if got := ParseChangelog(""); got != nil {
+		t.Errorf("ParseChangelog(\"\") = %#v, want nil", got)
+	}
+}
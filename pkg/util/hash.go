@@ -1,8 +1,11 @@
 package util
 
 import (
+	"bufio"
 	"crypto/md5"
 	"encoding/hex"
+	"io"
+	"os"
 	"strconv"
 	"unicode/utf16"
 )
@@ -71,3 +74,49 @@ func EncodeHash32Bytes(data []byte) string {
 	}
 	return strconv.Itoa(int(hash))
 }
+
+// EncodeHash32File computes the same hash as EncodeHash32Bytes for a file on disk
+// without loading it into memory
+// EncodeHash32File 对磁盘文件计算与 EncodeHash32Bytes 相同的哈希，无需将文件整体读入内存
+func EncodeHash32File(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	size := info.Size()
+
+	var hash int32 = 0
+	feed := func(r io.Reader) error {
+		br := bufio.NewReader(r)
+		for {
+			b, err := br.ReadByte()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			hash = (hash << 5) - hash + int32(b)
+		}
+	}
+
+	if size <= FileHashThreshold {
+		if err := feed(f); err != nil {
+			return "", err
+		}
+	} else {
+		if err := feed(io.NewSectionReader(f, 0, FileHashSliceSize)); err != nil {
+			return "", err
+		}
+		if err := feed(io.NewSectionReader(f, size-FileHashSliceSize, FileHashSliceSize)); err != nil {
+			return "", err
+		}
+	}
+	return strconv.Itoa(int(hash)), nil
+}
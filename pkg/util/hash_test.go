@@ -1,6 +1,8 @@
 package util
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -69,3 +71,23 @@ func TestHashBytesConsistency(t *testing.T) {
 		}
 	})
 }
+
+func TestHashFileMatchesBytes(t *testing.T) {
+	for _, size := range []int{0, 5, FileHashThreshold + 3} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i * 7)
+		}
+		p := filepath.Join(t.TempDir(), "f.bin")
+		if err := os.WriteFile(p, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := EncodeHash32File(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := EncodeHash32Bytes(data); got != want {
+			t.Errorf("size %d: EncodeHash32File = %v, want %v", size, got, want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Package util provides common utility functions
+// Package util 提供通用工具函数
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	excerptImageRegex    = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)|!\[\[[^\]]*\]\]`)
+	excerptLinkRegex     = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	excerptWikiRegex     = regexp.MustCompile(`\[\[([^\]|]*)(?:\|([^\]]*))?\]\]`)
+	excerptHTMLTagRegex  = regexp.MustCompile(`<[^>]+>`)
+	excerptEmphasisRegex = regexp.MustCompile("[*_~`=]{1,3}")
+	excerptListRegex     = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?`)
+)
+
+// MarkdownExcerpt returns the title and a plain-text excerpt of a Markdown document.
+// The title is the frontmatter "title" or the first heading; the excerpt is the text of the
+// following paragraphs with Markdown syntax stripped, cut to at most maxRunes runes.
+// MarkdownExcerpt 返回 Markdown 文档的标题与纯文本摘要。
+// 标题取 frontmatter 的 "title" 或第一个标题；摘要为其后段落去除 Markdown 语法后的文本，最多 maxRunes 个字符。
+func MarkdownExcerpt(content string, maxRunes int) (title string, excerpt string) {
+	yamlData, body, _ := ParseFrontmatter(content)
+	if t, ok := yamlData["title"].(string); ok {
+		title = strings.TrimSpace(t)
+	}
+
+	var parts []string
+	length := 0
+	inFence := false
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		if isMarkdownFence(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if m := markdownHeadingRegex.FindStringSubmatch(line); m != nil {
+			if title == "" && len(parts) == 0 {
+				title = plainMarkdownText(m[2])
+			}
+			continue
+		}
+
+		text := plainMarkdownText(strings.TrimLeft(excerptListRegex.ReplaceAllString(line, ""), "> \t"))
+		if text == "" || text == "---" {
+			continue
+		}
+		parts = append(parts, text)
+		length += len([]rune(text)) + 1
+		if maxRunes > 0 && length > maxRunes {
+			break
+		}
+	}
+
+	excerpt = strings.Join(parts, " ")
+	if runes := []rune(excerpt); maxRunes > 0 && len(runes) > maxRunes {
+		excerpt = strings.TrimSpace(string(runes[:maxRunes])) + "…"
+	}
+	return title, excerpt
+}
+
+// plainMarkdownText strips inline Markdown syntax from a single line
+// plainMarkdownText 去除单行文本中的行内 Markdown 语法
+func plainMarkdownText(s string) string {
+	s = excerptImageRegex.ReplaceAllString(s, "")
+	s = excerptLinkRegex.ReplaceAllString(s, "$1")
+	s = excerptWikiRegex.ReplaceAllStringFunc(s, func(m string) string {
+		sub := excerptWikiRegex.FindStringSubmatch(m)
+		if sub[2] != "" {
+			return sub[2]
+		}
+		return sub[1]
+	})
+	s = excerptHTMLTagRegex.ReplaceAllString(s, "")
+	s = excerptEmphasisRegex.ReplaceAllString(s, "")
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestMarkdownExcerpt(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		max         int
+		wantTitle   string
+		wantExcerpt string
+	}{
+		{
+			name:        "heading and paragraph",
+			content:     "# Projects\n\nActive **work** lives in [[Roadmap|the roadmap]] and [docs](http://x).\n\n![img](a.png)\n- first item\n",
+			max:         200,
+			wantTitle:   "Projects",
+			wantExcerpt: "Active work lives in the roadmap and docs. first item",
+		},
+		{
+			name:        "frontmatter title wins and code is skipped",
+			content:     "---\ntitle: Archive\n---\n# Ignored\n```\ncode\n```\nOld stuff",
+			max:         200,
+			wantTitle:   "Archive",
+			wantExcerpt: "Old stuff",
+		},
+		{
+			name:        "truncated",
+			content:     "abcdefghij klmnop",
+			max:         5,
+			wantTitle:   "",
+			wantExcerpt: "abcde…",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, excerpt := MarkdownExcerpt(tt.content, tt.max)
+			if title != tt.wantTitle || excerpt != tt.wantExcerpt {
+				t.Errorf("MarkdownExcerpt() = %q, %q; want %q, %q", title, excerpt, tt.wantTitle, tt.wantExcerpt)
+			}
+		})
+	}
+}
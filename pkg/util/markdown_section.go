@@ -0,0 +1,182 @@
+// Package util provides common utility functions
+// Package util 提供通用工具函数
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// markdownHeadingRegex matches ATX headings; group 1 is the level marker, group 2 the text
+// markdownHeadingRegex 匹配 ATX 标题；分组 1 为级别标记，分组 2 为标题文本
+var markdownHeadingRegex = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)(?:[ \t]+#+)?[ \t]*$`)
+
+// ExtractMarkdownSection returns the part of content addressed by an Obsidian link anchor
+// (the text after "#" in [[Note#Heading]] or [[Note#^block-id]]).
+// A heading anchor returns the heading line plus everything up to the next heading of the same
+// or higher level; a block anchor returns the line tagged with "^block-id", without the tag.
+// Nested heading anchors such as "H1#H2" use the last segment. Headings inside fenced code
+// blocks are ignored and matching is case-insensitive.
+// ExtractMarkdownSection 返回 Obsidian 链接锚点（[[Note#Heading]] 或 [[Note#^block-id]] 中 "#" 之后的部分）
+// 所指向的内容片段。
+// 标题锚点返回该标题行及其后直到下一个同级或更高级标题之前的内容；块锚点返回带 "^block-id" 标记的行（去掉标记）。
+// 形如 "H1#H2" 的嵌套标题锚点取最后一段。围栏代码块内的标题会被忽略，匹配不区分大小写。
+func ExtractMarkdownSection(content string, anchor string) (string, bool) {
+	if i := strings.LastIndex(anchor, "#"); i != -1 {
+		anchor = anchor[i+1:]
+	}
+	anchor = strings.TrimSpace(anchor)
+	if anchor == "" {
+		return "", false
+	}
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	if strings.HasPrefix(anchor, "^") {
+		marker := anchor
+		for _, line := range lines {
+			trimmed := strings.TrimRight(line, " \t")
+			if trimmed == marker || strings.HasSuffix(trimmed, " "+marker) {
+				return strings.TrimSpace(strings.TrimSuffix(trimmed, marker)), true
+			}
+		}
+		return "", false
+	}
+
+	start, level := -1, 0
+	inFence := false
+	for i, line := range lines {
+		if isMarkdownFence(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		m := markdownHeadingRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if start == -1 {
+			if strings.EqualFold(strings.TrimSpace(m[2]), anchor) {
+				start, level = i, len(m[1])
+			}
+			continue
+		}
+		if len(m[1]) <= level {
+			return strings.TrimSpace(strings.Join(lines[start:i], "\n")), true
+		}
+	}
+	if start == -1 {
+		return "", false
+	}
+	return strings.TrimSpace(strings.Join(lines[start:], "\n")), true
+}
+
+// isMarkdownFence reports whether line opens or closes a fenced code block
+// isMarkdownFence 判断该行是否为围栏代码块的开始或结束
+func isMarkdownFence(line string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// InsertUnderMarkdownHeading inserts text into the section of the heading named by heading and
+// reports whether the heading had to be created.
+// heading is the heading text, optionally with its level marker ("## Inbox"); with a marker only a
+// heading of that level matches. The text goes after the last non-blank line of the section, or right
+// below the heading line when atStart is set. A missing heading is added at the end of content,
+// at the given level or level 2. Headings inside frontmatter or fenced code blocks are ignored and
+// matching is case-insensitive.
+// InsertUnderMarkdownHeading 将 text 插入 heading 所指标题的段落中，并返回是否新建了该标题。
+// heading 为标题文本，可带级别标记（"## Inbox"）；带标记时仅匹配该级别的标题。text 插入到段落最后一个非空行之后，
+// atStart 为 true 时插入到标题行正下方。标题不存在时以给定级别（默认 2 级）添加到内容末尾。
+// Frontmatter 与围栏代码块内的标题会被忽略，匹配不区分大小写。
+func InsertUnderMarkdownHeading(content, heading, text string, atStart bool) (string, bool) {
+	level, name := 0, strings.TrimSpace(heading)
+	if m := markdownHeadingRegex.FindStringSubmatch(name); m != nil {
+		level, name = len(m[1]), strings.TrimSpace(m[2])
+	}
+
+	newline := "\n"
+	if strings.Contains(content, "\r\n") {
+		newline = "\r\n"
+	}
+	insert := strings.Split(strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), "\n")
+	lines := strings.Split(content, "\n")
+
+	start, startLevel, end := -1, 0, len(lines)
+	inFence := false
+	for i := markdownBodyStart(lines); i < len(lines); i++ {
+		line := strings.TrimSuffix(lines[i], "\r")
+		if isMarkdownFence(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		m := markdownHeadingRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if start == -1 {
+			if strings.EqualFold(strings.TrimSpace(m[2]), name) && (level == 0 || len(m[1]) == level) {
+				start, startLevel = i, len(m[1])
+			}
+			continue
+		}
+		if len(m[1]) <= startLevel {
+			end = i
+			break
+		}
+	}
+
+	if start == -1 {
+		if level == 0 {
+			level = 2
+		}
+		body := strings.TrimRight(content, "\r\n")
+		if body != "" {
+			body += newline + newline
+		}
+		return body + strings.Repeat("#", level) + " " + name + newline + strings.Join(insert, newline) + newline, true
+	}
+
+	at := start + 1
+	if !atStart {
+		at = end
+		for at > start+1 && strings.TrimSpace(lines[at-1]) == "" {
+			at--
+		}
+	}
+	if newline == "\r\n" {
+		for i := range insert {
+			insert[i] += "\r"
+		}
+		// Appended after a last line without line break: the old last line gets one, the new one does not
+		// 追加在没有换行符的最后一行之后：原最后一行补上换行符，新的最后一行不带
+		if at == len(lines) {
+			lines[at-1] += "\r"
+			insert[len(insert)-1] = strings.TrimSuffix(insert[len(insert)-1], "\r")
+		}
+	}
+	result := make([]string, 0, len(lines)+len(insert))
+	result = append(result, lines[:at]...)
+	result = append(result, insert...)
+	result = append(result, lines[at:]...)
+	return strings.Join(result, "\n"), false
+}
+
+// markdownBodyStart returns the index of the first line after the YAML frontmatter, 0 when there is none
+// markdownBodyStart 返回 YAML Frontmatter 之后第一行的下标，没有 Frontmatter 时返回 0
+func markdownBodyStart(lines []string) int {
+	if len(lines) == 0 || strings.TrimRight(lines[0], "\r") != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], "\r") == "---" {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestExtractMarkdownSection(t *testing.T) {
+	content := "# Title\nintro\n\n## Setup\nstep one\n```\n# not a heading\n```\n### Details\nmore\n## Usage\nrun it ^run-id\n"
+
+	tests := []struct {
+		name   string
+		anchor string
+		want   string
+		wantOK bool
+	}{
+		{name: "heading until same level", anchor: "Setup", want: "## Setup\nstep one\n```\n# not a heading\n```\n### Details\nmore", wantOK: true},
+		{name: "case insensitive", anchor: "usage", want: "## Usage\nrun it ^run-id", wantOK: true},
+		{name: "nested anchor uses last segment", anchor: "Setup#Details", want: "### Details\nmore", wantOK: true},
+		{name: "heading inside fence ignored", anchor: "not a heading", want: "", wantOK: false},
+		{name: "block reference", anchor: "^run-id", want: "run it", wantOK: true},
+		{name: "missing", anchor: "Nope", want: "", wantOK: false},
+		{name: "empty", anchor: "", want: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ExtractMarkdownSection(content, tt.anchor)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ExtractMarkdownSection(%q) = %q, %v; want %q, %v", tt.anchor, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestInsertUnderMarkdownHeading(t *testing.T) {
+	content := "---\n# yaml comment\ntags: [x]\n---\n# Title\n\n## Inbox\n- one\n\n### Sub\nsub\n\n## Done\n- old\n"
+
+	tests := []struct {
+		name        string
+		content     string
+		heading     string
+		atStart     bool
+		want        string
+		wantCreated bool
+	}{
+		{name: "end of section before next same level heading", content: content, heading: "## Inbox", want: "---\n# yaml comment\ntags: [x]\n---\n# Title\n\n## Inbox\n- one\n\n### Sub\nsub\n- new\n\n## Done\n- old\n"},
+		{name: "start of section", content: content, heading: "inbox", atStart: true, want: "---\n# yaml comment\ntags: [x]\n---\n# Title\n\n## Inbox\n- new\n- one\n\n### Sub\nsub\n\n## Done\n- old\n"},
+		{name: "last section keeps final newline", content: content, heading: "Done", want: "---\n# yaml comment\ntags: [x]\n---\n# Title\n\n## Inbox\n- one\n\n### Sub\nsub\n\n## Done\n- old\n- new\n"},
+		{name: "level must match", content: content, heading: "# Inbox", want: content + "\n# Inbox\n- new\n", wantCreated: true},
+		{name: "frontmatter comment is no heading", content: content, heading: "yaml comment", want: content + "\n## yaml comment\n- new\n", wantCreated: true},
+		{name: "empty note", content: "", heading: "Inbox", want: "## Inbox\n- new\n", wantCreated: true},
+		{name: "crlf without final newline", content: "## Inbox\r\n- one", heading: "Inbox", want: "## Inbox\r\n- one\r\n- new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, created := InsertUnderMarkdownHeading(tt.content, tt.heading, "- new\n", tt.atStart)
+			if got != tt.want || created != tt.wantCreated {
+				t.Errorf("InsertUnderMarkdownHeading(%q) = %q, %v; want %q, %v", tt.heading, got, created, tt.want, tt.wantCreated)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package util
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SnippetRange is a highlighted match inside a snippet, as rune offsets [Start, End)
+// SnippetRange 表示片段中的一处高亮匹配，为字符（rune）偏移区间 [Start, End)
+type SnippetRange struct {
+	Start int
+	End   int
+}
+
+// SearchSnippet extracts the context around the first case-insensitive match of any
+// whitespace-separated keyword term, keeping up to radius runes on each side. Whitespace is
+// collapsed to spaces and cut ends are marked with "…". ok is false when nothing matches.
+// SearchSnippet 截取关键词（按空白拆分的各个词，忽略大小写）首个匹配处的上下文，两侧各保留至多 radius 个字符。
+// 空白统一替换为空格，被截断的一端以 "…" 标记。未匹配时 ok 为 false。
+func SearchSnippet(text, keyword string, radius int) (snippet string, ranges []SnippetRange, ok bool) {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			runes[i] = ' '
+		}
+		lower[i] = unicode.ToLower(runes[i])
+	}
+
+	var matches []SnippetRange
+	for _, term := range UniqueStrings(strings.Fields(keyword)) {
+		needle := []rune(strings.Map(unicode.ToLower, term))
+		for i := 0; i+len(needle) <= len(lower); {
+			if string(lower[i:i+len(needle)]) == string(needle) {
+				matches = append(matches, SnippetRange{Start: i, End: i + len(needle)})
+				i += len(needle)
+				continue
+			}
+			i++
+		}
+	}
+	if len(matches) == 0 {
+		return "", nil, false
+	}
+
+	return snippetAround(runes, matches, radius)
+}
+
+// RegexSnippet is like SearchSnippet but takes the matches of a regular expression; empty matches are ignored
+// RegexSnippet 与 SearchSnippet 相同，但匹配来自正则表达式；空匹配被忽略
+func RegexSnippet(text string, re *regexp.Regexp, radius int) (snippet string, ranges []SnippetRange, ok bool) {
+	var matches []SnippetRange
+	bytePos, runePos := 0, 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		runePos += utf8.RuneCountInString(text[bytePos:loc[0]])
+		start := runePos
+		runePos += utf8.RuneCountInString(text[loc[0]:loc[1]])
+		bytePos = loc[1]
+		matches = append(matches, SnippetRange{Start: start, End: runePos})
+	}
+	if len(matches) == 0 {
+		return "", nil, false
+	}
+
+	runes := []rune(text)
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			runes[i] = ' '
+		}
+	}
+	return snippetAround(runes, matches, radius)
+}
+
+// snippetAround cuts the window around the first of matches and shifts the matches inside it
+// snippetAround 截取首个匹配周围的窗口，并换算窗口内各匹配的偏移
+func snippetAround(runes []rune, matches []SnippetRange, radius int) (string, []SnippetRange, bool) {
+	// Sort and merge overlapping matches of different terms
+	// 排序并合并不同词之间重叠的匹配
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	merged := matches[:1]
+	for _, m := range matches[1:] {
+		last := &merged[len(merged)-1]
+		if m.Start <= last.End {
+			last.End = max(last.End, m.End)
+			continue
+		}
+		merged = append(merged, m)
+	}
+
+	start := max(merged[0].Start-radius, 0)
+	end := min(merged[0].End+radius, len(runes))
+
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "…"
+	}
+	if end < len(runes) {
+		suffix = "…"
+	}
+	shift := len([]rune(prefix)) - start
+	var ranges []SnippetRange
+	for _, m := range merged {
+		if m.Start >= start && m.End <= end {
+			ranges = append(ranges, SnippetRange{Start: m.Start + shift, End: m.End + shift})
+		}
+	}
+
+	return prefix + string(runes[start:end]) + suffix, ranges, true
+}
+
+// HighlightSnippet HTML-escapes a snippet and wraps each range in <mark> tags
+// HighlightSnippet 对片段做 HTML 转义，并用 <mark> 标签包裹每个匹配区间
+func HighlightSnippet(snippet string, ranges []SnippetRange) string {
+	runes := []rune(snippet)
+	var b strings.Builder
+	pos := 0
+	for _, r := range ranges {
+		b.WriteString(html.EscapeString(string(runes[pos:r.Start])))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(string(runes[r.Start:r.End])))
+		b.WriteString("</mark>")
+		pos = r.End
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:])))
+	return b.String()
+}
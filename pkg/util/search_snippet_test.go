@@ -0,0 +1,79 @@
+package util
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestSearchSnippet(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		keyword  string
+		radius   int
+		wantOK   bool
+		wantHTML string
+	}{
+		{
+			name:     "middle match is cut on both sides",
+			text:     "alpha beta\nGamma delta epsilon",
+			keyword:  "gamma",
+			radius:   5,
+			wantOK:   true,
+			wantHTML: "…beta <mark>Gamma</mark> delt…",
+		},
+		{
+			name:     "multiple terms and escaping",
+			text:     "<b>Fast</b> note sync",
+			keyword:  "fast SYNC",
+			radius:   20,
+			wantOK:   true,
+			wantHTML: "&lt;b&gt;<mark>Fast</mark>&lt;/b&gt; note <mark>sync</mark>",
+		},
+		{
+			name:     "cjk",
+			text:     "这是全文搜索测试",
+			keyword:  "搜索",
+			radius:   2,
+			wantOK:   true,
+			wantHTML: "…全文<mark>搜索</mark>测试",
+		},
+		{
+			name:    "no match",
+			text:    "nothing here",
+			keyword: "missing",
+			radius:  10,
+			wantOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snippet, ranges, ok := SearchSnippet(tt.text, tt.keyword, tt.radius)
+			if ok != tt.wantOK {
+				t.Fatalf("SearchSnippet() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got := HighlightSnippet(snippet, ranges); got != tt.wantHTML {
+				t.Errorf("HighlightSnippet() = %q, want %q", got, tt.wantHTML)
+			}
+		})
+	}
+}
+
+func TestRegexSnippet(t *testing.T) {
+	re := regexp.MustCompile(`- \[ \] \w+`)
+	snippet, ranges, ok := RegexSnippet("# 待办\n- [ ] milk\n- [x] eggs\n- [ ] bread", re, 3)
+	if !ok {
+		t.Fatal("RegexSnippet() ok = false, want true")
+	}
+	if got, want := HighlightSnippet(snippet, ranges), "…待办 <mark>- [ ] milk</mark> - …"; got != want {
+		t.Errorf("HighlightSnippet() = %q, want %q", got, want)
+	}
+
+	if _, _, ok := RegexSnippet("abc", regexp.MustCompile(`x*`), 3); ok {
+		t.Error("RegexSnippet() with only empty matches ok = true, want false")
+	}
+}
@@ -0,0 +1,101 @@
+// Package util provides common utility functions
+// Package util 提供通用工具函数
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// inlineTagRegex matches #tags preceded by the start of a line or whitespace, so headings ("# Title"),
+// URL fragments and HTML entities are not taken for tags
+// Group 1: tag without the leading "#" // 不含前导 "#" 的标签
+// inlineTagRegex 匹配位于行首或空白之后的 #标签，避免把标题（"# Title"）、URL 片段和 HTML 实体当作标签
+var inlineTagRegex = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_/-]+)`)
+
+// ParseTags extracts the tags of a note: the "tags" / "tag" frontmatter property (a list or a comma
+// or space separated string) and inline #tags outside fenced code blocks.
+// Tags are returned without "#", deduplicated case-insensitively, in order of appearance;
+// purely numeric tags like #123 are ignored as Obsidian does.
+// ParseTags 提取笔记的标签：frontmatter 中的 "tags" / "tag" 属性（列表或逗号、空格分隔的字符串）
+// 以及代码块之外的行内 #标签。
+// 返回的标签不含 "#"，按出现顺序排列并忽略大小写去重；与 Obsidian 一致，忽略 #123 这类纯数字标签。
+func ParseTags(content string) []string {
+	if content == "" {
+		return nil
+	}
+
+	var tags []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		tag = strings.Trim(strings.TrimSpace(tag), "#")
+		if tag == "" || isNumeric(tag) {
+			return
+		}
+		key := strings.ToLower(tag)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		tags = append(tags, tag)
+	}
+
+	yamlData, body, _ := ParseFrontmatter(content)
+	for _, key := range []string{"tags", "tag"} {
+		switch v := yamlData[key].(type) {
+		case string:
+			for _, tag := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' }) {
+				add(tag)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if tag, ok := item.(string); ok {
+					add(tag)
+				}
+			}
+		}
+	}
+
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, match := range inlineTagRegex.FindAllStringSubmatch(line, -1) {
+			add(match[1])
+		}
+	}
+	return tags
+}
+
+// HasTag reports whether the note content carries tag, compared case-insensitively with or without
+// the leading "#". Nested tags match their parents: #temp/draft carries temp.
+// HasTag 判断笔记内容是否带有标签 tag，忽略大小写及前导 "#"。嵌套标签匹配其父标签：#temp/draft 带有 temp。
+func HasTag(content, tag string) bool {
+	tag = strings.ToLower(strings.Trim(strings.TrimSpace(tag), "#"))
+	if tag == "" {
+		return false
+	}
+	for _, t := range ParseTags(content) {
+		t = strings.ToLower(t)
+		if t == tag || strings.HasPrefix(t, tag+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isNumeric reports whether s only consists of ASCII digits
+// isNumeric 判断 s 是否只由 ASCII 数字组成
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name:     "empty",
+			content:  "",
+			expected: nil,
+		},
+		{
+			name:     "frontmatter list",
+			content:  "---\ntags: [work, \"#temp\"]\n---\nBody",
+			expected: []string{"work", "temp"},
+		},
+		{
+			name:     "frontmatter string",
+			content:  "---\ntags: work, temp\n---\nBody",
+			expected: []string{"work", "temp"},
+		},
+		{
+			name:     "inline tags",
+			content:  "Todo #temp and #Project/alpha\n#next line",
+			expected: []string{"temp", "Project/alpha", "next"},
+		},
+		{
+			name:     "frontmatter and inline deduplicated",
+			content:  "---\ntag: temp\n---\nSee #TEMP",
+			expected: []string{"temp"},
+		},
+		{
+			name:     "headings, fragments and numbers ignored",
+			content:  "# Title\n## Sub\nhttps://example.com/#anchor issue#12 #123",
+			expected: nil,
+		},
+		{
+			name:     "code blocks ignored",
+			content:  "```\n#include <stdio.h>\n```\n#real",
+			expected: []string{"real"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseTags(tt.content); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ParseTags() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	content := "Scratch #Temp/draft"
+	if !HasTag(content, "#temp") {
+		t.Error("nested tag should match its parent")
+	}
+	if !HasTag(content, "temp/draft") {
+		t.Error("exact nested tag should match")
+	}
+	if HasTag(content, "tem") {
+		t.Error("tag prefix should not match")
+	}
+	if HasTag(content, "") {
+		t.Error("empty tag should not match")
+	}
+}
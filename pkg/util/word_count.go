@@ -0,0 +1,48 @@
+// Package util provides common utility functions
+// Package util 提供通用工具函数
+package util
+
+import "unicode"
+
+// ReadingWordsPerMinute reading speed used to estimate reading time
+// ReadingWordsPerMinute 估算阅读时间所用的阅读速度
+const ReadingWordsPerMinute = 200
+
+// CountWords counts the words and characters of a note body, frontmatter excluded.
+// A word is a run of letters, digits, apostrophes or hyphens; like Obsidian, each Chinese, Japanese or
+// Korean character counts as a word of its own. Characters exclude whitespace
+// CountWords 统计笔记正文的字数与字符数，不含 frontmatter。
+// 一个单词为连续的字母、数字、撇号或连字符；与 Obsidian 一致，每个中日韩字符单独计为一个字。字符数不含空白
+func CountWords(content string) (words, chars int64) {
+	_, body, _ := ParseFrontmatter(content)
+	inWord := false
+	for _, r := range body {
+		if unicode.IsSpace(r) {
+			inWord = false
+			continue
+		}
+		chars++
+		switch {
+		case isCJK(r):
+			words++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || (inWord && (r == '\'' || r == '’' || r == '-')):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		default:
+			inWord = false
+		}
+	}
+	return words, chars
+}
+
+// ReadingMinutes estimated reading time in whole minutes, at least 1 for a non-empty text
+// ReadingMinutes 估算的阅读时间（整分钟），非空文本至少为 1
+func ReadingMinutes(words int64) int64 {
+	if words <= 0 {
+		return 0
+	}
+	return (words + ReadingWordsPerMinute - 1) / ReadingWordsPerMinute
+}
@@ -0,0 +1,35 @@
+package util
+
+import "testing"
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		words   int64
+		chars   int64
+	}{
+		{name: "empty", content: "", words: 0, chars: 0},
+		{name: "english", content: "Hello, world! It's a well-known fact.", words: 6, chars: 32},
+		{name: "cjk", content: "你好 世界", words: 4, chars: 4},
+		{name: "mixed", content: "Go 语言 2024", words: 4, chars: 8},
+		{name: "frontmatter excluded", content: "---\ntitle: Long title here\n---\nOne two", words: 2, chars: 6},
+		{name: "markdown", content: "# Title\n\n- [ ] task", words: 2, chars: 13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, chars := CountWords(tt.content)
+			if words != tt.words || chars != tt.chars {
+				t.Errorf("CountWords(%q) = %d, %d, want %d, %d", tt.content, words, chars, tt.words, tt.chars)
+			}
+		})
+	}
+}
+
+func TestReadingMinutes(t *testing.T) {
+	for words, want := range map[int64]int64{0: 0, 1: 1, 200: 1, 201: 2, 1000: 5} {
+		if got := ReadingMinutes(words); got != want {
+			t.Errorf("ReadingMinutes(%d) = %d, want %d", words, got, want)
+		}
+	}
+}
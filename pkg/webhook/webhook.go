@@ -0,0 +1,84 @@
+// Package webhook delivers signed JSON payloads to user-configured URLs
+// Package webhook 向用户配置的 URL 投递带签名的 JSON 负载
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// HeaderTimestamp unix seconds at which the payload was signed
+	// HeaderTimestamp 负载签名时的 Unix 秒级时间戳
+	HeaderTimestamp = "X-FNS-Timestamp"
+	// HeaderSignature "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>"
+	// HeaderSignature "sha256=" 加上 "<timestamp>.<body>" 的十六进制 HMAC-SHA256
+	HeaderSignature = "X-FNS-Signature"
+	// HeaderEvent event name of the payload
+	// HeaderEvent 负载的事件名
+	HeaderEvent = "X-FNS-Event"
+)
+
+// DefaultTimeout timeout of a single delivery
+// DefaultTimeout 单次投递的超时时间
+const DefaultTimeout = 10 * time.Second
+
+// Sign returns the signature of body for the given timestamp, as sent in HeaderSignature
+// Sign 返回 body 在给定时间戳下的签名，即 HeaderSignature 的值
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send POSTs payload as JSON to url. The request is signed when secret is not empty.
+// Any non-2xx response is reported as an error.
+// Send 以 JSON 形式将 payload POST 到 url，secret 不为空时对请求签名。
+// 非 2xx 响应视为错误。
+func Send(ctx context.Context, client *http.Client, url string, secret string, event string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fast-note-sync-service")
+	if event != "" {
+		req.Header.Set(HeaderEvent, event)
+	}
+	if secret != "" {
+		ts := time.Now().Unix()
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+		req.Header.Set(HeaderSignature, Sign(secret, ts, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: %s responded %s", url, resp.Status)
+	}
+	return nil
+}
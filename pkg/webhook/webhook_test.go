@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend_Signed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"status":"ok"}`, string(body))
+		assert.Equal(t, "backup.report", r.Header.Get(HeaderEvent))
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		assert.NoError(t, err)
+		assert.Equal(t, Sign("secret", timestamp, body), r.Header.Get(HeaderSignature))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	err := Send(context.Background(), nil, ts.URL, "secret", "backup.report", map[string]string{"status": "ok"})
+	assert.NoError(t, err)
+}
+
+func TestSend_UnsignedAndNon2xx(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(HeaderSignature))
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	err := Send(context.Background(), nil, ts.URL, "", "", map[string]string{})
+	assert.Error(t, err)
+}
@@ -257,6 +257,9 @@ CREATE TABLE "storage" (
     "access_url_prefix" text DEFAULT '',
     "user" text DEFAULT '',
     "password" text DEFAULT '',
+    "path_style" integer NOT NULL DEFAULT 0,
+    "ca_cert" text DEFAULT '',
+    "insecure_skip_verify" integer NOT NULL DEFAULT 0,
     "is_enabled" integer NOT NULL DEFAULT 0,
     "is_deleted" integer NOT NULL DEFAULT 0,
     "created_at" datetime DEFAULT NULL,
@@ -276,7 +279,7 @@ CREATE TABLE "backup_config" (
     "uid" integer NOT NULL DEFAULT 0,
     "vault_id" integer NOT NULL DEFAULT 0,
     "type" text DEFAULT '',
-    -- full, incremental, sync
+    -- full, incremental, sync, dedupe
     "storage_ids" text DEFAULT '',
     -- JSON array of storage ids: [1, 2]
     "is_enabled" integer DEFAULT 0,
@@ -294,6 +297,12 @@ CREATE TABLE "backup_config" (
     "last_message" text DEFAULT '',
     "password_mode" integer DEFAULT 0, -- 0: None, 1: Fixed, 2: Random
     "password_value" text DEFAULT '',
+    "webhook_url" text DEFAULT '',
+    -- URL that receives a signed JSON report of each backup run
+    "webhook_secret" text DEFAULT '',
+    -- HMAC-SHA256 key for the report signature
+    "ping_url" text DEFAULT '',
+    -- Dead-man switch ping URL (healthchecks.io compatible), pinged on start/success/failure
     "created_at" datetime DEFAULT NULL,
     "updated_at" datetime DEFAULT NULL
 );
@@ -313,7 +322,7 @@ CREATE TABLE "backup_history" (
     "config_id" integer NOT NULL DEFAULT 0,
     "storage_id" integer NOT NULL DEFAULT 0,
     "type" text DEFAULT '',
-    -- full, incremental, sync
+    -- full, incremental, sync, dedupe
     "start_time" datetime DEFAULT NULL,
     "end_time" datetime DEFAULT NULL,
     "status" integer DEFAULT 0,
@@ -325,6 +334,10 @@ CREATE TABLE "backup_history" (
     "file_path" text DEFAULT '',
     -- remote path/key
     "password" text DEFAULT '',
+    "archive_size" integer DEFAULT 0,
+    -- bytes of the uploaded object (zip archive or dedupe manifest)
+    "checksum" text DEFAULT '',
+    -- sha256 of the uploaded object, hex
     "created_at" datetime DEFAULT NULL,
     "updated_at" datetime DEFAULT NULL
 );
@@ -333,6 +346,25 @@ CREATE INDEX "idx_backup_history_uid" ON "backup_history" ("uid", "created_at" D
 
 CREATE INDEX "idx_backup_history_config_id" ON "backup_history" ("config_id");
 
+-- ----------------------------
+-- Table structure for backup_blob
+-- ----------------------------
+DROP TABLE IF EXISTS "backup_blob";
+
+CREATE TABLE "backup_blob" (
+    "id"          integer PRIMARY KEY AUTOINCREMENT,
+    "uid"         integer NOT NULL DEFAULT 0,
+    "config_id"   integer NOT NULL DEFAULT 0,
+    "storage_id"  integer NOT NULL DEFAULT 0,
+    "hash"        text NOT NULL DEFAULT '',  -- SHA-256 of the chunk, also its remote object name
+    "size"        integer NOT NULL DEFAULT 0,
+    "last_ref_at" datetime DEFAULT NULL,     -- start time of the latest run referencing the chunk
+    "created_at"  datetime DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX "idx_backup_blob_target_hash" ON "backup_blob" ("config_id", "storage_id", "hash");
+CREATE INDEX "idx_backup_blob_last_ref" ON "backup_blob" ("config_id", "storage_id", "last_ref_at");
+
 -- ----------------------------
 -- Table structure for git_sync_config
 -- ----------------------------
@@ -366,6 +398,8 @@ CREATE TABLE "git_sync_config" (
     -- 是否开启配置同步
     "config_sync_rules" text DEFAULT '',
     -- 存储规则列表的 JSON 数组 (例如 [".obsidian/appearance.json", ".obsidian/plugins/"])
+    "ping_url" text DEFAULT '',
+    -- 健康检查 Ping 地址 (兼容 healthchecks.io)，在开始/成功/失败时请求
     "created_at" datetime DEFAULT NULL,
     "updated_at" datetime DEFAULT NULL
 );
@@ -420,6 +454,198 @@ CREATE TABLE "sync_log" (
 CREATE INDEX "idx_sync_log_uid_created_at"  ON "sync_log" ("uid", "created_at" DESC);
 CREATE INDEX "idx_sync_log_uid_type_action" ON "sync_log" ("uid", "type", "action");
 
+-- ----------------------------
+-- Table structure for note_access_watch
+-- ----------------------------
+DROP TABLE IF EXISTS "note_access_watch";
+
+CREATE TABLE "note_access_watch" (
+    "id"         integer PRIMARY KEY AUTOINCREMENT,
+    "uid"        integer NOT NULL DEFAULT 0,
+    "vault_id"   integer NOT NULL DEFAULT 0,
+    "note_id"    integer NOT NULL DEFAULT 0,
+    "created_at" datetime DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX "idx_note_access_watch_note_id" ON "note_access_watch" ("note_id");
+
+-- ----------------------------
+-- Table structure for note_access_log
+-- ----------------------------
+DROP TABLE IF EXISTS "note_access_log";
+
+CREATE TABLE "note_access_log" (
+    "id"          integer PRIMARY KEY AUTOINCREMENT,
+    "uid"         integer NOT NULL DEFAULT 0,
+    "vault_id"    integer NOT NULL DEFAULT 0,
+    "note_id"     integer NOT NULL DEFAULT 0,
+    "path"        text DEFAULT '',
+    "channel"     text NOT NULL DEFAULT '',  -- 'share', 'api', 'webdav'
+    "actor"       text DEFAULT '',           -- share:<id> / token:<id> / client name
+    "client_type" text DEFAULT '',
+    "ip"          text DEFAULT '',
+    "ua"          text DEFAULT '',
+    "created_at"  datetime DEFAULT NULL
+);
+
+CREATE INDEX "idx_note_access_log_note_id"    ON "note_access_log" ("note_id", "created_at" DESC);
+CREATE INDEX "idx_note_access_log_created_at" ON "note_access_log" ("created_at");
+
+-- ----------------------------
+-- Table structure for webhook
+-- ----------------------------
+DROP TABLE IF EXISTS "webhook";
+
+CREATE TABLE "webhook" (
+    "id"         integer PRIMARY KEY AUTOINCREMENT,
+    "uid"        integer NOT NULL DEFAULT 0,
+    "name"       text DEFAULT '',
+    "url"        text DEFAULT '',
+    "secret"     text DEFAULT '',
+    "events"     text DEFAULT '',           -- comma separated, empty: all events
+    "is_enabled" integer NOT NULL DEFAULT 0,
+    "created_at" datetime DEFAULT NULL,
+    "updated_at" datetime DEFAULT NULL
+);
+
+-- ----------------------------
+-- Table structure for webhook_delivery
+-- ----------------------------
+DROP TABLE IF EXISTS "webhook_delivery";
+
+CREATE TABLE "webhook_delivery" (
+    "id"         integer PRIMARY KEY AUTOINCREMENT,
+    "uid"        integer NOT NULL DEFAULT 0,
+    "webhook_id" integer NOT NULL DEFAULT 0,
+    "event"      text NOT NULL DEFAULT '',
+    "payload"    text DEFAULT '',
+    "status"     text NOT NULL DEFAULT '',  -- pending, success, failed
+    "attempts"   integer NOT NULL DEFAULT 0,
+    "error"      text DEFAULT '',
+    "created_at" datetime DEFAULT NULL,
+    "updated_at" datetime DEFAULT NULL
+);
+
+CREATE INDEX "idx_webhook_delivery_webhook_id" ON "webhook_delivery" ("webhook_id");
+CREATE INDEX "idx_webhook_delivery_created_at" ON "webhook_delivery" ("created_at");
+
+-- ----------------------------
+-- Table structure for alert_channel
+-- ----------------------------
+DROP TABLE IF EXISTS "alert_channel";
+
+CREATE TABLE "alert_channel" (
+    "id"         integer PRIMARY KEY AUTOINCREMENT,
+    "uid"        integer NOT NULL DEFAULT 0,
+    "name"       text DEFAULT '',
+    "type"       text NOT NULL DEFAULT '',  -- email, telegram, gotify, ntfy
+    "recipients" text DEFAULT '',           -- email recipients, comma separated
+    "url"        text DEFAULT '',           -- gotify server / ntfy topic / telegram API root
+    "token"      text DEFAULT '',
+    "chat_id"    text DEFAULT '',
+    "priority"   integer NOT NULL DEFAULT 0,
+    "is_enabled" integer NOT NULL DEFAULT 0,
+    "created_at" datetime DEFAULT NULL,
+    "updated_at" datetime DEFAULT NULL
+);
+
+-- ----------------------------
+-- Table structure for note_policy
+-- ----------------------------
+DROP TABLE IF EXISTS "note_policy";
+
+CREATE TABLE "note_policy" (
+    "id"              integer PRIMARY KEY AUTOINCREMENT,
+    "uid"             integer NOT NULL DEFAULT 0,
+    "vault_id"        integer NOT NULL DEFAULT 0,
+    "name"            text DEFAULT '',
+    "folder"          text DEFAULT '',          -- empty: whole vault
+    "tag"             text DEFAULT '',          -- empty: all notes
+    "age_basis"       text NOT NULL DEFAULT '', -- modified, created
+    "older_than_days" integer NOT NULL DEFAULT 0,
+    "action"          text NOT NULL DEFAULT '', -- archive, delete
+    "archive_folder"  text DEFAULT '',
+    "is_enabled"      integer NOT NULL DEFAULT 0,
+    "last_run_time"   datetime DEFAULT NULL,
+    "created_at"      datetime DEFAULT NULL,
+    "updated_at"      datetime DEFAULT NULL
+);
+
+-- ----------------------------
+-- Table structure for note_policy_run
+-- ----------------------------
+DROP TABLE IF EXISTS "note_policy_run";
+
+CREATE TABLE "note_policy_run" (
+    "id"           integer PRIMARY KEY AUTOINCREMENT,
+    "uid"          integer NOT NULL DEFAULT 0,
+    "policy_id"    integer NOT NULL DEFAULT 0,
+    "trigger_type" text NOT NULL DEFAULT '', -- schedule, manual
+    "matched"      integer NOT NULL DEFAULT 0,
+    "succeeded"    integer NOT NULL DEFAULT 0,
+    "failed"       integer NOT NULL DEFAULT 0,
+    "paths"        text DEFAULT '',          -- newline separated
+    "message"      text DEFAULT '',
+    "start_time"   datetime DEFAULT NULL,
+    "end_time"     datetime DEFAULT NULL
+);
+
+CREATE INDEX "idx_note_policy_run_policy_id" ON "note_policy_run" ("policy_id");
+
+-- ----------------------------
+-- Table structure for note_template
+-- ----------------------------
+DROP TABLE IF EXISTS "note_template";
+
+CREATE TABLE "note_template" (
+    "id"          integer PRIMARY KEY AUTOINCREMENT,
+    "uid"         integer NOT NULL DEFAULT 0,
+    "name"        text NOT NULL DEFAULT '',
+    "description" text DEFAULT '',
+    "content"     text DEFAULT '',          -- {{date}}, {{time}}, {{title}} expanded on note creation
+    "created_at"  datetime DEFAULT NULL,
+    "updated_at"  datetime DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX "idx_note_template_uid_name" ON "note_template" ("uid", "name");
+
+-- ----------------------------
+-- Table structure for note_daily_setting
+-- ----------------------------
+DROP TABLE IF EXISTS "note_daily_setting";
+
+CREATE TABLE "note_daily_setting" (
+    "id"         integer PRIMARY KEY AUTOINCREMENT,
+    "uid"        integer NOT NULL DEFAULT 0,
+    "pattern"    text DEFAULT '',             -- e.g. Journal/{{YYYY-MM-DD}}.md, empty: {{YYYY-MM-DD}}.md
+    "template"   text DEFAULT '',             -- note template name
+    "timezone"   text DEFAULT '',             -- IANA time zone, empty: server local time
+    "updated_at" datetime DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX "idx_note_daily_setting_uid" ON "note_daily_setting" ("uid");
+
+-- ----------------------------
+-- Table structure for audit_log
+-- ----------------------------
+DROP TABLE IF EXISTS "audit_log";
+
+CREATE TABLE "audit_log" (
+    "id"          integer PRIMARY KEY AUTOINCREMENT,
+    "uid"         integer NOT NULL DEFAULT 0,  -- acting user, 0 for failed logins
+    "action"      text NOT NULL DEFAULT '',    -- user.login, user.login_failed, user.delete, note.delete, admin.config_update, backup.execute
+    "target"      text DEFAULT '',
+    "detail"      text DEFAULT '',
+    "ip"          text DEFAULT '',
+    "client_type" text DEFAULT '',
+    "client_name" text DEFAULT '',
+    "created_at"  datetime DEFAULT NULL
+);
+
+CREATE INDEX "idx_audit_log_uid" ON "audit_log" ("uid");
+CREATE INDEX "idx_audit_log_action" ON "audit_log" ("action");
+CREATE INDEX "idx_audit_log_created_at" ON "audit_log" ("created_at");
+
 -- ----------------------------
 -- Table structure for auth_token
 -- ----------------------------